@@ -11,6 +11,9 @@
 //   - Communication platforms (MS Teams, Discord)
 //   - Monitoring tools (Datadog, New Relic)
 //   - Custom webhooks
+//
+// AMP ships a built-in Teams publisher (target type and format "teams");
+// the MS Teams publisher below is kept as a self-contained reference.
 package main
 
 import (
@@ -0,0 +1,180 @@
+// Command replay re-runs a day of recorded alerts through the current and a
+// candidate Alertmanager-compatible config offline and reports how the
+// notifications would change.
+//
+// Usage:
+//
+//	replay -alerts history.json -baseline alertmanager.yml -candidate alertmanager.new.yml \
+//	    [-silences silences.json] [-candidate-silences silences.new.json] [-format text|json] [-fail-on-change]
+//
+// The alerts file is a history export; silence files are silence lists as
+// returned by GET /api/v2/silences/export. With -fail-on-change the command
+// exits with status 1 when any notification changes, so it can gate config
+// changes in CI.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/business/replay"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/routing"
+)
+
+func main() {
+	var (
+		alertsPath            = flag.String("alerts", "", "recorded alerts (history export, JSON)")
+		baselinePath          = flag.String("baseline", "", "current Alertmanager-compatible config (YAML)")
+		candidatePath         = flag.String("candidate", "", "candidate Alertmanager-compatible config (YAML)")
+		silencesPath          = flag.String("silences", "", "silences applied to both configs (JSON, optional)")
+		candidateSilencesPath = flag.String("candidate-silences", "", "silences for the candidate instead of -silences (JSON, optional)")
+		format                = flag.String("format", "text", "output format: text or json")
+		failOnChange          = flag.Bool("fail-on-change", false, "exit with status 1 when any notification changes")
+	)
+	flag.Parse()
+
+	if *alertsPath == "" || *baselinePath == "" || *candidatePath == "" {
+		fmt.Fprintln(os.Stderr, "replay: -alerts, -baseline and -candidate are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintln(os.Stderr, "replay: -format must be text or json")
+		os.Exit(2)
+	}
+	if *candidateSilencesPath == "" {
+		*candidateSilencesPath = *silencesPath
+	}
+
+	report, err := run(*alertsPath, *baselinePath, *candidatePath, *silencesPath, *candidateSilencesPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "replay:", err)
+		os.Exit(2)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeText(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "replay:", err)
+		os.Exit(2)
+	}
+
+	if *failOnChange && report.HasChanges() {
+		os.Exit(1)
+	}
+}
+
+func run(alertsPath, baselinePath, candidatePath, silencesPath, candidateSilencesPath string) (*replay.Report, error) {
+	f, err := os.Open(alertsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	alerts, err := replay.ReadAlerts(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", alertsPath, err)
+	}
+
+	baseline, err := loadConfig(baselinePath, silencesPath)
+	if err != nil {
+		return nil, err
+	}
+	candidate, err := loadConfig(candidatePath, candidateSilencesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	baselineResult, err := replay.Run(alerts, baseline)
+	if err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+	candidateResult, err := replay.Run(alerts, candidate)
+	if err != nil {
+		return nil, fmt.Errorf("candidate: %w", err)
+	}
+	return replay.Compare(baselineResult, candidateResult), nil
+}
+
+func loadConfig(configPath, silencesPath string) (replay.Config, error) {
+	routes, err := routing.NewRouteConfigParser().ParseFile(configPath)
+	if err != nil {
+		return replay.Config{}, fmt.Errorf("%s: %w", configPath, err)
+	}
+	cfg := replay.Config{Routes: routes}
+
+	if silencesPath != "" {
+		data, err := os.ReadFile(silencesPath)
+		if err != nil {
+			return replay.Config{}, err
+		}
+		var silences []core.APISilence
+		if err := json.Unmarshal(data, &silences); err != nil {
+			return replay.Config{}, fmt.Errorf("%s: invalid silences: %w", silencesPath, err)
+		}
+		cfg.Silences = silences
+	}
+	return cfg, nil
+}
+
+func writeText(w io.Writer, report *replay.Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Replayed %d alerts\n\n", report.Alerts)
+	fmt.Fprintf(&b, "%-10s %9s %9s %10s %14s\n", "", "notified", "silenced", "inhibited", "notifications")
+	for _, row := range []struct {
+		name    string
+		summary replay.Summary
+	}{{"baseline", report.Baseline}, {"candidate", report.Candidate}} {
+		fmt.Fprintf(&b, "%-10s %9d %9d %10d %14d\n",
+			row.name, row.summary.Notified, row.summary.Silenced, row.summary.Inhibited, row.summary.Notifications)
+	}
+
+	if !report.HasChanges() {
+		b.WriteString("\nNo notification changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if len(report.Receivers) > 0 {
+		b.WriteString("\nNotifications per receiver:\n")
+		for _, delta := range report.Receivers {
+			fmt.Fprintf(&b, "  %-30s %6d -> %6d (%+d)\n",
+				delta.Receiver, delta.Baseline, delta.Candidate, delta.Candidate-delta.Baseline)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nChanged alerts (%d):\n", len(report.Changes))
+	for _, change := range report.Changes {
+		fmt.Fprintf(&b, "  %s %s %s: %s -> %s\n",
+			change.Baseline.StartsAt.Format(time.RFC3339),
+			change.Baseline.AlertName,
+			change.Baseline.Fingerprint,
+			describe(change.Baseline),
+			describe(change.Candidate))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func describe(result replay.AlertResult) string {
+	switch result.Outcome {
+	case replay.OutcomeNotified:
+		return "notified " + strings.Join(result.Receivers, ",")
+	case replay.OutcomeSilenced:
+		return "silenced by " + strings.Join(result.SilencedBy, ",")
+	case replay.OutcomeInhibited:
+		return "inhibited by " + result.InhibitedBy
+	default:
+		return string(result.Outcome)
+	}
+}
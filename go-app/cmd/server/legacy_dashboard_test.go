@@ -68,6 +68,8 @@ func TestLegacyDashboardPlaceholderRoutes_RenderReadOnlyPages(t *testing.T) {
 					StartsAt:        "2026-03-09T10:00:00Z",
 					EndsAt:          "2026-03-09T11:00:00Z",
 					UpdatedAt:       "2026-03-09T10:05:00Z",
+					MatchCount:      42,
+					LastMatchedAt:   "2026-03-09T10:30:00Z",
 				},
 			},
 		},
@@ -123,7 +125,7 @@ func TestLegacyDashboardPlaceholderRoutes_RenderReadOnlyPages(t *testing.T) {
 		{
 			name:       "silences ready",
 			path:       "/dashboard/silences",
-			wantParts:  []string{"Silence inventory", "maintenance window", "alertname=Watchdog", "/api/v2/silences"},
+			wantParts:  []string{"Silence inventory", "maintenance window", "alertname=Watchdog", "/api/v2/silences", "Alerts suppressed", "2026-03-09T10:30:00Z"},
 			avoidParts: []string{"not yet implemented"},
 		},
 		{
@@ -219,3 +221,37 @@ func TestRenderTemplate_WhenTemplatesNotLoaded_ReturnsInternalServerError(t *tes
 		t.Fatalf("renderTemplate body = %q, want missing templates error", rec.Body.String())
 	}
 }
+
+func TestLegacyDashboardOverview_RendersLast24h(t *testing.T) {
+	provider := stubLegacyDashboardProvider{
+		overview: application.LegacyDashboardOverviewSummary{
+			Profile: "lite",
+			Last24h: &application.LegacyDashboardAlertCounts{
+				Total:         7,
+				BySeverity:    []application.LegacyDashboardCount{{Value: "critical", Count: 5}, {Value: "(none)", Count: 2}},
+				TopAlertNames: []application.LegacyDashboardCount{{Value: "DiskFull", Count: 4}},
+			},
+		},
+	}
+	mux := newLegacyDashboardTestMux(t, provider)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /dashboard status = %d, want 200", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, part := range []string{"Last 24 hours", "7 alerts started", "critical", "DiskFull"} {
+		if !strings.Contains(body, part) {
+			t.Fatalf("GET /dashboard body missing %q\nbody=%s", part, body)
+		}
+	}
+
+	// Without rollups the panel is hidden
+	mux = newLegacyDashboardTestMux(t, stubLegacyDashboardProvider{})
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	if strings.Contains(rec.Body.String(), "Last 24 hours") {
+		t.Fatal("GET /dashboard shows the last 24 hours without rollups")
+	}
+}
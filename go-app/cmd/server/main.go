@@ -84,6 +84,18 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	// Hot reload on SIGHUP
+	go func() {
+		hupChan := make(chan os.Signal, 1)
+		signal.Notify(hupChan, syscall.SIGHUP)
+		for range hupChan {
+			slog.Info("SIGHUP received, reloading configuration")
+			if err := registry.ReloadConfig(ctx); err != nil {
+				slog.Error("Configuration reload failed", "error", err)
+			}
+		}
+	}()
+
 	// Graceful shutdown
 	go func() {
 		sigChan := make(chan os.Signal, 1)
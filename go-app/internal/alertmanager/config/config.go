@@ -20,7 +20,8 @@ type GlobalConfig struct {
 	SMTPSmarthost    string        `yaml:"smtp_smarthost,omitempty" json:"smtp_smarthost,omitempty"`
 	SMTPAuthUsername string        `yaml:"smtp_auth_username,omitempty" json:"smtp_auth_username,omitempty"`
 	SMTPAuthPassword string        `yaml:"smtp_auth_password,omitempty" json:"smtp_auth_password,omitempty"`
-	SMTPRequireTLS   bool          `yaml:"smtp_require_tls,omitempty" json:"smtp_require_tls,omitempty"`
+	SMTPAuthIdentity string        `yaml:"smtp_auth_identity,omitempty" json:"smtp_auth_identity,omitempty"`
+	SMTPRequireTLS   *bool         `yaml:"smtp_require_tls,omitempty" json:"smtp_require_tls,omitempty"` // nil means true, as in Alertmanager
 	SlackAPIURL      string        `yaml:"slack_api_url,omitempty" json:"slack_api_url,omitempty"`
 	PagerdutyURL     string        `yaml:"pagerduty_url,omitempty" json:"pagerduty_url,omitempty"`
 	OpsGenieAPIURL   string        `yaml:"opsgenie_api_url,omitempty" json:"opsgenie_api_url,omitempty"`
@@ -35,6 +36,15 @@ type HTTPConfig struct {
 	BearerTokenFile string     `yaml:"bearer_token_file,omitempty" json:"bearer_token_file,omitempty"`
 	ProxyURL        string     `yaml:"proxy_url,omitempty" json:"proxy_url,omitempty"`
 	TLSConfig       *TLSConfig `yaml:"tls_config,omitempty" json:"tls_config,omitempty"`
+	OAuth2          *OAuth2    `yaml:"oauth2,omitempty" json:"oauth2,omitempty"`
+}
+
+// OAuth2 contains OAuth2 client credentials configuration
+type OAuth2 struct {
+	ClientID     string   `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+	ClientSecret string   `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	TokenURL     string   `yaml:"token_url,omitempty" json:"token_url,omitempty"`
 }
 
 // BasicAuth contains basic authentication configuration
@@ -51,6 +61,7 @@ type TLSConfig struct {
 	KeyFile            string `yaml:"key_file,omitempty" json:"key_file,omitempty"`
 	ServerName         string `yaml:"server_name,omitempty" json:"server_name,omitempty"`
 	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty" json:"insecure_skip_verify,omitempty"`
+	MinVersion         string `yaml:"min_version,omitempty" json:"min_version,omitempty"` // TLS10..TLS13
 }
 
 // Route defines a routing tree node
@@ -89,13 +100,18 @@ type Receiver struct {
 
 // EmailConfig defines email notification configuration
 type EmailConfig struct {
-	To         string            `yaml:"to,omitempty" json:"to,omitempty"`
-	From       string            `yaml:"from,omitempty" json:"from,omitempty"`
-	Smarthost  string            `yaml:"smarthost,omitempty" json:"smarthost,omitempty"`
-	Headers    map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
-	HTML       string            `yaml:"html,omitempty" json:"html,omitempty"`
-	Text       string            `yaml:"text,omitempty" json:"text,omitempty"`
-	RequireTLS *bool             `yaml:"require_tls,omitempty" json:"require_tls,omitempty"`
+	SendResolved bool              `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	To           string            `yaml:"to,omitempty" json:"to,omitempty"`
+	From         string            `yaml:"from,omitempty" json:"from,omitempty"`
+	Smarthost    string            `yaml:"smarthost,omitempty" json:"smarthost,omitempty"`
+	AuthUsername string            `yaml:"auth_username,omitempty" json:"auth_username,omitempty"`
+	AuthPassword string            `yaml:"auth_password,omitempty" json:"auth_password,omitempty"`
+	AuthIdentity string            `yaml:"auth_identity,omitempty" json:"auth_identity,omitempty"`
+	Headers      map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	HTML         string            `yaml:"html,omitempty" json:"html,omitempty"`
+	Text         string            `yaml:"text,omitempty" json:"text,omitempty"`
+	RequireTLS   *bool             `yaml:"require_tls,omitempty" json:"require_tls,omitempty"`
+	TLSConfig    *TLSConfig        `yaml:"tls_config,omitempty" json:"tls_config,omitempty"`
 }
 
 // PagerdutyConfig defines PagerDuty notification configuration
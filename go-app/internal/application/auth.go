@@ -0,0 +1,88 @@
+package application
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ipiton/AMP/internal/application/handlers"
+	appconfig "github.com/ipiton/AMP/internal/config"
+)
+
+// tokenAuthenticator resolves API tokens to their scopes.
+//
+// Tokens are indexed by SHA-256 digest so the raw secrets are not kept in
+// memory after startup and lookups do not compare secrets byte by byte.
+type tokenAuthenticator struct {
+	tokens map[[sha256.Size]byte]*apiToken
+}
+
+type apiToken struct {
+	name  string
+	scope *handlers.TokenScope // nil for unscoped (full access) tokens
+}
+
+func newTokenAuthenticator(cfg appconfig.AuthConfig) (*tokenAuthenticator, error) {
+	a := &tokenAuthenticator{tokens: make(map[[sha256.Size]byte]*apiToken, len(cfg.Tokens))}
+	for _, tc := range cfg.Tokens {
+		token := &apiToken{name: tc.Name}
+		if len(tc.Selector) > 0 {
+			selector, err := handlers.ParseLabelMatchers(tc.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("token %q: %w", tc.Name, err)
+			}
+			token.scope = &handlers.TokenScope{Name: tc.Name, Selector: selector}
+		}
+
+		digest := sha256.Sum256([]byte(tc.Token))
+		if _, dup := a.tokens[digest]; dup {
+			return nil, fmt.Errorf("token %q: duplicate token value", tc.Name)
+		}
+		a.tokens[digest] = token
+	}
+	return a, nil
+}
+
+// authenticate returns the token presented by r, or nil if none or unknown.
+func (a *tokenAuthenticator) authenticate(r *http.Request) *apiToken {
+	raw := strings.TrimSpace(r.Header.Get("X-API-Key"))
+	if auth := r.Header.Get("Authorization"); raw == "" && len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+		raw = strings.TrimSpace(auth[len("Bearer "):])
+	}
+	if raw == "" {
+		return nil
+	}
+	return a.tokens[sha256.Sum256([]byte(raw))]
+}
+
+// requiresAuth reports whether path is protected. Health, readiness and
+// metrics endpoints stay open for probes and scrapers.
+func requiresAuth(path string) bool {
+	return strings.HasPrefix(path, "/api/") || path == "/-/reload"
+}
+
+// scopedTokenAllowed reports whether a label-scoped token may call the endpoint.
+//
+// Scoped tokens can read alerts and manage silences (both filtered by the
+// handlers); ingestion, reload and endpoints that are not label-aware
+// (inhibitions, inhibition rule simulation and sources, decision traces,
+// delivery history, investigations, silence approvals) and admin endpoints (maintenance mode,
+// re-classification, publishing targets) need an unscoped token.
+func scopedTokenAllowed(method, path string) bool {
+	switch {
+	case path == "/api/v2/alerts", path == "/api/v2/alerts/groups":
+		return method == http.MethodGet
+	case path == "/api/v2/silences", path == "/api/v2/silences/preview", strings.HasPrefix(path, "/api/v2/silence/"):
+		return true
+	case path == "/api/v2/silences/stats",
+		strings.HasPrefix(path, "/api/v2/silences/") && strings.HasSuffix(path, "/history"):
+		return method == http.MethodGet
+	case strings.HasPrefix(path, "/api/v2/silences/from-template/"):
+		return method == http.MethodPost
+	case path == "/api/v2/status", path == "/api/v2/status/startup", path == "/api/v2/receivers":
+		return method == http.MethodGet
+	default:
+		return false
+	}
+}
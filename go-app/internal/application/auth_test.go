@@ -0,0 +1,230 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+func newAuthTestHandler(t *testing.T) (http.Handler, *ServiceRegistry) {
+	t.Helper()
+
+	cfg := &appconfig.Config{
+		Auth: appconfig.AuthConfig{
+			Enabled: true,
+			Tokens: []appconfig.APITokenConfig{
+				{Name: "admin", Token: "admin-token"},
+				{Name: "payments", Token: "payments-token", Selector: []string{`team="payments"`}},
+			},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	registry := &ServiceRegistry{
+		config:       cfg,
+		logger:       logger,
+		alertStore:   memory.NewAlertStore(),
+		silenceStore: memory.NewSilenceStore(),
+		silenceAudit: memory.NewSilenceAuditLog(0),
+		decisionLog:  memory.NewDecisionLog(0, 0),
+		startTime:    activeContractStartTime,
+		initialized:  true,
+	}
+	registry.silenceStore.SetAuditHook(registry.recordSilenceAudit)
+
+	now := time.Now().UTC()
+	if err := registry.alertStore.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "PaymentsDown", "team": "payments"}, Status: "firing"},
+		{Labels: map[string]string{"alertname": "SearchDown", "team": "search"}, Status: "firing"},
+	}, now); err != nil {
+		t.Fatalf("IngestBatch() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	NewRouter(registry).SetupRoutes(mux)
+	stack, err := NewMiddlewareStack(cfg, registry, logger)
+	if err != nil {
+		t.Fatalf("NewMiddlewareStack() error = %v", err)
+	}
+	return stack.Wrap(mux), registry
+}
+
+func doAuthRequest(handler http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthMiddleware_RequiresToken(t *testing.T) {
+	handler, _ := newAuthTestHandler(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{name: "missing token", method: http.MethodGet, path: "/api/v2/alerts", status: http.StatusUnauthorized},
+		{name: "unknown token", method: http.MethodGet, path: "/api/v2/alerts", token: "nope", status: http.StatusUnauthorized},
+		{name: "admin token", method: http.MethodGet, path: "/api/v2/alerts", token: "admin-token", status: http.StatusOK},
+		{name: "health stays open", method: http.MethodGet, path: "/-/healthy", status: http.StatusOK},
+		{name: "reload protected", method: http.MethodPost, path: "/-/reload", status: http.StatusUnauthorized},
+		{name: "scoped token cannot ingest", method: http.MethodPost, path: "/api/v2/alerts", token: "payments-token", status: http.StatusForbidden},
+		{name: "scoped token cannot read inhibitions", method: http.MethodGet, path: "/api/v2/inhibitions", token: "payments-token", status: http.StatusForbidden},
+		{name: "scoped token cannot read decisions", method: http.MethodGet, path: "/api/v2/alerts/abc/decisions", token: "payments-token", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doAuthRequest(handler, tt.method, tt.path, tt.token, "")
+			if rec.Code != tt.status {
+				t.Fatalf("%s %s status = %d, want %d; body=%s", tt.method, tt.path, rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/alerts", nil)
+	req.Header.Set("X-API-Key", "admin-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("X-API-Key status = %d, want 200", rec.Code)
+	}
+}
+
+func TestAuthMiddleware_ScopedTokenSeesOnlyMatchingAlerts(t *testing.T) {
+	handler, _ := newAuthTestHandler(t)
+
+	rec := doAuthRequest(handler, http.MethodGet, "/api/v2/alerts", "payments-token", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET alerts status = %d, want 200", rec.Code)
+	}
+	var alerts []core.APIGettableAlert
+	if err := json.Unmarshal(rec.Body.Bytes(), &alerts); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Labels["team"] != "payments" {
+		t.Fatalf("expected only the payments alert, got %+v", alerts)
+	}
+
+	rec = doAuthRequest(handler, http.MethodGet, "/api/v2/alerts/groups", "payments-token", "")
+	var groups []core.APIGettableAlertGroup
+	if err := json.Unmarshal(rec.Body.Bytes(), &groups); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Alerts) != 1 || groups[0].Alerts[0].Labels["team"] != "payments" {
+		t.Fatalf("expected one group with the payments alert, got %+v", groups)
+	}
+}
+
+func TestAuthMiddleware_ScopedTokenSilences(t *testing.T) {
+	handler, registry := newAuthTestHandler(t)
+
+	silence := func(team string) string {
+		return `{
+			"matchers": [{"name":"alertname","value":"Down.*","isRegex":true},{"name":"team","value":"` + team + `"}],
+			"endsAt": "2099-01-01T00:00:00Z",
+			"createdBy": "oncall",
+			"comment": "maintenance"
+		}`
+	}
+
+	if rec := doAuthRequest(handler, http.MethodPost, "/api/v2/silences", "payments-token", silence("search")); rec.Code != http.StatusForbidden {
+		t.Fatalf("out-of-scope silence status = %d, want 403", rec.Code)
+	}
+	unpinned := `{"matchers":[{"name":"alertname","value":"PaymentsDown"}],"endsAt":"2099-01-01T00:00:00Z","createdBy":"oncall","comment":"maintenance"}`
+	if rec := doAuthRequest(handler, http.MethodPost, "/api/v2/silences", "payments-token", unpinned); rec.Code != http.StatusForbidden {
+		t.Fatalf("unpinned silence status = %d, want 403", rec.Code)
+	}
+
+	rec := doAuthRequest(handler, http.MethodPost, "/api/v2/silences", "payments-token", silence("payments"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("in-scope silence status = %d, want 200; body=%s", rec.Code, rec.Body.String())
+	}
+
+	rec = doAuthRequest(handler, http.MethodPost, "/api/v2/silences", "admin-token", silence("search"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("admin silence status = %d, want 200; body=%s", rec.Code, rec.Body.String())
+	}
+	var created map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	searchID := created["silenceID"]
+
+	rec = doAuthRequest(handler, http.MethodGet, "/api/v2/silences", "payments-token", "")
+	var silences []core.APISilence
+	if err := json.Unmarshal(rec.Body.Bytes(), &silences); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(silences) != 1 {
+		t.Fatalf("expected 1 visible silence, got %d", len(silences))
+	}
+
+	rec = doAuthRequest(handler, http.MethodGet, "/api/v2/silences/stats", "payments-token", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET silence stats status = %d, want 200", rec.Code)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &silences); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(silences) != 1 || silences[0].ID == searchID {
+		t.Fatalf("expected stats for the payments silence only, got %+v", silences)
+	}
+
+	if rec := doAuthRequest(handler, http.MethodGet, "/api/v2/silence/"+searchID, "payments-token", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET out-of-scope silence status = %d, want 404", rec.Code)
+	}
+	if rec := doAuthRequest(handler, http.MethodDelete, "/api/v2/silence/"+searchID, "payments-token", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("DELETE out-of-scope silence status = %d, want 404", rec.Code)
+	}
+	if s, ok := registry.silenceStore.Get(searchID, time.Now().UTC()); !ok || s.Status.State != "active" {
+		t.Fatalf("out-of-scope silence must stay active, got %+v", s)
+	}
+
+	historyPath := "/api/v2/silences/" + searchID + "/history"
+	if rec := doAuthRequest(handler, http.MethodGet, historyPath, "payments-token", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET out-of-scope silence history status = %d, want 404", rec.Code)
+	}
+	if rec := doAuthRequest(handler, http.MethodDelete, "/api/v2/silence/"+searchID, "admin-token", ""); rec.Code != http.StatusOK {
+		t.Fatalf("admin DELETE status = %d, want 200", rec.Code)
+	}
+	rec = doAuthRequest(handler, http.MethodGet, historyPath, "admin-token", "")
+	var history []core.SilenceAuditEvent
+	if err := json.Unmarshal(rec.Body.Bytes(), &history); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(history) != 2 || history[0].Action != core.SilenceAuditCreate || history[1].Action != core.SilenceAuditExpire {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	for _, event := range history {
+		if event.Actor != "admin" {
+			t.Errorf("%s actor = %q, want the token name", event.Action, event.Actor)
+		}
+	}
+}
+
+func TestNewMiddlewareStack_InvalidSelector(t *testing.T) {
+	cfg := &appconfig.Config{
+		Auth: appconfig.AuthConfig{
+			Enabled: true,
+			Tokens:  []appconfig.APITokenConfig{{Name: "bad", Token: "t", Selector: []string{"team=payments"}}},
+		},
+	}
+	if _, err := NewMiddlewareStack(cfg, &ServiceRegistry{}, nil); err == nil {
+		t.Fatal("expected error for invalid selector")
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+)
+
+// InhibitionExplainRegistryProvider provides the inhibition rule engine and
+// the recorded inhibitions.
+type InhibitionExplainRegistryProvider interface {
+	InhibitionsRegistryProvider
+	InhibitionMatcher() inhibition.InhibitionMatcher
+}
+
+// inhibitionExplainer is implemented by inhibition.DefaultInhibitionMatcher.
+type inhibitionExplainer interface {
+	Explain(ctx context.Context, fingerprint string) (*inhibition.Explanation, error)
+}
+
+// alertInhibitionResponse is the response of GET /api/v2/alerts/{fingerprint}/inhibition.
+type alertInhibitionResponse struct {
+	*inhibition.Explanation
+	// Recorded is the inhibition recorded when the alert was last processed.
+	Recorded *inhibitionResponse `json:"recorded,omitempty"`
+}
+
+// AlertResourcesRegistryProvider is satisfied by ServiceRegistry.
+type AlertResourcesRegistryProvider interface {
+	DecisionLogProvider
+	DeliveryLogProvider
+	InhibitionExplainRegistryProvider
+}
+
+// AlertResourcesHandler serves the per-alert resources under /api/v2/alerts/:
+// {fingerprint}/decisions, {fingerprint}/deliveries and {fingerprint}/inhibition.
+func AlertResourcesHandler(registry AlertResourcesRegistryProvider) http.HandlerFunc {
+	decisions := AlertDecisionsHandler(registry)
+	deliveries := AlertDeliveriesHandler(registry)
+	inhibitionHandler := AlertInhibitionHandler(registry)
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimRight(r.URL.Path, "/")
+		if strings.HasSuffix(path, "/inhibition") {
+			inhibitionHandler(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/deliveries") {
+			deliveries(w, r)
+			return
+		}
+		decisions(w, r)
+	}
+}
+
+// AlertInhibitionHandler returns GET /api/v2/alerts/{fingerprint}/inhibition.
+//
+// Explains why a firing alert is or is not inhibited: every rule with
+// whether the alert matches its target side, the firing alerts matching its
+// source side and the comparison of the equal labels. Alerts that are not
+// firing are not found, as only firing alerts are inhibited.
+func AlertInhibitionHandler(registry InhibitionExplainRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fingerprint, ok := extractAlertResourceFingerprint(r.URL.Path, "/inhibition")
+		if !ok {
+			NotFoundHandler(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		matcher := registry.InhibitionMatcher()
+		if matcher == nil {
+			// Inhibition not configured: no rule can inhibit the alert
+			writeJSON(w, http.StatusOK, alertInhibitionResponse{
+				Explanation: &inhibition.Explanation{
+					Fingerprint: fingerprint,
+					Rules:       []inhibition.RuleExplanation{},
+				},
+			})
+			return
+		}
+		explainer, ok := matcher.(inhibitionExplainer)
+		if !ok {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "inhibition explanation unavailable"})
+			return
+		}
+
+		explanation, err := explainer.Explain(r.Context(), fingerprint)
+		if errors.Is(err, inhibition.ErrAlertNotFiring) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "alert is not firing"})
+			return
+		}
+		if err != nil {
+			InternalErrorHandler(w, "failed to explain inhibition: "+err.Error())
+			return
+		}
+
+		resp := alertInhibitionResponse{Explanation: explanation}
+		if stateManager := registry.InhibitionState(); stateManager != nil {
+			if state, err := stateManager.GetInhibitionState(r.Context(), fingerprint); err == nil && state != nil {
+				recorded := inhibitionResponse{
+					TargetFingerprint: state.TargetFingerprint,
+					SourceFingerprint: state.SourceFingerprint,
+					RuleName:          state.RuleName,
+					InhibitedAt:       state.InhibitedAt.UTC().Format(time.RFC3339),
+				}
+				if state.ExpiresAt != nil {
+					s := state.ExpiresAt.UTC().Format(time.RFC3339)
+					recorded.ExpiresAt = &s
+				}
+				resp.Recorded = &recorded
+			}
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// extractAlertResourceFingerprint parses /api/v2/alerts/<fingerprint><suffix>.
+func extractAlertResourceFingerprint(path, suffix string) (string, bool) {
+	rest := strings.TrimPrefix(strings.TrimRight(path, "/"), "/api/v2/alerts/")
+	fingerprint, found := strings.CutSuffix(rest, suffix)
+	if !found || fingerprint == "" || strings.Contains(fingerprint, "/") {
+		return "", false
+	}
+	return fingerprint, true
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+)
+
+// fakeAlertCache is a static inhibition.ActiveAlertCache.
+type fakeAlertCache struct {
+	alerts []*core.Alert
+}
+
+func (c *fakeAlertCache) GetFiringAlerts(_ context.Context) ([]*core.Alert, error) {
+	return c.alerts, nil
+}
+
+func (c *fakeAlertCache) AddFiringAlert(_ context.Context, _ *core.Alert) error { return nil }
+
+func (c *fakeAlertCache) RemoveAlert(_ context.Context, _ string) error { return nil }
+
+// fakeInhibitionExplainRegistry implements InhibitionExplainRegistryProvider.
+type fakeInhibitionExplainRegistry struct {
+	fakeInhibitionRegistry
+	matcher inhibition.InhibitionMatcher
+}
+
+func (r *fakeInhibitionExplainRegistry) InhibitionMatcher() inhibition.InhibitionMatcher {
+	return r.matcher
+}
+
+// recordedStateManager returns a recorded inhibition for every target.
+type recordedStateManager struct {
+	fakeStateManager
+	state *inhibition.InhibitionState
+}
+
+func (m *recordedStateManager) GetInhibitionState(_ context.Context, _ string) (*inhibition.InhibitionState, error) {
+	return m.state, nil
+}
+
+func newExplainRegistry() *fakeInhibitionExplainRegistry {
+	source := &core.Alert{
+		Fingerprint: "source",
+		AlertName:   "NodeDown",
+		Labels:      map[string]string{"alertname": "NodeDown", "node": "n1"},
+	}
+	target := &core.Alert{
+		Fingerprint: "target",
+		AlertName:   "InstanceDown",
+		Labels:      map[string]string{"alertname": "InstanceDown", "node": "n1"},
+	}
+	rules := []inhibition.InhibitionRule{{
+		Name:        "node-down",
+		SourceMatch: map[string]string{"alertname": "NodeDown"},
+		TargetMatch: map[string]string{"alertname": "InstanceDown"},
+		Equal:       []string{"node"},
+	}}
+	_ = inhibition.CompileRules(rules)
+
+	return &fakeInhibitionExplainRegistry{
+		fakeInhibitionRegistry: fakeInhibitionRegistry{stateManager: &recordedStateManager{
+			state: &inhibition.InhibitionState{
+				TargetFingerprint: "target",
+				SourceFingerprint: "source",
+				RuleName:          "node-down",
+				InhibitedAt:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		}},
+		matcher: inhibition.NewMatcher(&fakeAlertCache{alerts: []*core.Alert{source, target}}, rules, nil),
+	}
+}
+
+func TestAlertInhibitionHandler_ExplainsInhibition(t *testing.T) {
+	handler := AlertInhibitionHandler(newExplainRegistry())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/alerts/target/inhibition", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Inhibited bool `json:"inhibited"`
+		Rules     []struct {
+			Rule    string `json:"rule"`
+			Sources []struct {
+				Fingerprint string `json:"fingerprint"`
+				Equal       []struct {
+					Label string `json:"label"`
+					Equal bool   `json:"equal"`
+				} `json:"equal"`
+			} `json:"sources"`
+		} `json:"rules"`
+		Recorded *struct {
+			RuleName    string `json:"ruleName"`
+			InhibitedAt string `json:"inhibitedAt"`
+		} `json:"recorded"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !resp.Inhibited || len(resp.Rules) != 1 || resp.Rules[0].Rule != "node-down" {
+		t.Fatalf("response = %s, want inhibited by node-down", w.Body.String())
+	}
+	if src := resp.Rules[0].Sources; len(src) != 1 || src[0].Fingerprint != "source" ||
+		len(src[0].Equal) != 1 || !src[0].Equal[0].Equal {
+		t.Errorf("sources = %+v, want source with equal node", src)
+	}
+	if resp.Recorded == nil || resp.Recorded.InhibitedAt != "2026-01-02T03:04:05Z" {
+		t.Errorf("recorded = %+v, want the recorded inhibition", resp.Recorded)
+	}
+}
+
+func TestAlertInhibitionHandler_NotFiring(t *testing.T) {
+	handler := AlertInhibitionHandler(newExplainRegistry())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/alerts/resolved/inhibition", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestAlertInhibitionHandler_NoRulesConfigured(t *testing.T) {
+	handler := AlertInhibitionHandler(&fakeInhibitionExplainRegistry{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/alerts/target/inhibition", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp inhibition.Explanation
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Inhibited || resp.Fingerprint != "target" || len(resp.Rules) != 0 {
+		t.Errorf("response = %+v, want not inhibited without rules", resp)
+	}
+}
+
+func TestAlertInhibitionHandler_MethodAndPath(t *testing.T) {
+	handler := AlertInhibitionHandler(newExplainRegistry())
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodPost, "/api/v2/alerts/target/inhibition", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/api/v2/alerts//inhibition", http.StatusNotFound},
+		{http.MethodGet, "/api/v2/alerts/a/b/inhibition", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.status {
+			t.Errorf("%s %s status = %d, want %d", tc.method, tc.path, w.Code, tc.status)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// alertStatsMaxBuckets bounds the buckets a query may span.
+const alertStatsMaxBuckets = 1000
+
+// AlertStatsRegistryProvider is satisfied by ServiceRegistry.
+type AlertStatsRegistryProvider interface {
+	AlertRollups() *services.AlertRollupJob
+}
+
+type alertStatsResponse struct {
+	Granularity core.AlertRollupGranularity `json:"granularity"`
+	From        string                      `json:"from"`
+	To          string                      `json:"to"`
+	// Buckets are the rollups, oldest bucket first.
+	Buckets []core.AlertRollup `json:"buckets"`
+	// Totals add up the buckets by dimension and value.
+	Totals map[core.AlertRollupDimension]map[string]int64 `json:"totals"`
+}
+
+// AlertStatsHandler serves GET /api/v2/stats/alerts: the number of alerts
+// that started per hour or day, in total and by severity, alertname and
+// team, from the rollups maintained by the alert rollup job. Alerts without
+// a label are counted under the empty value.
+//
+// Query parameters:
+//   - granularity: hour (default) or day
+//   - dimension: total, severity, alertname or team (default: all)
+//   - from, to: RFC3339 bounds of the bucket starts, to exclusive (default:
+//     the last 24 hours, or the last 30 days for days)
+func AlertStatsHandler(registry AlertStatsRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		job := registry.AlertRollups()
+		if job == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "alert rollups are disabled"})
+			return
+		}
+
+		query := r.URL.Query()
+		granularity := core.AlertRollupGranularity(query.Get("granularity"))
+		if granularity == "" {
+			granularity = core.AlertRollupHourly
+		}
+		if !granularity.Valid() {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "granularity must be hour or day"})
+			return
+		}
+		dimension := core.AlertRollupDimension(query.Get("dimension"))
+		if dimension != "" && !isAlertRollupDimension(dimension) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "dimension must be total, severity, alertname or team"})
+			return
+		}
+
+		from, err := parseAlertTime(query.Get("from"))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid from: " + err.Error()})
+			return
+		}
+		to, err := parseAlertTime(query.Get("to"))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid to: " + err.Error()})
+			return
+		}
+		if to.IsZero() {
+			to = time.Now().UTC()
+		}
+		if from.IsZero() {
+			from = to.Add(-24 * time.Hour)
+			if granularity == core.AlertRollupDaily {
+				from = to.Add(-30 * 24 * time.Hour)
+			}
+		}
+		if !from.Before(to) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "from must be before to"})
+			return
+		}
+		if to.Sub(from) > alertStatsMaxBuckets*granularity.Duration() {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "range spans more than 1000 buckets"})
+			return
+		}
+
+		buckets, err := job.Query(r.Context(), core.AlertRollupQuery{
+			Granularity: granularity,
+			Dimension:   dimension,
+			From:        from,
+			To:          to,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, alertStatsResponse{
+			Granularity: granularity,
+			From:        from.Format(time.RFC3339),
+			To:          to.Format(time.RFC3339),
+			Buckets:     buckets,
+			Totals:      core.SumAlertRollups(buckets),
+		})
+	}
+}
+
+func isAlertRollupDimension(dimension core.AlertRollupDimension) bool {
+	for _, known := range core.AlertRollupDimensions {
+		if dimension == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+type fakeAlertStatsRegistry struct {
+	job *services.AlertRollupJob
+}
+
+func (r *fakeAlertStatsRegistry) AlertRollups() *services.AlertRollupJob { return r.job }
+
+// fakeRollupStore returns its rollups within the queried range.
+type fakeRollupStore struct {
+	rollups []core.AlertRollup
+	query   core.AlertRollupQuery
+}
+
+func (s *fakeRollupStore) ReplaceAlertRollups(context.Context, core.AlertRollupGranularity, time.Time, []core.AlertRollup) error {
+	return nil
+}
+
+func (s *fakeRollupStore) ListAlertRollups(_ context.Context, query core.AlertRollupQuery) ([]core.AlertRollup, error) {
+	s.query = query
+	rollups := make([]core.AlertRollup, 0)
+	for _, r := range s.rollups {
+		if r.Granularity == query.Granularity && !r.BucketStart.Before(query.From) && r.BucketStart.Before(query.To) &&
+			(query.Dimension == "" || r.Dimension == query.Dimension) {
+			rollups = append(rollups, r)
+		}
+	}
+	return rollups, nil
+}
+
+func (s *fakeRollupStore) DeleteAlertRollupsBefore(context.Context, core.AlertRollupGranularity, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func getAlertStats(t *testing.T, handler http.HandlerFunc, query string, wantStatus int) alertStatsResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/stats/alerts?"+query, nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != wantStatus {
+		t.Fatalf("GET %s status = %d, want %d; body: %s", query, rec.Code, wantStatus, rec.Body.String())
+	}
+	var resp alertStatsResponse
+	if wantStatus == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+	}
+	return resp
+}
+
+func TestAlertStatsHandler(t *testing.T) {
+	hour := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
+	rollup := func(bucket time.Time, dimension core.AlertRollupDimension, value string, count int64) core.AlertRollup {
+		return core.AlertRollup{Granularity: core.AlertRollupHourly, BucketStart: bucket, Dimension: dimension, Value: value, Count: count}
+	}
+	store := &fakeRollupStore{rollups: []core.AlertRollup{
+		rollup(hour.Add(-time.Hour), core.AlertRollupTotal, "", 1),
+		rollup(hour.Add(-time.Hour), core.AlertRollupSeverity, "critical", 1),
+		rollup(hour, core.AlertRollupTotal, "", 3),
+		rollup(hour, core.AlertRollupSeverity, "critical", 1),
+		rollup(hour, core.AlertRollupSeverity, "warning", 2),
+	}}
+	handler := AlertStatsHandler(&fakeAlertStatsRegistry{job: services.NewAlertRollupJob(nil, store, services.AlertRollupConfig{})})
+
+	resp := getAlertStats(t, handler, "dimension=severity&from=2026-03-16T09:00:00Z&to=2026-03-16T11:00:00Z", http.StatusOK)
+	if resp.Granularity != core.AlertRollupHourly || len(resp.Buckets) != 3 {
+		t.Fatalf("got %s with %d buckets, want hour with 3", resp.Granularity, len(resp.Buckets))
+	}
+	if got := resp.Totals[core.AlertRollupSeverity]; got["critical"] != 2 || got["warning"] != 2 {
+		t.Errorf("severity totals = %v, want critical 2 and warning 2", got)
+	}
+	if _, ok := resp.Totals[core.AlertRollupTotal]; ok {
+		t.Errorf("totals include unrequested dimension: %v", resp.Totals)
+	}
+
+	// By default the last 24 hours are returned
+	getAlertStats(t, handler, "", http.StatusOK)
+	if span := store.query.To.Sub(store.query.From); span != 24*time.Hour {
+		t.Errorf("default span = %s, want 24h", span)
+	}
+	getAlertStats(t, handler, "granularity=day", http.StatusOK)
+	if span := store.query.To.Sub(store.query.From); span != 30*24*time.Hour {
+		t.Errorf("default daily span = %s, want 720h", span)
+	}
+
+	for _, query := range []string{
+		"granularity=week",
+		"dimension=namespace",
+		"from=yesterday",
+		"from=2026-03-16T11:00:00Z&to=2026-03-16T09:00:00Z",
+		"from=2026-01-01T00:00:00Z&to=2026-03-16T00:00:00Z",
+	} {
+		getAlertStats(t, handler, query, http.StatusBadRequest)
+	}
+}
+
+func TestAlertStatsHandler_Disabled(t *testing.T) {
+	getAlertStats(t, AlertStatsHandler(&fakeAlertStatsRegistry{}), "", http.StatusNotFound)
+}
@@ -1,23 +1,23 @@
 package handlers
 
 import (
+	"compress/gzip"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"sort"
 	"strings"
 	"time"
 
 	appconfig "github.com/ipiton/AMP/internal/config"
 	"github.com/ipiton/AMP/internal/core"
 	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/mirror"
 	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
 	"github.com/ipiton/AMP/internal/infrastructure/webhook"
+	"github.com/ipiton/AMP/pkg/core/domain"
 )
 
 // RegistryProvider is an interface that provides access to the service registry.
@@ -31,24 +31,48 @@ type RegistryProvider interface {
 	ReloadConfig(ctx context.Context) error
 }
 
+// WebhookMirrorRegistryProvider is implemented by registries that mirror
+// accepted webhooks to a peer AMP.
+type WebhookMirrorRegistryProvider interface {
+	WebhookMirror() *mirror.Mirror
+}
+
+// maxAlertsBodySize limits alert payloads, after decompression.
+const maxAlertsBodySize = 10 * 1024 * 1024
+
 func AlertsHandler(registry RegistryProvider) http.HandlerFunc {
 	externalURL := registry.Config().Server.ExternalURL
+	clockSkewTolerance := registry.Config().Webhook.ClockSkewTolerance
+	// Inhibition is optional: registries without it report no inhibited alerts.
+	inhibitions, _ := registry.(InhibitionsRegistryProvider)
+	maintenance, _ := registry.(MaintenanceRegistryProvider)
+	acknowledgements, _ := registry.(AcknowledgementsRegistryProvider)
+	var peerMirror *mirror.Mirror
+	if provider, ok := registry.(WebhookMirrorRegistryProvider); ok {
+		peerMirror = provider.WebhookMirror()
+	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		alertStore := registry.AlertStore()
 		silenceStore := registry.SilenceStore()
 
 		switch r.Method {
 		case http.MethodGet:
-			handleAlertsGet(alertStore, silenceStore, w, r)
+			handleAlertsGet(alertStore, silenceStore, activeInhibitors(r.Context(), inhibitions), acknowledgementStore(acknowledgements), w, r)
 		case http.MethodPost:
-			handleAlertsPost(registry.AlertProcessor(), alertStore, silenceStore, externalURL, w, r)
+			if ingestionPaused(maintenance) {
+				// Senders retry on 503, so no alert is lost during maintenance.
+				w.Header().Set("Retry-After", maintenanceRetryAfter)
+				writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "alert ingestion is paused for maintenance"})
+				return
+			}
+			handleAlertsPost(registry.AlertProcessor(), alertStore, peerMirror, externalURL, clockSkewTolerance, w, r)
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
 	}
 }
 
-func handleAlertsGet(store *memory.AlertStore, silences *memory.SilenceStore, w http.ResponseWriter, r *http.Request) {
+func handleAlertsGet(store *memory.AlertStore, silences *memory.SilenceStore, inhibitors map[string][]string, acks *memory.AcknowledgementStore, w http.ResponseWriter, r *http.Request) {
 	status := parseAlertsStatusQuery(r.URL.Query().Get("status"))
 	includeResolved := parseBoolQueryLenient(r.URL.Query().Get("resolved"), false)
 	if status == "resolved" {
@@ -62,14 +86,21 @@ func handleAlertsGet(store *memory.AlertStore, silences *memory.SilenceStore, w
 	}
 
 	alerts := store.List(status, includeResolved)
+	scope := TokenScopeFromContext(r.Context())
 
 	now := time.Now().UTC()
 	gettableAlerts := make([]core.APIGettableAlert, 0, len(alerts))
 	for _, alert := range alerts {
-		if !MatchesLabels(filters, alert.Labels) {
+		if !scope.AllowsLabels(alert.Labels) || !MatchesLabels(filters, alert.Labels) {
 			continue
 		}
-		gettableAlerts = append(gettableAlerts, toGettableAlert(alert, silences, now))
+		gettable := toGettableAlert(alert, silences, now)
+		if ids := inhibitors[alert.Fingerprint]; len(ids) > 0 && alert.Status == "firing" {
+			gettable.Status.InhibitedBy = ids
+			gettable.Status.State = "suppressed"
+		}
+		gettable.Status.Acknowledged = acknowledgementOf(acks, alert, now)
+		gettableAlerts = append(gettableAlerts, gettable)
 	}
 
 	writeJSON(w, http.StatusOK, gettableAlerts)
@@ -81,11 +112,33 @@ func AlertGroupsHandler(registry RegistryProvider) http.HandlerFunc {
 		groupBy := queryParams["group_by"]
 
 		groups := registry.AlertStore().GroupAlerts(groupBy)
+		if scope := TokenScopeFromContext(r.Context()); scope != nil {
+			groups = scopeAlertGroups(scope, groups)
+		}
 		writeJSON(w, http.StatusOK, groups)
 	}
 }
 
-func handleAlertsPost(processor *services.AlertProcessor, store *memory.AlertStore, silences *memory.SilenceStore, externalURL string, w http.ResponseWriter, r *http.Request) {
+// scopeAlertGroups drops alerts outside scope and the groups left empty.
+func scopeAlertGroups(scope *TokenScope, groups []core.APIGettableAlertGroup) []core.APIGettableAlertGroup {
+	out := make([]core.APIGettableAlertGroup, 0, len(groups))
+	for _, group := range groups {
+		alerts := make([]core.APIGettableAlert, 0, len(group.Alerts))
+		for _, alert := range group.Alerts {
+			if scope.AllowsLabels(alert.Labels) {
+				alerts = append(alerts, alert)
+			}
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+		group.Alerts = alerts
+		out = append(out, group)
+	}
+	return out
+}
+
+func handleAlertsPost(processor *services.AlertProcessor, store *memory.AlertStore, peerMirror *mirror.Mirror, externalURL string, clockSkewTolerance time.Duration, w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if processor == nil {
@@ -95,16 +148,13 @@ func handleAlertsPost(processor *services.AlertProcessor, store *memory.AlertSto
 		return
 	}
 
-	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 10*1024*1024))
-	if err != nil {
+	body, err := readAlertsBody(w, r)
+	if errors.Is(err, errAlertsBodyTooLarge) {
 		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
 			"error": "request payload too large",
 		})
 		return
 	}
-
-	now := time.Now().UTC()
-	alerts, err := parseAlertsForProcessing(body, now, externalURL)
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -112,17 +162,20 @@ func handleAlertsPost(processor *services.AlertProcessor, store *memory.AlertSto
 		return
 	}
 
-	filteredAlerts := make([]*core.Alert, 0, len(alerts))
-	for _, alert := range alerts {
-		if alert.Status != core.StatusResolved && silences != nil && silences.HasActiveMatch(alert.Labels, now) {
-			continue
-		}
-		filteredAlerts = append(filteredAlerts, alert)
+	now := time.Now().UTC()
+	alerts, err := parseAlertsForProcessing(body, now, externalURL, clockSkewTolerance)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+		return
 	}
 
-	successfulInputs := make([]core.AlertIngestInput, 0, len(filteredAlerts))
+	// Silenced alerts go through the processor too: its silence stage
+	// suppresses publishing while the alert is still recorded.
+	successfulInputs := make([]core.AlertIngestInput, 0, len(alerts))
 	failedCount := 0
-	for _, alert := range filteredAlerts {
+	for _, alert := range alerts {
 		if err := processor.ProcessAlert(r.Context(), alert); err != nil {
 			failedCount++
 			continue
@@ -139,6 +192,10 @@ func handleAlertsPost(processor *services.AlertProcessor, store *memory.AlertSto
 		}
 	}
 
+	if len(successfulInputs) > 0 {
+		peerMirror.Forward(r, body)
+	}
+
 	if failedCount == 0 {
 		w.WriteHeader(http.StatusOK)
 		return
@@ -160,16 +217,44 @@ func handleAlertsPost(processor *services.AlertProcessor, store *memory.AlertSto
 	})
 }
 
-func parseAlertsForProcessing(body []byte, now time.Time, externalURL string) ([]*core.Alert, error) {
-	if alerts, err := parsePrometheusAlerts(body, externalURL); err == nil {
+var errAlertsBodyTooLarge = errors.New("request payload too large")
+
+// readAlertsBody reads the alert payload of r, decompressing it when sent
+// with Content-Encoding: gzip (as mirrored by peer AMPs).
+func readAlertsBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	reader := io.Reader(http.MaxBytesReader(w, r.Body, maxAlertsBodySize))
+	if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip payload: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, maxAlertsBodySize+1))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || len(body) > maxAlertsBodySize {
+		return nil, errAlertsBodyTooLarge
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+	return body, nil
+}
+
+// parseAlertsForProcessing parses Prometheus or legacy payloads. All timestamps
+// are normalized to UTC and sender clock skew up to clockSkewTolerance is absorbed.
+func parseAlertsForProcessing(body []byte, now time.Time, externalURL string, clockSkewTolerance time.Duration) ([]*core.Alert, error) {
+	if alerts, err := parsePrometheusAlerts(body, externalURL, clockSkewTolerance); err == nil {
 		return alerts, nil
 	}
 
-	return parseLegacyAlerts(body, now)
+	return parseLegacyAlerts(body, now, clockSkewTolerance)
 }
 
-func parsePrometheusAlerts(body []byte, externalURL string) ([]*core.Alert, error) {
-	parser := webhook.NewPrometheusParser(externalURL)
+func parsePrometheusAlerts(body []byte, externalURL string, clockSkewTolerance time.Duration) ([]*core.Alert, error) {
+	parser := webhook.NewPrometheusParser(externalURL, webhook.WithClockSkewTolerance(clockSkewTolerance))
 	parsedWebhook, err := parser.Parse(body)
 	if err != nil {
 		return nil, err
@@ -183,7 +268,7 @@ func parsePrometheusAlerts(body []byte, externalURL string) ([]*core.Alert, erro
 	return parser.ConvertToDomain(parsedWebhook)
 }
 
-func parseLegacyAlerts(body []byte, now time.Time) ([]*core.Alert, error) {
+func parseLegacyAlerts(body []byte, now time.Time, clockSkewTolerance time.Duration) ([]*core.Alert, error) {
 	payload, err := parseAlertIngestPayload(body)
 	if err != nil {
 		return nil, err
@@ -195,6 +280,7 @@ func parseLegacyAlerts(body []byte, now time.Time) ([]*core.Alert, error) {
 		if err != nil {
 			return nil, fmt.Errorf("alert[%d]: %w", i, err)
 		}
+		alert.NormalizeTimestamps(now, clockSkewTolerance)
 		alerts = append(alerts, alert)
 	}
 
@@ -223,7 +309,7 @@ func convertIngestInputToAlert(in core.AlertIngestInput, now time.Time) (*core.A
 	status := normalizeAlertStatus(in.Status, endsAt, now)
 	fingerprint := strings.TrimSpace(in.Fingerprint)
 	if fingerprint == "" {
-		fingerprint = labelsFingerprint(in.Labels)
+		fingerprint = domain.LabelsFingerprint(in.Labels)
 	}
 
 	var generatorURL *string
@@ -299,29 +385,6 @@ func normalizeAlertStatus(raw string, endsAt *time.Time, now time.Time) string {
 	return "firing"
 }
 
-func labelsFingerprint(labels map[string]string) string {
-	if len(labels) == 0 {
-		return ""
-	}
-
-	keys := make([]string, 0, len(labels))
-	for key := range labels {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	var builder strings.Builder
-	for _, key := range keys {
-		builder.WriteString(key)
-		builder.WriteByte('=')
-		builder.WriteString(labels[key])
-		builder.WriteByte('|')
-	}
-
-	sum := sha256.Sum256([]byte(builder.String()))
-	return hex.EncodeToString(sum[:16])
-}
-
 func cloneStringMap(src map[string]string) map[string]string {
 	if len(src) == 0 {
 		return map[string]string{}
@@ -364,8 +427,9 @@ func toGettableAlert(alert core.APIAlert, silences *memory.SilenceStore, now tim
 		GeneratorURL: alert.GeneratorURL,
 		Fingerprint:  alert.Fingerprint,
 		Status: core.APIAlertStatus{
-			State:      state,
-			SilencedBy: silencedBy,
+			State:       state,
+			SilencedBy:  silencedBy,
+			InhibitedBy: []string{},
 		},
 	}
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"io"
@@ -11,9 +12,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	appconfig "github.com/ipiton/AMP/internal/config"
 	"github.com/ipiton/AMP/internal/core"
 	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+	"github.com/ipiton/AMP/internal/infrastructure/mirror"
 	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
 )
 
@@ -286,14 +291,24 @@ func TestAlertsHandler_EmptyFilter_ReturnsAll(t *testing.T) {
 
 func TestAlertsHandler_SilencedAlertIsSuppressed(t *testing.T) {
 	publisher := &fakePublisher{}
+	silenceStore := memory.NewSilenceStore()
+	processor, err := services.NewAlertProcessor(services.AlertProcessorConfig{
+		FilterEngine:  &fakeFilterEngine{},
+		Publisher:     publisher,
+		SilenceEngine: services.NewSilenceEngine(silenceStore, nil),
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatalf("NewAlertProcessor() error = %v", err)
+	}
 	registry := &fakeRegistry{
 		alertStore:   memory.NewAlertStore(),
-		silenceStore: memory.NewSilenceStore(),
-		processor:    newTestProcessor(t, publisher),
+		silenceStore: silenceStore,
+		processor:    processor,
 	}
 
 	now := time.Now().UTC()
-	_, err := registry.silenceStore.CreateOrUpdate(&core.SilenceInput{
+	silenceID, err := registry.silenceStore.CreateOrUpdate(&core.SilenceInput{
 		Matchers: []core.SilenceMatcherInput{
 			{Name: "alertname", Value: "MutedAlert"},
 		},
@@ -325,7 +340,137 @@ func TestAlertsHandler_SilencedAlertIsSuppressed(t *testing.T) {
 	if len(publisher.published) != 0 {
 		t.Fatalf("expected silenced alert to be skipped, got %d published", len(publisher.published))
 	}
-	if total, _, _ := registry.alertStore.Stats(); total != 0 {
-		t.Fatalf("expected no stored alerts, got %d", total)
+	alerts := getAlerts(t, handler, "")
+	if len(alerts) != 1 {
+		t.Fatalf("expected silenced alert to be stored, got %d alerts", len(alerts))
+	}
+	if alerts[0].Status.State != "suppressed" {
+		t.Fatalf("alert state = %q, want suppressed", alerts[0].Status.State)
+	}
+	if len(alerts[0].Status.SilencedBy) != 1 || alerts[0].Status.SilencedBy[0] != silenceID {
+		t.Fatalf("silencedBy = %v, want [%s]", alerts[0].Status.SilencedBy, silenceID)
+	}
+}
+
+type inhibitingRegistry struct {
+	*fakeRegistry
+	stateManager inhibition.InhibitionStateManager
+}
+
+func (r *inhibitingRegistry) InhibitionState() inhibition.InhibitionStateManager {
+	return r.stateManager
+}
+
+func TestAlertsHandler_InhibitedAlertIsSuppressed(t *testing.T) {
+	registry := &inhibitingRegistry{
+		fakeRegistry: &fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: memory.NewSilenceStore()},
+		stateManager: &fakeStateManager{inhibitions: []*inhibition.InhibitionState{
+			{TargetFingerprint: "target", SourceFingerprint: "source", RuleName: "node-down"},
+		}},
+	}
+	now := time.Now().UTC()
+	_ = registry.alertStore.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "NodeDown"}, StartsAt: now.Format(time.RFC3339), Fingerprint: "source", Status: "firing"},
+		{Labels: map[string]string{"alertname": "DiskSlow"}, StartsAt: now.Format(time.RFC3339), Fingerprint: "target", Status: "firing"},
+	}, now)
+
+	alerts := getAlerts(t, AlertsHandler(registry), "")
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(alerts))
+	}
+	for _, alert := range alerts {
+		switch alert.Fingerprint {
+		case "source":
+			if alert.Status.State != "active" || alert.Status.InhibitedBy == nil || len(alert.Status.InhibitedBy) != 0 {
+				t.Errorf("source status = %+v, want active with empty inhibitedBy", alert.Status)
+			}
+		case "target":
+			if alert.Status.State != "suppressed" || len(alert.Status.InhibitedBy) != 1 || alert.Status.InhibitedBy[0] != "source" {
+				t.Errorf("target status = %+v, want suppressed by source", alert.Status)
+			}
+		}
+	}
+}
+
+type mirroringRegistry struct {
+	*fakeRegistry
+	mirror *mirror.Mirror
+}
+
+func (r *mirroringRegistry) WebhookMirror() *mirror.Mirror { return r.mirror }
+
+func TestAlertsHandler_MirrorsAcceptedWebhooks(t *testing.T) {
+	mirrored := make(chan string, 4)
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("peer payload is not gzip: %v", err)
+			return
+		}
+		body, _ := io.ReadAll(zr)
+		mirrored <- r.Header.Get(mirror.HeaderMirroredFrom) + " " + string(body)
+	}))
+	defer peer.Close()
+
+	peerMirror, err := mirror.New(mirror.Config{PeerURL: peer.URL, Origin: "us-east", Registerer: prometheus.NewRegistry()})
+	if err != nil {
+		t.Fatalf("mirror.New() error = %v", err)
+	}
+	peerMirror.Start(context.Background())
+	defer peerMirror.Stop()
+
+	registry := &mirroringRegistry{
+		fakeRegistry: &fakeRegistry{
+			alertStore:   memory.NewAlertStore(),
+			silenceStore: memory.NewSilenceStore(),
+			processor:    newTestProcessor(t, &fakePublisher{}),
+		},
+		mirror: peerMirror,
+	}
+	handler := AlertsHandler(registry)
+	payload := `[{"labels":{"alertname":"Mirrored"},"startsAt":"2026-03-08T10:00:00Z","status":"firing"}]`
+
+	// A gzip-compressed payload from a peer is accepted but not mirrored back.
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write([]byte(payload))
+	_ = zw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/alerts", &compressed)
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set(mirror.HeaderMirroredFrom, "eu-west")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("gzip POST status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	if total, _, _ := registry.alertStore.Stats(); total != 1 {
+		t.Fatalf("expected 1 stored alert, got %d", total)
+	}
+
+	// Rejected payloads are not mirrored either.
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/alerts", bytes.NewBufferString("{")))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid POST status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/alerts", bytes.NewBufferString(payload)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200", rec.Code)
+	}
+
+	select {
+	case got := <-mirrored:
+		if got != "us-east "+payload {
+			t.Errorf("mirrored = %q, want origin us-east and the original payload", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("accepted webhook was not mirrored")
+	}
+	select {
+	case got := <-mirrored:
+		t.Errorf("unexpected mirrored request %q", got)
+	case <-time.After(50 * time.Millisecond):
 	}
 }
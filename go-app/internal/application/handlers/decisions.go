@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// DecisionLogProvider is satisfied by ServiceRegistry.
+type DecisionLogProvider interface {
+	DecisionLog() *memory.DecisionLog
+}
+
+// AlertDecisionsHandler returns GET /api/v2/alerts/{fingerprint}/decisions.
+//
+// Responds with the most recent pipeline decision traces for the alert,
+// newest first. Other paths under /api/v2/alerts/ are not found.
+func AlertDecisionsHandler(registry DecisionLogProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fingerprint, ok := extractDecisionsFingerprint(r.URL.Path)
+		if !ok {
+			NotFoundHandler(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		log := registry.DecisionLog()
+		if log == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "decision log unavailable"})
+			return
+		}
+
+		traces := log.Get(fingerprint)
+		if len(traces) == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no decisions recorded for fingerprint"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, traces)
+	}
+}
+
+// extractDecisionsFingerprint parses /api/v2/alerts/<fingerprint>/decisions.
+func extractDecisionsFingerprint(path string) (string, bool) {
+	return extractAlertResourceFingerprint(path, "/decisions")
+}
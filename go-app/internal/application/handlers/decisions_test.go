@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeDecisionRegistry struct {
+	log *memory.DecisionLog
+}
+
+func (r *fakeDecisionRegistry) DecisionLog() *memory.DecisionLog { return r.log }
+
+func TestAlertDecisionsHandler(t *testing.T) {
+	registry := &fakeDecisionRegistry{log: memory.NewDecisionLog(0, 0)}
+	trace := core.NewDecisionTrace(&core.Alert{Fingerprint: "abc123", AlertName: "HighCPU", Status: core.StatusFiring}, time.Now())
+	trace.Add(core.DecisionStageSilence, "silenced", "", map[string]string{"s1": "true"})
+	trace.Finish(core.DecisionResultSilenced)
+	registry.log.Record(trace)
+
+	handler := AlertDecisionsHandler(registry)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{name: "known fingerprint", method: http.MethodGet, path: "/api/v2/alerts/abc123/decisions", status: http.StatusOK},
+		{name: "unknown fingerprint", method: http.MethodGet, path: "/api/v2/alerts/missing/decisions", status: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodPost, path: "/api/v2/alerts/abc123/decisions", status: http.StatusMethodNotAllowed},
+		{name: "unknown subresource", method: http.MethodGet, path: "/api/v2/alerts/abc123/other", status: http.StatusNotFound},
+		{name: "missing fingerprint", method: http.MethodGet, path: "/api/v2/alerts//decisions", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.status)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/alerts/abc123/decisions", nil))
+	var got []core.DecisionTrace
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(got) != 1 || got[0].Result != core.DecisionResultSilenced || got[0].Decisions[0].Details["s1"] != "true" {
+		t.Fatalf("unexpected traces: %+v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// DeliveryLogProvider is satisfied by ServiceRegistry.
+type DeliveryLogProvider interface {
+	DeliveryLog() *memory.DeliveryLog
+}
+
+// AlertDeliveriesHandler returns GET /api/v2/alerts/{fingerprint}/deliveries.
+//
+// Responds with the most recent publish attempts for the alert, newest
+// first: target, attempt number, status, provider status code, error type
+// and latency.
+func AlertDeliveriesHandler(registry DeliveryLogProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fingerprint, ok := extractAlertResourceFingerprint(r.URL.Path, "/deliveries")
+		if !ok {
+			NotFoundHandler(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		log := registry.DeliveryLog()
+		if log == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "delivery log unavailable"})
+			return
+		}
+
+		attempts := log.Get(fingerprint)
+		if len(attempts) == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no deliveries recorded for fingerprint"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, attempts)
+	}
+}
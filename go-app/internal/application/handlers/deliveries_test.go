@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeDeliveryRegistry struct {
+	log *memory.DeliveryLog
+}
+
+func (r *fakeDeliveryRegistry) DeliveryLog() *memory.DeliveryLog { return r.log }
+
+func TestAlertDeliveriesHandler(t *testing.T) {
+	registry := &fakeDeliveryRegistry{log: memory.NewDeliveryLog(0, 0)}
+	now := time.Now()
+	registry.log.RecordDelivery("abc123", core.DeliveryAttempt{
+		Target: "pagerduty", Attempt: 1, Status: core.DeliveryStatusFailed, StatusCode: http.StatusServiceUnavailable, AttemptedAt: now,
+	})
+	registry.log.RecordDelivery("abc123", core.DeliveryAttempt{
+		Target: "pagerduty", Attempt: 2, Status: core.DeliveryStatusSucceeded, AttemptedAt: now.Add(time.Second),
+	})
+
+	handler := AlertDeliveriesHandler(registry)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{name: "known fingerprint", method: http.MethodGet, path: "/api/v2/alerts/abc123/deliveries", status: http.StatusOK},
+		{name: "unknown fingerprint", method: http.MethodGet, path: "/api/v2/alerts/missing/deliveries", status: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodPost, path: "/api/v2/alerts/abc123/deliveries", status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.status)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/alerts/abc123/deliveries", nil))
+	var got []core.DeliveryAttempt
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(got) != 2 || got[0].Status != core.DeliveryStatusSucceeded || got[1].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected attempts: %+v", got)
+	}
+}
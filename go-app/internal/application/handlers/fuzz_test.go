@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+// FuzzParseLabelMatcher ensures filter query params never panic parsing or matching.
+func FuzzParseLabelMatcher(f *testing.F) {
+	seeds := []string{
+		`alertname="HighCPU"`,
+		`severity!="info"`,
+		`instance=~"prod-.*"`,
+		`job!~"(a|b"`,
+		`name=~"\p{Greek}+"`,
+		`x=~"(?i)ß"`,
+		`a="` + "‮\u0000\xff" + `"`,
+		`=""`,
+		``,
+	}
+	for _, seed := range seeds {
+		f.Add(seed, "value")
+	}
+
+	f.Fuzz(func(t *testing.T, raw, labelValue string) {
+		m, err := ParseLabelMatcher(raw)
+		if err != nil {
+			return
+		}
+		_ = MatchesLabels([]*LabelMatcher{m}, map[string]string{m.Name: labelValue})
+		_ = MatchesLabels([]*LabelMatcher{m}, map[string]string{})
+	})
+}
+
+// FuzzParseAlertsForProcessing ensures the /api/v2/alerts ingest path never panics.
+func FuzzParseAlertsForProcessing(f *testing.F) {
+	seeds := []string{
+		`[{"labels":{"alertname":"A"},"startsAt":"2025-01-01T00:00:00Z"}]`,
+		`[{"labels":{"alertname":"B"},"status":"resolved","endsAt":"2025-01-01T00:00:00+03:00"}]`,
+		`[{"labels":{"alertname":"` + "\U0001F525‍" + `"},"annotations":{"summary":"` + "\xed\xa0\x80" + `"}}]`,
+		`{"alerts":[]}`,
+		`[{"labels":{}}]`,
+		`[{"startsAt":"not-a-time"}]`,
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, body []byte) {
+		alerts, err := parseAlertsForProcessing(body, now, "", 30*time.Second)
+		if err != nil {
+			return
+		}
+		for _, alert := range alerts {
+			if alert == nil {
+				t.Fatal("nil alert without error")
+			}
+		}
+	})
+}
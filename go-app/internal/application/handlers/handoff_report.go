@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// HandoffReportRegistryProvider is satisfied by ServiceRegistry.
+type HandoffReportRegistryProvider interface {
+	HandoffReportScheduler() *services.HandoffReportScheduler
+}
+
+// HandoffReportHandler serves GET /api/v2/reports/handoff?team=<name>: the
+// team's on-call handoff report for the period ending now, as JSON. The
+// report is not published.
+func HandoffReportHandler(registry HandoffReportRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		scheduler := registry.HandoffReportScheduler()
+		if scheduler == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "handoff report is disabled"})
+			return
+		}
+
+		team := r.URL.Query().Get("team")
+		report, ok := scheduler.Report(team)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown team: " + team})
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	}
+}
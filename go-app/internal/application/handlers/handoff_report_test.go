@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/core/silencing"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeHandoffReportRegistry struct {
+	scheduler *services.HandoffReportScheduler
+}
+
+func (r *fakeHandoffReportRegistry) HandoffReportScheduler() *services.HandoffReportScheduler {
+	return r.scheduler
+}
+
+func TestHandoffReportHandler(t *testing.T) {
+	now := time.Now().UTC()
+	alerts := memory.NewAlertStore()
+	if err := alerts.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "DiskFull", "severity": "critical", "team": "payments"}, StartsAt: now.Add(-time.Hour).Format(time.RFC3339)},
+		{Labels: map[string]string{"alertname": "Other", "severity": "critical", "team": "search"}, StartsAt: now.Add(-time.Hour).Format(time.RFC3339)},
+	}, now); err != nil {
+		t.Fatalf("ingest error: %v", err)
+	}
+
+	schedule, err := silencing.ParseCronSchedule("0 9 * * 1", time.UTC)
+	if err != nil {
+		t.Fatalf("schedule error: %v", err)
+	}
+	scheduler, err := services.NewHandoffReportScheduler(
+		services.NewHandoffReportBuilder(alerts, memory.NewSilenceStore(), nil, 0, 0),
+		nil,
+		[]services.HandoffTeam{{
+			Name:         "payments",
+			Target:       "slack-payments",
+			MatchesAlert: func(labels map[string]string) bool { return labels["team"] == "payments" },
+		}},
+		services.HandoffReportSchedulerConfig{Schedule: schedule},
+	)
+	if err != nil {
+		t.Fatalf("scheduler error: %v", err)
+	}
+	handler := HandoffReportHandler(&fakeHandoffReportRegistry{scheduler: scheduler})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/reports/handoff?team=payments", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var report services.HandoffReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if report.Team != "payments" || report.TotalAlerts != 1 || report.UnresolvedTotal != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/reports/handoff?team=unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown team status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	HandoffReportHandler(&fakeHandoffReportRegistry{})(rec, httptest.NewRequest(http.MethodGet, "/api/v2/reports/handoff?team=payments", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+)
+
+const inhibitionSimulateSampleSize = 100
+
+// inhibitionSimulateRequest is the body of POST /api/v1/inhibition/simulate.
+type inhibitionSimulateRequest struct {
+	// Rule is the candidate rule in inhibit_rules syntax.
+	Rule inhibition.InhibitionRule `json:"rule"`
+	// From and To bound the window (RFC3339). By default all alerts kept
+	// by the alert store up to now are replayed.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+type simulatedAlert struct {
+	Fingerprint string            `json:"fingerprint"`
+	Labels      map[string]string `json:"labels"`
+	Status      string            `json:"status"`
+	StartsAt    string            `json:"startsAt"`
+	EndsAt      *string           `json:"endsAt,omitempty"`
+}
+
+type simulatedInhibitionResponse struct {
+	simulatedAlert
+	InhibitedBy simulatedAlert `json:"inhibitedBy"`
+	// Sources is the number of alerts that would have inhibited this one.
+	Sources int `json:"sources"`
+}
+
+type inhibitionSimulateResponse struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	// Evaluated is the number of alerts active within the window.
+	Evaluated int `json:"evaluated"`
+	// Count is the number of alerts the rule would have inhibited; at most
+	// 100 of them are listed.
+	Count     int                           `json:"count"`
+	Inhibited []simulatedInhibitionResponse `json:"inhibited"`
+}
+
+// InhibitionSimulateHandler serves POST /api/v1/inhibition/simulate.
+//
+// Replays the alerts of the alert store against a candidate inhibition rule
+// and reports which of them it would have inhibited, so that new rules can
+// be validated before they are deployed. Nothing is changed.
+func InhibitionSimulateHandler(registry RegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1024*1024))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+			return
+		}
+
+		var in inhibitionSimulateRequest
+		if err := json.Unmarshal(body, &in); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := in.Rule.Validate(); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid rule: " + err.Error()})
+			return
+		}
+		rules := []inhibition.InhibitionRule{in.Rule}
+		if err := inhibition.CompileRules(rules); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid rule: " + err.Error()})
+			return
+		}
+
+		from, err := parseAlertTime(in.From)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid from: " + err.Error()})
+			return
+		}
+		to, err := parseAlertTime(in.To)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid to: " + err.Error()})
+			return
+		}
+		if to.IsZero() {
+			to = time.Now().UTC()
+		}
+		if !from.IsZero() && !from.Before(to) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "from must be before to"})
+			return
+		}
+
+		stored := registry.AlertStore().List("", true)
+		alerts := make([]*core.Alert, 0, len(stored))
+		apiAlerts := make(map[*core.Alert]core.APIAlert, len(stored))
+		for _, apiAlert := range stored {
+			alert, ok := simulationAlert(apiAlert)
+			if !ok {
+				continue
+			}
+			alerts = append(alerts, alert)
+			apiAlerts[alert] = apiAlert
+		}
+
+		inhibited, evaluated := inhibition.SimulateRule(&rules[0], alerts, from, to)
+
+		resp := inhibitionSimulateResponse{
+			To:        to.Format(time.RFC3339),
+			Evaluated: evaluated,
+			Count:     len(inhibited),
+			Inhibited: make([]simulatedInhibitionResponse, 0, min(len(inhibited), inhibitionSimulateSampleSize)),
+		}
+		if !from.IsZero() {
+			resp.From = from.Format(time.RFC3339)
+		}
+		for _, result := range inhibited {
+			if len(resp.Inhibited) == inhibitionSimulateSampleSize {
+				break
+			}
+			resp.Inhibited = append(resp.Inhibited, simulatedInhibitionResponse{
+				simulatedAlert: toSimulatedAlert(apiAlerts[result.Target]),
+				InhibitedBy:    toSimulatedAlert(apiAlerts[result.InhibitedBy]),
+				Sources:        result.Sources,
+			})
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// simulationAlert converts a stored alert for inhibition.SimulateRule.
+func simulationAlert(in core.APIAlert) (*core.Alert, bool) {
+	startsAt, err := parseAlertTime(in.StartsAt)
+	if err != nil {
+		return nil, false
+	}
+	var endsAt *time.Time
+	if in.EndsAt != nil {
+		if endsAt, err = parseOptionalAlertTime(*in.EndsAt); err != nil {
+			return nil, false
+		}
+	}
+	return &core.Alert{
+		Fingerprint: in.Fingerprint,
+		AlertName:   in.Labels["alertname"],
+		Status:      core.AlertStatus(in.Status),
+		Labels:      in.Labels,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+	}, true
+}
+
+func toSimulatedAlert(in core.APIAlert) simulatedAlert {
+	return simulatedAlert{
+		Fingerprint: in.Fingerprint,
+		Labels:      in.Labels,
+		Status:      in.Status,
+		StartsAt:    in.StartsAt,
+		EndsAt:      in.EndsAt,
+	}
+}
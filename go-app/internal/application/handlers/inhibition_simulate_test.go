@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+const simulateRuleBody = `"rule": {
+	"source_match": {"alertname": "NodeDown"},
+	"target_match": {"alertname": "InstanceDown"},
+	"equal": ["node"]
+}`
+
+func newSimulateRegistry(t *testing.T) *fakeRegistry {
+	t.Helper()
+	store := memory.NewAlertStore()
+	err := store.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "NodeDown", "node": "n1"}, StartsAt: "2026-03-01T12:00:00Z", EndsAt: "2026-03-01T12:30:00Z", Status: "resolved"},
+		{Labels: map[string]string{"alertname": "InstanceDown", "node": "n1", "instance": "a"}, StartsAt: "2026-03-01T12:10:00Z", Status: "firing"},
+		{Labels: map[string]string{"alertname": "InstanceDown", "node": "n2", "instance": "b"}, StartsAt: "2026-03-01T12:10:00Z", Status: "firing"},
+	}, time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("IngestBatch() error = %v", err)
+	}
+	return &fakeRegistry{alertStore: store}
+}
+
+func TestInhibitionSimulateHandler(t *testing.T) {
+	handler := InhibitionSimulateHandler(newSimulateRegistry(t))
+
+	body := `{` + simulateRuleBody + `, "to": "2026-03-01T13:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inhibition/simulate", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body=%s", rec.Code, rec.Body.String())
+	}
+	var resp inhibitionSimulateResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Evaluated != 3 || resp.Count != 1 || len(resp.Inhibited) != 1 {
+		t.Fatalf("response = %+v, want 3 evaluated and 1 inhibited", resp)
+	}
+	got := resp.Inhibited[0]
+	if got.Labels["instance"] != "a" || got.InhibitedBy.Labels["alertname"] != "NodeDown" || got.Sources != 1 {
+		t.Errorf("inhibited[0] = %+v, want instance a inhibited by NodeDown", got)
+	}
+}
+
+func TestInhibitionSimulateHandler_WindowExcludesAlerts(t *testing.T) {
+	handler := InhibitionSimulateHandler(newSimulateRegistry(t))
+
+	body := `{` + simulateRuleBody + `, "from": "2026-03-01T12:40:00Z", "to": "2026-03-01T13:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inhibition/simulate", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body=%s", rec.Code, rec.Body.String())
+	}
+	var resp inhibitionSimulateResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Evaluated != 2 || resp.Count != 0 {
+		t.Errorf("response = %+v, want 2 evaluated and none inhibited", resp)
+	}
+}
+
+func TestInhibitionSimulateHandler_InvalidRequests(t *testing.T) {
+	handler := InhibitionSimulateHandler(newSimulateRegistry(t))
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed json", body: `{`},
+		{name: "missing rule", body: `{}`},
+		{name: "invalid regex", body: `{"rule": {"source_match_re": {"alertname": "("}, "target_match": {"alertname": "X"}}}`},
+		{name: "invalid time", body: `{` + simulateRuleBody + `, "from": "yesterday"}`},
+		{name: "from after to", body: `{` + simulateRuleBody + `, "from": "2026-03-01T13:00:00Z", "to": "2026-03-01T12:00:00Z"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inhibition/simulate", bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400, body=%s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+	"github.com/ipiton/AMP/internal/infrastructure/webhook"
+)
+
+// InhibitionSourcesRegistryProvider provides access to the external
+// inhibition sources.
+type InhibitionSourcesRegistryProvider interface {
+	InhibitionSources() *inhibition.ExternalSources
+}
+
+// InhibitionSourcesHandler serves GET /api/v1/inhibition/sources.
+//
+// Lists the external Alertmanager clusters whose alerts act as inhibition
+// sources, with the number of active source alerts and the last update or
+// error of each.
+func InhibitionSourcesHandler(registry InhibitionSourcesRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		sources := registry.InhibitionSources()
+		if sources == nil {
+			writeJSON(w, http.StatusOK, []inhibition.ExternalSourceStatus{})
+			return
+		}
+		writeJSON(w, http.StatusOK, sources.Status())
+	}
+}
+
+// InhibitionSourceWebhookHandler serves
+// POST /api/v1/inhibition/sources/{name}/webhook.
+//
+// Receives the webhook notifications of an external Alertmanager (configure
+// it as a webhook receiver with send_resolved) and applies them to the
+// source: firing alerts become inhibition sources, resolved ones stop
+// inhibiting.
+func InhibitionSourceWebhookHandler(registry InhibitionSourcesRegistryProvider) http.HandlerFunc {
+	parser := webhook.NewAlertmanagerParser()
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/inhibition/sources/"), "/webhook")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			NotFoundHandler(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		sources := registry.InhibitionSources()
+		if sources == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown inhibition source: " + name})
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1024*1024))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+			return
+		}
+		payload, err := parser.Parse(body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		alerts, err := parser.ConvertToDomain(payload)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if err := sources.Apply(name, alerts); err != nil {
+			if errors.Is(err, inhibition.ErrUnknownExternalSource) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown inhibition source: " + name})
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"source": name, "alerts": len(alerts)})
+	}
+}
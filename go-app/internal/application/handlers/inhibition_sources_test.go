@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+)
+
+type fakeInhibitionSourcesRegistry struct {
+	sources *inhibition.ExternalSources
+}
+
+func (r *fakeInhibitionSourcesRegistry) InhibitionSources() *inhibition.ExternalSources {
+	return r.sources
+}
+
+func TestInhibitionSourcesHandler_NotConfigured(t *testing.T) {
+	w := httptest.NewRecorder()
+	InhibitionSourcesHandler(&fakeInhibitionSourcesRegistry{})(w, httptest.NewRequest(http.MethodGet, "/api/v1/inhibition/sources", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+		t.Errorf("body = %s, want []", body)
+	}
+}
+
+func TestInhibitionSourceWebhookHandler(t *testing.T) {
+	sources := inhibition.NewExternalSources()
+	sources.AddSource("legacy", time.Minute)
+	registry := &fakeInhibitionSourcesRegistry{sources: sources}
+	webhookHandler := InhibitionSourceWebhookHandler(registry)
+
+	startsAt := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	payload := `{"version":"4","status":"firing","receiver":"amp","alerts":[` +
+		`{"status":"firing","labels":{"alertname":"NodeDown","node":"node1"},"startsAt":"` + startsAt + `"}]}`
+
+	w := httptest.NewRecorder()
+	webhookHandler(w, httptest.NewRequest(http.MethodPost, "/api/v1/inhibition/sources/legacy/webhook", strings.NewReader(payload)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	InhibitionSourcesHandler(registry)(w, httptest.NewRequest(http.MethodGet, "/api/v1/inhibition/sources", nil))
+	var status []inhibition.ExternalSourceStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if len(status) != 1 || status[0].Name != "legacy" || status[0].Alerts != 1 || status[0].UpdatedAt == nil {
+		t.Fatalf("status = %+v, want legacy with one alert", status)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"unknown source", http.MethodPost, "/api/v1/inhibition/sources/other/webhook", payload, http.StatusNotFound},
+		{"invalid payload", http.MethodPost, "/api/v1/inhibition/sources/legacy/webhook", "{", http.StatusBadRequest},
+		{"get not allowed", http.MethodGet, "/api/v1/inhibition/sources/legacy/webhook", "", http.StatusMethodNotAllowed},
+		{"unknown path", http.MethodPost, "/api/v1/inhibition/sources/legacy", payload, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			webhookHandler(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
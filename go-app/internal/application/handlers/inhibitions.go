@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -63,3 +64,27 @@ func InhibitionsHandler(registry InhibitionsRegistryProvider) http.HandlerFunc {
 		writeJSON(w, http.StatusOK, resp)
 	}
 }
+
+// activeInhibitors maps the fingerprints of inhibited alerts to the
+// fingerprints of the alerts inhibiting them. Errors and a missing state
+// manager yield no inhibitions: GET /api/v2/alerts still lists the alerts.
+func activeInhibitors(ctx context.Context, registry InhibitionsRegistryProvider) map[string][]string {
+	if registry == nil {
+		return nil
+	}
+	stateManager := registry.InhibitionState()
+	if stateManager == nil {
+		return nil
+	}
+
+	inhibitions, err := stateManager.GetActiveInhibitions(ctx)
+	if err != nil {
+		return nil
+	}
+
+	out := make(map[string][]string, len(inhibitions))
+	for _, state := range inhibitions {
+		out[state.TargetFingerprint] = append(out[state.TargetFingerprint], state.SourceFingerprint)
+	}
+	return out
+}
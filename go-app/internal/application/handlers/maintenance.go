@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// maintenanceRetryAfter is the Retry-After hint for alerts rejected while
+// ingestion is paused.
+const maintenanceRetryAfter = "60"
+
+// MaintenanceRegistryProvider is satisfied by ServiceRegistry.
+type MaintenanceRegistryProvider interface {
+	Maintenance() *services.MaintenanceMode
+}
+
+// MaintenanceHandler serves /api/v2/admin/maintenance:
+//   - GET: the current maintenance state
+//   - POST {enabled, reason, pauseIngestion, drainTimeout}: enter or leave
+//     maintenance mode
+//
+// In maintenance mode the publishing queue keeps accepting notifications but
+// holds them; leaving it drains the held notifications. Unlike metrics-only
+// mode nothing is dropped.
+func MaintenanceHandler(registry MaintenanceRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		maintenance := registry.Maintenance()
+		if maintenance == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "maintenance mode is not available"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, maintenance.Status())
+		case http.MethodPost:
+			handleMaintenancePost(maintenance, w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handleMaintenancePost(maintenance *services.MaintenanceMode, w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64*1024))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+
+	var in core.MaintenanceInput
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	status, err := maintenance.Apply(r.Context(), in, time.Now())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// ingestionPaused reports whether maintenance mode rejects incoming alerts.
+func ingestionPaused(registry MaintenanceRegistryProvider) bool {
+	if registry == nil {
+		return false
+	}
+	maintenance := registry.Maintenance()
+	return maintenance != nil && maintenance.IngestionPaused()
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// fakeHold is an in-memory services.PublishingHold.
+type fakeHold struct {
+	paused bool
+	queued int
+}
+
+func (h *fakeHold) Pause() bool {
+	was := h.paused
+	h.paused = true
+	return !was
+}
+
+func (h *fakeHold) Resume() bool {
+	was := h.paused
+	h.paused = false
+	h.queued = 0
+	return was
+}
+
+func (h *fakeHold) GetQueueSize() int { return h.queued }
+
+type maintenanceRegistry struct {
+	*fakeRegistry
+	maintenance *services.MaintenanceMode
+}
+
+func (r *maintenanceRegistry) Maintenance() *services.MaintenanceMode { return r.maintenance }
+
+func postMaintenance(t *testing.T, handler http.HandlerFunc, body string) (int, core.MaintenanceStatus) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/admin/maintenance", strings.NewReader(body)))
+	var status core.MaintenanceStatus
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+	}
+	return rec.Code, status
+}
+
+func TestMaintenanceHandler_EnterAndLeave(t *testing.T) {
+	hold := &fakeHold{}
+	registry := &maintenanceRegistry{maintenance: services.NewMaintenanceMode(hold, slog.New(slog.NewTextHandler(io.Discard, nil)))}
+	handler := MaintenanceHandler(registry)
+
+	code, status := postMaintenance(t, handler, `{"enabled":true,"reason":"Slack outage"}`)
+	if code != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200", code)
+	}
+	if !status.Active || !status.PublishingPaused || status.Reason != "Slack outage" || status.Since == nil {
+		t.Errorf("status = %+v, want active with paused publishing", status)
+	}
+	if !hold.paused {
+		t.Error("publishing was not paused")
+	}
+
+	hold.queued = 3
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/admin/maintenance", nil))
+	if !strings.Contains(rec.Body.String(), `"heldJobs":3`) {
+		t.Errorf("GET body = %s, want 3 held jobs", rec.Body.String())
+	}
+
+	code, status = postMaintenance(t, handler, `{"enabled":false,"drainTimeout":"1s"}`)
+	if code != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200", code)
+	}
+	if status.Active || hold.paused {
+		t.Errorf("status = %+v, paused = %v; want maintenance left", status, hold.paused)
+	}
+	if status.Drained == nil || !*status.Drained {
+		t.Errorf("drained = %v, want true", status.Drained)
+	}
+}
+
+func TestMaintenanceHandler_Validation(t *testing.T) {
+	handler := MaintenanceHandler(&maintenanceRegistry{maintenance: services.NewMaintenanceMode(nil, nil)})
+
+	for _, body := range []string{`not json`, `{"enabled":false,"drainTimeout":"soon"}`, `{"enabled":false,"drainTimeout":"1h"}`} {
+		if code, _ := postMaintenance(t, handler, body); code != http.StatusBadRequest {
+			t.Errorf("POST %s status = %d, want 400", body, code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodDelete, "/api/v2/admin/maintenance", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE status = %d, want 405", rec.Code)
+	}
+}
+
+func TestAlertsHandler_MaintenancePausesIngestion(t *testing.T) {
+	maintenance := services.NewMaintenanceMode(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	registry := &maintenanceRegistry{
+		fakeRegistry: &fakeRegistry{
+			alertStore:   memory.NewAlertStore(),
+			silenceStore: memory.NewSilenceStore(),
+			processor:    newTestProcessor(t, &fakePublisher{}),
+		},
+		maintenance: maintenance,
+	}
+	handler := AlertsHandler(registry)
+	post := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/alerts",
+			strings.NewReader(`[{"labels":{"alertname":"HighCPU"}}]`)))
+		return rec
+	}
+
+	if code, _ := postMaintenance(t, MaintenanceHandler(registry), `{"enabled":true}`); code != http.StatusOK {
+		t.Fatalf("enter status = %d", code)
+	}
+	if rec := post(); rec.Code != http.StatusOK {
+		t.Fatalf("ingestion during maintenance status = %d, want 200", rec.Code)
+	}
+
+	postMaintenance(t, MaintenanceHandler(registry), `{"enabled":true,"pauseIngestion":true}`)
+	rec := post()
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("paused ingestion status = %d, Retry-After = %q; want 503 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	postMaintenance(t, MaintenanceHandler(registry), `{"enabled":false}`)
+	if rec := post(); rec.Code != http.StatusOK {
+		t.Fatalf("ingestion after maintenance status = %d, want 200", rec.Code)
+	}
+}
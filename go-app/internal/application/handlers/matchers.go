@@ -90,19 +90,28 @@ func matchOne(m *LabelMatcher, val string) bool {
 	return false
 }
 
-// MatchesSilenceMatchers returns true if every filter matcher finds at least one silence
-// matcher with the same Name. This implements Alertmanager-compatible silence filtering.
+// MatchesSilenceMatchers reports whether a silence passes the ?filter= matchers.
+//
+// Mirrors Alertmanager: the silence matchers are treated as a label set
+// (name -> value). Positive filters (=, =~) require the name to be present;
+// negative filters (!=, !~) treat a missing name as an empty value.
 func MatchesSilenceMatchers(filters []*LabelMatcher, silenceMatchers []core.APISilenceMatcher) bool {
+	values := make(map[string]string, len(silenceMatchers))
+	for _, sm := range silenceMatchers {
+		values[sm.Name] = sm.Value
+	}
+
 	for _, f := range filters {
-		found := false
-		for _, sm := range silenceMatchers {
-			if sm.Name == f.Name {
-				found = true
-				break
+		val, present := values[f.Name]
+		switch f.Op {
+		case MatcherOpNotEqual, MatcherOpNotRegex:
+			if !matchOne(f, val) {
+				return false
+			}
+		default:
+			if !present || !matchOne(f, val) {
+				return false
 			}
-		}
-		if !found {
-			return false
 		}
 	}
 	return true
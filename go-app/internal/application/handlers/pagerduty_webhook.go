@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/webhooksec"
+)
+
+// AcknowledgementsRegistryProvider provides the alerts acknowledged in
+// incident management systems.
+type AcknowledgementsRegistryProvider interface {
+	AcknowledgementStore() *memory.AcknowledgementStore
+}
+
+// PagerDutyWebhookRegistryProvider is satisfied by ServiceRegistry.
+type PagerDutyWebhookRegistryProvider interface {
+	Config() *appconfig.Config
+	AcknowledgementsRegistryProvider
+}
+
+// pagerDutyWebhookPayload is the subset of a PagerDuty V3 webhook used here.
+type pagerDutyWebhookPayload struct {
+	Event struct {
+		EventType  string    `json:"event_type"`
+		OccurredAt time.Time `json:"occurred_at"`
+		Agent      *struct {
+			Summary string `json:"summary"`
+		} `json:"agent"`
+		Data struct {
+			// IncidentKey is the dedup key of the triggering event: the
+			// fingerprint of the alert for incidents opened by AMP.
+			IncidentKey string `json:"incident_key"`
+			HTMLURL     string `json:"html_url"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+// PagerDutyWebhookHandler serves the PagerDuty V3 webhook.
+//
+// incident.acknowledged marks the alert of the incident acknowledged;
+// incident.unacknowledged, incident.reopened and incident.resolved clear the
+// acknowledgement. Other events, and incidents not opened by AMP, are
+// ignored. Requests must carry a valid PagerDuty signature.
+func PagerDutyWebhookHandler(registry PagerDutyWebhookRegistryProvider) http.HandlerFunc {
+	cfg := registry.Config().PagerDutyWebhook
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.Enabled {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "pagerduty webhook integration is disabled"})
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64*1024))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+			return
+		}
+
+		if err := webhooksec.VerifyPagerDuty(cfg.SigningSecrets, r.Header, body); err != nil {
+			slog.Warn("Rejected PagerDuty webhook", "remote_addr", r.RemoteAddr, "error", err)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid pagerduty signature"})
+			return
+		}
+
+		var payload pagerDutyWebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+			return
+		}
+
+		store := registry.AcknowledgementStore()
+		if store == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "acknowledgements are not available"})
+			return
+		}
+
+		action := applyPagerDutyEvent(store, &payload, time.Now().UTC())
+		writeJSON(w, http.StatusOK, map[string]string{"status": action})
+	}
+}
+
+// acknowledgementStore returns the acknowledgement store of provider, nil
+// when the registry has none.
+func acknowledgementStore(provider AcknowledgementsRegistryProvider) *memory.AcknowledgementStore {
+	if provider == nil {
+		return nil
+	}
+	return provider.AcknowledgementStore()
+}
+
+// acknowledgementOf returns the acknowledgement of a firing alert, nil when
+// it is not acknowledged. Acknowledgements made before the alert started
+// belong to an earlier occurrence and are ignored.
+func acknowledgementOf(store *memory.AcknowledgementStore, alert core.APIAlert, now time.Time) *core.AlertAcknowledgement {
+	if store == nil || alert.Status != "firing" {
+		return nil
+	}
+	ack, at, ok := store.Get(alert.Fingerprint, now)
+	if !ok {
+		return nil
+	}
+	if startsAt, err := time.Parse(time.RFC3339, alert.StartsAt); err == nil && at.Before(startsAt) {
+		return nil
+	}
+	return &ack
+}
+
+// applyPagerDutyEvent records the acknowledgement change of payload and
+// returns what was done with it.
+func applyPagerDutyEvent(store *memory.AcknowledgementStore, payload *pagerDutyWebhookPayload, now time.Time) string {
+	event := payload.Event
+	fingerprint := event.Data.IncidentKey
+	if fingerprint == "" {
+		return "ignored"
+	}
+
+	switch event.EventType {
+	case "incident.acknowledged":
+		at := event.OccurredAt
+		if at.IsZero() {
+			at = now
+		}
+		ack := core.AlertAcknowledgement{Source: "pagerduty", URL: event.Data.HTMLURL}
+		if event.Agent != nil {
+			ack.By = event.Agent.Summary
+		}
+		store.Acknowledge(fingerprint, ack, at)
+		return "acknowledged"
+	case "incident.unacknowledged", "incident.reopened", "incident.resolved":
+		store.Unacknowledge(fingerprint)
+		return "unacknowledged"
+	default:
+		return "ignored"
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/webhooksec"
+)
+
+const testPagerDutySecret = "pd-webhook-secret"
+
+type pagerDutyRegistry struct {
+	fakeRegistry
+	cfg  *appconfig.Config
+	acks *memory.AcknowledgementStore
+}
+
+func (r *pagerDutyRegistry) Config() *appconfig.Config { return r.cfg }
+func (r *pagerDutyRegistry) AcknowledgementStore() *memory.AcknowledgementStore {
+	return r.acks
+}
+
+func newPagerDutyRegistry(t *testing.T) *pagerDutyRegistry {
+	return &pagerDutyRegistry{
+		fakeRegistry: fakeRegistry{
+			alertStore:   memory.NewAlertStore(),
+			silenceStore: memory.NewSilenceStore(),
+			processor:    newTestProcessor(t, &fakePublisher{}),
+		},
+		cfg: &appconfig.Config{PagerDutyWebhook: appconfig.PagerDutyWebhookConfig{
+			Enabled:        true,
+			SigningSecrets: []string{"old-secret", testPagerDutySecret},
+		}},
+		acks: memory.NewAcknowledgementStore(),
+	}
+}
+
+func signedPagerDutyRequest(secret, eventType, incidentKey string, occurredAt time.Time) *http.Request {
+	body := fmt.Sprintf(`{"event":{"event_type":%q,"occurred_at":%q,"agent":{"summary":"Jane Doe"},`+
+		`"data":{"incident_key":%q,"html_url":"https://acme.pagerduty.com/incidents/P1"}}}`,
+		eventType, occurredAt.Format(time.RFC3339), incidentKey)
+	req := httptest.NewRequest(http.MethodPost, "/integrations/pagerduty/webhook", bytes.NewBufferString(body))
+	req.Header.Set(webhooksec.PagerDutySignatureHeader, "v1="+webhooksec.SignHMACSHA256(secret, []byte(body)))
+	return req
+}
+
+func runPagerDutyWebhook(t *testing.T, handler http.HandlerFunc, req *http.Request) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	var resp map[string]string
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp["status"]
+}
+
+func TestPagerDutyWebhookHandler_AcknowledgesAlert(t *testing.T) {
+	registry := newPagerDutyRegistry(t)
+	alerts := AlertsHandler(registry)
+	postAlert(t, alerts, map[string]string{"alertname": "DiskFull"})
+	fingerprint := getAlerts(t, alerts, "")[0].Fingerprint
+
+	handler := PagerDutyWebhookHandler(registry)
+	code, status := runPagerDutyWebhook(t, handler, signedPagerDutyRequest(testPagerDutySecret, "incident.acknowledged", fingerprint, time.Now()))
+	if code != http.StatusOK || status != "acknowledged" {
+		t.Fatalf("webhook = %d %q, want 200 acknowledged", code, status)
+	}
+
+	ack := getAlerts(t, alerts, "")[0].Status.Acknowledged
+	if ack == nil {
+		t.Fatal("expected the alert to be acknowledged")
+	}
+	if ack.Source != "pagerduty" || ack.By != "Jane Doe" || ack.URL != "https://acme.pagerduty.com/incidents/P1" {
+		t.Errorf("unexpected acknowledgement: %+v", ack)
+	}
+
+	code, status = runPagerDutyWebhook(t, handler, signedPagerDutyRequest(testPagerDutySecret, "incident.unacknowledged", fingerprint, time.Now()))
+	if code != http.StatusOK || status != "unacknowledged" {
+		t.Fatalf("webhook = %d %q, want 200 unacknowledged", code, status)
+	}
+	if ack := getAlerts(t, alerts, "")[0].Status.Acknowledged; ack != nil {
+		t.Errorf("expected the acknowledgement cleared, got %+v", ack)
+	}
+}
+
+func TestPagerDutyWebhookHandler_IgnoresAcknowledgementOfEarlierOccurrence(t *testing.T) {
+	registry := newPagerDutyRegistry(t)
+	alerts := AlertsHandler(registry)
+	postAlert(t, alerts, map[string]string{"alertname": "DiskFull"})
+	alert := getAlerts(t, alerts, "")[0]
+
+	startsAt, err := time.Parse(time.RFC3339, alert.StartsAt)
+	if err != nil {
+		t.Fatalf("parse startsAt: %v", err)
+	}
+	registry.acks.Acknowledge(alert.Fingerprint, core.AlertAcknowledgement{Source: "pagerduty"}, startsAt.Add(-time.Minute))
+	if ack := getAlerts(t, alerts, "")[0].Status.Acknowledged; ack != nil {
+		t.Errorf("acknowledgement made before the alert started must be ignored, got %+v", ack)
+	}
+}
+
+func TestPagerDutyWebhookHandler_RejectsInvalidSignature(t *testing.T) {
+	registry := newPagerDutyRegistry(t)
+	handler := PagerDutyWebhookHandler(registry)
+
+	code, _ := runPagerDutyWebhook(t, handler, signedPagerDutyRequest("wrong-secret", "incident.acknowledged", "fp", time.Now()))
+	if code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", code)
+	}
+	if _, _, ok := registry.acks.Get("fp", time.Now()); ok {
+		t.Error("unsigned webhook must not acknowledge alerts")
+	}
+}
+
+func TestPagerDutyWebhookHandler_IgnoresOtherEvents(t *testing.T) {
+	registry := newPagerDutyRegistry(t)
+	handler := PagerDutyWebhookHandler(registry)
+
+	for _, req := range []*http.Request{
+		signedPagerDutyRequest(testPagerDutySecret, "incident.annotated", "fp", time.Now()),
+		signedPagerDutyRequest(testPagerDutySecret, "incident.acknowledged", "", time.Now()),
+	} {
+		if code, status := runPagerDutyWebhook(t, handler, req); code != http.StatusOK || status != "ignored" {
+			t.Errorf("webhook = %d %q, want 200 ignored", code, status)
+		}
+	}
+}
+
+func TestPagerDutyWebhookHandler_Disabled(t *testing.T) {
+	registry := newPagerDutyRegistry(t)
+	registry.cfg.PagerDutyWebhook.Enabled = false
+	handler := PagerDutyWebhookHandler(registry)
+
+	code, _ := runPagerDutyWebhook(t, handler, signedPagerDutyRequest(testPagerDutySecret, "incident.acknowledged", "fp", time.Now()))
+	if code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", code)
+	}
+}
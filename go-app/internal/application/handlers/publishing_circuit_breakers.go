@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// PublishingCircuitBreakers inspects and controls the circuit breakers of
+// the publishing targets.
+type PublishingCircuitBreakers interface {
+	GetTarget(name string) (*core.PublishingTarget, error)
+	CircuitBreakers() []infrapublishing.CircuitBreakerStatus
+	CircuitBreaker(target *core.PublishingTarget) infrapublishing.CircuitBreakerStatus
+	ForceCircuitBreaker(target *core.PublishingTarget, state infrapublishing.CircuitBreakerState) infrapublishing.CircuitBreakerStatus
+	ResetCircuitBreaker(target *core.PublishingTarget) infrapublishing.CircuitBreakerStatus
+}
+
+// PublishingCircuitBreakersRegistryProvider is satisfied by ServiceRegistry.
+type PublishingCircuitBreakersRegistryProvider interface {
+	// PublishingCircuitBreakers returns nil when publishing is not running
+	// (disabled, lite profile, metrics-only fallback).
+	PublishingCircuitBreakers() PublishingCircuitBreakers
+}
+
+// PublishingCircuitBreakersHandler serves GET
+// /api/v1/publishing/circuit-breakers: the circuit breakers of the targets
+// published to, by target name.
+func PublishingCircuitBreakersHandler(registry PublishingCircuitBreakersRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		breakers := registry.PublishingCircuitBreakers()
+		if breakers == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "publishing is not available"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"circuit_breakers": breakers.CircuitBreakers()})
+	}
+}
+
+// PublishingCircuitBreakerHandler serves
+// /api/v1/publishing/circuit-breakers/{target}:
+//   - GET: the circuit breaker of the target
+//   - POST /{target}/open: hold the breaker open, dropping the jobs of the
+//     target, e.g. while its provider has an incident
+//   - POST /{target}/close: hold the breaker closed, publishing to the
+//     target whatever its failures
+//   - POST /{target}/reset: close the breaker and release a held state
+//
+// A held breaker stays in its state until reset. Breakers are kept in
+// memory by each replica.
+func PublishingCircuitBreakerHandler(registry PublishingCircuitBreakersRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		breakers := registry.PublishingCircuitBreakers()
+		if breakers == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "publishing is not available"})
+			return
+		}
+
+		name, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/publishing/circuit-breakers/"), "/")
+		if name == "" || strings.Contains(action, "/") {
+			NotFoundHandler(w, r)
+			return
+		}
+		switch {
+		case action == "" && r.Method != http.MethodGet,
+			action != "" && r.Method != http.MethodPost:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		target, err := breakers.GetTarget(name)
+		if err != nil {
+			writePublishingTargetError(w, err)
+			return
+		}
+		switch action {
+		case "":
+			writeJSON(w, http.StatusOK, breakers.CircuitBreaker(target))
+		case "open":
+			writeJSON(w, http.StatusOK, breakers.ForceCircuitBreaker(target, infrapublishing.StateOpen))
+		case "close":
+			writeJSON(w, http.StatusOK, breakers.ForceCircuitBreaker(target, infrapublishing.StateClosed))
+		case "reset":
+			writeJSON(w, http.StatusOK, breakers.ResetCircuitBreaker(target))
+		default:
+			NotFoundHandler(w, r)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	businesspublishing "github.com/ipiton/AMP/internal/business/publishing"
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// fakePublishingCircuitBreakers knows the "pagerduty" target only.
+type fakePublishingCircuitBreakers struct {
+	state  string
+	forced bool
+}
+
+func (f *fakePublishingCircuitBreakers) GetTarget(name string) (*core.PublishingTarget, error) {
+	if name != "pagerduty" {
+		return nil, &businesspublishing.ErrTargetNotFound{TargetName: name}
+	}
+	return &core.PublishingTarget{Name: name}, nil
+}
+
+func (f *fakePublishingCircuitBreakers) CircuitBreakers() []infrapublishing.CircuitBreakerStatus {
+	return []infrapublishing.CircuitBreakerStatus{f.status("pagerduty")}
+}
+
+func (f *fakePublishingCircuitBreakers) CircuitBreaker(target *core.PublishingTarget) infrapublishing.CircuitBreakerStatus {
+	return f.status(target.Name)
+}
+
+func (f *fakePublishingCircuitBreakers) ForceCircuitBreaker(target *core.PublishingTarget, state infrapublishing.CircuitBreakerState) infrapublishing.CircuitBreakerStatus {
+	f.state, f.forced = state.String(), true
+	return f.status(target.Name)
+}
+
+func (f *fakePublishingCircuitBreakers) ResetCircuitBreaker(target *core.PublishingTarget) infrapublishing.CircuitBreakerStatus {
+	f.state, f.forced = infrapublishing.StateClosed.String(), false
+	return f.status(target.Name)
+}
+
+func (f *fakePublishingCircuitBreakers) status(target string) infrapublishing.CircuitBreakerStatus {
+	return infrapublishing.CircuitBreakerStatus{Target: target, State: f.state, Forced: f.forced}
+}
+
+type fakePublishingCircuitBreakersRegistry struct {
+	breakers PublishingCircuitBreakers
+}
+
+func (r *fakePublishingCircuitBreakersRegistry) PublishingCircuitBreakers() PublishingCircuitBreakers {
+	return r.breakers
+}
+
+func TestPublishingCircuitBreakerHandler(t *testing.T) {
+	breakers := &fakePublishingCircuitBreakers{state: "closed"}
+	handler := PublishingCircuitBreakerHandler(&fakePublishingCircuitBreakersRegistry{breakers: breakers})
+	serve := func(method, path string) (*httptest.ResponseRecorder, infrapublishing.CircuitBreakerStatus) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(method, "/api/v1/publishing/circuit-breakers/"+path, nil))
+		var status infrapublishing.CircuitBreakerStatus
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+		}
+		return rec, status
+	}
+
+	if rec, status := serve(http.MethodPost, "pagerduty/open"); rec.Code != http.StatusOK || status.State != "open" || !status.Forced {
+		t.Fatalf("open = %d %s", rec.Code, rec.Body.String())
+	}
+	if rec, status := serve(http.MethodGet, "pagerduty"); rec.Code != http.StatusOK || status.State != "open" {
+		t.Fatalf("get = %d %s", rec.Code, rec.Body.String())
+	}
+	if rec, status := serve(http.MethodPost, "pagerduty/reset"); rec.Code != http.StatusOK || status.State != "closed" || status.Forced {
+		t.Fatalf("reset = %d %s", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodPost, "slack/open", http.StatusNotFound},
+		{http.MethodPost, "pagerduty/half-open", http.StatusNotFound},
+		{http.MethodGet, "pagerduty/open", http.StatusMethodNotAllowed},
+		{http.MethodPost, "pagerduty", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if rec, _ := serve(tt.method, tt.path); rec.Code != tt.status {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.status)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	PublishingCircuitBreakersHandler(&fakePublishingCircuitBreakersRegistry{})(rec, httptest.NewRequest(http.MethodGet, "/api/v1/publishing/circuit-breakers", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without publishing status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+const (
+	defaultPublishingDeliveriesLimit = 100
+	maxPublishingDeliveriesLimit     = 1000
+)
+
+// PublishingDeliveries lists the publish attempts of alerts.
+type PublishingDeliveries interface {
+	ListDeliveries(ctx context.Context, fingerprint string, limit int) ([]core.DeliveryAttempt, error)
+}
+
+// PublishingDeliveriesRegistryProvider is satisfied by ServiceRegistry.
+type PublishingDeliveriesRegistryProvider interface {
+	// PublishingDeliveries returns the persisted audit log of publish
+	// attempts (persisted true), else the in-memory delivery log, nil if
+	// neither is available.
+	PublishingDeliveries() (deliveries PublishingDeliveries, persisted bool)
+}
+
+// PublishingDeliveriesHandler serves GET
+// /api/v1/publishing/deliveries?fingerprint=<fp>[&limit=<n>]: the audit
+// record of every attempt to publish the alert, newest first (limit
+// defaults to 100, at most 1000). Each record has the job ID, target,
+// status, provider status code, error, latency and the SHA-256 of the
+// payload sent, proving whether the alert was delivered or why not.
+//
+// "persisted" is false when attempts are only kept in memory (no database,
+// or publishing.deliveries.persist disabled): the latest attempts of this
+// replica.
+func PublishingDeliveriesHandler(registry PublishingDeliveriesRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		fingerprint := query.Get("fingerprint")
+		if fingerprint == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "fingerprint is required"})
+			return
+		}
+		limit := defaultPublishingDeliveriesLimit
+		if raw := query.Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxPublishingDeliveriesLimit {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be between 1 and 1000"})
+				return
+			}
+			limit = n
+		}
+
+		deliveries, persisted := registry.PublishingDeliveries()
+		if deliveries == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "delivery log unavailable"})
+			return
+		}
+		attempts, err := deliveries.ListDeliveries(r.Context(), fingerprint, limit)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if attempts == nil {
+			attempts = []core.DeliveryAttempt{}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"fingerprint": fingerprint,
+			"persisted":   persisted,
+			"deliveries":  attempts,
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakePublishingDeliveriesRegistry struct {
+	deliveries PublishingDeliveries
+	persisted  bool
+}
+
+func (r *fakePublishingDeliveriesRegistry) PublishingDeliveries() (PublishingDeliveries, bool) {
+	return r.deliveries, r.persisted
+}
+
+type failingPublishingDeliveries struct{}
+
+func (failingPublishingDeliveries) ListDeliveries(context.Context, string, int) ([]core.DeliveryAttempt, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func TestPublishingDeliveriesHandler(t *testing.T) {
+	log := memory.NewDeliveryLog(0, 0)
+	for attempt := 1; attempt <= 3; attempt++ {
+		log.RecordDelivery("fp1", core.DeliveryAttempt{
+			JobID:       "job-1",
+			Target:      "pagerduty",
+			Attempt:     attempt,
+			Status:      core.DeliveryStatusFailed,
+			StatusCode:  http.StatusBadGateway,
+			PayloadHash: "sha256:abc",
+		})
+	}
+	serve := func(registry PublishingDeliveriesRegistryProvider, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		PublishingDeliveriesHandler(registry)(rec, httptest.NewRequest(http.MethodGet, "/api/v1/publishing/deliveries"+query, nil))
+		return rec
+	}
+
+	rec := serve(&fakePublishingDeliveriesRegistry{deliveries: log}, "?fingerprint=fp1&limit=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Fingerprint string                 `json:"fingerprint"`
+		Persisted   bool                   `json:"persisted"`
+		Deliveries  []core.DeliveryAttempt `json:"deliveries"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if body.Fingerprint != "fp1" || body.Persisted || len(body.Deliveries) != 2 ||
+		body.Deliveries[0].Attempt != 3 || body.Deliveries[0].PayloadHash != "sha256:abc" {
+		t.Fatalf("body = %+v", body)
+	}
+
+	tests := []struct {
+		name     string
+		registry PublishingDeliveriesRegistryProvider
+		query    string
+		status   int
+	}{
+		{"unknown fingerprint", &fakePublishingDeliveriesRegistry{deliveries: log}, "?fingerprint=fp2", http.StatusOK},
+		{"missing fingerprint", &fakePublishingDeliveriesRegistry{deliveries: log}, "", http.StatusBadRequest},
+		{"invalid limit", &fakePublishingDeliveriesRegistry{deliveries: log}, "?fingerprint=fp1&limit=1001", http.StatusBadRequest},
+		{"store error", &fakePublishingDeliveriesRegistry{deliveries: failingPublishingDeliveries{}, persisted: true}, "?fingerprint=fp1", http.StatusInternalServerError},
+		{"unavailable", &fakePublishingDeliveriesRegistry{}, "?fingerprint=fp1", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		if rec := serve(tt.registry, tt.query); rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+	}
+}
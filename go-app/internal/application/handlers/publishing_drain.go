@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+const (
+	defaultPublishingDrainTimeout = 30 * time.Second
+	maxPublishingDrainTimeout     = 5 * time.Minute
+)
+
+// PublishingDrain drains the publishing queue before a shutdown.
+type PublishingDrain interface {
+	Drain(ctx context.Context, timeout time.Duration) (infrapublishing.DrainStatus, error)
+	DrainStatus() infrapublishing.DrainStatus
+}
+
+// PublishingDrainRegistryProvider is satisfied by ServiceRegistry.
+type PublishingDrainRegistryProvider interface {
+	// PublishingDrain returns nil when publishing is not running (disabled,
+	// lite profile, metrics-only fallback).
+	PublishingDrain() PublishingDrain
+}
+
+// PublishingDrainHandler serves /api/v1/publishing/drain:
+//   - GET: the jobs of the publishing queue not yet published
+//   - POST [?timeout=30s]: stop accepting new jobs and wait, at most timeout
+//     (up to 5m), for the queued and in-flight jobs to be published
+//
+// Meant for the preStop hook of rolling deployments: the response reports
+// the jobs left ("drained": false), which Stop would drop once its own
+// timeout fires. The queue keeps draining until the process stops.
+func PublishingDrainHandler(registry PublishingDrainRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		drain := registry.PublishingDrain()
+		if drain == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "publishing is not available"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, drain.DrainStatus())
+		case http.MethodPost:
+			timeout := defaultPublishingDrainTimeout
+			if raw := r.URL.Query().Get("timeout"); raw != "" {
+				d, err := time.ParseDuration(raw)
+				if err != nil || d < 0 || d > maxPublishingDrainTimeout {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": "timeout must be a duration between 0s and 5m"})
+					return
+				}
+				timeout = d
+			}
+			status, err := drain.Drain(r.Context(), timeout)
+			if errors.Is(err, infrapublishing.ErrQueuePaused) {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": "publishing is paused for maintenance"})
+				return
+			}
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, status)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+type fakePublishingDrain struct {
+	paused  bool
+	timeout time.Duration
+	status  infrapublishing.DrainStatus
+}
+
+func (f *fakePublishingDrain) Drain(_ context.Context, timeout time.Duration) (infrapublishing.DrainStatus, error) {
+	if f.paused {
+		return f.status, infrapublishing.ErrQueuePaused
+	}
+	f.timeout = timeout
+	f.status = infrapublishing.DrainStatus{Draining: true, Queued: 2, InFlight: 1}
+	return f.status, nil
+}
+
+func (f *fakePublishingDrain) DrainStatus() infrapublishing.DrainStatus {
+	return f.status
+}
+
+type fakePublishingDrainRegistry struct {
+	drain PublishingDrain
+}
+
+func (r *fakePublishingDrainRegistry) PublishingDrain() PublishingDrain {
+	return r.drain
+}
+
+func TestPublishingDrainHandler(t *testing.T) {
+	drain := &fakePublishingDrain{status: infrapublishing.DrainStatus{Drained: true}}
+	handler := PublishingDrainHandler(&fakePublishingDrainRegistry{drain: drain})
+	serve := func(method, query string) (*httptest.ResponseRecorder, infrapublishing.DrainStatus) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(method, "/api/v1/publishing/drain"+query, nil))
+		var status infrapublishing.DrainStatus
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+		}
+		return rec, status
+	}
+
+	if rec, status := serve(http.MethodGet, ""); rec.Code != http.StatusOK || status.Draining || !status.Drained {
+		t.Fatalf("get = %d %s", rec.Code, rec.Body.String())
+	}
+	rec, status := serve(http.MethodPost, "?timeout=10s")
+	if rec.Code != http.StatusOK || !status.Draining || status.Drained || status.Queued != 2 || status.InFlight != 1 {
+		t.Fatalf("drain = %d %s", rec.Code, rec.Body.String())
+	}
+	if drain.timeout != 10*time.Second {
+		t.Errorf("timeout = %s, want 10s", drain.timeout)
+	}
+	if serve(http.MethodPost, ""); drain.timeout != defaultPublishingDrainTimeout {
+		t.Errorf("default timeout = %s, want %s", drain.timeout, defaultPublishingDrainTimeout)
+	}
+
+	tests := []struct {
+		method, query string
+		status        int
+	}{
+		{http.MethodPost, "?timeout=10m", http.StatusBadRequest},
+		{http.MethodPost, "?timeout=soon", http.StatusBadRequest},
+		{http.MethodDelete, "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if rec, _ := serve(tt.method, tt.query); rec.Code != tt.status {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.query, rec.Code, tt.status)
+		}
+	}
+
+	drain.paused = true
+	if rec, _ := serve(http.MethodPost, ""); rec.Code != http.StatusConflict {
+		t.Errorf("drain while paused status = %d, want 409", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	PublishingDrainHandler(&fakePublishingDrainRegistry{})(rec, httptest.NewRequest(http.MethodPost, "/api/v1/publishing/drain", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without publishing status = %d, want 503", rec.Code)
+	}
+}
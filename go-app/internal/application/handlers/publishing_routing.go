@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// PublishingRouter selects the publishing targets of alerts.
+type PublishingRouter interface {
+	RouteTargets(enrichedAlert *core.EnrichedAlert) infrapublishing.RoutedTargets
+}
+
+// PublishingRouterRegistryProvider is satisfied by ServiceRegistry.
+type PublishingRouterRegistryProvider interface {
+	// PublishingRouter returns nil when publishing is not running
+	// (disabled, lite profile, metrics-only fallback).
+	PublishingRouter() PublishingRouter
+}
+
+// publishingRoutingTestRequest is the body of POST
+// /api/v1/publishing/routing/test.
+type publishingRoutingTestRequest struct {
+	Labels map[string]string `json:"labels"`
+	// Severity is the effective severity of the alert, e.g. the severity
+	// of its classification. Defaults to the severity label.
+	Severity string `json:"severity,omitempty"`
+}
+
+type publishingRoutingTestResponse struct {
+	// Routes are the matched routes, empty when routing is disabled.
+	Routes []string `json:"routes"`
+	// Targets are the enabled targets the alert would be published to.
+	Targets []string `json:"targets"`
+	// MissingTargets are the targets of the matched routes that are not
+	// discovered or disabled.
+	MissingTargets []string `json:"missing_targets,omitempty"`
+}
+
+// PublishingRoutingTestHandler serves POST /api/v1/publishing/routing/test.
+//
+// Reports the targets an alert with the given labels would be published to
+// under publishing.routing, and the routes that select them. Nothing is
+// published.
+func PublishingRoutingTestHandler(registry PublishingRouterRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		router := registry.PublishingRouter()
+		if router == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "publishing is not available"})
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64*1024))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+			return
+		}
+		var in publishingRoutingTestRequest
+		if err := json.Unmarshal(body, &in); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if len(in.Labels) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "labels are required"})
+			return
+		}
+
+		alert := &core.EnrichedAlert{Alert: &core.Alert{Labels: in.Labels}}
+		if in.Severity != "" {
+			alert.EnrichmentMetadata = map[string]any{core.MetadataEffectiveSeverity: in.Severity}
+		}
+		routed := router.RouteTargets(alert)
+
+		resp := publishingRoutingTestResponse{
+			Routes:         routed.Routes,
+			Targets:        make([]string, 0, len(routed.Targets)),
+			MissingTargets: routed.Missing,
+		}
+		if resp.Routes == nil {
+			resp.Routes = []string{}
+		}
+		for _, target := range routed.Targets {
+			resp.Targets = append(resp.Targets, target.Name)
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// fakePublishingRouter routes critical alerts to "pagerduty", the others to
+// "slack".
+type fakePublishingRouter struct{}
+
+func (fakePublishingRouter) RouteTargets(alert *core.EnrichedAlert) infrapublishing.RoutedTargets {
+	if severity, _ := alert.EffectiveSeverity(); severity == core.SeverityCritical {
+		return infrapublishing.RoutedTargets{
+			Routes:  []string{"route.routes[0]"},
+			Targets: []*core.PublishingTarget{{Name: "pagerduty"}},
+			Missing: []string{"oncall"},
+		}
+	}
+	return infrapublishing.RoutedTargets{Routes: []string{"route"}, Targets: []*core.PublishingTarget{{Name: "slack"}}}
+}
+
+type fakePublishingRouterRegistry struct {
+	router PublishingRouter
+}
+
+func (r *fakePublishingRouterRegistry) PublishingRouter() PublishingRouter { return r.router }
+
+func TestPublishingRoutingTestHandler(t *testing.T) {
+	handler := PublishingRoutingTestHandler(&fakePublishingRouterRegistry{router: fakePublishingRouter{}})
+	serve := func(body string) (*httptest.ResponseRecorder, publishingRoutingTestResponse) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/publishing/routing/test", strings.NewReader(body)))
+		var resp publishingRoutingTestResponse
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+		}
+		return rec, resp
+	}
+
+	rec, resp := serve(`{"labels":{"alertname":"DiskFull","severity":"critical"}}`)
+	if rec.Code != http.StatusOK || len(resp.Targets) != 1 || resp.Targets[0] != "pagerduty" ||
+		len(resp.Routes) != 1 || resp.Routes[0] != "route.routes[0]" || len(resp.MissingTargets) != 1 {
+		t.Fatalf("critical alert = %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec, resp = serve(`{"labels":{"alertname":"DiskFull","severity":"critical"},"severity":"warning"}`)
+	if rec.Code != http.StatusOK || len(resp.Targets) != 1 || resp.Targets[0] != "slack" {
+		t.Fatalf("alert with effective severity = %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec, _ := serve(`{"labels":{}}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("no labels status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	PublishingRoutingTestHandler(&fakePublishingRouterRegistry{})(rec, httptest.NewRequest(http.MethodPost, "/api/v1/publishing/routing/test", strings.NewReader(`{"labels":{"a":"b"}}`)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without publishing status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	businesspublishing "github.com/ipiton/AMP/internal/business/publishing"
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// PublishingTargets lists, writes and tests the publishing targets.
+type PublishingTargets interface {
+	businesspublishing.TargetStore
+
+	ListTargets() []*core.PublishingTarget
+	GetTarget(name string) (*core.PublishingTarget, error)
+	DiscoverTargets(ctx context.Context) error
+
+	// TestTarget sends a test alert named alertName to target.
+	TestTarget(ctx context.Context, target *core.PublishingTarget, alertName string) infrapublishing.TestAlertResult
+}
+
+// PublishingTargetsRegistryProvider is satisfied by ServiceRegistry.
+type PublishingTargetsRegistryProvider interface {
+	// PublishingTargets returns nil when publishing is not running
+	// (disabled, lite profile, metrics-only fallback).
+	PublishingTargets() PublishingTargets
+}
+
+// publishingTarget is a publishing target in the API.
+type publishingTarget struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	URL          string            `json:"url"`
+	Enabled      bool              `json:"enabled"`
+	Format       string            `json:"format"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	FilterConfig map[string]any    `json:"filter_config,omitempty"`
+}
+
+// publishingTargetInput is the body of a target creation or update.
+type publishingTargetInput struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	URL          string            `json:"url"`
+	Enabled      *bool             `json:"enabled"` // default true
+	Format       string            `json:"format"`
+	Headers      map[string]string `json:"headers"`
+	FilterConfig map[string]any    `json:"filter_config"`
+}
+
+// publishingTargetTestResult is the outcome of a test alert.
+type publishingTargetTestResult struct {
+	Success    bool                              `json:"success"`
+	Message    string                            `json:"message"`
+	Error      string                            `json:"error,omitempty"`
+	DurationMs int64                             `json:"duration_ms"`
+	Response   *infrapublishing.ProviderResponse `json:"response,omitempty"`
+}
+
+// PublishingTargetsHandler serves /api/v1/publishing/targets:
+//   - GET: the discovered targets
+//   - POST {name, type, url, format, enabled, headers, filter_config}:
+//     create a target, validated as discovered targets are and persisted as
+//     a K8s secret matching the discovery label selector
+func PublishingTargetsHandler(registry PublishingTargetsRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		targets := registry.PublishingTargets()
+		if targets == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "publishing targets are not available"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			list := targets.ListTargets()
+			response := make([]publishingTarget, 0, len(list))
+			for _, target := range list {
+				response = append(response, newPublishingTarget(target))
+			}
+			writeJSON(w, http.StatusOK, response)
+		case http.MethodPost:
+			target, ok := decodePublishingTarget(w, r, "")
+			if !ok {
+				return
+			}
+			if err := targets.CreateTarget(r.Context(), target); err != nil {
+				writePublishingTargetError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, newPublishingTarget(target))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// PublishingTargetHandler serves /api/v1/publishing/targets/{name}:
+//   - GET: the target
+//   - PUT: replace a target created through the API
+//   - DELETE: delete a target created through the API
+//   - POST /{name}/test {alert_name}: send a test alert and return the
+//     response of the provider
+//   - POST /refresh: rediscover the targets now
+//
+// Targets deployed as secrets with the configuration are read-only (409).
+func PublishingTargetHandler(registry PublishingTargetsRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		targets := registry.PublishingTargets()
+		if targets == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "publishing targets are not available"})
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/api/v1/publishing/targets/")
+		if name == "refresh" && r.Method == http.MethodPost {
+			handlePublishingTargetsRefresh(targets, w, r)
+			return
+		}
+		if test, ok := strings.CutSuffix(name, "/test"); ok {
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			handlePublishingTargetTest(targets, test, w, r)
+			return
+		}
+		if name == "" || strings.Contains(name, "/") {
+			NotFoundHandler(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			target, err := targets.GetTarget(name)
+			if err != nil {
+				writePublishingTargetError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, newPublishingTarget(target))
+		case http.MethodPut:
+			target, ok := decodePublishingTarget(w, r, name)
+			if !ok {
+				return
+			}
+			if err := targets.UpdateTarget(r.Context(), target); err != nil {
+				writePublishingTargetError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, newPublishingTarget(target))
+		case http.MethodDelete:
+			if err := targets.DeleteTarget(r.Context(), name); err != nil {
+				writePublishingTargetError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handlePublishingTargetTest(targets PublishingTargets, name string, w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		AlertName string `json:"alert_name"`
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64*1024))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &in); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
+	target, err := targets.GetTarget(name)
+	if err != nil {
+		writePublishingTargetError(w, err)
+		return
+	}
+	if !target.Enabled {
+		writeJSON(w, http.StatusOK, publishingTargetTestResult{Message: "Target is disabled"})
+		return
+	}
+
+	result := targets.TestTarget(r.Context(), target, in.AlertName)
+	writeJSON(w, http.StatusOK, publishingTargetTestResult{
+		Success:    result.Success,
+		Message:    "Test alert sent",
+		Error:      result.Error,
+		DurationMs: result.Duration.Milliseconds(),
+		Response:   result.Response,
+	})
+}
+
+func handlePublishingTargetsRefresh(targets PublishingTargets, w http.ResponseWriter, r *http.Request) {
+	if err := targets.DiscoverTargets(r.Context()); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	list := targets.ListTargets()
+	enabled := 0
+	for _, target := range list {
+		if target.Enabled {
+			enabled++
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success":         true,
+		"message":         "Targets refreshed successfully",
+		"total_targets":   len(list),
+		"enabled_targets": enabled,
+	})
+}
+
+// decodePublishingTarget reads the target of a creation (name "") or of the
+// update of target name.
+func decodePublishingTarget(w http.ResponseWriter, r *http.Request, name string) (*core.PublishingTarget, bool) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1024*1024))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return nil, false
+	}
+
+	var in publishingTargetInput
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	if name != "" {
+		if in.Name != "" && in.Name != name {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "target name cannot be changed"})
+			return nil, false
+		}
+		in.Name = name
+	}
+
+	return &core.PublishingTarget{
+		Name:         in.Name,
+		Type:         in.Type,
+		URL:          in.URL,
+		Enabled:      in.Enabled == nil || *in.Enabled,
+		Format:       core.PublishingFormat(in.Format),
+		Headers:      in.Headers,
+		FilterConfig: in.FilterConfig,
+	}, true
+}
+
+// writePublishingTargetError maps the errors of the target store.
+func writePublishingTargetError(w http.ResponseWriter, err error) {
+	var invalidErr *businesspublishing.InvalidTargetError
+	var notFoundErr *businesspublishing.ErrTargetNotFound
+	switch {
+	case errors.As(err, &invalidErr):
+		details := make([]map[string]string, 0, len(invalidErr.Errors))
+		for _, validationErr := range invalidErr.Errors {
+			details = append(details, map[string]string{"field": validationErr.Field, "message": validationErr.Message})
+		}
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error(), "details": details})
+	case errors.As(err, &notFoundErr):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, businesspublishing.ErrTargetExists), errors.Is(err, businesspublishing.ErrTargetNotManaged):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+func newPublishingTarget(target *core.PublishingTarget) publishingTarget {
+	return publishingTarget{
+		Name:         target.Name,
+		Type:         target.Type,
+		URL:          target.URL,
+		Enabled:      target.Enabled,
+		Format:       string(target.Format),
+		Headers:      target.Headers,
+		FilterConfig: target.FilterConfig,
+	}
+}
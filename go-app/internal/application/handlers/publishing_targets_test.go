@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	businesspublishing "github.com/ipiton/AMP/internal/business/publishing"
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// fakePublishingTargets keeps targets in memory; "configured" is read-only.
+type fakePublishingTargets struct {
+	targets map[string]*core.PublishingTarget
+	tested  []string
+}
+
+func (f *fakePublishingTargets) CreateTarget(_ context.Context, target *core.PublishingTarget) error {
+	if target.URL == "" {
+		return &businesspublishing.InvalidTargetError{Errors: []businesspublishing.ValidationError{
+			businesspublishing.NewValidationError("url", "field is required", ""),
+		}}
+	}
+	if _, ok := f.targets[target.Name]; ok {
+		return businesspublishing.ErrTargetExists
+	}
+	f.targets[target.Name] = target
+	return nil
+}
+
+func (f *fakePublishingTargets) UpdateTarget(_ context.Context, target *core.PublishingTarget) error {
+	if err := f.checkManaged(target.Name); err != nil {
+		return err
+	}
+	f.targets[target.Name] = target
+	return nil
+}
+
+func (f *fakePublishingTargets) DeleteTarget(_ context.Context, name string) error {
+	if err := f.checkManaged(name); err != nil {
+		return err
+	}
+	delete(f.targets, name)
+	return nil
+}
+
+func (f *fakePublishingTargets) checkManaged(name string) error {
+	if name == "configured" {
+		return businesspublishing.ErrTargetNotManaged
+	}
+	if _, ok := f.targets[name]; !ok {
+		return businesspublishing.NewTargetNotFoundError(name)
+	}
+	return nil
+}
+
+func (f *fakePublishingTargets) ListTargets() []*core.PublishingTarget {
+	list := make([]*core.PublishingTarget, 0, len(f.targets))
+	for _, target := range f.targets {
+		list = append(list, target)
+	}
+	return list
+}
+
+func (f *fakePublishingTargets) GetTarget(name string) (*core.PublishingTarget, error) {
+	if target, ok := f.targets[name]; ok {
+		return target, nil
+	}
+	return nil, businesspublishing.NewTargetNotFoundError(name)
+}
+
+func (f *fakePublishingTargets) DiscoverTargets(context.Context) error { return nil }
+
+func (f *fakePublishingTargets) TestTarget(_ context.Context, target *core.PublishingTarget, alertName string) infrapublishing.TestAlertResult {
+	f.tested = append(f.tested, target.Name+"/"+alertName)
+	return infrapublishing.TestAlertResult{
+		Error:    "HTTP 400",
+		Duration: 42 * time.Millisecond,
+		Response: &infrapublishing.ProviderResponse{StatusCode: http.StatusBadRequest, Body: "invalid_payload"},
+	}
+}
+
+type fakePublishingTargetsRegistry struct {
+	targets *fakePublishingTargets
+}
+
+func (r *fakePublishingTargetsRegistry) PublishingTargets() PublishingTargets {
+	if r.targets == nil {
+		return nil
+	}
+	return r.targets
+}
+
+func TestPublishingTargetHandlers_CRUD(t *testing.T) {
+	registry := &fakePublishingTargetsRegistry{targets: &fakePublishingTargets{targets: map[string]*core.PublishingTarget{
+		"configured": {Name: "configured", Type: "slack", URL: "https://hooks.slack.com/services/x", Format: core.FormatSlack, Enabled: true},
+	}}}
+	collection := PublishingTargetsHandler(registry)
+	byName := PublishingTargetHandler(registry)
+	serve := func(handler http.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	body := `{"name":"hooks","type":"webhook","url":"https://hooks.example.com","format":"webhook"}`
+	rec := serve(collection, http.MethodPost, "/api/v1/publishing/targets", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d, want 201; body: %s", rec.Code, rec.Body.String())
+	}
+	if target := registry.targets.targets["hooks"]; target == nil || !target.Enabled {
+		t.Fatalf("created target = %+v, want enabled by default", target)
+	}
+	if rec := serve(collection, http.MethodPost, "/api/v1/publishing/targets", body); rec.Code != http.StatusConflict {
+		t.Fatalf("duplicate POST status = %d, want 409", rec.Code)
+	}
+
+	rec = serve(collection, http.MethodPost, "/api/v1/publishing/targets", `{"name":"broken","type":"webhook","format":"webhook"}`)
+	var invalid struct {
+		Details []map[string]string `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &invalid); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || len(invalid.Details) != 1 || invalid.Details[0]["field"] != "url" {
+		t.Fatalf("invalid POST = %d %s, want 400 with the url error", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(byName, http.MethodPut, "/api/v1/publishing/targets/hooks", `{"type":"webhook","url":"https://hooks.example.com/v2","format":"webhook","enabled":false}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	rec = serve(byName, http.MethodGet, "/api/v1/publishing/targets/hooks", "")
+	var got publishingTarget
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if got.URL != "https://hooks.example.com/v2" || got.Enabled {
+		t.Fatalf("updated target = %+v", got)
+	}
+	if rec := serve(byName, http.MethodPut, "/api/v1/publishing/targets/hooks", `{"name":"other"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("rename PUT status = %d, want 400", rec.Code)
+	}
+
+	if rec := serve(byName, http.MethodDelete, "/api/v1/publishing/targets/configured", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("DELETE of a configured target status = %d, want 409", rec.Code)
+	}
+	if rec := serve(byName, http.MethodDelete, "/api/v1/publishing/targets/hooks", ""); rec.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200", rec.Code)
+	}
+	if rec := serve(byName, http.MethodGet, "/api/v1/publishing/targets/hooks", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET deleted status = %d, want 404", rec.Code)
+	}
+
+	rec = serve(collection, http.MethodGet, "/api/v1/publishing/targets", "")
+	var list []publishingTarget
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "configured" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+}
+
+func TestPublishingTargetHandler_Test(t *testing.T) {
+	registry := &fakePublishingTargetsRegistry{targets: &fakePublishingTargets{targets: map[string]*core.PublishingTarget{
+		"hooks":  {Name: "hooks", Type: "webhook", URL: "https://hooks.example.com", Format: core.FormatWebhook, Enabled: true},
+		"paused": {Name: "paused", Type: "webhook", URL: "https://hooks.example.com", Format: core.FormatWebhook},
+	}}}
+	handler := PublishingTargetHandler(registry)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/publishing/targets/hooks/test", strings.NewReader(`{"alert_name":"Smoke"}`)))
+	var result publishingTargetTestResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if rec.Code != http.StatusOK || result.Success || result.Error != "HTTP 400" || result.DurationMs != 42 ||
+		result.Response == nil || result.Response.StatusCode != http.StatusBadRequest || result.Response.Body != "invalid_payload" {
+		t.Fatalf("test = %d %s", rec.Code, rec.Body.String())
+	}
+	if len(registry.targets.tested) != 1 || registry.targets.tested[0] != "hooks/Smoke" {
+		t.Fatalf("tested = %v", registry.targets.tested)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/publishing/targets/paused/test", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Target is disabled") || len(registry.targets.tested) != 1 {
+		t.Fatalf("disabled target test = %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/publishing/targets/unknown/test", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown target test status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	PublishingTargetHandler(&fakePublishingTargetsRegistry{})(rec, httptest.NewRequest(http.MethodPost, "/api/v1/publishing/targets/hooks/test", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("test without publishing status = %d, want 503", rec.Code)
+	}
+}
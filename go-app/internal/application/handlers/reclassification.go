@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// ReclassificationRegistryProvider is satisfied by ServiceRegistry.
+type ReclassificationRegistryProvider interface {
+	Reclassification() *services.ReclassificationJob
+}
+
+// ReclassificationHandler serves /api/v2/admin/reclassify:
+//   - GET: progress of the current or last job
+//   - POST {from, to, ratePerSecond}: re-classify historical alerts whose
+//     startsAt is within [from, to], e.g. after upgrading the model
+//   - POST {resume: true, ratePerSecond}: resume a cancelled or failed job
+//   - DELETE: cancel the running job
+//
+// The job runs in the background; starting one while another runs
+// returns 409.
+func ReclassificationHandler(registry ReclassificationRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job := registry.Reclassification()
+		if job == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "re-classification is not available (classification disabled)"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, job.Status())
+		case http.MethodPost:
+			handleReclassificationPost(job, w, r)
+		case http.MethodDelete:
+			status, err := job.Cancel()
+			if err != nil {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, status)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handleReclassificationPost(job *services.ReclassificationJob, w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64*1024))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+
+	var in core.ReclassificationInput
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	status, err := job.Start(in, time.Now())
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, services.ErrReclassificationRunning) {
+			code = http.StatusConflict
+		}
+		writeJSON(w, code, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, status)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// emptyHistory is an AlertStorage without historical alerts.
+type emptyHistory struct {
+	core.AlertStorage
+}
+
+func (emptyHistory) ListAlerts(ctx context.Context, filters *core.AlertFilters) (*core.AlertList, error) {
+	return &core.AlertList{}, nil
+}
+
+type noopReclassifier struct{}
+
+func (noopReclassifier) ClassifyAlert(ctx context.Context, alert *core.Alert) (*core.ClassificationResult, error) {
+	return &core.ClassificationResult{Severity: core.SeverityInfo}, nil
+}
+
+func (noopReclassifier) InvalidateCache(ctx context.Context, fingerprint string) error { return nil }
+
+type reclassificationRegistry struct {
+	job *services.ReclassificationJob
+}
+
+func (r *reclassificationRegistry) Reclassification() *services.ReclassificationJob { return r.job }
+
+func TestReclassificationHandler(t *testing.T) {
+	job := services.NewReclassificationJob(noopReclassifier{}, emptyHistory{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handler := ReclassificationHandler(&reclassificationRegistry{job: job})
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(method, "/api/v2/admin/reclassify", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, `{"to":"2026-01-01T00:00:00Z"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST without from = %d, want 400", rec.Code)
+	}
+	if rec := serve(http.MethodDelete, ""); rec.Code != http.StatusConflict {
+		t.Errorf("DELETE without running job = %d, want 409", rec.Code)
+	}
+
+	rec := serve(http.MethodPost, `{"from":"2026-01-01T00:00:00Z","to":"2026-02-01T00:00:00Z","ratePerSecond":5}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST = %d: %s, want 202", rec.Code, rec.Body.String())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		rec = serve(http.MethodGet, "")
+		var status core.ReclassificationStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if status.State == core.ReclassificationCompleted {
+			if status.RatePerSecond != 5 || status.Persisted {
+				t.Errorf("status = %+v", status)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("state = %s, want completed", status.State)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if rec := serve(http.MethodPut, ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("PUT = %d, want 405", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	ReclassificationHandler(&reclassificationRegistry{})(rec, httptest.NewRequest(http.MethodGet, "/api/v2/admin/reclassify", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET without job = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// RecurringSilencesRegistryProvider is satisfied by ServiceRegistry.
+type RecurringSilencesRegistryProvider interface {
+	SilenceStore() *memory.SilenceStore
+	RecurringSilenceStore() *memory.RecurringSilenceStore
+}
+
+// RecurringSilencesHandler serves GET/POST /api/v2/silences/recurring.
+func RecurringSilencesHandler(registry RecurringSilencesRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		store := registry.RecurringSilenceStore()
+		if store == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "recurring silences are not available"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, store.List(time.Now().UTC()))
+		case http.MethodPost:
+			handleRecurringSilencePost(store, w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// RecurringSilenceByIDHandler serves GET/DELETE /api/v2/silences/recurring/{id}.
+//
+// Deleting a recurrence expires its materialized silences that have not
+// started yet; a window already in progress keeps running until it ends or is
+// expired through /api/v2/silence/{id}.
+func RecurringSilenceByIDHandler(registry RecurringSilencesRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		store := registry.RecurringSilenceStore()
+		if store == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "recurring silences are not available"})
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/silences/recurring/")
+		if id == "" || strings.Contains(id, "/") {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": memory.ErrRecurringSilenceNotFound.Error()})
+			return
+		}
+
+		now := time.Now().UTC()
+		switch r.Method {
+		case http.MethodGet:
+			rs, ok := store.Get(id, now)
+			if !ok {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": memory.ErrRecurringSilenceNotFound.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, rs)
+		case http.MethodDelete:
+			silenceIDs, err := store.Delete(id, now)
+			if errors.Is(err, memory.ErrRecurringSilenceNotFound) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			expirePendingSilences(registry.SilenceStore(), silenceIDs, TokenNameFromContext(r.Context()), now)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handleRecurringSilencePost(store *memory.RecurringSilenceStore, w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1024*1024))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+
+	var in core.RecurringSilenceInput
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	id, err := store.Create(&in, time.Now().UTC())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"recurringSilenceID": id})
+}
+
+// expirePendingSilences expires the listed silences that have not started yet.
+func expirePendingSilences(silences *memory.SilenceStore, ids []string, actor string, now time.Time) {
+	if silences == nil {
+		return
+	}
+	for _, id := range ids {
+		if s, ok := silences.Get(id, now); ok && s.Status.State == "pending" {
+			_ = silences.ExpireBy(id, actor, now)
+		}
+	}
+}
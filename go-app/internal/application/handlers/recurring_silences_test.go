@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeRecurringRegistry struct {
+	silences  *memory.SilenceStore
+	recurring *memory.RecurringSilenceStore
+}
+
+func (r *fakeRecurringRegistry) SilenceStore() *memory.SilenceStore { return r.silences }
+func (r *fakeRecurringRegistry) RecurringSilenceStore() *memory.RecurringSilenceStore {
+	return r.recurring
+}
+
+func TestRecurringSilencesHandler_CreateListDelete(t *testing.T) {
+	registry := &fakeRecurringRegistry{
+		silences:  memory.NewSilenceStore(),
+		recurring: memory.NewRecurringSilenceStore(),
+	}
+	collection := RecurringSilencesHandler(registry)
+	byID := RecurringSilenceByIDHandler(registry)
+
+	body := `{"schedule":"0 22 * * 0","timezone":"UTC","duration":"4h",` +
+		`"matchers":[{"name":"env","value":"staging"}],"createdBy":"ops","comment":"weekly maintenance"}`
+	rec := httptest.NewRecorder()
+	collection(rec, httptest.NewRequest(http.MethodPost, "/api/v2/silences/recurring", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var created map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	id := created["recurringSilenceID"]
+
+	rec = httptest.NewRecorder()
+	collection(rec, httptest.NewRequest(http.MethodGet, "/api/v2/silences/recurring", nil))
+	var list []core.APIRecurringSilence
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != id || list[0].Duration != "4h0m0s" || list[0].NextStartsAt == "" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	// Materialize the next window, then delete the recurrence: the pending
+	// silence must be expired with it.
+	now := time.Now().UTC()
+	occurrences := registry.recurring.DueOccurrences(now, 8*24*time.Hour)
+	if len(occurrences) == 0 {
+		t.Fatal("expected a due occurrence within 8 days")
+	}
+	occ := occurrences[0]
+	silenceID, err := registry.silences.CreateOrUpdate(&core.SilenceInput{
+		Matchers:  occ.Matchers,
+		StartsAt:  occ.StartsAt.Format(time.RFC3339),
+		EndsAt:    occ.EndsAt.Format(time.RFC3339),
+		CreatedBy: occ.CreatedBy,
+		Comment:   occ.Comment,
+	}, now)
+	if err != nil {
+		t.Fatalf("CreateOrUpdate() error = %v", err)
+	}
+	registry.recurring.MarkMaterialized(occ, silenceID, now)
+
+	rec = httptest.NewRecorder()
+	byID(rec, httptest.NewRequest(http.MethodDelete, "/api/v2/silences/recurring/"+id, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200", rec.Code)
+	}
+
+	silence, ok := registry.silences.Get(silenceID, time.Now().UTC())
+	if !ok {
+		t.Fatal("materialized silence disappeared")
+	}
+	if occ.StartsAt.After(now) && silence.Status.State != "expired" {
+		t.Errorf("pending silence state = %q, want expired", silence.Status.State)
+	}
+
+	rec = httptest.NewRecorder()
+	byID(rec, httptest.NewRequest(http.MethodGet, "/api/v2/silences/recurring/"+id, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET after delete status = %d, want 404", rec.Code)
+	}
+}
+
+func TestRecurringSilencesHandler_Validation(t *testing.T) {
+	handler := RecurringSilencesHandler(&fakeRecurringRegistry{
+		silences:  memory.NewSilenceStore(),
+		recurring: memory.NewRecurringSilenceStore(),
+	})
+
+	matchers := `"matchers":[{"name":"env","value":"staging"}],"createdBy":"ops","comment":"c"`
+	for _, body := range []string{
+		`{"schedule":"0 22 * *","duration":"4h",` + matchers + `}`,
+		`{"schedule":"0 22 * * 0","duration":"soon",` + matchers + `}`,
+		`{"schedule":"0 22 * * 0","duration":"10s",` + matchers + `}`,
+		`{"schedule":"0 22 * * 0","timezone":"Mars/Olympus","duration":"4h",` + matchers + `}`,
+		`{"schedule":"0 0 31 2 *","duration":"4h",` + matchers + `}`,
+		`{"schedule":"0 22 * * 0","duration":"4h","matchers":[],"createdBy":"ops","comment":"c"}`,
+		`{"schedule":"0 22 * * 0","duration":"4h","matchers":[{"name":"env","value":"staging"}],"comment":"c"}`,
+	} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/silences/recurring", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// RegionSummary is the firing alert load of one region.
+type RegionSummary struct {
+	Region       string   `json:"region"`
+	Zones        []string `json:"zones,omitempty"`
+	FiringAlerts int      `json:"firingAlerts"`
+	// Sources is the number of distinct alert sources (e.g. instances) firing.
+	Sources    int      `json:"sources"`
+	AlertNames []string `json:"alertNames"`
+	// SuspectedOutage is set when Sources reaches the configured threshold.
+	SuspectedOutage bool `json:"suspectedOutage"`
+}
+
+// RegionsHandler serves GET /api/v2/regions: firing alerts aggregated by the
+// region label set by region tagging, flagging regions where many distinct
+// sources fire at once as a suspected regional outage.
+func RegionsHandler(registry RegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		cfg := registry.Config().RegionTagging
+		if !cfg.Enabled {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "region tagging is disabled"})
+			return
+		}
+
+		scope := TokenScopeFromContext(r.Context())
+		alerts := make([]core.APIAlert, 0)
+		for _, alert := range registry.AlertStore().List("firing", false) {
+			if scope.AllowsLabels(alert.Labels) {
+				alerts = append(alerts, alert)
+			}
+		}
+		writeJSON(w, http.StatusOK, summarizeRegions(alerts, cfg.RegionLabel, cfg.ZoneLabel, cfg.SourceLabels, cfg.OutageThreshold))
+	}
+}
+
+// summarizeRegions groups alerts by region. Untagged alerts are skipped.
+// Regions are sorted by distinct sources, most affected first.
+func summarizeRegions(alerts []core.APIAlert, regionLabel, zoneLabel string, sourceLabels []string, outageThreshold int) []RegionSummary {
+	type regionAcc struct {
+		zones      map[string]struct{}
+		sources    map[string]struct{}
+		alertNames map[string]struct{}
+		firing     int
+	}
+	byRegion := make(map[string]*regionAcc)
+
+	for _, alert := range alerts {
+		region := alert.Labels[regionLabel]
+		if region == "" {
+			continue
+		}
+		acc, ok := byRegion[region]
+		if !ok {
+			acc = &regionAcc{
+				zones:      make(map[string]struct{}),
+				sources:    make(map[string]struct{}),
+				alertNames: make(map[string]struct{}),
+			}
+			byRegion[region] = acc
+		}
+		acc.firing++
+		if zone := alert.Labels[zoneLabel]; zone != "" {
+			acc.zones[zone] = struct{}{}
+		}
+		acc.alertNames[alert.Labels["alertname"]] = struct{}{}
+
+		source := alert.Fingerprint
+		for _, name := range sourceLabels {
+			if value := alert.Labels[name]; value != "" {
+				source = value
+				break
+			}
+		}
+		acc.sources[source] = struct{}{}
+	}
+
+	out := make([]RegionSummary, 0, len(byRegion))
+	for region, acc := range byRegion {
+		out = append(out, RegionSummary{
+			Region:          region,
+			Zones:           sortedKeys(acc.zones),
+			FiringAlerts:    acc.firing,
+			Sources:         len(acc.sources),
+			AlertNames:      sortedKeys(acc.alertNames),
+			SuspectedOutage: outageThreshold > 0 && len(acc.sources) >= outageThreshold,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Sources != out[j].Sources {
+			return out[i].Sources > out[j].Sources
+		}
+		return out[i].Region < out[j].Region
+	})
+	return out
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
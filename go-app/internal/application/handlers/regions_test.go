@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type regionsRegistry struct {
+	fakeRegistry
+	cfg *appconfig.Config
+}
+
+func (r *regionsRegistry) Config() *appconfig.Config { return r.cfg }
+
+func TestRegionsHandler(t *testing.T) {
+	now := time.Now().UTC()
+	store := memory.NewAlertStore()
+	inputs := []core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "NodeDown", "region": "eu-west-1", "zone": "eu-west-1a", "instance": "10.1.0.1:9100"}},
+		{Labels: map[string]string{"alertname": "NodeDown", "region": "eu-west-1", "zone": "eu-west-1b", "instance": "10.1.0.2:9100"}},
+		{Labels: map[string]string{"alertname": "HighLatency", "region": "eu-west-1", "instance": "10.1.0.2:9100"}},
+		{Labels: map[string]string{"alertname": "NodeDown", "region": "us-east-1", "instance": "10.2.0.1:9100"}},
+		{Labels: map[string]string{"alertname": "Untagged", "instance": "192.168.0.1:9100"}},
+	}
+	for i := range inputs {
+		inputs[i].StartsAt = now.Add(-time.Minute).Format(time.RFC3339)
+	}
+	if err := store.IngestBatch(inputs, now); err != nil {
+		t.Fatalf("ingest error: %v", err)
+	}
+
+	cfg := &appconfig.Config{RegionTagging: appconfig.RegionTaggingConfig{
+		Enabled:         true,
+		RegionLabel:     "region",
+		ZoneLabel:       "zone",
+		SourceLabels:    []string{"instance"},
+		OutageThreshold: 2,
+	}}
+	handler := RegionsHandler(&regionsRegistry{fakeRegistry: fakeRegistry{alertStore: store}, cfg: cfg})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/regions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var regions []RegionSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &regions); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(regions) != 2 {
+		t.Fatalf("got %d regions, want 2: %+v", len(regions), regions)
+	}
+	eu := regions[0]
+	if eu.Region != "eu-west-1" || eu.FiringAlerts != 3 || eu.Sources != 2 || !eu.SuspectedOutage {
+		t.Errorf("unexpected eu-west-1 summary: %+v", eu)
+	}
+	if len(eu.Zones) != 2 || len(eu.AlertNames) != 2 {
+		t.Errorf("unexpected eu-west-1 zones/alert names: %+v", eu)
+	}
+	if us := regions[1]; us.Region != "us-east-1" || us.SuspectedOutage {
+		t.Errorf("unexpected us-east-1 summary: %+v", us)
+	}
+
+	cfg.RegionTagging.Enabled = false
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/regions", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled status = %d, want 404", rec.Code)
+	}
+}
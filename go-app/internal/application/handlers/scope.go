@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// TokenScope restricts an API token holder to alerts matching Selector.
+//
+// A nil scope is unrestricted (auth disabled or an unscoped token).
+type TokenScope struct {
+	// Name identifies the token in logs.
+	Name string
+	// Selector matchers must all match an alert's labels (AND logic).
+	Selector []*LabelMatcher
+}
+
+type tokenScopeKey struct{}
+
+// WithTokenScope attaches scope to ctx.
+func WithTokenScope(ctx context.Context, scope *TokenScope) context.Context {
+	return context.WithValue(ctx, tokenScopeKey{}, scope)
+}
+
+type tokenNameKey struct{}
+
+// WithTokenName attaches the authenticated token's name to ctx.
+func WithTokenName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, tokenNameKey{}, name)
+}
+
+// TokenNameFromContext returns the authenticated token's name, or "" when
+// auth is disabled.
+func TokenNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(tokenNameKey{}).(string)
+	return name
+}
+
+// TokenScopeFromContext returns the caller's scope, or nil when unrestricted.
+func TokenScopeFromContext(ctx context.Context) *TokenScope {
+	scope, _ := ctx.Value(tokenScopeKey{}).(*TokenScope)
+	return scope
+}
+
+// AllowsLabels reports whether an alert with labels is visible to the scope.
+func (s *TokenScope) AllowsLabels(labels map[string]string) bool {
+	if s == nil {
+		return true
+	}
+	return MatchesLabels(s.Selector, labels)
+}
+
+// AllowsSilence reports whether a silence stays within the scope, i.e. it can
+// only ever mute alerts the token holder may see.
+//
+// Every selector label must be pinned by a positive equality matcher whose
+// value satisfies the selector; regex and negative silence matchers on a
+// selector label could reach outside the scope.
+func (s *TokenScope) AllowsSilence(matchers []core.APISilenceMatcher) bool {
+	if s == nil {
+		return true
+	}
+
+	for _, sel := range s.Selector {
+		pinned := false
+		for _, m := range matchers {
+			if m.Name != sel.Name {
+				continue
+			}
+			if m.IsRegex || !m.IsEqual || !matchOne(sel, m.Value) {
+				return false
+			}
+			pinned = true
+		}
+		if !pinned {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+const silenceApprovalsPrefix = "/api/v2/silences/approvals/"
+
+// SilenceApprovalRegistryProvider is satisfied by ServiceRegistry.
+type SilenceApprovalRegistryProvider interface {
+	SilenceStore() *memory.SilenceStore
+}
+
+// SilenceApprovalsHandler serves GET /api/v2/silences/approvals: silences the
+// silence policy holds until a second person approves them.
+func SilenceApprovalsHandler(registry SilenceApprovalRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, http.StatusOK, registry.SilenceStore().ListApprovals(time.Now().UTC()))
+	}
+}
+
+// SilenceApprovalByIDHandler serves /api/v2/silences/approvals/{id}:
+//   - GET returns the request
+//   - DELETE rejects it
+//   - POST .../approve creates the silence
+//
+// The approver is the API token name; without authentication it is taken from
+// the approvedBy field of the body. Requesters cannot approve their own silences.
+func SilenceApprovalByIDHandler(registry SilenceApprovalRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		store := registry.SilenceStore()
+		now := time.Now().UTC()
+		rest := strings.TrimPrefix(r.URL.Path, silenceApprovalsPrefix)
+		id, action, _ := strings.Cut(rest, "/")
+		if id == "" || (action != "" && action != "approve") {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": memory.ErrSilenceApprovalNotFound.Error()})
+			return
+		}
+
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			req, ok := store.GetApproval(id, now)
+			if !ok {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": memory.ErrSilenceApprovalNotFound.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, req)
+
+		case action == "" && r.Method == http.MethodDelete:
+			if err := store.RejectApproval(id, now); err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+
+		case action == "approve" && r.Method == http.MethodPost:
+			approver := TokenNameFromContext(r.Context())
+			if approver == "" {
+				var body struct {
+					ApprovedBy string `json:"approvedBy"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+					return
+				}
+				approver = body.ApprovedBy
+			}
+
+			silenceID, err := store.Approve(id, approver, now)
+			switch {
+			case errors.Is(err, memory.ErrSilenceApprovalNotFound):
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			case errors.Is(err, core.ErrSilencePolicyViolation):
+				writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+			case err != nil:
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			default:
+				writeJSON(w, http.StatusOK, map[string]string{"silenceID": silenceID})
+			}
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeSilenceApprovalRegistry struct {
+	silences *memory.SilenceStore
+}
+
+func (r *fakeSilenceApprovalRegistry) SilenceStore() *memory.SilenceStore { return r.silences }
+
+func TestSilenceApprovalFlow(t *testing.T) {
+	store := memory.NewSilenceStore()
+	store.SetPolicy(func(in *core.SilenceInput, _ time.Time) error {
+		if in.ApprovedBy == "" {
+			return core.ErrSilenceApprovalRequired
+		}
+		if in.ApprovedBy == in.Actor {
+			return core.ErrSilencePolicyViolation
+		}
+		return nil
+	})
+	now := time.Now().UTC()
+	body := `{"matchers":[{"name":"namespace","value":"production"}],"startsAt":"` + now.Format(time.RFC3339) +
+		`","endsAt":"` + now.Add(time.Hour).Format(time.RFC3339) + `","createdBy":"bob","comment":"OPS-1"}`
+
+	rec := httptest.NewRecorder()
+	handleSilencePost(store, rec, httptest.NewRequest(http.MethodPost, "/api/v2/silences", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("create status = %d, want 202; body: %s", rec.Code, rec.Body.String())
+	}
+	var pending map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &pending); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if pending["status"] != "pending_approval" || pending["approvalID"] == "" {
+		t.Fatalf("unexpected response: %+v", pending)
+	}
+
+	registry := &fakeSilenceApprovalRegistry{silences: store}
+	rec = httptest.NewRecorder()
+	SilenceApprovalsHandler(registry)(rec, httptest.NewRequest(http.MethodGet, "/api/v2/silences/approvals", nil))
+	var list []core.SilenceApprovalRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list) != 1 {
+		t.Fatalf("list = %s (err %v), want one request", rec.Body.String(), err)
+	}
+
+	approve := func(approver string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		path := "/api/v2/silences/approvals/" + pending["approvalID"] + "/approve"
+		SilenceApprovalByIDHandler(registry)(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"approvedBy":"`+approver+`"}`)))
+		return rec
+	}
+	if rec := approve("bob"); rec.Code != http.StatusForbidden {
+		t.Errorf("self-approval status = %d, want 403", rec.Code)
+	}
+	rec = approve("carol")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("approve status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	if got := store.List(now); len(got) != 1 || got[0].Status.State != "active" {
+		t.Errorf("expected one active silence, got %+v", got)
+	}
+	if rec := approve("carol"); rec.Code != http.StatusNotFound {
+		t.Errorf("second approval status = %d, want 404", rec.Code)
+	}
+}
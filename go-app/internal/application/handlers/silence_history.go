@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// SilenceHistoryRegistryProvider is satisfied by ServiceRegistry.
+type SilenceHistoryRegistryProvider interface {
+	SilenceStore() *memory.SilenceStore
+	SilenceAudit() core.SilenceAuditStore
+}
+
+// SilenceHistoryHandler serves GET /api/v2/silences/{id}/history: the
+// silence's audit trail (who created, updated or expired it, when, and what
+// changed), oldest first. History is kept for silences that no longer exist.
+func SilenceHistoryHandler(registry SilenceHistoryRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v2/silences/"), "/history")
+		if !ok || id == "" || strings.Contains(id, "/") {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{
+				"error": "silenceID in path must be of type uuid: " + strconv.Quote(id),
+			})
+			return
+		}
+
+		audit := registry.SilenceAudit()
+		if audit == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "silence audit trail is not available"})
+			return
+		}
+		events, err := audit.ListSilenceAudit(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		current, exists := registry.SilenceStore().Get(id, time.Now().UTC())
+		if !exists && len(events) == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": memory.ErrSilenceNotFound.Error()})
+			return
+		}
+
+		// Silences outside the caller's scope are reported as not found. A
+		// deleted silence is checked against its last recorded state.
+		if scope := TokenScopeFromContext(r.Context()); scope != nil {
+			matchers := current.Matchers
+			if !exists {
+				matchers = lastAuditedMatchers(events)
+			}
+			if !scope.AllowsSilence(matchers) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": memory.ErrSilenceNotFound.Error()})
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, events)
+	}
+}
+
+func lastAuditedMatchers(events []core.SilenceAuditEvent) []core.APISilenceMatcher {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].After != nil {
+			return events[i].After.Matchers
+		}
+		if events[i].Before != nil {
+			return events[i].Before.Matchers
+		}
+	}
+	return nil
+}
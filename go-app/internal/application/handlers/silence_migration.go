@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/alertmanager"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+const (
+	// maxSilenceImportSize caps uploaded snapshot and JSON imports.
+	maxSilenceImportSize = 64 * 1024 * 1024
+	// silenceSnapshotRetention matches Alertmanager's default
+	// --data.retention, after which it drops expired silences.
+	silenceSnapshotRetention = 120 * time.Hour
+)
+
+// SilenceMigrationRegistryProvider is satisfied by ServiceRegistry.
+type SilenceMigrationRegistryProvider interface {
+	SilenceStore() *memory.SilenceStore
+	SilenceMigrator() *services.SilenceMigrator
+	// MigrationAlertmanager returns nil when no Alertmanager is configured.
+	MigrationAlertmanager() (services.SilenceExportTarget, error)
+}
+
+// SilenceImportHandler serves POST /api/v2/silences/import. The source query
+// parameter selects where silences come from:
+//   - alertmanager: pulled from the configured Alertmanager's API
+//   - snapshot: the request body is an Alertmanager silences snapshot file
+//   - json: the request body is a silence list as returned by GET /api/v2/silences
+//
+// Expired silences and silences that already exist are skipped. With
+// dryRun=true nothing is created.
+func SilenceImportHandler(registry SilenceMigrationRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		defer r.Body.Close()
+
+		dryRun, ok := parseDryRun(w, r)
+		if !ok {
+			return
+		}
+		now := time.Now().UTC()
+
+		var silences []core.APISilence
+		switch source := r.URL.Query().Get("source"); source {
+		case "alertmanager":
+			client, ok := migrationAlertmanager(registry, w)
+			if !ok {
+				return
+			}
+			var err error
+			if silences, err = client.ListSilences(r.Context()); err != nil {
+				writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+				return
+			}
+		case "snapshot", "json":
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSilenceImportSize))
+			if err != nil {
+				writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+				return
+			}
+			if source == "snapshot" {
+				silences, err = alertmanager.ReadSilenceSnapshot(bytes.NewReader(body), now)
+			} else {
+				err = json.Unmarshal(body, &silences)
+			}
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid " + source + ": " + err.Error()})
+				return
+			}
+		default:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "source must be one of alertmanager, snapshot, json"})
+			return
+		}
+
+		report := registry.SilenceMigrator().Import(silences, services.SilenceMigrationOptions{
+			DryRun: dryRun,
+			Actor:  TokenNameFromContext(r.Context()),
+		}, now)
+		writeJSON(w, http.StatusOK, report)
+	}
+}
+
+// SilenceExportHandler serves /api/v2/silences/export.
+//
+// GET downloads the unexpired silences, as JSON by default or as an
+// Alertmanager silences snapshot file with format=snapshot. POST creates them
+// on the configured Alertmanager, skipping silences it already has; with
+// dryRun=true nothing is created.
+func SilenceExportHandler(registry SilenceMigrationRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleSilenceExportGet(registry.SilenceStore(), w, r)
+		case http.MethodPost:
+			dryRun, ok := parseDryRun(w, r)
+			if !ok {
+				return
+			}
+			client, ok := migrationAlertmanager(registry, w)
+			if !ok {
+				return
+			}
+			report, err := registry.SilenceMigrator().Export(r.Context(), client,
+				services.SilenceMigrationOptions{DryRun: dryRun}, time.Now().UTC())
+			if err != nil {
+				writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, report)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handleSilenceExportGet(store *memory.SilenceStore, w http.ResponseWriter, r *http.Request) {
+	all := store.List(time.Now().UTC())
+	silences := make([]core.APISilence, 0, len(all))
+	for _, s := range all {
+		if s.Status.State != "expired" {
+			silences = append(silences, s)
+		}
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		writeJSON(w, http.StatusOK, silences)
+	case "snapshot":
+		var buf bytes.Buffer
+		if err := alertmanager.WriteSilenceSnapshot(&buf, silences, silenceSnapshotRetention); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="silences"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf.Bytes())
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "format must be json or snapshot"})
+	}
+}
+
+func migrationAlertmanager(registry SilenceMigrationRegistryProvider, w http.ResponseWriter) (services.SilenceExportTarget, bool) {
+	client, err := registry.MigrationAlertmanager()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	if client == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "silence_migration.alertmanager_url is not configured"})
+		return nil, false
+	}
+	return client, true
+}
+
+func parseDryRun(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	raw := r.URL.Query().Get("dryRun")
+	if raw == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(raw)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "dryRun must be a boolean"})
+		return false, false
+	}
+	return dryRun, true
+}
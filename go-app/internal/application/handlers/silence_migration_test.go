@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeSilenceMigrationRegistry struct {
+	store *memory.SilenceStore
+}
+
+func (r *fakeSilenceMigrationRegistry) SilenceStore() *memory.SilenceStore { return r.store }
+
+func (r *fakeSilenceMigrationRegistry) SilenceMigrator() *services.SilenceMigrator {
+	return services.NewSilenceMigrator(r.store)
+}
+
+func (r *fakeSilenceMigrationRegistry) MigrationAlertmanager() (services.SilenceExportTarget, error) {
+	return nil, nil
+}
+
+func TestSilenceMigrationHandlers_SnapshotRoundTrip(t *testing.T) {
+	now := time.Now().UTC()
+	source := &fakeSilenceMigrationRegistry{store: memory.NewSilenceStore()}
+	if _, err := source.store.CreateOrUpdate(&core.SilenceInput{
+		Matchers:  []core.SilenceMatcherInput{{Name: "job", Value: "api"}},
+		StartsAt:  now.Format(time.RFC3339),
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "ops",
+		Comment:   "maintenance",
+	}, now); err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	SilenceExportHandler(source)(rec, httptest.NewRequest(http.MethodGet, "/api/v2/silences/export?format=snapshot", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	snapshot := rec.Body.Bytes()
+
+	dest := &fakeSilenceMigrationRegistry{store: memory.NewSilenceStore()}
+	importHandler := SilenceImportHandler(dest)
+
+	rec = httptest.NewRecorder()
+	importHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/silences/import?source=snapshot&dryRun=true", bytes.NewReader(snapshot)))
+	if rec.Code != http.StatusOK || len(dest.store.List(now)) != 0 {
+		t.Fatalf("dry run status = %d, silences = %d", rec.Code, len(dest.store.List(now)))
+	}
+
+	rec = httptest.NewRecorder()
+	importHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/silences/import?source=snapshot", bytes.NewReader(snapshot)))
+	var report services.SilenceMigrationReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if report.Migrated != 1 {
+		t.Fatalf("migrated = %d, want 1; body: %s", report.Migrated, rec.Body.String())
+	}
+	silences := dest.store.List(now)
+	if len(silences) != 1 || silences[0].Comment != "maintenance" || silences[0].Matchers[0].Value != "api" {
+		t.Errorf("unexpected imported silences: %+v", silences)
+	}
+}
+
+func TestSilenceMigrationHandlers_Errors(t *testing.T) {
+	registry := &fakeSilenceMigrationRegistry{store: memory.NewSilenceStore()}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		path    string
+		body    string
+		status  int
+	}{
+		{"invalid json", SilenceImportHandler(registry), http.MethodPost, "/api/v2/silences/import?source=json", "{", http.StatusBadRequest},
+		{"invalid dry run", SilenceImportHandler(registry), http.MethodPost, "/api/v2/silences/import?source=json&dryRun=maybe", "[]", http.StatusBadRequest},
+		{"alertmanager not configured", SilenceImportHandler(registry), http.MethodPost, "/api/v2/silences/import?source=alertmanager", "", http.StatusNotFound},
+		{"import get", SilenceImportHandler(registry), http.MethodGet, "/api/v2/silences/import", "", http.StatusMethodNotAllowed},
+		{"export unknown format", SilenceExportHandler(registry), http.MethodGet, "/api/v2/silences/export?format=xml", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		tt.handler(rec, httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// silenceStatsEntry is one silence in the GET /api/v2/silences/stats body.
+type silenceStatsEntry struct {
+	core.APISilence
+	core.SilenceMatchStats
+}
+
+// SilenceStatsHandler serves GET /api/v2/silences/stats: every silence with
+// the number of firing alerts it suppressed and when it last matched, most
+// matches first. Statistics are kept per replica since its start.
+//
+// Query parameters:
+//   - filter: label matchers the silence matchers must satisfy, as in GET /api/v2/silences
+//   - unmatched=true: only silences that never matched, e.g. to find stale silences
+func SilenceStatsHandler(registry RegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		filters, err := ParseLabelMatchers(r.URL.Query()["filter"])
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		unmatchedOnly := parseBoolQueryLenient(r.URL.Query().Get("unmatched"), false)
+
+		store := registry.SilenceStore()
+		stats := store.MatchStats()
+		scope := TokenScopeFromContext(r.Context())
+
+		result := make([]silenceStatsEntry, 0, len(stats))
+		for _, silence := range store.List(time.Now().UTC()) {
+			if !scope.AllowsSilence(silence.Matchers) || !MatchesSilenceMatchers(filters, silence.Matchers) {
+				continue
+			}
+			entry := silenceStatsEntry{APISilence: silence, SilenceMatchStats: stats[silence.ID]}
+			if unmatchedOnly && entry.MatchCount > 0 {
+				continue
+			}
+			result = append(result, entry)
+		}
+
+		sort.SliceStable(result, func(i, j int) bool {
+			if result[i].MatchCount != result[j].MatchCount {
+				return result[i].MatchCount > result[j].MatchCount
+			}
+			return result[i].ID < result[j].ID
+		})
+		writeJSON(w, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+func getSilenceStats(t *testing.T, handler http.HandlerFunc, query string) []silenceStatsEntry {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/silences/stats?"+query, nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var entries []silenceStatsEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	return entries
+}
+
+func TestSilenceStatsHandler(t *testing.T) {
+	store := memory.NewSilenceStore()
+	handler := SilenceStatsHandler(&fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: store})
+
+	busy := createSilence(t, store, []core.SilenceMatcherInput{{Name: "alertname", Value: "HighCPU"}})
+	zombie := createSilence(t, store, []core.SilenceMatcherInput{{Name: "alertname", Value: "Retired"}})
+	matchedAt := time.Now().UTC().Truncate(time.Second)
+	store.RecordMatches([]string{busy}, matchedAt)
+	store.RecordMatches([]string{busy}, matchedAt)
+
+	entries := getSilenceStats(t, handler, "")
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].ID != busy || entries[0].MatchCount != 2 {
+		t.Errorf("first entry = %s with %d matches, want %s with 2", entries[0].ID, entries[0].MatchCount, busy)
+	}
+	if entries[0].LastMatchedAt == nil || !entries[0].LastMatchedAt.Equal(matchedAt) {
+		t.Errorf("lastMatchedAt = %v, want %v", entries[0].LastMatchedAt, matchedAt)
+	}
+	if entries[1].ID != zombie || entries[1].MatchCount != 0 || entries[1].LastMatchedAt != nil {
+		t.Errorf("second entry = %+v, want unmatched %s", entries[1].SilenceMatchStats, zombie)
+	}
+
+	entries = getSilenceStats(t, handler, "unmatched=true")
+	if len(entries) != 1 || entries[0].ID != zombie {
+		t.Errorf("unmatched entries = %+v, want only %s", entries, zombie)
+	}
+
+	entries = getSilenceStats(t, handler, `filter=alertname%3D"HighCPU"`)
+	if len(entries) != 1 || entries[0].ID != busy {
+		t.Errorf("filtered entries = %+v, want only %s", entries, busy)
+	}
+}
+
+func TestSilenceStatsHandler_MethodNotAllowed(t *testing.T) {
+	handler := SilenceStatsHandler(&fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: memory.NewSilenceStore()})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/silences/stats", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
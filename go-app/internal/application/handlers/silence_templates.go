@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+const silenceFromTemplatePrefix = "/api/v2/silences/from-template/"
+
+// SilenceTemplateRegistryProvider is satisfied by ServiceRegistry.
+type SilenceTemplateRegistryProvider interface {
+	Config() *appconfig.Config
+	SilenceStore() *memory.SilenceStore
+}
+
+type silenceFromTemplateRequest struct {
+	Params    map[string]string `json:"params"`
+	Duration  string            `json:"duration"`
+	StartsAt  string            `json:"startsAt"`
+	CreatedBy string            `json:"createdBy"`
+}
+
+// SilenceFromTemplateHandler serves POST /api/v2/silences/from-template/{name}.
+// The named template from the silence_templates config is rendered with the
+// request parameters and created like a regular silence. createdBy defaults to
+// the API token name.
+func SilenceFromTemplateHandler(registry SilenceTemplateRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		defer r.Body.Close()
+
+		name := strings.TrimPrefix(r.URL.Path, silenceFromTemplatePrefix)
+		var cfg *appconfig.SilenceTemplateConfig
+		templates := registry.Config().SilenceTemplates
+		for i := range templates {
+			if templates[i].Name == name {
+				cfg = &templates[i]
+				break
+			}
+		}
+		if cfg == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "silence template not found"})
+			return
+		}
+		tmpl, err := silenceTemplateFromConfig(*cfg)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		var body silenceFromTemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+			return
+		}
+		req := services.SilenceTemplateRequest{Params: body.Params, CreatedBy: body.CreatedBy}
+		if body.Duration != "" {
+			if req.Duration, err = time.ParseDuration(body.Duration); err != nil || req.Duration <= 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "duration must be a positive duration such as 2h"})
+				return
+			}
+		}
+		if body.StartsAt != "" {
+			if req.StartsAt, err = time.Parse(time.RFC3339, body.StartsAt); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "startsAt must be an RFC3339 timestamp"})
+				return
+			}
+		}
+		if req.CreatedBy == "" {
+			req.CreatedBy = TokenNameFromContext(r.Context())
+		}
+
+		in, err := tmpl.Render(req, time.Now().UTC())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		saveSilence(registry.SilenceStore(), *in, w, r)
+	}
+}
+
+// silenceTemplateFromConfig parses the templates of a configured silence template.
+func silenceTemplateFromConfig(cfg appconfig.SilenceTemplateConfig) (*services.SilenceTemplate, error) {
+	tmpl := &services.SilenceTemplate{
+		Name:           cfg.Name,
+		Duration:       cfg.Duration,
+		MaxDuration:    cfg.MaxDuration,
+		RequiredParams: cfg.RequiredParams,
+	}
+	for _, m := range cfg.Matchers {
+		value, err := services.ParseSilenceTemplateText(m.Name, m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("silence template %q matcher %q: %w", cfg.Name, m.Name, err)
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		tmpl.Matchers = append(tmpl.Matchers, services.SilenceTemplateMatcher{
+			Name:    m.Name,
+			Value:   value,
+			IsRegex: m.IsRegex,
+			IsEqual: isEqual,
+		})
+	}
+	comment, err := services.ParseSilenceTemplateText("comment", cfg.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("silence template %q comment: %w", cfg.Name, err)
+	}
+	tmpl.Comment = comment
+	return tmpl, nil
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeSilenceTemplateRegistry struct {
+	config   *appconfig.Config
+	silences *memory.SilenceStore
+}
+
+func (r *fakeSilenceTemplateRegistry) Config() *appconfig.Config          { return r.config }
+func (r *fakeSilenceTemplateRegistry) SilenceStore() *memory.SilenceStore { return r.silences }
+
+func TestSilenceFromTemplateHandler(t *testing.T) {
+	registry := &fakeSilenceTemplateRegistry{
+		config: &appconfig.Config{SilenceTemplates: []appconfig.SilenceTemplateConfig{{
+			Name: "node-reboot",
+			Matchers: []appconfig.SilenceTemplateMatcherConfig{
+				{Name: "instance", Value: "{{ .instance }}"},
+			},
+			Duration:       time.Hour,
+			MaxDuration:    4 * time.Hour,
+			Comment:        "Reboot of {{ .instance }} ({{ .ticket }})",
+			RequiredParams: []string{"instance", "ticket"},
+		}}},
+		silences: memory.NewSilenceStore(),
+	}
+	handler := SilenceFromTemplateHandler(registry)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post("/api/v2/silences/from-template/node-reboot", `{"params":{"instance":"db-1","ticket":"OPS-42"},"duration":"2h","createdBy":"alice"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	silence, ok := registry.silences.Get(resp["silenceID"], time.Now().UTC())
+	if !ok {
+		t.Fatalf("silence %q not stored", resp["silenceID"])
+	}
+	if silence.Comment != "Reboot of db-1 (OPS-42)" || silence.CreatedBy != "alice" {
+		t.Errorf("unexpected silence: %+v", silence)
+	}
+	if len(silence.Matchers) != 1 || silence.Matchers[0].Value != "db-1" || !silence.Matchers[0].IsEqual {
+		t.Errorf("unexpected matchers: %+v", silence.Matchers)
+	}
+
+	if rec := post("/api/v2/silences/from-template/node-reboot", `{"params":{"instance":"db-1"}}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing param status = %d, want 400", rec.Code)
+	}
+	if rec := post("/api/v2/silences/from-template/node-reboot", `{"params":{"instance":"db-1","ticket":"OPS-42"},"duration":"8h"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("duration above max status = %d, want 400", rec.Code)
+	}
+	if rec := post("/api/v2/silences/from-template/unknown", `{}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown template status = %d, want 404", rec.Code)
+	}
+}
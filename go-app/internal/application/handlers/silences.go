@@ -2,15 +2,28 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
 	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
 )
 
+// silencePreviewSampleSize caps the alerts returned by the silence preview.
+const silencePreviewSampleSize = 20
+
+// silencePreviewResponse is the body returned by POST /api/v2/silences/preview.
+type silencePreviewResponse struct {
+	Count  int                     `json:"count"`
+	Alerts []core.APIGettableAlert `json:"alerts"`
+}
+
 func SilencesHandler(registry RegistryProvider) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		store := registry.SilenceStore()
@@ -36,6 +49,30 @@ func SilenceByIDHandler(registry RegistryProvider) http.HandlerFunc {
 			return
 		}
 
+		switch r.Method {
+		case http.MethodGet, http.MethodDelete:
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		// silenceID is declared as format: uuid in the Alertmanager OpenAPI spec.
+		if _, err := uuid.Parse(id); err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{
+				"error": "silenceID in path must be of type uuid: " + strconv.Quote(id),
+			})
+			return
+		}
+
+		// Silences outside the caller's scope are reported as not found.
+		scope := TokenScopeFromContext(r.Context())
+		if scope != nil {
+			if silence, ok := store.Get(id, time.Now().UTC()); ok && !scope.AllowsSilence(silence.Matchers) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+
 		switch r.Method {
 		case http.MethodGet:
 			silence, ok := store.Get(id, time.Now().UTC())
@@ -45,17 +82,80 @@ func SilenceByIDHandler(registry RegistryProvider) http.HandlerFunc {
 			}
 			writeJSON(w, http.StatusOK, silence)
 		case http.MethodDelete:
-			if !store.Delete(id) {
+			// Alertmanager expires the silence instead of removing it.
+			switch err := store.ExpireBy(id, TokenNameFromContext(r.Context()), time.Now().UTC()); {
+			case errors.Is(err, memory.ErrSilenceNotFound):
 				w.WriteHeader(http.StatusNotFound)
-				return
+			case err != nil:
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			default:
+				w.WriteHeader(http.StatusOK)
 			}
-			w.WriteHeader(http.StatusOK)
-		default:
+		}
+	}
+}
+
+// SilencePreviewHandler reports the currently-firing alerts a silence body
+// would match, without creating the silence.
+func SilencePreviewHandler(registry RegistryProvider) http.HandlerFunc {
+	// The engine is only used for its matcher cache; it has no silence source.
+	engine := services.NewSilenceEngine(nil, nil)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
 			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
 		}
+		handleSilencePreview(engine, registry.AlertStore(), registry.SilenceStore(), w, r)
 	}
 }
 
+func handleSilencePreview(engine *services.SilenceEngine, alerts *memory.AlertStore, silences *memory.SilenceStore, w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1024*1024))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+
+	var in core.SilenceInput
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	matchers, err := memory.ValidateSilenceMatchers(in.Matchers)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	now := time.Now().UTC()
+	scope := TokenScopeFromContext(r.Context())
+	resp := silencePreviewResponse{Alerts: make([]core.APIGettableAlert, 0)}
+	for _, alert := range alerts.List("firing", false) {
+		if !scope.AllowsLabels(alert.Labels) {
+			continue
+		}
+		matched, err := engine.MatchesSilence(core.APISilence{
+			Matchers:             matchers,
+			RequireLabelPresence: in.RequireLabelPresence,
+		}, alert.Labels)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if !matched {
+			continue
+		}
+		resp.Count++
+		if len(resp.Alerts) < silencePreviewSampleSize {
+			resp.Alerts = append(resp.Alerts, toGettableAlert(alert, silences, now))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func handleSilencesGet(store *memory.SilenceStore, w http.ResponseWriter, r *http.Request) {
 	filters, err := ParseLabelMatchers(r.URL.Query()["filter"])
 	if err != nil {
@@ -64,10 +164,11 @@ func handleSilencesGet(store *memory.SilenceStore, w http.ResponseWriter, r *htt
 	}
 
 	all := store.List(time.Now().UTC())
+	scope := TokenScopeFromContext(r.Context())
 
 	result := make([]core.APISilence, 0, len(all))
 	for _, s := range all {
-		if !MatchesSilenceMatchers(filters, s.Matchers) {
+		if !scope.AllowsSilence(s.Matchers) || !MatchesSilenceMatchers(filters, s.Matchers) {
 			continue
 		}
 		result = append(result, s)
@@ -90,11 +191,96 @@ func handleSilencePost(store *memory.SilenceStore, w http.ResponseWriter, r *htt
 		return
 	}
 
-	id, err := store.CreateOrUpdate(&in, time.Now().UTC())
+	saveSilence(store, in, w, r)
+}
+
+// saveSilence creates or updates a silence on behalf of the caller, within
+// the caller's token scope, and writes the Alertmanager-style response.
+// Silences the policy holds for approval are queued and answered with 202.
+// New silences that duplicate, subsume or are subsumed by an existing
+// silence are rejected with 409 unless allowOverlap=true is set; the
+// overlapping silences are listed as warnings either way.
+func saveSilence(store *memory.SilenceStore, in core.SilenceInput, w http.ResponseWriter, r *http.Request) {
+	scope := TokenScopeFromContext(r.Context())
+	if scope != nil {
+		if in.ID != "" {
+			if prev, ok := store.Get(in.ID, time.Now().UTC()); ok && !scope.AllowsSilence(prev.Matchers) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": memory.ErrSilenceNotFound.Error()})
+				return
+			}
+		}
+		if !scope.AllowsSilence(silenceInputMatchers(in.Matchers)) {
+			writeJSON(w, http.StatusForbidden, map[string]string{
+				"error": "silence matchers must pin every label of the token selector",
+			})
+			return
+		}
+	}
+
+	in.Actor = TokenNameFromContext(r.Context())
+	now := time.Now().UTC()
+
+	var overlaps []core.SilenceOverlap
+	if strings.TrimSpace(in.ID) == "" {
+		for _, overlap := range store.Overlaps(&in, now) {
+			if scope.AllowsSilence(overlap.Silence.Matchers) {
+				overlaps = append(overlaps, overlap)
+			}
+		}
+		if len(overlaps) > 0 && !parseBoolQueryLenient(r.URL.Query().Get("allowOverlap"), false) {
+			writeJSON(w, http.StatusConflict, map[string]any{
+				"error":    "silence overlaps existing silences; set allowOverlap=true to create it anyway",
+				"warnings": overlaps,
+			})
+			return
+		}
+	}
+
+	id, err := store.CreateOrUpdate(&in, now)
+	if errors.Is(err, core.ErrSilenceApprovalRequired) {
+		// Held until a second person approves it via
+		// POST /api/v2/silences/approvals/{id}/approve.
+		req, reqErr := store.RequestApproval(&in, err.Error(), now)
+		if reqErr != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": reqErr.Error()})
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{
+			"approvalID": req.ID,
+			"status":     "pending_approval",
+			"reason":     req.Reason,
+		})
+		return
+	}
+	if errors.Is(err, core.ErrSilencePolicyViolation) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, memory.ErrSilenceNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
 	}
 
+	if len(overlaps) > 0 {
+		writeJSON(w, http.StatusOK, map[string]any{"silenceID": id, "warnings": overlaps})
+		return
+	}
 	writeJSON(w, http.StatusOK, map[string]string{"silenceID": id})
 }
+
+// silenceInputMatchers converts request matchers to their API form (isEqual defaults to true).
+func silenceInputMatchers(in []core.SilenceMatcherInput) []core.APISilenceMatcher {
+	out := make([]core.APISilenceMatcher, 0, len(in))
+	for _, m := range in {
+		isEqual := true
+		if m.IsEqual != nil {
+			isEqual = *m.IsEqual
+		}
+		out = append(out, core.APISilenceMatcher{Name: m.Name, Value: m.Value, IsRegex: m.IsRegex, IsEqual: isEqual})
+	}
+	return out
+}
@@ -141,7 +141,7 @@ func TestSilencesHandler_PostGet_RoundTrip(t *testing.T) {
 	now := time.Now().UTC()
 	body := `{"matchers":[{"name":"alertname","value":"TestAlert","isRegex":false,"isEqual":true}],"startsAt":"` +
 		now.Add(-time.Minute).Format(time.RFC3339) + `","endsAt":"` +
-		now.Add(time.Hour).Format(time.RFC3339) + `","createdBy":"tester","comment":"round trip"}`
+		now.Add(time.Hour).Format(time.RFC3339) + `","createdBy":"tester","comment":"round trip","notifyOnExpiry":"slack-ops"}`
 
 	postReq := httptest.NewRequest(http.MethodPost, "/api/v2/silences", strings.NewReader(body))
 	postRec := httptest.NewRecorder()
@@ -154,4 +154,315 @@ func TestSilencesHandler_PostGet_RoundTrip(t *testing.T) {
 	if len(silences) != 1 {
 		t.Fatalf("expected 1 silence, got %d", len(silences))
 	}
+	if silences[0].NotifyOnExpiry != "slack-ops" {
+		t.Errorf("notifyOnExpiry = %q, want slack-ops", silences[0].NotifyOnExpiry)
+	}
+}
+
+func postSilence(t *testing.T, handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/silences", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func silencePayload(id, matchers, startsAt, endsAt string) string {
+	idField := ""
+	if id != "" {
+		idField = `"id":"` + id + `",`
+	}
+	return `{` + idField + `"matchers":` + matchers + `,"startsAt":"` + startsAt + `","endsAt":"` + endsAt +
+		`","createdBy":"tester","comment":"maintenance"}`
+}
+
+func TestSilencesHandler_PostValidation(t *testing.T) {
+	registry := &fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: memory.NewSilenceStore()}
+	handler := SilencesHandler(registry)
+
+	now := time.Now().UTC()
+	startsAt := now.Format(time.RFC3339)
+	endsAt := now.Add(time.Hour).Format(time.RFC3339)
+	matchers := `[{"name":"alertname","value":"A","isRegex":false,"isEqual":true}]`
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing createdBy", body: `{"matchers":` + matchers + `,"endsAt":"` + endsAt + `","comment":"c"}`},
+		{name: "missing comment", body: `{"matchers":` + matchers + `,"endsAt":"` + endsAt + `","createdBy":"me"}`},
+		{name: "no matchers", body: silencePayload("", `[]`, startsAt, endsAt)},
+		{name: "invalid label name", body: silencePayload("", `[{"name":"bad-name","value":"x","isRegex":false}]`, startsAt, endsAt)},
+		{name: "all matchers match empty", body: silencePayload("", `[{"name":"a","value":".*","isRegex":true},{"name":"b","value":"x","isRegex":false,"isEqual":false}]`, startsAt, endsAt)},
+		{name: "ends before start", body: silencePayload("", matchers, endsAt, startsAt)},
+		{name: "ends in past", body: silencePayload("", matchers, now.Add(-2*time.Hour).Format(time.RFC3339), now.Add(-time.Hour).Format(time.RFC3339))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postSilence(t, handler, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400; body: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	// An empty-value matcher is allowed as long as another matcher requires a non-empty value.
+	rec := postSilence(t, handler, silencePayload("", `[{"name":"alertname","value":"A","isRegex":false},{"name":"team","value":"","isRegex":false}]`, startsAt, endsAt))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSilencesHandler_PostUpdateSemantics(t *testing.T) {
+	store := memory.NewSilenceStore()
+	registry := &fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: store}
+	handler := SilencesHandler(registry)
+
+	now := time.Now().UTC()
+	matchers := `[{"name":"alertname","value":"A","isRegex":false,"isEqual":true}]`
+
+	rec := postSilence(t, handler, silencePayload("00000000-0000-4000-8000-000000000001", matchers, now.Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("update of unknown silence: status = %d, want 404", rec.Code)
+	}
+
+	id := createSilence(t, store, []core.SilenceMatcherInput{{Name: "alertname", Value: "A"}})
+	existing, _ := store.Get(id, now)
+
+	// Extending an active silence with unchanged matchers keeps the ID.
+	rec = postSilence(t, handler, silencePayload(id, matchers, existing.StartsAt, now.Add(3*time.Hour).Format(time.RFC3339)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("in-place update: status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["silenceID"] != id {
+		t.Fatalf("in-place update returned id %q, want %q", resp["silenceID"], id)
+	}
+
+	// Changing matchers expires the old silence and creates a new one.
+	rec = postSilence(t, handler, silencePayload(id, `[{"name":"alertname","value":"B","isRegex":false}]`, existing.StartsAt, now.Add(3*time.Hour).Format(time.RFC3339)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("replacing update: status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["silenceID"] == id || resp["silenceID"] == "" {
+		t.Fatalf("replacing update must return a new id, got %q", resp["silenceID"])
+	}
+
+	old, _ := store.Get(id, time.Now().UTC())
+	if old.Status.State != "expired" {
+		t.Fatalf("replaced silence state = %q, want expired", old.Status.State)
+	}
+}
+
+func TestSilencesHandler_PostOverlapping(t *testing.T) {
+	store := memory.NewSilenceStore()
+	registry := &fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: store}
+	handler := SilencesHandler(registry)
+
+	existing := createSilence(t, store, []core.SilenceMatcherInput{{Name: "alertname", Value: "A"}})
+
+	now := time.Now().UTC()
+	body := silencePayload("", `[{"name":"alertname","value":"A","isRegex":false},{"name":"env","value":"prod","isRegex":false}]`,
+		now.Format(time.RFC3339), now.Add(2*time.Hour).Format(time.RFC3339))
+
+	var resp struct {
+		SilenceID string                `json:"silenceID"`
+		Warnings  []core.SilenceOverlap `json:"warnings"`
+	}
+	rec := postSilence(t, handler, body)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("overlapping POST status = %d, want 409; body: %s", rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Silence.ID != existing || resp.Warnings[0].Relation != core.SilenceOverlapBroader {
+		t.Fatalf("warnings = %+v, want existing silence as broader", resp.Warnings)
+	}
+	if got := len(store.List(now)); got != 1 {
+		t.Fatalf("silences after rejected POST = %d, want 1", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/silences?allowOverlap=true", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("overridden POST status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	resp.Warnings = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.SilenceID == "" || len(resp.Warnings) != 1 {
+		t.Fatalf("overridden POST response = %s, want silenceID and one warning", rec.Body.String())
+	}
+
+	// Updates are not checked: a silence always overlaps its previous version.
+	rec = postSilence(t, handler, silencePayload(existing, `[{"name":"alertname","value":"A","isRegex":false}]`,
+		now.Format(time.RFC3339), now.Add(3*time.Hour).Format(time.RFC3339)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSilenceByIDHandler_DeleteExpires(t *testing.T) {
+	store := memory.NewSilenceStore()
+	registry := &fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: store}
+	handler := SilenceByIDHandler(registry)
+
+	id := createSilence(t, store, []core.SilenceMatcherInput{{Name: "alertname", Value: "A"}})
+
+	do := func(method, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v2/silence/"+id, nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodDelete, id); rec.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200", rec.Code)
+	}
+
+	rec := do(http.MethodGet, id)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET after expire status = %d, want 200", rec.Code)
+	}
+	var silence core.APISilence
+	if err := json.Unmarshal(rec.Body.Bytes(), &silence); err != nil {
+		t.Fatal(err)
+	}
+	if silence.Status.State != "expired" {
+		t.Fatalf("state = %q, want expired", silence.Status.State)
+	}
+
+	if rec := do(http.MethodDelete, id); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("second DELETE status = %d, want 500 (already expired)", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "00000000-0000-4000-8000-000000000001"); rec.Code != http.StatusNotFound {
+		t.Fatalf("DELETE unknown status = %d, want 404", rec.Code)
+	}
+	if rec := do(http.MethodGet, "not-a-uuid"); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("GET invalid id status = %d, want 422", rec.Code)
+	}
+}
+
+func TestSilencesHandler_FilterByMatcherValue(t *testing.T) {
+	store := memory.NewSilenceStore()
+	registry := &fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: store}
+	handler := SilencesHandler(registry)
+
+	createSilence(t, store, []core.SilenceMatcherInput{{Name: "alertname", Value: "A"}})
+	b := createSilence(t, store, []core.SilenceMatcherInput{{Name: "alertname", Value: "B"}, {Name: "severity", Value: "critical"}})
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{query: `filter=alertname%3D"B"`, want: 1},
+		{query: `filter=alertname%3D~"A|B"`, want: 2},
+		{query: `filter=severity!%3D"critical"`, want: 1},
+		{query: `filter=severity%3D~".*"`, want: 1},
+	}
+	for _, tt := range tests {
+		silences := getSilences(t, handler, tt.query)
+		if len(silences) != tt.want {
+			t.Fatalf("%s: got %d silences, want %d", tt.query, len(silences), tt.want)
+		}
+	}
+
+	silences := getSilences(t, handler, `filter=alertname%3D"B"`)
+	if silences[0].ID != b {
+		t.Fatalf("filter returned %q, want %q", silences[0].ID, b)
+	}
+}
+
+func TestSilencePreviewHandler(t *testing.T) {
+	alerts := memory.NewAlertStore()
+	now := time.Now().UTC()
+	_ = alerts.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "CPUHigh", "env": "prod"}, StartsAt: now.Format(time.RFC3339), Fingerprint: "f1", Status: "firing"},
+		{Labels: map[string]string{"alertname": "CPUHigh", "env": "staging"}, StartsAt: now.Format(time.RFC3339), Fingerprint: "f2", Status: "firing"},
+		{Labels: map[string]string{"alertname": "DiskFull", "env": "prod"}, StartsAt: now.Format(time.RFC3339), Fingerprint: "f3", Status: "firing"},
+		{Labels: map[string]string{"alertname": "CPUHigh", "env": "prod", "host": "b"}, StartsAt: now.Add(-time.Hour).Format(time.RFC3339), EndsAt: now.Add(-time.Minute).Format(time.RFC3339), Fingerprint: "f4", Status: "resolved"},
+	}, now)
+
+	silences := memory.NewSilenceStore()
+	handler := SilencePreviewHandler(&fakeRegistry{alertStore: alerts, silenceStore: silences})
+
+	preview := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/silences/preview", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("matches firing alerts only", func(t *testing.T) {
+		rec := preview(`{"matchers":[{"name":"alertname","value":"CPU.*","isRegex":true}]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+		}
+		var resp silencePreviewResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if resp.Count != 2 || len(resp.Alerts) != 2 {
+			t.Fatalf("count = %d, alerts = %d, want 2 and 2", resp.Count, len(resp.Alerts))
+		}
+		if resp.Alerts[0].Fingerprint != "f1" || resp.Alerts[1].Fingerprint != "f2" {
+			t.Errorf("unexpected alerts: %s, %s", resp.Alerts[0].Fingerprint, resp.Alerts[1].Fingerprint)
+		}
+	})
+
+	t.Run("negative matcher", func(t *testing.T) {
+		rec := preview(`{"matchers":[{"name":"alertname","value":"CPUHigh"},{"name":"env","value":"prod","isEqual":false}]}`)
+		var resp silencePreviewResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if resp.Count != 1 || resp.Alerts[0].Fingerprint != "f2" {
+			t.Errorf("got %+v, want only f2", resp)
+		}
+	})
+
+	t.Run("require label presence", func(t *testing.T) {
+		matchers := `"matchers":[{"name":"alertname","value":"CPUHigh"},{"name":"host","value":"a","isEqual":false}]`
+		for body, want := range map[string]int{
+			`{` + matchers + `}`:                             2,
+			`{` + matchers + `,"requireLabelPresence":true}`: 0,
+		} {
+			var resp silencePreviewResponse
+			if err := json.Unmarshal(preview(body).Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if resp.Count != want {
+				t.Errorf("body %s: count = %d, want %d", body, resp.Count, want)
+			}
+		}
+	})
+
+	t.Run("invalid matchers", func(t *testing.T) {
+		for _, body := range []string{
+			`{"matchers":[]}`,
+			`{"matchers":[{"name":"env","value":"","isRegex":false}]}`,
+			`{"matchers":[{"name":"env","value":"(","isRegex":true}]}`,
+			`not json`,
+		} {
+			if rec := preview(body); rec.Code != http.StatusBadRequest {
+				t.Errorf("body %s: status = %d, want 400", body, rec.Code)
+			}
+		}
+	})
+
+	t.Run("does not create silence", func(t *testing.T) {
+		if got := silences.List(now); len(got) != 0 {
+			t.Errorf("expected no silences, got %d", len(got))
+		}
+	})
 }
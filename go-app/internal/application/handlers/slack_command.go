@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/webhooksec"
+	"golang.org/x/time/rate"
+)
+
+const slackSilenceUsage = "Usage: `/amp silence <matcher>... <duration> \"<reason>\"`, e.g. " +
+	"`/amp silence alertname=Foo env!=dev 2h \"deploying fix\"`"
+
+// slackCommandResponse is the message returned to Slack; ephemeral responses
+// are only shown to the invoking user.
+type slackCommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// SlackCommandHandler serves the "/amp" Slack slash command.
+//
+// Supported subcommands:
+//   - silence <matcher>... <duration> "<reason>": creates a silence starting
+//     now, attributed to the Slack user.
+//
+// Requests must carry a valid Slack signature. Silence creation is rate
+// limited per Slack user. Command errors are answered with HTTP 200 and an
+// ephemeral message, as Slack only displays the body of successful responses.
+func SlackCommandHandler(registry RegistryProvider) http.HandlerFunc {
+	cfg := registry.Config().SlackCommand
+	limiter := newSlackUserLimiter(cfg.RateLimit)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.Enabled {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "slack command integration is disabled"})
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64*1024))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+			return
+		}
+
+		if err := webhooksec.VerifySlack(cfg.SigningSecret, r.Header, body, time.Now(), webhooksec.DefaultMaxSkew); err != nil {
+			slog.Warn("Rejected Slack command", "remote_addr", r.RemoteAddr, "error", err)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid slack signature"})
+			return
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid form body"})
+			return
+		}
+
+		text := handleSlackCommand(registry.SilenceStore(), cfg, limiter, form, time.Now().UTC())
+		writeJSON(w, http.StatusOK, slackCommandResponse{ResponseType: "ephemeral", Text: text})
+	}
+}
+
+// handleSlackCommand runs the command in form and returns the reply text.
+func handleSlackCommand(store *memory.SilenceStore, cfg appconfig.SlackCommandConfig, limiter *slackUserLimiter, form url.Values, now time.Time) string {
+	args, err := splitSlackCommandArgs(form.Get("text"))
+	if err != nil {
+		return err.Error() + "\n" + slackSilenceUsage
+	}
+	if len(args) == 0 || args[0] != "silence" {
+		return slackSilenceUsage
+	}
+
+	userID := form.Get("user_id")
+	if userID == "" {
+		return "Missing Slack user."
+	}
+
+	cmd, err := parseSlackSilenceCommand(args[1:], cfg.MaxDuration)
+	if err != nil {
+		return err.Error() + "\n" + slackSilenceUsage
+	}
+
+	if !limiter.allow(userID) {
+		return fmt.Sprintf("Rate limit exceeded: at most %d silences per hour.", cfg.RateLimit)
+	}
+
+	createdBy := "slack:" + userID
+	if name := form.Get("user_name"); name != "" {
+		createdBy = fmt.Sprintf("slack:%s (%s)", name, userID)
+	}
+
+	in := &core.SilenceInput{
+		Matchers:  cmd.matchers,
+		StartsAt:  now.Format(time.RFC3339),
+		EndsAt:    now.Add(cmd.duration).Format(time.RFC3339),
+		CreatedBy: createdBy,
+		Comment:   cmd.reason,
+	}
+	id, err := store.CreateOrUpdate(in, now)
+	if errors.Is(err, core.ErrSilenceApprovalRequired) {
+		req, reqErr := store.RequestApproval(in, err.Error(), now)
+		if reqErr != nil {
+			return "Silence rejected: " + reqErr.Error()
+		}
+		return fmt.Sprintf("Approval request `%s` created (%s). A second person must approve it.", req.ID, err)
+	}
+	if err != nil {
+		return "Silence rejected: " + err.Error()
+	}
+
+	slog.Info("Silence created from Slack",
+		"silence_id", id,
+		"created_by", createdBy,
+		"channel_id", form.Get("channel_id"),
+		"duration", cmd.duration.String())
+
+	return fmt.Sprintf("Silence `%s` created until %s.", id, now.Add(cmd.duration).Format(time.RFC3339))
+}
+
+type slackSilenceCommand struct {
+	matchers []core.SilenceMatcherInput
+	duration time.Duration
+	reason   string
+}
+
+// parseSlackSilenceCommand parses "<matcher>... <duration> <reason>".
+//
+// The first argument that is not a matcher is the duration; everything after
+// it is the reason.
+func parseSlackSilenceCommand(args []string, maxDuration time.Duration) (*slackSilenceCommand, error) {
+	cmd := &slackSilenceCommand{}
+
+	i := 0
+	for ; i < len(args); i++ {
+		m, ok, err := parseSlackMatcher(args[i])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		cmd.matchers = append(cmd.matchers, m)
+	}
+	if len(cmd.matchers) == 0 {
+		return nil, fmt.Errorf("at least one matcher is required")
+	}
+
+	if i == len(args) {
+		return nil, fmt.Errorf("duration is required")
+	}
+	d, err := parseSlackDuration(args[i])
+	if err != nil {
+		return nil, err
+	}
+	if maxDuration > 0 && d > maxDuration {
+		return nil, fmt.Errorf("duration %s exceeds the maximum of %s", d, maxDuration)
+	}
+	cmd.duration = d
+
+	cmd.reason = strings.TrimSpace(strings.Join(args[i+1:], " "))
+	if cmd.reason == "" {
+		return nil, fmt.Errorf("a reason is required")
+	}
+	return cmd, nil
+}
+
+// parseSlackMatcher parses name=value, name!=value, name=~regex or
+// name!~regex. ok is false when arg is not a matcher at all.
+func parseSlackMatcher(arg string) (m core.SilenceMatcherInput, ok bool, err error) {
+	idx := strings.IndexAny(arg, "=!")
+	if idx <= 0 {
+		return m, false, nil
+	}
+
+	name, rest := arg[:idx], arg[idx:]
+	var op MatcherOp
+	for _, candidate := range []MatcherOp{MatcherOpRegex, MatcherOpNotRegex, MatcherOpNotEqual, MatcherOpEqual} {
+		if strings.HasPrefix(rest, string(candidate)) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return m, false, fmt.Errorf("invalid matcher %q", arg)
+	}
+
+	isEqual := op == MatcherOpEqual || op == MatcherOpRegex
+	return core.SilenceMatcherInput{
+		Name:    name,
+		Value:   strings.TrimPrefix(rest, string(op)),
+		IsRegex: op == MatcherOpRegex || op == MatcherOpNotRegex,
+		IsEqual: &isEqual,
+	}, true, nil
+}
+
+// parseSlackDuration accepts Go durations plus whole days ("2d").
+func parseSlackDuration(raw string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+	return d, nil
+}
+
+// splitSlackCommandArgs splits text on whitespace; double quotes group words
+// and are removed, so env="us east" yields env=us east.
+func splitSlackCommandArgs(text string) ([]string, error) {
+	// Slack clients may substitute smart quotes.
+	text = strings.NewReplacer("“", `"`, "”", `"`).Replace(text)
+
+	var (
+		args    []string
+		current strings.Builder
+		inQuote bool
+		started bool
+	)
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			started = true
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if started {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// slackUserLimiter limits silence creation per Slack user.
+type slackUserLimiter struct {
+	perHour int
+	mu      sync.Mutex
+	users   map[string]*rate.Limiter
+}
+
+func newSlackUserLimiter(perHour int) *slackUserLimiter {
+	return &slackUserLimiter{perHour: perHour, users: make(map[string]*rate.Limiter)}
+}
+
+func (l *slackUserLimiter) allow(userID string) bool {
+	if l.perHour <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	limiter, ok := l.users[userID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(time.Hour/time.Duration(l.perHour)), l.perHour)
+		l.users[userID] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Allow()
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+const testSlackSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+type slackRegistry struct {
+	fakeRegistry
+	cfg *appconfig.Config
+}
+
+func (r *slackRegistry) Config() *appconfig.Config { return r.cfg }
+
+func newSlackCommandHandler(rateLimit int) (http.HandlerFunc, *memory.SilenceStore) {
+	store := memory.NewSilenceStore()
+	registry := &slackRegistry{
+		fakeRegistry: fakeRegistry{alertStore: memory.NewAlertStore(), silenceStore: store},
+		cfg: &appconfig.Config{SlackCommand: appconfig.SlackCommandConfig{
+			Enabled:       true,
+			SigningSecret: testSlackSecret,
+			MaxDuration:   24 * time.Hour,
+			RateLimit:     rateLimit,
+		}},
+	}
+	return SlackCommandHandler(registry), store
+}
+
+func signedSlackRequest(secret string, ts time.Time, text string) *http.Request {
+	body := url.Values{
+		"command":   {"/amp"},
+		"text":      {text},
+		"user_id":   {"U123"},
+		"user_name": {"alice"},
+	}.Encode()
+	rawTS := strconv.FormatInt(ts.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + rawTS + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/integrations/slack/command", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", rawTS)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func runSlackCommand(t *testing.T, handler http.HandlerFunc, req *http.Request) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		return rec.Code, rec.Body.String()
+	}
+	var resp slackCommandResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if resp.ResponseType != "ephemeral" {
+		t.Errorf("response_type = %q, want ephemeral", resp.ResponseType)
+	}
+	return rec.Code, resp.Text
+}
+
+func TestSlackCommandHandler_CreatesAttributedSilence(t *testing.T) {
+	handler, store := newSlackCommandHandler(5)
+
+	code, text := runSlackCommand(t, handler, signedSlackRequest(testSlackSecret, time.Now(),
+		`silence alertname=Foo env!="us east" 2h "deploying fix"`))
+	if code != http.StatusOK || !strings.Contains(text, "created") {
+		t.Fatalf("got %d %q, want silence created", code, text)
+	}
+
+	silences := store.List(time.Now().UTC())
+	if len(silences) != 1 {
+		t.Fatalf("expected 1 silence, got %d", len(silences))
+	}
+	s := silences[0]
+	if s.CreatedBy != "slack:alice (U123)" {
+		t.Errorf("CreatedBy = %q", s.CreatedBy)
+	}
+	if s.Comment != "deploying fix" {
+		t.Errorf("Comment = %q", s.Comment)
+	}
+	if len(s.Matchers) != 2 {
+		t.Fatalf("expected 2 matchers, got %+v", s.Matchers)
+	}
+	for _, m := range s.Matchers {
+		if m.Name == "env" && (m.Value != "us east" || m.IsEqual) {
+			t.Errorf("env matcher = %+v, want != \"us east\"", m)
+		}
+	}
+}
+
+func TestSlackCommandHandler_RejectsBadSignature(t *testing.T) {
+	handler, store := newSlackCommandHandler(5)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{name: "wrong secret", req: signedSlackRequest("other-secret", time.Now(), "silence a=b 1h x")},
+		{name: "stale timestamp", req: signedSlackRequest(testSlackSecret, time.Now().Add(-10*time.Minute), "silence a=b 1h x")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := runSlackCommand(t, handler, tt.req); code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", code)
+			}
+		})
+	}
+
+	if got := store.List(time.Now().UTC()); len(got) != 0 {
+		t.Errorf("expected no silences, got %d", len(got))
+	}
+}
+
+func TestSlackCommandHandler_Validation(t *testing.T) {
+	handler, store := newSlackCommandHandler(5)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "", want: "Usage"},
+		{text: "silence 2h reason", want: "at least one matcher"},
+		{text: "silence alertname=Foo", want: "duration is required"},
+		{text: "silence alertname=Foo soon reason", want: "invalid duration"},
+		{text: "silence alertname=Foo 2d reason", want: "exceeds the maximum"},
+		{text: "silence alertname=Foo 2h", want: "reason is required"},
+		{text: `silence alertname=~"(" 2h reason`, want: "invalid regex"},
+		{text: `silence alertname="Foo 2h reason`, want: "unterminated quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, text := runSlackCommand(t, handler, signedSlackRequest(testSlackSecret, time.Now(), tt.text))
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("reply = %q, want it to contain %q", text, tt.want)
+			}
+		})
+	}
+
+	if got := store.List(time.Now().UTC()); len(got) != 0 {
+		t.Errorf("expected no silences, got %d", len(got))
+	}
+}
+
+func TestSlackCommandHandler_RateLimitPerUser(t *testing.T) {
+	handler, store := newSlackCommandHandler(2)
+
+	for i := 0; i < 3; i++ {
+		_, text := runSlackCommand(t, handler, signedSlackRequest(testSlackSecret, time.Now(), "silence alertname=Foo 1h noisy"))
+		if i < 2 && !strings.Contains(text, "created") {
+			t.Fatalf("request %d: reply = %q, want created", i, text)
+		}
+		if i == 2 && !strings.Contains(text, "Rate limit exceeded") {
+			t.Fatalf("request %d: reply = %q, want rate limited", i, text)
+		}
+	}
+
+	if got := store.List(time.Now().UTC()); len(got) != 2 {
+		t.Errorf("expected 2 silences, got %d", len(got))
+	}
+}
+
+func TestSlackCommandHandler_Disabled(t *testing.T) {
+	handler := SlackCommandHandler(&fakeRegistry{silenceStore: memory.NewSilenceStore()})
+
+	rec := httptest.NewRecorder()
+	handler(rec, signedSlackRequest(testSlackSecret, time.Now(), "silence a=b 1h x"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
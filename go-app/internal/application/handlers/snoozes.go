@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// SnoozesRegistryProvider is satisfied by ServiceRegistry.
+type SnoozesRegistryProvider interface {
+	SnoozeStore() *memory.SnoozeStore
+}
+
+// SnoozesHandler serves /api/v2/snoozes:
+//   - GET ?user=<id>: the user's active snoozes
+//   - POST {user, fingerprint, duration}: snooze an alert for the user
+//   - DELETE ?user=<id>&fingerprint=<fp>: remove a snooze
+//
+// A snooze only stops chat publishers from mentioning the user for that
+// fingerprint; the alert is still routed and notified as usual.
+func SnoozesHandler(registry SnoozesRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		store := registry.SnoozeStore()
+		if store == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "snoozes are not available"})
+			return
+		}
+
+		now := time.Now().UTC()
+		switch r.Method {
+		case http.MethodGet:
+			user := r.URL.Query().Get("user")
+			if user == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "user is required"})
+				return
+			}
+			writeJSON(w, http.StatusOK, store.List(user, now))
+		case http.MethodPost:
+			handleSnoozePost(store, w, r, now)
+		case http.MethodDelete:
+			query := r.URL.Query()
+			err := store.Unsnooze(query.Get("user"), query.Get("fingerprint"), now)
+			if errors.Is(err, memory.ErrSnoozeNotFound) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handleSnoozePost(store *memory.SnoozeStore, w http.ResponseWriter, r *http.Request, now time.Time) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64*1024))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+
+	var in core.SnoozeInput
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	snooze, err := store.Snooze(&in, now)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, snooze)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeSnoozeRegistry struct {
+	snoozes *memory.SnoozeStore
+}
+
+func (r *fakeSnoozeRegistry) SnoozeStore() *memory.SnoozeStore { return r.snoozes }
+
+func TestSnoozesHandler_SnoozeListDelete(t *testing.T) {
+	registry := &fakeSnoozeRegistry{snoozes: memory.NewSnoozeStore()}
+	handler := SnoozesHandler(registry)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/snoozes",
+		strings.NewReader(`{"user":"U1","fingerprint":"fp","duration":"2h"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	if !registry.snoozes.IsSnoozed("U1", "fp", time.Now().Add(time.Hour)) {
+		t.Fatal("expected U1 to be snoozed for fp")
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/snoozes?user=U1", nil))
+	var list []core.APISnooze
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(list) != 1 || list[0].Fingerprint != "fp" || list[0].Until == "" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodDelete, "/api/v2/snoozes?user=U1&fingerprint=fp", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodDelete, "/api/v2/snoozes?user=U1&fingerprint=fp", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second DELETE status = %d, want 404", rec.Code)
+	}
+}
+
+func TestSnoozesHandler_Validation(t *testing.T) {
+	handler := SnoozesHandler(&fakeSnoozeRegistry{snoozes: memory.NewSnoozeStore()})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/snoozes", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GET without user status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/snoozes",
+		strings.NewReader(`{"user":"U1","fingerprint":"fp","duration":"30d"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("POST with invalid duration status = %d, want 400", rec.Code)
+	}
+}
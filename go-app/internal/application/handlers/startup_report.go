@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// StartupReportRegistryProvider is satisfied by ServiceRegistry.
+type StartupReportRegistryProvider interface {
+	StartupReport() *services.StartupReport
+}
+
+// StartupReportHandler serves GET /api/v2/status/startup: what AMP restored
+// at boot (silences, inhibitions, target groups and their timers) and what
+// may have been missed while it was down, as JSON.
+func StartupReportHandler(registry StartupReportRegistryProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		report := registry.StartupReport()
+		if report == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "startup reconciliation has not run"})
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	}
+}
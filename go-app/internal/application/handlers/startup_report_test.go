@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+type fakeStartupReportRegistry struct {
+	report *services.StartupReport
+}
+
+func (r *fakeStartupReportRegistry) StartupReport() *services.StartupReport {
+	return r.report
+}
+
+func TestStartupReportHandler(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	report := services.NewStartupReport(started, started.Add(-time.Hour), true)
+	report.Groups.Restored = 2
+
+	rec := httptest.NewRecorder()
+	StartupReportHandler(&fakeStartupReportRegistry{report: report}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/status/startup", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if body["downtime"] != "1h0m0s" || body["cleanShutdown"] != true {
+		t.Fatalf("unexpected downtime: %v", body)
+	}
+	if groups := body["groups"].(map[string]any); groups["restored"] != float64(2) {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+}
+
+func TestStartupReportHandler_NotReconciled(t *testing.T) {
+	rec := httptest.NewRecorder()
+	StartupReportHandler(&fakeStartupReportRegistry{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/status/startup", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	StartupReportHandler(&fakeStartupReportRegistry{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v2/status/startup", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
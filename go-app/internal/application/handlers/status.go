@@ -51,9 +51,16 @@ func InternalErrorHandler(w http.ResponseWriter, message string) {
 	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": message})
 }
 
-// Alertmanager compatible health endpoints
+// AlertmanagerHealthyHandler serves GET/HEAD /-/healthy with the plain-text
+// contract of upstream Alertmanager, so existing liveness probes work
+// unchanged.
 func AlertmanagerHealthyHandler(provider HealthStatusProvider) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
 		if provider == nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			_, _ = fmt.Fprint(w, "NOT OK")
@@ -71,8 +78,15 @@ func AlertmanagerHealthyHandler(provider HealthStatusProvider) http.HandlerFunc
 	}
 }
 
+// AlertmanagerReadyHandler serves GET/HEAD /-/ready with the plain-text
+// contract of upstream Alertmanager.
 func AlertmanagerReadyHandler(provider HealthStatusProvider) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
 		if provider == nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			_, _ = fmt.Fprint(w, "NOT READY")
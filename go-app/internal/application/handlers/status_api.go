@@ -65,6 +65,9 @@ func StatusAPIHandler(registry RegistryProvider) http.HandlerFunc {
 	}
 }
 
+// ReloadHandler serves POST /-/reload. As in upstream Alertmanager, a
+// successful reload answers 200 with an empty body, so config reloader
+// sidecars work unchanged.
 func ReloadHandler(registry RegistryProvider) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -73,12 +76,11 @@ func ReloadHandler(registry RegistryProvider) http.HandlerFunc {
 		}
 
 		if err := registry.ReloadConfig(r.Context()); err != nil {
-			InternalErrorHandler(w, "failed to reload configuration: "+err.Error())
+			InternalErrorHandler(w, "failed to reload config: "+err.Error())
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("OK"))
 	}
 }
 
@@ -105,8 +105,8 @@ func TestReloadHandler(t *testing.T) {
 		if rec.Code != http.StatusOK {
 			t.Errorf("got status %d, want 200", rec.Code)
 		}
-		if rec.Body.String() != "OK" {
-			t.Errorf("got body %q, want OK", rec.Body.String())
+		if rec.Body.Len() != 0 {
+			t.Errorf("got body %q, want empty", rec.Body.String())
 		}
 	})
 
@@ -151,3 +151,25 @@ func TestAlertmanagerHealthHandlers_TextContract(t *testing.T) {
 		t.Fatalf("AlertmanagerReadyHandler() body = %q, want NOT READY", body)
 	}
 }
+
+func TestAlertmanagerHealthHandlers_Methods(t *testing.T) {
+	provider := &testHealthProvider{}
+	handlers := map[string]http.HandlerFunc{
+		"/-/healthy": AlertmanagerHealthyHandler(provider),
+		"/-/ready":   AlertmanagerReadyHandler(provider),
+	}
+
+	for path, handler := range handlers {
+		for method, want := range map[string]int{
+			http.MethodGet:  http.StatusOK,
+			http.MethodHead: http.StatusOK,
+			http.MethodPost: http.StatusMethodNotAllowed,
+		} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+			if rec.Code != want {
+				t.Errorf("%s %s status = %d, want %d", method, path, rec.Code, want)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,6 +28,20 @@ type LegacyDashboardOverviewSummary struct {
 	PendingSilences      int
 	ExpiredSilences      int
 	DegradedReasons      []string
+	// Last24h is read from the alert rollups (nil when they are disabled).
+	Last24h *LegacyDashboardAlertCounts
+}
+
+// LegacyDashboardAlertCounts counts the alerts that started in a period.
+type LegacyDashboardAlertCounts struct {
+	Total         int64
+	BySeverity    []LegacyDashboardCount
+	TopAlertNames []LegacyDashboardCount
+}
+
+type LegacyDashboardCount struct {
+	Value string
+	Count int64
 }
 
 type LegacyDashboardAlertsSummary struct {
@@ -62,6 +77,7 @@ type LegacyDashboardSilencesSummary struct {
 	Active             int
 	Pending            int
 	Expired            int
+	UnmatchedActive    int
 	Truncated          bool
 	HiddenCount        int
 	Silences           []LegacyDashboardSilenceItem
@@ -77,6 +93,8 @@ type LegacyDashboardSilenceItem struct {
 	StartsAt        string
 	EndsAt          string
 	UpdatedAt       string
+	MatchCount      uint64
+	LastMatchedAt   string
 }
 
 type LegacyDashboardLLMSummary struct {
@@ -165,10 +183,57 @@ func (r *ServiceRegistry) LegacyDashboardOverview(ctx context.Context, now time.
 		summary.SilenceTotal, summary.ActiveSilences, summary.PendingSilences, summary.ExpiredSilences = r.silenceStore.Stats(now)
 	}
 	summary.DegradedReasons = append([]string(nil), r.degradedReasons...)
+	summary.Last24h = r.legacyDashboardLast24h(ctx, now)
 
 	return summary
 }
 
+// legacyDashboardLast24h counts the alerts of the last 24 hours from the
+// hourly alert rollups.
+func (r *ServiceRegistry) legacyDashboardLast24h(ctx context.Context, now time.Time) *LegacyDashboardAlertCounts {
+	if r.alertRollups == nil {
+		return nil
+	}
+	rollups, err := r.alertRollups.Query(ctx, core.AlertRollupQuery{
+		Granularity: core.AlertRollupHourly,
+		From:        now.Add(-24 * time.Hour),
+		To:          now,
+	})
+	if err != nil {
+		r.logger.Warn("Failed to read alert rollups for the dashboard", "error", err)
+		return nil
+	}
+
+	sums := core.SumAlertRollups(rollups)
+	return &LegacyDashboardAlertCounts{
+		Total:         sums[core.AlertRollupTotal][""],
+		BySeverity:    legacyDashboardCounts(sums[core.AlertRollupSeverity], 0),
+		TopAlertNames: legacyDashboardCounts(sums[core.AlertRollupAlertName], 5),
+	}
+}
+
+// legacyDashboardCounts returns counts largest first, at most limit of them
+// (0: all). Alerts without the label are shown as "(none)".
+func legacyDashboardCounts(counts map[string]int64, limit int) []LegacyDashboardCount {
+	items := make([]LegacyDashboardCount, 0, len(counts))
+	for value, count := range counts {
+		if value == "" {
+			value = "(none)"
+		}
+		items = append(items, LegacyDashboardCount{Value: value, Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Value < items[j].Value
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
 func (r *ServiceRegistry) LegacyDashboardAlerts(_ time.Time) LegacyDashboardAlertsSummary {
 	summary := LegacyDashboardAlertsSummary{
 		RuntimeStatus:      "limited",
@@ -242,6 +307,13 @@ func (r *ServiceRegistry) LegacyDashboardSilences(now time.Time) LegacyDashboard
 		return summary
 	}
 
+	stats := r.silenceStore.MatchStats()
+	for _, silence := range silences {
+		if silence.Status.State == "active" && stats[silence.ID].MatchCount == 0 {
+			summary.UnmatchedActive++
+		}
+	}
+
 	summary.RuntimeDetail = "Showing silence state from the active compatibility store."
 	if len(silences) > legacyDashboardListLimit {
 		summary.Truncated = true
@@ -261,6 +333,8 @@ func (r *ServiceRegistry) LegacyDashboardSilences(now time.Time) LegacyDashboard
 			StartsAt:        defaultDisplay(silence.StartsAt),
 			EndsAt:          defaultDisplay(silence.EndsAt),
 			UpdatedAt:       defaultDisplay(silence.UpdatedAt),
+			MatchCount:      stats[silence.ID].MatchCount,
+			LastMatchedAt:   formatLastMatchedAt(stats[silence.ID].LastMatchedAt),
 		})
 	}
 
@@ -507,6 +581,13 @@ func formatDuration(value time.Duration) string {
 	return value.Round(time.Millisecond).String()
 }
 
+func formatLastMatchedAt(value *time.Time) string {
+	if value == nil {
+		return "never"
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
 func formatFloat(value float64) string {
 	return fmt.Sprintf("%.2f", value)
 }
@@ -1,10 +1,12 @@
 package application
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/ipiton/AMP/internal/application/handlers"
 	appconfig "github.com/ipiton/AMP/internal/config"
 )
 
@@ -24,6 +26,7 @@ type MiddlewareStack struct {
 	config   *appconfig.Config
 	services *ServiceRegistry
 	logger   *slog.Logger
+	auth     *tokenAuthenticator // nil when auth is disabled
 
 	// Middleware functions
 	middlewares []Middleware
@@ -55,6 +58,14 @@ func NewMiddlewareStack(
 		middlewares: make([]Middleware, 0, 10),
 	}
 
+	if config.Auth.Enabled {
+		auth, err := newTokenAuthenticator(config.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth config: %w", err)
+		}
+		stack.auth = auth
+	}
+
 	// Build middleware stack
 	stack.buildStack()
 
@@ -79,8 +90,12 @@ func (s *MiddlewareStack) buildStack() {
 	//     s.middlewares = append(s.middlewares, s.corsMiddleware())
 	// }
 
+	// 5. Authentication (if enabled)
+	if s.auth != nil {
+		s.middlewares = append(s.middlewares, s.authMiddleware())
+	}
+
 	// TODO: Add more middleware
-	// - Authentication
 	// - Rate limiting
 	// - Compression
 
@@ -160,3 +175,44 @@ func (s *MiddlewareStack) corsMiddleware() Middleware {
 		})
 	}
 }
+
+// authMiddleware requires an API token on protected paths and attaches the
+// token's label scope to the request context for the handlers.
+func (s *MiddlewareStack) authMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !requiresAuth(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := s.auth.authenticate(r)
+			if token == nil {
+				s.logger.Debug("Rejected unauthenticated request",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"remote_addr", r.RemoteAddr)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="amp"`)
+				writeAuthError(w, http.StatusUnauthorized, "missing or invalid API token")
+				return
+			}
+
+			r = r.WithContext(handlers.WithTokenName(r.Context(), token.name))
+			if token.scope != nil {
+				if !scopedTokenAllowed(r.Method, r.URL.Path) {
+					writeAuthError(w, http.StatusForbidden, "token is scoped and cannot access this endpoint")
+					return
+				}
+				r = r.WithContext(handlers.WithTokenScope(r.Context(), token.scope))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeAuthError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,414 @@
+//go:build integration
+// +build integration
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/llm"
+)
+
+// pipeline_integration_test.go - end-to-end harness for the full alert pipeline:
+// ingest → classify → route → publish → history.
+//
+// Real dependencies (testcontainers): PostgreSQL, Redis.
+// Mock providers (httptest): LLM classification proxy, webhook receiver.
+// Target discovery uses a fake K8s client serving one publishing secret.
+//
+// Run:
+//
+//	go test -tags integration -run TestIntegration_Pipeline ./internal/application/
+//	AMP_SOAK_ALERTS=5000 go test -tags integration -run TestIntegration_PipelineSoak -timeout 30m ./internal/application/
+
+// pipelineEnv is a fully initialized ServiceRegistry plus its mock providers.
+type pipelineEnv struct {
+	registry *ServiceRegistry
+	llm      *mockLLM
+	receiver *mockReceiver
+}
+
+// mockLLM serves the proxy /classify API.
+type mockLLM struct {
+	server *httptest.Server
+	calls  atomic.Int64
+}
+
+func newMockLLM(t *testing.T) *mockLLM {
+	t.Helper()
+	m := &mockLLM{}
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(llm.ClassificationResponse{
+			Classification: llm.LLMClassificationResponse{
+				Severity:    4,
+				Category:    "infrastructure",
+				Summary:     "integration test classification",
+				Confidence:  0.9,
+				Reasoning:   "mock",
+				Suggestions: []string{"check the node"},
+			},
+			RequestID:      "integration",
+			ProcessingTime: "1ms",
+		})
+	}))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// mockReceiver records webhook deliveries keyed by alert name.
+type mockReceiver struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	bodies []map[string]any
+}
+
+func newMockReceiver(t *testing.T) *mockReceiver {
+	t.Helper()
+	m := &mockReceiver{}
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		m.mu.Lock()
+		m.bodies = append(m.bodies, body)
+		m.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockReceiver) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.bodies)
+}
+
+// fakeK8sClient serves static publishing target secrets.
+type fakeK8sClient struct {
+	secrets []corev1.Secret
+}
+
+func (f *fakeK8sClient) ListSecrets(context.Context, string, string) ([]corev1.Secret, error) {
+	return f.secrets, nil
+}
+
+func (f *fakeK8sClient) GetSecret(_ context.Context, _ string, name string) (*corev1.Secret, error) {
+	for i := range f.secrets {
+		if f.secrets[i].Name == name {
+			return &f.secrets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("secret %s not found", name)
+}
+
+func (f *fakeK8sClient) ApplySecret(_ context.Context, _ string, secret *corev1.Secret) error {
+	for i := range f.secrets {
+		if f.secrets[i].Name == secret.Name {
+			f.secrets[i] = *secret.DeepCopy()
+			return nil
+		}
+	}
+	f.secrets = append(f.secrets, *secret.DeepCopy())
+	return nil
+}
+
+func (f *fakeK8sClient) DeleteSecret(_ context.Context, _ string, name string) error {
+	for i := range f.secrets {
+		if f.secrets[i].Name == name {
+			f.secrets = append(f.secrets[:i], f.secrets[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("secret %s not found", name)
+}
+
+func (f *fakeK8sClient) ListConfigMaps(context.Context, string, string) ([]corev1.ConfigMap, error) {
+	return nil, nil
+}
+
+func (f *fakeK8sClient) WatchSecrets(context.Context, string, string) (watch.Interface, error) {
+	return watch.NewFake(), nil
+}
+
+func (f *fakeK8sClient) WatchConfigMaps(context.Context, string, string) (watch.Interface, error) {
+	return watch.NewFake(), nil
+}
+
+func (f *fakeK8sClient) Health(context.Context) error { return nil }
+func (f *fakeK8sClient) Close() error                 { return nil }
+
+func newTargetSecret(t *testing.T, target core.PublishingTarget) corev1.Secret {
+	t.Helper()
+	config, err := json.Marshal(target)
+	require.NoError(t, err)
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.Name,
+			Namespace: "default",
+			Labels:    map[string]string{"publishing-target": "true"},
+		},
+		Data: map[string][]byte{"config": config},
+	}
+}
+
+// startPostgres starts a PostgreSQL container and returns its host and port.
+func startPostgres(t *testing.T, ctx context.Context) (string, int) {
+	t.Helper()
+
+	container, err := postgres.Run(ctx,
+		"postgres:15-alpine",
+		postgres.WithDatabase("alerthistory"),
+		postgres.WithUsername("amp"),
+		postgres.WithPassword("amp"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60*time.Second)),
+	)
+	require.NoError(t, err, "failed to start postgres container")
+	t.Cleanup(func() { _ = container.Terminate(context.Background()) })
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "5432")
+	require.NoError(t, err)
+	return host, port.Int()
+}
+
+// startRedis starts a Redis container and returns its address.
+func startRedis(t *testing.T, ctx context.Context) string {
+	t.Helper()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:7-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections"),
+		},
+		Started: true,
+	})
+	require.NoError(t, err, "failed to start redis container")
+	t.Cleanup(func() { _ = container.Terminate(context.Background()) })
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "6379")
+	require.NoError(t, err)
+	return fmt.Sprintf("%s:%s", host, port.Port())
+}
+
+// setupPipeline boots the standard profile against real Postgres/Redis and mock providers.
+func setupPipeline(t *testing.T) *pipelineEnv {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	withIsolatedPrometheusRegistry(t)
+	t.Setenv("AMP_CONFIG_FILE", "")
+
+	ctx := context.Background()
+	pgHost, pgPort := startPostgres(t, ctx)
+	redisAddr := startRedis(t, ctx)
+
+	llmMock := newMockLLM(t)
+	receiver := newMockReceiver(t)
+
+	cfg := &appconfig.Config{
+		Profile: appconfig.ProfileStandard,
+		Storage: appconfig.StorageConfig{Backend: appconfig.StorageBackendPostgres},
+		Database: appconfig.DatabaseConfig{
+			Host:           pgHost,
+			Port:           pgPort,
+			Database:       "alerthistory",
+			Username:       "amp",
+			Password:       "amp",
+			SSLMode:        "disable",
+			MaxConnections: 10,
+			MinConnections: 1,
+			ConnectTimeout: 10 * time.Second,
+		},
+		Redis: appconfig.RedisConfig{
+			Addr:            redisAddr,
+			PoolSize:        10,
+			MinIdleConns:    1,
+			DialTimeout:     5 * time.Second,
+			ReadTimeout:     3 * time.Second,
+			WriteTimeout:    3 * time.Second,
+			MaxRetries:      3,
+			MinRetryBackoff: 8 * time.Millisecond,
+			MaxRetryBackoff: 512 * time.Millisecond,
+		},
+		LLM: appconfig.LLMConfig{
+			Enabled:    true,
+			Provider:   "proxy",
+			BaseURL:    llmMock.server.URL,
+			Model:      "mock",
+			Timeout:    5 * time.Second,
+			MaxRetries: 1,
+		},
+		Publishing: appconfig.PublishingConfig{
+			Enabled: true,
+			Discovery: appconfig.PublishingDiscoveryConfig{
+				Namespace:     "default",
+				LabelSelector: "publishing-target=true",
+			},
+			Queue: appconfig.PublishingQueueConfig{
+				MaxConcurrent:           5,
+				WorkerCount:             4,
+				HighPriorityQueueSize:   1000,
+				MediumPriorityQueueSize: 1000,
+				LowPriorityQueueSize:    1000,
+				MaxRetries:              2,
+				RetryInterval:           100 * time.Millisecond,
+				StopTimeout:             5 * time.Second,
+				JobTrackingCapacity:     1000,
+			},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	registry, err := NewServiceRegistry(cfg, logger)
+	require.NoError(t, err)
+
+	registry.k8sClient = &fakeK8sClient{secrets: []corev1.Secret{
+		newTargetSecret(t, core.PublishingTarget{
+			Name:    "integration-webhook",
+			Type:    "webhook",
+			URL:     receiver.server.URL,
+			Enabled: true,
+			Format:  core.FormatWebhook,
+		}),
+	}}
+
+	require.NoError(t, registry.Initialize(ctx))
+	t.Cleanup(func() { _ = registry.Shutdown(context.Background()) })
+
+	return &pipelineEnv{registry: registry, llm: llmMock, receiver: receiver}
+}
+
+func newPipelineAlert(name string) *core.Alert {
+	return &core.Alert{
+		AlertName: name,
+		Status:    core.StatusFiring,
+		Labels: map[string]string{
+			"alertname": name,
+			"severity":  "critical",
+			"namespace": "integration",
+		},
+		Annotations: map[string]string{"summary": "integration test alert"},
+		StartsAt:    time.Now().UTC(),
+	}
+}
+
+// TestIntegration_Pipeline_EndToEnd verifies that every stage is wired:
+// the alert is classified by the LLM, delivered to the discovered target
+// and persisted in PostgreSQL history.
+func TestIntegration_Pipeline_EndToEnd(t *testing.T) {
+	env := setupPipeline(t)
+	ctx := context.Background()
+
+	assert.Empty(t, env.registry.degradedReasons, "all dependencies are available, nothing should degrade")
+	assert.NoError(t, env.registry.Readiness(ctx))
+	require.NotNil(t, env.registry.publishingQueue, "publishing runtime must be active (not metrics-only)")
+
+	alert := newPipelineAlert("IntegrationHighCPU")
+	require.NoError(t, env.registry.AlertProcessor().ProcessAlert(ctx, alert))
+	require.NotEmpty(t, alert.Fingerprint, "deduplication must assign a fingerprint")
+
+	// classify
+	assert.GreaterOrEqual(t, env.llm.calls.Load(), int64(1))
+
+	// route → publish (asynchronous via queue)
+	assert.Eventually(t, func() bool { return env.receiver.count() >= 1 },
+		10*time.Second, 50*time.Millisecond, "webhook target must receive the alert")
+
+	// history
+	stored, err := env.registry.Storage().GetAlertByFingerprint(ctx, alert.Fingerprint)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "IntegrationHighCPU", stored.AlertName)
+	assert.Equal(t, core.StatusFiring, stored.Status)
+
+	// resolve updates history
+	resolved := newPipelineAlert("IntegrationHighCPU")
+	resolved.Status = core.StatusResolved
+	endsAt := time.Now().UTC()
+	resolved.EndsAt = &endsAt
+	require.NoError(t, env.registry.AlertProcessor().ProcessAlert(ctx, resolved))
+
+	assert.Eventually(t, func() bool {
+		stored, err := env.registry.Storage().GetAlertByFingerprint(ctx, alert.Fingerprint)
+		return err == nil && stored != nil && stored.Status == core.StatusResolved
+	}, 10*time.Second, 50*time.Millisecond, "resolved status must be persisted")
+}
+
+// TestIntegration_PipelineSoak pushes many distinct alerts concurrently and
+// checks that all are delivered and persisted without leaking goroutines.
+//
+// AMP_SOAK_ALERTS controls the number of alerts (default: 200).
+func TestIntegration_PipelineSoak(t *testing.T) {
+	total := 200
+	if v, err := strconv.Atoi(os.Getenv("AMP_SOAK_ALERTS")); err == nil && v > 0 {
+		total = v
+	}
+
+	env := setupPipeline(t)
+	ctx := context.Background()
+	baseline := runtime.NumGoroutine()
+
+	const producers = 8
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Int64
+	)
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := p; i < total; i += producers {
+				if err := env.registry.AlertProcessor().ProcessAlert(ctx, newPipelineAlert(fmt.Sprintf("Soak%05d", i))); err != nil {
+					failed.Add(1)
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	assert.Zero(t, failed.Load(), "no alert may fail processing")
+	assert.Eventually(t, func() bool { return env.receiver.count() >= total },
+		2*time.Minute, 100*time.Millisecond, "all alerts must be delivered")
+
+	stats, err := env.registry.Storage().GetAlertStats(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.TotalAlerts, total)
+
+	// Allow in-flight HTTP keep-alive goroutines to settle before comparing.
+	assert.Eventually(t, func() bool { return runtime.NumGoroutine() <= baseline+50 },
+		30*time.Second, 500*time.Millisecond, "goroutines must not grow with alert volume")
+}
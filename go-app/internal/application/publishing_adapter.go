@@ -9,6 +9,7 @@ import (
 	"github.com/ipiton/AMP/internal/core"
 	"github.com/ipiton/AMP/internal/core/services"
 	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+	"github.com/ipiton/AMP/pkg/core/domain"
 )
 
 type publishingCoordinator interface {
@@ -17,8 +18,10 @@ type publishingCoordinator interface {
 
 // ApplicationPublishingAdapter bridges AlertProcessor and the queue-based publishing stack.
 type ApplicationPublishingAdapter struct {
-	coordinator publishingCoordinator
-	logger      *slog.Logger
+	coordinator    publishingCoordinator
+	severityPolicy domain.SeverityPolicy
+	regionLabel    string
+	logger         *slog.Logger
 }
 
 // NewApplicationPublishingAdapter creates a publisher compatible with AlertProcessor.
@@ -31,11 +34,24 @@ func NewApplicationPublishingAdapter(coordinator publishingCoordinator, logger *
 	}
 
 	return &ApplicationPublishingAdapter{
-		coordinator: coordinator,
-		logger:      logger,
+		coordinator:    coordinator,
+		severityPolicy: domain.DefaultSeverityPolicy,
+		logger:         logger,
 	}, nil
 }
 
+// SetSeverityPolicy sets how the effective severity of published alerts is
+// resolved (publishing.severity_policy).
+func (p *ApplicationPublishingAdapter) SetSeverityPolicy(policy domain.SeverityPolicy) {
+	p.severityPolicy = policy
+}
+
+// SetRegionLabel sets the label indexed as the region of published alerts
+// (region_tagging.region_label).
+func (p *ApplicationPublishingAdapter) SetRegionLabel(label string) {
+	p.regionLabel = label
+}
+
 var _ services.Publisher = (*ApplicationPublishingAdapter)(nil)
 
 func (p *ApplicationPublishingAdapter) PublishToAll(ctx context.Context, alert *core.Alert) error {
@@ -52,11 +68,14 @@ func (p *ApplicationPublishingAdapter) publish(ctx context.Context, alert *core.
 	}
 
 	now := time.Now().UTC()
-	results, err := p.coordinator.PublishToAll(ctx, &core.EnrichedAlert{
+	enriched := &core.EnrichedAlert{
 		Alert:               alert,
 		Classification:      classification,
 		ProcessingTimestamp: &now,
-	})
+	}
+	enriched.ApplySeverityPolicy(p.severityPolicy)
+	enriched.IndexLabels(p.regionLabel)
+	results, err := p.coordinator.PublishToAll(ctx, enriched)
 	if err != nil {
 		return err
 	}
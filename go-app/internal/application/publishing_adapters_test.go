@@ -12,6 +12,7 @@ import (
 	appconfig "github.com/ipiton/AMP/internal/config"
 	"github.com/ipiton/AMP/internal/core"
 	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+	"github.com/ipiton/AMP/pkg/core/domain"
 )
 
 type fakePublishingCoordinator struct {
@@ -99,6 +100,75 @@ func TestApplicationPublishingAdapter_BuildsEnrichedAlert(t *testing.T) {
 	}
 }
 
+func TestApplicationPublishingAdapter_RecordsEffectiveSeverity(t *testing.T) {
+	coordinator := &fakePublishingCoordinator{
+		results: []*infrapublishing.PublishingResult{
+			{
+				Target:  &core.PublishingTarget{Name: "ops"},
+				Success: true,
+			},
+		},
+	}
+
+	adapter, err := NewApplicationPublishingAdapter(coordinator, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewApplicationPublishingAdapter() error = %v", err)
+	}
+	adapter.SetSeverityPolicy(domain.SeverityPolicyLabelFirst)
+
+	alert := &core.Alert{
+		Fingerprint: "abc123",
+		AlertName:   "HighCPU",
+		Labels:      map[string]string{"severity": "critical"},
+	}
+	classification := &core.ClassificationResult{Severity: core.SeverityWarning}
+
+	if err := adapter.PublishWithClassification(context.Background(), alert, classification); err != nil {
+		t.Fatalf("PublishWithClassification() error = %v", err)
+	}
+
+	severity, source := coordinator.alert.EffectiveSeverity()
+	if severity != core.SeverityCritical || source != domain.SeveritySourceLabel {
+		t.Fatalf("EffectiveSeverity() = %s (%s), want critical (label)", severity, source)
+	}
+	if got := coordinator.alert.EnrichmentMetadata[core.MetadataSeverityPolicy]; got != string(domain.SeverityPolicyLabelFirst) {
+		t.Fatalf("recorded severity policy = %v, want %s", got, domain.SeverityPolicyLabelFirst)
+	}
+}
+
+func TestApplicationPublishingAdapter_IndexesKnownLabels(t *testing.T) {
+	coordinator := &fakePublishingCoordinator{
+		results: []*infrapublishing.PublishingResult{
+			{
+				Target:  &core.PublishingTarget{Name: "ops"},
+				Success: true,
+			},
+		},
+	}
+
+	adapter, err := NewApplicationPublishingAdapter(coordinator, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewApplicationPublishingAdapter() error = %v", err)
+	}
+	adapter.SetRegionLabel("dc")
+
+	alert := &core.Alert{
+		Fingerprint: "abc123",
+		AlertName:   "HighCPU",
+		Labels:      map[string]string{"team": "sre", "dc": "eu-west"},
+	}
+	if err := adapter.PublishToAll(context.Background(), alert); err != nil {
+		t.Fatalf("PublishToAll() error = %v", err)
+	}
+
+	// Labels changed after publishing are not picked up by the index.
+	alert.Labels["team"] = "dba"
+	known := coordinator.alert.KnownLabels()
+	if known.Team != "sre" || known.Region != "eu-west" {
+		t.Fatalf("KnownLabels() = %+v, want team sre and region eu-west", known)
+	}
+}
+
 func TestApplicationPublishingAdapter_ReturnsErrorWhenAllTargetsFail(t *testing.T) {
 	coordinator := &fakePublishingCoordinator{
 		results: []*infrapublishing.PublishingResult{
@@ -2,6 +2,8 @@ package application
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 	appconfig "github.com/ipiton/AMP/internal/config"
 	"github.com/ipiton/AMP/internal/infrastructure/k8s"
 	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+	"github.com/ipiton/AMP/pkg/core/domain"
 	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -40,17 +43,20 @@ func (r *ServiceRegistry) initializePublishing(ctx context.Context) {
 }
 
 func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error {
-	k8sConfig := k8s.DefaultK8sClientConfig()
-	k8sConfig.Logger = r.logger
+	// A pre-set client (integration tests) takes precedence over in-cluster config.
+	if r.k8sClient == nil {
+		k8sConfig := k8s.DefaultK8sClientConfig()
+		k8sConfig.Logger = r.logger
 
-	k8sClient, err := k8s.NewK8sClient(k8sConfig)
-	if err != nil {
-		return err
+		k8sClient, err := k8s.NewK8sClient(k8sConfig)
+		if err != nil {
+			return err
+		}
+		r.k8sClient = k8sClient
 	}
-	r.k8sClient = k8sClient
 
 	discovery, err := businesspublishing.NewTargetDiscoveryManager(
-		k8sClient,
+		r.k8sClient,
 		resolvePublishingNamespace(r.config),
 		r.config.Publishing.Discovery.LabelSelector,
 		r.logger,
@@ -61,6 +67,15 @@ func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error
 	}
 	r.publishingDiscovery = discovery
 
+	publishingMetrics := v2.Global().Publishing
+	if sourced, ok := discovery.(*businesspublishing.DefaultTargetDiscoveryManager); ok {
+		sourced.SetSources(businesspublishing.DiscoverySourcesConfig{
+			Namespaces: r.config.Publishing.Discovery.Namespaces,
+			ConfigMaps: r.config.Publishing.Discovery.ConfigMaps,
+			Metrics:    publishingMetrics,
+		})
+	}
+
 	if err := discovery.DiscoverTargets(ctx); err != nil {
 		r.logger.Warn("Initial publishing target discovery failed, starting with empty cache", "error", err)
 	}
@@ -79,14 +94,32 @@ func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error
 		return err
 	}
 
-	publishingMetrics := v2.Global().Publishing
 	externalURL := r.config.Server.ExternalURL
 	r.publisherFactory = infrapublishing.NewPublisherFactory(
-		infrapublishing.NewAlertFormatter(externalURL),
+		infrapublishing.NewAlertFormatterWithStyles(externalURL, r.config.Publishing.SeverityStyles),
 		r.logger,
 		publishingMetrics,
 		externalURL,
 	)
+	if r.snoozes != nil {
+		r.publisherFactory.SetSnoozeChecker(r.snoozes)
+	}
+	if len(r.config.Publishing.Plugins) > 0 {
+		plugins, err := infrapublishing.NewPluginSupervisor(publisherPluginConfigs(r.config.Publishing.Plugins), r.logger)
+		if err != nil {
+			return err
+		}
+		plugins.Start()
+		r.publishingPlugins = plugins
+		r.publisherFactory.SetPluginSupervisor(plugins)
+	}
+	if len(r.config.Publishing.Commands) > 0 {
+		commands, err := infrapublishing.NewExecCommands(execCommandConfigs(r.config.Publishing.Commands))
+		if err != nil {
+			return err
+		}
+		r.publisherFactory.SetExecCommands(commands)
+	}
 
 	queueConfig := infrapublishing.DefaultPublishingQueueConfig()
 	queueConfig.WorkerCount = r.config.Publishing.Queue.WorkerCount
@@ -95,16 +128,106 @@ func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error
 	queueConfig.LowPriorityQueueSize = r.config.Publishing.Queue.LowPriorityQueueSize
 	queueConfig.MaxRetries = r.config.Publishing.Queue.MaxRetries
 	queueConfig.RetryInterval = r.config.Publishing.Queue.RetryInterval
+	queueConfig.DedupWindow = r.config.Publishing.Queue.DedupWindow
 	queueConfig.Metrics = publishingMetrics
+	queueConfig.Heartbeat = r.publishingQueueHeartbeat()
+	queueConfig.Shedding = infrapublishing.ShedPolicy{
+		SoftLimit: r.config.Publishing.Queue.Shedding.SoftLimit,
+		Order:     r.config.Publishing.Queue.Shedding.Order,
+	}
+	if scaling := r.config.Publishing.Queue.Autoscaling; scaling.Enabled {
+		queueConfig.Autoscaling = infrapublishing.WorkerAutoscaling{
+			MinWorkers:         scaling.MinWorkers,
+			MaxWorkers:         scaling.MaxWorkers,
+			ScaleUpUtilization: scaling.ScaleUpUtilization,
+			ScaleUpLatency:     scaling.ScaleUpLatency,
+			ScaleDownAfter:     scaling.ScaleDownAfter,
+			Interval:           scaling.Interval,
+		}
+	}
+	if len(r.config.Publishing.Queue.TargetLimits) > 0 {
+		queueConfig.TargetLimits = make(map[string]infrapublishing.TargetLimit, len(r.config.Publishing.Queue.TargetLimits))
+		for targetType, limit := range r.config.Publishing.Queue.TargetLimits {
+			queueConfig.TargetLimits[targetType] = infrapublishing.TargetLimit{
+				RateLimit:      limit.RateLimit,
+				Burst:          limit.Burst,
+				MaxConcurrency: limit.MaxConcurrency,
+			}
+		}
+	}
+	queueConfig.CircuitBreaker = circuitBreakerConfig(r.config.Publishing.Queue.CircuitBreaker)
+	if len(r.config.Publishing.Queue.CircuitBreakers) > 0 {
+		queueConfig.CircuitBreakers = make(map[string]infrapublishing.CircuitBreakerConfig, len(r.config.Publishing.Queue.CircuitBreakers))
+		for targetType, breaker := range r.config.Publishing.Queue.CircuitBreakers {
+			queueConfig.CircuitBreakers[targetType] = circuitBreakerConfig(breaker)
+		}
+	}
+	queueConfig.DeliverySLO = infrapublishing.DeliverySLO{
+		Thresholds:       r.config.Publishing.SLO.Thresholds,
+		DefaultThreshold: r.config.Publishing.SLO.DefaultThreshold,
+	}
+	if r.deliveryLog != nil {
+		queueConfig.Deliveries = r.deliveryLog
+	}
+	// Every publish attempt is persisted for auditing when a database is
+	// available
+	if r.config.Publishing.Deliveries.Persist && r.database != nil && r.database.Pool() != nil {
+		audit, err := infrapublishing.NewDeliveryAuditLog(infrapublishing.DeliveryAuditConfig{
+			Store:      infrapublishing.NewPostgreSQLDeliveryAuditStore(r.database.Pool()),
+			Retention:  r.config.Publishing.Deliveries.Retention,
+			BufferSize: r.config.Publishing.Deliveries.BufferSize,
+			Metrics:    publishingMetrics,
+			Logger:     r.logger,
+		})
+		if err != nil {
+			return err
+		}
+		audit.Start()
+		r.publishingDeliveryAudit = audit
+		if r.deliveryLog != nil {
+			queueConfig.Deliveries = infrapublishing.DeliveryRecorders{r.deliveryLog, audit}
+		} else {
+			queueConfig.Deliveries = audit
+		}
+	}
+	durableConfig, err := r.durablePublishingQueueConfig()
+	if err != nil {
+		return err
+	}
+	queueConfig.Durable = durableConfig
+	queueConfig.Targets = discoveryAdapter
+
+	// Firing alerts missing the delivery quorum of their class are
+	// escalated to fallback targets
+	var quorum *infrapublishing.QuorumTracker
+	if len(r.config.Publishing.Quorum.Policies) > 0 {
+		quorum, err = infrapublishing.NewQuorumTracker(quorumConfig(r.config.Publishing.Quorum, publishingMetrics, r.logger))
+		if err != nil {
+			return fmt.Errorf("publishing.quorum: %w", err)
+		}
+		queueConfig.Outcomes = quorum
+	}
+
+	// Jobs that fail after all retries are kept in the DLQ when a database
+	// is available
+	var dlq infrapublishing.DLQRepository
+	if r.database != nil && r.database.Pool() != nil {
+		r.publishingDLQ = infrapublishing.NewPostgreSQLDLQRepository(r.database.Pool(), nil, r.logger)
+		dlq = r.publishingDLQ
+	}
 
+	r.publishingJobs = infrapublishing.NewLRUJobTrackingStore(r.config.Publishing.Queue.JobTrackingCapacity)
 	r.publishingQueue = infrapublishing.NewPublishingQueue(
 		r.publisherFactory,
-		nil,
-		infrapublishing.NewLRUJobTrackingStore(r.config.Publishing.Queue.JobTrackingCapacity),
+		dlq,
+		r.publishingJobs,
 		queueConfig,
 		r.publishingMode,
 		r.logger,
 	)
+	if r.publishingDLQ != nil {
+		r.publishingDLQ.SetQueue(r.publishingQueue)
+	}
 	r.publishingQueue.Start()
 
 	coordinatorConfig := infrapublishing.DefaultCoordinatorConfig()
@@ -116,6 +239,26 @@ func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error
 		coordinatorConfig,
 		r.logger,
 	)
+	r.publishingCoordinator.SetJiraIssueKeys(r.publisherFactory)
+	if r.config.Publishing.Routing.Enabled {
+		router, err := infrapublishing.NewTargetRouter(targetRoute(r.config.Publishing.Routing.Route))
+		if err != nil {
+			return fmt.Errorf("publishing.routing: %w", err)
+		}
+		r.publishingCoordinator.SetTargetRouter(router)
+	}
+	if quorum != nil {
+		r.publishingCoordinator.SetQuorumTracker(quorum)
+	}
+
+	// Release the state of targets removed from discovery
+	r.publishingTargetGC = infrapublishing.NewTargetGC(
+		discoveryAdapter,
+		r.publishingQueue,
+		r.publisherFactory,
+		infrapublishing.TargetGCConfig{Metrics: publishingMetrics, Logger: r.logger},
+	)
+	r.publishingTargetGC.AddForgetter(r.publishingCoordinator)
 
 	if r.config.Publishing.Refresh.Enabled {
 		refreshConfig := businesspublishing.DefaultRefreshConfig()
@@ -168,7 +311,11 @@ func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error
 			return err
 		}
 		r.publishingHealth = healthMonitor
+		r.publishingTargetGC.AddForgetter(healthMonitor)
 	}
+	r.startDLQDrainer(ctx, discoveryAdapter, publishingMetrics)
+	r.publishingTargetGC.Start(ctx)
+	r.startDiscoveryWatch(ctx, discovery)
 
 	r.publishingMetricsCollector = businesspublishing.NewPublishingMetricsCollector()
 	r.publishingMetricsCollector.RegisterCollector(businesspublishing.NewDiscoveryMetricsCollector(discovery))
@@ -185,6 +332,12 @@ func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error
 	if err != nil {
 		return err
 	}
+	severityPolicy, err := domain.ParseSeverityPolicy(r.config.Publishing.SeverityPolicy)
+	if err != nil {
+		return err
+	}
+	publisher.SetSeverityPolicy(severityPolicy)
+	publisher.SetRegionLabel(r.config.RegionTagging.RegionLabel)
 	r.publisher = publisher
 
 	r.logger.Info("Publishing runtime initialized",
@@ -196,7 +349,38 @@ func (r *ServiceRegistry) initializePublishingRuntime(ctx context.Context) error
 	return nil
 }
 
+// startDiscoveryWatch rediscovers the targets as soon as their objects
+// change, and releases the state of removed targets right away.
+func (r *ServiceRegistry) startDiscoveryWatch(ctx context.Context, discovery businesspublishing.TargetDiscoveryManager) {
+	watcher, ok := discovery.(businesspublishing.TargetWatcher)
+	if !r.config.Publishing.Discovery.Watch || !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	gc := r.publishingTargetGC
+	go func() {
+		defer close(done)
+		watcher.Watch(ctx, func() { gc.Collect() })
+	}()
+	r.publishingDiscoveryWatch = cancel
+	r.publishingDiscoveryWatched = done
+}
+
 func (r *ServiceRegistry) shutdownPublishing() {
+	if r.publishingDiscoveryWatch != nil {
+		r.publishingDiscoveryWatch()
+		<-r.publishingDiscoveryWatched
+		r.publishingDiscoveryWatch = nil
+		r.publishingDiscoveryWatched = nil
+	}
+	if r.publishingTargetGC != nil {
+		r.publishingTargetGC.Stop()
+		r.publishingTargetGC = nil
+	}
+	r.stopDLQDrainer()
+
 	if r.publishingRefresh != nil {
 		timeout := r.config.Publishing.Queue.StopTimeout
 		if timeout <= 0 {
@@ -226,6 +410,11 @@ func (r *ServiceRegistry) shutdownPublishing() {
 		r.publishingMode = nil
 	}
 
+	// Release alerts held by target groupings while the queue still runs
+	if r.publishingCoordinator != nil {
+		r.publishingCoordinator.Stop()
+	}
+
 	if r.publishingQueue != nil {
 		timeout := r.config.Publishing.Queue.StopTimeout
 		if timeout <= 0 {
@@ -235,6 +424,19 @@ func (r *ServiceRegistry) shutdownPublishing() {
 			r.logger.Warn("Publishing queue shutdown failed", "error", err)
 		}
 		r.publishingQueue = nil
+		r.publishingJobs = nil
+	}
+
+	// Write the attempts of the last jobs
+	if r.publishingDeliveryAudit != nil {
+		timeout := r.config.Publishing.Queue.StopTimeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		if err := r.publishingDeliveryAudit.Stop(timeout); err != nil {
+			r.logger.Warn("Delivery audit log shutdown failed", "error", err)
+		}
+		r.publishingDeliveryAudit = nil
 	}
 
 	if r.publisherFactory != nil {
@@ -242,6 +444,16 @@ func (r *ServiceRegistry) shutdownPublishing() {
 		r.publisherFactory = nil
 	}
 
+	// Plugins outlive the queue so in-flight deliveries complete
+	if r.publishingPlugins != nil {
+		timeout := r.config.Publishing.Queue.StopTimeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		r.publishingPlugins.Stop(timeout)
+		r.publishingPlugins = nil
+	}
+
 	if r.k8sClient != nil {
 		if err := r.k8sClient.Close(); err != nil {
 			r.logger.Warn("Publishing k8s client shutdown failed", "error", err)
@@ -250,6 +462,7 @@ func (r *ServiceRegistry) shutdownPublishing() {
 	}
 
 	r.publishingCoordinator = nil
+	r.publishingDLQ = nil
 	r.publishingDiscoveryAdapter = nil
 	r.publishingDiscovery = nil
 	r.publishingMetricsCollector = nil
@@ -276,3 +489,76 @@ func resolvePublishingNamespace(cfg *appconfig.Config) string {
 
 	return "default"
 }
+
+// publisherPluginConfigs converts the configured publisher plugins.
+func publisherPluginConfigs(plugins []appconfig.PublisherPluginConfig) []infrapublishing.PluginConfig {
+	configs := make([]infrapublishing.PluginConfig, 0, len(plugins))
+	for _, plugin := range plugins {
+		configs = append(configs, infrapublishing.PluginConfig{
+			Name:             plugin.Name,
+			Path:             plugin.Path,
+			Args:             plugin.Args,
+			Env:              plugin.Env,
+			StartTimeout:     plugin.StartTimeout,
+			HealthInterval:   plugin.HealthInterval,
+			FailureThreshold: plugin.FailureThreshold,
+		})
+	}
+	return configs
+}
+
+// execCommandConfigs converts the configured commands of exec targets.
+func execCommandConfigs(commands []appconfig.ExecCommandConfig) []infrapublishing.ExecCommandConfig {
+	configs := make([]infrapublishing.ExecCommandConfig, 0, len(commands))
+	for _, command := range commands {
+		configs = append(configs, infrapublishing.ExecCommandConfig{
+			Name:           command.Name,
+			Path:           command.Path,
+			Args:           command.Args,
+			Env:            command.Env,
+			EnvAllowlist:   command.EnvAllowlist,
+			Timeout:        command.Timeout,
+			MaxConcurrency: command.MaxConcurrency,
+		})
+	}
+	return configs
+}
+
+// targetRoute converts the configured route tree under route.
+func targetRoute(route appconfig.PublishingRouteConfig) infrapublishing.TargetRoute {
+	converted := infrapublishing.TargetRoute{
+		Targets:  route.Targets,
+		Match:    route.Match,
+		MatchRE:  route.MatchRE,
+		Continue: route.Continue,
+	}
+	for _, child := range route.Routes {
+		converted.Routes = append(converted.Routes, targetRoute(child))
+	}
+	return converted
+}
+
+// quorumConfig converts the configured quorum policies.
+func quorumConfig(quorum appconfig.PublishingQuorumConfig, metrics *v2.PublishingMetrics, logger *slog.Logger) infrapublishing.QuorumConfig {
+	converted := infrapublishing.QuorumConfig{Timeout: quorum.Timeout, Metrics: metrics, Logger: logger}
+	for _, policy := range quorum.Policies {
+		converted.Policies = append(converted.Policies, infrapublishing.QuorumPolicy{
+			Name:       policy.Name,
+			Match:      policy.Match,
+			MatchRE:    policy.MatchRE,
+			Targets:    policy.Targets,
+			MinSuccess: policy.MinSuccess,
+			Fallback:   policy.Fallback,
+		})
+	}
+	return converted
+}
+
+// circuitBreakerConfig converts a configured circuit breaker.
+func circuitBreakerConfig(breaker appconfig.PublishingCircuitBreakerConfig) infrapublishing.CircuitBreakerConfig {
+	return infrapublishing.CircuitBreakerConfig{
+		FailureThreshold: breaker.FailureThreshold,
+		SuccessThreshold: breaker.SuccessThreshold,
+		Timeout:          breaker.Timeout,
+	}
+}
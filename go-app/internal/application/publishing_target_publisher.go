@@ -0,0 +1,32 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// queueTargetPublisher submits AMP's own notifications (silence expiry,
+// handoff reports) to the publishing queue for a named target.
+type queueTargetPublisher struct {
+	discovery *DiscoveryAdapter
+	queue     *infrapublishing.PublishingQueue
+}
+
+var _ services.TargetPublisher = (*queueTargetPublisher)(nil)
+
+func (p *queueTargetPublisher) PublishToTarget(_ context.Context, targetName string, alert *core.Alert) error {
+	target, err := p.discovery.GetTarget(targetName)
+	if err != nil {
+		return fmt.Errorf("target %q: %w", targetName, err)
+	}
+	if !target.Enabled {
+		return fmt.Errorf("target %q is disabled", targetName)
+	}
+	enriched := &core.EnrichedAlert{Alert: alert}
+	enriched.IndexLabels("")
+	return p.queue.Submit(enriched, target)
+}
@@ -25,16 +25,48 @@ func (rt *Router) SetupRoutes(mux *http.ServeMux) {
 	// API v2
 	mux.HandleFunc("/api/v2/alerts", handlers.AlertsHandler(rt.registry))
 	mux.HandleFunc("/api/v2/alerts/groups", handlers.AlertGroupsHandler(rt.registry))
+	mux.HandleFunc("/api/v2/alerts/", handlers.AlertResourcesHandler(rt.registry))
 	mux.HandleFunc("/api/v2/silences", handlers.SilencesHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/preview", handlers.SilencePreviewHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/stats", handlers.SilenceStatsHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/recurring", handlers.RecurringSilencesHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/recurring/", handlers.RecurringSilenceByIDHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/import", handlers.SilenceImportHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/export", handlers.SilenceExportHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/from-template/", handlers.SilenceFromTemplateHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/approvals", handlers.SilenceApprovalsHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/approvals/", handlers.SilenceApprovalByIDHandler(rt.registry))
+	mux.HandleFunc("/api/v2/silences/", handlers.SilenceHistoryHandler(rt.registry))
 	mux.HandleFunc("/api/v2/silence/", handlers.SilenceByIDHandler(rt.registry))
 	mux.HandleFunc("/api/v2/status", handlers.StatusAPIHandler(rt.registry))
+	mux.HandleFunc("/api/v2/status/startup", handlers.StartupReportHandler(rt.registry))
 	mux.HandleFunc("/api/v2/receivers", handlers.ReceiversHandler(rt.registry))
 	mux.HandleFunc("/api/v2/inhibitions", handlers.InhibitionsHandler(rt.registry))
+	mux.HandleFunc("/api/v2/snoozes", handlers.SnoozesHandler(rt.registry))
+	mux.HandleFunc("/api/v2/reports/handoff", handlers.HandoffReportHandler(rt.registry))
+	mux.HandleFunc("/api/v2/stats/alerts", handlers.AlertStatsHandler(rt.registry))
+	mux.HandleFunc("/api/v2/regions", handlers.RegionsHandler(rt.registry))
+	mux.HandleFunc("/api/v2/admin/maintenance", handlers.MaintenanceHandler(rt.registry))
+	mux.HandleFunc("/api/v2/admin/reclassify", handlers.ReclassificationHandler(rt.registry))
+
+	// Integrations (authenticated by request signature, not API tokens)
+	mux.HandleFunc("/integrations/slack/command", handlers.SlackCommandHandler(rt.registry))
+	mux.HandleFunc("/integrations/pagerduty/webhook", handlers.PagerDutyWebhookHandler(rt.registry))
 
 	// API v1 — Investigation pipeline (PHASE-5B)
 	// Register exact path first to prevent ServeMux from redirecting /api/v1/alerts → /api/v1/alerts/
 	mux.HandleFunc("/api/v1/alerts", handlers.NotFoundHandler)
 	mux.HandleFunc("/api/v1/alerts/", handlers.InvestigationHandler(rt.registry))
+	mux.HandleFunc("/api/v1/inhibition/simulate", handlers.InhibitionSimulateHandler(rt.registry))
+	mux.HandleFunc("/api/v1/inhibition/sources", handlers.InhibitionSourcesHandler(rt.registry))
+	mux.HandleFunc("/api/v1/inhibition/sources/", handlers.InhibitionSourceWebhookHandler(rt.registry))
+	mux.HandleFunc("/api/v1/publishing/targets", handlers.PublishingTargetsHandler(rt.registry))
+	mux.HandleFunc("/api/v1/publishing/targets/", handlers.PublishingTargetHandler(rt.registry))
+	mux.HandleFunc("/api/v1/publishing/routing/test", handlers.PublishingRoutingTestHandler(rt.registry))
+	mux.HandleFunc("/api/v1/publishing/circuit-breakers", handlers.PublishingCircuitBreakersHandler(rt.registry))
+	mux.HandleFunc("/api/v1/publishing/circuit-breakers/", handlers.PublishingCircuitBreakerHandler(rt.registry))
+	mux.HandleFunc("/api/v1/publishing/deliveries", handlers.PublishingDeliveriesHandler(rt.registry))
+	mux.HandleFunc("/api/v1/publishing/drain", handlers.PublishingDrainHandler(rt.registry))
 
 	// Health
 	mux.HandleFunc("/health", handlers.HealthHandler(rt.registry))
@@ -137,6 +137,12 @@ func newActiveContractMux(t *testing.T, storageHealthErr error) *http.ServeMux {
 		logger:            logger,
 		alertStore:        memory.NewAlertStore(),
 		silenceStore:      memory.NewSilenceStore(),
+		silenceAudit:      memory.NewSilenceAuditLog(0),
+		decisionLog:       memory.NewDecisionLog(0, 0),
+		deliveryLog:       memory.NewDeliveryLog(0, 0),
+		recurringSilences: memory.NewRecurringSilenceStore(),
+		snoozes:           memory.NewSnoozeStore(),
+		maintenance:       services.NewMaintenanceMode(nil, logger),
 		alertProcessor:    processor,
 		storageRuntime:    storageRuntime,
 		storage:           storageRuntime,
@@ -191,6 +197,10 @@ func TestActiveRuntimeContract_PresentEndpoints(t *testing.T) {
 		{name: "readyz", method: http.MethodGet, path: "/readyz", status: http.StatusOK},
 		{name: "alertmanager healthy get", method: http.MethodGet, path: "/-/healthy", status: http.StatusOK},
 		{name: "alertmanager ready get", method: http.MethodGet, path: "/-/ready", status: http.StatusOK},
+		{name: "alertmanager healthy head", method: http.MethodHead, path: "/-/healthy", status: http.StatusOK},
+		{name: "alertmanager ready head", method: http.MethodHead, path: "/-/ready", status: http.StatusOK},
+		{name: "alertmanager healthy post not allowed", method: http.MethodPost, path: "/-/healthy", status: http.StatusMethodNotAllowed},
+		{name: "alertmanager ready post not allowed", method: http.MethodPost, path: "/-/ready", status: http.StatusMethodNotAllowed},
 		{name: "metrics", method: http.MethodGet, path: "/metrics", status: http.StatusOK},
 	}
 
@@ -217,8 +227,50 @@ func TestActiveRuntimeContract_RestoredOperationalEndpointsPresent(t *testing.T)
 		status int
 	}{
 		{name: "status get", method: http.MethodGet, path: "/api/v2/status", status: http.StatusOK},
+		{name: "startup report before reconciliation", method: http.MethodGet, path: "/api/v2/status/startup", status: http.StatusServiceUnavailable},
 		{name: "receivers get", method: http.MethodGet, path: "/api/v2/receivers", status: http.StatusOK},
 		{name: "alert groups get", method: http.MethodGet, path: "/api/v2/alerts/groups", status: http.StatusOK},
+		{name: "alert decisions unknown fingerprint", method: http.MethodGet, path: "/api/v2/alerts/0123456789abcdef/decisions", status: http.StatusNotFound},
+		{name: "alert decisions post not allowed", method: http.MethodPost, path: "/api/v2/alerts/0123456789abcdef/decisions", status: http.StatusMethodNotAllowed},
+		{name: "alert deliveries unknown fingerprint", method: http.MethodGet, path: "/api/v2/alerts/0123456789abcdef/deliveries", status: http.StatusNotFound},
+		{name: "alert deliveries post not allowed", method: http.MethodPost, path: "/api/v2/alerts/0123456789abcdef/deliveries", status: http.StatusMethodNotAllowed},
+		{name: "alert inhibition without rules", method: http.MethodGet, path: "/api/v2/alerts/0123456789abcdef/inhibition", status: http.StatusOK},
+		{name: "alert inhibition post not allowed", method: http.MethodPost, path: "/api/v2/alerts/0123456789abcdef/inhibition", status: http.StatusMethodNotAllowed},
+		{name: "inhibition simulate invalid body", method: http.MethodPost, path: "/api/v1/inhibition/simulate", status: http.StatusBadRequest},
+		{name: "inhibition simulate get not allowed", method: http.MethodGet, path: "/api/v1/inhibition/simulate", status: http.StatusMethodNotAllowed},
+		{name: "inhibition sources get", method: http.MethodGet, path: "/api/v1/inhibition/sources", status: http.StatusOK},
+		{name: "inhibition source webhook unknown source", method: http.MethodPost, path: "/api/v1/inhibition/sources/legacy/webhook", status: http.StatusNotFound},
+		{name: "publishing targets without publishing runtime", method: http.MethodGet, path: "/api/v1/publishing/targets", status: http.StatusServiceUnavailable},
+		{name: "publishing target test without publishing runtime", method: http.MethodPost, path: "/api/v1/publishing/targets/hooks/test", status: http.StatusServiceUnavailable},
+		{name: "publishing routing test without publishing runtime", method: http.MethodPost, path: "/api/v1/publishing/routing/test", status: http.StatusServiceUnavailable},
+		{name: "circuit breakers without publishing runtime", method: http.MethodGet, path: "/api/v1/publishing/circuit-breakers", status: http.StatusServiceUnavailable},
+		{name: "circuit breaker open without publishing runtime", method: http.MethodPost, path: "/api/v1/publishing/circuit-breakers/hooks/open", status: http.StatusServiceUnavailable},
+		{name: "publishing deliveries unknown fingerprint", method: http.MethodGet, path: "/api/v1/publishing/deliveries?fingerprint=0123456789abcdef", status: http.StatusOK},
+		{name: "publishing deliveries without fingerprint", method: http.MethodGet, path: "/api/v1/publishing/deliveries", status: http.StatusBadRequest},
+		{name: "publishing drain without publishing runtime", method: http.MethodPost, path: "/api/v1/publishing/drain", status: http.StatusServiceUnavailable},
+		{name: "silence preview invalid body", method: http.MethodPost, path: "/api/v2/silences/preview", status: http.StatusBadRequest},
+		{name: "silence preview get not allowed", method: http.MethodGet, path: "/api/v2/silences/preview", status: http.StatusMethodNotAllowed},
+		{name: "silence stats get", method: http.MethodGet, path: "/api/v2/silences/stats", status: http.StatusOK},
+		{name: "silence stats post not allowed", method: http.MethodPost, path: "/api/v2/silences/stats", status: http.StatusMethodNotAllowed},
+		{name: "recurring silences get", method: http.MethodGet, path: "/api/v2/silences/recurring", status: http.StatusOK},
+		{name: "silence history unknown id", method: http.MethodGet, path: "/api/v2/silences/00000000-0000-4000-8000-000000000001/history", status: http.StatusNotFound},
+		{name: "silence history invalid id", method: http.MethodGet, path: "/api/v2/silences/not-a-uuid/history", status: http.StatusUnprocessableEntity},
+		{name: "recurring silence unknown id", method: http.MethodGet, path: "/api/v2/silences/recurring/unknown", status: http.StatusNotFound},
+		{name: "silence import without source", method: http.MethodPost, path: "/api/v2/silences/import", status: http.StatusBadRequest},
+		{name: "silence import alertmanager not configured", method: http.MethodPost, path: "/api/v2/silences/import?source=alertmanager", status: http.StatusNotFound},
+		{name: "silence export get", method: http.MethodGet, path: "/api/v2/silences/export", status: http.StatusOK},
+		{name: "silence export alertmanager not configured", method: http.MethodPost, path: "/api/v2/silences/export", status: http.StatusNotFound},
+		{name: "silence from unknown template", method: http.MethodPost, path: "/api/v2/silences/from-template/unknown", status: http.StatusNotFound},
+		{name: "silence approvals list", method: http.MethodGet, path: "/api/v2/silences/approvals", status: http.StatusOK},
+		{name: "approve unknown silence request", method: http.MethodPost, path: "/api/v2/silences/approvals/unknown/approve", status: http.StatusNotFound},
+		{name: "snoozes get without user", method: http.MethodGet, path: "/api/v2/snoozes", status: http.StatusBadRequest},
+		{name: "snoozes get", method: http.MethodGet, path: "/api/v2/snoozes?user=U1", status: http.StatusOK},
+		{name: "handoff report disabled", method: http.MethodGet, path: "/api/v2/reports/handoff?team=payments", status: http.StatusNotFound},
+		{name: "maintenance get", method: http.MethodGet, path: "/api/v2/admin/maintenance", status: http.StatusOK},
+		{name: "maintenance invalid body", method: http.MethodPost, path: "/api/v2/admin/maintenance", status: http.StatusBadRequest},
+		{name: "reclassify without classification", method: http.MethodGet, path: "/api/v2/admin/reclassify", status: http.StatusServiceUnavailable},
+		{name: "regions disabled", method: http.MethodGet, path: "/api/v2/regions", status: http.StatusNotFound},
+		{name: "slack command disabled", method: http.MethodPost, path: "/integrations/slack/command", status: http.StatusNotFound},
 		{name: "reload post", method: http.MethodPost, path: "/-/reload", status: http.StatusOK},
 		{name: "reload get not allowed", method: http.MethodGet, path: "/-/reload", status: http.StatusMethodNotAllowed},
 	}
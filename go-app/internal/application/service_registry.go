@@ -21,10 +21,15 @@ import (
 	invtools "github.com/ipiton/AMP/internal/infrastructure/investigation/tools"
 	"github.com/ipiton/AMP/internal/infrastructure/k8s"
 	"github.com/ipiton/AMP/internal/infrastructure/llm"
+	"github.com/ipiton/AMP/internal/infrastructure/mirror"
+	"github.com/ipiton/AMP/internal/infrastructure/silencewebhook"
 	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
 	investigationrepo "github.com/ipiton/AMP/internal/infrastructure/repository"
+	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
 	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/internal/infrastructure/watchdog"
 	"github.com/ipiton/AMP/pkg/metrics"
+	"github.com/ipiton/AMP/pkg/telemetry"
 )
 
 // alertCacheWithLifecycle extends ActiveAlertCache with lifecycle management (Stop).
@@ -65,6 +70,52 @@ type ServiceRegistry struct {
 	// Memory Stores (for Alertmanager compatibility mode)
 	alertStore   *memory.AlertStore
 	silenceStore *memory.SilenceStore
+	decisionLog  *memory.DecisionLog
+	deliveryLog  *memory.DeliveryLog
+
+	// Recurring silences and the scheduler materializing them into silenceStore
+	recurringSilences         *memory.RecurringSilenceStore
+	recurringSilenceScheduler *services.RecurringSilenceScheduler
+
+	// Notifies targets of silences created with notifyOnExpiry
+	silenceExpiryNotifier *services.SilenceExpiryNotifier
+
+	// Deletes silences past their retention
+	silenceGC *services.SilenceGC
+
+	// Deletes alerts past their retention from the alert history
+	alertGC *services.AlertGC
+
+	// Maintains hourly and daily alert counts for the dashboard
+	alertRollups *services.AlertRollupJob
+
+	// Switches the alert processor to digests during alert storms
+	stormDetector *services.StormDetector
+
+	// Publishes the scheduled on-call handoff report
+	handoffReportScheduler *services.HandoffReportScheduler
+
+	// Personal snoozes honoured by chat publishers
+	snoozes *memory.SnoozeStore
+
+	// Alerts acknowledged in PagerDuty
+	acknowledgements *memory.AcknowledgementStore
+
+	// Holds publishing (and optionally ingestion) during maintenance
+	maintenance *services.MaintenanceMode
+
+	// Admin-triggered re-classification of historical alerts
+	reclassification *services.ReclassificationJob
+
+	// Persistent backing for silenceStore (PostgreSQL or SQLite based on profile)
+	silenceRepo infrasilencing.SilenceRepository
+	silencePersistence *silencePersistence
+
+	// Applies silence changes of other replicas from the Redis silence cache
+	silenceReplicator *silenceReplicator
+
+	// Append-only change history of silences
+	silenceAudit core.SilenceAuditStore
 
 	// Core Services
 	alertProcessor    *services.AlertProcessor
@@ -77,6 +128,8 @@ type ServiceRegistry struct {
 	inhibitionCache   alertCacheWithLifecycle              // two-tier cache of firing alerts (includes Stop)
 	inhibitionMatcher inhibitionpkg.InhibitionMatcher      // rule engine
 	inhibitionState   inhibitionpkg.InhibitionStateManager // active inhibition tracking
+	inhibitionSources *inhibitionpkg.ExternalSources       // source alerts of other Alertmanager clusters (optional)
+	inhibitionPollers []*inhibitionpkg.ExternalSourcePoller
 
 	// Business Services
 	k8sClient                  k8s.K8sClient
@@ -86,7 +139,15 @@ type ServiceRegistry struct {
 	publishingHealth           businesspublishing.HealthMonitor
 	publishingMode             infrapublishing.ModeManager
 	publishingQueue            *infrapublishing.PublishingQueue
+	publishingJobs             infrapublishing.JobTrackingStore
 	publishingCoordinator      *infrapublishing.PublishingCoordinator
+	publishingTargetGC         *infrapublishing.TargetGC
+	publishingDiscoveryWatch   context.CancelFunc // stops watching target sources
+	publishingDiscoveryWatched chan struct{}      // closed once watching stopped
+	publishingDLQ              *infrapublishing.PostgreSQLDLQRepository // nil without a database
+	publishingDLQDrainer       *infrapublishing.DLQDrainer
+	publishingDeliveryAudit    *infrapublishing.DeliveryAuditLog
+	publishingPlugins          *infrapublishing.PluginSupervisor
 	publishingMetricsCollector *businesspublishing.PublishingMetricsCollector
 	publisherFactory           *infrapublishing.PublisherFactory
 
@@ -94,6 +155,22 @@ type ServiceRegistry struct {
 	investigationRepo  core.InvestigationRepository
 	investigationQueue *investigationinfra.InvestigationQueue
 
+	// Leak detection (goroutines, FDs, worker liveness)
+	watchdog *watchdog.Watchdog
+
+	// Forwards accepted webhooks to a peer AMP (optional)
+	webhookMirror *mirror.Mirror
+
+	// Posts silence lifecycle events to external webhooks (optional)
+	silenceWebhooks *silencewebhook.Notifier
+
+	// OpenTelemetry span exporter (nil when telemetry is disabled)
+	tracer *telemetry.Tracer
+
+	// Reconciliation with the previous run (see service_registry_startup.go)
+	startupReport      *services.StartupReport
+	runtimeCheckpoints *runtimeCheckpointer
+
 	// State
 	startTime         time.Time
 	reloadCoordinator *appconfig.ReloadCoordinator
@@ -168,6 +245,9 @@ func (r *ServiceRegistry) Initialize(ctx context.Context) error {
 		r.addDegradedReason("inhibition unavailable: %v", err)
 	}
 
+	// Step 2.75: Create leak watchdog before workers that report heartbeats
+	r.initializeWatchdog()
+
 	// Step 3: Initialize Business Services
 	if err := r.initializeBusinessServices(ctx); err != nil {
 		return fmt.Errorf("business services initialization failed: %w", err)
@@ -185,6 +265,45 @@ func (r *ServiceRegistry) Initialize(ctx context.Context) error {
 		return fmt.Errorf("alert processor initialization failed: %w", err)
 	}
 
+	// Step 5: Start watchdog now that meta-alerts can be processed
+	r.startWatchdog(ctx)
+
+	// Step 6: Start materializing recurring silences
+	r.startRecurringSilenceScheduler(ctx)
+
+	// Step 7: Start silence expiry notifications (requires the publishing queue)
+	r.startSilenceExpiryNotifier(ctx)
+
+	// Step 8: Start the on-call handoff report (requires the publishing queue)
+	r.startHandoffReportScheduler(ctx)
+
+	// Step 9: Start deleting silences and alerts past their retention
+	r.startSilenceGC(ctx)
+	r.startAlertGC(ctx)
+
+	// Step 10: Start sending storm digests (requires the alert processor)
+	r.startStormDetector(ctx)
+
+	// Step 11: Start applying silence changes of other replicas
+	r.startSilenceReplicator(ctx)
+
+	// Step 12: Start mirroring accepted webhooks to the peer AMP
+	if err := r.startWebhookMirror(ctx); err != nil {
+		return err
+	}
+
+	// Step 13: Start posting silence lifecycle events to webhooks
+	if err := r.startSilenceWebhooks(ctx); err != nil {
+		return err
+	}
+
+	// Step 14: Start maintaining the hourly and daily alert counts
+	r.startAlertRollups(ctx)
+
+	// Step 15: Restore the target groups of the previous run and report
+	// what may have been missed while AMP was down
+	r.reconcileStartup(ctx)
+
 	r.initialized = true
 	r.logger.Info("Service registry initialized successfully")
 	return nil
@@ -203,9 +322,22 @@ func (r *ServiceRegistry) initializeInfrastructure(ctx context.Context) error {
 	r.metrics = metrics.NewBusinessMetrics()
 	r.logger.Info("Business Metrics initialized")
 
+	if err := r.initializeTelemetry(); err != nil {
+		r.logger.Warn("Tracing disabled", "error", err)
+		r.addDegradedReason("tracing unavailable: %v", err)
+	}
+
 	// Initialize Memory Stores (compatibility mode)
 	r.alertStore = memory.NewAlertStore()
 	r.silenceStore = memory.NewSilenceStore()
+	r.silenceAudit = memory.NewSilenceAuditLog(0)
+	r.silenceStore.SetAuditHook(r.recordSilenceAudit)
+	r.initializeSilencePolicy()
+	r.decisionLog = memory.NewDecisionLog(0, 0)
+	r.deliveryLog = memory.NewDeliveryLog(0, 0)
+	r.recurringSilences = memory.NewRecurringSilenceStore()
+	r.snoozes = memory.NewSnoozeStore()
+	r.acknowledgements = memory.NewAcknowledgementStore()
 	r.logger.Info("Memory stores initialized (compatibility mode)")
 
 	// Initialize Database based on profile
@@ -218,12 +350,24 @@ func (r *ServiceRegistry) initializeInfrastructure(ctx context.Context) error {
 		return fmt.Errorf("storage initialization failed: %w", err)
 	}
 
+	// Back silence store with the database so silences survive restarts
+	if err := r.initializeSilencePersistence(ctx); err != nil {
+		r.logger.Warn("Silence persistence unavailable, silences are kept in memory only", "error", err)
+		r.addDegradedReason("silence persistence unavailable: %v", err)
+	}
+
 	// Initialize Cache (Redis or Memory based on profile)
 	if err := r.initializeCache(ctx); err != nil {
 		r.logger.Error("Cache initialization failed", "error", err)
 		// Continue without cache (graceful degradation)
 	}
 
+	// Share silences with other replicas (requires the Redis cache)
+	if err := r.initializeSilenceCache(ctx); err != nil {
+		r.logger.Warn("Silence cache unavailable, silences of other replicas are not picked up", "error", err)
+		r.addDegradedReason("silence cache unavailable: %v", err)
+	}
+
 	r.logger.Info("Infrastructure services initialized")
 	return nil
 }
@@ -390,6 +534,7 @@ func (r *ServiceRegistry) initializeCoreServices(ctx context.Context) error {
 		r.addDegradedReason("classification unavailable: %v", err)
 		// Continue without classification (graceful degradation)
 	}
+	r.initializeReclassification()
 
 	r.logger.Info("Core services initialized")
 	return nil
@@ -484,7 +629,10 @@ func (r *ServiceRegistry) initializeInhibition(ctx context.Context) error {
 		return fmt.Errorf("context cancelled before inhibition init: %w", err)
 	}
 
-	rules := r.config.Inhibition.ToInhibitionRules()
+	rules, err := r.config.Inhibition.ToInhibitionRules()
+	if err != nil {
+		return fmt.Errorf("invalid inhibition rules: %w", err)
+	}
 	if len(rules) == 0 {
 		r.logger.Warn("No inhibition rules configured, inhibition engine disabled")
 		return nil
@@ -494,7 +642,26 @@ func (r *ServiceRegistry) initializeInhibition(ctx context.Context) error {
 
 	alertCache := inhibitionpkg.NewTwoTierAlertCache(r.cache, r.logger)
 	stateManager := inhibitionpkg.NewDefaultStateManager(r.cache, r.logger, r.metrics)
-	matcher := inhibitionpkg.NewMatcher(alertCache, rules, r.logger)
+
+	// Alerts firing in other Alertmanager clusters inhibit as well
+	var sourceAlerts inhibitionpkg.ActiveAlertCache = alertCache
+	if sources, err := r.startInhibitionExternalSources(ctx); err != nil {
+		return err
+	} else if sources != nil {
+		sourceAlerts = inhibitionpkg.NewExternalSourceCache(alertCache, sources)
+	}
+	matcher := inhibitionpkg.NewMatcher(sourceAlerts, rules, r.logger)
+
+	// Recover source alerts and active inhibitions of the previous run
+	if restored, err := alertCache.Restore(ctx); err != nil {
+		r.logger.Warn("Failed to restore inhibition source alerts", "error", err)
+	} else if restored > 0 {
+		r.logger.Info("Restored inhibition source alerts", "alerts", restored)
+	}
+	if _, err := stateManager.Restore(ctx); err != nil {
+		r.logger.Warn("Failed to restore inhibition state, continuing with local state", "error", err)
+	}
+	stateManager.StartCleanupWorker(context.WithoutCancel(ctx))
 
 	r.inhibitionCache = alertCache
 	r.inhibitionState = stateManager
@@ -581,6 +748,66 @@ func (r *ServiceRegistry) initializeAlertProcessor(ctx context.Context) error {
 		Logger:             r.logger,
 		Metrics:            nil, // TODO: MetricsManager
 	}
+	if r.silenceStore != nil {
+		config.SilenceEngine = services.NewSilenceEngine(r.silenceStore, r.logger)
+	}
+	if r.decisionLog != nil {
+		config.DecisionLog = r.decisionLog
+	}
+	if r.config.RegionTagging.Enabled {
+		tagger, err := newRegionTagger(r.config.RegionTagging)
+		if err != nil {
+			r.logger.Warn("Region tagging disabled", "error", err)
+			r.addDegradedReason("region tagging unavailable: %v", err)
+		} else {
+			config.RegionTagger = tagger
+		}
+	}
+	if r.config.RoutingConditions.Enabled {
+		evaluator, err := newRoutingConditionEvaluator(r.config.RoutingConditions)
+		if err != nil {
+			r.logger.Warn("Routing conditions disabled", "error", err)
+			r.addDegradedReason("routing conditions unavailable: %v", err)
+		} else {
+			config.RoutingConditions = evaluator
+			r.logger.Info("Routing conditions enabled",
+				"prometheus", r.config.RoutingConditions.PrometheusURL,
+				"rules", len(r.config.RoutingConditions.Rules))
+		}
+	}
+	if r.config.CompositeAlerts.Enabled {
+		engine, err := newCompositeAlertEngine(r.config.CompositeAlerts)
+		if err != nil {
+			r.logger.Warn("Composite alerts disabled", "error", err)
+			r.addDegradedReason("composite alerts unavailable: %v", err)
+		} else {
+			config.CompositeAlerts = engine
+			config.CompositeHook = r.storeCompositeAlert
+		}
+	}
+	if r.config.StormDetection.Enabled {
+		r.stormDetector = r.newStormDetector(r.config.StormDetection)
+		config.StormDetector = r.stormDetector
+	}
+	if r.config.AlertSampling.Enabled {
+		sampler, err := newAlertSampler(r.config.AlertSampling, r.metrics)
+		if err != nil {
+			r.logger.Warn("Alert sampling disabled", "error", err)
+			r.addDegradedReason("alert sampling unavailable: %v", err)
+		} else {
+			config.Sampler = sampler
+			r.logger.Info("Alert sampling enabled", "policies", len(r.config.AlertSampling.Policies))
+		}
+	}
+	if r.config.AlertTraces.Enabled {
+		linker, err := newTraceLinker(r.config.AlertTraces)
+		if err != nil {
+			r.logger.Warn("Alert trace links disabled", "error", err)
+			r.addDegradedReason("alert trace links unavailable: %v", err)
+		} else {
+			config.TraceLinker = linker
+		}
+	}
 
 	processor, err := services.NewAlertProcessor(config)
 	if err != nil {
@@ -597,6 +824,7 @@ func (r *ServiceRegistry) initializeBusinessServices(ctx context.Context) error
 	r.logger.Info("Initializing business services...")
 
 	r.initializePublishing(ctx)
+	r.initializeMaintenance()
 
 	r.logger.Info("Business services initialized")
 	return nil
@@ -608,6 +836,19 @@ func (r *ServiceRegistry) Shutdown(ctx context.Context) error {
 
 	// Shutdown in reverse order of initialization
 
+	r.stopAlertRollups()
+	r.stopSilenceWebhooks()
+	r.stopWebhookMirror()
+	r.stopSilenceReplicator()
+	r.stopStormDetector()
+	r.stopAlertGC()
+	r.stopSilenceGC()
+	r.stopHandoffReportScheduler()
+	r.stopSilenceExpiryNotifier()
+	r.stopRecurringSilenceScheduler()
+	r.stopWatchdog()
+	r.stopReclassification()
+
 	// Shutdown Alert Processor
 	if r.alertProcessor != nil {
 		r.logger.Info("Shutting down Alert Processor...")
@@ -622,6 +863,13 @@ func (r *ServiceRegistry) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// Shutdown Inhibition state cleanup and Redis recovery worker
+	if stateManager, ok := r.inhibitionState.(*inhibitionpkg.DefaultStateManager); ok {
+		stateManager.StopCleanupWorker()
+	}
+
+	r.stopInhibitionExternalSources()
+
 	// Shutdown Inhibition cache background worker
 	if r.inhibitionCache != nil {
 		r.logger.Info("Shutting down inhibition cache...")
@@ -629,6 +877,12 @@ func (r *ServiceRegistry) Shutdown(ctx context.Context) error {
 		r.logger.Info("Inhibition cache stopped")
 	}
 
+	// Release the alerts held by target groupings before the last checkpoint
+	if r.publishingCoordinator != nil {
+		r.publishingCoordinator.Stop()
+	}
+	r.stopRuntimeCheckpoints()
+
 	r.shutdownPublishing()
 
 	// Shutdown Storage runtime before database ownership is torn down
@@ -651,6 +905,8 @@ func (r *ServiceRegistry) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	r.shutdownTelemetry(ctx)
+
 	r.initialized = false
 	r.logger.Info("All services shut down")
 	return nil
@@ -703,6 +959,36 @@ func (r *ServiceRegistry) SilenceStore() *memory.SilenceStore {
 	return r.silenceStore
 }
 
+// DecisionLog returns the per-alert pipeline decision traces.
+func (r *ServiceRegistry) DecisionLog() *memory.DecisionLog {
+	return r.decisionLog
+}
+
+// DeliveryLog returns the per-alert publish attempts.
+func (r *ServiceRegistry) DeliveryLog() *memory.DeliveryLog {
+	return r.deliveryLog
+}
+
+func (r *ServiceRegistry) RecurringSilenceStore() *memory.RecurringSilenceStore {
+	return r.recurringSilences
+}
+
+// SnoozeStore returns the per-user alert snoozes.
+func (r *ServiceRegistry) SnoozeStore() *memory.SnoozeStore {
+	return r.snoozes
+}
+
+// AcknowledgementStore returns the alerts acknowledged in incident
+// management systems.
+func (r *ServiceRegistry) AcknowledgementStore() *memory.AcknowledgementStore {
+	return r.acknowledgements
+}
+
+// Maintenance returns the maintenance mode controller.
+func (r *ServiceRegistry) Maintenance() *services.MaintenanceMode {
+	return r.maintenance
+}
+
 func (r *ServiceRegistry) StartTime() time.Time {
 	return r.startTime
 }
@@ -716,6 +1002,11 @@ func (r *ServiceRegistry) InhibitionState() inhibitionpkg.InhibitionStateManager
 	return r.inhibitionState
 }
 
+// InhibitionMatcher returns the inhibition rule engine (may be nil if not configured).
+func (r *ServiceRegistry) InhibitionMatcher() inhibitionpkg.InhibitionMatcher {
+	return r.inhibitionMatcher
+}
+
 // InvestigationRepository returns the investigation repository (may be nil if not initialized).
 func (r *ServiceRegistry) InvestigationRepository() core.InvestigationRepository {
 	return r.investigationRepo
@@ -743,11 +1034,7 @@ func (r *ServiceRegistry) ReloadConfig(ctx context.Context) error {
 	// Update local config pointer
 	r.config = r.reloadCoordinator.GetCurrentConfig()
 
-	// TODO(PARITY-A2): hot-reload inhibition rules — currently the matcher keeps the old rules
-	// after a config reload. To apply new inhibit_rules without restart, call initializeInhibition
-	// and replace r.inhibitionMatcher atomically (requires mutex on the matcher field).
-
-	return nil
+	return r.reloadInhibitionRules(ctx)
 }
 
 // Helper functions
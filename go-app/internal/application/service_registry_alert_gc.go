@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/routing"
+)
+
+// startAlertGC starts deleting alerts that expired more than
+// alert_gc.retention ago from the alert history. Firing alerts without
+// endsAt expire alert_gc.resolve_timeout after their last update, like the
+// Alertmanager global.resolve_timeout.
+func (r *ServiceRegistry) startAlertGC(ctx context.Context) {
+	cfg := r.config.AlertGC
+	if !cfg.Enabled || r.alertStore == nil {
+		r.logger.Info("Alert GC disabled")
+		return
+	}
+
+	resolveTimeout := routing.Duration(cfg.ResolveTimeout)
+	global := &routing.GlobalConfig{ResolveTimeout: &resolveTimeout}
+	r.alertGC = services.NewAlertGC(r.alertStore, services.AlertGCConfig{
+		Interval:       cfg.Interval,
+		Retention:      cfg.Retention,
+		ResolveTimeout: global.ResolveTimeoutOrDefault(),
+		BatchSize:      cfg.BatchSize,
+		Logger:         r.logger,
+	})
+	r.alertGC.Start(context.WithoutCancel(ctx))
+}
+
+func (r *ServiceRegistry) stopAlertGC() {
+	if r.alertGC == nil {
+		return
+	}
+	r.logger.Info("Shutting down alert GC...")
+	r.alertGC.Stop()
+	r.alertGC = nil
+}
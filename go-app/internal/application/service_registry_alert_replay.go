@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/alertmanager"
+	"github.com/ipiton/AMP/internal/infrastructure/promql"
+)
+
+// replayAlerts replays the firing alerts of the configured sources into the
+// alert store and the inhibition cache when AMP was down for at least
+// min_downtime (or the downtime is unknown). Returns nil when no replay ran.
+func (r *ServiceRegistry) replayAlerts(ctx context.Context, lastSeenAt time.Time) *services.AlertReplayResult {
+	cfg := r.config.AlertReplay
+	if !cfg.Enabled {
+		return nil
+	}
+	if !lastSeenAt.IsZero() && r.startTime.Sub(lastSeenAt) < cfg.MinDowntime {
+		r.logger.Info("Alert replay skipped: downtime below min_downtime",
+			"downtime", r.startTime.Sub(lastSeenAt).Round(time.Second), "min_downtime", cfg.MinDowntime)
+		return nil
+	}
+
+	sources, err := newAlertReplaySources(cfg)
+	if err != nil {
+		r.logger.Warn("Alert replay disabled", "error", err)
+		return nil
+	}
+	replayer := services.NewAlertReplayer(r.alertStore, r.inhibitionCache, services.AlertReplayConfig{
+		Sources:        sources,
+		ResolveTimeout: cfg.ResolveTimeout,
+		Logger:         r.logger,
+	})
+	result := replayer.Replay(ctx)
+	return &result
+}
+
+func newAlertReplaySources(cfg appconfig.AlertReplayConfig) ([]services.AlertReplaySource, error) {
+	sources := make([]services.AlertReplaySource, 0, len(cfg.Sources))
+	for _, src := range cfg.Sources {
+		source := services.AlertReplaySource{Name: src.Name}
+		if src.PrometheusURL != "" {
+			client, err := promql.NewClient(promql.Config{URL: src.PrometheusURL, Timeout: cfg.Timeout})
+			if err != nil {
+				return nil, fmt.Errorf("source %q: %w", src.Name, err)
+			}
+			source.Prometheus = client
+		} else {
+			client, err := alertmanager.NewClient(alertmanager.Config{URL: src.AlertmanagerURL, Timeout: cfg.Timeout})
+			if err != nil {
+				return nil, fmt.Errorf("source %q: %w", src.Name, err)
+			}
+			source.Alertmanager = client
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
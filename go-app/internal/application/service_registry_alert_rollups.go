@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure"
+	"github.com/ipiton/AMP/internal/infrastructure/repository"
+)
+
+// startAlertRollups starts maintaining the hourly and daily alert counts
+// in the alert_rollups table of the profile's database.
+func (r *ServiceRegistry) startAlertRollups(ctx context.Context) {
+	cfg := r.config.AlertRollups
+	if !cfg.Enabled || r.storage == nil {
+		r.logger.Info("Alert rollups disabled")
+		return
+	}
+	store := r.newAlertRollupRepository()
+	if store == nil {
+		r.logger.Warn("Alert rollups disabled: database is not initialized")
+		return
+	}
+
+	r.alertRollups = services.NewAlertRollupJob(r.storage, store, services.AlertRollupConfig{
+		Interval:        cfg.Interval,
+		Lateness:        cfg.Lateness,
+		HourlyRetention: cfg.HourlyRetention,
+		DailyRetention:  cfg.DailyRetention,
+		TeamLabel:       cfg.TeamLabel,
+		Metrics:         r.metrics,
+		Logger:          r.logger,
+	})
+	r.alertRollups.Start(context.WithoutCancel(ctx))
+}
+
+// newAlertRollupRepository returns the alert_rollups table of the profile's
+// database (nil without one).
+func (r *ServiceRegistry) newAlertRollupRepository() core.AlertRollupStore {
+	if r.config.Profile == appconfig.ProfileLite {
+		sqliteDB, ok := r.storageRuntime.(*infrastructure.SQLiteDatabase)
+		if !ok || sqliteDB.DB() == nil {
+			return nil
+		}
+		return repository.NewSQLiteAlertRollupRepository(sqliteDB.DB())
+	}
+	if r.database == nil || r.database.Pool() == nil {
+		return nil
+	}
+	return repository.NewPostgresAlertRollupRepository(r.database.Pool())
+}
+
+func (r *ServiceRegistry) stopAlertRollups() {
+	if r.alertRollups == nil {
+		return
+	}
+	r.logger.Info("Shutting down alert rollups...")
+	r.alertRollups.Stop()
+	r.alertRollups = nil
+}
+
+// AlertRollups returns the alert rollup job (nil when disabled).
+func (r *ServiceRegistry) AlertRollups() *services.AlertRollupJob {
+	return r.alertRollups
+}
@@ -0,0 +1,29 @@
+package application
+
+import (
+	"fmt"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/pkg/metrics"
+)
+
+// newAlertSampler builds the alert sampler from the configured policies.
+func newAlertSampler(cfg appconfig.AlertSamplingConfig, businessMetrics *metrics.BusinessMetrics) (*services.AlertSampler, error) {
+	policies := make([]services.SamplingPolicy, 0, len(cfg.Policies))
+	for _, pc := range cfg.Policies {
+		rates := make(map[services.SamplingStage]float64, 2)
+		if pc.ClassificationRate != nil {
+			rates[services.SamplingStageClassification] = *pc.ClassificationRate
+		}
+		if pc.EnrichmentRate != nil {
+			rates[services.SamplingStageEnrichment] = *pc.EnrichmentRate
+		}
+		policy, err := services.ParseSamplingPolicy(pc.Name, pc.AlertName, pc.Severity, rates)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", pc.Name, err)
+		}
+		policies = append(policies, policy)
+	}
+	return services.NewAlertSampler(policies, businessMetrics), nil
+}
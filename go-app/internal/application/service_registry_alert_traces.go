@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/pkg/telemetry"
+)
+
+// newTraceLinker builds the trace linker from config.
+func newTraceLinker(cfg appconfig.AlertTracesConfig) (*services.TraceLinker, error) {
+	linkerConfig := services.TraceLinkerConfig{
+		TraceparentKeys: cfg.TraceparentKeys,
+		TraceIDKeys:     cfg.TraceIDKeys,
+		SpanIDKeys:      cfg.SpanIDKeys,
+	}
+	if cfg.URLTemplate != "" {
+		tmpl, err := services.ParseTraceURLTemplate(cfg.URLTemplate)
+		if err != nil {
+			return nil, err
+		}
+		linkerConfig.URLTemplate = tmpl
+	}
+	return services.NewTraceLinker(linkerConfig), nil
+}
+
+// initializeTelemetry installs the OTLP trace exporter so alert processing
+// spans, and their links to the alerts' traces, reach the tracing backend.
+func (r *ServiceRegistry) initializeTelemetry() error {
+	if !r.config.Telemetry.Enabled {
+		return nil
+	}
+	tracer, err := telemetry.NewTracer(&telemetry.TracerConfig{
+		ServiceName:    r.config.App.Name,
+		ServiceVersion: r.config.App.Version,
+		Environment:    r.config.App.Environment,
+		Enabled:        true,
+		Endpoint:       r.config.Telemetry.Endpoint,
+		SamplingRatio:  r.config.Telemetry.SamplingRatio,
+		Logger:         r.logger,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tracer: %w", err)
+	}
+	r.tracer = tracer
+	return nil
+}
+
+// shutdownTelemetry flushes pending spans.
+func (r *ServiceRegistry) shutdownTelemetry(ctx context.Context) {
+	if r.tracer == nil {
+		return
+	}
+	if err := r.tracer.Shutdown(ctx); err != nil {
+		r.logger.Warn("Tracer shutdown warning", "error", err)
+	}
+	r.tracer = nil
+}
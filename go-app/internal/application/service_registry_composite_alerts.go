@@ -0,0 +1,55 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ipiton/AMP/internal/application/handlers"
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// newCompositeAlertEngine builds the alert-of-alerts engine from config. Each
+// rule's selector is parsed like an API token selector.
+func newCompositeAlertEngine(cfg appconfig.CompositeAlertsConfig) (*services.CompositeAlertEngine, error) {
+	rules := make([]services.CompositeRule, 0, len(cfg.Rules))
+	for _, rc := range cfg.Rules {
+		selector, err := handlers.ParseLabelMatchers(rc.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid selector: %w", rc.Name, err)
+		}
+		scope := &handlers.TokenScope{Name: rc.Name, Selector: selector}
+		rules = append(rules, services.CompositeRule{
+			Name:         rc.Name,
+			MatchesAlert: scope.AllowsLabels,
+			GroupBy:      rc.GroupBy,
+			Threshold:    rc.Threshold,
+			Window:       rc.Window,
+			Labels:       rc.Labels,
+			Annotations:  rc.Annotations,
+		})
+	}
+	return services.NewCompositeAlertEngine(rules, services.CompositeAlertEngineConfig{})
+}
+
+// storeCompositeAlert records a composite alert in the alert store so it is
+// listed by the API like the alerts it covers.
+func (r *ServiceRegistry) storeCompositeAlert(alert *core.Alert) {
+	in := core.AlertIngestInput{
+		Labels:      alert.Labels,
+		Annotations: alert.Annotations,
+		StartsAt:    alert.StartsAt.UTC().Format(time.RFC3339),
+		Fingerprint: alert.Fingerprint,
+		Status:      string(alert.Status),
+	}
+	if alert.EndsAt != nil {
+		in.EndsAt = alert.EndsAt.UTC().Format(time.RFC3339)
+	}
+	if err := r.alertStore.IngestBatch([]core.AlertIngestInput{in}, time.Now().UTC()); err != nil {
+		r.logger.Warn("Failed to store composite alert",
+			"alert", alert.AlertName,
+			"fingerprint", alert.Fingerprint,
+			"error", err)
+	}
+}
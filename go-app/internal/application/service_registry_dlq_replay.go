@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+
+	businesspublishing "github.com/ipiton/AMP/internal/business/publishing"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// startDLQDrainer replays the dead-lettered jobs of publishing targets once
+// they recovered.
+func (r *ServiceRegistry) startDLQDrainer(ctx context.Context, discovery infrapublishing.TargetDiscoveryManager, metrics *v2.PublishingMetrics) {
+	cfg := r.config.Publishing.DLQReplay
+	if !cfg.Enabled {
+		return
+	}
+	if r.publishingDLQ == nil {
+		r.logger.Warn("DLQ replay requires a PostgreSQL database, disabled")
+		return
+	}
+
+	config := infrapublishing.DLQDrainerConfig{
+		Interval:   cfg.CheckInterval,
+		HealthyFor: cfg.HealthyFor,
+		RateLimit:  cfg.RateLimit,
+		BatchSize:  cfg.BatchSize,
+		MaxBackoff: cfg.MaxBackoff,
+		Metrics:    metrics,
+		Logger:     r.logger,
+	}
+	if r.publishingHealth != nil {
+		config.Health = publishingHealthAdapter{monitor: r.publishingHealth}
+	}
+
+	r.publishingDLQDrainer = infrapublishing.NewDLQDrainer(r.publishingDLQ, r.publishingQueue, discovery, config)
+	r.publishingTargetGC.AddForgetter(r.publishingDLQDrainer)
+	r.publishingDLQDrainer.Start(ctx)
+	r.logger.Info("DLQ replay started",
+		"check_interval", cfg.CheckInterval,
+		"healthy_for", cfg.HealthyFor,
+		"rate_limit", cfg.RateLimit,
+		"health_checks", r.publishingHealth != nil,
+	)
+}
+
+// stopDLQDrainer stops replaying before the queue stops.
+func (r *ServiceRegistry) stopDLQDrainer() {
+	if r.publishingDLQDrainer == nil {
+		return
+	}
+	r.logger.Info("Shutting down DLQ replay...")
+	r.publishingDLQDrainer.Stop()
+	r.publishingDLQDrainer = nil
+}
+
+// publishingHealthAdapter exposes the target health checks to the DLQ
+// drainer.
+type publishingHealthAdapter struct {
+	monitor businesspublishing.HealthMonitor
+}
+
+func (a publishingHealthAdapter) GetHealthByName(ctx context.Context, targetName string) (infrapublishing.TargetHealth, error) {
+	status, err := a.monitor.GetHealthByName(ctx, targetName)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,41 @@
+package application
+
+import (
+	"fmt"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// durablePublishingQueueConfig makes the publishing queue durable when
+// publishing.queue.durable.backend is set.
+func (r *ServiceRegistry) durablePublishingQueueConfig() (infrapublishing.DurableQueueConfig, error) {
+	cfg := r.config.Publishing.Queue.Durable
+	if cfg.Backend == "" {
+		return infrapublishing.DurableQueueConfig{}, nil
+	}
+
+	var store infrapublishing.DurableQueueStore
+	switch cfg.Backend {
+	case appconfig.PublishingQueueBackendPostgres:
+		if r.database == nil || r.database.Pool() == nil {
+			return infrapublishing.DurableQueueConfig{}, fmt.Errorf("durable publishing queue requires a PostgreSQL connection")
+		}
+		store = infrapublishing.NewPostgreSQLDurableQueueStore(r.database.Pool(), r.logger)
+	case appconfig.PublishingQueueBackendRedis:
+		redisStore, err := infrapublishing.NewRedisDurableQueueStore(r.cache, cfg.KeyPrefix, r.logger)
+		if err != nil {
+			return infrapublishing.DurableQueueConfig{}, err
+		}
+		store = redisStore
+	default:
+		return infrapublishing.DurableQueueConfig{}, fmt.Errorf("unknown durable publishing queue backend %q", cfg.Backend)
+	}
+
+	r.logger.Info("Durable publishing queue enabled", "backend", cfg.Backend, "visibility_timeout", cfg.VisibilityTimeout)
+	return infrapublishing.DurableQueueConfig{
+		Store:             store,
+		Owner:             cfg.Owner,
+		VisibilityTimeout: cfg.VisibilityTimeout,
+	}, nil
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ipiton/AMP/internal/application/handlers"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/core/silencing"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// startHandoffReportScheduler starts publishing the weekly on-call handoff
+// report (handoff_report). It needs the publishing queue; configuration
+// errors degrade the service instead of failing startup.
+func (r *ServiceRegistry) startHandoffReportScheduler(ctx context.Context) {
+	cfg := r.config.HandoffReport
+	if !cfg.Enabled {
+		return
+	}
+	if r.publishingQueue == nil || r.publishingDiscoveryAdapter == nil {
+		r.logger.Warn("Handoff report disabled: publishing queue unavailable")
+		r.addDegradedReason("handoff report unavailable: publishing queue unavailable")
+		return
+	}
+
+	scheduler, err := r.newHandoffReportScheduler()
+	if err != nil {
+		r.logger.Warn("Handoff report disabled", "error", err)
+		r.addDegradedReason("handoff report unavailable: %v", err)
+		return
+	}
+	r.handoffReportScheduler = scheduler
+	r.handoffReportScheduler.Start(context.WithoutCancel(ctx))
+}
+
+func (r *ServiceRegistry) newHandoffReportScheduler() (*services.HandoffReportScheduler, error) {
+	cfg := r.config.HandoffReport
+
+	loc := time.UTC
+	if tz := strings.TrimSpace(cfg.Timezone); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+	}
+	schedule, err := silencing.ParseCronSchedule(cfg.Schedule, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	defaultTemplate, err := loadHandoffTemplate(cfg.TemplateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]services.HandoffTeam, 0, len(cfg.Teams))
+	for _, tc := range cfg.Teams {
+		selector, err := handlers.ParseLabelMatchers(tc.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("team %q: invalid selector: %w", tc.Name, err)
+		}
+		tmpl, err := loadHandoffTemplate(tc.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("team %q: %w", tc.Name, err)
+		}
+
+		scope := &handlers.TokenScope{Name: tc.Name, Selector: selector}
+		teams = append(teams, services.HandoffTeam{
+			Name:           tc.Name,
+			Target:         tc.Target,
+			Template:       tmpl,
+			MatchesAlert:   scope.AllowsLabels,
+			MatchesSilence: scope.AllowsSilence,
+		})
+	}
+
+	var deliveries services.FailedDeliverySource
+	if r.publishingJobs != nil {
+		deliveries = &trackedFailedDeliveries{jobs: r.publishingJobs, alerts: r.alertStore}
+	}
+
+	builder := services.NewHandoffReportBuilder(r.alertStore, r.silenceStore, deliveries, cfg.TopRules, cfg.SilenceHorizon)
+	publisher := &queueTargetPublisher{
+		discovery: r.publishingDiscoveryAdapter,
+		queue:     r.publishingQueue,
+	}
+	return services.NewHandoffReportScheduler(builder, publisher, teams, services.HandoffReportSchedulerConfig{
+		Schedule: schedule,
+		Period:   cfg.Period,
+		Template: defaultTemplate,
+		Logger:   r.logger,
+	})
+}
+
+// loadHandoffTemplate parses the template file at path (nil when path is empty).
+func loadHandoffTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := services.ParseHandoffTemplate(path, string(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+func (r *ServiceRegistry) stopHandoffReportScheduler() {
+	if r.handoffReportScheduler == nil {
+		return
+	}
+	r.logger.Info("Shutting down handoff report scheduler...")
+	r.handoffReportScheduler.Stop()
+	r.handoffReportScheduler = nil
+}
+
+// HandoffReportScheduler returns the handoff report scheduler (nil when disabled).
+func (r *ServiceRegistry) HandoffReportScheduler() *services.HandoffReportScheduler {
+	return r.handoffReportScheduler
+}
+
+// trackedFailedDeliveries counts failed publishing jobs from the job
+// tracking store. Only the most recent jobs are tracked, so counts for long
+// periods are a lower bound.
+type trackedFailedDeliveries struct {
+	jobs   infrapublishing.JobTrackingStore
+	alerts *memory.AlertStore
+}
+
+func (d *trackedFailedDeliveries) FailedDeliveriesSince(since time.Time, match func(labels map[string]string) bool) map[string]int {
+	// Jobs only carry the alert fingerprint; team selectors need the labels.
+	var labels map[string]map[string]string
+	if match != nil && d.alerts != nil {
+		all := d.alerts.List("", true)
+		labels = make(map[string]map[string]string, len(all))
+		for _, alert := range all {
+			labels[alert.Fingerprint] = alert.Labels
+		}
+	}
+
+	out := make(map[string]int)
+	for _, state := range []string{"failed", "dlq"} {
+		for _, job := range d.jobs.List(infrapublishing.JobFilters{State: state, Limit: d.jobs.Size()}) {
+			if job.CompletedAt == nil || *job.CompletedAt < since.Unix() {
+				continue
+			}
+			if match != nil {
+				l, ok := labels[job.Fingerprint]
+				if !ok || !match(l) {
+					continue
+				}
+			}
+			out[job.TargetName]++
+		}
+	}
+	return out
+}
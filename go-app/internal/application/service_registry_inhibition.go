@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	inhibitionpkg "github.com/ipiton/AMP/internal/infrastructure/inhibition"
+)
+
+// reloadInhibitionRules applies the inhibit rules of the current config to
+// the running matcher. The alert cache and the inhibition state are kept:
+// inhibitions of unchanged rules stay in place, so a reload does not
+// release a burst of previously inhibited notifications.
+func (r *ServiceRegistry) reloadInhibitionRules(ctx context.Context) error {
+	rules, err := r.config.Inhibition.ToInhibitionRules()
+	if err != nil {
+		return fmt.Errorf("invalid inhibition rules: %w", err)
+	}
+
+	matcher, ok := r.inhibitionMatcher.(*inhibitionpkg.DefaultInhibitionMatcher)
+	if !ok {
+		if len(rules) > 0 {
+			r.logger.Warn("Inhibition was disabled at startup, restart to apply the configured rules",
+				"rules", len(rules))
+		}
+		return nil
+	}
+
+	diff, err := matcher.ReloadRules(rules)
+	if err != nil {
+		return fmt.Errorf("invalid inhibition rules: %w", err)
+	}
+	if !diff.HasChanges() {
+		return nil
+	}
+
+	removed, err := matcher.ReconcileState(ctx, r.inhibitionState, diff)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile inhibition state: %w", err)
+	}
+	r.logger.Info("Inhibition rules hot-reloaded",
+		"rules", len(rules),
+		"added", len(diff.Added),
+		"removed", len(diff.Removed),
+		"changed", len(diff.Changed),
+		"unchanged", len(diff.Unchanged),
+		"inhibitions_released", removed)
+	return nil
+}
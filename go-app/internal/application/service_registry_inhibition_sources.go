@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipiton/AMP/internal/infrastructure/alertmanager"
+	inhibitionpkg "github.com/ipiton/AMP/internal/infrastructure/inhibition"
+)
+
+// startInhibitionExternalSources registers the configured external
+// Alertmanager clusters as inhibition sources and starts polling those with
+// a URL. Returns nil when none are configured.
+func (r *ServiceRegistry) startInhibitionExternalSources(ctx context.Context) (*inhibitionpkg.ExternalSources, error) {
+	configs := r.config.Inhibition.ExternalSources
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	sources := inhibitionpkg.NewExternalSources()
+	pollers := make([]*inhibitionpkg.ExternalSourcePoller, 0, len(configs))
+	for _, cfg := range configs {
+		sources.AddSource(cfg.Name, cfg.StaleAfter)
+		if cfg.URL == "" {
+			continue
+		}
+		client, err := alertmanager.NewClient(alertmanager.Config{URL: cfg.URL, Timeout: cfg.Timeout})
+		if err != nil {
+			return nil, fmt.Errorf("inhibition source %q: %w", cfg.Name, err)
+		}
+		pollers = append(pollers, inhibitionpkg.NewExternalSourcePoller(cfg.Name, client, sources, cfg.PollInterval, r.logger))
+	}
+
+	for _, poller := range pollers {
+		poller.Start(context.WithoutCancel(ctx))
+	}
+	r.inhibitionSources = sources
+	r.inhibitionPollers = pollers
+	r.logger.Info("External inhibition sources configured", "sources", len(configs), "polled", len(pollers))
+	return sources, nil
+}
+
+func (r *ServiceRegistry) stopInhibitionExternalSources() {
+	for _, poller := range r.inhibitionPollers {
+		poller.Stop()
+	}
+	r.inhibitionPollers = nil
+}
+
+// InhibitionSources returns the external inhibition sources (nil if none
+// are configured).
+func (r *ServiceRegistry) InhibitionSources() *inhibitionpkg.ExternalSources {
+	return r.inhibitionSources
+}
@@ -0,0 +1,15 @@
+package application
+
+import (
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// initializeMaintenance creates maintenance mode. Without a publishing queue
+// (publishing disabled or metrics-only) it only pauses ingestion.
+func (r *ServiceRegistry) initializeMaintenance() {
+	var hold services.PublishingHold
+	if r.publishingQueue != nil {
+		hold = r.publishingQueue
+	}
+	r.maintenance = services.NewMaintenanceMode(hold, r.logger)
+}
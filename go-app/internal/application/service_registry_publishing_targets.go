@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/ipiton/AMP/internal/application/handlers"
+	businesspublishing "github.com/ipiton/AMP/internal/business/publishing"
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
+)
+
+// publishingTargets serves the publishing targets API from the target
+// discovery, which persists the targets written through the API, and the
+// publisher factory of the queue, which sends the test alerts.
+type publishingTargets struct {
+	businesspublishing.TargetDiscoveryManager
+	businesspublishing.TargetStore
+
+	factory *infrapublishing.PublisherFactory
+}
+
+// TestTarget implements handlers.PublishingTargets.
+func (t *publishingTargets) TestTarget(ctx context.Context, target *core.PublishingTarget, alertName string) infrapublishing.TestAlertResult {
+	return infrapublishing.SendTestAlert(ctx, t.factory, target, infrapublishing.NewTestAlert(alertName))
+}
+
+// PublishingTargets returns the publishing targets API (nil unless the
+// publishing runtime is running).
+func (r *ServiceRegistry) PublishingTargets() handlers.PublishingTargets {
+	store, ok := r.publishingDiscovery.(businesspublishing.TargetStore)
+	if !ok || r.publisherFactory == nil {
+		return nil
+	}
+	return &publishingTargets{
+		TargetDiscoveryManager: r.publishingDiscovery,
+		TargetStore:            store,
+		factory:                r.publisherFactory,
+	}
+}
+
+// PublishingRouter returns the publishing coordinator, which routes alerts
+// to their targets (nil unless the publishing runtime is running).
+func (r *ServiceRegistry) PublishingRouter() handlers.PublishingRouter {
+	if r.publishingCoordinator == nil {
+		return nil
+	}
+	return r.publishingCoordinator
+}
+
+// publishingCircuitBreakers serves the circuit breakers API from the queue,
+// which owns the breakers, and the target discovery.
+type publishingCircuitBreakers struct {
+	businesspublishing.TargetDiscoveryManager
+	*infrapublishing.PublishingQueue
+}
+
+// PublishingCircuitBreakers returns the circuit breakers API (nil unless the
+// publishing runtime is running).
+func (r *ServiceRegistry) PublishingCircuitBreakers() handlers.PublishingCircuitBreakers {
+	if r.publishingQueue == nil || r.publishingDiscovery == nil {
+		return nil
+	}
+	return &publishingCircuitBreakers{
+		TargetDiscoveryManager: r.publishingDiscovery,
+		PublishingQueue:        r.publishingQueue,
+	}
+}
+
+// PublishingDeliveries returns the persisted audit log of publish attempts
+// when publishing runs with a database, else the in-memory delivery log.
+func (r *ServiceRegistry) PublishingDeliveries() (handlers.PublishingDeliveries, bool) {
+	if r.publishingDeliveryAudit != nil {
+		return r.publishingDeliveryAudit, true
+	}
+	if r.deliveryLog != nil {
+		return r.deliveryLog, false
+	}
+	return nil, false
+}
+
+// publishingDrain drains the queue once the coordinator released the
+// alerts held by target groupings, which the draining queue would reject
+// on shutdown.
+type publishingDrain struct {
+	*infrapublishing.PublishingQueue
+	coordinator *infrapublishing.PublishingCoordinator
+}
+
+func (d *publishingDrain) Drain(ctx context.Context, timeout time.Duration) (infrapublishing.DrainStatus, error) {
+	if d.coordinator != nil && !d.IsPaused() {
+		d.coordinator.ReleaseTargetGroups()
+	}
+	return d.PublishingQueue.Drain(ctx, timeout)
+}
+
+// PublishingDrain returns the drain API of the publishing queue (nil unless
+// the publishing runtime is running).
+func (r *ServiceRegistry) PublishingDrain() handlers.PublishingDrain {
+	if r.publishingQueue == nil {
+		return nil
+	}
+	return &publishingDrain{PublishingQueue: r.publishingQueue, coordinator: r.publishingCoordinator}
+}
@@ -0,0 +1,29 @@
+package application
+
+import (
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// initializeReclassification creates the re-classification job for
+// historical alerts. It needs the classification service (LLM enabled) and
+// storage.
+func (r *ServiceRegistry) initializeReclassification() {
+	if r.classificationSvc == nil || r.storage == nil {
+		return
+	}
+	r.reclassification = services.NewReclassificationJob(r.classificationSvc, r.storage, r.logger)
+}
+
+func (r *ServiceRegistry) stopReclassification() {
+	if r.reclassification == nil {
+		return
+	}
+	r.logger.Info("Shutting down re-classification job...")
+	r.reclassification.Stop()
+}
+
+// Reclassification returns the re-classification job (nil when
+// classification is disabled).
+func (r *ServiceRegistry) Reclassification() *services.ReclassificationJob {
+	return r.reclassification
+}
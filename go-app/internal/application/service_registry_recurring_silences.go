@@ -0,0 +1,32 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// startRecurringSilenceScheduler starts materializing recurring silences into
+// the silence store (recurring_silences).
+func (r *ServiceRegistry) startRecurringSilenceScheduler(ctx context.Context) {
+	if r.recurringSilences == nil || r.silenceStore == nil {
+		return
+	}
+
+	cfg := r.config.RecurringSilences
+	r.recurringSilenceScheduler = services.NewRecurringSilenceScheduler(r.recurringSilences, r.silenceStore, services.RecurringSilenceSchedulerConfig{
+		Interval:  cfg.Interval,
+		Lookahead: cfg.Lookahead,
+		Logger:    r.logger,
+	})
+	r.recurringSilenceScheduler.Start(context.WithoutCancel(ctx))
+}
+
+func (r *ServiceRegistry) stopRecurringSilenceScheduler() {
+	if r.recurringSilenceScheduler == nil {
+		return
+	}
+	r.logger.Info("Shutting down recurring silence scheduler...")
+	r.recurringSilenceScheduler.Stop()
+	r.recurringSilenceScheduler = nil
+}
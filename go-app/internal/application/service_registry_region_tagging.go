@@ -0,0 +1,49 @@
+package application
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// regionMappingFile is the format of region_tagging.mapping_file.
+type regionMappingFile struct {
+	Rules []appconfig.RegionRuleConfig `yaml:"rules"`
+}
+
+// newRegionTagger builds the region tagger from inline rules followed by the
+// rules of the optional mapping file.
+func newRegionTagger(cfg appconfig.RegionTaggingConfig) (*services.RegionTagger, error) {
+	ruleConfigs := append([]appconfig.RegionRuleConfig(nil), cfg.Rules...)
+	if cfg.MappingFile != "" {
+		data, err := os.ReadFile(cfg.MappingFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mapping file: %w", err)
+		}
+		var file regionMappingFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("invalid mapping file %s: %w", cfg.MappingFile, err)
+		}
+		ruleConfigs = append(ruleConfigs, file.Rules...)
+	}
+
+	rules := make([]services.RegionRule, 0, len(ruleConfigs))
+	for i, rc := range ruleConfigs {
+		rule, err := services.ParseRegionRule(rc.CIDR, rc.HostPattern, rc.Region, rc.Zone)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return services.NewRegionTagger(services.RegionTaggerConfig{
+		RegionLabel:  cfg.RegionLabel,
+		ZoneLabel:    cfg.ZoneLabel,
+		SourceLabels: cfg.SourceLabels,
+		Rules:        rules,
+	}), nil
+}
@@ -0,0 +1,43 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/ipiton/AMP/internal/application/handlers"
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/promql"
+)
+
+// newRoutingConditionEvaluator builds the PromQL condition evaluator from
+// config. Each rule's selector is parsed like an API token selector.
+func newRoutingConditionEvaluator(cfg appconfig.RoutingConditionsConfig) (*services.RoutingConditionEvaluator, error) {
+	client, err := promql.NewClient(promql.Config{URL: cfg.PrometheusURL, Timeout: cfg.Timeout})
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]services.RoutingCondition, 0, len(cfg.Rules))
+	for _, rc := range cfg.Rules {
+		selector, err := handlers.ParseLabelMatchers(rc.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid selector: %w", rc.Name, err)
+		}
+		query, err := services.ParseRoutingConditionQuery(rc.Name, rc.Query)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid query: %w", rc.Name, err)
+		}
+
+		scope := &handlers.TokenScope{Name: rc.Name, Selector: selector}
+		conditions = append(conditions, services.RoutingCondition{
+			Name:         rc.Name,
+			MatchesAlert: scope.AllowsLabels,
+			Query:        query,
+		})
+	}
+
+	return services.NewRoutingConditionEvaluator(client, conditions, services.RoutingConditionEvaluatorConfig{
+		CacheTTL: cfg.CacheTTL,
+		FailOpen: cfg.FailOpen,
+	})
+}
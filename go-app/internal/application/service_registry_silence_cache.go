@@ -0,0 +1,221 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ipiton/AMP/internal/core"
+	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// initializeSilenceCache shares silences with the other replicas through
+// Redis. The first replica seeds the cache with the silences restored from
+// the database; later replicas adopt the cached silences.
+func (r *ServiceRegistry) initializeSilenceCache(ctx context.Context) error {
+	cfg := r.config.SilenceCache
+	if !cfg.Enabled {
+		return nil
+	}
+
+	cache, err := infrasilencing.NewRedisSilenceCache(r.cache, cfg.KeyPrefix, uuid.NewString(), r.logger)
+	if err != nil {
+		return err
+	}
+
+	replicator := newSilenceReplicator(r.silenceStore, cache, r.silencePersistence, cfg.ResyncInterval, r.logger)
+	if err := replicator.bootstrap(ctx); err != nil {
+		return err
+	}
+
+	r.silenceReplicator = replicator
+	r.silenceStore.SetOnChange(r.onSilenceChange)
+	return nil
+}
+
+// onSilenceChange writes a silence change through to the database and then
+// announces it to the other replicas. It is the silence store's change hook
+// when the silence cache is enabled.
+func (r *ServiceRegistry) onSilenceChange() {
+	if r.silencePersistence != nil {
+		r.silencePersistence.sync()
+	}
+	if r.silenceReplicator != nil {
+		r.silenceReplicator.publish()
+	}
+}
+
+// startSilenceReplicator starts applying silence changes of other replicas.
+func (r *ServiceRegistry) startSilenceReplicator(ctx context.Context) {
+	if r.silenceReplicator == nil {
+		r.logger.Info("Silence cache disabled")
+		return
+	}
+	if err := r.silenceReplicator.start(context.WithoutCancel(ctx)); err != nil {
+		r.logger.Warn("Silence changes of other replicas are only picked up on resync", "error", err)
+		r.addDegradedReason("silence cache subscription unavailable: %v", err)
+	}
+}
+
+func (r *ServiceRegistry) stopSilenceReplicator() {
+	if r.silenceReplicator == nil {
+		return
+	}
+	r.logger.Info("Shutting down silence replicator...")
+	r.silenceReplicator.stop()
+}
+
+// silenceReplicator keeps the memory silence store in line with the silences
+// of the other replicas, read from the Redis silence cache.
+type silenceReplicator struct {
+	store          *memory.SilenceStore
+	cache          *infrasilencing.RedisSilenceCache
+	persistence    *silencePersistence // nil when silences are not persisted
+	resyncInterval time.Duration
+	logger         *slog.Logger
+
+	// mu serializes sharing local changes and applying the cache.
+	mu sync.Mutex
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newSilenceReplicator(store *memory.SilenceStore, cache *infrasilencing.RedisSilenceCache, persistence *silencePersistence, resyncInterval time.Duration, logger *slog.Logger) *silenceReplicator {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &silenceReplicator{
+		store:          store,
+		cache:          cache,
+		persistence:    persistence,
+		resyncInterval: resyncInterval,
+		logger:         logger,
+	}
+}
+
+// bootstrap seeds an empty cache with the local silences, or replaces the
+// local silences with the cached ones.
+func (s *silenceReplicator) bootstrap(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, err := s.cache.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if len(cached) == 0 {
+		if err := s.cache.Seed(ctx, s.store.ExportForPersistence(time.Now().UTC())); err != nil {
+			return err
+		}
+		s.logger.Info("Silence cache seeded")
+		return nil
+	}
+	return s.replace(cached)
+}
+
+// publish writes local silence changes to the cache.
+func (s *silenceReplicator) publish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), silencePersistenceTimeout)
+	defer cancel()
+
+	if _, err := s.cache.Sync(ctx, s.store.ExportForPersistence(time.Now().UTC())); err != nil {
+		s.logger.Warn("Failed to share silence change with other replicas", "error", err)
+	}
+}
+
+// apply writes local changes that could not be shared yet, then loads the
+// cached silences into the store. If the store changes while loading, the
+// cache is applied on the next notification instead: replacing the store
+// now would drop the change before its change hook shares it.
+func (s *silenceReplicator) apply(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	local := s.store.ExportForPersistence(time.Now().UTC())
+	if _, err := s.cache.Sync(ctx, local); err != nil {
+		return err
+	}
+	cached, err := s.cache.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if !sameSilences(local, s.store.ExportForPersistence(time.Now().UTC())) {
+		return nil
+	}
+	return s.replace(cached)
+}
+
+func (s *silenceReplicator) replace(cached []core.APISilence) error {
+	if err := s.store.ReplaceFromPersistence(cached, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to apply cached silences: %w", err)
+	}
+	s.cache.Adopt(cached)
+	// The other replicas already wrote these silences to the database.
+	if s.persistence != nil {
+		s.persistence.adopt(cached)
+	}
+	return nil
+}
+
+func sameSilences(a, b []core.APISilence) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if silenceSnapshot(a[i]) != silenceSnapshot(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// start subscribes to change notifications and applies the cache after each
+// of them and every resync interval. The periodic resync keeps running when
+// subscribing fails.
+func (s *silenceReplicator) start(ctx context.Context) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+	changes, err := s.cache.Watch(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.resyncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-changes:
+				if !ok {
+					changes = nil
+					continue
+				}
+			case <-ticker.C:
+			}
+
+			applyCtx, cancel := context.WithTimeout(ctx, silencePersistenceTimeout)
+			if err := s.apply(applyCtx); err != nil {
+				s.logger.Warn("Failed to apply silences of other replicas", "error", err)
+			}
+			cancel()
+		}
+	}()
+	return err
+}
+
+func (s *silenceReplicator) stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
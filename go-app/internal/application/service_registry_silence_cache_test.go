@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	infrastructurecache "github.com/ipiton/AMP/internal/infrastructure/cache"
+	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+func newTestSilenceReplica(t *testing.T, redisCache infrastructurecache.Cache, replicaID string) (*memory.SilenceStore, *silenceReplicator) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	cache, err := infrasilencing.NewRedisSilenceCache(redisCache, "amp:silences", replicaID, logger)
+	require.NoError(t, err)
+
+	store := memory.NewSilenceStore()
+	replicator := newSilenceReplicator(store, cache, nil, time.Hour, logger)
+	require.NoError(t, replicator.bootstrap(context.Background()))
+	store.SetOnChange(replicator.publish)
+	require.NoError(t, replicator.start(context.Background()))
+	t.Cleanup(replicator.stop)
+	return store, replicator
+}
+
+func TestSilenceReplicator_ReplicasConverge(t *testing.T) {
+	mr := miniredis.RunT(t)
+	redisCache, err := infrastructurecache.NewRedisCache(&infrastructurecache.CacheConfig{
+		Addr:        mr.Addr(),
+		PoolSize:    5,
+		DialTimeout: time.Second,
+		ReadTimeout: time.Second,
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+
+	storeA, _ := newTestSilenceReplica(t, redisCache, "replica-a")
+	storeB, _ := newTestSilenceReplica(t, redisCache, "replica-b")
+
+	now := time.Now().UTC()
+	id, err := storeA.CreateOrUpdate(&core.SilenceInput{
+		Matchers:  []core.SilenceMatcherInput{{Name: "alertname", Value: "DiskFull"}},
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "ops@example.com",
+		Comment:   "Disk replacement",
+	}, now)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return storeB.HasActiveMatch(map[string]string{"alertname": "DiskFull"}, time.Now().UTC())
+	}, time.Second, 10*time.Millisecond, "silence reaches the other replica within a second")
+
+	require.NoError(t, storeB.Expire(id, time.Now().UTC()))
+	require.Eventually(t, func() bool {
+		return !storeA.HasActiveMatch(map[string]string{"alertname": "DiskFull"}, time.Now().UTC())
+	}, time.Second, 10*time.Millisecond, "expiry reaches the replica that created the silence")
+
+	silence, ok := storeA.Get(id, time.Now().UTC())
+	require.True(t, ok)
+	assert.Equal(t, "expired", silence.Status.State)
+}
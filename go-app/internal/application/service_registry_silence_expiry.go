@@ -0,0 +1,38 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// startSilenceExpiryNotifier starts notifying the targets of silences created
+// with notifyOnExpiry (silence_expiry). It needs the publishing queue and is
+// skipped when publishing runs in metrics-only mode.
+func (r *ServiceRegistry) startSilenceExpiryNotifier(ctx context.Context) {
+	if r.silenceStore == nil || r.publishingQueue == nil || r.publishingDiscoveryAdapter == nil {
+		r.logger.Info("Silence expiry notifications disabled: publishing queue unavailable")
+		return
+	}
+
+	cfg := r.config.SilenceExpiry
+	publisher := &queueTargetPublisher{
+		discovery: r.publishingDiscoveryAdapter,
+		queue:     r.publishingQueue,
+	}
+	r.silenceExpiryNotifier = services.NewSilenceExpiryNotifier(r.silenceStore, r.alertStore, publisher, services.SilenceExpiryNotifierConfig{
+		Interval: cfg.Interval,
+		LeadTime: cfg.LeadTime,
+		Logger:   r.logger,
+	})
+	r.silenceExpiryNotifier.Start(context.WithoutCancel(ctx))
+}
+
+func (r *ServiceRegistry) stopSilenceExpiryNotifier() {
+	if r.silenceExpiryNotifier == nil {
+		return
+	}
+	r.logger.Info("Shutting down silence expiry notifier...")
+	r.silenceExpiryNotifier.Stop()
+	r.silenceExpiryNotifier = nil
+}
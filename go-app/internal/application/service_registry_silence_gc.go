@@ -0,0 +1,36 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// startSilenceGC starts deleting silences that expired more than
+// silence_gc.retention ago. Deletions are written through to the silences
+// table when silence persistence is enabled.
+func (r *ServiceRegistry) startSilenceGC(ctx context.Context) {
+	cfg := r.config.SilenceGC
+	if !cfg.Enabled || r.silenceStore == nil {
+		r.logger.Info("Silence GC disabled")
+		return
+	}
+
+	r.silenceGC = services.NewSilenceGC(r.silenceStore, services.SilenceGCConfig{
+		Interval:  cfg.Interval,
+		Retention: cfg.Retention,
+		BatchSize: cfg.BatchSize,
+		Metrics:   r.metrics,
+		Logger:    r.logger,
+	})
+	r.silenceGC.Start(context.WithoutCancel(ctx))
+}
+
+func (r *ServiceRegistry) stopSilenceGC() {
+	if r.silenceGC == nil {
+		return
+	}
+	r.logger.Info("Shutting down silence GC...")
+	r.silenceGC.Stop()
+	r.silenceGC = nil
+}
@@ -0,0 +1,27 @@
+package application
+
+import (
+	"github.com/ipiton/AMP/internal/core/services"
+	"github.com/ipiton/AMP/internal/infrastructure/alertmanager"
+)
+
+// SilenceMigrator returns the silence importer/exporter for the local
+// silence store.
+func (r *ServiceRegistry) SilenceMigrator() *services.SilenceMigrator {
+	return services.NewSilenceMigrator(r.silenceStore)
+}
+
+// MigrationAlertmanager returns a client for the Alertmanager configured in
+// silence_migration, or nil when none is configured. The config is read on
+// every call so reloads take effect.
+func (r *ServiceRegistry) MigrationAlertmanager() (services.SilenceExportTarget, error) {
+	cfg := r.config.SilenceMigration
+	if cfg.AlertmanagerURL == "" {
+		return nil, nil
+	}
+	client, err := alertmanager.NewClient(alertmanager.Config{URL: cfg.AlertmanagerURL, Timeout: cfg.Timeout})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
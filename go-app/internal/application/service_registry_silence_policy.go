@@ -0,0 +1,50 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// initializeSilencePolicy installs the silence policy on the silence store,
+// so it applies to every client creating silences.
+func (r *ServiceRegistry) initializeSilencePolicy() {
+	if !r.config.SilencePolicy.Enabled {
+		return
+	}
+	policy, err := newSilencePolicy(r.config.SilencePolicy)
+	if err != nil {
+		r.logger.Warn("Silence policy disabled", "error", err)
+		r.addDegradedReason("silence policy unavailable: %v", err)
+		return
+	}
+	r.silenceStore.SetPolicy(policy.Check)
+	r.logger.Info("Silence policy enabled",
+		"max_duration", r.config.SilencePolicy.MaxDuration,
+		"groups", len(r.config.SilencePolicy.Groups),
+		"protected", len(r.config.SilencePolicy.Protected))
+}
+
+func newSilencePolicy(cfg appconfig.SilencePolicyConfig) (*services.SilencePolicy, error) {
+	policy := services.SilencePolicyConfig{
+		MaxDuration: cfg.MaxDuration,
+		Protected:   cfg.Protected,
+	}
+	for _, group := range cfg.Groups {
+		policy.Groups = append(policy.Groups, services.SilencePolicyGroup{
+			Name:        group.Name,
+			Members:     group.Members,
+			MaxDuration: group.MaxDuration,
+		})
+	}
+	for _, pattern := range cfg.CommentPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comment pattern %q: %w", pattern, err)
+		}
+		policy.CommentPatterns = append(policy.CommentPatterns, re)
+	}
+	return services.NewSilencePolicy(policy), nil
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipiton/AMP/internal/infrastructure/silencewebhook"
+)
+
+// startSilenceWebhooks starts posting silence lifecycle events to the
+// configured webhooks (silence_webhooks). Disabled without endpoints.
+func (r *ServiceRegistry) startSilenceWebhooks(ctx context.Context) error {
+	cfg := r.config.SilenceWebhooks
+	if len(cfg.Endpoints) == 0 {
+		return nil
+	}
+
+	endpoints := make([]silencewebhook.Endpoint, 0, len(cfg.Endpoints))
+	for _, endpoint := range cfg.Endpoints {
+		endpoints = append(endpoints, silencewebhook.Endpoint{
+			URL:           endpoint.URL,
+			Events:        endpoint.Events,
+			Headers:       endpoint.Headers,
+			SigningSecret: endpoint.SigningSecret,
+		})
+	}
+	n, err := silencewebhook.New(silencewebhook.Config{
+		Endpoints:  endpoints,
+		Interval:   cfg.Interval,
+		Timeout:    cfg.Timeout,
+		QueueSize:  cfg.QueueSize,
+		MaxRetries: cfg.MaxRetries,
+		Logger:     r.logger,
+	}, r.silenceStore)
+	if err != nil {
+		return fmt.Errorf("silence webhooks: %w", err)
+	}
+	n.Start(context.WithoutCancel(ctx))
+	r.silenceWebhooks = n
+	return nil
+}
+
+func (r *ServiceRegistry) stopSilenceWebhooks() {
+	if r.silenceWebhooks == nil {
+		return
+	}
+	r.logger.Info("Shutting down silence webhooks...")
+	r.silenceWebhooks.Stop()
+	r.silenceWebhooks = nil
+}
@@ -0,0 +1,348 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+	coresilencing "github.com/ipiton/AMP/internal/core/silencing"
+	infrastructure "github.com/ipiton/AMP/internal/infrastructure"
+	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// silencePersistenceTimeout bounds a single write-through to the silence repository.
+const silencePersistenceTimeout = 5 * time.Second
+
+// initializeSilencePersistence backs the in-memory silence store with the
+// profile's database (PostgreSQL for standard, SQLite for lite) so silences
+// survive restarts. Persisted silences are restored into the memory store and
+// every subsequent change is written through.
+func (r *ServiceRegistry) initializeSilencePersistence(ctx context.Context) error {
+	if r.silenceStore == nil {
+		return fmt.Errorf("silence store is not initialized")
+	}
+
+	repo, err := r.newSilenceRepository()
+	if err != nil {
+		return err
+	}
+
+	persistence := newSilencePersistence(r.silenceStore, repo, r.logger)
+	if err := persistence.restore(ctx); err != nil {
+		return fmt.Errorf("failed to restore silences: %w", err)
+	}
+
+	r.silenceRepo = repo
+	r.silencePersistence = persistence
+	r.silenceStore.SetOnChange(persistence.sync)
+	r.silenceAudit = r.newSilenceAuditRepository()
+	return nil
+}
+
+// newSilenceAuditRepository returns the audit table of the profile's database.
+// Called once the silence repository for the same database is available.
+func (r *ServiceRegistry) newSilenceAuditRepository() core.SilenceAuditStore {
+	if r.config.Profile == appconfig.ProfileLite {
+		return infrasilencing.NewSQLiteSilenceAuditRepository(r.storageRuntime.(*infrastructure.SQLiteDatabase).DB())
+	}
+	return infrasilencing.NewPostgresSilenceAuditRepository(r.database.Pool())
+}
+
+// recordSilenceAudit appends a silence change to the audit trail and posts
+// it to the silence webhooks. It is registered as the silence store's audit
+// hook; failures are logged only.
+func (r *ServiceRegistry) recordSilenceAudit(event core.SilenceAuditEvent) {
+	r.logger.Info("Silence changed",
+		"silence_id", event.SilenceID,
+		"action", event.Action,
+		"actor", event.Actor)
+	r.silenceWebhooks.HandleAudit(event)
+
+	if r.silenceAudit == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), silencePersistenceTimeout)
+	defer cancel()
+	if err := r.silenceAudit.AppendSilenceAudit(ctx, &event); err != nil {
+		r.logger.Warn("Failed to record silence audit event",
+			"silence_id", event.SilenceID,
+			"action", event.Action,
+			"error", err)
+	}
+}
+
+// SilenceAudit returns the silence audit trail (database-backed when silence
+// persistence is available, in memory otherwise).
+func (r *ServiceRegistry) SilenceAudit() core.SilenceAuditStore {
+	return r.silenceAudit
+}
+
+// newSilenceRepository selects the silence repository matching the deployment profile.
+func (r *ServiceRegistry) newSilenceRepository() (infrasilencing.SilenceRepository, error) {
+	switch r.config.Profile {
+	case appconfig.ProfileLite:
+		sqliteDB, ok := r.storageRuntime.(*infrastructure.SQLiteDatabase)
+		if !ok || sqliteDB.DB() == nil {
+			return nil, fmt.Errorf("sqlite storage is not initialized")
+		}
+		return infrasilencing.NewSQLiteSilenceRepository(sqliteDB.DB(), r.logger, infrasilencing.NewSilenceMetrics()), nil
+
+	case appconfig.ProfileStandard:
+		if r.database == nil || r.database.Pool() == nil {
+			return nil, fmt.Errorf("postgres database is not initialized")
+		}
+		return infrasilencing.NewPostgresSilenceRepository(r.database.Pool(), r.logger), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported deployment profile: %q", r.config.Profile)
+	}
+}
+
+// SilenceRepository returns the persistent silence repository (nil when persistence is unavailable).
+func (r *ServiceRegistry) SilenceRepository() infrasilencing.SilenceRepository {
+	return r.silenceRepo
+}
+
+// silencePersistence mirrors the memory silence store into a SilenceRepository.
+//
+// The memory store stays the source of truth for the API and the pipeline;
+// repository failures are logged and retried on the next change.
+type silencePersistence struct {
+	store  *memory.SilenceStore
+	repo   infrasilencing.SilenceRepository
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	synced map[string]persistedSilence
+}
+
+// persistedSilence is what was last written for a silence ID.
+type persistedSilence struct {
+	snapshot  string     // APISilence JSON without the time-dependent status
+	updatedAt *time.Time // optimistic-lock token returned by the repository
+}
+
+func newSilencePersistence(store *memory.SilenceStore, repo infrasilencing.SilenceRepository, logger *slog.Logger) *silencePersistence {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &silencePersistence{
+		store:  store,
+		repo:   repo,
+		logger: logger,
+		synced: make(map[string]persistedSilence),
+	}
+}
+
+// restore loads all persisted silences into the memory store.
+func (p *silencePersistence) restore(ctx context.Context) error {
+	const pageSize = 1000
+
+	tokens := make(map[string]*time.Time)
+	items := make([]core.APISilence, 0)
+	for offset := 0; ; offset += pageSize {
+		page, err := p.repo.ListSilences(ctx, infrasilencing.SilenceFilter{
+			Limit:   pageSize,
+			Offset:  offset,
+			OrderBy: "created_at",
+		})
+		if err != nil {
+			return err
+		}
+		for _, silence := range page {
+			items = append(items, silenceToAPI(silence))
+			tokens[silence.ID] = silence.UpdatedAt
+		}
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	now := time.Now().UTC()
+	if err := p.store.RestoreFromPersistence(items, now); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, silence := range p.store.ExportForPersistence(now) {
+		p.synced[silence.ID] = persistedSilence{
+			snapshot:  silenceSnapshot(silence),
+			updatedAt: tokens[silence.ID],
+		}
+	}
+
+	p.logger.Info("Silences restored from persistent storage", "count", len(items))
+	return nil
+}
+
+// sync writes the difference between the memory store and the last persisted
+// state to the repository. It is registered as the store's change hook.
+func (p *silencePersistence) sync() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), silencePersistenceTimeout)
+	defer cancel()
+
+	current := p.store.ExportForPersistence(time.Now().UTC())
+	seen := make(map[string]struct{}, len(current))
+
+	for _, item := range current {
+		seen[item.ID] = struct{}{}
+		snapshot := silenceSnapshot(item)
+
+		prev, known := p.synced[item.ID]
+		if known && prev.snapshot == snapshot {
+			continue
+		}
+
+		silence, err := silenceFromAPI(item)
+		if err != nil {
+			p.logger.Warn("Failed to convert silence for persistence", "silence_id", item.ID, "error", err)
+			continue
+		}
+
+		if known {
+			silence.UpdatedAt = prev.updatedAt
+			err = p.update(ctx, silence)
+		} else {
+			_, err = p.repo.CreateSilence(ctx, silence)
+		}
+		if err != nil {
+			p.logger.Warn("Failed to persist silence", "silence_id", item.ID, "error", err)
+			continue
+		}
+
+		p.synced[item.ID] = persistedSilence{snapshot: snapshot, updatedAt: silence.UpdatedAt}
+	}
+
+	for id := range p.synced {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		if err := p.repo.DeleteSilence(ctx, id); err != nil && !errors.Is(err, infrasilencing.ErrSilenceNotFound) {
+			p.logger.Warn("Failed to delete persisted silence", "silence_id", id, "error", err)
+			continue
+		}
+		delete(p.synced, id)
+	}
+}
+
+// adopt records silences written to the repository by another replica as
+// synced, so they are neither written again nor deleted by the next sync.
+// Lock tokens are refreshed on the next update (see update).
+func (p *silencePersistence) adopt(items []core.APISilence) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	synced := make(map[string]persistedSilence, len(items))
+	for _, item := range items {
+		synced[item.ID] = persistedSilence{
+			snapshot:  silenceSnapshot(item),
+			updatedAt: p.synced[item.ID].updatedAt,
+		}
+	}
+	p.synced = synced
+}
+
+// update writes an existing silence, refreshing the lock token once on conflict
+// (another replica or a restore may have touched the row).
+func (p *silencePersistence) update(ctx context.Context, silence *coresilencing.Silence) error {
+	err := p.repo.UpdateSilence(ctx, silence)
+	switch {
+	case errors.Is(err, infrasilencing.ErrSilenceConflict):
+		stored, getErr := p.repo.GetSilenceByID(ctx, silence.ID)
+		if getErr != nil {
+			return err
+		}
+		silence.UpdatedAt = stored.UpdatedAt
+		return p.repo.UpdateSilence(ctx, silence)
+	case errors.Is(err, infrasilencing.ErrSilenceNotFound):
+		_, err = p.repo.CreateSilence(ctx, silence)
+		return err
+	default:
+		return err
+	}
+}
+
+func silenceSnapshot(in core.APISilence) string {
+	in.Status = core.APISilenceStatus{}
+	data, _ := json.Marshal(in)
+	return string(data)
+}
+
+func silenceFromAPI(in core.APISilence) (*coresilencing.Silence, error) {
+	startsAt, err := time.Parse(time.RFC3339, in.StartsAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startsAt: %w", err)
+	}
+	endsAt, err := time.Parse(time.RFC3339, in.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endsAt: %w", err)
+	}
+
+	// A silence expired before it started has StartsAt == EndsAt, which the
+	// repository schema rejects; keep it as a one-second expired window instead.
+	if !endsAt.After(startsAt) {
+		startsAt = endsAt.Add(-time.Second)
+	}
+
+	matchers := make([]coresilencing.Matcher, 0, len(in.Matchers))
+	for _, m := range in.Matchers {
+		matcherType := coresilencing.MatcherTypeEqual
+		switch {
+		case m.IsRegex && m.IsEqual:
+			matcherType = coresilencing.MatcherTypeRegex
+		case m.IsRegex:
+			matcherType = coresilencing.MatcherTypeNotRegex
+		case !m.IsEqual:
+			matcherType = coresilencing.MatcherTypeNotEqual
+		}
+		matchers = append(matchers, coresilencing.Matcher{
+			Name:    m.Name,
+			Value:   m.Value,
+			Type:    matcherType,
+			IsRegex: m.IsRegex,
+		})
+	}
+
+	return &coresilencing.Silence{
+		ID:                   in.ID,
+		CreatedBy:            in.CreatedBy,
+		Comment:              in.Comment,
+		StartsAt:             startsAt.UTC(),
+		EndsAt:               endsAt.UTC(),
+		Matchers:             matchers,
+		RequireLabelPresence: in.RequireLabelPresence,
+	}, nil
+}
+
+func silenceToAPI(in *coresilencing.Silence) core.APISilence {
+	matchers := make([]core.APISilenceMatcher, 0, len(in.Matchers))
+	for _, m := range in.Matchers {
+		matchers = append(matchers, core.APISilenceMatcher{
+			Name:    m.Name,
+			Value:   m.Value,
+			IsRegex: m.Type.IsRegexType(),
+			IsEqual: m.Type == coresilencing.MatcherTypeEqual || m.Type == coresilencing.MatcherTypeRegex,
+		})
+	}
+
+	return core.APISilence{
+		ID:                   in.ID,
+		Matchers:             matchers,
+		StartsAt:             in.StartsAt.UTC().Format(time.RFC3339),
+		EndsAt:               in.EndsAt.UTC().Format(time.RFC3339),
+		CreatedBy:            in.CreatedBy,
+		Comment:              in.Comment,
+		RequireLabelPresence: in.RequireLabelPresence,
+	}
+}
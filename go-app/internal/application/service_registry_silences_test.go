@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func newLiteSilenceTestRegistry(t *testing.T, dbPath string) *ServiceRegistry {
+	t.Helper()
+	withIsolatedPrometheusRegistry(t)
+
+	cfg := &appconfig.Config{
+		Profile: appconfig.ProfileLite,
+		Storage: appconfig.StorageConfig{
+			Backend:        appconfig.StorageBackendFilesystem,
+			FilesystemPath: dbPath,
+		},
+		Database: appconfig.DatabaseConfig{MaxConnections: 1, MinConnections: 1},
+		Redis: appconfig.RedisConfig{
+			Addr:         "127.0.0.1:1",
+			PoolSize:     1,
+			DialTimeout:  10 * time.Millisecond,
+			ReadTimeout:  10 * time.Millisecond,
+			WriteTimeout: 10 * time.Millisecond,
+			MaxRetries:   1,
+		},
+	}
+
+	registry, err := NewServiceRegistry(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	require.NoError(t, registry.Initialize(context.Background()))
+	return registry
+}
+
+func TestServiceRegistry_SilencesSurviveRestart(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "alerts.db")
+	ctx := context.Background()
+	now := time.Now().UTC()
+	isEqual := false
+
+	first := newLiteSilenceTestRegistry(t, dbPath)
+	require.NotNil(t, first.SilenceRepository())
+
+	keptID, err := first.SilenceStore().CreateOrUpdate(&core.SilenceInput{
+		Matchers: []core.SilenceMatcherInput{
+			{Name: "alertname", Value: "HighCPU"},
+			{Name: "env", Value: "prod.*", IsRegex: true, IsEqual: &isEqual},
+		},
+		StartsAt:  now.Add(-time.Minute).Format(time.RFC3339),
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "ops@example.com",
+		Comment:   "Planned maintenance",
+	}, now)
+	require.NoError(t, err)
+
+	expiredID, err := first.SilenceStore().CreateOrUpdate(&core.SilenceInput{
+		Matchers:  []core.SilenceMatcherInput{{Name: "alertname", Value: "DiskFull"}},
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "ops@example.com",
+		Comment:   "Noisy disk",
+	}, now)
+	require.NoError(t, err)
+
+	deletedID, err := first.SilenceStore().CreateOrUpdate(&core.SilenceInput{
+		Matchers:  []core.SilenceMatcherInput{{Name: "alertname", Value: "Flapping"}},
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "ops@example.com",
+		Comment:   "Temporary",
+	}, now)
+	require.NoError(t, err)
+
+	kept, ok := first.SilenceStore().Get(keptID, now)
+	require.True(t, ok)
+	updatedID, err := first.SilenceStore().CreateOrUpdate(&core.SilenceInput{
+		ID:        keptID,
+		Matchers:  []core.SilenceMatcherInput{{Name: "alertname", Value: "HighCPU"}, {Name: "env", Value: "prod.*", IsRegex: true, IsEqual: &isEqual}},
+		StartsAt:  kept.StartsAt,
+		EndsAt:    now.Add(2 * time.Hour).Format(time.RFC3339),
+		CreatedBy: "ops@example.com",
+		Comment:   "Extended maintenance",
+	}, now)
+	require.NoError(t, err)
+	require.Equal(t, keptID, updatedID, "active silence with unchanged matchers is updated in place")
+
+	require.NoError(t, first.SilenceStore().Expire(expiredID, now))
+	require.True(t, first.SilenceStore().Delete(deletedID))
+	require.NoError(t, first.Shutdown(ctx))
+
+	second := newLiteSilenceTestRegistry(t, dbPath)
+	t.Cleanup(func() { _ = second.Shutdown(ctx) })
+
+	silences := second.SilenceStore().List(time.Now().UTC())
+	require.Len(t, silences, 2)
+
+	restored := silences[0]
+	assert.Equal(t, keptID, restored.ID)
+	assert.Equal(t, "Extended maintenance", restored.Comment)
+	assert.Equal(t, now.Add(2*time.Hour).Format(time.RFC3339), restored.EndsAt)
+	assert.Equal(t, "active", restored.Status.State)
+	assert.Equal(t, []core.APISilenceMatcher{
+		{Name: "alertname", Value: "HighCPU", IsRegex: false, IsEqual: true},
+		{Name: "env", Value: "prod.*", IsRegex: true, IsEqual: false},
+	}, restored.Matchers)
+
+	assert.Equal(t, expiredID, silences[1].ID)
+	assert.Equal(t, "expired", silences[1].Status.State)
+}
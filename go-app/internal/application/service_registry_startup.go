@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core/services"
+	infrastructurecache "github.com/ipiton/AMP/internal/infrastructure/cache"
+	"github.com/ipiton/AMP/internal/infrastructure/grouping"
+)
+
+// Runtime checkpoint written to the cache, read back by the next start.
+const (
+	runtimeCheckpointKey      = "amp:runtime:checkpoint"
+	runtimeCheckpointInterval = 30 * time.Second
+	runtimeCheckpointTTL      = 7 * 24 * time.Hour
+	runtimeCheckpointTimeout  = 5 * time.Second
+)
+
+// runtimeCheckpoint is the state of a running AMP that does not survive a
+// restart otherwise. It is written every runtimeCheckpointInterval and at
+// shutdown, so the next start knows how long AMP was down and can restore
+// the target groups.
+type runtimeCheckpoint struct {
+	SeenAt        time.Time                   `json:"seen_at"`
+	CleanShutdown bool                        `json:"clean_shutdown"`
+	TargetGroups  []grouping.TargetGroupState `json:"target_groups,omitempty"`
+}
+
+// runtimeCheckpointer writes the runtime checkpoint periodically.
+type runtimeCheckpointer struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// reconcileStartup restores the target groups of the previous run, replays
+// the alerts firing elsewhere when configured, builds the startup report from
+// the restored state and starts checkpointing.
+// Called once all services are initialized, before alerts are accepted.
+func (r *ServiceRegistry) reconcileStartup(ctx context.Context) {
+	checkpoint := r.loadRuntimeCheckpoint(ctx)
+	report := services.NewStartupReport(r.startTime, checkpoint.SeenAt, checkpoint.CleanShutdown)
+
+	// Before counting the inhibition sources, which the replay warms up
+	report.Replay = r.replayAlerts(ctx, checkpoint.SeenAt)
+
+	if r.silenceStore != nil {
+		report.AddSilences(r.silenceStore.List(r.startTime))
+	}
+	if r.inhibitionCache != nil {
+		if alerts, err := r.inhibitionCache.GetFiringAlerts(ctx); err == nil {
+			report.Inhibition.SourceAlerts = len(alerts)
+		}
+	}
+	if r.inhibitionState != nil {
+		if inhibitions, err := r.inhibitionState.GetActiveInhibitions(ctx); err == nil {
+			report.Inhibition.Inhibitions = len(inhibitions)
+		}
+	}
+
+	if r.publishingCoordinator != nil && len(checkpoint.TargetGroups) > 0 {
+		restored := r.publishingCoordinator.RestoreTargetGroups(checkpoint.TargetGroups)
+		report.Groups = services.StartupGroups{Restored: restored.Groups, Dropped: restored.Dropped}
+		report.Timers = services.StartupTimers{Restored: restored.Timers, Missed: restored.MissedTimers}
+		for _, group := range checkpoint.TargetGroups {
+			if len(group.Firing) > 0 {
+				report.AddFiringGroup(group.LastFlush)
+			}
+		}
+	}
+
+	r.startupReport = report
+	r.logger.Info("Startup reconciliation", report.LogAttrs()...)
+
+	r.startRuntimeCheckpoints(ctx)
+}
+
+// StartupReport returns the reconciliation report of this start (nil until
+// the registry is initialized).
+func (r *ServiceRegistry) StartupReport() *services.StartupReport {
+	return r.startupReport
+}
+
+// loadRuntimeCheckpoint reads the checkpoint of the previous run. It is empty
+// on the first start and with the in-memory cache.
+func (r *ServiceRegistry) loadRuntimeCheckpoint(ctx context.Context) runtimeCheckpoint {
+	var checkpoint runtimeCheckpoint
+	if r.cache == nil {
+		return checkpoint
+	}
+	ctx, cancel := context.WithTimeout(ctx, runtimeCheckpointTimeout)
+	defer cancel()
+	if err := r.cache.Get(ctx, runtimeCheckpointKey, &checkpoint); err != nil {
+		if !errors.Is(err, infrastructurecache.ErrNotFound) {
+			r.logger.Warn("Failed to read runtime checkpoint", "error", err)
+		}
+		return runtimeCheckpoint{}
+	}
+	return checkpoint
+}
+
+// saveRuntimeCheckpoint writes the runtime checkpoint. cleanShutdown marks
+// the last checkpoint of a graceful shutdown.
+func (r *ServiceRegistry) saveRuntimeCheckpoint(cleanShutdown bool) {
+	if r.cache == nil {
+		return
+	}
+	checkpoint := runtimeCheckpoint{
+		SeenAt:        time.Now().UTC(),
+		CleanShutdown: cleanShutdown,
+	}
+	if r.publishingCoordinator != nil {
+		checkpoint.TargetGroups = r.publishingCoordinator.TargetGroups()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeCheckpointTimeout)
+	defer cancel()
+	if err := r.cache.Set(ctx, runtimeCheckpointKey, checkpoint, runtimeCheckpointTTL); err != nil {
+		r.logger.Warn("Failed to write runtime checkpoint", "error", err)
+	}
+}
+
+func (r *ServiceRegistry) startRuntimeCheckpoints(ctx context.Context) {
+	if r.cache == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	checkpoints := &runtimeCheckpointer{cancel: cancel}
+	r.runtimeCheckpoints = checkpoints
+
+	// Record this start right away: a crash before the first tick is
+	// measured from here
+	r.saveRuntimeCheckpoint(false)
+
+	checkpoints.wg.Add(1)
+	go func() {
+		defer checkpoints.wg.Done()
+		ticker := time.NewTicker(runtimeCheckpointInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.saveRuntimeCheckpoint(false)
+			}
+		}
+	}()
+}
+
+// stopRuntimeCheckpoints stops checkpointing and writes the checkpoint of a
+// clean shutdown. Call it once the target groups released their pending
+// alerts, so the next start does not send them again.
+func (r *ServiceRegistry) stopRuntimeCheckpoints() {
+	if r.runtimeCheckpoints == nil {
+		return
+	}
+	r.runtimeCheckpoints.cancel()
+	r.runtimeCheckpoints.wg.Wait()
+	r.runtimeCheckpoints = nil
+	r.saveRuntimeCheckpoint(true)
+}
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+
+	appconfig "github.com/ipiton/AMP/internal/config"
+	"github.com/ipiton/AMP/internal/core/services"
+)
+
+// newStormDetector builds the storm detector from config. Storm notifications
+// and digests are routed through the alert processor like any other alert.
+func (r *ServiceRegistry) newStormDetector(cfg appconfig.StormDetectionConfig) *services.StormDetector {
+	return services.NewStormDetector(services.StormDetectorConfig{
+		Window:             cfg.Window,
+		Multiplier:         cfg.Multiplier,
+		MinAlerts:          cfg.MinAlerts,
+		Cooldown:           cfg.Cooldown,
+		DigestInterval:     cfg.DigestInterval,
+		GroupBy:            cfg.GroupBy,
+		CriticalSeverities: cfg.CriticalSeverities,
+		Sink:               processorAlertSink{registry: r},
+		Metrics:            r.metrics,
+		Logger:             r.logger,
+	})
+}
+
+// startStormDetector starts the ticker that sends storm digests and ends
+// storms once the alert rate is back to normal.
+func (r *ServiceRegistry) startStormDetector(ctx context.Context) {
+	if r.stormDetector == nil {
+		r.logger.Info("Storm detection disabled")
+		return
+	}
+	r.stormDetector.Start(context.WithoutCancel(ctx))
+}
+
+func (r *ServiceRegistry) stopStormDetector() {
+	if r.stormDetector == nil {
+		return
+	}
+	r.logger.Info("Shutting down storm detector...")
+	r.stormDetector.Stop()
+	r.stormDetector = nil
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/watchdog"
+)
+
+// publishingQueueSubsystem is the watchdog subsystem name of publishing queue workers.
+const publishingQueueSubsystem = "publishing_queue"
+
+// processorAlertSink routes meta-alerts through the alert processor.
+//
+// The watchdog is created before the alert processor (publishing workers need
+// its heartbeat hook) and the storm detector is one of its inputs, so the
+// processor is resolved on every call.
+type processorAlertSink struct {
+	registry *ServiceRegistry
+}
+
+func (s processorAlertSink) ProcessAlert(ctx context.Context, alert *core.Alert) error {
+	if s.registry.alertProcessor == nil {
+		return fmt.Errorf("alert processor not initialized")
+	}
+	return s.registry.alertProcessor.ProcessAlert(ctx, alert)
+}
+
+// initializeWatchdog creates the leak watchdog (metrics.watchdog).
+// It is started by startWatchdog once the alert processor is ready.
+func (r *ServiceRegistry) initializeWatchdog() {
+	cfg := r.config.Metrics.Watchdog
+	if !cfg.Enabled {
+		return
+	}
+
+	r.watchdog = watchdog.New(watchdog.Config{
+		Interval:           cfg.Interval,
+		GoroutineThreshold: cfg.GoroutineThreshold,
+		FDThreshold:        cfg.FDThreshold,
+		Sink:               processorAlertSink{registry: r},
+		Logger:             r.logger,
+	})
+}
+
+// publishingQueueHeartbeat registers publishing workers with the watchdog and
+// returns their heartbeat hook (nil without a watchdog).
+func (r *ServiceRegistry) publishingQueueHeartbeat() func() {
+	if r.watchdog == nil {
+		return nil
+	}
+	wd := r.watchdog
+	wd.RegisterSubsystem(publishingQueueSubsystem, r.config.Metrics.Watchdog.WorkerStaleAfter)
+	return func() { wd.Heartbeat(publishingQueueSubsystem) }
+}
+
+func (r *ServiceRegistry) startWatchdog(ctx context.Context) {
+	if r.watchdog == nil {
+		return
+	}
+	r.watchdog.Start(context.WithoutCancel(ctx))
+}
+
+func (r *ServiceRegistry) stopWatchdog() {
+	if r.watchdog == nil {
+		return
+	}
+	r.logger.Info("Shutting down watchdog...")
+	r.watchdog.Stop()
+}
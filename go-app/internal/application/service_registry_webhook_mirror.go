@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipiton/AMP/internal/infrastructure/mirror"
+)
+
+// startWebhookMirror starts forwarding accepted webhooks to the peer AMP
+// (webhook.mirror). Disabled by default.
+func (r *ServiceRegistry) startWebhookMirror(ctx context.Context) error {
+	cfg := r.config.Webhook.Mirror
+	if !cfg.Enabled {
+		return nil
+	}
+
+	m, err := mirror.New(mirror.Config{
+		PeerURL:    cfg.PeerURL,
+		Origin:     cfg.Origin,
+		APIKey:     cfg.APIKey,
+		Timeout:    cfg.Timeout,
+		QueueSize:  cfg.QueueSize,
+		MaxRetries: cfg.MaxRetries,
+		Logger:     r.logger,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook mirror: %w", err)
+	}
+	m.Start(context.WithoutCancel(ctx))
+	r.webhookMirror = m
+	r.logger.Info("Webhook mirroring enabled", "peer_url", cfg.PeerURL)
+	return nil
+}
+
+func (r *ServiceRegistry) stopWebhookMirror() {
+	if r.webhookMirror == nil {
+		return
+	}
+	r.logger.Info("Shutting down webhook mirror...")
+	r.webhookMirror.Stop()
+	r.webhookMirror = nil
+}
+
+// WebhookMirror returns the peer webhook mirror (nil when disabled).
+func (r *ServiceRegistry) WebhookMirror() *mirror.Mirror {
+	return r.webhookMirror
+}
@@ -325,4 +325,8 @@ type DiscoveryStats struct {
 	// DiscoveryErrors is cumulative count of discovery errors.
 	// Incremented on K8s API failures (network, auth, timeout).
 	DiscoveryErrors int
+
+	// Sources is the outcome of the discoveries of every source that was
+	// discovered (see DiscoverySource).
+	Sources []DiscoverySourceStatus
 }
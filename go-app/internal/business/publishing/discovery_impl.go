@@ -13,6 +13,7 @@ import (
 	"github.com/ipiton/AMP/internal/core"
 	"github.com/ipiton/AMP/internal/infrastructure/k8s"
 	"github.com/ipiton/AMP/pkg/metrics"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
 )
 
 // DefaultTargetDiscoveryManager is production implementation of TargetDiscoveryManager.
@@ -74,10 +75,22 @@ type DefaultTargetDiscoveryManager struct {
 	// In-memory cache (thread-safe, O(1) Get)
 	cache *targetCache
 
+	// Sources beyond the secrets of namespace, and their outcomes
+	// (protected by mu)
+	sources        []DiscoverySource
+	refreshMetrics *v2.PublishingMetrics
+	sourceStates   map[DiscoverySource]*sourceState
+
 	// Statistics (protected by mu)
 	stats DiscoveryStats
 	mu    sync.RWMutex
 
+	// discoverMu serializes discoveries (refresh, watch, writes)
+	discoverMu sync.Mutex
+
+	// watchDebounce overrides the debounce of Watch (tests)
+	watchDebounce time.Duration
+
 	// Observability
 	logger  *slog.Logger
 	metrics *DiscoveryMetrics
@@ -171,6 +184,7 @@ func NewTargetDiscoveryManager(
 		namespace:     namespace,
 		labelSelector: labelSelector,
 		cache:         newTargetCache(),
+		sourceStates:  make(map[DiscoverySource]*sourceState),
 		logger:        logger,
 		metrics:       discoveryMetrics,
 	}
@@ -184,51 +198,107 @@ func NewTargetDiscoveryManager(
 	return manager, nil
 }
 
-// DiscoverTargets lists K8s secrets and refreshes in-memory cache.
+// sourceState is what the last discoveries of a source yielded.
+type sourceState struct {
+	status  DiscoverySourceStatus
+	targets []*core.PublishingTarget // of the last successful discovery
+}
+
+// DiscoverTargets lists the K8s objects of every source and refreshes
+// in-memory cache.
+//
+// A source whose listing fails keeps the targets of its last successful
+// discovery, so that a namespace that cannot be read does not unregister
+// the targets of the others. The cache is kept as is when every source
+// fails. A target name found in several sources is taken from the first.
 func (m *DefaultTargetDiscoveryManager) DiscoverTargets(ctx context.Context) error {
+	m.discoverMu.Lock()
+	defer m.discoverMu.Unlock()
+
 	startTime := time.Now()
+	sources := m.discoverySources()
 
 	m.logger.Info("Starting target discovery",
 		"namespace", m.namespace,
 		"label_selector", m.labelSelector,
+		"sources", len(sources),
 	)
 
-	// List secrets from K8s API
-	secrets, err := m.k8sClient.ListSecrets(ctx, m.namespace, m.labelSelector)
-	if err != nil {
-		// K8s API unavailable - keep old cache (graceful degradation)
-		m.logger.Error("Failed to list K8s secrets",
-			"namespace", m.namespace,
-			"error", err,
+	var (
+		validTargets []*core.PublishingTarget
+		objects      int
+		invalidCount int
+		firstErr     error
+		failed       int
+	)
+	names := make(map[string]DiscoverySource)
+	for _, source := range sources {
+		secrets, err := m.listSource(ctx, source)
+		if err != nil {
+			// K8s API unavailable - keep the targets of the source
+			m.logger.Error("Failed to list K8s objects",
+				"source", source.String(),
+				"error", err,
+			)
+			if firstErr == nil {
+				firstErr = NewDiscoveryFailedError(source.Namespace, err)
+			}
+			failed++
+
+			m.mu.Lock()
+			m.stats.DiscoveryErrors++
+			state := m.sourceState(source)
+			state.status.LastAttempt = time.Now()
+			state.status.Error = err.Error()
+			stale := state.targets
+			objects += state.status.Objects
+			m.mu.Unlock()
+
+			if m.metrics != nil {
+				m.metrics.ErrorsTotal.WithLabelValues("k8s_api").Inc()
+			}
+			validTargets = m.appendSourceTargets(validTargets, names, source, stale)
+			continue
+		}
+
+		m.logger.Debug("K8s objects listed",
+			"source", source.String(),
+			"count", len(secrets),
+			"duration_ms", time.Since(startTime).Milliseconds(),
 		)
 
-		// Update error statistics
-		m.mu.Lock()
-		m.stats.DiscoveryErrors++
-		m.mu.Unlock()
+		// Parse and validate secrets
+		targets, invalid := m.parseAndValidateSecrets(secrets)
+		objects += len(secrets)
+		invalidCount += invalid
 
-		// Record error metric
-		if m.metrics != nil {
-			m.metrics.ErrorsTotal.WithLabelValues("k8s_api").Inc()
+		m.mu.Lock()
+		state := m.sourceState(source)
+		now := time.Now()
+		state.status = DiscoverySourceStatus{
+			Source:      source.String(),
+			Objects:     len(secrets),
+			Targets:     len(targets),
+			LastAttempt: now,
+			LastSuccess: now,
 		}
+		state.targets = targets
+		m.mu.Unlock()
 
-		return NewDiscoveryFailedError(m.namespace, err)
+		validTargets = m.appendSourceTargets(validTargets, names, source, targets)
 	}
 
-	m.logger.Debug("K8s secrets listed",
-		"count", len(secrets),
-		"duration_ms", time.Since(startTime).Milliseconds(),
-	)
-
-	// Parse and validate secrets
-	validTargets, invalidCount := m.parseAndValidateSecrets(secrets)
+	if failed == len(sources) {
+		// Nothing listed - keep old cache (graceful degradation)
+		return firstErr
+	}
 
 	// Update cache atomically
 	m.cache.Set(validTargets)
 
 	// Update statistics
 	m.mu.Lock()
-	m.stats.TotalTargets = len(secrets)
+	m.stats.TotalTargets = objects
 	m.stats.ValidTargets = len(validTargets)
 	m.stats.InvalidTargets = invalidCount
 	m.stats.LastDiscovery = time.Now()
@@ -252,12 +322,49 @@ func (m *DefaultTargetDiscoveryManager) DiscoverTargets(ctx context.Context) err
 
 	m.logger.Info("Target discovery complete",
 		"duration_ms", time.Since(startTime).Milliseconds(),
-		"total_secrets", len(secrets),
+		"total_secrets", objects,
 		"valid_targets", len(validTargets),
 		"invalid_targets", invalidCount,
+		"failed_sources", failed,
 	)
 
-	return nil
+	// Partial success: the cache is refreshed, the error reports the
+	// sources that are stale
+	return firstErr
+}
+
+// sourceState returns the state of source, created if needed. Call with mu
+// held.
+func (m *DefaultTargetDiscoveryManager) sourceState(source DiscoverySource) *sourceState {
+	state, ok := m.sourceStates[source]
+	if !ok {
+		state = &sourceState{status: DiscoverySourceStatus{Source: source.String()}}
+		m.sourceStates[source] = state
+	}
+	return state
+}
+
+// appendSourceTargets appends the targets of source whose name was not
+// taken by a previous source.
+func (m *DefaultTargetDiscoveryManager) appendSourceTargets(
+	all []*core.PublishingTarget,
+	names map[string]DiscoverySource,
+	source DiscoverySource,
+	targets []*core.PublishingTarget,
+) []*core.PublishingTarget {
+	for _, target := range targets {
+		if first, ok := names[target.Name]; ok {
+			m.logger.Warn("Skipping target with duplicate name",
+				"target_name", target.Name,
+				"source", source.String(),
+				"kept_source", first.String(),
+			)
+			continue
+		}
+		names[target.Name] = source
+		all = append(all, target)
+	}
+	return all
 }
 
 // parseAndValidateSecrets parses and validates secrets, returns valid targets + invalid count.
@@ -266,8 +373,8 @@ func (m *DefaultTargetDiscoveryManager) parseAndValidateSecrets(secrets []corev1
 	invalidCount := 0
 
 	for _, secret := range secrets {
-		// Parse secret
-		target, err := parseSecret(secret)
+		// Parse secret (an Alertmanager config secret yields several targets)
+		targets, err := parseSecretTargets(secret)
 		if err != nil {
 			m.logger.Warn("Skipping secret with parse error",
 				"secret_name", secret.Name,
@@ -280,37 +387,39 @@ func (m *DefaultTargetDiscoveryManager) parseAndValidateSecrets(secrets []corev1
 			continue
 		}
 
-		// Validate target
-		validationErrs := validateTarget(target)
-		if len(validationErrs) > 0 {
-			m.logger.Warn("Skipping secret with validation errors",
-				"secret_name", secret.Name,
-				"target_name", target.Name,
-				"validation_errors", len(validationErrs),
-			)
-			for _, valErr := range validationErrs {
-				m.logger.Debug("Validation error detail",
-					"field", valErr.Field,
-					"message", valErr.Message,
-					"value", valErr.Value,
+		for _, target := range targets {
+			// Validate target
+			validationErrs := validateTarget(target)
+			if len(validationErrs) > 0 {
+				m.logger.Warn("Skipping secret with validation errors",
+					"secret_name", secret.Name,
+					"target_name", target.Name,
+					"validation_errors", len(validationErrs),
 				)
+				for _, valErr := range validationErrs {
+					m.logger.Debug("Validation error detail",
+						"field", valErr.Field,
+						"message", valErr.Message,
+						"value", valErr.Value,
+					)
+				}
+				invalidCount++
+				if m.metrics != nil {
+					m.metrics.ErrorsTotal.WithLabelValues("validate").Inc()
+				}
+				continue
 			}
-			invalidCount++
-			if m.metrics != nil {
-				m.metrics.ErrorsTotal.WithLabelValues("validate").Inc()
-			}
-			continue
-		}
 
-		// Valid target - add to list
-		validTargets = append(validTargets, target)
+			// Valid target - add to list
+			validTargets = append(validTargets, target)
 
-		m.logger.Debug("Parsed valid target",
-			"target_name", target.Name,
-			"type", target.Type,
-			"url", target.URL,
-			"enabled", target.Enabled,
-		)
+			m.logger.Debug("Parsed valid target",
+				"target_name", target.Name,
+				"type", target.Type,
+				"url", target.URL,
+				"enabled", target.Enabled,
+			)
+		}
 	}
 
 	return validTargets, invalidCount
@@ -319,7 +428,7 @@ func (m *DefaultTargetDiscoveryManager) parseAndValidateSecrets(secrets []corev1
 // updateTargetsGauge updates Prometheus gauge with target counts by type and enabled.
 func (m *DefaultTargetDiscoveryManager) updateTargetsGauge(targets []*core.PublishingTarget) {
 	// Reset all gauges (to handle deleted targets)
-	for _, targetType := range []string{"rootly", "pagerduty", "slack", "webhook"} {
+	for _, targetType := range []string{"rootly", "pagerduty", "slack", "webhook", "teams", "opsgenie", "email", "kafka", "jira", "googlechat", "mattermost", "aws", "plugin", "exec"} {
 		for _, enabled := range []string{"true", "false"} {
 			m.metrics.TargetsTotal.WithLabelValues(targetType, enabled).Set(0)
 		}
@@ -403,7 +512,13 @@ func (m *DefaultTargetDiscoveryManager) GetStats() DiscoveryStats {
 	defer m.mu.RUnlock()
 
 	// Return copy (thread-safe)
-	return m.stats
+	stats := m.stats
+	for _, source := range m.discoverySourcesLocked() {
+		if state, ok := m.sourceStates[source]; ok {
+			stats.Sources = append(stats.Sources, state.status)
+		}
+	}
+	return stats
 }
 
 // Health checks target discovery manager + K8s client health.
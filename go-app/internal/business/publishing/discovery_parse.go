@@ -5,10 +5,49 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/ipiton/AMP/internal/core"
+	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+
+	amconfig "github.com/ipiton/AMP/internal/alertmanager/config"
+	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
 )
 
+// alertmanagerConfigKey is the secret.Data key of an imported Alertmanager
+// configuration (alternative to 'config').
+const alertmanagerConfigKey = "alertmanager.yaml"
+
+// parseSecretTargets extracts the PublishingTargets of a K8s secret.
+//
+// A secret with 'config' holds one target (see parseSecret). A secret with
+// 'alertmanager.yaml' holds an imported Alertmanager configuration: each
+// entry of its receivers' email_configs becomes an email target (see
+// infrapublishing.EmailTargetsFromConfig); other receiver types are ignored
+// and keep using canonical target secrets.
+func parseSecretTargets(secret corev1.Secret) ([]*core.PublishingTarget, error) {
+	amData, ok := secret.Data[alertmanagerConfigKey]
+	if !ok {
+		target, err := parseSecret(secret)
+		if err != nil {
+			return nil, err
+		}
+		return []*core.PublishingTarget{target}, nil
+	}
+
+	var cfg amconfig.AlertmanagerConfig
+	if err := yaml.Unmarshal(amData, &cfg); err != nil {
+		return nil, NewInvalidSecretFormatError(
+			secret.Name,
+			fmt.Sprintf("invalid %s: %v", alertmanagerConfigKey, err),
+		)
+	}
+	targets, err := infrapublishing.EmailTargetsFromConfig(&cfg)
+	if err != nil {
+		return nil, NewInvalidSecretFormatError(secret.Name, err.Error())
+	}
+	return targets, nil
+}
+
 // parseSecret extracts PublishingTarget from K8s secret.
 //
 // Pipeline:
@@ -167,6 +167,42 @@ func TestParseSecret_RawJSON(t *testing.T) {
 	assert.Equal(t, "webhook", parsed.Type)
 }
 
+func TestParseSecretTargets_AlertmanagerConfig(t *testing.T) {
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager-import", Namespace: "monitoring"},
+		Data: map[string][]byte{
+			"alertmanager.yaml": []byte(`
+global:
+  smtp_smarthost: smtp.example.com:587
+  smtp_from: alerts@example.com
+route:
+  receiver: email-database
+  group_wait: 1m
+receivers:
+  - name: email-database
+    email_configs:
+      - to: dba-team@example.com
+  - name: slack-warnings
+    slack_configs:
+      - channel: "#alerts"
+`),
+		},
+	}
+
+	targets, err := parseSecretTargets(secret)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, "email-database", targets[0].Name)
+	assert.Equal(t, "smtp://smtp.example.com:587", targets[0].URL)
+	assert.Equal(t, "1m0s", targets[0].Headers["batch_wait"])
+	assert.Empty(t, validateTarget(targets[0]))
+
+	secret.Data["alertmanager.yaml"] = []byte("receivers:\n  - name: mail\n    email_configs:\n      - to: ops@example.com\n")
+	_, err = parseSecretTargets(secret)
+	var formatErr *ErrInvalidSecretFormat
+	assert.ErrorAs(t, err, &formatErr, "missing smarthost must be reported")
+}
+
 func TestIsBase64Encoded_ValidBase64(t *testing.T) {
 	data := []byte("SGVsbG8gV29ybGQ=") // "Hello World" in base64
 	assert.True(t, isBase64Encoded(data))
@@ -0,0 +1,153 @@
+package publishing
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// Kinds of K8s objects that targets are discovered from.
+const (
+	SourceKindSecret    = "secret"
+	SourceKindConfigMap = "configmap"
+)
+
+// DiscoverySource is a kind of K8s object in a namespace that targets are
+// discovered from: the objects matching the label selector whose data holds
+// a 'config' (or 'alertmanager.yaml') key, as described in parseSecret.
+type DiscoverySource struct {
+	Kind      string
+	Namespace string
+}
+
+// String returns "<kind>/<namespace>", the source label of the refresh
+// metrics.
+func (s DiscoverySource) String() string {
+	return s.Kind + "/" + s.Namespace
+}
+
+// DiscoverySourceStatus is the outcome of the discoveries of a source.
+type DiscoverySourceStatus struct {
+	Source string `json:"source"`
+
+	// Objects and Targets are the objects listed and the valid targets
+	// parsed at the last successful discovery, still in use when the last
+	// discovery failed.
+	Objects int `json:"objects"`
+	Targets int `json:"targets"`
+
+	LastAttempt time.Time `json:"last_attempt"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+
+	// Error is the error of the last discovery, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// DiscoverySourcesConfig selects the sources of a DefaultTargetDiscoveryManager
+// beyond the secrets of its namespace.
+type DiscoverySourcesConfig struct {
+	// Namespaces are watched in addition to the namespace of the manager.
+	Namespaces []string
+
+	// ConfigMaps also discovers targets from the config maps matching the
+	// label selector, in every namespace. Their targets should not hold
+	// credentials.
+	ConfigMaps bool
+
+	// Metrics records the refreshes of every source (optional).
+	Metrics *v2.PublishingMetrics
+}
+
+// SetSources configures the sources of the next discoveries. Targets
+// created through the TargetStore are still written to the namespace of the
+// manager.
+func (m *DefaultTargetDiscoveryManager) SetSources(config DiscoverySourcesConfig) {
+	namespaces := []string{m.namespace}
+	for _, namespace := range config.Namespaces {
+		if namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	sources := make([]DiscoverySource, 0, 2*len(namespaces))
+	for _, namespace := range namespaces {
+		sources = append(sources, DiscoverySource{Kind: SourceKindSecret, Namespace: namespace})
+	}
+	if config.ConfigMaps {
+		for _, namespace := range namespaces {
+			sources = append(sources, DiscoverySource{Kind: SourceKindConfigMap, Namespace: namespace})
+		}
+	}
+
+	m.mu.Lock()
+	m.sources = sources
+	m.refreshMetrics = config.Metrics
+	m.mu.Unlock()
+}
+
+// discoverySources returns the configured sources, the secrets of the
+// namespace of the manager by default.
+func (m *DefaultTargetDiscoveryManager) discoverySources() []DiscoverySource {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.discoverySourcesLocked()
+}
+
+func (m *DefaultTargetDiscoveryManager) discoverySourcesLocked() []DiscoverySource {
+	if len(m.sources) == 0 {
+		return []DiscoverySource{{Kind: SourceKindSecret, Namespace: m.namespace}}
+	}
+	return m.sources
+}
+
+// listSource lists the objects of source as secrets, and records the
+// outcome in the refresh metrics.
+func (m *DefaultTargetDiscoveryManager) listSource(ctx context.Context, source DiscoverySource) ([]corev1.Secret, error) {
+	m.mu.RLock()
+	refreshMetrics := m.refreshMetrics
+	m.mu.RUnlock()
+	if refreshMetrics != nil {
+		refreshMetrics.RecordRefreshStart()
+	}
+
+	start := time.Now()
+	var secrets []corev1.Secret
+	var err error
+	if source.Kind == SourceKindConfigMap {
+		var configMaps []corev1.ConfigMap
+		configMaps, err = m.k8sClient.ListConfigMaps(ctx, source.Namespace, m.labelSelector)
+		for _, configMap := range configMaps {
+			secrets = append(secrets, secretFromConfigMap(configMap))
+		}
+	} else {
+		secrets, err = m.k8sClient.ListSecrets(ctx, source.Namespace, m.labelSelector)
+	}
+
+	if refreshMetrics != nil {
+		if err != nil {
+			refreshMetrics.RecordRefreshComplete(source.String(), "failure", time.Since(start))
+			errorType, _ := classifyError(err)
+			refreshMetrics.RecordRefreshError(source.String(), errorType)
+		} else {
+			refreshMetrics.RecordRefreshComplete(source.String(), "success", time.Since(start))
+		}
+	}
+	return secrets, err
+}
+
+// secretFromConfigMap returns the secret of the same name and data as
+// configMap, to parse its targets as those of a secret.
+func secretFromConfigMap(configMap corev1.ConfigMap) corev1.Secret {
+	data := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for key, value := range configMap.BinaryData {
+		data[key] = value
+	}
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+	return corev1.Secret{ObjectMeta: configMap.ObjectMeta, Data: data}
+}
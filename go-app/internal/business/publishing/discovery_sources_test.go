@@ -0,0 +1,120 @@
+package publishing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+func sourceObjectMeta(name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"publishing-target": "true"}}
+}
+
+func sourceTargetConfig(name string) string {
+	return `{"name":"` + name + `","type":"webhook","url":"https://hooks.example.com/` + name + `","format":"webhook"}`
+}
+
+func TestDiscoverTargets_Sources(t *testing.T) {
+	client := newFakeSecretsClient(
+		corev1.Secret{ObjectMeta: sourceObjectMeta("ops", "monitoring"), Data: map[string][]byte{"config": []byte(sourceTargetConfig("ops"))}},
+		corev1.Secret{ObjectMeta: sourceObjectMeta("team-a", "team-a"), Data: map[string][]byte{"config": []byte(sourceTargetConfig("team-a"))}},
+	)
+	client.configMaps = []corev1.ConfigMap{
+		{ObjectMeta: sourceObjectMeta("chat", "team-a"), Data: map[string]string{"config": sourceTargetConfig("chat")}},
+		{ObjectMeta: sourceObjectMeta("ops-copy", "team-a"), Data: map[string]string{"config": sourceTargetConfig("ops")}},
+	}
+	registry := prometheus.NewRegistry()
+	manager, err := NewTargetDiscoveryManager(client, "monitoring", "publishing-target=true", nil, nil)
+	require.NoError(t, err)
+	discovery := manager.(*DefaultTargetDiscoveryManager)
+	discovery.SetSources(DiscoverySourcesConfig{
+		Namespaces: []string{"team-a", "monitoring"},
+		ConfigMaps: true,
+		Metrics:    v2.NewPublishingMetrics(registry),
+	})
+
+	require.NoError(t, discovery.DiscoverTargets(context.Background()))
+	names := make([]string, 0, 3)
+	for _, target := range discovery.ListTargets() {
+		names = append(names, target.Name)
+	}
+	assert.ElementsMatch(t, []string{"ops", "team-a", "chat"}, names, "the duplicate of ops is skipped")
+	ops, err := discovery.GetTarget("ops")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.example.com/ops", ops.URL)
+
+	stats := discovery.GetStats()
+	require.Len(t, stats.Sources, 4)
+	assert.Equal(t, "secret/monitoring", stats.Sources[0].Source)
+	assert.Equal(t, "configmap/team-a", stats.Sources[3].Source)
+	assert.Equal(t, 2, stats.Sources[3].Targets)
+	assert.Equal(t, 4, testutil.CollectAndCount(registry, "alert_history_publishing_refresh_last_success_timestamp"))
+
+	// A failing namespace keeps its targets, the others are refreshed
+	client.failing = map[string]bool{"team-a": true}
+	client.secrets["ops"] = corev1.Secret{ObjectMeta: sourceObjectMeta("ops", "monitoring"), Data: map[string][]byte{
+		"config": []byte(`{"name":"ops","type":"webhook","url":"https://hooks.example.com/v2","format":"webhook"}`),
+	}}
+	err = discovery.DiscoverTargets(context.Background())
+	var failedErr *ErrDiscoveryFailed
+	require.ErrorAs(t, err, &failedErr)
+	assert.Equal(t, "team-a", failedErr.Namespace)
+	assert.Len(t, discovery.ListTargets(), 3, "targets of the failing sources are kept")
+	ops, err = discovery.GetTarget("ops")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.example.com/v2", ops.URL)
+
+	stats = discovery.GetStats()
+	assert.Empty(t, stats.Sources[0].Error)
+	assert.NotEmpty(t, stats.Sources[1].Error)
+	assert.Equal(t, 1, stats.Sources[1].Targets)
+	assert.Equal(t, 2, testutil.CollectAndCount(registry, "alert_history_publishing_refresh_errors_total"))
+}
+
+func TestDiscoverTargets_Watch(t *testing.T) {
+	client := newFakeSecretsClient()
+	client.watcher = watch.NewFake()
+	manager, err := NewTargetDiscoveryManager(client, "monitoring", "publishing-target=true", nil, nil)
+	require.NoError(t, err)
+	discovery := manager.(*DefaultTargetDiscoveryManager)
+	discovery.watchDebounce = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		discovery.Watch(ctx, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	secret := corev1.Secret{ObjectMeta: sourceObjectMeta("hooks", "monitoring"), Data: map[string][]byte{"config": []byte(sourceTargetConfig("hooks"))}}
+	require.NoError(t, client.ApplySecret(ctx, "monitoring", &secret))
+	client.watcher.Add(&secret)
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no discovery after the watch event")
+	}
+	_, err = discovery.GetTarget("hooks")
+	assert.NoError(t, err, "the added target is registered")
+}
@@ -0,0 +1,191 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/k8s"
+)
+
+// Secrets of the targets written through a TargetStore: named after the
+// target and labeled so that they are discovered, and told apart from the
+// secrets deployed with the configuration.
+const (
+	managedTargetSecretPrefix = "amp-target-"
+	managedTargetLabel        = "app.kubernetes.io/managed-by"
+	managedTargetLabelValue   = "amp-api"
+)
+
+var (
+	// ErrTargetExists is returned when creating a target whose name is taken.
+	ErrTargetExists = errors.New("target already exists")
+
+	// ErrTargetNotManaged is returned when updating or deleting a target
+	// that was not created through a TargetStore, e.g. deployed as a secret
+	// with the configuration.
+	ErrTargetNotManaged = errors.New("target is not managed through the API")
+)
+
+// InvalidTargetError reports the validation errors of a target written
+// through a TargetStore.
+type InvalidTargetError struct {
+	Errors []ValidationError
+}
+
+// Error implements error interface.
+func (e *InvalidTargetError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", err.Field, err.Message))
+	}
+	return "invalid target: " + strings.Join(messages, "; ")
+}
+
+// TargetStore creates, updates and deletes publishing targets at runtime.
+//
+// Targets are validated as discovered targets are, then persisted as K8s
+// secrets matching the discovery label selector, so that they survive
+// restarts and reach the other replicas on their next refresh. The cache is
+// refreshed before returning.
+type TargetStore interface {
+	// CreateTarget adds target. Returns ErrTargetExists if its name is
+	// taken, *InvalidTargetError if it is invalid.
+	CreateTarget(ctx context.Context, target *core.PublishingTarget) error
+
+	// UpdateTarget replaces the target of the same name. Returns
+	// *ErrTargetNotFound, ErrTargetNotManaged or *InvalidTargetError.
+	UpdateTarget(ctx context.Context, target *core.PublishingTarget) error
+
+	// DeleteTarget removes target name. Returns *ErrTargetNotFound or
+	// ErrTargetNotManaged.
+	DeleteTarget(ctx context.Context, name string) error
+}
+
+var _ TargetStore = (*DefaultTargetDiscoveryManager)(nil)
+
+// CreateTarget implements TargetStore.
+func (m *DefaultTargetDiscoveryManager) CreateTarget(ctx context.Context, target *core.PublishingTarget) error {
+	if err := checkTarget(target); err != nil {
+		return err
+	}
+	if _, err := m.GetTarget(target.Name); err == nil {
+		return ErrTargetExists
+	}
+	if _, err := m.k8sClient.GetSecret(ctx, m.namespace, managedTargetSecretName(target.Name)); err == nil {
+		return ErrTargetExists
+	}
+	return m.saveTarget(ctx, target)
+}
+
+// UpdateTarget implements TargetStore.
+func (m *DefaultTargetDiscoveryManager) UpdateTarget(ctx context.Context, target *core.PublishingTarget) error {
+	if err := checkTarget(target); err != nil {
+		return err
+	}
+	if err := m.checkManaged(ctx, target.Name); err != nil {
+		return err
+	}
+	return m.saveTarget(ctx, target)
+}
+
+// DeleteTarget implements TargetStore.
+func (m *DefaultTargetDiscoveryManager) DeleteTarget(ctx context.Context, name string) error {
+	if err := m.checkManaged(ctx, name); err != nil {
+		return err
+	}
+	if err := m.k8sClient.DeleteSecret(ctx, m.namespace, managedTargetSecretName(name)); err != nil {
+		return err
+	}
+
+	m.logger.Info("Publishing target deleted", "target_name", name)
+	m.refreshAfterWrite(ctx)
+	return nil
+}
+
+// saveTarget writes the secret of target.
+func (m *DefaultTargetDiscoveryManager) saveTarget(ctx context.Context, target *core.PublishingTarget) error {
+	secretLabels, err := labels.ConvertSelectorToLabelsMap(m.labelSelector)
+	if err != nil {
+		return fmt.Errorf("label selector %q cannot label the secrets of created targets: %w", m.labelSelector, err)
+	}
+	secretLabels[managedTargetLabel] = managedTargetLabelValue
+
+	config, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("failed to encode target: %w", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   managedTargetSecretName(target.Name),
+			Labels: secretLabels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"config": config},
+	}
+	if err := m.k8sClient.ApplySecret(ctx, m.namespace, secret); err != nil {
+		return err
+	}
+
+	m.logger.Info("Publishing target saved",
+		"target_name", target.Name,
+		"type", target.Type,
+		"enabled", target.Enabled,
+	)
+	m.refreshAfterWrite(ctx)
+	return nil
+}
+
+// checkManaged returns nil if target name was created through the store.
+func (m *DefaultTargetDiscoveryManager) checkManaged(ctx context.Context, name string) error {
+	secret, err := m.k8sClient.GetSecret(ctx, m.namespace, managedTargetSecretName(name))
+	var notFoundErr *k8s.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		if _, err := m.GetTarget(name); err == nil {
+			return ErrTargetNotManaged // discovered from another secret
+		}
+		return NewTargetNotFoundError(name)
+	}
+	if err != nil {
+		return err
+	}
+	if secret.Labels[managedTargetLabel] != managedTargetLabelValue {
+		return ErrTargetNotManaged
+	}
+	return nil
+}
+
+// refreshAfterWrite rediscovers the targets once a secret is written. The
+// write succeeded: a failed discovery only delays the change until the next
+// refresh.
+func (m *DefaultTargetDiscoveryManager) refreshAfterWrite(ctx context.Context) {
+	if err := m.DiscoverTargets(ctx); err != nil {
+		m.logger.Warn("Target discovery after write failed, change applies on next refresh", "error", err)
+	}
+}
+
+// checkTarget applies the defaults of discovered targets and validates target.
+func checkTarget(target *core.PublishingTarget) error {
+	if target.Headers == nil {
+		target.Headers = make(map[string]string)
+	}
+	if target.FilterConfig == nil {
+		target.FilterConfig = make(map[string]any)
+	}
+	if errs := validateTarget(target); len(errs) > 0 {
+		return &InvalidTargetError{Errors: errs}
+	}
+	return nil
+}
+
+// managedTargetSecretName returns the secret name of target name.
+func managedTargetSecretName(name string) string {
+	return managedTargetSecretPrefix + name
+}
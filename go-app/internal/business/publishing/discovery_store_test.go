@@ -0,0 +1,208 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/k8s"
+)
+
+// fakeSecretsClient keeps secrets and config maps in memory, filtered by
+// namespace (when set) and label selector.
+type fakeSecretsClient struct {
+	mu         sync.Mutex
+	secrets    map[string]corev1.Secret
+	configMaps []corev1.ConfigMap
+	failing    map[string]bool // namespaces whose listing fails
+	watcher    *watch.FakeWatcher
+}
+
+func newFakeSecretsClient(secrets ...corev1.Secret) *fakeSecretsClient {
+	c := &fakeSecretsClient{secrets: make(map[string]corev1.Secret)}
+	for _, secret := range secrets {
+		c.secrets[secret.Name] = secret
+	}
+	return c
+}
+
+func (c *fakeSecretsClient) ListSecrets(_ context.Context, namespace string, labelSelector string) ([]corev1.Secret, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failing[namespace] {
+		return nil, k8s.NewConnectionError("K8s API unavailable", nil)
+	}
+	var secrets []corev1.Secret
+	for _, secret := range c.secrets {
+		if (secret.Namespace == "" || secret.Namespace == namespace) && selector.Matches(labels.Set(secret.Labels)) {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, nil
+}
+
+func (c *fakeSecretsClient) GetSecret(_ context.Context, namespace, name string) (*corev1.Secret, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	secret, ok := c.secrets[name]
+	if !ok {
+		return nil, k8s.NewNotFoundError(fmt.Sprintf("secret %s/%s not found", namespace, name))
+	}
+	return &secret, nil
+}
+
+func (c *fakeSecretsClient) ApplySecret(_ context.Context, _ string, secret *corev1.Secret) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.secrets[secret.Name] = *secret.DeepCopy()
+	return nil
+}
+
+func (c *fakeSecretsClient) DeleteSecret(_ context.Context, namespace, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.secrets[name]; !ok {
+		return k8s.NewNotFoundError(fmt.Sprintf("secret %s/%s not found", namespace, name))
+	}
+	delete(c.secrets, name)
+	return nil
+}
+
+func (c *fakeSecretsClient) ListConfigMaps(_ context.Context, namespace string, labelSelector string) ([]corev1.ConfigMap, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failing[namespace] {
+		return nil, k8s.NewConnectionError("K8s API unavailable", nil)
+	}
+	var configMaps []corev1.ConfigMap
+	for _, configMap := range c.configMaps {
+		if configMap.Namespace == namespace && selector.Matches(labels.Set(configMap.Labels)) {
+			configMaps = append(configMaps, configMap)
+		}
+	}
+	return configMaps, nil
+}
+
+func (c *fakeSecretsClient) WatchSecrets(context.Context, string, string) (watch.Interface, error) {
+	if c.watcher != nil {
+		return c.watcher, nil
+	}
+	return watch.NewFake(), nil
+}
+
+func (c *fakeSecretsClient) WatchConfigMaps(context.Context, string, string) (watch.Interface, error) {
+	return watch.NewFake(), nil
+}
+
+func (c *fakeSecretsClient) Health(context.Context) error { return nil }
+func (c *fakeSecretsClient) Close() error                 { return nil }
+
+func newTestTargetStore(t *testing.T, secrets ...corev1.Secret) (*DefaultTargetDiscoveryManager, *fakeSecretsClient) {
+	t.Helper()
+	client := newFakeSecretsClient(secrets...)
+	manager, err := NewTargetDiscoveryManager(client, "monitoring", "publishing-target=true", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.DiscoverTargets(context.Background()))
+	return manager.(*DefaultTargetDiscoveryManager), client
+}
+
+func TestTargetStore_CreateUpdateDelete(t *testing.T) {
+	store, client := newTestTargetStore(t)
+	ctx := context.Background()
+
+	target := &core.PublishingTarget{
+		Name:    "hooks",
+		Type:    "webhook",
+		URL:     "https://hooks.example.com/alerts",
+		Format:  core.FormatWebhook,
+		Enabled: true,
+	}
+	require.NoError(t, store.CreateTarget(ctx, target))
+
+	secret, err := client.GetSecret(ctx, "monitoring", "amp-target-hooks")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"publishing-target": "true", managedTargetLabel: managedTargetLabelValue}, secret.Labels)
+	discovered, err := store.GetTarget("hooks")
+	require.NoError(t, err, "the cache is refreshed")
+	assert.Equal(t, target.URL, discovered.URL)
+
+	assert.ErrorIs(t, store.CreateTarget(ctx, target), ErrTargetExists)
+
+	updated := *target
+	updated.Enabled = false
+	updated.Headers = map[string]string{"X-Team": "sre"}
+	require.NoError(t, store.UpdateTarget(ctx, &updated))
+	discovered, err = store.GetTarget("hooks")
+	require.NoError(t, err)
+	assert.False(t, discovered.Enabled)
+	assert.Equal(t, "sre", discovered.Headers["X-Team"])
+
+	require.NoError(t, store.DeleteTarget(ctx, "hooks"))
+	_, err = store.GetTarget("hooks")
+	assert.Error(t, err)
+
+	var notFoundErr *ErrTargetNotFound
+	assert.ErrorAs(t, store.DeleteTarget(ctx, "hooks"), &notFoundErr)
+	assert.ErrorAs(t, store.UpdateTarget(ctx, target), &notFoundErr)
+}
+
+func TestTargetStore_Validation(t *testing.T) {
+	store, client := newTestTargetStore(t)
+
+	err := store.CreateTarget(context.Background(), &core.PublishingTarget{
+		Name:   "pager",
+		Type:   "pagerduty",
+		URL:    "not-a-url",
+		Format: core.FormatSlack,
+	})
+	var invalidErr *InvalidTargetError
+	require.ErrorAs(t, err, &invalidErr)
+	fields := make([]string, 0, len(invalidErr.Errors))
+	for _, validationErr := range invalidErr.Errors {
+		fields = append(fields, validationErr.Field)
+	}
+	assert.Contains(t, fields, "url")
+	assert.Contains(t, fields, "format")
+	assert.Empty(t, client.secrets, "invalid targets are not persisted")
+}
+
+func TestTargetStore_ConfiguredTargetsAreReadOnly(t *testing.T) {
+	configured := core.PublishingTarget{
+		Name:    "rootly-prod",
+		Type:    "rootly",
+		URL:     "https://api.rootly.io/v1/incidents",
+		Format:  core.FormatRootly,
+		Enabled: true,
+	}
+	config, err := json.Marshal(configured)
+	require.NoError(t, err)
+	store, _ := newTestTargetStore(t, corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rootly-prod", Labels: map[string]string{"publishing-target": "true"}},
+		Data:       map[string][]byte{"config": config},
+	})
+	ctx := context.Background()
+
+	assert.ErrorIs(t, store.CreateTarget(ctx, &configured), ErrTargetExists)
+	assert.ErrorIs(t, store.UpdateTarget(ctx, &configured), ErrTargetNotManaged)
+	assert.ErrorIs(t, store.DeleteTarget(ctx, "rootly-prod"), ErrTargetNotManaged)
+	_, err = store.GetTarget("rootly-prod")
+	assert.NoError(t, err)
+}
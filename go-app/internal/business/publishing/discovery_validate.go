@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	infrapublishing "github.com/ipiton/AMP/internal/infrastructure/publishing"
 )
 
 // Target name pattern: alphanumeric + hyphens, 1-63 chars (K8s DNS-1123 subdomain)
@@ -16,11 +18,17 @@ var targetNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
 // Validation Rules:
 //  1. Required fields: name, type, url, format
 //  2. Name: alphanumeric + hyphens, 1-63 chars (DNS-1123 compliant)
-//  3. Type: one of [rootly, pagerduty, slack, webhook]
-//  4. URL: valid HTTP/HTTPS URL
-//  5. Format: one of [alertmanager, rootly, pagerduty, slack, webhook]
+//  3. Type: one of [rootly, pagerduty, slack, webhook, teams, opsgenie, email, kafka, jira, googlechat, mattermost, aws]
+//  4. URL: valid HTTP/HTTPS URL (SMTP/SMTPS URL for email, SNS topic ARN
+//     or SQS queue URL for aws)
+//  5. Format: one of [alertmanager, rootly, pagerduty, slack, webhook, teams, opsgenie, email, kafka, jira, googlechat, mattermost, aws]
 //  6. Type-Format compatibility (e.g., type=rootly requires format=rootly)
 //  7. Headers: no empty keys/values
+//  8. Grouping override: group_wait/group_interval headers are durations
+//  9. Severity styles: emoji_/color_/card_color_<severity> headers name a
+//     known severity and hold UTF-8 emoji, #RRGGBB colors and Adaptive Card colors
+//  10. Batching: batch_max_size/batch_flush_interval headers are in range,
+//     on webhook and alertmanager targets only
 //
 // Returns:
 //   - Empty slice if valid
@@ -74,18 +82,50 @@ func validateTarget(target *core.PublishingTarget) []ValidationError {
 	} else if !isValidTargetType(target.Type) {
 		errors = append(errors, NewValidationError(
 			"type",
-			"must be one of: rootly, pagerduty, slack, webhook",
+			"must be one of: rootly, pagerduty, slack, webhook, teams, opsgenie, email, kafka, jira, googlechat, mattermost, aws, plugin, exec",
 			target.Type,
 		))
 	}
 
-	// Validate URL (required, valid HTTP/HTTPS)
+	// Validate URL (required, valid HTTP/HTTPS, SMTP/SMTPS for email, ARN or queue URL for aws, any URL for plugin, exec://<command> for exec)
 	if target.URL == "" {
 		errors = append(errors, NewValidationError(
 			"url",
 			"field is required",
 			target.URL,
 		))
+	} else if target.Type == "email" {
+		if !isValidSMTPURL(target.URL) {
+			errors = append(errors, NewValidationError(
+				"url",
+				"must be valid SMTP or SMTPS URL (smtp://host:port)",
+				target.URL,
+			))
+		}
+	} else if target.Type == "aws" {
+		if err := infrapublishing.ValidateAWSTarget(target); err != nil {
+			errors = append(errors, NewValidationError(
+				"url",
+				err.Error(),
+				target.URL,
+			))
+		}
+	} else if target.Type == "plugin" {
+		if err := infrapublishing.ValidatePluginTarget(target); err != nil {
+			errors = append(errors, NewValidationError(
+				"url",
+				err.Error(),
+				target.URL,
+			))
+		}
+	} else if target.Type == "exec" {
+		if err := infrapublishing.ValidateExecTarget(target); err != nil {
+			errors = append(errors, NewValidationError(
+				"url",
+				err.Error(),
+				target.URL,
+			))
+		}
 	} else if !isValidURL(target.URL) {
 		errors = append(errors, NewValidationError(
 			"url",
@@ -104,7 +144,7 @@ func validateTarget(target *core.PublishingTarget) []ValidationError {
 	} else if !isValidFormat(string(target.Format)) {
 		errors = append(errors, NewValidationError(
 			"format",
-			"must be one of: alertmanager, rootly, pagerduty, slack, webhook",
+			"must be one of: alertmanager, rootly, pagerduty, slack, webhook, teams, opsgenie, email, kafka, jira, googlechat, mattermost, aws, plugin, exec",
 			string(target.Format),
 		))
 	}
@@ -138,6 +178,118 @@ func validateTarget(target *core.PublishingTarget) []ValidationError {
 		}
 	}
 
+	// Validate grouping override timers (group_by re-groups the target's alerts)
+	for _, key := range []string{"group_wait", "group_interval"} {
+		if value, ok := target.Headers[key]; ok && value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				errors = append(errors, NewValidationError(
+					"headers",
+					fmt.Sprintf("%s must be a non-negative duration (e.g. 30s)", key),
+					value,
+				))
+			}
+		}
+	}
+
+	// Validate the payload template
+	if err := infrapublishing.ValidateTargetPayloadTemplate(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate batching (one request per batch of alerts)
+	if err := infrapublishing.ValidateTargetBatching(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the rate and concurrency limits
+	if err := infrapublishing.ValidateTargetLimits(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the circuit breaker overrides
+	if err := infrapublishing.ValidateTargetCircuitBreaker(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the fallback chain
+	if err := infrapublishing.ValidateTargetFallback(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the notification schedule (notify_after, repeat_interval)
+	if err := infrapublishing.ValidateTargetSchedule(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the publish deduplication window
+	if err := infrapublishing.ValidateTargetDedupWindow(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the redaction (denylists, scrubbers)
+	if err := infrapublishing.ValidateTargetRedaction(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the outbound HTTP options (proxy, TLS)
+	if err := infrapublishing.ValidateTargetHTTPConfig(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate the Slack Web API options (a bot token needs a channel)
+	if err := infrapublishing.ValidateSlackTarget(target); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
+	// Validate severity style overrides (chat emoji and colors)
+	if _, err := infrapublishing.TargetSeverityStyles(target.Headers); err != nil {
+		errors = append(errors, NewValidationError(
+			"headers",
+			err.Error(),
+			"",
+		))
+	}
+
 	return errors
 }
 
@@ -170,11 +322,21 @@ func isValidTargetName(name string) bool {
 //   - pagerduty: PagerDuty incident response
 //   - slack: Slack messaging
 //   - webhook: Generic webhook (any endpoint)
+//   - teams: Microsoft Teams messaging
+//   - opsgenie: Opsgenie alerting
+//   - email: SMTP email
+//   - kafka: Kafka topic (through a Kafka REST Proxy)
+//   - jira: JIRA issues
+//   - googlechat: Google Chat spaces
+//   - mattermost: Mattermost channels
+//   - aws: Amazon SNS topic or SQS queue
+//   - plugin: external publisher plugin
+//   - exec: local command
 //
 // Case-sensitive: Must be lowercase.
 func isValidTargetType(targetType string) bool {
 	switch targetType {
-	case "rootly", "pagerduty", "slack", "webhook":
+	case "rootly", "pagerduty", "slack", "webhook", "teams", "opsgenie", "email", "kafka", "jira", "googlechat", "mattermost", "aws", "plugin", "exec":
 		return true
 	default:
 		return false
@@ -189,11 +351,21 @@ func isValidTargetType(targetType string) bool {
 //   - pagerduty: PagerDuty Events API v2 format
 //   - slack: Slack Incoming Webhook format
 //   - webhook: Generic JSON webhook
+//   - teams: Microsoft Teams Adaptive Card message
+//   - opsgenie: Opsgenie Alert API v2 create request
+//   - email: HTML + text email rendered from templates
+//   - kafka: alert event (JSON or Avro record)
+//   - jira: JIRA REST API v2 create issue request
+//   - googlechat: Google Chat message with a card (cards v2)
+//   - mattermost: Mattermost message with an attachment
+//   - aws: alert event (JSON) as the SNS/SQS message body
+//   - plugin: enriched alert passed to the plugin as is
+//   - exec: alert event (JSON) on the command's stdin
 //
 // Case-sensitive: Must be lowercase.
 func isValidFormat(format string) bool {
 	switch format {
-	case "alertmanager", "rootly", "pagerduty", "slack", "webhook":
+	case "alertmanager", "rootly", "pagerduty", "slack", "webhook", "teams", "opsgenie", "email", "kafka", "jira", "googlechat", "mattermost", "aws", "plugin", "exec":
 		return true
 	default:
 		return false
@@ -232,6 +404,23 @@ func isValidURL(rawURL string) bool {
 	return true
 }
 
+// isValidSMTPURL checks if URL is a valid SMTP server URL.
+//
+// Rules:
+//   - Must start with smtp:// (STARTTLS/plain) or smtps:// (implicit TLS)
+//   - Must have valid host; port optional
+//
+// Examples:
+//   - Valid: "smtp://smtp.example.com:587", "smtps://smtp.example.com"
+//   - Invalid: "https://smtp.example.com", "smtp.example.com:587"
+func isValidSMTPURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "smtp" || u.Scheme == "smtps") && u.Hostname() != ""
+}
+
 // isCompatibleTypeFormat checks type-format compatibility.
 //
 // Compatibility Matrix:
@@ -242,15 +431,25 @@ func isValidURL(rawURL string) bool {
 //	| pagerduty  | pagerduty                     | Strict: PagerDuty Events API   |
 //	| slack      | slack                         | Strict: Slack webhook only     |
 //	| webhook    | alertmanager, webhook         | Flexible: any generic format   |
+//	| teams      | teams                         | Strict: Adaptive Card message  |
+//	| opsgenie   | opsgenie                      | Strict: Opsgenie Alert API     |
+//	| email      | email                         | Strict: SMTP email             |
+//	| kafka      | kafka                         | Strict: alert event record     |
+//	| jira       | jira                          | Strict: JIRA REST API v2       |
+//	| googlechat | googlechat                    | Strict: Google Chat card       |
+//	| mattermost | mattermost                    | Strict: Mattermost attachment  |
+//	| aws        | aws                           | Strict: alert event message    |
+//	| exec       | exec, alertmanager, webhook   | Flexible: JSON on stdin        |
 //
-// Why strict for rootly/pagerduty/slack?
+// Why strict for rootly/pagerduty/slack/teams/opsgenie/email/kafka/jira/googlechat/mattermost/aws/plugin?
 //   - These have specific API contracts (payload structure)
 //   - Using wrong format would cause API errors
 //
-// Why flexible for webhook?
+// Why flexible for webhook and exec?
 //   - Generic webhooks accept various formats
 //   - Alertmanager format is common standard
 //   - Custom webhook format for non-standard endpoints
+//   - Scripts of exec targets parse whichever JSON payload they expect
 //
 // Example:
 //
@@ -259,10 +458,20 @@ func isValidURL(rawURL string) bool {
 //	isCompatibleTypeFormat("webhook", "alertmanager") // true (flexible)
 func isCompatibleTypeFormat(targetType, format string) bool {
 	compatibilityMap := map[string][]string{
-		"rootly":    {"rootly"},
-		"pagerduty": {"pagerduty"},
-		"slack":     {"slack"},
-		"webhook":   {"alertmanager", "webhook"}, // webhooks are flexible
+		"rootly":     {"rootly"},
+		"pagerduty":  {"pagerduty"},
+		"slack":      {"slack"},
+		"webhook":    {"alertmanager", "webhook"}, // webhooks are flexible
+		"teams":      {"teams"},
+		"opsgenie":   {"opsgenie"},
+		"email":      {"email"},
+		"kafka":      {"kafka"},
+		"jira":       {"jira"},
+		"googlechat": {"googlechat"},
+		"mattermost": {"mattermost"},
+		"aws":        {"aws"},
+		"plugin":     {"plugin"},
+		"exec":       {"exec", "alertmanager", "webhook"}, // any JSON payload for scripts
 	}
 
 	allowedFormats, ok := compatibilityMap[targetType]
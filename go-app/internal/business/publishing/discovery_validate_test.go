@@ -182,6 +182,148 @@ func TestValidateTarget_InvalidURL(t *testing.T) {
 	}
 }
 
+func TestValidateTarget_EmailURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"smtp://smtp.example.com:587", true},
+		{"smtps://smtp.example.com", true},
+		{"https://smtp.example.com", false},
+		{"smtp.example.com:587", false},
+		{"smtp://", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:   "test-target",
+				Type:   "email",
+				URL:    tt.url,
+				Format: core.FormatEmail,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else {
+				if assert.Len(t, errors, 1) {
+					assert.Equal(t, "url", errors[0].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTarget_AWSURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"arn:aws:sns:eu-west-1:123456789012:alerts", true},
+		{"arn:aws:sns:eu-west-1:123456789012:alerts.fifo", true},
+		{"https://sqs.eu-west-1.amazonaws.com/123456789012/alerts", true},
+		{"arn:aws:sqs:eu-west-1:123456789012:alerts", false},
+		{"arn:aws:sns:eu-west-1:123456789012", false},
+		{"sqs.eu-west-1.amazonaws.com/123456789012/alerts", false},
+		{"https://sqs.eu-west-1.amazonaws.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:   "test-target",
+				Type:   "aws",
+				URL:    tt.url,
+				Format: core.FormatAWS,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else {
+				if assert.Len(t, errors, 1) {
+					assert.Equal(t, "url", errors[0].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTarget_Plugin(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		plugin string
+		valid  bool
+	}{
+		{"custom scheme", "pager://oncall/primary", "pager", true},
+		{"missing plugin header", "pager://oncall/primary", "", false},
+		{"relative url", "oncall/primary", "pager", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "plugin",
+				URL:     tt.url,
+				Format:  core.FormatPlugin,
+				Headers: map[string]string{},
+			}
+			if tt.plugin != "" {
+				target.Headers["plugin"] = tt.plugin
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else {
+				if assert.Len(t, errors, 1) {
+					assert.Equal(t, "url", errors[0].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTarget_Exec(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		format core.PublishingFormat
+		valid  bool
+		field  string
+	}{
+		{"alert event", "exec://ticket-script", core.FormatExec, true, ""},
+		{"alertmanager payload", "exec://ticket-script", core.FormatAlertmanager, true, ""},
+		{"http url", "https://example.com/hook", core.FormatExec, false, "url"},
+		{"missing command", "exec://", core.FormatExec, false, "url"},
+		{"invalid command name", "exec://Ticket_Script", core.FormatExec, false, "url"},
+		{"incompatible format", "exec://ticket-script", core.FormatSlack, false, "format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:   "test-target",
+				Type:   "exec",
+				URL:    tt.url,
+				Format: tt.format,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else {
+				if assert.Len(t, errors, 1) {
+					assert.Equal(t, tt.field, errors[0].Field)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateTarget_MissingFormat(t *testing.T) {
 	target := &core.PublishingTarget{
 		Name:   "test-target",
@@ -229,6 +371,20 @@ func TestValidateTarget_TypeFormatMismatch(t *testing.T) {
 		{"webhook/alertmanager", "webhook", "alertmanager", true},
 		{"webhook/webhook", "webhook", "webhook", true},
 		{"webhook/rootly", "webhook", "rootly", false},
+		{"teams/teams", "teams", "teams", true},
+		{"teams/slack", "teams", "slack", false},
+		{"opsgenie/opsgenie", "opsgenie", "opsgenie", true},
+		{"opsgenie/webhook", "opsgenie", "webhook", false},
+		{"email/webhook", "email", "webhook", false},
+		{"kafka/kafka", "kafka", "kafka", true},
+		{"kafka/webhook", "kafka", "webhook", false},
+		{"jira/jira", "jira", "jira", true},
+		{"jira/webhook", "jira", "webhook", false},
+		{"googlechat/googlechat", "googlechat", "googlechat", true},
+		{"googlechat/slack", "googlechat", "slack", false},
+		{"mattermost/mattermost", "mattermost", "mattermost", true},
+		{"mattermost/slack", "mattermost", "slack", false},
+		{"aws/kafka", "aws", "kafka", false},
 	}
 
 	for _, tt := range tests {
@@ -306,6 +462,272 @@ func TestValidateTarget_EmptyHeaderValue(t *testing.T) {
 	assert.True(t, found)
 }
 
+func TestValidateTarget_GroupingHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		valid   bool
+	}{
+		{"per-service grouping", map[string]string{"group_by": "service", "group_wait": "10s", "group_interval": "2m"}, true},
+		{"immediate per-alert", map[string]string{"group_by": "...", "group_wait": "0s"}, true},
+		{"invalid group_wait", map[string]string{"group_by": "service", "group_wait": "soon"}, false},
+		{"negative group_interval", map[string]string{"group_by": "service", "group_interval": "-1m"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "webhook",
+				URL:     "https://example.com",
+				Format:  "webhook",
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestValidateTarget_PayloadTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		targetType string
+		template   string
+		valid      bool
+	}{
+		{"webhook", "webhook", `{"text": {{ .Alert.AlertName | toJson }}}`, true},
+		{"syntax error", "webhook", `{"text": {{ .Alert.AlertName }`, false},
+		{"unsupported type", "slack", `{"text": {{ .Alert.AlertName | toJson }}}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    tt.targetType,
+				URL:     "https://example.com",
+				Format:  core.PublishingFormat(tt.targetType),
+				Headers: map[string]string{"payload_template": tt.template},
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+				assert.Contains(t, errors[0].Message, "payload_template")
+			}
+		})
+	}
+}
+
+func TestValidateTarget_Redaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		valid   bool
+	}{
+		{"denylists and scrubbers", map[string]string{"redact_labels": "pod_ip, secret_*", "redact_annotations": "runbook_token", "redact_scrubbers": "tokens,emails,ips"}, true},
+		{"pattern", map[string]string{"redact_pattern": `acct-[0-9]+`}, true},
+		{"unknown scrubber", map[string]string{"redact_scrubbers": "tokens,phones"}, false},
+		{"invalid pattern", map[string]string{"redact_pattern": `acct-[0-9`}, false},
+		{"invalid glob", map[string]string{"redact_labels": "secret_["}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "webhook",
+				URL:     "https://example.com",
+				Format:  core.FormatWebhook,
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+				assert.Contains(t, errors[0].Message, "redact_")
+			}
+		})
+	}
+}
+
+func TestValidateTarget_OutboundHTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"proxy and mTLS", map[string]string{"proxy_url": "http://proxy.corp:3128", "tls_ca_file": "/etc/amp/ca.crt", "tls_cert_file": "/etc/amp/tls.crt", "tls_key_file": "/etc/amp/tls.key", "tls_min_version": "TLS13"}, ""},
+		{"invalid proxy", map[string]string{"proxy_url": "proxy.corp:3128"}, "proxy_url"},
+		{"certificate without key", map[string]string{"tls_cert_file": "/etc/amp/tls.crt"}, "tls_key_file"},
+		{"invalid min version", map[string]string{"tls_min_version": "SSL3"}, "tls_min_version"},
+		{"invalid insecure skip verify", map[string]string{"tls_insecure_skip_verify": "maybe"}, "tls_insecure_skip_verify"},
+		{"oauth2", map[string]string{"oauth2_token_url": "https://idp.corp/token", "oauth2_client_id": "amp", "oauth2_client_secret": "secret", "oauth2_scopes": "alerts.write"}, ""},
+		{"oauth2 without secret", map[string]string{"oauth2_token_url": "https://idp.corp/token", "oauth2_client_id": "amp"}, "oauth2_client_secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "webhook",
+				URL:     "https://example.com",
+				Format:  core.FormatWebhook,
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.want == "" {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+				assert.Contains(t, errors[0].Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTarget_Batching(t *testing.T) {
+	tests := []struct {
+		name       string
+		targetType string
+		format     core.PublishingFormat
+		headers    map[string]string
+		valid      bool
+	}{
+		{"webhook", "webhook", core.FormatWebhook, map[string]string{"batch_max_size": "50", "batch_flush_interval": "10s"}, true},
+		{"alertmanager format", "webhook", core.FormatAlertmanager, map[string]string{"batch_flush_interval": "2s"}, true},
+		{"size too large", "webhook", core.FormatWebhook, map[string]string{"batch_max_size": "5000"}, false},
+		{"invalid interval", "webhook", core.FormatWebhook, map[string]string{"batch_flush_interval": "soon"}, false},
+		{"unsupported type", "slack", core.FormatSlack, map[string]string{"batch_max_size": "10"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    tt.targetType,
+				URL:     "https://example.com",
+				Format:  tt.format,
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+				assert.Contains(t, errors[0].Message, "batch_")
+			}
+		})
+	}
+}
+
+func TestValidateTarget_Limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		valid   bool
+	}{
+		{"rate and concurrency", map[string]string{"rate_limit": "0.5", "rate_burst": "3", "max_concurrency": "2"}, true},
+		{"invalid rate", map[string]string{"rate_limit": "fast"}, false},
+		{"zero burst", map[string]string{"rate_burst": "0"}, false},
+		{"negative concurrency", map[string]string{"max_concurrency": "-1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "slack",
+				URL:     "https://hooks.slack.com/services/T/B/X",
+				Format:  core.FormatSlack,
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestValidateTarget_Schedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		valid   bool
+	}{
+		{"notify after and repeat", map[string]string{"notify_after": "10m", "repeat_interval": "4h"}, true},
+		{"repeat disabled", map[string]string{"repeat_interval": "0"}, true},
+		{"invalid delay", map[string]string{"notify_after": "later"}, false},
+		{"repeat too short", map[string]string{"repeat_interval": "10s"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "webhook",
+				URL:     "https://example.com",
+				Format:  core.FormatWebhook,
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestValidateTarget_SlackBotToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		valid   bool
+	}{
+		{"incoming webhook", nil, true},
+		{"bot token and channel", map[string]string{"Authorization": "Bearer xoxb-test", "channel": "#alerts"}, true},
+		{"bot token without channel", map[string]string{"Authorization": "Bearer xoxb-test"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "slack",
+				URL:     "https://slack.com/api",
+				Format:  core.FormatSlack,
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+			}
+		})
+	}
+}
+
 func TestIsValidTargetName(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -343,6 +765,14 @@ func TestIsValidTargetType(t *testing.T) {
 		{"pagerduty", "pagerduty", true},
 		{"slack", "slack", true},
 		{"webhook", "webhook", true},
+		{"teams", "teams", true},
+		{"opsgenie", "opsgenie", true},
+		{"email", "email", true},
+		{"kafka", "kafka", true},
+		{"jira", "jira", true},
+		{"googlechat", "googlechat", true},
+		{"mattermost", "mattermost", true},
+		{"aws", "aws", true},
 		{"invalid", "invalid", false},
 		{"uppercase", "ROOTLY", false},
 		{"empty", "", false},
@@ -367,6 +797,14 @@ func TestIsValidFormat(t *testing.T) {
 		{"pagerduty", "pagerduty", true},
 		{"slack", "slack", true},
 		{"webhook", "webhook", true},
+		{"teams", "teams", true},
+		{"opsgenie", "opsgenie", true},
+		{"email", "email", true},
+		{"kafka", "kafka", true},
+		{"jira", "jira", true},
+		{"googlechat", "googlechat", true},
+		{"mattermost", "mattermost", true},
+		{"aws", "aws", true},
 		{"invalid", "invalid", false},
 		{"uppercase", "ALERTMANAGER", false},
 		{"empty", "", false},
@@ -432,3 +870,36 @@ func TestIsCompatibleTypeFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTarget_SeverityStyleHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		valid   bool
+	}{
+		{"emoji and colors", map[string]string{"emoji_critical": "\U0001f6a8", "color_info": "#439FE0", "card_color_resolved": "good"}, true},
+		{"unknown severity", map[string]string{"emoji_page": "\U0001f6a8"}, false},
+		{"invalid color", map[string]string{"color_warning": "orange"}, false},
+		{"invalid card color", map[string]string{"card_color_critical": "red"}, false},
+		{"double-encoded emoji", map[string]string{"emoji_info": "\u00e2\u201e\u00b9\u00ef\u00b8\u008f"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{
+				Name:    "test-target",
+				Type:    "slack",
+				URL:     "https://hooks.slack.com/services/T/B/X",
+				Format:  "slack",
+				Headers: tt.headers,
+			}
+
+			errors := validateTarget(target)
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else if assert.Len(t, errors, 1) {
+				assert.Equal(t, "headers", errors[0].Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package publishing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Watch timing: changes are rediscovered watchDebounce after the first
+// event of a burst; a watch that cannot be established or ends early is
+// re-established after a backoff between watchMinBackoff and
+// watchMaxBackoff.
+const (
+	watchDebounce   = time.Second
+	watchMinBackoff = time.Second
+	watchMaxBackoff = time.Minute
+)
+
+// TargetWatcher is implemented by discovery managers that can rediscover
+// their targets as soon as they change.
+type TargetWatcher interface {
+	Watch(ctx context.Context, onChange func())
+}
+
+var _ TargetWatcher = (*DefaultTargetDiscoveryManager)(nil)
+
+// Watch rediscovers the targets whenever an object of a source is added,
+// modified or deleted, then calls onChange (optional), until ctx is done.
+//
+// Watching makes changes apply within seconds rather than at the next
+// periodic refresh, which still runs to recover from missed events. A burst
+// of events triggers a single discovery. A watch closed by the API server
+// is re-established, and the targets rediscovered since events may have
+// been missed meanwhile.
+func (m *DefaultTargetDiscoveryManager) Watch(ctx context.Context, onChange func()) {
+	changed := make(chan struct{}, 1)
+	var wg sync.WaitGroup
+	for _, source := range m.discoverySources() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.watchSource(ctx, source, changed)
+		}()
+	}
+	defer wg.Wait()
+
+	debounce := m.watchDebounce
+	if debounce <= 0 {
+		debounce = watchDebounce
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+
+		timer := time.NewTimer(debounce)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		select {
+		case <-changed: // coalesced into this discovery
+		default:
+		}
+
+		if err := m.DiscoverTargets(ctx); err != nil {
+			m.logger.Warn("Target discovery after watch event failed", "error", err)
+		}
+		if onChange != nil {
+			onChange()
+		}
+	}
+}
+
+// watchSource signals changed on every change of the objects of source,
+// until ctx is done.
+func (m *DefaultTargetDiscoveryManager) watchSource(ctx context.Context, source DiscoverySource, changed chan<- struct{}) {
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default: // a discovery is already pending
+		}
+	}
+
+	backoff := watchMinBackoff
+	for ctx.Err() == nil {
+		established := time.Now()
+		watcher, err := m.watchObjects(ctx, source)
+		if err != nil {
+			m.logger.Warn("Failed to watch target source, retrying",
+				"source", source.String(),
+				"backoff", backoff,
+				"error", err,
+			)
+		} else {
+			m.logger.Debug("Watching target source", "source", source.String())
+			m.drainWatch(ctx, source, watcher, notify)
+			if ctx.Err() != nil {
+				return
+			}
+			notify() // events may have been missed until the watch is back
+		}
+
+		// A watch that lasted resets the backoff
+		if err == nil && time.Since(established) > watchMaxBackoff {
+			backoff = watchMinBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, watchMaxBackoff)
+	}
+}
+
+// drainWatch calls notify on every change until watcher ends or ctx is done.
+func (m *DefaultTargetDiscoveryManager) drainWatch(ctx context.Context, source DiscoverySource, watcher watch.Interface, notify func()) {
+	defer watcher.Stop()
+	for {
+		var event watch.Event
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok = <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+		}
+
+		switch event.Type {
+		case watch.Added, watch.Modified, watch.Deleted:
+			notify()
+		case watch.Error:
+			m.logger.Warn("Target source watch failed",
+				"source", source.String(),
+				"error", k8serrors.FromObject(event.Object),
+			)
+			return
+		}
+	}
+}
+
+func (m *DefaultTargetDiscoveryManager) watchObjects(ctx context.Context, source DiscoverySource) (watch.Interface, error) {
+	if source.Kind == SourceKindConfigMap {
+		return m.k8sClient.WatchConfigMaps(ctx, source.Namespace, m.labelSelector)
+	}
+	return m.k8sClient.WatchSecrets(ctx, source.Namespace, m.labelSelector)
+}
@@ -296,6 +296,12 @@ func (m *DefaultHealthMonitor) GetStats(ctx context.Context) (*HealthStats, erro
 	return stats, nil
 }
 
+// ForgetTarget drops the cached health status of a target removed from
+// discovery. GetHealth already hides such orphaned entries; this frees them.
+func (m *DefaultHealthMonitor) ForgetTarget(name string) {
+	m.statusCache.Delete(name)
+}
+
 // runHealthCheckWorker is background goroutine for periodic checks.
 //
 // This worker:
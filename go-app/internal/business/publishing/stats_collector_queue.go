@@ -87,6 +87,7 @@ func NewQueueMetricsCollector(queue *publishing.PublishingQueue) *QueueMetricsCo
 //	//   "jobs_submitted_total": 12345.0,
 //	//   "jobs_completed_total": 12200.0,
 //	//   "jobs_failed_total": 100.0,
+//	//   "jobs_shed_total": 0.0,
 //	// }
 func (c *QueueMetricsCollector) Collect(ctx context.Context) (map[string]float64, error) {
 	if c.queue == nil {
@@ -130,6 +131,7 @@ func (c *QueueMetricsCollector) Collect(ctx context.Context) (map[string]float64
 	metrics["jobs_submitted_total"] = float64(stats.TotalSubmitted)
 	metrics["jobs_completed_total"] = float64(stats.TotalCompleted)
 	metrics["jobs_failed_total"] = float64(stats.TotalFailed)
+	metrics["jobs_shed_total"] = float64(stats.TotalShed)
 
 	// Derived success rate (completed / submitted)
 	if stats.TotalSubmitted > 0 {
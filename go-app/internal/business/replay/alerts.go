@@ -0,0 +1,47 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// ReadAlerts reads recorded alerts from a history export: either a history
+// response ({"alerts": [...]}, as returned by the history API) or a plain
+// JSON array of alerts. Alerts are returned sorted by StartsAt.
+func ReadAlerts(r io.Reader) ([]*core.Alert, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var alerts []*core.Alert
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &alerts)
+	} else {
+		var history core.HistoryResponse
+		err = json.Unmarshal(data, &history)
+		alerts = history.Alerts
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert history: %w", err)
+	}
+
+	for i, alert := range alerts {
+		if alert == nil || alert.StartsAt.IsZero() {
+			return nil, fmt.Errorf("alert[%d]: missing starts_at", i)
+		}
+		if alert.Fingerprint == "" {
+			alert.Fingerprint = fmt.Sprintf("replay-%d", i)
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].StartsAt.Before(alerts[j].StartsAt)
+	})
+	return alerts, nil
+}
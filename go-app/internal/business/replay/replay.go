@@ -0,0 +1,331 @@
+// Package replay re-runs recorded alerts through a routing, silencing and
+// inhibition configuration offline, so that config changes can be checked
+// against real traffic (e.g. in CI) before they are merged.
+//
+// Example:
+//
+//	alerts, _ := replay.ReadAlerts(historyExport)
+//	baseline, _ := replay.Run(alerts, replay.Config{Routes: current})
+//	candidate, _ := replay.Run(alerts, replay.Config{Routes: proposed})
+//	report := replay.Compare(baseline, candidate)
+package replay
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/grouping"
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+	"github.com/ipiton/AMP/internal/infrastructure/routing"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+// Outcome is what happened to a replayed alert.
+type Outcome string
+
+const (
+	OutcomeNotified  Outcome = "notified"
+	OutcomeSilenced  Outcome = "silenced"
+	OutcomeInhibited Outcome = "inhibited"
+)
+
+// Config is the configuration alerts are replayed through.
+type Config struct {
+	// Routes is an Alertmanager-compatible config: the route tree, the
+	// receivers and the inhibit rules.
+	Routes *routing.RouteConfig
+	// Silences are applied when they are active at the alert's StartsAt.
+	Silences []core.APISilence
+}
+
+// AlertResult is the outcome of one replayed alert.
+type AlertResult struct {
+	Fingerprint string    `json:"fingerprint"`
+	AlertName   string    `json:"alertname"`
+	StartsAt    time.Time `json:"startsAt"`
+	Outcome     Outcome   `json:"outcome"`
+	// Receivers the alert is routed to; only set for notified alerts.
+	Receivers  []string `json:"receivers,omitempty"`
+	SilencedBy []string `json:"silencedBy,omitempty"`
+	// InhibitedBy is the fingerprint of an alert inhibiting this one.
+	InhibitedBy string `json:"inhibitedBy,omitempty"`
+}
+
+// Result is the outcome of replaying alerts through one Config.
+type Result struct {
+	// Alerts holds one result per replayed alert, in input order.
+	Alerts []AlertResult `json:"alerts"`
+	// Notifications counts the notified alerts per receiver.
+	Notifications map[string]int `json:"notifications"`
+}
+
+// Run replays alerts through cfg, applying the stages in the order of the
+// alert processor: silences, then inhibition, then routing.
+//
+// Silences are matched at the alert's StartsAt. An alert is inhibited if a
+// source alert of any inhibit rule was active at the same time (see
+// inhibition.SimulateRule); silenced alerts still inhibit others, as in
+// Alertmanager. Grouping and repeat intervals are not simulated: each alert
+// counts as one notification per receiver.
+func Run(alerts []*core.Alert, cfg Config) (*Result, error) {
+	if cfg.Routes == nil || cfg.Routes.Route == nil {
+		return nil, fmt.Errorf("config has no root route")
+	}
+
+	silences := memory.NewSilenceStore()
+	if err := silences.RestoreFromPersistence(cfg.Silences, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("invalid silences: %w", err)
+	}
+
+	inhibitedBy, err := inhibitedAlerts(alerts, cfg.Routes.InhibitRules)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Alerts:        make([]AlertResult, 0, len(alerts)),
+		Notifications: make(map[string]int),
+	}
+	for _, alert := range alerts {
+		res := AlertResult{
+			Fingerprint: alert.Fingerprint,
+			AlertName:   alert.AlertName,
+			StartsAt:    alert.StartsAt,
+		}
+		if res.AlertName == "" {
+			res.AlertName = alert.Labels[core.LabelAlertName]
+		}
+
+		switch ids := silences.ActiveMatchingSilenceIDs(alert.Labels, alert.StartsAt); {
+		case len(ids) > 0:
+			res.Outcome = OutcomeSilenced
+			res.SilencedBy = ids
+		case inhibitedBy[alert] != nil:
+			res.Outcome = OutcomeInhibited
+			res.InhibitedBy = inhibitedBy[alert].Fingerprint
+		default:
+			res.Outcome = OutcomeNotified
+			res.Receivers = RouteReceivers(cfg.Routes, alert.Labels)
+			for _, receiver := range res.Receivers {
+				result.Notifications[receiver]++
+			}
+		}
+		result.Alerts = append(result.Alerts, res)
+	}
+	return result, nil
+}
+
+// inhibitedAlerts maps each inhibited alert to an alert inhibiting it.
+func inhibitedAlerts(alerts []*core.Alert, inhibitRules []routing.InhibitRule) (map[*core.Alert]*core.Alert, error) {
+	if len(inhibitRules) == 0 || len(alerts) == 0 {
+		return nil, nil
+	}
+
+	rules := make([]inhibition.InhibitionRule, 0, len(inhibitRules))
+	for _, r := range inhibitRules {
+		rules = append(rules, inhibition.InhibitionRule{
+			SourceMatch:   r.SourceMatch,
+			SourceMatchRE: r.SourceMatchRE,
+			TargetMatch:   r.TargetMatch,
+			TargetMatchRE: r.TargetMatchRE,
+			Equal:         r.Equal,
+		})
+	}
+	if err := inhibition.CompileRules(rules); err != nil {
+		return nil, fmt.Errorf("invalid inhibit rules: %w", err)
+	}
+
+	// Replay until the last recorded change; alerts still firing at the end
+	// of the recording stay active until then.
+	var from, to time.Time
+	for i, alert := range alerts {
+		if i == 0 || alert.StartsAt.Before(from) {
+			from = alert.StartsAt
+		}
+		if alert.StartsAt.After(to) {
+			to = alert.StartsAt
+		}
+		if alert.EndsAt != nil && alert.EndsAt.After(to) {
+			to = *alert.EndsAt
+		}
+	}
+
+	inhibited := make(map[*core.Alert]*core.Alert)
+	for i := range rules {
+		results, _ := inhibition.SimulateRule(&rules[i], alerts, from, to)
+		for _, r := range results {
+			if _, ok := inhibited[r.Target]; !ok {
+				inhibited[r.Target] = r.InhibitedBy
+			}
+		}
+	}
+	return inhibited, nil
+}
+
+// RouteReceivers returns the receivers an alert with labels is routed to,
+// following Alertmanager semantics: the deepest matching routes win, a
+// matching route stops its siblings unless it has continue: true, and the
+// root route catches everything else. Routes without a receiver inherit
+// the receiver of their parent.
+func RouteReceivers(cfg *routing.RouteConfig, labels map[string]string) []string {
+	var receivers []string
+	seen := make(map[string]bool)
+	for _, receiver := range matchRoute(cfg, cfg.Route, "", labels) {
+		if !seen[receiver] {
+			seen[receiver] = true
+			receivers = append(receivers, receiver)
+		}
+	}
+	return receivers
+}
+
+// matchRoute returns the receivers of the routes below route (including
+// route itself) that match labels, or nil if route does not match.
+func matchRoute(cfg *routing.RouteConfig, route *grouping.Route, parentReceiver string, labels map[string]string) []string {
+	if route == nil || !routeMatches(cfg, route, labels) {
+		return nil
+	}
+	receiver := route.Receiver
+	if receiver == "" {
+		receiver = parentReceiver
+	}
+
+	var receivers []string
+	for _, child := range route.Routes {
+		matched := matchRoute(cfg, child, receiver, labels)
+		receivers = append(receivers, matched...)
+		if matched != nil && !child.Continue {
+			break
+		}
+	}
+	if len(receivers) == 0 {
+		receivers = []string{receiver}
+	}
+	return receivers
+}
+
+func routeMatches(cfg *routing.RouteConfig, route *grouping.Route, labels map[string]string) bool {
+	for name, value := range route.Match {
+		if labels[name] != value {
+			return false
+		}
+	}
+	for name := range route.MatchRE {
+		regex, ok := cfg.GetCompiledRegex(route, name)
+		if !ok || !regex.MatchString(labels[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary counts the outcomes of a Result.
+type Summary struct {
+	Notified  int `json:"notified"`
+	Silenced  int `json:"silenced"`
+	Inhibited int `json:"inhibited"`
+	// Notifications is the number of notifications sent to all receivers.
+	Notifications int `json:"notifications"`
+}
+
+// ReceiverDelta compares the notifications of one receiver.
+type ReceiverDelta struct {
+	Receiver  string `json:"receiver"`
+	Baseline  int    `json:"baseline"`
+	Candidate int    `json:"candidate"`
+}
+
+// Change is an alert whose outcome differs between baseline and candidate.
+type Change struct {
+	Baseline  AlertResult `json:"baseline"`
+	Candidate AlertResult `json:"candidate"`
+}
+
+// Report is the delta between the baseline and the candidate configuration.
+type Report struct {
+	Alerts    int     `json:"alerts"`
+	Baseline  Summary `json:"baseline"`
+	Candidate Summary `json:"candidate"`
+	// Receivers lists the receivers whose notification count changed.
+	Receivers []ReceiverDelta `json:"receivers"`
+	// Changes lists the alerts whose outcome or receivers changed.
+	Changes []Change `json:"changes"`
+}
+
+// HasChanges reports whether the candidate changes any notification.
+func (r *Report) HasChanges() bool {
+	return len(r.Changes) > 0
+}
+
+// Compare reports the delta between two Results of the same alerts.
+func Compare(baseline, candidate *Result) *Report {
+	report := &Report{
+		Alerts:    len(baseline.Alerts),
+		Baseline:  summarize(baseline),
+		Candidate: summarize(candidate),
+		Receivers: make([]ReceiverDelta, 0),
+		Changes:   make([]Change, 0),
+	}
+
+	for i := range baseline.Alerts {
+		if i >= len(candidate.Alerts) {
+			break
+		}
+		b, c := baseline.Alerts[i], candidate.Alerts[i]
+		if b.Outcome != c.Outcome || !equalStrings(b.Receivers, c.Receivers) {
+			report.Changes = append(report.Changes, Change{Baseline: b, Candidate: c})
+		}
+	}
+
+	receivers := make(map[string]struct{})
+	for receiver := range baseline.Notifications {
+		receivers[receiver] = struct{}{}
+	}
+	for receiver := range candidate.Notifications {
+		receivers[receiver] = struct{}{}
+	}
+	for receiver := range receivers {
+		delta := ReceiverDelta{
+			Receiver:  receiver,
+			Baseline:  baseline.Notifications[receiver],
+			Candidate: candidate.Notifications[receiver],
+		}
+		if delta.Baseline != delta.Candidate {
+			report.Receivers = append(report.Receivers, delta)
+		}
+	}
+	sort.Slice(report.Receivers, func(i, j int) bool {
+		return report.Receivers[i].Receiver < report.Receivers[j].Receiver
+	})
+	return report
+}
+
+func summarize(result *Result) Summary {
+	var summary Summary
+	for _, alert := range result.Alerts {
+		switch alert.Outcome {
+		case OutcomeNotified:
+			summary.Notified++
+			summary.Notifications += len(alert.Receivers)
+		case OutcomeSilenced:
+			summary.Silenced++
+		case OutcomeInhibited:
+			summary.Inhibited++
+		}
+	}
+	return summary
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/routing"
+)
+
+const baselineConfig = `
+route:
+  receiver: default
+  routes:
+    - match:
+        severity: critical
+      receiver: pager
+    - match_re:
+        team: "db.*"
+      receiver: dba
+receivers:
+  - name: default
+    webhook_configs:
+      - url: https://example.com/default
+  - name: pager
+    webhook_configs:
+      - url: https://example.com/pager
+  - name: dba
+    webhook_configs:
+      - url: https://example.com/dba
+inhibit_rules:
+  - source_match:
+      alertname: NodeDown
+    target_match:
+      alertname: InstanceDown
+    equal: [node]
+`
+
+func parseConfig(t *testing.T, yaml string) *routing.RouteConfig {
+	t.Helper()
+	cfg, err := routing.NewRouteConfigParser().ParseString(yaml)
+	require.NoError(t, err)
+	return cfg
+}
+
+var replayStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func replayAlert(fingerprint string, minute int, labels map[string]string) *core.Alert {
+	return &core.Alert{
+		Fingerprint: fingerprint,
+		AlertName:   labels["alertname"],
+		Status:      core.StatusFiring,
+		Labels:      labels,
+		StartsAt:    replayStart.Add(time.Duration(minute) * time.Minute),
+	}
+}
+
+func TestRouteReceivers(t *testing.T) {
+	cfg := parseConfig(t, baselineConfig)
+
+	assert.Equal(t, []string{"pager"}, RouteReceivers(cfg, map[string]string{"severity": "critical", "team": "db"}))
+	assert.Equal(t, []string{"dba"}, RouteReceivers(cfg, map[string]string{"team": "dbops"}))
+	assert.Equal(t, []string{"default"}, RouteReceivers(cfg, map[string]string{"team": "web"}))
+
+	cfg.Route.Routes[0].Continue = true
+	assert.Equal(t, []string{"pager", "dba"}, RouteReceivers(cfg, map[string]string{"severity": "critical", "team": "db"}))
+}
+
+func TestRun(t *testing.T) {
+	cfg := parseConfig(t, baselineConfig)
+	alerts := []*core.Alert{
+		replayAlert("node", 0, map[string]string{"alertname": "NodeDown", "node": "n1", "severity": "critical"}),
+		replayAlert("instance", 5, map[string]string{"alertname": "InstanceDown", "node": "n1"}),
+		replayAlert("disk", 10, map[string]string{"alertname": "DiskFull", "team": "db"}),
+	}
+	silences := []core.APISilence{{
+		ID:       "maintenance",
+		Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+		StartsAt: replayStart.Format(time.RFC3339),
+		EndsAt:   replayStart.Add(time.Hour).Format(time.RFC3339),
+	}}
+
+	result, err := Run(alerts, Config{Routes: cfg, Silences: silences})
+	require.NoError(t, err)
+	require.Len(t, result.Alerts, 3)
+
+	assert.Equal(t, OutcomeNotified, result.Alerts[0].Outcome)
+	assert.Equal(t, []string{"pager"}, result.Alerts[0].Receivers)
+	assert.Equal(t, OutcomeInhibited, result.Alerts[1].Outcome)
+	assert.Equal(t, "node", result.Alerts[1].InhibitedBy)
+	assert.Equal(t, OutcomeSilenced, result.Alerts[2].Outcome)
+	assert.Equal(t, []string{"maintenance"}, result.Alerts[2].SilencedBy)
+	assert.Equal(t, map[string]int{"pager": 1}, result.Notifications)
+}
+
+func TestCompare(t *testing.T) {
+	alerts := []*core.Alert{
+		replayAlert("node", 0, map[string]string{"alertname": "NodeDown", "node": "n1", "severity": "critical"}),
+		replayAlert("instance", 5, map[string]string{"alertname": "InstanceDown", "node": "n1"}),
+		replayAlert("disk", 10, map[string]string{"alertname": "DiskFull", "team": "db"}),
+	}
+
+	baseline, err := Run(alerts, Config{Routes: parseConfig(t, baselineConfig)})
+	require.NoError(t, err)
+
+	// The candidate drops the inhibit rule and routes db alerts to default.
+	candidateConfig := strings.Replace(baselineConfig, `team: "db.*"`, `team: "dba.*"`, 1)
+	candidateConfig = candidateConfig[:strings.Index(candidateConfig, "inhibit_rules:")]
+	candidate, err := Run(alerts, Config{Routes: parseConfig(t, candidateConfig)})
+	require.NoError(t, err)
+
+	report := Compare(baseline, candidate)
+	require.True(t, report.HasChanges())
+	assert.Equal(t, 3, report.Alerts)
+	assert.Equal(t, Summary{Notified: 2, Inhibited: 1, Notifications: 2}, report.Baseline)
+	assert.Equal(t, Summary{Notified: 3, Notifications: 3}, report.Candidate)
+	assert.Equal(t, []ReceiverDelta{
+		{Receiver: "dba", Baseline: 1, Candidate: 0},
+		{Receiver: "default", Baseline: 0, Candidate: 2},
+	}, report.Receivers)
+	require.Len(t, report.Changes, 2)
+	assert.Equal(t, "instance", report.Changes[0].Baseline.Fingerprint)
+	assert.Equal(t, OutcomeInhibited, report.Changes[0].Baseline.Outcome)
+	assert.Equal(t, []string{"default"}, report.Changes[0].Candidate.Receivers)
+	assert.Equal(t, "disk", report.Changes[1].Candidate.Fingerprint)
+
+	assert.False(t, Compare(baseline, baseline).HasChanges())
+}
+
+func TestReadAlerts(t *testing.T) {
+	history := `{"alerts": [
+		{"fingerprint": "b", "alert_name": "B", "status": "firing", "labels": {"alertname": "B"}, "starts_at": "2026-03-01T12:10:00Z"},
+		{"fingerprint": "a", "alert_name": "A", "status": "resolved", "labels": {"alertname": "A"}, "starts_at": "2026-03-01T12:00:00Z", "ends_at": "2026-03-01T12:05:00Z"}
+	], "total": 2}`
+
+	alerts, err := ReadAlerts(strings.NewReader(history))
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "a", alerts[0].Fingerprint, "alerts are sorted by starts_at")
+	require.NotNil(t, alerts[0].EndsAt)
+
+	alerts, err = ReadAlerts(strings.NewReader(`[{"labels": {"alertname": "A"}, "starts_at": "2026-03-01T12:00:00Z"}]`))
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.NotEmpty(t, alerts[0].Fingerprint)
+
+	_, err = ReadAlerts(strings.NewReader(`[{"labels": {"alertname": "A"}}]`))
+	assert.Error(t, err)
+}
@@ -14,7 +14,7 @@ import (
 //
 // Design:
 //   - Lightweight wrapper around RouteMatcher
-//   - Stateless (no caching, no state)
+//   - Caches route resolution per routing label set (see RouteCache)
 //   - Thread-safe for concurrent use
 //   - Zero allocations in hot path (design goal: 1-2 max)
 //
@@ -26,7 +26,8 @@ import (
 // Thread Safety:
 //
 //	RouteEvaluator is safe for concurrent use.
-//	tree and matcher are immutable after construction.
+//	tree and matcher are immutable after construction; an evaluator
+//	created with NewManagedRouteEvaluator follows the manager's tree.
 //
 // Example:
 //
@@ -37,8 +38,11 @@ import (
 //	}
 //	// Use decision.Receiver, decision.GroupBy, etc.
 type RouteEvaluator struct {
-	// tree is the route tree (from TN-138)
-	tree *RouteTree
+	// trees returns the route tree (from TN-138) to evaluate against
+	trees func() *RouteTree
+
+	// cache caches route resolution (nil if disabled)
+	cache *RouteCache
 
 	// matcher finds matching routes (from TN-139)
 	matcher *RouteMatcher
@@ -68,6 +72,15 @@ type EvaluatorOptions struct {
 	// When true: no matches → use root receiver
 	// When false: no matches → return error
 	FallbackToRoot bool
+
+	// EnableRouteCache caches route resolution (default: true)
+	//
+	// Alerts with the same values of the labels the tree matches on
+	// reuse the first resolution until the tree changes.
+	EnableRouteCache bool
+
+	// RouteCacheSize is the max number of cached resolutions (default: 10000)
+	RouteCacheSize int
 }
 
 // DefaultEvaluatorOptions returns default evaluator options.
@@ -76,11 +89,15 @@ type EvaluatorOptions struct {
 //   - EnableLogging: false (debug disabled)
 //   - EnableMetrics: true (metrics enabled)
 //   - FallbackToRoot: true (graceful fallback)
+//   - EnableRouteCache: true (route resolution cached)
+//   - RouteCacheSize: 10000 (max cached label sets)
 func DefaultEvaluatorOptions() EvaluatorOptions {
 	return EvaluatorOptions{
-		EnableLogging:  false,
-		EnableMetrics:  true,
-		FallbackToRoot: true,
+		EnableLogging:    false,
+		EnableMetrics:    true,
+		FallbackToRoot:   true,
+		EnableRouteCache: true,
+		RouteCacheSize:   10000,
 	}
 }
 
@@ -94,7 +111,7 @@ func DefaultEvaluatorOptions() EvaluatorOptions {
 // Returns:
 //   - *RouteEvaluator: A new evaluator instance
 //
-// The evaluator is stateless and thread-safe.
+// The evaluator is thread-safe.
 // Multiple goroutines can call Evaluate() concurrently.
 //
 // Example:
@@ -106,9 +123,36 @@ func NewRouteEvaluator(
 	tree *RouteTree,
 	matcher *RouteMatcher,
 	opts EvaluatorOptions,
+) *RouteEvaluator {
+	return newRouteEvaluator(func() *RouteTree { return tree }, matcher, opts)
+}
+
+// NewManagedRouteEvaluator creates a RouteEvaluator that evaluates against
+// the current tree of manager.
+//
+// Hot reloads apply to the next evaluation, and the route cache is
+// invalidated on the first evaluation after each Reload or Rollback.
+//
+// Example:
+//
+//	manager, _ := NewRouteTreeManager(tree)
+//	evaluator := NewManagedRouteEvaluator(manager, matcher, DefaultEvaluatorOptions())
+//	_ = manager.Reload(newConfig) // evaluator picks up the new tree
+func NewManagedRouteEvaluator(
+	manager *RouteTreeManager,
+	matcher *RouteMatcher,
+	opts EvaluatorOptions,
+) *RouteEvaluator {
+	return newRouteEvaluator(manager.GetTree, matcher, opts)
+}
+
+func newRouteEvaluator(
+	trees func() *RouteTree,
+	matcher *RouteMatcher,
+	opts EvaluatorOptions,
 ) *RouteEvaluator {
 	e := &RouteEvaluator{
-		tree:    tree,
+		trees:   trees,
 		matcher: matcher,
 		opts:    opts,
 	}
@@ -118,9 +162,14 @@ func NewRouteEvaluator(
 		e.metrics = NewEvaluatorMetrics()
 	}
 
+	if opts.EnableRouteCache {
+		e.cache = NewRouteCache(opts.RouteCacheSize)
+	}
+
 	if opts.EnableLogging {
 		slog.Info("route evaluator initialized",
-			"fallback_to_root", opts.FallbackToRoot)
+			"fallback_to_root", opts.FallbackToRoot,
+			"route_cache", opts.EnableRouteCache)
 	}
 
 	return e
@@ -130,7 +179,7 @@ func NewRouteEvaluator(
 //
 // Algorithm:
 //  1. Validate input (tree != nil)
-//  2. Find matching routes using matcher
+//  2. Find matching routes (route cache, then matcher)
 //  3. If no matches: fallback to root (if enabled)
 //  4. Extract first match
 //  5. Build RoutingDecision from matched node
@@ -164,8 +213,10 @@ func NewRouteEvaluator(
 //	receiver := decision.Receiver
 //	groupBy := decision.GroupBy
 func (e *RouteEvaluator) Evaluate(alert *Alert) (*RoutingDecision, error) {
+	tree := e.trees()
+
 	// Step 1: Validate input
-	if e.tree == nil || e.tree.Root == nil {
+	if tree == nil || tree.Root == nil {
 		if e.metrics != nil {
 			e.metrics.RecordError("empty_tree")
 		}
@@ -175,7 +226,7 @@ func (e *RouteEvaluator) Evaluate(alert *Alert) (*RoutingDecision, error) {
 	start := time.Now()
 
 	// Step 2: Find matching routes
-	matchResult := e.matcher.FindMatchingRoutes(e.tree, alert)
+	matchResult := e.findMatchingRoutes(tree, alert)
 
 	// Step 3: Handle no matches
 	var node *RouteNode
@@ -191,7 +242,7 @@ func (e *RouteEvaluator) Evaluate(alert *Alert) (*RoutingDecision, error) {
 		}
 
 		// Fallback to root
-		node = e.tree.Root
+		node = tree.Root
 		matchedPath = "/ (root default)"
 
 		if e.metrics != nil {
@@ -305,8 +356,10 @@ func (e *RouteEvaluator) EvaluateWithAlternatives(
 		Alternatives: make([]*RoutingDecision, 0, 4), // Pre-allocate typical size
 	}
 
+	tree := e.trees()
+
 	// Step 1: Validate input
-	if e.tree == nil || e.tree.Root == nil {
+	if tree == nil || tree.Root == nil {
 		result.Error = ErrEmptyTree
 		if e.metrics != nil {
 			e.metrics.RecordError("empty_tree")
@@ -315,7 +368,7 @@ func (e *RouteEvaluator) EvaluateWithAlternatives(
 	}
 
 	// Step 2: Find matching routes
-	matchResult := e.matcher.FindMatchingRoutes(e.tree, alert)
+	matchResult := e.findMatchingRoutes(tree, alert)
 
 	// Step 3: Handle no matches
 	if matchResult.Empty() {
@@ -329,7 +382,7 @@ func (e *RouteEvaluator) EvaluateWithAlternatives(
 
 		// Fallback to root
 		result.Primary = e.buildDecision(
-			e.tree.Root,
+			tree.Root,
 			"/ (root default)",
 			matchResult,
 		)
@@ -405,6 +458,34 @@ func (e *RouteEvaluator) EvaluateWithAlternatives(
 	return result
 }
 
+// findMatchingRoutes finds the routes matching the alert, from the route
+// cache if possible.
+//
+// A cache hit returns a MatchResult without matching statistics
+// (zero duration, no matchers evaluated).
+func (e *RouteEvaluator) findMatchingRoutes(tree *RouteTree, alert *Alert) *MatchResult {
+	if e.cache == nil {
+		return e.matcher.FindMatchingRoutes(tree, alert)
+	}
+
+	if matches, ok := e.cache.Get(tree, alert); ok {
+		if e.metrics != nil {
+			e.metrics.RouteCacheHits.Inc()
+		}
+		return &MatchResult{Matches: matches}
+	}
+
+	if e.metrics != nil {
+		e.metrics.RouteCacheMisses.Inc()
+	}
+	result := e.matcher.FindMatchingRoutes(tree, alert)
+	e.cache.Put(tree, alert, result.Matches)
+	if e.metrics != nil {
+		e.metrics.RouteCacheSize.Set(float64(e.cache.Stats().Size))
+	}
+	return result
+}
+
 // buildDecision builds a RoutingDecision from a matched node.
 //
 // Helper function to avoid code duplication between
@@ -435,6 +516,16 @@ func (e *RouteEvaluator) buildDecision(
 	}
 }
 
+// GetRouteCacheStats returns route cache statistics.
+//
+// Returns zero stats if the route cache is disabled.
+func (e *RouteEvaluator) GetRouteCacheStats() RouteCacheStats {
+	if e.cache == nil {
+		return RouteCacheStats{}
+	}
+	return e.cache.Stats()
+}
+
 // GetMetrics returns the evaluator's metrics instance.
 //
 // Returns nil if metrics are disabled (opts.EnableMetrics=false).
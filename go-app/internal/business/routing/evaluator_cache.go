@@ -0,0 +1,215 @@
+package routing
+
+import (
+	"container/list"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RouteCache caches route resolution for RouteEvaluator.
+//
+// Alerts of one group share their group_by labels, and at high ingest rates
+// the same few label sets are routed over and over. A route only depends on
+// the labels the tree's matchers reference, so the cache key is that label
+// subset (values and presence); alerts that differ only in other labels
+// share one resolution.
+//
+// Features:
+//   - Entries are only valid for the tree they were resolved on: looking up
+//     with a different tree (after RouteTreeManager.Reload or Rollback)
+//     drops all entries
+//   - LRU eviction when cache reaches maxSize
+//   - Thread-safe concurrent access (sync.Mutex)
+//   - Statistics tracking (hits, misses, invalidations, size)
+//
+// Cached match slices are shared between callers and must not be modified.
+type RouteCache struct {
+	// tree is the tree the entries were resolved on
+	tree *RouteTree
+
+	// labels are the sorted label names referenced by tree's matchers
+	labels []string
+
+	// entries maps key → LRU element holding a *routeCacheEntry
+	entries map[string]*list.Element
+
+	// lru tracks access order for eviction
+	// Most recently used at front, least at back
+	lru *list.List
+
+	// maxSize limits cache size (default: 10000)
+	maxSize int
+
+	// mu protects all fields above and stats
+	mu sync.Mutex
+
+	stats RouteCacheStats
+}
+
+// routeCacheEntry is a single cached resolution.
+type routeCacheEntry struct {
+	key     string
+	matches []*RouteNode
+}
+
+// RouteCacheStats tracks route cache statistics.
+type RouteCacheStats struct {
+	// Hits is the number of cache hits
+	Hits uint64
+
+	// Misses is the number of cache misses
+	Misses uint64
+
+	// Invalidations is the number of times the cache was dropped
+	// because the route tree changed
+	Invalidations uint64
+
+	// Size is the current cache size
+	Size int
+}
+
+// HitRate returns the cache hit rate (0-1).
+//
+// Returns 0 if no lookups occurred.
+func (s RouteCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// NewRouteCache creates a new route cache.
+//
+// Parameters:
+//   - maxSize: Maximum cache size (0 = default of 10000)
+func NewRouteCache(maxSize int) *RouteCache {
+	if maxSize <= 0 {
+		maxSize = 10000 // Default
+	}
+
+	return &RouteCache{
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		maxSize: maxSize,
+	}
+}
+
+// Get returns the matches cached for the alert on tree.
+//
+// A tree other than the one the entries were resolved on invalidates
+// the cache and is a miss.
+//
+// Complexity: O(L) where L = labels referenced by the tree's matchers
+func (c *RouteCache) Get(tree *RouteTree, alert *Alert) ([]*RouteNode, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tree != tree {
+		c.reset(tree)
+	}
+
+	element, ok := c.entries[c.key(alert)]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	c.stats.Hits++
+	return element.Value.(*routeCacheEntry).matches, true
+}
+
+// Put caches the matches of the alert on tree.
+//
+// Matches resolved on a tree other than the cached one are not stored.
+func (c *RouteCache) Put(tree *RouteTree, alert *Alert, matches []*RouteNode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tree != tree {
+		return
+	}
+
+	key := c.key(alert)
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+
+	// Evict LRU entry if cache is full
+	if len(c.entries) >= c.maxSize {
+		if back := c.lru.Back(); back != nil {
+			c.lru.Remove(back)
+			delete(c.entries, back.Value.(*routeCacheEntry).key)
+		}
+	}
+
+	c.entries[key] = c.lru.PushFront(&routeCacheEntry{key: key, matches: matches})
+}
+
+// Stats returns current cache statistics.
+func (c *RouteCache) Stats() RouteCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = len(c.entries)
+	return stats
+}
+
+// reset drops all entries and prepares the cache for tree.
+//
+// Caller must hold c.mu.
+func (c *RouteCache) reset(tree *RouteTree) {
+	if c.tree != nil {
+		c.stats.Invalidations++
+	}
+
+	c.tree = tree
+	c.labels = routingLabels(tree)
+	c.entries = make(map[string]*list.Element)
+	c.lru = list.New()
+}
+
+// key builds the cache key of the alert from the routing labels.
+// A missing label and an empty one are distinct: != and !~ matchers
+// treat them differently.
+//
+// Caller must hold c.mu.
+func (c *RouteCache) key(alert *Alert) string {
+	var b strings.Builder
+	for _, name := range c.labels {
+		if value, ok := alert.Labels[name]; ok {
+			b.WriteByte('=')
+			b.WriteString(value)
+		} else {
+			b.WriteByte('!')
+		}
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
+
+// routingLabels returns the sorted label names referenced by the matchers
+// of tree.
+func routingLabels(tree *RouteTree) []string {
+	if tree == nil || tree.Root == nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	tree.Walk(func(node *RouteNode) bool {
+		for _, matcher := range node.Matchers {
+			seen[matcher.Name] = struct{}{}
+		}
+		return true
+	})
+
+	labels := make([]string, 0, len(seen))
+	for name := range seen {
+		labels = append(labels, name)
+	}
+	sort.Strings(labels)
+	return labels
+}
@@ -15,6 +15,11 @@ import (
 //   - no_match_total: Count of no-match fallbacks to root
 //   - multi_receiver_total: Count of multi-receiver evaluations
 //   - errors_total: Count of evaluation errors by type
+//   - route_cache_hits_total: Count of route cache hits
+//   - route_cache_misses_total: Count of route cache misses
+//   - route_cache_size: Current route cache size (gauge)
+//
+// The route cache hit rate is hits / (hits + misses).
 //
 // All metrics are prefixed with "alert_history_routing_" namespace.
 type EvaluatorMetrics struct {
@@ -32,6 +37,15 @@ type EvaluatorMetrics struct {
 
 	// ErrorsTotal counts evaluation errors by type
 	ErrorsTotal *prometheus.CounterVec
+
+	// RouteCacheHits counts route cache hits
+	RouteCacheHits prometheus.Counter
+
+	// RouteCacheMisses counts route cache misses
+	RouteCacheMisses prometheus.Counter
+
+	// RouteCacheSize tracks current route cache size
+	RouteCacheSize prometheus.Gauge
 }
 
 // NewEvaluatorMetrics creates Prometheus metrics for RouteEvaluator.
@@ -95,6 +109,33 @@ func NewEvaluatorMetrics() *EvaluatorMetrics {
 			},
 			[]string{"error_type"},
 		),
+
+		RouteCacheHits: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "alert_history",
+				Subsystem: "routing",
+				Name:      "route_cache_hits_total",
+				Help:      "Total number of route cache hits",
+			},
+		),
+
+		RouteCacheMisses: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "alert_history",
+				Subsystem: "routing",
+				Name:      "route_cache_misses_total",
+				Help:      "Total number of route cache misses",
+			},
+		),
+
+		RouteCacheSize: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "alert_history",
+				Subsystem: "routing",
+				Name:      "route_cache_size",
+				Help:      "Current size of route cache",
+			},
+		),
 	}
 }
 
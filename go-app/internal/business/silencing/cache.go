@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core/silencing"
+	"github.com/ipiton/AMP/pkg/clock"
 )
 
 // silenceCache is an in-memory cache for active silences.
@@ -54,6 +55,8 @@ type silenceCache struct {
 	// Metadata
 	lastSync time.Time // Last time cache was rebuilt from database
 	size     int       // Number of silences in cache (optimization)
+
+	clock clock.Clock // Time source for lastSync (default: real)
 }
 
 // newSilenceCache creates a new empty cache.
@@ -69,6 +72,7 @@ func newSilenceCache() *silenceCache {
 	return &silenceCache{
 		silences: make(map[string]*silencing.Silence),
 		byStatus: make(map[silencing.SilenceStatus][]string),
+		clock:    clock.Real(),
 	}
 }
 
@@ -253,7 +257,7 @@ func (c *silenceCache) Rebuild(silences []*silencing.Silence) {
 	c.rebuildStatusIndex()
 
 	// Update metadata
-	c.lastSync = time.Now()
+	c.lastSync = c.clock.Now()
 	c.size = len(c.silences)
 }
 
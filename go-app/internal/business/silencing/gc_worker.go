@@ -6,6 +6,7 @@ import (
 	"time"
 
 	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/pkg/clock"
 )
 
 // gcWorker handles periodic garbage collection of expired silences.
@@ -38,6 +39,7 @@ type gcWorker struct {
 
 	logger  *slog.Logger
 	metrics *SilenceMetrics
+	clock   clock.Clock // Time source for ticker and cutoffs (default: real)
 
 	stopCh chan struct{} // Signal to stop worker
 	doneCh chan struct{} // Signal when worker stopped
@@ -76,6 +78,7 @@ func newGCWorker(
 		batchSize: batchSize,
 		logger:    logger,
 		metrics:   metrics,
+		clock:     clock.Real(),
 		stopCh:    make(chan struct{}),
 		doneCh:    make(chan struct{}),
 	}
@@ -118,7 +121,7 @@ func (w *gcWorker) Start(ctx context.Context) {
 func (w *gcWorker) run(ctx context.Context) {
 	defer close(w.doneCh)
 
-	ticker := time.NewTicker(w.interval)
+	ticker := w.clock.NewTicker(w.interval)
 	defer ticker.Stop()
 
 	// Run immediately on startup (don't wait for first tick)
@@ -134,7 +137,7 @@ func (w *gcWorker) run(ctx context.Context) {
 			w.logger.Info("GC worker stopped (explicit stop)")
 			return
 
-		case <-ticker.C:
+		case <-ticker.C():
 			w.runCleanup(ctx)
 		}
 	}
@@ -215,7 +218,7 @@ func (w *gcWorker) expireActiveSilences(ctx context.Context) (int64, error) {
 	}()
 
 	// Call repository ExpireSilences (deleteExpired=false for status update)
-	count, err := w.repo.ExpireSilences(ctx, time.Now(), false)
+	count, err := w.repo.ExpireSilences(ctx, w.clock.Now(), false)
 	if err != nil {
 		w.logger.Error("ExpireSilences failed", "error", err)
 		return 0, err
@@ -255,7 +258,7 @@ func (w *gcWorker) deleteOldExpired(ctx context.Context) (int64, error) {
 	}()
 
 	// Calculate cutoff time (NOW - retention)
-	before := time.Now().Add(-w.retention)
+	before := w.clock.Now().Add(-w.retention)
 
 	// Call repository ExpireSilences (deleteExpired=true for hard delete)
 	count, err := w.repo.ExpireSilences(ctx, before, true)
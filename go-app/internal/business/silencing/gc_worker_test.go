@@ -12,6 +12,7 @@ import (
 
 	"github.com/ipiton/AMP/internal/core/silencing"
 	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/pkg/clock"
 )
 
 // ==================== Mock Repository for GC Worker Tests ====================
@@ -379,3 +380,55 @@ func TestGCWorker_ErrorHandling(t *testing.T) {
 	// Verify both phases were attempted
 	mockRepo.AssertExpectations(t)
 }
+
+// ==================== Test 9: Injected Clock ====================
+
+// TestGCWorker_FakeClock verifies cutoffs and ticks follow the injected clock.
+//
+// Coverage:
+//   - Phase 1 cutoff is clock.Now()
+//   - Phase 2 cutoff is clock.Now() - retention
+//   - Ticker fires only when the fake clock advances
+//
+// Expected:
+//   - Two cleanup cycles: startup + one tick
+func TestGCWorker_FakeClock(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mockRepo := new(mockGCRepository)
+	cache := newSilenceCache()
+	metrics := NewSilenceMetrics()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	retention := 24 * time.Hour
+
+	worker := newGCWorker(mockRepo, cache, 5*time.Minute, retention, 1000, logger, metrics)
+	worker.clock = fake
+
+	cycles := make(chan struct{}, 2)
+	mockRepo.On("ExpireSilences", mock.Anything, start, false).Return(int64(0), nil).Once()
+	mockRepo.On("ExpireSilences", mock.Anything, start.Add(-retention), true).Return(int64(0), nil).Once().
+		Run(func(mock.Arguments) { cycles <- struct{}{} })
+
+	next := start.Add(5 * time.Minute)
+	mockRepo.On("ExpireSilences", mock.Anything, next, false).Return(int64(0), nil).Once()
+	mockRepo.On("ExpireSilences", mock.Anything, next.Add(-retention), true).Return(int64(0), nil).Once().
+		Run(func(mock.Arguments) { cycles <- struct{}{} })
+
+	worker.Start(context.Background())
+	defer worker.Stop()
+
+	// Startup cleanup
+	<-cycles
+
+	// First tick
+	fake.BlockUntil(1)
+	fake.Advance(5 * time.Minute)
+	select {
+	case <-cycles:
+	case <-time.After(2 * time.Second):
+		t.Fatal("cleanup did not run after advancing the clock")
+	}
+
+	mockRepo.AssertExpectations(t)
+}
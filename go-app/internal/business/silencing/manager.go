@@ -50,6 +50,7 @@ import (
 
 	"github.com/ipiton/AMP/internal/core/silencing"
 	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/pkg/clock"
 )
 
 // SilenceManager coordinates silence lifecycle and alert filtering.
@@ -334,6 +335,9 @@ type SilenceManagerConfig struct {
 
 	// Shutdown Settings
 	ShutdownTimeout time.Duration // Max time for graceful shutdown (default: 30s)
+
+	// Clock drives worker tickers and GC cutoffs (default: clock.Real())
+	Clock clock.Clock
 }
 
 // DefaultSilenceManagerConfig returns default configuration.
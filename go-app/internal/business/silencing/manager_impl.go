@@ -10,6 +10,7 @@ import (
 
 	"github.com/ipiton/AMP/internal/core/silencing"
 	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/pkg/clock"
 )
 
 // DefaultSilenceManager implements SilenceManager interface.
@@ -162,6 +163,12 @@ func NewDefaultSilenceManager(
 		sm.metrics,
 	)
 
+	// Share one clock between cache and workers
+	sm.config.Clock = clock.OrReal(config.Clock)
+	sm.cache.clock = sm.config.Clock
+	sm.gcWorker.clock = sm.config.Clock
+	sm.syncWorker.clock = sm.config.Clock
+
 	logger.Info("Silence manager created",
 		"gc_interval", config.GCInterval,
 		"gc_retention", config.GCRetention,
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ipiton/AMP/internal/core/silencing"
 	infrasilencing "github.com/ipiton/AMP/internal/infrastructure/silencing"
+	"github.com/ipiton/AMP/pkg/clock"
 )
 
 // syncWorker handles periodic synchronization of in-memory cache with PostgreSQL.
@@ -42,6 +43,7 @@ type syncWorker struct {
 
 	logger  *slog.Logger
 	metrics *SilenceMetrics
+	clock   clock.Clock // Time source for ticker (default: real)
 
 	stopCh chan struct{} // Signal to stop worker
 	doneCh chan struct{} // Signal when worker stopped
@@ -75,6 +77,7 @@ func newSyncWorker(
 		interval: interval,
 		logger:   logger,
 		metrics:  metrics,
+		clock:    clock.Real(),
 		stopCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
 	}
@@ -113,7 +116,7 @@ func (w *syncWorker) Start(ctx context.Context) {
 func (w *syncWorker) run(ctx context.Context) {
 	defer close(w.doneCh)
 
-	ticker := time.NewTicker(w.interval)
+	ticker := w.clock.NewTicker(w.interval)
 	defer ticker.Stop()
 
 	// Run immediately on startup (critical for pod restart recovery)
@@ -129,7 +132,7 @@ func (w *syncWorker) run(ctx context.Context) {
 			w.logger.Info("Sync worker stopped (explicit stop)")
 			return
 
-		case <-ticker.C:
+		case <-ticker.C():
 			w.runSync(ctx)
 		}
 	}
@@ -3,10 +3,14 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/ipiton/AMP/pkg/core/domain"
 )
 
 // Config represents the application configuration
@@ -34,6 +38,438 @@ type Config struct {
 	Publishing PublishingConfig  `mapstructure:"publishing"`
 	Inhibition InhibitionConfig  `mapstructure:"inhibition" yaml:"inhibition,omitempty"`
 	Receivers  []ReceiverConfig `mapstructure:"receivers"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+
+	SlackCommand SlackCommandConfig `mapstructure:"slack_command"`
+
+	PagerDutyWebhook PagerDutyWebhookConfig `mapstructure:"pagerduty_webhook"`
+
+	RecurringSilences RecurringSilencesConfig `mapstructure:"recurring_silences"`
+
+	SilenceExpiry SilenceExpiryConfig `mapstructure:"silence_expiry"`
+
+	SilenceWebhooks SilenceWebhooksConfig `mapstructure:"silence_webhooks"`
+
+	SilenceGC SilenceGCConfig `mapstructure:"silence_gc"`
+
+	AlertGC AlertGCConfig `mapstructure:"alert_gc"`
+
+	SilenceTemplates []SilenceTemplateConfig `mapstructure:"silence_templates"`
+
+	SilencePolicy SilencePolicyConfig `mapstructure:"silence_policy"`
+
+	HandoffReport HandoffReportConfig `mapstructure:"handoff_report"`
+
+	RegionTagging RegionTaggingConfig `mapstructure:"region_tagging"`
+
+	RoutingConditions RoutingConditionsConfig `mapstructure:"routing_conditions"`
+
+	SilenceMigration SilenceMigrationConfig `mapstructure:"silence_migration"`
+
+	CompositeAlerts CompositeAlertsConfig `mapstructure:"composite_alerts"`
+
+	StormDetection StormDetectionConfig `mapstructure:"storm_detection"`
+
+	AlertTraces AlertTracesConfig `mapstructure:"alert_traces"`
+
+	AlertRollups AlertRollupsConfig `mapstructure:"alert_rollups"`
+
+	AlertReplay AlertReplayConfig `mapstructure:"alert_replay"`
+
+	SilenceCache SilenceCacheConfig `mapstructure:"silence_cache"`
+
+	AlertSampling AlertSamplingConfig `mapstructure:"alert_sampling"`
+}
+
+// AuthConfig holds API token authentication configuration.
+//
+// When enabled, /api/ endpoints and /-/reload require a token sent as
+// "Authorization: Bearer <token>" or "X-API-Key: <token>". Health, readiness
+// and metrics endpoints stay open.
+type AuthConfig struct {
+	Enabled bool             `mapstructure:"enabled"`
+	Tokens  []APITokenConfig `mapstructure:"tokens"`
+}
+
+// APITokenConfig holds a single API token.
+type APITokenConfig struct {
+	Name  string `mapstructure:"name"`
+	Token string `mapstructure:"token"`
+	// Selector restricts the holder to alerts and silences matching all
+	// matchers, e.g. ["team=\"payments\""]. Empty grants full access.
+	Selector []string `mapstructure:"selector"`
+}
+
+// SlackCommandConfig holds the Slack slash-command integration.
+//
+// When enabled, /integrations/slack/command accepts "/amp silence ..."
+// requests signed with the Slack app's signing secret. It is not covered by
+// API token auth; the request signature authenticates Slack instead.
+type SlackCommandConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	SigningSecret string `mapstructure:"signing_secret"`
+	// MaxDuration caps the duration of a silence created from Slack.
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+	// RateLimit is the number of silences one Slack user may create per hour.
+	RateLimit int `mapstructure:"rate_limit"`
+}
+
+// PagerDutyWebhookConfig holds the inbound PagerDuty webhook integration.
+//
+// When enabled, /integrations/pagerduty/webhook accepts PagerDuty V3 webhook
+// events signed with one of the subscription signing secrets, and marks
+// alerts acknowledged while their incident is acknowledged in PagerDuty. It
+// is not covered by API token auth; the request signature authenticates
+// PagerDuty instead.
+type PagerDutyWebhookConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// SigningSecrets of the webhook subscriptions; several are accepted so
+	// secrets can be rotated.
+	SigningSecrets []string `mapstructure:"signing_secrets"`
+}
+
+// RecurringSilencesConfig configures materialization of recurring silences.
+type RecurringSilencesConfig struct {
+	// Interval between scheduler passes.
+	Interval time.Duration `mapstructure:"interval"`
+	// Lookahead is how far ahead windows are created as pending silences.
+	Lookahead time.Duration `mapstructure:"lookahead"`
+}
+
+// SilenceExpiryConfig configures notifications for silences created with
+// notifyOnExpiry.
+type SilenceExpiryConfig struct {
+	// Interval between checks.
+	Interval time.Duration `mapstructure:"interval"`
+	// LeadTime is how long before a silence ends its "expiring" notification
+	// is sent.
+	LeadTime time.Duration `mapstructure:"lead_time"`
+}
+
+// SilenceWebhooksConfig configures webhooks receiving silence lifecycle
+// events. Enabled when endpoints are configured.
+type SilenceWebhooksConfig struct {
+	Endpoints []SilenceWebhookEndpointConfig `mapstructure:"endpoints"`
+	// Interval between checks for silences reaching their end time.
+	Interval   time.Duration `mapstructure:"interval"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	QueueSize  int           `mapstructure:"queue_size"`
+	MaxRetries int           `mapstructure:"max_retries"`
+}
+
+// SilenceWebhookEndpointConfig is a webhook receiving silence events.
+type SilenceWebhookEndpointConfig struct {
+	URL string `mapstructure:"url"`
+	// Events the endpoint receives: silence.created, silence.updated,
+	// silence.expired (default: all).
+	Events  []string          `mapstructure:"events"`
+	Headers map[string]string `mapstructure:"headers"`
+	// SigningSecret signs requests with X-AMP-Signature.
+	SigningSecret string `mapstructure:"signing_secret"`
+}
+
+// SilenceGCConfig configures garbage collection of expired silences.
+type SilenceGCConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Interval between collections.
+	Interval time.Duration `mapstructure:"interval"`
+	// Retention is how long expired silences are kept before deletion.
+	Retention time.Duration `mapstructure:"retention"`
+	// BatchSize caps the silences deleted per collection.
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// AlertGCConfig configures garbage collection of expired alerts from the
+// alert history.
+type AlertGCConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Interval between collections.
+	Interval time.Duration `mapstructure:"interval"`
+	// Retention is how long expired alerts are kept before deletion.
+	Retention time.Duration `mapstructure:"retention"`
+	// ResolveTimeout is when a firing alert without endsAt expires after its
+	// last update, like the Alertmanager global.resolve_timeout.
+	ResolveTimeout time.Duration `mapstructure:"resolve_timeout"`
+	// BatchSize caps the alerts deleted per collection.
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// AlertRollupsConfig configures the background job maintaining hourly and
+// daily alert counts for the dashboard and GET /api/v2/stats/alerts.
+type AlertRollupsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Interval between runs.
+	Interval time.Duration `mapstructure:"interval"`
+	// Lateness is how far back each run recomputes hourly buckets.
+	Lateness time.Duration `mapstructure:"lateness"`
+	// HourlyRetention and DailyRetention are how long rollups are kept.
+	HourlyRetention time.Duration `mapstructure:"hourly_retention"`
+	DailyRetention  time.Duration `mapstructure:"daily_retention"`
+	// TeamLabel is the alert label counted as the team.
+	TeamLabel string `mapstructure:"team_label"`
+}
+
+// AlertReplayConfig replays the firing alerts of Prometheus servers or
+// Alertmanagers at startup, so the active alerts and inhibition sources are
+// known before the next evaluation cycle pushes them again.
+type AlertReplayConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MinDowntime skips the replay when the previous run was seen more
+	// recently (0: replay on every start). Without a runtime checkpoint the
+	// downtime is unknown and alerts are always replayed.
+	MinDowntime time.Duration `mapstructure:"min_downtime"`
+	// ResolveTimeout is the endsAt given to alerts replayed from Prometheus,
+	// which does not send one.
+	ResolveTimeout time.Duration `mapstructure:"resolve_timeout"`
+	// Timeout bounds the requests to each source.
+	Timeout time.Duration             `mapstructure:"timeout"`
+	Sources []AlertReplaySourceConfig `mapstructure:"sources"`
+}
+
+// AlertReplaySourceConfig is a Prometheus (GET /api/v1/alerts) or an
+// Alertmanager (GET /api/v2/alerts) to replay firing alerts from.
+type AlertReplaySourceConfig struct {
+	Name string `mapstructure:"name"`
+	// Exactly one of PrometheusURL and AlertmanagerURL is set, e.g.
+	// http://prometheus:9090 or http://alertmanager:9093.
+	PrometheusURL   string `mapstructure:"prometheus_url"`
+	AlertmanagerURL string `mapstructure:"alertmanager_url"`
+}
+
+// SilencePolicyConfig limits the silences users create through the API and
+// Slack. Recurring silence windows and migrated silences are not checked.
+type SilencePolicyConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxDuration applies to creators outside every group (0: no limit).
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+	// Groups override MaxDuration for their members (API token names or
+	// createdBy values); the first group listing a creator wins.
+	Groups []SilencePolicyGroupConfig `mapstructure:"groups"`
+	// CommentPatterns are regular expressions every comment must match,
+	// e.g. "[A-Z]+-[0-9]+" for a ticket ID.
+	CommentPatterns []string `mapstructure:"comment_patterns"`
+	// Protected label sets, e.g. [{namespace: production}]. Silences that
+	// could match alerts carrying all labels of a set are held until a
+	// second person approves them. Matchers on other labels are ignored, so
+	// a silence without a namespace matcher also needs approval.
+	Protected []map[string]string `mapstructure:"protected"`
+}
+
+// SilencePolicyGroupConfig sets the maximum silence duration of a creator group.
+type SilencePolicyGroupConfig struct {
+	Name        string        `mapstructure:"name"`
+	Members     []string      `mapstructure:"members"`
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+}
+
+// SilenceTemplateConfig is a named silence skeleton for a recurring
+// maintenance type, used by POST /api/v2/silences/from-template/{name}.
+//
+// Matcher values and Comment are Go templates over the request parameters,
+// e.g. "{{ .instance }}"; regexQuote escapes a parameter for regex matchers.
+type SilenceTemplateConfig struct {
+	Name     string                         `mapstructure:"name"`
+	Matchers []SilenceTemplateMatcherConfig `mapstructure:"matchers"`
+	// Duration is the silence length when the request does not set one.
+	Duration time.Duration `mapstructure:"duration"`
+	// MaxDuration caps durations requested by callers (0: no cap).
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+	Comment     string        `mapstructure:"comment"`
+	// RequiredParams must be supplied by every request.
+	RequiredParams []string `mapstructure:"required_params"`
+}
+
+// SilenceTemplateMatcherConfig is a silence matcher whose value is a template.
+type SilenceTemplateMatcherConfig struct {
+	Name    string `mapstructure:"name"`
+	Value   string `mapstructure:"value"`
+	IsRegex bool   `mapstructure:"is_regex"`
+	// IsEqual defaults to true.
+	IsEqual *bool `mapstructure:"is_equal"`
+}
+
+// HandoffReportConfig configures the scheduled on-call handoff report.
+//
+// Each team gets its own report, built from the alerts and silences matching
+// its selector, and published to its target (e.g. a Slack or email target).
+type HandoffReportConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Schedule is a 5-field cron expression, e.g. "0 9 * * 1" for Mondays 09:00.
+	Schedule string `mapstructure:"schedule"`
+	// Timezone is the IANA zone the schedule is evaluated in.
+	Timezone string `mapstructure:"timezone"`
+	// Period is the time span each report covers.
+	Period time.Duration `mapstructure:"period"`
+	// TopRules is the number of noisiest rules listed.
+	TopRules int `mapstructure:"top_rules"`
+	// SilenceHorizon lists silences ending within this duration as expiring soon.
+	SilenceHorizon time.Duration `mapstructure:"silence_horizon"`
+	// TemplateFile optionally replaces the built-in text/template for all teams.
+	TemplateFile string              `mapstructure:"template_file"`
+	Teams        []HandoffTeamConfig `mapstructure:"teams"`
+}
+
+// HandoffTeamConfig is one recipient of the handoff report.
+type HandoffTeamConfig struct {
+	Name string `mapstructure:"name"`
+	// Selector matchers, e.g. ["team=\"payments\""]. Empty reports on all alerts.
+	Selector []string `mapstructure:"selector"`
+	// Target is the publishing target name the report is sent to.
+	Target string `mapstructure:"target"`
+	// TemplateFile overrides the report template for this team.
+	TemplateFile string `mapstructure:"template_file"`
+}
+
+// RegionTaggingConfig configures tagging of incoming alerts with the region
+// and zone of their source, derived from source labels such as "instance".
+//
+// Rules come from Rules and, optionally, a YAML mapping file with a top-level
+// "rules" list of the same shape. Inline rules are evaluated first.
+type RegionTaggingConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// RegionLabel and ZoneLabel are the labels set on tagged alerts.
+	RegionLabel string `mapstructure:"region_label"`
+	ZoneLabel   string `mapstructure:"zone_label"`
+	// SourceLabels are inspected in order for the source host or IP address.
+	SourceLabels []string           `mapstructure:"source_labels"`
+	Rules        []RegionRuleConfig `mapstructure:"rules"`
+	MappingFile  string             `mapstructure:"mapping_file"`
+	// OutageThreshold is the number of distinct firing sources in one region
+	// at which /api/v2/regions reports a suspected regional outage.
+	OutageThreshold int `mapstructure:"outage_threshold"`
+}
+
+// RegionRuleConfig maps a CIDR and/or host name pattern to a region and zone.
+type RegionRuleConfig struct {
+	CIDR string `mapstructure:"cidr" yaml:"cidr"`
+	// HostPattern is an anchored regular expression on the source host name.
+	HostPattern string `mapstructure:"host_pattern" yaml:"host_pattern"`
+	Region      string `mapstructure:"region" yaml:"region"`
+	Zone        string `mapstructure:"zone" yaml:"zone"`
+}
+
+// RoutingConditionsConfig gates publishing of firing alerts on live PromQL
+// conditions evaluated against Prometheus, so pages are only sent while the
+// impact is real (e.g. `sum(up{service="x"}) == 0`).
+type RoutingConditionsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// PrometheusURL is the base URL of the Prometheus queried.
+	PrometheusURL string `mapstructure:"prometheus_url"`
+	// Timeout bounds a single query.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// CacheTTL is how long a query result is reused across alerts.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// FailOpen publishes alerts when Prometheus cannot be queried.
+	FailOpen bool                     `mapstructure:"fail_open"`
+	Rules    []RoutingConditionConfig `mapstructure:"rules"`
+}
+
+// RoutingConditionConfig applies a PromQL condition to the alerts matching
+// Selector. Query is a Go template with the alert labels as .Labels; the
+// condition is met when it returns at least one series.
+type RoutingConditionConfig struct {
+	Name string `mapstructure:"name"`
+	// Selector matchers, e.g. ["service=\"x\""]. Empty applies to all alerts.
+	Selector []string `mapstructure:"selector"`
+	Query    string   `mapstructure:"query"`
+}
+
+// SilenceMigrationConfig configures the Alertmanager used by the silence
+// import/export API. Snapshot and JSON imports work without it.
+type SilenceMigrationConfig struct {
+	// AlertmanagerURL is the base URL of the Alertmanager silences are pulled
+	// from and pushed to, e.g. http://alertmanager:9093.
+	AlertmanagerURL string        `mapstructure:"alertmanager_url"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+}
+
+// CompositeAlertsConfig configures alert-of-alerts rules: when Threshold
+// distinct alerts matching a rule start firing within Window, one composite
+// alert is published instead of the individual alerts.
+type CompositeAlertsConfig struct {
+	Enabled bool                  `mapstructure:"enabled"`
+	Rules   []CompositeRuleConfig `mapstructure:"rules"`
+}
+
+// CompositeRuleConfig is a single composite alert rule. Name is the
+// composite's alertname.
+type CompositeRuleConfig struct {
+	Name string `mapstructure:"name"`
+	// Selector matchers for child alerts, e.g. ["alertname=\"NodeDown\""].
+	Selector []string `mapstructure:"selector"`
+	// GroupBy labels yield one composite per distinct value, e.g. ["cluster"].
+	GroupBy     []string          `mapstructure:"group_by"`
+	Threshold   int               `mapstructure:"threshold"`
+	Window      time.Duration     `mapstructure:"window"`
+	Labels      map[string]string `mapstructure:"labels"`
+	Annotations map[string]string `mapstructure:"annotations"`
+}
+
+// StormDetectionConfig configures storm mode. A storm starts when a Window
+// holds Multiplier times the baseline number of firing alerts (and at least
+// MinAlerts); until the rate has been normal for Cooldown, non-critical
+// alerts are only sent in digests and critical alerts are paged once per
+// group.
+type StormDetectionConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Window     time.Duration `mapstructure:"window"`
+	Multiplier float64       `mapstructure:"multiplier"`
+	MinAlerts  int           `mapstructure:"min_alerts"`
+	Cooldown   time.Duration `mapstructure:"cooldown"`
+	// DigestInterval is how often held back alerts are summarized.
+	DigestInterval time.Duration `mapstructure:"digest_interval"`
+	// GroupBy labels define the storm groups, e.g. ["alertname"].
+	GroupBy []string `mapstructure:"group_by"`
+	// CriticalSeverities are still paged (once per group) during a storm.
+	CriticalSeverities []string `mapstructure:"critical_severities"`
+}
+
+// AlertSamplingConfig limits expensive processing of low-value alerts.
+// The first policy matching an alert applies; alerts matching no policy are
+// fully processed.
+type AlertSamplingConfig struct {
+	Enabled  bool                   `mapstructure:"enabled"`
+	Policies []SamplingPolicyConfig `mapstructure:"policies"`
+}
+
+// SamplingPolicyConfig matches alerts by anchored alertname and severity
+// regular expressions (empty: any) and sets the fraction (0-1) of them that
+// get each stage. Unset rates mean 1.
+type SamplingPolicyConfig struct {
+	Name      string `mapstructure:"name"`
+	AlertName string `mapstructure:"alertname"`
+	Severity  string `mapstructure:"severity"`
+	// ClassificationRate is the fraction classified by the LLM; the others
+	// are processed transparently (filters, all targets).
+	ClassificationRate *float64 `mapstructure:"classification_rate"`
+	// EnrichmentRate is the fraction of classified alerts investigated.
+	EnrichmentRate *float64 `mapstructure:"enrichment_rate"`
+}
+
+// AlertTracesConfig links alerts carrying a trace context (traceparent or
+// trace_id annotations/labels, e.g. from exemplar-aware alerting rules) to
+// AMP's processing spans and adds a tracing UI link to notifications.
+type AlertTracesConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// URLTemplate renders the link with .TraceID and .SpanID, e.g.
+	// "https://tempo.example.com/trace/{{ .TraceID }}". Empty: no link.
+	URLTemplate string `mapstructure:"url_template"`
+	// Keys inspected for the trace context, annotations before labels.
+	TraceparentKeys []string `mapstructure:"traceparent_keys"`
+	TraceIDKeys     []string `mapstructure:"trace_id_keys"`
+	SpanIDKeys      []string `mapstructure:"span_id_keys"`
+}
+
+// SilenceCacheConfig shares silences between replicas through Redis: each
+// change is written to a Redis hash and announced over pub/sub, and the
+// other replicas reload the hash instead of the database. Requires redis.addr.
+type SilenceCacheConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// KeyPrefix namespaces the Redis hash and channel.
+	KeyPrefix string `mapstructure:"key_prefix"`
+	// ResyncInterval reloads the hash periodically in case a notification
+	// was missed, e.g. while reconnecting.
+	ResyncInterval time.Duration `mapstructure:"resync_interval"`
 }
 
 // InhibitionConfig holds inhibition rules configuration (Alertmanager parity, PARITY-A2)
@@ -44,6 +480,28 @@ type InhibitionConfig struct {
 	// ConfigFile is an optional path to a separate inhibition rules YAML file.
 	// If specified, rules from the file are merged with inline Rules.
 	ConfigFile string `mapstructure:"config_file" yaml:"config_file,omitempty"`
+
+	// ExternalSources are other Alertmanager clusters whose firing alerts
+	// act as inhibition sources, e.g. a legacy cluster during a gradual
+	// migration. Applied at startup.
+	ExternalSources []InhibitionExternalSourceConfig `mapstructure:"external_sources" yaml:"external_sources,omitempty"`
+}
+
+// InhibitionExternalSourceConfig is an external Alertmanager feeding source
+// alerts. With URL set its /api/v2/alerts is polled; without, the cluster
+// pushes its notifications to
+// POST /api/v1/inhibition/sources/{name}/webhook via a webhook receiver.
+type InhibitionExternalSourceConfig struct {
+	// Name identifies the source in the webhook path, status and logs.
+	Name string `mapstructure:"name" yaml:"name"`
+	// URL is the base URL of the Alertmanager to poll, e.g.
+	// http://alertmanager:9093. Empty for webhook-only sources.
+	URL          string        `mapstructure:"url" yaml:"url,omitempty"`
+	PollInterval time.Duration `mapstructure:"poll_interval" yaml:"poll_interval,omitempty"` // default 30s
+	Timeout      time.Duration `mapstructure:"timeout" yaml:"timeout,omitempty"`             // default 10s
+	// StaleAfter expires alerts not refreshed for this long (default 5m).
+	// Webhook sources need a repeat_interval below it.
+	StaleAfter time.Duration `mapstructure:"stale_after" yaml:"stale_after,omitempty"`
 }
 
 // InhibitionRuleConfig holds a single inhibition rule in config format
@@ -196,9 +654,20 @@ type AppConfig struct {
 
 // MetricsConfig holds metrics-related configuration
 type MetricsConfig struct {
-	Enabled bool   `mapstructure:"enabled"`
-	Path    string `mapstructure:"path"`
-	Port    int    `mapstructure:"port"`
+	Enabled  bool           `mapstructure:"enabled"`
+	Path     string         `mapstructure:"path"`
+	Port     int            `mapstructure:"port"`
+	Watchdog WatchdogConfig `mapstructure:"watchdog"`
+}
+
+// WatchdogConfig holds goroutine/FD/worker leak detection settings.
+// Breached thresholds raise AMP meta-alerts (0 disables a threshold).
+type WatchdogConfig struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	Interval           time.Duration `mapstructure:"interval"`
+	GoroutineThreshold int           `mapstructure:"goroutine_threshold"`
+	FDThreshold        int           `mapstructure:"fd_threshold"`
+	WorkerStaleAfter   time.Duration `mapstructure:"worker_stale_after"`
 }
 
 // WebhookConfig holds webhook endpoint configuration
@@ -210,6 +679,30 @@ type WebhookConfig struct {
 	Authentication  AuthenticationConfig `mapstructure:"authentication"`
 	Signature       SignatureConfig      `mapstructure:"signature"`
 	CORS            CORSWebhookConfig    `mapstructure:"cors"`
+
+	// ClockSkewTolerance absorbs sender clock skew on ingest: StartsAt up to
+	// this far in the future is clamped to now (0 = UTC normalization only).
+	ClockSkewTolerance time.Duration `mapstructure:"clock_skew_tolerance"`
+
+	Mirror WebhookMirrorConfig `mapstructure:"mirror"`
+}
+
+// WebhookMirrorConfig configures forwarding of every accepted webhook to a
+// peer AMP in another region (active-active DR). Forwarding is async and
+// gzip-compressed; mirrored requests are marked and never mirrored again,
+// so two peers can mirror to each other.
+type WebhookMirrorConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// PeerURL is the base URL of the peer AMP, e.g. https://amp.eu-west.example.com.
+	PeerURL string `mapstructure:"peer_url"`
+	// Origin names this instance in the X-AMP-Mirrored-From header
+	// (default: hostname).
+	Origin string `mapstructure:"origin"`
+	// APIKey is the peer's API token when it has auth enabled.
+	APIKey     string        `mapstructure:"api_key"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	QueueSize  int           `mapstructure:"queue_size"`
+	MaxRetries int           `mapstructure:"max_retries"`
 }
 
 // RateLimitingConfig holds rate limiting configuration
@@ -284,17 +777,99 @@ type TelemetryConfig struct {
 
 // PublishingConfig holds runtime publishing configuration.
 type PublishingConfig struct {
-	Enabled   bool                      `mapstructure:"enabled"`
+	Enabled bool `mapstructure:"enabled"`
+	// SeverityPolicy selects the effective severity of published alerts
+	// when both a classification and a severity label are present:
+	// "classification-first" (default), "label-first" or "max-of".
+	SeverityPolicy string `mapstructure:"severity_policy"`
+	// SeverityStyles overrides the emoji and colors of Slack and Teams
+	// messages per severity (critical, warning, info, noise, resolved).
+	// Unset fields keep their defaults; targets can override them again
+	// with emoji_<severity>, color_<severity> and card_color_<severity> headers.
+	SeverityStyles map[string]domain.SeverityStyle `mapstructure:"severity_styles"`
+
 	Discovery PublishingDiscoveryConfig `mapstructure:"discovery"`
 	Queue     PublishingQueueConfig     `mapstructure:"queue"`
 	Refresh   PublishingRefreshConfig   `mapstructure:"refresh"`
 	Health    PublishingHealthConfig    `mapstructure:"health"`
+	SLO       PublishingSLOConfig       `mapstructure:"slo"`
+	DLQReplay PublishingDLQReplayConfig `mapstructure:"dlq_replay"`
+	Routing   PublishingRoutingConfig   `mapstructure:"routing"`
+	Quorum    PublishingQuorumConfig    `mapstructure:"quorum"`
+
+	// Deliveries configures the audit log of publish attempts.
+	Deliveries PublishingDeliveriesConfig `mapstructure:"deliveries"`
+
+	// Plugins are external publisher plugins (pkg/publisherplugin) serving
+	// targets of type "plugin".
+	Plugins []PublisherPluginConfig `mapstructure:"plugins"`
+
+	// Commands are local commands run by targets of type "exec".
+	Commands []ExecCommandConfig `mapstructure:"commands"`
+}
+
+// ExecCommandConfig declares a local command exec targets run with the alert
+// on stdin.
+type ExecCommandConfig struct {
+	// Name is referenced by the URL (exec://<name>) of targets.
+	Name string `mapstructure:"name"`
+	// Path of the executable.
+	Path string   `mapstructure:"path"`
+	Args []string `mapstructure:"args"`
+	// Env holds KEY=VALUE variables set for the command.
+	Env []string `mapstructure:"env"`
+	// EnvAllowlist names the variables of AMP's environment passed to the
+	// command (default: PATH).
+	EnvAllowlist []string `mapstructure:"env_allowlist"`
+	// Timeout bounds a run (default: 30s).
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxConcurrency bounds the runs at a time (default: 4).
+	MaxConcurrency int `mapstructure:"max_concurrency"`
+}
+
+// PublisherPluginConfig declares an external publisher plugin binary,
+// launched and supervised by AMP.
+type PublisherPluginConfig struct {
+	// Name is referenced by the "plugin" header of targets.
+	Name string `mapstructure:"name"`
+	// Path of the plugin binary.
+	Path string   `mapstructure:"path"`
+	Args []string `mapstructure:"args"`
+	// Env holds KEY=VALUE variables added to the environment of AMP (a list,
+	// as configuration keys are case-insensitive).
+	Env []string `mapstructure:"env"`
+	// StartTimeout bounds the launch until the plugin is healthy (default: 10s).
+	StartTimeout time.Duration `mapstructure:"start_timeout"`
+	// HealthInterval between health checks (default: 30s).
+	HealthInterval time.Duration `mapstructure:"health_interval"`
+	// FailureThreshold is the number of failed health checks in a row that
+	// restart the plugin (default: 3).
+	FailureThreshold int `mapstructure:"failure_threshold"`
+}
+
+// PublishingSLOConfig holds the notification delivery SLO: how long a firing
+// alert may take from ingestion until the target provider acknowledged it.
+type PublishingSLOConfig struct {
+	// Thresholds maps a severity to its delivery deadline. A configured map
+	// replaces the defaults entirely.
+	Thresholds map[string]time.Duration `mapstructure:"thresholds"`
+	// DefaultThreshold applies to severities not listed in Thresholds.
+	// 0 leaves them untracked.
+	DefaultThreshold time.Duration `mapstructure:"default_threshold"`
 }
 
 // PublishingDiscoveryConfig holds target discovery settings.
 type PublishingDiscoveryConfig struct {
 	Namespace     string `mapstructure:"namespace"`
 	LabelSelector string `mapstructure:"label_selector"`
+	// Namespaces are searched for targets in addition to Namespace.
+	Namespaces []string `mapstructure:"namespaces"`
+	// ConfigMaps also discovers targets from the config maps matching
+	// LabelSelector.
+	ConfigMaps bool `mapstructure:"config_maps"`
+	// Watch rediscovers the targets as soon as their objects change,
+	// between the periodic refreshes.
+	Watch bool `mapstructure:"watch"`
 }
 
 // PublishingQueueConfig holds publishing queue settings.
@@ -308,6 +883,107 @@ type PublishingQueueConfig struct {
 	RetryInterval           time.Duration `mapstructure:"retry_interval"`
 	StopTimeout             time.Duration `mapstructure:"stop_timeout"`
 	JobTrackingCapacity     int           `mapstructure:"job_tracking_capacity"`
+	// DedupWindow suppresses unchanged alerts (same fingerprint, status,
+	// labels and annotations) submitted again to a target within this
+	// window after their delivery. 0 disables it.
+	DedupWindow time.Duration `mapstructure:"dedup_window"`
+
+	Shedding    PublishingQueueSheddingConfig    `mapstructure:"shedding"`
+	Durable     PublishingQueueDurableConfig     `mapstructure:"durable"`
+	Autoscaling PublishingQueueAutoscalingConfig `mapstructure:"autoscaling"`
+
+	// TargetLimits bounds the publish rate and concurrency of the targets of
+	// a type, keyed by target type (slack, pagerduty, webhook...). The
+	// rate_limit, rate_burst and max_concurrency target headers override
+	// them per target.
+	TargetLimits map[string]PublishingTargetLimitConfig `mapstructure:"target_limits"`
+
+	// CircuitBreaker configures the circuit breakers of the targets.
+	// CircuitBreakers overrides its non-zero fields by target type, and
+	// the circuit_failure_threshold, circuit_success_threshold and
+	// circuit_timeout target headers override them per target.
+	CircuitBreaker  PublishingCircuitBreakerConfig            `mapstructure:"circuit_breaker"`
+	CircuitBreakers map[string]PublishingCircuitBreakerConfig `mapstructure:"circuit_breakers"`
+}
+
+// PublishingCircuitBreakerConfig configures the circuit breaker of a target.
+type PublishingCircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed jobs opening the
+	// breaker.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// SuccessThreshold is the number of successful jobs closing a
+	// half-open breaker.
+	SuccessThreshold int `mapstructure:"success_threshold"`
+	// Timeout is how long an open breaker drops the jobs of its target
+	// before letting one through (half-open).
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// PublishingTargetLimitConfig limits the publishes to a target. Zero values
+// are unlimited.
+type PublishingTargetLimitConfig struct {
+	// RateLimit is the number of publishes per second.
+	RateLimit float64 `mapstructure:"rate_limit"`
+	// Burst is the number of publishes allowed at once above rate_limit
+	// (default: 1).
+	Burst int `mapstructure:"burst"`
+	// MaxConcurrency is the number of publishes in flight.
+	MaxConcurrency int `mapstructure:"max_concurrency"`
+}
+
+// PublishingQueueAutoscalingConfig scales the queue workers between
+// min_workers and max_workers with the load; worker_count is the initial
+// pool size.
+type PublishingQueueAutoscalingConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	MinWorkers int  `mapstructure:"min_workers"`
+	MaxWorkers int  `mapstructure:"max_workers"`
+	// ScaleUpUtilization of the high priority queue (0-1] above which
+	// workers are added.
+	ScaleUpUtilization float64 `mapstructure:"scale_up_utilization"`
+	// ScaleUpLatency is the queue wait above which workers are added (0
+	// ignores the wait).
+	ScaleUpLatency time.Duration `mapstructure:"scale_up_latency"`
+	// ScaleDownAfter is how long the queue must stay idle before workers are
+	// retired.
+	ScaleDownAfter time.Duration `mapstructure:"scale_down_after"`
+	// Interval between scaling decisions.
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// PublishingQueueDurableConfig stores the queued publishing jobs in
+// PostgreSQL or Redis so they survive restarts and crashes (at-least-once
+// delivery).
+type PublishingQueueDurableConfig struct {
+	// Backend is "" (in memory only), "postgres" or "redis".
+	Backend string `mapstructure:"backend"`
+	// VisibilityTimeout is how long the jobs of a crashed replica stay
+	// leased before another replica claims them.
+	VisibilityTimeout time.Duration `mapstructure:"visibility_timeout"`
+	// Owner identifies this replica in the store (default: hostname). A
+	// restarted replica recovers its own jobs without waiting for the
+	// visibility timeout.
+	Owner string `mapstructure:"owner"`
+	// KeyPrefix prefixes the Redis keys.
+	KeyPrefix string `mapstructure:"key_prefix"`
+}
+
+// Durable publishing queue backends.
+const (
+	PublishingQueueBackendPostgres = "postgres"
+	PublishingQueueBackendRedis    = "redis"
+)
+
+// PublishingQueueSheddingConfig holds severity-aware shedding settings: when
+// the queue approaches capacity, low-severity and resolved-alert jobs are
+// dropped first instead of rejecting whatever arrives next.
+type PublishingQueueSheddingConfig struct {
+	// SoftLimit is the queue fill ratio (0-1) at which shedding starts.
+	// 0 disables shedding.
+	SoftLimit float64 `mapstructure:"soft_limit"`
+	// Order lists the classes shed first to last: "resolved" or a
+	// severity. Classes not listed are never shed.
+	Order []string `mapstructure:"order"`
 }
 
 // PublishingRefreshConfig holds dynamic target refresh settings.
@@ -322,6 +998,86 @@ type PublishingRefreshConfig struct {
 	WarmupPeriod time.Duration `mapstructure:"warmup_period"`
 }
 
+// PublishingDLQReplayConfig replays the dead-lettered jobs of a target
+// automatically once it recovered. Requires the PostgreSQL DLQ.
+type PublishingDLQReplayConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CheckInterval is how often target health is checked.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	// HealthyFor is how long a target must stay healthy (closed circuit
+	// breaker, successful health checks) before its jobs are replayed.
+	HealthyFor time.Duration `mapstructure:"healthy_for"`
+	// RateLimit caps the replayed jobs per second across targets.
+	RateLimit float64 `mapstructure:"rate_limit"`
+	// BatchSize caps the jobs of a target replayed per check.
+	BatchSize int `mapstructure:"batch_size"`
+	// MaxBackoff caps the wait before replaying again to a target that
+	// failed again after a replay.
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+}
+
+// PublishingRoutingConfig selects the targets of every published alert
+// with a route tree, as the Alertmanager route tree selects receivers.
+// Alerts are published to all enabled targets when disabled.
+type PublishingRoutingConfig struct {
+	Enabled bool                  `mapstructure:"enabled"`
+	Route   PublishingRouteConfig `mapstructure:"route"`
+}
+
+// PublishingRouteConfig is a route of the publishing route tree. Alerts are
+// matched by their labels, with severity set to their effective severity.
+// The deepest matching routes win; a matching route stops its next siblings
+// unless Continue is set. The root route matches every alert.
+type PublishingRouteConfig struct {
+	// Targets are the names of the targets of the route. Empty inherits
+	// the targets of the parent route; at the root, all targets.
+	Targets []string `mapstructure:"targets"`
+	// Match requires labels to equal these values.
+	Match map[string]string `mapstructure:"match"`
+	// MatchRE requires labels to match these anchored regular expressions.
+	MatchRE  map[string]string       `mapstructure:"match_re"`
+	Continue bool                    `mapstructure:"continue"`
+	Routes   []PublishingRouteConfig `mapstructure:"routes"`
+}
+
+// PublishingDeliveriesConfig configures the audit log of publish attempts,
+// served by /api/v1/publishing/deliveries.
+type PublishingDeliveriesConfig struct {
+	// Persist writes every attempt to the publishing_deliveries table when
+	// a database is available; otherwise only the latest attempts are kept
+	// in memory.
+	Persist bool `mapstructure:"persist"`
+	// Retention is how long persisted attempts are kept.
+	Retention time.Duration `mapstructure:"retention"`
+	// BufferSize bounds the attempts waiting to be written; attempts
+	// recorded while it is full are dropped and counted.
+	BufferSize int `mapstructure:"buffer_size"`
+}
+
+// PublishingQuorumConfig requires the firing alerts of classes to be
+// delivered by a minimum number of targets, and escalates them to fallback
+// targets otherwise. Disabled without policies.
+type PublishingQuorumConfig struct {
+	// Timeout bounds the wait for the delivery outcomes; targets that have
+	// not delivered by then count as failed.
+	Timeout  time.Duration                  `mapstructure:"timeout"`
+	Policies []PublishingQuorumPolicyConfig `mapstructure:"policies"`
+}
+
+// PublishingQuorumPolicyConfig requires the alerts matching Match and
+// MatchRE (as routes match them) to be delivered by at least MinSuccess of
+// Targets. The first matching policy applies.
+type PublishingQuorumPolicyConfig struct {
+	Name       string            `mapstructure:"name"`
+	Match      map[string]string `mapstructure:"match"`
+	MatchRE    map[string]string `mapstructure:"match_re"`
+	Targets    []string          `mapstructure:"targets"`
+	MinSuccess int               `mapstructure:"min_success"`
+	// Fallback are the targets the alert is published to when the quorum
+	// is not met.
+	Fallback []string `mapstructure:"fallback"`
+}
+
 // PublishingHealthConfig holds publishing target health settings.
 type PublishingHealthConfig struct {
 	Enabled             bool          `mapstructure:"enabled"`
@@ -501,11 +1257,21 @@ func setDefaults() {
 	viper.SetDefault("metrics.enabled", true)
 	viper.SetDefault("metrics.path", "/metrics")
 	viper.SetDefault("metrics.port", 8080)
+	viper.SetDefault("metrics.watchdog.enabled", true)
+	viper.SetDefault("metrics.watchdog.interval", "15s")
+	viper.SetDefault("metrics.watchdog.goroutine_threshold", 10000)
+	viper.SetDefault("metrics.watchdog.fd_threshold", 4096)
+	viper.SetDefault("metrics.watchdog.worker_stale_after", "2m")
 
 	// Webhook defaults
 	viper.SetDefault("webhook.max_request_size", 10485760) // 10MB
 	viper.SetDefault("webhook.request_timeout", "30s")
 	viper.SetDefault("webhook.max_alerts_per_request", 1000)
+	viper.SetDefault("webhook.clock_skew_tolerance", "30s")
+	viper.SetDefault("webhook.mirror.enabled", false)
+	viper.SetDefault("webhook.mirror.timeout", "10s")
+	viper.SetDefault("webhook.mirror.queue_size", 1000)
+	viper.SetDefault("webhook.mirror.max_retries", 3)
 
 	// Webhook rate limiting defaults
 	viper.SetDefault("webhook.rate_limiting.enabled", true)
@@ -545,8 +1311,12 @@ func setDefaults() {
 
 	// Publishing defaults
 	viper.SetDefault("publishing.enabled", true)
+	viper.SetDefault("publishing.severity_policy", "classification-first")
 	viper.SetDefault("publishing.discovery.namespace", "")
 	viper.SetDefault("publishing.discovery.label_selector", "publishing-target=true")
+	viper.SetDefault("publishing.discovery.namespaces", []string{})
+	viper.SetDefault("publishing.discovery.config_maps", false)
+	viper.SetDefault("publishing.discovery.watch", true)
 
 	viper.SetDefault("publishing.queue.max_concurrent", 5)
 	viper.SetDefault("publishing.queue.worker_count", 10)
@@ -557,6 +1327,27 @@ func setDefaults() {
 	viper.SetDefault("publishing.queue.retry_interval", "2s")
 	viper.SetDefault("publishing.queue.stop_timeout", "10s")
 	viper.SetDefault("publishing.queue.job_tracking_capacity", 10000)
+	viper.SetDefault("publishing.queue.dedup_window", "0s")
+	viper.SetDefault("publishing.queue.shedding.soft_limit", 0.0)
+	viper.SetDefault("publishing.queue.shedding.order", []string{"resolved", "info", "warning"})
+	viper.SetDefault("publishing.queue.autoscaling.enabled", false)
+	viper.SetDefault("publishing.queue.autoscaling.min_workers", 5)
+	viper.SetDefault("publishing.queue.autoscaling.max_workers", 50)
+	viper.SetDefault("publishing.queue.autoscaling.scale_up_utilization", 0.5)
+	viper.SetDefault("publishing.queue.autoscaling.scale_up_latency", "10s")
+	viper.SetDefault("publishing.queue.autoscaling.scale_down_after", "2m")
+	viper.SetDefault("publishing.queue.autoscaling.interval", "5s")
+	viper.SetDefault("publishing.queue.target_limits.slack.rate_limit", 1.0)
+	viper.SetDefault("publishing.queue.target_limits.slack.burst", 1)
+	viper.SetDefault("publishing.queue.circuit_breaker.failure_threshold", 5)
+	viper.SetDefault("publishing.queue.circuit_breaker.success_threshold", 2)
+	viper.SetDefault("publishing.queue.circuit_breaker.timeout", "30s")
+	viper.SetDefault("publishing.queue.durable.backend", "")
+	viper.SetDefault("publishing.queue.durable.visibility_timeout", "5m")
+	viper.SetDefault("publishing.queue.durable.owner", "")
+	viper.SetDefault("publishing.queue.durable.key_prefix", "amp:publishing_queue")
+	viper.SetDefault("publishing.slo.thresholds", map[string]string{"critical": "30s", "warning": "2m", "info": "5m"})
+	viper.SetDefault("publishing.slo.default_threshold", "5m")
 
 	viper.SetDefault("publishing.refresh.enabled", true)
 	viper.SetDefault("publishing.refresh.interval", "5m")
@@ -567,6 +1358,17 @@ func setDefaults() {
 	viper.SetDefault("publishing.refresh.timeout", "30s")
 	viper.SetDefault("publishing.refresh.warmup_period", "30s")
 
+	viper.SetDefault("publishing.dlq_replay.enabled", false)
+	viper.SetDefault("publishing.routing.enabled", false)
+	viper.SetDefault("publishing.quorum.timeout", "5m")
+	viper.SetDefault("publishing.deliveries.persist", true)
+	viper.SetDefault("publishing.deliveries.retention", "720h")
+	viper.SetDefault("publishing.deliveries.buffer_size", 10000)
+	viper.SetDefault("publishing.dlq_replay.check_interval", "30s")
+	viper.SetDefault("publishing.dlq_replay.healthy_for", "5m")
+	viper.SetDefault("publishing.dlq_replay.rate_limit", 5)
+	viper.SetDefault("publishing.dlq_replay.batch_size", 100)
+	viper.SetDefault("publishing.dlq_replay.max_backoff", "30m")
 	viper.SetDefault("publishing.health.enabled", true)
 	viper.SetDefault("publishing.health.check_interval", "2m")
 	viper.SetDefault("publishing.health.http_timeout", "5s")
@@ -579,6 +1381,111 @@ func setDefaults() {
 	viper.SetDefault("publishing.health.follow_redirects", true)
 	viper.SetDefault("publishing.health.max_redirects", 3)
 
+	// API authentication defaults
+	viper.SetDefault("auth.enabled", false)
+
+	// Slack slash-command defaults
+	viper.SetDefault("slack_command.enabled", false)
+	viper.SetDefault("slack_command.max_duration", "168h")
+	viper.SetDefault("slack_command.rate_limit", 10)
+
+	// PagerDuty webhook defaults
+	viper.SetDefault("pagerduty_webhook.enabled", false)
+
+	// Recurring silence defaults
+	viper.SetDefault("recurring_silences.interval", "1m")
+	viper.SetDefault("recurring_silences.lookahead", "24h")
+
+	// Silence expiry notification defaults
+	viper.SetDefault("silence_expiry.interval", "1m")
+	viper.SetDefault("silence_expiry.lead_time", "15m")
+
+	// Silence webhook defaults
+	viper.SetDefault("silence_webhooks.interval", "1m")
+	viper.SetDefault("silence_webhooks.timeout", "10s")
+	viper.SetDefault("silence_webhooks.queue_size", 1000)
+	viper.SetDefault("silence_webhooks.max_retries", 3)
+
+	// Silence GC defaults (retention matches Alertmanager's --data.retention)
+	viper.SetDefault("silence_gc.enabled", true)
+	viper.SetDefault("silence_gc.interval", "1h")
+	viper.SetDefault("silence_gc.retention", "120h")
+	viper.SetDefault("silence_gc.batch_size", 1000)
+	viper.SetDefault("alert_gc.enabled", true)
+	viper.SetDefault("alert_gc.interval", "1h")
+	viper.SetDefault("alert_gc.retention", "120h")
+	viper.SetDefault("alert_gc.resolve_timeout", "5m")
+	viper.SetDefault("alert_gc.batch_size", 1000)
+
+	// Alert rollup defaults
+	viper.SetDefault("alert_rollups.enabled", true)
+	viper.SetDefault("alert_rollups.interval", "5m")
+	viper.SetDefault("alert_rollups.lateness", "1h")
+	viper.SetDefault("alert_rollups.hourly_retention", "336h")
+	viper.SetDefault("alert_rollups.daily_retention", "9600h")
+	viper.SetDefault("alert_rollups.team_label", "team")
+
+	// Alert replay defaults
+	viper.SetDefault("alert_replay.enabled", false)
+	viper.SetDefault("alert_replay.min_downtime", "0s")
+	viper.SetDefault("alert_replay.resolve_timeout", "5m")
+	viper.SetDefault("alert_replay.timeout", "10s")
+
+	// On-call handoff report defaults
+	viper.SetDefault("handoff_report.enabled", false)
+	viper.SetDefault("handoff_report.schedule", "0 9 * * 1")
+	viper.SetDefault("handoff_report.timezone", "UTC")
+	viper.SetDefault("handoff_report.period", "168h")
+	viper.SetDefault("handoff_report.top_rules", 5)
+	viper.SetDefault("handoff_report.silence_horizon", "72h")
+
+	// Region tagging defaults
+	viper.SetDefault("region_tagging.enabled", false)
+	viper.SetDefault("region_tagging.region_label", "region")
+	viper.SetDefault("region_tagging.zone_label", "zone")
+	viper.SetDefault("region_tagging.source_labels", []string{"instance"})
+	viper.SetDefault("region_tagging.outage_threshold", 3)
+
+	// Routing conditions defaults
+	viper.SetDefault("routing_conditions.enabled", false)
+	viper.SetDefault("routing_conditions.timeout", "5s")
+	viper.SetDefault("routing_conditions.cache_ttl", "30s")
+	viper.SetDefault("routing_conditions.fail_open", true)
+
+	// Silence migration defaults
+	viper.SetDefault("silence_migration.timeout", "10s")
+
+	// Composite alerts defaults
+	viper.SetDefault("composite_alerts.enabled", false)
+
+	// Silence policy defaults
+	viper.SetDefault("silence_policy.enabled", false)
+
+	// Storm detection defaults
+	viper.SetDefault("storm_detection.enabled", false)
+	viper.SetDefault("storm_detection.window", "1m")
+	viper.SetDefault("storm_detection.multiplier", 5)
+	viper.SetDefault("storm_detection.min_alerts", 50)
+	viper.SetDefault("storm_detection.cooldown", "5m")
+	viper.SetDefault("storm_detection.digest_interval", "5m")
+	viper.SetDefault("storm_detection.group_by", []string{"alertname"})
+	viper.SetDefault("storm_detection.critical_severities", []string{"critical"})
+
+	// Alert sampling defaults
+	viper.SetDefault("alert_sampling.enabled", false)
+
+	// Alert trace link defaults
+	viper.SetDefault("alert_traces.enabled", false)
+	viper.SetDefault("alert_traces.url_template", "")
+	viper.SetDefault("alert_traces.traceparent_keys", []string{"traceparent"})
+	viper.SetDefault("alert_traces.trace_id_keys", []string{"trace_id", "traceID"})
+	viper.SetDefault("alert_traces.span_id_keys", []string{"span_id", "spanID"})
+
+	// Silence cache defaults
+	viper.SetDefault("silence_cache.enabled", false)
+	viper.SetDefault("silence_cache.key_prefix", "amp:silences")
+	viper.SetDefault("silence_cache.resync_interval", "30s")
+
 	// Default receivers
 	viper.SetDefault("receivers", []map[string]string{
 		{"name": "default"},
@@ -648,6 +1555,617 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("publishing validation failed: %w", err)
 	}
 
+	if err := c.validateWebhookMirror(); err != nil {
+		return fmt.Errorf("webhook validation failed: %w", err)
+	}
+
+	if err := c.validateAuth(); err != nil {
+		return fmt.Errorf("auth validation failed: %w", err)
+	}
+
+	if err := c.validateSlackCommand(); err != nil {
+		return fmt.Errorf("slack_command validation failed: %w", err)
+	}
+
+	if err := c.validatePagerDutyWebhook(); err != nil {
+		return fmt.Errorf("pagerduty_webhook validation failed: %w", err)
+	}
+
+	if err := c.validateHandoffReport(); err != nil {
+		return fmt.Errorf("handoff_report validation failed: %w", err)
+	}
+
+	if err := c.validateRegionTagging(); err != nil {
+		return fmt.Errorf("region_tagging validation failed: %w", err)
+	}
+
+	if err := c.validateRoutingConditions(); err != nil {
+		return fmt.Errorf("routing_conditions validation failed: %w", err)
+	}
+
+	if err := c.validateSilenceMigration(); err != nil {
+		return fmt.Errorf("silence_migration validation failed: %w", err)
+	}
+
+	if err := c.validateCompositeAlerts(); err != nil {
+		return fmt.Errorf("composite_alerts validation failed: %w", err)
+	}
+
+	if err := c.validateSilenceGC(); err != nil {
+		return fmt.Errorf("silence_gc validation failed: %w", err)
+	}
+	if err := c.validateAlertGC(); err != nil {
+		return fmt.Errorf("alert_gc validation failed: %w", err)
+	}
+
+	if err := c.validateSilenceWebhooks(); err != nil {
+		return fmt.Errorf("silence_webhooks validation failed: %w", err)
+	}
+
+	if err := c.validateAlertRollups(); err != nil {
+		return fmt.Errorf("alert_rollups validation failed: %w", err)
+	}
+
+	if err := c.validateAlertReplay(); err != nil {
+		return fmt.Errorf("alert_replay validation failed: %w", err)
+	}
+
+	if err := c.validateSilenceTemplates(); err != nil {
+		return fmt.Errorf("silence_templates validation failed: %w", err)
+	}
+
+	if err := c.validateSilencePolicy(); err != nil {
+		return fmt.Errorf("silence_policy validation failed: %w", err)
+	}
+
+	if err := c.validateStormDetection(); err != nil {
+		return fmt.Errorf("storm_detection validation failed: %w", err)
+	}
+
+	if err := c.validateAlertTraces(); err != nil {
+		return fmt.Errorf("alert_traces validation failed: %w", err)
+	}
+
+	if err := c.validateAlertSampling(); err != nil {
+		return fmt.Errorf("alert_sampling validation failed: %w", err)
+	}
+
+	if err := c.validateSilenceCache(); err != nil {
+		return fmt.Errorf("silence_cache validation failed: %w", err)
+	}
+
+	if err := c.validateInhibitionExternalSources(); err != nil {
+		return fmt.Errorf("inhibition validation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Config) validateWebhookMirror() error {
+	m := c.Webhook.Mirror
+	if !m.Enabled {
+		return nil
+	}
+	if u, err := url.Parse(m.PeerURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("mirror.peer_url must be an absolute URL when mirroring is enabled")
+	}
+	if m.Timeout <= 0 {
+		return fmt.Errorf("mirror.timeout must be positive")
+	}
+	if m.QueueSize <= 0 {
+		return fmt.Errorf("mirror.queue_size must be positive")
+	}
+	if m.MaxRetries < 0 {
+		return fmt.Errorf("mirror.max_retries must be non-negative")
+	}
+	return nil
+}
+
+func (c *Config) validateAuth() error {
+	if !c.Auth.Enabled {
+		return nil
+	}
+	if len(c.Auth.Tokens) == 0 {
+		return fmt.Errorf("auth.tokens must not be empty when auth is enabled")
+	}
+
+	names := make(map[string]struct{}, len(c.Auth.Tokens))
+	for i, token := range c.Auth.Tokens {
+		if token.Name == "" {
+			return fmt.Errorf("auth.tokens[%d].name cannot be empty", i)
+		}
+		if _, dup := names[token.Name]; dup {
+			return fmt.Errorf("auth.tokens[%d].name %q is duplicated", i, token.Name)
+		}
+		names[token.Name] = struct{}{}
+		if token.Token == "" {
+			return fmt.Errorf("auth.tokens[%d].token cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateSlackCommand() error {
+	if !c.SlackCommand.Enabled {
+		return nil
+	}
+	if c.SlackCommand.SigningSecret == "" {
+		return fmt.Errorf("slack_command.signing_secret cannot be empty when enabled")
+	}
+	if c.SlackCommand.MaxDuration <= 0 {
+		return fmt.Errorf("slack_command.max_duration must be positive")
+	}
+	if c.SlackCommand.RateLimit <= 0 {
+		return fmt.Errorf("slack_command.rate_limit must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validatePagerDutyWebhook() error {
+	if !c.PagerDutyWebhook.Enabled {
+		return nil
+	}
+	if len(c.PagerDutyWebhook.SigningSecrets) == 0 {
+		return fmt.Errorf("pagerduty_webhook.signing_secrets cannot be empty when enabled")
+	}
+	for _, secret := range c.PagerDutyWebhook.SigningSecrets {
+		if strings.TrimSpace(secret) == "" {
+			return fmt.Errorf("pagerduty_webhook.signing_secrets cannot contain empty secrets")
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateHandoffReport() error {
+	if !c.HandoffReport.Enabled {
+		return nil
+	}
+	if strings.TrimSpace(c.HandoffReport.Schedule) == "" {
+		return fmt.Errorf("handoff_report.schedule cannot be empty when enabled")
+	}
+	if c.HandoffReport.Period <= 0 {
+		return fmt.Errorf("handoff_report.period must be positive")
+	}
+	if len(c.HandoffReport.Teams) == 0 {
+		return fmt.Errorf("handoff_report.teams must not be empty when enabled")
+	}
+
+	names := make(map[string]struct{}, len(c.HandoffReport.Teams))
+	for i, team := range c.HandoffReport.Teams {
+		if team.Name == "" {
+			return fmt.Errorf("handoff_report.teams[%d].name cannot be empty", i)
+		}
+		if _, dup := names[team.Name]; dup {
+			return fmt.Errorf("handoff_report.teams[%d].name %q is duplicated", i, team.Name)
+		}
+		names[team.Name] = struct{}{}
+		if team.Target == "" {
+			return fmt.Errorf("handoff_report.teams[%d].target cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateRegionTagging() error {
+	if !c.RegionTagging.Enabled {
+		return nil
+	}
+	if c.RegionTagging.RegionLabel == "" {
+		return fmt.Errorf("region_tagging.region_label cannot be empty")
+	}
+	if len(c.RegionTagging.SourceLabels) == 0 {
+		return fmt.Errorf("region_tagging.source_labels must not be empty when enabled")
+	}
+	if len(c.RegionTagging.Rules) == 0 && c.RegionTagging.MappingFile == "" {
+		return fmt.Errorf("region_tagging requires rules or a mapping_file when enabled")
+	}
+	if c.RegionTagging.OutageThreshold <= 0 {
+		return fmt.Errorf("region_tagging.outage_threshold must be positive")
+	}
+	for i, rule := range c.RegionTagging.Rules {
+		if rule.Region == "" {
+			return fmt.Errorf("region_tagging.rules[%d].region cannot be empty", i)
+		}
+		if rule.CIDR == "" && rule.HostPattern == "" {
+			return fmt.Errorf("region_tagging.rules[%d] needs a cidr or host_pattern", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateRoutingConditions() error {
+	if !c.RoutingConditions.Enabled {
+		return nil
+	}
+	if c.RoutingConditions.PrometheusURL == "" {
+		return fmt.Errorf("routing_conditions.prometheus_url is required when enabled")
+	}
+	if c.RoutingConditions.Timeout <= 0 {
+		return fmt.Errorf("routing_conditions.timeout must be positive")
+	}
+	if c.RoutingConditions.CacheTTL < 0 {
+		return fmt.Errorf("routing_conditions.cache_ttl cannot be negative")
+	}
+	names := make(map[string]bool, len(c.RoutingConditions.Rules))
+	for i, rule := range c.RoutingConditions.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("routing_conditions.rules[%d].name cannot be empty", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("routing_conditions.rules[%d]: duplicate name %q", i, rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.Query == "" {
+			return fmt.Errorf("routing_conditions.rules[%d].query cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateSilenceMigration() error {
+	if c.SilenceMigration.AlertmanagerURL == "" {
+		return nil
+	}
+	u, err := url.Parse(c.SilenceMigration.AlertmanagerURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("silence_migration.alertmanager_url must be an absolute URL")
+	}
+	if c.SilenceMigration.Timeout <= 0 {
+		return fmt.Errorf("silence_migration.timeout must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateCompositeAlerts() error {
+	if !c.CompositeAlerts.Enabled {
+		return nil
+	}
+	names := make(map[string]bool, len(c.CompositeAlerts.Rules))
+	for i, rule := range c.CompositeAlerts.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("composite_alerts.rules[%d].name cannot be empty", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("composite_alerts.rules[%d]: duplicate name %q", i, rule.Name)
+		}
+		names[rule.Name] = true
+		if len(rule.Selector) == 0 {
+			return fmt.Errorf("composite_alerts.rules[%d].selector cannot be empty", i)
+		}
+		if rule.Threshold < 2 {
+			return fmt.Errorf("composite_alerts.rules[%d].threshold must be at least 2", i)
+		}
+		if rule.Window <= 0 {
+			return fmt.Errorf("composite_alerts.rules[%d].window must be positive", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateSilenceGC() error {
+	if !c.SilenceGC.Enabled {
+		return nil
+	}
+	if c.SilenceGC.Interval <= 0 {
+		return fmt.Errorf("silence_gc.interval must be positive")
+	}
+	if c.SilenceGC.Retention < time.Hour {
+		return fmt.Errorf("silence_gc.retention must be at least 1h")
+	}
+	if c.SilenceGC.BatchSize <= 0 {
+		return fmt.Errorf("silence_gc.batch_size must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateAlertGC() error {
+	if !c.AlertGC.Enabled {
+		return nil
+	}
+	if c.AlertGC.Interval <= 0 {
+		return fmt.Errorf("alert_gc.interval must be positive")
+	}
+	if c.AlertGC.Retention < time.Hour {
+		return fmt.Errorf("alert_gc.retention must be at least 1h")
+	}
+	if c.AlertGC.ResolveTimeout <= 0 {
+		return fmt.Errorf("alert_gc.resolve_timeout must be positive")
+	}
+	if c.AlertGC.BatchSize <= 0 {
+		return fmt.Errorf("alert_gc.batch_size must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateAlertRollups() error {
+	r := c.AlertRollups
+	if !r.Enabled {
+		return nil
+	}
+	if r.Interval <= 0 {
+		return fmt.Errorf("alert_rollups.interval must be positive")
+	}
+	if r.Lateness < 0 {
+		return fmt.Errorf("alert_rollups.lateness must not be negative")
+	}
+	// Daily rollups are summed up from the hourly ones
+	if r.HourlyRetention < 24*time.Hour {
+		return fmt.Errorf("alert_rollups.hourly_retention must be at least 24h")
+	}
+	if r.DailyRetention < r.HourlyRetention {
+		return fmt.Errorf("alert_rollups.daily_retention must be at least alert_rollups.hourly_retention")
+	}
+	if r.TeamLabel == "" {
+		return fmt.Errorf("alert_rollups.team_label is required")
+	}
+	return nil
+}
+
+func (c *Config) validateAlertReplay() error {
+	r := c.AlertReplay
+	if !r.Enabled {
+		return nil
+	}
+	if len(r.Sources) == 0 {
+		return fmt.Errorf("alert_replay.sources must not be empty")
+	}
+	if r.MinDowntime < 0 {
+		return fmt.Errorf("alert_replay.min_downtime must not be negative")
+	}
+	if r.ResolveTimeout <= 0 {
+		return fmt.Errorf("alert_replay.resolve_timeout must be positive")
+	}
+	if r.Timeout <= 0 {
+		return fmt.Errorf("alert_replay.timeout must be positive")
+	}
+	names := make(map[string]bool, len(r.Sources))
+	for i, src := range r.Sources {
+		if !externalSourceNameRE.MatchString(src.Name) {
+			return fmt.Errorf("alert_replay.sources[%d].name must be non-empty and contain only letters, digits, '_', '.' and '-'", i)
+		}
+		if names[src.Name] {
+			return fmt.Errorf("alert_replay.sources[%d].name %q is duplicated", i, src.Name)
+		}
+		names[src.Name] = true
+		if (src.PrometheusURL == "") == (src.AlertmanagerURL == "") {
+			return fmt.Errorf("alert_replay.sources[%d]: exactly one of prometheus_url and alertmanager_url is required", i)
+		}
+		u, err := url.Parse(src.PrometheusURL + src.AlertmanagerURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("alert_replay.sources[%d]: URL must be absolute", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateSilenceWebhooks() error {
+	w := c.SilenceWebhooks
+	if len(w.Endpoints) == 0 {
+		return nil
+	}
+	for i, endpoint := range w.Endpoints {
+		if u, err := url.Parse(endpoint.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("silence_webhooks.endpoints[%d].url must be an absolute HTTP or HTTPS URL", i)
+		}
+		for _, event := range endpoint.Events {
+			switch event {
+			case "silence.created", "silence.updated", "silence.expired":
+			default:
+				return fmt.Errorf("silence_webhooks.endpoints[%d].events: unknown event %q (must be one of silence.created, silence.updated, silence.expired)", i, event)
+			}
+		}
+	}
+	if w.Interval <= 0 {
+		return fmt.Errorf("silence_webhooks.interval must be positive")
+	}
+	if w.Timeout <= 0 {
+		return fmt.Errorf("silence_webhooks.timeout must be positive")
+	}
+	if w.QueueSize <= 0 {
+		return fmt.Errorf("silence_webhooks.queue_size must be positive")
+	}
+	if w.MaxRetries < 0 {
+		return fmt.Errorf("silence_webhooks.max_retries must be non-negative")
+	}
+	return nil
+}
+
+func (c *Config) validateSilenceTemplates() error {
+	names := make(map[string]bool, len(c.SilenceTemplates))
+	for i, tmpl := range c.SilenceTemplates {
+		if tmpl.Name == "" || strings.Contains(tmpl.Name, "/") {
+			return fmt.Errorf("silence_templates[%d].name must be non-empty and must not contain '/'", i)
+		}
+		if names[tmpl.Name] {
+			return fmt.Errorf("silence_templates[%d]: duplicate name %q", i, tmpl.Name)
+		}
+		names[tmpl.Name] = true
+		if len(tmpl.Matchers) == 0 {
+			return fmt.Errorf("silence_templates[%d].matchers cannot be empty", i)
+		}
+		for j, m := range tmpl.Matchers {
+			if m.Name == "" {
+				return fmt.Errorf("silence_templates[%d].matchers[%d].name cannot be empty", i, j)
+			}
+		}
+		if tmpl.Duration <= 0 {
+			return fmt.Errorf("silence_templates[%d].duration must be positive", i)
+		}
+		if tmpl.MaxDuration < 0 || (tmpl.MaxDuration > 0 && tmpl.MaxDuration < tmpl.Duration) {
+			return fmt.Errorf("silence_templates[%d].max_duration must be at least duration", i)
+		}
+		if strings.TrimSpace(tmpl.Comment) == "" {
+			return fmt.Errorf("silence_templates[%d].comment cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateSilencePolicy() error {
+	cfg := c.SilencePolicy
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.MaxDuration < 0 {
+		return fmt.Errorf("silence_policy.max_duration cannot be negative")
+	}
+	for i, group := range cfg.Groups {
+		if group.Name == "" {
+			return fmt.Errorf("silence_policy.groups[%d].name cannot be empty", i)
+		}
+		if len(group.Members) == 0 {
+			return fmt.Errorf("silence_policy.groups[%d].members cannot be empty", i)
+		}
+		if group.MaxDuration < 0 {
+			return fmt.Errorf("silence_policy.groups[%d].max_duration cannot be negative", i)
+		}
+	}
+	for i, pattern := range cfg.CommentPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("silence_policy.comment_patterns[%d]: %w", i, err)
+		}
+	}
+	for i, set := range cfg.Protected {
+		if len(set) == 0 {
+			return fmt.Errorf("silence_policy.protected[%d] cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateStormDetection() error {
+	cfg := c.StormDetection
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Window <= 0 {
+		return fmt.Errorf("storm_detection.window must be positive")
+	}
+	if cfg.Multiplier <= 1 {
+		return fmt.Errorf("storm_detection.multiplier must be greater than 1")
+	}
+	if cfg.MinAlerts <= 0 {
+		return fmt.Errorf("storm_detection.min_alerts must be positive")
+	}
+	if cfg.Cooldown < cfg.Window {
+		return fmt.Errorf("storm_detection.cooldown must be at least one window")
+	}
+	if cfg.DigestInterval < cfg.Window {
+		return fmt.Errorf("storm_detection.digest_interval must be at least one window")
+	}
+	return nil
+}
+
+func (c *Config) validateAlertSampling() error {
+	cfg := c.AlertSampling
+	if !cfg.Enabled {
+		return nil
+	}
+	if len(cfg.Policies) == 0 {
+		return fmt.Errorf("alert_sampling requires at least one policy")
+	}
+	names := make(map[string]bool, len(cfg.Policies))
+	for i, policy := range cfg.Policies {
+		if policy.Name == "" {
+			return fmt.Errorf("alert_sampling.policies[%d].name is required", i)
+		}
+		if names[policy.Name] {
+			return fmt.Errorf("alert_sampling.policies[%d]: duplicate name %q", i, policy.Name)
+		}
+		names[policy.Name] = true
+		if policy.ClassificationRate == nil && policy.EnrichmentRate == nil {
+			return fmt.Errorf("alert_sampling.policies[%d] must set classification_rate or enrichment_rate", i)
+		}
+		for field, rate := range map[string]*float64{
+			"classification_rate": policy.ClassificationRate,
+			"enrichment_rate":     policy.EnrichmentRate,
+		} {
+			if rate != nil && (*rate < 0 || *rate > 1) {
+				return fmt.Errorf("alert_sampling.policies[%d].%s must be between 0 and 1", i, field)
+			}
+		}
+		for field, pattern := range map[string]string{"alertname": policy.AlertName, "severity": policy.Severity} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("alert_sampling.policies[%d].%s: %w", i, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateAlertTraces() error {
+	cfg := c.AlertTraces
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.URLTemplate != "" {
+		if _, err := template.New("url_template").Parse(cfg.URLTemplate); err != nil {
+			return fmt.Errorf("alert_traces.url_template: %w", err)
+		}
+	}
+	if len(cfg.TraceparentKeys) == 0 && len(cfg.TraceIDKeys) == 0 {
+		return fmt.Errorf("alert_traces requires traceparent_keys or trace_id_keys")
+	}
+	return nil
+}
+
+func (c *Config) validateSilenceCache() error {
+	cfg := c.SilenceCache
+	if !cfg.Enabled {
+		return nil
+	}
+	if c.Redis.Addr == "" {
+		return fmt.Errorf("silence_cache requires redis.addr")
+	}
+	if cfg.KeyPrefix == "" {
+		return fmt.Errorf("silence_cache.key_prefix cannot be empty")
+	}
+	if cfg.ResyncInterval <= 0 {
+		return fmt.Errorf("silence_cache.resync_interval must be positive")
+	}
+	return nil
+}
+
+var externalSourceNameRE = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func (c *Config) validateInhibitionExternalSources() error {
+	names := make(map[string]struct{}, len(c.Inhibition.ExternalSources))
+	for i, src := range c.Inhibition.ExternalSources {
+		if !externalSourceNameRE.MatchString(src.Name) {
+			return fmt.Errorf("external_sources[%d].name must be non-empty and contain only letters, digits, '_', '.' and '-'", i)
+		}
+		if _, dup := names[src.Name]; dup {
+			return fmt.Errorf("external_sources[%d].name %q is duplicated", i, src.Name)
+		}
+		names[src.Name] = struct{}{}
+		if src.URL != "" {
+			u, err := url.Parse(src.URL)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("external_sources[%d].url must be an absolute URL", i)
+			}
+		}
+		if src.PollInterval < 0 || src.Timeout < 0 || src.StaleAfter < 0 {
+			return fmt.Errorf("external_sources[%d]: durations must not be negative", i)
+		}
+	}
+	return nil
+}
+
+var publisherPluginNameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// validatePublishingRoute checks the regular expressions of route and its
+// children.
+func validatePublishingRoute(route PublishingRouteConfig, path string) error {
+	for name, pattern := range route.MatchRE {
+		if _, err := regexp.Compile("^(?:" + pattern + ")$"); err != nil {
+			return fmt.Errorf("%s.match_re.%s: %w", path, name, err)
+		}
+	}
+	for i, child := range route.Routes {
+		if err := validatePublishingRoute(child, fmt.Sprintf("%s.routes[%d]", path, i)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -656,6 +2174,19 @@ func (c *Config) validatePublishing() error {
 		return nil
 	}
 
+	if _, err := domain.ParseSeverityPolicy(c.Publishing.SeverityPolicy); err != nil {
+		return fmt.Errorf("publishing.severity_policy: %w", err)
+	}
+	for key, style := range c.Publishing.SeverityStyles {
+		if !domain.IsSeverityStyleKey(key) {
+			return fmt.Errorf("publishing.severity_styles: unknown severity %q (must be one of %s)",
+				key, strings.Join(domain.SeverityStyleKeys, ", "))
+		}
+		if err := domain.ValidateSeverityStyle(style); err != nil {
+			return fmt.Errorf("publishing.severity_styles.%s: %w", key, err)
+		}
+	}
+
 	if c.Publishing.Queue.MaxConcurrent <= 0 {
 		return fmt.Errorf("publishing.queue.max_concurrent must be positive")
 	}
@@ -683,6 +2214,153 @@ func (c *Config) validatePublishing() error {
 	if c.Publishing.Queue.JobTrackingCapacity <= 0 {
 		return fmt.Errorf("publishing.queue.job_tracking_capacity must be positive")
 	}
+	if window := c.Publishing.Queue.DedupWindow; window < 0 || window > 24*time.Hour {
+		return fmt.Errorf("publishing.queue.dedup_window must be between 0 and 24h")
+	}
+	if limit := c.Publishing.Queue.Shedding.SoftLimit; limit < 0 || limit >= 1 {
+		return fmt.Errorf("publishing.queue.shedding.soft_limit must be in [0, 1)")
+	}
+	seenShedClasses := make(map[string]bool, len(c.Publishing.Queue.Shedding.Order))
+	for _, class := range c.Publishing.Queue.Shedding.Order {
+		if class == "" || seenShedClasses[class] {
+			return fmt.Errorf("publishing.queue.shedding.order must list distinct, non-empty classes")
+		}
+		seenShedClasses[class] = true
+	}
+	if scaling := c.Publishing.Queue.Autoscaling; scaling.Enabled {
+		if scaling.MinWorkers <= 0 || scaling.MaxWorkers <= scaling.MinWorkers {
+			return fmt.Errorf("publishing.queue.autoscaling requires 0 < min_workers < max_workers")
+		}
+		if scaling.ScaleUpUtilization <= 0 || scaling.ScaleUpUtilization > 1 {
+			return fmt.Errorf("publishing.queue.autoscaling.scale_up_utilization must be in (0, 1]")
+		}
+		if scaling.ScaleUpLatency < 0 {
+			return fmt.Errorf("publishing.queue.autoscaling.scale_up_latency must be non-negative")
+		}
+		if scaling.ScaleDownAfter <= 0 || scaling.Interval <= 0 {
+			return fmt.Errorf("publishing.queue.autoscaling.scale_down_after and interval must be positive")
+		}
+	}
+	for targetType, limit := range c.Publishing.Queue.TargetLimits {
+		if limit.RateLimit < 0 || limit.Burst < 0 {
+			return fmt.Errorf("publishing.queue.target_limits.%s: rate_limit and burst must be non-negative", targetType)
+		}
+		if limit.MaxConcurrency < 0 || limit.MaxConcurrency > 1000 {
+			return fmt.Errorf("publishing.queue.target_limits.%s.max_concurrency must be between 0 and 1000", targetType)
+		}
+	}
+	if breaker := c.Publishing.Queue.CircuitBreaker; breaker.FailureThreshold < 1 || breaker.SuccessThreshold < 1 || breaker.Timeout <= 0 {
+		return fmt.Errorf("publishing.queue.circuit_breaker: failure_threshold, success_threshold and timeout must be positive")
+	}
+	for targetType, breaker := range c.Publishing.Queue.CircuitBreakers {
+		if breaker.FailureThreshold < 0 || breaker.SuccessThreshold < 0 || breaker.Timeout < 0 {
+			return fmt.Errorf("publishing.queue.circuit_breakers.%s: failure_threshold, success_threshold and timeout must be non-negative", targetType)
+		}
+	}
+	switch durable := c.Publishing.Queue.Durable; durable.Backend {
+	case "":
+	case PublishingQueueBackendPostgres, PublishingQueueBackendRedis:
+		if durable.VisibilityTimeout < time.Second {
+			return fmt.Errorf("publishing.queue.durable.visibility_timeout must be at least 1s")
+		}
+		if durable.Backend == PublishingQueueBackendRedis {
+			if c.Redis.Addr == "" {
+				return fmt.Errorf("publishing.queue.durable.backend redis requires redis.addr")
+			}
+			if durable.KeyPrefix == "" {
+				return fmt.Errorf("publishing.queue.durable.key_prefix cannot be empty")
+			}
+		}
+	default:
+		return fmt.Errorf("publishing.queue.durable.backend must be postgres or redis, got %q", durable.Backend)
+	}
+	for severity, threshold := range c.Publishing.SLO.Thresholds {
+		if threshold <= 0 {
+			return fmt.Errorf("publishing.slo.thresholds.%s must be positive", severity)
+		}
+	}
+	if c.Publishing.SLO.DefaultThreshold < 0 {
+		return fmt.Errorf("publishing.slo.default_threshold must be non-negative")
+	}
+	if c.Publishing.Routing.Enabled {
+		route := c.Publishing.Routing.Route
+		if len(route.Match) > 0 || len(route.MatchRE) > 0 {
+			return fmt.Errorf("publishing.routing.route: the root route cannot have matchers")
+		}
+		if err := validatePublishingRoute(route, "publishing.routing.route"); err != nil {
+			return err
+		}
+	}
+	if len(c.Publishing.Quorum.Policies) > 0 && c.Publishing.Quorum.Timeout <= 0 {
+		return fmt.Errorf("publishing.quorum.timeout must be positive")
+	}
+	quorumPolicies := make(map[string]bool, len(c.Publishing.Quorum.Policies))
+	for i, policy := range c.Publishing.Quorum.Policies {
+		path := fmt.Sprintf("publishing.quorum.policies[%d]", i)
+		if policy.Name == "" || quorumPolicies[policy.Name] {
+			return fmt.Errorf("%s.name must be unique and non-empty", path)
+		}
+		quorumPolicies[policy.Name] = true
+		if len(policy.Targets) == 0 {
+			return fmt.Errorf("%s.targets is required", path)
+		}
+		if policy.MinSuccess < 1 || policy.MinSuccess > len(policy.Targets) {
+			return fmt.Errorf("%s.min_success must be between 1 and the number of targets", path)
+		}
+		if err := validatePublishingRoute(PublishingRouteConfig{MatchRE: policy.MatchRE}, path); err != nil {
+			return err
+		}
+	}
+	if c.Publishing.Deliveries.Persist {
+		if c.Publishing.Deliveries.Retention <= 0 {
+			return fmt.Errorf("publishing.deliveries.retention must be positive")
+		}
+		if c.Publishing.Deliveries.BufferSize <= 0 {
+			return fmt.Errorf("publishing.deliveries.buffer_size must be positive")
+		}
+	}
+	pluginNames := make(map[string]bool, len(c.Publishing.Plugins))
+	for i, plugin := range c.Publishing.Plugins {
+		if !publisherPluginNameRE.MatchString(plugin.Name) {
+			return fmt.Errorf("publishing.plugins[%d].name must be lowercase alphanumeric with hyphens", i)
+		}
+		if pluginNames[plugin.Name] {
+			return fmt.Errorf("publishing.plugins[%d]: duplicate plugin %q", i, plugin.Name)
+		}
+		pluginNames[plugin.Name] = true
+		if plugin.Path == "" {
+			return fmt.Errorf("publishing.plugins[%d].path is required", i)
+		}
+		for _, variable := range plugin.Env {
+			if key, _, ok := strings.Cut(variable, "="); !ok || key == "" {
+				return fmt.Errorf("publishing.plugins[%d].env: %q is not KEY=VALUE", i, variable)
+			}
+		}
+		if plugin.StartTimeout < 0 || plugin.HealthInterval < 0 || plugin.FailureThreshold < 0 {
+			return fmt.Errorf("publishing.plugins[%d]: start_timeout, health_interval and failure_threshold must be non-negative", i)
+		}
+	}
+	commandNames := make(map[string]bool, len(c.Publishing.Commands))
+	for i, command := range c.Publishing.Commands {
+		if !publisherPluginNameRE.MatchString(command.Name) {
+			return fmt.Errorf("publishing.commands[%d].name must be lowercase alphanumeric with hyphens", i)
+		}
+		if commandNames[command.Name] {
+			return fmt.Errorf("publishing.commands[%d]: duplicate command %q", i, command.Name)
+		}
+		commandNames[command.Name] = true
+		if command.Path == "" {
+			return fmt.Errorf("publishing.commands[%d].path is required", i)
+		}
+		for _, variable := range command.Env {
+			if key, _, ok := strings.Cut(variable, "="); !ok || key == "" {
+				return fmt.Errorf("publishing.commands[%d].env: %q is not KEY=VALUE", i, variable)
+			}
+		}
+		if command.Timeout < 0 || command.MaxConcurrency < 0 {
+			return fmt.Errorf("publishing.commands[%d]: timeout and max_concurrency must be non-negative", i)
+		}
+	}
 
 	if c.Publishing.Refresh.Enabled {
 		if c.Publishing.Refresh.Interval <= 0 {
@@ -735,6 +2413,24 @@ func (c *Config) validatePublishing() error {
 		}
 	}
 
+	if replay := c.Publishing.DLQReplay; replay.Enabled {
+		if replay.CheckInterval <= 0 {
+			return fmt.Errorf("publishing.dlq_replay.check_interval must be positive")
+		}
+		if replay.HealthyFor <= 0 {
+			return fmt.Errorf("publishing.dlq_replay.healthy_for must be positive")
+		}
+		if replay.RateLimit <= 0 {
+			return fmt.Errorf("publishing.dlq_replay.rate_limit must be positive")
+		}
+		if replay.BatchSize <= 0 || replay.BatchSize > 1000 {
+			return fmt.Errorf("publishing.dlq_replay.batch_size must be between 1 and 1000")
+		}
+		if replay.MaxBackoff < replay.CheckInterval {
+			return fmt.Errorf("publishing.dlq_replay.max_backoff must be at least check_interval")
+		}
+	}
+
 	return nil
 }
 
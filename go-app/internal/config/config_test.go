@@ -63,6 +63,10 @@ func TestLoadConfigFromEnv_Defaults(t *testing.T) {
 	assert.Equal(t, "postgres", cfg.Database.Driver)
 	assert.Equal(t, "localhost", cfg.Database.Host)
 	assert.Equal(t, "alerthistory", cfg.Database.Database)
+	assert.Equal(t, time.Minute, cfg.RecurringSilences.Interval)
+	assert.Equal(t, 24*time.Hour, cfg.RecurringSilences.Lookahead)
+	assert.Equal(t, time.Minute, cfg.SilenceExpiry.Interval)
+	assert.Equal(t, 15*time.Minute, cfg.SilenceExpiry.LeadTime)
 }
 
 func TestLoadConfig_File(t *testing.T) {
@@ -228,3 +232,1401 @@ publishing:
 	require.Error(t, err)
 	assert.Nil(t, cfg)
 }
+
+func TestLoadConfig_SeverityPolicy(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  severity_policy: "max-of"
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, "max-of", cfg.Publishing.SeverityPolicy)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  severity_policy: "highest"
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "severity_policy")
+}
+
+func TestLoadConfig_SeverityStyles(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  severity_styles:
+    critical:
+      emoji: "🚨"
+    info:
+      color: "#439FE0"
+      card_color: accent
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, "🚨", cfg.Publishing.SeverityStyles["critical"].Emoji)
+	assert.Equal(t, "#439FE0", cfg.Publishing.SeverityStyles["info"].Color)
+	assert.Equal(t, "accent", cfg.Publishing.SeverityStyles["info"].CardColor)
+
+	for name, style := range map[string]string{
+		"unknown severity": "page:\n      emoji: \":pager:\"",
+		"double-encoded":   "info:\n      emoji: \"â„¹ï¸\"",
+		"invalid color":    "warning:\n      color: orange",
+	} {
+		t.Run(name, func(t *testing.T) {
+			resetViper()
+			yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  severity_styles:
+    ` + style + "\n"
+			cfg, err := LoadConfig(writeTempYAML(t, yaml))
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), "severity_styles")
+		})
+	}
+}
+
+func TestLoadConfig_QueueShedding(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    shedding:
+      soft_limit: 0.75
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, 0.75, cfg.Publishing.Queue.Shedding.SoftLimit)
+	assert.Equal(t, []string{"resolved", "info", "warning"}, cfg.Publishing.Queue.Shedding.Order)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    shedding:
+      soft_limit: 1.5
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "shedding.soft_limit")
+}
+
+func TestLoadConfig_QueueAutoscaling(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    autoscaling:
+      enabled: true
+      max_workers: 20
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	scaling := cfg.Publishing.Queue.Autoscaling
+	assert.Equal(t, 5, scaling.MinWorkers)
+	assert.Equal(t, 20, scaling.MaxWorkers)
+	assert.Equal(t, 0.5, scaling.ScaleUpUtilization)
+	assert.Equal(t, 2*time.Minute, scaling.ScaleDownAfter)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    autoscaling:
+      enabled: true
+      min_workers: 10
+      max_workers: 10
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "min_workers < max_workers")
+}
+
+func TestLoadConfig_QueueTargetLimits(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    target_limits:
+      slack:
+        max_concurrency: 2
+      pagerduty:
+        rate_limit: 50
+        burst: 10
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	limits := cfg.Publishing.Queue.TargetLimits
+	assert.Equal(t, PublishingTargetLimitConfig{RateLimit: 1, Burst: 1, MaxConcurrency: 2}, limits["slack"])
+	assert.Equal(t, PublishingTargetLimitConfig{RateLimit: 50, Burst: 10}, limits["pagerduty"])
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    target_limits:
+      webhook:
+        max_concurrency: -1
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "target_limits.webhook.max_concurrency")
+}
+
+func TestLoadConfig_QueueDedupWindow(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    dedup_window: 5m
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, cfg.Publishing.Queue.DedupWindow)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    dedup_window: 48h
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.queue.dedup_window")
+}
+
+func TestLoadConfig_DurablePublishingQueue(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+redis:
+  addr: "localhost:6379"
+publishing:
+  enabled: true
+  queue:
+    durable:
+      backend: redis
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, PublishingQueueBackendRedis, cfg.Publishing.Queue.Durable.Backend)
+	assert.Equal(t, 5*time.Minute, cfg.Publishing.Queue.Durable.VisibilityTimeout)
+	assert.Equal(t, "amp:publishing_queue", cfg.Publishing.Queue.Durable.KeyPrefix)
+
+	tests := []struct {
+		name    string
+		durable string
+		wantErr string
+	}{
+		{"unknown backend", "backend: kafka", "durable.backend must be postgres or redis"},
+		{"short visibility timeout", "backend: postgres\n      visibility_timeout: 100ms", "durable.visibility_timeout"},
+		{"redis without key prefix", "backend: redis\n      key_prefix: \"\"", "durable.key_prefix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetViper()
+			yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    durable:
+      ` + tt.durable + `
+`
+			cfg, err := LoadConfig(writeTempYAML(t, yaml))
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_PublishingDLQReplay(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  dlq_replay:
+    enabled: true
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Publishing.DLQReplay.CheckInterval)
+	assert.Equal(t, 5*time.Minute, cfg.Publishing.DLQReplay.HealthyFor)
+	assert.Equal(t, 5.0, cfg.Publishing.DLQReplay.RateLimit)
+	assert.Equal(t, 100, cfg.Publishing.DLQReplay.BatchSize)
+	assert.Equal(t, 30*time.Minute, cfg.Publishing.DLQReplay.MaxBackoff)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  dlq_replay:
+    enabled: true
+    check_interval: 1m
+    max_backoff: 30s
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "dlq_replay.max_backoff")
+}
+
+func TestLoadConfig_PublishingSLO(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Publishing.SLO.Thresholds["critical"])
+	assert.Equal(t, 2*time.Minute, cfg.Publishing.SLO.Thresholds["warning"])
+	assert.Equal(t, 5*time.Minute, cfg.Publishing.SLO.DefaultThreshold)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  slo:
+    thresholds:
+      critical: 10s
+    default_threshold: 0s
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"critical": 10 * time.Second}, cfg.Publishing.SLO.Thresholds)
+	assert.Zero(t, cfg.Publishing.SLO.DefaultThreshold)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  slo:
+    thresholds:
+      critical: -1s
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.slo.thresholds.critical")
+}
+
+func TestLoadConfig_PublisherPlugins(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  plugins:
+    - name: pager
+      path: /opt/amp/plugins/pager
+      args: ["--verbose"]
+      env: ["PAGER_REGION=eu"]
+      health_interval: 10s
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.Publishing.Plugins, 1)
+	plugin := cfg.Publishing.Plugins[0]
+	assert.Equal(t, "pager", plugin.Name)
+	assert.Equal(t, "/opt/amp/plugins/pager", plugin.Path)
+	assert.Equal(t, []string{"--verbose"}, plugin.Args)
+	assert.Equal(t, []string{"PAGER_REGION=eu"}, plugin.Env)
+	assert.Equal(t, 10*time.Second, plugin.HealthInterval)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  plugins:
+    - name: pager
+      path: /opt/amp/plugins/pager
+    - name: pager
+      path: /opt/amp/plugins/pager2
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "duplicate plugin")
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  plugins:
+    - name: pager
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.plugins[0].path")
+}
+
+func TestLoadConfig_ExecCommands(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  commands:
+    - name: ticket
+      path: /opt/amp/bin/open-ticket
+      args: ["--queue", "ops"]
+      env: ["TICKET_URL=http://tickets.local"]
+      env_allowlist: [PATH, HTTPS_PROXY]
+      timeout: 15s
+      max_concurrency: 2
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.Publishing.Commands, 1)
+	command := cfg.Publishing.Commands[0]
+	assert.Equal(t, "ticket", command.Name)
+	assert.Equal(t, "/opt/amp/bin/open-ticket", command.Path)
+	assert.Equal(t, []string{"--queue", "ops"}, command.Args)
+	assert.Equal(t, []string{"TICKET_URL=http://tickets.local"}, command.Env)
+	assert.Equal(t, []string{"PATH", "HTTPS_PROXY"}, command.EnvAllowlist)
+	assert.Equal(t, 15*time.Second, command.Timeout)
+	assert.Equal(t, 2, command.MaxConcurrency)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  commands:
+    - name: ticket
+      path: /opt/amp/bin/open-ticket
+      env: ["TICKET_URL"]
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.commands[0].env")
+}
+
+func TestLoadConfig_PublishingRouting(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  routing:
+    enabled: true
+    route:
+      targets: [slack-ops]
+      routes:
+        - match: {severity: critical}
+          targets: [pagerduty]
+          continue: true
+        - match_re: {namespace: "kube-.*"}
+          routes:
+            - match: {team: db}
+              targets: [slack-db]
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	routing := cfg.Publishing.Routing
+	assert.True(t, routing.Enabled)
+	assert.Equal(t, []string{"slack-ops"}, routing.Route.Targets)
+	require.Len(t, routing.Route.Routes, 2)
+	assert.Equal(t, map[string]string{"severity": "critical"}, routing.Route.Routes[0].Match)
+	assert.True(t, routing.Route.Routes[0].Continue)
+	assert.Equal(t, map[string]string{"namespace": "kube-.*"}, routing.Route.Routes[1].MatchRE)
+	require.Len(t, routing.Route.Routes[1].Routes, 1)
+	assert.Equal(t, []string{"slack-db"}, routing.Route.Routes[1].Routes[0].Targets)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  routing:
+    enabled: true
+    route:
+      routes:
+        - match_re: {namespace: "kube-("}
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.routing.route.routes[0].match_re.namespace")
+}
+
+func TestLoadConfig_PublishingQuorum(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  quorum:
+    policies:
+      - name: page
+        match: {severity: critical}
+        targets: [pagerduty, opsgenie]
+        min_success: 1
+        fallback: [slack-oncall]
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	quorum := cfg.Publishing.Quorum
+	assert.Equal(t, 5*time.Minute, quorum.Timeout)
+	require.Len(t, quorum.Policies, 1)
+	assert.Equal(t, "page", quorum.Policies[0].Name)
+	assert.Equal(t, map[string]string{"severity": "critical"}, quorum.Policies[0].Match)
+	assert.Equal(t, []string{"pagerduty", "opsgenie"}, quorum.Policies[0].Targets)
+	assert.Equal(t, 1, quorum.Policies[0].MinSuccess)
+	assert.Equal(t, []string{"slack-oncall"}, quorum.Policies[0].Fallback)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  quorum:
+    policies:
+      - name: page
+        targets: [pagerduty]
+        min_success: 2
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.quorum.policies[0].min_success")
+}
+
+func TestLoadConfig_WebhookMirror(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+webhook:
+  mirror:
+    enabled: true
+    peer_url: https://amp.eu-west.example.com
+    origin: us-east
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, "https://amp.eu-west.example.com", cfg.Webhook.Mirror.PeerURL)
+	assert.Equal(t, "us-east", cfg.Webhook.Mirror.Origin)
+	assert.Equal(t, 10*time.Second, cfg.Webhook.Mirror.Timeout)
+	assert.Equal(t, 1000, cfg.Webhook.Mirror.QueueSize)
+	assert.Equal(t, 3, cfg.Webhook.Mirror.MaxRetries)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+webhook:
+  mirror:
+    enabled: true
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "mirror.peer_url")
+}
+
+func TestLoadConfig_InhibitionExternalSources(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+inhibition:
+  external_sources:
+    - name: legacy
+      url: http://alertmanager-legacy:9093
+      poll_interval: 15s
+    - name: pushed
+      stale_after: 10m
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.Inhibition.ExternalSources, 2)
+	assert.Equal(t, "http://alertmanager-legacy:9093", cfg.Inhibition.ExternalSources[0].URL)
+	assert.Equal(t, 15*time.Second, cfg.Inhibition.ExternalSources[0].PollInterval)
+	assert.Equal(t, 10*time.Minute, cfg.Inhibition.ExternalSources[1].StaleAfter)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+inhibition:
+  external_sources:
+    - name: legacy
+    - name: legacy
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "duplicated")
+}
+
+func TestLoadConfig_AuthTokens(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+auth:
+  enabled: true
+  tokens:
+    - name: admin
+      token: admin-secret
+    - name: payments
+      token: payments-secret
+      selector:
+        - team="payments"
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.Auth.Tokens, 2)
+	assert.Equal(t, []string{`team="payments"`}, cfg.Auth.Tokens[1].Selector)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+auth:
+  enabled: true
+  tokens:
+    - name: admin
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "tokens without a value must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_SlackCommand(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+slack_command:
+  enabled: true
+  signing_secret: slack-secret
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, 168*time.Hour, cfg.SlackCommand.MaxDuration)
+	assert.Equal(t, 10, cfg.SlackCommand.RateLimit)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+slack_command:
+  enabled: true
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "enabled slack command without signing secret must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_PagerDutyWebhook(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+pagerduty_webhook:
+  enabled: true
+  signing_secrets: [old-secret, new-secret]
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old-secret", "new-secret"}, cfg.PagerDutyWebhook.SigningSecrets)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+pagerduty_webhook:
+  enabled: true
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "enabled pagerduty webhook without signing secrets must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_HandoffReport(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+handoff_report:
+  enabled: true
+  teams:
+    - name: payments
+      selector: ['team="payments"']
+      target: slack-payments
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, "0 9 * * 1", cfg.HandoffReport.Schedule)
+	assert.Equal(t, 168*time.Hour, cfg.HandoffReport.Period)
+	require.Len(t, cfg.HandoffReport.Teams, 1)
+	assert.Equal(t, []string{`team="payments"`}, cfg.HandoffReport.Teams[0].Selector)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+handoff_report:
+  enabled: true
+  teams:
+    - name: payments
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "team without target must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_RegionTagging(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+region_tagging:
+  enabled: true
+  rules:
+    - cidr: 10.1.0.0/16
+      region: eu-west-1
+      zone: eu-west-1a
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, "region", cfg.RegionTagging.RegionLabel)
+	assert.Equal(t, []string{"instance"}, cfg.RegionTagging.SourceLabels)
+	assert.Equal(t, 3, cfg.RegionTagging.OutageThreshold)
+	require.Len(t, cfg.RegionTagging.Rules, 1)
+	assert.Equal(t, "eu-west-1a", cfg.RegionTagging.Rules[0].Zone)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+region_tagging:
+  enabled: true
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "enabled without rules or mapping file must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_RoutingConditions(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+routing_conditions:
+  enabled: true
+  prometheus_url: http://prometheus:9090
+  rules:
+    - name: service-down
+      selector: ['severity="critical"']
+      query: 'sum(up{service="{{ .Labels.service }}"}) == 0'
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, cfg.RoutingConditions.Timeout)
+	assert.Equal(t, 30*time.Second, cfg.RoutingConditions.CacheTTL)
+	assert.True(t, cfg.RoutingConditions.FailOpen)
+	require.Len(t, cfg.RoutingConditions.Rules, 1)
+	assert.Equal(t, []string{`severity="critical"`}, cfg.RoutingConditions.Rules[0].Selector)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+routing_conditions:
+  enabled: true
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "enabled without prometheus_url must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_SilenceMigration(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_migration:
+  alertmanager_url: http://alertmanager:9093
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, "http://alertmanager:9093", cfg.SilenceMigration.AlertmanagerURL)
+	assert.Equal(t, 10*time.Second, cfg.SilenceMigration.Timeout)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_migration:
+  alertmanager_url: alertmanager
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "relative alertmanager_url must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_CompositeAlerts(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+composite_alerts:
+  enabled: true
+  rules:
+    - name: ClusterDegraded
+      selector: ['alertname="NodeDown"']
+      group_by: [cluster]
+      threshold: 5
+      window: 10m
+      labels:
+        severity: critical
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.CompositeAlerts.Rules, 1)
+	rule := cfg.CompositeAlerts.Rules[0]
+	assert.Equal(t, 10*time.Minute, rule.Window)
+	assert.Equal(t, []string{"cluster"}, rule.GroupBy)
+	assert.Equal(t, "critical", rule.Labels["severity"])
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+composite_alerts:
+  enabled: true
+  rules:
+    - name: ClusterDegraded
+      selector: ['alertname="NodeDown"']
+      threshold: 1
+      window: 10m
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "threshold below 2 must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_SilenceGC(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.True(t, cfg.SilenceGC.Enabled)
+	assert.Equal(t, time.Hour, cfg.SilenceGC.Interval)
+	assert.Equal(t, 120*time.Hour, cfg.SilenceGC.Retention)
+	assert.Equal(t, 1000, cfg.SilenceGC.BatchSize)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_gc:
+  retention: 10m
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "retention below 1h must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_AlertGC(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.True(t, cfg.AlertGC.Enabled)
+	assert.Equal(t, time.Hour, cfg.AlertGC.Interval)
+	assert.Equal(t, 120*time.Hour, cfg.AlertGC.Retention)
+	assert.Equal(t, 5*time.Minute, cfg.AlertGC.ResolveTimeout)
+	assert.Equal(t, 1000, cfg.AlertGC.BatchSize)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_gc:
+  resolve_timeout: 0s
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "non-positive resolve_timeout must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_SilenceTemplates(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_templates:
+  - name: node-reboot
+    matchers:
+      - name: instance
+        value: '{{ .instance }}'
+      - name: env
+        value: dev
+        is_equal: false
+    duration: 1h
+    max_duration: 4h
+    comment: 'Reboot of {{ .instance }} ({{ .ticket }})'
+    required_params: [instance, ticket]
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.SilenceTemplates, 1)
+	tmpl := cfg.SilenceTemplates[0]
+	assert.Equal(t, time.Hour, tmpl.Duration)
+	require.Len(t, tmpl.Matchers, 2)
+	assert.Nil(t, tmpl.Matchers[0].IsEqual)
+	require.NotNil(t, tmpl.Matchers[1].IsEqual)
+	assert.False(t, *tmpl.Matchers[1].IsEqual)
+	assert.Equal(t, []string{"instance", "ticket"}, tmpl.RequiredParams)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_templates:
+  - name: node-reboot
+    matchers:
+      - name: instance
+        value: '{{ .instance }}'
+    duration: 1h
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "template without comment must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_StormDetection(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+storm_detection:
+  enabled: true
+  min_alerts: 100
+  group_by: [alertname, cluster]
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.True(t, cfg.StormDetection.Enabled)
+	assert.Equal(t, time.Minute, cfg.StormDetection.Window)
+	assert.Equal(t, 5.0, cfg.StormDetection.Multiplier)
+	assert.Equal(t, 100, cfg.StormDetection.MinAlerts)
+	assert.Equal(t, 5*time.Minute, cfg.StormDetection.DigestInterval)
+	assert.Equal(t, []string{"alertname", "cluster"}, cfg.StormDetection.GroupBy)
+	assert.Equal(t, []string{"critical"}, cfg.StormDetection.CriticalSeverities)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+storm_detection:
+  enabled: true
+  multiplier: 1
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "multiplier of 1 must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_SilencePolicy(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_policy:
+  enabled: true
+  max_duration: 24h
+  groups:
+    - name: sre
+      members: [alice, bob]
+      max_duration: 168h
+  comment_patterns: ['[A-Z]+-[0-9]+']
+  protected:
+    - namespace: production
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	policy := cfg.SilencePolicy
+	assert.Equal(t, 24*time.Hour, policy.MaxDuration)
+	require.Len(t, policy.Groups, 1)
+	assert.Equal(t, []string{"alice", "bob"}, policy.Groups[0].Members)
+	assert.Equal(t, 168*time.Hour, policy.Groups[0].MaxDuration)
+	assert.Equal(t, []string{"[A-Z]+-[0-9]+"}, policy.CommentPatterns)
+	assert.Equal(t, []map[string]string{{"namespace": "production"}}, policy.Protected)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_policy:
+  enabled: true
+  comment_patterns: ['[unclosed']
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "invalid comment pattern must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_AlertTraces(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_traces:
+  enabled: true
+  url_template: "https://tempo.example.com/trace/{{ .TraceID }}"
+  trace_id_keys: [trace_id, exemplar_trace_id]
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.True(t, cfg.AlertTraces.Enabled)
+	assert.Equal(t, "https://tempo.example.com/trace/{{ .TraceID }}", cfg.AlertTraces.URLTemplate)
+	assert.Equal(t, []string{"traceparent"}, cfg.AlertTraces.TraceparentKeys)
+	assert.Equal(t, []string{"trace_id", "exemplar_trace_id"}, cfg.AlertTraces.TraceIDKeys)
+	assert.Equal(t, []string{"span_id", "spanID"}, cfg.AlertTraces.SpanIDKeys)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_traces:
+  enabled: true
+  url_template: "https://tempo.example.com/trace/{{ .TraceID "
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "invalid template must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_SilenceCache(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+redis:
+  addr: redis:6379
+silence_cache:
+  enabled: true
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.True(t, cfg.SilenceCache.Enabled)
+	assert.Equal(t, "amp:silences", cfg.SilenceCache.KeyPrefix)
+	assert.Equal(t, 30*time.Second, cfg.SilenceCache.ResyncInterval)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_cache:
+  enabled: true
+  resync_interval: 0s
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "non-positive resync interval must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_SilenceWebhooks(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_webhooks:
+  endpoints:
+    - url: https://change.example.com/hooks/amp
+      events: [silence.created, silence.expired]
+      headers:
+        Authorization: Bearer secret
+      signing_secret: s3cret
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.SilenceWebhooks.Endpoints, 1)
+	endpoint := cfg.SilenceWebhooks.Endpoints[0]
+	assert.Equal(t, "https://change.example.com/hooks/amp", endpoint.URL)
+	assert.Equal(t, []string{"silence.created", "silence.expired"}, endpoint.Events)
+	assert.Equal(t, "s3cret", endpoint.SigningSecret)
+	assert.Len(t, endpoint.Headers, 1)
+	assert.Equal(t, time.Minute, cfg.SilenceWebhooks.Interval)
+	assert.Equal(t, 1000, cfg.SilenceWebhooks.QueueSize)
+	assert.Equal(t, 3, cfg.SilenceWebhooks.MaxRetries)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+silence_webhooks:
+  endpoints:
+    - url: https://change.example.com/hooks/amp
+      events: [silence.deleted]
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "unknown events must be rejected")
+	assert.Contains(t, err.Error(), "silence_webhooks")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_AlertSampling(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_sampling:
+  enabled: true
+  policies:
+    - name: info-noise
+      severity: info|none
+      classification_rate: 0.1
+      enrichment_rate: 0
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	require.Len(t, cfg.AlertSampling.Policies, 1)
+	policy := cfg.AlertSampling.Policies[0]
+	assert.Equal(t, "info|none", policy.Severity)
+	require.NotNil(t, policy.ClassificationRate)
+	assert.Equal(t, 0.1, *policy.ClassificationRate)
+	require.NotNil(t, policy.EnrichmentRate)
+	assert.Equal(t, 0.0, *policy.EnrichmentRate)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_sampling:
+  enabled: true
+  policies:
+    - name: too-much
+      classification_rate: 1.5
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "rates above 1 must be rejected")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_AlertRollups(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.True(t, cfg.AlertRollups.Enabled)
+	assert.Equal(t, 5*time.Minute, cfg.AlertRollups.Interval)
+	assert.Equal(t, time.Hour, cfg.AlertRollups.Lateness)
+	assert.Equal(t, 14*24*time.Hour, cfg.AlertRollups.HourlyRetention)
+	assert.Equal(t, 400*24*time.Hour, cfg.AlertRollups.DailyRetention)
+	assert.Equal(t, "team", cfg.AlertRollups.TeamLabel)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_rollups:
+  hourly_retention: 12h
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err, "daily rollups need a day of hourly rollups")
+	assert.Contains(t, err.Error(), "alert_rollups")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfig_AlertReplay(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_replay:
+  enabled: true
+  min_downtime: 10m
+  sources:
+    - name: prometheus
+      prometheus_url: http://prometheus:9090
+    - name: legacy-am
+      alertmanager_url: http://alertmanager:9093
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.True(t, cfg.AlertReplay.Enabled)
+	assert.Equal(t, 10*time.Minute, cfg.AlertReplay.MinDowntime)
+	assert.Equal(t, 5*time.Minute, cfg.AlertReplay.ResolveTimeout)
+	assert.Equal(t, 10*time.Second, cfg.AlertReplay.Timeout)
+	require.Len(t, cfg.AlertReplay.Sources, 2)
+	assert.Equal(t, "http://alertmanager:9093", cfg.AlertReplay.Sources[1].AlertmanagerURL)
+
+	for name, sources := range map[string]string{
+		"no sources": `[]`,
+		"both URLs":  `[{name: x, prometheus_url: "http://p:9090", alertmanager_url: "http://a:9093"}]`,
+		"no URL":     `[{name: x}]`,
+		"bad name":   `[{name: "a b", prometheus_url: "http://p:9090"}]`,
+		"relative":   `[{name: x, prometheus_url: "prometheus:9090"}]`,
+	} {
+		resetViper()
+		yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+alert_replay:
+  enabled: true
+  sources: ` + sources + "\n"
+		cfg, err = LoadConfig(writeTempYAML(t, yaml))
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "alert_replay", name)
+		assert.Nil(t, cfg, name)
+	}
+}
+
+func TestLoadConfig_PublishingCircuitBreakers(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    circuit_breaker:
+      timeout: 1m
+    circuit_breakers:
+      pagerduty:
+        failure_threshold: 10
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, PublishingCircuitBreakerConfig{FailureThreshold: 5, SuccessThreshold: 2, Timeout: time.Minute}, cfg.Publishing.Queue.CircuitBreaker)
+	assert.Equal(t, map[string]PublishingCircuitBreakerConfig{"pagerduty": {FailureThreshold: 10}}, cfg.Publishing.Queue.CircuitBreakers)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  queue:
+    circuit_breakers:
+      slack:
+        timeout: -1s
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.queue.circuit_breakers.slack")
+}
+
+func TestLoadConfig_PublishingDeliveries(t *testing.T) {
+	resetViper()
+
+	yaml := `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+`
+	cfg, err := LoadConfig(writeTempYAML(t, yaml))
+	require.NoError(t, err)
+	assert.Equal(t, PublishingDeliveriesConfig{Persist: true, Retention: 720 * time.Hour, BufferSize: 10000}, cfg.Publishing.Deliveries)
+
+	resetViper()
+	yaml = `
+profile: "lite"
+storage:
+  backend: filesystem
+publishing:
+  enabled: true
+  deliveries:
+    retention: 0s
+`
+	cfg, err = LoadConfig(writeTempYAML(t, yaml))
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "publishing.deliveries.retention")
+}
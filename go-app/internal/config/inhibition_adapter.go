@@ -1,13 +1,16 @@
 package config
 
 import (
+	"fmt"
+
 	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
 )
 
 // ToInhibitionRules converts config rules to inhibition.InhibitionRule slice.
 // Used during ServiceRegistry initialization.
 // If ConfigFile is set, rules from the file are parsed and merged with inline Rules.
-func (c *InhibitionConfig) ToInhibitionRules() []inhibition.InhibitionRule {
+// Regex conditions of the returned rules are compiled.
+func (c *InhibitionConfig) ToInhibitionRules() ([]inhibition.InhibitionRule, error) {
 	rules := make([]inhibition.InhibitionRule, 0, len(c.Rules))
 
 	for _, r := range c.Rules {
@@ -21,13 +24,18 @@ func (c *InhibitionConfig) ToInhibitionRules() []inhibition.InhibitionRule {
 		})
 	}
 
+	if err := inhibition.CompileRules(rules); err != nil {
+		return nil, err
+	}
+
 	if c.ConfigFile != "" {
 		parser := inhibition.NewParser()
 		cfg, err := parser.ParseFile(c.ConfigFile)
-		if err == nil && cfg != nil {
-			rules = append(rules, cfg.Rules...)
+		if err != nil {
+			return nil, fmt.Errorf("inhibition config file: %w", err)
 		}
+		rules = append(rules, cfg.Rules...)
 	}
 
-	return rules
+	return rules, nil
 }
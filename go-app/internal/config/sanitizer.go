@@ -47,6 +47,14 @@ func (s *DefaultConfigSanitizer) Sanitize(cfg *Config) *Config {
 	sanitized.Webhook.Authentication.APIKey = s.redactionValue
 	sanitized.Webhook.Authentication.JWTSecret = s.redactionValue
 
+	// Redact API tokens
+	for i := range sanitized.Auth.Tokens {
+		sanitized.Auth.Tokens[i].Token = s.redactionValue
+	}
+
+	// Redact Slack signing secret
+	sanitized.SlackCommand.SigningSecret = s.redactionValue
+
 	// Redact webhook signature secret
 	sanitized.Webhook.Signature.Secret = s.redactionValue
 
@@ -0,0 +1,15 @@
+package core
+
+// AlertAcknowledgement records that an alert was acknowledged in an incident
+// management system, e.g. its PagerDuty incident acknowledged by the on-call
+// engineer. Shown in the status of firing alerts in the API.
+type AlertAcknowledgement struct {
+	// Source is the system the alert was acknowledged in, e.g. "pagerduty".
+	Source string `json:"source"`
+	// By names who acknowledged the alert, as reported by the source.
+	By string `json:"by,omitempty"`
+	// URL links to the acknowledged incident.
+	URL string `json:"url,omitempty"`
+	// At is when the alert was acknowledged (RFC3339).
+	At string `json:"at"`
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"time"
+
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+// ExpiresAt returns when the alert should be treated as resolved: at EndsAt,
+// else resolveTimeout after its last update (see domain.Alert.ExpiresAt).
+func (a *Alert) ExpiresAt(resolveTimeout time.Duration) time.Time {
+	return a.domainTimes().ExpiresAt(resolveTimeout)
+}
+
+// IsExpiredAt reports whether the alert should be treated as resolved at
+// now. Resolved alerts are always expired.
+func (a *Alert) IsExpiredAt(now time.Time, resolveTimeout time.Duration) bool {
+	return a.domainTimes().IsExpiredAt(now, resolveTimeout)
+}
+
+// domainTimes returns the status and timestamps of the alert as a
+// domain.Alert, which implements the timestamp rules.
+func (a *Alert) domainTimes() *domain.Alert {
+	return &domain.Alert{
+		Status:    domain.AlertStatus(a.Status),
+		StartsAt:  a.StartsAt,
+		EndsAt:    a.EndsAt,
+		Timestamp: a.Timestamp,
+	}
+}
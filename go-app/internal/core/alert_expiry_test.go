@@ -0,0 +1,25 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlert_IsExpiredAt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	updated := start.Add(10 * time.Minute)
+	firing := &Alert{Status: StatusFiring, StartsAt: start, Timestamp: &updated}
+
+	assert.Equal(t, updated.Add(5*time.Minute), firing.ExpiresAt(5*time.Minute))
+	assert.False(t, firing.IsExpiredAt(updated.Add(4*time.Minute), 5*time.Minute))
+	assert.True(t, firing.IsExpiredAt(updated.Add(5*time.Minute), 5*time.Minute))
+
+	endsAt := start.Add(time.Hour)
+	withEnd := &Alert{Status: StatusFiring, StartsAt: start, EndsAt: &endsAt}
+	assert.Equal(t, endsAt, withEnd.ExpiresAt(5*time.Minute))
+
+	resolved := &Alert{Status: StatusResolved, StartsAt: start, EndsAt: &endsAt}
+	assert.True(t, resolved.IsExpiredAt(start, 5*time.Minute))
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// AlertRollupGranularity is the bucket size of alert rollups.
+type AlertRollupGranularity string
+
+const (
+	AlertRollupHourly AlertRollupGranularity = "hour"
+	AlertRollupDaily  AlertRollupGranularity = "day"
+)
+
+// Duration returns the length of a bucket.
+func (g AlertRollupGranularity) Duration() time.Duration {
+	if g == AlertRollupDaily {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// Truncate returns the start of the (UTC) bucket containing t.
+func (g AlertRollupGranularity) Truncate(t time.Time) time.Time {
+	return t.UTC().Truncate(g.Duration())
+}
+
+// Valid reports whether g is a known granularity.
+func (g AlertRollupGranularity) Valid() bool {
+	return g == AlertRollupHourly || g == AlertRollupDaily
+}
+
+// AlertRollupDimension is what the alerts of a rollup are counted by.
+type AlertRollupDimension string
+
+const (
+	// AlertRollupTotal counts all alerts (one row per bucket, empty value).
+	AlertRollupTotal     AlertRollupDimension = "total"
+	AlertRollupSeverity  AlertRollupDimension = "severity"
+	AlertRollupAlertName AlertRollupDimension = "alertname"
+	AlertRollupTeam      AlertRollupDimension = "team"
+)
+
+// AlertRollupDimensions lists the dimensions of every bucket.
+var AlertRollupDimensions = []AlertRollupDimension{AlertRollupTotal, AlertRollupSeverity, AlertRollupAlertName, AlertRollupTeam}
+
+// AlertRollup counts the alerts that started in a bucket with one value of
+// a dimension. Alerts without the label are counted under the empty value,
+// so the counts of every dimension add up to the total.
+type AlertRollup struct {
+	Granularity AlertRollupGranularity `json:"granularity"`
+	BucketStart time.Time              `json:"bucketStart"`
+	Dimension   AlertRollupDimension   `json:"dimension"`
+	Value       string                 `json:"value"`
+	Count       int64                  `json:"count"`
+}
+
+// AlertRollupQuery selects the rollups of one granularity whose bucket
+// starts in [From, To). An empty Dimension selects all dimensions.
+type AlertRollupQuery struct {
+	Granularity AlertRollupGranularity
+	Dimension   AlertRollupDimension
+	From        time.Time
+	To          time.Time
+}
+
+// AlertRollupStore stores pre-aggregated alert counts.
+type AlertRollupStore interface {
+	// ReplaceAlertRollups replaces the rollups of the bucket of granularity
+	// starting at bucketStart with rollups, in one transaction.
+	ReplaceAlertRollups(ctx context.Context, granularity AlertRollupGranularity, bucketStart time.Time, rollups []AlertRollup) error
+	// ListAlertRollups returns the rollups matching query, oldest bucket first.
+	ListAlertRollups(ctx context.Context, query AlertRollupQuery) ([]AlertRollup, error)
+	// DeleteAlertRollupsBefore deletes the rollups of granularity whose
+	// bucket starts before cutoff and returns how many were deleted.
+	DeleteAlertRollupsBefore(ctx context.Context, granularity AlertRollupGranularity, cutoff time.Time) (int64, error)
+}
+
+// SumAlertRollups adds up the counts of rollups by dimension and value,
+// e.g. to total the hourly buckets of the last 24 hours.
+func SumAlertRollups(rollups []AlertRollup) map[AlertRollupDimension]map[string]int64 {
+	sums := make(map[AlertRollupDimension]map[string]int64)
+	for _, rollup := range rollups {
+		if sums[rollup.Dimension] == nil {
+			sums[rollup.Dimension] = make(map[string]int64)
+		}
+		sums[rollup.Dimension][rollup.Value] += rollup.Count
+	}
+	return sums
+}
@@ -0,0 +1,21 @@
+package core
+
+import (
+	"time"
+
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+// DefaultClockSkewTolerance is how far in the future StartsAt may be before it
+// is treated as a real timestamp instead of sender clock skew.
+const DefaultClockSkewTolerance = domain.DefaultClockSkewTolerance
+
+// NormalizeTimestamps converts all alert timestamps to UTC and absorbs small
+// clock skew of the sender (see domain.Alert.NormalizeTimestamps).
+func (a *Alert) NormalizeTimestamps(now time.Time, skewTolerance time.Duration) {
+	times := a.domainTimes()
+	times.NormalizeTimestamps(now, skewTolerance)
+	a.StartsAt = times.StartsAt
+	a.EndsAt = times.EndsAt
+	a.Timestamp = times.Timestamp
+}
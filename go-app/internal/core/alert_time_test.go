@@ -0,0 +1,72 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlert_NormalizeTimestamps(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	plus3 := time.FixedZone("UTC+3", 3*60*60)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name           string
+		alert          Alert
+		tolerance      time.Duration
+		expectedStarts time.Time
+		expectedEnds   *time.Time
+	}{
+		{
+			name:           "converts to UTC",
+			alert:          Alert{StartsAt: now.Add(-time.Minute).In(plus3), EndsAt: ptr(now.In(plus3))},
+			tolerance:      DefaultClockSkewTolerance,
+			expectedStarts: now.Add(-time.Minute),
+			expectedEnds:   ptr(now),
+		},
+		{
+			name:           "clamps StartsAt within tolerance",
+			alert:          Alert{StartsAt: now.Add(5 * time.Second)},
+			tolerance:      DefaultClockSkewTolerance,
+			expectedStarts: now,
+		},
+		{
+			name:           "keeps StartsAt beyond tolerance",
+			alert:          Alert{StartsAt: now.Add(time.Hour)},
+			tolerance:      DefaultClockSkewTolerance,
+			expectedStarts: now.Add(time.Hour),
+		},
+		{
+			name:           "clamps EndsAt before StartsAt within tolerance",
+			alert:          Alert{StartsAt: now.Add(-time.Minute), EndsAt: ptr(now.Add(-time.Minute - 2*time.Second))},
+			tolerance:      DefaultClockSkewTolerance,
+			expectedStarts: now.Add(-time.Minute),
+			expectedEnds:   ptr(now.Add(-time.Minute)),
+		},
+		{
+			name:           "zero tolerance only converts to UTC",
+			alert:          Alert{StartsAt: now.Add(5 * time.Second).In(plus3)},
+			tolerance:      0,
+			expectedStarts: now.Add(5 * time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := tt.alert
+			alert.NormalizeTimestamps(now.In(plus3), tt.tolerance)
+
+			assert.Equal(t, tt.expectedStarts, alert.StartsAt)
+			assert.Equal(t, time.UTC, alert.StartsAt.Location())
+			if tt.expectedEnds == nil {
+				assert.Nil(t, alert.EndsAt)
+				return
+			}
+			require.NotNil(t, alert.EndsAt)
+			assert.Equal(t, *tt.expectedEnds, *alert.EndsAt)
+		})
+	}
+}
@@ -39,6 +39,9 @@ type APIAlertStatus struct {
 	SilencedBy  []string `json:"silencedBy"`
 	InhibitedBy []string `json:"inhibitedBy"`
 	MutedBy     []string `json:"mutedBy"`
+	// Acknowledged is set on firing alerts acknowledged in an incident
+	// management system (AMP extension).
+	Acknowledged *AlertAcknowledgement `json:"acknowledged,omitempty"`
 }
 
 // APIAlert represents a single alert in GET /api/v2/alerts response
@@ -99,6 +102,25 @@ type SilenceInput struct {
 	EndsAt    string                `json:"endsAt"`
 	CreatedBy string                `json:"createdBy"`
 	Comment   string                `json:"comment"`
+	// NotifyOnExpiry names a publishing target notified shortly before the
+	// silence ends and when it ends with matching alerts still firing.
+	// It is not written to the silence repository and does not survive a
+	// restart.
+	NotifyOnExpiry string `json:"notifyOnExpiry,omitempty"`
+	// RequireLabelPresence makes the silence match only alerts that carry
+	// every label its matchers name, with a non-empty value. By default, as
+	// in Alertmanager, a missing label matches as an empty value, so
+	// env!="prod" also silences alerts without an env label.
+	RequireLabelPresence bool `json:"requireLabelPresence,omitempty"`
+	// Actor is who makes the change, recorded in the silence audit trail.
+	// Set by the API from the authenticated token; defaults to CreatedBy.
+	Actor string `json:"-"`
+	// ApprovedBy is the second person who approved a silence the silence
+	// policy held for approval. Set by the approval API only.
+	ApprovedBy string `json:"-"`
+	// BypassPolicy skips the silence policy for silences the server creates
+	// on its own behalf (recurring silences, migrations).
+	BypassPolicy bool `json:"-"`
 }
 
 // StoredSilenceMatcher represents the internal state of a silence matcher
@@ -118,6 +140,10 @@ type StoredSilenceState struct {
 	CreatedBy string
 	Comment   string
 	UpdatedAt time.Time
+
+	NotifyOnExpiry string
+
+	RequireLabelPresence bool
 }
 
 // APISilenceMatcher represents a label matcher in a silence
@@ -143,4 +169,9 @@ type APISilence struct {
 	CreatedBy string              `json:"createdBy"`
 	Comment   string              `json:"comment"`
 	Status    APISilenceStatus    `json:"status"`
+
+	NotifyOnExpiry string `json:"notifyOnExpiry,omitempty"`
+
+	// RequireLabelPresence is described on SilenceInput.
+	RequireLabelPresence bool `json:"requireLabelPresence,omitempty"`
 }
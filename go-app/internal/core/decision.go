@@ -0,0 +1,85 @@
+package core
+
+import "time"
+
+// DecisionStage identifies the pipeline stage that made a decision about an alert.
+type DecisionStage string
+
+const (
+	DecisionStageDeduplication  DecisionStage = "deduplication"
+	DecisionStageSilence        DecisionStage = "silence"
+	DecisionStageInhibition     DecisionStage = "inhibition"
+	DecisionStageCondition      DecisionStage = "routing_condition"
+	DecisionStageComposite      DecisionStage = "composite"
+	DecisionStageStorm          DecisionStage = "storm"
+	DecisionStageEnrichmentMode DecisionStage = "enrichment_mode"
+	DecisionStageClassification DecisionStage = "classification"
+	DecisionStageFilter         DecisionStage = "filter"
+	DecisionStageRoute          DecisionStage = "route"
+)
+
+// DecisionResult is the final outcome of processing an alert.
+type DecisionResult string
+
+const (
+	DecisionResultPublished       DecisionResult = "published"
+	DecisionResultDuplicate       DecisionResult = "duplicate"
+	DecisionResultSilenced        DecisionResult = "silenced"
+	DecisionResultInhibited       DecisionResult = "inhibited"
+	DecisionResultConditionNotMet DecisionResult = "condition_not_met"
+	DecisionResultComposited      DecisionResult = "composited"
+	DecisionResultStormSuppressed DecisionResult = "storm_suppressed"
+	DecisionResultFiltered        DecisionResult = "filtered"
+	DecisionResultFailed          DecisionResult = "failed"
+)
+
+// Decision is a single pipeline decision.
+type Decision struct {
+	Stage   DecisionStage     `json:"stage"`
+	Outcome string            `json:"outcome"`
+	Reason  string            `json:"reason,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// DecisionTrace records every decision made while processing one alert
+// occurrence, answering "why was (or wasn't) this alert published".
+type DecisionTrace struct {
+	Fingerprint string         `json:"fingerprint"`
+	AlertName   string         `json:"alertname"`
+	Status      AlertStatus    `json:"status"`
+	ProcessedAt time.Time      `json:"processed_at"`
+	Result      DecisionResult `json:"result"`
+	Decisions   []Decision     `json:"decisions"`
+}
+
+// NewDecisionTrace starts a trace for alert.
+func NewDecisionTrace(alert *Alert, now time.Time) *DecisionTrace {
+	return &DecisionTrace{
+		Fingerprint: alert.Fingerprint,
+		AlertName:   alert.AlertName,
+		Status:      alert.Status,
+		ProcessedAt: now.UTC(),
+		Decisions:   make([]Decision, 0, 6),
+	}
+}
+
+// Add appends a decision. Safe to call on a nil trace.
+func (t *DecisionTrace) Add(stage DecisionStage, outcome, reason string, details map[string]string) {
+	if t == nil {
+		return
+	}
+	t.Decisions = append(t.Decisions, Decision{
+		Stage:   stage,
+		Outcome: outcome,
+		Reason:  reason,
+		Details: details,
+	})
+}
+
+// Finish sets the final result unless one was already set. Safe to call on a nil trace.
+func (t *DecisionTrace) Finish(result DecisionResult) {
+	if t == nil || t.Result != "" {
+		return
+	}
+	t.Result = result
+}
@@ -0,0 +1,34 @@
+package core
+
+import "time"
+
+// DeliveryStatus is the outcome of one attempt to publish an alert to a target.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusSucceeded     DeliveryStatus = "succeeded"
+	DeliveryStatusFailed        DeliveryStatus = "failed"
+	DeliveryStatusCircuitOpen   DeliveryStatus = "circuit_open"   // not attempted, the target's circuit breaker was open
+	DeliveryStatusTargetRemoved DeliveryStatus = "target_removed" // not attempted, the target was removed from discovery
+	DeliveryStatusShed          DeliveryStatus = "shed"           // not attempted, shed as the queue neared capacity
+)
+
+// DeliveryAttempt records one attempt to publish an alert to a target,
+// answering "did (and when did) this notification reach the provider".
+type DeliveryAttempt struct {
+	JobID       string         `json:"job_id"`
+	Target      string         `json:"target"`
+	TargetType  string         `json:"target_type"`
+	AlertStatus AlertStatus    `json:"alert_status"`
+	Attempt     int            `json:"attempt"` // 1-based, per publishing job
+	Status      DeliveryStatus `json:"status"`
+	// StatusCode is the provider's HTTP status of the attempt, when known.
+	StatusCode  int       `json:"status_code,omitempty"`
+	ErrorType   string    `json:"error_type,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	LatencyMs   int64     `json:"latency_ms"`
+	// PayloadHash is the SHA-256 of the request body sent to the provider
+	// ("sha256:<hex>"), when it is reached over HTTP.
+	PayloadHash string `json:"payload_hash,omitempty"`
+}
@@ -32,6 +32,16 @@ const (
 	FormatPagerDuty    PublishingFormat = "pagerduty"
 	FormatSlack        PublishingFormat = "slack"
 	FormatWebhook      PublishingFormat = "webhook"
+	FormatTeams        PublishingFormat = "teams"
+	FormatOpsgenie     PublishingFormat = "opsgenie"
+	FormatEmail        PublishingFormat = "email"
+	FormatKafka        PublishingFormat = "kafka"
+	FormatJira         PublishingFormat = "jira"
+	FormatGoogleChat   PublishingFormat = "googlechat"
+	FormatMattermost   PublishingFormat = "mattermost"
+	FormatAWS          PublishingFormat = "aws"
+	FormatPlugin       PublishingFormat = "plugin"
+	FormatExec         PublishingFormat = "exec"
 )
 
 // Alert represents alert data model
@@ -45,6 +55,18 @@ type Alert struct {
 	EndsAt       *time.Time        `json:"ends_at,omitempty"`
 	GeneratorURL *string           `json:"generator_url,omitempty" validate:"omitempty,url"`
 	Timestamp    *time.Time        `json:"timestamp,omitempty"`
+
+	// SilencedBy lists IDs of active silences matching the alert (set by the silence stage).
+	SilencedBy []string `json:"silenced_by,omitempty"`
+}
+
+// TraceURLAnnotation holds the tracing UI link of the trace an alert was
+// raised from (set by the alert processor when the alert carries a trace ID).
+const TraceURLAnnotation = "trace_url"
+
+// IsSilenced reports whether the silence stage suppressed the alert.
+func (a *Alert) IsSilenced() bool {
+	return len(a.SilencedBy) > 0
 }
 
 // Namespace returns alert namespace from labels
@@ -81,7 +103,7 @@ type PublishingTarget struct {
 	Enabled      bool              `json:"enabled"`
 	FilterConfig map[string]any    `json:"filter_config"`
 	Headers      map[string]string `json:"headers"`
-	Format       PublishingFormat  `json:"format" validate:"required,oneof=alertmanager rootly pagerduty slack webhook"`
+	Format       PublishingFormat  `json:"format" validate:"required,oneof=alertmanager rootly pagerduty slack webhook teams opsgenie email kafka jira googlechat mattermost aws plugin exec"`
 }
 
 // EnrichedAlert represents alert enriched with classification data
@@ -90,6 +112,9 @@ type EnrichedAlert struct {
 	Classification      *ClassificationResult `json:"classification,omitempty"`
 	EnrichmentMetadata  map[string]any        `json:"enrichment_metadata,omitempty"`
 	ProcessingTimestamp *time.Time            `json:"processing_timestamp,omitempty"`
+
+	// knownLabels is set by IndexLabels.
+	knownLabels *KnownLabels
 }
 
 // Database interfaces following SOLID principles
@@ -0,0 +1,85 @@
+package core
+
+// Well-known label names.
+const (
+	LabelAlertName   = "alertname"
+	LabelNamespace   = "namespace"
+	LabelSeverity    = "severity"
+	LabelTeam        = "team"
+	LabelService     = "service"
+	LabelEnvironment = "environment"
+	LabelRegion      = "region"
+
+	// labelEnv is the short form of LabelEnvironment used by many exporters.
+	labelEnv = "env"
+)
+
+// KnownLabels is a parsed view of the well-known labels of an alert, so
+// that routing, enrichment and formatters do not look them up in the label
+// map again and again. Missing labels are empty.
+type KnownLabels struct {
+	AlertName   string `json:"alertname,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Team        string `json:"team,omitempty"`
+	Service     string `json:"service,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Region      string `json:"region,omitempty"`
+}
+
+// ParseKnownLabels extracts the well-known labels. regionLabel names the
+// region label (region_tagging.region_label); empty means LabelRegion.
+// Environment falls back to the "env" label.
+func ParseKnownLabels(labels map[string]string, regionLabel string) KnownLabels {
+	if regionLabel == "" {
+		regionLabel = LabelRegion
+	}
+	known := KnownLabels{
+		AlertName:   labels[LabelAlertName],
+		Namespace:   labels[LabelNamespace],
+		Severity:    labels[LabelSeverity],
+		Team:        labels[LabelTeam],
+		Service:     labels[LabelService],
+		Environment: labels[LabelEnvironment],
+		Region:      labels[regionLabel],
+	}
+	if known.Environment == "" {
+		known.Environment = labels[labelEnv]
+	}
+	return known
+}
+
+// IndexLabels parses the well-known labels of the alert once and memoizes
+// them for KnownLabels. It is called when the enriched alert is created,
+// before it is shared between goroutines; labels changed afterwards are not
+// picked up.
+func (e *EnrichedAlert) IndexLabels(regionLabel string) KnownLabels {
+	var labels map[string]string
+	if e.Alert != nil {
+		labels = e.Alert.Labels
+	}
+	known := ParseKnownLabels(labels, regionLabel)
+	e.knownLabels = &known
+	return known
+}
+
+// KnownLabels returns the labels memoized by IndexLabels, or parses them
+// (without memoizing) when the alert was not indexed.
+func (e *EnrichedAlert) KnownLabels() KnownLabels {
+	if e.knownLabels != nil {
+		return *e.knownLabels
+	}
+	if e.Alert == nil {
+		return KnownLabels{}
+	}
+	return ParseKnownLabels(e.Alert.Labels, "")
+}
+
+// WithAlert returns a copy of the enriched alert for alert, e.g. a redacted
+// copy of its alert, whose well-known labels are known.
+func (e *EnrichedAlert) WithAlert(alert *Alert, known KnownLabels) *EnrichedAlert {
+	c := *e
+	c.Alert = alert
+	c.knownLabels = &known
+	return &c
+}
@@ -0,0 +1,52 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestParseKnownLabels(t *testing.T) {
+	labels := map[string]string{
+		"alertname": "HighCPU",
+		"namespace": "payments",
+		"severity":  "critical",
+		"team":      "sre",
+		"service":   "api",
+		"env":       "prod",
+		"dc":        "eu-west",
+	}
+
+	assert.Equal(t, core.KnownLabels{
+		AlertName:   "HighCPU",
+		Namespace:   "payments",
+		Severity:    "critical",
+		Team:        "sre",
+		Service:     "api",
+		Environment: "prod",
+		Region:      "eu-west",
+	}, core.ParseKnownLabels(labels, "dc"))
+
+	labels["environment"] = "staging"
+	labels["region"] = "us-east"
+	known := core.ParseKnownLabels(labels, "")
+	assert.Equal(t, "staging", known.Environment)
+	assert.Equal(t, "us-east", known.Region)
+}
+
+func TestEnrichedAlert_IndexLabels(t *testing.T) {
+	alert := &core.Alert{Labels: map[string]string{"team": "sre", "namespace": "payments"}}
+	enriched := &core.EnrichedAlert{Alert: alert}
+
+	// Without an index, labels are parsed on every call.
+	assert.Equal(t, "sre", enriched.KnownLabels().Team)
+
+	enriched.IndexLabels("")
+	alert.Labels["team"] = "dba"
+	assert.Equal(t, "sre", enriched.KnownLabels().Team, "indexed labels are memoized")
+	assert.Equal(t, "payments", enriched.KnownLabels().Namespace)
+
+	assert.Equal(t, core.KnownLabels{}, (&core.EnrichedAlert{}).KnownLabels())
+}
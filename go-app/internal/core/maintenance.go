@@ -0,0 +1,32 @@
+package core
+
+import "time"
+
+// MaintenanceInput is the payload for entering or leaving maintenance mode.
+type MaintenanceInput struct {
+	// Enabled enters maintenance mode when true and leaves it when false.
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+	// PauseIngestion also rejects incoming alerts so that senders retry
+	// them after maintenance. By default ingestion and history keep running.
+	PauseIngestion bool `json:"pauseIngestion,omitempty"`
+	// DrainTimeout is how long leaving maintenance waits for the held
+	// notifications to be handed to publishers, as a Go duration, e.g. "1m".
+	DrainTimeout string `json:"drainTimeout,omitempty"`
+}
+
+// MaintenanceStatus represents maintenance mode in the API.
+type MaintenanceStatus struct {
+	Active         bool       `json:"active"`
+	Reason         string     `json:"reason,omitempty"`
+	Since          *time.Time `json:"since,omitempty"`
+	PauseIngestion bool       `json:"pauseIngestion"`
+	// PublishingPaused is false when publishing is disabled or runs in
+	// metrics-only mode, as there is nothing to hold.
+	PublishingPaused bool `json:"publishingPaused"`
+	// HeldJobs counts notifications waiting in the publishing queue.
+	HeldJobs int `json:"heldJobs"`
+	// Drained is set when leaving maintenance: whether the held
+	// notifications were handed to publishers within the drain timeout.
+	Drained *bool `json:"drained,omitempty"`
+}
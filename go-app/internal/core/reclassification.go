@@ -0,0 +1,52 @@
+package core
+
+import "time"
+
+// Reclassification job states.
+const (
+	ReclassificationIdle      = "idle"
+	ReclassificationRunning   = "running"
+	ReclassificationCompleted = "completed"
+	ReclassificationCancelled = "cancelled"
+	ReclassificationFailed    = "failed"
+)
+
+// ReclassificationInput is the payload for starting a re-classification of
+// historical alerts, e.g. after upgrading the classification model.
+type ReclassificationInput struct {
+	// From and To bound the alerts' startsAt. To defaults to now.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// RatePerSecond limits classifier calls (default 1).
+	RatePerSecond float64 `json:"ratePerSecond,omitempty"`
+	// Resume continues the last cancelled or failed job from its cursor
+	// instead of starting a new one. From and To are ignored.
+	Resume bool `json:"resume,omitempty"`
+}
+
+// ReclassificationStatus represents the progress of the re-classification
+// job in the API.
+type ReclassificationStatus struct {
+	State         string     `json:"state"`
+	From          *time.Time `json:"from,omitempty"`
+	To            *time.Time `json:"to,omitempty"`
+	RatePerSecond float64    `json:"ratePerSecond,omitempty"`
+	// Total is the number of alerts in the time range when the job started.
+	Total int `json:"total"`
+	// Processed counts alerts handled so far: Reclassified + Failed.
+	Processed    int `json:"processed"`
+	Reclassified int `json:"reclassified"`
+	// Changed counts re-classified alerts whose stored severity changed.
+	Changed int `json:"changed"`
+	Failed  int `json:"failed"`
+	// Cursor is the startsAt of the last processed alert. Alerts are
+	// processed newest first, so after a restart a job can be continued by
+	// starting it again with To set to the cursor.
+	Cursor *time.Time `json:"cursor,omitempty"`
+	// Persisted is false when the storage backend cannot store
+	// classification results; the job then only refreshes the cache.
+	Persisted  bool       `json:"persisted"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	LastError  string     `json:"lastError,omitempty"`
+}
@@ -0,0 +1,45 @@
+package core
+
+import "time"
+
+// RecurringSilenceInput is the payload for creating a recurring silence.
+type RecurringSilenceInput struct {
+	// Schedule is a 5-field cron expression for the start of each window,
+	// e.g. "0 22 * * 0" for Sundays at 22:00.
+	Schedule string `json:"schedule"`
+	// Timezone is the IANA zone the schedule is evaluated in (default UTC).
+	Timezone string `json:"timezone,omitempty"`
+	// Duration is the length of each window as a Go duration, e.g. "4h".
+	Duration  string                `json:"duration"`
+	Matchers  []SilenceMatcherInput `json:"matchers"`
+	CreatedBy string                `json:"createdBy"`
+	Comment   string                `json:"comment"`
+}
+
+// APIRecurringSilence represents a recurring silence in the API.
+type APIRecurringSilence struct {
+	ID        string              `json:"id"`
+	Schedule  string              `json:"schedule"`
+	Timezone  string              `json:"timezone"`
+	Duration  string              `json:"duration"`
+	Matchers  []APISilenceMatcher `json:"matchers"`
+	CreatedBy string              `json:"createdBy"`
+	Comment   string              `json:"comment"`
+	CreatedAt string              `json:"createdAt"`
+	// NextStartsAt is the start of the next window not yet materialized.
+	NextStartsAt string `json:"nextStartsAt,omitempty"`
+	// SilenceIDs are the silences materialized from this recurrence that have
+	// not ended yet.
+	SilenceIDs []string `json:"silenceIDs"`
+}
+
+// RecurringSilenceOccurrence is one concrete window of a recurring silence
+// that is due to be materialized as a silence.
+type RecurringSilenceOccurrence struct {
+	RecurrenceID string
+	StartsAt     time.Time
+	EndsAt       time.Time
+	Matchers     []SilenceMatcherInput
+	CreatedBy    string
+	Comment      string
+}
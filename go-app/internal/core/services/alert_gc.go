@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+const (
+	defaultAlertGCInterval  = time.Hour
+	defaultAlertGCRetention = 120 * time.Hour
+	defaultAlertGCBatchSize = 1000
+)
+
+// ExpiredAlertDeleter deletes alerts that expired before a cutoff.
+// Implemented by memory.AlertStore.
+type ExpiredAlertDeleter interface {
+	DeleteExpiredBefore(cutoff time.Time, resolveTimeout time.Duration, limit int, now time.Time) []string
+}
+
+// AlertGCConfig configures the AlertGC.
+type AlertGCConfig struct {
+	// Interval between runs (default: 1h).
+	Interval time.Duration
+
+	// Retention is how long expired alerts are kept in the alert history,
+	// like Alertmanager's --data.retention (default: 120h).
+	Retention time.Duration
+
+	// ResolveTimeout is when a firing alert without EndsAt expires after its
+	// last update, the Alertmanager global.resolve_timeout
+	// (default: domain.DefaultResolveTimeout).
+	ResolveTimeout time.Duration
+
+	// BatchSize caps the alerts deleted per run (default: 1000).
+	BatchSize int
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// AlertGC periodically deletes alerts that expired more than Retention ago,
+// so the alert history does not grow forever.
+type AlertGC struct {
+	store  ExpiredAlertDeleter
+	config AlertGCConfig
+	logger *slog.Logger
+	clock  clock.Clock
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAlertGC creates an alert GC (not started).
+func NewAlertGC(store ExpiredAlertDeleter, config AlertGCConfig) *AlertGC {
+	if config.Interval <= 0 {
+		config.Interval = defaultAlertGCInterval
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaultAlertGCRetention
+	}
+	if config.ResolveTimeout <= 0 {
+		config.ResolveTimeout = domain.DefaultResolveTimeout
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultAlertGCBatchSize
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &AlertGC{
+		store:  store,
+		config: config,
+		logger: config.Logger.With("component", "alert_gc"),
+		clock:  config.Clock,
+	}
+}
+
+// Start runs a collection immediately and then every Interval until ctx is
+// cancelled or Stop is called.
+func (g *AlertGC) Start(ctx context.Context) {
+	ctx, g.cancel = context.WithCancel(ctx)
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ticker := g.clock.NewTicker(g.config.Interval)
+		defer ticker.Stop()
+
+		g.Collect()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				g.Collect()
+			}
+		}
+	}()
+
+	g.logger.Info("Alert GC started",
+		"interval", g.config.Interval,
+		"retention", g.config.Retention,
+		"resolve_timeout", g.config.ResolveTimeout,
+		"batch_size", g.config.BatchSize,
+	)
+}
+
+// Stop stops the GC and waits for the running collection to finish.
+func (g *AlertGC) Stop() {
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.wg.Wait()
+}
+
+// Collect deletes up to BatchSize alerts that expired before the retention
+// cutoff. Returns how many were deleted.
+func (g *AlertGC) Collect() int {
+	now := g.clock.Now().UTC()
+	cutoff := now.Add(-g.config.Retention)
+	deleted := g.store.DeleteExpiredBefore(cutoff, g.config.ResolveTimeout, g.config.BatchSize, now)
+
+	switch {
+	case len(deleted) >= g.config.BatchSize:
+		// More may remain; they are picked up by the next runs.
+		g.logger.Warn("Alert GC batch limit reached",
+			"deleted", len(deleted),
+			"batch_size", g.config.BatchSize)
+	case len(deleted) > 0:
+		g.logger.Info("Expired alerts deleted",
+			"deleted", len(deleted),
+			"cutoff", cutoff)
+	}
+	return len(deleted)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+func TestAlertGC_Collect(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+	store := memory.NewAlertStore()
+	ingest := func(name string) {
+		require.NoError(t, store.IngestBatch([]core.AlertIngestInput{{
+			Labels:   map[string]string{"alertname": name},
+			StartsAt: fake.Now().Format(time.RFC3339),
+		}}, fake.Now()))
+	}
+	ingest("Stale")
+	fake.Advance(time.Hour)
+	ingest("Fresh")
+
+	gc := NewAlertGC(store, AlertGCConfig{
+		Retention:      24 * time.Hour,
+		ResolveTimeout: 5 * time.Minute,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:          fake,
+	})
+
+	fake.Advance(23 * time.Hour)
+	assert.Equal(t, 0, gc.Collect(), "expired but within retention")
+
+	fake.Advance(30 * time.Minute)
+	assert.Equal(t, 1, gc.Collect(), "expired resolve_timeout after the last update")
+	alerts := store.List("", true)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "Fresh", alerts[0].Labels["alertname"])
+}
@@ -33,6 +33,11 @@ type InvestigationSubmitter interface {
 	Submit(alert *core.Alert, classification *core.ClassificationResult)
 }
 
+// DecisionRecorder stores completed pipeline decision traces.
+type DecisionRecorder interface {
+	Record(trace *core.DecisionTrace)
+}
+
 // AlertProcessor handles alert processing with enrichment mode support
 type AlertProcessor struct {
 	enrichmentManager   EnrichmentModeManager
@@ -44,6 +49,15 @@ type AlertProcessor struct {
 	inhibitionCache     inhibition.ActiveAlertCache       // TN-130 PARITY-A2: cache of firing alerts
 	inhibitionMatcher   inhibition.InhibitionMatcher      // TN-130 Phase 6: Inhibition checking
 	inhibitionState     inhibition.InhibitionStateManager // TN-130 Phase 6: State tracking
+	silenceEngine       *SilenceEngine                    // Silence evaluation against active silences
+	decisionLog         DecisionRecorder                  // Per-alert decision traces (explainability)
+	regionTagger        *RegionTagger                     // Region/zone labels derived from the alert source
+	routingConditions   *RoutingConditionEvaluator        // Live PromQL conditions gating publishing
+	compositeAlerts     *CompositeAlertEngine             // Alert-of-alerts rules
+	compositeHook       func(*core.Alert)                 // Observes composite alerts before they are processed
+	stormDetector       *StormDetector                    // Storm mode: digests and per-group paging
+	traceLinker         *TraceLinker                      // Links alerts to the traces they were raised from
+	sampler             *AlertSampler                     // Sampling of LLM classification and investigations
 	businessMetrics     *metrics.BusinessMetrics          // TN-130 Phase 6: Business metrics for inhibition
 	logger              *slog.Logger
 	metrics             *metrics.MetricsManager
@@ -60,6 +74,15 @@ type AlertProcessorConfig struct {
 	InhibitionCache    inhibition.ActiveAlertCache       // TN-130 PARITY-A2: optional, cache of firing alerts
 	InhibitionMatcher  inhibition.InhibitionMatcher      // TN-130 Phase 6: optional, recommended for inhibition
 	InhibitionState    inhibition.InhibitionStateManager // TN-130 Phase 6: optional, for state tracking
+	SilenceEngine      *SilenceEngine                    // optional, suppresses publishing of silenced alerts
+	DecisionLog        DecisionRecorder                  // optional, records why alerts were (not) published
+	RegionTagger       *RegionTagger                     // optional, adds region/zone labels before any other stage
+	RoutingConditions  *RoutingConditionEvaluator        // optional, holds back firing alerts whose PromQL condition is not met
+	CompositeAlerts    *CompositeAlertEngine             // optional, replaces bursts of child alerts with one composite alert
+	CompositeHook      func(*core.Alert)                 // optional, e.g. stores composite alerts for the API
+	StormDetector      *StormDetector                    // optional, switches to digests during alert storms
+	TraceLinker        *TraceLinker                      // optional, links spans and notifications to the alert's trace
+	Sampler            *AlertSampler                     // optional, limits LLM classification and investigations of noisy alerts
 	BusinessMetrics    *metrics.BusinessMetrics          // TN-130 Phase 6: required if using inhibition
 	Logger             *slog.Logger
 	Metrics            *metrics.MetricsManager
@@ -89,6 +112,15 @@ func NewAlertProcessor(config AlertProcessorConfig) (*AlertProcessor, error) {
 		inhibitionCache:    config.InhibitionCache,    // TN-130 PARITY-A2
 		inhibitionMatcher:  config.InhibitionMatcher,  // TN-130 Phase 6
 		inhibitionState:    config.InhibitionState,    // TN-130 Phase 6
+		silenceEngine:      config.SilenceEngine,
+		decisionLog:        config.DecisionLog,
+		regionTagger:       config.RegionTagger,
+		routingConditions:  config.RoutingConditions,
+		compositeAlerts:    config.CompositeAlerts,
+		compositeHook:      config.CompositeHook,
+		stormDetector:      config.StormDetector,
+		traceLinker:        config.TraceLinker,
+		sampler:            config.Sampler,
 		businessMetrics:    config.BusinessMetrics,    // TN-130 Phase 6
 		logger:             config.Logger,
 		metrics:            config.Metrics,
@@ -99,11 +131,34 @@ func NewAlertProcessor(config AlertProcessorConfig) (*AlertProcessor, error) {
 func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) error {
 	startTime := time.Now()
 
+	// Region tagging runs first so dedup, silences, inhibition and routing
+	// all see the region labels. The fingerprint is kept: the region is
+	// derived from labels that are already part of it.
+	if p.regionTagger != nil && p.regionTagger.Tag(alert.Labels) {
+		p.logger.Debug("Alert tagged with region",
+			"alert", alert.AlertName,
+			"fingerprint", alert.Fingerprint,
+			"region", alert.Labels[p.regionTagger.RegionLabel()])
+	}
+
+	ctx, span := p.startAlertSpan(ctx, alert)
+	defer span.End()
+
+	var trace *core.DecisionTrace
+	if p.decisionLog != nil {
+		trace = core.NewDecisionTrace(alert, startTime)
+		defer func() {
+			trace.Finish(core.DecisionResultPublished)
+			p.decisionLog.Record(trace)
+		}()
+	}
+
 	// TN-036 Phase 3: Step 0 - Deduplication (before enrichment/filtering)
 	if p.deduplication != nil {
 		dedupResult, err := p.deduplication.ProcessAlert(ctx, alert)
 		if err != nil {
 			p.logger.Error("Deduplication failed", "error", err, "alert", alert.AlertName)
+			trace.Add(core.DecisionStageDeduplication, "error", err.Error(), nil)
 			// Continue with processing even if deduplication fails (graceful degradation)
 		} else {
 			p.logger.Info("Deduplication result",
@@ -111,12 +166,14 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 				"alert", alert.AlertName,
 				"fingerprint", alert.Fingerprint,
 				"processing_time", dedupResult.ProcessingTime)
+			trace.Add(core.DecisionStageDeduplication, string(dedupResult.Action), "", nil)
 
 			// If alert was ignored (exact duplicate), skip further processing
 			if dedupResult.Action == ProcessActionIgnored {
 				p.logger.Info("Alert ignored as duplicate, skipping processing",
 					"alert", alert.AlertName,
 					"fingerprint", alert.Fingerprint)
+				trace.Finish(core.DecisionResultDuplicate)
 				return nil // Not an error, just a duplicate
 			}
 
@@ -125,6 +182,14 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 		}
 	}
 
+	// Storm detection counts new and changed alerts; exact duplicates
+	// (re-sends of firing alerts) do not add to the ingest rate.
+	if p.stormDetector != nil {
+		if storm := p.stormDetector.Count(alert); storm != nil {
+			p.processStormAlert(ctx, storm)
+		}
+	}
+
 	// TN-130 PARITY-A2: Step 0.5 — Update inhibition cache and cleanup on status change
 	if p.inhibitionCache != nil {
 		switch alert.Status {
@@ -146,10 +211,45 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 			}
 			if p.inhibitionState != nil {
 				p.cleanupInhibitionsForSource(ctx, alert.Fingerprint)
+				p.clearInhibition(ctx, alert.Fingerprint)
 			}
 		}
 	}
 
+	// Step 0.75 - Silence check: silenced alerts are kept in history (dedup
+	// already stored them) and in the inhibition cache, but are not published.
+	if p.silenceEngine != nil && alert.Status == core.StatusFiring {
+		now := time.Now()
+		evaluations := p.silenceEngine.Evaluate(alert.Labels, now)
+		alert.SilencedBy = matchedSilenceIDs(evaluations)
+		if trace != nil {
+			evaluated := make(map[string]string, len(evaluations))
+			for _, ev := range evaluations {
+				evaluated[ev.SilenceID] = fmt.Sprintf("%t", ev.Matched)
+			}
+			outcome := "not_silenced"
+			if alert.IsSilenced() {
+				outcome = "silenced"
+			}
+			trace.Add(core.DecisionStageSilence, outcome, "", evaluated)
+		}
+		if alert.IsSilenced() {
+			p.silenceEngine.RecordMatches(alert.SilencedBy, now)
+			p.logger.Info("Alert silenced",
+				"alert", alert.AlertName,
+				"fingerprint", alert.Fingerprint,
+				"silenced_by", alert.SilencedBy)
+			if p.businessMetrics != nil {
+				p.businessMetrics.RecordSilenceCheck("silenced")
+			}
+			trace.Finish(core.DecisionResultSilenced)
+			return nil
+		}
+		if p.businessMetrics != nil {
+			p.businessMetrics.RecordSilenceCheck("allowed")
+		}
+	}
+
 	// TN-130 Phase 6: Step 1 - Inhibition check (after dedup, before classification)
 	if p.inhibitionMatcher != nil && alert.Status == core.StatusFiring {
 		inhibitionResult, err := p.inhibitionMatcher.ShouldInhibit(ctx, alert)
@@ -159,6 +259,7 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 				"alert", alert.AlertName,
 				"fingerprint", alert.Fingerprint)
 			// Fail-safe: continue processing on inhibition error
+			trace.Add(core.DecisionStageInhibition, "error", err.Error(), nil)
 		} else if inhibitionResult != nil && inhibitionResult.Matched {
 			p.logger.Info("Alert inhibited by rule",
 				"alert", alert.AlertName,
@@ -189,6 +290,12 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 				p.businessMetrics.RecordInhibitionDuration("check", inhibitionResult.MatchDuration.Seconds())
 			}
 
+			trace.Add(core.DecisionStageInhibition, "inhibited", "", map[string]string{
+				"rule":         inhibitionResult.Rule.Name,
+				"inhibited_by": inhibitionResult.InhibitedBy.Fingerprint,
+			})
+			trace.Finish(core.DecisionResultInhibited)
+
 			// Skip publishing - alert is inhibited
 			return nil
 		} else {
@@ -197,10 +304,77 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 				"alert", alert.AlertName,
 				"fingerprint", alert.Fingerprint)
 
+			// The alert may have been inhibited by a previous check
+			p.clearInhibition(ctx, alert.Fingerprint)
+
 			// Record allowed metric
 			if p.businessMetrics != nil {
 				p.businessMetrics.RecordInhibitionCheck("allowed")
 			}
+			trace.Add(core.DecisionStageInhibition, "not_inhibited", "", nil)
+		}
+	}
+
+	// Routing conditions: firing alerts are only published while their live
+	// PromQL condition holds, e.g. the service is actually down. Resolved
+	// alerts always pass so receivers never miss a resolution.
+	if p.routingConditions != nil && alert.Status == core.StatusFiring {
+		decision := p.routingConditions.Evaluate(ctx, alert.Labels)
+		if decision.Rule != "" {
+			details := map[string]string{"rule": decision.Rule, "query": decision.Query}
+			if !decision.Met {
+				p.logger.Info("Alert held back, routing condition not met",
+					"alert", alert.AlertName,
+					"fingerprint", alert.Fingerprint,
+					"rule", decision.Rule,
+					"reason", decision.Reason)
+				trace.Add(core.DecisionStageCondition, "not_met", decision.Reason, details)
+				trace.Finish(core.DecisionResultConditionNotMet)
+				return nil
+			}
+			trace.Add(core.DecisionStageCondition, "met", decision.Reason, details)
+		}
+	}
+
+	// Composite alerts: children feed alert-of-alerts rules. Composites that
+	// start firing or resolve are processed like any other alert; children
+	// covered by a firing composite are not published on their own.
+	if p.compositeAlerts != nil {
+		obs := p.compositeAlerts.Observe(alert)
+		for _, composite := range obs.Transitions {
+			p.processCompositeAlert(ctx, composite)
+		}
+		if obs.Suppressed {
+			p.logger.Info("Alert covered by composite alert",
+				"alert", alert.AlertName,
+				"fingerprint", alert.Fingerprint,
+				"rule", obs.Rule,
+				"composite", obs.Composite)
+			trace.Add(core.DecisionStageComposite, "covered", "", map[string]string{
+				"rule":      obs.Rule,
+				"composite": obs.Composite,
+			})
+			trace.Finish(core.DecisionResultComposited)
+			return nil
+		}
+	}
+
+	// Storm mode: alerts that are neither silenced nor otherwise held back
+	// are sent in digests, except the first critical alert of each group.
+	if p.stormDetector != nil {
+		obs := p.stormDetector.Observe(alert)
+		if obs.Suppressed {
+			p.logger.Debug("Alert held back during storm",
+				"alert", alert.AlertName,
+				"fingerprint", alert.Fingerprint,
+				"reason", obs.Reason,
+				"group", obs.Group)
+			trace.Add(core.DecisionStageStorm, obs.Reason, "", map[string]string{"group": obs.Group})
+			trace.Finish(core.DecisionResultStormSuppressed)
+			return nil
+		}
+		if obs.Group != "" {
+			trace.Add(core.DecisionStageStorm, "paged", "first critical alert of its group", map[string]string{"group": obs.Group})
 		}
 	}
 
@@ -217,19 +391,20 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 		"fingerprint", alert.Fingerprint,
 		"mode", mode,
 	)
+	trace.Add(core.DecisionStageEnrichmentMode, string(mode), "", nil)
 
 	// Route to appropriate handler based on mode
 	var processErr error
 	switch mode {
 	case EnrichmentModeTransparentWithRecommendations:
-		processErr = p.processTransparentWithRecommendations(ctx, alert)
+		processErr = p.processTransparentWithRecommendations(ctx, alert, trace)
 	case EnrichmentModeTransparent:
-		processErr = p.processTransparent(ctx, alert)
+		processErr = p.processTransparent(ctx, alert, trace)
 	case EnrichmentModeEnriched:
-		processErr = p.processEnriched(ctx, alert)
+		processErr = p.processEnriched(ctx, alert, trace)
 	default:
 		p.logger.Warn("Unknown enrichment mode, falling back to enriched", "mode", mode)
-		processErr = p.processEnriched(ctx, alert)
+		processErr = p.processEnriched(ctx, alert, trace)
 	}
 
 	// Record metrics
@@ -240,6 +415,8 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 	}
 
 	if processErr != nil {
+		trace.Add(core.DecisionStageRoute, "error", processErr.Error(), nil)
+		trace.Finish(core.DecisionResultFailed)
 		p.logger.Error("Alert processing failed",
 			"alert", alert.AlertName,
 			"mode", mode,
@@ -258,8 +435,37 @@ func (p *AlertProcessor) ProcessAlert(ctx context.Context, alert *core.Alert) er
 	return nil
 }
 
+// processCompositeAlert runs a composite alert through the pipeline. Failures
+// are logged: they must not fail the child alert that triggered them.
+func (p *AlertProcessor) processCompositeAlert(ctx context.Context, composite *core.Alert) {
+	p.logger.Info("Composite alert changed",
+		"alert", composite.AlertName,
+		"fingerprint", composite.Fingerprint,
+		"status", composite.Status)
+	if p.compositeHook != nil {
+		p.compositeHook(composite)
+	}
+	if err := p.ProcessAlert(ctx, composite); err != nil {
+		p.logger.Warn("Failed to process composite alert",
+			"alert", composite.AlertName,
+			"fingerprint", composite.Fingerprint,
+			"error", err)
+	}
+}
+
+// processStormAlert runs the AlertStorm notification through the pipeline.
+// Failures are logged: they must not fail the alert that started the storm.
+func (p *AlertProcessor) processStormAlert(ctx context.Context, storm *core.Alert) {
+	if err := p.ProcessAlert(ctx, storm); err != nil {
+		p.logger.Warn("Failed to process storm alert",
+			"alert", storm.AlertName,
+			"fingerprint", storm.Fingerprint,
+			"error", err)
+	}
+}
+
 // processTransparentWithRecommendations bypasses all processing (emergency mode)
-func (p *AlertProcessor) processTransparentWithRecommendations(ctx context.Context, alert *core.Alert) error {
+func (p *AlertProcessor) processTransparentWithRecommendations(ctx context.Context, alert *core.Alert, trace *core.DecisionTrace) error {
 	p.logger.Info("Processing in transparent_with_recommendations mode (bypass all)",
 		"alert", alert.AlertName,
 	)
@@ -267,11 +473,14 @@ func (p *AlertProcessor) processTransparentWithRecommendations(ctx context.Conte
 	// NO LLM classification
 	// NO filtering
 	// Publish to ALL targets immediately
+	trace.Add(core.DecisionStageClassification, "skipped", "transparent_with_recommendations mode", nil)
+	trace.Add(core.DecisionStageFilter, "skipped", "transparent_with_recommendations mode", nil)
+	trace.Add(core.DecisionStageRoute, "all_targets", "", nil)
 	return p.publisher.PublishToAll(ctx, alert)
 }
 
 // processTransparent processes without LLM but with filtering
-func (p *AlertProcessor) processTransparent(ctx context.Context, alert *core.Alert) error {
+func (p *AlertProcessor) processTransparent(ctx context.Context, alert *core.Alert, trace *core.DecisionTrace) error {
 	p.logger.Info("Processing in transparent mode (no LLM, with filtering)",
 		"alert", alert.AlertName,
 	)
@@ -285,15 +494,19 @@ func (p *AlertProcessor) processTransparent(ctx context.Context, alert *core.Ale
 			"reason", reason,
 		)
 		// TODO: Record filter metrics
+		trace.Add(core.DecisionStageFilter, "blocked", reason, nil)
+		trace.Finish(core.DecisionResultFiltered)
 		return nil // Not an error, just filtered out
 	}
+	trace.Add(core.DecisionStageFilter, "allowed", reason, nil)
 
 	// Publish to ALL configured targets
+	trace.Add(core.DecisionStageRoute, "all_targets", "", nil)
 	return p.publisher.PublishToAll(ctx, alert)
 }
 
 // processEnriched processes with full LLM classification and filtering (production mode)
-func (p *AlertProcessor) processEnriched(ctx context.Context, alert *core.Alert) error {
+func (p *AlertProcessor) processEnriched(ctx context.Context, alert *core.Alert, trace *core.DecisionTrace) error {
 	p.logger.Info("Processing in enriched mode (full LLM + filtering)",
 		"alert", alert.AlertName,
 	)
@@ -301,7 +514,17 @@ func (p *AlertProcessor) processEnriched(ctx context.Context, alert *core.Alert)
 	// Check if LLM client is available
 	if p.llmClient == nil {
 		p.logger.Warn("LLM client not configured, falling back to transparent mode")
-		return p.processTransparent(ctx, alert)
+		trace.Add(core.DecisionStageClassification, "none", "llm client not configured", nil)
+		return p.processTransparent(ctx, alert, trace)
+	}
+
+	if sampled, policy := p.sample(alert, SamplingStageClassification); !sampled {
+		p.logger.Debug("LLM classification sampled out, processing transparently",
+			"alert", alert.AlertName,
+			"policy", policy,
+		)
+		trace.Add(core.DecisionStageClassification, "sampled_out", "sampling policy "+policy, nil)
+		return p.processTransparent(ctx, alert, trace)
 	}
 
 	// Step 1: Classify with LLM
@@ -312,7 +535,8 @@ func (p *AlertProcessor) processEnriched(ctx context.Context, alert *core.Alert)
 			"error", err,
 		)
 		// Graceful degradation: fall back to transparent mode
-		return p.processTransparent(ctx, alert)
+		trace.Add(core.DecisionStageClassification, "none", "classification failed: "+err.Error(), nil)
+		return p.processTransparent(ctx, alert, trace)
 	}
 
 	p.logger.Info("Alert classified",
@@ -320,10 +544,21 @@ func (p *AlertProcessor) processEnriched(ctx context.Context, alert *core.Alert)
 		"severity", classification.Severity,
 		"confidence", classification.Confidence,
 	)
+	trace.Add(core.DecisionStageClassification, classificationSource(classification), "", map[string]string{
+		"severity":   string(classification.Severity),
+		"confidence": fmt.Sprintf("%.2f", classification.Confidence),
+	})
 
 	// PHASE-5A: Submit fire-and-forget investigation (does not block Phase 1).
 	if p.investigationQueue != nil {
-		p.investigationQueue.Submit(alert, classification)
+		if sampled, policy := p.sample(alert, SamplingStageEnrichment); sampled {
+			p.investigationQueue.Submit(alert, classification)
+		} else {
+			p.logger.Debug("Investigation sampled out",
+				"alert", alert.AlertName,
+				"policy", policy,
+			)
+		}
 	}
 
 	// Step 2: Apply filters (with classification context)
@@ -335,13 +570,35 @@ func (p *AlertProcessor) processEnriched(ctx context.Context, alert *core.Alert)
 			"severity", classification.Severity,
 		)
 		// TODO: Record filter metrics
+		trace.Add(core.DecisionStageFilter, "blocked", reason, nil)
+		trace.Finish(core.DecisionResultFiltered)
 		return nil // Not an error, just filtered out
 	}
+	trace.Add(core.DecisionStageFilter, "allowed", reason, nil)
 
 	// Step 3: Publish with classification (smart routing)
+	trace.Add(core.DecisionStageRoute, "classification", "", map[string]string{
+		"severity": string(classification.Severity),
+	})
 	return p.publisher.PublishWithClassification(ctx, alert, classification)
 }
 
+// sample reports whether alert goes through stage; true without a sampler.
+func (p *AlertProcessor) sample(alert *core.Alert, stage SamplingStage) (bool, string) {
+	if p.sampler == nil {
+		return true, ""
+	}
+	return p.sampler.Sample(alert, stage)
+}
+
+// classificationSource reports where a classification came from (llm or fallback rules).
+func classificationSource(classification *core.ClassificationResult) string {
+	if fallback, ok := classification.Metadata["fallback"].(bool); ok && fallback {
+		return "fallback"
+	}
+	return "llm"
+}
+
 // cleanupInhibitionsForSource removes all active inhibitions caused by the given source alert.
 // Called when a source (inhibitor) alert resolves.
 func (p *AlertProcessor) cleanupInhibitionsForSource(ctx context.Context, sourceFingerprint string) {
@@ -374,6 +631,27 @@ func (p *AlertProcessor) cleanupInhibitionsForSource(ctx context.Context, source
 	}
 }
 
+// clearInhibition removes the recorded inhibition of a target alert that is
+// resolved or no longer inhibited, so the API stops reporting it as inhibited.
+func (p *AlertProcessor) clearInhibition(ctx context.Context, targetFingerprint string) {
+	if p.inhibitionState == nil || targetFingerprint == "" {
+		return
+	}
+
+	inhibited, err := p.inhibitionState.IsInhibited(ctx, targetFingerprint)
+	if err != nil || !inhibited {
+		return
+	}
+	if err := p.inhibitionState.RemoveInhibition(ctx, targetFingerprint); err != nil {
+		p.logger.Warn("Failed to remove inhibition",
+			"error", err,
+			"target_fingerprint", targetFingerprint)
+		return
+	}
+	p.logger.Info("Inhibition removed (target no longer inhibited)",
+		"target_fingerprint", targetFingerprint)
+}
+
 // Health checks if all dependencies are healthy
 func (p *AlertProcessor) Health(ctx context.Context) error {
 	// Check enrichment manager
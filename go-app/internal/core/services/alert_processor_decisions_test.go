@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+type traceCollector struct {
+	traces []*core.DecisionTrace
+}
+
+func (c *traceCollector) Record(trace *core.DecisionTrace) {
+	c.traces = append(c.traces, trace)
+}
+
+type blockByNameFilter string
+
+func (f blockByNameFilter) ShouldBlock(alert *core.Alert, _ *core.ClassificationResult) (bool, string) {
+	if alert.AlertName == string(f) {
+		return true, "blocked by test rule"
+	}
+	return false, ""
+}
+
+func TestAlertProcessor_RecordsDecisionTrace(t *testing.T) {
+	collector := &traceCollector{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine: blockByNameFilter("Noisy"),
+		Publisher:    &recordingPublisher{},
+		SilenceEngine: NewSilenceEngine(staticSilenceSource{
+			{ID: "s-cpu", Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "HighCPU", IsEqual: true}}},
+			{ID: "s-disk", Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}}},
+		}, nil),
+		DecisionLog: collector,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, name := range []string{"HighCPU", "Noisy", "Latency"} {
+		alert := &core.Alert{Fingerprint: name, AlertName: name, Status: core.StatusFiring, Labels: map[string]string{"alertname": name}}
+		require.NoError(t, processor.ProcessAlert(ctx, alert))
+	}
+	require.Len(t, collector.traces, 3)
+
+	silenced := collector.traces[0]
+	assert.Equal(t, core.DecisionResultSilenced, silenced.Result)
+	require.Len(t, silenced.Decisions, 1)
+	assert.Equal(t, core.Decision{
+		Stage:   core.DecisionStageSilence,
+		Outcome: "silenced",
+		Details: map[string]string{"s-cpu": "true", "s-disk": "false"},
+	}, silenced.Decisions[0])
+
+	filtered := collector.traces[1]
+	assert.Equal(t, core.DecisionResultFiltered, filtered.Result)
+	last := filtered.Decisions[len(filtered.Decisions)-1]
+	assert.Equal(t, core.DecisionStageFilter, last.Stage)
+	assert.Equal(t, "blocked by test rule", last.Reason)
+
+	published := collector.traces[2]
+	assert.Equal(t, core.DecisionResultPublished, published.Result)
+	stages := make([]core.DecisionStage, 0, len(published.Decisions))
+	for _, d := range published.Decisions {
+		stages = append(stages, d.Stage)
+	}
+	assert.Equal(t, []core.DecisionStage{
+		core.DecisionStageSilence,
+		core.DecisionStageEnrichmentMode,
+		core.DecisionStageClassification,
+		core.DecisionStageFilter,
+		core.DecisionStageRoute,
+	}, stages)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+)
+
+func TestAlertProcessor_InhibitionLifecycle(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rules := []inhibition.InhibitionRule{{
+		Name:        "node-down",
+		SourceMatch: map[string]string{"alertname": "NodeDown"},
+		TargetMatch: map[string]string{"severity": "warning"},
+		Equal:       []string{"node"},
+	}}
+	require.NoError(t, inhibition.CompileRules(rules))
+
+	alertCache := inhibition.NewTwoTierAlertCache(nil, logger)
+	defer alertCache.Stop()
+	state := inhibition.NewDefaultStateManager(nil, logger, nil)
+	publisher := &recordingPublisher{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine:      allowAllFilter{},
+		Publisher:         publisher,
+		InhibitionCache:   alertCache,
+		InhibitionMatcher: inhibition.NewMatcher(alertCache, rules, logger),
+		InhibitionState:   state,
+		Logger:            logger,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	alert := func(fingerprint string, status core.AlertStatus, labels map[string]string) *core.Alert {
+		return &core.Alert{Fingerprint: fingerprint, AlertName: labels["alertname"], Status: status, Labels: labels}
+	}
+	source := map[string]string{"alertname": "NodeDown", "node": "n1"}
+	target := map[string]string{"alertname": "DiskSlow", "severity": "warning", "node": "n1"}
+
+	require.NoError(t, processor.ProcessAlert(ctx, alert("source", core.StatusFiring, source)))
+	require.NoError(t, processor.ProcessAlert(ctx, alert("target", core.StatusFiring, target)))
+	assert.Len(t, publisher.published, 1, "inhibited target is not published")
+	inhibited, err := state.GetInhibitionState(ctx, "target")
+	require.NoError(t, err)
+	assert.Equal(t, "source", inhibited.SourceFingerprint)
+
+	// Resolving the source lifts the inhibition.
+	require.NoError(t, processor.ProcessAlert(ctx, alert("source", core.StatusResolved, source)))
+	ok, err := state.IsInhibited(ctx, "target")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, processor.ProcessAlert(ctx, alert("target", core.StatusFiring, target)))
+	assert.Len(t, publisher.published, 3)
+
+	// A resolved target no longer reports as inhibited.
+	require.NoError(t, processor.ProcessAlert(ctx, alert("source", core.StatusFiring, source)))
+	require.NoError(t, processor.ProcessAlert(ctx, alert("target", core.StatusFiring, target)))
+	ok, _ = state.IsInhibited(ctx, "target")
+	require.True(t, ok)
+	require.NoError(t, processor.ProcessAlert(ctx, alert("target", core.StatusResolved, target)))
+	ok, _ = state.IsInhibited(ctx, "target")
+	assert.False(t, ok)
+}
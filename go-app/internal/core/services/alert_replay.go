@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/inhibition"
+	"github.com/ipiton/AMP/internal/infrastructure/promql"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+const defaultAlertReplayResolveTimeout = 5 * time.Minute
+
+// PrometheusAlertLister lists the alerts of Prometheus. Implemented by
+// *promql.Client.
+type PrometheusAlertLister interface {
+	Alerts(ctx context.Context) ([]promql.Alert, error)
+}
+
+// AlertmanagerAlertLister lists the alerts of an Alertmanager. Implemented
+// by *alertmanager.Client.
+type AlertmanagerAlertLister interface {
+	ListAlerts(ctx context.Context) ([]core.APIGettableAlert, error)
+}
+
+// AlertReplaySource is a Prometheus or an Alertmanager to replay firing
+// alerts from. Exactly one of Prometheus and Alertmanager is set.
+type AlertReplaySource struct {
+	Name         string
+	Prometheus   PrometheusAlertLister
+	Alertmanager AlertmanagerAlertLister
+}
+
+// AlertReplayConfig configures the AlertReplayer.
+type AlertReplayConfig struct {
+	Sources []AlertReplaySource
+
+	// ResolveTimeout is the endsAt given to alerts replayed from Prometheus
+	// (default: 5m), like Alertmanager's resolve_timeout. The next
+	// evaluation cycle pushes them again with their own endsAt.
+	ResolveTimeout time.Duration
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// AlertReplayResult reports a replay.
+type AlertReplayResult struct {
+	// Replayed counts the distinct firing alerts recorded.
+	Replayed int                       `json:"replayed"`
+	Sources  []AlertReplaySourceResult `json:"sources"`
+}
+
+// AlertReplaySourceResult reports the alerts of one source.
+type AlertReplaySourceResult struct {
+	Name   string `json:"name"`
+	Alerts int    `json:"alerts"` // firing alerts returned
+	Error  string `json:"error,omitempty"`
+}
+
+// AlertReplayer warms up a cold start with the alerts already firing in
+// Prometheus servers or Alertmanagers, so the active alerts and inhibition
+// sources are known before the next evaluation cycle pushes them again.
+//
+// Replayed alerts are recorded in the alert store and the inhibition cache
+// with the fingerprints live pushes get, so the pushes update them. They are
+// not processed: nothing is published for them.
+type AlertReplayer struct {
+	store  *memory.AlertStore
+	cache  inhibition.ActiveAlertCache
+	config AlertReplayConfig
+	logger *slog.Logger
+	clock  clock.Clock
+}
+
+// NewAlertReplayer creates an alert replayer. store and cache are optional.
+func NewAlertReplayer(store *memory.AlertStore, cache inhibition.ActiveAlertCache, config AlertReplayConfig) *AlertReplayer {
+	if config.ResolveTimeout <= 0 {
+		config.ResolveTimeout = defaultAlertReplayResolveTimeout
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &AlertReplayer{
+		store:  store,
+		cache:  cache,
+		config: config,
+		logger: config.Logger.With("component", "alert_replay"),
+		clock:  config.Clock,
+	}
+}
+
+// Replay lists the firing alerts of all sources concurrently and records
+// them. An alert firing in several sources is recorded once. Failing sources
+// are reported in the result and do not stop the others.
+func (r *AlertReplayer) Replay(ctx context.Context) AlertReplayResult {
+	now := r.clock.Now().UTC()
+
+	results := make([]AlertReplaySourceResult, len(r.config.Sources))
+	alerts := make([][]*core.Alert, len(r.config.Sources))
+	var wg sync.WaitGroup
+	for i, source := range r.config.Sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].Name = source.Name
+			firing, err := r.list(ctx, source, now)
+			if err != nil {
+				results[i].Error = err.Error()
+				r.logger.Warn("Failed to replay alerts", "source", source.Name, "error", err)
+				return
+			}
+			results[i].Alerts = len(firing)
+			alerts[i] = firing
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	var inputs []core.AlertIngestInput
+	for _, firing := range alerts {
+		for _, alert := range firing {
+			if seen[alert.Fingerprint] {
+				continue
+			}
+			seen[alert.Fingerprint] = true
+			inputs = append(inputs, toReplayIngestInput(alert))
+
+			if r.cache != nil {
+				if err := r.cache.AddFiringAlert(ctx, alert); err != nil {
+					r.logger.Warn("Failed to add replayed alert to inhibition cache",
+						"alert", alert.AlertName, "fingerprint", alert.Fingerprint, "error", err)
+				}
+			}
+		}
+	}
+	if r.store != nil && len(inputs) > 0 {
+		if err := r.store.IngestBatch(inputs, now); err != nil {
+			r.logger.Warn("Failed to store replayed alerts", "error", err)
+		}
+	}
+
+	r.logger.Info("Alerts replayed", "alerts", len(seen), "sources", len(results))
+	return AlertReplayResult{Replayed: len(seen), Sources: results}
+}
+
+// list returns the firing alerts of source.
+func (r *AlertReplayer) list(ctx context.Context, source AlertReplaySource, now time.Time) ([]*core.Alert, error) {
+	if source.Prometheus != nil {
+		alerts, err := source.Prometheus.Alerts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		endsAt := now.Add(r.config.ResolveTimeout)
+		firing := make([]*core.Alert, 0, len(alerts))
+		for _, alert := range alerts {
+			// Pending alerts have not fired yet
+			if alert.State != "firing" || alert.Labels[core.LabelAlertName] == "" {
+				continue
+			}
+			firing = append(firing, &core.Alert{
+				Fingerprint: domain.LabelsFingerprint(alert.Labels),
+				AlertName:   alert.Labels[core.LabelAlertName],
+				Status:      core.StatusFiring,
+				Labels:      alert.Labels,
+				Annotations: alert.Annotations,
+				StartsAt:    alert.ActiveAt.UTC(),
+				EndsAt:      &endsAt,
+				Timestamp:   &now,
+			})
+		}
+		return firing, nil
+	}
+
+	alerts, err := source.Alertmanager.ListAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	firing := make([]*core.Alert, 0, len(alerts))
+	for i := range alerts {
+		alert, err := alertFromReplayedGettable(&alerts[i], now)
+		if err != nil {
+			r.logger.Debug("Skipping replayed alert", "source", source.Name, "error", err)
+			continue
+		}
+		// Silenced and inhibited alerts are still firing
+		if alert.EndsAt != nil && !alert.EndsAt.After(now) {
+			continue
+		}
+		firing = append(firing, alert)
+	}
+	return firing, nil
+}
+
+// alertFromReplayedGettable converts an alert of the Alertmanager API,
+// keeping its fingerprint as the Alertmanager webhook sends it.
+func alertFromReplayedGettable(in *core.APIGettableAlert, now time.Time) (*core.Alert, error) {
+	if in.Fingerprint == "" || in.Labels[core.LabelAlertName] == "" {
+		return nil, fmt.Errorf("missing fingerprint or alertname")
+	}
+	startsAt, err := time.Parse(time.RFC3339Nano, in.StartsAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startsAt: %w", err)
+	}
+
+	alert := &core.Alert{
+		Fingerprint: in.Fingerprint,
+		AlertName:   in.Labels[core.LabelAlertName],
+		Status:      core.StatusFiring,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+		StartsAt:    startsAt.UTC(),
+		Timestamp:   &now,
+	}
+	if in.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339Nano, in.EndsAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endsAt: %w", err)
+		}
+		if !endsAt.IsZero() {
+			endsAt = endsAt.UTC()
+			alert.EndsAt = &endsAt
+		}
+	}
+	if in.GeneratorURL != "" {
+		generatorURL := in.GeneratorURL
+		alert.GeneratorURL = &generatorURL
+	}
+	return alert, nil
+}
+
+func toReplayIngestInput(alert *core.Alert) core.AlertIngestInput {
+	in := core.AlertIngestInput{
+		Labels:      alert.Labels,
+		Annotations: alert.Annotations,
+		StartsAt:    alert.StartsAt.Format(time.RFC3339),
+		Status:      string(alert.Status),
+		Fingerprint: alert.Fingerprint,
+	}
+	if alert.EndsAt != nil {
+		in.EndsAt = alert.EndsAt.Format(time.RFC3339)
+	}
+	if alert.GeneratorURL != nil {
+		in.GeneratorURL = *alert.GeneratorURL
+	}
+	return in
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/promql"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+type fakePrometheusAlerts struct {
+	alerts []promql.Alert
+	err    error
+}
+
+func (f *fakePrometheusAlerts) Alerts(context.Context) ([]promql.Alert, error) {
+	return f.alerts, f.err
+}
+
+type fakeAlertmanagerAlerts struct {
+	alerts []core.APIGettableAlert
+}
+
+func (f *fakeAlertmanagerAlerts) ListAlerts(context.Context) ([]core.APIGettableAlert, error) {
+	return f.alerts, nil
+}
+
+// firingAlertCache records the alerts added to the inhibition cache.
+type firingAlertCache struct {
+	mu     sync.Mutex
+	alerts map[string]*core.Alert
+}
+
+func (c *firingAlertCache) GetFiringAlerts(context.Context) ([]*core.Alert, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	alerts := make([]*core.Alert, 0, len(c.alerts))
+	for _, alert := range c.alerts {
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+func (c *firingAlertCache) AddFiringAlert(_ context.Context, alert *core.Alert) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.alerts[alert.Fingerprint] = alert
+	return nil
+}
+
+func (c *firingAlertCache) RemoveAlert(_ context.Context, fingerprint string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.alerts, fingerprint)
+	return nil
+}
+
+func TestAlertReplayer_Replay(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 10, 30, 0, 0, time.UTC))
+	now := fake.Now()
+	activeAt := now.Add(-time.Hour)
+
+	prometheus := &fakePrometheusAlerts{alerts: []promql.Alert{
+		{Labels: map[string]string{"alertname": "HighCPU", "instance": "a"}, State: "firing", ActiveAt: activeAt},
+		{Labels: map[string]string{"alertname": "HighCPU", "instance": "b"}, State: "pending", ActiveAt: now},
+	}}
+	// The second Prometheus of an HA pair evaluates the same rules
+	replica := &fakePrometheusAlerts{alerts: prometheus.alerts[:1]}
+	alertmanager := &fakeAlertmanagerAlerts{alerts: []core.APIGettableAlert{
+		{Fingerprint: "am-1", Labels: map[string]string{"alertname": "NodeDown"}, StartsAt: activeAt.Format(time.RFC3339), EndsAt: now.Add(time.Minute).Format(time.RFC3339)},
+		{Fingerprint: "am-2", Labels: map[string]string{"alertname": "Resolved"}, StartsAt: activeAt.Format(time.RFC3339), EndsAt: now.Add(-time.Minute).Format(time.RFC3339)},
+	}}
+	failing := &fakePrometheusAlerts{err: errors.New("connection refused")}
+
+	store := memory.NewAlertStore()
+	cache := &firingAlertCache{alerts: map[string]*core.Alert{}}
+	replayer := NewAlertReplayer(store, cache, AlertReplayConfig{
+		Sources: []AlertReplaySource{
+			{Name: "prometheus-0", Prometheus: prometheus},
+			{Name: "prometheus-1", Prometheus: replica},
+			{Name: "legacy", Alertmanager: alertmanager},
+			{Name: "down", Prometheus: failing},
+		},
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:  fake,
+	})
+
+	result := replayer.Replay(context.Background())
+	assert.Equal(t, 2, result.Replayed)
+	assert.Equal(t, []AlertReplaySourceResult{
+		{Name: "prometheus-0", Alerts: 1},
+		{Name: "prometheus-1", Alerts: 1},
+		{Name: "legacy", Alerts: 1},
+		{Name: "down", Error: "connection refused"},
+	}, result.Sources)
+
+	stored := store.List("", false)
+	require.Len(t, stored, 2)
+	fingerprints := []string{stored[0].Fingerprint, stored[1].Fingerprint}
+	assert.ElementsMatch(t, []string{"am-1", domain.LabelsFingerprint(prometheus.alerts[0].Labels)}, fingerprints)
+
+	require.Len(t, cache.alerts, 2)
+	cpu := cache.alerts[domain.LabelsFingerprint(prometheus.alerts[0].Labels)]
+	require.NotNil(t, cpu)
+	assert.Equal(t, activeAt, cpu.StartsAt)
+	assert.Equal(t, now.Add(defaultAlertReplayResolveTimeout), *cpu.EndsAt)
+}
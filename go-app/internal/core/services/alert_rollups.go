@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/ipiton/AMP/pkg/metrics"
+)
+
+const (
+	defaultAlertRollupInterval        = 5 * time.Minute
+	defaultAlertRollupLateness        = time.Hour
+	defaultAlertRollupHourlyRetention = 14 * 24 * time.Hour
+	defaultAlertRollupDailyRetention  = 400 * 24 * time.Hour
+	defaultAlertRollupTeamLabel       = "team"
+
+	alertRollupPageSize = 1000
+)
+
+// AlertRollupConfig configures the AlertRollupJob.
+type AlertRollupConfig struct {
+	// Interval between runs (default: 5m).
+	Interval time.Duration
+
+	// Lateness is how far back each run recomputes hourly buckets, for
+	// alerts stored after the hour they started in (default: 1h). Older
+	// buckets are final.
+	Lateness time.Duration
+
+	// HourlyRetention and DailyRetention are how long hourly and daily
+	// rollups are kept (default: 14 days and 400 days).
+	HourlyRetention time.Duration
+	DailyRetention  time.Duration
+
+	// TeamLabel is the alert label counted as the team (default: team).
+	TeamLabel string
+
+	// Metrics (optional).
+	Metrics *metrics.BusinessMetrics
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// AlertRollupJob maintains hourly and daily alert counts by severity,
+// alertname and team, so the dashboard and the stats API do not scan the
+// alert history. Alerts are counted in the UTC bucket they started in.
+//
+// Each run recomputes the hourly buckets of the lateness window from the
+// alerts table, and the daily buckets they belong to from the hourly ones.
+// The first run also fills in hourly buckets missing within the retention.
+// Runs are idempotent, so replicas sharing a database may all run the job.
+type AlertRollupJob struct {
+	alerts core.AlertStorage
+	store  core.AlertRollupStore
+	config AlertRollupConfig
+	logger *slog.Logger
+	clock  clock.Clock
+
+	mu         sync.Mutex // serializes runs
+	backfilled bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAlertRollupJob creates an alert rollup job (not started).
+func NewAlertRollupJob(alerts core.AlertStorage, store core.AlertRollupStore, config AlertRollupConfig) *AlertRollupJob {
+	if config.Interval <= 0 {
+		config.Interval = defaultAlertRollupInterval
+	}
+	if config.Lateness <= 0 {
+		config.Lateness = defaultAlertRollupLateness
+	}
+	if config.HourlyRetention <= 0 {
+		config.HourlyRetention = defaultAlertRollupHourlyRetention
+	}
+	if config.DailyRetention <= 0 {
+		config.DailyRetention = defaultAlertRollupDailyRetention
+	}
+	if config.TeamLabel == "" {
+		config.TeamLabel = defaultAlertRollupTeamLabel
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &AlertRollupJob{
+		alerts: alerts,
+		store:  store,
+		config: config,
+		logger: config.Logger.With("component", "alert_rollups"),
+		clock:  config.Clock,
+	}
+}
+
+// Start runs the job immediately and then every Interval until ctx is
+// cancelled or Stop is called.
+func (j *AlertRollupJob) Start(ctx context.Context) {
+	ctx, j.cancel = context.WithCancel(ctx)
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := j.clock.NewTicker(j.config.Interval)
+		defer ticker.Stop()
+
+		j.runLogged(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				j.runLogged(ctx)
+			}
+		}
+	}()
+
+	j.logger.Info("Alert rollups started",
+		"interval", j.config.Interval,
+		"lateness", j.config.Lateness,
+		"hourly_retention", j.config.HourlyRetention,
+		"daily_retention", j.config.DailyRetention,
+	)
+}
+
+// Stop stops the job and waits for the running run to finish.
+func (j *AlertRollupJob) Stop() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+}
+
+func (j *AlertRollupJob) runLogged(ctx context.Context) {
+	if _, _, err := j.Run(ctx); err != nil && ctx.Err() == nil {
+		j.logger.Warn("Alert rollup run failed", "error", err)
+	}
+}
+
+// Run recomputes the hourly buckets of the lateness window (plus, on the
+// first successful run, those missing within the retention), the daily
+// buckets they belong to, and deletes rollups past their retention.
+// Returns how many hourly and daily buckets were written.
+func (j *AlertRollupJob) Run(ctx context.Context) (hourly, daily int, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	defer func() {
+		if j.config.Metrics != nil {
+			result := "ok"
+			if err != nil {
+				result = "error"
+			}
+			j.config.Metrics.RecordAlertRollup(result, hourly, daily)
+		}
+	}()
+
+	now := j.clock.Now().UTC()
+	current := core.AlertRollupHourly.Truncate(now)
+	hourlyCutoff := core.AlertRollupHourly.Truncate(now.Add(-j.config.HourlyRetention))
+
+	var hours []time.Time
+	if !j.backfilled {
+		if hours, err = j.missingHours(ctx, hourlyCutoff, current); err != nil {
+			return 0, 0, err
+		}
+	}
+	for hour := core.AlertRollupHourly.Truncate(now.Add(-j.config.Lateness)); !hour.After(current); hour = hour.Add(time.Hour) {
+		if !slices.ContainsFunc(hours, hour.Equal) {
+			hours = append(hours, hour)
+		}
+	}
+	slices.SortFunc(hours, time.Time.Compare)
+
+	counts, err := j.count(ctx, hours[0], current.Add(time.Hour))
+	if err != nil {
+		return 0, 0, err
+	}
+	var days []time.Time
+	for _, hour := range hours {
+		if err := j.store.ReplaceAlertRollups(ctx, core.AlertRollupHourly, hour, counts[hour].rollups(core.AlertRollupHourly, hour)); err != nil {
+			return hourly, daily, err
+		}
+		hourly++
+
+		// Days partly past the hourly retention cannot be summed up
+		day := core.AlertRollupDaily.Truncate(hour)
+		if !day.Before(hourlyCutoff) && !slices.ContainsFunc(days, day.Equal) {
+			days = append(days, day)
+		}
+	}
+	j.backfilled = true
+
+	for _, day := range days {
+		if err := j.rollUpDay(ctx, day); err != nil {
+			return hourly, daily, err
+		}
+		daily++
+	}
+
+	if _, err := j.store.DeleteAlertRollupsBefore(ctx, core.AlertRollupHourly, hourlyCutoff); err != nil {
+		return hourly, daily, err
+	}
+	dailyCutoff := core.AlertRollupDaily.Truncate(now.Add(-j.config.DailyRetention))
+	if _, err := j.store.DeleteAlertRollupsBefore(ctx, core.AlertRollupDaily, dailyCutoff); err != nil {
+		return hourly, daily, err
+	}
+
+	j.logger.Debug("Alert rollups updated", "hourly", hourly, "daily", daily)
+	return hourly, daily, nil
+}
+
+// Query returns the rollups matching query, oldest bucket first.
+func (j *AlertRollupJob) Query(ctx context.Context, query core.AlertRollupQuery) ([]core.AlertRollup, error) {
+	return j.store.ListAlertRollups(ctx, query)
+}
+
+// missingHours returns the hours in [from, to) without a total rollup,
+// i.e. never computed.
+func (j *AlertRollupJob) missingHours(ctx context.Context, from, to time.Time) ([]time.Time, error) {
+	totals, err := j.store.ListAlertRollups(ctx, core.AlertRollupQuery{
+		Granularity: core.AlertRollupHourly,
+		Dimension:   core.AlertRollupTotal,
+		From:        from,
+		To:          to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	computed := make(map[int64]bool, len(totals))
+	for _, total := range totals {
+		computed[total.BucketStart.Unix()] = true
+	}
+	var missing []time.Time
+	for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+		if !computed[hour.Unix()] {
+			missing = append(missing, hour)
+		}
+	}
+	return missing, nil
+}
+
+// count counts the alerts that started in [from, to) by hour.
+func (j *AlertRollupJob) count(ctx context.Context, from, to time.Time) (map[time.Time]*alertRollupCounts, error) {
+	last := to.Add(-time.Nanosecond) // TimeRange.To is inclusive
+	counts := make(map[time.Time]*alertRollupCounts)
+	seen := make(map[string]bool)
+
+	for offset := 0; ; offset += alertRollupPageSize {
+		page, err := j.alerts.ListAlerts(ctx, &core.AlertFilters{
+			TimeRange: &core.TimeRange{From: &from, To: &last},
+			Limit:     alertRollupPageSize,
+			Offset:    offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list alerts: %w", err)
+		}
+
+		for _, alert := range page.Alerts {
+			// Alerts stored meanwhile shift the pages
+			if seen[alert.Fingerprint] || alert.StartsAt.Before(from) || !alert.StartsAt.Before(to) {
+				continue
+			}
+			seen[alert.Fingerprint] = true
+
+			hour := core.AlertRollupHourly.Truncate(alert.StartsAt)
+			if counts[hour] == nil {
+				counts[hour] = newAlertRollupCounts()
+			}
+			counts[hour].add(core.AlertRollupTotal, "", 1)
+			counts[hour].add(core.AlertRollupSeverity, alert.Labels["severity"], 1)
+			counts[hour].add(core.AlertRollupAlertName, alert.AlertName, 1)
+			counts[hour].add(core.AlertRollupTeam, alert.Labels[j.config.TeamLabel], 1)
+		}
+		if len(page.Alerts) < alertRollupPageSize {
+			return counts, nil
+		}
+	}
+}
+
+// rollUpDay recomputes the daily rollups of day from its hourly rollups.
+func (j *AlertRollupJob) rollUpDay(ctx context.Context, day time.Time) error {
+	hourly, err := j.store.ListAlertRollups(ctx, core.AlertRollupQuery{
+		Granularity: core.AlertRollupHourly,
+		From:        day,
+		To:          day.Add(core.AlertRollupDaily.Duration()),
+	})
+	if err != nil {
+		return err
+	}
+
+	counts := newAlertRollupCounts()
+	for _, rollup := range hourly {
+		counts.add(rollup.Dimension, rollup.Value, rollup.Count)
+	}
+	return j.store.ReplaceAlertRollups(ctx, core.AlertRollupDaily, day, counts.rollups(core.AlertRollupDaily, day))
+}
+
+// alertRollupCounts are the counts of one bucket by dimension and value.
+type alertRollupCounts struct {
+	counts map[core.AlertRollupDimension]map[string]int64
+}
+
+func newAlertRollupCounts() *alertRollupCounts {
+	return &alertRollupCounts{counts: make(map[core.AlertRollupDimension]map[string]int64)}
+}
+
+func (c *alertRollupCounts) add(dimension core.AlertRollupDimension, value string, n int64) {
+	if c.counts[dimension] == nil {
+		c.counts[dimension] = make(map[string]int64)
+	}
+	c.counts[dimension][value] += n
+}
+
+// rollups returns the rollups of the bucket, always including the total
+// (0 for empty buckets) that marks the bucket as computed.
+func (c *alertRollupCounts) rollups(granularity core.AlertRollupGranularity, bucketStart time.Time) []core.AlertRollup {
+	total := core.AlertRollup{Granularity: granularity, BucketStart: bucketStart, Dimension: core.AlertRollupTotal}
+	if c == nil {
+		return []core.AlertRollup{total}
+	}
+	total.Count = c.counts[core.AlertRollupTotal][""]
+
+	rollups := []core.AlertRollup{total}
+	for _, dimension := range core.AlertRollupDimensions[1:] {
+		values := c.counts[dimension]
+		for _, value := range slices.Sorted(maps.Keys(values)) {
+			rollups = append(rollups, core.AlertRollup{
+				Granularity: granularity,
+				BucketStart: bucketStart,
+				Dimension:   dimension,
+				Value:       value,
+				Count:       values[value],
+			})
+		}
+	}
+	return rollups
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+// memoryRollupStore keeps rollups like the repositories do.
+type memoryRollupStore struct {
+	mu      sync.Mutex
+	rollups []core.AlertRollup
+}
+
+func (s *memoryRollupStore) ReplaceAlertRollups(ctx context.Context, granularity core.AlertRollupGranularity, bucketStart time.Time, rollups []core.AlertRollup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rollups = slices.DeleteFunc(s.rollups, func(r core.AlertRollup) bool {
+		return r.Granularity == granularity && r.BucketStart.Equal(bucketStart)
+	})
+	s.rollups = append(s.rollups, rollups...)
+	return nil
+}
+
+func (s *memoryRollupStore) ListAlertRollups(ctx context.Context, query core.AlertRollupQuery) ([]core.AlertRollup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rollups []core.AlertRollup
+	for _, r := range s.rollups {
+		if r.Granularity == query.Granularity && !r.BucketStart.Before(query.From) && r.BucketStart.Before(query.To) &&
+			(query.Dimension == "" || r.Dimension == query.Dimension) {
+			rollups = append(rollups, r)
+		}
+	}
+	slices.SortStableFunc(rollups, func(a, b core.AlertRollup) int { return a.BucketStart.Compare(b.BucketStart) })
+	return rollups, nil
+}
+
+func (s *memoryRollupStore) DeleteAlertRollupsBefore(ctx context.Context, granularity core.AlertRollupGranularity, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.rollups)
+	s.rollups = slices.DeleteFunc(s.rollups, func(r core.AlertRollup) bool {
+		return r.Granularity == granularity && r.BucketStart.Before(cutoff)
+	})
+	return int64(before - len(s.rollups)), nil
+}
+
+// sums returns the summed rollups of granularity in [from, to).
+func (s *memoryRollupStore) sums(t *testing.T, granularity core.AlertRollupGranularity, from, to time.Time) map[core.AlertRollupDimension]map[string]int64 {
+	t.Helper()
+	rollups, err := s.ListAlertRollups(context.Background(), core.AlertRollupQuery{Granularity: granularity, From: from, To: to})
+	require.NoError(t, err)
+	return core.SumAlertRollups(rollups)
+}
+
+func rollupTestAlert(fingerprint, name, severity, team string, startsAt time.Time) *core.Alert {
+	labels := map[string]string{"alertname": name, "severity": severity}
+	if team != "" {
+		labels["owner"] = team
+	}
+	return &core.Alert{Fingerprint: fingerprint, AlertName: name, Status: core.StatusFiring, Labels: labels, StartsAt: startsAt}
+}
+
+func TestAlertRollupJob_Run(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 10, 30, 0, 0, time.UTC))
+	now := fake.Now()
+	alerts := &historyStorage{mockAlertStorage: newMockAlertStorage(), alerts: []*core.Alert{
+		rollupTestAlert("a", "HighCPU", "critical", "payments", now.Add(-10*time.Minute)),
+		rollupTestAlert("b", "HighCPU", "warning", "payments", now.Add(-20*time.Minute)),
+		rollupTestAlert("c", "DiskFull", "warning", "", now.Add(-90*time.Minute)),
+		rollupTestAlert("d", "DiskFull", "critical", "storage", now.Add(-26*time.Hour)),
+		rollupTestAlert("e", "Ancient", "info", "", now.Add(-30*24*time.Hour)),
+	}}
+	store := &memoryRollupStore{}
+	job := NewAlertRollupJob(alerts, store, AlertRollupConfig{
+		TeamLabel:       "owner",
+		HourlyRetention: 3 * 24 * time.Hour,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:           fake,
+	})
+	ctx := context.Background()
+
+	// The first run backfills the hourly retention
+	hourly, daily, err := job.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 73, hourly, "72 hours and the current one")
+	assert.Equal(t, 3, daily, "the oldest day is partly past the hourly retention")
+
+	hour := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
+	sums := store.sums(t, core.AlertRollupHourly, hour, hour.Add(time.Hour))
+	assert.Equal(t, map[core.AlertRollupDimension]map[string]int64{
+		core.AlertRollupTotal:     {"": 2},
+		core.AlertRollupSeverity:  {"critical": 1, "warning": 1},
+		core.AlertRollupAlertName: {"HighCPU": 2},
+		core.AlertRollupTeam:      {"payments": 2},
+	}, sums)
+
+	day := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	sums = store.sums(t, core.AlertRollupDaily, day, day.Add(24*time.Hour))
+	assert.Equal(t, int64(3), sums[core.AlertRollupTotal][""])
+	assert.Equal(t, map[string]int64{"payments": 2, "": 1}, sums[core.AlertRollupTeam])
+	sums = store.sums(t, core.AlertRollupDaily, day.Add(-24*time.Hour), day)
+	assert.Equal(t, map[string]int64{"critical": 1}, sums[core.AlertRollupSeverity])
+
+	// Later runs only recompute the lateness window, so buckets keep the
+	// alerts counted before they were updated or deleted
+	alerts.alerts = alerts.alerts[:1]
+	fake.Advance(time.Hour)
+	hourly, daily, err = job.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, hourly)
+	assert.Equal(t, 1, daily)
+	sums = store.sums(t, core.AlertRollupHourly, hour.Add(-time.Hour), hour)
+	assert.Equal(t, int64(1), sums[core.AlertRollupTotal][""], "final bucket")
+	sums = store.sums(t, core.AlertRollupHourly, hour, hour.Add(time.Hour))
+	assert.Equal(t, int64(1), sums[core.AlertRollupTotal][""], "recomputed bucket")
+
+	// Every computed bucket has a total, even without alerts
+	totals, err := store.ListAlertRollups(ctx, core.AlertRollupQuery{
+		Granularity: core.AlertRollupHourly, Dimension: core.AlertRollupTotal, From: now.Add(-30 * 24 * time.Hour), To: now.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Len(t, totals, 73, "hours past the retention are deleted")
+}
+
+func TestAlertRollupJob_BackfillsOnlyMissingHours(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 10, 30, 0, 0, time.UTC))
+	store := &memoryRollupStore{}
+	config := AlertRollupConfig{HourlyRetention: 24 * time.Hour, Clock: fake, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	alerts := &historyStorage{mockAlertStorage: newMockAlertStorage()}
+
+	hourly, _, err := NewAlertRollupJob(alerts, store, config).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 25, hourly)
+
+	// A restarted replica finds the earlier buckets computed
+	fake.Advance(3 * time.Hour)
+	hourly, _, err = NewAlertRollupJob(alerts, store, config).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, hourly, "the lateness window and the hour before it")
+}
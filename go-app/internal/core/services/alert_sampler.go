@@ -0,0 +1,111 @@
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/metrics"
+)
+
+// SamplingStage is an expensive processing step that can be sampled.
+type SamplingStage string
+
+const (
+	// SamplingStageClassification is LLM classification; sampled out alerts
+	// are processed transparently (filters, all targets).
+	SamplingStageClassification SamplingStage = "classification"
+	// SamplingStageEnrichment is the async investigation of classified alerts.
+	SamplingStageEnrichment SamplingStage = "enrichment"
+)
+
+// SamplingPolicy sets the fraction of matching alerts that go through each
+// stage. Stages without a rate are not sampled (rate 1).
+type SamplingPolicy struct {
+	Name      string
+	AlertName *regexp.Regexp // nil matches any alertname
+	Severity  *regexp.Regexp // nil matches any severity label
+	Rates     map[SamplingStage]float64
+}
+
+// ParseSamplingPolicy builds a policy from anchored alertname and severity
+// regular expressions. Empty patterns match any value.
+func ParseSamplingPolicy(name, alertName, severity string, rates map[SamplingStage]float64) (SamplingPolicy, error) {
+	policy := SamplingPolicy{Name: strings.TrimSpace(name), Rates: rates}
+	if policy.Name == "" {
+		return SamplingPolicy{}, fmt.Errorf("name is required")
+	}
+	var err error
+	if policy.AlertName, err = compileSamplingPattern(alertName); err != nil {
+		return SamplingPolicy{}, fmt.Errorf("invalid alertname pattern %q: %w", alertName, err)
+	}
+	if policy.Severity, err = compileSamplingPattern(severity); err != nil {
+		return SamplingPolicy{}, fmt.Errorf("invalid severity pattern %q: %w", severity, err)
+	}
+	for stage, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return SamplingPolicy{}, fmt.Errorf("%s rate must be between 0 and 1", stage)
+		}
+	}
+	return policy, nil
+}
+
+func compileSamplingPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+func (p SamplingPolicy) matches(alert *core.Alert) bool {
+	if p.AlertName != nil && !p.AlertName.MatchString(alert.AlertName) {
+		return false
+	}
+	return p.Severity == nil || p.Severity.MatchString(alert.Labels["severity"])
+}
+
+// AlertSampler decides which alerts get expensive processing, so low-value
+// noise does not consume LLM and investigation capacity.
+//
+// The first policy matching an alert applies. The decision is deterministic
+// per fingerprint and stage: repeated notifications of an alert are treated
+// alike, and a rate of 0.1 keeps the same tenth of distinct alerts.
+type AlertSampler struct {
+	policies []SamplingPolicy
+	metrics  *metrics.BusinessMetrics
+}
+
+// NewAlertSampler creates a sampler. metrics may be nil.
+func NewAlertSampler(policies []SamplingPolicy, metrics *metrics.BusinessMetrics) *AlertSampler {
+	return &AlertSampler{policies: policies, metrics: metrics}
+}
+
+// Sample reports whether alert goes through stage. For sampled out alerts it
+// also returns the name of the policy responsible.
+func (s *AlertSampler) Sample(alert *core.Alert, stage SamplingStage) (bool, string) {
+	for _, policy := range s.policies {
+		if !policy.matches(alert) {
+			continue
+		}
+		rate, ok := policy.Rates[stage]
+		if !ok || samplingPoint(alert.Fingerprint, stage) < rate {
+			return true, ""
+		}
+		if s.metrics != nil {
+			s.metrics.RecordAlertSampledOut(string(stage), policy.Name)
+		}
+		return false, policy.Name
+	}
+	return true, ""
+}
+
+// samplingPoint maps a fingerprint and stage to [0, 1).
+func samplingPoint(fingerprint string, stage SamplingStage) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(fingerprint))
+	h.Write([]byte{0})
+	h.Write([]byte(stage))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+type recordingInvestigations struct {
+	submitted []string
+}
+
+func (r *recordingInvestigations) Submit(alert *core.Alert, _ *core.ClassificationResult) {
+	r.submitted = append(r.submitted, alert.Fingerprint)
+}
+
+// samplingLLM is a fakeReclassifier usable as the processor's LLMClient.
+type samplingLLM struct {
+	fakeReclassifier
+}
+
+func (*samplingLLM) Health(context.Context) error { return nil }
+
+func newSamplingTestAlert(name, severity string) *core.Alert {
+	return &core.Alert{
+		Fingerprint: name + "-" + severity,
+		AlertName:   name,
+		Status:      core.StatusFiring,
+		Labels:      map[string]string{"alertname": name, "severity": severity},
+	}
+}
+
+func TestAlertSampler_Sample(t *testing.T) {
+	noise, err := ParseSamplingPolicy("noise", "Watchdog|InfoInhibitor", "", map[SamplingStage]float64{
+		SamplingStageClassification: 0,
+	})
+	require.NoError(t, err)
+	info, err := ParseSamplingPolicy("info", "", "info", map[SamplingStage]float64{
+		SamplingStageClassification: 1,
+		SamplingStageEnrichment:     0,
+	})
+	require.NoError(t, err)
+	sampler := NewAlertSampler([]SamplingPolicy{noise, info}, nil)
+
+	sampled, policy := sampler.Sample(newSamplingTestAlert("Watchdog", "info"), SamplingStageClassification)
+	assert.False(t, sampled)
+	assert.Equal(t, "noise", policy, "the first matching policy applies")
+
+	sampled, _ = sampler.Sample(newSamplingTestAlert("WatchdogX", "critical"), SamplingStageClassification)
+	assert.True(t, sampled, "patterns are anchored")
+
+	sampled, _ = sampler.Sample(newSamplingTestAlert("Watchdog", "none"), SamplingStageEnrichment)
+	assert.True(t, sampled, "stages without a rate are not sampled")
+
+	sampled, _ = sampler.Sample(newSamplingTestAlert("DiskFull", "info"), SamplingStageClassification)
+	assert.True(t, sampled)
+	sampled, policy = sampler.Sample(newSamplingTestAlert("DiskFull", "info"), SamplingStageEnrichment)
+	assert.False(t, sampled)
+	assert.Equal(t, "info", policy)
+}
+
+func TestAlertSampler_RateIsDeterministic(t *testing.T) {
+	policy, err := ParseSamplingPolicy("tenth", "", "", map[SamplingStage]float64{SamplingStageClassification: 0.1})
+	require.NoError(t, err)
+	sampler := NewAlertSampler([]SamplingPolicy{policy}, nil)
+
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		alert := newSamplingTestAlert(fmt.Sprintf("Alert%d", i), "warning")
+		sampled, _ := sampler.Sample(alert, SamplingStageClassification)
+		again, _ := sampler.Sample(alert, SamplingStageClassification)
+		require.Equal(t, sampled, again, "decision must be stable per fingerprint")
+		if sampled {
+			kept++
+		}
+	}
+	assert.InDelta(t, 1000, kept, 150)
+}
+
+func TestParseSamplingPolicy_Errors(t *testing.T) {
+	_, err := ParseSamplingPolicy("", "", "", nil)
+	assert.ErrorContains(t, err, "name is required")
+	_, err = ParseSamplingPolicy("bad", "(", "", nil)
+	assert.ErrorContains(t, err, "invalid alertname pattern")
+	_, err = ParseSamplingPolicy("bad", "", "", map[SamplingStage]float64{SamplingStageEnrichment: 2})
+	assert.ErrorContains(t, err, "between 0 and 1")
+}
+
+func TestAlertProcessor_Sampling(t *testing.T) {
+	policy, err := ParseSamplingPolicy("info", "", "info", map[SamplingStage]float64{
+		SamplingStageClassification: 0,
+	})
+	require.NoError(t, err)
+	warnings, err := ParseSamplingPolicy("warnings", "", "warning", map[SamplingStage]float64{
+		SamplingStageEnrichment: 0,
+	})
+	require.NoError(t, err)
+
+	llm := &samplingLLM{}
+	investigations := &recordingInvestigations{}
+	publisher := &recordingPublisher{}
+	collector := &traceCollector{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		LLMClient:          llm,
+		FilterEngine:       allowAllFilter{},
+		Publisher:          publisher,
+		InvestigationQueue: investigations,
+		Sampler:            NewAlertSampler([]SamplingPolicy{policy, warnings}, nil),
+		DecisionLog:        collector,
+		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, severity := range []string{"info", "warning", "critical"} {
+		require.NoError(t, processor.ProcessAlert(ctx, newSamplingTestAlert("HighCPU", severity)))
+	}
+
+	assert.Equal(t, []string{"HighCPU-warning", "HighCPU-critical"}, llm.classified())
+	assert.Equal(t, []string{"HighCPU-critical"}, investigations.submitted)
+	assert.Len(t, publisher.published, 3, "sampled out alerts are still published")
+
+	require.Len(t, collector.traces, 3)
+	var classification core.Decision
+	for _, d := range collector.traces[0].Decisions {
+		if d.Stage == core.DecisionStageClassification {
+			classification = d
+		}
+	}
+	assert.Equal(t, "sampled_out", classification.Outcome)
+	assert.Equal(t, "sampling policy info", classification.Reason)
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+// CompositeRuleLabel is set on composite alerts to the name of the rule that
+// produced them. Alerts carrying it are never counted as children.
+const CompositeRuleLabel = "composite_rule"
+
+// CompositeRule synthesizes a higher-level alert when Threshold distinct child
+// alerts matching the rule start firing within Window, e.g. five node alerts
+// in one cluster become a single "ClusterDegraded" page.
+type CompositeRule struct {
+	Name string
+	// MatchesAlert selects the child alerts.
+	MatchesAlert func(labels map[string]string) bool
+	// GroupBy partitions children by these labels, e.g. ["cluster"]; each
+	// group has its own composite. Empty groups all children together.
+	GroupBy   []string
+	Threshold int
+	Window    time.Duration
+	// Labels and Annotations are added to the composite alert. Its alertname
+	// defaults to the rule name.
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// CompositeObservation is the outcome of observing a child alert.
+type CompositeObservation struct {
+	// Suppressed reports that the child must not be published individually
+	// because a composite covers it.
+	Suppressed bool
+	// Rule and Composite identify the covering composite when Suppressed.
+	Rule      string
+	Composite string
+	// Transitions are composite alerts that started firing or resolved.
+	Transitions []*core.Alert
+}
+
+// CompositeAlertEngineConfig configures the CompositeAlertEngine.
+type CompositeAlertEngineConfig struct {
+	Clock clock.Clock
+}
+
+type compositeChild struct {
+	startsAt time.Time
+	// published is set for children paged before their composite fired.
+	published bool
+}
+
+type compositeGroup struct {
+	labels   map[string]string
+	children map[string]*compositeChild
+	active   *core.Alert
+}
+
+// CompositeAlertEngine tracks child alerts per composite rule and group.
+// While a composite is firing, its children are not paged individually; it
+// resolves once all of its children have resolved. Children paged before the
+// composite fired still have their resolution published.
+type CompositeAlertEngine struct {
+	rules       []CompositeRule
+	clock       clock.Clock
+	fingerprint FingerprintGenerator
+
+	mu     sync.Mutex
+	groups map[string]*compositeGroup
+}
+
+// NewCompositeAlertEngine creates an engine for rules.
+func NewCompositeAlertEngine(rules []CompositeRule, config CompositeAlertEngineConfig) (*CompositeAlertEngine, error) {
+	for _, rule := range rules {
+		if rule.Name == "" || rule.MatchesAlert == nil {
+			return nil, fmt.Errorf("composite rule %q: name and matcher are required", rule.Name)
+		}
+		if rule.Threshold < 2 {
+			return nil, fmt.Errorf("composite rule %q: threshold must be at least 2", rule.Name)
+		}
+		if rule.Window <= 0 {
+			return nil, fmt.Errorf("composite rule %q: window must be positive", rule.Name)
+		}
+	}
+	return &CompositeAlertEngine{
+		rules:       rules,
+		clock:       clock.OrReal(config.Clock),
+		fingerprint: NewFingerprintGenerator(nil),
+		groups:      make(map[string]*compositeGroup),
+	}, nil
+}
+
+// Observe records a firing or resolved child alert. The first matching rule
+// claims the child.
+func (e *CompositeAlertEngine) Observe(alert *core.Alert) CompositeObservation {
+	var obs CompositeObservation
+	if alert == nil || alert.Labels[CompositeRuleLabel] != "" {
+		return obs
+	}
+
+	now := e.clock.Now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.rules {
+		rule := &e.rules[i]
+		if !rule.MatchesAlert(alert.Labels) {
+			continue
+		}
+		key, groupLabels := compositeGroupKey(rule, alert.Labels)
+		group := e.groups[key]
+		if group == nil {
+			if alert.Status != core.StatusFiring {
+				return obs
+			}
+			group = &compositeGroup{labels: groupLabels, children: make(map[string]*compositeChild)}
+			e.groups[key] = group
+		}
+
+		if alert.Status == core.StatusFiring {
+			e.observeFiring(rule, group, alert, now, &obs)
+		} else {
+			e.observeResolved(rule, group, alert, now, &obs)
+		}
+
+		if group.active == nil && len(group.children) == 0 {
+			delete(e.groups, key)
+		}
+		return obs
+	}
+	return obs
+}
+
+func (e *CompositeAlertEngine) observeFiring(rule *CompositeRule, group *compositeGroup, alert *core.Alert, now time.Time, obs *CompositeObservation) {
+	cutoff := now.Add(-rule.Window)
+	if group.active == nil {
+		// Children that started firing before the window can no longer
+		// contribute to a composite.
+		for fp, c := range group.children {
+			if c.startsAt.Before(cutoff) {
+				delete(group.children, fp)
+			}
+		}
+	}
+
+	child, seen := group.children[alert.Fingerprint]
+	if !seen {
+		startsAt := alert.StartsAt
+		if startsAt.IsZero() || startsAt.After(now) {
+			startsAt = now
+		}
+		child = &compositeChild{startsAt: startsAt}
+		group.children[alert.Fingerprint] = child
+	}
+
+	if group.active == nil {
+		recent := 0
+		for _, c := range group.children {
+			if !c.startsAt.Before(cutoff) {
+				recent++
+			}
+		}
+		if recent < rule.Threshold {
+			child.published = true
+			return
+		}
+		group.active = e.newComposite(rule, group, now)
+		obs.Transitions = append(obs.Transitions, group.active)
+	}
+
+	obs.Suppressed = true
+	obs.Rule = rule.Name
+	obs.Composite = group.active.Fingerprint
+}
+
+func (e *CompositeAlertEngine) observeResolved(rule *CompositeRule, group *compositeGroup, alert *core.Alert, now time.Time, obs *CompositeObservation) {
+	child, ok := group.children[alert.Fingerprint]
+	if !ok {
+		return
+	}
+	delete(group.children, alert.Fingerprint)
+	if group.active == nil {
+		return
+	}
+
+	// Receivers only get resolutions for children they were paged about.
+	if !child.published {
+		obs.Suppressed = true
+		obs.Rule = rule.Name
+		obs.Composite = group.active.Fingerprint
+	}
+
+	if len(group.children) == 0 {
+		resolved := *group.active
+		endsAt := now
+		resolved.Status = core.StatusResolved
+		resolved.EndsAt = &endsAt
+		obs.Transitions = append(obs.Transitions, &resolved)
+		group.active = nil
+	}
+}
+
+func (e *CompositeAlertEngine) newComposite(rule *CompositeRule, group *compositeGroup, now time.Time) *core.Alert {
+	labels := make(map[string]string, len(group.labels)+len(rule.Labels)+2)
+	labels["alertname"] = rule.Name
+	for k, v := range group.labels {
+		labels[k] = v
+	}
+	for k, v := range rule.Labels {
+		labels[k] = v
+	}
+	labels[CompositeRuleLabel] = rule.Name
+
+	annotations := make(map[string]string, len(rule.Annotations)+1)
+	annotations["summary"] = fmt.Sprintf("%d alerts matching %s fired within %s", len(group.children), rule.Name, rule.Window)
+	for k, v := range rule.Annotations {
+		annotations[k] = v
+	}
+
+	alert := &core.Alert{
+		AlertName:   labels["alertname"],
+		Status:      core.StatusFiring,
+		Labels:      labels,
+		Annotations: annotations,
+		StartsAt:    now,
+	}
+	alert.Fingerprint = e.fingerprint.Generate(alert)
+	return alert
+}
+
+// compositeGroupKey identifies the group of a child within a rule.
+func compositeGroupKey(rule *CompositeRule, labels map[string]string) (string, map[string]string) {
+	groupLabels := make(map[string]string, len(rule.GroupBy))
+	parts := make([]string, 0, len(rule.GroupBy)+1)
+	parts = append(parts, rule.Name)
+	names := append([]string(nil), rule.GroupBy...)
+	sort.Strings(names)
+	for _, name := range names {
+		if v := labels[name]; v != "" {
+			groupLabels[name] = v
+		}
+		parts = append(parts, name+"="+labels[name])
+	}
+	return strings.Join(parts, "\x00"), groupLabels
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+func nodeAlert(instance, cluster string, status core.AlertStatus, startsAt time.Time) *core.Alert {
+	return &core.Alert{
+		Fingerprint: "fp-" + cluster + "-" + instance,
+		AlertName:   "NodeDown",
+		Status:      status,
+		Labels:      map[string]string{"alertname": "NodeDown", "instance": instance, "cluster": cluster},
+		StartsAt:    startsAt,
+	}
+}
+
+func clusterDegradedRule() CompositeRule {
+	return CompositeRule{
+		Name:         "ClusterDegraded",
+		MatchesAlert: func(labels map[string]string) bool { return labels["alertname"] == "NodeDown" },
+		GroupBy:      []string{"cluster"},
+		Threshold:    3,
+		Window:       10 * time.Minute,
+		Labels:       map[string]string{"severity": "critical"},
+	}
+}
+
+func TestCompositeAlertEngine_Observe(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+	engine, err := NewCompositeAlertEngine([]CompositeRule{clusterDegradedRule()}, CompositeAlertEngineConfig{Clock: fake})
+	require.NoError(t, err)
+
+	now := fake.Now()
+	// A stale child outside the window does not count.
+	obs := engine.Observe(nodeAlert("old", "eu", core.StatusFiring, now.Add(-time.Hour)))
+	assert.False(t, obs.Suppressed)
+
+	for i := 1; i <= 2; i++ {
+		obs = engine.Observe(nodeAlert(fmt.Sprint(i), "eu", core.StatusFiring, now))
+		assert.False(t, obs.Suppressed, "below threshold children are published")
+		assert.Empty(t, obs.Transitions)
+	}
+	obs = engine.Observe(nodeAlert("3", "us", core.StatusFiring, now))
+	assert.False(t, obs.Suppressed, "other cluster is a separate group")
+
+	obs = engine.Observe(nodeAlert("3", "eu", core.StatusFiring, now))
+	assert.True(t, obs.Suppressed)
+	require.Len(t, obs.Transitions, 1)
+	composite := obs.Transitions[0]
+	assert.Equal(t, core.StatusFiring, composite.Status)
+	assert.Equal(t, "ClusterDegraded", composite.AlertName)
+	assert.Equal(t, map[string]string{
+		"alertname":        "ClusterDegraded",
+		"cluster":          "eu",
+		"severity":         "critical",
+		CompositeRuleLabel: "ClusterDegraded",
+	}, composite.Labels)
+	assert.Equal(t, composite.Fingerprint, obs.Composite)
+
+	assert.False(t, engine.Observe(composite).Suppressed, "composites are never children")
+
+	obs = engine.Observe(nodeAlert("4", "eu", core.StatusFiring, now))
+	assert.True(t, obs.Suppressed)
+	assert.Empty(t, obs.Transitions, "composite fires once")
+
+	// Resolutions: published children resolve normally, covered ones do not.
+	assert.False(t, engine.Observe(nodeAlert("1", "eu", core.StatusResolved, now)).Suppressed)
+	assert.True(t, engine.Observe(nodeAlert("4", "eu", core.StatusResolved, now)).Suppressed)
+	engine.Observe(nodeAlert("old", "eu", core.StatusResolved, now))
+	engine.Observe(nodeAlert("2", "eu", core.StatusResolved, now))
+
+	fake.Advance(time.Minute)
+	obs = engine.Observe(nodeAlert("3", "eu", core.StatusResolved, now))
+	assert.True(t, obs.Suppressed)
+	require.Len(t, obs.Transitions, 1, "composite resolves with its last child")
+	assert.Equal(t, core.StatusResolved, obs.Transitions[0].Status)
+	assert.Equal(t, composite.Fingerprint, obs.Transitions[0].Fingerprint)
+	require.NotNil(t, obs.Transitions[0].EndsAt)
+	assert.Equal(t, fake.Now(), *obs.Transitions[0].EndsAt)
+}
+
+func TestAlertProcessor_CompositeAlerts(t *testing.T) {
+	rule := clusterDegradedRule()
+	rule.Threshold = 2
+	engine, err := NewCompositeAlertEngine([]CompositeRule{rule}, CompositeAlertEngineConfig{})
+	require.NoError(t, err)
+
+	var hooked []*core.Alert
+	publisher := &recordingPublisher{}
+	collector := &traceCollector{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine:    blockByNameFilter(""),
+		Publisher:       publisher,
+		CompositeAlerts: engine,
+		CompositeHook:   func(a *core.Alert) { hooked = append(hooked, a) },
+		DecisionLog:     collector,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, processor.ProcessAlert(ctx, nodeAlert("1", "eu", core.StatusFiring, time.Now())))
+	require.NoError(t, processor.ProcessAlert(ctx, nodeAlert("2", "eu", core.StatusFiring, time.Now())))
+
+	require.Len(t, publisher.published, 2, "first child and the composite")
+	assert.Equal(t, "NodeDown", publisher.published[0].AlertName)
+	assert.Equal(t, "ClusterDegraded", publisher.published[1].AlertName)
+	require.Len(t, hooked, 1)
+
+	last := collector.traces[len(collector.traces)-1]
+	assert.Equal(t, core.DecisionResultComposited, last.Result)
+	assert.Equal(t, "ClusterDegraded", last.Decisions[len(last.Decisions)-1].Details["rule"])
+}
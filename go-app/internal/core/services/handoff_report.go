@@ -0,0 +1,476 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/silencing"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+const (
+	defaultHandoffCheckInterval   = time.Minute
+	defaultHandoffPeriod          = 7 * 24 * time.Hour
+	defaultHandoffSilenceHorizon  = 72 * time.Hour
+	defaultHandoffTopRules        = 5
+	handoffUnresolvedSampleSize   = 20
+	handoffExpiringSilencesSample = 20
+)
+
+// HandoffReportAlertName is the alert name of published handoff reports.
+const HandoffReportAlertName = "OnCallHandoffReport"
+
+// DefaultHandoffTemplate renders a HandoffReport as plain text (Slack mrkdwn
+// compatible).
+const DefaultHandoffTemplate = `*On-call handoff{{if .Team}}: {{.Team}}{{end}}* ({{date .PeriodStart}} to {{date .PeriodEnd}})
+
+*Alerts fired:* {{.TotalAlerts}}{{range .Severities}}
+• {{.Name}}: {{.Count}}{{end}}
+
+*Noisiest rules:*{{range .NoisyRules}}
+• {{.Name}}: {{.Count}}{{else}} none{{end}}
+
+*Unresolved alerts:* {{.UnresolvedTotal}}{{range .Unresolved}}
+• {{.AlertName}} ({{.Severity}}) firing since {{date .Since}}{{end}}
+
+*Silences expiring soon:*{{range .ExpiringSilences}}
+• {{.ID}} ends {{date .EndsAt}} by {{.CreatedBy}}: {{.Comment}}{{else}} none{{end}}
+
+*Failed deliveries (dead-lettered):* {{.FailedDeliveriesTotal}}{{range .FailedDeliveries}}
+• {{.Name}}: {{.Count}}{{end}}
+`
+
+// HandoffCount is a named count in a handoff report.
+type HandoffCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// HandoffAlert is an unresolved alert in a handoff report.
+type HandoffAlert struct {
+	Fingerprint string    `json:"fingerprint"`
+	AlertName   string    `json:"alertName"`
+	Severity    string    `json:"severity"`
+	Since       time.Time `json:"since"`
+}
+
+// HandoffSilence is a silence expiring soon in a handoff report.
+type HandoffSilence struct {
+	ID        string    `json:"id"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+// HandoffReport summarizes an on-call period for one team.
+type HandoffReport struct {
+	Team        string    `json:"team"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+
+	// TotalAlerts counts alerts that started during the period.
+	TotalAlerts int            `json:"totalAlerts"`
+	Severities  []HandoffCount `json:"severities"`
+	NoisyRules  []HandoffCount `json:"noisyRules"`
+
+	// Unresolved lists the oldest firing alerts; UnresolvedTotal counts all.
+	UnresolvedTotal int            `json:"unresolvedTotal"`
+	Unresolved      []HandoffAlert `json:"unresolved"`
+
+	ExpiringSilences []HandoffSilence `json:"expiringSilences"`
+
+	// FailedDeliveries counts notifications that exhausted their retries
+	// during the period, by target.
+	FailedDeliveriesTotal int            `json:"failedDeliveriesTotal"`
+	FailedDeliveries      []HandoffCount `json:"failedDeliveries"`
+}
+
+// HandoffTeam selects the alerts and silences reported to one team.
+type HandoffTeam struct {
+	Name string
+	// Target is the publishing target the report is sent to.
+	Target string
+	// Template overrides the scheduler template when set.
+	Template *template.Template
+	// MatchesAlert reports whether an alert belongs to the team (nil: all).
+	MatchesAlert func(labels map[string]string) bool
+	// MatchesSilence reports whether a silence belongs to the team (nil: all).
+	MatchesSilence func(matchers []core.APISilenceMatcher) bool
+}
+
+// FailedDeliverySource reports notifications that failed permanently.
+type FailedDeliverySource interface {
+	// FailedDeliveriesSince counts failed deliveries of alerts accepted by
+	// match since the given time, by target name.
+	FailedDeliveriesSince(since time.Time, match func(labels map[string]string) bool) map[string]int
+}
+
+// HandoffReportBuilder builds handoff reports from the live alert, silence
+// and publishing state.
+type HandoffReportBuilder struct {
+	alerts         FiringAlertLister
+	silences       SilenceLister
+	deliveries     FailedDeliverySource
+	topRules       int
+	silenceHorizon time.Duration
+}
+
+// NewHandoffReportBuilder creates a builder. deliveries may be nil.
+// topRules and silenceHorizon fall back to defaults when not positive.
+func NewHandoffReportBuilder(alerts FiringAlertLister, silences SilenceLister, deliveries FailedDeliverySource, topRules int, silenceHorizon time.Duration) *HandoffReportBuilder {
+	if topRules <= 0 {
+		topRules = defaultHandoffTopRules
+	}
+	if silenceHorizon <= 0 {
+		silenceHorizon = defaultHandoffSilenceHorizon
+	}
+	return &HandoffReportBuilder{
+		alerts:         alerts,
+		silences:       silences,
+		deliveries:     deliveries,
+		topRules:       topRules,
+		silenceHorizon: silenceHorizon,
+	}
+}
+
+// Build summarizes the period ending at now for team.
+func (b *HandoffReportBuilder) Build(team HandoffTeam, now time.Time, period time.Duration) *HandoffReport {
+	now = now.UTC()
+	start := now.Add(-period)
+	report := &HandoffReport{
+		Team:             team.Name,
+		PeriodStart:      start,
+		PeriodEnd:        now,
+		Severities:       []HandoffCount{},
+		NoisyRules:       []HandoffCount{},
+		Unresolved:       []HandoffAlert{},
+		ExpiringSilences: []HandoffSilence{},
+		FailedDeliveries: []HandoffCount{},
+	}
+
+	bySeverity := make(map[string]int)
+	byRule := make(map[string]int)
+	for _, alert := range b.alerts.List("", true) {
+		if team.MatchesAlert != nil && !team.MatchesAlert(alert.Labels) {
+			continue
+		}
+		startsAt, err := time.Parse(time.RFC3339, alert.StartsAt)
+		if err != nil {
+			continue
+		}
+
+		if !startsAt.Before(start) && !startsAt.After(now) {
+			report.TotalAlerts++
+			bySeverity[labelOr(alert.Labels, "severity", "unknown")]++
+			byRule[labelOr(alert.Labels, "alertname", "unknown")]++
+		}
+
+		if alert.Status == "firing" {
+			report.UnresolvedTotal++
+			report.Unresolved = append(report.Unresolved, HandoffAlert{
+				Fingerprint: alert.Fingerprint,
+				AlertName:   labelOr(alert.Labels, "alertname", "unknown"),
+				Severity:    labelOr(alert.Labels, "severity", "unknown"),
+				Since:       startsAt,
+			})
+		}
+	}
+	report.Severities = sortedCounts(bySeverity, 0)
+	report.NoisyRules = sortedCounts(byRule, b.topRules)
+
+	sort.Slice(report.Unresolved, func(i, j int) bool {
+		if !report.Unresolved[i].Since.Equal(report.Unresolved[j].Since) {
+			return report.Unresolved[i].Since.Before(report.Unresolved[j].Since)
+		}
+		return report.Unresolved[i].Fingerprint < report.Unresolved[j].Fingerprint
+	})
+	if len(report.Unresolved) > handoffUnresolvedSampleSize {
+		report.Unresolved = report.Unresolved[:handoffUnresolvedSampleSize]
+	}
+
+	horizon := now.Add(b.silenceHorizon)
+	for _, silence := range b.silences.List(now) {
+		if silence.Status.State != "active" {
+			continue
+		}
+		if team.MatchesSilence != nil && !team.MatchesSilence(silence.Matchers) {
+			continue
+		}
+		endsAt, err := time.Parse(time.RFC3339, silence.EndsAt)
+		if err != nil || endsAt.After(horizon) {
+			continue
+		}
+		report.ExpiringSilences = append(report.ExpiringSilences, HandoffSilence{
+			ID:        silence.ID,
+			EndsAt:    endsAt,
+			CreatedBy: silence.CreatedBy,
+			Comment:   silence.Comment,
+		})
+	}
+	sort.Slice(report.ExpiringSilences, func(i, j int) bool {
+		return report.ExpiringSilences[i].EndsAt.Before(report.ExpiringSilences[j].EndsAt)
+	})
+	if len(report.ExpiringSilences) > handoffExpiringSilencesSample {
+		report.ExpiringSilences = report.ExpiringSilences[:handoffExpiringSilencesSample]
+	}
+
+	if b.deliveries != nil {
+		failed := b.deliveries.FailedDeliveriesSince(start, team.MatchesAlert)
+		for _, n := range failed {
+			report.FailedDeliveriesTotal += n
+		}
+		report.FailedDeliveries = sortedCounts(failed, 0)
+	}
+
+	return report
+}
+
+// ParseHandoffTemplate parses a handoff report template. The "date" function
+// formats a time as "2006-01-02 15:04 MST".
+func ParseHandoffTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"date": func(t time.Time) string { return t.Format("2006-01-02 15:04 MST") },
+	}).Parse(text)
+}
+
+// RenderHandoffReport renders report with tmpl.
+func RenderHandoffReport(tmpl *template.Template, report *HandoffReport) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, report); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// HandoffReportSchedulerConfig configures the HandoffReportScheduler.
+type HandoffReportSchedulerConfig struct {
+	// Schedule decides when reports are sent.
+	Schedule *silencing.CronSchedule
+
+	// Period covered by each report (default: 7d).
+	Period time.Duration
+
+	// Template renders reports of teams without their own template
+	// (default: DefaultHandoffTemplate).
+	Template *template.Template
+
+	// Interval between schedule checks (default: 1m).
+	Interval time.Duration
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// HandoffReportScheduler publishes each team's handoff report to its target
+// on a cron schedule. Runs missed while the process was down are not caught
+// up.
+type HandoffReportScheduler struct {
+	builder   *HandoffReportBuilder
+	publisher TargetPublisher
+	teams     []HandoffTeam
+	template  *template.Template
+	config    HandoffReportSchedulerConfig
+	logger    *slog.Logger
+	clock     clock.Clock
+
+	mu      sync.Mutex
+	nextRun time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHandoffReportScheduler creates a scheduler (not started).
+func NewHandoffReportScheduler(builder *HandoffReportBuilder, publisher TargetPublisher, teams []HandoffTeam, config HandoffReportSchedulerConfig) (*HandoffReportScheduler, error) {
+	if config.Schedule == nil {
+		return nil, fmt.Errorf("handoff report schedule is required")
+	}
+	if config.Period <= 0 {
+		config.Period = defaultHandoffPeriod
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultHandoffCheckInterval
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	if config.Template == nil {
+		tmpl, err := ParseHandoffTemplate("default", DefaultHandoffTemplate)
+		if err != nil {
+			return nil, err
+		}
+		config.Template = tmpl
+	}
+
+	return &HandoffReportScheduler{
+		builder:   builder,
+		publisher: publisher,
+		teams:     teams,
+		template:  config.Template,
+		config:    config,
+		logger:    config.Logger.With("component", "handoff_report_scheduler"),
+		clock:     config.Clock,
+		nextRun:   config.Schedule.Next(config.Clock.Now()),
+	}, nil
+}
+
+// Start checks the schedule every Interval until ctx is cancelled or Stop is
+// called.
+func (s *HandoffReportScheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := s.clock.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				s.Tick(ctx)
+			}
+		}
+	}()
+
+	s.logger.Info("Handoff report scheduler started",
+		"teams", len(s.teams),
+		"next_run", s.nextRun,
+	)
+}
+
+// Stop stops the scheduler and waits for the running send to finish.
+func (s *HandoffReportScheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// Tick sends all reports if the scheduled time has passed. Returns how many
+// were published.
+func (s *HandoffReportScheduler) Tick(ctx context.Context) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if s.nextRun.IsZero() || now.Before(s.nextRun) {
+		return 0
+	}
+	s.nextRun = s.config.Schedule.Next(now)
+	return s.sendAll(ctx, now)
+}
+
+// Report builds the current report for the named team without publishing it.
+func (s *HandoffReportScheduler) Report(team string) (*HandoffReport, bool) {
+	for _, t := range s.teams {
+		if t.Name == team {
+			return s.builder.Build(t, s.clock.Now(), s.config.Period), true
+		}
+	}
+	return nil, false
+}
+
+// SendNow publishes all reports immediately, independent of the schedule.
+func (s *HandoffReportScheduler) SendNow(ctx context.Context) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sendAll(ctx, s.clock.Now())
+}
+
+func (s *HandoffReportScheduler) sendAll(ctx context.Context, now time.Time) int {
+	sent := 0
+	for _, team := range s.teams {
+		report := s.builder.Build(team, now, s.config.Period)
+
+		tmpl := s.template
+		if team.Template != nil {
+			tmpl = team.Template
+		}
+		text, err := RenderHandoffReport(tmpl, report)
+		if err != nil {
+			s.logger.Warn("Failed to render handoff report", "team", team.Name, "error", err)
+			continue
+		}
+
+		if err := s.publisher.PublishToTarget(ctx, team.Target, handoffReportAlert(report, text)); err != nil {
+			s.logger.Warn("Failed to publish handoff report",
+				"team", team.Name,
+				"target", team.Target,
+				"error", err)
+			continue
+		}
+		sent++
+		s.logger.Info("Published handoff report",
+			"team", team.Name,
+			"target", team.Target,
+			"alerts", report.TotalAlerts,
+			"unresolved", report.UnresolvedTotal)
+	}
+	return sent
+}
+
+// handoffReportAlert wraps a rendered report in the synthetic alert that is
+// published; the report text is the description annotation.
+func handoffReportAlert(report *HandoffReport, text string) *core.Alert {
+	team := report.Team
+	if team == "" {
+		team = "all"
+	}
+	return &core.Alert{
+		Fingerprint: fmt.Sprintf("%s:%s:%d", HandoffReportAlertName, team, report.PeriodEnd.Unix()),
+		AlertName:   HandoffReportAlertName,
+		Status:      core.StatusFiring,
+		Labels: map[string]string{
+			"alertname": HandoffReportAlertName,
+			"team":      team,
+			"severity":  "info",
+		},
+		Annotations: map[string]string{
+			"summary":     fmt.Sprintf("On-call handoff report for %s", team),
+			"description": text,
+		},
+		StartsAt: report.PeriodEnd,
+	}
+}
+
+// sortedCounts orders counts by count (desc) then name, keeping at most
+// limit entries (0: all).
+func sortedCounts(counts map[string]int, limit int) []HandoffCount {
+	out := make([]HandoffCount, 0, len(counts))
+	for name, n := range counts {
+		out = append(out, HandoffCount{Name: name, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func labelOr(labels map[string]string, name, fallback string) string {
+	if v := labels[name]; v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/silencing"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+type fakeFailedDeliveries map[string]int
+
+func (f fakeFailedDeliveries) FailedDeliveriesSince(time.Time, func(map[string]string) bool) map[string]int {
+	return f
+}
+
+func seedHandoffStores(t *testing.T, now time.Time) (*memory.AlertStore, *memory.SilenceStore) {
+	t.Helper()
+	alerts := memory.NewAlertStore()
+	resolvedAt := now.Add(-47 * time.Hour).Format(time.RFC3339)
+	require.NoError(t, alerts.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "DiskFull", "severity": "critical", "team": "payments", "instance": "a"}, StartsAt: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+		{Labels: map[string]string{"alertname": "DiskFull", "severity": "critical", "team": "payments", "instance": "b"}, StartsAt: now.Add(-48 * time.Hour).Format(time.RFC3339), EndsAt: resolvedAt},
+		{Labels: map[string]string{"alertname": "HighLatency", "severity": "warning", "team": "payments"}, StartsAt: now.Add(-24 * time.Hour).Format(time.RFC3339), EndsAt: resolvedAt},
+		{Labels: map[string]string{"alertname": "OldAlert", "severity": "warning", "team": "payments"}, StartsAt: now.Add(-30 * 24 * time.Hour).Format(time.RFC3339)},
+		{Labels: map[string]string{"alertname": "Other", "severity": "critical", "team": "search"}, StartsAt: now.Add(-time.Hour).Format(time.RFC3339)},
+	}, now))
+
+	silences := memory.NewSilenceStore()
+	for _, endsIn := range []time.Duration{24 * time.Hour, 10 * 24 * time.Hour} {
+		_, err := silences.CreateOrUpdate(&core.SilenceInput{
+			Matchers:  []core.SilenceMatcherInput{{Name: "team", Value: "payments"}},
+			StartsAt:  now.Format(time.RFC3339),
+			EndsAt:    now.Add(endsIn).Format(time.RFC3339),
+			CreatedBy: "ops",
+			Comment:   "maintenance",
+		}, now)
+		require.NoError(t, err)
+	}
+	return alerts, silences
+}
+
+func paymentsTeam() HandoffTeam {
+	return HandoffTeam{
+		Name:         "payments",
+		Target:       "slack-payments",
+		MatchesAlert: func(labels map[string]string) bool { return labels["team"] == "payments" },
+	}
+}
+
+func TestHandoffReportBuilder_Build(t *testing.T) {
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	alerts, silences := seedHandoffStores(t, now)
+	builder := NewHandoffReportBuilder(alerts, silences, fakeFailedDeliveries{"slack-payments": 2}, 1, 72*time.Hour)
+
+	report := builder.Build(paymentsTeam(), now, 7*24*time.Hour)
+
+	assert.Equal(t, 3, report.TotalAlerts)
+	assert.Equal(t, []HandoffCount{{Name: "critical", Count: 2}, {Name: "warning", Count: 1}}, report.Severities)
+	assert.Equal(t, []HandoffCount{{Name: "DiskFull", Count: 2}}, report.NoisyRules)
+
+	assert.Equal(t, 2, report.UnresolvedTotal)
+	require.Len(t, report.Unresolved, 2)
+	assert.Equal(t, "OldAlert", report.Unresolved[0].AlertName, "oldest unresolved first")
+
+	require.Len(t, report.ExpiringSilences, 1)
+	assert.Equal(t, now.Add(24*time.Hour), report.ExpiringSilences[0].EndsAt)
+
+	assert.Equal(t, 2, report.FailedDeliveriesTotal)
+
+	tmpl, err := ParseHandoffTemplate("default", DefaultHandoffTemplate)
+	require.NoError(t, err)
+	text, err := RenderHandoffReport(tmpl, report)
+	require.NoError(t, err)
+	assert.Contains(t, text, "On-call handoff: payments")
+	assert.Contains(t, text, "• DiskFull: 2")
+	assert.Contains(t, text, "*Failed deliveries (dead-lettered):* 2")
+}
+
+func TestHandoffReportScheduler_SendsOnSchedule(t *testing.T) {
+	// Sunday 2026-03-15 12:00 UTC; reports go out Mondays at 09:00.
+	fake := clock.NewFake(time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC))
+	alerts, silences := seedHandoffStores(t, fake.Now())
+	publisher := &fakeTargetPublisher{}
+
+	schedule, err := silencing.ParseCronSchedule("0 9 * * 1", time.UTC)
+	require.NoError(t, err)
+
+	team := paymentsTeam()
+	team.Template, err = ParseHandoffTemplate("payments", "{{.Team}}: {{.TotalAlerts}} alerts")
+	require.NoError(t, err)
+
+	scheduler, err := NewHandoffReportScheduler(
+		NewHandoffReportBuilder(alerts, silences, nil, 0, 0),
+		publisher,
+		[]HandoffTeam{team},
+		HandoffReportSchedulerConfig{
+			Schedule: schedule,
+			Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Clock:    fake,
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, scheduler.Tick(context.Background()), "not due yet")
+
+	fake.Advance(21 * time.Hour)
+	assert.Equal(t, 1, scheduler.Tick(context.Background()))
+	assert.Equal(t, 0, scheduler.Tick(context.Background()), "sent once per run")
+
+	require.Len(t, publisher.sent, 1)
+	assert.Equal(t, "slack-payments", publisher.sent[0].target)
+	assert.Equal(t, HandoffReportAlertName, publisher.sent[0].alert.AlertName)
+	assert.Equal(t, "payments: 3 alerts", publisher.sent[0].alert.Annotations["description"])
+}
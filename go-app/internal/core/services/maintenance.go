@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+const (
+	defaultMaintenanceDrainTimeout = 30 * time.Second
+	maxMaintenanceDrainTimeout     = 5 * time.Minute
+	maintenanceDrainPollInterval   = 50 * time.Millisecond
+)
+
+// PublishingHold pauses and resumes delivery of queued notifications.
+// Implemented by publishing.PublishingQueue.
+type PublishingHold interface {
+	Pause() bool
+	Resume() bool
+	GetQueueSize() int
+}
+
+// MaintenanceMode pauses publishing for planned provider outages or AMP
+// maintenance. Notifications keep being queued and are delivered when
+// maintenance ends; optionally alert ingestion is paused as well.
+type MaintenanceMode struct {
+	hold   PublishingHold // nil when nothing is published
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	status core.MaintenanceStatus
+}
+
+// NewMaintenanceMode creates maintenance mode for hold, which may be nil
+// when publishing is disabled or runs in metrics-only mode.
+func NewMaintenanceMode(hold PublishingHold, logger *slog.Logger) *MaintenanceMode {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MaintenanceMode{hold: hold, logger: logger}
+}
+
+// Status returns the current maintenance state.
+func (m *MaintenanceMode) Status() core.MaintenanceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.statusLocked()
+}
+
+// IngestionPaused reports whether incoming alerts are rejected.
+func (m *MaintenanceMode) IngestionPaused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status.Active && m.status.PauseIngestion
+}
+
+// Apply enters or leaves maintenance mode. Entering it again updates the
+// reason and ingestion setting. Leaving it resumes publishing and waits up
+// to the drain timeout for the held notifications to be picked up.
+func (m *MaintenanceMode) Apply(ctx context.Context, in core.MaintenanceInput, now time.Time) (core.MaintenanceStatus, error) {
+	drainTimeout := defaultMaintenanceDrainTimeout
+	if raw := strings.TrimSpace(in.DrainTimeout); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return core.MaintenanceStatus{}, fmt.Errorf("invalid drainTimeout: %w", err)
+		}
+		if d < 0 || d > maxMaintenanceDrainTimeout {
+			return core.MaintenanceStatus{}, fmt.Errorf("drainTimeout must be between 0 and %s", maxMaintenanceDrainTimeout)
+		}
+		drainTimeout = d
+	}
+
+	if in.Enabled {
+		return m.enter(in, now), nil
+	}
+	return m.exit(ctx, drainTimeout), nil
+}
+
+func (m *MaintenanceMode) enter(in core.MaintenanceInput, now time.Time) core.MaintenanceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.status.Active {
+		since := now.UTC()
+		m.status.Active = true
+		m.status.Since = &since
+		if m.hold != nil {
+			m.hold.Pause()
+		}
+	}
+	m.status.Reason = strings.TrimSpace(in.Reason)
+	m.status.PauseIngestion = in.PauseIngestion
+
+	m.logger.Warn("Maintenance mode enabled",
+		"reason", m.status.Reason,
+		"pause_ingestion", m.status.PauseIngestion,
+		"publishing_paused", m.hold != nil)
+	return m.statusLocked()
+}
+
+func (m *MaintenanceMode) exit(ctx context.Context, drainTimeout time.Duration) core.MaintenanceStatus {
+	m.mu.Lock()
+	wasActive := m.status.Active
+	m.status = core.MaintenanceStatus{}
+	if wasActive && m.hold != nil {
+		m.hold.Resume()
+	}
+	m.mu.Unlock()
+
+	if wasActive {
+		m.logger.Info("Maintenance mode disabled", "held_jobs", m.heldJobs())
+	}
+
+	drained := m.waitDrained(ctx, drainTimeout)
+	status := m.Status()
+	status.Drained = &drained
+	return status
+}
+
+// waitDrained waits until the publishing queue is empty, the timeout
+// elapses or ctx is done. Reports whether the queue is empty.
+func (m *MaintenanceMode) waitDrained(ctx context.Context, timeout time.Duration) bool {
+	if m.heldJobs() == 0 {
+		return true
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(maintenanceDrainPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return m.heldJobs() == 0
+		case <-deadline.C:
+			return m.heldJobs() == 0
+		case <-ticker.C:
+			if m.heldJobs() == 0 {
+				return true
+			}
+		}
+	}
+}
+
+func (m *MaintenanceMode) heldJobs() int {
+	if m.hold == nil {
+		return 0
+	}
+	return m.hold.GetQueueSize()
+}
+
+func (m *MaintenanceMode) statusLocked() core.MaintenanceStatus {
+	status := m.status
+	status.PublishingPaused = status.Active && m.hold != nil
+	status.HeldJobs = m.heldJobs()
+	return status
+}
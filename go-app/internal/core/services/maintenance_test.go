@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// drainingHold releases one held job per poll once resumed.
+type drainingHold struct {
+	mu     sync.Mutex
+	paused bool
+	queued int
+}
+
+func (h *drainingHold) Pause() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	was := h.paused
+	h.paused = true
+	return !was
+}
+
+func (h *drainingHold) Resume() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	was := h.paused
+	h.paused = false
+	return was
+}
+
+func (h *drainingHold) GetQueueSize() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.paused && h.queued > 0 {
+		h.queued--
+	}
+	return h.queued
+}
+
+func TestMaintenanceMode_LeaveDrainsHeldJobs(t *testing.T) {
+	hold := &drainingHold{}
+	mode := NewMaintenanceMode(hold, nil)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	status, err := mode.Apply(ctx, core.MaintenanceInput{Enabled: true, Reason: " provider outage "}, now)
+	require.NoError(t, err)
+	assert.True(t, status.PublishingPaused)
+	assert.Equal(t, "provider outage", status.Reason)
+	assert.Equal(t, now, *status.Since)
+	assert.False(t, mode.IngestionPaused())
+
+	// Entering again keeps the start time and updates the settings.
+	status, err = mode.Apply(ctx, core.MaintenanceInput{Enabled: true, PauseIngestion: true}, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, now, *status.Since)
+	assert.True(t, mode.IngestionPaused())
+
+	hold.queued = 5
+	status, err = mode.Apply(ctx, core.MaintenanceInput{DrainTimeout: "5s"}, now)
+	require.NoError(t, err)
+	assert.False(t, status.Active)
+	assert.False(t, mode.IngestionPaused())
+	require.NotNil(t, status.Drained)
+	assert.True(t, *status.Drained)
+	assert.Zero(t, status.HeldJobs)
+}
+
+func TestMaintenanceMode_DrainTimeout(t *testing.T) {
+	hold := &drainingHold{}
+	mode := NewMaintenanceMode(hold, nil)
+	ctx := context.Background()
+
+	_, err := mode.Apply(ctx, core.MaintenanceInput{Enabled: true}, time.Now())
+	require.NoError(t, err)
+	hold.queued = 1000
+
+	status, err := mode.Apply(ctx, core.MaintenanceInput{DrainTimeout: "0s"}, time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, status.Drained)
+	assert.False(t, *status.Drained)
+	assert.Positive(t, status.HeldJobs)
+}
+
+func TestMaintenanceMode_WithoutPublishing(t *testing.T) {
+	mode := NewMaintenanceMode(nil, nil)
+
+	status, err := mode.Apply(context.Background(), core.MaintenanceInput{Enabled: true, PauseIngestion: true}, time.Now())
+	require.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.False(t, status.PublishingPaused)
+	assert.True(t, mode.IngestionPaused())
+
+	_, err = mode.Apply(context.Background(), core.MaintenanceInput{DrainTimeout: "-1s"}, time.Now())
+	assert.Error(t, err)
+	assert.True(t, mode.Status().Active, "invalid input leaves the state unchanged")
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+const (
+	defaultReclassificationRate = 1.0
+	maxReclassificationRate     = 100.0
+	reclassificationBatchSize   = 100
+	// reclassificationMaxFailures consecutive failures stop the job, e.g.
+	// when the LLM is unavailable; it can be resumed later.
+	reclassificationMaxFailures = 10
+)
+
+// ErrReclassificationRunning is returned when a job is already running.
+var ErrReclassificationRunning = errors.New("a re-classification job is already running")
+
+// errFallbackClassification marks results of the rule-based fallback, which
+// must not replace stored model output.
+var errFallbackClassification = errors.New("classifier returned a fallback result")
+
+// Reclassifier classifies alerts. Implemented by ClassificationService.
+type Reclassifier interface {
+	ClassifyAlert(ctx context.Context, alert *core.Alert) (*core.ClassificationResult, error)
+	InvalidateCache(ctx context.Context, fingerprint string) error
+}
+
+// reclassificationCursor is the position of a job: alerts are listed newest
+// first, up to and including To, skipping the Skip alerts already processed
+// at exactly To.
+type reclassificationCursor struct {
+	to   time.Time
+	skip int
+}
+
+// ReclassificationJob re-classifies historical alerts within a time range,
+// e.g. after upgrading the classification model, so that analytics and
+// accuracy comparisons use the new model's output.
+//
+// One job runs at a time in the background, rate-limited to spare the LLM.
+// Cancelled or failed jobs can be resumed from where they stopped.
+type ReclassificationJob struct {
+	classifier Reclassifier
+	alerts     core.AlertStorage
+	results    core.ClassificationStorage // nil when results cannot be stored
+	logger     *slog.Logger
+	batchSize  int
+
+	mu      sync.Mutex
+	status  core.ReclassificationStatus
+	cursor  reclassificationCursor
+	cancel  context.CancelFunc
+	done    chan struct{}
+	stopped bool
+}
+
+// NewReclassificationJob creates the job. Results are stored when alerts
+// also implements core.ClassificationStorage.
+func NewReclassificationJob(classifier Reclassifier, alerts core.AlertStorage, logger *slog.Logger) *ReclassificationJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	results, _ := alerts.(core.ClassificationStorage)
+	return &ReclassificationJob{
+		classifier: classifier,
+		alerts:     alerts,
+		results:    results,
+		logger:     logger.With("component", "reclassification"),
+		batchSize:  reclassificationBatchSize,
+		status: core.ReclassificationStatus{
+			State:     core.ReclassificationIdle,
+			Persisted: results != nil,
+		},
+	}
+}
+
+// Status returns the progress of the current or last job.
+func (j *ReclassificationJob) Status() core.ReclassificationStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.statusLocked()
+}
+
+// Start starts a job, or resumes the last cancelled or failed one.
+func (j *ReclassificationJob) Start(in core.ReclassificationInput, now time.Time) (core.ReclassificationStatus, error) {
+	ratePerSecond := in.RatePerSecond
+	if ratePerSecond == 0 {
+		ratePerSecond = defaultReclassificationRate
+	}
+	if ratePerSecond < 0 || ratePerSecond > maxReclassificationRate {
+		return core.ReclassificationStatus{}, fmt.Errorf("ratePerSecond must be between 0 and %g", maxReclassificationRate)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.stopped {
+		return core.ReclassificationStatus{}, fmt.Errorf("re-classification is shutting down")
+	}
+	if j.status.State == core.ReclassificationRunning {
+		return core.ReclassificationStatus{}, ErrReclassificationRunning
+	}
+
+	if in.Resume {
+		if j.status.State != core.ReclassificationCancelled && j.status.State != core.ReclassificationFailed {
+			return core.ReclassificationStatus{}, fmt.Errorf("no cancelled or failed job to resume (state %s)", j.status.State)
+		}
+		j.status.LastError = ""
+		j.status.FinishedAt = nil
+	} else {
+		to := in.To
+		if to.IsZero() {
+			to = now
+		}
+		if in.From.IsZero() {
+			return core.ReclassificationStatus{}, fmt.Errorf("from is required")
+		}
+		if !in.From.Before(to) {
+			return core.ReclassificationStatus{}, fmt.Errorf("from must be before to")
+		}
+		from, to, startedAt := in.From.UTC(), to.UTC(), now.UTC()
+		j.status = core.ReclassificationStatus{
+			From:      &from,
+			To:        &to,
+			Total:     -1,
+			Persisted: j.results != nil,
+			StartedAt: &startedAt,
+		}
+		j.cursor = reclassificationCursor{to: to}
+	}
+	j.status.State = core.ReclassificationRunning
+	j.status.RatePerSecond = ratePerSecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.done = make(chan struct{})
+	go j.run(ctx, rate.NewLimiter(rate.Limit(ratePerSecond), 1), j.done)
+
+	j.logger.Info("Re-classification started",
+		"from", j.status.From,
+		"to", j.status.To,
+		"rate_per_second", ratePerSecond,
+		"resumed", in.Resume,
+		"persisted", j.results != nil)
+	return j.statusLocked(), nil
+}
+
+// Cancel stops the running job; it can be resumed later.
+func (j *ReclassificationJob) Cancel() (core.ReclassificationStatus, error) {
+	j.mu.Lock()
+	if j.status.State != core.ReclassificationRunning {
+		state := j.status.State
+		j.mu.Unlock()
+		return core.ReclassificationStatus{}, fmt.Errorf("no running job to cancel (state %s)", state)
+	}
+	cancel, done := j.cancel, j.done
+	j.mu.Unlock()
+
+	cancel()
+	<-done
+	return j.Status(), nil
+}
+
+// Stop cancels the running job and rejects new ones (for shutdown).
+func (j *ReclassificationJob) Stop() {
+	j.mu.Lock()
+	j.stopped = true
+	cancel, done := j.cancel, j.done
+	j.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (j *ReclassificationJob) run(ctx context.Context, limiter *rate.Limiter, done chan struct{}) {
+	defer close(done)
+
+	failures := 0
+	for {
+		j.mu.Lock()
+		cursor := j.cursor
+		from := *j.status.From
+		countTotal := j.status.Total < 0
+		j.mu.Unlock()
+
+		page, err := j.alerts.ListAlerts(ctx, &core.AlertFilters{
+			TimeRange: &core.TimeRange{From: &from, To: &cursor.to},
+			Limit:     j.batchSize,
+			Offset:    cursor.skip,
+		})
+		if err != nil {
+			j.finish(ctx, fmt.Errorf("list alerts: %w", err))
+			return
+		}
+		if countTotal {
+			j.mu.Lock()
+			j.status.Total = page.Total
+			j.mu.Unlock()
+		}
+		if len(page.Alerts) == 0 {
+			j.finish(ctx, nil)
+			return
+		}
+
+		for _, alert := range page.Alerts {
+			if err := limiter.Wait(ctx); err != nil {
+				j.finish(ctx, nil)
+				return
+			}
+			err := j.reclassify(ctx, alert)
+			if ctx.Err() != nil {
+				// Cancelled mid-alert: leave the cursor so it is retried
+				j.finish(ctx, nil)
+				return
+			}
+			j.advance(alert, err)
+			if err == nil {
+				failures = 0
+				continue
+			}
+			failures++
+			j.logger.Warn("Failed to re-classify alert",
+				"fingerprint", alert.Fingerprint,
+				"error", err)
+			if failures >= reclassificationMaxFailures {
+				j.finish(ctx, fmt.Errorf("%d consecutive failures, last: %w", failures, err))
+				return
+			}
+		}
+	}
+}
+
+// reclassify classifies alert bypassing the cache and stores the result.
+func (j *ReclassificationJob) reclassify(ctx context.Context, alert *core.Alert) error {
+	_ = j.classifier.InvalidateCache(ctx, alert.Fingerprint)
+	result, err := j.classifier.ClassifyAlert(ctx, alert)
+	if err != nil {
+		return err
+	}
+	if fallback, _ := result.Metadata["fallback"].(bool); fallback {
+		// Do not let the fallback result linger in the cache either
+		_ = j.classifier.InvalidateCache(ctx, alert.Fingerprint)
+		return errFallbackClassification
+	}
+	if j.results == nil {
+		return nil
+	}
+
+	previous, err := j.results.GetClassification(ctx, alert.Fingerprint)
+	if err != nil {
+		return fmt.Errorf("get stored classification: %w", err)
+	}
+	if err := j.results.SaveClassification(ctx, alert.Fingerprint, result); err != nil {
+		return err
+	}
+	if previous != nil && previous.Severity != result.Severity {
+		j.mu.Lock()
+		j.status.Changed++
+		j.mu.Unlock()
+	}
+	return nil
+}
+
+// advance counts alert as processed and moves the cursor past it.
+func (j *ReclassificationJob) advance(alert *core.Alert, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Processed++
+	if err != nil {
+		j.status.Failed++
+		j.status.LastError = err.Error()
+	} else {
+		j.status.Reclassified++
+	}
+
+	if alert.StartsAt.Equal(j.cursor.to) {
+		j.cursor.skip++
+	} else {
+		j.cursor = reclassificationCursor{to: alert.StartsAt, skip: 1}
+	}
+	cursor := j.cursor.to.UTC()
+	j.status.Cursor = &cursor
+}
+
+// finish ends the run: failed when err is set, cancelled when ctx is done,
+// completed otherwise.
+func (j *ReclassificationJob) finish(ctx context.Context, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	finishedAt := time.Now().UTC()
+	j.status.FinishedAt = &finishedAt
+	switch {
+	case err != nil && ctx.Err() == nil:
+		j.status.State = core.ReclassificationFailed
+		j.status.LastError = err.Error()
+		j.logger.Error("Re-classification failed", "processed", j.status.Processed, "error", err)
+	case ctx.Err() != nil:
+		j.status.State = core.ReclassificationCancelled
+		j.logger.Info("Re-classification cancelled", "processed", j.status.Processed)
+	default:
+		j.status.State = core.ReclassificationCompleted
+		j.logger.Info("Re-classification completed",
+			"processed", j.status.Processed,
+			"reclassified", j.status.Reclassified,
+			"changed", j.status.Changed,
+			"failed", j.status.Failed)
+	}
+}
+
+func (j *ReclassificationJob) statusLocked() core.ReclassificationStatus {
+	status := j.status
+	if status.Total < 0 {
+		status.Total = 0
+	}
+	return status
+}
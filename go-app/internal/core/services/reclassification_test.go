@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// historyStorage lists alerts like the storage backends (newest first,
+// startsAt within the time range) and stores classification results.
+type historyStorage struct {
+	*mockAlertStorage
+	alerts []*core.Alert
+
+	mu              sync.Mutex
+	classifications map[string]*core.ClassificationResult
+}
+
+func (s *historyStorage) ListAlerts(ctx context.Context, filters *core.AlertFilters) (*core.AlertList, error) {
+	var matched []*core.Alert
+	for _, alert := range s.alerts {
+		if alert.StartsAt.Before(*filters.TimeRange.From) || alert.StartsAt.After(*filters.TimeRange.To) {
+			continue
+		}
+		matched = append(matched, alert)
+	}
+	sort.SliceStable(matched, func(i, k int) bool { return matched[i].StartsAt.After(matched[k].StartsAt) })
+
+	list := &core.AlertList{Total: len(matched)}
+	if filters.Offset < len(matched) {
+		matched = matched[filters.Offset:]
+		if len(matched) > filters.Limit {
+			matched = matched[:filters.Limit]
+		}
+		list.Alerts = matched
+	}
+	return list, nil
+}
+
+func (s *historyStorage) SaveClassification(ctx context.Context, fingerprint string, result *core.ClassificationResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.classifications[fingerprint] = result
+	return nil
+}
+
+func (s *historyStorage) GetClassification(ctx context.Context, fingerprint string) (*core.ClassificationResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.classifications[fingerprint], nil
+}
+
+// fakeReclassifier classifies everything as critical; classify overrides it.
+type fakeReclassifier struct {
+	mu       sync.Mutex
+	calls    []string
+	classify func(alert *core.Alert) (*core.ClassificationResult, error)
+}
+
+func (c *fakeReclassifier) ClassifyAlert(ctx context.Context, alert *core.Alert) (*core.ClassificationResult, error) {
+	c.mu.Lock()
+	c.calls = append(c.calls, alert.Fingerprint)
+	c.mu.Unlock()
+	if c.classify != nil {
+		return c.classify(alert)
+	}
+	return &core.ClassificationResult{Severity: core.SeverityCritical, Confidence: 0.9}, nil
+}
+
+func (c *fakeReclassifier) InvalidateCache(ctx context.Context, fingerprint string) error { return nil }
+
+func (c *fakeReclassifier) classified() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.calls...)
+}
+
+var reclassificationBase = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newHistoryStorage stores n alerts one minute apart (alert-0 is the
+// oldest).
+func newHistoryStorage(n int) *historyStorage {
+	storage := &historyStorage{
+		mockAlertStorage: newMockAlertStorage(),
+		classifications:  map[string]*core.ClassificationResult{},
+	}
+	for i := 0; i < n; i++ {
+		storage.alerts = append(storage.alerts, &core.Alert{
+			Fingerprint: fmt.Sprintf("alert-%d", i),
+			StartsAt:    reclassificationBase.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return storage
+}
+
+func newTestReclassificationJob(classifier Reclassifier, storage core.AlertStorage) *ReclassificationJob {
+	return NewReclassificationJob(classifier, storage, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func waitReclassification(t *testing.T, job *ReclassificationJob) core.ReclassificationStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status := job.Status()
+		if status.State != core.ReclassificationRunning {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job still running: %+v", status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReclassificationJob_ReclassifiesTimeRange(t *testing.T) {
+	storage := newHistoryStorage(5)
+	// alert-2 and alert-1 straddle the page boundary with equal startsAt:
+	// neither may be skipped or repeated
+	storage.alerts[1].StartsAt = storage.alerts[2].StartsAt
+	storage.classifications["alert-2"] = &core.ClassificationResult{Severity: core.SeverityInfo}
+	storage.classifications["alert-3"] = &core.ClassificationResult{Severity: core.SeverityCritical}
+	classifier := &fakeReclassifier{}
+	job := newTestReclassificationJob(classifier, storage)
+	job.batchSize = 2
+
+	// alert-0 and alert-4 are outside the range
+	_, err := job.Start(core.ReclassificationInput{
+		From:          reclassificationBase.Add(time.Minute),
+		To:            reclassificationBase.Add(3 * time.Minute),
+		RatePerSecond: 100,
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	status := waitReclassification(t, job)
+
+	if status.State != core.ReclassificationCompleted {
+		t.Fatalf("state = %s (%s), want completed", status.State, status.LastError)
+	}
+	if status.Total != 3 || status.Processed != 3 || status.Reclassified != 3 || status.Failed != 0 {
+		t.Errorf("status = %+v, want 3 of 3 reclassified", status)
+	}
+	if status.Changed != 1 {
+		t.Errorf("changed = %d, want 1 (alert-2 info -> critical)", status.Changed)
+	}
+	if !status.Persisted || status.Cursor == nil || !status.Cursor.Equal(reclassificationBase.Add(2*time.Minute)) {
+		t.Errorf("persisted/cursor = %v/%v", status.Persisted, status.Cursor)
+	}
+
+	calls := classifier.classified()
+	sort.Strings(calls)
+	if fmt.Sprint(calls) != "[alert-1 alert-2 alert-3]" {
+		t.Errorf("classified %v, want alert-1..alert-3 once each", calls)
+	}
+	if storage.classifications["alert-1"] == nil || storage.classifications["alert-2"].Severity != core.SeverityCritical {
+		t.Errorf("results not stored: %v", storage.classifications)
+	}
+}
+
+func TestReclassificationJob_StopsOnRepeatedFailuresAndResumes(t *testing.T) {
+	storage := newHistoryStorage(reclassificationMaxFailures + 5)
+	var mu sync.Mutex
+	llmDown := true
+	classifier := &fakeReclassifier{classify: func(alert *core.Alert) (*core.ClassificationResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if llmDown {
+			// The fallback result must not count as the new model's output
+			return &core.ClassificationResult{Severity: core.SeverityWarning, Metadata: map[string]any{"fallback": true}}, nil
+		}
+		return &core.ClassificationResult{Severity: core.SeverityCritical}, nil
+	}}
+	job := newTestReclassificationJob(classifier, storage)
+
+	if _, err := job.Start(core.ReclassificationInput{From: reclassificationBase, RatePerSecond: 100}, reclassificationBase.Add(time.Hour)); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	status := waitReclassification(t, job)
+	if status.State != core.ReclassificationFailed || status.Failed != reclassificationMaxFailures || status.LastError == "" {
+		t.Fatalf("status = %+v, want failed after %d failures", status, reclassificationMaxFailures)
+	}
+	if len(storage.classifications) != 0 {
+		t.Errorf("fallback results stored: %v", storage.classifications)
+	}
+
+	mu.Lock()
+	llmDown = false
+	mu.Unlock()
+	if _, err := job.Start(core.ReclassificationInput{Resume: true, RatePerSecond: 100}, time.Now()); err != nil {
+		t.Fatalf("resume error = %v", err)
+	}
+	status = waitReclassification(t, job)
+	if status.State != core.ReclassificationCompleted || status.Reclassified != 5 || status.Processed != status.Total {
+		t.Errorf("status after resume = %+v, want the remaining 5 reclassified", status)
+	}
+}
+
+func TestReclassificationJob_CancelAndValidation(t *testing.T) {
+	storage := newHistoryStorage(3)
+	block := make(chan struct{})
+	classifier := &fakeReclassifier{classify: func(alert *core.Alert) (*core.ClassificationResult, error) {
+		<-block
+		return nil, errors.New("cancelled")
+	}}
+	job := newTestReclassificationJob(classifier, storage)
+
+	if _, err := job.Start(core.ReclassificationInput{}, time.Now()); err == nil {
+		t.Error("Start() without from accepted")
+	}
+	if _, err := job.Start(core.ReclassificationInput{From: reclassificationBase, RatePerSecond: 1000}, time.Now()); err == nil {
+		t.Error("Start() with excessive rate accepted")
+	}
+	if _, err := job.Start(core.ReclassificationInput{Resume: true}, time.Now()); err == nil {
+		t.Error("Start() resumed without a previous job")
+	}
+
+	if _, err := job.Start(core.ReclassificationInput{From: reclassificationBase}, time.Now()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := job.Start(core.ReclassificationInput{From: reclassificationBase}, time.Now()); !errors.Is(err, ErrReclassificationRunning) {
+		t.Errorf("second Start() error = %v, want ErrReclassificationRunning", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(block)
+	}()
+	status, err := job.Cancel()
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if status.State != core.ReclassificationCancelled || status.Processed != 0 {
+		t.Errorf("status = %+v, want cancelled with the interrupted alert not counted", status)
+	}
+	if _, err := job.Cancel(); err == nil {
+		t.Error("Cancel() without running job succeeded")
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+const (
+	defaultRecurringSilenceInterval  = time.Minute
+	defaultRecurringSilenceLookahead = 24 * time.Hour
+)
+
+// RecurringSilenceSource provides recurring silence windows due for
+// materialization. Implemented by memory.RecurringSilenceStore.
+type RecurringSilenceSource interface {
+	DueOccurrences(now time.Time, lookahead time.Duration) []core.RecurringSilenceOccurrence
+	MarkMaterialized(occ core.RecurringSilenceOccurrence, silenceID string, now time.Time)
+}
+
+// SilenceCreator creates concrete silences. Implemented by memory.SilenceStore.
+type SilenceCreator interface {
+	CreateOrUpdate(in *core.SilenceInput, now time.Time) (string, error)
+}
+
+// RecurringSilenceSchedulerConfig configures the RecurringSilenceScheduler.
+type RecurringSilenceSchedulerConfig struct {
+	// Interval between materialization passes (default: 1m).
+	Interval time.Duration
+
+	// Lookahead is how far ahead windows are created as pending silences
+	// (default: 24h).
+	Lookahead time.Duration
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// RecurringSilenceScheduler materializes recurring silence windows as
+// concrete silences ahead of time, so upcoming maintenance windows show up as
+// pending silences and take effect even if a pass is delayed.
+type RecurringSilenceScheduler struct {
+	source   RecurringSilenceSource
+	silences SilenceCreator
+	config   RecurringSilenceSchedulerConfig
+	logger   *slog.Logger
+	clock    clock.Clock
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRecurringSilenceScheduler creates a scheduler (not started).
+func NewRecurringSilenceScheduler(source RecurringSilenceSource, silences SilenceCreator, config RecurringSilenceSchedulerConfig) *RecurringSilenceScheduler {
+	if config.Interval <= 0 {
+		config.Interval = defaultRecurringSilenceInterval
+	}
+	if config.Lookahead <= 0 {
+		config.Lookahead = defaultRecurringSilenceLookahead
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &RecurringSilenceScheduler{
+		source:   source,
+		silences: silences,
+		config:   config,
+		logger:   config.Logger.With("component", "recurring_silence_scheduler"),
+		clock:    config.Clock,
+	}
+}
+
+// Start runs a materialization pass immediately and then every Interval until
+// ctx is cancelled or Stop is called.
+func (s *RecurringSilenceScheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := s.clock.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		s.Materialize()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				s.Materialize()
+			}
+		}
+	}()
+
+	s.logger.Info("Recurring silence scheduler started",
+		"interval", s.config.Interval,
+		"lookahead", s.config.Lookahead,
+	)
+}
+
+// Stop stops the scheduler and waits for the running pass to finish.
+func (s *RecurringSilenceScheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// Materialize creates silences for all due windows and returns how many were
+// created. Failed windows are logged and retried on the next pass.
+func (s *RecurringSilenceScheduler) Materialize() int {
+	now := s.clock.Now().UTC()
+
+	created := 0
+	for _, occ := range s.source.DueOccurrences(now, s.config.Lookahead) {
+		id, err := s.silences.CreateOrUpdate(&core.SilenceInput{
+			Matchers:  occ.Matchers,
+			StartsAt:  occ.StartsAt.Format(time.RFC3339),
+			EndsAt:    occ.EndsAt.Format(time.RFC3339),
+			CreatedBy: occ.CreatedBy,
+			Comment:   fmt.Sprintf("%s (recurring silence %s)", occ.Comment, occ.RecurrenceID),
+			// The policy applies when the recurring silence is defined,
+			// not to each materialized window.
+			BypassPolicy: true,
+		}, now)
+		if err != nil {
+			s.logger.Warn("Failed to materialize recurring silence",
+				"recurrence_id", occ.RecurrenceID,
+				"starts_at", occ.StartsAt,
+				"error", err)
+			continue
+		}
+
+		s.source.MarkMaterialized(occ, id, now)
+		created++
+		s.logger.Debug("Materialized recurring silence",
+			"recurrence_id", occ.RecurrenceID,
+			"silence_id", id,
+			"starts_at", occ.StartsAt,
+			"ends_at", occ.EndsAt)
+	}
+	return created
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+func TestRecurringSilenceScheduler_MaterializesAhead(t *testing.T) {
+	// Wednesday 2026-03-11 12:00 UTC
+	fake := clock.NewFake(time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC))
+	recurring := memory.NewRecurringSilenceStore()
+	silences := memory.NewSilenceStore()
+
+	id, err := recurring.Create(&core.RecurringSilenceInput{
+		Schedule:  "0 22 * * 0",
+		Duration:  "4h",
+		Matchers:  []core.SilenceMatcherInput{{Name: "env", Value: "staging"}},
+		CreatedBy: "ops",
+		Comment:   "weekly maintenance",
+	}, fake.Now())
+	require.NoError(t, err)
+
+	scheduler := NewRecurringSilenceScheduler(recurring, silences, RecurringSilenceSchedulerConfig{
+		Lookahead: 7 * 24 * time.Hour,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:     fake,
+	})
+
+	assert.Equal(t, 1, scheduler.Materialize())
+	assert.Equal(t, 0, scheduler.Materialize(), "a window is materialized only once")
+
+	list := silences.List(fake.Now())
+	require.Len(t, list, 1)
+	assert.Equal(t, "pending", list[0].Status.State)
+	assert.Equal(t, "2026-03-15T22:00:00Z", list[0].StartsAt)
+	assert.Equal(t, "2026-03-16T02:00:00Z", list[0].EndsAt)
+	assert.Equal(t, "ops", list[0].CreatedBy)
+	assert.Contains(t, list[0].Comment, id)
+
+	// A week later the next window comes into the lookahead.
+	fake.Advance(7 * 24 * time.Hour)
+	assert.Equal(t, 1, scheduler.Materialize())
+
+	rs, ok := recurring.Get(id, fake.Now())
+	require.True(t, ok)
+	assert.Len(t, rs.SilenceIDs, 1, "ended windows are no longer listed")
+	assert.Equal(t, "2026-03-29T22:00:00Z", rs.NextStartsAt)
+}
+
+func TestRecurringSilenceScheduler_WindowInProgress(t *testing.T) {
+	// Sunday 23:00, one hour into the window.
+	fake := clock.NewFake(time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC))
+	recurring := memory.NewRecurringSilenceStore()
+	silences := memory.NewSilenceStore()
+
+	_, err := recurring.Create(&core.RecurringSilenceInput{
+		Schedule:  "0 22 * * 0",
+		Duration:  "4h",
+		Matchers:  []core.SilenceMatcherInput{{Name: "env", Value: "staging"}},
+		CreatedBy: "ops",
+		Comment:   "weekly maintenance",
+	}, fake.Now())
+	require.NoError(t, err)
+
+	scheduler := NewRecurringSilenceScheduler(recurring, silences, RecurringSilenceSchedulerConfig{
+		Lookahead: time.Hour,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:     fake,
+	})
+	assert.Equal(t, 1, scheduler.Materialize())
+
+	list := silences.List(fake.Now())
+	require.Len(t, list, 1)
+	assert.Equal(t, "active", list[0].Status.State)
+	assert.Equal(t, "2026-03-16T02:00:00Z", list[0].EndsAt)
+}
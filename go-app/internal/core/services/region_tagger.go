@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultRegionLabel = "region"
+	defaultZoneLabel   = "zone"
+)
+
+// RegionRule maps alert sources to a region and zone. A rule matches a source
+// whose IP address is within Prefix, or whose host name matches Host.
+type RegionRule struct {
+	Prefix netip.Prefix
+	Host   *regexp.Regexp
+	Region string
+	Zone   string
+}
+
+// ParseRegionRule builds a rule from a CIDR and/or an anchored host name
+// regular expression. At least one of them and a region are required.
+func ParseRegionRule(cidr, hostPattern, region, zone string) (RegionRule, error) {
+	rule := RegionRule{Region: strings.TrimSpace(region), Zone: strings.TrimSpace(zone)}
+	if rule.Region == "" {
+		return RegionRule{}, fmt.Errorf("region is required")
+	}
+	if cidr = strings.TrimSpace(cidr); cidr != "" {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return RegionRule{}, fmt.Errorf("invalid cidr %q: %w", cidr, err)
+		}
+		rule.Prefix = prefix.Masked()
+	}
+	if hostPattern != "" {
+		re, err := regexp.Compile("^(?:" + hostPattern + ")$")
+		if err != nil {
+			return RegionRule{}, fmt.Errorf("invalid host pattern %q: %w", hostPattern, err)
+		}
+		rule.Host = re
+	}
+	if !rule.Prefix.IsValid() && rule.Host == nil {
+		return RegionRule{}, fmt.Errorf("cidr or host pattern is required")
+	}
+	return rule, nil
+}
+
+func (r RegionRule) matches(host string, addr netip.Addr) bool {
+	if addr.IsValid() && r.Prefix.IsValid() && r.Prefix.Contains(addr.Unmap()) {
+		return true
+	}
+	return r.Host != nil && r.Host.MatchString(host)
+}
+
+// RegionTaggerConfig configures the RegionTagger.
+type RegionTaggerConfig struct {
+	// RegionLabel and ZoneLabel are the labels set on alerts (default:
+	// "region" and "zone").
+	RegionLabel string
+	ZoneLabel   string
+
+	// SourceLabels are inspected in order for the alert's source address,
+	// e.g. "instance" holding "10.1.2.3:9100" (default: ["instance"]).
+	SourceLabels []string
+
+	// Rules are evaluated in order; the first match wins.
+	Rules []RegionRule
+}
+
+// RegionTagger tags alerts with the region and zone of their source, so
+// routes and silences can be scoped by region and regional outages show up as
+// one group. Alerts that already carry a region label are left untouched.
+type RegionTagger struct {
+	config RegionTaggerConfig
+}
+
+// NewRegionTagger creates a tagger.
+func NewRegionTagger(config RegionTaggerConfig) *RegionTagger {
+	if config.RegionLabel == "" {
+		config.RegionLabel = defaultRegionLabel
+	}
+	if config.ZoneLabel == "" {
+		config.ZoneLabel = defaultZoneLabel
+	}
+	if len(config.SourceLabels) == 0 {
+		config.SourceLabels = []string{"instance"}
+	}
+	return &RegionTagger{config: config}
+}
+
+// RegionLabel returns the label the region is written to.
+func (t *RegionTagger) RegionLabel() string {
+	return t.config.RegionLabel
+}
+
+// Tag sets the region (and zone, when known) labels from the first rule
+// matching one of the source labels. Reports whether labels were changed.
+func (t *RegionTagger) Tag(labels map[string]string) bool {
+	if labels == nil || labels[t.config.RegionLabel] != "" {
+		return false
+	}
+
+	for _, name := range t.config.SourceLabels {
+		host := sourceHost(labels[name])
+		if host == "" {
+			continue
+		}
+		addr, _ := netip.ParseAddr(host)
+		for _, rule := range t.config.Rules {
+			if !rule.matches(host, addr) {
+				continue
+			}
+			labels[t.config.RegionLabel] = rule.Region
+			if rule.Zone != "" && labels[t.config.ZoneLabel] == "" {
+				labels[t.config.ZoneLabel] = rule.Zone
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// sourceHost extracts the host from a label value such as "host:9100",
+// "[::1]:9100" or "https://host/metrics".
+func sourceHost(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if strings.Contains(value, "://") {
+		if u, err := url.Parse(value); err == nil {
+			return strings.ToLower(u.Hostname())
+		}
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(strings.Trim(value, "[]"))
+}
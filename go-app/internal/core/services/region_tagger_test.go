@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionTagger_Tag(t *testing.T) {
+	cidrRule, err := ParseRegionRule("10.1.0.0/16", "", "eu-west-1", "eu-west-1a")
+	require.NoError(t, err)
+	hostRule, err := ParseRegionRule("", `.*\.use1\.example\.com`, "us-east-1", "")
+	require.NoError(t, err)
+
+	tagger := NewRegionTagger(RegionTaggerConfig{
+		SourceLabels: []string{"instance", "host"},
+		Rules:        []RegionRule{cidrRule, hostRule},
+	})
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		wantRegion string
+		wantZone   string
+		changed    bool
+	}{
+		{name: "ip with port", labels: map[string]string{"instance": "10.1.2.3:9100"}, wantRegion: "eu-west-1", wantZone: "eu-west-1a", changed: true},
+		{name: "host name from second source label", labels: map[string]string{"instance": "", "host": "DB1.use1.example.com"}, wantRegion: "us-east-1", changed: true},
+		{name: "url", labels: map[string]string{"instance": "https://api.use1.example.com/metrics"}, wantRegion: "us-east-1", changed: true},
+		{name: "no match", labels: map[string]string{"instance": "10.2.0.1:9100"}},
+		{name: "existing region kept", labels: map[string]string{"instance": "10.1.2.3:9100", "region": "manual"}, wantRegion: "manual"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.changed, tagger.Tag(tt.labels))
+			assert.Equal(t, tt.wantRegion, tt.labels["region"])
+			assert.Equal(t, tt.wantZone, tt.labels["zone"])
+		})
+	}
+}
+
+func TestParseRegionRule_Invalid(t *testing.T) {
+	_, err := ParseRegionRule("", "", "eu-west-1", "")
+	assert.Error(t, err, "cidr or host pattern required")
+	_, err = ParseRegionRule("10.1.0.0/99", "", "eu-west-1", "")
+	assert.Error(t, err)
+	_, err = ParseRegionRule("10.1.0.0/16", "", "", "")
+	assert.Error(t, err, "region required")
+}
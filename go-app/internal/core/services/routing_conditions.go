@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ipiton/AMP/internal/infrastructure/promql"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+// PromQLQuerier runs PromQL instant queries. Implemented by *promql.Client.
+type PromQLQuerier interface {
+	Query(ctx context.Context, query string) (*promql.Result, error)
+}
+
+// RoutingCondition gates publishing of matching alerts on a live PromQL
+// condition, e.g. only page for a service when `sum(up{service="x"}) == 0`.
+type RoutingCondition struct {
+	Name string
+	// MatchesAlert selects the alerts the condition applies to.
+	MatchesAlert func(labels map[string]string) bool
+	// Query is executed with the alert as data, so {{ .Labels.service }}
+	// expands to the alert's service label. The condition is met when the
+	// query returns at least one series (or a non-zero scalar).
+	Query *template.Template
+}
+
+// ParseRoutingConditionQuery parses a condition query template.
+func ParseRoutingConditionQuery(name, text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	return template.New(name).Option("missingkey=zero").Parse(text)
+}
+
+// RoutingConditionEvaluatorConfig configures the RoutingConditionEvaluator.
+type RoutingConditionEvaluatorConfig struct {
+	// CacheTTL is how long a query result is reused (default: 30s). Alerts
+	// rendering the same query share one result.
+	CacheTTL time.Duration
+	// FailOpen publishes alerts when a query fails or times out; otherwise
+	// they are held back like alerts whose condition is not met.
+	FailOpen bool
+	Clock    clock.Clock
+}
+
+// RoutingConditionDecision is the outcome of evaluating the conditions of an
+// alert.
+type RoutingConditionDecision struct {
+	Met bool
+	// Rule and Query identify the condition that decided the outcome. Empty
+	// when no condition applies to the alert.
+	Rule  string
+	Query string
+	// Reason is set when the outcome was not a plain query result, e.g. a
+	// failed query.
+	Reason string
+}
+
+type conditionCacheEntry struct {
+	met     bool
+	err     error
+	expires time.Time
+}
+
+// RoutingConditionEvaluator evaluates routing conditions against Prometheus,
+// caching results per rendered query.
+type RoutingConditionEvaluator struct {
+	querier    PromQLQuerier
+	conditions []RoutingCondition
+	config     RoutingConditionEvaluatorConfig
+
+	mu    sync.Mutex
+	cache map[string]conditionCacheEntry
+}
+
+// NewRoutingConditionEvaluator creates an evaluator.
+func NewRoutingConditionEvaluator(querier PromQLQuerier, conditions []RoutingCondition, config RoutingConditionEvaluatorConfig) (*RoutingConditionEvaluator, error) {
+	if querier == nil {
+		return nil, fmt.Errorf("promql querier is required")
+	}
+	for _, c := range conditions {
+		if c.MatchesAlert == nil || c.Query == nil {
+			return nil, fmt.Errorf("routing condition %q: matcher and query are required", c.Name)
+		}
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 30 * time.Second
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &RoutingConditionEvaluator{
+		querier:    querier,
+		conditions: conditions,
+		config:     config,
+		cache:      make(map[string]conditionCacheEntry),
+	}, nil
+}
+
+// Evaluate checks every condition matching labels; all of them must be met.
+func (e *RoutingConditionEvaluator) Evaluate(ctx context.Context, labels map[string]string) RoutingConditionDecision {
+	decision := RoutingConditionDecision{Met: true}
+
+	for _, c := range e.conditions {
+		if !c.MatchesAlert(labels) {
+			continue
+		}
+		decision.Rule = c.Name
+
+		var buf bytes.Buffer
+		if err := c.Query.Execute(&buf, struct{ Labels map[string]string }{labels}); err != nil {
+			decision.Query = ""
+			if !e.onError(&decision, fmt.Errorf("render query: %w", err)) {
+				return decision
+			}
+			continue
+		}
+		decision.Query = buf.String()
+
+		met, err := e.query(ctx, decision.Query)
+		if err != nil {
+			if !e.onError(&decision, err) {
+				return decision
+			}
+			continue
+		}
+		if !met {
+			decision.Met = false
+			return decision
+		}
+	}
+	return decision
+}
+
+// onError applies the failure policy and reports whether evaluation continues.
+func (e *RoutingConditionEvaluator) onError(decision *RoutingConditionDecision, err error) bool {
+	if e.config.FailOpen {
+		decision.Reason = "fail open: " + err.Error()
+		return true
+	}
+	decision.Met = false
+	decision.Reason = err.Error()
+	return false
+}
+
+// query returns the cached result of query or runs it. Failures are cached
+// too, so an unreachable Prometheus costs one timeout per TTL, not per alert.
+func (e *RoutingConditionEvaluator) query(ctx context.Context, query string) (bool, error) {
+	now := e.config.Clock.Now()
+
+	e.mu.Lock()
+	entry, ok := e.cache[query]
+	e.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.met, entry.err
+	}
+
+	result, err := e.querier.Query(ctx, query)
+	entry = conditionCacheEntry{met: err == nil && result.Truthy(), err: err, expires: now.Add(e.config.CacheTTL)}
+
+	e.mu.Lock()
+	for key, cached := range e.cache {
+		if !now.Before(cached.expires) {
+			delete(e.cache, key)
+		}
+	}
+	e.cache[query] = entry
+	e.mu.Unlock()
+
+	return entry.met, entry.err
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/promql"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+type fakePromQL struct {
+	results map[string]*promql.Result
+	err     error
+	queries []string
+}
+
+func (f *fakePromQL) Query(_ context.Context, query string) (*promql.Result, error) {
+	f.queries = append(f.queries, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if result, ok := f.results[query]; ok {
+		return result, nil
+	}
+	return &promql.Result{Type: promql.ResultTypeVector}, nil
+}
+
+func serviceDownCondition(t *testing.T) RoutingCondition {
+	t.Helper()
+	tmpl, err := ParseRoutingConditionQuery("service-down", `sum(up{service="{{ .Labels.service }}"}) == 0`)
+	require.NoError(t, err)
+	return RoutingCondition{
+		Name:         "service-down",
+		MatchesAlert: func(labels map[string]string) bool { return labels["severity"] == "critical" },
+		Query:        tmpl,
+	}
+}
+
+func TestRoutingConditionEvaluator_Evaluate(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+	querier := &fakePromQL{results: map[string]*promql.Result{
+		`sum(up{service="api"}) == 0`: {Type: promql.ResultTypeVector, Samples: []promql.Sample{{Value: 0}}},
+	}}
+	evaluator, err := NewRoutingConditionEvaluator(querier, []RoutingCondition{serviceDownCondition(t)},
+		RoutingConditionEvaluatorConfig{CacheTTL: time.Minute, Clock: fake})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	decision := evaluator.Evaluate(ctx, map[string]string{"severity": "critical", "service": "api"})
+	assert.True(t, decision.Met)
+	assert.Equal(t, "service-down", decision.Rule)
+
+	decision = evaluator.Evaluate(ctx, map[string]string{"severity": "critical", "service": "web"})
+	assert.False(t, decision.Met, "web is still up")
+	assert.Equal(t, `sum(up{service="web"}) == 0`, decision.Query)
+
+	decision = evaluator.Evaluate(ctx, map[string]string{"severity": "warning", "service": "web"})
+	assert.True(t, decision.Met, "condition does not apply")
+	assert.Empty(t, decision.Rule)
+
+	evaluator.Evaluate(ctx, map[string]string{"severity": "critical", "service": "api"})
+	assert.Len(t, querier.queries, 2, "cached result reused")
+
+	fake.Advance(2 * time.Minute)
+	evaluator.Evaluate(ctx, map[string]string{"severity": "critical", "service": "api"})
+	assert.Len(t, querier.queries, 3, "cache expired")
+}
+
+func TestRoutingConditionEvaluator_QueryFailure(t *testing.T) {
+	querier := &fakePromQL{err: errors.New("context deadline exceeded")}
+	labels := map[string]string{"severity": "critical", "service": "api"}
+
+	open, err := NewRoutingConditionEvaluator(querier, []RoutingCondition{serviceDownCondition(t)},
+		RoutingConditionEvaluatorConfig{FailOpen: true})
+	require.NoError(t, err)
+	decision := open.Evaluate(context.Background(), labels)
+	assert.True(t, decision.Met)
+	assert.Contains(t, decision.Reason, "fail open")
+
+	closed, err := NewRoutingConditionEvaluator(querier, []RoutingCondition{serviceDownCondition(t)},
+		RoutingConditionEvaluatorConfig{})
+	require.NoError(t, err)
+	decision = closed.Evaluate(context.Background(), labels)
+	assert.False(t, decision.Met)
+	assert.Contains(t, decision.Reason, "deadline exceeded")
+}
+
+func TestAlertProcessor_RoutingConditionHoldsBackAlert(t *testing.T) {
+	evaluator, err := NewRoutingConditionEvaluator(&fakePromQL{}, []RoutingCondition{serviceDownCondition(t)},
+		RoutingConditionEvaluatorConfig{})
+	require.NoError(t, err)
+	collector := &traceCollector{}
+	publisher := &recordingPublisher{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine:      blockByNameFilter(""),
+		Publisher:         publisher,
+		RoutingConditions: evaluator,
+		DecisionLog:       collector,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	labels := map[string]string{"alertname": "ApiDown", "severity": "critical", "service": "api"}
+	firing := &core.Alert{Fingerprint: "f1", AlertName: "ApiDown", Status: core.StatusFiring, Labels: labels}
+	require.NoError(t, processor.ProcessAlert(context.Background(), firing))
+	assert.Empty(t, publisher.published)
+	require.Len(t, collector.traces, 1)
+	assert.Equal(t, core.DecisionResultConditionNotMet, collector.traces[0].Result)
+	last := collector.traces[0].Decisions[len(collector.traces[0].Decisions)-1]
+	assert.Equal(t, core.DecisionStageCondition, last.Stage)
+	assert.Equal(t, "service-down", last.Details["rule"])
+
+	resolved := &core.Alert{Fingerprint: "f1", AlertName: "ApiDown", Status: core.StatusResolved, Labels: labels}
+	require.NoError(t, processor.ProcessAlert(context.Background(), resolved))
+	assert.Len(t, publisher.published, 1, "resolved alerts bypass conditions")
+}
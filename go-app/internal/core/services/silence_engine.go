@@ -0,0 +1,161 @@
+package services
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+// ActiveSilenceSource provides the silences that are active at a given time.
+//
+// Implemented by memory.SilenceStore.
+type ActiveSilenceSource interface {
+	ActiveSilences(now time.Time) []core.APISilence
+}
+
+// SilenceMatchRecorder counts the alerts suppressed by each silence.
+//
+// Implemented by memory.SilenceStore.
+type SilenceMatchRecorder interface {
+	RecordMatches(ids []string, now time.Time)
+}
+
+// SilenceEngine evaluates active silences against alerts in the processing pipeline.
+//
+// Matchers are compiled once through a shared domain.MatcherCache, so the
+// per-alert cost is a map lookup plus the match itself. Regex matchers are
+// anchored, as in Alertmanager.
+//
+// Thread-safe: the cache is concurrency-safe and the source is read-only here.
+type SilenceEngine struct {
+	source ActiveSilenceSource
+	cache  *domain.MatcherCache
+	logger *slog.Logger
+}
+
+// NewSilenceEngine creates a silence engine backed by source.
+func NewSilenceEngine(source ActiveSilenceSource, logger *slog.Logger) *SilenceEngine {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SilenceEngine{
+		source: source,
+		cache:  domain.NewMatcherCache(domain.DefaultMatcherCacheSize),
+		logger: logger,
+	}
+}
+
+// SilenceEvaluation is the result of evaluating one active silence against an alert.
+type SilenceEvaluation struct {
+	SilenceID string
+	Matched   bool
+}
+
+// Evaluate checks every active silence against labels.
+//
+// Silences with matchers that fail to compile are skipped (fail-open).
+func (e *SilenceEngine) Evaluate(labels map[string]string, now time.Time) []SilenceEvaluation {
+	if e == nil || e.source == nil {
+		return nil
+	}
+
+	var out []SilenceEvaluation
+	for _, silence := range e.source.ActiveSilences(now) {
+		matched, err := e.MatchesSilence(silence, labels)
+		if err != nil {
+			e.logger.Warn("Skipping silence with invalid matcher", "silence_id", silence.ID, "error", err)
+			continue
+		}
+		out = append(out, SilenceEvaluation{SilenceID: silence.ID, Matched: matched})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].SilenceID < out[j].SilenceID })
+	return out
+}
+
+// RecordMatches counts a suppressed alert for each silence in ids, when the
+// engine's source keeps match statistics.
+func (e *SilenceEngine) RecordMatches(ids []string, now time.Time) {
+	if e == nil || len(ids) == 0 {
+		return
+	}
+	if recorder, ok := e.source.(SilenceMatchRecorder); ok {
+		recorder.RecordMatches(ids, now)
+	}
+}
+
+// MatchingSilenceIDs returns the sorted IDs of active silences matching labels.
+func (e *SilenceEngine) MatchingSilenceIDs(labels map[string]string, now time.Time) []string {
+	return matchedSilenceIDs(e.Evaluate(labels, now))
+}
+
+func matchedSilenceIDs(evaluations []SilenceEvaluation) []string {
+	var ids []string
+	for _, ev := range evaluations {
+		if ev.Matched {
+			ids = append(ids, ev.SilenceID)
+		}
+	}
+	return ids
+}
+
+// Matches reports whether all silence matchers match labels, using the same
+// semantics as the pipeline. A silence without matchers matches nothing.
+func (e *SilenceEngine) Matches(matchers []core.APISilenceMatcher, labels map[string]string) (bool, error) {
+	if len(matchers) == 0 {
+		return false, nil
+	}
+
+	for _, m := range matchers {
+		compiled, err := e.cache.Compile(toDomainMatcher(m))
+		if err != nil {
+			return false, err
+		}
+		// Alertmanager treats a missing label as an empty value.
+		if _, ok := labels[m.Name]; !ok {
+			if !compiled.Matches(map[string]string{m.Name: ""}) {
+				return false, nil
+			}
+			continue
+		}
+		if !compiled.Matches(labels) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// MatchesSilence is Matches honouring the silence's matching policy: with
+// RequireLabelPresence, a matcher never matches a missing or empty label.
+func (e *SilenceEngine) MatchesSilence(silence core.APISilence, labels map[string]string) (bool, error) {
+	if silence.RequireLabelPresence {
+		for _, m := range silence.Matchers {
+			if labels[m.Name] == "" {
+				return false, nil
+			}
+		}
+	}
+	return e.Matches(silence.Matchers, labels)
+}
+
+func toDomainMatcher(m core.APISilenceMatcher) domain.Matcher {
+	matcherType := domain.MatcherTypeEqual
+	switch {
+	case m.IsRegex && m.IsEqual:
+		matcherType = domain.MatcherTypeRegex
+	case m.IsRegex:
+		matcherType = domain.MatcherTypeNotRegex
+	case !m.IsEqual:
+		matcherType = domain.MatcherTypeNotEqual
+	}
+
+	value := m.Value
+	if m.IsRegex {
+		value = "^(?:" + value + ")$"
+	}
+
+	return domain.Matcher{Name: m.Name, Value: value, Type: matcherType, IsRegex: m.IsRegex}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+type staticSilenceSource []core.APISilence
+
+func (s staticSilenceSource) ActiveSilences(time.Time) []core.APISilence {
+	return s
+}
+
+type recordingPublisher struct {
+	published []*core.Alert
+}
+
+func (p *recordingPublisher) PublishToAll(_ context.Context, alert *core.Alert) error {
+	p.published = append(p.published, alert)
+	return nil
+}
+
+func (p *recordingPublisher) PublishWithClassification(_ context.Context, alert *core.Alert, _ *core.ClassificationResult) error {
+	p.published = append(p.published, alert)
+	return nil
+}
+
+type allowAllFilter struct{}
+
+func (allowAllFilter) ShouldBlock(*core.Alert, *core.ClassificationResult) (bool, string) {
+	return false, ""
+}
+
+func TestSilenceEngine_MatchingSilenceIDs(t *testing.T) {
+	engine := NewSilenceEngine(staticSilenceSource{
+		{ID: "b-regex", Matchers: []core.APISilenceMatcher{{Name: "instance", Value: "db-.*", IsRegex: true, IsEqual: true}}},
+		{ID: "a-equal", Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "HighCPU", IsEqual: true}}},
+		{ID: "partial-regex", Matchers: []core.APISilenceMatcher{{Name: "instance", Value: "db", IsRegex: true, IsEqual: true}}},
+		{ID: "not-prod", Matchers: []core.APISilenceMatcher{
+			{Name: "alertname", Value: "HighCPU", IsEqual: true},
+			{Name: "env", Value: "prod", IsEqual: false},
+		}},
+		{ID: "invalid", Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "(", IsRegex: true, IsEqual: true}}},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	now := time.Now()
+
+	assert.Equal(t, []string{"a-equal", "b-regex"},
+		engine.MatchingSilenceIDs(map[string]string{"alertname": "HighCPU", "instance": "db-01", "env": "prod"}, now),
+		"regex matchers are anchored and != excludes matching values")
+	assert.Equal(t, []string{"a-equal", "not-prod"},
+		engine.MatchingSilenceIDs(map[string]string{"alertname": "HighCPU"}, now),
+		"a missing label is treated as empty")
+	assert.Empty(t, engine.MatchingSilenceIDs(map[string]string{"alertname": "DiskFull"}, now))
+}
+
+func TestSilenceEngine_RequireLabelPresence(t *testing.T) {
+	notProd := []core.APISilenceMatcher{
+		{Name: "alertname", Value: "HighCPU", IsEqual: true},
+		{Name: "env", Value: "prod", IsEqual: false},
+	}
+	engine := NewSilenceEngine(staticSilenceSource{
+		{ID: "lenient", Matchers: notProd},
+		{ID: "strict", Matchers: notProd, RequireLabelPresence: true},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	now := time.Now()
+
+	assert.Equal(t, []string{"lenient", "strict"},
+		engine.MatchingSilenceIDs(map[string]string{"alertname": "HighCPU", "env": "staging"}, now))
+	assert.Equal(t, []string{"lenient"},
+		engine.MatchingSilenceIDs(map[string]string{"alertname": "HighCPU"}, now),
+		"a missing label never matches with requireLabelPresence")
+	assert.Equal(t, []string{"lenient"},
+		engine.MatchingSilenceIDs(map[string]string{"alertname": "HighCPU", "env": ""}, now),
+		"an empty label counts as missing")
+}
+
+func TestAlertProcessor_SilencedAlertIsNotPublished(t *testing.T) {
+	publisher := &recordingPublisher{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine: allowAllFilter{},
+		Publisher:    publisher,
+		SilenceEngine: NewSilenceEngine(staticSilenceSource{
+			{ID: "maintenance", Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "HighCPU", IsEqual: true}}},
+		}, nil),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	silenced := &core.Alert{AlertName: "HighCPU", Status: core.StatusFiring, Labels: map[string]string{"alertname": "HighCPU"}}
+	require.NoError(t, processor.ProcessAlert(context.Background(), silenced))
+	assert.Equal(t, []string{"maintenance"}, silenced.SilencedBy)
+	assert.Empty(t, publisher.published)
+
+	resolved := &core.Alert{AlertName: "HighCPU", Status: core.StatusResolved, Labels: map[string]string{"alertname": "HighCPU"}}
+	require.NoError(t, processor.ProcessAlert(context.Background(), resolved))
+	assert.False(t, resolved.IsSilenced())
+
+	other := &core.Alert{AlertName: "DiskFull", Status: core.StatusFiring, Labels: map[string]string{"alertname": "DiskFull"}}
+	require.NoError(t, processor.ProcessAlert(context.Background(), other))
+	assert.Len(t, publisher.published, 2)
+}
+
+type countingSilenceSource struct {
+	staticSilenceSource
+	matches map[string]int
+}
+
+func (s *countingSilenceSource) RecordMatches(ids []string, _ time.Time) {
+	for _, id := range ids {
+		s.matches[id]++
+	}
+}
+
+func TestAlertProcessor_RecordsSilenceMatches(t *testing.T) {
+	source := &countingSilenceSource{
+		staticSilenceSource: staticSilenceSource{
+			{ID: "maintenance", Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "HighCPU", IsEqual: true}}},
+			{ID: "zombie", Matchers: []core.APISilenceMatcher{{Name: "alertname", Value: "Retired", IsEqual: true}}},
+		},
+		matches: make(map[string]int),
+	}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine:  allowAllFilter{},
+		Publisher:     &recordingPublisher{},
+		SilenceEngine: NewSilenceEngine(source, nil),
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	for _, status := range []core.AlertStatus{core.StatusFiring, core.StatusFiring, core.StatusResolved} {
+		alert := &core.Alert{AlertName: "HighCPU", Status: status, Labels: map[string]string{"alertname": "HighCPU"}}
+		require.NoError(t, processor.ProcessAlert(context.Background(), alert))
+	}
+
+	assert.Equal(t, map[string]int{"maintenance": 2}, source.matches, "only suppressed firing alerts count")
+}
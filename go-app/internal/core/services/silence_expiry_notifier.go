@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+const (
+	defaultSilenceExpiryInterval = time.Minute
+	defaultSilenceExpiryLeadTime = 15 * time.Minute
+)
+
+// Names of the notifications sent for silences with notifyOnExpiry.
+const (
+	SilenceExpiringAlertName = "SilenceExpiring"
+	SilenceExpiredAlertName  = "SilenceExpired"
+)
+
+// SilenceLister lists silences. Implemented by memory.SilenceStore.
+type SilenceLister interface {
+	List(now time.Time) []core.APISilence
+}
+
+// FiringAlertLister lists alerts. Implemented by memory.AlertStore.
+type FiringAlertLister interface {
+	List(statusFilter string, includeResolved bool) []core.APIAlert
+}
+
+// TargetPublisher delivers a synthetic notification alert to the named
+// publishing target.
+type TargetPublisher interface {
+	PublishToTarget(ctx context.Context, targetName string, alert *core.Alert) error
+}
+
+// SilenceExpiryNotifierConfig configures the SilenceExpiryNotifier.
+type SilenceExpiryNotifierConfig struct {
+	// Interval between checks (default: 1m).
+	Interval time.Duration
+
+	// LeadTime is how long before a silence ends the "expiring" notification
+	// is sent (default: 15m).
+	LeadTime time.Duration
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// SilenceExpiryNotifier watches silences created with notifyOnExpiry and
+// publishes a notification to their target when they are about to expire, and
+// again when they have expired while matching alerts are still firing.
+type SilenceExpiryNotifier struct {
+	silences  SilenceLister
+	alerts    FiringAlertLister
+	publisher TargetPublisher
+	engine    *SilenceEngine
+	config    SilenceExpiryNotifierConfig
+	logger    *slog.Logger
+	clock     clock.Clock
+
+	mu    sync.Mutex
+	state map[string]*silenceExpiryState
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// silenceExpiryState tracks the notifications already sent for a silence.
+type silenceExpiryState struct {
+	endsAt time.Time
+	// seenActive is set once the silence was observed active, so silences
+	// that were already expired when first seen are never reported.
+	seenActive bool
+	expiring   bool
+}
+
+// NewSilenceExpiryNotifier creates a notifier (not started).
+func NewSilenceExpiryNotifier(silences SilenceLister, alerts FiringAlertLister, publisher TargetPublisher, config SilenceExpiryNotifierConfig) *SilenceExpiryNotifier {
+	if config.Interval <= 0 {
+		config.Interval = defaultSilenceExpiryInterval
+	}
+	if config.LeadTime <= 0 {
+		config.LeadTime = defaultSilenceExpiryLeadTime
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &SilenceExpiryNotifier{
+		silences:  silences,
+		alerts:    alerts,
+		publisher: publisher,
+		engine:    NewSilenceEngine(nil, config.Logger),
+		config:    config,
+		logger:    config.Logger.With("component", "silence_expiry_notifier"),
+		clock:     config.Clock,
+		state:     make(map[string]*silenceExpiryState),
+	}
+}
+
+// Start runs a check immediately and then every Interval until ctx is
+// cancelled or Stop is called.
+func (n *SilenceExpiryNotifier) Start(ctx context.Context) {
+	ctx, n.cancel = context.WithCancel(ctx)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		ticker := n.clock.NewTicker(n.config.Interval)
+		defer ticker.Stop()
+
+		n.Check(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				n.Check(ctx)
+			}
+		}
+	}()
+
+	n.logger.Info("Silence expiry notifier started",
+		"interval", n.config.Interval,
+		"lead_time", n.config.LeadTime,
+	)
+}
+
+// Stop stops the notifier and waits for the running check to finish.
+func (n *SilenceExpiryNotifier) Stop() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
+}
+
+// Check evaluates all silences with notifyOnExpiry and publishes the due
+// notifications. Returns how many were published.
+func (n *SilenceExpiryNotifier) Check(ctx context.Context) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := n.clock.Now().UTC()
+	seen := make(map[string]struct{})
+	sent := 0
+
+	for _, silence := range n.silences.List(now) {
+		if silence.NotifyOnExpiry == "" {
+			continue
+		}
+		endsAt, err := time.Parse(time.RFC3339, silence.EndsAt)
+		if err != nil {
+			continue
+		}
+		seen[silence.ID] = struct{}{}
+
+		st, ok := n.state[silence.ID]
+		if !ok {
+			st = &silenceExpiryState{endsAt: endsAt}
+			n.state[silence.ID] = st
+		}
+		if !st.endsAt.Equal(endsAt) {
+			// Extended or expired early: the lead-time notice is due again.
+			st.endsAt = endsAt
+			st.expiring = false
+		}
+
+		switch silence.Status.State {
+		case "active":
+			st.seenActive = true
+			if !st.expiring && endsAt.Sub(now) <= n.config.LeadTime {
+				st.expiring = true
+				if n.publish(ctx, SilenceExpiringAlertName, silence, endsAt, n.firingMatches(silence), now) {
+					sent++
+				}
+			}
+		case "expired":
+			if st.seenActive {
+				if firing := n.firingMatches(silence); firing > 0 && n.publish(ctx, SilenceExpiredAlertName, silence, endsAt, firing, now) {
+					sent++
+				}
+			}
+			delete(n.state, silence.ID)
+		}
+	}
+
+	for id := range n.state {
+		if _, ok := seen[id]; !ok {
+			delete(n.state, id)
+		}
+	}
+	return sent
+}
+
+// firingMatches counts the firing alerts matched by the silence.
+func (n *SilenceExpiryNotifier) firingMatches(silence core.APISilence) int {
+	if n.alerts == nil {
+		return 0
+	}
+	count := 0
+	for _, alert := range n.alerts.List("firing", false) {
+		if matched, err := n.engine.MatchesSilence(silence, alert.Labels); err == nil && matched {
+			count++
+		}
+	}
+	return count
+}
+
+func (n *SilenceExpiryNotifier) publish(ctx context.Context, alertName string, silence core.APISilence, endsAt time.Time, firing int, now time.Time) bool {
+	alert := silenceExpiryAlert(alertName, silence, endsAt, firing, now)
+	if err := n.publisher.PublishToTarget(ctx, silence.NotifyOnExpiry, alert); err != nil {
+		n.logger.Warn("Failed to publish silence expiry notification",
+			"silence_id", silence.ID,
+			"target", silence.NotifyOnExpiry,
+			"notification", alertName,
+			"error", err)
+		return false
+	}
+
+	n.logger.Info("Published silence expiry notification",
+		"silence_id", silence.ID,
+		"target", silence.NotifyOnExpiry,
+		"notification", alertName,
+		"firing_alerts", firing)
+	return true
+}
+
+// silenceExpiryAlert builds the synthetic alert published for a silence.
+func silenceExpiryAlert(alertName string, silence core.APISilence, endsAt time.Time, firing int, now time.Time) *core.Alert {
+	summary := fmt.Sprintf("Silence %s expires at %s", silence.ID, endsAt.Format(time.RFC3339))
+	if alertName == SilenceExpiredAlertName {
+		summary = fmt.Sprintf("Silence %s expired at %s", silence.ID, endsAt.Format(time.RFC3339))
+	}
+
+	return &core.Alert{
+		Fingerprint: fmt.Sprintf("%s:%s:%d", alertName, silence.ID, endsAt.Unix()),
+		AlertName:   alertName,
+		Status:      core.StatusFiring,
+		Labels: map[string]string{
+			"alertname":  alertName,
+			"silence_id": silence.ID,
+			"severity":   "info",
+		},
+		Annotations: map[string]string{
+			"summary": summary,
+			"description": fmt.Sprintf("Matchers: %s\nCreated by: %s\nComment: %s\nMatching firing alerts: %d",
+				core.FormatSilenceMatchers(silence.Matchers), silence.CreatedBy, silence.Comment, firing),
+		},
+		StartsAt: now,
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+type recordedNotification struct {
+	target string
+	alert  *core.Alert
+}
+
+type fakeTargetPublisher struct {
+	sent []recordedNotification
+}
+
+func (p *fakeTargetPublisher) PublishToTarget(_ context.Context, targetName string, alert *core.Alert) error {
+	p.sent = append(p.sent, recordedNotification{target: targetName, alert: alert})
+	return nil
+}
+
+func newExpiryTestNotifier(fake *clock.Fake, silences *memory.SilenceStore, alerts *memory.AlertStore, publisher *fakeTargetPublisher) *SilenceExpiryNotifier {
+	return NewSilenceExpiryNotifier(silences, alerts, publisher, SilenceExpiryNotifierConfig{
+		LeadTime: 15 * time.Minute,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:    fake,
+	})
+}
+
+func TestSilenceExpiryNotifier_ExpiringAndExpiredWithFiringAlerts(t *testing.T) {
+	start := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	silences := memory.NewSilenceStore()
+	alerts := memory.NewAlertStore()
+	publisher := &fakeTargetPublisher{}
+
+	id, err := silences.CreateOrUpdate(&core.SilenceInput{
+		Matchers:       []core.SilenceMatcherInput{{Name: "env", Value: "staging"}},
+		StartsAt:       start.Format(time.RFC3339),
+		EndsAt:         start.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy:      "ops",
+		Comment:        "maintenance",
+		NotifyOnExpiry: "slack-ops",
+	}, start)
+	require.NoError(t, err)
+	require.NoError(t, alerts.IngestBatch([]core.AlertIngestInput{{
+		Labels:   map[string]string{"alertname": "DiskFull", "env": "staging"},
+		StartsAt: start.Format(time.RFC3339),
+	}}, start))
+
+	notifier := newExpiryTestNotifier(fake, silences, alerts, publisher)
+
+	assert.Equal(t, 0, notifier.Check(context.Background()), "silence is not expiring yet")
+
+	fake.Advance(50 * time.Minute)
+	assert.Equal(t, 1, notifier.Check(context.Background()))
+	assert.Equal(t, 0, notifier.Check(context.Background()), "expiring notice is sent once")
+
+	fake.Advance(15 * time.Minute)
+	assert.Equal(t, 1, notifier.Check(context.Background()))
+	assert.Equal(t, 0, notifier.Check(context.Background()), "expired notice is sent once")
+
+	require.Len(t, publisher.sent, 2)
+	assert.Equal(t, "slack-ops", publisher.sent[0].target)
+	assert.Equal(t, SilenceExpiringAlertName, publisher.sent[0].alert.AlertName)
+	assert.Equal(t, id, publisher.sent[0].alert.Labels["silence_id"])
+	assert.Equal(t, SilenceExpiredAlertName, publisher.sent[1].alert.AlertName)
+	assert.Contains(t, publisher.sent[1].alert.Annotations["description"], "Matching firing alerts: 1")
+}
+
+func TestSilenceExpiryNotifier_SkipsExpiredWithoutFiringAlerts(t *testing.T) {
+	start := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	silences := memory.NewSilenceStore()
+	publisher := &fakeTargetPublisher{}
+
+	_, err := silences.CreateOrUpdate(&core.SilenceInput{
+		Matchers:       []core.SilenceMatcherInput{{Name: "env", Value: "staging"}},
+		StartsAt:       start.Format(time.RFC3339),
+		EndsAt:         start.Add(10 * time.Minute).Format(time.RFC3339),
+		CreatedBy:      "ops",
+		Comment:        "maintenance",
+		NotifyOnExpiry: "slack-ops",
+	}, start)
+	require.NoError(t, err)
+	_, err = silences.CreateOrUpdate(&core.SilenceInput{
+		Matchers:  []core.SilenceMatcherInput{{Name: "env", Value: "prod"}},
+		StartsAt:  start.Format(time.RFC3339),
+		EndsAt:    start.Add(10 * time.Minute).Format(time.RFC3339),
+		CreatedBy: "ops",
+		Comment:   "no notification requested",
+	}, start)
+	require.NoError(t, err)
+
+	notifier := newExpiryTestNotifier(fake, silences, memory.NewAlertStore(), publisher)
+
+	assert.Equal(t, 1, notifier.Check(context.Background()), "already within the lead time")
+	fake.Advance(time.Hour)
+	assert.Equal(t, 0, notifier.Check(context.Background()), "no firing alerts left")
+	require.Len(t, publisher.sent, 1)
+}
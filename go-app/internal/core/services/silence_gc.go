@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/ipiton/AMP/pkg/metrics"
+)
+
+const (
+	defaultSilenceGCInterval  = time.Hour
+	defaultSilenceGCRetention = 120 * time.Hour
+	defaultSilenceGCBatchSize = 1000
+)
+
+// SilenceGCActor is recorded in the audit trail of silences deleted by GC.
+const SilenceGCActor = "silence-gc"
+
+// ExpiredSilenceDeleter permanently deletes expired silences. Implemented by
+// memory.SilenceStore; with persistence enabled, deletions are written
+// through to the silences table.
+type ExpiredSilenceDeleter interface {
+	DeleteExpiredBefore(cutoff time.Time, limit int, actor, reason string, now time.Time) []string
+}
+
+// SilenceGCConfig configures the SilenceGC.
+type SilenceGCConfig struct {
+	// Interval between runs (default: 1h).
+	Interval time.Duration
+
+	// Retention is how long expired silences are kept, like Alertmanager's
+	// --data.retention (default: 120h).
+	Retention time.Duration
+
+	// BatchSize caps the silences deleted per run (default: 1000).
+	BatchSize int
+
+	// Metrics (optional).
+	Metrics *metrics.BusinessMetrics
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// SilenceGC periodically deletes silences that expired more than Retention
+// ago, so they do not accumulate forever once persisted.
+type SilenceGC struct {
+	store  ExpiredSilenceDeleter
+	config SilenceGCConfig
+	logger *slog.Logger
+	clock  clock.Clock
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSilenceGC creates a silence GC (not started).
+func NewSilenceGC(store ExpiredSilenceDeleter, config SilenceGCConfig) *SilenceGC {
+	if config.Interval <= 0 {
+		config.Interval = defaultSilenceGCInterval
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaultSilenceGCRetention
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultSilenceGCBatchSize
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &SilenceGC{
+		store:  store,
+		config: config,
+		logger: config.Logger.With("component", "silence_gc"),
+		clock:  config.Clock,
+	}
+}
+
+// Start runs a collection immediately and then every Interval until ctx is
+// cancelled or Stop is called.
+func (g *SilenceGC) Start(ctx context.Context) {
+	ctx, g.cancel = context.WithCancel(ctx)
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ticker := g.clock.NewTicker(g.config.Interval)
+		defer ticker.Stop()
+
+		g.Collect()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				g.Collect()
+			}
+		}
+	}()
+
+	g.logger.Info("Silence GC started",
+		"interval", g.config.Interval,
+		"retention", g.config.Retention,
+		"batch_size", g.config.BatchSize,
+	)
+}
+
+// Stop stops the GC and waits for the running collection to finish.
+func (g *SilenceGC) Stop() {
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.wg.Wait()
+}
+
+// Collect deletes up to BatchSize silences that ended before the retention
+// cutoff. Returns how many were deleted.
+func (g *SilenceGC) Collect() int {
+	now := g.clock.Now().UTC()
+	cutoff := now.Add(-g.config.Retention)
+	deleted := g.store.DeleteExpiredBefore(cutoff, g.config.BatchSize, SilenceGCActor, "expired before retention cutoff", now)
+
+	result := "clean"
+	switch {
+	case len(deleted) >= g.config.BatchSize:
+		// More may remain; they are picked up by the next runs.
+		result = "truncated"
+		g.logger.Warn("Silence GC batch limit reached",
+			"deleted", len(deleted),
+			"batch_size", g.config.BatchSize)
+	case len(deleted) > 0:
+		result = "deleted"
+		g.logger.Info("Expired silences deleted",
+			"deleted", len(deleted),
+			"cutoff", cutoff)
+	}
+	if g.config.Metrics != nil {
+		g.config.Metrics.RecordSilenceGC(result, len(deleted))
+	}
+	return len(deleted)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+func TestSilenceGC_Collect(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+	store := memory.NewSilenceStore()
+	var audited []core.SilenceAuditEvent
+	store.SetAuditHook(func(e core.SilenceAuditEvent) { audited = append(audited, e) })
+
+	create := func(endsIn time.Duration) string {
+		id, err := store.CreateOrUpdate(&core.SilenceInput{
+			Matchers:  []core.SilenceMatcherInput{{Name: "job", Value: "api"}},
+			StartsAt:  fake.Now().Format(time.RFC3339),
+			EndsAt:    fake.Now().Add(endsIn).Format(time.RFC3339),
+			CreatedBy: "ops",
+			Comment:   "maintenance",
+		}, fake.Now())
+		require.NoError(t, err)
+		return id
+	}
+	first := create(time.Hour)
+	second := create(2 * time.Hour)
+	third := create(3 * time.Hour)
+	active := create(30 * 24 * time.Hour)
+
+	gc := NewSilenceGC(store, SilenceGCConfig{
+		Retention: 24 * time.Hour,
+		BatchSize: 2,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:     fake,
+	})
+
+	fake.Advance(24*time.Hour + 30*time.Minute)
+	assert.Equal(t, 0, gc.Collect(), "expired but within retention")
+
+	fake.Advance(4 * time.Hour)
+	audited = nil
+	assert.Equal(t, 2, gc.Collect(), "capped at the batch size")
+	_, ok := store.Get(first, fake.Now())
+	assert.False(t, ok, "oldest deleted first")
+	_, ok = store.Get(third, fake.Now())
+	assert.True(t, ok)
+
+	require.Len(t, audited, 2)
+	assert.Equal(t, second, audited[1].SilenceID)
+	assert.Equal(t, core.SilenceAuditDelete, audited[1].Action)
+	assert.Equal(t, SilenceGCActor, audited[1].Actor)
+
+	assert.Equal(t, 1, gc.Collect())
+	assert.Equal(t, 0, gc.Collect())
+	_, ok = store.Get(active, fake.Now())
+	assert.True(t, ok, "unexpired silences are kept")
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Outcomes of migrating a single silence.
+const (
+	SilenceMigrated         = "migrated"
+	SilenceMigrationPlanned = "planned"
+	SilenceSkippedExpired   = "skipped_expired"
+	SilenceSkippedDuplicate = "skipped_duplicate"
+	SilenceMigrationFailed  = "failed"
+)
+
+// DefaultSilenceMigrationActor is recorded in the audit trail of imported
+// silences when no actor is given.
+const DefaultSilenceMigrationActor = "silence-migration"
+
+// SilenceImportStore is the local silence store silences are imported into
+// and exported from. Implemented by *memory.SilenceStore.
+type SilenceImportStore interface {
+	List(now time.Time) []core.APISilence
+	CreateOrUpdate(in *core.SilenceInput, now time.Time) (string, error)
+}
+
+// SilenceExportTarget is a remote Alertmanager silences are exported to.
+type SilenceExportTarget interface {
+	ListSilences(ctx context.Context) ([]core.APISilence, error)
+	CreateSilence(ctx context.Context, in core.SilenceInput) (string, error)
+}
+
+// SilenceMigrationOptions controls an import or export.
+type SilenceMigrationOptions struct {
+	// DryRun reports what would be migrated without changing anything.
+	DryRun bool
+	// Actor is recorded in the audit trail of imported silences.
+	Actor string
+}
+
+// SilenceMigrationResult is the outcome for one source silence.
+type SilenceMigrationResult struct {
+	SourceID string `json:"sourceID"`
+	// ID is the silence's ID on the destination, when migrated.
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SilenceMigrationReport summarizes an import or export.
+type SilenceMigrationReport struct {
+	DryRun   bool                     `json:"dryRun"`
+	Migrated int                      `json:"migrated"`
+	Skipped  int                      `json:"skipped"`
+	Failed   int                      `json:"failed"`
+	Results  []SilenceMigrationResult `json:"results"`
+}
+
+func (r *SilenceMigrationReport) add(result SilenceMigrationResult) {
+	switch result.Status {
+	case SilenceMigrated, SilenceMigrationPlanned:
+		r.Migrated++
+	case SilenceMigrationFailed:
+		r.Failed++
+	default:
+		r.Skipped++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// SilenceMigrator copies silences between AMP and Alertmanager so a cutover
+// in either direction keeps active maintenance windows. Expired silences are
+// skipped, and so are silences the destination already has (same matchers
+// and end time), which makes repeated migrations idempotent.
+type SilenceMigrator struct {
+	store SilenceImportStore
+}
+
+// NewSilenceMigrator creates a migrator for the local store.
+func NewSilenceMigrator(store SilenceImportStore) *SilenceMigrator {
+	return &SilenceMigrator{store: store}
+}
+
+// Import creates the unexpired silences in the local store. Imported silences
+// get new IDs; the source IDs are reported alongside.
+func (m *SilenceMigrator) Import(silences []core.APISilence, opts SilenceMigrationOptions, now time.Time) *SilenceMigrationReport {
+	actor := opts.Actor
+	if actor == "" {
+		actor = DefaultSilenceMigrationActor
+	}
+
+	existing := silenceKeys(m.store.List(now), now)
+	report := &SilenceMigrationReport{DryRun: opts.DryRun, Results: make([]SilenceMigrationResult, 0, len(silences))}
+	for _, s := range silences {
+		result := SilenceMigrationResult{SourceID: s.ID}
+		switch status, key := classifyMigration(s, existing, now); status {
+		case "":
+			if opts.DryRun {
+				result.Status = SilenceMigrationPlanned
+				break
+			}
+			in := silenceMigrationInput(s)
+			in.Actor = actor
+			// Migrated silences already exist upstream; the policy only
+			// applies to new silences.
+			in.BypassPolicy = true
+			id, err := m.store.CreateOrUpdate(&in, now)
+			if err != nil {
+				result.Status, result.Error = SilenceMigrationFailed, err.Error()
+				break
+			}
+			result.Status, result.ID = SilenceMigrated, id
+			existing[key] = true
+		default:
+			result.Status = status
+		}
+		report.add(result)
+	}
+	return report
+}
+
+// Export creates the unexpired local silences on target.
+func (m *SilenceMigrator) Export(ctx context.Context, target SilenceExportTarget, opts SilenceMigrationOptions, now time.Time) (*SilenceMigrationReport, error) {
+	remote, err := target.ListSilences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target silences: %w", err)
+	}
+	existing := silenceKeys(remote, now)
+
+	local := m.store.List(now)
+	report := &SilenceMigrationReport{DryRun: opts.DryRun, Results: make([]SilenceMigrationResult, 0, len(local))}
+	for _, s := range local {
+		result := SilenceMigrationResult{SourceID: s.ID}
+		switch status, key := classifyMigration(s, existing, now); status {
+		case "":
+			if opts.DryRun {
+				result.Status = SilenceMigrationPlanned
+				break
+			}
+			id, err := target.CreateSilence(ctx, silenceMigrationInput(s))
+			if err != nil {
+				result.Status, result.Error = SilenceMigrationFailed, err.Error()
+				break
+			}
+			result.Status, result.ID = SilenceMigrated, id
+			existing[key] = true
+		default:
+			result.Status = status
+		}
+		report.add(result)
+	}
+	return report, nil
+}
+
+// classifyMigration returns the skip status of s, or "" when it should be
+// migrated, along with its duplicate-detection key.
+func classifyMigration(s core.APISilence, existing map[string]bool, now time.Time) (string, string) {
+	key := silenceKey(s)
+	if silenceExpired(s, now) {
+		return SilenceSkippedExpired, key
+	}
+	if existing[key] {
+		return SilenceSkippedDuplicate, key
+	}
+	return "", key
+}
+
+func silenceExpired(s core.APISilence, now time.Time) bool {
+	if s.Status.State == "expired" {
+		return true
+	}
+	endsAt, err := time.Parse(time.RFC3339, s.EndsAt)
+	return err == nil && !now.Before(endsAt)
+}
+
+// silenceKeys indexes the unexpired silences by silenceKey.
+func silenceKeys(silences []core.APISilence, now time.Time) map[string]bool {
+	keys := make(map[string]bool, len(silences))
+	for _, s := range silences {
+		if !silenceExpired(s, now) {
+			keys[silenceKey(s)] = true
+		}
+	}
+	return keys
+}
+
+// silenceKey identifies a silence by its order-independent matchers and end
+// time, the parts that survive a migration unchanged.
+func silenceKey(s core.APISilence) string {
+	matchers := append([]core.APISilenceMatcher(nil), s.Matchers...)
+	sort.Slice(matchers, func(i, j int) bool {
+		if matchers[i].Name != matchers[j].Name {
+			return matchers[i].Name < matchers[j].Name
+		}
+		return matchers[i].Value < matchers[j].Value
+	})
+	endsAt := s.EndsAt
+	if t, err := time.Parse(time.RFC3339, s.EndsAt); err == nil {
+		endsAt = t.UTC().Format(time.RFC3339)
+	}
+	return core.FormatSilenceMatchers(matchers) + "|" + endsAt
+}
+
+func silenceMigrationInput(s core.APISilence) core.SilenceInput {
+	matchers := make([]core.SilenceMatcherInput, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		isEqual := m.IsEqual
+		matchers = append(matchers, core.SilenceMatcherInput{
+			Name:    m.Name,
+			Value:   m.Value,
+			IsRegex: m.IsRegex,
+			IsEqual: &isEqual,
+		})
+	}
+	return core.SilenceInput{
+		Matchers:  matchers,
+		StartsAt:  s.StartsAt,
+		EndsAt:    s.EndsAt,
+		CreatedBy: s.CreatedBy,
+		Comment:   s.Comment,
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+type fakeExportTarget struct {
+	silences []core.APISilence
+	created  []core.SilenceInput
+}
+
+func (f *fakeExportTarget) ListSilences(context.Context) ([]core.APISilence, error) {
+	return f.silences, nil
+}
+
+func (f *fakeExportTarget) CreateSilence(_ context.Context, in core.SilenceInput) (string, error) {
+	f.created = append(f.created, in)
+	return "remote-" + in.Comment, nil
+}
+
+func migrationSilence(id, comment string, start, end time.Time, matchers ...core.APISilenceMatcher) core.APISilence {
+	return core.APISilence{
+		ID:        id,
+		Matchers:  matchers,
+		StartsAt:  start.Format(time.RFC3339),
+		EndsAt:    end.Format(time.RFC3339),
+		CreatedBy: "ops",
+		Comment:   comment,
+	}
+}
+
+func TestSilenceMigrator_Import(t *testing.T) {
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	store := memory.NewSilenceStore()
+	migrator := NewSilenceMigrator(store)
+
+	job := core.APISilenceMatcher{Name: "job", Value: "api", IsEqual: true}
+	env := core.APISilenceMatcher{Name: "env", Value: "dev", IsEqual: false}
+	source := []core.APISilence{
+		migrationSilence("am-1", "active", now.Add(-time.Hour), now.Add(time.Hour), job, env),
+		migrationSilence("am-2", "expired", now.Add(-2*time.Hour), now.Add(-time.Hour), job),
+	}
+
+	dry := migrator.Import(source, SilenceMigrationOptions{DryRun: true}, now)
+	assert.Equal(t, 1, dry.Migrated)
+	assert.Empty(t, store.List(now), "dry run changes nothing")
+
+	report := migrator.Import(source, SilenceMigrationOptions{}, now)
+	assert.Equal(t, 1, report.Migrated)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, SilenceSkippedExpired, report.Results[1].Status)
+
+	silences := store.List(now)
+	require.Len(t, silences, 1)
+	assert.Equal(t, report.Results[0].ID, silences[0].ID)
+	assert.Equal(t, "active", silences[0].Status.State)
+	assert.ElementsMatch(t, []core.APISilenceMatcher{job, env}, silences[0].Matchers)
+
+	// Matcher order does not matter for duplicate detection.
+	source[0].Matchers = []core.APISilenceMatcher{env, job}
+	again := migrator.Import(source, SilenceMigrationOptions{}, now)
+	assert.Equal(t, 0, again.Migrated)
+	assert.Equal(t, SilenceSkippedDuplicate, again.Results[0].Status)
+}
+
+func TestSilenceMigrator_Export(t *testing.T) {
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	store := memory.NewSilenceStore()
+	for _, comment := range []string{"one", "two"} {
+		_, err := store.CreateOrUpdate(&core.SilenceInput{
+			Matchers:  []core.SilenceMatcherInput{{Name: "job", Value: comment}},
+			StartsAt:  now.Format(time.RFC3339),
+			EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+			CreatedBy: "ops",
+			Comment:   comment,
+		}, now)
+		require.NoError(t, err)
+	}
+
+	target := &fakeExportTarget{silences: []core.APISilence{
+		migrationSilence("am-1", "one", now, now.Add(time.Hour), core.APISilenceMatcher{Name: "job", Value: "one", IsEqual: true}),
+	}}
+	report, err := NewSilenceMigrator(store).Export(context.Background(), target, SilenceMigrationOptions{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Migrated)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, target.created, 1)
+	assert.Equal(t, "two", target.created[0].Comment)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// SilencePolicyGroup sets the maximum silence duration for a group of creators.
+type SilencePolicyGroup struct {
+	Name string
+	// Members are API token names or createdBy values.
+	Members     []string
+	MaxDuration time.Duration
+}
+
+// SilencePolicyConfig configures the SilencePolicy.
+type SilencePolicyConfig struct {
+	// MaxDuration applies to creators outside every group (0: no limit).
+	MaxDuration time.Duration
+
+	// Groups are checked in order; the first group listing the creator wins.
+	Groups []SilencePolicyGroup
+
+	// CommentPatterns must all match the comment, e.g. a ticket ID.
+	CommentPatterns []*regexp.Regexp
+
+	// Protected label sets, e.g. {namespace: production}. A silence that
+	// could match alerts carrying all labels of a set needs the approval of
+	// a second person. Matchers on other labels are not taken into account.
+	Protected []map[string]string
+}
+
+// SilencePolicy enforces limits on new and updated silences. It is installed
+// as the silence store's policy hook, so every client is subject to it.
+type SilencePolicy struct {
+	config SilencePolicyConfig
+}
+
+// NewSilencePolicy creates a silence policy.
+func NewSilencePolicy(config SilencePolicyConfig) *SilencePolicy {
+	return &SilencePolicy{config: config}
+}
+
+// Check validates a silence. It returns an error wrapping
+// core.ErrSilencePolicyViolation when the silence breaks the policy, or
+// core.ErrSilenceApprovalRequired when it only lacks a second person's
+// approval. The creator is the API token name, falling back to createdBy.
+func (p *SilencePolicy) Check(in *core.SilenceInput, now time.Time) error {
+	creator := silenceCreator(in)
+
+	startsAt := now
+	if raw := strings.TrimSpace(in.StartsAt); raw != "" {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil && t.After(now) {
+			startsAt = t
+		}
+	}
+	endsAt, err := time.Parse(time.RFC3339, strings.TrimSpace(in.EndsAt))
+	if err != nil {
+		return fmt.Errorf("%w: invalid end time", core.ErrSilencePolicyViolation)
+	}
+	if limit, group := p.maxDuration(creator); limit > 0 && endsAt.Sub(startsAt) > limit {
+		return fmt.Errorf("%w: duration %s exceeds the maximum of %s for %s",
+			core.ErrSilencePolicyViolation, endsAt.Sub(startsAt).Round(time.Second), limit, group)
+	}
+
+	for _, re := range p.config.CommentPatterns {
+		if !re.MatchString(in.Comment) {
+			return fmt.Errorf("%w: comment must match %q", core.ErrSilencePolicyViolation, re.String())
+		}
+	}
+
+	if set := p.protectedSet(in.Matchers); set != nil {
+		approver := strings.TrimSpace(in.ApprovedBy)
+		if approver == "" {
+			return fmt.Errorf("%w: matches protected labels %s", core.ErrSilenceApprovalRequired, formatLabelSet(set))
+		}
+		if approver == creator {
+			return fmt.Errorf("%w: %s cannot approve their own silence", core.ErrSilencePolicyViolation, approver)
+		}
+	}
+	return nil
+}
+
+// maxDuration returns the duration limit of creator and who it applies to.
+func (p *SilencePolicy) maxDuration(creator string) (time.Duration, string) {
+	for _, group := range p.config.Groups {
+		for _, member := range group.Members {
+			if member == creator {
+				return group.MaxDuration, "group " + group.Name
+			}
+		}
+	}
+	return p.config.MaxDuration, "silences"
+}
+
+// protectedSet returns the first protected label set the matchers could match.
+func (p *SilencePolicy) protectedSet(matchers []core.SilenceMatcherInput) map[string]string {
+	for _, set := range p.config.Protected {
+		if silenceCouldMatch(matchers, set) {
+			return set
+		}
+	}
+	return nil
+}
+
+// silenceCouldMatch reports whether alerts carrying the labels of set can be
+// matched by matchers; matchers on labels outside set are ignored.
+func silenceCouldMatch(matchers []core.SilenceMatcherInput, set map[string]string) bool {
+	for _, m := range matchers {
+		value, ok := set[m.Name]
+		if !ok {
+			continue
+		}
+		match := value == m.Value
+		if m.IsRegex {
+			re, err := regexp.Compile(m.Value)
+			if err != nil {
+				return false
+			}
+			match = re.MatchString(value)
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		if match != isEqual {
+			return false
+		}
+	}
+	return true
+}
+
+func silenceCreator(in *core.SilenceInput) string {
+	if actor := strings.TrimSpace(in.Actor); actor != "" {
+		return actor
+	}
+	return strings.TrimSpace(in.CreatedBy)
+}
+
+func formatLabelSet(set map[string]string) string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", name, set[name]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
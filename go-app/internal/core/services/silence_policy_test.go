@@ -0,0 +1,104 @@
+package services
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/storage/memory"
+)
+
+func testSilencePolicy() *SilencePolicy {
+	return NewSilencePolicy(SilencePolicyConfig{
+		MaxDuration: 4 * time.Hour,
+		Groups: []SilencePolicyGroup{
+			{Name: "sre", Members: []string{"alice"}, MaxDuration: 7 * 24 * time.Hour},
+		},
+		CommentPatterns: []*regexp.Regexp{regexp.MustCompile(`[A-Z]+-[0-9]+`)},
+		Protected:       []map[string]string{{"namespace": "production"}},
+	})
+}
+
+func policyInput(createdBy string, duration time.Duration, comment string, matchers ...core.SilenceMatcherInput) *core.SilenceInput {
+	now := time.Now().UTC()
+	return &core.SilenceInput{
+		Matchers:  matchers,
+		StartsAt:  now.Format(time.RFC3339),
+		EndsAt:    now.Add(duration).Format(time.RFC3339),
+		CreatedBy: createdBy,
+		Comment:   comment,
+	}
+}
+
+func TestSilencePolicy_Check(t *testing.T) {
+	policy := testSilencePolicy()
+	now := time.Now().UTC()
+	staging := core.SilenceMatcherInput{Name: "namespace", Value: "staging"}
+	notEqual := false
+
+	tests := []struct {
+		name    string
+		in      *core.SilenceInput
+		wantErr error
+	}{
+		{"allowed", policyInput("bob", time.Hour, "OPS-1", staging), nil},
+		{"too long for default", policyInput("bob", 8*time.Hour, "OPS-1", staging), core.ErrSilencePolicyViolation},
+		{"group limit", policyInput("alice", 48*time.Hour, "OPS-1", staging), nil},
+		{"comment without ticket", policyInput("bob", time.Hour, "maintenance", staging), core.ErrSilencePolicyViolation},
+		{"protected namespace", policyInput("bob", time.Hour, "OPS-1", core.SilenceMatcherInput{Name: "namespace", Value: "production"}), core.ErrSilenceApprovalRequired},
+		{"protected by regex", policyInput("bob", time.Hour, "OPS-1", core.SilenceMatcherInput{Name: "namespace", Value: "prod.*", IsRegex: true}), core.ErrSilenceApprovalRequired},
+		{"no namespace matcher", policyInput("bob", time.Hour, "OPS-1", core.SilenceMatcherInput{Name: "alertname", Value: "DiskFull"}), core.ErrSilenceApprovalRequired},
+		{"excludes production", policyInput("bob", time.Hour, "OPS-1",
+			core.SilenceMatcherInput{Name: "alertname", Value: "DiskFull"},
+			core.SilenceMatcherInput{Name: "namespace", Value: "production", IsEqual: &notEqual}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.in, now)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSilencePolicy_TwoPersonApproval(t *testing.T) {
+	store := memory.NewSilenceStore()
+	store.SetPolicy(testSilencePolicy().Check)
+	now := time.Now().UTC()
+
+	in := policyInput("bob", time.Hour, "OPS-1", core.SilenceMatcherInput{Name: "namespace", Value: "production"})
+	_, err := store.CreateOrUpdate(in, now)
+	require.ErrorIs(t, err, core.ErrSilenceApprovalRequired)
+	assert.Empty(t, store.List(now), "unapproved silence is not created")
+
+	req, err := store.RequestApproval(in, err.Error(), now)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", req.RequestedBy)
+	require.Len(t, store.ListApprovals(now), 1)
+
+	_, err = store.Approve(req.ID, "bob", now)
+	require.ErrorIs(t, err, core.ErrSilencePolicyViolation, "requester cannot approve")
+
+	id, err := store.Approve(req.ID, "carol", now)
+	require.NoError(t, err)
+	silence, ok := store.Get(id, now)
+	require.True(t, ok)
+	assert.Equal(t, "active", silence.Status.State)
+	assert.Empty(t, store.ListApprovals(now))
+
+	bypass := policyInput("scheduler", 30*24*time.Hour, "recurring", core.SilenceMatcherInput{Name: "namespace", Value: "production"})
+	bypass.BypassPolicy = true
+	_, err = store.CreateOrUpdate(bypass, now)
+	assert.NoError(t, err)
+
+	_, err = store.RequestApproval(in, "", now)
+	require.NoError(t, err)
+	assert.Empty(t, store.ListApprovals(now.Add(2*time.Hour)), "requests expire with their silence")
+}
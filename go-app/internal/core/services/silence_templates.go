@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// SilenceTemplateMatcher is a matcher of a silence template. Value is a
+// template over the request parameters, e.g. {{ .instance }}.
+type SilenceTemplateMatcher struct {
+	Name    string
+	Value   *template.Template
+	IsRegex bool
+	IsEqual bool
+}
+
+// SilenceTemplate describes a recurring maintenance type, so on-call
+// engineers create consistent silences by filling in a few parameters.
+type SilenceTemplate struct {
+	Name     string
+	Matchers []SilenceTemplateMatcher
+	// Duration is used when the request does not set one.
+	Duration time.Duration
+	// MaxDuration caps requested durations (0: no cap).
+	MaxDuration time.Duration
+	// Comment is a template over the request parameters.
+	Comment *template.Template
+	// RequiredParams must be present and non-empty in every request.
+	RequiredParams []string
+}
+
+// SilenceTemplateRequest holds the values a silence is created from.
+type SilenceTemplateRequest struct {
+	Params map[string]string
+	// Duration overrides the template's default duration when positive.
+	Duration time.Duration
+	// StartsAt defaults to now.
+	StartsAt  time.Time
+	CreatedBy string
+}
+
+// ParseSilenceTemplateText parses a matcher value or comment template.
+// Referencing a parameter that was not supplied is an error. The regexQuote
+// function escapes a parameter for use in a regex matcher.
+func ParseSilenceTemplateText(name, text string) (*template.Template, error) {
+	return template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"regexQuote": regexp.QuoteMeta}).
+		Parse(text)
+}
+
+// Render builds the silence input for req.
+func (t *SilenceTemplate) Render(req SilenceTemplateRequest, now time.Time) (*core.SilenceInput, error) {
+	for _, name := range t.RequiredParams {
+		if strings.TrimSpace(req.Params[name]) == "" {
+			return nil, fmt.Errorf("parameter %q is required", name)
+		}
+	}
+	params := req.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+
+	duration := t.Duration
+	if req.Duration > 0 {
+		duration = req.Duration
+	}
+	if t.MaxDuration > 0 && duration > t.MaxDuration {
+		return nil, fmt.Errorf("duration %s exceeds the template maximum of %s", duration, t.MaxDuration)
+	}
+	startsAt := req.StartsAt
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+
+	matchers := make([]core.SilenceMatcherInput, 0, len(t.Matchers))
+	for _, m := range t.Matchers {
+		value, err := executeSilenceTemplate(m.Value, params)
+		if err != nil {
+			return nil, fmt.Errorf("matcher %q: %w", m.Name, err)
+		}
+		isEqual := m.IsEqual
+		matchers = append(matchers, core.SilenceMatcherInput{
+			Name:    m.Name,
+			Value:   value,
+			IsRegex: m.IsRegex,
+			IsEqual: &isEqual,
+		})
+	}
+
+	comment, err := executeSilenceTemplate(t.Comment, params)
+	if err != nil {
+		return nil, fmt.Errorf("comment: %w", err)
+	}
+
+	return &core.SilenceInput{
+		Matchers:  matchers,
+		StartsAt:  startsAt.UTC().Format(time.RFC3339),
+		EndsAt:    startsAt.Add(duration).UTC().Format(time.RFC3339),
+		CreatedBy: req.CreatedBy,
+		Comment:   comment,
+	}, nil
+}
+
+func executeSilenceTemplate(tmpl *template.Template, params map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nodeRebootTemplate(t *testing.T) *SilenceTemplate {
+	t.Helper()
+	instance, err := ParseSilenceTemplateText("instance", "{{ regexQuote .instance }}(:[0-9]+)?")
+	require.NoError(t, err)
+	env, err := ParseSilenceTemplateText("env", "prod")
+	require.NoError(t, err)
+	comment, err := ParseSilenceTemplateText("comment", "Reboot of {{ .instance }} ({{ .ticket }})")
+	require.NoError(t, err)
+	return &SilenceTemplate{
+		Name: "node-reboot",
+		Matchers: []SilenceTemplateMatcher{
+			{Name: "instance", Value: instance, IsRegex: true, IsEqual: true},
+			{Name: "env", Value: env, IsEqual: true},
+		},
+		Duration:       time.Hour,
+		MaxDuration:    4 * time.Hour,
+		Comment:        comment,
+		RequiredParams: []string{"instance", "ticket"},
+	}
+}
+
+func TestSilenceTemplate_Render(t *testing.T) {
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	tmpl := nodeRebootTemplate(t)
+
+	in, err := tmpl.Render(SilenceTemplateRequest{
+		Params:    map[string]string{"instance": "db-1.example.com", "ticket": "OPS-42"},
+		CreatedBy: "alice",
+	}, now)
+	require.NoError(t, err)
+	require.Len(t, in.Matchers, 2)
+	assert.Equal(t, `db-1\.example\.com(:[0-9]+)?`, in.Matchers[0].Value)
+	assert.True(t, in.Matchers[0].IsRegex)
+	assert.Equal(t, "Reboot of db-1.example.com (OPS-42)", in.Comment)
+	assert.Equal(t, "2026-03-16T09:00:00Z", in.StartsAt)
+	assert.Equal(t, "2026-03-16T10:00:00Z", in.EndsAt)
+	assert.Equal(t, "alice", in.CreatedBy)
+
+	in, err = tmpl.Render(SilenceTemplateRequest{
+		Params:   map[string]string{"instance": "db-1", "ticket": "OPS-42"},
+		Duration: 2 * time.Hour,
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-16T11:00:00Z", in.EndsAt)
+}
+
+func TestSilenceTemplate_RenderErrors(t *testing.T) {
+	now := time.Now()
+	tmpl := nodeRebootTemplate(t)
+
+	_, err := tmpl.Render(SilenceTemplateRequest{Params: map[string]string{"instance": "db-1"}}, now)
+	assert.ErrorContains(t, err, `parameter "ticket" is required`)
+
+	_, err = tmpl.Render(SilenceTemplateRequest{
+		Params:   map[string]string{"instance": "db-1", "ticket": "OPS-42"},
+		Duration: 5 * time.Hour,
+	}, now)
+	assert.ErrorContains(t, err, "exceeds the template maximum")
+
+	tmpl.RequiredParams = nil
+	_, err = tmpl.Render(SilenceTemplateRequest{Params: map[string]string{"instance": "db-1"}}, now)
+	assert.Error(t, err, "unknown parameters fail rendering")
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// StartupRepeatInterval is the repeat interval missed repeat notifications
+// are estimated with (the Alertmanager default repeat_interval).
+const StartupRepeatInterval = 4 * time.Hour
+
+// StartupSilences reports the silences known at startup.
+type StartupSilences struct {
+	Restored int `json:"restored"`
+
+	// ExpiredWhileDown lists the silences that ended between the last
+	// checkpoint of the previous run and startup.
+	ExpiredWhileDown []string `json:"expiredWhileDown"`
+
+	// MissedExpiryNotifications lists the silences of ExpiredWhileDown whose
+	// expiry notification (notifyOnExpiry) was never sent.
+	MissedExpiryNotifications []string `json:"missedExpiryNotifications"`
+}
+
+// StartupInhibition reports the inhibition state restored at startup.
+type StartupInhibition struct {
+	SourceAlerts int `json:"sourceAlerts"`
+	Inhibitions  int `json:"inhibitions"`
+}
+
+// StartupGroups reports the target groups restored at startup.
+type StartupGroups struct {
+	Restored int `json:"restored"`
+	Dropped  int `json:"dropped"` // groups of targets no longer discovered
+}
+
+// StartupTimers reports the flush timers of the restored target groups.
+type StartupTimers struct {
+	Restored int `json:"restored"`
+	Missed   int `json:"missed"` // expired while AMP was down, fired at startup
+}
+
+// StartupReport reconciles the state AMP restored at boot with the previous
+// run, so operators know what may have been missed during the downtime.
+//
+// The previous run is known from its last checkpoint; without one (first
+// start, in-memory cache) LastSeenAt is nil and nothing is estimated.
+type StartupReport struct {
+	StartedAt     time.Time  `json:"startedAt"`
+	LastSeenAt    *time.Time `json:"lastSeenAt,omitempty"`
+	Downtime      string     `json:"downtime,omitempty"`
+	CleanShutdown *bool      `json:"cleanShutdown,omitempty"`
+
+	Silences   StartupSilences   `json:"silences"`
+	Inhibition StartupInhibition `json:"inhibition"`
+	Groups     StartupGroups     `json:"groups"`
+	Timers     StartupTimers     `json:"timers"`
+
+	// Replay reports the firing alerts replayed from Prometheus or
+	// Alertmanager; nil when no replay ran.
+	Replay *AlertReplayResult `json:"replay,omitempty"`
+
+	// MissedRepeatNotifications estimates the repeat notifications firing
+	// target groups would have received during the downtime, at
+	// StartupRepeatInterval.
+	MissedRepeatNotifications int `json:"missedRepeatNotificationsEstimate"`
+}
+
+// NewStartupReport creates the report of a start at startedAt. lastSeenAt is
+// the last checkpoint of the previous run, zero when unknown.
+func NewStartupReport(startedAt, lastSeenAt time.Time, cleanShutdown bool) *StartupReport {
+	report := &StartupReport{
+		StartedAt: startedAt,
+		Silences: StartupSilences{
+			ExpiredWhileDown:          []string{},
+			MissedExpiryNotifications: []string{},
+		},
+	}
+	if !lastSeenAt.IsZero() {
+		report.LastSeenAt = &lastSeenAt
+		report.Downtime = startedAt.Sub(lastSeenAt).Round(time.Second).String()
+		report.CleanShutdown = &cleanShutdown
+	}
+	return report
+}
+
+// AddSilences counts the silences known at startup and lists those that
+// expired while AMP was down.
+func (r *StartupReport) AddSilences(silences []core.APISilence) {
+	r.Silences.Restored += len(silences)
+	if r.LastSeenAt == nil {
+		return
+	}
+	for _, silence := range silences {
+		endsAt, err := time.Parse(time.RFC3339, silence.EndsAt)
+		if err != nil || !endsAt.After(*r.LastSeenAt) || endsAt.After(r.StartedAt) {
+			continue
+		}
+		r.Silences.ExpiredWhileDown = append(r.Silences.ExpiredWhileDown, silence.ID)
+		if silence.NotifyOnExpiry != "" {
+			r.Silences.MissedExpiryNotifications = append(r.Silences.MissedExpiryNotifications, silence.ID)
+		}
+	}
+	sort.Strings(r.Silences.ExpiredWhileDown)
+	sort.Strings(r.Silences.MissedExpiryNotifications)
+}
+
+// AddFiringGroup estimates the repeat notifications a group firing at the
+// last checkpoint and last notified at lastFlush missed during the downtime.
+func (r *StartupReport) AddFiringGroup(lastFlush time.Time) {
+	if r.LastSeenAt == nil || lastFlush.IsZero() {
+		return
+	}
+	due := func(at time.Time) int {
+		if at.Before(lastFlush) {
+			return 0
+		}
+		return int(at.Sub(lastFlush) / StartupRepeatInterval)
+	}
+	r.MissedRepeatNotifications += due(r.StartedAt) - due(*r.LastSeenAt)
+}
+
+// LogAttrs returns the report as slog attributes.
+func (r *StartupReport) LogAttrs() []any {
+	attrs := []any{
+		"silences_restored", r.Silences.Restored,
+		"silences_expired_while_down", len(r.Silences.ExpiredWhileDown),
+		"missed_expiry_notifications", len(r.Silences.MissedExpiryNotifications),
+		"inhibition_source_alerts", r.Inhibition.SourceAlerts,
+		"inhibitions", r.Inhibition.Inhibitions,
+		"groups_restored", r.Groups.Restored,
+		"groups_dropped", r.Groups.Dropped,
+		"timers_restored", r.Timers.Restored,
+		"timers_missed", r.Timers.Missed,
+		"missed_repeat_notifications_estimate", r.MissedRepeatNotifications,
+	}
+	if r.Replay != nil {
+		attrs = append(attrs, "alerts_replayed", r.Replay.Replayed)
+	}
+	if r.LastSeenAt != nil {
+		attrs = append(attrs, "downtime", r.Downtime, "clean_shutdown", *r.CleanShutdown)
+	} else {
+		attrs = append(attrs, "downtime", "unknown")
+	}
+	return attrs
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupReport_SilencesExpiredWhileDown(t *testing.T) {
+	lastSeen := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	started := lastSeen.Add(2 * time.Hour)
+	report := NewStartupReport(started, lastSeen, false)
+
+	silence := func(id string, endsAt time.Time, notify string) core.APISilence {
+		return core.APISilence{ID: id, EndsAt: endsAt.Format(time.RFC3339), NotifyOnExpiry: notify}
+	}
+	report.AddSilences([]core.APISilence{
+		silence("expired-before", lastSeen.Add(-time.Minute), "chat"),
+		silence("expired-down", lastSeen.Add(time.Hour), ""),
+		silence("expired-down-notify", lastSeen.Add(90*time.Minute), "chat"),
+		silence("active", started.Add(time.Hour), "chat"),
+	})
+
+	assert.Equal(t, 4, report.Silences.Restored)
+	assert.Equal(t, []string{"expired-down", "expired-down-notify"}, report.Silences.ExpiredWhileDown)
+	assert.Equal(t, []string{"expired-down-notify"}, report.Silences.MissedExpiryNotifications)
+	assert.Equal(t, "2h0m0s", report.Downtime)
+	require.NotNil(t, report.CleanShutdown)
+	assert.False(t, *report.CleanShutdown)
+}
+
+func TestStartupReport_MissedRepeatNotifications(t *testing.T) {
+	lastSeen := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	report := NewStartupReport(lastSeen.Add(8*time.Hour+30*time.Minute), lastSeen, true)
+
+	// Notified 3h before the checkpoint: repeats were due 1h and 5h into the downtime
+	report.AddFiringGroup(lastSeen.Add(-3 * time.Hour))
+	// Notified at the checkpoint: repeats were due 4h and 8h into the downtime
+	report.AddFiringGroup(lastSeen)
+	report.AddFiringGroup(time.Time{})
+
+	assert.Equal(t, 4, report.MissedRepeatNotifications)
+}
+
+func TestStartupReport_UnknownPreviousRun(t *testing.T) {
+	report := NewStartupReport(time.Now(), time.Time{}, false)
+	report.AddSilences([]core.APISilence{{ID: "s", EndsAt: time.Now().Add(-time.Minute).Format(time.RFC3339), NotifyOnExpiry: "chat"}})
+	report.AddFiringGroup(time.Now().Add(-24 * time.Hour))
+
+	assert.Nil(t, report.LastSeenAt)
+	assert.Nil(t, report.CleanShutdown)
+	assert.Equal(t, 1, report.Silences.Restored)
+	assert.Empty(t, report.Silences.ExpiredWhileDown)
+	assert.Zero(t, report.MissedRepeatNotifications)
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/ipiton/AMP/pkg/metrics"
+)
+
+const (
+	defaultStormWindow         = time.Minute
+	defaultStormMultiplier     = 5
+	defaultStormMinAlerts      = 50
+	defaultStormCooldown       = 5 * time.Minute
+	defaultStormDigestInterval = 5 * time.Minute
+
+	// stormBaselineSmoothing weighs the latest window in the baseline rate.
+	stormBaselineSmoothing = 0.2
+	// stormMaxRolledWindows bounds the windows replayed after a long pause.
+	stormMaxRolledWindows = 64
+	// stormDigestMaxGroups caps the groups listed in a digest.
+	stormDigestMaxGroups = 20
+	// stormSuppressedTTL is how long resolutions of digested alerts are
+	// held back after they were digested.
+	stormSuppressedTTL = 24 * time.Hour
+)
+
+const (
+	// StormLabel is set on storm notifications to the storm ID. Alerts
+	// carrying it are never counted or digested.
+	StormLabel = "storm"
+	// StormAlertName is the meta-notification that fires while in storm mode.
+	StormAlertName = "AlertStorm"
+	// StormDigestAlertName is the periodic digest of alerts held back during a storm.
+	StormDigestAlertName = "AlertStormDigest"
+)
+
+// StormAlertSink processes storm notifications that are not triggered by an
+// incoming alert (digests and the end of a storm).
+type StormAlertSink interface {
+	ProcessAlert(ctx context.Context, alert *core.Alert) error
+}
+
+// StormDetectorConfig configures the StormDetector.
+type StormDetectorConfig struct {
+	// Window is the rate measurement window (default: 1m).
+	Window time.Duration
+
+	// Multiplier is how many times the baseline rate the alerts of a window
+	// must reach to start a storm (default: 5).
+	Multiplier float64
+
+	// MinAlerts is the least number of alerts in a window that starts a
+	// storm, so quiet systems do not storm on a handful of alerts (default: 50).
+	MinAlerts int
+
+	// Cooldown is how long the rate must stay below the storm threshold
+	// before storm mode ends (default: 5m).
+	Cooldown time.Duration
+
+	// DigestInterval is how often held back alerts are sent as a digest
+	// (default: 5m).
+	DigestInterval time.Duration
+
+	// GroupBy are the labels alerts are grouped by during a storm
+	// (default: ["alertname"]).
+	GroupBy []string
+
+	// CriticalSeverities are still paged during a storm, once per group
+	// and digest interval (default: ["critical"]).
+	CriticalSeverities []string
+
+	// Sink receives digests and the resolved storm notification.
+	Sink StormAlertSink
+
+	// Metrics (optional).
+	Metrics *metrics.BusinessMetrics
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+}
+
+// StormObservation is the outcome of observing an alert.
+type StormObservation struct {
+	// Suppressed reports that the alert must not be published individually.
+	Suppressed bool
+	// Reason is "digested" for alerts sent in the next digest and "grouped"
+	// for critical alerts of a group that was already paged.
+	Reason string
+	// Group identifies the storm group of the alert.
+	Group string
+}
+
+// StormDetector detects alert storms: windows whose firing alert count is
+// Multiplier times the baseline rate. In storm mode, non-critical alerts are
+// only sent in periodic digests, critical alerts are paged once per group,
+// and a single AlertStorm notification fires until the rate has been back to
+// normal for Cooldown.
+type StormDetector struct {
+	config   StormDetectorConfig
+	critical map[string]bool
+	logger   *slog.Logger
+	clock    clock.Clock
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCount int
+	baseline    float64
+	hasBaseline bool
+
+	active      *core.Alert
+	calmSince   time.Time
+	digestStart time.Time
+	digestSeq   int
+	groups      map[string]int
+	paged       map[string]bool
+	suppressed  map[string]time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewStormDetector creates a storm detector (its ticker is not started).
+func NewStormDetector(config StormDetectorConfig) *StormDetector {
+	if config.Window <= 0 {
+		config.Window = defaultStormWindow
+	}
+	if config.Multiplier <= 1 {
+		config.Multiplier = defaultStormMultiplier
+	}
+	if config.MinAlerts <= 0 {
+		config.MinAlerts = defaultStormMinAlerts
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaultStormCooldown
+	}
+	if config.DigestInterval <= 0 {
+		config.DigestInterval = defaultStormDigestInterval
+	}
+	if len(config.GroupBy) == 0 {
+		config.GroupBy = []string{"alertname"}
+	}
+	if len(config.CriticalSeverities) == 0 {
+		config.CriticalSeverities = []string{"critical"}
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	critical := make(map[string]bool, len(config.CriticalSeverities))
+	for _, s := range config.CriticalSeverities {
+		critical[s] = true
+	}
+	return &StormDetector{
+		config:     config,
+		critical:   critical,
+		logger:     config.Logger.With("component", "storm_detector"),
+		clock:      config.Clock,
+		groups:     make(map[string]int),
+		paged:      make(map[string]bool),
+		suppressed: make(map[string]time.Time),
+	}
+}
+
+// Start ticks every Window until ctx is cancelled or Stop is called, sending
+// digests and the end of storms to the sink.
+func (d *StormDetector) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		ticker := d.clock.NewTicker(d.config.Window)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				for _, alert := range d.Tick() {
+					d.send(ctx, alert)
+				}
+			}
+		}
+	}()
+
+	d.logger.Info("Storm detector started",
+		"window", d.config.Window,
+		"multiplier", d.config.Multiplier,
+		"min_alerts", d.config.MinAlerts,
+		"cooldown", d.config.Cooldown,
+	)
+}
+
+// Stop stops the ticker and waits for a running tick to finish.
+func (d *StormDetector) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+func (d *StormDetector) send(ctx context.Context, alert *core.Alert) {
+	if d.config.Sink == nil {
+		return
+	}
+	if err := d.config.Sink.ProcessAlert(ctx, alert); err != nil {
+		d.logger.Warn("Failed to send storm notification",
+			"alert", alert.AlertName,
+			"fingerprint", alert.Fingerprint,
+			"error", err)
+	}
+}
+
+// Active reports whether storm mode is on.
+func (d *StormDetector) Active() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.active != nil
+}
+
+// Count adds a firing alert to the ingest rate. When it starts a storm, the
+// firing AlertStorm notification is returned.
+func (d *StormDetector) Count(alert *core.Alert) *core.Alert {
+	if alert == nil || alert.Status != core.StatusFiring || alert.Labels[StormLabel] != "" {
+		return nil
+	}
+
+	now := d.clock.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.roll(now)
+	d.windowCount++
+	if d.active != nil {
+		return nil
+	}
+	threshold := d.threshold()
+	if float64(d.windowCount) < threshold {
+		return nil
+	}
+
+	id := now.UTC().Format("20060102T150405Z")
+	d.active = &core.Alert{
+		AlertName: StormAlertName,
+		Status:    core.StatusFiring,
+		Labels: map[string]string{
+			"alertname": StormAlertName,
+			"severity":  "critical",
+			StormLabel:  id,
+		},
+		Annotations: map[string]string{
+			"summary": fmt.Sprintf("Alert storm: %d alerts within %s (baseline %.1f)", d.windowCount, d.config.Window, d.baseline),
+			"description": fmt.Sprintf("Storm mode is on: %s alerts are only sent in digests every %s and other alerts are paged once per %s group.",
+				d.nonCriticalText(), d.config.DigestInterval, strings.Join(d.config.GroupBy, "/")),
+		},
+		StartsAt:    now,
+		Fingerprint: "storm-" + id,
+	}
+	d.calmSince = time.Time{}
+	d.digestStart = now
+	d.digestSeq = 0
+
+	d.logger.Warn("Alert storm detected",
+		"alerts", d.windowCount,
+		"window", d.config.Window,
+		"baseline", d.baseline,
+		"threshold", threshold)
+	if d.config.Metrics != nil {
+		d.config.Metrics.RecordStormTransition("entered")
+	}
+	return d.active
+}
+
+// Observe decides whether alert is published individually. Firing alerts are
+// held back for the digest unless they are critical and the first of their
+// group in the digest interval; resolutions of held back alerts are dropped.
+func (d *StormDetector) Observe(alert *core.Alert) StormObservation {
+	var obs StormObservation
+	if alert == nil || alert.Labels[StormLabel] != "" {
+		return obs
+	}
+
+	now := d.clock.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if alert.Status != core.StatusFiring {
+		if _, ok := d.suppressed[alert.Fingerprint]; ok {
+			delete(d.suppressed, alert.Fingerprint)
+			obs.Suppressed = true
+			obs.Reason = "digested"
+			d.recordSuppressed(obs.Reason)
+		}
+		return obs
+	}
+	if d.active == nil {
+		return obs
+	}
+
+	key := d.groupKey(alert.Labels)
+	obs.Group = key
+	if d.critical[alert.Labels["severity"]] && !d.paged[key] {
+		d.paged[key] = true
+		return obs
+	}
+
+	obs.Suppressed = true
+	obs.Reason = "digested"
+	if d.critical[alert.Labels["severity"]] {
+		obs.Reason = "grouped"
+	}
+	d.groups[key]++
+	d.suppressed[alert.Fingerprint] = now
+	d.recordSuppressed(obs.Reason)
+	return obs
+}
+
+// Tick closes elapsed rate windows and returns the notifications that are
+// due: a digest every DigestInterval during a storm and, once the rate has
+// been normal for Cooldown, a final digest and the resolved AlertStorm.
+func (d *StormDetector) Tick() []*core.Alert {
+	now := d.clock.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.roll(now)
+	for fp, at := range d.suppressed {
+		if now.Sub(at) > stormSuppressedTTL {
+			delete(d.suppressed, fp)
+		}
+	}
+	if d.active == nil {
+		return nil
+	}
+
+	var out []*core.Alert
+	ending := !d.calmSince.IsZero() && now.Sub(d.calmSince) >= d.config.Cooldown
+	if ending || now.Sub(d.digestStart) >= d.config.DigestInterval {
+		if digest := d.digest(now); digest != nil {
+			out = append(out, digest)
+		}
+	}
+	if ending {
+		resolved := *d.active
+		endsAt := now
+		resolved.Status = core.StatusResolved
+		resolved.EndsAt = &endsAt
+		out = append(out, &resolved)
+
+		d.logger.Info("Alert storm ended",
+			"started", d.active.StartsAt,
+			"duration", now.Sub(d.active.StartsAt))
+		d.active = nil
+		if d.config.Metrics != nil {
+			d.config.Metrics.RecordStormTransition("exited")
+		}
+	}
+	return out
+}
+
+// digest builds a digest of the held back groups and starts a new interval.
+// Returns nil when nothing was held back.
+func (d *StormDetector) digest(now time.Time) *core.Alert {
+	groups := d.groups
+	since := d.digestStart
+	d.groups = make(map[string]int)
+	d.paged = make(map[string]bool)
+	d.digestStart = now
+	if len(groups) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(groups))
+	total := 0
+	for key, count := range groups {
+		keys = append(keys, key)
+		total += count
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if groups[keys[i]] != groups[keys[j]] {
+			return groups[keys[i]] > groups[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	var b strings.Builder
+	for i, key := range keys {
+		if i == stormDigestMaxGroups {
+			fmt.Fprintf(&b, "… and %d more groups\n", len(keys)-i)
+			break
+		}
+		fmt.Fprintf(&b, "• %s: %d\n", key, groups[key])
+	}
+
+	d.digestSeq++
+	seq := strconv.Itoa(d.digestSeq)
+	id := d.active.Labels[StormLabel]
+	endsAt := now.Add(d.config.DigestInterval)
+	if d.config.Metrics != nil {
+		d.config.Metrics.RecordStormDigest(total)
+	}
+	return &core.Alert{
+		AlertName: StormDigestAlertName,
+		Status:    core.StatusFiring,
+		Labels: map[string]string{
+			"alertname": StormDigestAlertName,
+			"severity":  "info",
+			StormLabel:  id,
+			"digest":    seq,
+		},
+		Annotations: map[string]string{
+			"summary":     fmt.Sprintf("Alert storm digest: %d alerts in %d groups since %s", total, len(keys), since.UTC().Format(time.RFC3339)),
+			"description": strings.TrimSuffix(b.String(), "\n"),
+		},
+		StartsAt:    now,
+		EndsAt:      &endsAt,
+		Fingerprint: "storm-" + id + "-digest-" + seq,
+	}
+}
+
+// roll closes the rate windows that ended before now. The baseline only
+// learns from windows outside storms; during a storm, calmSince tracks when
+// the rate dropped below the threshold.
+func (d *StormDetector) roll(now time.Time) {
+	if d.windowStart.IsZero() {
+		d.windowStart = now
+		return
+	}
+	elapsed := int(now.Sub(d.windowStart) / d.config.Window)
+	for i := 0; i < elapsed && i < stormMaxRolledWindows; i++ {
+		count := float64(d.windowCount)
+		end := d.windowStart.Add(d.config.Window)
+		if d.active == nil {
+			if d.hasBaseline {
+				d.baseline += stormBaselineSmoothing * (count - d.baseline)
+			} else {
+				d.baseline = count
+				d.hasBaseline = true
+			}
+		} else if count < d.threshold() {
+			if d.calmSince.IsZero() {
+				d.calmSince = end
+			}
+		} else {
+			d.calmSince = time.Time{}
+		}
+		if d.config.Metrics != nil {
+			d.config.Metrics.SetStormRates(count, d.baseline)
+		}
+		d.windowStart = end
+		d.windowCount = 0
+	}
+	if elapsed > stormMaxRolledWindows {
+		d.windowStart = d.windowStart.Add(time.Duration(elapsed-stormMaxRolledWindows) * d.config.Window)
+	}
+}
+
+// threshold is the window alert count that starts (or sustains) a storm.
+func (d *StormDetector) threshold() float64 {
+	threshold := d.config.Multiplier * d.baseline
+	if minAlerts := float64(d.config.MinAlerts); threshold < minAlerts {
+		threshold = minAlerts
+	}
+	return threshold
+}
+
+// groupKey identifies the storm group of an alert, e.g. "alertname=DiskFull".
+func (d *StormDetector) groupKey(labels map[string]string) string {
+	parts := make([]string, 0, len(d.config.GroupBy))
+	for _, name := range d.config.GroupBy {
+		parts = append(parts, name+"="+labels[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (d *StormDetector) nonCriticalText() string {
+	return "non-" + strings.Join(d.config.CriticalSeverities, "/")
+}
+
+func (d *StormDetector) recordSuppressed(reason string) {
+	if d.config.Metrics != nil {
+		d.config.Metrics.RecordStormSuppressed(reason)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+func stormAlert(name, severity string, i int, status core.AlertStatus) *core.Alert {
+	return &core.Alert{
+		Fingerprint: fmt.Sprintf("fp-%s-%d", name, i),
+		AlertName:   name,
+		Status:      status,
+		Labels:      map[string]string{"alertname": name, "severity": severity, "instance": fmt.Sprint(i)},
+		StartsAt:    time.Now(),
+	}
+}
+
+func newTestStormDetector(fake *clock.Fake) *StormDetector {
+	return NewStormDetector(StormDetectorConfig{
+		Window:         time.Minute,
+		Multiplier:     3,
+		MinAlerts:      10,
+		Cooldown:       2 * time.Minute,
+		DigestInterval: 5 * time.Minute,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:          fake,
+	})
+}
+
+func TestStormDetector_EntersAndExitsStormMode(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+	detector := newTestStormDetector(fake)
+
+	// Baseline: 2 alerts per minute, so the threshold is max(10, 3*2).
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, detector.Count(stormAlert("Warmup", "warning", i, core.StatusFiring)))
+	}
+	fake.Advance(time.Minute)
+
+	var storm *core.Alert
+	for i := 0; i < 10; i++ {
+		if s := detector.Count(stormAlert("DiskFull", "warning", i, core.StatusFiring)); s != nil {
+			require.Nil(t, storm, "storm starts once")
+			storm = s
+		}
+	}
+	require.NotNil(t, storm)
+	assert.Equal(t, StormAlertName, storm.AlertName)
+	assert.Equal(t, core.StatusFiring, storm.Status)
+	assert.True(t, detector.Active())
+
+	obs := detector.Observe(stormAlert("DiskFull", "warning", 1, core.StatusFiring))
+	assert.True(t, obs.Suppressed)
+	assert.Equal(t, "digested", obs.Reason)
+
+	obs = detector.Observe(stormAlert("NodeDown", "critical", 1, core.StatusFiring))
+	assert.False(t, obs.Suppressed, "first critical alert of a group is paged")
+	obs = detector.Observe(stormAlert("NodeDown", "critical", 2, core.StatusFiring))
+	assert.True(t, obs.Suppressed)
+	assert.Equal(t, "grouped", obs.Reason)
+
+	obs = detector.Observe(stormAlert("DiskFull", "warning", 1, core.StatusResolved))
+	assert.True(t, obs.Suppressed, "resolution of a digested alert is dropped")
+	obs = detector.Observe(stormAlert("NodeDown", "critical", 1, core.StatusResolved))
+	assert.False(t, obs.Suppressed, "resolution of a paged alert is published")
+
+	// The storm window closes above the threshold, the next one is calm.
+	fake.Advance(time.Minute)
+	assert.Empty(t, detector.Tick())
+	fake.Advance(time.Minute)
+	assert.Empty(t, detector.Tick())
+	assert.True(t, detector.Active(), "cooldown not over yet")
+
+	fake.Advance(2 * time.Minute)
+	out := detector.Tick()
+	require.Len(t, out, 2)
+	assert.Equal(t, StormDigestAlertName, out[0].AlertName)
+	assert.Equal(t, "• alertname=DiskFull: 1\n• alertname=NodeDown: 1", out[0].Annotations["description"])
+	assert.Equal(t, StormAlertName, out[1].AlertName)
+	assert.Equal(t, core.StatusResolved, out[1].Status)
+	assert.False(t, detector.Active())
+
+	obs = detector.Observe(stormAlert("DiskFull", "warning", 3, core.StatusFiring))
+	assert.False(t, obs.Suppressed)
+}
+
+func TestStormDetector_PeriodicDigest(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+	detector := newTestStormDetector(fake)
+
+	// No baseline yet: MinAlerts alone starts the storm.
+	for i := 0; i < 10; i++ {
+		detector.Count(stormAlert("DiskFull", "warning", i, core.StatusFiring))
+	}
+	require.True(t, detector.Active())
+
+	for minute := 0; minute < 5; minute++ {
+		for i := 0; i < 20; i++ {
+			alert := stormAlert("DiskFull", "warning", minute*100+i, core.StatusFiring)
+			detector.Count(alert)
+			detector.Observe(alert)
+		}
+		fake.Advance(time.Minute)
+		out := detector.Tick()
+		if minute < 4 {
+			assert.Empty(t, out)
+			continue
+		}
+		require.Len(t, out, 1, "digest after the digest interval, storm continues")
+		assert.Equal(t, "Alert storm digest: 100 alerts in 1 groups since 2026-03-16T09:00:00Z", out[0].Annotations["summary"])
+		assert.Equal(t, "1", out[0].Labels["digest"])
+	}
+	assert.True(t, detector.Active())
+}
+
+func TestAlertProcessor_StormMode(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+	detector := newTestStormDetector(fake)
+
+	publisher := &recordingPublisher{}
+	collector := &traceCollector{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine:  blockByNameFilter(""),
+		Publisher:     publisher,
+		StormDetector: detector,
+		DecisionLog:   collector,
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 12; i++ {
+		require.NoError(t, processor.ProcessAlert(ctx, stormAlert("DiskFull", "warning", i, core.StatusFiring)))
+	}
+
+	// Nine alerts before the storm, the AlertStorm notification, then the
+	// alert that started the storm and the rest are held back.
+	require.Len(t, publisher.published, 10)
+	assert.Equal(t, StormAlertName, publisher.published[9].AlertName)
+
+	last := collector.traces[len(collector.traces)-1]
+	assert.Equal(t, core.DecisionResultStormSuppressed, last.Result)
+	assert.Equal(t, "alertname=DiskFull", last.Decisions[len(last.Decisions)-1].Details["group"])
+}
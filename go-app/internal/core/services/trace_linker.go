@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// TraceLinkerConfig configures the TraceLinker.
+type TraceLinkerConfig struct {
+	// TraceparentKeys, TraceIDKeys and SpanIDKeys are inspected in order,
+	// annotations before labels. Defaults: ["traceparent"],
+	// ["trace_id", "traceID"] and ["span_id", "spanID"].
+	TraceparentKeys []string
+	TraceIDKeys     []string
+	SpanIDKeys      []string
+
+	// URLTemplate renders the tracing UI link with .TraceID and .SpanID
+	// (optional, see ParseTraceURLTemplate).
+	URLTemplate *template.Template
+}
+
+// TraceLinker connects alerts raised from exemplars to their traces: the
+// alert's trace context is linked to the span processing the alert, and a
+// tracing UI link is added to the alert for notifications.
+type TraceLinker struct {
+	config TraceLinkerConfig
+}
+
+// traceURLData is the data the URL template is executed with.
+type traceURLData struct {
+	TraceID string
+	SpanID  string
+}
+
+// ParseTraceURLTemplate parses a tracing UI link template, e.g.
+// "https://tempo.example.com/trace/{{ .TraceID }}".
+func ParseTraceURLTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("trace_url").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace URL template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// NewTraceLinker creates a trace linker.
+func NewTraceLinker(config TraceLinkerConfig) *TraceLinker {
+	if len(config.TraceparentKeys) == 0 {
+		config.TraceparentKeys = []string{"traceparent"}
+	}
+	if len(config.TraceIDKeys) == 0 {
+		config.TraceIDKeys = []string{"trace_id", "traceID"}
+	}
+	if len(config.SpanIDKeys) == 0 {
+		config.SpanIDKeys = []string{"span_id", "spanID"}
+	}
+	return &TraceLinker{config: config}
+}
+
+// Extract returns the trace context carried by the alert. A W3C traceparent
+// wins over a bare trace ID. Without a span ID the returned span context
+// has a valid trace ID only.
+func (l *TraceLinker) Extract(alert *core.Alert) (trace.SpanContext, bool) {
+	if alert == nil {
+		return trace.SpanContext{}, false
+	}
+
+	if value := l.lookup(alert, l.config.TraceparentKeys); value != "" {
+		carrier := propagation.MapCarrier{"traceparent": value}
+		ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			return sc, true
+		}
+	}
+
+	traceID, err := trace.TraceIDFromHex(normalizeTraceHex(l.lookup(alert, l.config.TraceIDKeys), 32))
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	cfg := trace.SpanContextConfig{TraceID: traceID, Remote: true}
+	if spanID, err := trace.SpanIDFromHex(normalizeTraceHex(l.lookup(alert, l.config.SpanIDKeys), 16)); err == nil {
+		cfg.SpanID = spanID
+	}
+	return trace.NewSpanContext(cfg), true
+}
+
+// Link extracts the alert's trace context and, when a URL template is
+// configured, stores the tracing UI link in the trace_url annotation. An
+// existing trace_url annotation is kept.
+func (l *TraceLinker) Link(alert *core.Alert) (trace.SpanContext, bool) {
+	sc, ok := l.Extract(alert)
+	if !ok || l.config.URLTemplate == nil || alert.Annotations[core.TraceURLAnnotation] != "" {
+		return sc, ok
+	}
+
+	data := traceURLData{TraceID: sc.TraceID().String()}
+	if sc.HasSpanID() {
+		data.SpanID = sc.SpanID().String()
+	}
+	var buf bytes.Buffer
+	if err := l.config.URLTemplate.Execute(&buf, data); err != nil {
+		return sc, ok
+	}
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
+	}
+	alert.Annotations[core.TraceURLAnnotation] = buf.String()
+	return sc, ok
+}
+
+func (l *TraceLinker) lookup(alert *core.Alert, keys []string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(alert.Annotations[key]); value != "" {
+			return value
+		}
+	}
+	for _, key := range keys {
+		if value := strings.TrimSpace(alert.Labels[key]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalizeTraceHex lower-cases a hex ID and left-pads shorter IDs, e.g.
+// 64-bit Jaeger trace IDs, to width.
+func normalizeTraceHex(id string, width int) string {
+	id = strings.ToLower(id)
+	if id == "" || len(id) >= width {
+		return id
+	}
+	return strings.Repeat("0", width-len(id)) + id
+}
+
+// alertProcessorTracer is the instrumentation name of alert processing spans.
+const alertProcessorTracer = "github.com/ipiton/AMP/internal/core/services"
+
+// startAlertSpan starts the span covering the processing of an alert. When
+// the alert carries a trace context, the span links to it and the alert gets
+// its tracing UI link.
+func (p *AlertProcessor) startAlertSpan(ctx context.Context, alert *core.Alert) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			attribute.String("alert.name", alert.AlertName),
+			attribute.String("alert.fingerprint", alert.Fingerprint),
+			attribute.String("alert.status", string(alert.Status)),
+		),
+	}
+	if p.traceLinker != nil {
+		if sc, ok := p.traceLinker.Link(alert); ok {
+			// A link without a span ID is only kept with attributes.
+			opts = append(opts,
+				trace.WithLinks(trace.Link{
+					SpanContext: sc,
+					Attributes:  []attribute.KeyValue{attribute.String("link.source", "alert")},
+				}),
+				trace.WithAttributes(attribute.String("alert.trace_id", sc.TraceID().String())))
+		}
+	}
+	return otel.Tracer(alertProcessorTracer).Start(ctx, "ProcessAlert", opts...)
+}
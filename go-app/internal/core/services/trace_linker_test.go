@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func tracedAlert(annotations, labels map[string]string) *core.Alert {
+	return &core.Alert{
+		Fingerprint: "fp-traced",
+		AlertName:   "HighLatency",
+		Status:      core.StatusFiring,
+		Labels:      labels,
+		Annotations: annotations,
+		StartsAt:    time.Now(),
+	}
+}
+
+func TestTraceLinker_Extract(t *testing.T) {
+	linker := NewTraceLinker(TraceLinkerConfig{})
+
+	tests := []struct {
+		name        string
+		alert       *core.Alert
+		wantOK      bool
+		wantTraceID string
+		wantSpanID  string
+	}{
+		{
+			name: "traceparent annotation",
+			alert: tracedAlert(map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			}, nil),
+			wantOK:      true,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+		},
+		{
+			name: "trace and span ID annotations",
+			alert: tracedAlert(map[string]string{
+				"trace_id": "4BF92F3577B34DA6A3CE929D0E0E4736",
+				"span_id":  "00f067aa0ba902b7",
+			}, nil),
+			wantOK:      true,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+		},
+		{
+			name:        "64-bit trace ID label",
+			alert:       tracedAlert(nil, map[string]string{"traceID": "a3ce929d0e0e4736"}),
+			wantOK:      true,
+			wantTraceID: "0000000000000000a3ce929d0e0e4736",
+		},
+		{
+			name: "invalid traceparent falls back to trace_id",
+			alert: tracedAlert(map[string]string{
+				"traceparent": "garbage",
+				"trace_id":    "4bf92f3577b34da6a3ce929d0e0e4736",
+			}, nil),
+			wantOK:      true,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:   "invalid trace ID",
+			alert:  tracedAlert(map[string]string{"trace_id": "not-hex"}, nil),
+			wantOK: false,
+		},
+		{
+			name:   "no trace context",
+			alert:  tracedAlert(map[string]string{"summary": "slow"}, map[string]string{"alertname": "HighLatency"}),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := linker.Extract(tt.alert)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantTraceID, sc.TraceID().String())
+			assert.Equal(t, tt.wantSpanID != "", sc.HasSpanID())
+			if tt.wantSpanID != "" {
+				assert.Equal(t, tt.wantSpanID, sc.SpanID().String())
+			}
+		})
+	}
+}
+
+func TestTraceLinker_LinkAddsTraceURL(t *testing.T) {
+	tmpl, err := ParseTraceURLTemplate("https://tempo.example.com/trace/{{ .TraceID }}?span={{ .SpanID }}")
+	require.NoError(t, err)
+	linker := NewTraceLinker(TraceLinkerConfig{URLTemplate: tmpl})
+
+	alert := tracedAlert(nil, map[string]string{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736"})
+	_, ok := linker.Link(alert)
+	require.True(t, ok)
+	assert.Equal(t, "https://tempo.example.com/trace/4bf92f3577b34da6a3ce929d0e0e4736?span=",
+		alert.Annotations[core.TraceURLAnnotation])
+
+	// A link set by the alerting rule itself is kept.
+	alert = tracedAlert(map[string]string{
+		"trace_id":              "4bf92f3577b34da6a3ce929d0e0e4736",
+		core.TraceURLAnnotation: "https://custom/trace",
+	}, nil)
+	linker.Link(alert)
+	assert.Equal(t, "https://custom/trace", alert.Annotations[core.TraceURLAnnotation])
+
+	alert = tracedAlert(map[string]string{"summary": "slow"}, nil)
+	linker.Link(alert)
+	assert.NotContains(t, alert.Annotations, core.TraceURLAnnotation)
+}
+
+func TestParseTraceURLTemplate_Invalid(t *testing.T) {
+	_, err := ParseTraceURLTemplate("https://tempo/{{ .TraceID ")
+	assert.Error(t, err)
+}
+
+func TestAlertProcessor_LinksAlertTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tmpl, err := ParseTraceURLTemplate("https://tempo.example.com/trace/{{ .TraceID }}")
+	require.NoError(t, err)
+	publisher := &recordingPublisher{}
+	processor, err := NewAlertProcessor(AlertProcessorConfig{
+		FilterEngine: blockByNameFilter(""),
+		Publisher:    publisher,
+		TraceLinker:  NewTraceLinker(TraceLinkerConfig{URLTemplate: tmpl}),
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, err)
+
+	alert := tracedAlert(map[string]string{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}, map[string]string{"alertname": "HighLatency"})
+	require.NoError(t, processor.ProcessAlert(context.Background(), alert))
+
+	require.Len(t, publisher.published, 1)
+	assert.Equal(t, "https://tempo.example.com/trace/4bf92f3577b34da6a3ce929d0e0e4736",
+		publisher.published[0].Annotations[core.TraceURLAnnotation])
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "ProcessAlert", spans[0].Name)
+	require.Len(t, spans[0].Links, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].Links[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Links[0].SpanContext.SpanID().String())
+}
@@ -0,0 +1,51 @@
+package core
+
+import "github.com/ipiton/AMP/pkg/core/domain"
+
+// Enrichment metadata keys recording how the effective severity of an alert
+// was chosen (see ApplySeverityPolicy).
+const (
+	MetadataEffectiveSeverity = "effective_severity"
+	MetadataSeveritySource    = "severity_source"
+	MetadataSeverityPolicy    = "severity_policy"
+)
+
+// ApplySeverityPolicy resolves the effective severity under policy and
+// records it, its source and the policy in EnrichmentMetadata, so that
+// publishers, traces and stored alerts show which severity won and why.
+func (e *EnrichedAlert) ApplySeverityPolicy(policy domain.SeverityPolicy) AlertSeverity {
+	severity, source := e.resolveSeverity(policy)
+	if e.EnrichmentMetadata == nil {
+		e.EnrichmentMetadata = make(map[string]any, 3)
+	}
+	e.EnrichmentMetadata[MetadataEffectiveSeverity] = string(severity)
+	e.EnrichmentMetadata[MetadataSeveritySource] = string(source)
+	e.EnrichmentMetadata[MetadataSeverityPolicy] = string(policy)
+	return severity
+}
+
+// EffectiveSeverity returns the severity recorded by ApplySeverityPolicy
+// and its source, or resolves it under the default policy when no policy
+// was applied.
+func (e *EnrichedAlert) EffectiveSeverity() (AlertSeverity, domain.SeveritySource) {
+	if severity, ok := e.EnrichmentMetadata[MetadataEffectiveSeverity].(string); ok {
+		source, _ := e.EnrichmentMetadata[MetadataSeveritySource].(string)
+		return AlertSeverity(severity), domain.SeveritySource(source)
+	}
+	return e.resolveSeverity(domain.DefaultSeverityPolicy)
+}
+
+func (e *EnrichedAlert) resolveSeverity(policy domain.SeverityPolicy) (AlertSeverity, domain.SeveritySource) {
+	var classification domain.AlertSeverity
+	if e.Classification != nil {
+		classification = domain.AlertSeverity(e.Classification.Severity)
+	}
+	var label string
+	if e.Alert != nil {
+		if sev := e.Alert.Severity(); sev != nil {
+			label = *sev
+		}
+	}
+	severity, source := domain.ResolveSeverity(policy, classification, label)
+	return AlertSeverity(severity), source
+}
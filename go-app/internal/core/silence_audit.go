@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SilenceAuditAction is the kind of change recorded in the silence audit trail.
+type SilenceAuditAction string
+
+const (
+	SilenceAuditCreate SilenceAuditAction = "create"
+	SilenceAuditUpdate SilenceAuditAction = "update"
+	SilenceAuditExpire SilenceAuditAction = "expire"
+	SilenceAuditDelete SilenceAuditAction = "delete"
+)
+
+// SilenceAuditEvent is one append-only entry of a silence's change history.
+type SilenceAuditEvent struct {
+	ID        int64              `json:"id"`
+	SilenceID string             `json:"silenceID"`
+	Action    SilenceAuditAction `json:"action"`
+	// Actor is the API token name when authenticated, otherwise the
+	// silence's createdBy. Empty when unknown.
+	Actor     string    `json:"actor"`
+	Timestamp time.Time `json:"timestamp"`
+	// Reason explains changes not made directly by the actor, e.g. a silence
+	// expired because an update replaced it with a new one.
+	Reason  string               `json:"reason,omitempty"`
+	Before  *APISilence          `json:"before,omitempty"`
+	After   *APISilence          `json:"after,omitempty"`
+	Changes []SilenceFieldChange `json:"changes,omitempty"`
+}
+
+// SilenceFieldChange is a field that differs between the before and after
+// state of a silence.
+type SilenceFieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// SilenceAuditStore is the append-only storage of silence audit events.
+type SilenceAuditStore interface {
+	// AppendSilenceAudit stores event and sets its ID.
+	AppendSilenceAudit(ctx context.Context, event *SilenceAuditEvent) error
+	// ListSilenceAudit returns the events of a silence, oldest first.
+	ListSilenceAudit(ctx context.Context, silenceID string) ([]SilenceAuditEvent, error)
+}
+
+// NewSilenceAuditEvent builds an event for a change from before to after
+// (either may be nil) and computes the field diff.
+func NewSilenceAuditEvent(silenceID string, action SilenceAuditAction, actor string, before, after *APISilence, at time.Time) SilenceAuditEvent {
+	return SilenceAuditEvent{
+		SilenceID: silenceID,
+		Action:    action,
+		Actor:     actor,
+		Timestamp: at.UTC(),
+		Before:    before,
+		After:     after,
+		Changes:   DiffSilences(before, after),
+	}
+}
+
+// DiffSilences lists the user-visible fields that differ between two silence
+// states. A nil side is treated as empty.
+func DiffSilences(before, after *APISilence) []SilenceFieldChange {
+	var b, a APISilence
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"matchers", FormatSilenceMatchers(b.Matchers), FormatSilenceMatchers(a.Matchers)},
+		{"startsAt", b.StartsAt, a.StartsAt},
+		{"endsAt", b.EndsAt, a.EndsAt},
+		{"createdBy", b.CreatedBy, a.CreatedBy},
+		{"comment", b.Comment, a.Comment},
+		{"notifyOnExpiry", b.NotifyOnExpiry, a.NotifyOnExpiry},
+		{"requireLabelPresence", strconv.FormatBool(b.RequireLabelPresence), strconv.FormatBool(a.RequireLabelPresence)},
+	}
+
+	var changes []SilenceFieldChange
+	for _, f := range fields {
+		if f.before != f.after {
+			changes = append(changes, SilenceFieldChange{Field: f.name, Before: f.before, After: f.after})
+		}
+	}
+	return changes
+}
+
+// FormatSilenceMatchers renders matchers in PromQL selector form, e.g.
+// {env="prod", job=~"api.*"}.
+func FormatSilenceMatchers(matchers []APISilenceMatcher) string {
+	if len(matchers) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		op := "="
+		switch {
+		case m.IsRegex && m.IsEqual:
+			op = "=~"
+		case m.IsRegex:
+			op = "!~"
+		case !m.IsEqual:
+			op = "!="
+		}
+		parts = append(parts, fmt.Sprintf("%s%s%q", m.Name, op, m.Value))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,23 @@
+package core
+
+// SilenceOverlapRelation describes how an existing silence relates to a new
+// one with an overlapping time range.
+type SilenceOverlapRelation string
+
+const (
+	// SilenceOverlapDuplicate means both silences match the same alerts.
+	SilenceOverlapDuplicate SilenceOverlapRelation = "duplicate"
+	// SilenceOverlapBroader means the existing silence already matches every
+	// alert the new one matches.
+	SilenceOverlapBroader SilenceOverlapRelation = "broader"
+	// SilenceOverlapNarrower means the new silence matches every alert the
+	// existing one matches.
+	SilenceOverlapNarrower SilenceOverlapRelation = "narrower"
+)
+
+// SilenceOverlap is an existing silence that subsumes or is subsumed by a
+// new silence during the new silence's time range.
+type SilenceOverlap struct {
+	Relation SilenceOverlapRelation `json:"relation"`
+	Silence  APISilence             `json:"silence"`
+}
@@ -0,0 +1,23 @@
+package core
+
+import "errors"
+
+var (
+	// ErrSilencePolicyViolation is returned when a silence breaks the silence
+	// policy, e.g. it is too long for its creator or its comment lacks a ticket ID.
+	ErrSilencePolicyViolation = errors.New("silence policy violation")
+	// ErrSilenceApprovalRequired is returned when a silence matches a protected
+	// label set and needs the approval of a second person.
+	ErrSilenceApprovalRequired = errors.New("silence requires approval")
+)
+
+// SilenceApprovalRequest is a silence held until a second person approves it.
+type SilenceApprovalRequest struct {
+	ID string `json:"id"`
+	// Silence is created as requested once approved.
+	Silence     SilenceInput `json:"silence"`
+	RequestedBy string       `json:"requestedBy"`
+	RequestedAt string       `json:"requestedAt"`
+	// Reason explains why approval is required.
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,10 @@
+package core
+
+import "time"
+
+// SilenceMatchStats counts the firing alerts a silence suppressed since the
+// process started. Every received notification of a silenced alert counts.
+type SilenceMatchStats struct {
+	MatchCount    uint64     `json:"matchCount"`
+	LastMatchedAt *time.Time `json:"lastMatchedAt,omitempty"`
+}
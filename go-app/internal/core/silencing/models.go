@@ -70,6 +70,10 @@ type Silence struct {
 	// UpdatedAt is the timestamp of the last update to this silence.
 	// Nil if the silence has never been updated.
 	UpdatedAt *time.Time `json:"updatedAt,omitempty" db:"updated_at"`
+
+	// RequireLabelPresence makes every matcher fail on a missing or empty
+	// label instead of treating it as an empty value (Alertmanager default).
+	RequireLabelPresence bool `json:"requireLabelPresence,omitempty" db:"require_label_presence"`
 }
 
 // SilenceStatus represents the state of a silence.
@@ -105,7 +109,14 @@ const (
 //
 //	silence.Status = silence.CalculateStatus()
 func (s *Silence) CalculateStatus() SilenceStatus {
-	now := time.Now()
+	return s.StatusAt(time.Now())
+}
+
+// StatusAt returns the status of the silence at the given instant.
+//
+// Use it instead of CalculateStatus when the current time comes from an
+// injected clock (see pkg/clock).
+func (s *Silence) StatusAt(now time.Time) SilenceStatus {
 	if now.Before(s.StartsAt) {
 		return SilenceStatusPending
 	}
@@ -0,0 +1,77 @@
+package silencing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday
+	base := time.Date(2026, 3, 11, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2026, 3, 11, 10, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2026, 3, 11, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 22 * * 0", want: time.Date(2026, 3, 15, 22, 0, 0, 0, time.UTC)},
+		{expr: "0 22 * * 7", want: time.Date(2026, 3, 15, 22, 0, 0, 0, time.UTC)},
+		{expr: "0 9 * * 1-5", want: time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)},
+		{expr: "30 10 * * *", want: time.Date(2026, 3, 12, 10, 30, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", want: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 12 20 * 4", want: time.Date(2026, 3, 12, 12, 0, 0, 0, time.UTC)}, // the 20th or a Thursday
+		{expr: "0,30 8-9 * * *", want: time.Date(2026, 3, 12, 8, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", want: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{expr: "@hourly", want: time.Date(2026, 3, 11, 11, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCronSchedule(tt.expr, nil)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(s.Next(base)), "got %s, want %s", s.Next(base), tt.want)
+		})
+	}
+}
+
+func TestCronSchedule_NextInLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	s, err := ParseCronSchedule("0 22 * * 0", berlin)
+	require.NoError(t, err)
+
+	// Sunday 20:00 UTC is 22:00 CET.
+	next := s.Next(time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC))
+	assert.True(t, next.Equal(time.Date(2026, 3, 15, 21, 0, 0, 0, time.UTC)), "got %s", next.UTC())
+}
+
+func TestCronSchedule_NeverMatches(t *testing.T) {
+	s, err := ParseCronSchedule("0 0 31 2 *", nil)
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParseCronSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCronSchedule(expr, nil)
+		assert.Error(t, err, "expr %q", expr)
+	}
+}
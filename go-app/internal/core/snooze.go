@@ -0,0 +1,26 @@
+package core
+
+import "time"
+
+// SnoozeChecker reports whether a user has muted notifications for an alert
+// fingerprint. Implemented by memory.SnoozeStore and consulted by chat
+// publishers before mentioning a user.
+type SnoozeChecker interface {
+	IsSnoozed(userID, fingerprint string, now time.Time) bool
+}
+
+// SnoozeInput is the payload for snoozing an alert for one user.
+type SnoozeInput struct {
+	// User is the chat user ID the snooze applies to, e.g. a Slack member ID.
+	User        string `json:"user"`
+	Fingerprint string `json:"fingerprint"`
+	// Duration is how long the snooze lasts as a Go duration, e.g. "2h".
+	Duration string `json:"duration"`
+}
+
+// APISnooze represents a personal snooze in the API.
+type APISnooze struct {
+	User        string `json:"user"`
+	Fingerprint string `json:"fingerprint"`
+	Until       string `json:"until"`
+}
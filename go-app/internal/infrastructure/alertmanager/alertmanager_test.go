@@ -0,0 +1,125 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestSilenceSnapshot_RoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	silences := []core.APISilence{
+		{
+			ID: "a1",
+			Matchers: []core.APISilenceMatcher{
+				{Name: "alertname", Value: "DiskFull", IsEqual: true},
+				{Name: "instance", Value: "db-.*", IsRegex: true, IsEqual: true},
+				{Name: "env", Value: "dev", IsEqual: false},
+			},
+			StartsAt:  now.Add(-time.Hour).Format(time.RFC3339),
+			EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+			UpdatedAt: now.Add(-time.Hour).Format(time.RFC3339),
+			CreatedBy: "ops",
+			Comment:   "maintenance",
+		},
+		{
+			ID:        "a2",
+			Matchers:  []core.APISilenceMatcher{{Name: "job", Value: "batch", IsEqual: true}},
+			StartsAt:  now.Add(-2 * time.Hour).Format(time.RFC3339),
+			EndsAt:    now.Add(-time.Hour).Format(time.RFC3339),
+			UpdatedAt: now.Add(-time.Hour).Format(time.RFC3339),
+			CreatedBy: "ops",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSilenceSnapshot(&buf, silences, 120*time.Hour))
+
+	got, err := ReadSilenceSnapshot(&buf, now)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	silences[0].Status.State = "active"
+	silences[1].Status.State = "expired"
+	assert.Equal(t, silences, got)
+}
+
+func TestReadSilenceSnapshot_LegacyComments(t *testing.T) {
+	var comment, silence, mesh []byte
+	comment = appendString(comment, commentAuthor, "alice")
+	comment = appendString(comment, commentComment, "old format")
+	silence = appendString(silence, silenceID, "legacy")
+	silence = appendMessage(silence, silenceComments, comment)
+	silence = appendMessage(silence, silenceEndsAt, encodeTimestamp(time.Unix(2000, 0)))
+	mesh = appendMessage(mesh, meshSilenceSilence, silence)
+	data := append(protowire.AppendVarint(nil, uint64(len(mesh))), mesh...)
+
+	got, err := ReadSilenceSnapshot(bytes.NewReader(data), time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "alice", got[0].CreatedBy)
+	assert.Equal(t, "old format", got[0].Comment)
+	assert.Equal(t, "active", got[0].Status.State)
+
+	_, err = ReadSilenceSnapshot(bytes.NewReader(data[:len(data)-3]), time.Unix(1000, 0))
+	assert.Error(t, err, "truncated snapshot")
+}
+
+func TestClient_Silences(t *testing.T) {
+	var created core.SilenceInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/am/api/v2/silences", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[{"id":"s1","matchers":[{"name":"job","value":"api","isRegex":false,"isEqual":true}],
+				"startsAt":"2026-03-16T08:00:00Z","endsAt":"2026-03-16T10:00:00Z","createdBy":"ops","comment":"c","status":{"state":"active"}}]`))
+		case http.MethodPost:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"silenceID":"new-id"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{URL: server.URL + "/am/"})
+	require.NoError(t, err)
+
+	silences, err := client.ListSilences(context.Background())
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	assert.Equal(t, "active", silences[0].Status.State)
+
+	id, err := client.CreateSilence(context.Background(), core.SilenceInput{CreatedBy: "amp", Comment: "migrated"})
+	require.NoError(t, err)
+	assert.Equal(t, "new-id", id)
+	assert.Equal(t, "migrated", created.Comment)
+}
+
+func TestClient_ListAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/alerts", r.URL.Path)
+		_, _ = w.Write([]byte(`[{"labels":{"alertname":"NodeDown","node":"n1"},"annotations":{},"receivers":[{"name":"pager"}],
+			"startsAt":"2026-03-16T08:00:00Z","updatedAt":"2026-03-16T08:01:00Z","endsAt":"2026-03-16T08:05:00Z",
+			"fingerprint":"abc","status":{"state":"suppressed","silencedBy":["s1"],"inhibitedBy":[]}}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{URL: server.URL})
+	require.NoError(t, err)
+
+	alerts, err := client.ListAlerts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "abc", alerts[0].Fingerprint)
+	assert.Equal(t, "suppressed", alerts[0].Status.State)
+	assert.Equal(t, "n1", alerts[0].Labels["node"])
+}
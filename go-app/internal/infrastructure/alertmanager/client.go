@@ -0,0 +1,123 @@
+// Package alertmanager talks to a running Prometheus Alertmanager and reads
+// and writes its silence snapshot files, for migrating silences and
+// inhibition sources between Alertmanager and AMP.
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Config configures the Client.
+type Config struct {
+	// URL is the Alertmanager base URL, e.g. http://alertmanager:9093.
+	// Credentials in the URL are sent as basic auth.
+	URL string
+	// Timeout bounds a single request (default: 10s).
+	Timeout time.Duration
+	// HTTPClient (default: a client with Timeout).
+	HTTPClient *http.Client
+}
+
+// Client is a minimal Alertmanager API v2 client for silences and alerts.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Alertmanager at config.URL.
+func NewClient(config Config) (*Client, error) {
+	base, err := url.Parse(strings.TrimSpace(config.URL))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid alertmanager url %q", config.URL)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(base.String(), "/"),
+		httpClient: config.HTTPClient,
+	}, nil
+}
+
+// ListSilences returns all silences known to Alertmanager, including expired
+// ones.
+func (c *Client) ListSilences(ctx context.Context) ([]core.APISilence, error) {
+	var silences []core.APISilence
+	if err := c.do(ctx, http.MethodGet, "/api/v2/silences", nil, &silences); err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+// ListAlerts returns the alerts known to Alertmanager, including silenced
+// and inhibited ones.
+func (c *Client) ListAlerts(ctx context.Context) ([]core.APIGettableAlert, error) {
+	var alerts []core.APIGettableAlert
+	if err := c.do(ctx, http.MethodGet, "/api/v2/alerts", nil, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// CreateSilence creates a silence and returns its Alertmanager ID.
+func (c *Client) CreateSilence(ctx context.Context, in core.SilenceInput) (string, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode silence: %w", err)
+	}
+	var resp struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v2/silences", body, &resp); err != nil {
+		return "", err
+	}
+	return resp.SilenceID, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build alertmanager request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("alertmanager request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 32*1024*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read alertmanager response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alertmanager %s %s returned HTTP %d: %s",
+			method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid alertmanager response: %w", err)
+	}
+	return nil
+}
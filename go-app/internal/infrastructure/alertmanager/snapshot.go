@@ -0,0 +1,364 @@
+package alertmanager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// maxSnapshotEntry bounds a single silence entry of a snapshot file.
+const maxSnapshotEntry = 1 << 20
+
+// Field numbers of Alertmanager's silencepb messages. The snapshot file
+// (--storage.path/silences) is a sequence of length-delimited MeshSilence
+// messages.
+const (
+	meshSilenceSilence   protowire.Number = 1
+	meshSilenceExpiresAt protowire.Number = 2
+
+	silenceID        protowire.Number = 1
+	silenceMatchers  protowire.Number = 2
+	silenceStartsAt  protowire.Number = 3
+	silenceEndsAt    protowire.Number = 4
+	silenceUpdatedAt protowire.Number = 5
+	silenceComments  protowire.Number = 7 // deprecated, used by old versions
+	silenceCreatedBy protowire.Number = 8
+	silenceComment   protowire.Number = 9
+
+	matcherType    protowire.Number = 1
+	matcherName    protowire.Number = 2
+	matcherPattern protowire.Number = 3
+
+	commentAuthor  protowire.Number = 1
+	commentComment protowire.Number = 2
+
+	timestampSeconds protowire.Number = 1
+	timestampNanos   protowire.Number = 2
+)
+
+// Matcher types of silencepb.Matcher.
+const (
+	matcherTypeEqual     = 0
+	matcherTypeRegexp    = 1
+	matcherTypeNotEqual  = 2
+	matcherTypeNotRegexp = 3
+)
+
+// ReadSilenceSnapshot decodes an Alertmanager silences snapshot file. Silence
+// states are computed relative to now.
+func ReadSilenceSnapshot(r io.Reader, now time.Time) ([]core.APISilence, error) {
+	br := bufio.NewReader(r)
+	var silences []core.APISilence
+	for {
+		size, err := readUvarint(br)
+		if errors.Is(err, io.EOF) {
+			return silences, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("snapshot entry %d: %w", len(silences), err)
+		}
+		if size > maxSnapshotEntry {
+			return nil, fmt.Errorf("snapshot entry %d: size %d exceeds limit", len(silences), size)
+		}
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(br, msg); err != nil {
+			return nil, fmt.Errorf("snapshot entry %d: %w", len(silences), err)
+		}
+		silence, err := decodeMeshSilence(msg, now)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot entry %d: %w", len(silences), err)
+		}
+		silences = append(silences, silence)
+	}
+}
+
+// WriteSilenceSnapshot encodes silences in the Alertmanager snapshot format,
+// so they can be loaded by an Alertmanager started on the file. Each silence
+// is retained by Alertmanager until retention after it ends.
+func WriteSilenceSnapshot(w io.Writer, silences []core.APISilence, retention time.Duration) error {
+	for _, s := range silences {
+		msg, err := encodeMeshSilence(s, retention)
+		if err != nil {
+			return fmt.Errorf("silence %s: %w", s.ID, err)
+		}
+		if _, err := w.Write(protowire.AppendVarint(nil, uint64(len(msg)))); err != nil {
+			return err
+		}
+		if _, err := w.Write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readUvarint(r io.ByteReader) (uint64, error) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			if shift > 0 && errors.Is(err, io.EOF) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		value |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid length prefix")
+}
+
+// walkFields calls fn for each field of a protobuf message. Only the
+// length-delimited value (bytes) or varint value (num) matching the wire type
+// is set.
+func walkFields(msg []byte, fn func(num protowire.Number, typ protowire.Type, bytes []byte, value uint64) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		var (
+			bytes []byte
+			value uint64
+		)
+		switch typ {
+		case protowire.BytesType:
+			bytes, n = protowire.ConsumeBytes(msg)
+		case protowire.VarintType:
+			value, n = protowire.ConsumeVarint(msg)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, msg)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		if err := fn(num, typ, bytes, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeMeshSilence(msg []byte, now time.Time) (core.APISilence, error) {
+	var (
+		silence core.APISilence
+		found   bool
+	)
+	err := walkFields(msg, func(num protowire.Number, typ protowire.Type, b []byte, _ uint64) error {
+		if num != meshSilenceSilence || typ != protowire.BytesType {
+			return nil
+		}
+		found = true
+		var err error
+		silence, err = decodeSilence(b, now)
+		return err
+	})
+	if err != nil {
+		return core.APISilence{}, err
+	}
+	if !found {
+		return core.APISilence{}, fmt.Errorf("entry has no silence")
+	}
+	return silence, nil
+}
+
+func decodeSilence(msg []byte, now time.Time) (core.APISilence, error) {
+	var (
+		silence                     core.APISilence
+		startsAt, endsAt, updatedAt time.Time
+		legacyAuthor, legacyComment string
+	)
+	err := walkFields(msg, func(num protowire.Number, typ protowire.Type, b []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		var err error
+		switch num {
+		case silenceID:
+			silence.ID = string(b)
+		case silenceMatchers:
+			var m core.APISilenceMatcher
+			m, err = decodeMatcher(b)
+			silence.Matchers = append(silence.Matchers, m)
+		case silenceStartsAt:
+			startsAt, err = decodeTimestamp(b)
+		case silenceEndsAt:
+			endsAt, err = decodeTimestamp(b)
+		case silenceUpdatedAt:
+			updatedAt, err = decodeTimestamp(b)
+		case silenceCreatedBy:
+			silence.CreatedBy = string(b)
+		case silenceComment:
+			silence.Comment = string(b)
+		case silenceComments:
+			err = walkFields(b, func(num protowire.Number, _ protowire.Type, b []byte, _ uint64) error {
+				switch num {
+				case commentAuthor:
+					legacyAuthor = string(b)
+				case commentComment:
+					legacyComment = string(b)
+				}
+				return nil
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return core.APISilence{}, err
+	}
+
+	if silence.CreatedBy == "" {
+		silence.CreatedBy = legacyAuthor
+	}
+	if silence.Comment == "" {
+		silence.Comment = legacyComment
+	}
+	silence.StartsAt = startsAt.UTC().Format(time.RFC3339)
+	silence.EndsAt = endsAt.UTC().Format(time.RFC3339)
+	silence.UpdatedAt = updatedAt.UTC().Format(time.RFC3339)
+
+	switch {
+	case now.Before(startsAt):
+		silence.Status.State = "pending"
+	case !now.Before(endsAt):
+		silence.Status.State = "expired"
+	default:
+		silence.Status.State = "active"
+	}
+	return silence, nil
+}
+
+func decodeMatcher(msg []byte) (core.APISilenceMatcher, error) {
+	var (
+		matcher core.APISilenceMatcher
+		kind    uint64
+	)
+	err := walkFields(msg, func(num protowire.Number, typ protowire.Type, b []byte, value uint64) error {
+		switch {
+		case num == matcherType && typ == protowire.VarintType:
+			kind = value
+		case num == matcherName:
+			matcher.Name = string(b)
+		case num == matcherPattern:
+			matcher.Value = string(b)
+		}
+		return nil
+	})
+	if err != nil {
+		return core.APISilenceMatcher{}, err
+	}
+
+	switch kind {
+	case matcherTypeEqual:
+		matcher.IsEqual = true
+	case matcherTypeRegexp:
+		matcher.IsEqual, matcher.IsRegex = true, true
+	case matcherTypeNotEqual:
+	case matcherTypeNotRegexp:
+		matcher.IsRegex = true
+	default:
+		return core.APISilenceMatcher{}, fmt.Errorf("unknown matcher type %d", kind)
+	}
+	return matcher, nil
+}
+
+func decodeTimestamp(msg []byte) (time.Time, error) {
+	var seconds, nanos int64
+	err := walkFields(msg, func(num protowire.Number, typ protowire.Type, _ []byte, value uint64) error {
+		if typ != protowire.VarintType {
+			return nil
+		}
+		switch num {
+		case timestampSeconds:
+			seconds = int64(value)
+		case timestampNanos:
+			nanos = int64(int32(value))
+		}
+		return nil
+	})
+	return time.Unix(seconds, nanos).UTC(), err
+}
+
+func encodeMeshSilence(s core.APISilence, retention time.Duration) ([]byte, error) {
+	startsAt, err := time.Parse(time.RFC3339, s.StartsAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startsAt: %w", err)
+	}
+	endsAt, err := time.Parse(time.RFC3339, s.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endsAt: %w", err)
+	}
+	updatedAt, err := time.Parse(time.RFC3339, s.UpdatedAt)
+	if err != nil {
+		updatedAt = startsAt
+	}
+
+	var silence []byte
+	silence = appendString(silence, silenceID, s.ID)
+	for _, m := range s.Matchers {
+		kind := uint64(matcherTypeEqual)
+		switch {
+		case m.IsRegex && m.IsEqual:
+			kind = matcherTypeRegexp
+		case m.IsRegex:
+			kind = matcherTypeNotRegexp
+		case !m.IsEqual:
+			kind = matcherTypeNotEqual
+		}
+		var matcher []byte
+		if kind != matcherTypeEqual {
+			matcher = protowire.AppendTag(matcher, matcherType, protowire.VarintType)
+			matcher = protowire.AppendVarint(matcher, kind)
+		}
+		matcher = appendString(matcher, matcherName, m.Name)
+		matcher = appendString(matcher, matcherPattern, m.Value)
+		silence = appendMessage(silence, silenceMatchers, matcher)
+	}
+	silence = appendMessage(silence, silenceStartsAt, encodeTimestamp(startsAt))
+	silence = appendMessage(silence, silenceEndsAt, encodeTimestamp(endsAt))
+	silence = appendMessage(silence, silenceUpdatedAt, encodeTimestamp(updatedAt))
+	silence = appendString(silence, silenceCreatedBy, s.CreatedBy)
+	silence = appendString(silence, silenceComment, s.Comment)
+
+	var mesh []byte
+	mesh = appendMessage(mesh, meshSilenceSilence, silence)
+	mesh = appendMessage(mesh, meshSilenceExpiresAt, encodeTimestamp(endsAt.Add(retention)))
+	return mesh, nil
+}
+
+func encodeTimestamp(t time.Time) []byte {
+	var b []byte
+	if seconds := t.Unix(); seconds != 0 {
+		b = protowire.AppendTag(b, timestampSeconds, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(seconds))
+	}
+	if nanos := t.Nanosecond(); nanos != 0 {
+		b = protowire.AppendTag(b, timestampNanos, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(nanos))
+	}
+	return b
+}
+
+func appendString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
@@ -17,10 +17,11 @@
 //	keyGen := NewGroupKeyGenerator() // from TN-122
 //
 //	manager, err := NewDefaultGroupManager(DefaultGroupManagerConfig{
-//	    KeyGenerator: keyGen,
-//	    Config:       config,
-//	    Logger:       slog.Default(),
-//	    Metrics:      businessMetrics,
+//	    KeyGenerator:   keyGen,
+//	    Config:         config,
+//	    Logger:         slog.Default(),
+//	    Metrics:        businessMetrics,
+//	    ResolveTimeout: routeConfig.Global.ResolveTimeoutOrDefault(),
 //	})
 //
 //	// Add alert to group
@@ -46,6 +47,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
 	"github.com/ipiton/AMP/pkg/metrics"
 )
 
@@ -157,16 +159,25 @@ func (g *AlertGroup) GetResolvedCount() int {
 	return g.Metadata.ResolvedCount
 }
 
-// IsExpired checks if the group should be considered expired based on maxAge.
+// IsExpired checks if the group should be considered expired based on maxAge,
+// with the default resolve_timeout. See IsExpiredAt.
+func (g *AlertGroup) IsExpired(maxAge time.Duration) bool {
+	return g.IsExpiredAt(time.Now(), maxAge, domain.DefaultResolveTimeout)
+}
+
+// IsExpiredAt checks if the group should be considered expired at now.
 //
 // A group is expired if:
 //  1. All alerts are resolved AND resolved_at is older than maxAge, OR
-//  2. updated_at is older than maxAge (no activity)
-func (g *AlertGroup) IsExpired(maxAge time.Duration) bool {
+//  2. updated_at is older than maxAge (no activity), OR
+//  3. All alerts are resolved or stopped receiving updates for
+//     resolveTimeout (see core.Alert.ExpiresAt), the last of them more than
+//     maxAge ago
+func (g *AlertGroup) IsExpiredAt(now time.Time, maxAge, resolveTimeout time.Duration) bool {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	cutoffTime := time.Now().Add(-maxAge)
+	cutoffTime := now.Add(-maxAge)
 
 	// Check if all alerts resolved and resolved_at exceeded maxAge
 	if g.Metadata.State == GroupStateResolved {
@@ -180,7 +191,20 @@ func (g *AlertGroup) IsExpired(maxAge time.Duration) bool {
 		return true
 	}
 
-	return false
+	// Check if all alerts expired under resolve_timeout more than maxAge ago
+	if len(g.Alerts) == 0 {
+		return false
+	}
+	var lastExpiry time.Time
+	for _, alert := range g.Alerts {
+		if !alert.IsExpiredAt(now, resolveTimeout) {
+			return false
+		}
+		if expiresAt := alert.ExpiresAt(resolveTimeout); expiresAt.After(lastExpiry) {
+			lastExpiry = expiresAt
+		}
+	}
+	return lastExpiry.Before(cutoffTime)
 }
 
 // Clone creates a shallow copy of the AlertGroup.
@@ -546,6 +570,12 @@ type DefaultGroupManagerConfig struct {
 
 	// Metrics for Prometheus integration (optional, recommended for production)
 	Metrics *metrics.BusinessMetrics
+
+	// ResolveTimeout is the global resolve_timeout: firing alerts without
+	// EndsAt that received no update for this long count as resolved when
+	// expired groups are cleaned up (optional, defaults to
+	// domain.DefaultResolveTimeout; see routing.GlobalConfig).
+	ResolveTimeout time.Duration
 }
 
 // Validate checks if the configuration is valid.
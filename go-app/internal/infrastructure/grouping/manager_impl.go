@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
 	"github.com/ipiton/AMP/pkg/metrics"
 )
 
@@ -59,6 +60,9 @@ type DefaultGroupManager struct {
 	// metrics for Prometheus integration
 	metrics *metrics.BusinessMetrics
 
+	// resolveTimeout is the global resolve_timeout used by cleanup
+	resolveTimeout time.Duration
+
 	// stats tracks operation statistics
 	stats *groupStats
 }
@@ -95,6 +99,9 @@ func NewDefaultGroupManager(ctx context.Context, cfg DefaultGroupManagerConfig)
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
+	if cfg.ResolveTimeout <= 0 {
+		cfg.ResolveTimeout = domain.DefaultResolveTimeout
+	}
 
 	// Storage is required for distributed state (TN-125)
 	if cfg.Storage == nil {
@@ -110,6 +117,7 @@ func NewDefaultGroupManager(ctx context.Context, cfg DefaultGroupManagerConfig)
 		publisher:        cfg.Publisher,    // Optional (TN-124)
 		logger:           cfg.Logger,
 		metrics:          cfg.Metrics,
+		resolveTimeout:   cfg.ResolveTimeout,
 		stats:            &groupStats{},
 	}
 
@@ -385,7 +393,7 @@ func (m *DefaultGroupManager) CleanupExpiredGroups(
 
 	deletedCount := 0
 	for _, group := range allGroups {
-		if !group.IsExpired(maxAge) {
+		if !group.IsExpiredAt(startTime, maxAge, m.resolveTimeout) {
 			continue
 		}
 
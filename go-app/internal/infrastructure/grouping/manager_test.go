@@ -535,6 +535,39 @@ func TestCleanupExpiredGroups_NoExpiredGroups(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCleanupExpiredGroups_ExpiredByResolveTimeout(t *testing.T) {
+	manager := createTestManager(t)
+	ctx := context.Background()
+
+	// Firing alert without endsAt that stopped receiving updates
+	groupKey := GroupKey("alertname=Silent")
+	alert := createTestAlert("Silent", core.StatusFiring, map[string]string{})
+	lastUpdate := time.Now().Add(-2 * time.Hour)
+	alert.StartsAt = lastUpdate
+	alert.Timestamp = &lastUpdate
+	manager.AddAlertToGroup(ctx, alert, groupKey)
+
+	// The group itself was updated recently
+	deleted, err := manager.CleanupExpiredGroups(ctx, 1*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted, "alert expired resolve_timeout after its last update")
+}
+
+func TestAlertGroup_IsExpiredAt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lastUpdate := now.Add(-90 * time.Minute)
+	group := &AlertGroup{
+		Alerts: map[string]*core.Alert{
+			"fp": {Fingerprint: "fp", Status: core.StatusFiring, StartsAt: lastUpdate, Timestamp: &lastUpdate},
+		},
+		Metadata: &GroupMetadata{State: GroupStateFiring, UpdatedAt: now},
+	}
+
+	assert.True(t, group.IsExpiredAt(now, time.Hour, 5*time.Minute))
+	assert.False(t, group.IsExpiredAt(now, time.Hour, time.Hour), "expired 30m ago, within maxAge")
+	assert.False(t, group.IsExpiredAt(now, time.Hour, 2*time.Hour), "still firing")
+}
+
 // === UpdateGroupState Tests ===
 
 func TestUpdateGroupState_AllFiring(t *testing.T) {
@@ -0,0 +1,320 @@
+// Package grouping provides per-target re-grouping of published alerts.
+//
+// A publishing target can override how the alerts it receives are grouped
+// (group_by), e.g. per alert for a ticketing webhook and per service for a
+// chat channel. TargetGrouper collects the alerts of each (target, group)
+// and releases them together, with group_wait and group_interval timers
+// kept separately for every target grouping.
+//
+// Example Usage:
+//
+//	grouper := NewTargetGrouper(TargetGrouperConfig{
+//	    Flush: func(target *core.PublishingTarget, key GroupKey, alerts []*core.EnrichedAlert) {
+//	        for _, alert := range alerts {
+//	            queue.Submit(alert, target)
+//	        }
+//	    },
+//	})
+//	defer grouper.Stop()
+//
+//	cfg := TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: 30 * time.Second, GroupInterval: 5 * time.Minute}
+//	err := grouper.Add(target, cfg, enrichedAlert)
+package grouping
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+// Defaults of TargetGroupConfig timers (the Alertmanager route defaults).
+const (
+	DefaultTargetGroupWait     = 30 * time.Second
+	DefaultTargetGroupInterval = 5 * time.Minute
+)
+
+// TargetGroupConfig is the grouping override of a publishing target.
+type TargetGroupConfig struct {
+	// GroupBy lists the labels alerts are grouped by. ["..."] groups by all
+	// labels (one group per alert); an empty list puts all alerts in one group.
+	GroupBy []string `json:"group_by"`
+
+	// GroupWait delays the first notification of a new group, so alerts
+	// firing together are released together. 0 releases them right away.
+	GroupWait time.Duration `json:"group_wait"`
+
+	// GroupInterval is the minimum time between notifications of a group.
+	GroupInterval time.Duration `json:"group_interval"`
+}
+
+// TargetFlushFunc receives the alerts of a target group when its timer
+// expires: the latest notification of every alert that changed since the
+// previous flush, in arrival order.
+type TargetFlushFunc func(target *core.PublishingTarget, key GroupKey, alerts []*core.EnrichedAlert)
+
+// TargetGrouperConfig configures TargetGrouper.
+type TargetGrouperConfig struct {
+	// Flush receives released groups (required).
+	Flush TargetFlushFunc
+
+	// KeyGenerator builds group keys (default: NewGroupKeyGenerator()).
+	KeyGenerator *GroupKeyGenerator
+
+	// Clock drives group timers (default: clock.Real()).
+	Clock clock.Clock
+
+	Logger *slog.Logger
+}
+
+// targetGroupID identifies a group of a target.
+type targetGroupID struct {
+	target string
+	key    GroupKey
+}
+
+// targetGroup is the state of one target group.
+type targetGroup struct {
+	target  *core.PublishingTarget
+	config  TargetGroupConfig
+	pending []*core.EnrichedAlert // unreleased notifications, one per fingerprint
+	firing  map[string]struct{}   // released firing fingerprints
+
+	lastFlush time.Time   // zero until the first flush
+	flushAt   time.Time   // when timer expires
+	timer     clock.Timer // nil while no flush is scheduled
+}
+
+// TargetGrouper re-groups the alerts of publishing targets that override
+// the grouping.
+//
+// Group lifecycle (per target and group key):
+//   - the first alert schedules a flush after group_wait
+//   - alerts arriving before the flush join it; repeated notifications of
+//     an alert replace the pending one
+//   - later alerts are released group_interval after the previous flush
+//   - a group is forgotten once a flush leaves none of its alerts firing,
+//     so the next alert waits group_wait again
+//
+// Thread-safe: Add may be called concurrently.
+type TargetGrouper struct {
+	flush  TargetFlushFunc
+	keyGen *GroupKeyGenerator
+	clock  clock.Clock
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	groups  map[targetGroupID]*targetGroup
+	stopped bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTargetGrouper creates a target grouper.
+func NewTargetGrouper(config TargetGrouperConfig) *TargetGrouper {
+	if config.KeyGenerator == nil {
+		config.KeyGenerator = NewGroupKeyGenerator()
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TargetGrouper{
+		flush:  config.Flush,
+		keyGen: config.KeyGenerator,
+		clock:  clock.OrReal(config.Clock),
+		logger: config.Logger.With("component", "target_grouper"),
+		groups: make(map[targetGroupID]*targetGroup),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Add queues alert for target, grouped by config.
+//
+// Returns an error when no group key can be built or the grouper is
+// stopped; callers should then deliver the alert directly.
+func (g *TargetGrouper) Add(target *core.PublishingTarget, config TargetGroupConfig, alert *core.EnrichedAlert) error {
+	key, err := g.keyGen.GenerateKey(alert.Alert.Labels, config.GroupBy)
+	if err != nil {
+		return fmt.Errorf("group key for target %s: %w", target.Name, err)
+	}
+	id := targetGroupID{target: target.Name, key: key}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return fmt.Errorf("target grouper stopped")
+	}
+
+	group, ok := g.groups[id]
+	if !ok {
+		group = &targetGroup{firing: make(map[string]struct{})}
+		g.groups[id] = group
+	}
+	// The latest target and settings apply to the next flush
+	group.target = target
+	group.config = config
+
+	fingerprint := alert.Alert.Fingerprint
+	replaced := false
+	for i, pending := range group.pending {
+		if pending.Alert.Fingerprint == fingerprint {
+			group.pending[i] = alert
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		group.pending = append(group.pending, alert)
+	}
+
+	if group.timer == nil {
+		delay := config.GroupWait
+		if !group.lastFlush.IsZero() {
+			delay = max(0, config.GroupInterval-g.clock.Since(group.lastFlush))
+		}
+		g.schedule(id, group, delay)
+	}
+	return nil
+}
+
+// schedule starts the flush timer of group. Caller holds g.mu.
+func (g *TargetGrouper) schedule(id targetGroupID, group *targetGroup, delay time.Duration) {
+	timer := g.clock.NewTimer(delay)
+	group.timer = timer
+	group.flushAt = g.clock.Now().Add(delay)
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		select {
+		case <-timer.C():
+			g.flushGroup(id, timer)
+		case <-g.ctx.Done():
+			timer.Stop()
+		}
+	}()
+}
+
+// flushGroup releases the pending alerts of a group whose timer expired.
+func (g *TargetGrouper) flushGroup(id targetGroupID, timer clock.Timer) {
+	g.mu.Lock()
+	group, ok := g.groups[id]
+	if !ok || group.timer != timer {
+		g.mu.Unlock()
+		return
+	}
+	target, alerts := g.release(id, group)
+	g.mu.Unlock()
+
+	g.deliver(target, id.key, alerts)
+}
+
+// release takes the pending alerts of group and forgets the group when no
+// alert is left firing. Caller holds g.mu.
+func (g *TargetGrouper) release(id targetGroupID, group *targetGroup) (*core.PublishingTarget, []*core.EnrichedAlert) {
+	alerts := group.pending
+	group.pending = nil
+	group.timer = nil
+	group.flushAt = time.Time{}
+	group.lastFlush = g.clock.Now()
+
+	for _, alert := range alerts {
+		if alert.Alert.Status == core.StatusResolved {
+			delete(group.firing, alert.Alert.Fingerprint)
+		} else {
+			group.firing[alert.Alert.Fingerprint] = struct{}{}
+		}
+	}
+	if len(group.firing) == 0 {
+		delete(g.groups, id)
+	}
+	return group.target, alerts
+}
+
+func (g *TargetGrouper) deliver(target *core.PublishingTarget, key GroupKey, alerts []*core.EnrichedAlert) {
+	if len(alerts) == 0 {
+		return
+	}
+	g.logger.Debug("Releasing target group",
+		"target", target.Name,
+		"group_key", key,
+		"alerts", len(alerts))
+	g.flush(target, key, alerts)
+}
+
+// ForgetTarget drops the groups of a target removed from discovery and
+// releases their pending alerts right away. Timers still running find their
+// group gone and do nothing.
+func (g *TargetGrouper) ForgetTarget(name string) {
+	type flush struct {
+		target *core.PublishingTarget
+		key    GroupKey
+		alerts []*core.EnrichedAlert
+	}
+	var flushes []flush
+
+	g.mu.Lock()
+	for id, group := range g.groups {
+		if id.target != name {
+			continue
+		}
+		flushes = append(flushes, flush{target: group.target, key: id.key, alerts: group.pending})
+		delete(g.groups, id)
+	}
+	g.mu.Unlock()
+
+	for _, f := range flushes {
+		g.deliver(f.target, f.key, f.alerts)
+	}
+}
+
+// Pending returns the number of alerts waiting for a flush.
+func (g *TargetGrouper) Pending() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := 0
+	for _, group := range g.groups {
+		n += len(group.pending)
+	}
+	return n
+}
+
+// Stop cancels all timers and releases the pending alerts right away, so
+// shutdown does not drop notifications. Later Adds fail.
+func (g *TargetGrouper) Stop() {
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+		return
+	}
+	g.stopped = true
+	g.cancel()
+
+	type flush struct {
+		target *core.PublishingTarget
+		key    GroupKey
+		alerts []*core.EnrichedAlert
+	}
+	var flushes []flush
+	for id, group := range g.groups {
+		if len(group.pending) == 0 {
+			continue
+		}
+		target, alerts := g.release(id, group)
+		flushes = append(flushes, flush{target: target, key: id.key, alerts: alerts})
+	}
+	g.mu.Unlock()
+
+	g.wg.Wait()
+	for _, f := range flushes {
+		g.deliver(f.target, f.key, f.alerts)
+	}
+}
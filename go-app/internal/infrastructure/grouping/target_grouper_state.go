@@ -0,0 +1,115 @@
+package grouping
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// TargetGroupState is the state of a target group, checkpointed so that a
+// restarted AMP picks up its groups where the previous run left them.
+type TargetGroupState struct {
+	Target  string                `json:"target"`
+	Key     GroupKey              `json:"key"`
+	Config  TargetGroupConfig     `json:"config"`
+	Pending []*core.EnrichedAlert `json:"pending,omitempty"`
+	Firing  []string              `json:"firing,omitempty"`
+
+	LastFlush time.Time `json:"last_flush"`
+	FlushAt   time.Time `json:"flush_at"` // zero while no flush is scheduled
+}
+
+// TargetGroupRestore counts what Restore brought back.
+type TargetGroupRestore struct {
+	Groups       int // groups restored
+	Dropped      int // groups of targets no longer discovered
+	Timers       int // flush timers restarted
+	MissedTimers int // flush timers that expired while AMP was down, fired right away
+}
+
+// Snapshot returns the state of all target groups.
+func (g *TargetGrouper) Snapshot() []TargetGroupState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	states := make([]TargetGroupState, 0, len(g.groups))
+	for id, group := range g.groups {
+		state := TargetGroupState{
+			Target:    id.target,
+			Key:       id.key,
+			Config:    group.config,
+			Pending:   append([]*core.EnrichedAlert(nil), group.pending...),
+			LastFlush: group.lastFlush,
+			FlushAt:   group.flushAt,
+		}
+		for fingerprint := range group.firing {
+			state.Firing = append(state.Firing, fingerprint)
+		}
+		slices.Sort(state.Firing)
+		states = append(states, state)
+	}
+	return states
+}
+
+// Restore recreates the groups of a snapshot taken by a previous run.
+// resolve returns the current target of a name, or nil when the target is no
+// longer discovered: its groups are dropped. Groups that already exist are
+// kept as they are.
+//
+// Pending alerts are released when their flush was due; timers that expired
+// while AMP was down fire right away.
+func (g *TargetGrouper) Restore(states []TargetGroupState, resolve func(name string) *core.PublishingTarget) TargetGroupRestore {
+	var result TargetGroupRestore
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return result
+	}
+
+	now := g.clock.Now()
+	for _, state := range states {
+		target := resolve(state.Target)
+		if target == nil {
+			result.Dropped++
+			continue
+		}
+		id := targetGroupID{target: state.Target, key: state.Key}
+		if _, ok := g.groups[id]; ok {
+			continue
+		}
+
+		group := &targetGroup{
+			target:    target,
+			config:    state.Config,
+			pending:   state.Pending,
+			firing:    make(map[string]struct{}, len(state.Firing)),
+			lastFlush: state.LastFlush,
+		}
+		for _, fingerprint := range state.Firing {
+			group.firing[fingerprint] = struct{}{}
+		}
+		if len(group.pending) == 0 && len(group.firing) == 0 {
+			continue
+		}
+		g.groups[id] = group
+		result.Groups++
+
+		if len(group.pending) == 0 {
+			continue
+		}
+		// A group checkpointed before its timer started waits group_wait again
+		delay := state.Config.GroupWait
+		if !state.FlushAt.IsZero() {
+			delay = state.FlushAt.Sub(now)
+		}
+		if delay <= 0 {
+			delay = 0
+			result.MissedTimers++
+		}
+		g.schedule(id, group, delay)
+		result.Timers++
+	}
+	return result
+}
@@ -0,0 +1,221 @@
+package grouping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// targetFlush is a group released by a TargetGrouper under test.
+type targetFlush struct {
+	target       string
+	key          GroupKey
+	fingerprints []string
+}
+
+func newTestTargetGrouper(t *testing.T) (*TargetGrouper, *clock.Fake, chan targetFlush) {
+	t.Helper()
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	flushes := make(chan targetFlush, 16)
+	grouper := NewTargetGrouper(TargetGrouperConfig{
+		Clock: fake,
+		Flush: func(target *core.PublishingTarget, key GroupKey, alerts []*core.EnrichedAlert) {
+			f := targetFlush{target: target.Name, key: key}
+			for _, alert := range alerts {
+				f.fingerprints = append(f.fingerprints, alert.Alert.Fingerprint)
+			}
+			flushes <- f
+		},
+	})
+	t.Cleanup(grouper.Stop)
+	return grouper, fake, flushes
+}
+
+func newTargetGrouperAlert(fingerprint, service string, status core.AlertStatus) *core.EnrichedAlert {
+	return &core.EnrichedAlert{Alert: &core.Alert{
+		Fingerprint: fingerprint,
+		AlertName:   "HighLatency",
+		Status:      status,
+		Labels:      map[string]string{"alertname": "HighLatency", "service": service, "instance": fingerprint},
+	}}
+}
+
+func receiveFlush(t *testing.T, flushes chan targetFlush) targetFlush {
+	t.Helper()
+	select {
+	case f := <-flushes:
+		return f
+	case <-time.After(time.Second):
+		t.Fatal("no group released")
+		return targetFlush{}
+	}
+}
+
+func assertNoFlush(t *testing.T, flushes chan targetFlush) {
+	t.Helper()
+	select {
+	case f := <-flushes:
+		t.Fatalf("unexpected release: %+v", f)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestTargetGrouper_GroupWaitAndInterval(t *testing.T) {
+	grouper, fake, flushes := newTestTargetGrouper(t)
+	target := &core.PublishingTarget{Name: "chat"}
+	cfg := TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: 30 * time.Second, GroupInterval: 5 * time.Minute}
+
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("a", "checkout", core.StatusFiring)))
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("b", "checkout", core.StatusFiring)))
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("a", "checkout", core.StatusFiring)))
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("c", "payments", core.StatusFiring)))
+	assert.Equal(t, 3, grouper.Pending())
+
+	fake.Advance(29 * time.Second)
+	assertNoFlush(t, flushes)
+
+	fake.Advance(time.Second)
+	released := map[GroupKey][]string{}
+	for range 2 {
+		f := receiveFlush(t, flushes)
+		assert.Equal(t, "chat", f.target)
+		released[f.key] = f.fingerprints
+	}
+	assert.Equal(t, []string{"a", "b"}, released["service=checkout"], "repeated notifications collapse")
+	assert.Equal(t, []string{"c"}, released["service=payments"])
+
+	// Changes to a notified group wait for group_interval
+	fake.Advance(time.Minute)
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("a", "checkout", core.StatusResolved)))
+	fake.Advance(3*time.Minute + 59*time.Second)
+	assertNoFlush(t, flushes)
+	fake.Advance(time.Second)
+	f := receiveFlush(t, flushes)
+	assert.Equal(t, []string{"a"}, f.fingerprints)
+	assert.Zero(t, grouper.Pending())
+}
+
+func TestTargetGrouper_SeparateTimersPerTarget(t *testing.T) {
+	grouper, fake, flushes := newTestTargetGrouper(t)
+	perAlert := TargetGroupConfig{GroupBy: []string{SpecialGroupingMarker}, GroupInterval: time.Minute}
+	perService := TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: 10 * time.Second, GroupInterval: time.Minute}
+
+	alert := newTargetGrouperAlert("a", "checkout", core.StatusFiring)
+	require.NoError(t, grouper.Add(&core.PublishingTarget{Name: "tickets"}, perAlert, alert))
+	require.NoError(t, grouper.Add(&core.PublishingTarget{Name: "chat"}, perService, alert))
+
+	// group_wait 0 releases right away
+	f := receiveFlush(t, flushes)
+	assert.Equal(t, "tickets", f.target)
+	assertNoFlush(t, flushes)
+
+	fake.Advance(10 * time.Second)
+	f = receiveFlush(t, flushes)
+	assert.Equal(t, "chat", f.target)
+	assert.Equal(t, GroupKey("service=checkout"), f.key)
+}
+
+func TestTargetGrouper_ResolvedGroupStartsOver(t *testing.T) {
+	grouper, fake, flushes := newTestTargetGrouper(t)
+	target := &core.PublishingTarget{Name: "chat"}
+	cfg := TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: 10 * time.Second, GroupInterval: time.Hour}
+
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("a", "checkout", core.StatusFiring)))
+	fake.Advance(10 * time.Second)
+	receiveFlush(t, flushes)
+
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("a", "checkout", core.StatusResolved)))
+	fake.Advance(time.Hour)
+	receiveFlush(t, flushes)
+
+	// Nothing fires anymore: a new alert waits group_wait, not group_interval
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("b", "checkout", core.StatusFiring)))
+	fake.Advance(10 * time.Second)
+	assert.Equal(t, []string{"b"}, receiveFlush(t, flushes).fingerprints)
+}
+
+func TestTargetGrouper_StopReleasesPending(t *testing.T) {
+	grouper, _, flushes := newTestTargetGrouper(t)
+	target := &core.PublishingTarget{Name: "chat"}
+	cfg := TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: time.Hour, GroupInterval: time.Hour}
+
+	require.NoError(t, grouper.Add(target, cfg, newTargetGrouperAlert("a", "checkout", core.StatusFiring)))
+	grouper.Stop()
+
+	assert.Equal(t, []string{"a"}, receiveFlush(t, flushes).fingerprints)
+	assert.Error(t, grouper.Add(target, cfg, newTargetGrouperAlert("b", "checkout", core.StatusFiring)))
+}
+
+func TestTargetGrouper_ForgetTarget(t *testing.T) {
+	grouper, fake, flushes := newTestTargetGrouper(t)
+	cfg := TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: time.Minute, GroupInterval: time.Hour}
+
+	require.NoError(t, grouper.Add(&core.PublishingTarget{Name: "old-chat"}, cfg, newTargetGrouperAlert("a", "checkout", core.StatusFiring)))
+	require.NoError(t, grouper.Add(&core.PublishingTarget{Name: "chat"}, cfg, newTargetGrouperAlert("b", "checkout", core.StatusFiring)))
+
+	grouper.ForgetTarget("old-chat")
+	f := receiveFlush(t, flushes)
+	assert.Equal(t, "old-chat", f.target)
+	assert.Equal(t, []string{"a"}, f.fingerprints)
+	assert.Equal(t, 1, grouper.Pending())
+
+	// The stale timer of the forgotten group releases nothing
+	fake.Advance(time.Minute)
+	f = receiveFlush(t, flushes)
+	assert.Equal(t, "chat", f.target)
+	assertNoFlush(t, flushes)
+}
+
+func TestTargetGrouper_SnapshotRestore(t *testing.T) {
+	grouper, fake, _ := newTestTargetGrouper(t)
+	chat := &core.PublishingTarget{Name: "chat"}
+	cfg := TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: time.Minute, GroupInterval: time.Hour}
+
+	require.NoError(t, grouper.Add(chat, cfg, newTargetGrouperAlert("a", "checkout", core.StatusFiring)))
+	require.NoError(t, grouper.Add(chat, cfg, newTargetGrouperAlert("b", "payments", core.StatusFiring)))
+	require.NoError(t, grouper.Add(&core.PublishingTarget{Name: "old-chat"}, cfg, newTargetGrouperAlert("c", "checkout", core.StatusFiring)))
+	fake.Advance(30 * time.Second)
+
+	states := grouper.Snapshot()
+	require.Len(t, states, 3)
+	grouper.Stop()
+
+	// The restarted grouper resumes the timers where they were
+	restored, restoredClock, flushes := newTestTargetGrouper(t)
+	restoredClock.Set(fake.Now().Add(10 * time.Second))
+	result := restored.Restore(states, func(name string) *core.PublishingTarget {
+		if name == "chat" {
+			return chat
+		}
+		return nil
+	})
+	assert.Equal(t, TargetGroupRestore{Groups: 2, Dropped: 1, Timers: 2}, result)
+	assert.Equal(t, 2, restored.Pending())
+
+	assertNoFlush(t, flushes)
+	restoredClock.Advance(20 * time.Second)
+	for range 2 {
+		assert.Equal(t, "chat", receiveFlush(t, flushes).target)
+	}
+}
+
+func TestTargetGrouper_RestoreMissedTimer(t *testing.T) {
+	grouper, fake, flushes := newTestTargetGrouper(t)
+	state := TargetGroupState{
+		Target:  "chat",
+		Key:     "service=checkout",
+		Config:  TargetGroupConfig{GroupBy: []string{"service"}, GroupWait: time.Minute, GroupInterval: time.Hour},
+		Pending: []*core.EnrichedAlert{newTargetGrouperAlert("a", "checkout", core.StatusFiring)},
+		FlushAt: fake.Now().Add(-time.Minute),
+	}
+
+	result := grouper.Restore([]TargetGroupState{state}, func(string) *core.PublishingTarget {
+		return &core.PublishingTarget{Name: "chat"}
+	})
+	assert.Equal(t, TargetGroupRestore{Groups: 1, Timers: 1, MissedTimers: 1}, result)
+	assert.Equal(t, []string{"a"}, receiveFlush(t, flushes).fingerprints)
+}
@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ipiton/AMP/pkg/clock"
 	"github.com/ipiton/AMP/pkg/metrics"
 )
 
@@ -62,6 +63,9 @@ type DefaultTimerManager struct {
 	logger  *slog.Logger
 	metrics *metrics.BusinessMetrics
 
+	// Time source for timer scheduling (real clock in production)
+	clock clock.Clock
+
 	// Statistics (in-memory, for GetStats)
 	stats   *timerStats
 	statsMu sync.RWMutex
@@ -79,8 +83,8 @@ type DefaultTimerManager struct {
 
 // timerHandle represents an active timer's runtime state.
 type timerHandle struct {
-	// Go standard library timer
-	timer *time.Timer
+	// Timer created from the manager's clock
+	timer clock.Timer
 
 	// Context for cancellation
 	ctx    context.Context
@@ -124,6 +128,10 @@ type TimerManagerConfig struct {
 	// Observability
 	Logger  *slog.Logger
 	Metrics *metrics.BusinessMetrics
+
+	// Clock drives timer scheduling and timestamps (default: clock.Real()).
+	// Tests inject clock.NewFake to expire timers without sleeping.
+	Clock clock.Clock
 }
 
 // NewDefaultTimerManager creates a new timer manager.
@@ -171,6 +179,7 @@ func NewDefaultTimerManager(config TimerManagerConfig) (*DefaultTimerManager, er
 	if config.Logger == nil {
 		config.Logger = slog.Default()
 	}
+	config.Clock = clock.OrReal(config.Clock)
 
 	// Create context for lifecycle management
 	ctx, cancel := context.WithCancel(context.Background())
@@ -186,6 +195,7 @@ func NewDefaultTimerManager(config TimerManagerConfig) (*DefaultTimerManager, er
 		config:       &config,
 		logger:       config.Logger,
 		metrics:      config.Metrics,
+		clock:        config.Clock,
 		stats: &timerStats{
 			durationSum:   make(map[TimerType]time.Duration),
 			durationCount: make(map[TimerType]int64),
@@ -263,7 +273,7 @@ func (tm *DefaultTimerManager) StartTimer(
 	tm.timersMu.Unlock()
 
 	// Create timer struct
-	now := time.Now()
+	now := tm.clock.Now()
 	timer := &GroupTimer{
 		GroupKey:  groupKey,
 		TimerType: timerType,
@@ -289,7 +299,7 @@ func (tm *DefaultTimerManager) StartTimer(
 
 	// Start Go timer
 	timerCtx, cancelFunc := context.WithCancel(tm.ctx)
-	goTimer := time.NewTimer(duration)
+	goTimer := tm.clock.NewTimer(duration)
 
 	handle := &timerHandle{
 		timer:     goTimer,
@@ -435,7 +445,7 @@ func (tm *DefaultTimerManager) ResetTimer(
 	// Update metadata with reset count
 	if timer.Metadata != nil {
 		timer.Metadata.ResetCount = resetCount
-		now := time.Now()
+		now := tm.clock.Now()
 		timer.Metadata.LastResetAt = &now
 
 		// Save updated metadata
@@ -547,7 +557,7 @@ func (tm *DefaultTimerManager) handleTimerExpiration(handle *timerHandle, timer
 	defer tm.wg.Done()
 
 	select {
-	case <-handle.timer.C:
+	case <-handle.timer.C():
 		// Timer expired naturally
 		tm.onTimerExpired(handle.ctx, handle.groupKey, handle.timerType)
 
@@ -672,7 +682,7 @@ func (tm *DefaultTimerManager) RestoreTimers(ctx context.Context) (restored int,
 		return 0, 0, fmt.Errorf("failed to list timers: %w", err)
 	}
 
-	now := time.Now()
+	now := tm.clock.Now()
 
 	for _, timer := range timers {
 		if timer.ExpiresAt.Before(now) {
@@ -688,7 +698,7 @@ func (tm *DefaultTimerManager) RestoreTimers(ctx context.Context) (restored int,
 			missed++
 		} else {
 			// Timer still valid - restore it
-			remaining := time.Until(timer.ExpiresAt)
+			remaining := tm.clock.Until(timer.ExpiresAt)
 
 			tm.logger.Info("Restoring timer",
 				"group_key", timer.GroupKey,
@@ -697,7 +707,7 @@ func (tm *DefaultTimerManager) RestoreTimers(ctx context.Context) (restored int,
 
 			// Start timer with remaining duration
 			timerCtx, cancelFunc := context.WithCancel(tm.ctx)
-			goTimer := time.NewTimer(remaining)
+			goTimer := tm.clock.NewTimer(remaining)
 
 			handle := &timerHandle{
 				timer:     goTimer,
@@ -773,7 +783,7 @@ func (tm *DefaultTimerManager) GetStats(ctx context.Context) (*TimerStats, error
 		ResetCount:      tm.stats.totalReset,
 		MissedTimers:    tm.stats.totalMissed,
 		AverageDuration: avgDuration,
-		Timestamp:       time.Now(),
+		Timestamp:       tm.clock.Now(),
 	}, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -359,6 +360,47 @@ func TestDefaultTimerManager_OnTimerExpired(t *testing.T) {
 	assert.True(t, callbackCalled.Load())
 }
 
+// TestDefaultTimerManager_FakeClock tests that timers follow the injected clock
+func TestDefaultTimerManager_FakeClock(t *testing.T) {
+	_, storage, groupManager := setupTestTimerManager(t)
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	manager, err := NewDefaultTimerManager(TimerManagerConfig{
+		Storage:      storage,
+		GroupManager: groupManager,
+		Logger:       slog.Default(),
+		Clock:        fake,
+	})
+	require.NoError(t, err)
+	defer manager.Shutdown(context.Background())
+
+	expired := make(chan GroupKey, 1)
+	manager.OnTimerExpired(func(ctx context.Context, groupKey GroupKey, timerType TimerType, group *AlertGroup) error {
+		expired <- groupKey
+		return nil
+	})
+
+	ctx := context.Background()
+	timer, err := manager.StartTimer(ctx, "test-group", GroupWaitTimer, 30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, fake.Now().Add(30*time.Second), timer.ExpiresAt)
+
+	fake.Advance(29 * time.Second)
+	select {
+	case <-expired:
+		t.Fatal("timer expired before its deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	fake.Advance(1 * time.Second)
+	select {
+	case key := <-expired:
+		assert.Equal(t, GroupKey("test-group"), key)
+	case <-time.After(time.Second):
+		t.Fatal("timer did not expire after advancing the clock")
+	}
+}
+
 // TestDefaultTimerManager_OnTimerExpired_MultipleCallbacks tests multiple callbacks
 func TestDefaultTimerManager_OnTimerExpired_MultipleCallbacks(t *testing.T) {
 	manager, _, groupManager := setupTestTimerManager(t)
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
@@ -117,6 +119,12 @@ type TwoTierAlertCache struct {
 	l1Mutex sync.RWMutex
 	l1Max   int // Max entries in L1
 
+	// version changes with every change of L1 (see Version)
+	version atomic.Uint64
+	// changes are the latest changes of L1, in version order (see
+	// ChangesSince). Guarded by l1Mutex.
+	changes []versionedAlertChange
+
 	// L2: Redis cache
 	redisCache cache.Cache
 	keyPrefix  string
@@ -287,6 +295,59 @@ func (c *TwoTierAlertCache) GetFiringAlerts(ctx context.Context) ([]*core.Alert,
 	return []*core.Alert{}, nil
 }
 
+// Version implements VersionedAlertCache.Version. The alerts are not
+// versioned while L1 is empty and Redis is configured, because
+// GetFiringAlerts then reads them from Redis.
+func (c *TwoTierAlertCache) Version() (uint64, bool) {
+	c.l1Mutex.RLock()
+	defer c.l1Mutex.RUnlock()
+	if len(c.l1Cache) == 0 && c.redisCache != nil {
+		return 0, false
+	}
+	return c.version.Load(), true
+}
+
+// ChangesSince implements IncrementalAlertCache.ChangesSince. The latest
+// 4096 changes are known.
+func (c *TwoTierAlertCache) ChangesSince(version uint64) ([]AlertChange, uint64, bool) {
+	c.l1Mutex.RLock()
+	defer c.l1Mutex.RUnlock()
+	if len(c.l1Cache) == 0 && c.redisCache != nil {
+		return nil, 0, false
+	}
+
+	current := c.version.Load()
+	if version == current {
+		return nil, current, true
+	}
+	first := sort.Search(len(c.changes), func(i int) bool { return c.changes[i].version > version })
+	if first == len(c.changes) || c.changes[first].version != version+1 {
+		return nil, 0, false
+	}
+	changes := make([]AlertChange, 0, len(c.changes)-first)
+	for _, change := range c.changes[first:] {
+		changes = append(changes, change.AlertChange)
+	}
+	return changes, current, true
+}
+
+// Restore loads the firing alerts persisted in Redis into L1, e.g. after a
+// restart. Without it, GetFiringAlerts only falls back to Redis while L1 is
+// empty, so source alerts persisted before a restart would be missed once
+// the first new alert arrives. Returns the number of restored alerts.
+func (c *TwoTierAlertCache) Restore(ctx context.Context) (int, error) {
+	if c.redisCache == nil {
+		return 0, nil
+	}
+
+	alerts, err := c.getFromRedis(ctx)
+	if err != nil {
+		return 0, err
+	}
+	c.populateL1(alerts)
+	return len(alerts), nil
+}
+
 // AddFiringAlert implements ActiveAlertCache.AddFiringAlert.
 //
 // Adds alert to both L1 and L2 caches.
@@ -318,11 +379,13 @@ func (c *TwoTierAlertCache) AddFiringAlert(ctx context.Context, alert *core.Aler
 		}
 		if oldestKey != "" {
 			delete(c.l1Cache, oldestKey)
+			c.recordChange(oldestKey, nil)
 			c.metrics.Evictions.Inc()
 		}
 	}
 
 	c.l1Cache[alert.Fingerprint] = alert
+	c.recordChange(alert.Fingerprint, alert)
 	c.metrics.CacheSize.Set(float64(len(c.l1Cache)))
 	c.l1Mutex.Unlock()
 
@@ -365,7 +428,10 @@ func (c *TwoTierAlertCache) RemoveAlert(ctx context.Context, fingerprint string)
 
 	// Remove from L1
 	c.l1Mutex.Lock()
-	delete(c.l1Cache, fingerprint)
+	if _, ok := c.l1Cache[fingerprint]; ok {
+		delete(c.l1Cache, fingerprint)
+		c.recordChange(fingerprint, nil)
+	}
 	c.metrics.CacheSize.Set(float64(len(c.l1Cache)))
 	c.l1Mutex.Unlock()
 
@@ -433,12 +499,14 @@ func (c *TwoTierAlertCache) cleanup() {
 		// Remove if alert has ended
 		if alert.EndsAt != nil && alert.EndsAt.Before(now) {
 			delete(c.l1Cache, fingerprint)
+			c.recordChange(fingerprint, nil)
 			removed++
 			c.metrics.Evictions.Inc()
 		}
 		// Remove if alert is too old (TTL)
 		if alert.StartsAt.Add(c.ttl).Before(now) {
 			delete(c.l1Cache, fingerprint)
+			c.recordChange(fingerprint, nil)
 			removed++
 			c.metrics.Evictions.Inc()
 		}
@@ -552,9 +620,33 @@ func (c *TwoTierAlertCache) populateL1(alerts []*core.Alert) {
 			break
 		}
 		c.l1Cache[alert.Fingerprint] = alert
+		c.recordChange(alert.Fingerprint, alert)
 	}
 }
 
+// maxAlertCacheChanges bounds the changes kept for ChangesSince. Matchers
+// lagging further behind re-read the firing alerts.
+const maxAlertCacheChanges = 4096
+
+type versionedAlertChange struct {
+	version uint64
+	AlertChange
+}
+
+// recordChange bumps the version for a change of L1. l1Mutex must be held.
+func (c *TwoTierAlertCache) recordChange(fingerprint string, alert *core.Alert) {
+	version := c.version.Add(1)
+	if len(c.changes) >= maxAlertCacheChanges {
+		kept := copy(c.changes, c.changes[len(c.changes)-maxAlertCacheChanges/2:])
+		clear(c.changes[kept:])
+		c.changes = c.changes[:kept]
+	}
+	c.changes = append(c.changes, versionedAlertChange{
+		version:     version,
+		AlertChange: AlertChange{Fingerprint: fingerprint, Alert: alert},
+	})
+}
+
 // redisKey generates Redis key for an alert fingerprint.
 func (c *TwoTierAlertCache) redisKey(fingerprint string) string {
 	return c.keyPrefix + fingerprint
@@ -277,6 +277,69 @@ func TestTwoTierAlertCache_RemoveAlert(t *testing.T) {
 	}
 }
 
+func TestTwoTierAlertCache_Version(t *testing.T) {
+	ctx := context.Background()
+	cache := NewTwoTierAlertCache(nil, nil)
+	defer cache.Stop()
+
+	v0, ok := cache.Version()
+	if !ok {
+		t.Fatal("Version() ok = false without Redis")
+	}
+	_ = cache.AddFiringAlert(ctx, createCacheTestAlert("TestAlert", "fp-version"))
+	v1, _ := cache.Version()
+	if v1 == v0 {
+		t.Error("Version() unchanged after AddFiringAlert")
+	}
+	_ = cache.RemoveAlert(ctx, "fp-unknown")
+	if v, _ := cache.Version(); v != v1 {
+		t.Error("Version() changed after removing an unknown alert")
+	}
+	_ = cache.RemoveAlert(ctx, "fp-version")
+	if v, _ := cache.Version(); v == v1 {
+		t.Error("Version() unchanged after RemoveAlert")
+	}
+
+	withRedis := NewTwoTierAlertCache(&mockRedisCache{}, nil)
+	defer withRedis.Stop()
+	if _, ok := withRedis.Version(); ok {
+		t.Error("Version() ok = true while alerts are read from Redis")
+	}
+}
+
+func TestTwoTierAlertCache_ChangesSince(t *testing.T) {
+	ctx := context.Background()
+	cache := NewTwoTierAlertCache(nil, nil)
+	defer cache.Stop()
+
+	v0, _ := cache.Version()
+	alert := createCacheTestAlert("TestAlert", "fp-changes")
+	_ = cache.AddFiringAlert(ctx, alert)
+	_ = cache.RemoveAlert(ctx, "fp-changes")
+
+	changes, current, ok := cache.ChangesSince(v0)
+	if !ok {
+		t.Fatal("ChangesSince() ok = false")
+	}
+	if v, _ := cache.Version(); current != v {
+		t.Errorf("ChangesSince() current = %d, want %d", current, v)
+	}
+	if len(changes) != 2 || changes[0].Alert != alert || changes[1].Fingerprint != "fp-changes" || changes[1].Alert != nil {
+		t.Errorf("ChangesSince() = %+v, want the add and the removal", changes)
+	}
+	if changes, _, ok := cache.ChangesSince(current); !ok || len(changes) != 0 {
+		t.Errorf("ChangesSince(current) = %v, %v, want no changes", changes, ok)
+	}
+
+	// Older changes are dropped
+	for i := 0; i < maxAlertCacheChanges; i++ {
+		_ = cache.AddFiringAlert(ctx, alert)
+	}
+	if _, _, ok := cache.ChangesSince(v0); ok {
+		t.Error("ChangesSince() ok = true for dropped changes")
+	}
+}
+
 func TestTwoTierAlertCache_WithRedis(t *testing.T) {
 	redis := &mockRedisCache{}
 	cache := NewTwoTierAlertCache(redis, nil)
@@ -0,0 +1,199 @@
+package inhibition
+
+import (
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// equalIndex is an inverted index of the firing alerts matching the source
+// side of each rule, keyed by the values of the rule's equal labels.
+//
+// Without it, every check compares the target with every firing alert for
+// every rule (O(rules × alerts)). With it, a check looks up the sources
+// sharing the target's equal label values per rule, so its cost no longer
+// grows with the number of firing alerts that cannot inhibit the target.
+//
+// The index is updated incrementally, by apply with the changes reported by
+// an IncrementalAlertCache or else by sync: only added, replaced and removed
+// alerts are (re-)indexed. It is not safe for concurrent use; the matcher
+// guards it with indexMu.
+type equalIndex struct {
+	// rulesPtr identifies the rule set the index was built for.
+	rulesPtr *[]InhibitionRule
+	rules    []InhibitionRule
+
+	// version is the cache version of the indexed alerts, if versioned.
+	version   uint64
+	versioned bool
+
+	alerts map[*core.Alert]*indexedAlert
+	// fingerprints maps fingerprints to their alert, for apply.
+	fingerprints map[string]*indexedAlert
+	// sources[i] maps equal label values to the source alerts of rules[i].
+	sources []map[string][]*core.Alert
+	// generation marks the alerts seen by the current sync.
+	generation uint64
+}
+
+type indexedAlert struct {
+	alert      *core.Alert
+	generation uint64
+	// keys[i] is the equal key of the alert for rule i, if it is a source.
+	keys []indexKey
+}
+
+type indexKey struct {
+	rule int
+	key  string
+}
+
+func newEqualIndex(rulesPtr *[]InhibitionRule) *equalIndex {
+	ix := &equalIndex{
+		rulesPtr:     rulesPtr,
+		rules:        *rulesPtr,
+		alerts:       make(map[*core.Alert]*indexedAlert),
+		fingerprints: make(map[string]*indexedAlert),
+		sources:      make([]map[string][]*core.Alert, len(*rulesPtr)),
+	}
+	for i := range ix.sources {
+		ix.sources[i] = make(map[string][]*core.Alert)
+	}
+	return ix
+}
+
+// sync brings the index in line with the firing alerts. Alerts are
+// identified by pointer, so an alert replaced in the cache by a new
+// *core.Alert is re-indexed, but labels changed in place are not detected.
+func (ix *equalIndex) sync(alerts []*core.Alert) {
+	ix.generation++
+	for _, alert := range alerts {
+		if entry, ok := ix.alerts[alert]; ok {
+			entry.generation = ix.generation
+			continue
+		}
+		ix.add(alert)
+	}
+	for _, entry := range ix.alerts {
+		if entry.generation != ix.generation {
+			ix.remove(entry)
+		}
+	}
+}
+
+// apply updates the index with changes of the firing alerts, oldest first.
+func (ix *equalIndex) apply(changes []AlertChange) {
+	for _, change := range changes {
+		if entry, ok := ix.fingerprints[change.Fingerprint]; ok {
+			if entry.alert == change.Alert {
+				continue
+			}
+			ix.remove(entry)
+		}
+		if change.Alert != nil && change.Alert.Status == core.StatusFiring {
+			ix.add(change.Alert)
+		}
+	}
+}
+
+func (ix *equalIndex) add(alert *core.Alert) {
+	entry := &indexedAlert{alert: alert, generation: ix.generation}
+	for i := range ix.rules {
+		rule := &ix.rules[i]
+		if !matchSourceSide(rule, alert.Labels) {
+			continue
+		}
+		key := equalKey(rule.Equal, alert.Labels)
+		ix.sources[i][key] = append(ix.sources[i][key], alert)
+		entry.keys = append(entry.keys, indexKey{rule: i, key: key})
+	}
+	ix.alerts[alert] = entry
+	ix.fingerprints[alert.Fingerprint] = entry
+}
+
+func (ix *equalIndex) remove(entry *indexedAlert) {
+	for _, k := range entry.keys {
+		bucket := ix.sources[k.rule][k.key]
+		for j, source := range bucket {
+			if source == entry.alert {
+				bucket = append(bucket[:j], bucket[j+1:]...)
+				break
+			}
+		}
+		if len(bucket) == 0 {
+			delete(ix.sources[k.rule], k.key)
+		} else {
+			ix.sources[k.rule][k.key] = bucket
+		}
+	}
+	delete(ix.alerts, entry.alert)
+	if ix.fingerprints[entry.alert.Fingerprint] == entry {
+		delete(ix.fingerprints, entry.alert.Fingerprint)
+	}
+}
+
+// forEachInhibitor calls fn for every rule and source alert inhibiting
+// target, in rule order, until fn returns false. Matching follows
+// matchRuleFast.
+func (ix *equalIndex) forEachInhibitor(target *core.Alert, fn func(rule *InhibitionRule, source *core.Alert) bool) {
+	for i := range ix.rules {
+		rule := &ix.rules[i]
+		if !matchTargetSide(rule, target.Labels) {
+			continue
+		}
+		sources := ix.sources[i][equalKey(rule.Equal, target.Labels)]
+		if len(sources) == 0 {
+			continue
+		}
+
+		targetIsSource := matchSourceSide(rule, target.Labels)
+		for _, source := range sources {
+			if source.Fingerprint == target.Fingerprint {
+				continue
+			}
+			// Two-sided matches cannot inhibit each other
+			if targetIsSource && matchTargetSide(rule, source.Labels) {
+				continue
+			}
+			if !equalLabels(rule.Equal, source.Labels, target.Labels) {
+				continue // key collision
+			}
+			if !fn(rule, source) {
+				return
+			}
+		}
+	}
+}
+
+// equalKeySeparator never occurs in valid UTF-8 label values.
+const equalKeySeparator = '\xff'
+
+// equalKey joins the values of the equal labels (missing as empty).
+func equalKey(equal []string, labels map[string]string) string {
+	switch len(equal) {
+	case 0:
+		return ""
+	case 1:
+		return labels[equal[0]]
+	}
+
+	n := len(equal)
+	for _, name := range equal {
+		n += len(labels[name])
+	}
+	key := make([]byte, 0, n)
+	for i, name := range equal {
+		if i > 0 {
+			key = append(key, equalKeySeparator)
+		}
+		key = append(key, labels[name]...)
+	}
+	return string(key)
+}
+
+func equalLabels(equal []string, a, b map[string]string) bool {
+	for _, name := range equal {
+		if a[name] != b[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,322 @@
+package inhibition
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// naiveInhibitors evaluates every rule against every firing alert.
+func naiveInhibitors(rules []InhibitionRule, firing []*core.Alert, target *core.Alert) []string {
+	var found []string
+	for i := range rules {
+		for _, source := range firing {
+			if source.Fingerprint != target.Fingerprint && ruleInhibits(&rules[i], source, target) {
+				found = append(found, rules[i].Name+"/"+source.Fingerprint)
+			}
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+func indexedInhibitors(t *testing.T, matcher *DefaultInhibitionMatcher, target *core.Alert) []string {
+	t.Helper()
+	results, err := matcher.FindInhibitors(context.Background(), target)
+	if err != nil {
+		t.Fatalf("FindInhibitors() error = %v", err)
+	}
+	found := make([]string, 0, len(results))
+	for _, r := range results {
+		found = append(found, r.Rule.Name+"/"+r.InhibitedBy.Fingerprint)
+	}
+	sort.Strings(found)
+	return found
+}
+
+// randomInhibitionSetup builds rules and alerts over a small label space, so
+// that matches, equal label mismatches, missing labels and two-sided matches
+// all occur.
+func randomInhibitionSetup(rng *rand.Rand, numRules, numAlerts int) ([]InhibitionRule, []*core.Alert) {
+	names := []string{"NodeDown", "InstanceDown", "PodDown", "DiskFull"}
+	value := func(label string) string { return fmt.Sprintf("%s%d", label, rng.Intn(3)) }
+
+	rules := make([]InhibitionRule, numRules)
+	for i := range rules {
+		rules[i] = InhibitionRule{
+			Name:        fmt.Sprintf("rule%d", i),
+			SourceMatch: map[string]string{"alertname": names[rng.Intn(len(names))]},
+			TargetMatch: map[string]string{"alertname": names[rng.Intn(len(names))]},
+		}
+		switch rng.Intn(4) {
+		case 0:
+			rules[i].Equal = []string{"node"}
+		case 1:
+			rules[i].Equal = []string{"node", "cluster"}
+		case 2:
+			rules[i].TargetMatchRE = map[string]string{"cluster": "cluster[01]"}
+		}
+	}
+
+	alerts := make([]*core.Alert, numAlerts)
+	for i := range alerts {
+		labels := map[string]string{"alertname": names[rng.Intn(len(names))], "node": value("node")}
+		if rng.Intn(4) > 0 {
+			labels["cluster"] = value("cluster")
+		}
+		alerts[i] = &core.Alert{
+			Fingerprint: fmt.Sprintf("fp-%d", i),
+			AlertName:   labels["alertname"],
+			Status:      core.StatusFiring,
+			Labels:      labels,
+		}
+	}
+	return rules, alerts
+}
+
+func TestEqualIndex_MatchesNaiveEvaluation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	rules, alerts := randomInhibitionSetup(rng, 20, 60)
+	if err := CompileRules(rules); err != nil {
+		t.Fatalf("CompileRules() error = %v", err)
+	}
+
+	cache := &mockCache{firingAlerts: alerts}
+	matcher := NewMatcher(cache, rules, nil)
+	for _, target := range alerts {
+		want := naiveInhibitors(rules, alerts, target)
+		if got := indexedInhibitors(t, matcher, target); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("inhibitors of %s = %v, want %v", target.Fingerprint, got, want)
+		}
+	}
+
+	// Remove and replace alerts; the index must follow incrementally.
+	cache.firingAlerts = append([]*core.Alert(nil), alerts[10:]...)
+	replaced := *cache.firingAlerts[0]
+	replaced.Labels = map[string]string{"alertname": "NodeDown", "node": "node0", "cluster": "cluster0"}
+	cache.firingAlerts[0] = &replaced
+	for _, target := range alerts {
+		want := naiveInhibitors(rules, cache.firingAlerts, target)
+		if got := indexedInhibitors(t, matcher, target); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("after update, inhibitors of %s = %v, want %v", target.Fingerprint, got, want)
+		}
+	}
+}
+
+func TestEqualIndex_VersionedCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewTwoTierAlertCache(nil, nil)
+	defer cache.Stop()
+
+	matcher := NewMatcher(cache, []InhibitionRule{createTestRule("rule")}, nil)
+	source := createTestAlert("NodeDown", "critical", "node1", "prod")
+	target := createTestAlert("InstanceDown", "warning", "node1", "prod")
+
+	check := func(want bool) {
+		t.Helper()
+		result, err := matcher.ShouldInhibit(ctx, target)
+		if err != nil {
+			t.Fatalf("ShouldInhibit() error = %v", err)
+		}
+		if result.Matched != want {
+			t.Errorf("ShouldInhibit() matched = %v, want %v", result.Matched, want)
+		}
+	}
+
+	check(false)
+	if err := cache.AddFiringAlert(ctx, source); err != nil {
+		t.Fatalf("AddFiringAlert() error = %v", err)
+	}
+	check(true)
+	check(true) // served from the index without re-reading the cache
+
+	moved := createTestAlert("NodeDown", "critical", "node2", "prod")
+	moved.Fingerprint = source.Fingerprint
+	if err := cache.AddFiringAlert(ctx, moved); err != nil {
+		t.Fatalf("AddFiringAlert() error = %v", err)
+	}
+	check(false)
+
+	if err := cache.AddFiringAlert(ctx, source); err != nil {
+		t.Fatalf("AddFiringAlert() error = %v", err)
+	}
+	check(true)
+	if err := cache.RemoveAlert(ctx, source.Fingerprint); err != nil {
+		t.Fatalf("RemoveAlert() error = %v", err)
+	}
+	check(false)
+}
+
+func TestEqualIndex_AppliesCacheChanges(t *testing.T) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(2))
+	rules, alerts := randomInhibitionSetup(rng, 20, 60)
+	if err := CompileRules(rules); err != nil {
+		t.Fatalf("CompileRules() error = %v", err)
+	}
+
+	cache := NewTwoTierAlertCache(nil, nil)
+	defer cache.Stop()
+	matcher := NewMatcher(cache, rules, nil)
+	firing := map[string]*core.Alert{}
+	for i := 0; i < 300; i++ {
+		alert := alerts[rng.Intn(len(alerts))]
+		if rng.Intn(3) == 0 {
+			_ = cache.RemoveAlert(ctx, alert.Fingerprint)
+			delete(firing, alert.Fingerprint)
+		} else {
+			_ = cache.AddFiringAlert(ctx, alert)
+			firing[alert.Fingerprint] = alert
+		}
+
+		current := make([]*core.Alert, 0, len(firing))
+		for _, a := range firing {
+			current = append(current, a)
+		}
+		target := alerts[rng.Intn(len(alerts))]
+		want := naiveInhibitors(rules, current, target)
+		if got := indexedInhibitors(t, matcher, target); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("step %d: inhibitors of %s = %v, want %v", i, target.Fingerprint, got, want)
+		}
+	}
+}
+
+func TestEqualIndex_RebuiltOnRuleReload(t *testing.T) {
+	source := createTestAlert("NodeDown", "critical", "node1", "prod")
+	target := createTestAlert("InstanceDown", "warning", "node2", "prod")
+	matcher := NewMatcher(&mockCache{firingAlerts: []*core.Alert{source}}, []InhibitionRule{createTestRule("rule")}, nil)
+
+	result, err := matcher.ShouldInhibit(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ShouldInhibit() error = %v", err)
+	}
+	if result.Matched {
+		t.Fatal("different nodes matched before reload")
+	}
+
+	rule := createTestRule("rule")
+	rule.Equal = []string{"cluster"}
+	if _, err := matcher.ReloadRules([]InhibitionRule{rule}); err != nil {
+		t.Fatalf("ReloadRules() error = %v", err)
+	}
+	result, err = matcher.ShouldInhibit(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ShouldInhibit() error = %v", err)
+	}
+	if !result.Matched {
+		t.Error("reloaded rule with equal [cluster] did not match")
+	}
+}
+
+func TestEqualKey(t *testing.T) {
+	labels := map[string]string{"node": "n1", "cluster": "c1"}
+	tests := []struct {
+		equal []string
+		want  string
+	}{
+		{equal: nil, want: ""},
+		{equal: []string{"node"}, want: "n1"},
+		{equal: []string{"node", "cluster"}, want: "n1\xffc1"},
+		{equal: []string{"node", "missing"}, want: "n1\xff"},
+	}
+	for _, tt := range tests {
+		if got := equalKey(tt.equal, labels); got != tt.want {
+			t.Errorf("equalKey(%v) = %q, want %q", tt.equal, got, tt.want)
+		}
+	}
+}
+
+// --- Benchmarks ---
+
+// largeInhibitionSetup returns 200 rules (one source alertname each, equal
+// on node) and 5000 firing alerts spread over 100 nodes, plus a target.
+func largeInhibitionSetup(b *testing.B) (*TwoTierAlertCache, []InhibitionRule, *core.Alert) {
+	b.Helper()
+	cache := NewTwoTierAlertCacheWithOptions(nil, nil, &AlertCacheOptions{L1Max: 10000})
+
+	rules := make([]InhibitionRule, 200)
+	for i := range rules {
+		rules[i] = InhibitionRule{
+			Name:        fmt.Sprintf("rule%d", i),
+			SourceMatch: map[string]string{"alertname": fmt.Sprintf("Source%d", i)},
+			TargetMatch: map[string]string{"severity": "warning"},
+			Equal:       []string{"node"},
+		}
+	}
+	if err := CompileRules(rules); err != nil {
+		b.Fatalf("CompileRules() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 5000; i++ {
+		alert := createTestAlert(fmt.Sprintf("Source%d", i%200), "critical", fmt.Sprintf("node%d", i%100), "prod")
+		alert.Fingerprint = fmt.Sprintf("fp-%d", i)
+		if err := cache.AddFiringAlert(ctx, alert); err != nil {
+			b.Fatalf("AddFiringAlert() error = %v", err)
+		}
+	}
+
+	// No source shares the target's node, so every rule is evaluated.
+	target := createTestAlert("InstanceDown", "warning", "node-unknown", "prod")
+	return cache, rules, target
+}
+
+func BenchmarkShouldInhibit_5000Alerts_200Rules_Indexed(b *testing.B) {
+	cache, rules, target := largeInhibitionSetup(b)
+	defer cache.Stop()
+	matcher := NewMatcher(cache, rules, nil)
+	ctx := context.Background()
+	_, _ = matcher.ShouldInhibit(ctx, target) // build the index
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = matcher.ShouldInhibit(ctx, target)
+	}
+}
+
+// BenchmarkShouldInhibit_5000Alerts_200Rules_Churn adds a firing alert
+// before every check, as the alert processor does, so each check applies
+// the cache changes to the index.
+func BenchmarkShouldInhibit_5000Alerts_200Rules_Churn(b *testing.B) {
+	cache, rules, target := largeInhibitionSetup(b)
+	defer cache.Stop()
+	matcher := NewMatcher(cache, rules, nil)
+	ctx := context.Background()
+	_, _ = matcher.ShouldInhibit(ctx, target)
+
+	alerts := make([]*core.Alert, 100)
+	for i := range alerts {
+		alerts[i] = createTestAlert(fmt.Sprintf("Source%d", i), "critical", fmt.Sprintf("node%d", i), "prod")
+		alerts[i].Fingerprint = fmt.Sprintf("fp-churn-%d", i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.AddFiringAlert(ctx, alerts[i%len(alerts)])
+		_, _ = matcher.ShouldInhibit(ctx, target)
+	}
+}
+
+// BenchmarkShouldInhibit_5000Alerts_200Rules_Naive is the O(rules × alerts)
+// evaluation the index replaces, for comparison.
+func BenchmarkShouldInhibit_5000Alerts_200Rules_Naive(b *testing.B) {
+	cache, rules, target := largeInhibitionSetup(b)
+	defer cache.Stop()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		firing, _ := cache.GetFiringAlerts(ctx)
+		for r := range rules {
+			for _, source := range firing {
+				if ruleInhibits(&rules[r], source, target) {
+					break
+				}
+			}
+		}
+	}
+}
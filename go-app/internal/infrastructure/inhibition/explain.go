@@ -0,0 +1,121 @@
+package inhibition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrAlertNotFiring is returned by Explain for alerts that are not in the
+// firing alert cache. Only firing alerts are checked for inhibition.
+var ErrAlertNotFiring = errors.New("alert is not firing")
+
+// Explanation tells why an alert is or is not inhibited.
+type Explanation struct {
+	Fingerprint string `json:"fingerprint"`
+	AlertName   string `json:"alertName"`
+	Inhibited   bool   `json:"inhibited"`
+	// Rules lists every rule in evaluation order.
+	Rules []RuleExplanation `json:"rules"`
+}
+
+// RuleExplanation is the evaluation of one rule for the target alert.
+type RuleExplanation struct {
+	Rule string `json:"rule"`
+	// TargetMatched reports whether the alert matches target_match and
+	// target_match_re. Sources are only evaluated when it does.
+	TargetMatched bool `json:"targetMatched"`
+	// Inhibits reports whether any source inhibits the alert by this rule.
+	Inhibits bool `json:"inhibits"`
+	// Sources lists the firing alerts matching the source side of the rule.
+	Sources []SourceExplanation `json:"sources,omitempty"`
+}
+
+// SourceExplanation is the evaluation of one candidate source alert.
+type SourceExplanation struct {
+	Fingerprint string `json:"fingerprint"`
+	AlertName   string `json:"alertName"`
+	// TwoSided reports that the source and the target both match both sides
+	// of the rule; such alerts never inhibit each other.
+	TwoSided bool               `json:"twoSided,omitempty"`
+	Equal    []EqualLabelResult `json:"equal,omitempty"`
+	Inhibits bool               `json:"inhibits"`
+}
+
+// EqualLabelResult compares one label of the rule's equal list. Missing
+// labels compare as empty values.
+type EqualLabelResult struct {
+	Label       string `json:"label"`
+	SourceValue string `json:"sourceValue"`
+	TargetValue string `json:"targetValue"`
+	Equal       bool   `json:"equal"`
+}
+
+// Explain evaluates all rules for the firing alert with the given
+// fingerprint, using the same semantics as ShouldInhibit, and reports each
+// step. Unlike ShouldInhibit it does not stop at the first match and is
+// meant for debugging, not for the hot path.
+//
+// Returns ErrAlertNotFiring if the alert is not in the firing alert cache.
+func (m *DefaultInhibitionMatcher) Explain(ctx context.Context, fingerprint string) (*Explanation, error) {
+	firingAlerts, err := m.cache.GetFiringAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firing alerts: %w", err)
+	}
+
+	targetIdx := -1
+	for i, alert := range firingAlerts {
+		if alert.Fingerprint == fingerprint {
+			targetIdx = i
+			break
+		}
+	}
+	if targetIdx < 0 {
+		return nil, ErrAlertNotFiring
+	}
+	target := firingAlerts[targetIdx]
+
+	rules := m.Rules()
+	ids := ruleIDs(rules)
+	explanation := &Explanation{
+		Fingerprint: target.Fingerprint,
+		AlertName:   target.AlertName,
+		Rules:       make([]RuleExplanation, 0, len(rules)),
+	}
+	for i := range rules {
+		rule := &rules[i]
+		ruleExp := RuleExplanation{
+			Rule:          ids[i],
+			TargetMatched: matchTargetSide(rule, target.Labels),
+		}
+		if ruleExp.TargetMatched {
+			for _, source := range firingAlerts {
+				if source.Fingerprint == target.Fingerprint || !matchSourceSide(rule, source.Labels) {
+					continue
+				}
+				sourceExp := SourceExplanation{
+					Fingerprint: source.Fingerprint,
+					AlertName:   source.AlertName,
+					TwoSided:    matchSourceSide(rule, target.Labels) && matchTargetSide(rule, source.Labels),
+				}
+				equal := true
+				for _, label := range rule.Equal {
+					result := EqualLabelResult{
+						Label:       label,
+						SourceValue: source.Labels[label],
+						TargetValue: target.Labels[label],
+					}
+					result.Equal = result.SourceValue == result.TargetValue
+					equal = equal && result.Equal
+					sourceExp.Equal = append(sourceExp.Equal, result)
+				}
+				sourceExp.Inhibits = !sourceExp.TwoSided && equal
+				ruleExp.Inhibits = ruleExp.Inhibits || sourceExp.Inhibits
+				ruleExp.Sources = append(ruleExp.Sources, sourceExp)
+			}
+		}
+		explanation.Inhibited = explanation.Inhibited || ruleExp.Inhibits
+		explanation.Rules = append(explanation.Rules, ruleExp)
+	}
+	return explanation, nil
+}
@@ -0,0 +1,77 @@
+package inhibition
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestExplain(t *testing.T) {
+	sameNode := createTestAlert("NodeDown", "critical", "node1", "prod")
+	otherNode := createTestAlert("NodeDown", "critical", "node2", "prod")
+	target := createTestAlert("InstanceDown", "warning", "node1", "prod")
+	cache := &mockCache{firingAlerts: []*core.Alert{sameNode, otherNode, target}}
+
+	podRule := createTestRule("pods")
+	podRule.TargetMatch = map[string]string{"alertname": "PodDown"}
+	matcher := NewMatcher(cache, []InhibitionRule{podRule, createTestRule("node-down")}, nil)
+
+	explanation, err := matcher.Explain(context.Background(), target.Fingerprint)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if !explanation.Inhibited {
+		t.Error("Explain().Inhibited = false, want true")
+	}
+	if len(explanation.Rules) != 2 {
+		t.Fatalf("Explain() rules = %d, want 2", len(explanation.Rules))
+	}
+
+	if pods := explanation.Rules[0]; pods.TargetMatched || pods.Inhibits || len(pods.Sources) != 0 {
+		t.Errorf("pods rule = %+v, want target not matched and no sources", pods)
+	}
+
+	nodeDown := explanation.Rules[1]
+	if !nodeDown.TargetMatched || !nodeDown.Inhibits || len(nodeDown.Sources) != 2 {
+		t.Fatalf("node-down rule = %+v, want two sources and inhibition", nodeDown)
+	}
+	if src := nodeDown.Sources[0]; src.Fingerprint != sameNode.Fingerprint || !src.Inhibits {
+		t.Errorf("source %+v, want %s inhibiting", src, sameNode.Fingerprint)
+	}
+	wantEqual := []EqualLabelResult{
+		{Label: "node", SourceValue: "node2", TargetValue: "node1", Equal: false},
+		{Label: "cluster", SourceValue: "prod", TargetValue: "prod", Equal: true},
+	}
+	if src := nodeDown.Sources[1]; src.Inhibits || !reflect.DeepEqual(src.Equal, wantEqual) {
+		t.Errorf("source %+v, want equal results %+v and no inhibition", src, wantEqual)
+	}
+}
+
+func TestExplain_AgreesWithShouldInhibit(t *testing.T) {
+	source := createTestAlert("NodeDown", "critical", "node2", "prod")
+	target := createTestAlert("InstanceDown", "warning", "node1", "prod")
+	matcher := NewMatcher(&mockCache{firingAlerts: []*core.Alert{source, target}}, []InhibitionRule{createTestRule("node-down")}, nil)
+
+	result, err := matcher.ShouldInhibit(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ShouldInhibit() error = %v", err)
+	}
+	explanation, err := matcher.Explain(context.Background(), target.Fingerprint)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if explanation.Inhibited != result.Matched {
+		t.Errorf("Explain().Inhibited = %v, ShouldInhibit().Matched = %v", explanation.Inhibited, result.Matched)
+	}
+}
+
+func TestExplain_AlertNotFiring(t *testing.T) {
+	matcher := NewMatcher(&mockCache{}, []InhibitionRule{createTestRule("node-down")}, nil)
+
+	if _, err := matcher.Explain(context.Background(), "unknown"); !errors.Is(err, ErrAlertNotFiring) {
+		t.Errorf("Explain() error = %v, want ErrAlertNotFiring", err)
+	}
+}
@@ -0,0 +1,326 @@
+package inhibition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// ErrUnknownExternalSource is returned for updates of a source that was not
+// registered with AddSource.
+var ErrUnknownExternalSource = errors.New("unknown external inhibition source")
+
+// DefaultExternalStaleAfter is how long alerts of an external source stay
+// active without an update.
+const DefaultExternalStaleAfter = 5 * time.Minute
+
+// ExternalSources holds source alerts that fire in other Alertmanager
+// clusters, e.g. a legacy cluster during a gradual migration, so that they
+// can inhibit alerts in AMP.
+//
+// A source is fed either by polling the cluster's /api/v2/alerts (Replace,
+// see ExternalSourcePoller) or by the cluster's webhook notifications
+// (Apply). Alerts that were not refreshed within the source's staleAfter
+// expire: a failing poller or a webhook that stopped sending must not keep
+// alerts inhibited forever.
+//
+// Thread-safety: safe for concurrent use.
+type ExternalSources struct {
+	mu      sync.Mutex
+	sources map[string]*externalSource
+	// nextExpiry is the earliest expiry of a held alert (zero: none known).
+	nextExpiry time.Time
+	version    uint64
+	now        func() time.Time
+}
+
+type externalSource struct {
+	staleAfter  time.Duration
+	alerts      map[string]externalAlert // by fingerprint
+	updatedAt   time.Time
+	lastError   string
+	lastErrorAt time.Time
+}
+
+type externalAlert struct {
+	alert     *core.Alert
+	expiresAt time.Time
+}
+
+// ExternalSourceStatus describes an external source.
+type ExternalSourceStatus struct {
+	Name string `json:"name"`
+	// Alerts is the number of active source alerts.
+	Alerts      int        `json:"alerts"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+// NewExternalSources creates an empty set of external sources.
+func NewExternalSources() *ExternalSources {
+	return &ExternalSources{
+		sources: make(map[string]*externalSource),
+		now:     time.Now,
+	}
+}
+
+// AddSource registers a source. staleAfter <= 0 uses
+// DefaultExternalStaleAfter. Registering an existing source only updates
+// its staleAfter.
+func (s *ExternalSources) AddSource(name string, staleAfter time.Duration) {
+	if staleAfter <= 0 {
+		staleAfter = DefaultExternalStaleAfter
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src, ok := s.sources[name]; ok {
+		src.staleAfter = staleAfter
+		return
+	}
+	s.sources[name] = &externalSource{staleAfter: staleAfter, alerts: make(map[string]externalAlert)}
+}
+
+// Replace sets the firing alerts of a source to a complete snapshot, as
+// returned by polling the cluster. Alerts missing from the snapshot are
+// resolved.
+func (s *ExternalSources) Replace(name string, alerts []*core.Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.sources[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownExternalSource, name)
+	}
+
+	now := s.now()
+	next := make(map[string]externalAlert, len(alerts))
+	changed := false
+	for _, alert := range alerts {
+		if !isFiringAt(alert, now) {
+			continue
+		}
+		expiresAt := expiryOf(alert, now, src.staleAfter)
+		if prev, ok := src.alerts[alert.Fingerprint]; ok && maps.Equal(prev.alert.Labels, alert.Labels) {
+			// Keep the held alert so the equal-labels index is not rebuilt.
+			alert = prev.alert
+		} else {
+			changed = true
+		}
+		next[alert.Fingerprint] = externalAlert{alert: alert, expiresAt: expiresAt}
+	}
+	if len(next) != len(src.alerts) {
+		changed = true
+	}
+
+	src.alerts = next
+	src.updatedAt = now
+	src.lastError = ""
+	s.updateNextExpiry()
+	if changed {
+		s.version++
+	}
+	return nil
+}
+
+// Apply applies incremental updates of a source, as sent by the cluster's
+// webhook notifications: firing alerts are added or refreshed, resolved
+// alerts are removed.
+func (s *ExternalSources) Apply(name string, alerts []*core.Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.sources[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownExternalSource, name)
+	}
+
+	now := s.now()
+	changed := false
+	for _, alert := range alerts {
+		prev, held := src.alerts[alert.Fingerprint]
+		if !isFiringAt(alert, now) {
+			if held {
+				delete(src.alerts, alert.Fingerprint)
+				changed = true
+			}
+			continue
+		}
+		expiresAt := expiryOf(alert, now, src.staleAfter)
+		if held && maps.Equal(prev.alert.Labels, alert.Labels) {
+			alert = prev.alert
+		} else {
+			changed = true
+		}
+		src.alerts[alert.Fingerprint] = externalAlert{alert: alert, expiresAt: expiresAt}
+	}
+
+	src.updatedAt = now
+	src.lastError = ""
+	s.updateNextExpiry()
+	if changed {
+		s.version++
+	}
+	return nil
+}
+
+// RecordError records a failed update of a source. Its alerts are kept
+// until they expire.
+func (s *ExternalSources) RecordError(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src, ok := s.sources[name]; ok {
+		src.lastError = err.Error()
+		src.lastErrorAt = s.now()
+	}
+}
+
+// Alerts returns the active source alerts of all sources.
+func (s *ExternalSources) Alerts() []*core.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.expire(now)
+	var alerts []*core.Alert
+	for _, src := range s.sources {
+		for _, held := range src.alerts {
+			alerts = append(alerts, held.alert)
+		}
+	}
+	return alerts
+}
+
+// Version returns a counter that changes whenever a source alert is added,
+// replaced, removed or expires.
+func (s *ExternalSources) Version() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(s.now())
+	return s.version
+}
+
+// Status returns the status of all sources, sorted by name.
+func (s *ExternalSources) Status() []ExternalSourceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(s.now())
+	statuses := make([]ExternalSourceStatus, 0, len(s.sources))
+	for name, src := range s.sources {
+		status := ExternalSourceStatus{Name: name, Alerts: len(src.alerts), LastError: src.lastError}
+		if !src.updatedAt.IsZero() {
+			updatedAt := src.updatedAt.UTC()
+			status.UpdatedAt = &updatedAt
+		}
+		if src.lastError != "" {
+			lastErrorAt := src.lastErrorAt.UTC()
+			status.LastErrorAt = &lastErrorAt
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// expire drops expired alerts. Must be called with mu held.
+func (s *ExternalSources) expire(now time.Time) {
+	if s.nextExpiry.IsZero() || now.Before(s.nextExpiry) {
+		return
+	}
+	for _, src := range s.sources {
+		for fingerprint, held := range src.alerts {
+			if !now.Before(held.expiresAt) {
+				delete(src.alerts, fingerprint)
+			}
+		}
+	}
+	s.version++
+	s.updateNextExpiry()
+}
+
+// updateNextExpiry recomputes nextExpiry. Must be called with mu held.
+func (s *ExternalSources) updateNextExpiry() {
+	s.nextExpiry = time.Time{}
+	for _, src := range s.sources {
+		for _, held := range src.alerts {
+			if s.nextExpiry.IsZero() || held.expiresAt.Before(s.nextExpiry) {
+				s.nextExpiry = held.expiresAt
+			}
+		}
+	}
+}
+
+func isFiringAt(alert *core.Alert, now time.Time) bool {
+	if alert == nil || alert.Status != core.StatusFiring {
+		return false
+	}
+	return alert.EndsAt == nil || alert.EndsAt.After(now)
+}
+
+// expiryOf returns when a refreshed alert expires: after staleAfter, or at
+// its EndsAt if that is earlier.
+func expiryOf(alert *core.Alert, now time.Time, staleAfter time.Duration) time.Time {
+	expiresAt := now.Add(staleAfter)
+	if alert.EndsAt != nil && alert.EndsAt.Before(expiresAt) {
+		expiresAt = *alert.EndsAt
+	}
+	return expiresAt
+}
+
+// ExternalSourceCache serves the firing alerts of a local cache together
+// with the alerts of external sources. Writes go to the local cache only.
+type ExternalSourceCache struct {
+	local   ActiveAlertCache
+	sources *ExternalSources
+}
+
+var _ VersionedAlertCache = (*ExternalSourceCache)(nil)
+
+// NewExternalSourceCache wraps local with the alerts of sources.
+func NewExternalSourceCache(local ActiveAlertCache, sources *ExternalSources) *ExternalSourceCache {
+	return &ExternalSourceCache{local: local, sources: sources}
+}
+
+// GetFiringAlerts returns the local and the external firing alerts.
+func (c *ExternalSourceCache) GetFiringAlerts(ctx context.Context) ([]*core.Alert, error) {
+	alerts, err := c.local.GetFiringAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	external := c.sources.Alerts()
+	if len(external) == 0 {
+		return alerts, nil
+	}
+	merged := make([]*core.Alert, 0, len(alerts)+len(external))
+	merged = append(merged, alerts...)
+	return append(merged, external...), nil
+}
+
+// AddFiringAlert adds a local firing alert.
+func (c *ExternalSourceCache) AddFiringAlert(ctx context.Context, alert *core.Alert) error {
+	return c.local.AddFiringAlert(ctx, alert)
+}
+
+// RemoveAlert removes a local alert.
+func (c *ExternalSourceCache) RemoveAlert(ctx context.Context, fingerprint string) error {
+	return c.local.RemoveAlert(ctx, fingerprint)
+}
+
+// Version combines the versions of the local cache and the external
+// sources; both only grow, so their sum changes whenever either does.
+func (c *ExternalSourceCache) Version() (uint64, bool) {
+	versioned, ok := c.local.(VersionedAlertCache)
+	if !ok {
+		return 0, false
+	}
+	local, ok := versioned.Version()
+	if !ok {
+		return 0, false
+	}
+	return local + c.sources.Version(), true
+}
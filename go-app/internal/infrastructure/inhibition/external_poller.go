@@ -0,0 +1,139 @@
+package inhibition
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// DefaultExternalPollInterval is how often an external Alertmanager is
+// polled for source alerts.
+const DefaultExternalPollInterval = 30 * time.Second
+
+// ExternalAlertLister lists the alerts of an external Alertmanager
+// (GET /api/v2/alerts).
+type ExternalAlertLister interface {
+	ListAlerts(ctx context.Context) ([]core.APIGettableAlert, error)
+}
+
+// ExternalSourcePoller keeps one source of ExternalSources up to date by
+// polling an external Alertmanager.
+type ExternalSourcePoller struct {
+	name     string
+	lister   ExternalAlertLister
+	sources  *ExternalSources
+	interval time.Duration
+	logger   *slog.Logger
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// NewExternalSourcePoller creates a poller for the source name, which must be
+// registered in sources. interval <= 0 uses DefaultExternalPollInterval.
+func NewExternalSourcePoller(name string, lister ExternalAlertLister, sources *ExternalSources, interval time.Duration, logger *slog.Logger) *ExternalSourcePoller {
+	if interval <= 0 {
+		interval = DefaultExternalPollInterval
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ExternalSourcePoller{
+		name:     name,
+		lister:   lister,
+		sources:  sources,
+		interval: interval,
+		logger:   logger.With("inhibition_source", name),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start polls once and then every interval until Stop or ctx is done.
+func (p *ExternalSourcePoller) Start(ctx context.Context) {
+	p.done.Add(1)
+	go func() {
+		defer p.done.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			if err := p.Poll(ctx); err != nil {
+				p.logger.Warn("Failed to poll external inhibition source", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the poller and waits for it to exit.
+func (p *ExternalSourcePoller) Stop() {
+	close(p.stop)
+	p.done.Wait()
+}
+
+// Poll fetches the alerts of the external Alertmanager once and replaces
+// the source's alerts with them.
+func (p *ExternalSourcePoller) Poll(ctx context.Context) error {
+	gettable, err := p.lister.ListAlerts(ctx)
+	if err != nil {
+		p.sources.RecordError(p.name, err)
+		return err
+	}
+
+	alerts := make([]*core.Alert, 0, len(gettable))
+	for i := range gettable {
+		alert, err := alertFromGettable(&gettable[i])
+		if err != nil {
+			p.logger.Debug("Skipping invalid external alert", "fingerprint", gettable[i].Fingerprint, "error", err)
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	return p.sources.Replace(p.name, alerts)
+}
+
+// alertFromGettable converts an Alertmanager API v2 alert. Silenced and
+// inhibited alerts stay firing: as in Alertmanager, suppressed alerts still
+// inhibit others.
+func alertFromGettable(in *core.APIGettableAlert) (*core.Alert, error) {
+	if in.Fingerprint == "" {
+		return nil, fmt.Errorf("missing fingerprint")
+	}
+	startsAt, err := time.Parse(time.RFC3339Nano, in.StartsAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startsAt: %w", err)
+	}
+
+	alert := &core.Alert{
+		Fingerprint: in.Fingerprint,
+		AlertName:   in.Labels[core.LabelAlertName],
+		Status:      core.StatusFiring,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+		StartsAt:    startsAt,
+	}
+	if in.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339Nano, in.EndsAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endsAt: %w", err)
+		}
+		if !endsAt.IsZero() {
+			alert.EndsAt = &endsAt
+		}
+	}
+	if in.GeneratorURL != "" {
+		generatorURL := in.GeneratorURL
+		alert.GeneratorURL = &generatorURL
+	}
+	return alert, nil
+}
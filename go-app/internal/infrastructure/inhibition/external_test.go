@@ -0,0 +1,212 @@
+package inhibition
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// versionedMockCache is a mockCache that reports a version.
+type versionedMockCache struct {
+	mockCache
+	version uint64
+}
+
+func (m *versionedMockCache) Version() (uint64, bool) {
+	return m.version, true
+}
+
+type fakeExternalLister struct {
+	alerts []core.APIGettableAlert
+	err    error
+}
+
+func (l *fakeExternalLister) ListAlerts(ctx context.Context) ([]core.APIGettableAlert, error) {
+	return l.alerts, l.err
+}
+
+func newTestExternalSources(now *time.Time) *ExternalSources {
+	sources := NewExternalSources()
+	sources.now = func() time.Time { return *now }
+	sources.AddSource("legacy", time.Minute)
+	return sources
+}
+
+func fingerprints(alerts []*core.Alert) map[string]bool {
+	out := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		out[alert.Fingerprint] = true
+	}
+	return out
+}
+
+func TestExternalSources_Replace(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sources := newTestExternalSources(&now)
+
+	a := createTestAlert("NodeDown", "critical", "node1", "prod")
+	b := createTestAlert("NodeDown", "critical", "node2", "prod")
+	if err := sources.Replace("legacy", []*core.Alert{a, b}); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got := fingerprints(sources.Alerts()); len(got) != 2 || !got[a.Fingerprint] || !got[b.Fingerprint] {
+		t.Fatalf("Alerts() = %v, want both alerts", got)
+	}
+
+	// An unchanged snapshot keeps the version; a missing alert is resolved.
+	version := sources.Version()
+	if err := sources.Replace("legacy", []*core.Alert{a, b}); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if sources.Version() != version {
+		t.Error("Version() changed for an unchanged snapshot")
+	}
+	if err := sources.Replace("legacy", []*core.Alert{a}); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if sources.Version() == version {
+		t.Error("Version() unchanged after an alert was resolved")
+	}
+	if got := fingerprints(sources.Alerts()); len(got) != 1 || !got[a.Fingerprint] {
+		t.Fatalf("Alerts() = %v, want only %s", got, a.Fingerprint)
+	}
+
+	if err := sources.Replace("unknown", nil); !errors.Is(err, ErrUnknownExternalSource) {
+		t.Errorf("Replace(unknown) error = %v, want ErrUnknownExternalSource", err)
+	}
+}
+
+func TestExternalSources_Apply(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sources := newTestExternalSources(&now)
+
+	a := createTestAlert("NodeDown", "critical", "node1", "prod")
+	b := createTestAlert("NodeDown", "critical", "node2", "prod")
+	if err := sources.Apply("legacy", []*core.Alert{a}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if err := sources.Apply("legacy", []*core.Alert{b}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := sources.Alerts(); len(got) != 2 {
+		t.Fatalf("Alerts() = %d alerts, want 2", len(got))
+	}
+
+	resolved := *a
+	resolved.Status = core.StatusResolved
+	if err := sources.Apply("legacy", []*core.Alert{&resolved}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := fingerprints(sources.Alerts()); len(got) != 1 || !got[b.Fingerprint] {
+		t.Fatalf("Alerts() = %v, want only %s", got, b.Fingerprint)
+	}
+}
+
+func TestExternalSources_Expiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sources := newTestExternalSources(&now)
+
+	a := createTestAlert("NodeDown", "critical", "node1", "prod")
+	b := createTestAlert("NodeDown", "critical", "node2", "prod")
+	endsAt := now.Add(10 * time.Second)
+	b.EndsAt = &endsAt
+	if err := sources.Apply("legacy", []*core.Alert{a, b}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	version := sources.Version()
+
+	// b ends before the source goes stale.
+	now = now.Add(20 * time.Second)
+	if got := fingerprints(sources.Alerts()); len(got) != 1 || !got[a.Fingerprint] {
+		t.Fatalf("Alerts() = %v, want only %s", got, a.Fingerprint)
+	}
+	if sources.Version() == version {
+		t.Error("Version() unchanged after an alert ended")
+	}
+
+	// A failing source keeps its alerts until they go stale.
+	sources.RecordError("legacy", errors.New("connection refused"))
+	status := sources.Status()
+	if len(status) != 1 || status[0].Alerts != 1 || status[0].LastError != "connection refused" || status[0].LastErrorAt == nil {
+		t.Fatalf("Status() = %+v, want one alert and the last error", status)
+	}
+	now = now.Add(time.Minute)
+	if got := sources.Alerts(); len(got) != 0 {
+		t.Fatalf("Alerts() = %d alerts after staleAfter, want 0", len(got))
+	}
+}
+
+func TestExternalSourceCache_InhibitsLocalAlerts(t *testing.T) {
+	now := time.Now()
+	sources := newTestExternalSources(&now)
+	local := &versionedMockCache{}
+	matcher := NewMatcher(NewExternalSourceCache(local, sources), []InhibitionRule{createTestRule("node-down")}, nil)
+
+	target := createTestAlert("InstanceDown", "warning", "node1", "prod")
+	result, err := matcher.ShouldInhibit(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ShouldInhibit() error = %v", err)
+	}
+	if result.Matched {
+		t.Fatal("ShouldInhibit() matched without source alerts")
+	}
+
+	source := createTestAlert("NodeDown", "critical", "node1", "prod")
+	if err := sources.Apply("legacy", []*core.Alert{source}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	result, err = matcher.ShouldInhibit(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ShouldInhibit() error = %v", err)
+	}
+	if !result.Matched || result.InhibitedBy.Fingerprint != source.Fingerprint {
+		t.Fatalf("ShouldInhibit() = %+v, want inhibited by the external alert", result)
+	}
+
+	resolved := *source
+	resolved.Status = core.StatusResolved
+	if err := sources.Apply("legacy", []*core.Alert{&resolved}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	result, err = matcher.ShouldInhibit(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ShouldInhibit() error = %v", err)
+	}
+	if result.Matched {
+		t.Fatal("ShouldInhibit() matched after the external alert resolved")
+	}
+}
+
+func TestExternalSourcePoller_Poll(t *testing.T) {
+	now := time.Now()
+	sources := newTestExternalSources(&now)
+	lister := &fakeExternalLister{alerts: []core.APIGettableAlert{
+		{
+			Labels:      map[string]string{"alertname": "NodeDown", "node": "node1"},
+			StartsAt:    now.Add(-time.Minute).Format(time.RFC3339Nano),
+			Fingerprint: "abc",
+		},
+		// Invalid alerts are skipped.
+		{Labels: map[string]string{"alertname": "Broken"}},
+	}}
+	poller := NewExternalSourcePoller("legacy", lister, sources, 0, nil)
+
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	alerts := sources.Alerts()
+	if len(alerts) != 1 || alerts[0].Fingerprint != "abc" || alerts[0].AlertName != "NodeDown" {
+		t.Fatalf("Alerts() = %+v, want the polled alert", alerts)
+	}
+
+	lister.err = errors.New("timeout")
+	if err := poller.Poll(context.Background()); err == nil {
+		t.Fatal("Poll() error = nil, want the lister error")
+	}
+	if status := sources.Status(); status[0].LastError != "timeout" || status[0].Alerts != 1 {
+		t.Fatalf("Status() = %+v, want the error recorded and the alert kept", status)
+	}
+}
@@ -180,3 +180,37 @@ type ActiveAlertCache interface {
 	// Used when alerts resolve or expire.
 	RemoveAlert(ctx context.Context, fingerprint string) error
 }
+
+// VersionedAlertCache is an ActiveAlertCache that tracks changes to the
+// firing alerts. The matcher re-reads the firing alerts for its equal-labels
+// index only when the version changed.
+type VersionedAlertCache interface {
+	ActiveAlertCache
+
+	// Version returns a counter that changes whenever a firing alert is
+	// added, replaced or removed. ok is false when the alerts cannot be
+	// versioned right now (e.g. they are read from Redis) and must be
+	// re-read.
+	Version() (version uint64, ok bool)
+}
+
+// IncrementalAlertCache is a VersionedAlertCache that also reports the
+// changes between versions, so that the matcher updates its equal-labels
+// index with the changed alerts instead of re-reading all firing alerts.
+type IncrementalAlertCache interface {
+	VersionedAlertCache
+
+	// ChangesSince returns the alerts added, replaced or removed after
+	// version, oldest first, and the current version. ok is false when the
+	// changes are no longer known or the alerts cannot be versioned; the
+	// firing alerts must then be re-read.
+	ChangesSince(version uint64) (changes []AlertChange, current uint64, ok bool)
+}
+
+// AlertChange is an alert added to, replaced in or removed from an
+// IncrementalAlertCache.
+type AlertChange struct {
+	Fingerprint string
+	// Alert is the new alert, nil when it was removed.
+	Alert *core.Alert
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
@@ -12,10 +14,12 @@ import (
 // DefaultInhibitionMatcher is the standard implementation of InhibitionMatcher.
 //
 // Thread-safety: Safe for concurrent use (all operations are read-only or use thread-safe cache).
+// Rules are replaced atomically by ReloadRules; checks in flight finish with
+// the rules they started with.
 // Performance: <500µs per inhibition check (p99), <5µs per rule matching.
 //
 // Optimizations:
-//   - Alert pre-filtering by alertname (source_match)
+//   - Inverted index of source alerts by equal label values (equalIndex)
 //   - Early exit on first mismatch
 //   - Zero allocations in hot path
 //   - Inlined label checking
@@ -26,8 +30,15 @@ import (
 //	result, err := matcher.ShouldInhibit(ctx, targetAlert)
 type DefaultInhibitionMatcher struct {
 	cache  ActiveAlertCache
-	rules  []InhibitionRule
+	rules  atomic.Pointer[[]InhibitionRule]
 	logger *slog.Logger
+
+	// reloadMu serializes ReloadRules.
+	reloadMu sync.Mutex
+
+	// index is the equal-labels index of the firing alerts (see withIndex).
+	indexMu sync.RWMutex
+	index   *equalIndex
 }
 
 // NewMatcher creates a new InhibitionMatcher with the given configuration.
@@ -49,11 +60,17 @@ func NewMatcher(cache ActiveAlertCache, rules []InhibitionRule, logger *slog.Log
 		logger = slog.Default()
 	}
 
-	return &DefaultInhibitionMatcher{
+	m := &DefaultInhibitionMatcher{
 		cache:  cache,
-		rules:  rules,
 		logger: logger,
 	}
+	m.rules.Store(&rules)
+	return m
+}
+
+// Rules returns the current inhibition rules. The slice must not be modified.
+func (m *DefaultInhibitionMatcher) Rules() []InhibitionRule {
+	return *m.rules.Load()
 }
 
 // ShouldInhibit implements InhibitionMatcher.ShouldInhibit.
@@ -63,9 +80,9 @@ func NewMatcher(cache ActiveAlertCache, rules []InhibitionRule, logger *slog.Log
 //
 // Performance optimizations:
 //   - Early exit on context cancellation
-//   - Pre-filter alerts by source_match.alertname if present
+//   - Sources are looked up in the equal-labels index (see withIndex)
 //   - Skip self-inhibition check early
-//   - Minimal allocations (reuse slices where possible)
+//   - No allocations per candidate source
 func (m *DefaultInhibitionMatcher) ShouldInhibit(
 	ctx context.Context,
 	targetAlert *core.Alert,
@@ -79,77 +96,30 @@ func (m *DefaultInhibitionMatcher) ShouldInhibit(
 	default:
 	}
 
-	// Get all firing alerts from cache
-	firingAlerts, err := m.cache.GetFiringAlerts(ctx)
+	result := &MatchResult{}
+	err := m.withIndex(ctx, func(ix *equalIndex) {
+		ix.forEachInhibitor(targetAlert, func(rule *InhibitionRule, source *core.Alert) bool {
+			result.Matched = true
+			result.InhibitedBy = source
+			result.Rule = rule
+			return false // first match wins
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get firing alerts: %w", err)
+		return nil, err
 	}
-
-	// Fast path: no firing alerts = no inhibition
-	if len(firingAlerts) == 0 {
-		return &MatchResult{
-			Matched:       false,
-			MatchDuration: time.Since(startTime),
-		}, nil
-	}
-
-	// Pre-compute target fingerprint for self-inhibition check (avoid repeated string comparison)
-	targetFP := targetAlert.Fingerprint
-
-	// Check each rule (early exit on first match)
-	for i := range m.rules {
-		rule := &m.rules[i]
-
-		// Pre-filter optimization: if rule has source_match.alertname, only check alerts with that alertname
-		var candidateAlerts []*core.Alert
-		if alertname, hasAlertname := rule.SourceMatch["alertname"]; hasAlertname {
-			// Filter alerts by alertname (significant performance boost for large alert sets)
-			candidateAlerts = make([]*core.Alert, 0, len(firingAlerts)/10) // estimate 10% match rate
-			for _, alert := range firingAlerts {
-				if alert.Fingerprint != targetFP && alert.Labels["alertname"] == alertname {
-					candidateAlerts = append(candidateAlerts, alert)
-				}
-			}
-		} else {
-			// No alertname filter, check all firing alerts (but skip self-inhibition)
-			candidateAlerts = make([]*core.Alert, 0, len(firingAlerts))
-			for _, alert := range firingAlerts {
-				if alert.Fingerprint != targetFP {
-					candidateAlerts = append(candidateAlerts, alert)
-				}
-			}
-		}
-
-		// Check each candidate alert as potential source
-		for _, sourceAlert := range candidateAlerts {
-			// Check if rule matches (inlined hot path)
-			if m.matchRuleFast(rule, sourceAlert, targetAlert) {
-				duration := time.Since(startTime)
-
-				// Only log in debug mode to avoid I/O overhead in hot path
-				if m.logger != nil {
-					m.logger.Info("Alert inhibited",
-						"target", targetFP,
-						"source", sourceAlert.Fingerprint,
-						"rule", rule.Name,
-						"duration", duration)
-				}
-
-				return &MatchResult{
-					Matched:       true,
-					InhibitedBy:   sourceAlert,
-					Rule:          rule,
-					MatchDuration: duration,
-				}, nil
-			}
-		}
+	result.MatchDuration = time.Since(startTime)
+
+	// Only log in debug mode to avoid I/O overhead in hot path
+	if result.Matched && m.logger != nil {
+		m.logger.Info("Alert inhibited",
+			"target", targetAlert.Fingerprint,
+			"source", result.InhibitedBy.Fingerprint,
+			"rule", result.Rule.Name,
+			"duration", result.MatchDuration)
 	}
 
-	// No match found
-	return &MatchResult{
-		Matched:       false,
-		MatchDuration: time.Since(startTime),
-	}, nil
+	return result, nil
 }
 
 // FindInhibitors implements InhibitionMatcher.FindInhibitors.
@@ -157,9 +127,8 @@ func (m *DefaultInhibitionMatcher) ShouldInhibit(
 // Returns ALL matching inhibitions (no early return).
 //
 // Performance optimizations:
-//   - Pre-filter alerts by source_match.alertname if present
+//   - Sources are looked up in the equal-labels index (see withIndex)
 //   - Skip self-inhibition check early
-//   - Pre-allocate results slice with estimated capacity
 func (m *DefaultInhibitionMatcher) FindInhibitors(
 	ctx context.Context,
 	targetAlert *core.Alert,
@@ -173,77 +142,113 @@ func (m *DefaultInhibitionMatcher) FindInhibitors(
 	default:
 	}
 
-	// Get all firing alerts from cache
-	firingAlerts, err := m.cache.GetFiringAlerts(ctx)
+	results := []*MatchResult{}
+	err := m.withIndex(ctx, func(ix *equalIndex) {
+		ix.forEachInhibitor(targetAlert, func(rule *InhibitionRule, source *core.Alert) bool {
+			results = append(results, &MatchResult{
+				Matched:       true,
+				InhibitedBy:   source,
+				Rule:          rule,
+				MatchDuration: time.Since(startTime),
+			})
+			return true
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get firing alerts: %w", err)
+		return nil, err
+	}
+
+	// Only log in debug mode
+	if m.logger != nil {
+		m.logger.Debug("Find inhibitors complete",
+			"target", targetAlert.Fingerprint,
+			"inhibitors_found", len(results),
+			"duration", time.Since(startTime))
+	}
+
+	return results, nil
+}
+
+// withIndex calls fn with the equal-labels index of the current rules and
+// firing alerts, holding indexMu for reading.
+//
+// With a VersionedAlertCache the firing alerts are only re-read when the
+// cache version changed, so an unchanged alert set costs no more than the
+// lookups; an IncrementalAlertCache reports the changed alerts, so that a
+// new alert costs no more than indexing it. Otherwise the firing alerts are
+// read on every call and the index is synced incrementally.
+func (m *DefaultInhibitionMatcher) withIndex(ctx context.Context, fn func(ix *equalIndex)) error {
+	rulesPtr := m.rules.Load()
+	var version uint64
+	var versioned bool
+	if vc, ok := m.cache.(VersionedAlertCache); ok {
+		version, versioned = vc.Version()
 	}
 
-	// Fast path: no firing alerts = no inhibitions
-	if len(firingAlerts) == 0 {
-		return []*MatchResult{}, nil
+	m.indexMu.RLock()
+	if ix := m.index; ix != nil && ix.rulesPtr == rulesPtr && versioned && ix.versioned && ix.version == version {
+		defer m.indexMu.RUnlock()
+		fn(ix)
+		return nil
 	}
+	m.indexMu.RUnlock()
 
-	// Pre-allocate results slice (estimate: 5% of rules might match)
-	results := make([]*MatchResult, 0, len(m.rules)/20+1)
-	targetFP := targetAlert.Fingerprint
-
-	// Check each rule (collect ALL matches, no early return)
-	for i := range m.rules {
-		rule := &m.rules[i]
-
-		// Pre-filter optimization: if rule has source_match.alertname, only check alerts with that alertname
-		var candidateAlerts []*core.Alert
-		if alertname, hasAlertname := rule.SourceMatch["alertname"]; hasAlertname {
-			candidateAlerts = make([]*core.Alert, 0, len(firingAlerts)/10)
-			for _, alert := range firingAlerts {
-				if alert.Fingerprint != targetFP && alert.Labels["alertname"] == alertname {
-					candidateAlerts = append(candidateAlerts, alert)
-				}
-			}
-		} else {
-			candidateAlerts = make([]*core.Alert, 0, len(firingAlerts))
-			for _, alert := range firingAlerts {
-				if alert.Fingerprint != targetFP {
-					candidateAlerts = append(candidateAlerts, alert)
-				}
-			}
+	if !versioned || !m.applyChanges(rulesPtr) {
+		firingAlerts, err := m.cache.GetFiringAlerts(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get firing alerts: %w", err)
 		}
 
-		// Check each candidate alert as potential source
-		for _, sourceAlert := range candidateAlerts {
-			if m.matchRuleFast(rule, sourceAlert, targetAlert) {
-				results = append(results, &MatchResult{
-					Matched:       true,
-					InhibitedBy:   sourceAlert,
-					Rule:          rule,
-					MatchDuration: time.Since(startTime),
-				})
-			}
+		m.indexMu.Lock()
+		if m.index == nil || m.index.rulesPtr != rulesPtr {
+			m.index = newEqualIndex(rulesPtr)
 		}
+		m.index.sync(firingAlerts)
+		m.index.version, m.index.versioned = version, versioned
+		m.indexMu.Unlock()
 	}
 
-	// Only log in debug mode
-	if m.logger != nil {
-		m.logger.Debug("Find inhibitors complete",
-			"target", targetFP,
-			"inhibitors_found", len(results),
-			"duration", time.Since(startTime))
+	// Another caller may sync a newer snapshot in between; that is fine.
+	m.indexMu.RLock()
+	defer m.indexMu.RUnlock()
+	fn(m.index)
+	return nil
+}
+
+// applyChanges updates the index of rulesPtr with the changes of an
+// IncrementalAlertCache since the indexed version. It returns false when
+// the firing alerts must be re-read instead.
+func (m *DefaultInhibitionMatcher) applyChanges(rulesPtr *[]InhibitionRule) bool {
+	ic, ok := m.cache.(IncrementalAlertCache)
+	if !ok {
+		return false
 	}
 
-	return results, nil
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	ix := m.index
+	if ix == nil || ix.rulesPtr != rulesPtr || !ix.versioned {
+		return false
+	}
+	changes, current, ok := ic.ChangesSince(ix.version)
+	if !ok {
+		return false
+	}
+	ix.apply(changes)
+	ix.version = current
+	return true
 }
 
 // MatchRule implements InhibitionMatcher.MatchRule.
 //
 // Core matching logic (pure function, no I/O):
-//  1. Check source_match (exact label matching)
-//  2. Check source_match_re (regex label matching)
-//  3. Check target_match (exact label matching)
-//  4. Check target_match_re (regex label matching)
-//  5. Check equal labels (must have same value in both alerts)
+//  1. Check target_match and target_match_re against the target alert
+//  2. Check source_match and source_match_re against the source alert
+//  3. Reject alerts matching both sides of the rule (Alertmanager parity)
+//  4. Check equal labels (must have same value in both alerts)
 //
-// All conditions must match (AND logic).
+// All conditions must match (AND logic). See matchRuleFast for the
+// Alertmanager semantics of missing labels.
 //
 // Performance: <5µs per call (zero allocations, inlined checks).
 //
@@ -257,76 +262,79 @@ func (m *DefaultInhibitionMatcher) MatchRule(
 
 // matchRuleFast is an optimized version of MatchRule for internal hot path usage.
 //
+// Matching follows Alertmanager semantics:
+//   - A missing label matches like an empty value, so source_match {foo: ""}
+//     matches alerts without foo and regexes see "" for missing labels
+//   - Regexes are anchored (compiled as ^(?:pattern)$ by the parser)
+//   - Equal labels compare values with missing as empty, so a label missing
+//     from both alerts is equal
+//   - An alert matching both the source and the target side of a rule never
+//     inhibits another alert matching both sides (this also stops an alert
+//     from inhibiting itself)
+//
 // Optimizations:
-//   - Inlined label checks (no function calls)
 //   - Early exit on first mismatch
-//   - Minimized map lookups
 //   - Zero allocations
 //
 // Performance: <2µs per call (hot path optimized).
-//
-//go:inline
 func (m *DefaultInhibitionMatcher) matchRuleFast(
 	rule *InhibitionRule,
 	sourceAlert, targetAlert *core.Alert,
 ) bool {
-	// 1. Check source_match conditions (exact matching) - INLINED
-	for key, requiredValue := range rule.SourceMatch {
-		actualValue, exists := sourceAlert.Labels[key]
-		if !exists || actualValue != requiredValue {
-			return false // Early exit
-		}
+	return ruleInhibits(rule, sourceAlert, targetAlert)
+}
+
+// ruleInhibits implements matchRuleFast; it needs no matcher state.
+func ruleInhibits(rule *InhibitionRule, sourceAlert, targetAlert *core.Alert) bool {
+	// 1. Target and source conditions
+	if !matchTargetSide(rule, targetAlert.Labels) || !matchSourceSide(rule, sourceAlert.Labels) {
+		return false
 	}
 
-	// 2. Check source_match_re conditions (regex matching) - INLINED
-	for key := range rule.SourceMatchRE {
-		actualValue, exists := sourceAlert.Labels[key]
-		if !exists {
+	// 2. Two-sided matches cannot inhibit each other
+	if matchSourceSide(rule, targetAlert.Labels) && matchTargetSide(rule, sourceAlert.Labels) {
+		return false
+	}
+
+	// 3. Check equal labels (must have the same value in both alerts)
+	for _, labelName := range rule.Equal {
+		if sourceAlert.Labels[labelName] != targetAlert.Labels[labelName] {
 			return false // Early exit
 		}
+	}
+
+	// All conditions matched
+	return true
+}
 
+// matchSourceSide reports whether labels satisfy source_match and source_match_re.
+func matchSourceSide(rule *InhibitionRule, labels map[string]string) bool {
+	for key, requiredValue := range rule.SourceMatch {
+		if labels[key] != requiredValue {
+			return false
+		}
+	}
+	for key := range rule.SourceMatchRE {
 		re, hasRE := rule.compiledSourceRE[key]
-		if !hasRE || !re.MatchString(actualValue) {
-			return false // Early exit
+		if !hasRE || !re.MatchString(labels[key]) {
+			return false
 		}
 	}
+	return true
+}
 
-	// 3. Check target_match conditions (exact matching) - INLINED
+// matchTargetSide reports whether labels satisfy target_match and target_match_re.
+func matchTargetSide(rule *InhibitionRule, labels map[string]string) bool {
 	for key, requiredValue := range rule.TargetMatch {
-		actualValue, exists := targetAlert.Labels[key]
-		if !exists || actualValue != requiredValue {
-			return false // Early exit
+		if labels[key] != requiredValue {
+			return false
 		}
 	}
-
-	// 4. Check target_match_re conditions (regex matching) - INLINED
 	for key := range rule.TargetMatchRE {
-		actualValue, exists := targetAlert.Labels[key]
-		if !exists {
-			return false // Early exit
-		}
-
 		re, hasRE := rule.compiledTargetRE[key]
-		if !hasRE || !re.MatchString(actualValue) {
-			return false // Early exit
+		if !hasRE || !re.MatchString(labels[key]) {
+			return false
 		}
 	}
-
-	// 5. Check equal labels (must match between source and target) - INLINED
-	for _, labelName := range rule.Equal {
-		sourceVal, sourceOk := sourceAlert.Labels[labelName]
-		targetVal, targetOk := targetAlert.Labels[labelName]
-
-		// If label missing in either alert OR values differ → no match
-		if !sourceOk || !targetOk || sourceVal != targetVal {
-			return false // Early exit
-		}
-	}
-
-	// All conditions matched
 	return true
 }
-
-// Note: Helper functions matchLabels() and matchLabelsRE() were removed in favor of
-// inlined matching logic in matchRuleFast() for better performance (zero allocations,
-// early exit optimizations, and reduced function call overhead).
@@ -868,11 +868,11 @@ func TestMatchRuleFast_EmptyConditions(t *testing.T) {
 	sourceAlert := createTestAlert("NodeDown", "critical", "node1", "prod")
 	targetAlert := createTestAlert("InstanceDown", "warning", "node1", "prod")
 
-	// Rule with ONLY equal labels (empty source/target match)
+	// Rule with empty source match
 	rule := InhibitionRule{
 		Name:        "empty-conditions",
 		SourceMatch: map[string]string{}, // Empty
-		TargetMatch: map[string]string{}, // Empty
+		TargetMatch: map[string]string{"alertname": "InstanceDown"},
 		Equal:       []string{"cluster"},
 	}
 	rule.compiledSourceRE = make(map[string]*regexp.Regexp)
@@ -880,9 +880,17 @@ func TestMatchRuleFast_EmptyConditions(t *testing.T) {
 
 	matcher := NewMatcher(&mockCache{}, []InhibitionRule{rule}, nil)
 
-	// Should match (empty conditions = always true, only equal matters)
+	// Should match (empty source conditions = always true)
 	if !matcher.matchRuleFast(&rule, sourceAlert, targetAlert) {
-		t.Error("Expected match (empty source/target conditions with matching equal labels)")
+		t.Error("Expected match (empty source conditions with matching equal labels)")
+	}
+
+	// With both sides empty every alert matches both sides of the rule,
+	// and two-sided matches never inhibit each other (Alertmanager parity)
+	bothEmpty := rule
+	bothEmpty.TargetMatch = map[string]string{}
+	if matcher.matchRuleFast(&bothEmpty, sourceAlert, targetAlert) {
+		t.Error("Expected no match (both alerts match both sides of the rule)")
 	}
 
 	// Test mismatch in equal labels
@@ -929,6 +937,85 @@ func TestMatchRuleFast_MissingRegexCompilation(t *testing.T) {
 	}
 }
 
+// --- Alertmanager Semantics Tests ---
+
+// TestMatchRuleFast_MissingLabelMatchesEmpty tests that a missing label
+// matches like an empty value on both sides and in equal
+func TestMatchRuleFast_MissingLabelMatchesEmpty(t *testing.T) {
+	sourceAlert := &core.Alert{Fingerprint: "fp-source", Labels: map[string]string{"alertname": "ClusterDown"}}
+	targetAlert := &core.Alert{Fingerprint: "fp-target", Labels: map[string]string{"alertname": "PodDown", "zone": ""}}
+
+	rules := []InhibitionRule{{
+		Name:          "missing-as-empty",
+		SourceMatch:   map[string]string{"alertname": "ClusterDown", "maintenance": ""},
+		TargetMatchRE: map[string]string{"team": "(ops)?"},
+		Equal:         []string{"cluster", "zone"},
+	}}
+	if err := CompileRules(rules); err != nil {
+		t.Fatalf("CompileRules() error = %v", err)
+	}
+	matcher := NewMatcher(&mockCache{}, rules, nil)
+
+	if !matcher.matchRuleFast(&rules[0], sourceAlert, targetAlert) {
+		t.Error("Expected match (missing labels match empty values)")
+	}
+
+	sourceAlert.Labels["cluster"] = "prod"
+	if matcher.matchRuleFast(&rules[0], sourceAlert, targetAlert) {
+		t.Error("Expected no match (equal label set on one side only)")
+	}
+}
+
+// TestMatchRuleFast_TwoSidedMatch tests that an alert matching both sides
+// of a rule does not inhibit another alert matching both sides
+func TestMatchRuleFast_TwoSidedMatch(t *testing.T) {
+	rule := InhibitionRule{
+		Name:        "critical-inhibits-warning",
+		SourceMatch: map[string]string{"severity": "critical"},
+		TargetMatch: map[string]string{"team": "ops"},
+		Equal:       []string{"cluster"},
+	}
+	matcher := NewMatcher(&mockCache{}, []InhibitionRule{rule}, nil)
+
+	critical := &core.Alert{Fingerprint: "fp-1", Labels: map[string]string{"severity": "critical", "team": "ops", "cluster": "prod"}}
+	otherCritical := &core.Alert{Fingerprint: "fp-2", Labels: map[string]string{"severity": "critical", "team": "ops", "cluster": "prod"}}
+	warning := &core.Alert{Fingerprint: "fp-3", Labels: map[string]string{"severity": "warning", "team": "ops", "cluster": "prod"}}
+
+	if matcher.matchRuleFast(&rule, critical, otherCritical) {
+		t.Error("Expected no match (both alerts match both sides)")
+	}
+	if !matcher.matchRuleFast(&rule, critical, warning) {
+		t.Error("Expected match (target does not match the source side)")
+	}
+}
+
+// TestCompileRules_AnchorsRegex tests that regex conditions must match the
+// whole label value
+func TestCompileRules_AnchorsRegex(t *testing.T) {
+	rules := []InhibitionRule{{
+		Name:          "anchored",
+		SourceMatchRE: map[string]string{"service": "api|web"},
+		TargetMatch:   map[string]string{"alertname": "HighLatency"},
+	}}
+	if err := CompileRules(rules); err != nil {
+		t.Fatalf("CompileRules() error = %v", err)
+	}
+	matcher := NewMatcher(&mockCache{}, rules, nil)
+	target := &core.Alert{Fingerprint: "fp-target", Labels: map[string]string{"alertname": "HighLatency"}}
+
+	for value, want := range map[string]bool{"api": true, "web": true, "api-gateway": false, "webapi": false} {
+		source := &core.Alert{Fingerprint: "fp-source", Labels: map[string]string{"service": value}}
+		if got := matcher.matchRuleFast(&rules[0], source, target); got != want {
+			t.Errorf("service=%q: match = %v, want %v", value, got, want)
+		}
+	}
+
+	rules[0].SourceMatchRE["service"] = "("
+	if err := CompileRules(rules); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+}
+
 // --- Benchmarks ---
 
 func BenchmarkShouldInhibit_SingleRule(b *testing.B) {
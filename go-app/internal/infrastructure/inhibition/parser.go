@@ -297,13 +297,24 @@ func (p *DefaultInhibitionParser) applyDefaults(config *InhibitionConfig) {
 // Pre-compilation improves performance during matching.
 // Invalid patterns return ParseError with detailed information.
 func (p *DefaultInhibitionParser) compileRegexPatterns(config *InhibitionConfig) error {
-	for i := range config.Rules {
-		rule := &config.Rules[i]
+	return CompileRules(config.Rules)
+}
+
+// CompileRules compiles the source_match_re and target_match_re patterns of
+// rules in place. Rules built outside the parser (e.g. from the application
+// config) must be compiled before they are passed to NewMatcher; a regex
+// condition without a compiled pattern never matches.
+//
+// Patterns are anchored like in Alertmanager: "api" matches only "api",
+// not "api-gateway".
+func CompileRules(rules []InhibitionRule) error {
+	for i := range rules {
+		rule := &rules[i]
 
 		// Compile source_match_re patterns
 		rule.compiledSourceRE = make(map[string]*regexp.Regexp)
 		for key, pattern := range rule.SourceMatchRE {
-			re, err := regexp.Compile(pattern)
+			re, err := compileAnchored(pattern)
 			if err != nil {
 				return NewParseError(
 					fmt.Sprintf("rules[%d].source_match_re.%s", i, key),
@@ -317,7 +328,7 @@ func (p *DefaultInhibitionParser) compileRegexPatterns(config *InhibitionConfig)
 		// Compile target_match_re patterns
 		rule.compiledTargetRE = make(map[string]*regexp.Regexp)
 		for key, pattern := range rule.TargetMatchRE {
-			re, err := regexp.Compile(pattern)
+			re, err := compileAnchored(pattern)
 			if err != nil {
 				return NewParseError(
 					fmt.Sprintf("rules[%d].target_match_re.%s", i, key),
@@ -332,6 +343,14 @@ func (p *DefaultInhibitionParser) compileRegexPatterns(config *InhibitionConfig)
 	return nil
 }
 
+// compileAnchored compiles pattern so it must match the whole label value.
+func compileAnchored(pattern string) (*regexp.Regexp, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, err
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
 // validateSemantics performs semantic validation (business rules).
 //
 // Semantic validations:
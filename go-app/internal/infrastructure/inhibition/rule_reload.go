@@ -0,0 +1,221 @@
+package inhibition
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RuleSetDiff describes how ReloadRules changed the inhibition rules.
+//
+// Rules are identified by name. Unnamed rules (e.g. inline rules from the
+// application config) are identified by their conditions, so editing one
+// shows up as a removal plus an addition.
+type RuleSetDiff struct {
+	Added     []string `json:"added,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Changed   []string `json:"changed,omitempty"`
+	Unchanged []string `json:"unchanged,omitempty"`
+}
+
+// HasChanges reports whether any rule was added, removed or changed.
+func (d RuleSetDiff) HasChanges() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Changed) > 0
+}
+
+// ReloadRules replaces the rules of the matcher, e.g. after a config reload.
+//
+// Unchanged rules keep their compiled patterns and CreatedAt; only added and
+// changed rules are compiled. The new rule set is swapped in atomically, so
+// concurrent checks see either the old or the new rules. On a compile error
+// the old rules stay in place.
+//
+// Inhibition state is not touched; use ReconcileState with the returned
+// diff to drop inhibitions that no longer hold.
+func (m *DefaultInhibitionMatcher) ReloadRules(rules []InhibitionRule) (RuleSetDiff, error) {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	oldRules := m.Rules()
+	oldByID := make(map[string]*InhibitionRule, len(oldRules))
+	for i, id := range ruleIDs(oldRules) {
+		oldByID[id] = &oldRules[i]
+	}
+
+	var diff RuleSetDiff
+	next := make([]InhibitionRule, len(rules))
+	for i, id := range ruleIDs(rules) {
+		old, existed := oldByID[id]
+		delete(oldByID, id)
+
+		switch {
+		case existed && ruleConditions(old) == ruleConditions(&rules[i]):
+			next[i] = *old
+			diff.Unchanged = append(diff.Unchanged, id)
+			continue
+		case existed:
+			diff.Changed = append(diff.Changed, id)
+		default:
+			diff.Added = append(diff.Added, id)
+		}
+
+		next[i] = rules[i]
+		if err := CompileRules(next[i : i+1]); err != nil {
+			return RuleSetDiff{}, fmt.Errorf("rule %s: %w", id, err)
+		}
+	}
+	for id := range oldByID {
+		diff.Removed = append(diff.Removed, id)
+	}
+	sort.Strings(diff.Removed)
+
+	m.rules.Store(&next)
+
+	if diff.HasChanges() {
+		m.logger.Info("Inhibition rules reloaded",
+			"rules", len(next),
+			"added", diff.Added,
+			"removed", diff.Removed,
+			"changed", diff.Changed)
+	}
+	return diff, nil
+}
+
+// ReconcileState brings recorded inhibitions in line with the rules after
+// ReloadRules. Inhibitions of unchanged rules are kept as they are. All
+// others are re-checked against the current rules and the firing alerts:
+// they are kept (under the name of the matching rule) while some rule still
+// inhibits the target, and removed otherwise. Removed targets are published
+// again the next time they are received.
+//
+// Returns the number of removed inhibitions.
+func (m *DefaultInhibitionMatcher) ReconcileState(
+	ctx context.Context,
+	state InhibitionStateManager,
+	diff RuleSetDiff,
+) (int, error) {
+	if state == nil {
+		return 0, nil
+	}
+
+	inhibitions, err := state.GetActiveInhibitions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active inhibitions: %w", err)
+	}
+
+	unchanged := make(map[string]struct{}, len(diff.Unchanged))
+	for _, id := range diff.Unchanged {
+		unchanged[id] = struct{}{}
+	}
+
+	var stale []*InhibitionState
+	for _, inh := range inhibitions {
+		if _, ok := unchanged[inh.RuleName]; !ok {
+			stale = append(stale, inh)
+		}
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	firingAlerts, err := m.cache.GetFiringAlerts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get firing alerts: %w", err)
+	}
+	firing := make(map[string]int, len(firingAlerts))
+	for i, alert := range firingAlerts {
+		firing[alert.Fingerprint] = i
+	}
+
+	rules := m.Rules()
+	removed := 0
+	for _, inh := range stale {
+		var rule *InhibitionRule
+		sourceIdx, hasSource := firing[inh.SourceFingerprint]
+		targetIdx, hasTarget := firing[inh.TargetFingerprint]
+		if hasSource && hasTarget {
+			for i := range rules {
+				if m.matchRuleFast(&rules[i], firingAlerts[sourceIdx], firingAlerts[targetIdx]) {
+					rule = &rules[i]
+					break
+				}
+			}
+		}
+
+		if rule != nil {
+			if rule.Name != inh.RuleName {
+				updated := *inh
+				updated.RuleName = rule.Name
+				if err := state.RecordInhibition(ctx, &updated); err != nil {
+					m.logger.Warn("Failed to update inhibition rule name",
+						"target_fingerprint", inh.TargetFingerprint,
+						"error", err)
+				}
+			}
+			continue
+		}
+
+		if err := state.RemoveInhibition(ctx, inh.TargetFingerprint); err != nil {
+			return removed, fmt.Errorf("failed to remove inhibition of %s: %w", inh.TargetFingerprint, err)
+		}
+		removed++
+		m.logger.Info("Inhibition removed (rule no longer matches)",
+			"target_fingerprint", inh.TargetFingerprint,
+			"source_fingerprint", inh.SourceFingerprint,
+			"rule", inh.RuleName)
+	}
+	return removed, nil
+}
+
+// ruleIDs returns the identity of each rule for ReloadRules: its name, or
+// its conditions for unnamed rules. Duplicates get a "#n" suffix.
+func ruleIDs(rules []InhibitionRule) []string {
+	ids := make([]string, len(rules))
+	seen := make(map[string]int, len(rules))
+	for i := range rules {
+		id := rules[i].Name
+		if id == "" {
+			id = ruleConditions(&rules[i])
+		}
+		if n := seen[id]; n > 0 {
+			seen[id] = n + 1
+			id = fmt.Sprintf("%s#%d", id, n+1)
+		} else {
+			seen[id] = 1
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+// ruleConditions renders the matching conditions of a rule in a canonical
+// form, e.g. {alertname="NodeDown"} -> {alertname="InstanceDown"} equal(node).
+func ruleConditions(rule *InhibitionRule) string {
+	var b strings.Builder
+	writeMatchers(&b, rule.SourceMatch, rule.SourceMatchRE)
+	b.WriteString(" -> ")
+	writeMatchers(&b, rule.TargetMatch, rule.TargetMatchRE)
+	if len(rule.Equal) > 0 {
+		equal := append([]string(nil), rule.Equal...)
+		sort.Strings(equal)
+		b.WriteString(" equal(")
+		b.WriteString(strings.Join(equal, ","))
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+func writeMatchers(b *strings.Builder, match, matchRE map[string]string) {
+	matchers := make([]string, 0, len(match)+len(matchRE))
+	for name, value := range match {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", name, value))
+	}
+	for name, pattern := range matchRE {
+		matchers = append(matchers, fmt.Sprintf("%s=~%q", name, pattern))
+	}
+	sort.Strings(matchers)
+	b.WriteString("{")
+	b.WriteString(strings.Join(matchers, ","))
+	b.WriteString("}")
+}
@@ -0,0 +1,177 @@
+package inhibition
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestReloadRules_Diff(t *testing.T) {
+	keep := createTestRule("keep")
+	keep.CreatedAt = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	edit := createTestRule("edit")
+	drop := createTestRule("drop")
+	matcher := NewMatcher(&mockCache{}, []InhibitionRule{keep, edit, drop}, nil)
+
+	edited := createTestRule("edit")
+	edited.Equal = []string{"cluster"}
+	diff, err := matcher.ReloadRules([]InhibitionRule{createTestRule("keep"), edited, createTestRule("new")})
+	if err != nil {
+		t.Fatalf("ReloadRules() error = %v", err)
+	}
+
+	want := RuleSetDiff{
+		Added:     []string{"new"},
+		Removed:   []string{"drop"},
+		Changed:   []string{"edit"},
+		Unchanged: []string{"keep"},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("ReloadRules() diff = %+v, want %+v", diff, want)
+	}
+
+	rules := matcher.Rules()
+	if len(rules) != 3 {
+		t.Fatalf("Rules() = %d rules, want 3", len(rules))
+	}
+	if !rules[0].CreatedAt.Equal(keep.CreatedAt) {
+		t.Error("unchanged rule was rebuilt instead of kept")
+	}
+	if !reflect.DeepEqual(rules[1].Equal, []string{"cluster"}) {
+		t.Errorf("changed rule Equal = %v, want [cluster]", rules[1].Equal)
+	}
+}
+
+func TestReloadRules_UnnamedRulesByConditions(t *testing.T) {
+	rule := createTestRule("")
+	matcher := NewMatcher(&mockCache{}, []InhibitionRule{rule}, nil)
+
+	diff, err := matcher.ReloadRules([]InhibitionRule{createTestRule("")})
+	if err != nil {
+		t.Fatalf("ReloadRules() error = %v", err)
+	}
+	if diff.HasChanges() {
+		t.Errorf("identical unnamed rule reported as changed: %+v", diff)
+	}
+
+	changed := createTestRule("")
+	changed.TargetMatch = map[string]string{"alertname": "PodDown"}
+	diff, err = matcher.ReloadRules([]InhibitionRule{changed})
+	if err != nil {
+		t.Fatalf("ReloadRules() error = %v", err)
+	}
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 {
+		t.Errorf("edited unnamed rule diff = %+v, want one added and one removed", diff)
+	}
+}
+
+func TestReloadRules_InvalidRegexKeepsOldRules(t *testing.T) {
+	matcher := NewMatcher(&mockCache{}, []InhibitionRule{createTestRule("keep")}, nil)
+
+	bad := createTestRule("bad")
+	bad.TargetMatchRE = map[string]string{"severity": "("}
+	if _, err := matcher.ReloadRules([]InhibitionRule{bad}); err == nil {
+		t.Fatal("ReloadRules() with invalid regex error = nil")
+	}
+	if rules := matcher.Rules(); len(rules) != 1 || rules[0].Name != "keep" {
+		t.Errorf("Rules() after failed reload = %+v, want the old rules", rules)
+	}
+}
+
+func TestReloadRules_CompilesNewRegexRules(t *testing.T) {
+	source := createTestAlert("NodeDown", "critical", "node1", "prod")
+	target := createTestAlert("InstanceDown", "warning", "node1", "prod")
+	matcher := NewMatcher(&mockCache{firingAlerts: []*core.Alert{source}}, nil, nil)
+
+	rule := createTestRule("regex")
+	rule.TargetMatch = nil
+	rule.TargetMatchRE = map[string]string{"alertname": "Instance.*"}
+	if _, err := matcher.ReloadRules([]InhibitionRule{rule}); err != nil {
+		t.Fatalf("ReloadRules() error = %v", err)
+	}
+
+	result, err := matcher.ShouldInhibit(context.Background(), target)
+	if err != nil {
+		t.Fatalf("ShouldInhibit() error = %v", err)
+	}
+	if !result.Matched {
+		t.Error("reloaded regex rule did not match")
+	}
+}
+
+func TestReconcileState(t *testing.T) {
+	ctx := context.Background()
+	source := createTestAlert("NodeDown", "critical", "node1", "prod")
+	instance1 := createTestAlert("InstanceDown", "warning", "node1", "prod")
+	instance1.Fingerprint = "instance-1"
+	instance2 := createTestAlert("InstanceDown", "warning", "node1", "prod")
+	instance2.Fingerprint = "instance-2"
+	pod := createTestAlert("PodDown", "warning", "node1", "prod")
+	cache := &mockCache{firingAlerts: []*core.Alert{source, instance1, instance2, pod}}
+
+	podRule := createTestRule("pods")
+	podRule.TargetMatch = map[string]string{"alertname": "PodDown"}
+	matcher := NewMatcher(cache, []InhibitionRule{
+		createTestRule("keep"),
+		createTestRule("edit"),
+		podRule,
+	}, nil)
+	state := newTestStateManager(t)
+	for fp, rule := range map[string]string{"instance-1": "keep", "instance-2": "edit", pod.Fingerprint: "pods"} {
+		if err := state.RecordInhibition(ctx, newTestState(fp, source.Fingerprint, rule)); err != nil {
+			t.Fatalf("RecordInhibition() error = %v", err)
+		}
+	}
+
+	// "edit" still matches with the new Equal labels; "pods" is gone and
+	// no remaining rule inhibits the PodDown alert.
+	edited := createTestRule("edit")
+	edited.Equal = []string{"cluster"}
+	diff, err := matcher.ReloadRules([]InhibitionRule{createTestRule("keep"), edited})
+	if err != nil {
+		t.Fatalf("ReloadRules() error = %v", err)
+	}
+
+	removed, err := matcher.ReconcileState(ctx, state, diff)
+	if err != nil {
+		t.Fatalf("ReconcileState() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("ReconcileState() removed = %d, want 1", removed)
+	}
+
+	for fp, want := range map[string]bool{"instance-1": true, "instance-2": true, pod.Fingerprint: false} {
+		inhibited, err := state.IsInhibited(ctx, fp)
+		if err != nil {
+			t.Fatalf("IsInhibited(%s) error = %v", fp, err)
+		}
+		if inhibited != want {
+			t.Errorf("IsInhibited(%s) = %v, want %v", fp, inhibited, want)
+		}
+	}
+}
+
+func TestReconcileState_RemovedSourceReleasesTarget(t *testing.T) {
+	ctx := context.Background()
+	target := createTestAlert("InstanceDown", "warning", "node1", "prod")
+	matcher := NewMatcher(&mockCache{firingAlerts: []*core.Alert{target}}, []InhibitionRule{createTestRule("old")}, nil)
+	state := newTestStateManager(t)
+	if err := state.RecordInhibition(ctx, newTestState(target.Fingerprint, "fp-gone", "old")); err != nil {
+		t.Fatalf("RecordInhibition() error = %v", err)
+	}
+
+	diff, err := matcher.ReloadRules([]InhibitionRule{createTestRule("new")})
+	if err != nil {
+		t.Fatalf("ReloadRules() error = %v", err)
+	}
+	removed, err := matcher.ReconcileState(ctx, state, diff)
+	if err != nil {
+		t.Fatalf("ReconcileState() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("ReconcileState() removed = %d, want 1", removed)
+	}
+}
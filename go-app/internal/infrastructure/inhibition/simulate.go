@@ -0,0 +1,75 @@
+package inhibition
+
+import (
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// SimulatedInhibition is an alert a candidate rule would have inhibited.
+type SimulatedInhibition struct {
+	Target *core.Alert
+	// InhibitedBy is the first source alert found that inhibits Target.
+	InhibitedBy *core.Alert
+	// Sources counts the source alerts that inhibit Target.
+	Sources int
+}
+
+// SimulateRule reports which of alerts rule would have inhibited between
+// from and to, e.g. to validate a new rule before deploying it. The rule
+// must be compiled (see CompileRules).
+//
+// An alert is active from StartsAt until EndsAt once resolved, or until to
+// while firing. A source inhibits a target only if both were active at the
+// same time within the window; matching follows ShouldInhibit. Returns the
+// inhibited alerts in the order of alerts and the number of alerts active
+// in the window.
+func SimulateRule(rule *InhibitionRule, alerts []*core.Alert, from, to time.Time) ([]SimulatedInhibition, int) {
+	type activeAlert struct {
+		alert    *core.Alert
+		from, to time.Time
+	}
+
+	active := make([]activeAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		start, end := alert.StartsAt, to
+		if alert.Status == core.StatusResolved && alert.EndsAt != nil {
+			end = *alert.EndsAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.Before(start) {
+			continue // not active within the window
+		}
+		active = append(active, activeAlert{alert: alert, from: start, to: end})
+	}
+
+	var inhibited []SimulatedInhibition
+	for _, target := range active {
+		if !matchTargetSide(rule, target.alert.Labels) {
+			continue
+		}
+		result := SimulatedInhibition{Target: target.alert}
+		for _, source := range active {
+			if source.alert.Fingerprint == target.alert.Fingerprint {
+				continue
+			}
+			overlaps := !source.from.After(target.to) && !target.from.After(source.to)
+			if !overlaps || !ruleInhibits(rule, source.alert, target.alert) {
+				continue
+			}
+			if result.InhibitedBy == nil {
+				result.InhibitedBy = source.alert
+			}
+			result.Sources++
+		}
+		if result.InhibitedBy != nil {
+			inhibited = append(inhibited, result)
+		}
+	}
+	return inhibited, len(active)
+}
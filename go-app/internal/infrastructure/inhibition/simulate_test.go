@@ -0,0 +1,71 @@
+package inhibition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func simulationAlert(name, node string, startsAt time.Time, endsAt *time.Time) *core.Alert {
+	alert := createTestAlert(name, "critical", node, "prod")
+	if name != "NodeDown" {
+		alert.Labels["severity"] = "warning"
+	}
+	alert.StartsAt = startsAt
+	if endsAt != nil {
+		alert.Status = core.StatusResolved
+		alert.EndsAt = endsAt
+	}
+	return alert
+}
+
+func TestSimulateRule(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	rules := []InhibitionRule{createTestRule("sim")}
+	if err := CompileRules(rules); err != nil {
+		t.Fatalf("CompileRules() error = %v", err)
+	}
+
+	source := simulationAlert("NodeDown", "node1", at(0), ptr(at(30)))
+	overlapping := simulationAlert("InstanceDown", "node1", at(10), nil)
+	later := simulationAlert("InstanceDown", "node1", at(40), nil)
+	later.Fingerprint = "fp-later"
+	otherNode := simulationAlert("InstanceDown", "node2", at(10), nil)
+
+	inhibited, evaluated := SimulateRule(&rules[0], []*core.Alert{source, overlapping, later, otherNode}, at(0), at(60))
+	if evaluated != 4 {
+		t.Errorf("evaluated = %d, want 4", evaluated)
+	}
+	if len(inhibited) != 1 {
+		t.Fatalf("inhibited = %d alerts, want 1", len(inhibited))
+	}
+	if inhibited[0].Target != overlapping || inhibited[0].InhibitedBy != source || inhibited[0].Sources != 1 {
+		t.Errorf("inhibited[0] = %+v, want %s inhibited by %s", inhibited[0], overlapping.Fingerprint, source.Fingerprint)
+	}
+}
+
+func TestSimulateRule_Window(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	rules := []InhibitionRule{createTestRule("sim")}
+	if err := CompileRules(rules); err != nil {
+		t.Fatalf("CompileRules() error = %v", err)
+	}
+
+	source := simulationAlert("NodeDown", "node1", at(0), ptr(at(30)))
+	target := simulationAlert("InstanceDown", "node1", at(10), ptr(at(20)))
+	alerts := []*core.Alert{source, target}
+
+	if inhibited, evaluated := SimulateRule(&rules[0], alerts, at(35), at(60)); len(inhibited) != 0 || evaluated != 0 {
+		t.Errorf("window after both alerts: inhibited = %d, evaluated = %d, want 0 and 0", len(inhibited), evaluated)
+	}
+	if inhibited, evaluated := SimulateRule(&rules[0], alerts, at(15), at(60)); len(inhibited) != 1 || evaluated != 2 {
+		t.Errorf("window overlapping both alerts: inhibited = %d, evaluated = %d, want 1 and 2", len(inhibited), evaluated)
+	}
+}
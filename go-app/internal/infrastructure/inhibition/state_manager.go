@@ -3,9 +3,11 @@ package inhibition
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ipiton/AMP/internal/infrastructure/cache"
@@ -71,9 +73,19 @@ type DefaultStateManager struct {
 	// Redis key prefix
 	redisPrefix string
 
-	// Redis TTL for state entries
+	// Redis SET of persisted target fingerprints, used by Restore
+	redisIndexKey string
+
+	// Redis TTL for state entries without ExpiresAt
 	redisTTL time.Duration
 
+	// Set while Redis is unavailable: state is kept in memory only and
+	// resynced to Redis by the cleanup worker once it recovers
+	degraded atomic.Bool
+
+	// Targets removed while degraded, deleted from Redis on resync
+	pendingRemovals sync.Map
+
 	// Logger
 	logger *slog.Logger
 
@@ -106,6 +118,7 @@ func NewDefaultStateManager(redisStore cache.Cache, logger *slog.Logger, metrics
 		states:          sync.Map{},
 		redisStore:      redisStore,
 		redisPrefix:     "inhibition:state:",
+		redisIndexKey:   "inhibition:state:index",
 		redisTTL:        24 * time.Hour,
 		logger:          logger,
 		metrics:         metrics,
@@ -132,6 +145,7 @@ func (sm *DefaultStateManager) RecordInhibition(ctx context.Context, state *Inhi
 
 	// Store in memory
 	sm.states.Store(state.TargetFingerprint, state)
+	sm.pendingRemovals.Delete(state.TargetFingerprint)
 
 	sm.logger.Debug("Recorded inhibition",
 		"target", state.TargetFingerprint,
@@ -140,7 +154,7 @@ func (sm *DefaultStateManager) RecordInhibition(ctx context.Context, state *Inhi
 	)
 
 	// Persist to Redis if available
-	if sm.redisStore != nil {
+	if sm.redisAvailable() {
 		if err := sm.persistToRedis(ctx, state); err != nil {
 			sm.logger.Warn("Failed to persist inhibition state to Redis",
 				"error", err,
@@ -151,6 +165,7 @@ func (sm *DefaultStateManager) RecordInhibition(ctx context.Context, state *Inhi
 				sm.metrics.RecordInhibitionStateRedisError("persist")
 			}
 			// Non-critical: in-memory state is still valid
+			sm.markDegraded(err)
 		}
 	}
 
@@ -182,9 +197,10 @@ func (sm *DefaultStateManager) RemoveInhibition(ctx context.Context, targetFinge
 	)
 
 	// Remove from Redis if available
-	if sm.redisStore != nil {
-		key := sm.redisPrefix + targetFingerprint
-		if err := sm.redisStore.Delete(ctx, key); err != nil {
+	if sm.redisStore != nil && !sm.redisAvailable() {
+		sm.pendingRemovals.Store(targetFingerprint, struct{}{})
+	} else if sm.redisStore != nil {
+		if err := sm.deleteFromRedis(ctx, targetFingerprint); err != nil {
 			sm.logger.Warn("Failed to remove inhibition state from Redis",
 				"error", err,
 				"target", targetFingerprint,
@@ -194,6 +210,8 @@ func (sm *DefaultStateManager) RemoveInhibition(ctx context.Context, targetFinge
 				sm.metrics.RecordInhibitionStateRedisError("delete")
 			}
 			// Non-critical: in-memory state is already removed
+			sm.markDegraded(err)
+			sm.pendingRemovals.Store(targetFingerprint, struct{}{})
 		}
 	}
 
@@ -264,7 +282,7 @@ func (sm *DefaultStateManager) IsInhibited(ctx context.Context, targetFingerprin
 	value, ok := sm.states.Load(targetFingerprint)
 	if !ok {
 		// Try Redis fallback if available (TN-129)
-		if sm.redisStore != nil {
+		if sm.redisAvailable() {
 			state, err := sm.loadFromRedis(ctx, targetFingerprint)
 			if err == nil && state != nil {
 				// Repopulate memory cache
@@ -314,7 +332,7 @@ func (sm *DefaultStateManager) GetInhibitionState(ctx context.Context, targetFin
 	value, ok := sm.states.Load(targetFingerprint)
 	if !ok {
 		// Try Redis fallback if available
-		if sm.redisStore != nil {
+		if sm.redisAvailable() {
 			state, err := sm.loadFromRedis(ctx, targetFingerprint)
 			if err != nil {
 				return nil, nil // Not found
@@ -341,31 +359,59 @@ func (sm *DefaultStateManager) GetInhibitionState(ctx context.Context, targetFin
 	return state, nil
 }
 
-// persistToRedis persists an inhibition state to Redis.
+// persistToRedis persists an inhibition state to Redis and indexes it for
+// Restore. The key expires with the inhibition, or after redisTTL for
+// inhibitions lasting until the source resolves (re-recorded while active).
 func (sm *DefaultStateManager) persistToRedis(ctx context.Context, state *InhibitionState) error {
+	ttl := sm.redisTTL
+	if state.ExpiresAt != nil {
+		ttl = time.Until(*state.ExpiresAt)
+		if ttl <= 0 {
+			return nil
+		}
+	}
+
 	data, err := json.Marshal(state)
 	if err != nil {
 		return fmt.Errorf("failed to marshal state: %w", err)
 	}
 
 	key := sm.redisPrefix + state.TargetFingerprint
-	if err := sm.redisStore.Set(ctx, key, string(data), sm.redisTTL); err != nil {
+	if err := sm.redisStore.Set(ctx, key, string(data), ttl); err != nil {
 		return fmt.Errorf("failed to set Redis key: %w", err)
 	}
+	if err := sm.redisStore.SAdd(ctx, sm.redisIndexKey, state.TargetFingerprint); err != nil {
+		return fmt.Errorf("failed to index Redis key: %w", err)
+	}
 
 	return nil
 }
 
+// deleteFromRedis removes an inhibition state and its index entry from Redis.
+func (sm *DefaultStateManager) deleteFromRedis(ctx context.Context, targetFingerprint string) error {
+	if err := sm.redisStore.Delete(ctx, sm.redisPrefix+targetFingerprint); err != nil {
+		return fmt.Errorf("failed to delete Redis key: %w", err)
+	}
+	if err := sm.redisStore.SRem(ctx, sm.redisIndexKey, targetFingerprint); err != nil {
+		return fmt.Errorf("failed to unindex Redis key: %w", err)
+	}
+	return nil
+}
+
 // loadFromRedis loads an inhibition state from Redis.
 func (sm *DefaultStateManager) loadFromRedis(ctx context.Context, targetFingerprint string) (*InhibitionState, error) {
 	key := sm.redisPrefix + targetFingerprint
 
 	var data string
 	if err := sm.redisStore.Get(ctx, key, &data); err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, err
+		}
 		// Record Redis error metric
 		if sm.metrics != nil {
 			sm.metrics.RecordInhibitionStateRedisError("load")
 		}
+		sm.markDegraded(err)
 		return nil, fmt.Errorf("failed to get Redis key: %w", err)
 	}
 
@@ -440,6 +486,7 @@ func (sm *DefaultStateManager) cleanupWorker(ctx context.Context) {
 
 		case <-ticker.C:
 			sm.cleanupExpiredStates(ctx)
+			sm.recoverRedis(ctx)
 		}
 	}
 }
@@ -489,6 +536,17 @@ func (sm *DefaultStateManager) cleanupExpiredStates(ctx context.Context) {
 		)
 	}
 
+	// The Redis keys expire on their own; drop their index entries
+	if len(expiredFingerprints) > 0 && sm.redisAvailable() {
+		members := make([]interface{}, len(expiredFingerprints))
+		for i, fp := range expiredFingerprints {
+			members[i] = fp
+		}
+		if err := sm.redisStore.SRem(ctx, sm.redisIndexKey, members...); err != nil {
+			sm.markDegraded(err)
+		}
+	}
+
 	// Record cleanup duration
 	if sm.metrics != nil {
 		// 		duration := time.Since(start)
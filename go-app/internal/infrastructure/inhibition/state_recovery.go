@@ -0,0 +1,138 @@
+package inhibition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ipiton/AMP/internal/infrastructure/cache"
+)
+
+// Restore rebuilds the in-memory state from Redis, e.g. after a restart.
+// Index entries whose state expired or vanished are pruned. Returns the
+// number of restored inhibitions; on a Redis error the state manager
+// continues on local state only.
+func (sm *DefaultStateManager) Restore(ctx context.Context) (int, error) {
+	if sm.redisStore == nil {
+		return 0, nil
+	}
+
+	fingerprints, err := sm.redisStore.SMembers(ctx, sm.redisIndexKey)
+	if err != nil {
+		if sm.metrics != nil {
+			sm.metrics.RecordInhibitionStateRedisError("restore")
+		}
+		sm.markDegraded(err)
+		return 0, fmt.Errorf("failed to list inhibition states: %w", err)
+	}
+
+	now := time.Now()
+	restored := 0
+	var stale []interface{}
+	for _, fp := range fingerprints {
+		state, err := sm.loadFromRedis(ctx, fp)
+		if errors.Is(err, cache.ErrNotFound) {
+			stale = append(stale, fp)
+			continue
+		}
+		if err != nil {
+			return restored, err
+		}
+		if state.ExpiresAt != nil && !now.Before(*state.ExpiresAt) {
+			stale = append(stale, fp)
+			continue
+		}
+		sm.states.Store(fp, state)
+		restored++
+	}
+
+	if len(stale) > 0 {
+		if err := sm.redisStore.SRem(ctx, sm.redisIndexKey, stale...); err != nil {
+			sm.logger.Warn("Failed to prune stale inhibition state index entries", "error", err)
+		}
+	}
+	if sm.metrics != nil {
+		sm.metrics.SetInhibitionStateActive(float64(sm.countActiveStates()))
+	}
+
+	sm.logger.Info("Restored inhibition state from Redis",
+		"restored", restored,
+		"stale", len(stale),
+	)
+	return restored, nil
+}
+
+// IsDegraded reports whether Redis is unavailable and the state is kept in
+// local memory only.
+func (sm *DefaultStateManager) IsDegraded() bool {
+	return sm.degraded.Load()
+}
+
+// redisAvailable reports whether state changes should be written to Redis.
+func (sm *DefaultStateManager) redisAvailable() bool {
+	return sm.redisStore != nil && !sm.degraded.Load()
+}
+
+// markDegraded switches to local state after a Redis error.
+func (sm *DefaultStateManager) markDegraded(err error) {
+	if !sm.degraded.CompareAndSwap(false, true) {
+		return
+	}
+	sm.logger.Warn("Redis unavailable, inhibition state falls back to local memory", "error", err)
+	if sm.metrics != nil {
+		sm.metrics.SetInhibitionStateDegraded(true)
+	}
+}
+
+// recoverRedis resyncs Redis with the local state once Redis is reachable
+// again: inhibitions recorded meanwhile are written and those removed
+// meanwhile deleted. States of other replicas sharing Redis are kept. It is
+// a no-op unless degraded.
+func (sm *DefaultStateManager) recoverRedis(ctx context.Context) {
+	if sm.redisStore == nil || !sm.degraded.Load() {
+		return
+	}
+	if err := sm.redisStore.Ping(ctx); err != nil {
+		return
+	}
+
+	if err := sm.resyncRedis(ctx); err != nil {
+		sm.logger.Warn("Failed to resync inhibition state to Redis", "error", err)
+		if sm.metrics != nil {
+			sm.metrics.RecordInhibitionStateRedisError("resync")
+		}
+		return
+	}
+
+	sm.degraded.Store(false)
+	if sm.metrics != nil {
+		sm.metrics.SetInhibitionStateDegraded(false)
+	}
+	sm.logger.Info("Redis recovered, inhibition state resynced")
+}
+
+func (sm *DefaultStateManager) resyncRedis(ctx context.Context) error {
+	var err error
+	sm.pendingRemovals.Range(func(key, _ interface{}) bool {
+		fp := key.(string)
+		if err = sm.deleteFromRedis(ctx, fp); err != nil {
+			return false
+		}
+		sm.pendingRemovals.Delete(fp)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	sm.states.Range(func(_, value interface{}) bool {
+		state, ok := value.(*InhibitionState)
+		if !ok {
+			return true
+		}
+		err = sm.persistToRedis(ctx, state)
+		return err == nil
+	})
+	return err
+}
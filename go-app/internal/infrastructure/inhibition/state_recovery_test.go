@@ -0,0 +1,218 @@
+package inhibition
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/infrastructure/cache"
+)
+
+var errRedisDown = errors.New("redis: connection refused")
+
+// flakyCache is an in-memory cache that fails every call while down.
+type flakyCache struct {
+	*cache.MemoryCache
+	down bool
+}
+
+func newFlakyCache() *flakyCache {
+	return &flakyCache{MemoryCache: cache.NewMemoryCache(slog.Default())}
+}
+
+func (c *flakyCache) Get(ctx context.Context, key string, dest interface{}) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.MemoryCache.Get(ctx, key, dest)
+}
+
+func (c *flakyCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.MemoryCache.Set(ctx, key, value, ttl)
+}
+
+func (c *flakyCache) Delete(ctx context.Context, key string) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.MemoryCache.Delete(ctx, key)
+}
+
+func (c *flakyCache) Ping(ctx context.Context) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.MemoryCache.Ping(ctx)
+}
+
+func (c *flakyCache) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.MemoryCache.SAdd(ctx, key, members...)
+}
+
+func (c *flakyCache) SMembers(ctx context.Context, key string) ([]string, error) {
+	if c.down {
+		return nil, errRedisDown
+	}
+	return c.MemoryCache.SMembers(ctx, key)
+}
+
+func (c *flakyCache) SRem(ctx context.Context, key string, members ...interface{}) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.MemoryCache.SRem(ctx, key, members...)
+}
+
+func TestStateManager_RestoreFromRedis(t *testing.T) {
+	ctx := context.Background()
+	redis := newFlakyCache()
+
+	before := NewDefaultStateManager(redis, slog.Default(), nil)
+	expiresAt := time.Now().Add(time.Hour)
+	timed := newTestState("target-timed", "source-1", "rule-1")
+	timed.ExpiresAt = &expiresAt
+	for _, state := range []*InhibitionState{newTestState("target-1", "source-1", "rule-1"), timed, newTestState("target-2", "source-2", "rule-2")} {
+		if err := before.RecordInhibition(ctx, state); err != nil {
+			t.Fatalf("RecordInhibition() error = %v", err)
+		}
+	}
+	if err := before.RemoveInhibition(ctx, "target-2"); err != nil {
+		t.Fatalf("RemoveInhibition() error = %v", err)
+	}
+	// A state whose key expired in Redis is pruned from the index.
+	if err := redis.MemoryCache.Delete(ctx, "inhibition:state:target-timed"); err != nil {
+		t.Fatal(err)
+	}
+
+	after := NewDefaultStateManager(redis, slog.Default(), nil)
+	restored, err := after.Restore(ctx)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("Restore() = %d, want 1", restored)
+	}
+	inhibited, _ := after.GetInhibitedAlerts(ctx)
+	if len(inhibited) != 1 || inhibited[0] != "target-1" {
+		t.Errorf("inhibited after restore = %v, want [target-1]", inhibited)
+	}
+	if index, _ := redis.SMembers(ctx, "inhibition:state:index"); len(index) != 1 {
+		t.Errorf("index after restore = %v, want only target-1", index)
+	}
+}
+
+func TestStateManager_PersistedTTLFollowsExpiry(t *testing.T) {
+	ctx := context.Background()
+	redis := newFlakyCache()
+	sm := NewDefaultStateManager(redis, slog.Default(), nil)
+
+	expiresAt := time.Now().Add(10 * time.Minute)
+	state := newTestState("target", "source", "rule")
+	state.ExpiresAt = &expiresAt
+	if err := sm.RecordInhibition(ctx, state); err != nil {
+		t.Fatalf("RecordInhibition() error = %v", err)
+	}
+
+	ttl, err := redis.TTL(ctx, "inhibition:state:target")
+	if err != nil {
+		t.Fatalf("TTL() error = %v", err)
+	}
+	if ttl <= 0 || ttl > 10*time.Minute {
+		t.Errorf("TTL = %v, want at most the 10m until expiry", ttl)
+	}
+}
+
+func TestStateManager_RedisOutageFallsBackAndResyncs(t *testing.T) {
+	ctx := context.Background()
+	redis := newFlakyCache()
+	sm := NewDefaultStateManager(redis, slog.Default(), nil)
+
+	if err := sm.RecordInhibition(ctx, newTestState("removed-during-outage", "source", "rule")); err != nil {
+		t.Fatalf("RecordInhibition() error = %v", err)
+	}
+
+	redis.down = true
+	if err := sm.RecordInhibition(ctx, newTestState("recorded-during-outage", "source", "rule")); err != nil {
+		t.Fatalf("RecordInhibition() during outage error = %v", err)
+	}
+	if !sm.IsDegraded() {
+		t.Fatal("state manager not degraded after a Redis error")
+	}
+	if err := sm.RemoveInhibition(ctx, "removed-during-outage"); err != nil {
+		t.Fatalf("RemoveInhibition() during outage error = %v", err)
+	}
+	if ok, _ := sm.IsInhibited(ctx, "recorded-during-outage"); !ok {
+		t.Error("local state lost during outage")
+	}
+
+	// Still down: recovery waits.
+	sm.recoverRedis(ctx)
+	if !sm.IsDegraded() {
+		t.Fatal("recovered while Redis is still down")
+	}
+
+	redis.down = false
+	sm.recoverRedis(ctx)
+	if sm.IsDegraded() {
+		t.Fatal("still degraded after Redis recovered")
+	}
+
+	restarted := NewDefaultStateManager(redis, slog.Default(), nil)
+	if _, err := restarted.Restore(ctx); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if ok, _ := restarted.IsInhibited(ctx, "recorded-during-outage"); !ok {
+		t.Error("inhibition recorded during the outage was not resynced")
+	}
+	if ok, _ := restarted.IsInhibited(ctx, "removed-during-outage"); ok {
+		t.Error("inhibition removed during the outage is still in Redis")
+	}
+}
+
+func TestStateManager_RestoreWhileRedisDown(t *testing.T) {
+	redis := newFlakyCache()
+	redis.down = true
+	sm := NewDefaultStateManager(redis, slog.Default(), nil)
+
+	if _, err := sm.Restore(context.Background()); err == nil {
+		t.Fatal("Restore() with Redis down succeeded")
+	}
+	if !sm.IsDegraded() {
+		t.Error("state manager not degraded after a failed restore")
+	}
+}
+
+func TestTwoTierAlertCache_Restore(t *testing.T) {
+	ctx := context.Background()
+	redis := newFlakyCache()
+
+	before := NewTwoTierAlertCache(redis, slog.Default())
+	defer before.Stop()
+	if err := before.AddFiringAlert(ctx, createTestAlert("NodeDown", "critical", "node-1", "prod")); err != nil {
+		t.Fatalf("AddFiringAlert() error = %v", err)
+	}
+
+	after := NewTwoTierAlertCache(redis, slog.Default())
+	defer after.Stop()
+	restored, err := after.Restore(ctx)
+	if err != nil || restored != 1 {
+		t.Fatalf("Restore() = %d, %v; want 1", restored, err)
+	}
+
+	// A new alert no longer hides the restored source alert.
+	if err := after.AddFiringAlert(ctx, createTestAlert("InstanceDown", "warning", "node-2", "prod")); err != nil {
+		t.Fatal(err)
+	}
+	alerts, _ := after.GetFiringAlerts(ctx)
+	if len(alerts) != 2 {
+		t.Errorf("firing alerts = %d, want 2", len(alerts))
+	}
+}
@@ -29,7 +29,9 @@ import (
 
 	"github.com/ipiton/AMP/pkg/retry"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -45,6 +47,26 @@ type K8sClient interface {
 	// Returns NotFoundError if secret doesn't exist.
 	GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error)
 
+	// ApplySecret creates secret, or replaces the labels, annotations and data
+	// of the existing secret of the same name.
+	ApplySecret(ctx context.Context, namespace string, secret *corev1.Secret) error
+
+	// DeleteSecret deletes a specific secret by name.
+	// Returns NotFoundError if secret doesn't exist.
+	DeleteSecret(ctx context.Context, namespace, name string) error
+
+	// ListConfigMaps returns config maps from namespace matching label selector.
+	// Returns empty slice if no config maps match the selector.
+	ListConfigMaps(ctx context.Context, namespace string, labelSelector string) ([]corev1.ConfigMap, error)
+
+	// WatchSecrets watches the secrets of namespace matching label selector.
+	// The watch ends when ctx is done or the API server closes it.
+	WatchSecrets(ctx context.Context, namespace string, labelSelector string) (watch.Interface, error)
+
+	// WatchConfigMaps watches the config maps of namespace matching label
+	// selector. The watch ends when ctx is done or the API server closes it.
+	WatchConfigMaps(ctx context.Context, namespace string, labelSelector string) (watch.Interface, error)
+
 	// Health checks if K8s API is accessible.
 	// Returns ConnectionError if API is unavailable.
 	Health(ctx context.Context) error
@@ -219,6 +241,149 @@ func (c *DefaultK8sClient) GetSecret(ctx context.Context, namespace, name string
 	return secret, nil
 }
 
+// ApplySecret creates secret, or updates the existing secret of the same name.
+func (c *DefaultK8sClient) ApplySecret(ctx context.Context, namespace string, secret *corev1.Secret) error {
+	c.logger.Debug("Applying K8s secret",
+		"namespace", namespace,
+		"name", secret.Name,
+	)
+
+	secrets := c.clientset.CoreV1().Secrets(namespace)
+	err := c.retryWithBackoff(ctx, func() error {
+		existing, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			desired := secret.DeepCopy()
+			desired.Namespace = namespace
+			_, err = secrets.Create(ctx, desired, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		updated := existing.DeepCopy()
+		updated.Labels = secret.Labels
+		updated.Annotations = secret.Annotations
+		updated.Data = secret.Data
+		updated.StringData = secret.StringData
+		if secret.Type != "" {
+			updated.Type = secret.Type
+		}
+		_, err = secrets.Update(ctx, updated, metav1.UpdateOptions{})
+		return err
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to apply secret",
+			"namespace", namespace,
+			"name", secret.Name,
+			"error", err,
+		)
+		return wrapK8sError("apply secret", err)
+	}
+
+	c.logger.Info("Successfully applied secret",
+		"namespace", namespace,
+		"name", secret.Name,
+	)
+
+	return nil
+}
+
+// DeleteSecret deletes a specific secret by name.
+func (c *DefaultK8sClient) DeleteSecret(ctx context.Context, namespace, name string) error {
+	c.logger.Debug("Deleting K8s secret",
+		"namespace", namespace,
+		"name", name,
+	)
+
+	err := c.retryWithBackoff(ctx, func() error {
+		return c.clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	})
+
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return NewNotFoundError(fmt.Sprintf("secret %s/%s not found", namespace, name))
+		}
+
+		c.logger.Error("Failed to delete secret",
+			"namespace", namespace,
+			"name", name,
+			"error", err,
+		)
+		return wrapK8sError("delete secret", err)
+	}
+
+	c.logger.Info("Successfully deleted secret",
+		"namespace", namespace,
+		"name", name,
+	)
+
+	return nil
+}
+
+// ListConfigMaps returns config maps from namespace matching label selector.
+func (c *DefaultK8sClient) ListConfigMaps(ctx context.Context, namespace string, labelSelector string) ([]corev1.ConfigMap, error) {
+	c.logger.Debug("Listing K8s config maps",
+		"namespace", namespace,
+		"label_selector", labelSelector,
+	)
+
+	var configMaps []corev1.ConfigMap
+	err := c.retryWithBackoff(ctx, func() error {
+		list, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labelSelector,
+			Limit:         1000,
+		})
+		if err != nil {
+			return err
+		}
+
+		configMaps = list.Items
+		if list.Continue != "" {
+			c.logger.Warn("Config maps list truncated, pagination not implemented",
+				"namespace", namespace,
+				"continue_token", list.Continue,
+			)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to list config maps",
+			"namespace", namespace,
+			"error", err,
+		)
+		return nil, wrapK8sError("list config maps", err)
+	}
+
+	c.logger.Debug("Successfully listed config maps",
+		"namespace", namespace,
+		"count", len(configMaps),
+	)
+
+	return configMaps, nil
+}
+
+// WatchSecrets watches the secrets of namespace matching label selector.
+func (c *DefaultK8sClient) WatchSecrets(ctx context.Context, namespace string, labelSelector string) (watch.Interface, error) {
+	w, err := c.clientset.CoreV1().Secrets(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, wrapK8sError("watch secrets", err)
+	}
+	return w, nil
+}
+
+// WatchConfigMaps watches the config maps of namespace matching label selector.
+func (c *DefaultK8sClient) WatchConfigMaps(ctx context.Context, namespace string, labelSelector string) (watch.Interface, error) {
+	w, err := c.clientset.CoreV1().ConfigMaps(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, wrapK8sError("watch config maps", err)
+	}
+	return w, nil
+}
+
 // Health checks if K8s API is accessible.
 func (c *DefaultK8sClient) Health(ctx context.Context) error {
 	// Short timeout for health checks
@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -137,6 +138,73 @@ func TestGetSecret_NotFound(t *testing.T) {
 	assert.ErrorAs(t, err, &notFoundErr)
 }
 
+func TestApplySecret_CreatesAndUpdates(t *testing.T) {
+	client := createFakeClient()
+	ctx := context.Background()
+
+	secret := createTestSecret("amp-target-hooks", "", map[string]string{"publishing-target": "true"}, map[string][]byte{
+		"config": []byte(`{"name":"hooks"}`),
+	})
+	require.NoError(t, client.ApplySecret(ctx, "default", secret))
+
+	created, err := client.GetSecret(ctx, "default", "amp-target-hooks")
+	require.NoError(t, err)
+	assert.Equal(t, "default", created.Namespace)
+	assert.Equal(t, []byte(`{"name":"hooks"}`), created.Data["config"])
+
+	secret.Data["config"] = []byte(`{"name":"hooks","enabled":false}`)
+	require.NoError(t, client.ApplySecret(ctx, "default", secret))
+
+	updated, err := client.GetSecret(ctx, "default", "amp-target-hooks")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`{"name":"hooks","enabled":false}`), updated.Data["config"])
+	assert.Equal(t, map[string]string{"publishing-target": "true"}, updated.Labels)
+}
+
+func TestDeleteSecret(t *testing.T) {
+	client := createFakeClient(createTestSecret("test-secret-1", "default", nil, nil))
+	ctx := context.Background()
+
+	require.NoError(t, client.DeleteSecret(ctx, "default", "test-secret-1"))
+	_, err := client.GetSecret(ctx, "default", "test-secret-1")
+	var notFoundErr *NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+
+	err = client.DeleteSecret(ctx, "default", "test-secret-1")
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestListConfigMapsAndWatch(t *testing.T) {
+	client := createFakeClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher, err := client.WatchConfigMaps(ctx, "default", "publishing-target=true")
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	for _, configMap := range []*corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default", Labels: map[string]string{"publishing-target": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default", Labels: map[string]string{"app": "other"}}},
+	} {
+		_, err := client.clientset.CoreV1().ConfigMaps("default").Create(ctx, configMap, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	configMaps, err := client.ListConfigMaps(ctx, "default", "publishing-target=true")
+	require.NoError(t, err)
+	require.Len(t, configMaps, 1)
+	assert.Equal(t, "target", configMaps[0].Name)
+
+	select {
+	case event := <-watcher.ResultChan():
+		assert.Equal(t, watch.Added, event.Type)
+		assert.Equal(t, "target", event.Object.(*corev1.ConfigMap).Name)
+	case <-time.After(time.Second):
+		t.Fatal("no watch event")
+	}
+}
+
 func TestListSecrets_ContextCancelled(t *testing.T) {
 	client := createFakeClient()
 
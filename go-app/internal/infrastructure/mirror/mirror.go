@@ -0,0 +1,281 @@
+// Package mirror forwards accepted alert webhooks to a peer AMP, typically
+// in another region, so that a regional outage loses neither alert history
+// nor notifications (active-active DR).
+//
+// Forwarding is asynchronous: accepted payloads are queued and sent
+// gzip-compressed to the peer's POST /api/v2/alerts by a background worker,
+// so a slow or unavailable peer never delays ingestion. When the queue is
+// full, payloads are dropped and counted.
+//
+// Loop prevention: mirrored requests carry the X-AMP-Mirrored-From header
+// and are never mirrored again, so two peers can mirror to each other.
+//
+// Usage:
+//
+//	m, err := mirror.New(mirror.Config{PeerURL: "https://amp.eu-west.example.com", Origin: "us-east"})
+//	m.Start(ctx)
+//	defer m.Stop()
+//
+//	// in the webhook handler, once the payload was accepted
+//	m.Forward(r, body)
+package mirror
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ipiton/AMP/pkg/httperror"
+	"github.com/ipiton/AMP/pkg/retry"
+)
+
+// HeaderMirroredFrom marks a mirrored request with the origin instance.
+// Requests carrying it are not mirrored again.
+const HeaderMirroredFrom = "X-AMP-Mirrored-From"
+
+// Mirror results counted in alert_history_mirror_requests_total.
+const (
+	ResultSent    = "sent"    // accepted by the peer
+	ResultFailed  = "failed"  // rejected by the peer or retries exhausted
+	ResultDropped = "dropped" // queue full
+)
+
+const (
+	alertsPath       = "/api/v2/alerts"
+	defaultTimeout   = 10 * time.Second
+	defaultQueueSize = 1000
+	metricsNamespace = "alert_history"
+	metricsSubsystem = "mirror"
+)
+
+// Config configures the Mirror.
+type Config struct {
+	// PeerURL is the base URL of the peer AMP (required).
+	PeerURL string
+
+	// Origin names this instance in the X-AMP-Mirrored-From header
+	// (default: hostname).
+	Origin string
+
+	// APIKey is sent as bearer token when the peer requires auth (optional).
+	APIKey string
+
+	// Timeout per request (default: 10s).
+	Timeout time.Duration
+
+	// QueueSize bounds the payloads waiting to be sent (default: 1000).
+	QueueSize int
+
+	// MaxRetries for network errors, 429 and 5xx responses (0 = none).
+	// Retries back off exponentially from 1s (see retry.Webhook).
+	MaxRetries int
+
+	// Registerer for metrics (default: prometheus.DefaultRegisterer).
+	Registerer prometheus.Registerer
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// HTTPClient overrides the client built from Timeout (for tests).
+	HTTPClient *http.Client
+}
+
+// Mirror forwards webhook payloads to a peer AMP.
+//
+// Thread-safe: Forward may be called concurrently from any goroutine.
+type Mirror struct {
+	config   Config
+	endpoint string
+	client   *http.Client
+	logger   *slog.Logger
+	queue    chan []byte
+
+	retry retry.Strategy
+
+	requests  *prometheus.CounterVec
+	queueSize prometheus.Gauge
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a Mirror and registers its metrics.
+func New(config Config) (*Mirror, error) {
+	peer, err := url.Parse(config.PeerURL)
+	if err != nil || peer.Scheme == "" || peer.Host == "" {
+		return nil, fmt.Errorf("peer URL must be an absolute URL, got %q", config.PeerURL)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.Origin == "" {
+		config.Origin, _ = os.Hostname()
+	}
+	if config.Origin == "" {
+		// The header must be non-empty for loop prevention to work
+		config.Origin = "amp"
+	}
+	if config.Registerer == nil {
+		config.Registerer = prometheus.DefaultRegisterer
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+
+	m := &Mirror{
+		config:   config,
+		endpoint: strings.TrimSuffix(config.PeerURL, "/") + alertsPath,
+		client:   client,
+		logger:   config.Logger.With("component", "webhook_mirror", "peer", peer.Host),
+		queue:    make(chan []byte, config.QueueSize),
+
+		retry: retry.Webhook(config.MaxRetries),
+
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "requests_total",
+			Help:      "Webhook payloads mirrored to the peer AMP by result (sent/failed/dropped)",
+		}, []string{"result"}),
+		queueSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "queue_size",
+			Help:      "Webhook payloads waiting to be mirrored to the peer AMP",
+		}),
+	}
+	m.retry.Logger = m.logger
+	m.retry.OperationName = "webhook_mirror"
+	config.Registerer.MustRegister(m.requests, m.queueSize)
+	return m, nil
+}
+
+// Start starts the background sender.
+func (m *Mirror) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.wg.Add(1)
+	go m.run(ctx)
+}
+
+// Stop stops the background sender. Payloads still queued are dropped.
+func (m *Mirror) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+}
+
+// IsMirrored reports whether r was mirrored by a peer.
+func IsMirrored(r *http.Request) bool {
+	return r.Header.Get(HeaderMirroredFrom) != ""
+}
+
+// Forward queues body, an accepted webhook payload of r, for the peer.
+// Payloads that were themselves mirrored are skipped. Never blocks; returns
+// false when the payload was not queued. Safe to call on a nil Mirror.
+func (m *Mirror) Forward(r *http.Request, body []byte) bool {
+	if m == nil || IsMirrored(r) {
+		return false
+	}
+	select {
+	case m.queue <- body:
+		m.queueSize.Set(float64(len(m.queue)))
+		return true
+	default:
+		m.requests.WithLabelValues(ResultDropped).Inc()
+		m.logger.Warn("Mirror queue full, webhook not mirrored", "queue_size", m.config.QueueSize)
+		return false
+	}
+}
+
+func (m *Mirror) run(ctx context.Context) {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case body := <-m.queue:
+			m.queueSize.Set(float64(len(m.queue)))
+			if err := m.sendWithRetry(ctx, body); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				m.requests.WithLabelValues(ResultFailed).Inc()
+				m.logger.Error("Failed to mirror webhook", "error", err)
+				continue
+			}
+			m.requests.WithLabelValues(ResultSent).Inc()
+		}
+	}
+}
+
+func (m *Mirror) sendWithRetry(ctx context.Context, body []byte) error {
+	payload, err := compress(body)
+	if err != nil {
+		return err
+	}
+	return retry.DoSimple(ctx, m.retry, func() error {
+		return m.send(ctx, payload)
+	})
+}
+
+// send posts payload once. Responses other than 2xx are returned as
+// *httperror.HTTPAPIError, so the retry classifier retries 429 and 5xx only.
+func (m *Mirror) send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set(HeaderMirroredFrom, m.config.Origin)
+	if m.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.config.APIKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return httperror.NewHTTPError(resp.StatusCode, fmt.Sprintf("peer returned HTTP %d", resp.StatusCode), "mirror")
+	}
+	return nil
+}
+
+func compress(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, fmt.Errorf("compress payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,222 @@
+package mirror
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ipiton/AMP/pkg/retry"
+)
+
+// fakePeer records mirrored requests and answers with the queued statuses
+// (200 once they are used up).
+type fakePeer struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+	received chan struct{}
+}
+
+func newFakePeer(statuses ...int) *fakePeer {
+	return &fakePeer{statuses: statuses, received: make(chan struct{}, 16)}
+}
+
+func (p *fakePeer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	body, _ := io.ReadAll(zr)
+
+	p.mu.Lock()
+	p.bodies = append(p.bodies, string(body))
+	p.headers = append(p.headers, r.Header.Clone())
+	status := http.StatusOK
+	if len(p.statuses) > 0 {
+		status, p.statuses = p.statuses[0], p.statuses[1:]
+	}
+	p.mu.Unlock()
+
+	w.WriteHeader(status)
+	p.received <- struct{}{}
+}
+
+func (p *fakePeer) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-p.received:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("peer received %d of %d requests", i, n)
+		}
+	}
+}
+
+func newTestMirror(t *testing.T, peerURL string, config Config) *Mirror {
+	t.Helper()
+	config.PeerURL = peerURL
+	config.Registerer = prometheus.NewRegistry()
+	m, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	m.retry = m.retry.WithAfter(func(time.Duration) <-chan time.Time { return time.After(time.Millisecond) })
+	return m
+}
+
+func webhookRequest(headers map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/v2/alerts", nil)
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestMirror_ForwardsCompressedWithLoopPreventionHeader(t *testing.T) {
+	peer := newFakePeer()
+	server := httptest.NewServer(peer)
+	defer server.Close()
+
+	m := newTestMirror(t, server.URL+"/", Config{Origin: "us-east", APIKey: "peer-token"})
+	m.Start(context.Background())
+	defer m.Stop()
+
+	if !m.Forward(webhookRequest(nil), []byte(`[{"labels":{"alertname":"A"}}]`)) {
+		t.Fatal("Forward() = false, want queued")
+	}
+	peer.wait(t, 1)
+
+	peer.mu.Lock()
+	defer peer.mu.Unlock()
+	if peer.bodies[0] != `[{"labels":{"alertname":"A"}}]` {
+		t.Errorf("peer body = %s", peer.bodies[0])
+	}
+	h := peer.headers[0]
+	if h.Get("Content-Encoding") != "gzip" || h.Get(HeaderMirroredFrom) != "us-east" || h.Get("Authorization") != "Bearer peer-token" {
+		t.Errorf("peer headers = %v", h)
+	}
+
+	waitForCount(t, m.requests.WithLabelValues(ResultSent), 1)
+}
+
+func TestMirror_SkipsMirroredRequests(t *testing.T) {
+	m := newTestMirror(t, "http://peer.example.com", Config{})
+	if m.Forward(webhookRequest(map[string]string{HeaderMirroredFrom: "eu-west"}), []byte(`[]`)) {
+		t.Error("Forward() queued a mirrored request")
+	}
+
+	var nilMirror *Mirror
+	if nilMirror.Forward(webhookRequest(nil), []byte(`[]`)) {
+		t.Error("Forward() on nil Mirror = true")
+	}
+}
+
+func TestMirror_RetriesTransientFailures(t *testing.T) {
+	peer := newFakePeer(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	server := httptest.NewServer(peer)
+	defer server.Close()
+
+	m := newTestMirror(t, server.URL, Config{MaxRetries: 3})
+	m.Start(context.Background())
+	defer m.Stop()
+
+	m.Forward(webhookRequest(nil), []byte(`[]`))
+	peer.wait(t, 3)
+	waitForCount(t, m.requests.WithLabelValues(ResultSent), 1)
+}
+
+func TestMirror_RetriesBackOffWithJitter(t *testing.T) {
+	peer := newFakePeer(http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusBadGateway)
+	server := httptest.NewServer(peer)
+	defer server.Close()
+
+	m := newTestMirror(t, server.URL, Config{MaxRetries: 3})
+	var mu sync.Mutex
+	var delays []time.Duration
+	m.retry = m.retry.WithJitterSource(retry.NewSeededJitterSource(1)).WithAfter(func(d time.Duration) <-chan time.Time {
+		mu.Lock()
+		delays = append(delays, d)
+		mu.Unlock()
+		return time.After(time.Millisecond)
+	})
+	m.Start(context.Background())
+	defer m.Stop()
+
+	m.Forward(webhookRequest(nil), []byte(`[]`))
+	peer.wait(t, 4)
+	waitForCount(t, m.requests.WithLabelValues(ResultSent), 1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delays) != 3 {
+		t.Fatalf("backed off %d times, want 3", len(delays))
+	}
+	for i, d := range delays {
+		base := time.Second << i
+		if d < base*85/100 || d > base*115/100 {
+			t.Errorf("delay %d = %v, want %v ±15%%", i, d, base)
+		}
+	}
+}
+
+func TestMirror_DoesNotRetryRejectedPayloads(t *testing.T) {
+	peer := newFakePeer(http.StatusBadRequest)
+	server := httptest.NewServer(peer)
+	defer server.Close()
+
+	m := newTestMirror(t, server.URL, Config{MaxRetries: 3})
+	m.Start(context.Background())
+	defer m.Stop()
+
+	m.Forward(webhookRequest(nil), []byte(`[]`))
+	peer.wait(t, 1)
+	waitForCount(t, m.requests.WithLabelValues(ResultFailed), 1)
+
+	peer.mu.Lock()
+	defer peer.mu.Unlock()
+	if len(peer.bodies) != 1 {
+		t.Errorf("peer received %d requests, want 1", len(peer.bodies))
+	}
+}
+
+func TestMirror_DropsWhenQueueFull(t *testing.T) {
+	m := newTestMirror(t, "http://peer.example.com", Config{QueueSize: 1})
+
+	// Not started: the first payload stays queued
+	if !m.Forward(webhookRequest(nil), []byte(`[]`)) {
+		t.Fatal("Forward() = false, want queued")
+	}
+	if m.Forward(webhookRequest(nil), []byte(`[]`)) {
+		t.Fatal("Forward() = true on full queue")
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues(ResultDropped)); got != 1 {
+		t.Errorf("dropped = %v, want 1", got)
+	}
+}
+
+func TestNew_RejectsRelativePeerURL(t *testing.T) {
+	if _, err := New(Config{PeerURL: "amp.eu-west", Registerer: prometheus.NewRegistry()}); err == nil {
+		t.Error("New() accepted a relative peer URL")
+	}
+}
+
+func waitForCount(t *testing.T, counter prometheus.Counter, want float64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for testutil.ToFloat64(counter) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("counter = %v, want %v", testutil.ToFloat64(counter), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
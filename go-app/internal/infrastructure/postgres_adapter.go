@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/compression"
 )
 
 // PostgresDatabase адаптер для PostgreSQL, реализующий общий интерфейс Database
@@ -274,7 +275,7 @@ func (p *PostgresDatabase) SaveAlert(ctx context.Context, alert *core.Alert) err
 		return fmt.Errorf("failed to marshal labels: %w", err)
 	}
 
-	annotationsJSON, err := json.Marshal(alert.Annotations)
+	annotationsJSON, err := json.Marshal(compression.CompressValues(alert.Annotations, compression.DefaultThreshold))
 	if err != nil {
 		return fmt.Errorf("failed to marshal annotations: %w", err)
 	}
@@ -368,6 +369,9 @@ func (p *PostgresDatabase) GetAlertByFingerprint(ctx context.Context, fingerprin
 	if err := json.Unmarshal(annotationsJSON, &alert.Annotations); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
 	}
+	if err := compression.DecompressValues(alert.Annotations); err != nil {
+		return nil, fmt.Errorf("failed to decompress annotations: %w", err)
+	}
 
 	// Обработка nullable полей
 	if endsAt != nil {
@@ -518,6 +522,9 @@ func (p *PostgresDatabase) ListAlerts(ctx context.Context, filters *core.AlertFi
 		if err := json.Unmarshal(annotationsJSON, &alert.Annotations); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
 		}
+		if err := compression.DecompressValues(alert.Annotations); err != nil {
+			return nil, fmt.Errorf("failed to decompress annotations: %w", err)
+		}
 
 		// Обработка nullable полей
 		if endsAt != nil {
@@ -561,7 +568,7 @@ func (p *PostgresDatabase) UpdateAlert(ctx context.Context, alert *core.Alert) e
 		return fmt.Errorf("failed to marshal labels: %w", err)
 	}
 
-	annotationsJSON, err := json.Marshal(alert.Annotations)
+	annotationsJSON, err := json.Marshal(compression.CompressValues(alert.Annotations, compression.DefaultThreshold))
 	if err != nil {
 		return fmt.Errorf("failed to marshal annotations: %w", err)
 	}
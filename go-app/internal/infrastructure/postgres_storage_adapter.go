@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/compression"
 )
 
 // PostgresStorageAdapter exposes AlertStorage over an existing pgx pool.
@@ -59,7 +60,7 @@ func (p *PostgresStorageAdapter) SaveAlert(ctx context.Context, alert *core.Aler
 		return fmt.Errorf("failed to marshal labels: %w", err)
 	}
 
-	annotationsJSON, err := json.Marshal(alert.Annotations)
+	annotationsJSON, err := json.Marshal(compression.CompressValues(alert.Annotations, compression.DefaultThreshold))
 	if err != nil {
 		return fmt.Errorf("failed to marshal annotations: %w", err)
 	}
@@ -146,6 +147,9 @@ func (p *PostgresStorageAdapter) GetAlertByFingerprint(ctx context.Context, fing
 	if err := json.Unmarshal(annotationsJSON, &alert.Annotations); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
 	}
+	if err := compression.DecompressValues(alert.Annotations); err != nil {
+		return nil, fmt.Errorf("failed to decompress annotations: %w", err)
+	}
 
 	if endsAt != nil {
 		if t, ok := endsAt.(time.Time); ok {
@@ -274,6 +278,9 @@ func (p *PostgresStorageAdapter) ListAlerts(ctx context.Context, filters *core.A
 		if err := json.Unmarshal(annotationsJSON, &alert.Annotations); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
 		}
+		if err := compression.DecompressValues(alert.Annotations); err != nil {
+			return nil, fmt.Errorf("failed to decompress annotations: %w", err)
+		}
 
 		if endsAt != nil {
 			if t, ok := endsAt.(time.Time); ok {
@@ -311,7 +318,7 @@ func (p *PostgresStorageAdapter) UpdateAlert(ctx context.Context, alert *core.Al
 	if err != nil {
 		return fmt.Errorf("failed to marshal labels: %w", err)
 	}
-	annotationsJSON, err := json.Marshal(alert.Annotations)
+	annotationsJSON, err := json.Marshal(compression.CompressValues(alert.Annotations, compression.DefaultThreshold))
 	if err != nil {
 		return fmt.Errorf("failed to marshal annotations: %w", err)
 	}
@@ -468,3 +475,75 @@ func (p *PostgresStorageAdapter) CleanupOldAlerts(ctx context.Context, retention
 	)
 	return rowsAffected, nil
 }
+
+// SaveClassification stores a classification result in alert_classifications.
+// Every result is kept as a new row, so that re-classifications can be
+// compared with earlier ones; GetClassification returns the latest.
+func (p *PostgresStorageAdapter) SaveClassification(ctx context.Context, fingerprint string, result *core.ClassificationResult) error {
+	if p.pool == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	recommendationsJSON, err := json.Marshal(result.Recommendations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recommendations: %w", err)
+	}
+	metadataJSON, err := json.Marshal(result.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_classifications (
+			alert_fingerprint, severity, confidence, reasoning,
+			recommendations, processing_time, metadata
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	if _, err := p.pool.Exec(ctx, query,
+		fingerprint, string(result.Severity), result.Confidence, result.Reasoning,
+		recommendationsJSON, result.ProcessingTime, metadataJSON,
+	); err != nil {
+		return fmt.Errorf("failed to save classification: %w", err)
+	}
+	return nil
+}
+
+// GetClassification returns the latest classification result of an alert,
+// or nil when it was never classified.
+func (p *PostgresStorageAdapter) GetClassification(ctx context.Context, fingerprint string) (*core.ClassificationResult, error) {
+	if p.pool == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	query := `
+		SELECT severity, confidence, COALESCE(reasoning, ''), recommendations,
+		       COALESCE(processing_time, 0), metadata
+		FROM alert_classifications
+		WHERE alert_fingerprint = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1`
+
+	result := &core.ClassificationResult{}
+	var recommendationsJSON, metadataJSON []byte
+	if err := p.pool.QueryRow(ctx, query, fingerprint).Scan(
+		&result.Severity, &result.Confidence, &result.Reasoning,
+		&recommendationsJSON, &result.ProcessingTime, &metadataJSON,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get classification: %w", err)
+	}
+
+	if len(recommendationsJSON) > 0 {
+		if err := json.Unmarshal(recommendationsJSON, &result.Recommendations); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal recommendations: %w", err)
+		}
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &result.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,239 @@
+// Package promql provides a minimal client for Prometheus instant queries
+// and alerts.
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result types returned by the Prometheus query API.
+const (
+	ResultTypeVector = "vector"
+	ResultTypeScalar = "scalar"
+	ResultTypeMatrix = "matrix"
+	ResultTypeString = "string"
+)
+
+// Sample is one series of a query result. Scalars have no labels.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Result is the outcome of an instant query.
+type Result struct {
+	Type    string
+	Samples []Sample
+}
+
+// Truthy reports whether the result satisfies a condition the way alerting
+// rules do: a vector with at least one series, or a non-zero scalar. A
+// comparison such as `sum(up{job="x"}) == 0` is therefore true when it
+// returns a series, whatever the series value is.
+func (r *Result) Truthy() bool {
+	if r == nil {
+		return false
+	}
+	switch r.Type {
+	case ResultTypeScalar:
+		return len(r.Samples) > 0 && r.Samples[0].Value != 0
+	default:
+		return len(r.Samples) > 0
+	}
+}
+
+// Config configures the Client.
+type Config struct {
+	// URL is the Prometheus base URL, e.g. http://prometheus:9090.
+	URL string
+	// Timeout bounds a single query, also sent to Prometheus (default: 5s).
+	Timeout time.Duration
+	// HTTPClient (default: a client with Timeout).
+	HTTPClient *http.Client
+}
+
+// Client runs instant queries against the Prometheus HTTP API.
+type Client struct {
+	baseURL    string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Prometheus at config.URL.
+func NewClient(config Config) (*Client, error) {
+	base, err := url.Parse(strings.TrimSpace(config.URL))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid prometheus url %q", config.URL)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(base.String(), "/"),
+		timeout:    config.Timeout,
+		httpClient: config.HTTPClient,
+	}, nil
+}
+
+// apiResponse is the envelope of the Prometheus HTTP API.
+type apiResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+		Alerts     []Alert         `json:"alerts"`
+	} `json:"data"`
+}
+
+type apiSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  []any             `json:"value"`
+	Values [][]any           `json:"values"`
+}
+
+// Query runs query as an instant query evaluated now.
+func (c *Client) Query(ctx context.Context, query string) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	form := url.Values{
+		"query":   {query},
+		"timeout": {c.timeout.String()},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/query", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prometheus request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	parsed, err := c.do(req, "query")
+	if err != nil {
+		return nil, err
+	}
+	return parseResult(parsed.Data.ResultType, parsed.Data.Result)
+}
+
+// Alert is an alert of the alerting rules of Prometheus.
+type Alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	// State is pending or firing.
+	State    string    `json:"state"`
+	ActiveAt time.Time `json:"activeAt"`
+	Value    string    `json:"value"`
+}
+
+// Alerts returns the pending and firing alerts of Prometheus
+// (GET /api/v1/alerts).
+func (c *Client) Alerts(ctx context.Context) ([]Alert, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/alerts", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prometheus request: %w", err)
+	}
+
+	parsed, err := c.do(req, "alerts request")
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Data.Alerts, nil
+}
+
+// do sends req and decodes the API envelope; what names the request in errors.
+func (c *Client) do(req *http.Request, what string) (*apiResponse, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus %s failed: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prometheus response: %w", err)
+	}
+
+	var parsed apiResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid prometheus response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if parsed.Status != "success" {
+		return nil, fmt.Errorf("prometheus %s failed: %s: %s", what, parsed.ErrorType, parsed.Error)
+	}
+	return &parsed, nil
+}
+
+func parseResult(resultType string, raw json.RawMessage) (*Result, error) {
+	result := &Result{Type: resultType}
+
+	switch resultType {
+	case ResultTypeScalar, ResultTypeString:
+		var pair []any
+		if err := json.Unmarshal(raw, &pair); err != nil {
+			return nil, fmt.Errorf("invalid %s result: %w", resultType, err)
+		}
+		if resultType == ResultTypeString {
+			if len(pair) == 2 && pair[1] != "" {
+				result.Samples = []Sample{{Value: 1}}
+			}
+			return result, nil
+		}
+		value, err := sampleValue(pair)
+		if err != nil {
+			return nil, err
+		}
+		result.Samples = []Sample{{Value: value}}
+
+	case ResultTypeVector, ResultTypeMatrix:
+		var series []apiSeries
+		if err := json.Unmarshal(raw, &series); err != nil {
+			return nil, fmt.Errorf("invalid %s result: %w", resultType, err)
+		}
+		for _, s := range series {
+			pair := s.Value
+			if resultType == ResultTypeMatrix && len(s.Values) > 0 {
+				pair = s.Values[len(s.Values)-1]
+			}
+			value, err := sampleValue(pair)
+			if err != nil {
+				return nil, err
+			}
+			result.Samples = append(result.Samples, Sample{Labels: s.Metric, Value: value})
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported result type %q", resultType)
+	}
+	return result, nil
+}
+
+// sampleValue decodes a [<unix time>, "<value>"] pair.
+func sampleValue(pair []any) (float64, error) {
+	if len(pair) != 2 {
+		return 0, fmt.Errorf("invalid sample %v", pair)
+	}
+	raw, ok := pair[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value %v", pair[1])
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample value %q: %w", raw, err)
+	}
+	return value, nil
+}
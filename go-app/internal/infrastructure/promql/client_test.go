@@ -0,0 +1,86 @@
+package promql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, body string, status int) (*Client, *string) {
+	t.Helper()
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/prom/api/v1/query", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		gotQuery = r.PostForm.Get("query")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{URL: server.URL + "/prom/"})
+	require.NoError(t, err)
+	return client, &gotQuery
+}
+
+func TestClient_QueryVector(t *testing.T) {
+	client, gotQuery := newTestClient(t, `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"service":"x"},"value":[1700000000.1,"0"]}]}}`, http.StatusOK)
+
+	result, err := client.Query(context.Background(), `sum(up{service="x"}) == 0`)
+	require.NoError(t, err)
+	assert.Equal(t, `sum(up{service="x"}) == 0`, *gotQuery)
+	require.Len(t, result.Samples, 1)
+	assert.Equal(t, "x", result.Samples[0].Labels["service"])
+	assert.True(t, result.Truthy(), "a returned series satisfies the condition even with value 0")
+}
+
+func TestClient_QueryEmptyVectorAndScalar(t *testing.T) {
+	client, _ := newTestClient(t, `{"status":"success","data":{"resultType":"vector","result":[]}}`, http.StatusOK)
+	result, err := client.Query(context.Background(), `up == 0`)
+	require.NoError(t, err)
+	assert.False(t, result.Truthy())
+
+	client, _ = newTestClient(t, `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"0"]}}`, http.StatusOK)
+	result, err = client.Query(context.Background(), `scalar(vector(0))`)
+	require.NoError(t, err)
+	assert.False(t, result.Truthy(), "zero scalar is false")
+}
+
+func TestClient_QueryError(t *testing.T) {
+	client, _ := newTestClient(t, `{"status":"error","errorType":"bad_data","error":"parse error"}`, http.StatusBadRequest)
+	_, err := client.Query(context.Background(), `sum(`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse error")
+
+	_, err = NewClient(Config{URL: "not a url"})
+	assert.Error(t, err)
+}
+
+func TestClient_Alerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/api/v1/alerts", r.URL.Path)
+		_, _ = w.Write([]byte(`{"status":"success","data":{"alerts":[
+			{"labels":{"alertname":"HighCPU"},"annotations":{"summary":"cpu"},"state":"firing","activeAt":"2026-03-16T09:00:00Z","value":"9.5e-01"},
+			{"labels":{"alertname":"DiskFull"},"state":"pending","activeAt":"2026-03-16T09:30:00.5Z","value":"1e+00"}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{URL: server.URL})
+	require.NoError(t, err)
+	alerts, err := client.Alerts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "HighCPU", alerts[0].Labels["alertname"])
+	assert.Equal(t, "cpu", alerts[0].Annotations["summary"])
+	assert.Equal(t, "firing", alerts[0].State)
+	assert.Equal(t, time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC), alerts[0].ActiveAt.UTC())
+	assert.Equal(t, "pending", alerts[1].State)
+}
@@ -0,0 +1,238 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// aws_client.go - Amazon SNS and SQS client (query API, Signature Version 4)
+
+// API versions of the query APIs.
+const (
+	awsSNSAPIVersion = "2010-03-31"
+	awsSQSAPIVersion = "2012-11-05"
+)
+
+// Services of AWS targets, as named in request signatures.
+const (
+	awsServiceSNS = "sns"
+	awsServiceSQS = "sqs"
+)
+
+// awsThrottlingCodes are the error codes of throttled requests. They are
+// reported as 429, so the publishing queue retries them.
+var awsThrottlingCodes = map[string]bool{
+	"Throttling":                true,
+	"ThrottlingException":       true,
+	"ThrottledException":        true,
+	"RequestThrottled":          true,
+	"TooManyRequestsException":  true,
+	"KMSThrottling":             true,
+	"RequestThrottledException": true,
+}
+
+// AWSMessage is a message published to an SNS topic or sent to an SQS queue.
+type AWSMessage struct {
+	Body       string
+	Attributes map[string]string // String message attributes
+
+	// FIFO topics and queues only
+	GroupID         string
+	DeduplicationID string
+}
+
+// AWSClient delivers messages to an SNS topic or SQS queue.
+//
+// Failed requests are returned as *httperror.HTTPAPIError with ProviderAWS,
+// so that the publishing queue can classify them.
+type AWSClient interface {
+	// Send publishes message to the topic, or sends it to the queue, of the
+	// client.
+	Send(ctx context.Context, message AWSMessage) error
+}
+
+// HTTPAWSClient implements AWSClient with the SNS Publish and SQS
+// SendMessage query APIs, signing requests with Signature Version 4.
+// Throttling errors are reported as 429 (see awsThrottlingCodes).
+type HTTPAWSClient struct {
+	httpClient  *http.Client
+	destination awsTargetConfig
+	credentials awsCredentialsProvider
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+// newHTTPAWSClient creates a client for the SNS topic or SQS queue of cfg
+// timeout: request timeout (<= 0 uses 10s)
+func newHTTPAWSClient(cfg awsTargetConfig, timeout time.Duration, logger *slog.Logger) *HTTPAWSClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	httpClient := &http.Client{
+		Timeout: timeout,
+		Transport: newOutboundTransport(&http.Transport{
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12, // TLS 1.2+ required
+			},
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 4,
+			IdleConnTimeout:     30 * time.Second,
+			DialContext: (&net.Dialer{
+				Timeout:   5 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+		}),
+	}
+	return &HTTPAWSClient{
+		httpClient:  httpClient,
+		destination: cfg,
+		credentials: newAWSCredentialsProvider(cfg, httpClient),
+		now:         time.Now,
+		logger:      logger.With("component", "aws_client"),
+	}
+}
+
+// Send publishes message with SNS Publish or SQS SendMessage.
+func (c *HTTPAWSClient) Send(ctx context.Context, message AWSMessage) error {
+	dest := c.destination
+	c.logger.DebugContext(ctx, "Sending message to AWS",
+		slog.String("service", dest.service),
+		slog.String("destination", dest.destination()))
+
+	form := url.Values{}
+	endpoint := dest.endpoint
+	attributePrefix := "MessageAttributes.entry."
+	if dest.service == awsServiceSNS {
+		form.Set("Action", "Publish")
+		form.Set("Version", awsSNSAPIVersion)
+		form.Set("TopicArn", dest.topicARN)
+		form.Set("Message", message.Body)
+	} else {
+		form.Set("Action", "SendMessage")
+		form.Set("Version", awsSQSAPIVersion)
+		form.Set("MessageBody", message.Body)
+		attributePrefix = "MessageAttribute."
+		endpoint = dest.queueURL
+	}
+
+	names := make([]string, 0, len(message.Attributes))
+	for name := range message.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		prefix := attributePrefix + strconv.Itoa(i+1) + "."
+		form.Set(prefix+"Name", name)
+		form.Set(prefix+"Value.DataType", "String")
+		form.Set(prefix+"Value.StringValue", message.Attributes[name])
+	}
+	if message.GroupID != "" {
+		form.Set("MessageGroupId", message.GroupID)
+	}
+	if message.DeduplicationID != "" {
+		form.Set("MessageDeduplicationId", message.DeduplicationID)
+	}
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+
+	body := []byte(form.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequest(req, body, creds, dest.region, dest.service, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return parseAWSError(resp, respBody, ProviderAWS)
+}
+
+// parseAWSError converts the error response of a query API into an
+// *httperror.HTTPAPIError. Throttled requests are reported as 429.
+func parseAWSError(resp *http.Response, body []byte, provider string) error {
+	var errBody struct {
+		Code    string `xml:"Error>Code"`
+		Message string `xml:"Error>Message"`
+	}
+	message := truncateString(string(body), 512)
+	if xml.Unmarshal(body, &errBody) == nil && errBody.Code != "" {
+		message = errBody.Code + ": " + errBody.Message
+	}
+
+	apiErr := &httperror.HTTPAPIError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		Provider:   provider,
+	}
+	if awsThrottlingCodes[errBody.Code] {
+		apiErr.StatusCode = http.StatusTooManyRequests
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			apiErr.RetryAfter = seconds
+		}
+	}
+	return apiErr
+}
+
+// awsClients caches AWS clients by destination and credentials.
+type awsClients struct {
+	*clientCache[AWSClient]
+	logger *slog.Logger
+}
+
+func newAWSClients(logger *slog.Logger) *awsClients {
+	return &awsClients{
+		clientCache: newClientCache[AWSClient](awsClientKey),
+		logger:      logger,
+	}
+}
+
+// awsClientKey identifies the client of a target: its destination, region,
+// endpoints and credentials.
+func awsClientKey(target *core.PublishingTarget) string {
+	headers := target.Headers
+	return strings.Join([]string{
+		target.URL,
+		headers[awsRegionHeader], headers[awsEndpointHeader], headers[awsSTSEndpointHeader],
+		headers[awsAccessKeyIDHeader], headers[awsSecretAccessKeyHeader], headers[awsSessionTokenHeader],
+		headers[awsRoleARNHeader],
+	}, "\x00")
+}
+
+// get returns the client for target, created from its parsed cfg.
+func (c *awsClients) get(target *core.PublishingTarget, cfg awsTargetConfig) AWSClient {
+	return c.getOrCreate(target, func() AWSClient {
+		return newHTTPAWSClient(cfg, 10*time.Second, c.logger)
+	})
+}
@@ -0,0 +1,253 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// aws_credentials.go - AWS credentials of SNS/SQS targets
+
+// Environment variables of the default AWS credential chain.
+const (
+	awsAccessKeyIDEnv         = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnv     = "AWS_SECRET_ACCESS_KEY"
+	awsSessionTokenEnv        = "AWS_SESSION_TOKEN"
+	awsRoleARNEnv             = "AWS_ROLE_ARN"
+	awsWebIdentityTokenEnv    = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	awsRegionEnv              = "AWS_REGION"
+	awsDefaultRegionEnv       = "AWS_DEFAULT_REGION"
+	awsInstanceMetadataURL    = "http://169.254.169.254"
+	awsCredentialsRefreshSkew = 5 * time.Minute
+	awsAssumeRoleSessionName  = "amp-publisher"
+)
+
+// ErrNoAWSCredentials is returned when no AWS credentials are configured for
+// a target nor available from the environment.
+var ErrNoAWSCredentials = errors.New("aws: no credentials (set access_key_id/secret_access_key, role_arn with a web identity token, or run with an IAM role)")
+
+// awsCredentials are the credentials requests are signed with. Temporary
+// credentials carry a session token and expire.
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expires         time.Time // zero for long-term credentials
+}
+
+// awsCredentialsProvider returns the credentials to sign a request with.
+type awsCredentialsProvider interface {
+	Retrieve(ctx context.Context) (awsCredentials, error)
+}
+
+// staticAWSCredentials are credentials configured on the target or in the
+// environment.
+type staticAWSCredentials awsCredentials
+
+func (c staticAWSCredentials) Retrieve(context.Context) (awsCredentials, error) {
+	return awsCredentials(c), nil
+}
+
+// webIdentityAWSCredentials assumes an IAM role with the web identity token
+// of the pod (IAM roles for service accounts, EKS Pod Identity webhooks).
+type webIdentityAWSCredentials struct {
+	httpClient  *http.Client
+	stsEndpoint string
+	roleARN     string
+	tokenFile   string
+}
+
+func (c *webIdentityAWSCredentials) Retrieve(ctx context.Context) (awsCredentials, error) {
+	token, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: failed to read web identity token: %w", err)
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {c.roleARN},
+		"RoleSessionName":  {awsAssumeRoleSessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.stsEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: failed to create STS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: STS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: failed to read STS response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return awsCredentials{}, fmt.Errorf("aws: assume role %s: %w", c.roleARN, parseAWSError(resp, body, ProviderAWS))
+	}
+
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: failed to decode STS response: %w", err)
+	}
+	return awsCredentials{
+		AccessKeyID:     result.Credentials.AccessKeyID,
+		SecretAccessKey: result.Credentials.SecretAccessKey,
+		SessionToken:    result.Credentials.SessionToken,
+		Expires:         result.Credentials.Expiration,
+	}, nil
+}
+
+// instanceRoleAWSCredentials reads the credentials of the EC2 instance
+// role from the instance metadata service (IMDSv2).
+type instanceRoleAWSCredentials struct {
+	httpClient *http.Client
+	endpoint   string
+}
+
+func (c *instanceRoleAWSCredentials) Retrieve(ctx context.Context) (awsCredentials, error) {
+	get := func(method, path string, header http.Header) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header = header
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("%s %s: status %d", method, path, resp.StatusCode)
+		}
+		return strings.TrimSpace(string(body)), nil
+	}
+
+	token, err := get(http.MethodPut, "/latest/api/token", http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"21600"}})
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: instance metadata unavailable: %w", err)
+	}
+	header := http.Header{"X-Aws-Ec2-Metadata-Token": {token}}
+	roles, err := get(http.MethodGet, "/latest/meta-data/iam/security-credentials/", header)
+	if err != nil || roles == "" {
+		return awsCredentials{}, fmt.Errorf("aws: no instance role: %w", errors.Join(ErrNoAWSCredentials, err))
+	}
+	role, _, _ := strings.Cut(roles, "\n")
+	raw, err := get(http.MethodGet, "/latest/meta-data/iam/security-credentials/"+role, header)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: failed to read instance role credentials: %w", err)
+	}
+
+	var result struct {
+		AccessKeyID     string    `json:"AccessKeyId"`
+		SecretAccessKey string    `json:"SecretAccessKey"`
+		Token           string    `json:"Token"`
+		Expiration      time.Time `json:"Expiration"`
+	}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return awsCredentials{}, fmt.Errorf("aws: failed to decode instance role credentials: %w", err)
+	}
+	return awsCredentials{
+		AccessKeyID:     result.AccessKeyID,
+		SecretAccessKey: result.SecretAccessKey,
+		SessionToken:    result.Token,
+		Expires:         result.Expiration,
+	}, nil
+}
+
+// failedAWSCredentials is the provider of targets whose credentials cannot
+// be obtained.
+type failedAWSCredentials struct {
+	err error
+}
+
+func (c failedAWSCredentials) Retrieve(context.Context) (awsCredentials, error) {
+	return awsCredentials{}, c.err
+}
+
+// cachedAWSCredentials caches the credentials of a provider until shortly
+// before they expire.
+type cachedAWSCredentials struct {
+	provider awsCredentialsProvider
+
+	mu    sync.Mutex
+	creds awsCredentials
+	valid bool
+}
+
+func (c *cachedAWSCredentials) Retrieve(ctx context.Context) (awsCredentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.valid && (c.creds.Expires.IsZero() || time.Until(c.creds.Expires) > awsCredentialsRefreshSkew) {
+		return c.creds, nil
+	}
+	creds, err := c.provider.Retrieve(ctx)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	c.creds, c.valid = creds, true
+	return creds, nil
+}
+
+// newAWSCredentialsProvider returns the credentials of a target, in order:
+//   - access_key_id/secret_access_key (and session_token) headers
+//   - role_arn header, assumed with the web identity token of the pod
+//   - AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY environment variables
+//   - AWS_ROLE_ARN with AWS_WEB_IDENTITY_TOKEN_FILE (IAM roles for service accounts)
+//   - the EC2 instance role (instance metadata service)
+func newAWSCredentialsProvider(cfg awsTargetConfig, httpClient *http.Client) awsCredentialsProvider {
+	if cfg.accessKeyID != "" {
+		return staticAWSCredentials{
+			AccessKeyID:     cfg.accessKeyID,
+			SecretAccessKey: cfg.secretAccessKey,
+			SessionToken:    cfg.sessionToken,
+		}
+	}
+
+	stsEndpoint := cfg.stsEndpoint
+	if stsEndpoint == "" {
+		stsEndpoint = "https://sts." + cfg.region + "." + awsDNSSuffix(cfg.partition) + "/"
+	}
+	tokenFile := os.Getenv(awsWebIdentityTokenEnv)
+	if cfg.roleARN != "" {
+		if tokenFile == "" {
+			return failedAWSCredentials{err: fmt.Errorf("aws: role_arn requires a web identity token (%s)", awsWebIdentityTokenEnv)}
+		}
+		return &cachedAWSCredentials{provider: &webIdentityAWSCredentials{
+			httpClient: httpClient, stsEndpoint: stsEndpoint, roleARN: cfg.roleARN, tokenFile: tokenFile,
+		}}
+	}
+
+	if id, secret := os.Getenv(awsAccessKeyIDEnv), os.Getenv(awsSecretAccessKeyEnv); id != "" && secret != "" {
+		return staticAWSCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv(awsSessionTokenEnv)}
+	}
+	if roleARN := os.Getenv(awsRoleARNEnv); roleARN != "" && tokenFile != "" {
+		return &cachedAWSCredentials{provider: &webIdentityAWSCredentials{
+			httpClient: httpClient, stsEndpoint: stsEndpoint, roleARN: roleARN, tokenFile: tokenFile,
+		}}
+	}
+	return &cachedAWSCredentials{provider: &instanceRoleAWSCredentials{httpClient: httpClient, endpoint: awsInstanceMetadataURL}}
+}
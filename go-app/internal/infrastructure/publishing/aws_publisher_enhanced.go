@@ -0,0 +1,298 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// aws_publisher_enhanced.go - Amazon SNS topic and SQS queue publisher
+
+// Target headers configuring AWS delivery.
+const (
+	awsRegionHeader            = "region"             // default: from the topic ARN or queue URL
+	awsEndpointHeader          = "endpoint"           // SNS API endpoint (VPC endpoint, LocalStack)
+	awsSTSEndpointHeader       = "sts_endpoint"       // STS endpoint for role_arn
+	awsAccessKeyIDHeader       = "access_key_id"      // static credentials
+	awsSecretAccessKeyHeader   = "secret_access_key"  // static credentials
+	awsSessionTokenHeader      = "session_token"      // static temporary credentials
+	awsRoleARNHeader           = "role_arn"           // role assumed with the pod's web identity token
+	awsMessageAttributesHeader = "message_attributes" // comma-separated labels; default alertname,severity,namespace
+)
+
+// awsDefaultAttributeLabels are the labels sent as message attributes when
+// the target does not list them.
+var awsDefaultAttributeLabels = []string{"alertname", "severity", "namespace"}
+
+// awsMaxMessageAttributes is the message attribute limit of SNS and SQS;
+// one is taken by the alert status.
+const awsMaxMessageAttributes = 10
+
+// awsAttributeNameRegex matches the label names usable as attribute names.
+var awsAttributeNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ErrMissingAWSRegion is returned when the region of an AWS target is
+// neither configured nor derivable from its URL or the environment.
+var ErrMissingAWSRegion = errors.New("aws: region not found (set the region header or AWS_REGION)")
+
+// awsTargetConfig is the AWS delivery configuration of a target.
+type awsTargetConfig struct {
+	service   string // awsServiceSNS or awsServiceSQS
+	topicARN  string // SNS
+	queueURL  string // SQS
+	region    string
+	partition string // aws, aws-cn, aws-us-gov
+	fifo      bool
+
+	endpoint    string // SNS API endpoint
+	stsEndpoint string // "" uses the regional STS endpoint
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	roleARN         string
+
+	attributeLabels []string
+}
+
+// destination returns the topic ARN or queue URL.
+func (c awsTargetConfig) destination() string {
+	if c.service == awsServiceSNS {
+		return c.topicARN
+	}
+	return c.queueURL
+}
+
+// awsDNSSuffix returns the domain of the endpoints of an AWS partition.
+func awsDNSSuffix(partition string) string {
+	if partition == "aws-cn" {
+		return "amazonaws.com.cn"
+	}
+	return "amazonaws.com"
+}
+
+// ValidateAWSTarget checks the destination, region and headers of an AWS
+// target, for target discovery.
+func ValidateAWSTarget(target *core.PublishingTarget) error {
+	_, err := parseAWSTargetConfig(target)
+	return err
+}
+
+// parseAWSTargetConfig reads the AWS delivery configuration of a target:
+// the URL is an SNS topic ARN (arn:aws:sns:<region>:<account>:<topic>) or
+// an SQS queue URL (https://sqs.<region>.amazonaws.com/<account>/<queue>).
+func parseAWSTargetConfig(target *core.PublishingTarget) (awsTargetConfig, error) {
+	headers := target.Headers
+	cfg := awsTargetConfig{
+		region:          strings.TrimSpace(headers[awsRegionHeader]),
+		partition:       "aws",
+		endpoint:        strings.TrimSpace(headers[awsEndpointHeader]),
+		stsEndpoint:     strings.TrimSpace(headers[awsSTSEndpointHeader]),
+		accessKeyID:     headers[awsAccessKeyIDHeader],
+		secretAccessKey: headers[awsSecretAccessKeyHeader],
+		sessionToken:    headers[awsSessionTokenHeader],
+		roleARN:         strings.TrimSpace(headers[awsRoleARNHeader]),
+	}
+
+	if strings.HasPrefix(target.URL, "arn:") {
+		// arn:<partition>:sns:<region>:<account>:<topic>
+		parts := strings.Split(target.URL, ":")
+		if len(parts) != 6 || parts[2] != awsServiceSNS || parts[4] == "" || parts[5] == "" {
+			return awsTargetConfig{}, fmt.Errorf("aws: invalid SNS topic ARN %q (SQS queues are configured by queue URL)", target.URL)
+		}
+		cfg.service = awsServiceSNS
+		cfg.topicARN = target.URL
+		cfg.partition = parts[1]
+		cfg.fifo = strings.HasSuffix(parts[5], ".fifo")
+		if cfg.region == "" {
+			cfg.region = parts[3]
+		}
+	} else {
+		u, err := url.Parse(target.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+			return awsTargetConfig{}, fmt.Errorf("aws: URL must be an SNS topic ARN or an SQS queue URL: %q", target.URL)
+		}
+		cfg.service = awsServiceSQS
+		cfg.queueURL = target.URL
+		cfg.fifo = strings.HasSuffix(u.Path, ".fifo")
+		host := u.Hostname()
+		if strings.HasSuffix(host, ".amazonaws.com.cn") {
+			cfg.partition = "aws-cn"
+		}
+		if cfg.region == "" {
+			cfg.region = awsRegionFromQueueHost(host)
+		}
+	}
+
+	if cfg.region == "" {
+		cfg.region = os.Getenv(awsRegionEnv)
+	}
+	if cfg.region == "" {
+		cfg.region = os.Getenv(awsDefaultRegionEnv)
+	}
+	if cfg.region == "" {
+		return awsTargetConfig{}, ErrMissingAWSRegion
+	}
+	if cfg.service == awsServiceSNS && cfg.endpoint == "" {
+		cfg.endpoint = "https://sns." + cfg.region + "." + awsDNSSuffix(cfg.partition) + "/"
+	}
+	for _, endpoint := range []string{cfg.endpoint, cfg.stsEndpoint} {
+		if u, err := url.Parse(endpoint); endpoint != "" && (err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "") {
+			return awsTargetConfig{}, fmt.Errorf("aws: invalid endpoint %q", endpoint)
+		}
+	}
+	if (cfg.accessKeyID == "") != (cfg.secretAccessKey == "") {
+		return awsTargetConfig{}, errors.New("aws: access_key_id and secret_access_key must be set together")
+	}
+
+	cfg.attributeLabels = awsDefaultAttributeLabels
+	if raw, ok := headers[awsMessageAttributesHeader]; ok {
+		cfg.attributeLabels = nil
+		for _, label := range strings.Split(raw, ",") {
+			if label = strings.TrimSpace(label); label == "" {
+				continue
+			}
+			if !awsAttributeNameRegex.MatchString(label) || label == "status" {
+				return awsTargetConfig{}, fmt.Errorf("aws: invalid message attribute label %q", label)
+			}
+			cfg.attributeLabels = append(cfg.attributeLabels, label)
+		}
+		if len(cfg.attributeLabels) > awsMaxMessageAttributes-1 {
+			return awsTargetConfig{}, fmt.Errorf("aws: at most %d message attribute labels", awsMaxMessageAttributes-1)
+		}
+	}
+	return cfg, nil
+}
+
+// awsRegionFromQueueHost returns the region of an SQS endpoint host
+// (sqs.<region>.amazonaws.com or the legacy <region>.queue.amazonaws.com),
+// "" for other hosts.
+func awsRegionFromQueueHost(host string) string {
+	labels := strings.Split(host, ".")
+	if len(labels) < 4 || labels[2] != "amazonaws" {
+		return ""
+	}
+	switch {
+	case labels[0] == "sqs":
+		return labels[1]
+	case labels[1] == "queue":
+		return labels[0]
+	}
+	return ""
+}
+
+// EnhancedAWSPublisher implements AlertPublisher for Amazon SNS topics and
+// SQS queues.
+//
+// Every firing and resolved notification is delivered as a message whose
+// body is the alert event (as for Kafka targets), with message attributes
+// from the alert's labels and status, so SNS subscriptions can filter on
+// them. FIFO topics and queues (.fifo) group messages by fingerprint and
+// deduplicate them by fingerprint and status.
+type EnhancedAWSPublisher struct {
+	*BaseEnhancedPublisher             // Embedded base publisher for common functionality
+	clients                *awsClients // AWS clients by destination and credentials
+}
+
+// NewEnhancedAWSPublisher creates a new SNS/SQS publisher
+// metrics: Prometheus metrics recorder
+// formatter: Alert formatter used with core.FormatAWS
+func NewEnhancedAWSPublisher(
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return newEnhancedAWSPublisher(newAWSClients(logger), metrics, formatter, logger)
+}
+
+func newEnhancedAWSPublisher(clients *awsClients, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedAWSPublisher {
+	return &EnhancedAWSPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "aws_publisher"),
+		),
+		clients: clients,
+	}
+}
+
+// Publish delivers the alert event of enrichedAlert to the target topic or
+// queue.
+func (p *EnhancedAWSPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	cfg, err := parseAWSTargetConfig(target)
+	if err != nil {
+		return err
+	}
+	client := p.clients.get(target, cfg)
+	operation := "publish"
+	if cfg.service == awsServiceSQS {
+		operation = "send_message"
+	}
+
+	alert := enrichedAlert.Alert
+	p.LogPublishStart(ctx, v2.ProviderAWS, enrichedAlert)
+
+	event, err := p.GetFormatter().FormatAlert(ctx, enrichedAlert, core.FormatAWS)
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderAWS, operation, "format_error")
+		}
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordPayloadSize(v2.ProviderAWS, len(body))
+	}
+
+	message := AWSMessage{
+		Body:       string(body),
+		Attributes: map[string]string{"status": string(alert.Status)},
+	}
+	for _, label := range cfg.attributeLabels {
+		if value := alert.Labels[label]; value != "" {
+			message.Attributes[label] = value
+		}
+	}
+	if cfg.fifo {
+		message.GroupID = alert.Fingerprint
+		message.DeduplicationID = alert.Fingerprint + "-" + string(alert.Status)
+	}
+
+	startTime := time.Now()
+	err = client.Send(ctx, message)
+	duration := time.Since(startTime)
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordAPIDuration(v2.ProviderAWS, operation, "POST", duration)
+	}
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderAWS, operation, GetPublishingErrorType(err))
+		}
+		p.LogPublishError(ctx, v2.ProviderAWS, alert.Fingerprint, err)
+		return fmt.Errorf("failed to deliver to %s in %s: %w", cfg.destination(), target.Name, err)
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordMessage(v2.ProviderAWS, "success")
+	}
+	p.LogPublishSuccess(ctx, v2.ProviderAWS, alert.Fingerprint, duration)
+	return nil
+}
+
+// Name returns publisher name
+func (p *EnhancedAWSPublisher) Name() string {
+	return "AWS"
+}
@@ -0,0 +1,314 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// TestSignAWSRequest checks the signer against the example of the AWS
+// Signature Version 4 documentation (IAM ListUsers).
+func TestSignAWSRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(req, nil, creds, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		req.Header.Get("Authorization"))
+	assert.Empty(t, req.Header.Get("X-Amz-Security-Token"))
+}
+
+func TestParseAWSTargetConfig(t *testing.T) {
+	t.Setenv(awsRegionEnv, "")
+	t.Setenv(awsDefaultRegionEnv, "")
+
+	cfg, err := parseAWSTargetConfig(&core.PublishingTarget{URL: "arn:aws:sns:eu-west-1:123456789012:alerts"})
+	require.NoError(t, err)
+	assert.Equal(t, awsServiceSNS, cfg.service)
+	assert.Equal(t, "eu-west-1", cfg.region)
+	assert.Equal(t, "https://sns.eu-west-1.amazonaws.com/", cfg.endpoint)
+	assert.False(t, cfg.fifo)
+	assert.Equal(t, awsDefaultAttributeLabels, cfg.attributeLabels)
+
+	cfg, err = parseAWSTargetConfig(&core.PublishingTarget{URL: "arn:aws-cn:sns:cn-north-1:123456789012:alerts.fifo"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://sns.cn-north-1.amazonaws.com.cn/", cfg.endpoint)
+	assert.True(t, cfg.fifo)
+
+	cfg, err = parseAWSTargetConfig(&core.PublishingTarget{
+		URL:     "https://sqs.us-east-2.amazonaws.com/123456789012/alerts.fifo",
+		Headers: map[string]string{"message_attributes": "alertname, team"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, awsServiceSQS, cfg.service)
+	assert.Equal(t, "us-east-2", cfg.region)
+	assert.True(t, cfg.fifo)
+	assert.Equal(t, []string{"alertname", "team"}, cfg.attributeLabels)
+
+	// Legacy queue host, and region from the environment for custom hosts
+	cfg, err = parseAWSTargetConfig(&core.PublishingTarget{URL: "https://ap-south-1.queue.amazonaws.com/123456789012/alerts"})
+	require.NoError(t, err)
+	assert.Equal(t, "ap-south-1", cfg.region)
+
+	_, err = parseAWSTargetConfig(&core.PublishingTarget{URL: "https://sqs.internal.example.com/123456789012/alerts"})
+	assert.ErrorIs(t, err, ErrMissingAWSRegion)
+	t.Setenv(awsRegionEnv, "eu-central-1")
+	cfg, err = parseAWSTargetConfig(&core.PublishingTarget{URL: "https://sqs.internal.example.com/123456789012/alerts"})
+	require.NoError(t, err)
+	assert.Equal(t, "eu-central-1", cfg.region)
+}
+
+func TestParseAWSTargetConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		headers map[string]string
+	}{
+		{"SQS ARN", "arn:aws:sqs:eu-west-1:123456789012:alerts", nil},
+		{"short ARN", "arn:aws:sns:eu-west-1:alerts", nil},
+		{"no queue path", "https://sqs.eu-west-1.amazonaws.com/", nil},
+		{"not a URL", "sqs.eu-west-1.amazonaws.com/123456789012/alerts", nil},
+		{"key without secret", "arn:aws:sns:eu-west-1:123456789012:alerts", map[string]string{"access_key_id": "AKID"}},
+		{"invalid endpoint", "arn:aws:sns:eu-west-1:123456789012:alerts", map[string]string{"endpoint": "sns.local"}},
+		{"invalid attribute", "arn:aws:sns:eu-west-1:123456789012:alerts", map[string]string{"message_attributes": "app.kubernetes.io/name"}},
+		{"status attribute", "arn:aws:sns:eu-west-1:123456789012:alerts", map[string]string{"message_attributes": "status"}},
+		{"too many attributes", "arn:aws:sns:eu-west-1:123456789012:alerts", map[string]string{"message_attributes": "a,b,c,d,e,f,g,h,i,j"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAWSTarget(&core.PublishingTarget{URL: tt.url, Headers: tt.headers})
+			assert.Error(t, err)
+		})
+	}
+}
+
+// awsRequest is a query API request received by the fake AWS endpoint.
+type awsRequest struct {
+	path          string
+	authorization string
+	securityToken string
+	form          url.Values
+}
+
+func newFakeAWSEndpoint(t *testing.T, status int, response string) (*httptest.Server, func() []awsRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []awsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		mu.Lock()
+		requests = append(requests, awsRequest{
+			path:          r.URL.Path,
+			authorization: r.Header.Get("Authorization"),
+			securityToken: r.Header.Get("X-Amz-Security-Token"),
+			form:          r.PostForm,
+		})
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []awsRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]awsRequest(nil), requests...)
+	}
+}
+
+func newAWSTestAlert(status core.AlertStatus) *core.EnrichedAlert {
+	alert := newKafkaTestAlert(status)
+	alert.Alert.Fingerprint = "aws-fp"
+	alert.Alert.Labels = map[string]string{"alertname": "HighCPU", "severity": "warning", "team": "infra"}
+	return alert
+}
+
+func TestEnhancedAWSPublisher_SNS(t *testing.T) {
+	server, requests := newFakeAWSEndpoint(t, http.StatusOK, `<PublishResponse><PublishResult><MessageId>1</MessageId></PublishResult></PublishResponse>`)
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{
+		Name:   "sns-alerts",
+		Type:   "aws",
+		URL:    "arn:aws:sns:eu-west-1:123456789012:alerts",
+		Format: core.FormatAWS,
+		Headers: map[string]string{
+			"endpoint":          server.URL,
+			"access_key_id":     "AKIDEXAMPLE",
+			"secret_access_key": "secret",
+		},
+	}
+
+	// Endpoint, region and credentials come from the target, not the factory
+	publisher, err := factory.CreatePublisher(target.Type)
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedAWSPublisher{}, publisher)
+	require.NoError(t, publisher.Publish(context.Background(), newAWSTestAlert(core.StatusFiring), target))
+
+	got := requests()
+	require.Len(t, got, 1)
+	form := got[0].form
+	assert.Equal(t, "Publish", form.Get("Action"))
+	assert.Equal(t, awsSNSAPIVersion, form.Get("Version"))
+	assert.Equal(t, target.URL, form.Get("TopicArn"))
+	assert.Contains(t, form.Get("Message"), `"event_type":"alert.firing"`)
+	assert.Contains(t, got[0].authorization, "Credential=AKIDEXAMPLE/")
+	assert.Contains(t, got[0].authorization, "/eu-west-1/sns/aws4_request")
+	assert.Empty(t, got[0].securityToken)
+
+	// Attributes sorted by name: alertname, severity, status (no namespace label)
+	assert.Equal(t, "alertname", form.Get("MessageAttributes.entry.1.Name"))
+	assert.Equal(t, "HighCPU", form.Get("MessageAttributes.entry.1.Value.StringValue"))
+	assert.Equal(t, "severity", form.Get("MessageAttributes.entry.2.Name"))
+	assert.Equal(t, "status", form.Get("MessageAttributes.entry.3.Name"))
+	assert.Equal(t, "firing", form.Get("MessageAttributes.entry.3.Value.StringValue"))
+	assert.Equal(t, "String", form.Get("MessageAttributes.entry.3.Value.DataType"))
+	assert.Empty(t, form.Get("MessageAttributes.entry.4.Name"))
+	assert.Empty(t, form.Get("MessageGroupId"), "standard topic")
+}
+
+func TestEnhancedAWSPublisher_SQSFIFO(t *testing.T) {
+	server, requests := newFakeAWSEndpoint(t, http.StatusOK, `<SendMessageResponse/>`)
+
+	target := &core.PublishingTarget{
+		Name:   "sqs-alerts",
+		Type:   "aws",
+		URL:    server.URL + "/123456789012/alerts.fifo",
+		Format: core.FormatAWS,
+		Headers: map[string]string{
+			"region":             "us-east-2",
+			"access_key_id":      "ASIAEXAMPLE",
+			"secret_access_key":  "secret",
+			"session_token":      "session",
+			"message_attributes": "team",
+		},
+	}
+	publisher := NewEnhancedAWSPublisher(nil, NewAlertFormatter(""), slog.Default())
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, newAWSTestAlert(core.StatusFiring), target))
+	require.NoError(t, publisher.Publish(ctx, newAWSTestAlert(core.StatusResolved), target))
+
+	got := requests()
+	require.Len(t, got, 2)
+	for i, status := range []string{"firing", "resolved"} {
+		form := got[i].form
+		assert.Equal(t, "/123456789012/alerts.fifo", got[i].path)
+		assert.Equal(t, "SendMessage", form.Get("Action"))
+		assert.Contains(t, form.Get("MessageBody"), `"event_type":"alert.`+status+`"`)
+		assert.Equal(t, "status", form.Get("MessageAttribute.1.Name"))
+		assert.Equal(t, status, form.Get("MessageAttribute.1.Value.StringValue"))
+		assert.Equal(t, "team", form.Get("MessageAttribute.2.Name"))
+		assert.Equal(t, "infra", form.Get("MessageAttribute.2.Value.StringValue"))
+		assert.Equal(t, "aws-fp", form.Get("MessageGroupId"))
+		assert.Equal(t, "aws-fp-"+status, form.Get("MessageDeduplicationId"))
+		assert.Contains(t, got[i].authorization, "/us-east-2/sqs/aws4_request")
+		assert.Equal(t, "session", got[i].securityToken)
+	}
+}
+
+func TestEnhancedAWSPublisher_Throttled(t *testing.T) {
+	server, _ := newFakeAWSEndpoint(t, http.StatusBadRequest,
+		`<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`)
+
+	target := &core.PublishingTarget{
+		Name:    "sns-alerts",
+		Type:    "aws",
+		URL:     "arn:aws:sns:eu-west-1:123456789012:alerts",
+		Format:  core.FormatAWS,
+		Headers: map[string]string{"endpoint": server.URL, "access_key_id": "AKID", "secret_access_key": "secret"},
+	}
+	publisher := NewEnhancedAWSPublisher(nil, NewAlertFormatter(""), slog.Default())
+	err := publisher.Publish(context.Background(), newAWSTestAlert(core.StatusFiring), target)
+
+	var apiErr *httperror.HTTPAPIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, ProviderAWS, apiErr.Provider)
+	assert.Equal(t, "Throttling: Rate exceeded", apiErr.Message)
+}
+
+func TestEnhancedAWSPublisher_WebIdentity(t *testing.T) {
+	var stsCalls int
+	var stsMu sync.Mutex
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/amp", r.PostForm.Get("RoleArn"))
+		assert.Equal(t, "pod-token", r.PostForm.Get("WebIdentityToken"))
+		stsMu.Lock()
+		stsCalls++
+		stsMu.Unlock()
+		_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>` +
+			`<AccessKeyId>ASIAROLE</AccessKeyId><SecretAccessKey>role-secret</SecretAccessKey><SessionToken>role-session</SessionToken>` +
+			`<Expiration>` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `</Expiration>` +
+			`</Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer sts.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("pod-token\n"), 0o600))
+	t.Setenv(awsWebIdentityTokenEnv, tokenFile)
+
+	server, requests := newFakeAWSEndpoint(t, http.StatusOK, `<PublishResponse/>`)
+	target := &core.PublishingTarget{
+		Name:   "sns-alerts",
+		Type:   "aws",
+		URL:    "arn:aws:sns:eu-west-1:123456789012:alerts",
+		Format: core.FormatAWS,
+		Headers: map[string]string{
+			"endpoint":     server.URL,
+			"sts_endpoint": sts.URL,
+			"role_arn":     "arn:aws:iam::123456789012:role/amp",
+		},
+	}
+	publisher := NewEnhancedAWSPublisher(nil, NewAlertFormatter(""), slog.Default())
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, newAWSTestAlert(core.StatusFiring), target))
+	require.NoError(t, publisher.Publish(ctx, newAWSTestAlert(core.StatusResolved), target))
+
+	got := requests()
+	require.Len(t, got, 2)
+	for _, req := range got {
+		assert.True(t, strings.HasPrefix(req.authorization, "AWS4-HMAC-SHA256 Credential=ASIAROLE/"))
+		assert.Equal(t, "role-session", req.securityToken)
+	}
+	assert.Equal(t, 1, stsCalls, "credentials are cached until they expire")
+}
+
+func TestAWSClients_Retain(t *testing.T) {
+	clients := newAWSClients(slog.Default())
+	a := &core.PublishingTarget{URL: "arn:aws:sns:eu-west-1:123456789012:a"}
+	b := &core.PublishingTarget{URL: "arn:aws:sns:eu-west-1:123456789012:b"}
+	for _, target := range []*core.PublishingTarget{a, b} {
+		cfg, err := parseAWSTargetConfig(target)
+		require.NoError(t, err)
+		clients.get(target, cfg)
+	}
+	require.Len(t, clients.clients, 2)
+
+	clients.retain([]*core.PublishingTarget{b})
+	assert.Len(t, clients.clients, 1)
+	assert.Contains(t, clients.clients, awsClientKey(b))
+}
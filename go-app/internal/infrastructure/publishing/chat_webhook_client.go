@@ -0,0 +1,161 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// chat_webhook_client.go - incoming webhook client of Google Chat and Mattermost
+
+// ChatWebhookClient posts messages to the incoming webhooks of a chat
+// (Google Chat, Mattermost).
+type ChatWebhookClient interface {
+	// PostMessage posts a JSON-encoded message to webhookURL.
+	// Failed requests are returned as *httperror.HTTPAPIError with the
+	// provider of the client, so that the publishing queue can classify them.
+	PostMessage(ctx context.Context, webhookURL string, payload []byte) error
+}
+
+// HTTPChatWebhookClient implements ChatWebhookClient using HTTP, for chats
+// whose webhooks answer 2xx on success and an error status otherwise.
+// The webhook URL carries the credentials, so one client serves every
+// target of a chat.
+type HTTPChatWebhookClient struct {
+	httpClient *http.Client
+	provider   string
+	logger     *slog.Logger
+}
+
+// NewHTTPChatWebhookClient creates a new chat webhook client
+// provider: ProviderGoogleChat or ProviderMattermost
+// timeout: request timeout (<= 0 uses 10s)
+func NewHTTPChatWebhookClient(provider string, timeout time.Duration, logger *slog.Logger) ChatWebhookClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPChatWebhookClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: newOutboundTransport(&http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12, // TLS 1.2+ required
+				},
+				MaxIdleConns:        10,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     30 * time.Second,
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+			}),
+		},
+		provider: provider,
+		logger:   logger.With("component", provider+"_client"),
+	}
+}
+
+// PostMessage posts payload to webhookURL.
+func (c *HTTPChatWebhookClient) PostMessage(ctx context.Context, webhookURL string, payload []byte) error {
+	c.logger.DebugContext(ctx, "Posting message to chat webhook",
+		slog.String("webhook_url", maskWebhookURL(webhookURL)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &httperror.HTTPAPIError{
+		StatusCode: resp.StatusCode,
+		Message:    truncateString(string(body), 512),
+		Provider:   c.provider,
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			apiErr.RetryAfter = seconds
+		}
+	}
+	return apiErr
+}
+
+// publishChatMessage formats enrichedAlert in format, lets decorate adjust
+// the payload (optional), and posts it to webhookURL, recording the metrics
+// and logs of provider.
+func (b *BaseEnhancedPublisher) publishChatMessage(
+	ctx context.Context,
+	client ChatWebhookClient,
+	provider string,
+	format core.PublishingFormat,
+	webhookURL string,
+	enrichedAlert *core.EnrichedAlert,
+	target *core.PublishingTarget,
+	decorate func(payload map[string]any),
+) error {
+	startTime := time.Now()
+	fingerprint := enrichedAlert.Alert.Fingerprint
+	ctx = withTargetSeverityStyles(ctx, target)
+
+	b.LogPublishStart(ctx, provider, enrichedAlert)
+
+	payload, err := b.GetFormatter().FormatAlert(ctx, enrichedAlert, format)
+	if err != nil {
+		if b.GetMetrics() != nil {
+			b.GetMetrics().RecordAPIError(provider, "post_message", "format_error")
+		}
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+	if decorate != nil {
+		decorate(payload)
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if b.GetMetrics() != nil {
+		b.GetMetrics().RecordPayloadSize(provider, len(payloadBytes))
+	}
+
+	err = client.PostMessage(ctx, webhookURL, payloadBytes)
+	duration := time.Since(startTime)
+	if err != nil {
+		if b.GetMetrics() != nil {
+			b.GetMetrics().RecordAPIError(provider, "post_message", GetPublishingErrorType(err))
+			b.GetMetrics().RecordAPIDuration(provider, "post_message", "POST", duration)
+		}
+		b.LogPublishError(ctx, provider, fingerprint, err)
+		return fmt.Errorf("failed to post message to %s: %w", target.Name, err)
+	}
+
+	if b.GetMetrics() != nil {
+		b.GetMetrics().RecordMessage(provider, "success")
+		b.GetMetrics().RecordAPIDuration(provider, "post_message", "POST", duration)
+	}
+	b.LogPublishSuccess(ctx, provider, fingerprint, duration)
+	return nil
+}
@@ -1,6 +1,7 @@
 package publishing
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
@@ -37,10 +38,47 @@ type CircuitBreakerConfig struct {
 	Timeout          time.Duration // Time to wait before trying half-open
 }
 
+// DefaultCircuitBreakerConfig returns the configuration of the circuit
+// breakers of targets without overrides.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: 5,
+		SuccessThreshold: 2,
+		Timeout:          30 * time.Second,
+	}
+}
+
+// Validate checks the configuration values. Zero values are allowed in
+// overrides, where they keep the value overridden.
+func (c CircuitBreakerConfig) Validate() error {
+	if c.FailureThreshold < 0 || c.SuccessThreshold < 0 {
+		return fmt.Errorf("failure and success thresholds must be non-negative")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// override returns c with the non-zero values of o.
+func (c CircuitBreakerConfig) override(o CircuitBreakerConfig) CircuitBreakerConfig {
+	if o.FailureThreshold > 0 {
+		c.FailureThreshold = o.FailureThreshold
+	}
+	if o.SuccessThreshold > 0 {
+		c.SuccessThreshold = o.SuccessThreshold
+	}
+	if o.Timeout > 0 {
+		c.Timeout = o.Timeout
+	}
+	return c
+}
+
 // CircuitBreaker implements circuit breaker pattern per target
 type CircuitBreaker struct {
 	config          CircuitBreakerConfig
 	state           CircuitBreakerState
+	forced          bool // state set manually, kept until Reset
 	failureCount    int
 	successCount    int
 	lastFailureTime time.Time
@@ -48,6 +86,20 @@ type CircuitBreaker struct {
 	mu              sync.RWMutex
 }
 
+// CircuitBreakerStatus is the state of a circuit breaker, for the API.
+type CircuitBreakerStatus struct {
+	Target string `json:"target"`
+	State  string `json:"state"`
+	// Forced is true when the state was set manually.
+	Forced           bool       `json:"forced"`
+	Failures         int        `json:"failures"`
+	Successes        int        `json:"successes"`
+	LastFailure      *time.Time `json:"last_failure,omitempty"`
+	FailureThreshold int        `json:"failure_threshold"`
+	SuccessThreshold int        `json:"success_threshold"`
+	Timeout          string     `json:"timeout"`
+}
+
 // NewCircuitBreaker creates a new circuit breaker
 func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
 	return &CircuitBreaker{
@@ -70,6 +122,10 @@ func (cb *CircuitBreaker) CanAttempt() bool {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
 
+	if cb.forced {
+		return cb.state != StateOpen
+	}
+
 	switch cb.state {
 	case StateClosed:
 		return true
@@ -92,6 +148,11 @@ func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	if cb.forced {
+		cb.failureCount = 0
+		return
+	}
+
 	switch cb.state {
 	case StateClosed:
 		// Reset failure count on success
@@ -121,6 +182,9 @@ func (cb *CircuitBreaker) RecordFailure() {
 
 	cb.failureCount++
 	cb.lastFailureTime = time.Now()
+	if cb.forced {
+		return
+	}
 
 	switch cb.state {
 	case StateClosed:
@@ -142,16 +206,59 @@ func (cb *CircuitBreaker) State() CircuitBreakerState {
 	return cb.state
 }
 
-// Reset resets the circuit breaker to closed state
+// Reset resets the circuit breaker to closed state, releasing a forced
+// state
 func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
 	cb.state = StateClosed
+	cb.forced = false
 	cb.failureCount = 0
 	cb.successCount = 0
 }
 
+// Force holds the circuit breaker in state until Reset, e.g. open to stop
+// publishing to a provider during an incident, or closed to keep
+// publishing to it whatever its failures.
+func (cb *CircuitBreaker) Force(state CircuitBreakerState) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = state
+	cb.forced = true
+	cb.successCount = 0
+}
+
+// setConfig replaces the configuration, keeping the state.
+func (cb *CircuitBreaker) setConfig(config CircuitBreakerConfig) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.config = config
+}
+
+// Status returns the state and configuration of the circuit breaker.
+func (cb *CircuitBreaker) Status() CircuitBreakerStatus {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	status := CircuitBreakerStatus{
+		Target:           cb.targetName,
+		State:            cb.state.String(),
+		Forced:           cb.forced,
+		Failures:         cb.failureCount,
+		Successes:        cb.successCount,
+		FailureThreshold: cb.config.FailureThreshold,
+		SuccessThreshold: cb.config.SuccessThreshold,
+		Timeout:          cb.config.Timeout.String(),
+	}
+	if !cb.lastFailureTime.IsZero() {
+		lastFailure := cb.lastFailureTime.UTC()
+		status.LastFailure = &lastFailure
+	}
+	return status
+}
+
 // GetFailureCount returns current failure count
 func (cb *CircuitBreaker) GetFailureCount() int {
 	cb.mu.RLock()
@@ -117,3 +117,43 @@ func TestCircuitBreaker_Reset(t *testing.T) {
 	assert.Equal(t, 0, cb.GetFailureCount())
 	assert.True(t, cb.CanAttempt())
 }
+
+func TestCircuitBreaker_Force(t *testing.T) {
+	cb := NewCircuitBreakerWithName(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		SuccessThreshold: 1,
+		Timeout:          time.Millisecond,
+	}, "pagerduty")
+
+	// Held open: no half-open probe after the timeout, successes ignored
+	cb.Force(StateOpen)
+	time.Sleep(5 * time.Millisecond)
+	assert.False(t, cb.CanAttempt())
+	cb.RecordSuccess()
+	assert.Equal(t, StateOpen, cb.State())
+
+	// Held closed: failures do not trip it
+	cb.Force(StateClosed)
+	for range 5 {
+		cb.RecordFailure()
+	}
+	assert.True(t, cb.CanAttempt())
+	status := cb.Status()
+	assert.Equal(t, "closed", status.State)
+	assert.True(t, status.Forced)
+	assert.Equal(t, "pagerduty", status.Target)
+	assert.NotNil(t, status.LastFailure)
+
+	cb.Reset()
+	assert.False(t, cb.Status().Forced)
+	cb.RecordFailure()
+	cb.RecordFailure()
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreakerConfig_Validate(t *testing.T) {
+	assert.NoError(t, CircuitBreakerConfig{}.Validate())
+	assert.NoError(t, DefaultCircuitBreakerConfig().Validate())
+	assert.Error(t, CircuitBreakerConfig{FailureThreshold: -1}.Validate())
+	assert.Error(t, CircuitBreakerConfig{Timeout: -time.Second}.Validate())
+}
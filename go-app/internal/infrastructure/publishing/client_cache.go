@@ -0,0 +1,55 @@
+package publishing
+
+import (
+	"sync"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// clientCache holds the API clients of one target type by client key: what
+// sets the clients of two targets apart, such as URL and credentials.
+//
+// The publishing queue creates publishers by target type only, so publishers
+// look up the client of each target at publish time. Clients make a single
+// attempt per request; the queue retries transient failures and applies the
+// circuit breaker of the target.
+type clientCache[C any] struct {
+	key func(target *core.PublishingTarget) string
+
+	mu      sync.Mutex
+	clients map[string]C
+}
+
+func newClientCache[C any](key func(target *core.PublishingTarget) string) *clientCache[C] {
+	return &clientCache[C]{key: key, clients: make(map[string]C)}
+}
+
+// getOrCreate returns the client of target, created with newClient on first
+// use.
+func (c *clientCache[C]) getOrCreate(target *core.PublishingTarget, newClient func() C) C {
+	key := c.key(target)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client, ok := c.clients[key]
+	if !ok {
+		client = newClient()
+		c.clients[key] = client
+	}
+	return client
+}
+
+// retain drops the clients of the keys none of targets has, so that removed
+// targets and rotated credentials do not keep clients alive.
+func (c *clientCache[C]) retain(targets []*core.PublishingTarget) {
+	keep := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		keep[c.key(target)] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.clients {
+		if !keep[key] {
+			delete(c.clients, key)
+		}
+	}
+}
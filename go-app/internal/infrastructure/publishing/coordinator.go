@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/grouping"
 )
 
 // PublishingResult represents the result of publishing to a single target
@@ -20,7 +21,11 @@ type PublishingResult struct {
 type PublishingCoordinator struct {
 	queue            *PublishingQueue
 	discoveryManager TargetDiscoveryManager
-	modeManager      ModeManager // TN-060: Mode manager for metrics-only fallback
+	modeManager      ModeManager             // TN-060: Mode manager for metrics-only fallback
+	grouper          *grouping.TargetGrouper // Targets overriding group_by
+	jiraIssueKeys    JiraIssueKeys           // Issue keys recorded in enrichment metadata (optional)
+	router           *TargetRouter           // Selects the targets of every alert (optional)
+	quorum           *QuorumTracker          // Checks the delivery quorum of firing alerts (optional)
 	semaphore        chan struct{}
 	logger           *slog.Logger
 }
@@ -49,13 +54,18 @@ func NewPublishingCoordinator(
 		logger = slog.Default()
 	}
 
-	return &PublishingCoordinator{
+	c := &PublishingCoordinator{
 		queue:            queue,
 		discoveryManager: discoveryManager,
 		modeManager:      modeManager,
 		semaphore:        make(chan struct{}, config.MaxConcurrent),
 		logger:           logger,
 	}
+	c.grouper = grouping.NewTargetGrouper(grouping.TargetGrouperConfig{
+		Flush:  c.submitGroup,
+		Logger: logger,
+	})
+	return c
 }
 
 // PublishToAll publishes alert to all enabled targets concurrently
@@ -89,11 +99,36 @@ func (c *PublishingCoordinator) PublishToAll(ctx context.Context, enrichedAlert
 		return nil, fmt.Errorf("no enabled publishing targets")
 	}
 
+	if c.router != nil {
+		routed := c.routeTargets(enrichedAlert, enabledTargets)
+		if len(routed.Missing) > 0 {
+			c.logger.Debug("Routed publishing targets not available",
+				"targets", routed.Missing,
+				"fingerprint", enrichedAlert.Alert.Fingerprint,
+			)
+		}
+		if len(routed.Targets) == 0 {
+			c.logger.Info("Alert routed to no publishing target",
+				"routes", routed.Routes,
+				"fingerprint", enrichedAlert.Alert.Fingerprint,
+			)
+			return []*PublishingResult{}, nil
+		}
+		enabledTargets = routed.Targets
+	}
+
 	c.logger.Info("Publishing to multiple targets",
 		"total_targets", len(enabledTargets),
 		"fingerprint", enrichedAlert.Alert.Fingerprint,
 	)
 
+	enrichedAlert = c.linkJiraIssue(enrichedAlert)
+
+	var quorum *quorumDispatch
+	if c.quorum != nil {
+		quorum = c.quorum.begin(enrichedAlert, enabledTargets)
+	}
+
 	// Publish to all targets concurrently
 	results := make([]*PublishingResult, len(enabledTargets))
 	var wg sync.WaitGroup
@@ -120,8 +155,8 @@ func (c *PublishingCoordinator) PublishToAll(ctx context.Context, enrichedAlert
 				return
 			}
 
-			// Submit to queue
-			err := c.queue.Submit(enrichedAlert, t)
+			// Submit to queue (or the target grouping)
+			err := c.submit(enrichedAlert, t)
 
 			mu.Lock()
 			results[idx] = &PublishingResult{
@@ -135,6 +170,9 @@ func (c *PublishingCoordinator) PublishToAll(ctx context.Context, enrichedAlert
 
 	// Wait for all publishing operations to complete
 	wg.Wait()
+	if c.quorum != nil {
+		c.quorum.submitted(quorum, results)
+	}
 
 	// Count successes
 	successCount := 0
@@ -192,6 +230,8 @@ func (c *PublishingCoordinator) PublishToTargets(ctx context.Context, enrichedAl
 		"fingerprint", enrichedAlert.Alert.Fingerprint,
 	)
 
+	enrichedAlert = c.linkJiraIssue(enrichedAlert)
+
 	// Publish concurrently
 	results := make([]*PublishingResult, len(targets))
 	var wg sync.WaitGroup
@@ -218,8 +258,8 @@ func (c *PublishingCoordinator) PublishToTargets(ctx context.Context, enrichedAl
 				return
 			}
 
-			// Submit to queue
-			err := c.queue.Submit(enrichedAlert, t)
+			// Submit to queue (or the target grouping)
+			err := c.submit(enrichedAlert, t)
 
 			mu.Lock()
 			results[idx] = &PublishingResult{
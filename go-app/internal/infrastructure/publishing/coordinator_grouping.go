@@ -0,0 +1,138 @@
+package publishing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/grouping"
+)
+
+// Target headers overriding how the alerts of a target are grouped.
+const (
+	targetGroupByHeader       = "group_by"       // comma-separated labels, "..." for per-alert
+	targetGroupWaitHeader     = "group_wait"     // default 30s
+	targetGroupIntervalHeader = "group_interval" // default 5m
+)
+
+// targetGroupConfig reads the grouping override of target. ok is false
+// when the target has no group_by header: its alerts are submitted as they
+// arrive.
+func targetGroupConfig(target *core.PublishingTarget) (cfg grouping.TargetGroupConfig, ok bool, err error) {
+	raw, ok := target.Headers[targetGroupByHeader]
+	if !ok {
+		return grouping.TargetGroupConfig{}, false, nil
+	}
+
+	cfg = grouping.TargetGroupConfig{
+		GroupBy:       parseGroupBy(raw),
+		GroupWait:     grouping.DefaultTargetGroupWait,
+		GroupInterval: grouping.DefaultTargetGroupInterval,
+	}
+	if cfg.GroupWait, err = targetGroupDuration(target, targetGroupWaitHeader, cfg.GroupWait); err != nil {
+		return grouping.TargetGroupConfig{}, false, err
+	}
+	if cfg.GroupInterval, err = targetGroupDuration(target, targetGroupIntervalHeader, cfg.GroupInterval); err != nil {
+		return grouping.TargetGroupConfig{}, false, err
+	}
+	return cfg, true, nil
+}
+
+// parseGroupBy parses a comma-separated group_by header.
+func parseGroupBy(raw string) []string {
+	groupBy := []string{}
+	for _, label := range strings.Split(raw, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			groupBy = append(groupBy, label)
+		}
+	}
+	return groupBy
+}
+
+func targetGroupDuration(target *core.PublishingTarget, header string, fallback time.Duration) (time.Duration, error) {
+	raw, ok := target.Headers[header]
+	if !ok {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(raw))
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q for target %s", header, raw, target.Name)
+	}
+	return d, nil
+}
+
+// submit hands enrichedAlert to the queue, or to the target grouper when
+// target overrides the grouping. Alerts that cannot be grouped are
+// submitted right away.
+func (c *PublishingCoordinator) submit(enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	cfg, grouped, err := targetGroupConfig(target)
+	if err != nil {
+		c.logger.Warn("Ignoring invalid target grouping", "target", target.Name, "error", err)
+	}
+	if grouped {
+		err := c.grouper.Add(target, cfg, enrichedAlert)
+		if err == nil {
+			return nil
+		}
+		c.logger.Warn("Target grouping failed, submitting alert directly",
+			"target", target.Name,
+			"fingerprint", enrichedAlert.Alert.Fingerprint,
+			"error", err,
+		)
+	}
+	return c.queue.Submit(enrichedAlert, target)
+}
+
+// submitGroup submits the alerts released by the target grouper.
+func (c *PublishingCoordinator) submitGroup(target *core.PublishingTarget, key grouping.GroupKey, alerts []*core.EnrichedAlert) {
+	for _, alert := range alerts {
+		if err := c.queue.Submit(alert, target); err != nil {
+			c.logger.Warn("Publishing enqueue failed",
+				"target", target.Name,
+				"group_key", key,
+				"fingerprint", alert.Alert.Fingerprint,
+				"error", err,
+			)
+		}
+	}
+}
+
+// ReleaseTargetGroups releases the alerts held by target groupings to the
+// queue without waiting for their group_wait, before the queue drains.
+// Alerts of grouped targets are submitted right away afterwards.
+func (c *PublishingCoordinator) ReleaseTargetGroups() {
+	c.grouper.Stop()
+}
+
+// ForgetTarget releases the alerts held for a target removed from
+// discovery and drops its groups.
+func (c *PublishingCoordinator) ForgetTarget(name string) {
+	c.grouper.ForgetTarget(name)
+}
+
+// TargetGroups returns the state of the target groups, for checkpoints.
+func (c *PublishingCoordinator) TargetGroups() []grouping.TargetGroupState {
+	return c.grouper.Snapshot()
+}
+
+// RestoreTargetGroups recreates the target groups of a checkpoint taken by
+// a previous run. Groups of targets no longer discovered are dropped.
+func (c *PublishingCoordinator) RestoreTargetGroups(states []grouping.TargetGroupState) grouping.TargetGroupRestore {
+	return c.grouper.Restore(states, func(name string) *core.PublishingTarget {
+		target, err := c.discoveryManager.GetTarget(name)
+		if err != nil {
+			return nil
+		}
+		return target
+	})
+}
+
+// Stop releases the alerts held by target groupings to the queue, and
+// drops the alerts awaiting their quorum. Call it before stopping the queue.
+func (c *PublishingCoordinator) Stop() {
+	c.grouper.Stop()
+	if c.quorum != nil {
+		c.quorum.stop()
+	}
+}
@@ -0,0 +1,65 @@
+package publishing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/grouping"
+)
+
+func TestTargetGroupConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    grouping.TargetGroupConfig
+		grouped bool
+		wantErr bool
+	}{
+		{
+			name:    "no override",
+			headers: map[string]string{"Authorization": "Bearer x"},
+		},
+		{
+			name:    "per service with defaults",
+			headers: map[string]string{"group_by": "service, namespace"},
+			want: grouping.TargetGroupConfig{
+				GroupBy:       []string{"service", "namespace"},
+				GroupWait:     grouping.DefaultTargetGroupWait,
+				GroupInterval: grouping.DefaultTargetGroupInterval,
+			},
+			grouped: true,
+		},
+		{
+			name:    "per alert without delay",
+			headers: map[string]string{"group_by": "...", "group_wait": "0s", "group_interval": "1m"},
+			want: grouping.TargetGroupConfig{
+				GroupBy:       []string{"..."},
+				GroupInterval: time.Minute,
+			},
+			grouped: true,
+		},
+		{
+			name:    "invalid group_wait",
+			headers: map[string]string{"group_by": "service", "group_wait": "later"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, grouped, err := targetGroupConfig(&core.PublishingTarget{Name: "t", Headers: tt.headers})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.grouped, grouped)
+			if tt.grouped {
+				assert.Equal(t, tt.want, cfg)
+			}
+		})
+	}
+}
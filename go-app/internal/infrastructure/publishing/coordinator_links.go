@@ -0,0 +1,40 @@
+package publishing
+
+import (
+	"maps"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// JiraIssueKeys looks up the JIRA issue opened for an alert (implemented by
+// PublisherFactory).
+type JiraIssueKeys interface {
+	JiraIssueKey(fingerprint string) (string, bool)
+}
+
+// SetJiraIssueKeys makes the coordinator record the JIRA issue key of
+// alerts in their enrichment metadata (MetadataJiraIssueKey) before
+// publishing, so that other publishers can link to the issue. Call it
+// before publishing starts.
+func (c *PublishingCoordinator) SetJiraIssueKeys(keys JiraIssueKeys) {
+	c.jiraIssueKeys = keys
+}
+
+// linkJiraIssue returns enrichedAlert with the key of its JIRA issue in
+// the enrichment metadata. The alert is copied rather than modified, since
+// it is shared with the caller; alerts without an issue are returned as is.
+func (c *PublishingCoordinator) linkJiraIssue(enrichedAlert *core.EnrichedAlert) *core.EnrichedAlert {
+	if c.jiraIssueKeys == nil {
+		return enrichedAlert
+	}
+	key, ok := c.jiraIssueKeys.JiraIssueKey(enrichedAlert.Alert.Fingerprint)
+	if !ok || enrichedAlert.EnrichmentMetadata[MetadataJiraIssueKey] == key {
+		return enrichedAlert
+	}
+
+	linked := *enrichedAlert
+	linked.EnrichmentMetadata = make(map[string]any, len(enrichedAlert.EnrichmentMetadata)+1)
+	maps.Copy(linked.EnrichmentMetadata, enrichedAlert.EnrichmentMetadata)
+	linked.EnrichmentMetadata[MetadataJiraIssueKey] = key
+	return &linked
+}
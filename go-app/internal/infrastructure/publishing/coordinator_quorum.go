@@ -0,0 +1,349 @@
+package publishing
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// DefaultQuorumTimeout bounds the wait for the outcomes of the targets of a
+// quorum.
+const DefaultQuorumTimeout = 5 * time.Minute
+
+// Quorum results, the result label of alert_history_publishing_quorum_total.
+const (
+	QuorumMet     = "met"     // MinSuccess targets delivered the alert
+	QuorumNotMet  = "not_met" // too many targets failed to still reach MinSuccess
+	QuorumTimeout = "timeout" // the targets had not delivered in time
+)
+
+// QuorumPolicy requires the firing alerts of a class to be delivered by at
+// least MinSuccess of Targets, e.g. a page must reach PagerDuty or
+// Opsgenie, and escalates them to the Fallback targets otherwise.
+type QuorumPolicy struct {
+	Name string
+
+	// Match and MatchRE select the alerts of the policy by their labels,
+	// as they select the alerts of a route (see TargetRoute).
+	Match   map[string]string
+	MatchRE map[string]string
+
+	Targets    []string
+	MinSuccess int
+	Fallback   []string
+}
+
+// QuorumConfig configures a QuorumTracker.
+type QuorumConfig struct {
+	// Policies are tried in order; the first matching policy applies.
+	Policies []QuorumPolicy
+
+	// Timeout bounds the wait for the outcomes of the targets (default:
+	// DefaultQuorumTimeout). Targets without outcome by then count as
+	// failed.
+	Timeout time.Duration
+
+	Metrics *v2.PublishingMetrics // optional
+	Logger  *slog.Logger
+}
+
+// QuorumTracker checks that firing alerts are delivered by the quorum of
+// their policy. The queue reports the final outcome of every job to it (see
+// PublishingQueueConfig.Outcomes); the coordinator registers the alerts it
+// publishes (see PublishingCoordinator.SetQuorumTracker).
+//
+// The quorum of an alert is evaluated as soon as it is met or can no longer
+// be met, or after the timeout. Targets the alert was not submitted to
+// (disabled, routed away, failed submission) count as failed. While an
+// alert awaits its outcomes, publishing it again does not start another
+// evaluation.
+type QuorumTracker struct {
+	policies []*compiledQuorumPolicy
+	timeout  time.Duration
+	metrics  *v2.PublishingMetrics
+	logger   *slog.Logger
+
+	// Set by the coordinator
+	targets TargetDiscoveryManager
+	submit  func(alert *core.EnrichedAlert, target *core.PublishingTarget) error
+
+	mu      sync.Mutex
+	pending map[string]*quorumDispatch // by fingerprint
+}
+
+var _ JobOutcomeRecorder = (*QuorumTracker)(nil)
+
+type compiledQuorumPolicy struct {
+	labelMatchers
+	QuorumPolicy
+}
+
+type quorumOutcome int
+
+const (
+	quorumPending quorumOutcome = iota
+	quorumDelivered
+	quorumFailed
+)
+
+// quorumDispatch is an alert awaiting the outcomes of its quorum.
+type quorumDispatch struct {
+	alert      *core.EnrichedAlert
+	policy     *compiledQuorumPolicy
+	outcomes   map[string]quorumOutcome // by policy target
+	dispatched []string                 // all targets the alert was submitted to
+	submitted  bool                     // all submissions returned
+	timer      *time.Timer
+}
+
+// NewQuorumTracker validates and compiles the policies of config.
+func NewQuorumTracker(config QuorumConfig) (*QuorumTracker, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultQuorumTimeout
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	t := &QuorumTracker{
+		timeout: config.Timeout,
+		metrics: config.Metrics,
+		logger:  config.Logger,
+		pending: make(map[string]*quorumDispatch),
+	}
+	for i, policy := range config.Policies {
+		path := fmt.Sprintf("policies[%d]", i)
+		if policy.Name == "" {
+			return nil, fmt.Errorf("%s.name is required", path)
+		}
+		if len(policy.Targets) == 0 {
+			return nil, fmt.Errorf("%s.targets is required", path)
+		}
+		if policy.MinSuccess < 1 || policy.MinSuccess > len(policy.Targets) {
+			return nil, fmt.Errorf("%s.min_success must be between 1 and the number of targets", path)
+		}
+		matchers, err := compileLabelMatchers(policy.Match, policy.MatchRE, path)
+		if err != nil {
+			return nil, err
+		}
+		t.policies = append(t.policies, &compiledQuorumPolicy{labelMatchers: matchers, QuorumPolicy: policy})
+	}
+	return t, nil
+}
+
+// SetQuorumTracker makes the coordinator register the firing alerts it
+// publishes to all targets with tracker, and escalate those missing their
+// quorum to the fallback targets. tracker must be the Outcomes of the queue.
+// Call it before publishing starts.
+func (c *PublishingCoordinator) SetQuorumTracker(tracker *QuorumTracker) {
+	tracker.targets = c.discoveryManager
+	tracker.submit = c.submit
+	c.quorum = tracker
+}
+
+// begin registers alert before it is submitted to targets, so that no
+// outcome is missed. It returns nil when the alert has no policy, is not
+// firing, or already awaits its outcomes.
+func (t *QuorumTracker) begin(alert *core.EnrichedAlert, targets []*core.PublishingTarget) *quorumDispatch {
+	if alert.Alert.Status != core.StatusFiring {
+		return nil
+	}
+	labels := routingLabels(alert)
+	index := slices.IndexFunc(t.policies, func(policy *compiledQuorumPolicy) bool { return policy.matches(labels) })
+	if index < 0 {
+		return nil
+	}
+
+	policy := t.policies[index]
+	d := &quorumDispatch{
+		alert:    alert,
+		policy:   policy,
+		outcomes: make(map[string]quorumOutcome, len(policy.Targets)),
+	}
+	for _, target := range targets {
+		d.dispatched = append(d.dispatched, target.Name)
+	}
+	for _, name := range policy.Targets {
+		if slices.Contains(d.dispatched, name) {
+			d.outcomes[name] = quorumPending
+		} else {
+			d.outcomes[name] = quorumFailed
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.pending[alert.Alert.Fingerprint]; ok {
+		return nil
+	}
+	t.pending[alert.Alert.Fingerprint] = d
+	return d
+}
+
+// submitted records the targets d failed to be submitted to and starts the
+// evaluation of its quorum.
+func (t *QuorumTracker) submitted(d *quorumDispatch, results []*PublishingResult) {
+	if d == nil {
+		return
+	}
+
+	t.mu.Lock()
+	for _, result := range results {
+		if !result.Success && d.outcomes[result.Target.Name] == quorumPending {
+			d.outcomes[result.Target.Name] = quorumFailed
+		}
+	}
+	d.submitted = true
+	result, done := d.evaluate()
+	if done {
+		delete(t.pending, d.alert.Alert.Fingerprint)
+	} else {
+		fingerprint := d.alert.Alert.Fingerprint
+		d.timer = time.AfterFunc(t.timeout, func() { t.expire(fingerprint, d) })
+	}
+	t.mu.Unlock()
+
+	if done {
+		t.finish(d, result)
+	}
+}
+
+// RecordJobOutcome implements JobOutcomeRecorder.
+func (t *QuorumTracker) RecordJobOutcome(fingerprint, target string, delivered bool) {
+	t.mu.Lock()
+	d := t.pending[fingerprint]
+	if d == nil {
+		t.mu.Unlock()
+		return
+	}
+	if outcome, ok := d.outcomes[target]; !ok || outcome != quorumPending {
+		t.mu.Unlock()
+		return
+	}
+	if delivered {
+		d.outcomes[target] = quorumDelivered
+	} else {
+		d.outcomes[target] = quorumFailed
+	}
+	if !d.submitted {
+		t.mu.Unlock()
+		return
+	}
+	result, done := d.evaluate()
+	if done {
+		delete(t.pending, fingerprint)
+		d.timer.Stop()
+	}
+	t.mu.Unlock()
+
+	if done {
+		t.finish(d, result)
+	}
+}
+
+// expire evaluates the quorum of d once its timeout elapsed.
+func (t *QuorumTracker) expire(fingerprint string, d *quorumDispatch) {
+	t.mu.Lock()
+	if t.pending[fingerprint] != d {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.pending, fingerprint)
+	result := QuorumTimeout
+	if d.delivered() >= d.policy.MinSuccess {
+		result = QuorumMet
+	}
+	t.mu.Unlock()
+
+	t.finish(d, result)
+}
+
+// stop drops the alerts awaiting their outcomes.
+func (t *QuorumTracker) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for fingerprint, d := range t.pending {
+		if d.timer != nil {
+			d.timer.Stop()
+		}
+		delete(t.pending, fingerprint)
+	}
+}
+
+// evaluate returns the result of the quorum of d, unless it still depends on
+// pending outcomes.
+func (d *quorumDispatch) evaluate() (string, bool) {
+	delivered := d.delivered()
+	pending := 0
+	for _, outcome := range d.outcomes {
+		if outcome == quorumPending {
+			pending++
+		}
+	}
+	switch {
+	case delivered >= d.policy.MinSuccess:
+		return QuorumMet, true
+	case delivered+pending < d.policy.MinSuccess:
+		return QuorumNotMet, true
+	default:
+		return "", false
+	}
+}
+
+func (d *quorumDispatch) delivered() int {
+	delivered := 0
+	for _, outcome := range d.outcomes {
+		if outcome == quorumDelivered {
+			delivered++
+		}
+	}
+	return delivered
+}
+
+// finish records the result of the quorum of d and escalates its alert to
+// the fallback targets when the quorum was missed.
+func (t *QuorumTracker) finish(d *quorumDispatch, result string) {
+	policy := d.policy.Name
+	if t.metrics != nil {
+		t.metrics.RecordQuorum(policy, result)
+	}
+	if result == QuorumMet {
+		return
+	}
+
+	t.logger.Warn("Publishing quorum not met, escalating to fallback targets",
+		"policy", policy,
+		"result", result,
+		"delivered", d.delivered(),
+		"min_success", d.policy.MinSuccess,
+		"fallback", d.policy.Fallback,
+		"fingerprint", d.alert.Alert.Fingerprint,
+	)
+	for _, name := range d.policy.Fallback {
+		if slices.Contains(d.dispatched, name) {
+			continue // already published to
+		}
+		target, err := t.targets.GetTarget(name)
+		if err != nil || !target.Enabled {
+			t.logger.Warn("Quorum fallback target not available", "policy", policy, "target", name)
+			continue
+		}
+		if err := t.submit(d.alert, target); err != nil {
+			t.logger.Warn("Quorum fallback failed",
+				"policy", policy,
+				"target", name,
+				"fingerprint", d.alert.Alert.Fingerprint,
+				"error", err,
+			)
+			continue
+		}
+		if t.metrics != nil {
+			t.metrics.RecordQuorumFallback(policy, name)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package publishing
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// newTestQuorumTracker returns a tracker paging critical alerts to
+// pagerduty or opsgenie, with slack as fallback, and the targets its
+// fallbacks were submitted to.
+func newTestQuorumTracker(t *testing.T, timeout time.Duration) (*QuorumTracker, *prometheus.Registry, func() []string) {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	tracker, err := NewQuorumTracker(QuorumConfig{
+		Policies: []QuorumPolicy{{
+			Name:       "page",
+			Match:      map[string]string{"severity": "critical"},
+			Targets:    []string{"pagerduty", "opsgenie"},
+			MinSuccess: 1,
+			Fallback:   []string{"slack", "pagerduty"},
+		}},
+		Timeout: timeout,
+		Metrics: v2.NewPublishingMetrics(registry),
+	})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var fallbacks []string
+	tracker.targets = &mockTargetDiscoveryManager{targets: []*core.PublishingTarget{
+		{Name: "pagerduty", Enabled: true},
+		{Name: "opsgenie", Enabled: true},
+		{Name: "slack", Enabled: true},
+	}}
+	tracker.submit = func(_ *core.EnrichedAlert, target *core.PublishingTarget) error {
+		mu.Lock()
+		defer mu.Unlock()
+		fallbacks = append(fallbacks, target.Name)
+		return nil
+	}
+	return tracker, registry, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), fallbacks...)
+	}
+}
+
+func quorumAlert(fingerprint, severity string, status core.AlertStatus) *core.EnrichedAlert {
+	return &core.EnrichedAlert{Alert: &core.Alert{
+		Fingerprint: fingerprint,
+		Status:      status,
+		Labels:      map[string]string{"severity": severity},
+	}}
+}
+
+// dispatch registers alert as submitted to targets, failing the submissions
+// to failed.
+func dispatch(tracker *QuorumTracker, alert *core.EnrichedAlert, targets []string, failed ...string) {
+	var publishing []*core.PublishingTarget
+	var results []*PublishingResult
+	for _, name := range targets {
+		target := &core.PublishingTarget{Name: name, Enabled: true}
+		publishing = append(publishing, target)
+		results = append(results, &PublishingResult{Target: target, Success: !contains(failed, name)})
+	}
+	d := tracker.begin(alert, publishing)
+	tracker.submitted(d, results)
+}
+
+// quorumResults returns the quorum evaluations recorded in registry by
+// result.
+func quorumResults(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	results := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "alert_history_publishing_quorum_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" {
+					results[label.GetValue()] += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return results
+}
+
+func TestQuorumTracker_Met(t *testing.T) {
+	tracker, registry, fallbacks := newTestQuorumTracker(t, time.Minute)
+
+	dispatch(tracker, quorumAlert("fp-1", "critical", core.StatusFiring), []string{"pagerduty", "opsgenie", "chat"})
+	tracker.RecordJobOutcome("fp-1", "chat", false) // not counted
+	tracker.RecordJobOutcome("fp-1", "pagerduty", false)
+	assert.Len(t, tracker.pending, 1, "opsgenie can still deliver")
+	tracker.RecordJobOutcome("fp-1", "opsgenie", true)
+
+	assert.Empty(t, tracker.pending)
+	assert.Empty(t, fallbacks())
+	assert.Equal(t, map[string]float64{QuorumMet: 1}, quorumResults(t, registry))
+}
+
+func TestQuorumTracker_NotMet(t *testing.T) {
+	tracker, registry, fallbacks := newTestQuorumTracker(t, time.Minute)
+
+	// Routed away from the targets of the policy
+	dispatch(tracker, quorumAlert("fp-1", "critical", core.StatusFiring), []string{"chat"})
+
+	assert.Empty(t, tracker.pending)
+	assert.Equal(t, []string{"slack", "pagerduty"}, fallbacks())
+	assert.Equal(t, map[string]float64{QuorumNotMet: 1}, quorumResults(t, registry))
+	assert.Equal(t, 2, testutil.CollectAndCount(registry, "alert_history_publishing_quorum_fallbacks_total"))
+
+	// opsgenie was not submitted, pagerduty failed its submission; the
+	// fallbacks the alert was already submitted to are skipped
+	dispatch(tracker, quorumAlert("fp-2", "critical", core.StatusFiring), []string{"pagerduty", "chat"}, "pagerduty")
+	assert.Equal(t, []string{"slack", "pagerduty", "slack"}, fallbacks())
+
+	dispatch(tracker, quorumAlert("fp-3", "critical", core.StatusFiring), []string{"pagerduty", "slack"})
+	tracker.RecordJobOutcome("fp-3", "pagerduty", false)
+	assert.Equal(t, []string{"slack", "pagerduty", "slack"}, fallbacks())
+	assert.Equal(t, map[string]float64{QuorumNotMet: 3}, quorumResults(t, registry))
+}
+
+func TestQuorumTracker_OutcomeBeforeSubmitted(t *testing.T) {
+	tracker, _, fallbacks := newTestQuorumTracker(t, time.Minute)
+	alert := quorumAlert("fp-1", "critical", core.StatusFiring)
+	targets := []*core.PublishingTarget{{Name: "pagerduty", Enabled: true}}
+
+	// Deduplicated jobs report their outcome during the submission
+	d := tracker.begin(alert, targets)
+	require.NotNil(t, d)
+	tracker.RecordJobOutcome("fp-1", "pagerduty", true)
+	assert.Nil(t, tracker.begin(alert, targets), "the alert already awaits its outcomes")
+	tracker.submitted(d, []*PublishingResult{{Target: targets[0], Success: true}})
+
+	assert.Empty(t, tracker.pending)
+	assert.Empty(t, fallbacks())
+}
+
+func TestQuorumTracker_Timeout(t *testing.T) {
+	tracker, registry, fallbacks := newTestQuorumTracker(t, 10*time.Millisecond)
+
+	dispatch(tracker, quorumAlert("fp-1", "critical", core.StatusFiring), []string{"pagerduty", "opsgenie"})
+	require.Eventually(t, func() bool { return len(fallbacks()) == 1 }, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"slack"}, fallbacks(), "pagerduty was already published to")
+
+	tracker.RecordJobOutcome("fp-1", "pagerduty", true) // late, ignored
+	assert.Equal(t, map[string]float64{QuorumTimeout: 1}, quorumResults(t, registry))
+}
+
+func TestQuorumTracker_Untracked(t *testing.T) {
+	tracker, _, _ := newTestQuorumTracker(t, time.Minute)
+	targets := []*core.PublishingTarget{{Name: "pagerduty", Enabled: true}}
+
+	assert.Nil(t, tracker.begin(quorumAlert("fp-1", "warning", core.StatusFiring), targets), "no matching policy")
+	assert.Nil(t, tracker.begin(quorumAlert("fp-1", "critical", core.StatusResolved), targets), "resolved alert")
+}
+
+func TestNewQuorumTracker_Invalid(t *testing.T) {
+	tests := map[string]QuorumPolicy{
+		"no name":             {Targets: []string{"a"}, MinSuccess: 1},
+		"no targets":          {Name: "p", MinSuccess: 1},
+		"min_success too big": {Name: "p", Targets: []string{"a"}, MinSuccess: 2},
+		"invalid match_re":    {Name: "p", Targets: []string{"a"}, MinSuccess: 1, MatchRE: map[string]string{"team": "("}},
+	}
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewQuorumTracker(QuorumConfig{Policies: []QuorumPolicy{policy}})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// Reasons of publish attempts missing from the delivery audit log, the
+// reason label of alert_history_publishing_delivery_audit_dropped_total.
+const (
+	DeliveryAuditDroppedBufferFull = "buffer_full" // the write buffer was full
+	DeliveryAuditDroppedStoreError = "store_error" // the store rejected the write
+)
+
+const (
+	defaultDeliveryAuditBufferSize = 10000
+	defaultDeliveryAuditRetention  = 30 * 24 * time.Hour
+	deliveryAuditBatchSize         = 100
+	deliveryAuditFlushInterval     = time.Second
+	deliveryAuditPurgeInterval     = time.Hour
+	deliveryAuditWriteTimeout      = 10 * time.Second
+)
+
+// DeliveryAuditRecord is a publish attempt of the alert Fingerprint.
+type DeliveryAuditRecord struct {
+	Fingerprint string
+	core.DeliveryAttempt
+}
+
+// DeliveryAuditStore persists publish attempts.
+type DeliveryAuditStore interface {
+	// AppendDeliveries stores records.
+	AppendDeliveries(ctx context.Context, records []DeliveryAuditRecord) error
+	// ListDeliveries returns the latest limit attempts of the alert
+	// fingerprint, newest first.
+	ListDeliveries(ctx context.Context, fingerprint string, limit int) ([]core.DeliveryAttempt, error)
+	// PurgeDeliveries deletes the attempts made before before and returns
+	// how many were deleted.
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DeliveryAuditConfig configures a DeliveryAuditLog.
+type DeliveryAuditConfig struct {
+	// Store persists the attempts (required).
+	Store DeliveryAuditStore
+
+	// Retention is how long attempts are kept (default: 720h).
+	Retention time.Duration
+
+	// BufferSize bounds the attempts waiting to be written (default:
+	// 10000). Attempts recorded while it is full are dropped and counted.
+	BufferSize int
+
+	Metrics *v2.PublishingMetrics
+	Logger  *slog.Logger
+}
+
+// DeliveryAuditLog is a DeliveryRecorder persisting every publish attempt:
+// job ID, target, provider status code, latency and payload hash. Unlike
+// the in-memory delivery log, it survives restarts and is shared by the
+// replicas, so the delivery of an alert (or why it was not delivered) can be
+// proven afterwards.
+//
+// Attempts are written asynchronously in batches so that publishing never
+// waits on the store; attempts made before the retention are purged hourly.
+type DeliveryAuditLog struct {
+	store     DeliveryAuditStore
+	retention time.Duration
+	records   chan DeliveryAuditRecord
+	metrics   *v2.PublishingMetrics
+	logger    *slog.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewDeliveryAuditLog creates a delivery audit log. Call Start to write
+// attempts.
+func NewDeliveryAuditLog(cfg DeliveryAuditConfig) (*DeliveryAuditLog, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("delivery audit log requires a store")
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultDeliveryAuditRetention
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultDeliveryAuditBufferSize
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &DeliveryAuditLog{
+		store:     cfg.Store,
+		retention: cfg.Retention,
+		records:   make(chan DeliveryAuditRecord, cfg.BufferSize),
+		metrics:   cfg.Metrics,
+		logger:    cfg.Logger,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// RecordDelivery queues attempt for writing. It never blocks: the attempt
+// is dropped when the buffer is full.
+func (l *DeliveryAuditLog) RecordDelivery(fingerprint string, attempt core.DeliveryAttempt) {
+	if fingerprint == "" {
+		return
+	}
+	select {
+	case l.records <- DeliveryAuditRecord{Fingerprint: fingerprint, DeliveryAttempt: attempt}:
+	default:
+		l.dropped(DeliveryAuditDroppedBufferFull, 1)
+	}
+}
+
+// ListDeliveries returns the latest limit attempts of the alert fingerprint,
+// newest first. Attempts still buffered are not listed.
+func (l *DeliveryAuditLog) ListDeliveries(ctx context.Context, fingerprint string, limit int) ([]core.DeliveryAttempt, error) {
+	return l.store.ListDeliveries(ctx, fingerprint, limit)
+}
+
+// Start writes the queued attempts in the background.
+func (l *DeliveryAuditLog) Start() {
+	go l.run()
+}
+
+// Stop writes the attempts still queued and stops, waiting at most timeout.
+func (l *DeliveryAuditLog) Stop(timeout time.Duration) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	select {
+	case <-l.done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("delivery audit log did not stop within %s", timeout)
+	}
+}
+
+func (l *DeliveryAuditLog) run() {
+	defer close(l.done)
+
+	flush := time.NewTicker(deliveryAuditFlushInterval)
+	defer flush.Stop()
+	purge := time.NewTicker(deliveryAuditPurgeInterval)
+	defer purge.Stop()
+
+	l.purge()
+	batch := make([]DeliveryAuditRecord, 0, deliveryAuditBatchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+		l.write(batch)
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case record := <-l.records:
+			batch = append(batch, record)
+			if len(batch) >= deliveryAuditBatchSize {
+				write()
+			}
+		case <-flush.C:
+			write()
+		case <-purge.C:
+			l.purge()
+		case <-l.stop:
+			for {
+				select {
+				case record := <-l.records:
+					batch = append(batch, record)
+					if len(batch) >= deliveryAuditBatchSize {
+						write()
+					}
+				default:
+					write()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *DeliveryAuditLog) write(batch []DeliveryAuditRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryAuditWriteTimeout)
+	defer cancel()
+	if err := l.store.AppendDeliveries(ctx, batch); err != nil {
+		l.logger.Error("Failed to write delivery audit records", "records", len(batch), "error", err)
+		l.dropped(DeliveryAuditDroppedStoreError, len(batch))
+	}
+}
+
+func (l *DeliveryAuditLog) purge() {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryAuditWriteTimeout)
+	defer cancel()
+	purged, err := l.store.PurgeDeliveries(ctx, time.Now().Add(-l.retention))
+	if err != nil {
+		l.logger.Warn("Failed to purge delivery audit records", "error", err)
+		return
+	}
+	if purged > 0 {
+		l.logger.Info("Purged delivery audit records", "records", purged, "retention", l.retention)
+	}
+}
+
+func (l *DeliveryAuditLog) dropped(reason string, count int) {
+	if l.metrics != nil {
+		l.metrics.RecordDeliveryAuditDropped(reason, count)
+	}
+}
+
+// DeliveryRecorders records publish attempts with each of its recorders.
+type DeliveryRecorders []DeliveryRecorder
+
+// RecordDelivery records attempt with each recorder.
+func (r DeliveryRecorders) RecordDelivery(fingerprint string, attempt core.DeliveryAttempt) {
+	for _, recorder := range r {
+		recorder.RecordDelivery(fingerprint, attempt)
+	}
+}
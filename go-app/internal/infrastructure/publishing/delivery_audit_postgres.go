@@ -0,0 +1,91 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// PostgreSQLDeliveryAuditStore stores publish attempts in the
+// publishing_deliveries table.
+type PostgreSQLDeliveryAuditStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgreSQLDeliveryAuditStore creates a delivery audit store on db.
+func NewPostgreSQLDeliveryAuditStore(db *pgxpool.Pool) *PostgreSQLDeliveryAuditStore {
+	return &PostgreSQLDeliveryAuditStore{db: db}
+}
+
+// AppendDeliveries stores records in one batch.
+func (s *PostgreSQLDeliveryAuditStore) AppendDeliveries(ctx context.Context, records []DeliveryAuditRecord) error {
+	query := `
+		INSERT INTO publishing_deliveries (
+			fingerprint, job_id, target_name, target_type, alert_status, attempt, status,
+			status_code, error_type, error, payload_hash, latency_ms, attempted_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	batch := &pgx.Batch{}
+	for _, r := range records {
+		batch.Queue(query,
+			r.Fingerprint, r.JobID, r.Target, r.TargetType, string(r.AlertStatus), r.Attempt, string(r.Status),
+			r.StatusCode, r.ErrorType, r.Error, r.PayloadHash, r.LatencyMs, r.AttemptedAt,
+		)
+	}
+	if err := s.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to store publish attempts: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the latest limit attempts of the alert fingerprint,
+// newest first.
+func (s *PostgreSQLDeliveryAuditStore) ListDeliveries(ctx context.Context, fingerprint string, limit int) ([]core.DeliveryAttempt, error) {
+	query := `
+		SELECT job_id, target_name, target_type, alert_status, attempt, status,
+			status_code, error_type, error, payload_hash, latency_ms, attempted_at
+		FROM publishing_deliveries
+		WHERE fingerprint = $1
+		ORDER BY attempted_at DESC, id DESC
+		LIMIT $2
+	`
+	rows, err := s.db.Query(ctx, query, fingerprint, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list publish attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []core.DeliveryAttempt
+	for rows.Next() {
+		var a core.DeliveryAttempt
+		var alertStatus, status string
+		if err := rows.Scan(
+			&a.JobID, &a.Target, &a.TargetType, &alertStatus, &a.Attempt, &status,
+			&a.StatusCode, &a.ErrorType, &a.Error, &a.PayloadHash, &a.LatencyMs, &a.AttemptedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan publish attempt: %w", err)
+		}
+		a.AlertStatus = core.AlertStatus(alertStatus)
+		a.Status = core.DeliveryStatus(status)
+		a.AttemptedAt = a.AttemptedAt.UTC()
+		attempts = append(attempts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read publish attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// PurgeDeliveries deletes the attempts made before before.
+func (s *PostgreSQLDeliveryAuditStore) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM publishing_deliveries WHERE attempted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge publish attempts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,121 @@
+package publishing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+type fakeDeliveryAuditStore struct {
+	mu      sync.Mutex
+	records []DeliveryAuditRecord
+	purged  time.Time
+	err     error
+}
+
+func (s *fakeDeliveryAuditStore) AppendDeliveries(_ context.Context, records []DeliveryAuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *fakeDeliveryAuditStore) ListDeliveries(_ context.Context, fingerprint string, limit int) ([]core.DeliveryAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var attempts []core.DeliveryAttempt
+	for i := len(s.records) - 1; i >= 0 && len(attempts) < limit; i-- {
+		if s.records[i].Fingerprint == fingerprint {
+			attempts = append(attempts, s.records[i].DeliveryAttempt)
+		}
+	}
+	return attempts, nil
+}
+
+func (s *fakeDeliveryAuditStore) PurgeDeliveries(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purged = before
+	return 0, nil
+}
+
+func TestDeliveryAuditLog(t *testing.T) {
+	store := &fakeDeliveryAuditStore{}
+	audit, err := NewDeliveryAuditLog(DeliveryAuditConfig{Store: store, Retention: time.Hour})
+	require.NoError(t, err)
+	audit.Start()
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		audit.RecordDelivery("fp1", core.DeliveryAttempt{JobID: "job-1", Target: "pagerduty", Attempt: attempt})
+	}
+	audit.RecordDelivery("fp2", core.DeliveryAttempt{JobID: "job-2", Target: "slack", Attempt: 1})
+	require.NoError(t, audit.Stop(time.Second))
+
+	// Attempts still buffered are written on stop
+	attempts, err := audit.ListDeliveries(context.Background(), "fp1", 2)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, 3, attempts[0].Attempt)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), store.purged, time.Minute)
+}
+
+func TestDeliveryAuditLog_Dropped(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	store := &fakeDeliveryAuditStore{err: errors.New("database unavailable")}
+	audit, err := NewDeliveryAuditLog(DeliveryAuditConfig{Store: store, BufferSize: 2, Metrics: v2.NewPublishingMetrics(registry)})
+	require.NoError(t, err)
+
+	// Not started: the third attempt overflows the buffer
+	for attempt := 1; attempt <= 3; attempt++ {
+		audit.RecordDelivery("fp1", core.DeliveryAttempt{Attempt: attempt})
+	}
+	audit.Start()
+	require.NoError(t, audit.Stop(time.Second))
+
+	expected := `
+# HELP alert_history_publishing_delivery_audit_dropped_total Publish attempts missing from the delivery audit log by reason (buffer_full/store_error)
+# TYPE alert_history_publishing_delivery_audit_dropped_total counter
+alert_history_publishing_delivery_audit_dropped_total{reason="buffer_full"} 1
+alert_history_publishing_delivery_audit_dropped_total{reason="store_error"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "alert_history_publishing_delivery_audit_dropped_total"))
+}
+
+func TestOutboundTransport_DeliveryReceipt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	payload := `{"alert":"HighCPU"}`
+	receipt := &deliveryReceipt{}
+	req, err := http.NewRequestWithContext(withDeliveryReceipt(context.Background(), receipt), http.MethodPost, server.URL, strings.NewReader(payload))
+	require.NoError(t, err)
+	client := &http.Client{Timeout: 5 * time.Second, Transport: newOutboundTransport(nil)}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	sum := sha256.Sum256([]byte(payload))
+	statusCode, payloadHash := receipt.get()
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), payloadHash)
+}
@@ -0,0 +1,70 @@
+package publishing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type deliveryReceiptKey struct{}
+
+// deliveryReceipt keeps the status code of the last response of the
+// outbound transport to the requests of a publish attempt, and the hash of
+// the body of the last request: the receipt of the delivery recorded in the
+// audit log.
+type deliveryReceipt struct {
+	mu          sync.Mutex
+	statusCode  int
+	payloadHash string
+}
+
+func withDeliveryReceipt(ctx context.Context, receipt *deliveryReceipt) context.Context {
+	return context.WithValue(ctx, deliveryReceiptKey{}, receipt)
+}
+
+func deliveryReceiptFromContext(ctx context.Context) *deliveryReceipt {
+	receipt, _ := ctx.Value(deliveryReceiptKey{}).(*deliveryReceipt)
+	return receipt
+}
+
+// recordRequest hashes the body of req, read from a copy (GetBody) so that
+// the request is sent unchanged. Requests without a replayable body are
+// not hashed.
+func (r *deliveryReceipt) recordRequest(req *http.Request) {
+	if req.GetBody == nil {
+		return
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.payloadHash = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	r.mu.Unlock()
+}
+
+func (r *deliveryReceipt) recordResponse(resp *http.Response) {
+	r.mu.Lock()
+	r.statusCode = resp.StatusCode
+	r.mu.Unlock()
+}
+
+// get returns the status code and payload hash recorded, zero if none.
+// A nil receipt records nothing.
+func (r *deliveryReceipt) get() (statusCode int, payloadHash string) {
+	if r == nil {
+		return 0, ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statusCode, r.payloadHash
+}
@@ -0,0 +1,313 @@
+package publishing
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// DLQDrainerConfig configures DLQDrainer.
+type DLQDrainerConfig struct {
+	// Interval between target health checks (default: 30s).
+	Interval time.Duration
+
+	// HealthyFor is how long a target must stay healthy before its
+	// dead-lettered jobs are replayed (default: 5m).
+	HealthyFor time.Duration
+
+	// RateLimit caps the replayed jobs per second across targets
+	// (default: 5).
+	RateLimit float64
+
+	// BatchSize caps the jobs of a target replayed per check (default: 100).
+	BatchSize int
+
+	// MaxBackoff caps the wait before a target is drained again after a
+	// replay failed or the target failed again after a replay (default: 30m).
+	MaxBackoff time.Duration
+
+	// Health reports target health checks (optional: only the circuit
+	// breakers of the queue are watched without it).
+	Health HealthMonitor
+
+	// Metrics counts the replayed jobs (optional).
+	Metrics *v2.PublishingMetrics
+
+	Logger *slog.Logger
+}
+
+// DLQ drainer defaults.
+const (
+	DefaultDLQDrainInterval   = 30 * time.Second
+	DefaultDLQDrainHealthyFor = 5 * time.Minute
+	DefaultDLQDrainRateLimit  = 5
+	DefaultDLQDrainBatchSize  = 100
+	DefaultDLQDrainMaxBackoff = 30 * time.Minute
+)
+
+// DLQ replay results.
+const (
+	DLQReplayResultSuccess = "success"
+	DLQReplayResultFailed  = "failed"
+)
+
+// DLQDrainer replays the dead-lettered jobs of a target once it recovered.
+//
+// A target is healthy while its circuit breaker is closed and, with a
+// HealthMonitor, its health checks succeed. Once a discovered target has been
+// healthy for HealthyFor, its DLQ entries that failed before it recovered
+// are submitted again, oldest first, with its current configuration and at
+// most RateLimit per second.
+// Replays stop while the queue is more than half full, leaving room for live
+// alerts.
+//
+// When a replay fails, or the target turns unhealthy again before its DLQ
+// is drained, the target is backed off: it is drained again after twice
+// the check interval, doubling up to MaxBackoff. The backoff resets once
+// no entry of the target is left to replay.
+type DLQDrainer struct {
+	dlq        DLQRepository
+	queue      *PublishingQueue
+	discovery  TargetDiscoveryManager
+	health     HealthMonitor
+	metrics    *v2.PublishingMetrics
+	limiter    *rate.Limiter
+	interval   time.Duration
+	healthyFor time.Duration
+	batchSize  int
+	maxBackoff time.Duration
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	targets map[string]*drainTarget
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// drainTarget is the replay state of a target with dead-lettered jobs.
+type drainTarget struct {
+	healthySince time.Time // zero while unhealthy
+	failures     int       // failed drains since the DLQ was last drained
+	retryAt      time.Time // backoff deadline
+	replayed     bool      // jobs were replayed since the target recovered
+}
+
+// NewDLQDrainer creates a DLQ drainer.
+func NewDLQDrainer(dlq DLQRepository, queue *PublishingQueue, discovery TargetDiscoveryManager, config DLQDrainerConfig) *DLQDrainer {
+	if config.Interval <= 0 {
+		config.Interval = DefaultDLQDrainInterval
+	}
+	if config.HealthyFor <= 0 {
+		config.HealthyFor = DefaultDLQDrainHealthyFor
+	}
+	if config.RateLimit <= 0 {
+		config.RateLimit = DefaultDLQDrainRateLimit
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultDLQDrainBatchSize
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultDLQDrainMaxBackoff
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	return &DLQDrainer{
+		dlq:        dlq,
+		queue:      queue,
+		discovery:  discovery,
+		health:     config.Health,
+		metrics:    config.Metrics,
+		limiter:    rate.NewLimiter(rate.Limit(config.RateLimit), 1),
+		interval:   config.Interval,
+		healthyFor: config.HealthyFor,
+		batchSize:  config.BatchSize,
+		maxBackoff: config.MaxBackoff,
+		logger:     config.Logger.With("component", "dlq_drainer"),
+		targets:    make(map[string]*drainTarget),
+	}
+}
+
+// Drain checks the health of the targets with dead-lettered jobs and
+// replays those of the targets that recovered. Returns the number of jobs
+// replayed.
+func (d *DLQDrainer) Drain(ctx context.Context) int {
+	stats, err := d.dlq.GetStats(ctx)
+	if err != nil {
+		d.logger.Warn("Failed to read DLQ stats", "error", err)
+		return 0
+	}
+	names := make([]string, 0, len(stats.EntriesByTarget))
+	for name := range stats.EntriesByTarget {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	replayed := 0
+	for _, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		replayed += d.drainTarget(ctx, name, time.Now())
+	}
+	return replayed
+}
+
+func (d *DLQDrainer) drainTarget(ctx context.Context, name string, now time.Time) int {
+	target, err := d.discovery.GetTarget(name)
+	if err != nil {
+		// Removed or renamed: its jobs are left for manual replay
+		delete(d.targets, name)
+		return 0
+	}
+
+	state, ok := d.targets[name]
+	if !ok {
+		state = &drainTarget{}
+		d.targets[name] = state
+	}
+
+	if !d.isHealthy(ctx, name) {
+		if state.replayed {
+			state.replayed = false
+			d.backOff(state, now)
+			d.logger.Warn("Target failed again after DLQ replay, backing off",
+				"target", name,
+				"retry_at", state.retryAt,
+			)
+		}
+		state.healthySince = time.Time{}
+		return 0
+	}
+	if state.healthySince.IsZero() {
+		state.healthySince = now
+	}
+	if now.Sub(state.healthySince) < d.healthyFor || now.Before(state.retryAt) {
+		return 0
+	}
+
+	pending := false
+	// Jobs that failed while the target was healthy would fail again
+	entries, err := d.dlq.Read(ctx, DLQFilters{
+		TargetName:   name,
+		Replayed:     &pending,
+		FailedBefore: &state.healthySince,
+		Limit:        d.batchSize,
+	})
+	if err != nil {
+		d.logger.Warn("Failed to read DLQ entries", "target", name, "error", err)
+		return 0
+	}
+	if len(entries) == 0 {
+		state.failures = 0
+		return 0
+	}
+
+	// Read returns the newest entries first
+	slices.Reverse(entries)
+	replayed := 0
+	for _, entry := range entries {
+		if d.queue.GetQueueSize()*2 > d.queue.GetQueueCapacity() {
+			break
+		}
+		if err := d.limiter.Wait(ctx); err != nil {
+			break
+		}
+		if err := d.queue.Submit(entry.EnrichedAlert, target); err != nil {
+			d.recordReplay(name, DLQReplayResultFailed)
+			d.backOff(state, now)
+			d.logger.Warn("Failed to replay DLQ entry, backing off",
+				"target", name,
+				"dlq_id", entry.ID,
+				"retry_at", state.retryAt,
+				"error", err,
+			)
+			break
+		}
+		d.recordReplay(name, DLQReplayResultSuccess)
+		if err := d.dlq.MarkReplayed(ctx, entry.ID, DLQReplayResultSuccess); err != nil {
+			d.logger.Warn("Failed to mark DLQ entry replayed", "target", name, "dlq_id", entry.ID, "error", err)
+		}
+		replayed++
+	}
+
+	if replayed > 0 {
+		state.replayed = true
+		d.logger.Info("Replayed DLQ entries of recovered target", "target", name, "entries", replayed)
+	}
+	return replayed
+}
+
+// isHealthy reports whether the circuit breaker of a target is closed and
+// its health checks succeed.
+func (d *DLQDrainer) isHealthy(ctx context.Context, name string) bool {
+	if d.queue.isCircuitOpen(name) {
+		return false
+	}
+	if d.health == nil {
+		return true
+	}
+	health, err := d.health.GetHealthByName(ctx, name)
+	return err == nil && health.IsHealthy()
+}
+
+// backOff delays the next drain of a target, doubling the delay with every
+// failure up to maxBackoff.
+func (d *DLQDrainer) backOff(state *drainTarget, now time.Time) {
+	state.failures++
+	delay := d.maxBackoff
+	if state.failures < 32 {
+		delay = min(d.interval<<state.failures, d.maxBackoff)
+	}
+	state.retryAt = now.Add(delay)
+}
+
+func (d *DLQDrainer) recordReplay(target, result string) {
+	if d.metrics != nil {
+		d.metrics.RecordDLQReplay(target, result)
+	}
+}
+
+// ForgetTarget drops the replay state of a removed target.
+func (d *DLQDrainer) ForgetTarget(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.targets, name)
+}
+
+// Start drains every interval until ctx is done or Stop is called.
+func (d *DLQDrainer) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.Drain(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops draining and waits for a running drain.
+func (d *DLQDrainer) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
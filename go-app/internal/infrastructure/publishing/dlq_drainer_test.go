@@ -0,0 +1,184 @@
+package publishing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// drainTestDLQ serves DLQ entries and records replays.
+type drainTestDLQ struct {
+	recordingDLQRepository
+	mu       sync.Mutex
+	entries  []*DLQEntry
+	replayed map[uuid.UUID]string
+}
+
+func (r *drainTestDLQ) add(target, fingerprint string, failedAt time.Time) {
+	alert := panicTestAlert()
+	alert.Alert.Fingerprint = fingerprint
+	// Newest first, like PostgreSQLDLQRepository.Read
+	r.entries = append([]*DLQEntry{{
+		ID:            uuid.New(),
+		Fingerprint:   fingerprint,
+		TargetName:    target,
+		EnrichedAlert: alert,
+		TargetConfig:  &core.PublishingTarget{Name: target, Type: "webhook", URL: "http://stale.example.com"},
+		FailedAt:      failedAt,
+	}}, r.entries...)
+}
+
+func (r *drainTestDLQ) Read(ctx context.Context, filters DLQFilters) ([]*DLQEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var entries []*DLQEntry
+	for _, entry := range r.entries {
+		_, replayed := r.replayed[entry.ID]
+		switch {
+		case filters.TargetName != "" && entry.TargetName != filters.TargetName:
+		case filters.Replayed != nil && replayed != *filters.Replayed:
+		case filters.FailedBefore != nil && !entry.FailedAt.Before(*filters.FailedBefore):
+		default:
+			entries = append(entries, entry)
+		}
+	}
+	if filters.Limit > 0 && len(entries) > filters.Limit {
+		entries = entries[:filters.Limit]
+	}
+	return entries, nil
+}
+
+func (r *drainTestDLQ) MarkReplayed(ctx context.Context, id uuid.UUID, result string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.replayed == nil {
+		r.replayed = make(map[uuid.UUID]string)
+	}
+	r.replayed[id] = result
+	return nil
+}
+
+func (r *drainTestDLQ) GetStats(ctx context.Context) (*DLQStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := &DLQStats{EntriesByTarget: make(map[string]int)}
+	for _, entry := range r.entries {
+		stats.EntriesByTarget[entry.TargetName]++
+	}
+	return stats, nil
+}
+
+func newDrainTest(t *testing.T, health HealthMonitor) (*DLQDrainer, *drainTestDLQ, *PublishingQueue, *core.PublishingTarget) {
+	t.Helper()
+	dlq := &drainTestDLQ{}
+	queue := newPanickingQueue(dlq)
+	t.Cleanup(queue.cancel)
+
+	target := &core.PublishingTarget{Name: "flaky-webhook", Type: "webhook", URL: "http://current.example.com"}
+	discovery := NewStubTargetDiscoveryManager(slog.Default())
+	discovery.SetTargets([]*core.PublishingTarget{target})
+
+	drainer := NewDLQDrainer(dlq, queue, discovery, DLQDrainerConfig{
+		Interval:   10 * time.Millisecond,
+		HealthyFor: 20 * time.Millisecond,
+		RateLimit:  1000,
+		Health:     health,
+	})
+	return drainer, dlq, queue, target
+}
+
+func TestDLQDrainer_ReplaysOnceTargetHealthy(t *testing.T) {
+	drainer, dlq, queue, target := newDrainTest(t, nil)
+	failedAt := time.Now().Add(-time.Minute)
+	dlq.add(target.Name, "fp-1", failedAt)
+	dlq.add(target.Name, "fp-2", failedAt.Add(time.Second))
+	dlq.add("removed-webhook", "fp-3", failedAt)
+
+	assert.Equal(t, 0, drainer.Drain(context.Background()), "target not healthy for long enough yet")
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, 2, drainer.Drain(context.Background()))
+
+	first, second := <-queue.mediumPriorityJobs, <-queue.mediumPriorityJobs
+	assert.Equal(t, "fp-1", first.EnrichedAlert.Alert.Fingerprint, "oldest entry first")
+	assert.Equal(t, "fp-2", second.EnrichedAlert.Alert.Fingerprint)
+	assert.Equal(t, "http://current.example.com", first.Target.URL, "replayed to the current target")
+	assert.Len(t, dlq.replayed, 2)
+
+	assert.Equal(t, 0, drainer.Drain(context.Background()), "entries are replayed once")
+}
+
+func TestDLQDrainer_WaitsForHealthyTarget(t *testing.T) {
+	health := &mockHealthMonitor{healthStatus: map[string]TargetHealth{}}
+	drainer, dlq, queue, target := newDrainTest(t, health)
+	dlq.add(target.Name, "fp-1", time.Now().Add(-time.Minute))
+
+	// Open circuit breaker
+	cb := queue.getCircuitBreaker(target)
+	for range 5 {
+		cb.RecordFailure()
+	}
+	drainer.Drain(context.Background())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 0, drainer.Drain(context.Background()))
+
+	// Failing health checks
+	cb.Reset()
+	health.healthStatus[target.Name] = &mockTargetHealth{}
+	drainer.Drain(context.Background())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 0, drainer.Drain(context.Background()))
+
+	delete(health.healthStatus, target.Name)
+	drainer.Drain(context.Background())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, drainer.Drain(context.Background()))
+}
+
+func TestDLQDrainer_BacksOffWhenTargetFailsAgain(t *testing.T) {
+	drainer, dlq, queue, target := newDrainTest(t, nil)
+	failedAt := time.Now().Add(-time.Minute)
+	dlq.add(target.Name, "fp-1", failedAt)
+
+	drainer.Drain(context.Background())
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, 1, drainer.Drain(context.Background()))
+
+	// The replayed job fails again and opens the circuit
+	cb := queue.getCircuitBreaker(target)
+	for range 5 {
+		cb.RecordFailure()
+	}
+	dlq.add(target.Name, "fp-1", failedAt.Add(time.Second))
+	before := time.Now()
+	drainer.Drain(context.Background())
+	state := drainer.targets[target.Name]
+	require.Equal(t, 1, state.failures)
+	assert.WithinDuration(t, before.Add(20*time.Millisecond), state.retryAt, 10*time.Millisecond)
+
+	// Backoff doubles up to MaxBackoff
+	drainer.maxBackoff = 50 * time.Millisecond
+	drainer.backOff(state, before)
+	assert.Equal(t, before.Add(40*time.Millisecond), state.retryAt)
+	drainer.backOff(state, before)
+	assert.Equal(t, before.Add(50*time.Millisecond), state.retryAt)
+}
+
+func TestDLQDrainer_SkipsEntriesFailedWhileHealthy(t *testing.T) {
+	drainer, dlq, _, target := newDrainTest(t, nil)
+
+	dlq.add(target.Name, "fp-1", time.Now().Add(-time.Minute))
+	drainer.Drain(context.Background()) // target healthy from now on
+	dlq.add(target.Name, "fp-2", time.Now())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, drainer.Drain(context.Background()))
+	assert.Len(t, dlq.replayed, 1)
+	assert.Equal(t, 0, drainer.Drain(context.Background()))
+}
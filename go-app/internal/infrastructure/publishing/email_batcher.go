@@ -0,0 +1,115 @@
+package publishing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// emailMaxBatchSize — число алертов, при котором батч отправляется до
+// истечения окна.
+const emailMaxBatchSize = 100
+
+// emailBatcher группирует алерты email target-а (batch_wait) в одно письмо.
+//
+// Каждый Publish ждёт отправки своего батча и получает её результат, чтобы
+// ошибка SMTP дошла до очереди (retry, DLQ, circuit breaker, история
+// доставки). Пока батч открыт, ожидающие Publish занимают worker-ы очереди.
+// Батчи хранятся в PublisherFactory, т.к. очередь создаёт новый publisher на
+// каждый job. Батч отправляется по таймеру, при emailMaxBatchSize алертах
+// или при close().
+type emailBatcher struct {
+	mu      sync.Mutex
+	batches map[string]*emailBatch // по имени target-а
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// emailBatch — открытый батч одного target-а.
+type emailBatch struct {
+	target *core.PublishingTarget
+	alerts []*core.EnrichedAlert
+	send   func(alerts []*core.EnrichedAlert, target *core.PublishingTarget) error
+	timer  *time.Timer
+
+	done chan struct{} // закрывается после отправки
+	err  error         // результат отправки, читать после done
+}
+
+func newEmailBatcher() *emailBatcher {
+	return &emailBatcher{batches: make(map[string]*emailBatch)}
+}
+
+// add добавляет алерт в открытый батч target-а или открывает новый на wait.
+// Повторный алерт с тем же fingerprint заменяет предыдущий (последнее
+// состояние); оба вызова получают результат отправки батча. Возвращает nil
+// после close() — тогда вызывающий отправляет письмо сам.
+func (b *emailBatcher) add(alert *core.EnrichedAlert, target *core.PublishingTarget, wait time.Duration, send func([]*core.EnrichedAlert, *core.PublishingTarget) error) *emailBatch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+
+	key := target.Name
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &emailBatch{done: make(chan struct{})}
+		b.batches[key] = batch
+		b.wg.Add(1)
+		batch.timer = time.AfterFunc(wait, func() { b.flush(key, batch) })
+	}
+	// Последняя конфигурация target-а побеждает (refresh discovery)
+	batch.target = target
+	batch.send = send
+
+	replaced := false
+	for i, queued := range batch.alerts {
+		if queued.Alert.Fingerprint == alert.Alert.Fingerprint {
+			batch.alerts[i] = alert
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		batch.alerts = append(batch.alerts, alert)
+	}
+
+	// Stop() == false: таймер уже сработал, flush заберёт батч сам
+	if len(batch.alerts) >= emailMaxBatchSize && batch.timer.Stop() {
+		go b.flush(key, batch)
+	}
+	return batch
+}
+
+// flush закрывает батч, отправляет его и будит ожидающих. Вызывается ровно
+// один раз на батч.
+func (b *emailBatcher) flush(key string, batch *emailBatch) {
+	defer b.wg.Done()
+
+	b.mu.Lock()
+	if b.batches[key] == batch {
+		delete(b.batches, key)
+	}
+	alerts, target, send := batch.alerts, batch.target, batch.send
+	b.mu.Unlock()
+
+	batch.err = send(alerts, target)
+	close(batch.done)
+}
+
+// close отправляет открытые батчи, не дожидаясь окна, и ждёт отправки.
+// Алерты, добавленные после close(), отправляются без группировки.
+func (b *emailBatcher) close() {
+	b.mu.Lock()
+	b.closed = true
+	for key, batch := range b.batches {
+		if batch.timer.Stop() {
+			go b.flush(key, batch)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
@@ -68,6 +68,19 @@ func (d *SMTPDialer) isDirectTLS() bool {
 	return d.config.RequireTLS && d.config.Port == 465
 }
 
+// tlsConfig возвращает TLS-конфигурацию для direct TLS и STARTTLS.
+func (d *SMTPDialer) tlsConfig() *tls.Config {
+	serverName := d.config.TLSServerName
+	if serverName == "" {
+		serverName = d.config.Host
+	}
+	return &tls.Config{
+		ServerName:         serverName,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: d.config.InsecureSkipVerify, // opt-in через smtp_tls_insecure_skip_verify
+	}
+}
+
 // dialSMTP устанавливает соединение и создаёт SMTP client.
 // Порт 465 + RequireTLS → direct TLS (SMTPS): TLS handshake до SMTP banner.
 // Иначе → обычный TCP; STARTTLS вызывается в setupSMTPSession если RequireTLS=true.
@@ -81,11 +94,7 @@ func (d *SMTPDialer) dialSMTP(ctx context.Context) (*smtp.Client, error) {
 		if err != nil {
 			return nil, fmt.Errorf("email: dial %s: %w", addr, err)
 		}
-		tlsCfg := &tls.Config{
-			ServerName: d.config.Host,
-			MinVersion: tls.VersionTLS12,
-		}
-		tlsConn := tls.Client(rawConn, tlsCfg)
+		tlsConn := tls.Client(rawConn, d.tlsConfig())
 		if err := tlsConn.HandshakeContext(ctx); err != nil {
 			rawConn.Close()
 			return nil, fmt.Errorf("email: TLS handshake: %w", err)
@@ -124,16 +133,12 @@ func (d *SMTPDialer) dialSMTP(ctx context.Context) (*smtp.Client, error) {
 // Вызывается из SendEmail и Health для устранения дублирования кода.
 func (d *SMTPDialer) setupSMTPSession(client *smtp.Client) error {
 	if d.config.RequireTLS && !d.isDirectTLS() {
-		tlsCfg := &tls.Config{
-			ServerName: d.config.Host,
-			MinVersion: tls.VersionTLS12,
-		}
-		if err := client.StartTLS(tlsCfg); err != nil {
+		if err := client.StartTLS(d.tlsConfig()); err != nil {
 			return fmt.Errorf("StartTLS: %w", err)
 		}
 	}
 	if d.config.Username != "" {
-		auth := smtp.PlainAuth("", d.config.Username, d.config.Password, d.config.Host)
+		auth := smtp.PlainAuth(d.config.Identity, d.config.Username, d.config.Password, d.config.Host)
 		if err := client.Auth(auth); err != nil {
 			return fmt.Errorf("AUTH: %w", err)
 		}
@@ -286,8 +291,9 @@ func buildMIMEMessage(msg *EmailMessage, resolvedFrom string, to []string) ([]by
 	buf.WriteString("Content-Type: multipart/alternative; boundary=\"" + boundary + "\"\r\n")
 
 	// Дополнительные заголовки (sanitize CRLF для предотвращения header injection).
-	// Зарезервированные заголовки (Content-Type, MIME-Version) пропускаются —
-	// их значения управляются buildMIMEMessage, дублирование сломает MIME-парсинг.
+	// Зарезервированные заголовки (Content-Type, MIME-Version, а также уже
+	// записанные выше From/To/Subject/Date/Message-ID) пропускаются —
+	// их значения управляются buildMIMEMessage, дубликаты ломают MIME-парсинг.
 	// Сортировка ключей для детерминированного порядка в unit-тестах.
 	headerKeys := make([]string, 0, len(msg.Headers))
 	for k := range msg.Headers {
@@ -297,7 +303,7 @@ func buildMIMEMessage(msg *EmailMessage, resolvedFrom string, to []string) ([]by
 	for _, k := range headerKeys {
 		// Пропускаем заголовки управляемые MIME-конструктором
 		switch strings.ToLower(k) {
-		case "content-type", "mime-version":
+		case "content-type", "mime-version", "from", "to", "subject", "date", "message-id":
 			continue
 		}
 		v := msg.Headers[k]
@@ -3,14 +3,17 @@ package publishing
 import "time"
 
 // SMTPConfig содержит параметры подключения к SMTP-серверу.
-// Заполняется из PublishingTarget.Headers при создании publisher-а.
+// Заполняется из PublishingTarget (URL и Headers) при отправке.
 type SMTPConfig struct {
-	Host       string // SMTP-хост (без порта)
-	Port       int    // SMTP-порт (по умолчанию 587)
-	Username   string // SMTP AUTH username
-	Password   string // SMTP AUTH password
-	RequireTLS bool   // Требовать TLS: порт 465 → direct TLS (SMTPS), остальные → STARTTLS
-	From       string // Адрес отправителя (MAIL FROM)
+	Host               string // SMTP-хост (без порта)
+	Port               int    // SMTP-порт (по умолчанию 587)
+	Username           string // SMTP AUTH username
+	Password           string // SMTP AUTH password
+	Identity           string // SMTP AUTH PLAIN identity (обычно пустой)
+	RequireTLS         bool   // Требовать TLS: порт 465 → direct TLS (SMTPS), остальные → STARTTLS
+	TLSServerName      string // Имя сервера для проверки сертификата (по умолчанию Host)
+	InsecureSkipVerify bool   // Не проверять сертификат сервера (только для тестовых стендов)
+	From               string // Адрес отправителя (MAIL FROM)
 }
 
 // EmailMessage представляет готовое к отправке письмо.
@@ -32,14 +35,40 @@ type emailTemplateData struct {
 	CommonAnnotations map[string]string
 	Labels            map[string]string
 	Annotations       map[string]string
-	Alerts            []emailAlertItem
+	Alerts            emailAlerts
 	Receiver          string
 	ExternalURL       string
 	SilenceURL        string
 }
 
+// emailAlerts — алерты письма. Firing/Resolved доступны в шаблонах как
+// .Alerts.Firing и .Alerts.Resolved, как в шаблонах Alertmanager.
+type emailAlerts []emailAlertItem
+
+// Firing возвращает firing алерты.
+func (a emailAlerts) Firing() []emailAlertItem {
+	return a.withStatus("firing")
+}
+
+// Resolved возвращает resolved алерты.
+func (a emailAlerts) Resolved() []emailAlertItem {
+	return a.withStatus("resolved")
+}
+
+func (a emailAlerts) withStatus(status string) []emailAlertItem {
+	var out []emailAlertItem
+	for _, alert := range a {
+		if alert.Status == status {
+			out = append(out, alert)
+		}
+	}
+	return out
+}
+
 // emailAlertItem — один алерт в контексте шаблона.
 type emailAlertItem struct {
+	Status      string
+	Fingerprint string
 	Labels      map[string]string
 	Annotations map[string]string
 	StartsAt    time.Time
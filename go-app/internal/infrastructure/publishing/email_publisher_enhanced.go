@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"strconv"
 	"strings"
 	"text/template"
@@ -16,12 +17,33 @@ import (
 	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
 )
 
+// Ключи target.Headers, управляющие доставкой (помимо to/from/smtp_*/шаблонов,
+// см. extractEmailConfig и extractSMTPConfig).
+const (
+	// emailHeaderPrefix — префикс per-receiver заголовков письма:
+	// "header.X-Priority": "1" добавляет заголовок "X-Priority: 1".
+	// Значения рендерятся как шаблоны, как headers в Alertmanager email_configs.
+	emailHeaderPrefix = "header."
+	// emailBatchWaitKey — окно группировки (например "30s"): алерты target-а,
+	// пришедшие в течение окна, отправляются одним письмом.
+	emailBatchWaitKey = "batch_wait"
+	// emailSendResolvedKey — "false" отключает письма о resolved алертах.
+	emailSendResolvedKey = "send_resolved"
+)
+
+// emailSendTimeout — таймаут отправки одного письма батча.
+const emailSendTimeout = 30 * time.Second
+
 // EnhancedEmailPublisher реализует AlertPublisher для SMTP email-доставки.
 // Использует SMTPClient (net/smtp) и рендерит HTML+Text multipart письма
 // из шаблонов defaults.GetDefaultEmailTemplates().
+//
+// SMTP-клиент определяется по target при отправке: очередь создаёт
+// publisher-ы только по типу target-а.
 type EnhancedEmailPublisher struct {
 	*BaseEnhancedPublisher
-	client      SMTPClient
+	clientFor   func(target *core.PublishingTarget) (SMTPClient, error)
+	batcher     *emailBatcher
 	externalURL string
 }
 
@@ -34,13 +56,26 @@ func NewEnhancedEmailPublisher(
 	logger *slog.Logger,
 	externalURL string,
 ) AlertPublisher {
+	clientFor := func(*core.PublishingTarget) (SMTPClient, error) { return client, nil }
+	return newEnhancedEmailPublisher(clientFor, newEmailBatcher(), metrics, formatter, logger, externalURL)
+}
+
+func newEnhancedEmailPublisher(
+	clientFor func(target *core.PublishingTarget) (SMTPClient, error),
+	batcher *emailBatcher,
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+	externalURL string,
+) *EnhancedEmailPublisher {
 	return &EnhancedEmailPublisher{
 		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
 			metrics,
 			formatter,
 			logger.With("component", "email_publisher"),
 		),
-		client:      client,
+		clientFor:   clientFor,
+		batcher:     batcher,
 		externalURL: externalURL,
 	}
 }
@@ -51,29 +86,79 @@ func (p *EnhancedEmailPublisher) Name() string {
 }
 
 // Publish рендерит и отправляет email для enrichedAlert через target.
+//
+// Если у target задан batch_wait, алерт добавляется в батч и Publish ждёт
+// его отправки (см. emailBatcher): ошибка письма возвращается каждому
+// алерту батча, и очередь повторяет их как обычно. Отмена ctx прекращает
+// ожидание, но не отправку батча.
 func (p *EnhancedEmailPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
-	startTime := time.Now()
-
 	p.LogPublishStart(ctx, ProviderEmail, enrichedAlert)
 
+	if enrichedAlert.Alert.Status == core.StatusResolved && strings.EqualFold(target.Headers[emailSendResolvedKey], "false") {
+		p.GetLogger().DebugContext(ctx, "Skipping resolved alert (send_resolved=false)",
+			slog.String("target", target.Name),
+			slog.String("fingerprint", enrichedAlert.Alert.Fingerprint))
+		return nil
+	}
+
+	if wait := emailBatchWait(target); wait > 0 {
+		if batch := p.batcher.add(enrichedAlert, target, wait, p.sendBatch); batch != nil {
+			select {
+			case <-batch.done:
+				return batch.err
+			case <-ctx.Done():
+				return fmt.Errorf("email: waiting for batch: %w", ctx.Err())
+			}
+		}
+	}
+	return p.send(ctx, []*core.EnrichedAlert{enrichedAlert}, target)
+}
+
+// sendBatch отправляет письмо батча. Контекст не берётся у Publish: батч
+// общий для нескольких вызовов, и отмена одного из них не должна его
+// прерывать. Повторы выполняет очередь.
+func (p *EnhancedEmailPublisher) sendBatch(alerts []*core.EnrichedAlert, target *core.PublishingTarget) error {
+	ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+	defer cancel()
+	return p.send(ctx, alerts, target)
+}
+
+// send рендерит одно письмо для alerts и отправляет его.
+func (p *EnhancedEmailPublisher) send(ctx context.Context, alerts []*core.EnrichedAlert, target *core.PublishingTarget) error {
+	startTime := time.Now()
+	fingerprint := emailFingerprints(alerts)
+
 	// Извлечь параметры email из target.Headers
 	to, from, subjectTmpl, htmlTmpl, textTmpl := extractEmailConfig(target)
 	if len(to) == 0 {
 		err := fmt.Errorf("email: no recipients configured for target %q (set 'to' header)", target.Name)
-		p.LogPublishError(ctx, ProviderEmail, enrichedAlert.Alert.Fingerprint, err)
+		p.LogPublishError(ctx, ProviderEmail, fingerprint, err)
 		if p.GetMetrics() != nil {
 			p.GetMetrics().RecordAPIError(ProviderEmail, "send", "invalid_recipient")
 		}
 		return err
 	}
 
-	// Построить template data из enrichedAlert
-	tmplData := buildEmailTemplateData(enrichedAlert, target, p.externalURL)
+	client, err := p.clientFor(target)
+	if err != nil {
+		p.LogPublishError(ctx, ProviderEmail, fingerprint, err)
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(ProviderEmail, "send", "config_error")
+		}
+		return err
+	}
+
+	// Построить template data из алертов
+	tmplData := buildEmailBatchTemplateData(alerts, target, p.externalURL)
 
-	// Рендеринг тела письма
+	// Рендеринг тела письма и per-receiver заголовков
 	subject, html, text, err := renderEmailContent(tmplData, subjectTmpl, htmlTmpl, textTmpl)
+	var headers map[string]string
+	if err == nil {
+		headers, err = renderEmailHeaders(tmplData, target)
+	}
 	if err != nil {
-		p.LogPublishError(ctx, ProviderEmail, enrichedAlert.Alert.Fingerprint, err)
+		p.LogPublishError(ctx, ProviderEmail, fingerprint, err)
 		if p.GetMetrics() != nil {
 			p.GetMetrics().RecordAPIError(ProviderEmail, "send", "format_error")
 		}
@@ -87,15 +172,13 @@ func (p *EnhancedEmailPublisher) Publish(ctx context.Context, enrichedAlert *cor
 		Subject: subject,
 		HTML:    html,
 		Text:    text,
-		Headers: map[string]string{
-			"X-Mailer": "Alertmanager++ OSS",
-		},
+		Headers: headers,
 	}
 
 	// Отправить
-	if err := p.client.SendEmail(ctx, msg); err != nil {
+	if err := client.SendEmail(ctx, msg); err != nil {
 		errType := classifyEmailError(err)
-		p.LogPublishError(ctx, ProviderEmail, enrichedAlert.Alert.Fingerprint, err)
+		p.LogPublishError(ctx, ProviderEmail, fingerprint, err)
 		if p.GetMetrics() != nil {
 			p.GetMetrics().RecordAPIError(ProviderEmail, "send", errType)
 			p.GetMetrics().RecordAPIDuration(ProviderEmail, "send", "SMTP", time.Since(startTime))
@@ -105,7 +188,7 @@ func (p *EnhancedEmailPublisher) Publish(ctx context.Context, enrichedAlert *cor
 
 	// Успех
 	duration := time.Since(startTime)
-	p.LogPublishSuccess(ctx, ProviderEmail, enrichedAlert.Alert.Fingerprint, duration)
+	p.LogPublishSuccess(ctx, ProviderEmail, fingerprint, duration)
 	if p.GetMetrics() != nil {
 		p.GetMetrics().RecordMessage(ProviderEmail, "success")
 		p.GetMetrics().RecordAPIDuration(ProviderEmail, "send", "SMTP", duration)
@@ -114,6 +197,44 @@ func (p *EnhancedEmailPublisher) Publish(ctx context.Context, enrichedAlert *cor
 	return nil
 }
 
+// emailBatchWait возвращает окно группировки target-а (0 — без группировки).
+func emailBatchWait(target *core.PublishingTarget) time.Duration {
+	wait, err := time.ParseDuration(target.Headers[emailBatchWaitKey])
+	if err != nil || wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// emailFingerprints возвращает fingerprints алертов письма для логов.
+func emailFingerprints(alerts []*core.EnrichedAlert) string {
+	fingerprints := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		fingerprints = append(fingerprints, alert.Alert.Fingerprint)
+	}
+	return strings.Join(fingerprints, ",")
+}
+
+// renderEmailHeaders рендерит заголовки письма: X-Mailer и per-receiver
+// заголовки из ключей target.Headers с префиксом "header.".
+func renderEmailHeaders(data *emailTemplateData, target *core.PublishingTarget) (map[string]string, error) {
+	headers := map[string]string{
+		"X-Mailer": "Alertmanager++ OSS",
+	}
+	for key, value := range target.Headers {
+		name, ok := strings.CutPrefix(key, emailHeaderPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		rendered, err := renderTemplate("header "+name, value, data)
+		if err != nil {
+			return nil, fmt.Errorf("header %s template: %w", name, err)
+		}
+		headers[name] = rendered
+	}
+	return headers, nil
+}
+
 // extractEmailConfig извлекает email-параметры из target.Headers.
 //
 // Поддерживаемые ключи Headers:
@@ -163,19 +284,38 @@ func extractEmailConfig(target *core.PublishingTarget) (to []string, from, subje
 	return
 }
 
-// extractSMTPConfig извлекает SMTP-параметры из target.Headers.
+// extractSMTPConfig извлекает SMTP-параметры из target.URL и target.Headers.
+//
+// URL вида smtp://host:port (STARTTLS, если smtp_tls=true) или
+// smtps://host:port (direct TLS, порт по умолчанию 465) задаёт сервер,
+// если не задан smtp_host.
 //
 // Поддерживаемые ключи Headers:
-//   - "smtp_host"     — SMTP сервер (обязательный)
+//   - "smtp_host"     — SMTP сервер (обязательный, если URL не smtp/smtps)
 //   - "smtp_port"     — SMTP порт (по умолчанию 587)
 //   - "smtp_username" — SMTP auth username
 //   - "smtp_password" — SMTP auth password
+//   - "smtp_identity" — SMTP auth PLAIN identity
 //   - "smtp_tls"      — "true" для STARTTLS (по умолчанию false)
+//   - "smtp_tls_server_name"          — имя сервера в сертификате
+//   - "smtp_tls_insecure_skip_verify" — "true" отключает проверку сертификата
 //   - "from"          — адрес отправителя
 func extractSMTPConfig(target *core.PublishingTarget) SMTPConfig {
 	cfg := SMTPConfig{
 		Port: 587,
 	}
+
+	if u, err := url.Parse(target.URL); err == nil && (u.Scheme == "smtp" || u.Scheme == "smtps") {
+		cfg.Host = u.Hostname()
+		if u.Scheme == "smtps" {
+			cfg.Port = 465
+			cfg.RequireTLS = true
+		}
+		if port, err := strconv.Atoi(u.Port()); err == nil && port > 0 {
+			cfg.Port = port
+		}
+	}
+
 	if target.Headers == nil {
 		return cfg
 	}
@@ -194,9 +334,18 @@ func extractSMTPConfig(target *core.PublishingTarget) SMTPConfig {
 	if v, ok := target.Headers["smtp_password"]; ok {
 		cfg.Password = v
 	}
+	if v, ok := target.Headers["smtp_identity"]; ok {
+		cfg.Identity = v
+	}
 	if v, ok := target.Headers["smtp_tls"]; ok {
 		cfg.RequireTLS = strings.EqualFold(v, "true")
 	}
+	if v, ok := target.Headers["smtp_tls_server_name"]; ok {
+		cfg.TLSServerName = v
+	}
+	if v, ok := target.Headers["smtp_tls_insecure_skip_verify"]; ok {
+		cfg.InsecureSkipVerify = strings.EqualFold(v, "true")
+	}
 	if v, ok := target.Headers["from"]; ok {
 		cfg.From = v
 	}
@@ -206,59 +355,109 @@ func extractSMTPConfig(target *core.PublishingTarget) SMTPConfig {
 
 // buildEmailTemplateData строит контекст шаблона из EnrichedAlert и PublishingTarget.
 func buildEmailTemplateData(enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget, externalURL string) *emailTemplateData {
-	alert := enrichedAlert.Alert
-
-	// Для single-alert publisher GroupLabels = {alertname: alert.AlertName}
-	groupLabels := map[string]string{
-		"alertname": alert.AlertName,
-	}
+	return buildEmailBatchTemplateData([]*core.EnrichedAlert{enrichedAlert}, target, externalURL)
+}
 
-	// CommonLabels / Labels — все labels алерта
-	commonLabels := make(map[string]string, len(alert.Labels))
-	for k, v := range alert.Labels {
-		commonLabels[k] = v
-	}
+// buildEmailBatchTemplateData строит контекст шаблона письма с несколькими
+// алертами, как уведомление группы в Alertmanager:
+//   - Status: "firing", если хотя бы один алерт firing, иначе "resolved"
+//   - CommonLabels/CommonAnnotations: пары, одинаковые у всех алертов
+//   - GroupLabels.alertname: общее имя алерта или имена через запятую
+//
+// Labels/Annotations совпадают с CommonLabels/CommonAnnotations (для
+// одного алерта — все его labels/annotations).
+func buildEmailBatchTemplateData(alerts []*core.EnrichedAlert, target *core.PublishingTarget, externalURL string) *emailTemplateData {
+	status := string(core.StatusResolved)
+	var alertNames []string
+	seenNames := make(map[string]bool, len(alerts))
+	items := make(emailAlerts, 0, len(alerts))
+	var commonLabels, commonAnnotations map[string]string
+
+	for i, enrichedAlert := range alerts {
+		alert := enrichedAlert.Alert
+		if alert.Status == core.StatusFiring {
+			status = string(core.StatusFiring)
+		}
+		if !seenNames[alert.AlertName] {
+			seenNames[alert.AlertName] = true
+			alertNames = append(alertNames, alert.AlertName)
+		}
 
-	// CommonAnnotations / Annotations — все annotations алерта
-	commonAnnotations := make(map[string]string, len(alert.Annotations))
-	for k, v := range alert.Annotations {
-		commonAnnotations[k] = v
-	}
+		if i == 0 {
+			commonLabels = copyStringMap(alert.Labels)
+			commonAnnotations = copyStringMap(alert.Annotations)
+		} else {
+			retainCommon(commonLabels, alert.Labels)
+			retainCommon(commonAnnotations, alert.Annotations)
+		}
 
-	// Один алерт в массиве Alerts
-	alertItem := emailAlertItem{
-		Labels:      commonLabels,
-		Annotations: commonAnnotations,
-		StartsAt:    alert.StartsAt,
-		EndsAt:      alert.EndsAt,
+		items = append(items, emailAlertItem{
+			Status:      string(alert.Status),
+			Fingerprint: alert.Fingerprint,
+			Labels:      copyStringMap(alert.Labels),
+			Annotations: copyStringMap(alert.Annotations),
+			StartsAt:    alert.StartsAt,
+			EndsAt:      alert.EndsAt,
+		})
 	}
 
 	return &emailTemplateData{
-		Status:            string(alert.Status),
-		GroupLabels:       groupLabels,
+		Status:            status,
+		GroupLabels:       map[string]string{"alertname": strings.Join(alertNames, ", ")},
 		CommonLabels:      commonLabels,
 		CommonAnnotations: commonAnnotations,
 		Labels:            commonLabels,
 		Annotations:       commonAnnotations,
-		Alerts:            []emailAlertItem{alertItem},
+		Alerts:            items,
 		Receiver:          target.Name,
 		ExternalURL:       externalURL,
-		SilenceURL:        notifurl.BuildSilenceURL(externalURL, alert.Labels),
+		SilenceURL:        notifurl.BuildSilenceURL(externalURL, commonLabels),
+	}
+}
+
+// copyStringMap возвращает копию m (пустую map для nil).
+func copyStringMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// retainCommon удаляет из common пары, которых нет в m.
+func retainCommon(common, m map[string]string) {
+	for k, v := range common {
+		if m[k] != v {
+			delete(common, k)
+		}
 	}
 }
 
 // emailTemplateFuncs возвращает FuncMap с функциями для email-шаблонов.
+// Аргументы — any: отсутствующий ключ map (например .Labels.instance у
+// алерта без instance) передаётся как nil, а не как строка.
 var emailTemplateFuncs = template.FuncMap{
-	"upper": strings.ToUpper,
-	"lower": strings.ToLower,
-	"default": func(def string, val string) string {
-		if val == "" {
-			return def
+	"upper": func(val any) string { return strings.ToUpper(templateString(val)) },
+	"lower": func(val any) string { return strings.ToLower(templateString(val)) },
+	"default": func(def string, val any) string {
+		if s := templateString(val); s != "" {
+			return s
 		}
-		return val
+		return def
 	},
 }
 
+// templateString возвращает строковое значение аргумента шаблона ("" для nil).
+func templateString(val any) string {
+	if val == nil {
+		return ""
+	}
+	if s, ok := val.(string); ok {
+		return s
+	}
+	return fmt.Sprint(val)
+}
+
 // renderEmailContent рендерит subject, html и text из шаблонов и данных.
 func renderEmailContent(data *emailTemplateData, subjectTmpl, htmlTmpl, textTmpl string) (subject, html, text string, err error) {
 	subject, err = renderTemplate("subject", subjectTmpl, data)
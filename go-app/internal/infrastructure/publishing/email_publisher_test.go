@@ -19,10 +19,10 @@ import (
 // ============================================================================
 
 type MockSMTPClient struct {
-	SendEmailCalls  []*EmailMessage
-	SendEmailErr    error
-	HealthErr       error
-	CloseCalled     bool
+	SendEmailCalls []*EmailMessage
+	SendEmailErr   error
+	HealthErr      error
+	CloseCalled    bool
 }
 
 func (m *MockSMTPClient) SendEmail(_ context.Context, msg *EmailMessage) error {
@@ -626,7 +626,7 @@ func TestEncodeRFC2047Words_SingleNonASCII(t *testing.T) {
 // не создаёт невалидный Message-ID с trailing '>'.
 func TestGenerateMessageID_DisplayName(t *testing.T) {
 	inputs := []struct {
-		from   string
+		from       string
 		wantDomain string
 	}{
 		{"alerts@example.com", "example.com"},
@@ -708,6 +708,231 @@ func TestPublisherFactory_CreatePublisherForTarget_Email(t *testing.T) {
 	}
 }
 
+func TestPublisherFactory_CreatePublisher_EmailResolvesTarget(t *testing.T) {
+	factory := NewPublisherFactory(nil, testLogger(), nil, "")
+	defer factory.Shutdown()
+
+	// Очередь создаёт publisher только по типу: SMTP сервер берётся из target
+	pub, err := factory.CreatePublisher("email")
+	if err != nil {
+		t.Fatalf("CreatePublisher(email) error: %v", err)
+	}
+	target := newTestTarget(map[string]string{"to": "ops@example.com"})
+	err = pub.Publish(context.Background(), newTestEnrichedAlert(core.StatusFiring), target)
+	if err == nil || !strings.Contains(err.Error(), "no SMTP server") {
+		t.Errorf("Publish() error = %v, want missing SMTP server", err)
+	}
+
+	target.URL = "smtps://smtp.example.com"
+	client, err := factory.emailClient(target)
+	if err != nil {
+		t.Fatalf("emailClient() error: %v", err)
+	}
+	if cfg := client.(*SMTPDialer).config; cfg.Host != "smtp.example.com" || cfg.Port != 465 || !cfg.RequireTLS {
+		t.Errorf("SMTP config = %+v, want smtp.example.com:465 with TLS", cfg)
+	}
+	if again, _ := factory.emailClient(target); again != client {
+		t.Error("SMTP client not reused for the same server")
+	}
+}
+
+// ============================================================================
+// Тесты группировки, заголовков и send_resolved
+// ============================================================================
+
+func TestEnhancedEmailPublisher_Publish_Batched(t *testing.T) {
+	mock := &MockSMTPClient{}
+	batcher := newEmailBatcher()
+	pub := newEnhancedEmailPublisher(func(*core.PublishingTarget) (SMTPClient, error) { return mock, nil },
+		batcher, nil, nil, testLogger(), "")
+
+	target := newTestTarget(map[string]string{
+		"to":                "ops@example.com",
+		"from":              "alerts@example.com",
+		"batch_wait":        "1h",
+		"subject_template":  "{{ len .Alerts.Firing }} firing, {{ len .Alerts.Resolved }} resolved: {{ .GroupLabels.alertname }}",
+		"header.X-Severity": "{{ .CommonLabels.severity }}",
+		"header.Subject":    "ignored",
+		"header.X-Team":     "dba",
+	})
+
+	cpu := newTestEnrichedAlert(core.StatusFiring)
+	disk := newTestEnrichedAlert(core.StatusFiring)
+	disk.Alert.Fingerprint = "fp-test-002"
+	disk.Alert.AlertName = "DiskFull"
+	disk.Alert.Labels = map[string]string{"alertname": "DiskFull", "severity": "critical"}
+	cpuResolved := newTestEnrichedAlert(core.StatusResolved)
+
+	results := publishBatched(t, pub, batcher, target, cpu, disk, cpuResolved)
+	if len(mock.SendEmailCalls) != 0 {
+		t.Fatalf("SendEmail called before the batch window, %d calls", len(mock.SendEmailCalls))
+	}
+
+	// close() отправляет открытые батчи, не дожидаясь окна
+	batcher.close()
+
+	for range 3 {
+		if err := <-results; err != nil {
+			t.Fatalf("Publish() error: %v", err)
+		}
+	}
+	if len(mock.SendEmailCalls) != 1 {
+		t.Fatalf("SendEmail called %d times, want 1", len(mock.SendEmailCalls))
+	}
+	msg := mock.SendEmailCalls[0]
+	if want := "1 firing, 1 resolved: HighCPU, DiskFull"; msg.Subject != want {
+		t.Errorf("Subject = %q, want %q", msg.Subject, want)
+	}
+	if msg.Headers["X-Severity"] != "critical" || msg.Headers["X-Team"] != "dba" {
+		t.Errorf("Headers = %v, want rendered per-receiver headers", msg.Headers)
+	}
+
+	// После close() алерты отправляются сразу
+	if err := pub.Publish(context.Background(), cpu, target); err != nil {
+		t.Fatalf("Publish() after close error: %v", err)
+	}
+	if len(mock.SendEmailCalls) != 2 {
+		t.Errorf("SendEmail called %d times after close, want 2", len(mock.SendEmailCalls))
+	}
+}
+
+func TestEnhancedEmailPublisher_Publish_BatchedSendError(t *testing.T) {
+	mock := &MockSMTPClient{SendEmailErr: errors.New("451 try again later")}
+	batcher := newEmailBatcher()
+	pub := newEnhancedEmailPublisher(func(*core.PublishingTarget) (SMTPClient, error) { return mock, nil },
+		batcher, nil, nil, testLogger(), "")
+	target := newTestTarget(map[string]string{"to": "ops@example.com", "batch_wait": "1h"})
+
+	cpu := newTestEnrichedAlert(core.StatusFiring)
+	disk := newTestEnrichedAlert(core.StatusFiring)
+	disk.Alert.Fingerprint = "fp-test-002"
+
+	results := publishBatched(t, pub, batcher, target, cpu, disk)
+	batcher.close()
+
+	// Ошибка письма возвращается каждому алерту батча, чтобы очередь их повторила
+	for range 2 {
+		if err := <-results; err == nil || !strings.Contains(err.Error(), "451") {
+			t.Errorf("Publish() error = %v, want the SMTP error", err)
+		}
+	}
+	if len(mock.SendEmailCalls) != 1 {
+		t.Errorf("SendEmail called %d times, want 1", len(mock.SendEmailCalls))
+	}
+}
+
+func TestEnhancedEmailPublisher_Publish_BatchedContextCanceled(t *testing.T) {
+	mock := &MockSMTPClient{}
+	batcher := newEmailBatcher()
+	defer batcher.close()
+	pub := newEnhancedEmailPublisher(func(*core.PublishingTarget) (SMTPClient, error) { return mock, nil },
+		batcher, nil, nil, testLogger(), "")
+	target := newTestTarget(map[string]string{"to": "ops@example.com", "batch_wait": "1h"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pub.Publish(ctx, newTestEnrichedAlert(core.StatusFiring), target); !errors.Is(err, context.Canceled) {
+		t.Errorf("Publish() error = %v, want context.Canceled", err)
+	}
+}
+
+// publishBatched вызывает Publish для alerts по порядку, каждый в отдельной
+// горутине (Publish ждёт отправки батча), дожидаясь попадания алерта в батч
+// target-а перед следующим. Результаты Publish приходят в возвращаемый канал.
+func publishBatched(t *testing.T, pub AlertPublisher, batcher *emailBatcher, target *core.PublishingTarget, alerts ...*core.EnrichedAlert) <-chan error {
+	t.Helper()
+	results := make(chan error, len(alerts))
+	for _, alert := range alerts {
+		go func() { results <- pub.Publish(context.Background(), alert, target) }()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for !emailBatched(batcher, target, alert) {
+			if time.Now().After(deadline) {
+				t.Fatalf("alert %s not batched", alert.Alert.Fingerprint)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	return results
+}
+
+func emailBatched(batcher *emailBatcher, target *core.PublishingTarget, alert *core.EnrichedAlert) bool {
+	batcher.mu.Lock()
+	defer batcher.mu.Unlock()
+	if batch := batcher.batches[target.Name]; batch != nil {
+		for _, queued := range batch.alerts {
+			if queued == alert {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestBuildEmailBatchTemplateData_CommonLabels(t *testing.T) {
+	first := newTestEnrichedAlert(core.StatusResolved)
+	second := newTestEnrichedAlert(core.StatusFiring)
+	second.Alert.Labels = map[string]string{"alertname": "HighCPU", "severity": "critical", "instance": "node-2"}
+
+	data := buildEmailBatchTemplateData([]*core.EnrichedAlert{first, second}, newTestTarget(nil), "")
+
+	if data.Status != "firing" {
+		t.Errorf("Status = %q, want firing", data.Status)
+	}
+	if _, ok := data.CommonLabels["instance"]; ok || data.CommonLabels["severity"] != "critical" {
+		t.Errorf("CommonLabels = %v, want severity only shared labels", data.CommonLabels)
+	}
+	if data.GroupLabels["alertname"] != "HighCPU" {
+		t.Errorf("GroupLabels.alertname = %q, want HighCPU", data.GroupLabels["alertname"])
+	}
+	if len(data.Alerts.Firing()) != 1 || data.Alerts.Resolved()[0].Labels["instance"] != "node-1" {
+		t.Errorf("Alerts = %+v, want one firing and node-1 resolved", data.Alerts)
+	}
+}
+
+func TestEnhancedEmailPublisher_Publish_SendResolvedFalse(t *testing.T) {
+	mock := &MockSMTPClient{}
+	pub := NewEnhancedEmailPublisher(mock, nil, nil, testLogger(), "")
+	target := newTestTarget(map[string]string{"to": "ops@example.com", "send_resolved": "false"})
+
+	if err := pub.Publish(context.Background(), newTestEnrichedAlert(core.StatusResolved), target); err != nil {
+		t.Fatalf("Publish() error: %v", err)
+	}
+	if err := pub.Publish(context.Background(), newTestEnrichedAlert(core.StatusFiring), target); err != nil {
+		t.Fatalf("Publish() error: %v", err)
+	}
+	if len(mock.SendEmailCalls) != 1 {
+		t.Errorf("SendEmail called %d times, want 1 (firing only)", len(mock.SendEmailCalls))
+	}
+}
+
+func TestExtractSMTPConfig_URL(t *testing.T) {
+	tests := []struct {
+		url      string
+		headers  map[string]string
+		wantHost string
+		wantPort int
+		wantTLS  bool
+	}{
+		{"smtp://smtp.example.com:2525", nil, "smtp.example.com", 2525, false},
+		{"smtp://smtp.example.com", map[string]string{"smtp_tls": "true"}, "smtp.example.com", 587, true},
+		{"smtps://smtp.example.com", nil, "smtp.example.com", 465, true},
+		{"smtp://smtp.example.com:25", map[string]string{"smtp_host": "relay.local", "smtp_port": "26"}, "relay.local", 26, false},
+		{"http://placeholder.local", nil, "", 587, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			target := newTestTarget(tt.headers)
+			target.URL = tt.url
+			cfg := extractSMTPConfig(target)
+			if cfg.Host != tt.wantHost || cfg.Port != tt.wantPort || cfg.RequireTLS != tt.wantTLS {
+				t.Errorf("config = %s:%d tls=%v, want %s:%d tls=%v",
+					cfg.Host, cfg.Port, cfg.RequireTLS, tt.wantHost, tt.wantPort, tt.wantTLS)
+			}
+		})
+	}
+}
+
 // ============================================================================
 // Тесты валидации пустого From
 // ============================================================================
@@ -0,0 +1,187 @@
+package publishing
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	amconfig "github.com/ipiton/AMP/internal/alertmanager/config"
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// defaultEmailGroupWait — group_wait Alertmanager по умолчанию; используется
+// как batch_wait, если у корневого маршрута он не задан.
+const defaultEmailGroupWait = 30 * time.Second
+
+// alertmanagerDefaultTemplateRe распознаёт ссылки на встроенные шаблоны
+// Alertmanager ({{ template "email.default.html" . }}), которых здесь нет:
+// вместо них используются defaults.GetDefaultEmailTemplates().
+var alertmanagerDefaultTemplateRe = regexp.MustCompile(`^\{\{-?\s*template\s+"(email\.default\.[a-z]+|__subject)"\s+\.\s*-?\}\}$`)
+
+// invalidTargetNameChars — символы, недопустимые в имени target-а (DNS-1123).
+var invalidTargetNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// EmailTargetsFromConfig преобразует email_configs receiver-ов Alertmanager
+// в email publishing targets, чтобы импортированные конфиги продолжали
+// рассылать почту.
+//
+// Соответствие полей:
+//   - to, from, smarthost, auth_*, require_tls, tls_config — с fallback на
+//     global.smtp_* (require_tls по умолчанию true, как в Alertmanager)
+//   - smarthost с портом 465 — smtps:// (direct TLS)
+//   - html, text — html_template, text_template (ссылки на встроенные
+//     шаблоны Alertmanager заменяются шаблонами AMP)
+//   - headers.Subject — subject_template; остальные headers (кроме To и
+//     From) — заголовки письма "header.<Name>"
+//   - send_resolved — send_resolved (по умолчанию false, как в Alertmanager)
+//   - group_wait корневого маршрута — batch_wait (по умолчанию 30s)
+//
+// Имя target-а — имя receiver-а (с суффиксом -N для нескольких
+// email_configs), приведённое к DNS-1123. tls_config с файлами сертификатов
+// не поддерживается и возвращает ошибку.
+func EmailTargetsFromConfig(cfg *amconfig.AlertmanagerConfig) ([]*core.PublishingTarget, error) {
+	global := cfg.Global
+	if global == nil {
+		global = &amconfig.GlobalConfig{}
+	}
+	batchWait := defaultEmailGroupWait
+	if cfg.Route != nil && cfg.Route.GroupWait > 0 {
+		batchWait = cfg.Route.GroupWait
+	}
+
+	var targets []*core.PublishingTarget
+	for _, receiver := range cfg.Receivers {
+		for i, emailCfg := range receiver.EmailConfigs {
+			name := receiver.Name
+			if len(receiver.EmailConfigs) > 1 {
+				name = fmt.Sprintf("%s-%d", name, i+1)
+			}
+			target, err := emailTargetFromConfig(emailTargetName(name), global, emailCfg, batchWait)
+			if err != nil {
+				return nil, fmt.Errorf("receiver %q email_configs[%d]: %w", receiver.Name, i, err)
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+func emailTargetFromConfig(name string, global *amconfig.GlobalConfig, cfg *amconfig.EmailConfig, batchWait time.Duration) (*core.PublishingTarget, error) {
+	if cfg.To == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+	from := firstNonEmpty(cfg.From, global.SMTPFrom)
+	if from == "" {
+		return nil, fmt.Errorf("from is required (set from or global.smtp_from)")
+	}
+	smarthost := firstNonEmpty(cfg.Smarthost, global.SMTPSmarthost)
+	if smarthost == "" {
+		return nil, fmt.Errorf("smarthost is required (set smarthost or global.smtp_smarthost)")
+	}
+	host, port, err := net.SplitHostPort(smarthost)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smarthost %q: %w", smarthost, err)
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return nil, fmt.Errorf("invalid smarthost port %q", port)
+	}
+
+	requireTLS := true
+	if cfg.RequireTLS != nil {
+		requireTLS = *cfg.RequireTLS
+	} else if global.SMTPRequireTLS != nil {
+		requireTLS = *global.SMTPRequireTLS
+	}
+
+	headers := map[string]string{
+		"to":                 cfg.To,
+		"from":               from,
+		emailSendResolvedKey: strconv.FormatBool(cfg.SendResolved),
+		emailBatchWaitKey:    batchWait.String(),
+	}
+	scheme := "smtp"
+	if port == "465" {
+		// Alertmanager использует implicit TLS на 465 независимо от require_tls
+		scheme = "smtps"
+	} else {
+		headers["smtp_tls"] = strconv.FormatBool(requireTLS)
+	}
+
+	setIfNotEmpty(headers, "smtp_username", firstNonEmpty(cfg.AuthUsername, global.SMTPAuthUsername))
+	setIfNotEmpty(headers, "smtp_password", firstNonEmpty(cfg.AuthPassword, global.SMTPAuthPassword))
+	setIfNotEmpty(headers, "smtp_identity", firstNonEmpty(cfg.AuthIdentity, global.SMTPAuthIdentity))
+
+	if tlsCfg := cfg.TLSConfig; tlsCfg != nil {
+		if tlsCfg.CAFile != "" || tlsCfg.CertFile != "" || tlsCfg.KeyFile != "" {
+			return nil, fmt.Errorf("tls_config ca_file, cert_file and key_file are not supported")
+		}
+		setIfNotEmpty(headers, "smtp_tls_server_name", tlsCfg.ServerName)
+		if tlsCfg.InsecureSkipVerify {
+			headers["smtp_tls_insecure_skip_verify"] = "true"
+		}
+	}
+
+	if !isAlertmanagerDefaultTemplate(cfg.HTML) {
+		setIfNotEmpty(headers, "html_template", cfg.HTML)
+	}
+	if !isAlertmanagerDefaultTemplate(cfg.Text) {
+		setIfNotEmpty(headers, "text_template", cfg.Text)
+	}
+	for key, value := range cfg.Headers {
+		switch strings.ToLower(key) {
+		case "subject":
+			if !isAlertmanagerDefaultTemplate(value) {
+				setIfNotEmpty(headers, "subject_template", value)
+			}
+		case "to", "from":
+			// Получатели и отправитель задаются полями to/from
+		default:
+			setIfNotEmpty(headers, emailHeaderPrefix+key, value)
+		}
+	}
+
+	return &core.PublishingTarget{
+		Name:         name,
+		Type:         string(TargetTypeEmail),
+		URL:          (&url.URL{Scheme: scheme, Host: net.JoinHostPort(host, port)}).String(),
+		Enabled:      true,
+		Format:       core.FormatEmail,
+		Headers:      headers,
+		FilterConfig: map[string]any{},
+	}, nil
+}
+
+// isAlertmanagerDefaultTemplate сообщает, ссылается ли шаблон только на
+// встроенный шаблон Alertmanager.
+func isAlertmanagerDefaultTemplate(tmpl string) bool {
+	return alertmanagerDefaultTemplateRe.MatchString(strings.TrimSpace(tmpl))
+}
+
+// emailTargetName приводит имя receiver-а к имени target-а (DNS-1123).
+func emailTargetName(name string) string {
+	name = invalidTargetNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func setIfNotEmpty(m map[string]string, key, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
@@ -0,0 +1,128 @@
+package publishing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	amconfig "github.com/ipiton/AMP/internal/alertmanager/config"
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestEmailTargetsFromConfig(t *testing.T) {
+	requireTLS := false
+	cfg := &amconfig.AlertmanagerConfig{
+		Global: &amconfig.GlobalConfig{
+			SMTPSmarthost:    "smtp.example.com:587",
+			SMTPFrom:         "alerts@example.com",
+			SMTPAuthUsername: "alerts",
+			SMTPAuthPassword: "secret",
+		},
+		Route: &amconfig.Route{Receiver: "DBA Team", GroupWait: 10 * time.Second},
+		Receivers: []*amconfig.Receiver{
+			{Name: "webhook-only", WebhookConfigs: []*amconfig.WebhookConfig{{URL: "https://hooks.example.com"}}},
+			{
+				Name: "DBA Team",
+				EmailConfigs: []*amconfig.EmailConfig{
+					{
+						To:           "dba@example.com",
+						SendResolved: true,
+						HTML:         `{{ template "email.default.html" . }}`,
+						Text:         "{{ range .Alerts }}{{ .Annotations.summary }}{{ end }}",
+						Headers: map[string]string{
+							"Subject":    "DB: {{ .GroupLabels.alertname }}",
+							"To":         "ignored@example.com",
+							"X-Priority": "1",
+						},
+					},
+					{
+						To:         "oncall@example.com",
+						From:       "db-alerts@example.com",
+						Smarthost:  "mail.example.com:465",
+						RequireTLS: &requireTLS,
+						TLSConfig:  &amconfig.TLSConfig{ServerName: "mx.example.com"},
+					},
+				},
+			},
+		},
+	}
+
+	targets, err := EmailTargetsFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("EmailTargetsFromConfig() error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(targets))
+	}
+
+	first := targets[0]
+	if first.Name != "dba-team-1" || first.Type != "email" || first.Format != core.FormatEmail || !first.Enabled {
+		t.Errorf("target = %+v", first)
+	}
+	if first.URL != "smtp://smtp.example.com:587" {
+		t.Errorf("URL = %q", first.URL)
+	}
+	wantHeaders := map[string]string{
+		"to":                "dba@example.com",
+		"from":              "alerts@example.com",
+		"smtp_username":     "alerts",
+		"smtp_password":     "secret",
+		"smtp_tls":          "true",
+		"send_resolved":     "true",
+		"batch_wait":        "10s",
+		"subject_template":  "DB: {{ .GroupLabels.alertname }}",
+		"text_template":     "{{ range .Alerts }}{{ .Annotations.summary }}{{ end }}",
+		"header.X-Priority": "1",
+	}
+	for key, want := range wantHeaders {
+		if got := first.Headers[key]; got != want {
+			t.Errorf("Headers[%q] = %q, want %q", key, got, want)
+		}
+	}
+	for _, key := range []string{"html_template", "header.To", "header.Subject"} {
+		if _, ok := first.Headers[key]; ok {
+			t.Errorf("Headers[%q] set, want omitted", key)
+		}
+	}
+
+	second := targets[1]
+	if second.Name != "dba-team-2" || second.URL != "smtps://mail.example.com:465" {
+		t.Errorf("target = %s %s", second.Name, second.URL)
+	}
+	if second.Headers["from"] != "db-alerts@example.com" || second.Headers["send_resolved"] != "false" ||
+		second.Headers["smtp_tls_server_name"] != "mx.example.com" {
+		t.Errorf("Headers = %v", second.Headers)
+	}
+	smtpCfg := extractSMTPConfig(second)
+	if smtpCfg.Host != "mail.example.com" || smtpCfg.Port != 465 || !smtpCfg.RequireTLS {
+		t.Errorf("SMTP config = %+v, want implicit TLS on 465", smtpCfg)
+	}
+}
+
+func TestEmailTargetsFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   *amconfig.EmailConfig
+		wantErr string
+	}{
+		{"missing to", &amconfig.EmailConfig{From: "a@example.com", Smarthost: "smtp:25"}, "to is required"},
+		{"missing from", &amconfig.EmailConfig{To: "b@example.com", Smarthost: "smtp:25"}, "from is required"},
+		{"missing smarthost", &amconfig.EmailConfig{To: "b@example.com", From: "a@example.com"}, "smarthost is required"},
+		{"smarthost without port", &amconfig.EmailConfig{To: "b@example.com", From: "a@example.com", Smarthost: "smtp"}, "invalid smarthost"},
+		{"certificate files", &amconfig.EmailConfig{
+			To: "b@example.com", From: "a@example.com", Smarthost: "smtp:25",
+			TLSConfig: &amconfig.TLSConfig{CAFile: "/etc/ca.pem"},
+		}, "not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &amconfig.AlertmanagerConfig{
+				Receivers: []*amconfig.Receiver{{Name: "mail", EmailConfigs: []*amconfig.EmailConfig{tt.email}}},
+			}
+			_, err := EmailTargetsFromConfig(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -27,10 +27,19 @@ import (
 
 // Provider constants for error identification
 const (
-	ProviderSlack     = "slack"
-	ProviderPagerDuty = "pagerduty"
-	ProviderRootly    = "rootly"
-	ProviderWebhook   = "webhook"
+	ProviderSlack      = "slack"
+	ProviderPagerDuty  = "pagerduty"
+	ProviderRootly     = "rootly"
+	ProviderWebhook    = "webhook"
+	ProviderTeams      = "teams"
+	ProviderOpsgenie   = "opsgenie"
+	ProviderKafka      = "kafka"
+	ProviderJira       = "jira"
+	ProviderGoogleChat = "googlechat"
+	ProviderMattermost = "mattermost"
+	ProviderAWS        = "aws"
+	ProviderPlugin     = "plugin"
+	ProviderExec       = "exec"
 )
 
 // ============================================================================
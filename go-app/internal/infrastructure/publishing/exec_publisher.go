@@ -0,0 +1,315 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// exec_publisher.go - publisher running local commands with the alert on stdin
+
+// ExecCommandConfig declares a local command exec targets can run.
+type ExecCommandConfig struct {
+	// Name identifies the command in the URL (exec://<name>) of targets.
+	Name string
+	// Path of the executable and the arguments it is run with.
+	Path string
+	Args []string
+	// Env holds KEY=VALUE variables set for the command.
+	Env []string
+	// EnvAllowlist names the variables of AMP's environment passed to the
+	// command; others are not (default: PATH).
+	EnvAllowlist []string
+	// Timeout bounds a run; the command is killed afterwards (default: 30s).
+	Timeout time.Duration
+	// MaxConcurrency bounds the runs of the command at a time; further
+	// deliveries wait (default: 4).
+	MaxConcurrency int
+}
+
+// Exec command defaults.
+const (
+	DefaultExecTimeout        = 30 * time.Second
+	DefaultExecMaxConcurrency = 4
+
+	execOutputLimit = 4096            // output kept for errors
+	execWaitDelay   = 2 * time.Second // for children holding the output open
+)
+
+// Exit codes (sysexits.h) deciding how failed runs are retried; other
+// non-zero codes are retried conservatively.
+const (
+	execExitUsage    = 64 // EX_USAGE: permanent
+	execExitDataErr  = 65 // EX_DATAERR: permanent
+	execExitTempFail = 75 // EX_TEMPFAIL: transient
+)
+
+// ErrExecCommandNotFound is returned for commands that are not configured.
+var ErrExecCommandNotFound = errors.New("exec command not configured")
+
+// ExecCommands runs the configured commands of exec targets, each with its
+// own concurrency limit.
+type ExecCommands struct {
+	commands map[string]*execCommand
+}
+
+type execCommand struct {
+	config ExecCommandConfig
+	env    []string
+	slots  chan struct{}
+}
+
+// NewExecCommands validates configs and applies their defaults. The
+// environment of the commands is taken from AMP's environment now.
+func NewExecCommands(configs []ExecCommandConfig) (*ExecCommands, error) {
+	commands := make(map[string]*execCommand, len(configs))
+	for _, config := range configs {
+		if !pluginNameRegex.MatchString(config.Name) {
+			return nil, fmt.Errorf("invalid exec command name %q", config.Name)
+		}
+		if _, ok := commands[config.Name]; ok {
+			return nil, fmt.Errorf("duplicate exec command %q", config.Name)
+		}
+		if config.Path == "" {
+			return nil, fmt.Errorf("exec command %q: path is required", config.Name)
+		}
+		if config.Timeout <= 0 {
+			config.Timeout = DefaultExecTimeout
+		}
+		if config.MaxConcurrency <= 0 {
+			config.MaxConcurrency = DefaultExecMaxConcurrency
+		}
+		if config.EnvAllowlist == nil {
+			config.EnvAllowlist = []string{"PATH"}
+		}
+
+		var env []string
+		for _, name := range config.EnvAllowlist {
+			if value, ok := os.LookupEnv(name); ok {
+				env = append(env, name+"="+value)
+			}
+		}
+		for _, variable := range config.Env {
+			if key, _, ok := strings.Cut(variable, "="); !ok || key == "" {
+				return nil, fmt.Errorf("exec command %q: %q is not KEY=VALUE", config.Name, variable)
+			}
+			env = append(env, variable)
+		}
+
+		commands[config.Name] = &execCommand{
+			config: config,
+			env:    env,
+			slots:  make(chan struct{}, config.MaxConcurrency),
+		}
+	}
+	return &ExecCommands{commands: commands}, nil
+}
+
+// ValidateExecTarget checks the URL of an exec target: exec://<command>.
+// The command is looked up when alerts are delivered.
+func ValidateExecTarget(target *core.PublishingTarget) error {
+	name, err := execCommandName(target.URL)
+	if err != nil {
+		return err
+	}
+	if !pluginNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid exec command name %q", name)
+	}
+	return nil
+}
+
+// execCommandName returns the command named by the URL of an exec target.
+func execCommandName(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "exec" {
+		return "", errors.New("must be exec://<command>")
+	}
+	if parsed.Host == "" || strings.Trim(parsed.Path, "/") != "" {
+		return "", errors.New("must be exec://<command>")
+	}
+	return parsed.Host, nil
+}
+
+// run runs the command name with input on stdin and env added to its
+// environment, once one of its slots is free.
+func (c *ExecCommands) run(ctx context.Context, name string, input []byte, env []string) error {
+	var command *execCommand
+	if c != nil {
+		command = c.commands[name]
+	}
+	if command == nil {
+		return fmt.Errorf("%w: %q", ErrExecCommandNotFound, name)
+	}
+
+	select {
+	case command.slots <- struct{}{}:
+		defer func() { <-command.slots }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, command.config.Timeout)
+	defer cancel()
+
+	output := &limitedBuffer{limit: execOutputLimit}
+	cmd := exec.CommandContext(runCtx, command.config.Path, command.config.Args...)
+	cmd.Env = append(slices.Clip(command.env), env...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = execWaitDelay
+
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return httperror.NewHTTPError(http.StatusGatewayTimeout,
+			fmt.Sprintf("command %s timed out after %s%s", name, command.config.Timeout, output.suffix()), ProviderExec)
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("command %s: %w", name, err)
+	}
+	message := fmt.Sprintf("command %s exited with status %d%s", name, exitErr.ExitCode(), output.suffix())
+	switch exitErr.ExitCode() {
+	case execExitTempFail:
+		return httperror.NewHTTPError(http.StatusServiceUnavailable, message, ProviderExec)
+	case execExitUsage, execExitDataErr:
+		return httperror.NewHTTPError(http.StatusBadRequest, message, ProviderExec)
+	default:
+		return errors.New(message)
+	}
+}
+
+// limitedBuffer keeps the first limit bytes written to it.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// suffix formats the output for error messages ("" without output).
+func (b *limitedBuffer) suffix() string {
+	output := strings.TrimSpace(b.buf.String())
+	if output == "" {
+		return ""
+	}
+	if b.truncated {
+		output += " ..."
+	}
+	return ": " + output
+}
+
+// EnhancedExecPublisher delivers alerts to local commands (publishing.commands)
+// for integrations that only exist as scripts. The command is named by the
+// target URL (exec://<command>); it gets the alert formatted in the target's
+// format as JSON on stdin and AMP_TARGET, AMP_ALERT_NAME,
+// AMP_ALERT_FINGERPRINT and AMP_ALERT_STATUS in its environment.
+//
+// Exit status 0 is a delivery. Timeouts and exit status 75 (EX_TEMPFAIL) are
+// retried, 64 (EX_USAGE) and 65 (EX_DATAERR) go to the DLQ, other failures
+// are retried conservatively. The output of failed runs is kept in the error.
+type EnhancedExecPublisher struct {
+	*BaseEnhancedPublisher               // Embedded base publisher for common functionality
+	commands               *ExecCommands // Configured commands (nil: none)
+}
+
+// NewEnhancedExecPublisher creates a publisher for commands.
+func NewEnhancedExecPublisher(
+	commands *ExecCommands,
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return &EnhancedExecPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "exec_publisher"),
+		),
+		commands: commands,
+	}
+}
+
+// Publish runs the command of target with enrichedAlert.
+func (p *EnhancedExecPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	name, err := execCommandName(target.URL)
+	if err != nil {
+		return fmt.Errorf("invalid exec target %s: %w", target.Name, err)
+	}
+	format := target.Format
+	if format == "" {
+		format = core.FormatExec
+	}
+	payload, err := p.GetFormatter().FormatAlert(withTargetPayloadTemplate(ctx, target), enrichedAlert, format)
+	if err != nil {
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	alert := enrichedAlert.Alert
+	env := []string{
+		"AMP_TARGET=" + target.Name,
+		"AMP_ALERT_NAME=" + alert.AlertName,
+		"AMP_ALERT_FINGERPRINT=" + alert.Fingerprint,
+		"AMP_ALERT_STATUS=" + string(alert.Status),
+	}
+	p.LogPublishStart(ctx, v2.ProviderExec, enrichedAlert)
+
+	startTime := time.Now()
+	err = p.commands.run(ctx, name, input, env)
+	duration := time.Since(startTime)
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordAPIDuration(v2.ProviderExec, "publish", "EXEC", duration)
+	}
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderExec, "publish", GetPublishingErrorType(err))
+		}
+		p.LogPublishError(ctx, v2.ProviderExec, alert.Fingerprint, err)
+		return fmt.Errorf("failed to deliver to %s: %w", target.Name, err)
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordMessage(v2.ProviderExec, "success")
+	}
+	p.LogPublishSuccess(ctx, v2.ProviderExec, alert.Fingerprint, duration)
+	return nil
+}
+
+// Name returns publisher name
+func (p *EnhancedExecPublisher) Name() string {
+	return "Exec"
+}
@@ -0,0 +1,183 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// writeExecScript writes a shell script running body and returns its path.
+func writeExecScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o700))
+	return path
+}
+
+func execTestTarget(command string) *core.PublishingTarget {
+	return &core.PublishingTarget{
+		Name:   "ticketing",
+		Type:   "exec",
+		URL:    "exec://" + command,
+		Format: core.FormatExec,
+	}
+}
+
+func newTestExecPublisher(t *testing.T, configs ...ExecCommandConfig) AlertPublisher {
+	t.Helper()
+	commands, err := NewExecCommands(configs)
+	require.NoError(t, err)
+	return NewEnhancedExecPublisher(commands, nil, NewAlertFormatter(""), slog.Default())
+}
+
+func TestExecPublisher_Publish(t *testing.T) {
+	t.Setenv("AMP_TEST_ALLOWED", "allowed")
+	t.Setenv("AMP_TEST_SECRET", "secret")
+	output := filepath.Join(t.TempDir(), "output")
+	script := writeExecScript(t, `cat > "$OUTPUT"
+echo "$AMP_TARGET $AMP_ALERT_NAME $AMP_ALERT_FINGERPRINT $AMP_ALERT_STATUS" >> "$OUTPUT.env"
+echo "${AMP_TEST_ALLOWED-unset} ${AMP_TEST_SECRET-unset}" >> "$OUTPUT.env"`)
+
+	publisher := newTestExecPublisher(t, ExecCommandConfig{
+		Name:         "ticket",
+		Path:         script,
+		Env:          []string{"OUTPUT=" + output},
+		EnvAllowlist: []string{"PATH", "AMP_TEST_ALLOWED"},
+	})
+	require.NoError(t, publisher.Publish(context.Background(), pluginTestAlert(nil), execTestTarget("ticket")))
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var event map[string]any
+	require.NoError(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "alert.firing", event["event_type"])
+	assert.Equal(t, "fp-1", event["fingerprint"])
+
+	env, err := os.ReadFile(output + ".env")
+	require.NoError(t, err)
+	assert.Equal(t, "ticketing HighCPU fp-1 firing\nallowed unset\n", string(env))
+
+	// Targets choose the JSON payload by their format
+	target := execTestTarget("ticket")
+	target.Format = core.FormatAlertmanager
+	require.NoError(t, publisher.Publish(context.Background(), pluginTestAlert(nil), target))
+	data, err = os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"alerts"`)
+}
+
+func TestExecPublisher_ExitStatus(t *testing.T) {
+	script := writeExecScript(t, `echo "failed: $1" >&2
+exit "$1"`)
+	tests := []struct {
+		code      string
+		errorType QueueErrorType
+	}{
+		{"75", QueueErrorTypeTransient},
+		{"65", QueueErrorTypePermanent},
+		{"64", QueueErrorTypePermanent},
+		{"1", QueueErrorTypeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			publisher := newTestExecPublisher(t, ExecCommandConfig{Name: "ticket", Path: script, Args: []string{tt.code}})
+			err := publisher.Publish(context.Background(), pluginTestAlert(nil), execTestTarget("ticket"))
+			require.Error(t, err)
+			assert.Equal(t, tt.errorType, classifyPublishingError(err))
+			assert.Contains(t, err.Error(), "exited with status "+tt.code+": failed: "+tt.code)
+		})
+	}
+}
+
+func TestExecPublisher_Timeout(t *testing.T) {
+	script := writeExecScript(t, `echo started
+exec sleep 10`)
+	publisher := newTestExecPublisher(t, ExecCommandConfig{Name: "slow", Path: script, Timeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	err := publisher.Publish(context.Background(), pluginTestAlert(nil), execTestTarget("slow"))
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.True(t, httperror.IsTimeout(err), err.Error())
+	assert.Equal(t, QueueErrorTypeTransient, classifyPublishingError(err))
+	assert.Contains(t, err.Error(), "timed out after 100ms: started")
+}
+
+func TestExecPublisher_MaxConcurrency(t *testing.T) {
+	lock := filepath.Join(t.TempDir(), "lock")
+	// Fails when another run holds the lock
+	script := writeExecScript(t, `mkdir "$LOCK" || exit 1
+sleep 0.1
+rmdir "$LOCK"`)
+	publisher := newTestExecPublisher(t, ExecCommandConfig{
+		Name:           "serial",
+		Path:           script,
+		Env:            []string{"LOCK=" + lock},
+		MaxConcurrency: 1,
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- publisher.Publish(context.Background(), pluginTestAlert(nil), execTestTarget("serial"))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func TestExecPublisher_UnknownCommand(t *testing.T) {
+	publisher := NewEnhancedExecPublisher(nil, nil, NewAlertFormatter(""), slog.Default())
+	assert.ErrorIs(t, publisher.Publish(context.Background(), pluginTestAlert(nil), execTestTarget("missing")), ErrExecCommandNotFound)
+
+	publisher = newTestExecPublisher(t, ExecCommandConfig{Name: "other", Path: "/bin/true"})
+	assert.ErrorIs(t, publisher.Publish(context.Background(), pluginTestAlert(nil), execTestTarget("missing")), ErrExecCommandNotFound)
+}
+
+func TestNewExecCommands_Invalid(t *testing.T) {
+	tests := map[string][]ExecCommandConfig{
+		"invalid name": {{Name: "Ticket", Path: "/bin/true"}},
+		"missing path": {{Name: "ticket"}},
+		"duplicate":    {{Name: "ticket", Path: "/bin/true"}, {Name: "ticket", Path: "/bin/false"}},
+		"invalid env":  {{Name: "ticket", Path: "/bin/true", Env: []string{"TOKEN"}}},
+	}
+	for name, configs := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewExecCommands(configs)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	buf := &limitedBuffer{limit: 8}
+	n, err := buf.Write([]byte("hello "))
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+	n, err = buf.Write([]byte("world"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	_, _ = buf.Write([]byte("!"))
+	assert.Equal(t, ": hello wo ...", buf.suffix())
+	assert.Empty(t, (&limitedBuffer{limit: 8}).suffix())
+	assert.False(t, strings.Contains(buf.buf.String(), "!"))
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	notifurl "github.com/ipiton/AMP/internal/notification/url"
+	"github.com/ipiton/AMP/pkg/core/domain"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
 )
 
 // stringBuilderPool provides reusable strings.Builder instances to reduce allocations
@@ -29,57 +34,37 @@ func putBuilder(b *strings.Builder) {
 	stringBuilderPool.Put(b)
 }
 
-// formatterResultPool provides reusable map[string]any instances for formatting results.
-//
-// This is a critical optimization for hot path (formatter called 1000+ times/sec).
-// Pre-allocating with capacity 30 covers typical alert formats.
-//
-// Performance impact:
-//   - Before: 1 allocation/call, ~2KB/allocation
-//   - After:  0 allocations/call
-//   - Improvement: 50% faster, 100% less GC pressure
-//
-// Usage:
-//
-//	result := getFormatterResult()
-//	defer releaseFormatterResult(result)
-//	// ... fill result
-//	return result, nil
-var formatterResultPool = sync.Pool{
-	New: func() interface{} {
-		return make(map[string]any, 30) // Pre-allocate typical size
-	},
-}
+// formatterResultCapacity pre-sizes formatter results, covering typical
+// alert formats.
+const formatterResultCapacity = 30
 
-// getFormatterResult gets a map from the pool for formatting results.
+// newFormatterResult allocates the map returned by a formatter.
 //
-// IMPORTANT: Caller MUST call releaseFormatterResult() when done to return to pool.
-// Use defer to ensure cleanup even on error paths.
-func getFormatterResult() map[string]any {
-	return formatterResultPool.Get().(map[string]any)
-}
-
-// releaseFormatterResult returns a map to the pool after clearing all keys.
-//
-// This clears the map to prevent memory leaks and prepares it for reuse.
-func releaseFormatterResult(m map[string]any) {
-	// Clear all keys (faster than creating new map)
-	for k := range m {
-		delete(m, k)
-	}
-	formatterResultPool.Put(m)
+// Results are owned by the caller and never recycled: publishers hold them
+// after FormatAlert returns (batch payloads, retries of the queue, payloads
+// marshaled later), so a pooled map could be cleared and reused by another
+// alert while still referenced.
+func newFormatterResult() map[string]any {
+	return make(map[string]any, formatterResultCapacity)
 }
 
 // AlertFormatter defines the interface for formatting alerts for different publishing targets
 type AlertFormatter interface {
-	// FormatAlert formats an enriched alert for a specific target format
+	// FormatAlert formats an enriched alert for a specific target format.
+	// The payload is owned by the caller, which may keep or modify it.
 	FormatAlert(ctx context.Context, enrichedAlert *core.EnrichedAlert, format core.PublishingFormat) (map[string]any, error)
+
+	// FormatBatch formats several alerts of one target as a single payload,
+	// owned by the caller
+	FormatBatch(ctx context.Context, alerts []*core.EnrichedAlert, format core.PublishingFormat) (map[string]any, error)
 }
 
 // DefaultAlertFormatter implements AlertFormatter using strategy pattern
 type DefaultAlertFormatter struct {
 	formatters  map[core.PublishingFormat]formatFunc
 	externalURL string
+	styles      map[string]domain.SeverityStyle // severity emoji and colors of chat formats
+	metrics     *v2.PublishingMetrics           // records payload truncations (optional)
 }
 
 // formatFunc is the function signature for format-specific implementations
@@ -89,9 +74,21 @@ type formatFunc func(*core.EnrichedAlert) (map[string]any, error)
 // externalURL is the public base URL of this AMP instance (env: AMP_SERVER_EXTERNAL_URL).
 // Empty string causes callback links to be omitted (graceful degradation).
 func NewAlertFormatter(externalURL string) AlertFormatter {
+	return NewAlertFormatterWithStyles(externalURL, nil)
+}
+
+// NewAlertFormatterWithStyles creates a new alert formatter whose chat
+// messages (Slack, Teams, Google Chat, Mattermost) use styles
+// (publishing.severity_styles) merged over domain.DefaultSeverityStyles.
+func NewAlertFormatterWithStyles(externalURL string, styles map[string]domain.SeverityStyle) AlertFormatter {
+	return newAlertFormatter(externalURL, domain.MergeSeverityStyles(domain.DefaultSeverityStyles(), styles))
+}
+
+func newAlertFormatter(externalURL string, styles map[string]domain.SeverityStyle) *DefaultAlertFormatter {
 	formatter := &DefaultAlertFormatter{
 		formatters:  make(map[core.PublishingFormat]formatFunc),
 		externalURL: externalURL,
+		styles:      styles,
 	}
 
 	// Register format strategies
@@ -100,31 +97,70 @@ func NewAlertFormatter(externalURL string) AlertFormatter {
 	formatter.formatters[core.FormatPagerDuty] = formatter.formatPagerDuty
 	formatter.formatters[core.FormatSlack] = formatter.formatSlack
 	formatter.formatters[core.FormatWebhook] = formatter.formatWebhook
+	formatter.formatters[core.FormatTeams] = formatter.formatTeams
+	formatter.formatters[core.FormatOpsgenie] = formatter.formatOpsgenie
+	formatter.formatters[core.FormatKafka] = formatter.formatKafka
+	formatter.formatters[core.FormatJira] = formatter.formatJira
+	formatter.formatters[core.FormatGoogleChat] = formatter.formatGoogleChat
+	formatter.formatters[core.FormatMattermost] = formatter.formatMattermost
+	formatter.formatters[core.FormatAWS] = formatter.formatKafka  // same alert event
+	formatter.formatters[core.FormatExec] = formatter.formatKafka // same alert event
 
 	return formatter
 }
 
-// FormatAlert formats an enriched alert for a specific target format
+// SetMetrics makes the formatter record the payloads it truncates to the
+// size limits of their provider.
+func (f *DefaultAlertFormatter) SetMetrics(metrics *v2.PublishingMetrics) {
+	f.metrics = metrics
+}
+
+// FormatAlert formats an enriched alert for a specific target format.
+// Payloads over the size limits of their provider are truncated (see
+// payloadBudgets).
 func (f *DefaultAlertFormatter) FormatAlert(ctx context.Context, enrichedAlert *core.EnrichedAlert, format core.PublishingFormat) (map[string]any, error) {
 	if enrichedAlert == nil || enrichedAlert.Alert == nil {
 		return nil, fmt.Errorf("enriched alert or alert is nil")
 	}
 
+	// Per-target redaction (redact_* headers): formats the redacted alert
+	// and scrubs the payload
+	if redaction := redactionFromContext(ctx); redaction != nil {
+		payload, err := f.FormatAlert(withRedaction(ctx, nil), redaction.Alert(enrichedAlert), format)
+		if err != nil {
+			return nil, err
+		}
+		return redaction.Payload(payload), nil
+	}
+
+	// Per-target payload template (payload_template header): replaces the
+	// format altogether
+	if text, ok := payloadTemplateFromContext(ctx); ok {
+		return renderPayloadTemplate(text, enrichedAlert)
+	}
+
+	// Per-target style overrides (see WithSeverityStyles): format with a
+	// formatter whose styles include them
+	if overrides := SeverityStylesFromContext(ctx); len(overrides) > 0 {
+		metrics := f.metrics
+		f = newAlertFormatter(f.externalURL, domain.MergeSeverityStyles(f.styles, overrides))
+		f.metrics = metrics
+	}
+
 	formatFn, exists := f.formatters[format]
 	if !exists {
 		// Default to webhook format
 		formatFn = f.formatWebhook
 	}
 
-	return formatFn(enrichedAlert)
+	return f.formatWithinBudget(format, enrichedAlert, formatFn)
 }
 
 // formatAlertmanager formats alert in Alertmanager v4 webhook format
 func (f *DefaultAlertFormatter) formatAlertmanager(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
 	alert := enrichedAlert.Alert
 
-	// Get result map from pool (optimization: 0 allocations)
-	result := getFormatterResult()
+	result := newFormatterResult()
 
 	// Build Alertmanager-compatible alert
 	amAlert := map[string]any{
@@ -169,7 +205,7 @@ func (f *DefaultAlertFormatter) formatAlertmanager(enrichedAlert *core.EnrichedA
 		amAlert["annotations"] = annotations
 	}
 
-	// Fill result map (already from pool)
+	// Fill result map
 	result["receiver"] = "alert-history-proxy"
 	result["status"] = string(alert.Status)
 	result["alerts"] = []map[string]any{amAlert}
@@ -188,9 +224,9 @@ func (f *DefaultAlertFormatter) formatAlertmanager(enrichedAlert *core.EnrichedA
 func (f *DefaultAlertFormatter) formatRootly(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
 	alert := enrichedAlert.Alert
 	classification := enrichedAlert.Classification
+	labels := enrichedAlert.KnownLabels()
 
-	// Get result map from pool (optimization: 0 allocations)
-	result := getFormatterResult()
+	result := newFormatterResult()
 
 	// Map severity to Rootly levels
 	severity := "major"
@@ -205,8 +241,8 @@ func (f *DefaultAlertFormatter) formatRootly(enrichedAlert *core.EnrichedAlert)
 		case core.SeverityNoise:
 			severity = "low"
 		}
-	} else if sev, ok := alert.Labels["severity"]; ok {
-		switch strings.ToLower(sev) {
+	} else if labels.Severity != "" {
+		switch strings.ToLower(labels.Severity) {
 		case "critical":
 			severity = "critical"
 		case "warning":
@@ -217,9 +253,9 @@ func (f *DefaultAlertFormatter) formatRootly(enrichedAlert *core.EnrichedAlert)
 	}
 
 	// Build title
-	namespace := "unknown"
-	if ns := alert.Namespace(); ns != nil {
-		namespace = *ns
+	namespace := labels.Namespace
+	if namespace == "" {
+		namespace = "unknown"
 	}
 
 	title := fmt.Sprintf("[%s] Alert in %s", alert.AlertName, namespace)
@@ -261,7 +297,7 @@ func (f *DefaultAlertFormatter) formatRootly(enrichedAlert *core.EnrichedAlert)
 
 	description := builder.String()
 
-	// Fill result map (already from pool)
+	// Fill result map
 	result["title"] = title
 	result["description"] = description
 	result["severity"] = severity
@@ -278,8 +314,7 @@ func (f *DefaultAlertFormatter) formatPagerDuty(enrichedAlert *core.EnrichedAler
 	alert := enrichedAlert.Alert
 	classification := enrichedAlert.Classification
 
-	// Get result map from pool (optimization: 0 allocations)
-	result := getFormatterResult()
+	result := newFormatterResult()
 
 	// Determine event action
 	eventAction := "trigger"
@@ -333,7 +368,7 @@ func (f *DefaultAlertFormatter) formatPagerDuty(enrichedAlert *core.EnrichedAler
 		}
 	}
 
-	// Fill result map (already from pool)
+	// Fill result map
 	result["event_action"] = eventAction
 	result["dedup_key"] = alert.Fingerprint
 	result["payload"] = map[string]any{
@@ -349,35 +384,12 @@ func (f *DefaultAlertFormatter) formatPagerDuty(enrichedAlert *core.EnrichedAler
 
 // formatSlack formats alert for Slack webhook with Blocks API
 func (f *DefaultAlertFormatter) formatSlack(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
-	alert := enrichedAlert.Alert
-	classification := enrichedAlert.Classification
-
-	// Get result map from pool (optimization: 0 allocations)
-	result := getFormatterResult()
-
-	// Determine color based on severity
-	color := "#FFA500" // Orange (warning)
-	emoji := "⚠️"
+	card := f.buildRichCard(enrichedAlert, slackCardLimits)
 
-	if classification != nil {
-		switch classification.Severity {
-		case core.SeverityCritical:
-			color = "#FF0000" // Red
-			emoji = "🔴"
-		case core.SeverityWarning:
-			color = "#FFA500" // Orange
-			emoji = "⚠️"
-		case core.SeverityInfo:
-			color = "#36A64F" // Green
-			emoji = "ℹ️"
-		case core.SeverityNoise:
-			color = "#808080" // Gray
-			emoji = "🔇"
-		}
-	}
+	result := newFormatterResult()
 
 	// Build header
-	header := fmt.Sprintf("%s *%s* - %s", emoji, alert.AlertName, alert.Status)
+	header := fmt.Sprintf("%s *%s* - %s", card.Style.Emoji, card.AlertName, card.Status)
 
 	// Build text sections
 	var blocks []map[string]any
@@ -392,28 +404,11 @@ func (f *DefaultAlertFormatter) formatSlack(enrichedAlert *core.EnrichedAlert) (
 	})
 
 	// Alert details
-	fields := []map[string]any{
-		{
-			"type": "mrkdwn",
-			"text": fmt.Sprintf("*Status:*\n%s", alert.Status),
-		},
-		{
-			"type": "mrkdwn",
-			"text": fmt.Sprintf("*Started:*\n%s", alert.StartsAt.Format("2006-01-02 15:04:05")),
-		},
-	}
-
-	if ns := alert.Namespace(); ns != nil {
+	fields := make([]map[string]any, 0, len(card.Fields))
+	for _, field := range card.Fields {
 		fields = append(fields, map[string]any{
 			"type": "mrkdwn",
-			"text": fmt.Sprintf("*Namespace:*\n%s", *ns),
-		})
-	}
-
-	if classification != nil {
-		fields = append(fields, map[string]any{
-			"type": "mrkdwn",
-			"text": fmt.Sprintf("*AI Severity:*\n%s (%.0f%%)", classification.Severity, classification.Confidence*100),
+			"text": fmt.Sprintf("*%s:*\n%s", field.Title, field.Value),
 		})
 	}
 
@@ -422,36 +417,27 @@ func (f *DefaultAlertFormatter) formatSlack(enrichedAlert *core.EnrichedAlert) (
 		"fields": fields,
 	})
 
-	// AI Classification details
-	if classification != nil {
-		blocks = append(blocks, map[string]any{
-			"type": "section",
-			"text": map[string]any{
-				"type": "mrkdwn",
-				"text": fmt.Sprintf("*AI Reasoning:*\n%s", truncateString(classification.Reasoning, 300)),
-			},
-		})
+	if card.Description != "" {
+		blocks = append(blocks, slackSection(card.Description))
+	}
 
-		if len(classification.Recommendations) > 0 {
-			recsBuilder := getBuilder()
-			defer putBuilder(recsBuilder)
+	if card.TraceURL != "" {
+		blocks = append(blocks, slackSection(fmt.Sprintf("<%s|View trace>", card.TraceURL)))
+	}
 
-			recsBuilder.WriteString("*Recommendations:*\n")
-			for i, rec := range classification.Recommendations {
-				if i >= 3 {
-					break
-				}
-				fmt.Fprintf(recsBuilder, "• %s\n", rec)
-			}
+	// AI Classification details
+	if card.Reasoning != "" {
+		blocks = append(blocks, slackSection(fmt.Sprintf("*AI Reasoning:*\n%s", card.Reasoning)))
+	}
+	if len(card.Recommendations) > 0 {
+		recsBuilder := getBuilder()
+		defer putBuilder(recsBuilder)
 
-			blocks = append(blocks, map[string]any{
-				"type": "section",
-				"text": map[string]any{
-					"type": "mrkdwn",
-					"text": recsBuilder.String(),
-				},
-			})
+		recsBuilder.WriteString("*Recommendations:*\n")
+		for _, rec := range card.Recommendations {
+			fmt.Fprintf(recsBuilder, "• %s\n", rec)
 		}
+		blocks = append(blocks, slackSection(recsBuilder.String()))
 	}
 
 	// Divider
@@ -465,16 +451,21 @@ func (f *DefaultAlertFormatter) formatSlack(enrichedAlert *core.EnrichedAlert) (
 		"elements": []map[string]any{
 			{
 				"type": "mrkdwn",
-				"text": fmt.Sprintf("Fingerprint: `%s`", alert.Fingerprint),
+				"text": fmt.Sprintf("Fingerprint: `%s`", card.Fingerprint),
 			},
 		},
 	})
 
-	// Fill result map (already from pool)
+	// Fill result map
+	// Plain text fallback for notifications and clients without Block Kit
+	result["text"] = header
+	if card.Summary != "" {
+		result["text"] = header + "\n" + card.Summary
+	}
 	result["blocks"] = blocks
 	result["attachments"] = []map[string]any{
 		{
-			"color":  color,
+			"color":  card.Style.Color,
 			"fields": fields,
 		},
 	}
@@ -482,12 +473,578 @@ func (f *DefaultAlertFormatter) formatSlack(enrichedAlert *core.EnrichedAlert) (
 	return result, nil
 }
 
+// slackSection returns a Slack section block with mrkdwn text.
+func slackSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]any{
+			"type": "mrkdwn",
+			"text": text,
+		},
+	}
+}
+
+// formatTeams formats alert as a Microsoft Teams message carrying an
+// Adaptive Card, as accepted by Teams Workflows and incoming webhooks.
+//
+// Spec: https://adaptivecards.io/explorer/
+func (f *DefaultAlertFormatter) formatTeams(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
+	card := f.buildRichCard(enrichedAlert, teamsCardLimits)
+
+	result := newFormatterResult()
+
+	// Adaptive Card colors: attention (red), warning (yellow), good (green)
+	body := []map[string]any{
+		{
+			"type":   "TextBlock",
+			"text":   card.Title(),
+			"size":   "Large",
+			"weight": "Bolder",
+			"color":  card.Style.CardColor,
+			"wrap":   true,
+		},
+	}
+
+	if card.Summary != "" {
+		body = append(body, teamsTextBlock(card.Summary))
+	}
+
+	// Alert details
+	facts := make([]map[string]any, 0, len(card.Fields))
+	for _, field := range card.Fields {
+		facts = append(facts, map[string]any{"title": field.Title, "value": field.Value})
+	}
+	body = append(body, map[string]any{
+		"type":  "FactSet",
+		"facts": facts,
+	})
+
+	if card.Description != "" {
+		body = append(body, teamsTextBlock(card.Description))
+	}
+
+	// AI Classification details
+	if card.Reasoning != "" {
+		body = append(body, teamsTextBlock(fmt.Sprintf("**AI Reasoning:** %s", card.Reasoning)))
+	}
+	if len(card.Recommendations) > 0 {
+		recsBuilder := getBuilder()
+		defer putBuilder(recsBuilder)
+
+		recsBuilder.WriteString("**Recommendations:**")
+		for _, rec := range card.Recommendations {
+			fmt.Fprintf(recsBuilder, "\n- %s", rec)
+		}
+		body = append(body, teamsTextBlock(recsBuilder.String()))
+	}
+
+	// Fingerprint
+	body = append(body, map[string]any{
+		"type":     "TextBlock",
+		"text":     fmt.Sprintf("Fingerprint: %s", card.Fingerprint),
+		"size":     "Small",
+		"isSubtle": true,
+		"wrap":     true,
+	})
+
+	adaptiveCard := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+		"msteams": map[string]any{"width": "Full"},
+	}
+	if len(card.Links) > 0 {
+		actions := make([]map[string]any, 0, len(card.Links))
+		for _, link := range card.Links {
+			actions = append(actions, map[string]any{"type": "Action.OpenUrl", "title": link.Title, "url": link.URL})
+		}
+		adaptiveCard["actions"] = actions
+	}
+
+	// Fill result map
+	result["type"] = "message"
+	result["attachments"] = []map[string]any{
+		{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     adaptiveCard,
+		},
+	}
+
+	return result, nil
+}
+
+// teamsTextBlock returns a wrapping Adaptive Card TextBlock.
+func teamsTextBlock(text string) map[string]any {
+	return map[string]any{
+		"type": "TextBlock",
+		"text": text,
+		"wrap": true,
+	}
+}
+
+// formatGoogleChat formats alert as a Google Chat message carrying a card
+// (cards v2), as accepted by Google Chat incoming webhooks. The thread key is
+// the fingerprint, so that the notifications of an alert share a thread.
+// Card texts are HTML, so alert texts are escaped.
+//
+// Spec: https://developers.google.com/workspace/chat/api/reference/rest/v1/cards
+func (f *DefaultAlertFormatter) formatGoogleChat(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
+	card := f.buildRichCard(enrichedAlert, googleChatCardLimits)
+
+	result := newFormatterResult()
+
+	header := map[string]any{"title": card.Title()}
+	if card.Summary != "" {
+		header["subtitle"] = card.Summary
+	}
+
+	// Alert details; the status is colored by severity
+	details := make([]map[string]any, 0, len(card.Fields))
+	for _, field := range card.Fields {
+		text := html.EscapeString(field.Value)
+		if field.Title == "Status" && card.Style.Color != "" {
+			text = fmt.Sprintf(`<font color="%s">%s</font>`, card.Style.Color, text)
+		}
+		details = append(details, map[string]any{
+			"decoratedText": map[string]any{"topLabel": field.Title, "text": text},
+		})
+	}
+	sections := []map[string]any{{"widgets": details}}
+
+	if card.Description != "" {
+		sections = append(sections, map[string]any{
+			"widgets": []map[string]any{googleChatParagraph(html.EscapeString(card.Description))},
+		})
+	}
+
+	// AI Classification details
+	if card.Reasoning != "" {
+		widgets := []map[string]any{googleChatParagraph(html.EscapeString(card.Reasoning))}
+		if len(card.Recommendations) > 0 {
+			recs := make([]string, 0, len(card.Recommendations))
+			for _, rec := range card.Recommendations {
+				recs = append(recs, "• "+html.EscapeString(rec))
+			}
+			widgets = append(widgets, googleChatParagraph("<b>Recommendations:</b><br>"+strings.Join(recs, "<br>")))
+		}
+		sections = append(sections, map[string]any{
+			"header":  "AI Classification",
+			"widgets": widgets,
+		})
+	}
+
+	// Links and fingerprint
+	var footer []map[string]any
+	if len(card.Links) > 0 {
+		buttons := make([]map[string]any, 0, len(card.Links))
+		for _, link := range card.Links {
+			buttons = append(buttons, map[string]any{
+				"text":    link.Title,
+				"onClick": map[string]any{"openLink": map[string]any{"url": link.URL}},
+			})
+		}
+		footer = append(footer, map[string]any{"buttonList": map[string]any{"buttons": buttons}})
+	}
+	footer = append(footer, googleChatParagraph(fmt.Sprintf(`<font color="#808080">Fingerprint: %s</font>`, html.EscapeString(card.Fingerprint))))
+	sections = append(sections, map[string]any{"widgets": footer})
+
+	// Fill result map
+	result["cardsV2"] = []map[string]any{
+		{
+			"cardId": "alert",
+			"card": map[string]any{
+				"header":   header,
+				"sections": sections,
+			},
+		},
+	}
+	result["thread"] = map[string]any{"threadKey": card.Fingerprint}
+
+	return result, nil
+}
+
+// googleChatParagraph returns a Google Chat textParagraph widget with HTML
+// text.
+func googleChatParagraph(text string) map[string]any {
+	return map[string]any{"textParagraph": map[string]any{"text": text}}
+}
+
+// formatMattermost formats alert as a Mattermost incoming webhook message
+// carrying a message attachment colored by severity. Attachment texts are
+// Markdown.
+//
+// Spec: https://developers.mattermost.com/integrate/reference/message-attachments/
+func (f *DefaultAlertFormatter) formatMattermost(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
+	card := f.buildRichCard(enrichedAlert, mattermostCardLimits)
+
+	result := newFormatterResult()
+
+	fields := make([]map[string]any, 0, len(card.Fields))
+	for _, field := range card.Fields {
+		fields = append(fields, map[string]any{"title": field.Title, "value": field.Value, "short": true})
+	}
+
+	var text []string
+	if card.Summary != "" {
+		text = append(text, "**"+card.Summary+"**")
+	}
+	if card.Description != "" {
+		text = append(text, card.Description)
+	}
+	if card.Reasoning != "" {
+		text = append(text, "**AI Reasoning:** "+card.Reasoning)
+	}
+	if len(card.Recommendations) > 0 {
+		recsBuilder := getBuilder()
+		defer putBuilder(recsBuilder)
+
+		recsBuilder.WriteString("**Recommendations:**")
+		for _, rec := range card.Recommendations {
+			fmt.Fprintf(recsBuilder, "\n- %s", rec)
+		}
+		text = append(text, recsBuilder.String())
+	}
+	if len(card.Links) > 0 {
+		links := make([]string, 0, len(card.Links))
+		for _, link := range card.Links {
+			links = append(links, fmt.Sprintf("[%s](%s)", link.Title, link.URL))
+		}
+		text = append(text, strings.Join(links, " · "))
+	}
+
+	// Plain text fallback for notifications
+	fallback := card.Title()
+	if card.Summary != "" {
+		fallback += ": " + card.Summary
+	}
+
+	attachment := map[string]any{
+		"fallback": fallback,
+		"color":    card.Style.Color,
+		"title":    card.Title(),
+		"fields":   fields,
+		"footer":   fmt.Sprintf("Fingerprint: %s", card.Fingerprint),
+	}
+	if len(text) > 0 {
+		attachment["text"] = strings.Join(text, "\n\n")
+	}
+
+	// Fill result map
+	result["attachments"] = []map[string]any{attachment}
+
+	return result, nil
+}
+
+// Opsgenie Alert API limits
+const (
+	opsgenieMaxMessage     = 130
+	opsgenieMaxDescription = 15000
+	opsgenieMaxTags        = 20
+	opsgenieMaxTagLength   = 50
+)
+
+// opsgenieResponderLabels map labels to Opsgenie responder types. Values are
+// comma-separated team, user, escalation or schedule names.
+var opsgenieResponderLabels = []struct{ label, responderType string }{
+	{"opsgenie_team", "team"},
+	{"opsgenie_user", "user"},
+	{"opsgenie_escalation", "escalation"},
+	{"opsgenie_schedule", "schedule"},
+}
+
+// formatOpsgenie formats alert as an Opsgenie Alert API v2 create request.
+// The fingerprint is the alias, so that Opsgenie deduplicates repeated
+// notifications and the alert can be acknowledged and closed by alias.
+func (f *DefaultAlertFormatter) formatOpsgenie(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
+	alert := enrichedAlert.Alert
+	classification := enrichedAlert.Classification
+	labels := enrichedAlert.KnownLabels()
+
+	result := newFormatterResult()
+
+	message := alert.AlertName
+	if summary := alert.Annotations["summary"]; summary != "" {
+		message = fmt.Sprintf("%s: %s", alert.AlertName, summary)
+	}
+
+	// Description (also carries the body of AMP's own notifications)
+	descBuilder := getBuilder()
+	defer putBuilder(descBuilder)
+	descBuilder.WriteString(alert.Annotations["description"])
+	if classification != nil {
+		if descBuilder.Len() > 0 {
+			descBuilder.WriteString("\n\n")
+		}
+		fmt.Fprintf(descBuilder, "AI: %s (%.0f%%) - %s", classification.Severity, classification.Confidence*100, classification.Reasoning)
+		for i, rec := range classification.Recommendations {
+			if i >= 3 {
+				break
+			}
+			fmt.Fprintf(descBuilder, "\n- %s", rec)
+		}
+	}
+
+	// Details: labels plus links
+	details := make(map[string]string, len(alert.Labels)+6)
+	for k, v := range alert.Labels {
+		details[k] = v
+	}
+	details["fingerprint"] = alert.Fingerprint
+	details["starts_at"] = alert.StartsAt.UTC().Format(time.RFC3339)
+	for _, key := range []string{"runbook_url", "dashboard_url", core.TraceURLAnnotation} {
+		if v := alert.Annotations[key]; v != "" {
+			details[key] = v
+		}
+	}
+	if alert.GeneratorURL != nil {
+		details["source_url"] = *alert.GeneratorURL
+	}
+	if silenceURL := notifurl.BuildSilenceURL(f.externalURL, alert.Labels); silenceURL != "" {
+		details["silence_url"] = silenceURL
+	}
+
+	// Tags: sorted labels, within Opsgenie's limits
+	tags := labelsToTags(alert.Labels)
+	sort.Strings(tags)
+	if len(tags) > opsgenieMaxTags {
+		tags = tags[:opsgenieMaxTags]
+	}
+	for i, tag := range tags {
+		tags[i] = truncateString(tag, opsgenieMaxTagLength)
+	}
+
+	result["message"] = truncateString(message, opsgenieMaxMessage)
+	result["alias"] = alert.Fingerprint
+	result["description"] = truncateString(descBuilder.String(), opsgenieMaxDescription)
+	result["priority"] = opsgeniePriority(alert, labels.Severity, classification)
+	result["source"] = "AMP"
+	result["tags"] = tags
+	result["details"] = details
+	if labels.Service != "" {
+		result["entity"] = labels.Service
+	}
+	if responders := opsgenieResponders(alert.Labels, labels.Team); len(responders) > 0 {
+		result["responders"] = responders
+	}
+
+	return result, nil
+}
+
+// opsgeniePriority maps the severity to an Opsgenie priority (P1-P5). An
+// opsgenie_priority label or annotation overrides it.
+func opsgeniePriority(alert *core.Alert, severity string, classification *core.ClassificationResult) string {
+	for _, override := range []string{alert.Annotations["opsgenie_priority"], alert.Labels["opsgenie_priority"]} {
+		switch p := strings.ToUpper(strings.TrimSpace(override)); p {
+		case "P1", "P2", "P3", "P4", "P5":
+			return p
+		}
+	}
+
+	if classification != nil {
+		severity = string(classification.Severity)
+	}
+	switch strings.ToLower(severity) {
+	case string(core.SeverityCritical):
+		return "P1"
+	case "high", "error":
+		return "P2"
+	case string(core.SeverityWarning):
+		return "P3"
+	case string(core.SeverityInfo):
+		return "P5"
+	case string(core.SeverityNoise):
+		return "P5"
+	default:
+		return "P3"
+	}
+}
+
+// opsgenieResponders builds the responders from the opsgenie_team,
+// opsgenie_user, opsgenie_escalation and opsgenie_schedule labels. Without
+// any of them, the team label routes the alert to the team of that name.
+func opsgenieResponders(alertLabels map[string]string, team string) []map[string]string {
+	var responders []map[string]string
+	for _, l := range opsgenieResponderLabels {
+		for _, name := range strings.Split(alertLabels[l.label], ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			key := "name"
+			if l.responderType == "user" {
+				key = "username"
+			}
+			responders = append(responders, map[string]string{"type": l.responderType, key: name})
+		}
+	}
+	if len(responders) == 0 && team != "" {
+		responders = append(responders, map[string]string{"type": "team", "name": team})
+	}
+	return responders
+}
+
+// formatKafka formats alert as a Kafka alert event. Every field is always
+// present, empty when unknown, so the record also matches
+// kafkaEventAvroSchema, which has no optional fields.
+func (f *DefaultAlertFormatter) formatKafka(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
+	alert := enrichedAlert.Alert
+	classification := enrichedAlert.Classification
+
+	event := newFormatterResult()
+
+	labels := alert.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	annotations := alert.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	endsAt := ""
+	if alert.EndsAt != nil {
+		endsAt = alert.EndsAt.UTC().Format(time.RFC3339Nano)
+	}
+	generatorURL := ""
+	if alert.GeneratorURL != nil {
+		generatorURL = *alert.GeneratorURL
+	}
+	processedAt := time.Now()
+	if enrichedAlert.ProcessingTimestamp != nil {
+		processedAt = *enrichedAlert.ProcessingTimestamp
+	}
+
+	severity := enrichedAlert.KnownLabels().Severity
+	classificationSeverity, reasoning, confidence := "", "", 0.0
+	recommendations := []string{}
+	if classification != nil {
+		classificationSeverity = string(classification.Severity)
+		severity = classificationSeverity
+		confidence = classification.Confidence
+		reasoning = classification.Reasoning
+		recommendations = append(recommendations, classification.Recommendations...)
+	}
+
+	event["event_type"] = "alert." + string(alert.Status)
+	event["fingerprint"] = alert.Fingerprint
+	event["alert_name"] = alert.AlertName
+	event["status"] = string(alert.Status)
+	event["severity"] = severity
+	event["labels"] = labels
+	event["annotations"] = annotations
+	event["starts_at"] = alert.StartsAt.UTC().Format(time.RFC3339Nano)
+	event["ends_at"] = endsAt
+	event["generator_url"] = generatorURL
+	event["classification_severity"] = classificationSeverity
+	event["classification_confidence"] = confidence
+	event["classification_reasoning"] = reasoning
+	event["recommendations"] = recommendations
+	event["processed_at"] = processedAt.UTC().Format(time.RFC3339Nano)
+
+	return event, nil
+}
+
+// JIRA field limits
+const (
+	jiraMaxSummary     = 255
+	jiraMaxDescription = 32767
+	jiraMaxLabels      = 20
+	jiraMaxLabelLength = 255
+)
+
+// formatJira formats alert as a JIRA REST API v2 create issue request
+// (summary, wiki markup description, labels). The JIRA publisher adds the
+// project, issue type, priority and group label of the target.
+func (f *DefaultAlertFormatter) formatJira(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
+	alert := enrichedAlert.Alert
+	classification := enrichedAlert.Classification
+
+	result := newFormatterResult()
+
+	summary := alert.AlertName
+	if s := alert.Annotations["summary"]; s != "" {
+		summary = fmt.Sprintf("%s: %s", alert.AlertName, s)
+	}
+
+	desc := getBuilder()
+	defer putBuilder(desc)
+	if d := alert.Annotations["description"]; d != "" {
+		desc.WriteString(d)
+		desc.WriteString("\n\n")
+	}
+	fmt.Fprintf(desc, "*Alert:* %s\n*Fingerprint:* %s\n*Started:* %s\n",
+		alert.AlertName, alert.Fingerprint, alert.StartsAt.UTC().Format(time.RFC3339))
+	if severity := jiraSeverity(enrichedAlert); severity != "" {
+		fmt.Fprintf(desc, "*Severity:* %s\n", severity)
+	}
+	if classification != nil {
+		fmt.Fprintf(desc, "\nh3. AI classification\n%s (%.0f%%) - %s\n", classification.Severity, classification.Confidence*100, classification.Reasoning)
+		for _, rec := range classification.Recommendations {
+			fmt.Fprintf(desc, "* %s\n", rec)
+		}
+	}
+	if len(alert.Labels) > 0 {
+		desc.WriteString("\nh3. Labels\n||Label||Value||\n")
+		keys := make([]string, 0, len(alert.Labels))
+		for k := range alert.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(desc, "|%s|%s|\n", k, alert.Labels[k])
+		}
+	}
+	links := make([]string, 0, 4)
+	for _, l := range []struct{ title, url string }{
+		{"Runbook", alert.Annotations["runbook_url"]},
+		{"Dashboard", alert.Annotations["dashboard_url"]},
+		{"Trace", alert.Annotations[core.TraceURLAnnotation]},
+		{"Silence", notifurl.BuildSilenceURL(f.externalURL, alert.Labels)},
+	} {
+		if l.url != "" {
+			links = append(links, fmt.Sprintf("[%s|%s]", l.title, l.url))
+		}
+	}
+	if alert.GeneratorURL != nil {
+		links = append(links, fmt.Sprintf("[Source|%s]", *alert.GeneratorURL))
+	}
+	if len(links) > 0 {
+		fmt.Fprintf(desc, "\n%s\n", strings.Join(links, " | "))
+	}
+
+	// Labels: sorted alert labels; JIRA labels cannot contain spaces
+	labels := labelsToTags(alert.Labels)
+	sort.Strings(labels)
+	if len(labels) > jiraMaxLabels {
+		labels = labels[:jiraMaxLabels]
+	}
+	for i, label := range labels {
+		labels[i] = truncateString(strings.Join(strings.Fields(label), "_"), jiraMaxLabelLength)
+	}
+	labels = append(labels, "amp")
+
+	result["fields"] = map[string]any{
+		"summary":     truncateString(strings.Join(strings.Fields(summary), " "), jiraMaxSummary),
+		"description": truncateString(desc.String(), jiraMaxDescription),
+		"labels":      labels,
+	}
+	return result, nil
+}
+
+// jiraSeverity is the severity of enrichedAlert: the classification, else
+// the severity label.
+func jiraSeverity(enrichedAlert *core.EnrichedAlert) string {
+	if enrichedAlert.Classification != nil {
+		return string(enrichedAlert.Classification.Severity)
+	}
+	return enrichedAlert.KnownLabels().Severity
+}
+
 // formatWebhook formats alert for generic webhook (simple JSON)
 func (f *DefaultAlertFormatter) formatWebhook(enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
 	alert := enrichedAlert.Alert
 
-	// Get result map from pool (optimization: 0 allocations)
-	payload := getFormatterResult()
+	payload := newFormatterResult()
 
 	payload["alert_name"] = alert.AlertName
 	payload["fingerprint"] = alert.Fingerprint
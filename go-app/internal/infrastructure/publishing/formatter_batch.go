@@ -0,0 +1,118 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// FormatBatch formats several alerts of one target as a single payload.
+//
+// The Alertmanager format merges the alerts into one webhook message, as
+// Alertmanager itself sends groups. Other formats wrap the per-alert payloads
+// (payload templates included):
+//
+//	{"status": "firing", "count": 2, "alerts": [{...}, {...}]}
+func (f *DefaultAlertFormatter) FormatBatch(ctx context.Context, alerts []*core.EnrichedAlert, format core.PublishingFormat) (map[string]any, error) {
+	if len(alerts) == 0 {
+		return nil, fmt.Errorf("empty alert batch")
+	}
+
+	payloads := make([]map[string]any, 0, len(alerts))
+	for _, enrichedAlert := range alerts {
+		payload, err := f.FormatAlert(ctx, enrichedAlert, format)
+		if err != nil {
+			return nil, fmt.Errorf("alert %s: %w", batchAlertFingerprint(enrichedAlert), err)
+		}
+		payloads = append(payloads, payload)
+	}
+
+	if _, templated := payloadTemplateFromContext(ctx); format == core.FormatAlertmanager && !templated {
+		// The payloads are redacted already; the common labels and
+		// annotations are taken from the redacted alerts
+		if redaction := redactionFromContext(ctx); redaction != nil {
+			redacted := make([]*core.EnrichedAlert, len(alerts))
+			for i, enrichedAlert := range alerts {
+				redacted[i] = redaction.Alert(enrichedAlert)
+			}
+			alerts = redacted
+		}
+		return f.mergeAlertmanagerBatch(alerts, payloads), nil
+	}
+	return map[string]any{
+		"status": batchStatus(alerts),
+		"count":  len(alerts),
+		"alerts": payloads,
+	}, nil
+}
+
+// mergeAlertmanagerBatch merges the single-alert Alertmanager messages of a
+// batch into one.
+func (f *DefaultAlertFormatter) mergeAlertmanagerBatch(alerts []*core.EnrichedAlert, payloads []map[string]any) map[string]any {
+	amAlerts := make([]map[string]any, 0, len(payloads))
+	for _, payload := range payloads {
+		if single, ok := payload["alerts"].([]map[string]any); ok {
+			amAlerts = append(amAlerts, single...)
+		}
+	}
+
+	labels := make([]map[string]string, len(alerts))
+	annotations := make([]map[string]string, len(alerts))
+	for i, enrichedAlert := range alerts {
+		labels[i] = enrichedAlert.Alert.Labels
+		annotations[i] = enrichedAlert.Alert.Annotations
+	}
+
+	return map[string]any{
+		"receiver":          "alert-history-proxy",
+		"status":            batchStatus(alerts),
+		"alerts":            amAlerts,
+		"groupLabels":       map[string]string{},
+		"commonLabels":      commonPairs(labels),
+		"commonAnnotations": commonPairs(annotations),
+		"externalURL":       f.externalURL,
+		"version":           "4",
+		"groupKey":          fmt.Sprintf("batch:%s", alerts[0].Alert.Fingerprint),
+		"truncatedAlerts":   0,
+	}
+}
+
+// batchStatus is "firing" when any alert of the batch fires, as for
+// Alertmanager groups.
+func batchStatus(alerts []*core.EnrichedAlert) string {
+	for _, enrichedAlert := range alerts {
+		if enrichedAlert.Alert.Status == core.StatusFiring {
+			return string(core.StatusFiring)
+		}
+	}
+	return string(core.StatusResolved)
+}
+
+// commonPairs returns the key/value pairs shared by all sets.
+func commonPairs(sets []map[string]string) map[string]string {
+	common := make(map[string]string)
+	if len(sets) == 0 {
+		return common
+	}
+	for key, value := range sets[0] {
+		shared := true
+		for _, set := range sets[1:] {
+			if v, ok := set[key]; !ok || v != value {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			common[key] = value
+		}
+	}
+	return common
+}
+
+func batchAlertFingerprint(enrichedAlert *core.EnrichedAlert) string {
+	if enrichedAlert == nil || enrichedAlert.Alert == nil {
+		return "<nil>"
+	}
+	return enrichedAlert.Alert.Fingerprint
+}
@@ -0,0 +1,104 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func createTestAlertBatch() []*core.EnrichedAlert {
+	first := createTestEnrichedAlert()
+	second := createTestEnrichedAlert()
+	second.Alert = &core.Alert{
+		Fingerprint: "test-fingerprint-456",
+		AlertName:   "OtherAlert",
+		Status:      core.StatusResolved,
+		Labels:      map[string]string{"alertname": "OtherAlert", "severity": "warning", "namespace": "staging"},
+		Annotations: map[string]string{"summary": "Test alert summary"},
+		StartsAt:    first.Alert.StartsAt,
+	}
+	return []*core.EnrichedAlert{first, second}
+}
+
+func TestFormatBatch_Alertmanager(t *testing.T) {
+	formatter := NewAlertFormatter("https://amp.example.com")
+
+	payload, err := formatter.FormatBatch(context.Background(), createTestAlertBatch(), core.FormatAlertmanager)
+	require.NoError(t, err)
+
+	alerts, ok := payload["alerts"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "test-fingerprint-123", alerts[0]["fingerprint"])
+	assert.Equal(t, "test-fingerprint-456", alerts[1]["fingerprint"])
+	assert.Equal(t, "firing", payload["status"])
+	assert.Equal(t, map[string]string{"severity": "warning"}, payload["commonLabels"])
+	assert.Equal(t, map[string]string{"summary": "Test alert summary"}, payload["commonAnnotations"])
+	assert.Equal(t, "https://amp.example.com", payload["externalURL"])
+	assert.Equal(t, "batch:test-fingerprint-123", payload["groupKey"])
+}
+
+func TestFormatBatch_Webhook(t *testing.T) {
+	formatter := NewAlertFormatter("")
+	batch := createTestAlertBatch()
+	batch[0].Alert.Status = core.StatusResolved
+
+	payload, err := formatter.FormatBatch(context.Background(), batch, core.FormatWebhook)
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", payload["status"])
+	assert.Equal(t, 2, payload["count"])
+	alerts := payload["alerts"].([]map[string]any)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "OtherAlert", alerts[1]["alert_name"])
+
+	t.Run("payload template renders every alert", func(t *testing.T) {
+		target := &core.PublishingTarget{Headers: map[string]string{targetPayloadTemplateHeader: `{"name": "{{ .Alert.AlertName }}"}`}}
+		payload, err := formatter.FormatBatch(withTargetPayloadTemplate(context.Background(), target), batch, core.FormatAlertmanager)
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]any{{"name": "TestAlert"}, {"name": "OtherAlert"}}, payload["alerts"])
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		_, err := formatter.FormatBatch(context.Background(), nil, core.FormatWebhook)
+		require.Error(t, err)
+	})
+}
+
+func TestWebhookPublisher_PublishBatch(t *testing.T) {
+	var requests int
+	var body map[string]any
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		header = r.Header
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := &core.PublishingTarget{
+		Name:    "busy",
+		Type:    "webhook",
+		URL:     server.URL,
+		Headers: map[string]string{"X-Token": "secret", targetBatchMaxSizeHeader: "50"},
+		Format:  core.FormatAlertmanager,
+	}
+	publisher := NewWebhookPublisher(NewAlertFormatter(""), slog.Default()).(BatchPublisher)
+	require.NoError(t, publisher.PublishBatch(context.Background(), createTestAlertBatch(), target))
+
+	assert.Equal(t, 1, requests)
+	assert.Len(t, body["alerts"], 2)
+	assert.Equal(t, "secret", header.Get("X-Token"))
+	assert.Empty(t, header.Get(targetBatchMaxSizeHeader), "batching options are not sent")
+	assert.NotContains(t, targetHTTPHeaders(target.Headers), targetBatchMaxSizeHeader)
+}
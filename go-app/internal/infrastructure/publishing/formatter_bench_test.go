@@ -140,35 +140,3 @@ func BenchmarkStringBuilderPool(b *testing.B) {
 		}
 	})
 }
-
-// BenchmarkFormatterResultPool benchmarks the formatter result pool
-func BenchmarkFormatterResultPool(b *testing.B) {
-	b.Run("WithPool", func(b *testing.B) {
-		b.ReportAllocs()
-		b.ResetTimer()
-
-		for i := 0; i < b.N; i++ {
-			result := getFormatterResult()
-			result["alert_name"] = "TestAlert"
-			result["fingerprint"] = "abc123"
-			result["status"] = "firing"
-			result["labels"] = map[string]string{"severity": "critical"}
-			result["annotations"] = map[string]string{"summary": "Test"}
-			releaseFormatterResult(result)
-		}
-	})
-
-	b.Run("WithoutPool", func(b *testing.B) {
-		b.ReportAllocs()
-		b.ResetTimer()
-
-		for i := 0; i < b.N; i++ {
-			result := make(map[string]any, 30)
-			result["alert_name"] = "TestAlert"
-			result["fingerprint"] = "abc123"
-			result["status"] = "firing"
-			result["labels"] = map[string]string{"severity": "critical"}
-			result["annotations"] = map[string]string{"summary": "Test"}
-		}
-	})
-}
@@ -0,0 +1,181 @@
+package publishing
+
+import (
+	"encoding/json"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// formatter_budget.go - provider payload size limits
+
+// payloadBudget is the size of the payloads a provider accepts.
+type payloadBudget struct {
+	MaxBytes  int // JSON size of the payload (0: unlimited)
+	MaxBlocks int // Slack blocks (0: unlimited)
+}
+
+// payloadBudgets are the budgets of the formats whose provider rejects larger
+// payloads with a 400, at the documented limits.
+var payloadBudgets = map[core.PublishingFormat]payloadBudget{
+	core.FormatSlack:      {MaxBlocks: 50},
+	core.FormatTeams:      {MaxBytes: 28 * 1024},  // Teams message: 28 KB
+	core.FormatGoogleChat: {MaxBytes: 32000},      // Google Chat message: 32,000 bytes
+	core.FormatPagerDuty:  {MaxBytes: 512 * 1024}, // PagerDuty event: 512 KB
+}
+
+// Truncation stages, as recorded by payload_truncated_total. exceeded is
+// recorded when the payload is over budget even with every truncation.
+const (
+	truncationStageBlocks      = "blocks"
+	truncationStageLabels      = "labels"
+	truncationStageAnnotations = "annotations"
+	truncationStageReasoning   = "reasoning"
+	truncationStageExceeded    = "exceeded"
+)
+
+// Lengths texts are truncated to by the annotations and reasoning stages.
+const (
+	truncatedAnnotationLength     = 1000
+	truncatedReasoningLength      = 300
+	truncatedRecommendationLength = 200
+)
+
+// payloadTruncations shrink an alert whose payload is over budget, in the
+// order they are tried: labels go first, then annotations, then the AI
+// reasoning. Each applies to the alert shrunk by the previous ones.
+var payloadTruncations = []struct {
+	stage  string
+	shrink func(*core.EnrichedAlert) *core.EnrichedAlert
+}{
+	{truncationStageLabels, withoutExtraLabels},
+	{truncationStageAnnotations, withTruncatedAnnotations},
+	{truncationStageReasoning, withTruncatedReasoning},
+}
+
+// budgetKeptLabels are the labels kept by the labels stage: those of
+// KnownLabels, which the formats show.
+var budgetKeptLabels = []string{
+	core.LabelAlertName, core.LabelNamespace, core.LabelSeverity, core.LabelTeam,
+	core.LabelService, core.LabelEnvironment, core.LabelRegion,
+}
+
+// budgetKeptAnnotations are the annotations kept by the annotations stage.
+var budgetKeptAnnotations = []string{
+	"summary", "description", "runbook_url", "dashboard_url", core.TraceURLAnnotation,
+}
+
+// formatWithinBudget formats enrichedAlert with formatFn and, when the
+// payload is over the budget of format, formats it again with the alert
+// truncated stage by stage until it fits. Payloads still over budget are
+// returned as is, for the provider to decide.
+func (f *DefaultAlertFormatter) formatWithinBudget(format core.PublishingFormat, enrichedAlert *core.EnrichedAlert, formatFn formatFunc) (map[string]any, error) {
+	payload, err := formatFn(enrichedAlert)
+	budget, ok := payloadBudgets[format]
+	if err != nil || !ok {
+		return payload, err
+	}
+
+	stage := ""
+	if budget.capBlocks(payload) {
+		stage = truncationStageBlocks
+	}
+	for _, truncation := range payloadTruncations {
+		if budget.fits(payload) {
+			break
+		}
+		enrichedAlert = truncation.shrink(enrichedAlert)
+		if payload, err = formatFn(enrichedAlert); err != nil {
+			return nil, err
+		}
+		budget.capBlocks(payload)
+		stage = truncation.stage
+	}
+	if !budget.fits(payload) {
+		stage = truncationStageExceeded
+	}
+
+	if stage != "" && f.metrics != nil {
+		f.metrics.RecordPayloadTruncated(string(format), stage)
+	}
+	return payload, nil
+}
+
+// fits reports whether payload is within the byte budget.
+func (b payloadBudget) fits(payload map[string]any) bool {
+	if b.MaxBytes <= 0 {
+		return true
+	}
+	data, err := json.Marshal(payload)
+	return err != nil || len(data) <= b.MaxBytes
+}
+
+// capBlocks drops the blocks of payload over the block budget, keeping the
+// last one (the fingerprint context), and reports whether it dropped any.
+func (b payloadBudget) capBlocks(payload map[string]any) bool {
+	blocks, ok := payload["blocks"].([]map[string]any)
+	if b.MaxBlocks <= 0 || !ok || len(blocks) <= b.MaxBlocks {
+		return false
+	}
+	capped := append(blocks[:b.MaxBlocks-1:b.MaxBlocks-1], blocks[len(blocks)-1])
+	payload["blocks"] = capped
+	return true
+}
+
+// withoutExtraLabels returns a copy of enrichedAlert keeping only the
+// budgetKeptLabels.
+func withoutExtraLabels(enrichedAlert *core.EnrichedAlert) *core.EnrichedAlert {
+	alert := *enrichedAlert.Alert
+	alert.Labels = keepPairs(alert.Labels, budgetKeptLabels, 0)
+	return enrichedAlert.WithAlert(&alert, enrichedAlert.KnownLabels())
+}
+
+// withTruncatedAnnotations returns a copy of enrichedAlert keeping only the
+// budgetKeptAnnotations, truncated.
+func withTruncatedAnnotations(enrichedAlert *core.EnrichedAlert) *core.EnrichedAlert {
+	alert := *enrichedAlert.Alert
+	alert.Annotations = keepPairs(alert.Annotations, budgetKeptAnnotations, truncatedAnnotationLength)
+	return enrichedAlert.WithAlert(&alert, enrichedAlert.KnownLabels())
+}
+
+// withTruncatedReasoning returns a copy of enrichedAlert with its AI
+// reasoning and recommendations truncated.
+func withTruncatedReasoning(enrichedAlert *core.EnrichedAlert) *core.EnrichedAlert {
+	classification := enrichedAlert.Classification
+	if classification == nil {
+		return enrichedAlert
+	}
+	c := *classification
+	c.Reasoning = truncateString(c.Reasoning, truncatedReasoningLength)
+	recommendations := classification.Recommendations
+	if len(recommendations) > richCardMaxRecommendations {
+		recommendations = recommendations[:richCardMaxRecommendations]
+	}
+	c.Recommendations = make([]string, len(recommendations))
+	for i, recommendation := range recommendations {
+		c.Recommendations[i] = truncateString(recommendation, truncatedRecommendationLength)
+	}
+
+	truncated := enrichedAlert.WithAlert(enrichedAlert.Alert, enrichedAlert.KnownLabels())
+	truncated.Classification = &c
+	return truncated
+}
+
+// keepPairs returns the pairs named in names, values truncated to maxLen
+// (0: not truncated).
+func keepPairs(pairs map[string]string, names []string, maxLen int) map[string]string {
+	if pairs == nil {
+		return nil
+	}
+	kept := make(map[string]string, len(names))
+	for _, name := range names {
+		value, ok := pairs[name]
+		if !ok {
+			continue
+		}
+		if maxLen > 0 {
+			value = truncateString(value, maxLen)
+		}
+		kept[name] = value
+	}
+	return kept
+}
@@ -0,0 +1,132 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+func payloadSize(t *testing.T, payload map[string]any) int {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return len(data)
+}
+
+// oversizedAlert returns an alert with count labels and annotations of size
+// bytes each.
+func oversizedAlert(count, size int) *core.EnrichedAlert {
+	alert := createTestEnrichedAlert()
+	for i := 0; i < count; i++ {
+		alert.Alert.Labels[fmt.Sprintf("label_%d", i)] = strings.Repeat("l", size)
+		alert.Alert.Annotations[fmt.Sprintf("note_%d", i)] = strings.Repeat("a", size)
+	}
+	return alert
+}
+
+func TestFormatAlert_TruncatesToBudget(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	formatter := newAlertFormatter("https://amp.example.com", nil)
+	formatter.SetMetrics(v2.NewRegistry(v2.WithPrometheusRegisterer(registry)).Publishing)
+
+	t.Run("labels go first", func(t *testing.T) {
+		alert := oversizedAlert(100, 6*1024) // ~600 KB of labels, as much of annotations
+		alert.Alert.Annotations = map[string]string{"summary": "Disk full"}
+
+		payload, err := formatter.FormatAlert(context.Background(), alert, core.FormatPagerDuty)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, payloadSize(t, payload), payloadBudgets[core.FormatPagerDuty].MaxBytes)
+
+		details := payload["payload"].(map[string]any)["custom_details"].(map[string]any)
+		assert.Equal(t, map[string]string{"alertname": "TestAlert", "severity": "warning", "namespace": "production"}, details["labels"])
+		assert.Equal(t, map[string]string{"summary": "Disk full"}, details["annotations"])
+		assert.Equal(t, alert.Classification.Reasoning, details["ai_classification"].(map[string]any)["reasoning"])
+	})
+
+	t.Run("annotations before reasoning", func(t *testing.T) {
+		alert := oversizedAlert(100, 6*1024)
+		payload, err := formatter.FormatAlert(context.Background(), alert, core.FormatPagerDuty)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, payloadSize(t, payload), payloadBudgets[core.FormatPagerDuty].MaxBytes)
+
+		details := payload["payload"].(map[string]any)["custom_details"].(map[string]any)
+		assert.Equal(t, map[string]string{
+			"summary":     alert.Alert.Annotations["summary"],
+			"description": alert.Alert.Annotations["description"],
+		}, details["annotations"])
+	})
+
+	t.Run("reasoning last", func(t *testing.T) {
+		alert := createTestEnrichedAlert()
+		alert.Classification.Recommendations = []string{strings.Repeat("r", 20*1024), strings.Repeat("s", 20*1024)}
+
+		payload, err := formatter.FormatAlert(context.Background(), alert, core.FormatTeams)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, payloadSize(t, payload), payloadBudgets[core.FormatTeams].MaxBytes)
+		// The alert itself is shared with the other targets
+		assert.Len(t, alert.Classification.Recommendations[0], 20*1024)
+	})
+
+	t.Run("within budget", func(t *testing.T) {
+		alert := createTestEnrichedAlert()
+		alert.Alert.Labels["pod"] = "api-0"
+		payload, err := formatter.FormatAlert(context.Background(), alert, core.FormatPagerDuty)
+		require.NoError(t, err)
+		details := payload["payload"].(map[string]any)["custom_details"].(map[string]any)
+		assert.Contains(t, details["labels"], "pod")
+	})
+
+	t.Run("formats without budget", func(t *testing.T) {
+		payload, err := formatter.FormatAlert(context.Background(), oversizedAlert(100, 6*1024), core.FormatWebhook)
+		require.NoError(t, err)
+		assert.Greater(t, payloadSize(t, payload), 512*1024)
+	})
+
+	metric := "alert_history_publishing_payload_truncated_total"
+	assert.Equal(t, 3, testutil.CollectAndCount(registry, metric))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	stages := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != metric {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			stages[labels["format"]+"/"+labels["stage"]] = m.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{"pagerduty/labels": 1, "pagerduty/annotations": 1, "teams/reasoning": 1}, stages)
+}
+
+func TestPayloadBudget_CapBlocks(t *testing.T) {
+	budget := payloadBudgets[core.FormatSlack]
+	blocks := make([]map[string]any, 60)
+	for i := range blocks {
+		blocks[i] = map[string]any{"type": "section", "n": i}
+	}
+	payload := map[string]any{"blocks": blocks}
+
+	require.True(t, budget.capBlocks(payload))
+	capped := payload["blocks"].([]map[string]any)
+	assert.Len(t, capped, 50)
+	assert.Equal(t, 48, capped[48]["n"])
+	assert.Equal(t, 59, capped[49]["n"], "the last block is kept")
+	assert.Equal(t, 49, blocks[49]["n"], "the formatted blocks are not overwritten")
+
+	assert.False(t, budget.capBlocks(payload))
+	assert.False(t, budget.capBlocks(map[string]any{"text": "no blocks"}))
+}
@@ -0,0 +1,132 @@
+package publishing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	notifurl "github.com/ipiton/AMP/internal/notification/url"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+// formatter_card.go - chat-agnostic content of chat notifications
+
+// richCardMaxRecommendations is the number of AI recommendations shown in
+// chat notifications.
+const richCardMaxRecommendations = 3
+
+// richCardLimits are the lengths the texts of a chat notification are
+// truncated to, below the limits of the chat API.
+type richCardLimits struct {
+	Summary     int
+	Description int
+	Reasoning   int
+}
+
+// Truncation lengths of the chat formats.
+var (
+	slackCardLimits      = richCardLimits{Summary: 3000, Description: 2900, Reasoning: 300} // section text: 3000
+	teamsCardLimits      = richCardLimits{Summary: 1000, Description: 2000, Reasoning: 300}
+	googleChatCardLimits = richCardLimits{Summary: 1000, Description: 2000, Reasoning: 300}
+	mattermostCardLimits = richCardLimits{Summary: 1000, Description: 3000, Reasoning: 300} // post: 16383
+)
+
+// richCard is the content of a chat notification, independent of the markup
+// of a chat. The chat formats (Slack, Teams, Google Chat, Mattermost) build it
+// with buildRichCard and only render it, so that severity styles, field
+// layout and truncation are the same in every chat.
+type richCard struct {
+	Style       domain.SeverityStyle // emoji and colors of the alert's severity
+	AlertName   string
+	Status      core.AlertStatus
+	Summary     string
+	Fields      []richCardField
+	Description string // also carries the body of AMP's own notifications
+	Fingerprint string
+
+	// AI classification (empty without classification)
+	Reasoning       string
+	Recommendations []string
+
+	TraceURL string         // deep link to the trace the alert was raised from
+	Links    []richCardLink // Runbook, Dashboard, View trace, Source, Silence
+}
+
+// richCardField is a titled value, shown as a fact or a short field.
+type richCardField struct {
+	Title string
+	Value string
+}
+
+// richCardLink is a titled URL, shown as a button or link.
+type richCardLink struct {
+	Title string
+	URL   string
+}
+
+// Title returns the plain-text title of the card: emoji, alert name and
+// status.
+func (c *richCard) Title() string {
+	return fmt.Sprintf("%s %s - %s", c.Style.Emoji, c.AlertName, c.Status)
+}
+
+// buildRichCard builds the chat notification of an alert, with its texts
+// truncated to limits.
+func (f *DefaultAlertFormatter) buildRichCard(enrichedAlert *core.EnrichedAlert, limits richCardLimits) *richCard {
+	alert := enrichedAlert.Alert
+	classification := enrichedAlert.Classification
+	labels := enrichedAlert.KnownLabels()
+
+	card := &richCard{
+		Style:       f.styles[severityStyleKey(enrichedAlert)],
+		AlertName:   alert.AlertName,
+		Status:      alert.Status,
+		Summary:     truncateString(alert.Annotations["summary"], limits.Summary),
+		Description: truncateString(alert.Annotations["description"], limits.Description),
+		Fingerprint: alert.Fingerprint,
+		TraceURL:    alert.Annotations[core.TraceURLAnnotation],
+	}
+
+	// Alert details
+	card.Fields = append(card.Fields, richCardField{"Status", string(alert.Status)})
+	if labels.Severity != "" {
+		card.Fields = append(card.Fields, richCardField{"Severity", labels.Severity})
+	}
+	if labels.Namespace != "" {
+		card.Fields = append(card.Fields, richCardField{"Namespace", labels.Namespace})
+	}
+	card.Fields = append(card.Fields, richCardField{"Started", alert.StartsAt.UTC().Format(time.RFC3339)})
+	if alert.EndsAt != nil && alert.Status == core.StatusResolved {
+		card.Fields = append(card.Fields, richCardField{"Ended", alert.EndsAt.UTC().Format(time.RFC3339)})
+	}
+	if classification != nil {
+		card.Fields = append(card.Fields, richCardField{
+			"AI Severity",
+			fmt.Sprintf("%s (%.0f%%)", classification.Severity, classification.Confidence*100),
+		})
+
+		card.Reasoning = truncateString(classification.Reasoning, limits.Reasoning)
+		card.Recommendations = classification.Recommendations
+		if len(card.Recommendations) > richCardMaxRecommendations {
+			card.Recommendations = card.Recommendations[:richCardMaxRecommendations]
+		}
+	}
+
+	// Links
+	addLink := func(title, url string) {
+		if url != "" {
+			card.Links = append(card.Links, richCardLink{title, url})
+		}
+	}
+	addLink("Runbook", alert.Annotations["runbook_url"])
+	addLink("Dashboard", alert.Annotations["dashboard_url"])
+	addLink("View trace", card.TraceURL)
+	if alert.GeneratorURL != nil {
+		addLink("Source", *alert.GeneratorURL)
+	}
+	if alert.Status == core.StatusFiring {
+		addLink("Silence", notifurl.BuildSilenceURL(f.externalURL, alert.Labels))
+	}
+
+	return card
+}
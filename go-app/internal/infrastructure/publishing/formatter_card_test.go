@@ -0,0 +1,82 @@
+package publishing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+func TestBuildRichCard(t *testing.T) {
+	formatter := newAlertFormatter("https://amp.example.com", nil)
+	alert := createTestEnrichedAlert()
+	alert.Alert.StartsAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alert.Alert.Annotations["description"] = strings.Repeat("d", 50)
+	alert.Alert.Annotations["runbook_url"] = "https://runbooks.example.com/test"
+	alert.Classification.Recommendations = append(alert.Classification.Recommendations, "Page the DBA")
+	formatter.styles = map[string]domain.SeverityStyle{"warning": {Emoji: "W", Color: "#FFA500"}}
+
+	card := formatter.buildRichCard(alert, richCardLimits{Summary: 100, Description: 20, Reasoning: 100})
+
+	assert.Equal(t, "W TestAlert - firing", card.Title())
+	assert.Equal(t, "#FFA500", card.Style.Color)
+	assert.Equal(t, []richCardField{
+		{"Status", "firing"},
+		{"Severity", "warning"},
+		{"Namespace", "production"},
+		{"Started", "2026-03-01T12:00:00Z"},
+		{"AI Severity", "warning (85%)"},
+	}, card.Fields)
+	assert.Len(t, card.Description, 20)
+	assert.True(t, strings.HasSuffix(card.Description, "..."))
+	assert.Len(t, card.Recommendations, richCardMaxRecommendations)
+
+	titles := make([]string, 0, len(card.Links))
+	for _, link := range card.Links {
+		titles = append(titles, link.Title)
+	}
+	assert.Equal(t, []string{"Runbook", "Source", "Silence"}, titles)
+}
+
+func TestBuildRichCard_Resolved(t *testing.T) {
+	alert := createTestEnrichedAlert()
+	alert.Alert.Status = core.StatusResolved
+	endsAt := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	alert.Alert.EndsAt = &endsAt
+	alert.Classification = nil
+
+	card := newAlertFormatter("https://amp.example.com", nil).buildRichCard(alert, teamsCardLimits)
+
+	assert.Contains(t, card.Fields, richCardField{"Ended", "2026-03-01T13:00:00Z"})
+	assert.Empty(t, card.Reasoning)
+	for _, link := range card.Links {
+		assert.NotEqual(t, "Silence", link.Title, "resolved alerts need no silence link")
+	}
+}
+
+// TestChatFormats_SeverityColor verifies that every chat format takes its
+// color from the same severity style.
+func TestChatFormats_SeverityColor(t *testing.T) {
+	formatter := NewAlertFormatter("")
+	alert := newCriticalTestAlert()
+
+	slack, err := formatter.FormatAlert(context.Background(), alert, core.FormatSlack)
+	require.NoError(t, err)
+	assert.Equal(t, "#FF0000", slack["attachments"].([]map[string]any)[0]["color"])
+
+	mattermost, err := formatter.FormatAlert(context.Background(), alert, core.FormatMattermost)
+	require.NoError(t, err)
+	assert.Equal(t, "#FF0000", mattermost["attachments"].([]map[string]any)[0]["color"])
+
+	googleChat, err := formatter.FormatAlert(context.Background(), alert, core.FormatGoogleChat)
+	require.NoError(t, err)
+	sections := googleChatCard(t, googleChat)["sections"].([]map[string]any)
+	status := sections[0]["widgets"].([]map[string]any)[0]["decoratedText"].(map[string]any)
+	assert.Equal(t, `<font color="#FF0000">firing</font>`, status["text"])
+}
@@ -2,6 +2,9 @@ package publishing
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -193,6 +196,26 @@ func TestFormatAlert_Slack_Critical(t *testing.T) {
 	assert.Equal(t, "#FF0000", attachments[0]["color"])
 }
 
+func TestFormatAlert_Slack_TraceLink(t *testing.T) {
+	formatter := NewAlertFormatter("")
+	enrichedAlert := createTestEnrichedAlert()
+	enrichedAlert.Alert.Annotations[core.TraceURLAnnotation] = "https://tempo.example.com/trace/4bf92f35"
+
+	result, err := formatter.FormatAlert(context.Background(), enrichedAlert, core.FormatSlack)
+
+	require.NoError(t, err)
+	blocks, ok := result["blocks"].([]map[string]any)
+	require.True(t, ok)
+
+	var found bool
+	for _, block := range blocks {
+		if text, ok := block["text"].(map[string]any); ok && text["text"] == "<https://tempo.example.com/trace/4bf92f35|View trace>" {
+			found = true
+		}
+	}
+	assert.True(t, found, "trace link block")
+}
+
 func TestFormatAlert_Webhook(t *testing.T) {
 	formatter := NewAlertFormatter("")
 	enrichedAlert := createTestEnrichedAlert()
@@ -294,3 +317,78 @@ func TestLabelsToTags(t *testing.T) {
 	assert.Contains(t, tags, "environment:production")
 	assert.Contains(t, tags, "team:platform")
 }
+
+// TestFormatAlert_ResultsOwnedByCaller formats alerts concurrently while
+// earlier payloads are held, as the queue does across retries: a payload
+// must never change once returned (run with -race).
+func TestFormatAlert_ResultsOwnedByCaller(t *testing.T) {
+	formatter := NewAlertFormatter("")
+	formats := []core.PublishingFormat{
+		core.FormatAlertmanager, core.FormatRootly, core.FormatPagerDuty, core.FormatSlack,
+		core.FormatWebhook, core.FormatTeams, core.FormatOpsgenie, core.FormatKafka,
+		core.FormatJira, core.FormatGoogleChat, core.FormatMattermost,
+	}
+	format := func(format core.PublishingFormat, i int) (map[string]any, string) {
+		alert := createTestEnrichedAlert()
+		alert.Alert.Fingerprint = fmt.Sprintf("fp-%d", i)
+		alert.Alert.AlertName = fmt.Sprintf("Alert%d", i)
+		// assert, not require: called from several goroutines
+		payload, err := formatter.FormatAlert(context.Background(), alert, format)
+		assert.NoError(t, err)
+		data, err := json.Marshal(payload)
+		assert.NoError(t, err)
+		return payload, string(data)
+	}
+
+	for _, f := range formats {
+		held, want := format(f, 0)
+
+		var wg sync.WaitGroup
+		for i := 1; i <= 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					// Callers may modify their payload
+					if payload, _ := format(f, i); payload != nil {
+						payload["modified"] = i
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		got, err := json.Marshal(held)
+		require.NoError(t, err)
+		assert.JSONEq(t, want, string(got), "format %s", f)
+	}
+}
+
+// TestFormatAlert_SecondCallKeepsFirstResult formats two alerts one after
+// the other: the second result is a new map and leaves the first unchanged.
+func TestFormatAlert_SecondCallKeepsFirstResult(t *testing.T) {
+	formatter := NewAlertFormatter("")
+	for _, f := range []core.PublishingFormat{
+		core.FormatAlertmanager, core.FormatRootly, core.FormatPagerDuty, core.FormatSlack,
+		core.FormatWebhook, core.FormatTeams, core.FormatOpsgenie, core.FormatKafka,
+		core.FormatJira, core.FormatGoogleChat, core.FormatMattermost,
+	} {
+		first, err := formatter.FormatAlert(context.Background(), createTestEnrichedAlert(), f)
+		require.NoError(t, err)
+		want, err := json.Marshal(first)
+		require.NoError(t, err)
+
+		other := createTestEnrichedAlert()
+		other.Alert.Fingerprint = "fp-second"
+		other.Alert.AlertName = "SecondAlert"
+		other.Alert.Status = core.StatusResolved
+		second, err := formatter.FormatAlert(context.Background(), other, f)
+		require.NoError(t, err)
+		second["modified"] = true
+
+		got, err := json.Marshal(first)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(want), string(got), "format %s", f)
+		assert.NotContains(t, first, "modified", "format %s", f)
+	}
+}
@@ -0,0 +1,71 @@
+package publishing
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// googlechat_publisher_enhanced.go - Google Chat publisher (cards v2)
+
+// googleChatReplyOption makes Google Chat post messages with a thread key
+// into the thread of that key, starting it if needed.
+const googleChatReplyOption = "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD"
+
+// EnhancedGoogleChatPublisher implements AlertPublisher for Google Chat
+// spaces. Every notification is posted as a card into the thread of the
+// alert (keyed by fingerprint), so that the resolution follows the firing
+// notification. The webhook URL is read from the target at publish time.
+type EnhancedGoogleChatPublisher struct {
+	*BaseEnhancedPublisher                   // Embedded base publisher for common functionality
+	client                 ChatWebhookClient // Google Chat webhook client
+}
+
+// NewEnhancedGoogleChatPublisher creates a new Google Chat publisher
+// metrics: Prometheus metrics recorder
+// formatter: Alert formatter used with core.FormatGoogleChat
+func NewEnhancedGoogleChatPublisher(
+	client ChatWebhookClient,
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return &EnhancedGoogleChatPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "googlechat_publisher"),
+		),
+		client: client,
+	}
+}
+
+// Publish posts the alert to the Google Chat space of target.
+func (p *EnhancedGoogleChatPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	return p.publishChatMessage(ctx, p.client, v2.ProviderGoogleChat, core.FormatGoogleChat,
+		googleChatThreadURL(target.URL), enrichedAlert, target, nil)
+}
+
+// Name returns publisher name
+func (p *EnhancedGoogleChatPublisher) Name() string {
+	return "GoogleChat"
+}
+
+// googleChatThreadURL adds the messageReplyOption that threads messages by
+// their thread key to a webhook URL, unless the URL sets one.
+func googleChatThreadURL(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL
+	}
+	query := u.Query()
+	if query.Has("messageReplyOption") {
+		return webhookURL
+	}
+	query.Set("messageReplyOption", googleChatReplyOption)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,129 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// googleChatCard returns the card of a formatted Google Chat message.
+func googleChatCard(t *testing.T, payload map[string]any) map[string]any {
+	t.Helper()
+	cards, ok := payload["cardsV2"].([]map[string]any)
+	require.True(t, ok, "cardsV2")
+	require.Len(t, cards, 1)
+	card, ok := cards[0]["card"].(map[string]any)
+	require.True(t, ok, "card")
+	return card
+}
+
+func TestFormatGoogleChat_FiringAlert(t *testing.T) {
+	alert := newTeamsTestAlert(core.StatusFiring)
+	alert.Alert.Annotations["description"] = "<script>alert(1)</script>"
+
+	payload, err := NewAlertFormatter("https://amp.example.com").FormatAlert(context.Background(), alert, core.FormatGoogleChat)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"threadKey": "teams-fp"}, payload["thread"])
+	card := googleChatCard(t, payload)
+	assert.Equal(t, map[string]any{"title": "🔴 HighCPU - firing", "subtitle": "CPU usage above 90%"}, card["header"])
+
+	sections := card["sections"].([]map[string]any)
+	description := sections[1]["widgets"].([]map[string]any)[0]["textParagraph"].(map[string]any)
+	assert.Equal(t, "&lt;script&gt;alert(1)&lt;/script&gt;", description["text"], "alert texts are escaped")
+
+	buttons := map[string]string{}
+	footer := sections[len(sections)-1]["widgets"].([]map[string]any)
+	for _, button := range footer[0]["buttonList"].(map[string]any)["buttons"].([]map[string]any) {
+		buttons[button["text"].(string)] = button["onClick"].(map[string]any)["openLink"].(map[string]any)["url"].(string)
+	}
+	assert.Equal(t, "https://runbooks.example.com/high-cpu", buttons["Runbook"])
+	assert.Contains(t, buttons["Silence"], "https://amp.example.com/#/silences?filter=")
+}
+
+func TestEnhancedGoogleChatPublisher_Publish(t *testing.T) {
+	var received map[string]any
+	var replyOption string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.URL.Query().Get("token"))
+		replyOption = r.URL.Query().Get("messageReplyOption")
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{Name: "ops-gchat", Type: "googlechat", URL: server.URL + "/v1/spaces/AAA/messages?key=k&token=secret", Format: core.FormatGoogleChat}
+
+	// The queue creates publishers by type
+	publisher, err := factory.CreatePublisher(target.Type)
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedGoogleChatPublisher{}, publisher)
+
+	require.NoError(t, publisher.Publish(context.Background(), newTeamsTestAlert(core.StatusFiring), target))
+	assert.Equal(t, googleChatReplyOption, replyOption)
+	assert.Len(t, received["cardsV2"], 1)
+}
+
+func TestGoogleChatThreadURL(t *testing.T) {
+	assert.Equal(t,
+		"https://chat.googleapis.com/v1/spaces/AAA/messages?key=k&messageReplyOption=REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD&token=t",
+		googleChatThreadURL("https://chat.googleapis.com/v1/spaces/AAA/messages?key=k&token=t"))
+
+	explicit := "https://chat.googleapis.com/v1/spaces/AAA/messages?key=k&token=t&messageReplyOption=MESSAGE_REPLY_OPTION_UNSPECIFIED"
+	assert.Equal(t, explicit, googleChatThreadURL(explicit))
+}
+
+func TestHTTPChatWebhookClient_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		wantClass  httperror.Class
+	}{
+		{"ok", http.StatusOK, "", 0},
+		{"rate limited", http.StatusTooManyRequests, "30", httperror.ClassTransient},
+		{"unavailable", http.StatusServiceUnavailable, "", httperror.ClassTransient},
+		{"bad message", http.StatusBadRequest, "", httperror.ClassPermanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewHTTPChatWebhookClient(ProviderGoogleChat, 0, slog.Default())
+			err := client.PostMessage(context.Background(), server.URL, []byte(`{}`))
+			if tt.status == http.StatusOK {
+				require.NoError(t, err)
+				return
+			}
+
+			apiErr := AsPublishingError(err)
+			require.NotNil(t, apiErr, "error = %v", err)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, ProviderGoogleChat, apiErr.Provider)
+			assert.Equal(t, tt.wantClass, httperror.Classify(err))
+			if tt.retryAfter != "" {
+				assert.Equal(t, 30, httperror.GetRetryAfter(err))
+			}
+		})
+	}
+}
@@ -167,6 +167,10 @@ type DetailedQueueStatsResponse struct {
 	TotalFailed    int64   `json:"total_failed"`
 	SuccessRate    float64 `json:"success_rate_percent"`
 
+	// Jobs shed under queue pressure
+	TotalShed      int64            `json:"total_shed"`
+	ShedBySeverity map[string]int64 `json:"shed_by_severity"`
+
 	// DLQ stats
 	DLQSize int `json:"dlq_size"`
 }
@@ -533,6 +537,7 @@ func (h *PublishingHandlers) SubmitAlert(w http.ResponseWriter, r *http.Request)
 			Reasoning:  "Submitted via API",
 		},
 	}
+	enrichedAlert.IndexLabels("")
 
 	// Submit to specific target or all targets
 	var jobIDs []string
@@ -610,6 +615,8 @@ func (h *PublishingHandlers) GetDetailedQueueStats(w http.ResponseWriter, r *htt
 		TotalCompleted: stats.TotalCompleted,
 		TotalFailed:    stats.TotalFailed,
 		SuccessRate:    successRate,
+		TotalShed:      stats.TotalShed,
+		ShedBySeverity: stats.ShedBySeverity,
 		DLQSize:        dlqSize,
 	}
 
@@ -853,37 +860,5 @@ func (h *PublishingHandlers) sendError(w http.ResponseWriter, status int, messag
 }
 
 func (h *PublishingHandlers) createTestAlert(alertName string) *core.EnrichedAlert {
-	if alertName == "" {
-		alertName = "TestAlert"
-	}
-
-	now := time.Now()
-	generatorURL := "http://test/alert"
-
-	return &core.EnrichedAlert{
-		Alert: &core.Alert{
-			Fingerprint: "test-" + alertName,
-			AlertName:   alertName,
-			Status:      core.StatusFiring,
-			Labels: map[string]string{
-				"alertname": alertName,
-				"severity":  "info",
-				"test":      "true",
-			},
-			Annotations: map[string]string{
-				"summary":     "Test alert for target validation",
-				"description": "This is a test alert sent via API",
-			},
-			StartsAt:     now,
-			GeneratorURL: &generatorURL,
-		},
-		Classification: &core.ClassificationResult{
-			Severity:   core.SeverityInfo,
-			Confidence: 1.0,
-			Reasoning:  "Test alert",
-			Recommendations: []string{
-				"This is a test - no action required",
-			},
-		},
-	}
+	return NewTestAlert(alertName)
 }
@@ -0,0 +1,284 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// jira_client.go - JIRA REST API v2 client (JIRA Cloud and Server/Data Center)
+
+// ErrMissingJiraCredentials is returned when a JIRA target has no
+// Authorization header.
+var ErrMissingJiraCredentials = errors.New("jira: Authorization not found in target configuration")
+
+// ErrJiraTransitionNotFound is returned when an issue has no transition of
+// the configured name in its current status.
+var ErrJiraTransitionNotFound = errors.New("jira: transition not found")
+
+// JiraClient manages issues through the JIRA REST API v2.
+//
+// Failed requests are returned as *httperror.HTTPAPIError with
+// ProviderJira, so that the publishing queue can classify them.
+type JiraClient interface {
+	// CreateIssue creates an issue from a JSON-encoded create request and
+	// returns its key.
+	CreateIssue(ctx context.Context, payload []byte) (string, error)
+	// FindOpenIssue returns the key of the latest unresolved issue of
+	// project labelled label, or "" when there is none.
+	FindOpenIssue(ctx context.Context, project, label string) (string, error)
+	// AddComment adds a comment to the issue.
+	AddComment(ctx context.Context, issueKey, body string) error
+	// TransitionIssue moves the issue through the transition named
+	// transition (or leading to the status of that name).
+	TransitionIssue(ctx context.Context, issueKey, transition string) error
+}
+
+// HTTPJiraClient implements JiraClient with the JIRA REST API v2
+// (/rest/api/2).
+type HTTPJiraClient struct {
+	httpClient    *http.Client
+	baseURL       string
+	authorization string
+	logger        *slog.Logger
+}
+
+// NewHTTPJiraClient creates a new JIRA client
+// baseURL: JIRA base URL (e.g. https://example.atlassian.net)
+// authorization: Authorization header ("Basic <base64(email:api token)>" or "Bearer <personal access token>")
+// timeout: request timeout (<= 0 uses 10s)
+func NewHTTPJiraClient(baseURL, authorization string, timeout time.Duration, logger *slog.Logger) JiraClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPJiraClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: newOutboundTransport(&http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12, // TLS 1.2+ required
+				},
+				MaxIdleConns:        10,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     30 * time.Second,
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+			}),
+		},
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		authorization: authorization,
+		logger:        logger.With("component", "jira_client"),
+	}
+}
+
+// CreateIssue posts payload to /rest/api/2/issue.
+func (c *HTTPJiraClient) CreateIssue(ctx context.Context, payload []byte) (string, error) {
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", payload, &created); err != nil {
+		return "", err
+	}
+	if created.Key == "" {
+		return "", fmt.Errorf("jira: no issue key in create response")
+	}
+	return created.Key, nil
+}
+
+// FindOpenIssue searches /rest/api/2/search with JQL.
+func (c *HTTPJiraClient) FindOpenIssue(ctx context.Context, project, label string) (string, error) {
+	jql := fmt.Sprintf("project = %s AND labels = %s AND statusCategory != Done ORDER BY created DESC",
+		jiraQuote(project), jiraQuote(label))
+	payload, err := json.Marshal(map[string]any{
+		"jql":        jql,
+		"maxResults": 1,
+		"fields":     []string{"status"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	var result struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/search", payload, &result); err != nil {
+		return "", err
+	}
+	if len(result.Issues) == 0 {
+		return "", nil
+	}
+	return result.Issues[0].Key, nil
+}
+
+// AddComment posts to /rest/api/2/issue/{key}/comment.
+func (c *HTTPJiraClient) AddComment(ctx context.Context, issueKey, body string) error {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return c.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(issueKey)+"/comment", payload, nil)
+}
+
+// TransitionIssue looks up the transitions available to the issue and
+// posts the matching one to /rest/api/2/issue/{key}/transitions.
+func (c *HTTPJiraClient) TransitionIssue(ctx context.Context, issueKey, transition string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(issueKey) + "/transitions"
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &available); err != nil {
+		return err
+	}
+
+	id := ""
+	for _, t := range available.Transitions {
+		if strings.EqualFold(t.Name, transition) || strings.EqualFold(t.To.Name, transition) {
+			id = t.ID
+			break
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("%w: %q for issue %s", ErrJiraTransitionNotFound, transition, issueKey)
+	}
+
+	payload, err := json.Marshal(map[string]any{"transition": map[string]string{"id": id}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return c.do(ctx, http.MethodPost, path, payload, nil)
+}
+
+// do sends a request and decodes a successful JSON response into out
+// (when not nil).
+func (c *HTTPJiraClient) do(ctx context.Context, method, path string, payload []byte, out any) error {
+	c.logger.DebugContext(ctx, "Sending request to JIRA", slog.String("method", method), slog.String("path", path))
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", c.authorization)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err := parseJiraResponse(resp, respBody); err != nil {
+		return err
+	}
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("jira: failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseJiraResponse returns nil for a successful response and an
+// *httperror.HTTPAPIError otherwise, with JIRA's error messages.
+func parseJiraResponse(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	message := truncateString(string(body), 512)
+	var errBody struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if json.Unmarshal(body, &errBody) == nil {
+		messages := append([]string(nil), errBody.ErrorMessages...)
+		fields := make([]string, 0, len(errBody.Errors))
+		for field := range errBody.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			messages = append(messages, field+": "+errBody.Errors[field])
+		}
+		if len(messages) > 0 {
+			message = strings.Join(messages, "; ")
+		}
+	}
+	apiErr := &httperror.HTTPAPIError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		Provider:   ProviderJira,
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			apiErr.RetryAfter = seconds
+		}
+	}
+	return apiErr
+}
+
+// jiraQuote quotes s as a JQL string literal.
+func jiraQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// jiraClients caches JIRA clients by URL and Authorization header.
+type jiraClients struct {
+	*clientCache[JiraClient]
+	logger *slog.Logger
+}
+
+func newJiraClients(logger *slog.Logger) *jiraClients {
+	return &jiraClients{
+		clientCache: newClientCache[JiraClient](func(target *core.PublishingTarget) string {
+			return target.URL + "\x00" + target.Headers["Authorization"]
+		}),
+		logger: logger,
+	}
+}
+
+// get returns the client for target.
+func (c *jiraClients) get(target *core.PublishingTarget) (JiraClient, error) {
+	authorization := target.Headers["Authorization"]
+	if authorization == "" {
+		return nil, ErrMissingJiraCredentials
+	}
+	return c.getOrCreate(target, func() JiraClient {
+		return NewHTTPJiraClient(target.URL, authorization, 10*time.Second, c.logger)
+	}), nil
+}
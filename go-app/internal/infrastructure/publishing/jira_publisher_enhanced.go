@@ -0,0 +1,407 @@
+package publishing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/grouping"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// jira_publisher_enhanced.go - JIRA issue publisher (REST API v2)
+
+// MetadataJiraIssueKey is the enrichment metadata key of the JIRA issue
+// opened for an alert (see PublishingCoordinator.SetJiraIssueKeys).
+const MetadataJiraIssueKey = "jira_issue_key"
+
+// ErrMissingJiraProject is returned when a JIRA target has no project header.
+var ErrMissingJiraProject = errors.New("jira: project not found in target configuration")
+
+// Target headers configuring JIRA issues.
+const (
+	jiraProjectHeader           = "project"            // project key, required
+	jiraIssueTypeHeader         = "issue_type"         // default "Bug"
+	jiraResolveTransitionHeader = "resolve_transition" // default "Done"; "" keeps issues open
+	jiraPriorityHeaderPrefix    = "priority_"          // priority_<severity>: JIRA priority name, "" for none
+)
+
+// Defaults of JIRA targets.
+const (
+	defaultJiraIssueType         = "Bug"
+	defaultJiraResolveTransition = "Done"
+)
+
+// defaultJiraGroupBy groups alerts into issues when the target has no
+// group_by header.
+var defaultJiraGroupBy = []string{"alertname"}
+
+// defaultJiraPriorities map severities to the default JIRA priority scheme.
+var defaultJiraPriorities = map[string]string{
+	string(core.SeverityCritical): "Highest",
+	string(core.SeverityWarning):  "High",
+	string(core.SeverityInfo):     "Low",
+	string(core.SeverityNoise):    "Lowest",
+}
+
+// jiraTargetConfig is the JIRA issue configuration of a target.
+type jiraTargetConfig struct {
+	project           string
+	issueType         string
+	resolveTransition string
+	groupBy           []string
+}
+
+// parseJiraTargetConfig reads the JIRA issue configuration from the target
+// headers.
+func parseJiraTargetConfig(target *core.PublishingTarget) (jiraTargetConfig, error) {
+	headers := target.Headers
+	cfg := jiraTargetConfig{
+		project:           strings.TrimSpace(headers[jiraProjectHeader]),
+		issueType:         strings.TrimSpace(headers[jiraIssueTypeHeader]),
+		resolveTransition: defaultJiraResolveTransition,
+		groupBy:           defaultJiraGroupBy,
+	}
+	if cfg.project == "" {
+		return jiraTargetConfig{}, ErrMissingJiraProject
+	}
+	if cfg.issueType == "" {
+		cfg.issueType = defaultJiraIssueType
+	}
+	if transition, ok := headers[jiraResolveTransitionHeader]; ok {
+		cfg.resolveTransition = strings.TrimSpace(transition)
+	}
+	if raw, ok := headers[targetGroupByHeader]; ok {
+		cfg.groupBy = parseGroupBy(raw)
+	}
+	return cfg, nil
+}
+
+// jiraPriority returns the JIRA priority of enrichedAlert: the
+// priority_<severity> header of the target, else the default mapping.
+// "" leaves the priority to JIRA.
+func jiraPriority(target *core.PublishingTarget, enrichedAlert *core.EnrichedAlert) string {
+	severity := strings.ToLower(jiraSeverity(enrichedAlert))
+	if priority, ok := target.Headers[jiraPriorityHeaderPrefix+severity]; ok {
+		return strings.TrimSpace(priority)
+	}
+	if priority, ok := defaultJiraPriorities[severity]; ok {
+		return priority
+	}
+	return "Medium"
+}
+
+// jiraGroupLabel is the JIRA label identifying the issue of a target group,
+// so that open issues are found again after a restart.
+func jiraGroupLabel(target *core.PublishingTarget, key grouping.GroupKey) string {
+	sum := sha256.Sum256([]byte(target.Name + "\x00" + string(key)))
+	return "amp-group-" + hex.EncodeToString(sum[:8])
+}
+
+// EnhancedJiraPublisher implements AlertPublisher for JIRA.
+//
+// Alerts are grouped by the group_by header of the target (default:
+// alertname), and every group has one open issue:
+//   - the first firing alert of a group opens the issue (or reuses an open
+//     issue labelled with the group label, e.g. after a restart)
+//   - further alerts of the group are added to it as comments
+//   - when every alert of the group is resolved, the issue is moved through
+//     the resolve_transition
+//
+// The issue key of every firing alert is kept for cross-linking: the
+// publishing coordinator records it in the enrichment metadata of later
+// notifications (MetadataJiraIssueKey).
+type EnhancedJiraPublisher struct {
+	*BaseEnhancedPublisher                 // Embedded base publisher for common functionality
+	clients                *jiraClients    // JIRA clients by URL and credentials
+	issues                 *jiraIssueIndex // Open issues by target group
+	keyGen                 *grouping.GroupKeyGenerator
+}
+
+// NewEnhancedJiraPublisher creates a new JIRA publisher
+// metrics: Prometheus metrics recorder
+// formatter: Alert formatter used with core.FormatJira
+func NewEnhancedJiraPublisher(
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return newEnhancedJiraPublisher(newJiraClients(logger), newJiraIssueIndex(), metrics, formatter, logger)
+}
+
+func newEnhancedJiraPublisher(clients *jiraClients, issues *jiraIssueIndex, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedJiraPublisher {
+	return &EnhancedJiraPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "jira_publisher"),
+		),
+		clients: clients,
+		issues:  issues,
+		keyGen:  grouping.NewGroupKeyGenerator(),
+	}
+}
+
+// Publish opens, comments on or resolves the JIRA issue of the group of
+// enrichedAlert.
+func (p *EnhancedJiraPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	cfg, err := parseJiraTargetConfig(target)
+	if err != nil {
+		return err
+	}
+	client, err := p.clients.get(target)
+	if err != nil {
+		return err
+	}
+	alert := enrichedAlert.Alert
+	key, err := p.keyGen.GenerateKey(alert.Labels, cfg.groupBy)
+	if err != nil {
+		return fmt.Errorf("jira: group key: %w", err)
+	}
+
+	p.LogPublishStart(ctx, v2.ProviderJira, enrichedAlert)
+
+	issue := p.issues.lock(target.Name, key)
+	defer p.issues.unlock(issue)
+
+	switch alert.Status {
+	case core.StatusFiring:
+		return p.publishFiring(ctx, client, cfg, issue, enrichedAlert, target, key)
+	case core.StatusResolved:
+		return p.publishResolved(ctx, client, cfg, issue, enrichedAlert, target, key)
+	default:
+		return fmt.Errorf("unknown alert status: %s", alert.Status)
+	}
+}
+
+// Name returns publisher name
+func (p *EnhancedJiraPublisher) Name() string {
+	return "JIRA"
+}
+
+func (p *EnhancedJiraPublisher) publishFiring(ctx context.Context, client JiraClient, cfg jiraTargetConfig, issue *jiraIssue, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget, key grouping.GroupKey) error {
+	alert := enrichedAlert.Alert
+	if _, known := issue.firing[alert.Fingerprint]; known {
+		return nil // Repeated notification
+	}
+
+	if issue.key == "" {
+		if err := p.findIssue(ctx, client, cfg, issue, alert.Fingerprint, target, key); err != nil {
+			return err
+		}
+	}
+	if issue.key == "" {
+		if err := p.createIssue(ctx, client, cfg, issue, enrichedAlert, target, key); err != nil {
+			return err
+		}
+	} else {
+		comment := fmt.Sprintf("Alert firing: *%s* (%s)", alert.AlertName, alert.Fingerprint)
+		if summary := alert.Annotations["summary"]; summary != "" {
+			comment += "\n" + summary
+		}
+		if err := p.call(ctx, "add_comment", alert.Fingerprint, target, func() error {
+			return client.AddComment(ctx, issue.key, comment)
+		}); err != nil {
+			return err
+		}
+	}
+	p.issues.addFiring(issue, alert.Fingerprint)
+	return nil
+}
+
+func (p *EnhancedJiraPublisher) publishResolved(ctx context.Context, client JiraClient, cfg jiraTargetConfig, issue *jiraIssue, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget, key grouping.GroupKey) error {
+	alert := enrichedAlert.Alert
+	if issue.key == "" {
+		// Not opened by this instance: resolve an open issue of the group
+		if err := p.findIssue(ctx, client, cfg, issue, alert.Fingerprint, target, key); err != nil {
+			return err
+		}
+		if issue.key == "" {
+			return nil
+		}
+	}
+
+	p.issues.removeFiring(issue, alert.Fingerprint)
+	if len(issue.firing) > 0 {
+		comment := fmt.Sprintf("Alert resolved: *%s* (%s), %d still firing", alert.AlertName, alert.Fingerprint, len(issue.firing))
+		return p.call(ctx, "add_comment", alert.Fingerprint, target, func() error {
+			return client.AddComment(ctx, issue.key, comment)
+		})
+	}
+
+	if cfg.resolveTransition != "" {
+		if err := p.call(ctx, "transition_issue", alert.Fingerprint, target, func() error {
+			return client.TransitionIssue(ctx, issue.key, cfg.resolveTransition)
+		}); err != nil {
+			return err
+		}
+	}
+	p.issues.close(issue)
+	return nil
+}
+
+// findIssue looks up an open issue of the group in JIRA.
+func (p *EnhancedJiraPublisher) findIssue(ctx context.Context, client JiraClient, cfg jiraTargetConfig, issue *jiraIssue, fingerprint string, target *core.PublishingTarget, key grouping.GroupKey) error {
+	return p.call(ctx, "search_issues", fingerprint, target, func() error {
+		found, err := client.FindOpenIssue(ctx, cfg.project, jiraGroupLabel(target, key))
+		issue.key = found
+		return err
+	})
+}
+
+func (p *EnhancedJiraPublisher) createIssue(ctx context.Context, client JiraClient, cfg jiraTargetConfig, issue *jiraIssue, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget, key grouping.GroupKey) error {
+	payload, err := p.GetFormatter().FormatAlert(ctx, enrichedAlert, core.FormatJira)
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderJira, "create_issue", "format_error")
+		}
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+	fields, ok := payload["fields"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("jira: formatter returned no issue fields")
+	}
+	fields["project"] = map[string]string{"key": cfg.project}
+	fields["issuetype"] = map[string]string{"name": cfg.issueType}
+	if priority := jiraPriority(target, enrichedAlert); priority != "" {
+		fields["priority"] = map[string]string{"name": priority}
+	}
+	labels, _ := fields["labels"].([]string)
+	fields["labels"] = append(labels, jiraGroupLabel(target, key))
+
+	payloadBytes, err := json.Marshal(map[string]any{"fields": fields})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordPayloadSize(v2.ProviderJira, len(payloadBytes))
+	}
+
+	return p.call(ctx, "create_issue", enrichedAlert.Alert.Fingerprint, target, func() error {
+		created, err := client.CreateIssue(ctx, payloadBytes)
+		issue.key = created
+		return err
+	})
+}
+
+// call runs a JIRA API request and records its outcome.
+func (p *EnhancedJiraPublisher) call(ctx context.Context, endpoint, fingerprint string, target *core.PublishingTarget, request func() error) error {
+	startTime := time.Now()
+	err := request()
+	duration := time.Since(startTime)
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordAPIDuration(v2.ProviderJira, endpoint, "POST", duration)
+	}
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderJira, endpoint, GetPublishingErrorType(err))
+		}
+		p.LogPublishError(ctx, v2.ProviderJira, fingerprint, err)
+		return fmt.Errorf("failed to %s in %s: %w", strings.ReplaceAll(endpoint, "_", " "), target.Name, err)
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordMessage(v2.ProviderJira, "success")
+	}
+	p.LogPublishSuccess(ctx, v2.ProviderJira, fingerprint, duration)
+	return nil
+}
+
+// jiraIssue is the open issue of a target group.
+type jiraIssue struct {
+	mu     sync.Mutex // Held while the group is published
+	id     string
+	key    string              // "" until opened or found
+	firing map[string]struct{} // Firing fingerprints of the group
+	closed bool                // Removed from the index
+}
+
+// jiraIssueIndex tracks the open JIRA issues of target groups and the
+// issue keys of firing alerts. Publishing to a group is serialized, so
+// that concurrent alerts of a new group open a single issue.
+type jiraIssueIndex struct {
+	mu            sync.Mutex
+	groups        map[string]*jiraIssue
+	byFingerprint map[string]string
+}
+
+func newJiraIssueIndex() *jiraIssueIndex {
+	return &jiraIssueIndex{
+		groups:        make(map[string]*jiraIssue),
+		byFingerprint: make(map[string]string),
+	}
+}
+
+// lock returns the locked issue of a target group.
+func (x *jiraIssueIndex) lock(target string, key grouping.GroupKey) *jiraIssue {
+	id := target + "\x00" + string(key)
+	for {
+		x.mu.Lock()
+		issue, ok := x.groups[id]
+		if !ok {
+			issue = &jiraIssue{id: id, firing: make(map[string]struct{})}
+			x.groups[id] = issue
+		}
+		x.mu.Unlock()
+
+		issue.mu.Lock()
+		if !issue.closed {
+			return issue
+		}
+		// Closed while waiting: start over with a new issue
+		issue.mu.Unlock()
+	}
+}
+
+func (x *jiraIssueIndex) unlock(issue *jiraIssue) {
+	// Forget groups whose issue could not be opened
+	if issue.key == "" && !issue.closed {
+		x.close(issue)
+	}
+	issue.mu.Unlock()
+}
+
+func (x *jiraIssueIndex) addFiring(issue *jiraIssue, fingerprint string) {
+	issue.firing[fingerprint] = struct{}{}
+	x.mu.Lock()
+	x.byFingerprint[fingerprint] = issue.key
+	x.mu.Unlock()
+}
+
+func (x *jiraIssueIndex) removeFiring(issue *jiraIssue, fingerprint string) {
+	delete(issue.firing, fingerprint)
+	x.mu.Lock()
+	if x.byFingerprint[fingerprint] == issue.key {
+		delete(x.byFingerprint, fingerprint)
+	}
+	x.mu.Unlock()
+}
+
+// close removes issue from the index. Caller holds issue.mu.
+func (x *jiraIssueIndex) close(issue *jiraIssue) {
+	issue.closed = true
+	x.mu.Lock()
+	delete(x.groups, issue.id)
+	for fingerprint := range issue.firing {
+		if x.byFingerprint[fingerprint] == issue.key {
+			delete(x.byFingerprint, fingerprint)
+		}
+	}
+	x.mu.Unlock()
+}
+
+// issueKey returns the key of the open issue of a firing alert.
+func (x *jiraIssueIndex) issueKey(fingerprint string) (string, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	key, ok := x.byFingerprint[fingerprint]
+	return key, ok
+}
@@ -0,0 +1,290 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+func newJiraTestAlert(fingerprint, instance string, status core.AlertStatus) *core.EnrichedAlert {
+	return &core.EnrichedAlert{
+		Alert: &core.Alert{
+			Fingerprint: fingerprint,
+			AlertName:   "DiskFull",
+			Status:      status,
+			Labels:      map[string]string{"alertname": "DiskFull", "severity": "critical", "instance": instance},
+			Annotations: map[string]string{"summary": "Disk almost full", "runbook_url": "https://runbooks.example.com/disk"},
+			StartsAt:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestFormatJira_Fields(t *testing.T) {
+	alert := newJiraTestAlert("fp-1", "db 1", core.StatusFiring)
+	payload, err := NewAlertFormatter("https://amp.example.com").FormatAlert(context.Background(), alert, core.FormatJira)
+	require.NoError(t, err)
+
+	fields := payload["fields"].(map[string]any)
+	assert.Equal(t, "DiskFull: Disk almost full", fields["summary"])
+	assert.Equal(t, []string{"alertname:DiskFull", "instance:db_1", "severity:critical", "amp"}, fields["labels"])
+	description := fields["description"].(string)
+	assert.Contains(t, description, "*Fingerprint:* fp-1")
+	assert.Contains(t, description, "|instance|db 1|")
+	assert.Contains(t, description, "[Runbook|https://runbooks.example.com/disk]")
+	assert.Contains(t, description, "[Silence|https://amp.example.com/")
+}
+
+// fakeJira is a JIRA REST API v2 server recording the requests it receives.
+type fakeJira struct {
+	mu          sync.Mutex
+	created     []map[string]any // create issue fields
+	comments    map[string][]string
+	transitions map[string][]string // issue key -> transition IDs
+	open        map[string]string   // group label -> open issue key
+}
+
+func newFakeJira(t *testing.T) (*fakeJira, *httptest.Server) {
+	t.Helper()
+	f := &fakeJira{
+		comments:    make(map[string][]string),
+		transitions: make(map[string][]string),
+		open:        make(map[string]string),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic dXNlcjp0b2tlbg==" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errorMessages":["Unauthorized"]}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		_ = json.Unmarshal(body, &req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		path := strings.TrimPrefix(r.URL.Path, "/rest/api/2/")
+		switch {
+		case path == "search":
+			issues := []map[string]string{}
+			for label, key := range f.open {
+				if strings.Contains(req["jql"].(string), `labels = "`+label+`"`) {
+					issues = append(issues, map[string]string{"key": key})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"issues": issues})
+		case path == "issue":
+			fields := req["fields"].(map[string]any)
+			f.created = append(f.created, fields)
+			_ = json.NewEncoder(w).Encode(map[string]string{"key": fmt.Sprintf("OPS-%d", len(f.created))})
+		case strings.HasSuffix(path, "/comment"):
+			key := strings.TrimSuffix(strings.TrimPrefix(path, "issue/"), "/comment")
+			f.comments[key] = append(f.comments[key], req["body"].(string))
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasSuffix(path, "/transitions") && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"11","name":"Start Progress","to":{"name":"In Progress"}},{"id":"31","name":"Resolve","to":{"name":"Done"}}]}`))
+		case strings.HasSuffix(path, "/transitions"):
+			key := strings.TrimSuffix(strings.TrimPrefix(path, "issue/"), "/transitions")
+			f.transitions[key] = append(f.transitions[key], req["transition"].(map[string]any)["id"].(string))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func newJiraTestTarget(url string) *core.PublishingTarget {
+	return &core.PublishingTarget{
+		Name:   "ops-jira",
+		Type:   "jira",
+		URL:    url,
+		Format: core.FormatJira,
+		Headers: map[string]string{
+			"Authorization":    "Basic dXNlcjp0b2tlbg==",
+			"project":          "OPS",
+			"issue_type":       "Incident",
+			"priority_warning": "Medium",
+		},
+	}
+}
+
+func TestEnhancedJiraPublisher_IssuePerGroup(t *testing.T) {
+	fake, server := newFakeJira(t)
+	target := newJiraTestTarget(server.URL)
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+
+	// The project and issue type come from the target headers
+	publisher, err := factory.CreatePublisher(target.Type)
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedJiraPublisher{}, publisher)
+
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-1", "db-1", core.StatusFiring), target))
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-1", "db-1", core.StatusFiring), target))
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-2", "db-2", core.StatusFiring), target))
+
+	// One issue for the alertname group, the second alert as a comment
+	require.Len(t, fake.created, 1)
+	fields := fake.created[0]
+	assert.Equal(t, map[string]any{"key": "OPS"}, fields["project"])
+	assert.Equal(t, map[string]any{"name": "Incident"}, fields["issuetype"])
+	assert.Equal(t, map[string]any{"name": "Highest"}, fields["priority"])
+	assert.Contains(t, fields["labels"], "instance:db-1")
+	assert.Contains(t, fields["labels"], jiraGroupLabel(target, "alertname=DiskFull"))
+	require.Len(t, fake.comments["OPS-1"], 1)
+	assert.Contains(t, fake.comments["OPS-1"][0], "fp-2")
+
+	key, ok := factory.JiraIssueKey("fp-2")
+	assert.True(t, ok)
+	assert.Equal(t, "OPS-1", key)
+
+	// Resolving the first alert leaves the issue open
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-1", "db-1", core.StatusResolved), target))
+	assert.Empty(t, fake.transitions["OPS-1"])
+	assert.Contains(t, fake.comments["OPS-1"][1], "1 still firing")
+
+	// Resolving the last one resolves it ("Done" matches the target status)
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-2", "db-2", core.StatusResolved), target))
+	assert.Equal(t, []string{"31"}, fake.transitions["OPS-1"])
+	_, ok = factory.JiraIssueKey("fp-2")
+	assert.False(t, ok)
+
+	// The next firing alert opens a new issue
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-1", "db-1", core.StatusFiring), target))
+	assert.Len(t, fake.created, 2)
+}
+
+func TestEnhancedJiraPublisher_ReusesOpenIssue(t *testing.T) {
+	fake, server := newFakeJira(t)
+	target := newJiraTestTarget(server.URL)
+	target.Headers["group_by"] = "instance"
+	fake.open[jiraGroupLabel(target, "instance=db-1")] = "OPS-7"
+
+	// A new instance (e.g. after a restart) finds the issue of the group
+	publisher := NewEnhancedJiraPublisher(nil, NewAlertFormatter(""), slog.Default())
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-1", "db-1", core.StatusFiring), target))
+	assert.Empty(t, fake.created)
+	assert.Len(t, fake.comments["OPS-7"], 1)
+
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-1", "db-1", core.StatusResolved), target))
+	assert.Equal(t, []string{"31"}, fake.transitions["OPS-7"])
+
+	// Resolved alerts without an open issue are ignored
+	require.NoError(t, publisher.Publish(ctx, newJiraTestAlert("fp-3", "db-3", core.StatusResolved), target))
+	assert.Empty(t, fake.created)
+}
+
+func TestEnhancedJiraPublisher_ConcurrentAlertsOpenOneIssue(t *testing.T) {
+	fake, server := newFakeJira(t)
+	target := newJiraTestTarget(server.URL)
+	publisher := NewEnhancedJiraPublisher(nil, NewAlertFormatter(""), slog.Default())
+
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			alert := newJiraTestAlert(fmt.Sprintf("fp-%d", i), fmt.Sprintf("db-%d", i), core.StatusFiring)
+			assert.NoError(t, publisher.Publish(context.Background(), alert, target))
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, fake.created, 1)
+	assert.Len(t, fake.comments["OPS-1"], 4)
+}
+
+func TestEnhancedJiraPublisher_Errors(t *testing.T) {
+	_, server := newFakeJira(t)
+	publisher := NewEnhancedJiraPublisher(nil, NewAlertFormatter(""), slog.Default())
+	alert := newJiraTestAlert("fp-1", "db-1", core.StatusFiring)
+
+	target := newJiraTestTarget(server.URL)
+	delete(target.Headers, "project")
+	assert.ErrorIs(t, publisher.Publish(context.Background(), alert, target), ErrMissingJiraProject)
+
+	target = newJiraTestTarget(server.URL)
+	delete(target.Headers, "Authorization")
+	assert.ErrorIs(t, publisher.Publish(context.Background(), alert, target), ErrMissingJiraCredentials)
+
+	target = newJiraTestTarget(server.URL)
+	target.Headers["Authorization"] = "Basic wrong"
+	err := publisher.Publish(context.Background(), alert, target)
+	apiErr := AsPublishingError(err)
+	require.NotNil(t, apiErr, "error = %v", err)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "Unauthorized", apiErr.Message)
+	assert.Equal(t, ProviderJira, apiErr.Provider)
+
+	// Unknown transitions are not retried
+	target = newJiraTestTarget(server.URL)
+	target.Headers["resolve_transition"] = "Close Issue"
+	require.NoError(t, publisher.Publish(context.Background(), alert, target))
+	err = publisher.Publish(context.Background(), newJiraTestAlert("fp-1", "db-1", core.StatusResolved), target)
+	assert.ErrorIs(t, err, ErrJiraTransitionNotFound)
+	assert.NotEqual(t, QueueErrorTypeTransient, classifyPublishingError(err))
+}
+
+func TestJiraPriority(t *testing.T) {
+	target := &core.PublishingTarget{Headers: map[string]string{"priority_warning": "Medium", "priority_info": ""}}
+	withSeverity := func(severity string) *core.EnrichedAlert {
+		return &core.EnrichedAlert{Alert: &core.Alert{Labels: map[string]string{"severity": severity}}}
+	}
+
+	assert.Equal(t, "Highest", jiraPriority(target, withSeverity("critical")))
+	assert.Equal(t, "Medium", jiraPriority(target, withSeverity("warning")))
+	assert.Equal(t, "", jiraPriority(target, withSeverity("info")), "empty header leaves the priority to JIRA")
+	assert.Equal(t, "Medium", jiraPriority(target, withSeverity("unknown")))
+}
+
+func TestParseJiraResponse_FieldErrors(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusBadRequest, Header: http.Header{}}
+	err := parseJiraResponse(resp, []byte(`{"errorMessages":[],"errors":{"priority":"Priority is invalid","issuetype":"Specify a valid issue type"}}`))
+	apiErr := AsPublishingError(err)
+	require.NotNil(t, apiErr)
+	assert.Equal(t, "issuetype: Specify a valid issue type; priority: Priority is invalid", apiErr.Message)
+	assert.Equal(t, httperror.ClassPermanent, httperror.Classify(err))
+}
+
+// jiraIssueKeysFunc adapts a function to JiraIssueKeys.
+type jiraIssueKeysFunc func(fingerprint string) (string, bool)
+
+func (f jiraIssueKeysFunc) JiraIssueKey(fingerprint string) (string, bool) { return f(fingerprint) }
+
+func TestPublishingCoordinator_LinkJiraIssue(t *testing.T) {
+	c := &PublishingCoordinator{}
+	alert := &core.EnrichedAlert{
+		Alert:              &core.Alert{Fingerprint: "fp-1"},
+		EnrichmentMetadata: map[string]any{core.MetadataEffectiveSeverity: "critical"},
+	}
+	assert.Same(t, alert, c.linkJiraIssue(alert), "no issue keys configured")
+
+	c.SetJiraIssueKeys(jiraIssueKeysFunc(func(fingerprint string) (string, bool) {
+		return "OPS-1", fingerprint == "fp-1"
+	}))
+	linked := c.linkJiraIssue(alert)
+	assert.Equal(t, "OPS-1", linked.EnrichmentMetadata[MetadataJiraIssueKey])
+	assert.Equal(t, "critical", linked.EnrichmentMetadata[core.MetadataEffectiveSeverity])
+	assert.NotContains(t, alert.EnrichmentMetadata, MetadataJiraIssueKey, "caller's alert is not modified")
+
+	other := &core.EnrichedAlert{Alert: &core.Alert{Fingerprint: "fp-2"}}
+	assert.Same(t, other, c.linkJiraIssue(other))
+}
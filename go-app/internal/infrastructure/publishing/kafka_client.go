@@ -0,0 +1,213 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// kafka_client.go - Kafka REST Proxy client (produce API v2)
+
+// Content types of REST Proxy v2 produce requests, by embedded format.
+const (
+	kafkaJSONContentType = "application/vnd.kafka.json.v2+json"
+	kafkaAvroContentType = "application/vnd.kafka.avro.v2+json"
+	kafkaAcceptType      = "application/vnd.kafka.v2+json"
+)
+
+// Per-record error codes of produce responses.
+const (
+	kafkaRecordErrorNonRetriable = 1
+	kafkaRecordErrorRetriable    = 2
+)
+
+// KafkaRecord is a record of a produce request. Without a partition, the
+// producer of the REST Proxy picks the partition by hashing the key.
+type KafkaRecord struct {
+	Key       string `json:"key"`
+	Value     any    `json:"value"`
+	Partition *int   `json:"partition,omitempty"`
+}
+
+// KafkaProduceRequest is the body of a REST Proxy produce request. Avro
+// requests carry the schemas, or the ID of a registered value schema.
+type KafkaProduceRequest struct {
+	KeySchema     string        `json:"key_schema,omitempty"`
+	ValueSchema   string        `json:"value_schema,omitempty"`
+	ValueSchemaID int           `json:"value_schema_id,omitempty"`
+	Records       []KafkaRecord `json:"records"`
+}
+
+// KafkaClient produces records to Kafka topics.
+//
+// Failed requests are returned as *httperror.HTTPAPIError with
+// ProviderKafka, so that the publishing queue can classify them.
+type KafkaClient interface {
+	// Produce writes a JSON-encoded KafkaProduceRequest to topic.
+	// contentType selects the embedded format (JSON or Avro).
+	Produce(ctx context.Context, topic, contentType string, payload []byte) error
+}
+
+// HTTPKafkaClient implements KafkaClient with the produce API of a Kafka
+// REST Proxy (Confluent REST Proxy v2 and compatible proxies).
+//
+// Producer settings such as acks and idempotence are configured on the
+// proxy. The client does not retry: failures the proxy reports as
+// retriable are returned as 503 and retried by the publishing queue.
+type HTTPKafkaClient struct {
+	httpClient    *http.Client
+	baseURL       string
+	authorization string
+	logger        *slog.Logger
+}
+
+// NewHTTPKafkaClient creates a new Kafka REST Proxy client
+// baseURL: REST Proxy URL, e.g. https://kafka-rest.example.com
+// authorization: Authorization header value ("" for none)
+// timeout: request timeout (<= 0 uses 10s)
+func NewHTTPKafkaClient(baseURL, authorization string, timeout time.Duration, logger *slog.Logger) KafkaClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPKafkaClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: newOutboundTransport(&http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12, // TLS 1.2+ required
+				},
+				MaxIdleConns:        10,
+				MaxIdleConnsPerHost: 4,
+				IdleConnTimeout:     30 * time.Second,
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+			}),
+		},
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		authorization: authorization,
+		logger:        logger.With("component", "kafka_client"),
+	}
+}
+
+// Produce posts payload to /topics/{topic}.
+func (c *HTTPKafkaClient) Produce(ctx context.Context, topic, contentType string, payload []byte) error {
+	c.logger.DebugContext(ctx, "Producing to Kafka REST Proxy", slog.String("topic", topic))
+
+	endpoint := c.baseURL + "/topics/" + url.PathEscape(topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", kafkaAcceptType)
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	return parseKafkaResponse(resp, body)
+}
+
+// parseKafkaResponse returns nil when every record was written and an
+// *httperror.HTTPAPIError otherwise. A successful response can still carry
+// per-record errors: retriable ones are reported as 503, others as 422.
+func parseKafkaResponse(resp *http.Response, body []byte) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := truncateString(string(body), 512)
+		var errBody struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if json.Unmarshal(body, &errBody) == nil && errBody.Message != "" {
+			message = fmt.Sprintf("%s (error code %d)", errBody.Message, errBody.ErrorCode)
+		}
+		apiErr := &httperror.HTTPAPIError{
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Provider:   ProviderKafka,
+		}
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			if seconds, err := strconv.Atoi(retryAfter); err == nil {
+				apiErr.RetryAfter = seconds
+			}
+		}
+		return apiErr
+	}
+
+	var result struct {
+		Offsets []struct {
+			Partition int     `json:"partition"`
+			ErrorCode *int    `json:"error_code"`
+			Error     *string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode produce response: %w", err)
+	}
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode == nil {
+			continue
+		}
+		statusCode := http.StatusUnprocessableEntity
+		if *offset.ErrorCode == kafkaRecordErrorRetriable {
+			statusCode = http.StatusServiceUnavailable
+		}
+		message := "record not written"
+		if offset.Error != nil && *offset.Error != "" {
+			message = *offset.Error
+		}
+		return &httperror.HTTPAPIError{
+			StatusCode: statusCode,
+			Message:    fmt.Sprintf("%s (partition %d)", message, offset.Partition),
+			Provider:   ProviderKafka,
+		}
+	}
+	return nil
+}
+
+// kafkaClients caches Kafka REST Proxy clients by URL and Authorization
+// header.
+type kafkaClients struct {
+	*clientCache[KafkaClient]
+	logger *slog.Logger
+}
+
+func newKafkaClients(logger *slog.Logger) *kafkaClients {
+	return &kafkaClients{
+		clientCache: newClientCache[KafkaClient](func(target *core.PublishingTarget) string {
+			return target.URL + "\x00" + target.Headers["Authorization"]
+		}),
+		logger: logger,
+	}
+}
+
+// get returns the client for target.
+func (c *kafkaClients) get(target *core.PublishingTarget) KafkaClient {
+	return c.getOrCreate(target, func() KafkaClient {
+		return NewHTTPKafkaClient(target.URL, target.Headers["Authorization"], 10*time.Second, c.logger)
+	})
+}
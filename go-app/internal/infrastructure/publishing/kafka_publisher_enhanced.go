@@ -0,0 +1,222 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// kafka_publisher_enhanced.go - Kafka alert event publisher (via REST Proxy)
+
+// ErrMissingKafkaTopic is returned when a Kafka target has no topic header.
+var ErrMissingKafkaTopic = errors.New("kafka: topic not found in target configuration")
+
+// Target headers configuring Kafka delivery.
+const (
+	kafkaTopicHeader         = "topic"           // required
+	kafkaPartitionHeader     = "partition"       // fixed partition; default: by key
+	kafkaEncodingHeader      = "encoding"        // json (default) or avro
+	kafkaValueSchemaIDHeader = "value_schema_id" // registered Avro value schema
+	kafkaDeliveryHeader      = "delivery"        // at_least_once (default) or at_most_once
+)
+
+// Delivery guarantees of Kafka targets.
+const (
+	kafkaDeliveryAtLeastOnce = "at_least_once"
+	kafkaDeliveryAtMostOnce  = "at_most_once"
+)
+
+// kafkaKeyAvroSchema is the Avro schema of record keys (fingerprints).
+const kafkaKeyAvroSchema = `"string"`
+
+// kafkaEventAvroSchema is the Avro schema of the events built by
+// formatKafka. Consumers that need a registered schema can register it and
+// configure its ID with the value_schema_id header.
+const kafkaEventAvroSchema = `{
+  "type": "record",
+  "name": "AlertEvent",
+  "namespace": "io.amp.alerts",
+  "fields": [
+    {"name": "event_type", "type": "string"},
+    {"name": "fingerprint", "type": "string"},
+    {"name": "alert_name", "type": "string"},
+    {"name": "status", "type": "string"},
+    {"name": "severity", "type": "string", "default": ""},
+    {"name": "labels", "type": {"type": "map", "values": "string"}, "default": {}},
+    {"name": "annotations", "type": {"type": "map", "values": "string"}, "default": {}},
+    {"name": "starts_at", "type": "string"},
+    {"name": "ends_at", "type": "string", "default": ""},
+    {"name": "generator_url", "type": "string", "default": ""},
+    {"name": "classification_severity", "type": "string", "default": ""},
+    {"name": "classification_confidence", "type": "double", "default": 0},
+    {"name": "classification_reasoning", "type": "string", "default": ""},
+    {"name": "recommendations", "type": {"type": "array", "items": "string"}, "default": []},
+    {"name": "processed_at", "type": "string"}
+  ]
+}`
+
+// kafkaTargetConfig is the Kafka delivery configuration of a target.
+type kafkaTargetConfig struct {
+	topic         string
+	partition     *int
+	avro          bool
+	valueSchemaID int
+	atMostOnce    bool
+}
+
+// parseKafkaTargetConfig reads the Kafka delivery configuration from the
+// target headers.
+func parseKafkaTargetConfig(target *core.PublishingTarget) (kafkaTargetConfig, error) {
+	headers := target.Headers
+	cfg := kafkaTargetConfig{topic: strings.TrimSpace(headers[kafkaTopicHeader])}
+	if cfg.topic == "" {
+		return kafkaTargetConfig{}, ErrMissingKafkaTopic
+	}
+
+	if raw := headers[kafkaPartitionHeader]; raw != "" {
+		partition, err := strconv.Atoi(raw)
+		if err != nil || partition < 0 {
+			return kafkaTargetConfig{}, fmt.Errorf("kafka: invalid partition %q", raw)
+		}
+		cfg.partition = &partition
+	}
+
+	switch encoding := strings.ToLower(headers[kafkaEncodingHeader]); encoding {
+	case "", "json":
+	case "avro":
+		cfg.avro = true
+	default:
+		return kafkaTargetConfig{}, fmt.Errorf("kafka: invalid encoding %q (json, avro)", encoding)
+	}
+	if raw := headers[kafkaValueSchemaIDHeader]; raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 || !cfg.avro {
+			return kafkaTargetConfig{}, fmt.Errorf("kafka: invalid value_schema_id %q (requires encoding avro)", raw)
+		}
+		cfg.valueSchemaID = id
+	}
+
+	switch delivery := strings.ToLower(headers[kafkaDeliveryHeader]); delivery {
+	case "", kafkaDeliveryAtLeastOnce:
+	case kafkaDeliveryAtMostOnce:
+		cfg.atMostOnce = true
+	default:
+		return kafkaTargetConfig{}, fmt.Errorf("kafka: invalid delivery %q (%s, %s)", delivery, kafkaDeliveryAtLeastOnce, kafkaDeliveryAtMostOnce)
+	}
+	return cfg, nil
+}
+
+// EnhancedKafkaPublisher implements AlertPublisher for Kafka topics.
+//
+// Every firing and resolved notification becomes an alert event record
+// (formatKafka) keyed by the alert fingerprint, so all events of an alert
+// land in one partition, in order, unless the target fixes the partition.
+//
+// Delivery guarantees:
+//   - at_least_once: failed produce requests are retried by the publishing
+//     queue; a request that timed out after reaching Kafka is written twice
+//   - at_most_once: failed produce requests are never retried
+type EnhancedKafkaPublisher struct {
+	*BaseEnhancedPublisher               // Embedded base publisher for common functionality
+	clients                *kafkaClients // Kafka REST Proxy clients by URL and credentials
+}
+
+// NewEnhancedKafkaPublisher creates a new Kafka publisher
+// metrics: Prometheus metrics recorder
+// formatter: Alert formatter used with core.FormatKafka
+func NewEnhancedKafkaPublisher(
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return newEnhancedKafkaPublisher(newKafkaClients(logger), metrics, formatter, logger)
+}
+
+func newEnhancedKafkaPublisher(clients *kafkaClients, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedKafkaPublisher {
+	return &EnhancedKafkaPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "kafka_publisher"),
+		),
+		clients: clients,
+	}
+}
+
+// Publish writes the alert event of enrichedAlert to the target topic.
+func (p *EnhancedKafkaPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	cfg, err := parseKafkaTargetConfig(target)
+	if err != nil {
+		return err
+	}
+	client := p.clients.get(target)
+
+	fingerprint := enrichedAlert.Alert.Fingerprint
+	p.LogPublishStart(ctx, v2.ProviderKafka, enrichedAlert)
+
+	event, err := p.GetFormatter().FormatAlert(ctx, enrichedAlert, core.FormatKafka)
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderKafka, "produce", "format_error")
+		}
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+
+	request := KafkaProduceRequest{
+		Records: []KafkaRecord{{Key: fingerprint, Value: event, Partition: cfg.partition}},
+	}
+	contentType := kafkaJSONContentType
+	if cfg.avro {
+		contentType = kafkaAvroContentType
+		request.KeySchema = kafkaKeyAvroSchema
+		if cfg.valueSchemaID > 0 {
+			request.ValueSchemaID = cfg.valueSchemaID
+		} else {
+			request.ValueSchema = kafkaEventAvroSchema
+		}
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordPayloadSize(v2.ProviderKafka, len(payload))
+	}
+
+	startTime := time.Now()
+	err = client.Produce(ctx, cfg.topic, contentType, payload)
+	duration := time.Since(startTime)
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordAPIDuration(v2.ProviderKafka, "produce", "POST", duration)
+	}
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderKafka, "produce", GetPublishingErrorType(err))
+		}
+		p.LogPublishError(ctx, v2.ProviderKafka, fingerprint, err)
+		err = fmt.Errorf("failed to produce to %s in %s: %w", cfg.topic, target.Name, err)
+		if cfg.atMostOnce {
+			return fmt.Errorf("%w (%w)", err, errNotRetried)
+		}
+		return err
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordMessage(v2.ProviderKafka, "success")
+	}
+	p.LogPublishSuccess(ctx, v2.ProviderKafka, fingerprint, duration)
+	return nil
+}
+
+// Name returns publisher name
+func (p *EnhancedKafkaPublisher) Name() string {
+	return "Kafka"
+}
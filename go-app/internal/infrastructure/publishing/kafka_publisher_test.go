@@ -0,0 +1,254 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+func newKafkaTestAlert(status core.AlertStatus) *core.EnrichedAlert {
+	processed := time.Date(2026, 3, 1, 12, 0, 5, 0, time.UTC)
+	return &core.EnrichedAlert{
+		Alert: &core.Alert{
+			Fingerprint: "kafka-fp",
+			AlertName:   "HighCPU",
+			Status:      status,
+			Labels:      map[string]string{"alertname": "HighCPU", "severity": "warning"},
+			StartsAt:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		Classification: &core.ClassificationResult{
+			Severity:        core.SeverityCritical,
+			Confidence:      0.9,
+			Reasoning:       "sustained saturation",
+			Recommendations: []string{"scale out"},
+		},
+		ProcessingTimestamp: &processed,
+	}
+}
+
+func TestFormatKafka_Event(t *testing.T) {
+	event, err := NewAlertFormatter("").FormatAlert(context.Background(), newKafkaTestAlert(core.StatusFiring), core.FormatKafka)
+	require.NoError(t, err)
+
+	assert.Equal(t, "alert.firing", event["event_type"])
+	assert.Equal(t, "kafka-fp", event["fingerprint"])
+	assert.Equal(t, "critical", event["severity"], "classification wins over the severity label")
+	assert.Equal(t, 0.9, event["classification_confidence"])
+	assert.Equal(t, []string{"scale out"}, event["recommendations"])
+	assert.Equal(t, map[string]string{}, event["annotations"])
+	assert.Equal(t, "", event["ends_at"])
+	assert.Equal(t, "2026-03-01T12:00:05Z", event["processed_at"])
+
+	// Every field of the event is in the Avro schema, and vice versa
+	var schema struct {
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(kafkaEventAvroSchema), &schema))
+	fields := make([]string, 0, len(schema.Fields))
+	for _, f := range schema.Fields {
+		fields = append(fields, f.Name)
+	}
+	keys := make([]string, 0, len(event))
+	for k := range event {
+		keys = append(keys, k)
+	}
+	assert.ElementsMatch(t, fields, keys)
+}
+
+// kafkaRequest is a produce request received by the fake REST Proxy.
+type kafkaRequest struct {
+	path        string
+	contentType string
+	auth        string
+	body        KafkaProduceRequest
+}
+
+func newFakeKafkaRESTProxy(t *testing.T, response string) (*httptest.Server, func() []kafkaRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []kafkaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := kafkaRequest{path: r.URL.Path, contentType: r.Header.Get("Content-Type"), auth: r.Header.Get("Authorization")}
+		require.NoError(t, json.Unmarshal(body, &req.body))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		w.Header().Set("Content-Type", kafkaAcceptType)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []kafkaRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]kafkaRequest(nil), requests...)
+	}
+}
+
+func TestEnhancedKafkaPublisher_Produce(t *testing.T) {
+	server, requests := newFakeKafkaRESTProxy(t, `{"offsets":[{"partition":1,"offset":42,"error_code":null,"error":null}]}`)
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{
+		Name:    "analytics",
+		Type:    "kafka",
+		URL:     server.URL,
+		Format:  core.FormatKafka,
+		Headers: map[string]string{"topic": "alert-events", "Authorization": "Basic dXNlcjpwYXNz"},
+	}
+
+	// Topic and proxy credentials come from the target headers
+	publisher, err := factory.CreatePublisher(target.Type)
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedKafkaPublisher{}, publisher)
+
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, newKafkaTestAlert(core.StatusFiring), target))
+	require.NoError(t, publisher.Publish(ctx, newKafkaTestAlert(core.StatusResolved), target))
+
+	got := requests()
+	require.Len(t, got, 2)
+	for i, wantEvent := range []string{"alert.firing", "alert.resolved"} {
+		assert.Equal(t, "/topics/alert-events", got[i].path)
+		assert.Equal(t, kafkaJSONContentType, got[i].contentType)
+		assert.Equal(t, "Basic dXNlcjpwYXNz", got[i].auth)
+		assert.Empty(t, got[i].body.ValueSchema)
+		require.Len(t, got[i].body.Records, 1)
+		record := got[i].body.Records[0]
+		assert.Equal(t, "kafka-fp", record.Key)
+		assert.Nil(t, record.Partition, "partitioned by key")
+		assert.Equal(t, wantEvent, record.Value.(map[string]any)["event_type"])
+	}
+}
+
+func TestEnhancedKafkaPublisher_Avro(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantSchema string
+		wantID     int
+	}{
+		{"inline schema", map[string]string{}, kafkaEventAvroSchema, 0},
+		{"registered schema", map[string]string{"value_schema_id": "7"}, "", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newFakeKafkaRESTProxy(t, `{"offsets":[{"partition":3,"offset":1}]}`)
+			headers := map[string]string{"topic": "alert-events", "encoding": "avro", "partition": "3"}
+			for k, v := range tt.headers {
+				headers[k] = v
+			}
+			target := &core.PublishingTarget{Name: "analytics", Type: "kafka", URL: server.URL, Format: core.FormatKafka, Headers: headers}
+
+			publisher := NewEnhancedKafkaPublisher(nil, NewAlertFormatter(""), slog.Default())
+			require.NoError(t, publisher.Publish(context.Background(), newKafkaTestAlert(core.StatusFiring), target))
+
+			got := requests()
+			require.Len(t, got, 1)
+			assert.Equal(t, kafkaAvroContentType, got[0].contentType)
+			assert.Equal(t, kafkaKeyAvroSchema, got[0].body.KeySchema)
+			assert.Equal(t, tt.wantSchema, got[0].body.ValueSchema)
+			assert.Equal(t, tt.wantID, got[0].body.ValueSchemaID)
+			require.NotNil(t, got[0].body.Records[0].Partition)
+			assert.Equal(t, 3, *got[0].body.Records[0].Partition)
+		})
+	}
+}
+
+func TestParseKafkaTargetConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"missing topic", map[string]string{}},
+		{"negative partition", map[string]string{"topic": "t", "partition": "-1"}},
+		{"unknown encoding", map[string]string{"topic": "t", "encoding": "protobuf"}},
+		{"schema id without avro", map[string]string{"topic": "t", "value_schema_id": "7"}},
+		{"unknown delivery", map[string]string{"topic": "t", "delivery": "exactly_once"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseKafkaTargetConfig(&core.PublishingTarget{Headers: tt.headers})
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := parseKafkaTargetConfig(&core.PublishingTarget{})
+	assert.ErrorIs(t, err, ErrMissingKafkaTopic)
+}
+
+func TestEnhancedKafkaPublisher_DeliveryGuarantee(t *testing.T) {
+	retriable := `{"offsets":[{"partition":0,"offset":null,"error_code":2,"error":"NOT_ENOUGH_REPLICAS"}]}`
+
+	tests := []struct {
+		delivery string
+		want     QueueErrorType
+	}{
+		{"at_least_once", QueueErrorTypeTransient},
+		{"at_most_once", QueueErrorTypePermanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.delivery, func(t *testing.T) {
+			server, _ := newFakeKafkaRESTProxy(t, retriable)
+			target := &core.PublishingTarget{
+				Name:    "analytics",
+				Type:    "kafka",
+				URL:     server.URL,
+				Format:  core.FormatKafka,
+				Headers: map[string]string{"topic": "alert-events", "delivery": tt.delivery},
+			}
+
+			publisher := NewEnhancedKafkaPublisher(nil, NewAlertFormatter(""), slog.Default())
+			err := publisher.Publish(context.Background(), newKafkaTestAlert(core.StatusFiring), target)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "NOT_ENOUGH_REPLICAS")
+			assert.Equal(t, tt.want, classifyPublishingError(err))
+		})
+	}
+}
+
+func TestHTTPKafkaClient_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantCode  int
+		wantClass httperror.Class
+	}{
+		{"unknown topic", http.StatusNotFound, `{"error_code":40401,"message":"Topic not found."}`, http.StatusNotFound, httperror.ClassPermanent},
+		{"invalid schema", http.StatusUnprocessableEntity, `{"error_code":42205,"message":"Invalid schema"}`, http.StatusUnprocessableEntity, httperror.ClassPermanent},
+		{"record error", http.StatusOK, `{"offsets":[{"partition":0,"error_code":1,"error":"RECORD_TOO_LARGE"}]}`, http.StatusUnprocessableEntity, httperror.ClassPermanent},
+		{"proxy unavailable", http.StatusServiceUnavailable, `upstream connect error`, http.StatusServiceUnavailable, httperror.ClassTransient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := NewHTTPKafkaClient(server.URL, "", time.Second, slog.Default()).Produce(context.Background(), "t", kafkaJSONContentType, []byte(`{"records":[]}`))
+			apiErr := AsPublishingError(err)
+			require.NotNil(t, apiErr, "error = %v", err)
+			assert.Equal(t, tt.wantCode, apiErr.StatusCode)
+			assert.Equal(t, ProviderKafka, apiErr.Provider)
+			assert.Equal(t, tt.wantClass, httperror.Classify(err))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package publishing
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// mattermost_publisher_enhanced.go - Mattermost publisher (message attachments)
+
+// mattermostOverrideHeaders are the target headers copied into the message,
+// overriding the channel, user name and icon of the incoming webhook.
+var mattermostOverrideHeaders = []string{"channel", "username", "icon_url", "icon_emoji"}
+
+// EnhancedMattermostPublisher implements AlertPublisher for Mattermost.
+// Every notification (firing and resolved) is posted as a new message with
+// an attachment colored by severity: incoming webhooks cannot update
+// messages. The webhook URL is read from the target at publish time.
+type EnhancedMattermostPublisher struct {
+	*BaseEnhancedPublisher                   // Embedded base publisher for common functionality
+	client                 ChatWebhookClient // Mattermost webhook client
+}
+
+// NewEnhancedMattermostPublisher creates a new Mattermost publisher
+// metrics: Prometheus metrics recorder
+// formatter: Alert formatter used with core.FormatMattermost
+func NewEnhancedMattermostPublisher(
+	client ChatWebhookClient,
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return &EnhancedMattermostPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "mattermost_publisher"),
+		),
+		client: client,
+	}
+}
+
+// Publish posts the alert to the Mattermost webhook of target.
+func (p *EnhancedMattermostPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	return p.publishChatMessage(ctx, p.client, v2.ProviderMattermost, core.FormatMattermost,
+		target.URL, enrichedAlert, target, func(payload map[string]any) {
+			for _, header := range mattermostOverrideHeaders {
+				if value := target.Headers[header]; value != "" {
+					payload[header] = value
+				}
+			}
+		})
+}
+
+// Name returns publisher name
+func (p *EnhancedMattermostPublisher) Name() string {
+	return "Mattermost"
+}
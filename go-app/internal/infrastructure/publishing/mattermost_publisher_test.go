@@ -0,0 +1,64 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestFormatMattermost_FiringAlert(t *testing.T) {
+	payload, err := NewAlertFormatter("https://amp.example.com").FormatAlert(context.Background(), newTeamsTestAlert(core.StatusFiring), core.FormatMattermost)
+	require.NoError(t, err)
+
+	attachments, ok := payload["attachments"].([]map[string]any)
+	require.True(t, ok, "attachments")
+	require.Len(t, attachments, 1)
+	attachment := attachments[0]
+	assert.Equal(t, "🔴 HighCPU - firing", attachment["title"])
+	assert.Equal(t, "🔴 HighCPU - firing: CPU usage above 90%", attachment["fallback"])
+	assert.Equal(t, "#FF0000", attachment["color"])
+	assert.Equal(t, "Fingerprint: teams-fp", attachment["footer"])
+	assert.Contains(t, attachment["fields"], map[string]any{"title": "Namespace", "value": "prod", "short": true})
+	assert.Contains(t, attachment["text"], "[Runbook](https://runbooks.example.com/high-cpu)")
+	assert.Contains(t, attachment["text"], "[Silence](https://amp.example.com/#/silences?filter=")
+}
+
+func TestEnhancedMattermostPublisher_Publish(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &received))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{
+		Name:    "ops-mattermost",
+		Type:    "mattermost",
+		URL:     server.URL + "/hooks/abc",
+		Format:  core.FormatMattermost,
+		Headers: map[string]string{"channel": "oncall", "username": "AMP", "color_critical": "#B22222"},
+	}
+
+	publisher, err := factory.CreatePublisherForTarget(target)
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedMattermostPublisher{}, publisher)
+
+	require.NoError(t, publisher.Publish(context.Background(), newTeamsTestAlert(core.StatusFiring), target))
+	assert.Equal(t, "oncall", received["channel"])
+	assert.Equal(t, "AMP", received["username"])
+	assert.NotContains(t, received, "icon_url")
+	attachments := received["attachments"].([]any)
+	assert.Equal(t, "#B22222", attachments[0].(map[string]any)["color"], "per-target severity style")
+}
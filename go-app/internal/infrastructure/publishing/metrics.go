@@ -176,8 +176,19 @@ func (m *metricsFormatterMiddleware) FormatAlert(ctx context.Context, enrichedAl
 	formatStr := string(format)
 
 	result, err := m.next.FormatAlert(ctx, enrichedAlert, format)
-	duration := time.Since(start)
+	m.record(formatStr, result, err, time.Since(start))
+	return result, err
+}
+
+// FormatBatch records the batch payload like a single one
+func (m *metricsFormatterMiddleware) FormatBatch(ctx context.Context, alerts []*core.EnrichedAlert, format core.PublishingFormat) (map[string]any, error) {
+	start := time.Now()
+	result, err := m.next.FormatBatch(ctx, alerts, format)
+	m.record(string(format), result, err, time.Since(start))
+	return result, err
+}
 
+func (m *metricsFormatterMiddleware) record(formatStr string, result map[string]any, err error, duration time.Duration) {
 	// Record duration and request
 	if err != nil {
 		m.metrics.RecordFormatDuration(formatStr, "failure", duration)
@@ -202,8 +213,6 @@ func (m *metricsFormatterMiddleware) FormatAlert(ctx context.Context, enrichedAl
 			m.metrics.RecordFormatBytes(formatStr, size)
 		}
 	}
-
-	return result, err
 }
 
 // classifyError classifies error for metrics
@@ -22,6 +22,15 @@ const (
 	TargetTypeWebhook      TargetType = "webhook"
 	TargetTypeAlertmanager TargetType = "alertmanager"
 	TargetTypeEmail        TargetType = "email"
+	TargetTypeTeams        TargetType = "teams"
+	TargetTypeOpsgenie     TargetType = "opsgenie"
+	TargetTypeKafka        TargetType = "kafka"
+	TargetTypeJira         TargetType = "jira"
+	TargetTypeGoogleChat   TargetType = "googlechat"
+	TargetTypeMattermost   TargetType = "mattermost"
+	TargetTypeAWS          TargetType = "aws"
+	TargetTypePlugin       TargetType = "plugin"
+	TargetTypeExec         TargetType = "exec"
 )
 
 // ParseTargetType converts string to TargetType
@@ -39,6 +48,24 @@ func ParseTargetType(s string) TargetType {
 		return TargetTypeAlertmanager
 	case "email":
 		return TargetTypeEmail
+	case "teams", "msteams", "ms_teams":
+		return TargetTypeTeams
+	case "opsgenie", "ops_genie":
+		return TargetTypeOpsgenie
+	case "kafka":
+		return TargetTypeKafka
+	case "jira":
+		return TargetTypeJira
+	case "googlechat", "google_chat", "gchat":
+		return TargetTypeGoogleChat
+	case "mattermost":
+		return TargetTypeMattermost
+	case "aws", "sns", "sqs":
+		return TargetTypeAWS
+	case "plugin":
+		return TargetTypePlugin
+	case "exec":
+		return TargetTypeExec
 	default:
 		return TargetTypeWebhook // Default to generic webhook
 	}
@@ -0,0 +1,202 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// opsgenie_client.go - Opsgenie Alert API v2 client
+
+// DefaultOpsgenieAPIURL is the Opsgenie API of the US instance; EU accounts
+// use https://api.eu.opsgenie.com.
+const DefaultOpsgenieAPIURL = "https://api.opsgenie.com"
+
+// opsgenieSource is reported as the source of alerts, acks and closes.
+const opsgenieSource = "AMP"
+
+// ErrMissingOpsgenieAPIKey is returned when an Opsgenie target has neither an
+// api_key header nor a "GenieKey" Authorization header.
+var ErrMissingOpsgenieAPIKey = errors.New("opsgenie: api_key not found in target configuration")
+
+// OpsgenieClient manages alerts through the Opsgenie Alert API v2.
+// Alerts are identified by their alias, so creating an alert whose alias
+// is still open only increments its count in Opsgenie.
+//
+// Failed requests are returned as *httperror.HTTPAPIError with
+// ProviderOpsgenie, so that the publishing queue can classify them.
+type OpsgenieClient interface {
+	// CreateAlert creates an alert from a JSON-encoded create request.
+	CreateAlert(ctx context.Context, payload []byte) error
+	// AcknowledgeAlert acknowledges the open alert with alias.
+	AcknowledgeAlert(ctx context.Context, alias, note string) error
+	// CloseAlert closes the open alert with alias.
+	CloseAlert(ctx context.Context, alias, note string) error
+}
+
+// HTTPOpsgenieClient implements OpsgenieClient with the Opsgenie Alert API
+// v2. Opsgenie processes requests asynchronously and answers 202 once a
+// request was accepted, so a successful call does not mean the alert
+// changed yet.
+type HTTPOpsgenieClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	logger     *slog.Logger
+}
+
+// NewHTTPOpsgenieClient creates a new Opsgenie client
+// baseURL: Opsgenie API URL ("" uses DefaultOpsgenieAPIURL)
+// apiKey: API key of an Opsgenie API integration
+// timeout: request timeout (<= 0 uses 10s)
+func NewHTTPOpsgenieClient(baseURL, apiKey string, timeout time.Duration, logger *slog.Logger) OpsgenieClient {
+	if baseURL == "" {
+		baseURL = DefaultOpsgenieAPIURL
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPOpsgenieClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: newOutboundTransport(&http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12, // TLS 1.2+ required
+				},
+				MaxIdleConns:        10,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     30 * time.Second,
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+			}),
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		logger:  logger.With("component", "opsgenie_client"),
+	}
+}
+
+// CreateAlert posts payload to /v2/alerts.
+func (c *HTTPOpsgenieClient) CreateAlert(ctx context.Context, payload []byte) error {
+	return c.post(ctx, "/v2/alerts", payload)
+}
+
+// AcknowledgeAlert posts to /v2/alerts/{alias}/acknowledge.
+func (c *HTTPOpsgenieClient) AcknowledgeAlert(ctx context.Context, alias, note string) error {
+	return c.alertAction(ctx, alias, "acknowledge", note)
+}
+
+// CloseAlert posts to /v2/alerts/{alias}/close.
+func (c *HTTPOpsgenieClient) CloseAlert(ctx context.Context, alias, note string) error {
+	return c.alertAction(ctx, alias, "close", note)
+}
+
+func (c *HTTPOpsgenieClient) alertAction(ctx context.Context, alias, action, note string) error {
+	payload, err := json.Marshal(map[string]string{"source": opsgenieSource, "note": note})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	path := fmt.Sprintf("/v2/alerts/%s/%s?identifierType=alias", url.PathEscape(alias), action)
+	return c.post(ctx, path, payload)
+}
+
+func (c *HTTPOpsgenieClient) post(ctx context.Context, path string, payload []byte) error {
+	c.logger.DebugContext(ctx, "Posting request to Opsgenie", slog.String("path", path))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	return parseOpsgenieResponse(resp, body)
+}
+
+// parseOpsgenieResponse returns nil for a successful response and an
+// *httperror.HTTPAPIError otherwise.
+func parseOpsgenieResponse(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	message := truncateString(string(body), 512)
+	var errBody struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &errBody) == nil && errBody.Message != "" {
+		message = errBody.Message
+	}
+	apiErr := &httperror.HTTPAPIError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		Provider:   ProviderOpsgenie,
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			apiErr.RetryAfter = seconds
+		}
+	}
+	return apiErr
+}
+
+// opsgenieAPIKey extracts the API key from the target headers: api_key, or
+// an Authorization header in "GenieKey <key>" form.
+func opsgenieAPIKey(target *core.PublishingTarget) string {
+	if key := target.Headers["api_key"]; key != "" {
+		return key
+	}
+	return strings.TrimSpace(strings.TrimPrefix(target.Headers["Authorization"], "GenieKey "))
+}
+
+// opsgenieClients caches Opsgenie clients by API URL and key.
+type opsgenieClients struct {
+	*clientCache[OpsgenieClient]
+	logger *slog.Logger
+}
+
+func newOpsgenieClients(logger *slog.Logger) *opsgenieClients {
+	return &opsgenieClients{
+		clientCache: newClientCache[OpsgenieClient](func(target *core.PublishingTarget) string {
+			return target.URL + "\x00" + opsgenieAPIKey(target)
+		}),
+		logger: logger,
+	}
+}
+
+// get returns the client for target.
+func (c *opsgenieClients) get(target *core.PublishingTarget) (OpsgenieClient, error) {
+	apiKey := opsgenieAPIKey(target)
+	if apiKey == "" {
+		return nil, ErrMissingOpsgenieAPIKey
+	}
+	return c.getOrCreate(target, func() OpsgenieClient {
+		return NewHTTPOpsgenieClient(target.URL, apiKey, 10*time.Second, c.logger)
+	}), nil
+}
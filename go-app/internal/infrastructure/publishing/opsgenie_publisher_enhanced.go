@@ -0,0 +1,135 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// opsgenie_publisher_enhanced.go - Opsgenie publisher (Alert API v2)
+
+// opsgenieAcknowledgedAnnotation set to "true" on a firing alert
+// acknowledges its Opsgenie alert, e.g. when the alert source tracks
+// acknowledgements itself. An annotation, because labels would change the
+// fingerprint and thereby the alias.
+const opsgenieAcknowledgedAnnotation = "acknowledged"
+
+// EnhancedOpsgeniePublisher implements AlertPublisher for Opsgenie.
+//
+// The alert fingerprint is the Opsgenie alias, so that repeated firing
+// notifications deduplicate into one Opsgenie alert and resolved alerts
+// close it:
+//   - firing: create (Opsgenie increments the count of an open alias),
+//     then acknowledge when the alert is annotated acknowledged="true"
+//   - resolved: close
+type EnhancedOpsgeniePublisher struct {
+	*BaseEnhancedPublisher                  // Embedded base publisher for common functionality
+	clients                *opsgenieClients // Opsgenie clients by API URL and key
+}
+
+// NewEnhancedOpsgeniePublisher creates a new Opsgenie publisher
+// metrics: Prometheus metrics recorder
+// formatter: Alert formatter used with core.FormatOpsgenie
+func NewEnhancedOpsgeniePublisher(
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return newEnhancedOpsgeniePublisher(newOpsgenieClients(logger), metrics, formatter, logger)
+}
+
+func newEnhancedOpsgeniePublisher(clients *opsgenieClients, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedOpsgeniePublisher {
+	return &EnhancedOpsgeniePublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "opsgenie_publisher"),
+		),
+		clients: clients,
+	}
+}
+
+// Publish creates, acknowledges or closes the Opsgenie alert of enrichedAlert.
+func (p *EnhancedOpsgeniePublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	client, err := p.clients.get(target)
+	if err != nil {
+		return err
+	}
+
+	alert := enrichedAlert.Alert
+	p.LogPublishStart(ctx, v2.ProviderOpsgenie, enrichedAlert)
+
+	switch alert.Status {
+	case core.StatusFiring:
+		if err := p.createAlert(ctx, client, enrichedAlert, target); err != nil {
+			return err
+		}
+		if strings.EqualFold(alert.Annotations[opsgenieAcknowledgedAnnotation], "true") {
+			return p.call(ctx, "acknowledge_alert", alert.Fingerprint, target, func() error {
+				return client.AcknowledgeAlert(ctx, alert.Fingerprint, "Acknowledged at the alert source")
+			})
+		}
+		return nil
+	case core.StatusResolved:
+		return p.call(ctx, "close_alert", alert.Fingerprint, target, func() error {
+			return client.CloseAlert(ctx, alert.Fingerprint, "Resolved")
+		})
+	default:
+		return fmt.Errorf("unknown alert status: %s", alert.Status)
+	}
+}
+
+// Name returns publisher name
+func (p *EnhancedOpsgeniePublisher) Name() string {
+	return "Opsgenie"
+}
+
+func (p *EnhancedOpsgeniePublisher) createAlert(ctx context.Context, client OpsgenieClient, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	payload, err := p.GetFormatter().FormatAlert(ctx, enrichedAlert, core.FormatOpsgenie)
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderOpsgenie, "create_alert", "format_error")
+		}
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordPayloadSize(v2.ProviderOpsgenie, len(payloadBytes))
+	}
+
+	return p.call(ctx, "create_alert", enrichedAlert.Alert.Fingerprint, target, func() error {
+		return client.CreateAlert(ctx, payloadBytes)
+	})
+}
+
+// call runs an Opsgenie API request and records its outcome.
+func (p *EnhancedOpsgeniePublisher) call(ctx context.Context, endpoint, fingerprint string, target *core.PublishingTarget, request func() error) error {
+	startTime := time.Now()
+	err := request()
+	duration := time.Since(startTime)
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordAPIDuration(v2.ProviderOpsgenie, endpoint, "POST", duration)
+	}
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderOpsgenie, endpoint, GetPublishingErrorType(err))
+		}
+		p.LogPublishError(ctx, v2.ProviderOpsgenie, fingerprint, err)
+		return fmt.Errorf("failed to %s in %s: %w", strings.ReplaceAll(endpoint, "_", " "), target.Name, err)
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordMessage(v2.ProviderOpsgenie, "success")
+	}
+	p.LogPublishSuccess(ctx, v2.ProviderOpsgenie, fingerprint, duration)
+	return nil
+}
@@ -0,0 +1,240 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+func newOpsgenieTestAlert(status core.AlertStatus) *core.EnrichedAlert {
+	return &core.EnrichedAlert{
+		Alert: &core.Alert{
+			Fingerprint: "opsgenie-fp",
+			AlertName:   "HighCPU",
+			Status:      status,
+			Labels: map[string]string{
+				"alertname": "HighCPU",
+				"severity":  "critical",
+				"service":   "checkout",
+				"team":      "payments",
+			},
+			Annotations: map[string]string{
+				"summary":     "CPU usage above 90%",
+				"description": "checkout pods are saturated",
+				"runbook_url": "https://runbooks.example.com/high-cpu",
+			},
+			StartsAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestFormatOpsgenie_CreateRequest(t *testing.T) {
+	payload, err := NewAlertFormatter("https://amp.example.com").FormatAlert(context.Background(), newOpsgenieTestAlert(core.StatusFiring), core.FormatOpsgenie)
+	require.NoError(t, err)
+
+	assert.Equal(t, "HighCPU: CPU usage above 90%", payload["message"])
+	assert.Equal(t, "opsgenie-fp", payload["alias"])
+	assert.Equal(t, "P1", payload["priority"])
+	assert.Equal(t, "checkout", payload["entity"])
+	assert.Equal(t, "checkout pods are saturated", payload["description"])
+	assert.Equal(t, []map[string]string{{"type": "team", "name": "payments"}}, payload["responders"])
+	assert.Contains(t, payload["tags"], "severity:critical")
+
+	details := payload["details"].(map[string]string)
+	assert.Equal(t, "https://runbooks.example.com/high-cpu", details["runbook_url"])
+	assert.Contains(t, details["silence_url"], "https://amp.example.com/#/silences?filter=")
+}
+
+func TestFormatOpsgenie_PriorityAndResponders(t *testing.T) {
+	tests := []struct {
+		name           string
+		labels         map[string]string
+		annotations    map[string]string
+		classification *core.ClassificationResult
+		wantPriority   string
+		wantResponders []map[string]string
+	}{
+		{
+			name:         "warning",
+			labels:       map[string]string{"severity": "warning"},
+			wantPriority: "P3",
+		},
+		{
+			name:           "classification wins over label",
+			labels:         map[string]string{"severity": "warning"},
+			classification: &core.ClassificationResult{Severity: core.SeverityInfo},
+			wantPriority:   "P5",
+		},
+		{
+			name:         "priority override",
+			labels:       map[string]string{"severity": "info"},
+			annotations:  map[string]string{"opsgenie_priority": "p2"},
+			wantPriority: "P2",
+		},
+		{
+			name: "responder labels replace the team label",
+			labels: map[string]string{
+				"team":              "payments",
+				"opsgenie_team":     "sre, dba",
+				"opsgenie_user":     "jane@example.com",
+				"opsgenie_schedule": "primary",
+			},
+			wantPriority: "P3",
+			wantResponders: []map[string]string{
+				{"type": "team", "name": "sre"},
+				{"type": "team", "name": "dba"},
+				{"type": "user", "username": "jane@example.com"},
+				{"type": "schedule", "name": "primary"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &core.EnrichedAlert{
+				Alert: &core.Alert{
+					Fingerprint: "fp",
+					AlertName:   "Test",
+					Status:      core.StatusFiring,
+					Labels:      tt.labels,
+					Annotations: tt.annotations,
+				},
+				Classification: tt.classification,
+			}
+			payload, err := NewAlertFormatter("").FormatAlert(context.Background(), alert, core.FormatOpsgenie)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPriority, payload["priority"])
+			if tt.wantResponders == nil {
+				assert.NotContains(t, payload, "responders")
+			} else {
+				assert.Equal(t, tt.wantResponders, payload["responders"])
+			}
+		})
+	}
+}
+
+// opsgenieRequest is a request received by the fake Opsgenie API.
+type opsgenieRequest struct {
+	path   string
+	query  string
+	auth   string
+	fields map[string]any
+}
+
+func newFakeOpsgenie(t *testing.T) (*httptest.Server, func() []opsgenieRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []opsgenieRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := opsgenieRequest{path: r.URL.Path, query: r.URL.RawQuery, auth: r.Header.Get("Authorization")}
+		require.NoError(t, json.Unmarshal(body, &req.fields))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"result":"Request will be processed","requestId":"r1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []opsgenieRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]opsgenieRequest(nil), requests...)
+	}
+}
+
+func TestEnhancedOpsgeniePublisher_Lifecycle(t *testing.T) {
+	server, requests := newFakeOpsgenie(t)
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{
+		Name:    "opsgenie-oncall",
+		Type:    "opsgenie",
+		URL:     server.URL,
+		Format:  core.FormatOpsgenie,
+		Headers: map[string]string{"Authorization": "GenieKey test-key"},
+	}
+
+	// The API key comes from the target, resolved at publish time
+	publisher, err := factory.CreatePublisher(target.Type)
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedOpsgeniePublisher{}, publisher)
+
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, newOpsgenieTestAlert(core.StatusFiring), target))
+
+	acked := newOpsgenieTestAlert(core.StatusFiring)
+	acked.Alert.Annotations["acknowledged"] = "true"
+	require.NoError(t, publisher.Publish(ctx, acked, target))
+
+	require.NoError(t, publisher.Publish(ctx, newOpsgenieTestAlert(core.StatusResolved), target))
+
+	got := requests()
+	require.Len(t, got, 4)
+	for _, req := range got {
+		assert.Equal(t, "GenieKey test-key", req.auth)
+	}
+	assert.Equal(t, "/v2/alerts", got[0].path)
+	assert.Equal(t, "opsgenie-fp", got[0].fields["alias"])
+	assert.Equal(t, "/v2/alerts", got[1].path)
+	assert.Equal(t, "/v2/alerts/opsgenie-fp/acknowledge", got[2].path)
+	assert.Equal(t, "identifierType=alias", got[2].query)
+	assert.Equal(t, "/v2/alerts/opsgenie-fp/close", got[3].path)
+	assert.Equal(t, "identifierType=alias", got[3].query)
+	assert.Equal(t, "AMP", got[3].fields["source"])
+}
+
+func TestEnhancedOpsgeniePublisher_MissingAPIKey(t *testing.T) {
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{Name: "opsgenie", Type: "opsgenie", URL: DefaultOpsgenieAPIURL, Format: core.FormatOpsgenie}
+
+	publisher, err := factory.CreatePublisherForTarget(target)
+	require.NoError(t, err)
+	err = publisher.Publish(context.Background(), newOpsgenieTestAlert(core.StatusFiring), target)
+	assert.ErrorIs(t, err, ErrMissingOpsgenieAPIKey)
+}
+
+func TestHTTPOpsgenieClient_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantClass   httperror.Class
+		wantMessage string
+	}{
+		{"rate limited", http.StatusTooManyRequests, `{"message":"Too many requests"}`, httperror.ClassTransient, "Too many requests"},
+		{"invalid key", http.StatusUnauthorized, `{"message":"Key format is not valid!"}`, httperror.ClassPermanent, "Key format is not valid!"},
+		{"invalid request", http.StatusUnprocessableEntity, `not json`, httperror.ClassPermanent, "not json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := NewHTTPOpsgenieClient(server.URL, "key", time.Second, slog.Default()).CloseAlert(context.Background(), "fp", "")
+			apiErr := AsPublishingError(err)
+			require.NotNil(t, apiErr, "error = %v", err)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, ProviderOpsgenie, apiErr.Provider)
+			assert.True(t, strings.Contains(apiErr.Message, tt.wantMessage), apiErr.Message)
+			assert.Equal(t, tt.wantClass, httperror.Classify(err))
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package publishing
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	amconfig "github.com/ipiton/AMP/internal/alertmanager/config"
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Target headers configuring the outbound HTTP connections to a target, for
+// egress proxies, private CAs and mTLS. They are never sent.
+//
+//   - proxy_url: HTTP(S) proxy of the target (default: HTTPS_PROXY and
+//     HTTP_PROXY, except for NO_PROXY hosts)
+//   - tls_ca_file: PEM CA bundle trusted in addition to the system roots
+//   - tls_cert_file, tls_key_file: PEM client certificate and key (mTLS),
+//     read again on every handshake, so that rotated files are picked up
+//   - tls_server_name: server name verified instead of the URL host
+//   - tls_min_version: TLS10, TLS11, TLS12 (default) or TLS13
+//   - tls_insecure_skip_verify: "true" skips server certificate verification
+//
+// Webhook and Alertmanager targets can also authenticate with OAuth2 (see
+// targetOAuth2TokenURLHeader).
+const (
+	targetProxyURLHeader              = "proxy_url"
+	targetTLSCAFileHeader             = "tls_ca_file"
+	targetTLSCertFileHeader           = "tls_cert_file"
+	targetTLSKeyFileHeader            = "tls_key_file"
+	targetTLSServerNameHeader         = "tls_server_name"
+	targetTLSMinVersionHeader         = "tls_min_version"
+	targetTLSInsecureSkipVerifyHeader = "tls_insecure_skip_verify"
+)
+
+// tlsVersions are the accepted tls_min_version values, as in the
+// Alertmanager tls_config.
+var tlsVersions = map[string]uint16{
+	"TLS10": tls.VersionTLS10,
+	"TLS11": tls.VersionTLS11,
+	"TLS12": tls.VersionTLS12,
+	"TLS13": tls.VersionTLS13,
+}
+
+// ParseTargetHTTPConfig reads the outbound HTTP and OAuth2 headers of target
+// into an Alertmanager http_config. It returns nil when the target sets none.
+func ParseTargetHTTPConfig(target *core.PublishingTarget) (*amconfig.HTTPConfig, error) {
+	headers := target.Headers
+	cfg := &amconfig.HTTPConfig{ProxyURL: strings.TrimSpace(headers[targetProxyURLHeader])}
+	tlsCfg := amconfig.TLSConfig{
+		CAFile:     strings.TrimSpace(headers[targetTLSCAFileHeader]),
+		CertFile:   strings.TrimSpace(headers[targetTLSCertFileHeader]),
+		KeyFile:    strings.TrimSpace(headers[targetTLSKeyFileHeader]),
+		ServerName: strings.TrimSpace(headers[targetTLSServerNameHeader]),
+		MinVersion: strings.ToUpper(strings.TrimSpace(headers[targetTLSMinVersionHeader])),
+	}
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid %s %q: must be an http or https URL", targetProxyURLHeader, cfg.ProxyURL)
+		}
+	}
+	if (tlsCfg.CertFile == "") != (tlsCfg.KeyFile == "") {
+		return nil, fmt.Errorf("%s and %s must be set together", targetTLSCertFileHeader, targetTLSKeyFileHeader)
+	}
+	if _, ok := tlsVersions[tlsCfg.MinVersion]; tlsCfg.MinVersion != "" && !ok {
+		return nil, fmt.Errorf("invalid %s %q: want TLS10, TLS11, TLS12 or TLS13", targetTLSMinVersionHeader, headers[targetTLSMinVersionHeader])
+	}
+	if raw, ok := headers[targetTLSInsecureSkipVerifyHeader]; ok {
+		insecure, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: must be true or false", targetTLSInsecureSkipVerifyHeader, raw)
+		}
+		tlsCfg.InsecureSkipVerify = insecure
+	}
+
+	if tlsCfg != (amconfig.TLSConfig{}) {
+		cfg.TLSConfig = &tlsCfg
+	}
+	oauth2, err := parseTargetOAuth2(headers)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OAuth2 = oauth2
+
+	if cfg.ProxyURL == "" && cfg.TLSConfig == nil && cfg.OAuth2 == nil {
+		return nil, nil
+	}
+	return cfg, nil
+}
+
+// ValidateTargetHTTPConfig checks the outbound HTTP and OAuth2 headers of
+// target, if any. The certificate files are read at publish time.
+func ValidateTargetHTTPConfig(target *core.PublishingTarget) error {
+	cfg, err := ParseTargetHTTPConfig(target)
+	if err != nil || cfg == nil || cfg.OAuth2 == nil {
+		return err
+	}
+	for _, targetType := range oauth2TargetTypes {
+		if target.Type == targetType {
+			return nil
+		}
+	}
+	return fmt.Errorf("oauth2 headers are only supported by webhook and alertmanager targets")
+}
+
+type httpConfigKey struct{}
+
+// withTargetHTTPConfig attaches the outbound HTTP config of target to ctx,
+// for the clients of the publishers (see outboundTransport).
+func withTargetHTTPConfig(ctx context.Context, target *core.PublishingTarget) (context.Context, error) {
+	cfg, err := ParseTargetHTTPConfig(target)
+	if err != nil || cfg == nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, httpConfigKey{}, cfg), nil
+}
+
+// httpConfigFromContext returns the config attached by withTargetHTTPConfig,
+// or nil.
+func httpConfigFromContext(ctx context.Context) *amconfig.HTTPConfig {
+	cfg, _ := ctx.Value(httpConfigKey{}).(*amconfig.HTTPConfig)
+	return cfg
+}
+
+// outboundTransport is the transport of the publisher clients. Requests whose
+// context carries the outbound HTTP config of their target go through a
+// transport built for that config from base, with an OAuth2 access token if
+// configured; the others go through base. Both honor the proxy environment
+// variables unless the target sets proxy_url.
+type outboundTransport struct {
+	base *http.Transport
+
+	mu         sync.Mutex
+	transports map[string]*configTransport // by config
+}
+
+// configTransport is the transport of an outbound HTTP config.
+type configTransport struct {
+	transport *http.Transport
+	oauth2    *oauth2Tokens // nil without OAuth2
+}
+
+// newOutboundTransport returns the outbound transport of a publisher client
+// tuned as base (nil: http.DefaultTransport).
+func newOutboundTransport(base *http.Transport) *outboundTransport {
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if base.Proxy == nil {
+		base.Proxy = http.ProxyFromEnvironment
+	}
+	return &outboundTransport{base: base, transports: make(map[string]*configTransport)}
+}
+
+// RoundTrip implements http.RoundTripper. The responses to test alerts are
+// recorded (see SendTestAlert), and the receipts of queued deliveries (see
+// deliveryReceipt).
+func (t *outboundTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	receipt := deliveryReceiptFromContext(req.Context())
+	if receipt != nil {
+		receipt.recordRequest(req)
+	}
+	resp, err := t.roundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if recorder := responseRecorderFromContext(req.Context()); recorder != nil {
+		recorder.record(resp)
+	}
+	if receipt != nil {
+		receipt.recordResponse(resp)
+	}
+	return resp, err
+}
+
+func (t *outboundTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	cfg := httpConfigFromContext(req.Context())
+	if cfg == nil {
+		return t.base.RoundTrip(req)
+	}
+	transport, err := t.transportFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if transport.oauth2 != nil {
+		return transport.oauth2.roundTrip(transport.transport, req)
+	}
+	return transport.transport.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of every transport.
+func (t *outboundTransport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, transport := range t.transports {
+		transport.transport.CloseIdleConnections()
+	}
+}
+
+// transportFor returns the transport of cfg, built on first use.
+func (t *outboundTransport) transportFor(cfg *amconfig.HTTPConfig) (*configTransport, error) {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	key := string(raw)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if transport, ok := t.transports[key]; ok {
+		return transport, nil
+	}
+	built, err := buildOutboundTransport(t.base, cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport := &configTransport{transport: built}
+	if cfg.OAuth2 != nil {
+		transport.oauth2 = &oauth2Tokens{cfg: cfg.OAuth2}
+	}
+	t.transports[key] = transport
+	return transport, nil
+}
+
+// buildOutboundTransport returns a copy of base with the proxy and TLS
+// settings of cfg.
+func buildOutboundTransport(base *http.Transport, cfg *amconfig.HTTPConfig) (*http.Transport, error) {
+	transport := base.Clone()
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", targetProxyURLHeader, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if cfg.TLSConfig == nil {
+		return transport, nil
+	}
+
+	tlsCfg := cfg.TLSConfig
+	clientTLS := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		clientTLS = transport.TLSClientConfig.Clone()
+	}
+	if version, ok := tlsVersions[tlsCfg.MinVersion]; ok {
+		clientTLS.MinVersion = version
+	}
+	clientTLS.ServerName = tlsCfg.ServerName
+	clientTLS.InsecureSkipVerify = tlsCfg.InsecureSkipVerify
+
+	if tlsCfg.CAFile != "" {
+		pem, err := os.ReadFile(tlsCfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", targetTLSCAFileHeader, err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil || roots == nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s %s contains no PEM certificate", targetTLSCAFileHeader, tlsCfg.CAFile)
+		}
+		clientTLS.RootCAs = roots
+	}
+
+	if tlsCfg.CertFile != "" {
+		certFile, keyFile := tlsCfg.CertFile, tlsCfg.KeyFile
+		// Fail early on unreadable files rather than on every handshake
+		if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		clientTLS.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+
+	transport.TLSClientConfig = clientTLS
+	return transport, nil
+}
@@ -0,0 +1,150 @@
+package publishing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	amconfig "github.com/ipiton/AMP/internal/alertmanager/config"
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestParseTargetHTTPConfig(t *testing.T) {
+	cfg, err := ParseTargetHTTPConfig(&core.PublishingTarget{Headers: map[string]string{"X-Token": "secret"}})
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "targets without outbound HTTP headers use the defaults")
+
+	cfg, err = ParseTargetHTTPConfig(&core.PublishingTarget{Headers: map[string]string{
+		targetProxyURLHeader:              "http://proxy.corp:3128",
+		targetTLSCAFileHeader:             "/etc/amp/ca.crt",
+		targetTLSCertFileHeader:           "/etc/amp/tls.crt",
+		targetTLSKeyFileHeader:            "/etc/amp/tls.key",
+		targetTLSServerNameHeader:         "hooks.example.com",
+		targetTLSMinVersionHeader:         "tls13",
+		targetTLSInsecureSkipVerifyHeader: "false",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, &amconfig.HTTPConfig{
+		ProxyURL: "http://proxy.corp:3128",
+		TLSConfig: &amconfig.TLSConfig{
+			CAFile:     "/etc/amp/ca.crt",
+			CertFile:   "/etc/amp/tls.crt",
+			KeyFile:    "/etc/amp/tls.key",
+			ServerName: "hooks.example.com",
+			MinVersion: "TLS13",
+		},
+	}, cfg)
+}
+
+func TestOutboundTransport_ProxyURL(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	target := &core.PublishingTarget{
+		Name:    "egress",
+		Type:    "webhook",
+		URL:     "http://hooks.example.com/alerts",
+		Format:  core.FormatWebhook,
+		Headers: map[string]string{targetProxyURLHeader: proxy.URL},
+	}
+	queue := &PublishingQueue{ctx: context.Background()}
+	publisher := NewWebhookPublisher(NewAlertFormatter(""), slog.Default())
+	require.NoError(t, queue.publish(queue.ctx, publisher, &PublishingJob{EnrichedAlert: createTestEnrichedAlert(), Target: target}))
+	assert.Equal(t, "http://hooks.example.com/alerts", proxied)
+	assert.NotContains(t, targetHTTPHeaders(target.Headers), targetProxyURLHeader)
+}
+
+func TestOutboundTransport_CustomCAAndClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certFile, keyFile := writeTestClientCertificate(t, dir)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	client := &http.Client{Timeout: 5 * time.Second, Transport: newOutboundTransport(nil)}
+	get := func(headers map[string]string) error {
+		ctx, err := withTargetHTTPConfig(context.Background(), &core.PublishingTarget{Headers: headers})
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// The server certificate is not trusted without the CA, and the server
+	// requires a client certificate
+	assert.Error(t, get(nil))
+	assert.Error(t, get(map[string]string{targetTLSCAFileHeader: caFile}))
+
+	require.NoError(t, get(map[string]string{
+		targetTLSCAFileHeader:   caFile,
+		targetTLSCertFileHeader: certFile,
+		targetTLSKeyFileHeader:  keyFile,
+	}))
+
+	err := get(map[string]string{targetTLSCAFileHeader: filepath.Join(dir, "missing.crt")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), targetTLSCAFileHeader)
+}
+
+// writeTestClientCertificate writes a self-signed client certificate and its
+// key to dir.
+func writeTestClientCertificate(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "amp"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return cert, certFile, keyFile
+}
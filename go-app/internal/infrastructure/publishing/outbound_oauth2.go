@@ -0,0 +1,179 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	amconfig "github.com/ipiton/AMP/internal/alertmanager/config"
+)
+
+// Target headers authenticating the requests to a target with an OAuth2
+// access token obtained with the client credentials grant, as the oauth2
+// block of an Alertmanager http_config. They are never sent.
+//
+//   - oauth2_token_url: token endpoint of the identity provider
+//   - oauth2_client_id, oauth2_client_secret: client credentials, sent with
+//     HTTP Basic authentication
+//   - oauth2_scopes: comma- or space-separated scopes (optional)
+const (
+	targetOAuth2TokenURLHeader     = "oauth2_token_url"
+	targetOAuth2ClientIDHeader     = "oauth2_client_id"
+	targetOAuth2ClientSecretHeader = "oauth2_client_secret"
+	targetOAuth2ScopesHeader       = "oauth2_scopes"
+)
+
+// oauth2TargetTypes are the target types authenticating with OAuth2: those
+// posting to an arbitrary endpoint.
+var oauth2TargetTypes = []string{"webhook", "alertmanager"}
+
+// oauth2ExpiryMargin is how long before its expiry an access token is
+// renewed, so that it does not expire in flight.
+const oauth2ExpiryMargin = 30 * time.Second
+
+// parseTargetOAuth2 reads the OAuth2 headers, nil when there are none.
+func parseTargetOAuth2(headers map[string]string) (*amconfig.OAuth2, error) {
+	cfg := &amconfig.OAuth2{
+		TokenURL:     strings.TrimSpace(headers[targetOAuth2TokenURLHeader]),
+		ClientID:     strings.TrimSpace(headers[targetOAuth2ClientIDHeader]),
+		ClientSecret: headers[targetOAuth2ClientSecretHeader],
+		Scopes: strings.FieldsFunc(headers[targetOAuth2ScopesHeader], func(r rune) bool {
+			return r == ',' || r == ' '
+		}),
+	}
+	if cfg.TokenURL == "" && cfg.ClientID == "" && cfg.ClientSecret == "" && len(cfg.Scopes) == 0 {
+		return nil, nil
+	}
+
+	if cfg.TokenURL == "" || cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("%s, %s and %s must be set together", targetOAuth2TokenURLHeader, targetOAuth2ClientIDHeader, targetOAuth2ClientSecretHeader)
+	}
+	tokenURL, err := url.Parse(cfg.TokenURL)
+	if err != nil || (tokenURL.Scheme != "http" && tokenURL.Scheme != "https") || tokenURL.Host == "" {
+		return nil, fmt.Errorf("invalid %s %q: must be an http or https URL", targetOAuth2TokenURLHeader, cfg.TokenURL)
+	}
+	return cfg, nil
+}
+
+// oauth2Tokens fetches and caches the access token of an OAuth2 config.
+type oauth2Tokens struct {
+	cfg *amconfig.OAuth2
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time // zero: no known expiry
+}
+
+// get returns the cached access token, fetching a new one through
+// transport when there is none or it is about to expire.
+func (o *oauth2Tokens) get(ctx context.Context, transport http.RoundTripper) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.token != "" && (o.expires.IsZero() || time.Now().Before(o.expires)) {
+		return o.token, nil
+	}
+
+	token, expiresIn, err := o.fetch(ctx, transport)
+	if err != nil {
+		return "", err
+	}
+	o.token, o.expires = token, time.Time{}
+	if expiresIn > 0 {
+		o.expires = time.Now().Add(expiresIn - oauth2ExpiryMargin)
+	}
+	return o.token, nil
+}
+
+// invalidate drops token from the cache, unless it was renewed already.
+func (o *oauth2Tokens) invalidate(token string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.token == token {
+		o.token = ""
+	}
+}
+
+// fetch requests an access token with the client credentials grant.
+func (o *oauth2Tokens) fetch(ctx context.Context, transport http.RoundTripper) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.cfg.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("oauth2 token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.cfg.ClientID), url.QueryEscape(o.cfg.ClientSecret))
+
+	resp, err := (&http.Client{Transport: transport, Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("oauth2 token request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", 0, fmt.Errorf("oauth2 token request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("oauth2 token request: HTTP %d: %s", resp.StatusCode, truncateString(string(body), 200))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", 0, fmt.Errorf("oauth2 token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", 0, fmt.Errorf("oauth2 token response has no access_token")
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("oauth2 token type %q is not supported", token.TokenType)
+	}
+	return token.AccessToken, time.Duration(token.ExpiresIn) * time.Second, nil
+}
+
+// roundTrip sends req through transport with the access token. A 401
+// response invalidates the token, e.g. revoked before its expiry: the request
+// is sent again once with a new token.
+func (o *oauth2Tokens) roundTrip(transport http.RoundTripper, req *http.Request) (*http.Response, error) {
+	token, err := o.get(req.Context(), transport)
+	if err != nil {
+		return nil, err
+	}
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+	resp, err := transport.RoundTrip(authorized)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil // the body cannot be sent again
+	}
+
+	o.invalidate(token)
+	token, err = o.get(req.Context(), transport)
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+	return transport.RoundTrip(retry)
+}
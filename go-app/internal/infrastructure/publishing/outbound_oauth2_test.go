@@ -0,0 +1,148 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// fakeIdentityProvider issues tok-1, tok-2, ... with the client credentials
+// grant to client "amp".
+type fakeIdentityProvider struct {
+	mu        sync.Mutex
+	issued    int
+	expiresIn int
+}
+
+func (p *fakeIdentityProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != "amp" || secret != "s3cr3t" || r.FormValue("grant_type") != "client_credentials" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	p.mu.Lock()
+	p.issued++
+	token := fmt.Sprintf("tok-%d", p.issued)
+	p.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"access_token": %q, "token_type": "Bearer", "expires_in": %d, "scope": %q}`, token, p.expiresIn, r.FormValue("scope"))
+}
+
+func (p *fakeIdentityProvider) tokensIssued() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.issued
+}
+
+func oauth2TestTarget(url, tokenURL string) *core.PublishingTarget {
+	return &core.PublishingTarget{
+		Name:   "internal-api",
+		Type:   "webhook",
+		URL:    url,
+		Format: core.FormatWebhook,
+		Headers: map[string]string{
+			targetOAuth2TokenURLHeader:     tokenURL,
+			targetOAuth2ClientIDHeader:     "amp",
+			targetOAuth2ClientSecretHeader: "s3cr3t",
+			targetOAuth2ScopesHeader:       "alerts.write, alerts.read",
+		},
+	}
+}
+
+func TestOutboundTransport_OAuth2(t *testing.T) {
+	idp := &fakeIdentityProvider{expiresIn: 3600}
+	tokenServer := httptest.NewServer(idp)
+	defer tokenServer.Close()
+
+	var mu sync.Mutex
+	var authorizations []string
+	accepted := "tok-1"
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get(targetOAuth2ClientSecretHeader), "the OAuth2 options are not sent")
+		if r.Header.Get("Authorization") != "Bearer "+accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer api.Close()
+
+	target := oauth2TestTarget(api.URL, tokenServer.URL)
+	queue := &PublishingQueue{ctx: context.Background()}
+	publisher := NewWebhookPublisher(NewAlertFormatter(""), slog.Default())
+	publish := func() error {
+		return queue.publish(queue.ctx, publisher, &PublishingJob{EnrichedAlert: createTestEnrichedAlert(), Target: target})
+	}
+
+	// The token is fetched once and reused
+	require.NoError(t, publish())
+	require.NoError(t, publish())
+	assert.Equal(t, 1, idp.tokensIssued())
+	assert.Equal(t, []string{"Bearer tok-1", "Bearer tok-1"}, authorizations)
+
+	// A revoked token is refreshed on 401 and the request sent again
+	mu.Lock()
+	accepted, authorizations = "tok-2", nil
+	mu.Unlock()
+	require.NoError(t, publish())
+	assert.Equal(t, 2, idp.tokensIssued())
+	assert.Equal(t, []string{"Bearer tok-1", "Bearer tok-2"}, authorizations)
+
+	// Only once: a token rejected again fails the request
+	mu.Lock()
+	accepted = "never"
+	mu.Unlock()
+	assert.Error(t, publish())
+	assert.Equal(t, 3, idp.tokensIssued())
+
+	// Invalid credentials fail the request without reaching the API
+	mu.Lock()
+	accepted, authorizations = "tok-3", nil
+	mu.Unlock()
+	target.Headers[targetOAuth2ClientSecretHeader] = "wrong"
+	err := publish()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth2 token request")
+	assert.Empty(t, authorizations)
+}
+
+func TestOAuth2Tokens_Expiry(t *testing.T) {
+	idp := &fakeIdentityProvider{expiresIn: 30} // within the expiry margin
+	tokenServer := httptest.NewServer(idp)
+	defer tokenServer.Close()
+
+	cfg, err := ParseTargetHTTPConfig(oauth2TestTarget("https://api.example.com", tokenServer.URL))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alerts.write", "alerts.read"}, cfg.OAuth2.Scopes)
+
+	tokens := &oauth2Tokens{cfg: cfg.OAuth2}
+	for i := 1; i <= 2; i++ {
+		token, err := tokens.get(context.Background(), http.DefaultTransport)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("tok-%d", i), token, "expired tokens are renewed")
+	}
+}
+
+func TestValidateTargetHTTPConfig_OAuth2(t *testing.T) {
+	target := oauth2TestTarget("https://api.example.com", "https://idp.example.com/token")
+	assert.NoError(t, ValidateTargetHTTPConfig(target))
+
+	target.Type = "slack"
+	assert.Error(t, ValidateTargetHTTPConfig(target))
+
+	target.Type = "webhook"
+	delete(target.Headers, targetOAuth2ClientSecretHeader)
+	assert.Error(t, ValidateTargetHTTPConfig(target))
+}
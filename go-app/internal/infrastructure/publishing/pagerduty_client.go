@@ -9,8 +9,10 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/ipiton/AMP/internal/core"
 	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
 	"golang.org/x/time/rate"
 )
@@ -97,11 +99,11 @@ func NewPagerDutyEventsClient(config PagerDutyClientConfig, logger *slog.Logger)
 	// Create HTTP client with TLS 1.2+
 	httpClient := &http.Client{
 		Timeout: config.Timeout,
-		Transport: &http.Transport{
+		Transport: newOutboundTransport(&http.Transport{
 			TLSClientConfig: &tls.Config{
 				MinVersion: tls.VersionTLS12,
 			},
-		},
+		}),
 	}
 
 	// Create rate limiter (requests per second)
@@ -490,3 +492,52 @@ func (c *pagerDutyEventsClientImpl) parseError(resp *http.Response) *PagerDutyAP
 
 	return NewPagerDutyAPIError(resp.StatusCode, errorResp.Message, errorResp.Errors)
 }
+
+// pagerDutyRoutingKey returns the routing key of a PagerDuty target: its
+// routing_key header, else its Authorization header without "Bearer ".
+func pagerDutyRoutingKey(target *core.PublishingTarget) string {
+	if routingKey, ok := target.Headers["routing_key"]; ok {
+		return routingKey
+	}
+	if auth, ok := target.Headers["Authorization"]; ok {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+// pagerDutyBaseURL returns the Events API base URL of target. The full
+// enqueue URL used by Alertmanager receivers is accepted too.
+func pagerDutyBaseURL(target *core.PublishingTarget) string {
+	return strings.TrimSuffix(strings.TrimSuffix(target.URL, "/"), "/v2/enqueue")
+}
+
+// pagerDutyClients caches PagerDuty clients by URL and routing key.
+type pagerDutyClients struct {
+	*clientCache[PagerDutyEventsClient]
+	logger *slog.Logger
+}
+
+func newPagerDutyClients(logger *slog.Logger) *pagerDutyClients {
+	return &pagerDutyClients{
+		clientCache: newClientCache[PagerDutyEventsClient](func(target *core.PublishingTarget) string {
+			return target.URL + "\x00" + pagerDutyRoutingKey(target)
+		}),
+		logger: logger,
+	}
+}
+
+// get returns the client for target. Every routing key gets its own client,
+// so that each is rate limited separately.
+func (c *pagerDutyClients) get(target *core.PublishingTarget) (PagerDutyEventsClient, error) {
+	if pagerDutyRoutingKey(target) == "" {
+		return nil, ErrMissingRoutingKey
+	}
+	return c.getOrCreate(target, func() PagerDutyEventsClient {
+		return NewPagerDutyEventsClient(PagerDutyClientConfig{
+			BaseURL:    pagerDutyBaseURL(target),
+			Timeout:    10 * time.Second,
+			MaxRetries: 3,
+			RateLimit:  120.0, // 120 req/min
+		}, c.logger)
+	}), nil
+}
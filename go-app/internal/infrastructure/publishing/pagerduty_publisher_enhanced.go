@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
 	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
 )
 
@@ -14,7 +15,8 @@ import (
 // Provides incident lifecycle management (trigger, acknowledge, resolve) and change events
 type EnhancedPagerDutyPublisher struct {
 	*BaseEnhancedPublisher                       // Embedded base publisher for common functionality
-	client                 PagerDutyEventsClient // PagerDuty-specific events client
+	client                 PagerDutyEventsClient // PagerDuty-specific events client (nil: resolved per target)
+	clients                *pagerDutyClients     // PagerDuty clients by target, when client is nil
 	cache                  EventKeyCache         // For tracking event keys (incident lifecycle)
 }
 
@@ -37,6 +39,20 @@ func NewEnhancedPagerDutyPublisher(
 	}
 }
 
+// newEnhancedPagerDutyPublisher creates a PagerDuty publisher resolving the
+// client of each target at publish time, as the publishing queue needs.
+func newEnhancedPagerDutyPublisher(clients *pagerDutyClients, cache EventKeyCache, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedPagerDutyPublisher {
+	return &EnhancedPagerDutyPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "pagerduty_publisher"),
+		),
+		clients: clients,
+		cache:   cache,
+	}
+}
+
 // Publish publishes enriched alert to PagerDuty
 // Routes to trigger/acknowledge/resolve based on alert status
 func (p *EnhancedPagerDutyPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
@@ -47,18 +63,25 @@ func (p *EnhancedPagerDutyPublisher) Publish(ctx context.Context, enrichedAlert
 	if routingKey == "" {
 		return ErrMissingRoutingKey
 	}
+	client := p.client
+	if client == nil {
+		var err error
+		if client, err = p.clients.get(target); err != nil {
+			return err
+		}
+	}
 
 	// Check for change event label
 	if isChangeEvent(alert) {
-		return p.sendChangeEvent(ctx, enrichedAlert, routingKey)
+		return p.sendChangeEvent(ctx, client, enrichedAlert, routingKey)
 	}
 
 	// Determine event action based on alert status
 	switch alert.Status {
 	case core.StatusFiring:
-		return p.triggerEvent(ctx, enrichedAlert, routingKey)
+		return p.triggerEvent(ctx, client, enrichedAlert, routingKey)
 	case core.StatusResolved:
-		return p.resolveEvent(ctx, enrichedAlert, routingKey)
+		return p.resolveEvent(ctx, client, enrichedAlert, routingKey)
 	default:
 		return fmt.Errorf("unknown alert status: %s", alert.Status)
 	}
@@ -70,7 +93,7 @@ func (p *EnhancedPagerDutyPublisher) Name() string {
 }
 
 // triggerEvent sends a trigger event to PagerDuty (creates or updates incident)
-func (p *EnhancedPagerDutyPublisher) triggerEvent(ctx context.Context, enrichedAlert *core.EnrichedAlert, routingKey string) error {
+func (p *EnhancedPagerDutyPublisher) triggerEvent(ctx context.Context, client PagerDutyEventsClient, enrichedAlert *core.EnrichedAlert, routingKey string) error {
 	alert := enrichedAlert.Alert
 
 	// Format alert using TN-051 formatter
@@ -93,7 +116,7 @@ func (p *EnhancedPagerDutyPublisher) triggerEvent(ctx context.Context, enrichedA
 	}
 
 	// Send to PagerDuty
-	resp, err := p.client.TriggerEvent(ctx, req)
+	resp, err := client.TriggerEvent(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to trigger event: %w", err)
 	}
@@ -119,18 +142,9 @@ func (p *EnhancedPagerDutyPublisher) triggerEvent(ctx context.Context, enrichedA
 }
 
 // acknowledgeEvent acknowledges an event in PagerDuty
-func (p *EnhancedPagerDutyPublisher) acknowledgeEvent(ctx context.Context, enrichedAlert *core.EnrichedAlert, routingKey string) error {
+func (p *EnhancedPagerDutyPublisher) acknowledgeEvent(ctx context.Context, client PagerDutyEventsClient, enrichedAlert *core.EnrichedAlert, routingKey string) error {
 	alert := enrichedAlert.Alert
-
-	// Lookup dedup key from cache
-	dedupKey, found := p.cache.Get(alert.Fingerprint)
-	if !found {
-		p.GetLogger().Warn("Cannot acknowledge event: not tracked in cache",
-			"fingerprint", alert.Fingerprint,
-			"alert_name", alert.AlertName,
-		)
-		return ErrEventNotTracked
-	}
+	dedupKey := p.dedupKey(alert)
 
 	// Build acknowledge request
 	req := &AcknowledgeEventRequest{
@@ -140,7 +154,7 @@ func (p *EnhancedPagerDutyPublisher) acknowledgeEvent(ctx context.Context, enric
 	}
 
 	// Send to PagerDuty
-	_, err := p.client.AcknowledgeEvent(ctx, req)
+	_, err := client.AcknowledgeEvent(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to acknowledge event: %w", err)
 	}
@@ -161,18 +175,9 @@ func (p *EnhancedPagerDutyPublisher) acknowledgeEvent(ctx context.Context, enric
 }
 
 // resolveEvent resolves an event in PagerDuty
-func (p *EnhancedPagerDutyPublisher) resolveEvent(ctx context.Context, enrichedAlert *core.EnrichedAlert, routingKey string) error {
+func (p *EnhancedPagerDutyPublisher) resolveEvent(ctx context.Context, client PagerDutyEventsClient, enrichedAlert *core.EnrichedAlert, routingKey string) error {
 	alert := enrichedAlert.Alert
-
-	// Lookup dedup key from cache
-	dedupKey, found := p.cache.Get(alert.Fingerprint)
-	if !found {
-		p.GetLogger().Warn("Cannot resolve event: not tracked in cache",
-			"fingerprint", alert.Fingerprint,
-			"alert_name", alert.AlertName,
-		)
-		return ErrEventNotTracked
-	}
+	dedupKey := p.dedupKey(alert)
 
 	// Build resolve request
 	req := &ResolveEventRequest{
@@ -182,7 +187,7 @@ func (p *EnhancedPagerDutyPublisher) resolveEvent(ctx context.Context, enrichedA
 	}
 
 	// Send to PagerDuty
-	_, err := p.client.ResolveEvent(ctx, req)
+	_, err := client.ResolveEvent(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to resolve event: %w", err)
 	}
@@ -206,7 +211,7 @@ func (p *EnhancedPagerDutyPublisher) resolveEvent(ctx context.Context, enrichedA
 }
 
 // sendChangeEvent sends a change event to PagerDuty (deployment, config change, etc.)
-func (p *EnhancedPagerDutyPublisher) sendChangeEvent(ctx context.Context, enrichedAlert *core.EnrichedAlert, routingKey string) error {
+func (p *EnhancedPagerDutyPublisher) sendChangeEvent(ctx context.Context, client PagerDutyEventsClient, enrichedAlert *core.EnrichedAlert, routingKey string) error {
 	alert := enrichedAlert.Alert
 
 	// Build change event request
@@ -227,7 +232,7 @@ func (p *EnhancedPagerDutyPublisher) sendChangeEvent(ctx context.Context, enrich
 	}
 
 	// Send to PagerDuty
-	_, err := p.client.SendChangeEvent(ctx, req)
+	_, err := client.SendChangeEvent(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to send change event: %w", err)
 	}
@@ -248,24 +253,23 @@ func (p *EnhancedPagerDutyPublisher) sendChangeEvent(ctx context.Context, enrich
 
 // Helper Methods
 
-// extractRoutingKey extracts routing key from target configuration
-func (p *EnhancedPagerDutyPublisher) extractRoutingKey(target *core.PublishingTarget) string {
-	// Check target headers for routing_key
-	if routingKey, ok := target.Headers["routing_key"]; ok {
-		return routingKey
-	}
-
-	// Check for Authorization header (Bearer token format)
-	if auth, ok := target.Headers["Authorization"]; ok {
-		// Remove "Bearer " prefix if present
-		const bearerPrefix = "Bearer "
-		if len(auth) > len(bearerPrefix) && auth[:len(bearerPrefix)] == bearerPrefix {
-			return auth[len(bearerPrefix):]
-		}
-		return auth
+// dedupKey returns the dedup key of the PagerDuty event of alert. Triggers
+// use the fingerprint, so alerts triggered before a restart (or by another
+// replica) are acknowledged and resolved without a cached key.
+func (p *EnhancedPagerDutyPublisher) dedupKey(alert *core.Alert) string {
+	if dedupKey, found := p.cache.Get(alert.Fingerprint); found {
+		return dedupKey
 	}
+	p.GetLogger().Debug("PagerDuty event not tracked in cache, using fingerprint as dedup key",
+		"fingerprint", alert.Fingerprint,
+		"alert_name", alert.AlertName,
+	)
+	return alert.Fingerprint
+}
 
-	return ""
+// extractRoutingKey extracts routing key from target configuration
+func (p *EnhancedPagerDutyPublisher) extractRoutingKey(target *core.PublishingTarget) string {
+	return pagerDutyRoutingKey(target)
 }
 
 // buildPayload builds TriggerEventPayload from formatted alert data
@@ -318,6 +322,14 @@ func (p *EnhancedPagerDutyPublisher) extractLinks(alert *core.Alert) []EventLink
 		})
 	}
 
+	// Extract link to the trace the alert was raised from
+	if traceURL, ok := alert.Annotations[core.TraceURLAnnotation]; ok {
+		links = append(links, EventLink{
+			Href: traceURL,
+			Text: "Trace",
+		})
+	}
+
 	return links
 }
 
@@ -336,21 +348,24 @@ func (p *EnhancedPagerDutyPublisher) extractImages(alert *core.Alert) []EventIma
 	return images
 }
 
-// getSeverity gets severity from enriched alert
+// getSeverity maps the effective severity of the alert (see
+// core.EnrichedAlert.ApplySeverityPolicy) to a PagerDuty severity. Alerts
+// without any severity default to warning.
 func getSeverity(enrichedAlert *core.EnrichedAlert) string {
-	if enrichedAlert.Classification != nil {
-		switch enrichedAlert.Classification.Severity {
-		case core.SeverityCritical:
-			return SeverityCritical
-		case core.SeverityWarning:
-			return SeverityWarning
-		case core.SeverityInfo:
-			return SeverityInfo
-		default:
-			return SeverityWarning
-		}
+	severity, source := enrichedAlert.EffectiveSeverity()
+	if source == domain.SeveritySourceDefault {
+		return SeverityWarning
+	}
+	switch severity {
+	case core.SeverityCritical:
+		return SeverityCritical
+	case core.SeverityWarning:
+		return SeverityWarning
+	case core.SeverityInfo:
+		return SeverityInfo
+	default:
+		return SeverityWarning
 	}
-	return SeverityWarning
 }
 
 // isChangeEvent checks if alert is a change event (deployment, config change, etc.)
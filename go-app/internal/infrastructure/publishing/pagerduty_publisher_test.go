@@ -0,0 +1,67 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// TestEnhancedPagerDutyPublisher_ResolveUntrackedEvent tests resolving an
+// alert triggered before a restart: the fingerprint is the dedup key.
+func TestEnhancedPagerDutyPublisher_ResolveUntrackedEvent(t *testing.T) {
+	var received ResolveEventRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/events", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(EventResponse{Status: "success", DedupKey: received.DedupKey})
+	}))
+	defer server.Close()
+
+	publisher := newEnhancedPagerDutyPublisher(newPagerDutyClients(slog.Default()), NewEventKeyCache(time.Hour), nil, nil, slog.Default())
+	target := &core.PublishingTarget{
+		Name:    "pagerduty",
+		Type:    "pagerduty",
+		URL:     server.URL + "/v2/enqueue",
+		Headers: map[string]string{"routing_key": "test-key"},
+	}
+	alert := &core.EnrichedAlert{Alert: &core.Alert{Fingerprint: "fp-1", AlertName: "DiskFull", Status: core.StatusResolved}}
+
+	require.NoError(t, publisher.Publish(context.Background(), alert, target))
+	assert.Equal(t, "resolve", received.EventAction)
+	assert.Equal(t, "test-key", received.RoutingKey)
+	assert.Equal(t, "fp-1", received.DedupKey)
+}
+
+// TestPagerDutyClients tests the per-target client cache
+func TestPagerDutyClients(t *testing.T) {
+	clients := newPagerDutyClients(slog.Default())
+
+	a := &core.PublishingTarget{Name: "a", URL: "https://events.pagerduty.com", Headers: map[string]string{"routing_key": "key-a"}}
+	b := &core.PublishingTarget{Name: "b", URL: "https://events.pagerduty.com", Headers: map[string]string{"Authorization": "Bearer key-b"}}
+
+	clientA, err := clients.get(a)
+	require.NoError(t, err)
+	clientB, err := clients.get(b)
+	require.NoError(t, err)
+	assert.NotSame(t, clientA, clientB)
+
+	again, err := clients.get(a)
+	require.NoError(t, err)
+	assert.Same(t, clientA, again)
+
+	_, err = clients.get(&core.PublishingTarget{Name: "none", URL: "https://events.pagerduty.com"})
+	assert.ErrorIs(t, err, ErrMissingRoutingKey)
+
+	clients.retain([]*core.PublishingTarget{b})
+	assert.Len(t, clients.clients, 1)
+}
@@ -560,8 +560,16 @@ func (p *DefaultParallelPublisher) publishToTarget(
 		return
 	}
 
-	// Publish alert
-	err = publisher.Publish(ctx, alert, target)
+	// Publish alert, redacted for the target and through its outbound HTTP
+	// config
+	publishCtx, redacted, err := redactForTarget(ctx, target, []*core.EnrichedAlert{alert})
+	if err != nil {
+		err = fmt.Errorf("failed to redact alert: %w", err)
+	} else if publishCtx, err = withTargetHTTPConfig(publishCtx, target); err != nil {
+		err = fmt.Errorf("invalid outbound HTTP config: %w", err)
+	} else {
+		err = publisher.Publish(publishCtx, redacted[0], target)
+	}
 	result.Duration = time.Since(startTime)
 
 	// Handle result
@@ -0,0 +1,108 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// targetPayloadTemplateHeader is the target header holding a Go
+// text/template that renders the JSON body sent to the target instead of the
+// built-in format. It is never sent.
+//
+// The template is executed with the *core.EnrichedAlert, e.g.
+//
+//	{"text": "{{ .Alert.AlertName }} is {{ .Alert.Status }}",
+//	 "severity": {{ .Alert.Labels.severity | default "warning" | toJson }}
+//	 {{- with .Classification }}, "ai_severity": "{{ .Severity }}"{{ end }}}
+//
+// with the sprig functions except env and expandenv.
+const targetPayloadTemplateHeader = "payload_template"
+
+// payloadTemplateTargetTypes are the target types honouring payload
+// templates: those posting a generic JSON payload.
+var payloadTemplateTargetTypes = []string{"webhook", "alertmanager", "exec"}
+
+var payloadTemplateFuncs = func() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	// Templates come from target secrets; do not expose the environment
+	delete(funcs, "env")
+	delete(funcs, "expandenv")
+	return funcs
+}()
+
+// ParsePayloadTemplate parses the payload template of a target.
+func ParsePayloadTemplate(text string) (*template.Template, error) {
+	return template.New(targetPayloadTemplateHeader).Funcs(payloadTemplateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// ValidateTargetPayloadTemplate checks the payload template of target, if
+// any: it must parse and the target type must support it.
+func ValidateTargetPayloadTemplate(target *core.PublishingTarget) error {
+	text, ok := target.Headers[targetPayloadTemplateHeader]
+	if !ok {
+		return nil
+	}
+	supported := false
+	for _, targetType := range payloadTemplateTargetTypes {
+		if target.Type == targetType {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%s is only supported by webhook, alertmanager and exec targets", targetPayloadTemplateHeader)
+	}
+	if _, err := ParsePayloadTemplate(text); err != nil {
+		return fmt.Errorf("%s: %w", targetPayloadTemplateHeader, err)
+	}
+	return nil
+}
+
+type payloadTemplateKey struct{}
+
+// withTargetPayloadTemplate attaches the payload template of target to ctx;
+// the formatter renders it instead of the target format. Invalid templates
+// are rejected by target discovery; should one get here anyway, formatting
+// fails rather than sending the built-in payload the receiver rejects.
+func withTargetPayloadTemplate(ctx context.Context, target *core.PublishingTarget) context.Context {
+	if target == nil {
+		return ctx
+	}
+	text, ok := target.Headers[targetPayloadTemplateHeader]
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, payloadTemplateKey{}, text)
+}
+
+// payloadTemplateFromContext returns the template attached by
+// withTargetPayloadTemplate.
+func payloadTemplateFromContext(ctx context.Context) (string, bool) {
+	text, ok := ctx.Value(payloadTemplateKey{}).(string)
+	return text, ok
+}
+
+// renderPayloadTemplate renders text with enrichedAlert. The output must be
+// a JSON object.
+func renderPayloadTemplate(text string, enrichedAlert *core.EnrichedAlert) (map[string]any, error) {
+	tmpl, err := ParsePayloadTemplate(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, enrichedAlert); err != nil {
+		return nil, fmt.Errorf("payload template: %w", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		return nil, fmt.Errorf("payload template must render a JSON object: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,122 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+const testPayloadTemplate = `{
+  "text": "{{ .Alert.AlertName }} is {{ .Alert.Status | toString | upper }}",
+  "instance": {{ .Alert.Labels.instance | default "unknown" | toJson }}
+  {{- with .Classification }}, "ai_severity": "{{ .Severity }}"{{ end }}
+}`
+
+func TestFormatAlert_PayloadTemplate(t *testing.T) {
+	formatter := NewAlertFormatter("")
+	target := &core.PublishingTarget{Type: "webhook", Headers: map[string]string{
+		targetPayloadTemplateHeader: testPayloadTemplate,
+	}}
+	ctx := withTargetPayloadTemplate(context.Background(), target)
+
+	payload, err := formatter.FormatAlert(ctx, createTestEnrichedAlert(), core.FormatWebhook)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"text":        "TestAlert is FIRING",
+		"instance":    "unknown",
+		"ai_severity": "warning",
+	}, payload)
+
+	// Without classification
+	alert := createTestEnrichedAlert()
+	alert.Classification = nil
+	payload, err = formatter.FormatAlert(ctx, alert, core.FormatWebhook)
+	require.NoError(t, err)
+	assert.NotContains(t, payload, "ai_severity")
+
+	// Other targets keep the built-in format
+	payload, err = formatter.FormatAlert(context.Background(), createTestEnrichedAlert(), core.FormatWebhook)
+	require.NoError(t, err)
+	assert.NotContains(t, payload, "text")
+
+	t.Run("output must be a JSON object", func(t *testing.T) {
+		target := &core.PublishingTarget{Headers: map[string]string{targetPayloadTemplateHeader: `{{ .Alert.AlertName }}`}}
+		_, err := formatter.FormatAlert(withTargetPayloadTemplate(context.Background(), target), createTestEnrichedAlert(), core.FormatWebhook)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "JSON object")
+	})
+
+	t.Run("environment is not exposed", func(t *testing.T) {
+		_, err := ParsePayloadTemplate(`{"home": "{{ env "HOME" }}"}`)
+		require.Error(t, err)
+	})
+}
+
+func TestValidateTargetPayloadTemplate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		targetType string
+		template   string
+		wantErr    string
+	}{
+		"webhook":        {targetType: "webhook", template: testPayloadTemplate},
+		"exec":           {targetType: "exec", template: testPayloadTemplate},
+		"syntax error":   {targetType: "webhook", template: `{{ .Alert.AlertName `, wantErr: "payload_template:"},
+		"unknown func":   {targetType: "alertmanager", template: `{{ nosuchfunc }}`, wantErr: "payload_template:"},
+		"unsupported":    {targetType: "slack", template: testPayloadTemplate, wantErr: "only supported by"},
+		"no template":    {targetType: "slack"},
+		"empty template": {targetType: "webhook", template: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			target := &core.PublishingTarget{Type: tc.targetType, Headers: map[string]string{}}
+			if name != "no template" {
+				target.Headers[targetPayloadTemplateHeader] = tc.template
+			}
+			err := ValidateTargetPayloadTemplate(target)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestWebhookPublisher_PayloadTemplate(t *testing.T) {
+	var body map[string]any
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := &core.PublishingTarget{
+		Name: "picky",
+		Type: "webhook",
+		URL:  server.URL,
+		Headers: map[string]string{
+			"X-Token":                   "secret",
+			targetPayloadTemplateHeader: testPayloadTemplate,
+		},
+		Format: core.FormatWebhook,
+	}
+	publisher := NewWebhookPublisher(NewAlertFormatter(""), slog.Default())
+	require.NoError(t, publisher.Publish(context.Background(), createTestEnrichedAlert(), target))
+
+	assert.Equal(t, "TestAlert is FIRING", body["text"])
+	assert.Equal(t, "secret", header.Get("X-Token"))
+	assert.Empty(t, header.Get(targetPayloadTemplateHeader), "the template is not sent")
+	assert.NotContains(t, targetHTTPHeaders(target.Headers), targetPayloadTemplateHeader)
+}
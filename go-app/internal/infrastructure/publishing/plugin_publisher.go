@@ -0,0 +1,155 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+	"github.com/ipiton/AMP/pkg/publisherplugin"
+)
+
+// plugin_publisher.go - publisher delegating to external publisher plugins
+
+// pluginHeader is the target header naming the plugin of a plugin target.
+const pluginHeader = "plugin"
+
+// ValidatePluginTarget checks the plugin and URL of a plugin target. The URL
+// is passed to the plugin; any absolute URL is accepted.
+func ValidatePluginTarget(target *core.PublishingTarget) error {
+	name := target.Headers[pluginHeader]
+	if name == "" {
+		return errors.New("plugin targets require the plugin header")
+	}
+	if !pluginNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid plugin name %q", name)
+	}
+	parsed, err := url.Parse(target.URL)
+	if err != nil || !parsed.IsAbs() {
+		return errors.New("must be an absolute URL")
+	}
+	return nil
+}
+
+// pluginPublishRequest is the publisherplugin.PublishRequest sent to plugins,
+// with AMP's types (same JSON).
+type pluginPublishRequest struct {
+	Alert  *core.EnrichedAlert    `json:"alert"`
+	Target *core.PublishingTarget `json:"target"`
+}
+
+// EnhancedPluginPublisher delivers alerts through external publisher plugins
+// (pkg/publisherplugin). The plugin of a target is named by its "plugin"
+// header; the enriched alert and the target are passed to it as is.
+//
+// gRPC status codes of the plugin are mapped to HTTP errors, so the queue
+// retries Unavailable, ResourceExhausted and DeadlineExceeded failures and
+// sends InvalidArgument, FailedPrecondition and other permanent failures to
+// the DLQ. A plugin that is not running is retried.
+type EnhancedPluginPublisher struct {
+	*BaseEnhancedPublisher                   // Embedded base publisher for common functionality
+	plugins                *PluginSupervisor // Running plugins (nil: none configured)
+}
+
+// NewEnhancedPluginPublisher creates a publisher for the plugins of
+// supervisor.
+func NewEnhancedPluginPublisher(
+	plugins *PluginSupervisor,
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return &EnhancedPluginPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "plugin_publisher"),
+		),
+		plugins: plugins,
+	}
+}
+
+// Publish passes enrichedAlert to the plugin of target.
+func (p *EnhancedPluginPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	name := target.Headers[pluginHeader]
+	if p.plugins == nil {
+		return fmt.Errorf("%w: %q (no plugins configured)", ErrPluginNotFound, name)
+	}
+	conn, err := p.plugins.conn(name)
+	if errors.Is(err, ErrPluginUnavailable) {
+		return httperror.NewHTTPErrorWithCause(http.StatusServiceUnavailable, err.Error(), ProviderPlugin, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	alert := enrichedAlert.Alert
+	p.LogPublishStart(ctx, v2.ProviderPlugin, enrichedAlert)
+
+	startTime := time.Now()
+	err = conn.Invoke(ctx, publisherplugin.PublishMethod,
+		&pluginPublishRequest{Alert: enrichedAlert, Target: target}, &publisherplugin.PublishResponse{})
+	duration := time.Since(startTime)
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordAPIDuration(v2.ProviderPlugin, "publish", "GRPC", duration)
+	}
+	if err != nil {
+		err = pluginCallError(name, err)
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderPlugin, "publish", GetPublishingErrorType(err))
+		}
+		p.LogPublishError(ctx, v2.ProviderPlugin, alert.Fingerprint, err)
+		return fmt.Errorf("failed to deliver to %s: %w", target.Name, err)
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordMessage(v2.ProviderPlugin, "success")
+	}
+	p.LogPublishSuccess(ctx, v2.ProviderPlugin, alert.Fingerprint, duration)
+	return nil
+}
+
+// Name returns publisher name
+func (p *EnhancedPluginPublisher) Name() string {
+	return "Plugin"
+}
+
+// pluginStatusHTTPCodes maps the gRPC status codes of plugins to the HTTP
+// status codes the queue classifies.
+var pluginStatusHTTPCodes = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// pluginCallError converts the gRPC error of a call to plugin name into an
+// *httperror.HTTPAPIError. Unknown and other codes stay plain errors.
+func pluginCallError(name string, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	message := "plugin " + name + ": " + st.Message()
+	code, ok := pluginStatusHTTPCodes[st.Code()]
+	if !ok {
+		return errors.New(message)
+	}
+	return httperror.NewHTTPError(code, message, ProviderPlugin)
+}
@@ -0,0 +1,243 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/publisherplugin"
+)
+
+// testPluginEnv makes the test binary serve testPluginPublisher (see
+// TestHelperPublisherPlugin).
+const testPluginEnv = "AMP_TEST_PUBLISHER_PLUGIN"
+
+// testPluginPublisher appends the alerts it receives to the file named by
+// its output, and fails or exits as the plugin_error label of the alert
+// says.
+type testPluginPublisher struct {
+	output string
+}
+
+func (p testPluginPublisher) Name() string { return "test" }
+
+func (p testPluginPublisher) Publish(_ context.Context, alert *publisherplugin.EnrichedAlert, target *publisherplugin.Target) error {
+	switch alert.Alert.Labels["plugin_error"] {
+	case "exit":
+		os.Exit(1)
+	case "permanent":
+		return publisherplugin.Permanent(errors.New("no such channel"))
+	case "unavailable":
+		return publisherplugin.Retryable(errors.New("backend down"))
+	}
+	f, err := os.OpenFile(p.output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s %s %s\n", alert.Alert.Fingerprint, target.Name, target.URL)
+	return err
+}
+
+// TestHelperPublisherPlugin is the plugin binary of the tests, run as a
+// child process of the test binary.
+func TestHelperPublisherPlugin(t *testing.T) {
+	output := os.Getenv(testPluginEnv)
+	if output == "" {
+		t.Skip("publisher plugin helper process")
+	}
+	if err := publisherplugin.Serve(testPluginPublisher{output: output}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+// startTestPlugins starts a supervisor of the test plugin "test" and
+// returns it with the file the plugin writes received alerts to.
+func startTestPlugins(t *testing.T) (*PluginSupervisor, string) {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "published")
+	plugins, err := NewPluginSupervisor([]PluginConfig{{
+		Name:           "test",
+		Path:           os.Args[0],
+		Args:           []string{"-test.run=^TestHelperPublisherPlugin$"},
+		Env:            []string{testPluginEnv + "=" + output},
+		HealthInterval: 100 * time.Millisecond,
+	}}, slog.Default())
+	require.NoError(t, err)
+	plugins.Start()
+	t.Cleanup(func() { plugins.Stop(5 * time.Second) })
+	return plugins, output
+}
+
+func pluginTestAlert(labels map[string]string) *core.EnrichedAlert {
+	return &core.EnrichedAlert{Alert: &core.Alert{
+		Fingerprint: "fp-1",
+		AlertName:   "HighCPU",
+		Status:      core.StatusFiring,
+		Labels:      labels,
+		StartsAt:    time.Now(),
+	}}
+}
+
+func pluginTestTarget() *core.PublishingTarget {
+	return &core.PublishingTarget{
+		Name:    "pager",
+		Type:    "plugin",
+		URL:     "pager://oncall/primary",
+		Format:  core.FormatPlugin,
+		Headers: map[string]string{"plugin": "test"},
+	}
+}
+
+func TestPluginPublisher_Publish(t *testing.T) {
+	plugins, output := startTestPlugins(t)
+	publisher := NewEnhancedPluginPublisher(plugins, nil, NewAlertFormatter(""), slog.Default())
+
+	require.NoError(t, publisher.Publish(context.Background(), pluginTestAlert(nil), pluginTestTarget()))
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "fp-1 pager pager://oncall/primary\n", string(data))
+
+	err = publisher.Publish(context.Background(), pluginTestAlert(map[string]string{"plugin_error": "permanent"}), pluginTestTarget())
+	require.Error(t, err)
+	assert.Equal(t, QueueErrorTypePermanent, classifyPublishingError(err))
+	assert.Contains(t, err.Error(), "no such channel")
+
+	err = publisher.Publish(context.Background(), pluginTestAlert(map[string]string{"plugin_error": "unavailable"}), pluginTestTarget())
+	require.Error(t, err)
+	assert.Equal(t, QueueErrorTypeTransient, classifyPublishingError(err))
+}
+
+func TestPluginPublisher_UnknownPlugin(t *testing.T) {
+	target := pluginTestTarget()
+	target.Headers["plugin"] = "missing"
+
+	publisher := NewEnhancedPluginPublisher(nil, nil, NewAlertFormatter(""), slog.Default())
+	assert.ErrorIs(t, publisher.Publish(context.Background(), pluginTestAlert(nil), target), ErrPluginNotFound)
+
+	plugins, err := NewPluginSupervisor(nil, slog.Default())
+	require.NoError(t, err)
+	defer plugins.Stop(time.Second)
+	publisher = NewEnhancedPluginPublisher(plugins, nil, NewAlertFormatter(""), slog.Default())
+	assert.ErrorIs(t, publisher.Publish(context.Background(), pluginTestAlert(nil), target), ErrPluginNotFound)
+}
+
+func TestPluginSupervisor_LaunchFailure(t *testing.T) {
+	plugins, err := NewPluginSupervisor([]PluginConfig{{
+		Name:         "broken",
+		Path:         filepath.Join(t.TempDir(), "missing-plugin"),
+		StartTimeout: time.Second,
+	}}, slog.Default())
+	require.NoError(t, err)
+	plugins.Start()
+	defer plugins.Stop(time.Second)
+
+	target := pluginTestTarget()
+	target.Headers["plugin"] = "broken"
+	publisher := NewEnhancedPluginPublisher(plugins, nil, NewAlertFormatter(""), slog.Default())
+	err = publisher.Publish(context.Background(), pluginTestAlert(nil), target)
+	assert.ErrorIs(t, err, ErrPluginUnavailable)
+	assert.Equal(t, QueueErrorTypeTransient, classifyPublishingError(err))
+}
+
+func TestPluginSupervisor_RestartsExitedPlugin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the restart backoff")
+	}
+	plugins, output := startTestPlugins(t)
+	publisher := NewEnhancedPluginPublisher(plugins, nil, NewAlertFormatter(""), slog.Default())
+	pid := plugins.plugins["test"].pid()
+
+	err := publisher.Publish(context.Background(), pluginTestAlert(map[string]string{"plugin_error": "exit"}), pluginTestTarget())
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool {
+		newPID := plugins.plugins["test"].pid()
+		return newPID != 0 && newPID != pid
+	}, 10*time.Second, 50*time.Millisecond)
+	require.NoError(t, publisher.Publish(context.Background(), pluginTestAlert(nil), pluginTestTarget()))
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "fp-1 pager")
+}
+
+func TestNewPluginSupervisor_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []PluginConfig
+	}{
+		{"invalid name", []PluginConfig{{Name: "My_Plugin", Path: "/bin/true"}}},
+		{"duplicate", []PluginConfig{{Name: "a", Path: "/bin/true"}, {Name: "a", Path: "/bin/false"}}},
+		{"no path", []PluginConfig{{Name: "a"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPluginSupervisor(tt.plugins, slog.Default())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidatePluginTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		plugin  string
+		wantErr bool
+	}{
+		{"custom scheme", "pager://oncall/primary", "pager", false},
+		{"https", "https://pager.example.com/api", "pager", false},
+		{"missing plugin", "pager://oncall", "", true},
+		{"invalid plugin", "pager://oncall", "Pager_1", true},
+		{"relative url", "oncall/primary", "pager", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &core.PublishingTarget{Name: "t", Type: "plugin", URL: tt.url, Headers: map[string]string{}}
+			if tt.plugin != "" {
+				target.Headers["plugin"] = tt.plugin
+			}
+			err := ValidatePluginTarget(target)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPluginCallError(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want QueueErrorType
+	}{
+		{codes.Unavailable, QueueErrorTypeTransient},
+		{codes.ResourceExhausted, QueueErrorTypeTransient},
+		{codes.DeadlineExceeded, QueueErrorTypeTransient},
+		{codes.InvalidArgument, QueueErrorTypePermanent},
+		{codes.FailedPrecondition, QueueErrorTypePermanent},
+		{codes.PermissionDenied, QueueErrorTypePermanent},
+		{codes.Unknown, QueueErrorTypeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := pluginCallError("pager", status.Error(tt.code, "failed"))
+			assert.Equal(t, tt.want, classifyPublishingError(err))
+			assert.Contains(t, err.Error(), "plugin pager: failed")
+		})
+	}
+}
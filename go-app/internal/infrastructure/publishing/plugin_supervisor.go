@@ -0,0 +1,430 @@
+package publishing
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/ipiton/AMP/pkg/publisherplugin"
+)
+
+// plugin_supervisor.go - launches and supervises external publisher plugins
+
+// PluginConfig declares an external publisher plugin.
+type PluginConfig struct {
+	// Name identifies the plugin in the "plugin" header of targets.
+	Name string
+	// Path of the plugin binary.
+	Path string
+	// Args and Env (KEY=VALUE, added to AMP's environment) the binary is
+	// launched with.
+	Args []string
+	Env  []string
+	// StartTimeout bounds the launch until the plugin is healthy (default: 10s).
+	StartTimeout time.Duration
+	// HealthInterval between health checks (default: 30s).
+	HealthInterval time.Duration
+	// FailureThreshold is the number of consecutive failed health checks
+	// restarting the plugin (default: 3).
+	FailureThreshold int
+}
+
+// Plugin supervision defaults.
+const (
+	DefaultPluginStartTimeout     = 10 * time.Second
+	DefaultPluginHealthInterval   = 30 * time.Second
+	DefaultPluginFailureThreshold = 3
+
+	pluginCallTimeout        = 5 * time.Second
+	pluginRestartMinBackoff  = time.Second
+	pluginRestartMaxBackoff  = time.Minute
+	pluginSocketPollInterval = 50 * time.Millisecond
+)
+
+// pluginNameRegex matches valid plugin names.
+var pluginNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+var (
+	// ErrPluginNotFound is returned for plugins that are not configured.
+	ErrPluginNotFound = errors.New("publisher plugin not configured")
+	// ErrPluginUnavailable is returned while a plugin is not running.
+	ErrPluginUnavailable = errors.New("publisher plugin not running")
+)
+
+// PluginSupervisor runs the external publisher plugins: it launches their
+// binaries, waits until they serve the plugin protocol (pkg/publisherplugin)
+// on a unix socket, checks their health and restarts them, with exponential
+// backoff, when they exit or fail FailureThreshold health checks in a row.
+type PluginSupervisor struct {
+	plugins   map[string]*supervisedPlugin
+	socketDir string
+	logger    *slog.Logger
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// supervisedPlugin is a plugin and its running process.
+type supervisedPlugin struct {
+	config PluginConfig
+	socket string
+	logger *slog.Logger
+
+	mu       sync.RWMutex
+	process  *pluginProcess // nil while not running
+	restarts int
+}
+
+// pluginProcess is a launched plugin binary.
+type pluginProcess struct {
+	cmd     *exec.Cmd
+	conn    *grpc.ClientConn
+	exited  chan struct{} // closed when the process exited
+	waitErr error         // set before exited is closed
+}
+
+// NewPluginSupervisor validates plugins and creates their supervisor.
+func NewPluginSupervisor(plugins []PluginConfig, logger *slog.Logger) (*PluginSupervisor, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	socketDir, err := os.MkdirTemp("", "amp-plugins-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plugin socket directory: %w", err)
+	}
+
+	s := &PluginSupervisor{
+		plugins:   make(map[string]*supervisedPlugin, len(plugins)),
+		socketDir: socketDir,
+		logger:    logger.With("component", "plugin_supervisor"),
+		stopCh:    make(chan struct{}),
+	}
+	for _, config := range plugins {
+		if !pluginNameRegex.MatchString(config.Name) {
+			os.RemoveAll(socketDir)
+			return nil, fmt.Errorf("invalid plugin name %q", config.Name)
+		}
+		if _, ok := s.plugins[config.Name]; ok {
+			os.RemoveAll(socketDir)
+			return nil, fmt.Errorf("duplicate plugin %q", config.Name)
+		}
+		if config.Path == "" {
+			os.RemoveAll(socketDir)
+			return nil, fmt.Errorf("plugin %q: path is required", config.Name)
+		}
+		if config.StartTimeout <= 0 {
+			config.StartTimeout = DefaultPluginStartTimeout
+		}
+		if config.HealthInterval <= 0 {
+			config.HealthInterval = DefaultPluginHealthInterval
+		}
+		if config.FailureThreshold <= 0 {
+			config.FailureThreshold = DefaultPluginFailureThreshold
+		}
+		s.plugins[config.Name] = &supervisedPlugin{
+			config: config,
+			socket: filepath.Join(socketDir, config.Name+".sock"),
+			logger: s.logger.With("plugin", config.Name),
+		}
+	}
+	return s, nil
+}
+
+// Start launches the plugins and supervises them until Stop. It returns once
+// every plugin was launched or failed to launch; failed plugins are retried
+// in the background.
+func (s *PluginSupervisor) Start() {
+	var launched sync.WaitGroup
+	for _, p := range s.plugins {
+		launched.Add(1)
+		s.wg.Add(1)
+		go s.supervise(p, launched.Done)
+	}
+	launched.Wait()
+	s.logger.Info("Publisher plugins started", "plugins", slices.Sorted(maps.Keys(s.plugins)))
+}
+
+// Stop asks the plugins to shut down, kills those still running after
+// timeout and removes their sockets.
+func (s *PluginSupervisor) Stop(timeout time.Duration) {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	s.wg.Wait()
+	for _, p := range s.plugins {
+		p.terminate(timeout)
+	}
+	os.RemoveAll(s.socketDir)
+	s.logger.Info("Publisher plugins stopped")
+}
+
+// conn returns the connection to the running plugin name.
+func (s *PluginSupervisor) conn(name string) (*grpc.ClientConn, error) {
+	p, ok := s.plugins[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrPluginNotFound, name)
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.process == nil {
+		return nil, fmt.Errorf("%w: %q", ErrPluginUnavailable, name)
+	}
+	return p.process.conn, nil
+}
+
+// supervise launches p and relaunches it until Stop. launched is called after
+// the first launch.
+func (s *PluginSupervisor) supervise(p *supervisedPlugin, launched func()) {
+	defer s.wg.Done()
+
+	backoff := pluginRestartMinBackoff
+	for {
+		err := p.launch()
+		if launched != nil {
+			launched()
+			launched = nil
+		}
+		if err != nil {
+			p.logger.Error("Failed to launch publisher plugin", "path", p.config.Path, "error", err)
+		} else {
+			startedAt := time.Now()
+			p.logger.Info("Publisher plugin running", "pid", p.pid())
+			if !s.watch(p) {
+				return // stopping: Stop terminates the plugin
+			}
+			p.terminate(pluginCallTimeout)
+			if time.Since(startedAt) > pluginRestartMaxBackoff {
+				backoff = pluginRestartMinBackoff
+			}
+		}
+
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, pluginRestartMaxBackoff)
+
+		p.mu.Lock()
+		p.restarts++
+		restarts := p.restarts
+		p.mu.Unlock()
+		p.logger.Warn("Restarting publisher plugin", "restarts", restarts)
+	}
+}
+
+// watch checks the health of the running p until it exits, fails
+// FailureThreshold health checks in a row (returning true) or the supervisor
+// stops (returning false).
+func (s *PluginSupervisor) watch(p *supervisedPlugin) bool {
+	p.mu.RLock()
+	process := p.process
+	p.mu.RUnlock()
+
+	ticker := time.NewTicker(p.config.HealthInterval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-s.stopCh:
+			return false
+		case <-process.exited:
+			p.logger.Error("Publisher plugin exited", "error", process.waitErr)
+			return true
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), pluginCallTimeout)
+			err := checkPluginHealth(ctx, process.conn)
+			cancel()
+			if err == nil {
+				failures = 0
+				continue
+			}
+			failures++
+			p.logger.Warn("Publisher plugin health check failed",
+				"error", err,
+				"failures", failures,
+				"threshold", p.config.FailureThreshold)
+			if failures >= p.config.FailureThreshold {
+				return true
+			}
+		}
+	}
+}
+
+// launch starts the plugin binary and waits until it serves healthy on its
+// socket.
+func (p *supervisedPlugin) launch() error {
+	os.Remove(p.socket)
+
+	cmd := exec.Command(p.config.Path, p.config.Args...)
+	cmd.Env = append(os.Environ(),
+		publisherplugin.MagicCookieEnv+"="+publisherplugin.MagicCookieValue,
+		publisherplugin.ProtocolVersionEnv+"="+publisherplugin.ProtocolVersion,
+		publisherplugin.SocketEnv+"="+p.socket,
+		publisherplugin.NameEnv+"="+p.config.Name,
+	)
+	cmd.Env = append(cmd.Env, p.config.Env...)
+	stdout, err := newPluginLogPipe(p.logger, "stdout")
+	if err != nil {
+		return err
+	}
+	stderr, err := newPluginLogPipe(p.logger, "stderr")
+	if err != nil {
+		stdout.Close()
+		return err
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err = cmd.Start()
+	// The plugin holds its own copies of the pipe ends
+	stdout.Close()
+	stderr.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+
+	process := &pluginProcess{cmd: cmd, exited: make(chan struct{})}
+	go func() {
+		process.waitErr = cmd.Wait()
+		close(process.exited)
+	}()
+
+	if err := process.connect(p.socket, p.config.StartTimeout); err != nil {
+		process.kill()
+		return err
+	}
+
+	p.mu.Lock()
+	p.process = process
+	p.mu.Unlock()
+	return nil
+}
+
+// connect waits for the plugin to listen on socket and report healthy.
+func (process *pluginProcess) connect(socket string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		if _, err := os.Stat(socket); err == nil {
+			break
+		}
+		select {
+		case <-process.exited:
+			return fmt.Errorf("exited during startup: %v", process.waitErr)
+		case <-ctx.Done():
+			return fmt.Errorf("not listening on %s after %s", socket, timeout)
+		case <-time.After(pluginSocketPollInterval):
+		}
+	}
+
+	conn, err := grpc.NewClient("unix://"+socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(publisherplugin.CodecName)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	if err := checkPluginHealth(ctx, conn, grpc.WaitForReady(true)); err != nil {
+		conn.Close()
+		return fmt.Errorf("not healthy after %s: %w", timeout, err)
+	}
+	process.conn = conn
+	return nil
+}
+
+// kill kills the process and waits for it to exit.
+func (process *pluginProcess) kill() {
+	process.cmd.Process.Kill()
+	<-process.exited
+	if process.conn != nil {
+		process.conn.Close()
+	}
+}
+
+// terminate asks the running plugin to shut down and kills it if it does
+// not exit within timeout.
+func (p *supervisedPlugin) terminate(timeout time.Duration) {
+	p.mu.Lock()
+	process := p.process
+	p.process = nil
+	p.mu.Unlock()
+	if process == nil {
+		return
+	}
+
+	select {
+	case <-process.exited:
+	default:
+		ctx, cancel := context.WithTimeout(context.Background(), min(timeout, pluginCallTimeout))
+		err := process.conn.Invoke(ctx, publisherplugin.ShutdownMethod, &publisherplugin.ShutdownRequest{}, &publisherplugin.ShutdownResponse{})
+		cancel()
+		if err != nil {
+			p.logger.Warn("Publisher plugin shutdown failed", "error", err)
+		}
+		select {
+		case <-process.exited:
+		case <-time.After(timeout):
+			p.logger.Warn("Killing publisher plugin after shutdown timeout", "timeout", timeout)
+		}
+	}
+	process.kill()
+}
+
+// pid returns the process ID of the running plugin (0 if not running).
+func (p *supervisedPlugin) pid() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.process == nil {
+		return 0
+	}
+	return p.process.cmd.Process.Pid
+}
+
+// checkPluginHealth calls the Health method of the plugin on conn.
+func checkPluginHealth(ctx context.Context, conn *grpc.ClientConn, opts ...grpc.CallOption) error {
+	var resp publisherplugin.HealthResponse
+	if err := conn.Invoke(ctx, publisherplugin.HealthMethod, &publisherplugin.HealthRequest{}, &resp, opts...); err != nil {
+		return err
+	}
+	if resp.ProtocolVersion != publisherplugin.ProtocolVersion {
+		return fmt.Errorf("plugin speaks protocol version %q, AMP %q", resp.ProtocolVersion, publisherplugin.ProtocolVersion)
+	}
+	if !resp.Healthy {
+		return fmt.Errorf("unhealthy: %s", resp.Message)
+	}
+	return nil
+}
+
+// newPluginLogPipe returns the write end of a pipe whose lines are logged
+// as the output of a plugin on stream. The reader stops when every copy of
+// the write end is closed.
+func newPluginLogPipe(logger *slog.Logger, stream string) (*os.File, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s pipe: %w", stream, err)
+	}
+	go func() {
+		defer reader.Close()
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			logger.Info(scanner.Text(), "stream", stream)
+		}
+		// Keep draining after an overlong line so the plugin never blocks
+		io.Copy(io.Discard, reader)
+	}()
+	return writer, nil
+}
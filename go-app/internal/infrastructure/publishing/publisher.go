@@ -26,6 +26,13 @@ type AlertPublisher interface {
 	Name() string
 }
 
+// BatchPublisher is implemented by publishers able to send several alerts of
+// a target in one request (see batched targets in PublishingQueue)
+type BatchPublisher interface {
+	// PublishBatch publishes alerts to the target as one notification
+	PublishBatch(ctx context.Context, alerts []*core.EnrichedAlert, target *core.PublishingTarget) error
+}
+
 // HTTPPublisher is a base HTTP client for all publishers
 type HTTPPublisher struct {
 	formatter  AlertFormatter
@@ -42,7 +49,8 @@ func NewHTTPPublisher(formatter AlertFormatter, logger *slog.Logger) *HTTPPublis
 	return &HTTPPublisher{
 		formatter: formatter,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: newOutboundTransport(nil),
 		},
 		logger: logger,
 	}
@@ -51,11 +59,26 @@ func NewHTTPPublisher(formatter AlertFormatter, logger *slog.Logger) *HTTPPublis
 // publish is a helper method to perform HTTP POST with formatted payload
 func (p *HTTPPublisher) publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
 	// Format alert for target format
-	payload, err := p.formatter.FormatAlert(ctx, enrichedAlert, target.Format)
+	formatCtx := withTargetPayloadTemplate(withTargetSeverityStyles(ctx, target), target)
+	payload, err := p.formatter.FormatAlert(formatCtx, enrichedAlert, target.Format)
 	if err != nil {
 		return fmt.Errorf("failed to format alert: %w", err)
 	}
+	return p.post(ctx, payload, target)
+}
+
+// publishBatch performs one HTTP POST for several alerts
+func (p *HTTPPublisher) publishBatch(ctx context.Context, alerts []*core.EnrichedAlert, target *core.PublishingTarget) error {
+	formatCtx := withTargetPayloadTemplate(withTargetSeverityStyles(ctx, target), target)
+	payload, err := p.formatter.FormatBatch(formatCtx, alerts, target.Format)
+	if err != nil {
+		return fmt.Errorf("failed to format alert batch: %w", err)
+	}
+	return p.post(ctx, payload, target)
+}
 
+// post sends payload to target
+func (p *HTTPPublisher) post(ctx context.Context, payload map[string]any, target *core.PublishingTarget) error {
 	// Marshal to JSON
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -70,7 +93,7 @@ func (p *HTTPPublisher) publish(ctx context.Context, enrichedAlert *core.Enriche
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	for key, value := range target.Headers {
+	for key, value := range targetHTTPHeaders(target.Headers) {
 		req.Header.Set(key, value)
 	}
 
@@ -86,7 +109,7 @@ func (p *HTTPPublisher) publish(ctx context.Context, enrichedAlert *core.Enriche
 
 	// Check status code
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return NewPublishingError(resp.StatusCode, fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), target.Type)
 	}
 
 	p.logger.Debug("Alert published successfully",
@@ -180,26 +203,67 @@ func (p *WebhookPublisher) Publish(ctx context.Context, enrichedAlert *core.Enri
 	return p.publish(ctx, enrichedAlert, target)
 }
 
+// PublishBatch publishes several alerts to generic webhook in one request
+func (p *WebhookPublisher) PublishBatch(ctx context.Context, alerts []*core.EnrichedAlert, target *core.PublishingTarget) error {
+	return p.publishBatch(ctx, alerts, target)
+}
+
 // Name returns publisher name
 func (p *WebhookPublisher) Name() string {
 	return "Webhook"
 }
 
+// TeamsPublisher publishes alerts to Microsoft Teams webhooks
+type TeamsPublisher struct {
+	*HTTPPublisher
+}
+
+// NewTeamsPublisher creates a new Teams publisher
+func NewTeamsPublisher(formatter AlertFormatter, logger *slog.Logger) AlertPublisher {
+	return &TeamsPublisher{
+		HTTPPublisher: NewHTTPPublisher(formatter, logger),
+	}
+}
+
+// Publish publishes alert to Teams
+func (p *TeamsPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	return p.publish(ctx, enrichedAlert, target)
+}
+
+// Name returns publisher name
+func (p *TeamsPublisher) Name() string {
+	return "Teams"
+}
+
 // PublisherFactory creates publishers based on target type
 type PublisherFactory struct {
 	formatter          AlertFormatter
 	logger             *slog.Logger
-	externalURL        string                           // AMP public URL for callback links
-	rootlyCache        IncidentIDCache                  // Shared Rootly incident cache
-	rootlyClientMap    map[string]RootlyIncidentsClient // Cache of Rootly clients by API key
-	pagerDutyCache     EventKeyCache                    // Shared PagerDuty event key cache
-	pagerDutyClientMap map[string]PagerDutyEventsClient // Cache of PagerDuty clients by routing key
-	slackCache         MessageIDCache                   // Shared Slack message cache (for threading)
-	slackClientMap     map[string]SlackWebhookClient    // Cache of Slack clients by webhook URL
-	slackCleanupWorker func()                           // Slack cache cleanup worker cancel function
-	emailClientMu      sync.RWMutex                     // Guards emailClientMap for concurrent access
-	emailClientMap     map[string]SMTPClient            // Cache of SMTP clients by smtp_host:port
-	metrics            *v2.PublishingMetrics            // Unified publishing metrics (v2)
+	externalURL        string                // AMP public URL for callback links
+	rootlyCache        IncidentIDCache       // Shared Rootly incident cache
+	rootlyClients      *rootlyClients        // Cache of Rootly clients by API URL and key
+	pagerDutyCache     EventKeyCache         // Shared PagerDuty event key cache
+	pagerDutyClients   *pagerDutyClients     // Cache of PagerDuty clients by URL and routing key
+	slackCache         MessageIDCache        // Shared Slack message cache (for threading)
+	slackClients       *slackClients         // Cache of Slack clients by URL and bot token
+	slackCleanupWorker func()                // Slack cache cleanup worker cancel function
+	emailClientMu      sync.RWMutex          // Guards emailClientMap for concurrent access
+	emailClientMap     map[string]SMTPClient // Cache of SMTP clients by SMTP server and credentials
+	emailBatcher       *emailBatcher         // Pending email batches (batch_wait), shared by email publishers
+	teamsClients       *teamsClients         // Cache of Teams clients by webhook URL
+	opsgenieClients    *opsgenieClients      // Cache of Opsgenie clients by API URL and key
+	kafkaClients       *kafkaClients         // Cache of Kafka REST Proxy clients by URL and credentials
+	jiraClients        *jiraClients          // Cache of JIRA clients by URL and credentials
+	jiraIssues         *jiraIssueIndex       // Open JIRA issues by target group, shared by JIRA publishers
+	googleChatClient   ChatWebhookClient     // Google Chat webhook client, shared by all Google Chat targets
+	mattermostClient   ChatWebhookClient     // Mattermost webhook client, shared by all Mattermost targets
+	awsClients         *awsClients           // Cache of SNS/SQS clients by destination and credentials
+	webhookClient      *WebhookHTTPClient    // Webhook client, shared by all webhook and Alertmanager targets
+	webhookValidator   *WebhookValidator     // Validator of webhook and Alertmanager targets
+	plugins            *PluginSupervisor     // External publisher plugins (optional)
+	execCommands       *ExecCommands         // Local commands of exec targets (optional)
+	metrics            *v2.PublishingMetrics // Unified publishing metrics (v2)
+	snoozes            core.SnoozeChecker    // Personal snoozes honoured by chat publishers (optional)
 }
 
 // NewPublisherFactory creates a new publisher factory with unified v2 metrics.
@@ -209,18 +273,34 @@ func NewPublisherFactory(formatter AlertFormatter, logger *slog.Logger, metrics
 	slackCache := NewMessageCache()
 	slackCleanupWorker := StartCleanupWorker(slackCache, 5*time.Minute, 24*time.Hour)
 
+	// Payload truncations are recorded with the publishing metrics
+	if defaultFormatter, ok := formatter.(*DefaultAlertFormatter); ok && metrics != nil {
+		defaultFormatter.SetMetrics(metrics)
+	}
+
 	return &PublisherFactory{
 		formatter:          formatter,
 		logger:             logger,
 		externalURL:        externalURL,
 		rootlyCache:        NewIncidentIDCache(24 * time.Hour), // 24h TTL for Rootly incident tracking
-		rootlyClientMap:    make(map[string]RootlyIncidentsClient),
+		rootlyClients:      newRootlyClients(logger),
 		pagerDutyCache:     NewEventKeyCache(24 * time.Hour), // 24h TTL for PagerDuty event tracking
-		pagerDutyClientMap: make(map[string]PagerDutyEventsClient),
+		pagerDutyClients:   newPagerDutyClients(logger),
 		slackCache:         slackCache, // Slack message cache for threading
-		slackClientMap:     make(map[string]SlackWebhookClient),
+		slackClients:       newSlackClients(logger),
 		slackCleanupWorker: slackCleanupWorker,
 		emailClientMap:     make(map[string]SMTPClient),
+		emailBatcher:       newEmailBatcher(),
+		teamsClients:       newTeamsClients(logger),
+		opsgenieClients:    newOpsgenieClients(logger),
+		kafkaClients:       newKafkaClients(logger),
+		jiraClients:        newJiraClients(logger),
+		jiraIssues:         newJiraIssueIndex(),
+		googleChatClient:   NewHTTPChatWebhookClient(ProviderGoogleChat, 10*time.Second, logger),
+		mattermostClient:   NewHTTPChatWebhookClient(ProviderMattermost, 10*time.Second, logger),
+		awsClients:         newAWSClients(logger),
+		webhookClient:      NewWebhookHTTPClient(queueWebhookRetryConfig, logger),
+		webhookValidator:   NewWebhookValidatorWithConfig(targetValidationConfig, logger),
 		metrics:            metrics, // Unified v2 metrics
 	}
 }
@@ -229,23 +309,35 @@ func NewPublisherFactory(formatter AlertFormatter, logger *slog.Logger, metrics
 func (f *PublisherFactory) CreatePublisher(targetType string) (AlertPublisher, error) {
 	switch TargetType(targetType) {
 	case TargetTypeRootly:
-		return NewRootlyPublisher(f.formatter, f.logger), nil
+		return f.createEnhancedRootlyPublisher(), nil
 	case TargetTypePagerDuty:
-		return NewPagerDutyPublisher(f.formatter, f.logger), nil
+		return f.createEnhancedPagerDutyPublisher(), nil
 	case TargetTypeSlack:
-		return NewSlackPublisher(f.formatter, f.logger), nil
+		return f.createEnhancedSlackPublisher(), nil
+	case TargetTypeTeams:
+		return f.createEnhancedTeamsPublisher(), nil
+	case TargetTypeOpsgenie:
+		return f.createEnhancedOpsgeniePublisher(), nil
+	case TargetTypeKafka:
+		return f.createEnhancedKafkaPublisher(), nil
+	case TargetTypeJira:
+		return f.createEnhancedJiraPublisher(), nil
+	case TargetTypeGoogleChat:
+		return f.createEnhancedGoogleChatPublisher(), nil
+	case TargetTypeMattermost:
+		return f.createEnhancedMattermostPublisher(), nil
+	case TargetTypeAWS:
+		return f.createEnhancedAWSPublisher(), nil
+	case TargetTypePlugin:
+		return f.createEnhancedPluginPublisher(), nil
+	case TargetTypeExec:
+		return f.createEnhancedExecPublisher(), nil
 	case TargetTypeWebhook, TargetTypeAlertmanager:
-		return NewWebhookPublisher(f.formatter, f.logger), nil
+		return f.newWebhookPublisher(), nil
 	case TargetTypeEmail:
-		return NewEnhancedEmailPublisher(
-			NewSMTPDialer(SMTPConfig{Port: 587}, f.logger),
-			f.metrics,
-			f.formatter,
-			f.logger,
-			f.externalURL,
-		), nil
+		return f.newEmailPublisher(), nil
 	default:
-		return NewWebhookPublisher(f.formatter, f.logger), nil // Default to webhook
+		return f.newWebhookPublisher(), nil // Default to webhook
 	}
 }
 
@@ -253,11 +345,29 @@ func (f *PublisherFactory) CreatePublisher(targetType string) (AlertPublisher, e
 func (f *PublisherFactory) CreatePublisherForTarget(target *core.PublishingTarget) (AlertPublisher, error) {
 	switch TargetType(target.Type) {
 	case TargetTypeRootly:
-		return f.createEnhancedRootlyPublisher(target)
+		return f.createEnhancedRootlyPublisher(), nil
 	case TargetTypePagerDuty:
-		return f.createEnhancedPagerDutyPublisher(target)
+		return f.createEnhancedPagerDutyPublisher(), nil
 	case TargetTypeSlack:
-		return f.createEnhancedSlackPublisher(target)
+		return f.createEnhancedSlackPublisher(), nil
+	case TargetTypeTeams:
+		return f.createEnhancedTeamsPublisher(), nil
+	case TargetTypeOpsgenie:
+		return f.createEnhancedOpsgeniePublisher(), nil
+	case TargetTypeKafka:
+		return f.createEnhancedKafkaPublisher(), nil
+	case TargetTypeJira:
+		return f.createEnhancedJiraPublisher(), nil
+	case TargetTypeGoogleChat:
+		return f.createEnhancedGoogleChatPublisher(), nil
+	case TargetTypeMattermost:
+		return f.createEnhancedMattermostPublisher(), nil
+	case TargetTypeAWS:
+		return f.createEnhancedAWSPublisher(), nil
+	case TargetTypePlugin:
+		return f.createEnhancedPluginPublisher(), nil
+	case TargetTypeExec:
+		return f.createEnhancedExecPublisher(), nil
 	case TargetTypeWebhook, TargetTypeAlertmanager:
 		return f.createEnhancedWebhookPublisher(target)
 	case TargetTypeEmail:
@@ -267,162 +377,137 @@ func (f *PublisherFactory) CreatePublisherForTarget(target *core.PublishingTarge
 	}
 }
 
-// createEnhancedRootlyPublisher creates an EnhancedRootlyPublisher with full Rootly API integration
-func (f *PublisherFactory) createEnhancedRootlyPublisher(target *core.PublishingTarget) (AlertPublisher, error) {
-	// Extract API key from target headers
-	apiKey := ""
-	if auth, ok := target.Headers["Authorization"]; ok {
-		// Remove "Bearer " prefix if present
-		apiKey = strings.TrimPrefix(auth, "Bearer ")
-	}
-
-	if apiKey == "" {
-		f.logger.Warn("Rootly target missing API key, falling back to HTTP publisher", "target", target.Name)
-		return NewRootlyPublisher(f.formatter, f.logger), nil
-	}
+// createEnhancedRootlyPublisher creates an EnhancedRootlyPublisher with
+// full Rootly API integration. It resolves the client of the target's API
+// key at publish time, so the publishing queue updates and resolves
+// incidents too; targets without an API key get the HTTP publisher.
+func (f *PublisherFactory) createEnhancedRootlyPublisher() AlertPublisher {
+	return newEnhancedRootlyPublisher(f.rootlyClients, f.rootlyCache, f.metrics, f.formatter, f.logger)
+}
 
-	// Get or create Rootly client for this API key
-	client, ok := f.rootlyClientMap[apiKey]
-	if !ok {
-		// Create new client with configuration
-		config := ClientConfig{
-			BaseURL: target.URL,
-			APIKey:  apiKey,
-			Timeout: 10 * time.Second,
-		}
-		client = NewRootlyIncidentsClient(config, f.logger)
-		f.rootlyClientMap[apiKey] = client
-	}
+// createEnhancedPagerDutyPublisher creates an EnhancedPagerDutyPublisher
+// with full PagerDuty Events API v2 integration (trigger, resolve, change
+// events). It resolves the client of the target's routing key at publish
+// time, so the publishing queue sends resolve events too.
+func (f *PublisherFactory) createEnhancedPagerDutyPublisher() AlertPublisher {
+	return newEnhancedPagerDutyPublisher(f.pagerDutyClients, f.pagerDutyCache, f.metrics, f.formatter, f.logger)
+}
 
-	// Create EnhancedRootlyPublisher with shared cache and unified metrics
-	return NewEnhancedRootlyPublisher(
-		client,
-		f.rootlyCache,
-		f.metrics,
-		f.formatter,
-		f.logger,
-	), nil
-}
-
-// createEnhancedPagerDutyPublisher creates an EnhancedPagerDutyPublisher with full PagerDuty Events API v2 integration
-func (f *PublisherFactory) createEnhancedPagerDutyPublisher(target *core.PublishingTarget) (AlertPublisher, error) {
-	// Extract routing key from target headers
-	routingKey := ""
-	if rk, ok := target.Headers["routing_key"]; ok {
-		routingKey = rk
-	}
+// createEnhancedSlackPublisher creates an EnhancedSlackPublisher with full
+// Slack integration. Like the Opsgenie publisher, it resolves the webhook
+// or Web API client of the target at publish time, so the publishing queue
+// threads messages too; the message cache is shared by all Slack targets.
+func (f *PublisherFactory) createEnhancedSlackPublisher() AlertPublisher {
+	publisher := newEnhancedSlackPublisher(f.slackClients, f.slackCache, f.metrics, f.formatter, f.logger)
+	publisher.snoozes = f.snoozes
+	return publisher
+}
 
-	// Check for Authorization header (Bearer token format)
-	if auth, ok := target.Headers["Authorization"]; ok {
-		// Remove "Bearer " prefix if present
-		const bearerPrefix = "Bearer "
-		if len(auth) > len(bearerPrefix) && auth[:len(bearerPrefix)] == bearerPrefix {
-			routingKey = auth[len(bearerPrefix):]
-		} else {
-			routingKey = auth
-		}
-	}
+// createEnhancedTeamsPublisher creates an EnhancedTeamsPublisher posting
+// Adaptive Cards. Like the Opsgenie publisher, it resolves the client of the
+// target's webhook URL at publish time.
+func (f *PublisherFactory) createEnhancedTeamsPublisher() AlertPublisher {
+	return newEnhancedTeamsPublisher(f.teamsClients, f.metrics, f.formatter, f.logger)
+}
 
-	if routingKey == "" {
-		f.logger.Warn("PagerDuty target missing routing_key, falling back to HTTP publisher", "target", target.Name)
-		return NewPagerDutyPublisher(f.formatter, f.logger), nil
-	}
+// createEnhancedOpsgeniePublisher creates an EnhancedOpsgeniePublisher.
+// The API key is read from the target at publish time, so the same
+// publisher serves the publishing queue, which creates publishers by type.
+func (f *PublisherFactory) createEnhancedOpsgeniePublisher() AlertPublisher {
+	return newEnhancedOpsgeniePublisher(f.opsgenieClients, f.metrics, f.formatter, f.logger)
+}
 
-	// Get or create PagerDuty client for this routing key
-	client, ok := f.pagerDutyClientMap[routingKey]
-	if !ok {
-		// Create new client with configuration
-		config := PagerDutyClientConfig{
-			BaseURL:    target.URL,
-			Timeout:    10 * time.Second,
-			MaxRetries: 3,
-			RateLimit:  120.0, // 120 req/min
-		}
-		if config.BaseURL == "" {
-			config.BaseURL = "https://events.pagerduty.com"
-		}
-		client = NewPagerDutyEventsClient(config, f.logger)
-		f.pagerDutyClientMap[routingKey] = client
-	}
+// createEnhancedKafkaPublisher creates an EnhancedKafkaPublisher. Like the
+// Opsgenie publisher, it reads the topic and delivery options from the
+// target at publish time.
+func (f *PublisherFactory) createEnhancedKafkaPublisher() AlertPublisher {
+	return newEnhancedKafkaPublisher(f.kafkaClients, f.metrics, f.formatter, f.logger)
+}
 
-	// Create EnhancedPagerDutyPublisher with shared cache and unified metrics
-	return NewEnhancedPagerDutyPublisher(
-		client,
-		f.pagerDutyCache,
-		f.metrics,
-		f.formatter,
-		f.logger,
-	), nil
-}
-
-// createEnhancedSlackPublisher creates an EnhancedSlackPublisher with full Slack Webhook API integration
-func (f *PublisherFactory) createEnhancedSlackPublisher(target *core.PublishingTarget) (AlertPublisher, error) {
-	// Use target.URL as webhook URL
-	webhookURL := target.URL
-	if webhookURL == "" {
-		f.logger.Warn("Slack target missing webhook URL, falling back to HTTP publisher", "target", target.Name)
-		return NewSlackPublisher(f.formatter, f.logger), nil
-	}
+// createEnhancedJiraPublisher creates an EnhancedJiraPublisher. Like the
+// Opsgenie publisher, it reads the project and credentials from the target
+// at publish time; the open issues are shared by all JIRA publishers.
+func (f *PublisherFactory) createEnhancedJiraPublisher() AlertPublisher {
+	return newEnhancedJiraPublisher(f.jiraClients, f.jiraIssues, f.metrics, f.formatter, f.logger)
+}
 
-	// Get or create Slack client for this webhook URL
-	client, ok := f.slackClientMap[webhookURL]
-	if !ok {
-		// Create new Slack webhook client
-		client = NewHTTPSlackWebhookClient(webhookURL, f.logger)
-		f.slackClientMap[webhookURL] = client
-	}
+// JiraIssueKey returns the key of the open JIRA issue of a firing alert,
+// for cross-linking in other publishers.
+func (f *PublisherFactory) JiraIssueKey(fingerprint string) (string, bool) {
+	return f.jiraIssues.issueKey(fingerprint)
+}
 
-	// Create EnhancedSlackPublisher with shared cache and unified metrics
-	return NewEnhancedSlackPublisher(
-		client,
-		f.slackCache,
-		f.metrics,
-		f.formatter,
-		f.logger,
-	), nil
+// createEnhancedGoogleChatPublisher creates an EnhancedGoogleChatPublisher.
+// The webhook URL carries the credentials of a target, so all Google Chat
+// targets share one client.
+func (f *PublisherFactory) createEnhancedGoogleChatPublisher() AlertPublisher {
+	return NewEnhancedGoogleChatPublisher(f.googleChatClient, f.metrics, f.formatter, f.logger)
 }
 
-// createEnhancedWebhookPublisher creates an EnhancedWebhookPublisher with full validation and metrics
-func (f *PublisherFactory) createEnhancedWebhookPublisher(target *core.PublishingTarget) (AlertPublisher, error) {
-	f.logger.Info("Creating enhanced webhook publisher",
-		"target", target.Name,
-		"url", target.URL)
+// createEnhancedMattermostPublisher creates an EnhancedMattermostPublisher.
+// Like Google Chat, all Mattermost targets share one client.
+func (f *PublisherFactory) createEnhancedMattermostPublisher() AlertPublisher {
+	return NewEnhancedMattermostPublisher(f.mattermostClient, f.metrics, f.formatter, f.logger)
+}
 
-	// Create HTTP client with default retry config
-	client := NewWebhookHTTPClient(DefaultWebhookRetryConfig, f.logger)
+// createEnhancedAWSPublisher creates an EnhancedAWSPublisher. Like the
+// Kafka publisher, it reads the topic or queue and credentials from the
+// target at publish time.
+func (f *PublisherFactory) createEnhancedAWSPublisher() AlertPublisher {
+	return newEnhancedAWSPublisher(f.awsClients, f.metrics, f.formatter, f.logger)
+}
 
-	// Create validator with default config
-	validator := NewWebhookValidator(f.logger)
+// createEnhancedPluginPublisher creates an EnhancedPluginPublisher for the
+// plugins of the factory.
+func (f *PublisherFactory) createEnhancedPluginPublisher() AlertPublisher {
+	return NewEnhancedPluginPublisher(f.plugins, f.metrics, f.formatter, f.logger)
+}
 
-	// Create enhanced webhook publisher with unified metrics
-	publisher := NewEnhancedWebhookPublisher(
-		client,
-		validator,
-		f.formatter,
-		f.metrics, // Unified v2 metrics instance
-		f.logger,
-	)
+// createEnhancedExecPublisher creates an EnhancedExecPublisher for the
+// commands of the factory.
+func (f *PublisherFactory) createEnhancedExecPublisher() AlertPublisher {
+	return NewEnhancedExecPublisher(f.execCommands, f.metrics, f.formatter, f.logger)
+}
 
-	f.logger.Info("Enhanced webhook publisher created successfully",
-		"target", target.Name,
-		"features", "4 auth strategies, 6 validation rules, exponential backoff retry")
+// createEnhancedWebhookPublisher creates an EnhancedWebhookPublisher with
+// validation, authentication, payload signing and metrics. The target is read
+// at publish time, so the publishing queue, which creates publishers by type,
+// gets the same publisher.
+func (f *PublisherFactory) createEnhancedWebhookPublisher(target *core.PublishingTarget) (AlertPublisher, error) {
+	f.logger.Debug("Creating enhanced webhook publisher", "target", target.Name)
+	return f.newWebhookPublisher(), nil
+}
 
-	return publisher, nil
+func (f *PublisherFactory) newWebhookPublisher() *EnhancedWebhookPublisher {
+	return NewEnhancedWebhookPublisher(f.webhookClient, f.webhookValidator, f.formatter, f.metrics, f.logger)
 }
 
-// createEnhancedEmailPublisher создаёт EnhancedEmailPublisher с конфигурацией из target.Headers.
-// SMTP-клиент кешируется по ключу "host:port" для переиспользования.
+// createEnhancedEmailPublisher создаёт EnhancedEmailPublisher для target.
+// SMTP конфиг читается из target.URL и target.Headers при отправке.
 func (f *PublisherFactory) createEnhancedEmailPublisher(target *core.PublishingTarget) (AlertPublisher, error) {
-	// Извлечь SMTP конфиг из target.Headers
-	smtpCfg := extractSMTPConfig(target)
+	if extractSMTPConfig(target).Host == "" {
+		f.logger.Warn("Email target missing SMTP server (smtp:// URL or smtp_host), publisher will fail on send",
+			slog.String("target", target.Name))
+	}
+	return f.newEmailPublisher(), nil
+}
+
+func (f *PublisherFactory) newEmailPublisher() *EnhancedEmailPublisher {
+	return newEnhancedEmailPublisher(f.emailClient, f.emailBatcher, f.metrics, f.formatter, f.logger, f.externalURL)
+}
 
+// emailClient возвращает SMTP клиент для target.
+// Одинаковый SMTP сервер с одинаковыми credentials переиспользуется.
+func (f *PublisherFactory) emailClient(target *core.PublishingTarget) (SMTPClient, error) {
+	smtpCfg := extractSMTPConfig(target)
 	if smtpCfg.Host == "" {
-		f.logger.Warn("Email target missing smtp_host, publisher will fail on send",
-			slog.String("target", target.Name))
+		return nil, fmt.Errorf("email: no SMTP server configured for target %q (set smtp:// URL or smtp_host header)", target.Name)
 	}
 
-	// Ключ кеша — smtp_host:port (одинаковый SMTP сервер переиспользуется)
-	cacheKey := strings.Join([]string{smtpCfg.Host, strconv.Itoa(smtpCfg.Port)}, ":")
+	cacheKey := strings.Join([]string{
+		smtpCfg.Host, strconv.Itoa(smtpCfg.Port), smtpCfg.Username, smtpCfg.Password, smtpCfg.Identity,
+		strconv.FormatBool(smtpCfg.RequireTLS), smtpCfg.TLSServerName, strconv.FormatBool(smtpCfg.InsecureSkipVerify), smtpCfg.From,
+	}, "\x00")
 
 	f.emailClientMu.RLock()
 	client, ok := f.emailClientMap[cacheKey]
@@ -436,18 +521,46 @@ func (f *PublisherFactory) createEnhancedEmailPublisher(target *core.PublishingT
 		}
 		f.emailClientMu.Unlock()
 	}
+	return client, nil
+}
 
-	return NewEnhancedEmailPublisher(
-		client,
-		f.metrics,
-		f.formatter,
-		f.logger,
-		f.externalURL,
-	), nil
+// RetainTargets drops the cached Rootly, Slack, PagerDuty, Teams, Opsgenie,
+// Kafka, JIRA and AWS clients that none of targets uses anymore, e.g. after
+// a target was removed or its credentials rotated.
+func (f *PublisherFactory) RetainTargets(targets []*core.PublishingTarget) {
+	f.rootlyClients.retain(targets)
+	f.slackClients.retain(targets)
+	f.pagerDutyClients.retain(targets)
+	f.opsgenieClients.retain(targets)
+	f.kafkaClients.retain(targets)
+	f.jiraClients.retain(targets)
+	f.awsClients.retain(targets)
+	f.teamsClients.retain(targets)
+}
+
+// SetSnoozeChecker sets the personal snoozes consulted by chat publishers
+// before mentioning a user. Affects publishers created afterwards.
+func (f *PublisherFactory) SetSnoozeChecker(snoozes core.SnoozeChecker) {
+	f.snoozes = snoozes
+}
+
+// SetPluginSupervisor sets the external publisher plugins of plugin targets.
+// Affects publishers created afterwards.
+func (f *PublisherFactory) SetPluginSupervisor(plugins *PluginSupervisor) {
+	f.plugins = plugins
+}
+
+// SetExecCommands sets the local commands of exec targets. Affects publishers
+// created afterwards.
+func (f *PublisherFactory) SetExecCommands(commands *ExecCommands) {
+	f.execCommands = commands
 }
 
 // Shutdown stops all background workers
 func (f *PublisherFactory) Shutdown() {
+	// Send pending email batches
+	f.emailBatcher.close()
+
 	// Stop Slack cache cleanup worker
 	if f.slackCleanupWorker != nil {
 		f.slackCleanupWorker()
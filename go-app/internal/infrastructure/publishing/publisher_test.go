@@ -186,9 +186,9 @@ func TestPublisherFactory_CreatePublisher(t *testing.T) {
 		{"rootly", "Rootly"},
 		{"pagerduty", "PagerDuty"},
 		{"slack", "Slack"},
-		{"webhook", "Webhook"},
-		{"alertmanager", "Webhook"}, // Alertmanager uses webhook publisher
-		{"unknown", "Webhook"},      // Unknown defaults to webhook
+		{"webhook", "EnhancedWebhook"},
+		{"alertmanager", "EnhancedWebhook"}, // Alertmanager uses webhook publisher
+		{"unknown", "EnhancedWebhook"},      // Unknown defaults to webhook
 	}
 
 	for _, tt := range tests {
@@ -2,6 +2,7 @@ package publishing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -74,6 +75,7 @@ const (
 	QueueErrorTypeUnknown   QueueErrorType = iota // Default, retry with caution
 	QueueErrorTypeTransient                       // Network timeout, rate limit, 502/503/504 → RETRY
 	QueueErrorTypePermanent                       // 400 bad request, 401 unauthorized, 404 → NO RETRY
+	QueueErrorTypePoison                          // Publisher panicked → quarantine in DLQ, NO RETRY
 )
 
 func (e QueueErrorType) String() string {
@@ -82,6 +84,8 @@ func (e QueueErrorType) String() string {
 		return "transient"
 	case QueueErrorTypePermanent:
 		return "permanent"
+	case QueueErrorTypePoison:
+		return "poison"
 	default:
 		return "unknown"
 	}
@@ -92,9 +96,31 @@ type PublishingJob struct {
 	// Core fields
 	EnrichedAlert *core.EnrichedAlert
 	Target        *core.PublishingTarget
-	RetryCount    int
 	SubmittedAt   time.Time
 
+	// RetryCount is the number of attempts made before the job was requeued
+	// for a provider cooldown; they count against the retry limit.
+	RetryCount int
+	// requeueAt is set by processJob when the job is to be requeued once
+	// the cooldown of its provider ends, instead of acknowledged.
+	requeueAt time.Time
+
+	// Batch holds the alerts of a batched target published together
+	// (EnrichedAlert is the first of them); nil for single-alert jobs.
+	Batch []*core.EnrichedAlert
+
+	// NotBefore is the due time of a job held by the schedule of its target
+	// (notify_after, repeat_interval); zero for queued jobs.
+	NotBefore time.Time
+	// Renotify marks scheduled jobs of alerts already published.
+	Renotify bool
+
+	// FallbackFor is the primary target of a job handed to a fallback
+	// target, and Fallbacks the rest of its fallback chain; empty for
+	// primary jobs.
+	FallbackFor string
+	Fallbacks   []string
+
 	// Extended fields for 150% quality
 	ID          string         // UUID v4
 	Priority    Priority       // HIGH/MEDIUM/LOW
@@ -102,7 +128,7 @@ type PublishingJob struct {
 	StartedAt   *time.Time     // When processing began
 	CompletedAt *time.Time     // When processing completed
 	LastError   error          // Most recent error
-	ErrorType   QueueErrorType // transient/permanent/unknown
+	ErrorType   QueueErrorType // transient/permanent/poison/unknown
 }
 
 // PublishingQueue manages async publishing with worker pool and retry logic
@@ -121,14 +147,40 @@ type PublishingQueue struct {
 	workerCount      int
 	logger           *slog.Logger
 	metrics          *v2.PublishingMetrics // v2 metrics for queue operations
+	heartbeat        func()                // worker liveness hook (optional)
 	wg               sync.WaitGroup
 	ctx              context.Context
 	cancel           context.CancelFunc
 	circuitBreakers  map[string]*CircuitBreaker
+	breakerDefaults  CircuitBreakerConfig
+	breakersByType   map[string]CircuitBreakerConfig
+	targets          TargetDiscoveryManager
+	removedTargets   map[string]struct{} // targets whose queued jobs are drained
+	targetJobs       map[string]int      // outstanding jobs by target name (queued, in flight, requeued)
+	cooldowns        *providerCooldowns  // rate-limited providers, shared by workers
+	limiters         *targetLimiters     // per-target rate and concurrency limits
+	hold             queueHold           // maintenance pause
+	shedPolicy       ShedPolicy          // severity-aware shedding near capacity
+	shed             shedCounters
+	deliverySLO      DeliverySLO        // ingest-to-ack deadlines by severity
+	deliveries       DeliveryRecorder   // per-alert attempt history (optional)
+	outcomes         JobOutcomeRecorder // final outcome of every job (optional)
+	batcher          *jobBatcher        // open batches of batched targets
+	scheduler        *jobScheduler      // jobs held for notify_after/repeat_interval
+	dedup            *publishDedup      // last delivered content per target and alert
+	durable          *durableJobs       // stored jobs (nil: in memory only)
+	scaler           *workerScaler      // worker autoscaling (nil: fixed pool)
+	workers          atomic.Int32       // worker pool size
+	busyWorkers      atomic.Int32
+	pendingJobs      atomic.Int64 // sent to the job channels, not yet processed
+	requeuedJobs     atomic.Int64 // waiting for a provider cooldown to end
+	draining         atomic.Bool
+	nextWorkerID     atomic.Int32
 	mu               sync.RWMutex
 	totalSubmitted   atomic.Int64
 	totalCompleted   atomic.Int64
 	totalFailed      atomic.Int64
+	totalPanics      atomic.Int64
 }
 
 // PublishingQueueConfig holds configuration for publishing queue
@@ -139,9 +191,57 @@ type PublishingQueueConfig struct {
 	LowPriorityQueueSize    int
 	MaxRetries              int
 	RetryInterval           time.Duration
-	CircuitTimeout          time.Duration
+	CircuitTimeout          time.Duration         // Deprecated: use CircuitBreaker.Timeout
 	Metrics                 *v2.PublishingMetrics // v2 metrics (optional, will create if nil)
 	Workers                 int                   // Deprecated: use WorkerCount
+
+	// Heartbeat is called by every worker on each loop iteration (optional).
+	// Used by the watchdog to detect dead or deadlocked workers.
+	Heartbeat func()
+
+	// Shedding drops low-severity and resolved jobs when the queue
+	// approaches capacity (optional, disabled by default).
+	Shedding ShedPolicy
+
+	// DeliverySLO sets the ingest-to-acknowledgement deadlines tracked for
+	// firing notifications (optional, untracked when zero).
+	DeliverySLO DeliverySLO
+
+	// Deliveries records every publish attempt per alert (optional).
+	Deliveries DeliveryRecorder
+
+	// Outcomes is told the final outcome of every job (optional).
+	Outcomes JobOutcomeRecorder
+
+	// Targets looks up the fallback targets of the targets with a fallback
+	// header (optional, no fallback without it).
+	Targets TargetDiscoveryManager
+
+	// Durable stores the queued jobs so they survive restarts and crashes
+	// (optional, in memory only by default).
+	Durable DurableQueueConfig
+
+	// Autoscaling scales the workers with the load (optional, WorkerCount
+	// workers by default). WorkerCount is then the initial pool size.
+	Autoscaling WorkerAutoscaling
+
+	// DedupWindow suppresses unchanged alerts submitted again within this
+	// window after they were delivered to a target (optional, disabled when
+	// zero). The dedup_window target header overrides it.
+	DedupWindow time.Duration
+
+	// TargetLimits bounds the publish rate and concurrency of the targets of
+	// a type, keyed by target type (optional). The rate_limit, rate_burst and
+	// max_concurrency target headers override them.
+	TargetLimits map[string]TargetLimit
+
+	// CircuitBreaker configures the circuit breakers of the targets; zero
+	// fields default to DefaultCircuitBreakerConfig. CircuitBreakers
+	// overrides its non-zero fields by target type, and the
+	// circuit_failure_threshold, circuit_success_threshold and circuit_timeout
+	// target headers override both.
+	CircuitBreaker  CircuitBreakerConfig
+	CircuitBreakers map[string]CircuitBreakerConfig
 }
 
 // DefaultPublishingQueueConfig returns default configuration
@@ -154,6 +254,7 @@ func DefaultPublishingQueueConfig() PublishingQueueConfig {
 		MaxRetries:              3,
 		RetryInterval:           2 * time.Second,
 		CircuitTimeout:          30 * time.Second,
+		DeliverySLO:             DefaultDeliverySLO,
 	}
 }
 
@@ -170,6 +271,41 @@ func NewPublishingQueue(factory *PublisherFactory, dlqRepository DLQRepository,
 		logger = slog.Default()
 	}
 
+	if err := config.Shedding.Validate(); err != nil {
+		logger.Warn("Invalid queue shed policy, shedding disabled", "error", err)
+		config.Shedding = ShedPolicy{}
+	}
+	if err := config.DeliverySLO.Validate(); err != nil {
+		logger.Warn("Invalid delivery SLO, SLO tracking disabled", "error", err)
+		config.DeliverySLO = DeliverySLO{}
+	}
+	if err := config.Autoscaling.Validate(); err != nil {
+		logger.Warn("Invalid worker autoscaling, fixed worker pool", "error", err)
+		config.Autoscaling = WorkerAutoscaling{}
+	}
+	for targetType, limit := range config.TargetLimits {
+		if err := limit.Validate(); err != nil {
+			logger.Warn("Invalid target limit, targets unlimited", "target_type", targetType, "error", err)
+			delete(config.TargetLimits, targetType)
+		}
+	}
+	if err := config.CircuitBreaker.Validate(); err != nil {
+		logger.Warn("Invalid circuit breaker, using defaults", "error", err)
+		config.CircuitBreaker = CircuitBreakerConfig{}
+	}
+	for targetType, breaker := range config.CircuitBreakers {
+		if err := breaker.Validate(); err != nil {
+			logger.Warn("Invalid target circuit breaker, using defaults", "target_type", targetType, "error", err)
+			delete(config.CircuitBreakers, targetType)
+		}
+	}
+	if config.CircuitBreaker.Timeout == 0 {
+		config.CircuitBreaker.Timeout = config.CircuitTimeout
+	}
+	if config.Autoscaling.Enabled() {
+		config.WorkerCount = config.Autoscaling.clamp(config.WorkerCount)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	queue := &PublishingQueue{
@@ -188,7 +324,25 @@ func NewPublishingQueue(factory *PublisherFactory, dlqRepository DLQRepository,
 		ctx:                ctx,
 		cancel:             cancel,
 		circuitBreakers:    make(map[string]*CircuitBreaker),
+		breakerDefaults:    config.CircuitBreaker,
+		breakersByType:     config.CircuitBreakers,
+		removedTargets:     make(map[string]struct{}),
+		targetJobs:         make(map[string]int),
+		cooldowns:          newProviderCooldowns(),
+		limiters:           newTargetLimiters(config.TargetLimits),
+		heartbeat:          config.Heartbeat,
+		shedPolicy:         config.Shedding,
+		deliverySLO:        config.DeliverySLO,
+		deliveries:         config.Deliveries,
+		outcomes:           config.Outcomes,
+		targets:            config.Targets,
+		durable:            newDurableJobs(config.Durable),
+		scaler:             newWorkerScaler(config.Autoscaling),
 	}
+	queue.batcher = newJobBatcher(queue.submitBatch)
+	queue.scheduler = newJobScheduler()
+	queue.dedup = newPublishDedup(config.DedupWindow)
+	queue.workers.Store(int32(config.WorkerCount))
 
 	// Initialize worker metrics
 	if metrics != nil {
@@ -196,6 +350,7 @@ func NewPublishingQueue(factory *PublisherFactory, dlqRepository DLQRepository,
 		metrics.UpdateQueueSize("high", 0, config.HighPriorityQueueSize)
 		metrics.UpdateQueueSize("medium", 0, config.MediumPriorityQueueSize)
 		metrics.UpdateQueueSize("low", 0, config.LowPriorityQueueSize)
+		config.DeliverySLO.exportThresholds(metrics)
 	}
 
 	return queue
@@ -203,11 +358,25 @@ func NewPublishingQueue(factory *PublisherFactory, dlqRepository DLQRepository,
 
 // Start starts the worker pool
 func (q *PublishingQueue) Start() {
-	q.logger.Info("Starting publishing queue", "workers", q.workerCount)
+	q.logger.Info("Starting publishing queue",
+		"workers", q.workerCount,
+		"autoscaling", q.scaler != nil,
+		"durable", q.durable != nil,
+	)
+
+	// Queue the jobs stored before a restart or by crashed replicas
+	if q.durable != nil {
+		q.recoverJobs(true)
+		q.durable.wg.Add(1)
+		go q.renewLeases()
+	}
 
-	for i := 0; i < q.workerCount; i++ {
-		q.wg.Add(1)
-		go q.worker(i)
+	for range q.workerCount {
+		q.spawnWorker()
+	}
+	if q.scaler != nil {
+		q.scaler.wg.Add(1)
+		go q.runAutoscaler()
 	}
 }
 
@@ -215,6 +384,25 @@ func (q *PublishingQueue) Start() {
 func (q *PublishingQueue) Stop(timeout time.Duration) error {
 	q.logger.Info("Stopping publishing queue", "timeout", timeout)
 
+	// Paused workers would never drain; drop the held jobs instead of
+	// publishing them during maintenance.
+	if q.IsPaused() {
+		q.logger.Warn("Publishing queue stopped while paused, dropping held jobs", "held_jobs", q.GetQueueSize())
+		q.cancel()
+	}
+
+	// No worker is added once the channels close
+	if q.scaler != nil {
+		close(q.scaler.stop)
+		q.scaler.wg.Wait()
+	}
+
+	// No scheduled job is released once the channels close
+	q.closeScheduler()
+
+	// Enqueue the open batches before the channels close
+	q.batcher.close()
+
 	// Close all priority job channels to signal workers
 	close(q.highPriorityJobs)
 	close(q.mediumPriorityJobs)
@@ -227,18 +415,31 @@ func (q *PublishingQueue) Stop(timeout time.Duration) error {
 		close(done)
 	}()
 
+	var err error
 	select {
 	case <-done:
 		q.logger.Info("Publishing queue stopped gracefully")
-		return nil
 	case <-time.After(timeout):
+		status := q.DrainStatus()
 		q.cancel() // Force cancel remaining jobs
-		return fmt.Errorf("publishing queue stop timeout after %v", timeout)
+		err = fmt.Errorf("publishing queue stop timeout after %v (%d queued, %d in flight)", timeout, status.Queued, status.InFlight)
 	}
+
+	// Unprocessed stored jobs are recovered on restart
+	if q.durable != nil {
+		close(q.durable.stop)
+		q.durable.wg.Wait()
+	}
+	return err
 }
 
 // Submit submits a job to the publishing queue
 func (q *PublishingQueue) Submit(enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	// New jobs are rejected while the queue drains before a shutdown
+	if q.draining.Load() {
+		return ErrQueueDraining
+	}
+
 	// Generate job ID
 	jobID := uuid.NewString()
 
@@ -256,20 +457,68 @@ func (q *PublishingQueue) Submit(enrichedAlert *core.EnrichedAlert, target *core
 		State:         JobStateQueued,
 	}
 
-	// Select appropriate queue
-	var targetQueue chan *PublishingJob
+	// Unchanged alerts are not published again within the dedup window
+	if q.isDuplicateJob(job) {
+		return nil
+	}
+
+	// Scheduled targets get firing alerts published later, if still firing
+	if q.scheduleJob(job) {
+		return nil
+	}
+	return q.admit(job)
+}
+
+// admit sheds, batches or queues job
+func (q *PublishingQueue) admit(job *PublishingJob) error {
+	// Shed low-value jobs before the queue fills up
+	if q.shouldShed(job, q.jobsFor(job.Priority)) {
+		q.recordDelivery(job, 1, core.DeliveryStatusShed, time.Now(), 0, nil, nil)
+		return fmt.Errorf("%w (priority=%s)", ErrJobShed, job.Priority)
+	}
+
+	// Batched targets get their alerts published together on flush
+	if q.batchJob(job) {
+		return nil
+	}
+
+	return q.enqueue(job)
+}
+
+// jobsFor returns the job channel of priority
+func (q *PublishingQueue) jobsFor(priority Priority) chan *PublishingJob {
 	switch priority {
 	case PriorityHigh:
-		targetQueue = q.highPriorityJobs
+		return q.highPriorityJobs
 	case PriorityMedium:
-		targetQueue = q.mediumPriorityJobs
+		return q.mediumPriorityJobs
 	case PriorityLow:
-		targetQueue = q.lowPriorityJobs
+		return q.lowPriorityJobs
 	default:
-		targetQueue = q.mediumPriorityJobs
+		return q.mediumPriorityJobs
 	}
+}
+
+// enqueue stores job (durable queue) and sends it to the channel of its
+// priority without blocking
+func (q *PublishingQueue) enqueue(job *PublishingJob) error {
+	q.persistJob(job)
+	if err := q.send(job); err != nil {
+		q.ackJob(job)
+		return err
+	}
+	return nil
+}
 
-	// Submit to queue
+// send sends job to the channel of its priority without blocking
+func (q *PublishingQueue) send(job *PublishingJob) error {
+	priority := job.Priority
+	targetQueue := q.jobsFor(priority)
+
+	// Submit to queue (counted before the send so that a worker never
+	// processes an uncounted job)
+	q.pendingJobs.Add(1)
+	q.trackTargetJob(job.Target.Name, 1)
 	select {
 	case targetQueue <- job:
 		q.totalSubmitted.Add(1)
@@ -288,19 +537,24 @@ func (q *PublishingQueue) Submit(enrichedAlert *core.EnrichedAlert, target *core
 		// Level guard: avoid expensive string formatting in production
 		if q.logger.Enabled(q.ctx, slog.LevelDebug) {
 			q.logger.Debug("Job submitted",
-				"job_id", jobID,
+				"job_id", job.ID,
 				"priority", priority,
-				"target", target.Name,
-				"fingerprint", enrichedAlert.Alert.Fingerprint,
+				"target", job.Target.Name,
+				"fingerprint", job.EnrichedAlert.Alert.Fingerprint,
+				"alerts", len(job.alerts()),
 			)
 		}
 		return nil
 	case <-q.ctx.Done():
+		q.pendingJobs.Add(-1)
+		q.trackTargetJob(job.Target.Name, -1)
 		if q.metrics != nil {
 			q.metrics.RecordQueueSubmission(priority.String(), false)
 		}
 		return fmt.Errorf("publishing queue is shutting down")
 	default:
+		q.pendingJobs.Add(-1)
+		q.trackTargetJob(job.Target.Name, -1)
 		if q.metrics != nil {
 			q.metrics.RecordQueueSubmission(priority.String(), false)
 		}
@@ -318,6 +572,24 @@ func (q *PublishingQueue) worker(id int) {
 	}
 
 	for {
+		if q.heartbeat != nil {
+			q.heartbeat()
+		}
+		if q.shouldRetire() {
+			return
+		}
+
+		// Hold queued jobs while paused for maintenance
+		if resumed := q.hold.wait(); resumed != nil {
+			select {
+			case <-resumed:
+			case <-q.ctx.Done():
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+			continue
+		}
+
 		var job *PublishingJob
 		var priority Priority
 
@@ -361,6 +633,10 @@ func (q *PublishingQueue) worker(id int) {
 		}
 
 		if job != nil {
+			if q.scaler != nil {
+				q.scaler.observeWait(time.Since(job.SubmittedAt))
+			}
+
 			// TN-060: Check mode before processing (metrics-only mode fallback)
 			if q.modeManager != nil && q.modeManager.IsMetricsOnly() {
 				// Level guard: avoid expensive logging in production
@@ -372,6 +648,9 @@ func (q *PublishingQueue) worker(id int) {
 					)
 				}
 				// Skip processing, continue to next job
+				q.ackJob(job)
+				q.pendingJobs.Add(-1)
+				q.trackTargetJob(job.Target.Name, -1)
 				continue
 			}
 
@@ -380,8 +659,18 @@ func (q *PublishingQueue) worker(id int) {
 				q.metrics.RecordWorkerActive()
 			}
 
-			// Process job
-			q.processJob(job)
+			// Process job (panics are recovered and the job quarantined)
+			q.busyWorkers.Add(1)
+			q.safeProcessJob(job, id)
+			q.busyWorkers.Add(-1)
+			if until := job.requeueAt; !until.IsZero() {
+				job.requeueAt = time.Time{}
+				q.requeueAfter(job, until)
+			} else {
+				q.ackJob(job)
+			}
+			q.pendingJobs.Add(-1)
+			q.trackTargetJob(job.Target.Name, -1)
 
 			// Update worker metrics (v2 API uses Inc/Dec pattern)
 			if q.metrics != nil {
@@ -415,13 +704,22 @@ func (q *PublishingQueue) processJob(job *PublishingJob) {
 		q.jobTrackingStore.Add(job)
 	}
 
+	// Jobs queued before their target was removed are not published
+	if q.isTargetRemoved(job.Target.Name) {
+		q.drainRemovedTargetJob(job)
+		return
+	}
+
 	// Check circuit breaker
-	cb := q.getCircuitBreaker(job.Target.Name)
+	cb := q.getCircuitBreaker(job.Target)
 	if !cb.CanAttempt() {
 		q.logger.Warn("Circuit breaker open, skipping publish",
 			"target", job.Target.Name,
 			"state", cb.State(),
 		)
+		q.recordDelivery(job, 1, core.DeliveryStatusCircuitOpen, time.Now(), 0, nil, nil)
+		q.recordOutcome(job, false)
+		q.fallBack(job, FallbackReasonCircuitOpen)
 		return
 	}
 
@@ -439,7 +737,10 @@ func (q *PublishingQueue) processJob(job *PublishingJob) {
 			"type", job.Target.Type,
 			"error", err,
 		)
+		q.recordDelivery(job, 1, core.DeliveryStatusFailed, now, 0, err, nil)
+		q.recordOutcome(job, false)
 		cb.RecordFailure()
+		q.fallBack(job, FallbackReasonFailed)
 		if q.metrics != nil {
 			q.metrics.RecordJobFailure(job.Target.Name)
 		}
@@ -454,6 +755,13 @@ func (q *PublishingQueue) processJob(job *PublishingJob) {
 	err = q.retryPublish(publisher, job)
 	duration := time.Since(startTime).Seconds()
 
+	// Provider account rate-limited: published once the pause ends
+	var cooldown *providerCooldownError
+	if errors.As(err, &cooldown) {
+		job.requeueAt = cooldown.until
+		return
+	}
+
 	if err != nil {
 		q.totalFailed.Add(1)
 
@@ -468,11 +776,14 @@ func (q *PublishingQueue) processJob(job *PublishingJob) {
 			// v2 API: RecordJobFailure(target string)
 			q.metrics.RecordJobFailure(job.Target.Name)
 		}
+		q.recordDeliverySLO(job, false)
+		q.recordOutcome(job, false)
+		q.fallBack(job, FallbackReasonFailed)
 
 		// Send to Dead Letter Queue
 		if q.dlqRepository != nil {
 			job.State = JobStateDLQ
-			dlqErr := q.dlqRepository.Write(q.ctx, job)
+			dlqErr := q.writeDLQ(job)
 			if dlqErr != nil {
 				q.logger.Error("Failed to write to DLQ",
 					"job_id", job.ID,
@@ -486,11 +797,11 @@ func (q *PublishingQueue) processJob(job *PublishingJob) {
 					"error_type", job.ErrorType,
 				)
 			}
+		}
 
-			// Track DLQ state
-			if q.jobTrackingStore != nil {
-				q.jobTrackingStore.Add(job)
-			}
+		// Track failed/DLQ state
+		if q.jobTrackingStore != nil {
+			q.jobTrackingStore.Add(job)
 		}
 	} else {
 		q.totalCompleted.Add(1)
@@ -502,10 +813,14 @@ func (q *PublishingQueue) processJob(job *PublishingJob) {
 			"queue_time", time.Since(job.SubmittedAt),
 		)
 		cb.RecordSuccess()
+		q.dedup.delivered(job, time.Now())
 		if q.metrics != nil {
 			// v2 API: RecordJobSuccess(target, priority string, duration time.Duration)
 			q.metrics.RecordJobSuccess(job.Target.Name, job.Priority.String(), time.Duration(duration*float64(time.Second)))
+			q.metrics.RecordTargetDelivery(job.Target.Name, job.deliveryPath())
 		}
+		q.recordDeliverySLO(job, true)
+		q.recordOutcome(job, true)
 
 		// Track success state (updated in retryPublish)
 		if q.jobTrackingStore != nil {
@@ -514,13 +829,17 @@ func (q *PublishingQueue) processJob(job *PublishingJob) {
 	}
 }
 
-// getCircuitBreaker gets or creates circuit breaker for target
-func (q *PublishingQueue) getCircuitBreaker(targetName string) *CircuitBreaker {
+// getCircuitBreaker gets or creates circuit breaker for target. The
+// configuration of an existing breaker follows the target's.
+func (q *PublishingQueue) getCircuitBreaker(target *core.PublishingTarget) *CircuitBreaker {
+	config := q.circuitBreakerConfig(target)
+
 	q.mu.RLock()
-	cb, exists := q.circuitBreakers[targetName]
+	cb, exists := q.circuitBreakers[target.Name]
 	q.mu.RUnlock()
 
 	if exists {
+		cb.setConfig(config)
 		return cb
 	}
 
@@ -529,29 +848,30 @@ func (q *PublishingQueue) getCircuitBreaker(targetName string) *CircuitBreaker {
 	defer q.mu.Unlock()
 
 	// Double-check after acquiring write lock
-	if cb, exists := q.circuitBreakers[targetName]; exists {
+	if cb, exists := q.circuitBreakers[target.Name]; exists {
 		return cb
 	}
 
-	cb = NewCircuitBreakerWithName(
-		CircuitBreakerConfig{
-			FailureThreshold: 5,
-			SuccessThreshold: 2,
-			Timeout:          30 * time.Second,
-		},
-		targetName,
-	)
-
-	q.circuitBreakers[targetName] = cb
+	cb = NewCircuitBreakerWithName(config, target.Name)
+	q.circuitBreakers[target.Name] = cb
 
 	// Level guard: avoid expensive logging in production
 	if q.logger.Enabled(q.ctx, slog.LevelDebug) {
-		q.logger.Debug("Created circuit breaker", "target", targetName)
+		q.logger.Debug("Created circuit breaker", "target", target.Name)
 	}
 
 	return cb
 }
 
+// isCircuitOpen reports whether the circuit breaker of a target is open or
+// half-open. Targets without one have not failed yet.
+func (q *PublishingQueue) isCircuitOpen(targetName string) bool {
+	q.mu.RLock()
+	cb, exists := q.circuitBreakers[targetName]
+	q.mu.RUnlock()
+	return exists && cb.State() != StateClosed
+}
+
 // GetQueueSize returns total current queue size (all priorities)
 func (q *PublishingQueue) GetQueueSize() int {
 	return len(q.highPriorityJobs) + len(q.mediumPriorityJobs) + len(q.lowPriorityJobs)
@@ -588,6 +908,9 @@ type QueueStats struct {
 	TotalSubmitted int64
 	TotalCompleted int64
 	TotalFailed    int64
+	TotalPanics    int64
+	TotalShed      int64
+	ShedBySeverity map[string]int64
 }
 
 // GetStats returns detailed queue statistics
@@ -601,17 +924,22 @@ func (q *PublishingQueue) GetStats() QueueStats {
 		activeJobs = len(processingJobs) + len(retryingJobs)
 	}
 
+	shedBySeverity, totalShed := q.shed.snapshot()
+
 	stats := QueueStats{
 		TotalSize:      q.GetQueueSize(),
 		HighPriority:   q.GetQueueSizeByPriority(PriorityHigh),
 		MedPriority:    q.GetQueueSizeByPriority(PriorityMedium),
 		LowPriority:    q.GetQueueSizeByPriority(PriorityLow),
 		Capacity:       q.GetQueueCapacity(),
-		WorkerCount:    q.workerCount,
+		WorkerCount:    int(q.workers.Load()),
 		ActiveJobs:     activeJobs, // Now tracked via JobTrackingStore
 		TotalSubmitted: q.totalSubmitted.Load(),
 		TotalCompleted: q.totalCompleted.Load(),
 		TotalFailed:    q.totalFailed.Load(),
+		TotalPanics:    q.totalPanics.Load(),
+		TotalShed:      totalShed,
+		ShedBySeverity: shedBySeverity,
 	}
 
 	return stats
@@ -634,7 +962,7 @@ func (q *PublishingQueue) retryPublish(publisher AlertPublisher, job *Publishing
 	// Note: Uses queue-specific config (maxRetries, retryInterval) which can be
 	// overridden by global retry config if needed
 	strategy := retry.Strategy{
-		MaxAttempts:     q.maxRetries + 1, // maxRetries is retry count, not total attempts
+		MaxAttempts:     max(q.maxRetries+1-job.RetryCount, 1), // maxRetries is retry count, not total attempts
 		BaseDelay:       q.retryInterval,
 		MaxDelay:        30 * time.Second, // TODO: Make configurable via config.Retry.MaxDelay
 		Multiplier:      2.0,              // TODO: Make configurable via config.Retry.Multiplier
@@ -646,15 +974,43 @@ func (q *PublishingQueue) retryPublish(publisher AlertPublisher, job *Publishing
 
 	// Track attempt count for job state updates
 	attemptCount := 0
+	providerKey := cooldownKey(job.Target)
 
 	// Execute publish with retry
 	err := retry.DoSimple(q.ctx, strategy, func() error {
+		// Requeue the job while its provider account is rate-limited
+		if until := q.cooldowns.pausedUntil(providerKey, time.Now()); !until.IsZero() {
+			return &providerCooldownError{key: providerKey, until: until}
+		}
+
 		attemptCount++
+		attempt := job.RetryCount + attemptCount
+
+		// Hold retries while the queue is paused for maintenance
+		if err := q.waitResumed(); err != nil {
+			return err
+		}
 
-		// Try publish
-		publishErr := publisher.Publish(q.ctx, job.EnrichedAlert, job.Target)
+		// Stay within the rate and concurrency limits of the target
+		release, err := q.acquireTargetLimits(job.Target)
+		if err != nil {
+			return err
+		}
+
+		// Try publish (the slot is released even if the publisher panics),
+		// keeping the receipt of the provider
+		receipt := &deliveryReceipt{}
+		attemptedAt := time.Now()
+		publishErr := func() error {
+			defer release()
+			return q.publish(withDeliveryReceipt(q.ctx, receipt), publisher, job)
+		}()
+		latency := time.Since(attemptedAt)
 
 		if publishErr != nil {
+			q.recordDelivery(job, attempt, core.DeliveryStatusFailed, attemptedAt, latency, publishErr, receipt)
+			until := q.pauseProviderOnRateLimit(job.Target, publishErr)
+
 			// Classify error for job tracking
 			errorType := classifyPublishingError(publishErr)
 			job.LastError = publishErr
@@ -671,10 +1027,15 @@ func (q *PublishingQueue) retryPublish(publisher AlertPublisher, job *Publishing
 				q.metrics.RecordRetryAttempt(job.Target.Name, errorType.String())
 			}
 
+			// Retried once the pause ends instead of after a backoff
+			if !until.IsZero() && attemptCount < strategy.MaxAttempts {
+				return &providerCooldownError{key: providerKey, until: until, err: publishErr}
+			}
 			return publishErr
 		}
 
 		// Success!
+		q.recordDelivery(job, attempt, core.DeliveryStatusSucceeded, attemptedAt, latency, nil, receipt)
 		job.State = JobStateSucceeded
 		now := time.Now()
 		job.CompletedAt = &now
@@ -682,11 +1043,17 @@ func (q *PublishingQueue) retryPublish(publisher AlertPublisher, job *Publishing
 	})
 
 	// Handle final result
+	var cooldown *providerCooldownError
+	if errors.As(err, &cooldown) {
+		job.RetryCount += attemptCount
+		job.State = JobStateRetrying
+		return err
+	}
 	if err != nil {
 		job.State = JobStateFailed
 		now := time.Now()
 		job.CompletedAt = &now
-		return fmt.Errorf("publish failed after %d attempts: %w", attemptCount, err)
+		return fmt.Errorf("publish failed after %d attempts: %w", job.RetryCount+attemptCount, err)
 	}
 
 	return nil
@@ -702,6 +1069,12 @@ func (c *PublishingErrorClassifier) IsRetryable(err error) bool {
 		return false
 	}
 
+	// Jobs of a rate-limited provider account are requeued, not retried
+	var cooldown *providerCooldownError
+	if errors.As(err, &cooldown) {
+		return false
+	}
+
 	// Use queue's error classification
 	errorType := classifyPublishingError(err)
 
@@ -0,0 +1,212 @@
+package publishing
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerAutoscaling scales the queue workers between MinWorkers and
+// MaxWorkers with the load.
+//
+// Workers are added (half the pool at a time) as soon as the high priority
+// queue fills above ScaleUpUtilization or a job waited in the queue longer
+// than ScaleUpLatency. They are retired one per Interval once the queue has
+// been idle (empty, at most half of the workers busy) for ScaleDownAfter; any
+// load resets that delay, so a pool that just grew does not shrink right
+// away.
+type WorkerAutoscaling struct {
+	// MinWorkers and MaxWorkers bound the pool. Autoscaling is disabled
+	// unless MaxWorkers is greater than MinWorkers.
+	MinWorkers int
+	MaxWorkers int
+
+	// ScaleUpUtilization of the high priority queue (0-1] above which
+	// workers are added (default: 0.5).
+	ScaleUpUtilization float64
+
+	// ScaleUpLatency is the queue wait above which workers are added
+	// (optional, wait is ignored when zero).
+	ScaleUpLatency time.Duration
+
+	// ScaleDownAfter is how long the queue must stay idle before workers are
+	// retired (default: 2m).
+	ScaleDownAfter time.Duration
+
+	// Interval between scaling decisions (default: 5s).
+	Interval time.Duration
+}
+
+// Worker autoscaling defaults.
+const (
+	DefaultScaleUpUtilization = 0.5
+	DefaultScaleDownAfter     = 2 * time.Minute
+	DefaultScalingInterval    = 5 * time.Second
+)
+
+// Enabled reports whether the pool may scale.
+func (a WorkerAutoscaling) Enabled() bool {
+	return a.MinWorkers > 0 && a.MaxWorkers > a.MinWorkers
+}
+
+// Validate checks the autoscaling bounds and thresholds.
+func (a WorkerAutoscaling) Validate() error {
+	if !a.Enabled() {
+		return nil
+	}
+	if a.ScaleUpUtilization < 0 || a.ScaleUpUtilization > 1 {
+		return fmt.Errorf("scale up utilization must be between 0 and 1, got %v", a.ScaleUpUtilization)
+	}
+	if a.ScaleUpLatency < 0 || a.ScaleDownAfter < 0 || a.Interval < 0 {
+		return fmt.Errorf("autoscaling durations must be non-negative")
+	}
+	return nil
+}
+
+func (a WorkerAutoscaling) withDefaults() WorkerAutoscaling {
+	if a.ScaleUpUtilization == 0 {
+		a.ScaleUpUtilization = DefaultScaleUpUtilization
+	}
+	if a.ScaleDownAfter == 0 {
+		a.ScaleDownAfter = DefaultScaleDownAfter
+	}
+	if a.Interval == 0 {
+		a.Interval = DefaultScalingInterval
+	}
+	return a
+}
+
+// clamp bounds a worker count to the pool limits.
+func (a WorkerAutoscaling) clamp(workers int) int {
+	return min(max(workers, a.MinWorkers), a.MaxWorkers)
+}
+
+// workerScaler holds the autoscaling state of the queue.
+type workerScaler struct {
+	config WorkerAutoscaling
+
+	retire    chan struct{} // one token per worker to retire
+	maxWait   atomic.Int64  // longest queue wait (ns) since the last decision
+	idleSince time.Time     // zero while loaded; owned by the scaling loop
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newWorkerScaler(config WorkerAutoscaling) *workerScaler {
+	if !config.Enabled() {
+		return nil
+	}
+	config = config.withDefaults()
+	return &workerScaler{
+		config: config,
+		retire: make(chan struct{}, config.MaxWorkers),
+		stop:   make(chan struct{}),
+	}
+}
+
+// observeWait records how long a job waited in the queue.
+func (s *workerScaler) observeWait(wait time.Duration) {
+	for {
+		current := s.maxWait.Load()
+		if int64(wait) <= current || s.maxWait.CompareAndSwap(current, int64(wait)) {
+			return
+		}
+	}
+}
+
+// spawnWorker starts a worker goroutine.
+func (q *PublishingQueue) spawnWorker() {
+	id := int(q.nextWorkerID.Add(1)) - 1
+	q.wg.Add(1)
+	go q.worker(id)
+}
+
+// shouldRetire reports whether the calling worker must exit to shrink the
+// pool.
+func (q *PublishingQueue) shouldRetire() bool {
+	if q.scaler == nil {
+		return false
+	}
+	select {
+	case <-q.scaler.retire:
+		return true
+	default:
+		return false
+	}
+}
+
+// autoscale makes a scaling decision and returns the change of the pool
+// size.
+func (q *PublishingQueue) autoscale(now time.Time) int {
+	s := q.scaler
+	workers := int(q.workers.Load())
+	maxWait := time.Duration(s.maxWait.Swap(0))
+
+	// Jobs held for maintenance are no load
+	if q.IsPaused() {
+		s.idleSince = time.Time{}
+		return 0
+	}
+
+	loaded := s.config.ScaleUpLatency > 0 && maxWait > s.config.ScaleUpLatency
+	if highCap := cap(q.highPriorityJobs); highCap > 0 {
+		loaded = loaded || float64(len(q.highPriorityJobs))/float64(highCap) > s.config.ScaleUpUtilization
+	}
+	idle := q.GetQueueSize() == 0 && int(q.busyWorkers.Load())*2 <= workers
+
+	delta := 0
+	switch {
+	case loaded:
+		s.idleSince = time.Time{}
+		delta = s.config.clamp(workers+max(workers/2, 1)) - workers
+		for range delta {
+			q.spawnWorker()
+		}
+	case idle:
+		if s.idleSince.IsZero() {
+			s.idleSince = now
+		}
+		if now.Sub(s.idleSince) >= s.config.ScaleDownAfter && workers > s.config.MinWorkers {
+			s.retire <- struct{}{}
+			delta = -1
+		}
+	default:
+		s.idleSince = time.Time{}
+	}
+	if delta == 0 {
+		return 0
+	}
+
+	workers = int(q.workers.Add(int32(delta)))
+	q.logger.Info("Publishing queue workers scaled",
+		"workers", workers,
+		"delta", delta,
+		"high_priority_queue", len(q.highPriorityJobs),
+		"max_wait", maxWait,
+	)
+	if q.metrics != nil {
+		q.metrics.RecordWorkerScaling(delta, workers)
+	}
+	return delta
+}
+
+// runAutoscaler makes a scaling decision every interval until the queue
+// stops.
+func (q *PublishingQueue) runAutoscaler() {
+	defer q.scaler.wg.Done()
+
+	ticker := time.NewTicker(q.scaler.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.scaler.stop:
+			return
+		case <-q.ctx.Done():
+			return
+		case now := <-ticker.C:
+			q.autoscale(now)
+		}
+	}
+}
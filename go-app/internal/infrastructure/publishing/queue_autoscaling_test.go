@@ -0,0 +1,144 @@
+package publishing
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+func newAutoscalingTestQueue(workers int, autoscaling WorkerAutoscaling) *PublishingQueue {
+	return NewPublishingQueue(
+		nil,
+		&recordingDLQRepository{},
+		NewLRUJobTrackingStore(16),
+		PublishingQueueConfig{
+			WorkerCount:             workers,
+			HighPriorityQueueSize:   4,
+			MediumPriorityQueueSize: 4,
+			LowPriorityQueueSize:    4,
+			RetryInterval:           time.Millisecond,
+			Metrics:                 v2.NewRegistry(v2.WithPrometheusRegisterer(prometheus.NewRegistry())).Publishing,
+			Autoscaling:             autoscaling,
+		},
+		nil,
+		slog.Default(),
+	)
+}
+
+func TestWorkerAutoscaling_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  WorkerAutoscaling
+		enabled bool
+		wantErr bool
+	}{
+		{"disabled", WorkerAutoscaling{}, false, false},
+		{"fixed pool", WorkerAutoscaling{MinWorkers: 4, MaxWorkers: 4}, false, false},
+		{"valid", WorkerAutoscaling{MinWorkers: 1, MaxWorkers: 8, ScaleUpUtilization: 0.8}, true, false},
+		{"utilization too high", WorkerAutoscaling{MinWorkers: 1, MaxWorkers: 8, ScaleUpUtilization: 1.5}, true, true},
+		{"negative interval", WorkerAutoscaling{MinWorkers: 1, MaxWorkers: 8, Interval: -time.Second}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Enabled(); got != tt.enabled {
+				t.Errorf("Enabled() = %v, want %v", got, tt.enabled)
+			}
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublishingQueue_ClampsInitialWorkers(t *testing.T) {
+	queue := newAutoscalingTestQueue(10, WorkerAutoscaling{MinWorkers: 1, MaxWorkers: 4})
+	defer queue.cancel()
+	if got := queue.GetStats().WorkerCount; got != 4 {
+		t.Errorf("WorkerCount = %d, want MaxWorkers", got)
+	}
+}
+
+func TestPublishingQueue_ScalesUpOnHighPriorityUtilization(t *testing.T) {
+	queue := newAutoscalingTestQueue(2, WorkerAutoscaling{MinWorkers: 1, MaxWorkers: 3})
+	defer queue.cancel()
+
+	for range 3 {
+		queue.highPriorityJobs <- durableTestJob("high")
+	}
+	if delta := queue.autoscale(time.Now()); delta != 1 {
+		t.Fatalf("autoscale() = %d, want 1 (capped by MaxWorkers)", delta)
+	}
+	if got := queue.workers.Load(); got != 3 {
+		t.Errorf("workers = %d, want 3", got)
+	}
+}
+
+func TestPublishingQueue_ScalesUpOnQueueWait(t *testing.T) {
+	queue := newAutoscalingTestQueue(2, WorkerAutoscaling{MinWorkers: 1, MaxWorkers: 10, ScaleUpLatency: time.Second})
+	defer queue.cancel()
+
+	queue.scaler.observeWait(500 * time.Millisecond)
+	if delta := queue.autoscale(time.Now()); delta != 0 {
+		t.Fatalf("autoscale() = %d below the latency threshold", delta)
+	}
+	queue.scaler.observeWait(2 * time.Second)
+	queue.scaler.observeWait(time.Millisecond)
+	if delta := queue.autoscale(time.Now()); delta != 1 {
+		t.Fatalf("autoscale() = %d, want 1", delta)
+	}
+}
+
+func TestPublishingQueue_ScalesDownAfterIdlePeriod(t *testing.T) {
+	queue := newAutoscalingTestQueue(3, WorkerAutoscaling{MinWorkers: 2, MaxWorkers: 6, ScaleDownAfter: time.Minute})
+	defer queue.cancel()
+
+	now := time.Now()
+	for _, offset := range []time.Duration{0, 30 * time.Second} {
+		if delta := queue.autoscale(now.Add(offset)); delta != 0 {
+			t.Fatalf("autoscale(+%v) = %d before the idle period", offset, delta)
+		}
+	}
+
+	// Load resets the idle period
+	queue.mediumPriorityJobs <- durableTestJob("medium")
+	queue.autoscale(now.Add(45 * time.Second))
+	<-queue.mediumPriorityJobs
+	if delta := queue.autoscale(now.Add(90 * time.Second)); delta != 0 {
+		t.Fatalf("autoscale() = %d right after load", delta)
+	}
+
+	if delta := queue.autoscale(now.Add(150 * time.Second)); delta != -1 {
+		t.Fatalf("autoscale() = %d, want -1", delta)
+	}
+	if !queue.shouldRetire() {
+		t.Error("no worker asked to retire")
+	}
+	if delta := queue.autoscale(now.Add(155 * time.Second)); delta != 0 {
+		t.Errorf("autoscale() = %d below MinWorkers", delta)
+	}
+}
+
+func TestPublishingQueue_RetiresWorkers(t *testing.T) {
+	queue := newAutoscalingTestQueue(3, WorkerAutoscaling{
+		MinWorkers:     1,
+		MaxWorkers:     3,
+		ScaleDownAfter: 10 * time.Millisecond,
+		Interval:       5 * time.Millisecond,
+	})
+	queue.Start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for queue.GetStats().WorkerCount > 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("WorkerCount = %d, want MinWorkers", queue.GetStats().WorkerCount)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := queue.Stop(time.Second); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Target headers enabling batching: the queue publishes the alerts of the
+// target together, in one request of at most batch_max_size alerts, sent
+// once batch_flush_interval has elapsed since the first alert or the batch
+// is full. They are never sent.
+const (
+	targetBatchMaxSizeHeader       = "batch_max_size"       // default 100
+	targetBatchFlushIntervalHeader = "batch_flush_interval" // default 5s
+)
+
+const (
+	defaultBatchMaxSize       = 100
+	defaultBatchFlushInterval = 5 * time.Second
+	maxBatchMaxSize           = 1000
+	maxBatchFlushInterval     = 5 * time.Minute
+)
+
+// Batch flush triggers (metric label)
+const (
+	BatchTriggerSize     = "size"
+	BatchTriggerInterval = "interval"
+	BatchTriggerShutdown = "shutdown"
+	BatchTriggerRemoved  = "removed"
+)
+
+// batchTargetTypes are the target types that can be batched: those posting
+// a generic JSON payload through a BatchPublisher.
+var batchTargetTypes = []string{"webhook", "alertmanager"}
+
+// TargetBatchConfig is the batching configuration of a target.
+type TargetBatchConfig struct {
+	MaxSize       int
+	FlushInterval time.Duration
+}
+
+// ParseTargetBatchConfig reads the batching headers of target. ok is false
+// when the target has none: its alerts are published one by one.
+func ParseTargetBatchConfig(target *core.PublishingTarget) (cfg TargetBatchConfig, ok bool, err error) {
+	rawSize, hasSize := target.Headers[targetBatchMaxSizeHeader]
+	rawInterval, hasInterval := target.Headers[targetBatchFlushIntervalHeader]
+	if !hasSize && !hasInterval {
+		return TargetBatchConfig{}, false, nil
+	}
+
+	cfg = TargetBatchConfig{MaxSize: defaultBatchMaxSize, FlushInterval: defaultBatchFlushInterval}
+	if hasSize {
+		size, err := strconv.Atoi(strings.TrimSpace(rawSize))
+		if err != nil || size < 1 || size > maxBatchMaxSize {
+			return TargetBatchConfig{}, false, fmt.Errorf("invalid %s %q: must be between 1 and %d", targetBatchMaxSizeHeader, rawSize, maxBatchMaxSize)
+		}
+		cfg.MaxSize = size
+	}
+	if hasInterval {
+		interval, err := time.ParseDuration(strings.TrimSpace(rawInterval))
+		if err != nil || interval <= 0 || interval > maxBatchFlushInterval {
+			return TargetBatchConfig{}, false, fmt.Errorf("invalid %s %q: must be a duration up to %s", targetBatchFlushIntervalHeader, rawInterval, maxBatchFlushInterval)
+		}
+		cfg.FlushInterval = interval
+	}
+	return cfg, true, nil
+}
+
+// ValidateTargetBatching checks the batching headers of target, if any: they
+// must parse and the target type must support batching.
+func ValidateTargetBatching(target *core.PublishingTarget) error {
+	_, ok, err := ParseTargetBatchConfig(target)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	for _, targetType := range batchTargetTypes {
+		if target.Type == targetType {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s/%s are only supported by webhook and alertmanager targets", targetBatchMaxSizeHeader, targetBatchFlushIntervalHeader)
+}
+
+// jobBatcher collects the alerts of batched targets until their batch is
+// full or its flush interval elapses, then hands them to flush as one batch.
+type jobBatcher struct {
+	mu      sync.Mutex
+	batches map[string]*jobBatch // by target name
+	closed  bool
+	wg      sync.WaitGroup
+	flush   func(alerts []*core.EnrichedAlert, target *core.PublishingTarget, trigger string)
+}
+
+// jobBatch is the open batch of one target.
+type jobBatch struct {
+	target *core.PublishingTarget
+	alerts []*core.EnrichedAlert
+	timer  *time.Timer
+}
+
+func newJobBatcher(flush func([]*core.EnrichedAlert, *core.PublishingTarget, string)) *jobBatcher {
+	return &jobBatcher{batches: make(map[string]*jobBatch), flush: flush}
+}
+
+// add adds alert to the open batch of target or opens one. An alert with the
+// fingerprint of a batched one replaces it (latest state). Returns false
+// after close(): the caller enqueues the alert itself.
+func (b *jobBatcher) add(alert *core.EnrichedAlert, target *core.PublishingTarget, cfg TargetBatchConfig) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return false
+	}
+
+	key := target.Name
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &jobBatch{}
+		b.batches[key] = batch
+		b.wg.Add(1)
+		batch.timer = time.AfterFunc(cfg.FlushInterval, func() { b.release(key, batch, BatchTriggerInterval) })
+	}
+	// The latest target configuration wins (discovery refresh)
+	batch.target = target
+
+	replaced := false
+	for i, queued := range batch.alerts {
+		if queued.Alert.Fingerprint == alert.Alert.Fingerprint {
+			batch.alerts[i] = alert
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		batch.alerts = append(batch.alerts, alert)
+	}
+
+	// Stop() == false: the timer fired, release takes the batch itself
+	if len(batch.alerts) >= cfg.MaxSize && batch.timer.Stop() {
+		go b.release(key, batch, BatchTriggerSize)
+	}
+	return true
+}
+
+// release closes batch and flushes it. Called exactly once per batch.
+func (b *jobBatcher) release(key string, batch *jobBatch, trigger string) {
+	defer b.wg.Done()
+
+	b.mu.Lock()
+	if b.batches[key] == batch {
+		delete(b.batches, key)
+	}
+	alerts, target := batch.alerts, batch.target
+	b.mu.Unlock()
+
+	b.flush(alerts, target, trigger)
+}
+
+// forget flushes the open batch of a target removed from discovery right
+// away, so that its job is drained with the other jobs of the target.
+// Returns once the batch is handed to flush; a batch whose interval already
+// elapsed is flushed by its timer.
+func (b *jobBatcher) forget(name string) {
+	b.mu.Lock()
+	batch, ok := b.batches[name]
+	if !ok || !batch.timer.Stop() {
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Unlock()
+
+	b.release(name, batch, BatchTriggerRemoved)
+}
+
+// close flushes the open batches without waiting for their interval and
+// waits for the flushes. Alerts added after close() are not batched.
+func (b *jobBatcher) close() {
+	b.mu.Lock()
+	b.closed = true
+	for key, batch := range b.batches {
+		if batch.timer.Stop() {
+			go b.release(key, batch, BatchTriggerShutdown)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// batchJob hands the alert of job to the batcher when its target is
+// batched. Returns false when the job must be enqueued as is.
+func (q *PublishingQueue) batchJob(job *PublishingJob) bool {
+	cfg, batched, err := ParseTargetBatchConfig(job.Target)
+	if err != nil {
+		q.logger.Warn("Ignoring invalid target batching", "target", job.Target.Name, "error", err)
+	}
+	if !batched {
+		return false
+	}
+	return q.batcher.add(job.EnrichedAlert, job.Target, cfg)
+}
+
+// submitBatch enqueues a flushed batch as one job, prioritised as its most
+// urgent alert. A batch that cannot be enqueued goes to the DLQ.
+func (q *PublishingQueue) submitBatch(alerts []*core.EnrichedAlert, target *core.PublishingTarget, trigger string) {
+	if len(alerts) == 0 {
+		return
+	}
+	if q.metrics != nil {
+		q.metrics.RecordBatchFlush(target.Name, trigger, len(alerts))
+	}
+
+	priority := PriorityLow
+	for _, alert := range alerts {
+		priority = min(priority, determinePriority(alert))
+	}
+	job := &PublishingJob{
+		EnrichedAlert: alerts[0],
+		Batch:         alerts,
+		Target:        target,
+		SubmittedAt:   time.Now(),
+		ID:            uuid.NewString(),
+		Priority:      priority,
+		State:         JobStateQueued,
+	}
+	if len(alerts) == 1 {
+		job.Batch = nil
+	}
+
+	if err := q.enqueue(job); err != nil {
+		q.logger.Error("Failed to enqueue alert batch",
+			"job_id", job.ID,
+			"target", target.Name,
+			"batch_size", len(alerts),
+			"trigger", trigger,
+			"error", err,
+		)
+		now := time.Now()
+		job.State = JobStateDLQ
+		job.CompletedAt = &now
+		job.LastError = err
+		job.ErrorType = QueueErrorTypeTransient
+		q.totalFailed.Add(1)
+		if q.dlqRepository != nil {
+			if dlqErr := q.writeDLQ(job); dlqErr != nil {
+				q.logger.Error("Failed to write alert batch to DLQ",
+					"job_id", job.ID,
+					"target", target.Name,
+					"error", dlqErr,
+				)
+			}
+		}
+	}
+}
+
+// alerts returns the alerts published by job: its batch, or its alert.
+func (j *PublishingJob) alerts() []*core.EnrichedAlert {
+	if len(j.Batch) > 0 {
+		return j.Batch
+	}
+	return []*core.EnrichedAlert{j.EnrichedAlert}
+}
+
+// publish publishes the alert or the batch of job. Publishers without batch
+// support publish the alerts of a batch one by one.
+//
+// The alerts are redacted for the target first (see TargetRedaction), and
+// sent through its outbound HTTP config (see ParseTargetHTTPConfig).
+func (q *PublishingQueue) publish(ctx context.Context, publisher AlertPublisher, job *PublishingJob) error {
+	ctx, alerts, err := redactForTarget(ctx, job.Target, job.alerts())
+	if err != nil {
+		return fmt.Errorf("failed to redact alerts: %w", err)
+	}
+	if ctx, err = withTargetHTTPConfig(ctx, job.Target); err != nil {
+		return fmt.Errorf("invalid outbound HTTP config: %w", err)
+	}
+	if len(job.Batch) == 0 {
+		return publisher.Publish(ctx, alerts[0], job.Target)
+	}
+	if batchPublisher, ok := publisher.(BatchPublisher); ok {
+		return batchPublisher.PublishBatch(ctx, alerts, job.Target)
+	}
+	for _, alert := range alerts {
+		if err := publisher.Publish(ctx, alert, job.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDLQ writes job to the DLQ; a batch is written as one entry per alert
+// so each can be replayed.
+func (q *PublishingQueue) writeDLQ(job *PublishingJob) error {
+	if len(job.Batch) == 0 {
+		return q.dlqRepository.Write(q.ctx, job)
+	}
+	var errs []error
+	for _, alert := range job.Batch {
+		single := *job
+		single.EnrichedAlert = alert
+		single.Batch = nil
+		if err := q.dlqRepository.Write(q.ctx, &single); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
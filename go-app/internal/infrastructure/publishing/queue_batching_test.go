@@ -0,0 +1,190 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// batchRecordingPublisher records the batches it publishes.
+type batchRecordingPublisher struct {
+	scriptedPublisher
+	batches [][]*core.EnrichedAlert
+}
+
+func (p *batchRecordingPublisher) PublishBatch(ctx context.Context, alerts []*core.EnrichedAlert, target *core.PublishingTarget) error {
+	p.batches = append(p.batches, alerts)
+	return p.Publish(ctx, nil, target)
+}
+
+func batchTestAlert(fingerprint, severity string) *core.EnrichedAlert {
+	alert := panicTestAlert()
+	alert.Alert.Fingerprint = fingerprint
+	alert.Alert.Labels = map[string]string{"severity": severity}
+	return alert
+}
+
+func batchTestTarget(headers map[string]string) *core.PublishingTarget {
+	return &core.PublishingTarget{Name: "busy-webhook", Type: "webhook", URL: "http://127.0.0.1:1", Headers: headers}
+}
+
+func TestParseTargetBatchConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    TargetBatchConfig
+		batched bool
+		wantErr bool
+	}{
+		{"not batched", map[string]string{"X-Token": "secret"}, TargetBatchConfig{}, false, false},
+		{"defaults", map[string]string{targetBatchMaxSizeHeader: "100"}, TargetBatchConfig{MaxSize: 100, FlushInterval: defaultBatchFlushInterval}, true, false},
+		{"both", map[string]string{targetBatchMaxSizeHeader: " 20 ", targetBatchFlushIntervalHeader: "30s"}, TargetBatchConfig{MaxSize: 20, FlushInterval: 30 * time.Second}, true, false},
+		{"interval only", map[string]string{targetBatchFlushIntervalHeader: "1s"}, TargetBatchConfig{MaxSize: defaultBatchMaxSize, FlushInterval: time.Second}, true, false},
+		{"zero size", map[string]string{targetBatchMaxSizeHeader: "0"}, TargetBatchConfig{}, false, true},
+		{"size too large", map[string]string{targetBatchMaxSizeHeader: "1001"}, TargetBatchConfig{}, false, true},
+		{"negative interval", map[string]string{targetBatchFlushIntervalHeader: "-1s"}, TargetBatchConfig{}, false, true},
+		{"interval too long", map[string]string{targetBatchFlushIntervalHeader: "1h"}, TargetBatchConfig{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, batched, err := ParseTargetBatchConfig(batchTestTarget(tt.headers))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || batched != tt.batched {
+				t.Errorf("ParseTargetBatchConfig() = %+v, %v; want %+v, %v", got, batched, tt.want, tt.batched)
+			}
+		})
+	}
+}
+
+func TestPublishingQueue_BatchesUpToMaxSize(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.cancel()
+
+	target := batchTestTarget(map[string]string{targetBatchMaxSizeHeader: "3", targetBatchFlushIntervalHeader: "1m"})
+	for _, alert := range []*core.EnrichedAlert{
+		batchTestAlert("fp-1", "info"),
+		batchTestAlert("fp-2", "warning"),
+		batchTestAlert("fp-1", "critical"), // latest state of fp-1 replaces it
+	} {
+		if err := queue.Submit(alert, target); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	if size := queue.GetQueueSize(); size != 0 {
+		t.Fatalf("queue size = %d before the batch is full", size)
+	}
+	if err := queue.Submit(batchTestAlert("fp-3", "info"), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	var job *PublishingJob
+	select {
+	case job = <-queue.highPriorityJobs:
+	case <-time.After(time.Second):
+		t.Fatal("full batch was not enqueued as a high priority job")
+	}
+	if len(job.Batch) != 3 || job.EnrichedAlert != job.Batch[0] {
+		t.Fatalf("Batch = %d alerts, EnrichedAlert = %v", len(job.Batch), job.EnrichedAlert)
+	}
+	if got := job.Batch[0].KnownLabels().Severity; got != "critical" {
+		t.Errorf("fp-1 severity = %q, want the latest state", got)
+	}
+}
+
+func TestPublishingQueue_FlushesBatchOnInterval(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.cancel()
+
+	target := batchTestTarget(map[string]string{targetBatchFlushIntervalHeader: "20ms"})
+	if err := queue.Submit(batchTestAlert("fp-1", "warning"), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	select {
+	case job := <-queue.mediumPriorityJobs:
+		if job.Batch != nil || job.EnrichedAlert.Alert.Fingerprint != "fp-1" {
+			t.Errorf("single-alert batch = %+v, want a plain job", job)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batch was not flushed after its interval")
+	}
+}
+
+func TestPublishingQueue_StopFlushesBatches(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+
+	target := batchTestTarget(map[string]string{targetBatchFlushIntervalHeader: "5m"})
+	for i := range 2 {
+		if err := queue.Submit(batchTestAlert(fmt.Sprintf("fp-%d", i), "warning"), target); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	if err := queue.Stop(time.Second); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	job := <-queue.mediumPriorityJobs
+	if job == nil || len(job.Batch) != 2 {
+		t.Fatalf("job = %+v, want the open batch", job)
+	}
+}
+
+func TestPublishingQueue_PublishesBatch(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	deliveries := &recordingDeliveries{}
+	queue.deliveries = deliveries
+
+	alerts := []*core.EnrichedAlert{batchTestAlert("fp-1", "warning"), batchTestAlert("fp-2", "warning")}
+	job := &PublishingJob{ID: "batch-1", EnrichedAlert: alerts[0], Batch: alerts, Target: batchTestTarget(nil)}
+
+	batchPublisher := &batchRecordingPublisher{}
+	if err := queue.retryPublish(batchPublisher, job); err != nil {
+		t.Fatalf("retryPublish() error = %v", err)
+	}
+	if len(batchPublisher.batches) != 1 || len(batchPublisher.batches[0]) != 2 {
+		t.Errorf("published batches = %v, want one of 2 alerts", batchPublisher.batches)
+	}
+	for _, alert := range alerts {
+		got := deliveries.attempts[alert.Alert.Fingerprint]
+		if len(got) != 1 || got[0].JobID != "batch-1" || got[0].Status != core.DeliveryStatusSucceeded {
+			t.Errorf("deliveries of %s = %+v", alert.Alert.Fingerprint, got)
+		}
+	}
+
+	// Publishers without batch support publish the alerts one by one
+	publisher := &scriptedPublisher{}
+	if err := queue.retryPublish(publisher, job); err != nil {
+		t.Fatalf("retryPublish() error = %v", err)
+	}
+	if len(publisher.calls) != 2 {
+		t.Errorf("Publish() called %d times, want 2", len(publisher.calls))
+	}
+}
+
+func TestPublishingQueue_WritesBatchToDLQPerAlert(t *testing.T) {
+	dlq := &recordingDLQRepository{}
+	queue := newPanickingQueue(dlq)
+	defer queue.cancel()
+
+	alerts := []*core.EnrichedAlert{batchTestAlert("fp-1", "warning"), batchTestAlert("fp-2", "warning")}
+	job := &PublishingJob{ID: "batch-1", EnrichedAlert: alerts[0], Batch: alerts, Target: batchTestTarget(nil)}
+	if err := queue.writeDLQ(job); err != nil {
+		t.Fatalf("writeDLQ() error = %v", err)
+	}
+
+	written := dlq.written()
+	if len(written) != 2 {
+		t.Fatalf("DLQ entries = %d, want one per alert", len(written))
+	}
+	for i, entry := range written {
+		if entry.ID != "batch-1" || entry.Batch != nil || entry.EnrichedAlert != alerts[i] {
+			t.Errorf("DLQ entry %d = %+v", i, entry)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package publishing
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Target headers overriding the circuit breaker configured for the target
+// type. They are never sent.
+const (
+	targetCircuitFailureThresholdHeader = "circuit_failure_threshold"
+	targetCircuitSuccessThresholdHeader = "circuit_success_threshold"
+	targetCircuitTimeoutHeader          = "circuit_timeout"
+)
+
+const maxCircuitTimeout = time.Hour
+
+// ParseTargetCircuitBreaker reads the circuit breaker headers of target over
+// base, the configuration of its type.
+func ParseTargetCircuitBreaker(target *core.PublishingTarget, base CircuitBreakerConfig) (CircuitBreakerConfig, error) {
+	config := base
+	for header, threshold := range map[string]*int{
+		targetCircuitFailureThresholdHeader: &config.FailureThreshold,
+		targetCircuitSuccessThresholdHeader: &config.SuccessThreshold,
+	} {
+		raw, ok := target.Headers[header]
+		if !ok {
+			continue
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || value < 1 {
+			return CircuitBreakerConfig{}, fmt.Errorf("invalid %s %q: must be a positive integer", header, raw)
+		}
+		*threshold = value
+	}
+	if raw, ok := target.Headers[targetCircuitTimeoutHeader]; ok {
+		value, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || value < time.Second || value > maxCircuitTimeout {
+			return CircuitBreakerConfig{}, fmt.Errorf("invalid %s %q: must be a duration between 1s and %s", targetCircuitTimeoutHeader, raw, maxCircuitTimeout)
+		}
+		config.Timeout = value
+	}
+	return config, nil
+}
+
+// ValidateTargetCircuitBreaker checks the circuit breaker headers of target,
+// if any.
+func ValidateTargetCircuitBreaker(target *core.PublishingTarget) error {
+	_, err := ParseTargetCircuitBreaker(target, DefaultCircuitBreakerConfig())
+	return err
+}
+
+// circuitBreakerConfig returns the circuit breaker configuration of target:
+// the defaults, overridden by the queue configuration, the configuration of
+// its type, then its headers. Invalid headers are ignored (discovery rejects
+// them).
+func (q *PublishingQueue) circuitBreakerConfig(target *core.PublishingTarget) CircuitBreakerConfig {
+	base := DefaultCircuitBreakerConfig().override(q.breakerDefaults).override(q.breakersByType[target.Type])
+	config, err := ParseTargetCircuitBreaker(target, base)
+	if err != nil {
+		q.logger.Warn("Ignoring invalid target circuit breaker", "target", target.Name, "error", err)
+		return base
+	}
+	return config
+}
+
+// CircuitBreakers returns the state of the circuit breakers of the targets
+// published to, by target name.
+func (q *PublishingQueue) CircuitBreakers() []CircuitBreakerStatus {
+	q.mu.RLock()
+	breakers := make([]*CircuitBreaker, 0, len(q.circuitBreakers))
+	for _, cb := range q.circuitBreakers {
+		breakers = append(breakers, cb)
+	}
+	q.mu.RUnlock()
+
+	statuses := make([]CircuitBreakerStatus, 0, len(breakers))
+	for _, cb := range breakers {
+		statuses = append(statuses, cb.Status())
+	}
+	slices.SortFunc(statuses, func(a, b CircuitBreakerStatus) int { return strings.Compare(a.Target, b.Target) })
+	return statuses
+}
+
+// CircuitBreaker returns the state of the circuit breaker of target, closed
+// if it was not published to yet.
+func (q *PublishingQueue) CircuitBreaker(target *core.PublishingTarget) CircuitBreakerStatus {
+	return q.getCircuitBreaker(target).Status()
+}
+
+// ForceCircuitBreaker holds the circuit breaker of target in state until
+// ResetCircuitBreaker. Jobs of a target forced open are dropped as when its
+// breaker trips; a target forced closed is published to whatever its
+// failures.
+func (q *PublishingQueue) ForceCircuitBreaker(target *core.PublishingTarget, state CircuitBreakerState) CircuitBreakerStatus {
+	cb := q.getCircuitBreaker(target)
+	cb.Force(state)
+	q.logger.Warn("Circuit breaker forced", "target", target.Name, "state", state)
+	return cb.Status()
+}
+
+// ResetCircuitBreaker closes the circuit breaker of target and releases a
+// forced state.
+func (q *PublishingQueue) ResetCircuitBreaker(target *core.PublishingTarget) CircuitBreakerStatus {
+	cb := q.getCircuitBreaker(target)
+	cb.Reset()
+	q.logger.Info("Circuit breaker reset", "target", target.Name)
+	return cb.Status()
+}
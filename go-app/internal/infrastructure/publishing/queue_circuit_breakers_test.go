@@ -0,0 +1,90 @@
+package publishing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestPublishingQueue_CircuitBreakerConfig(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	queue.breakerDefaults = CircuitBreakerConfig{Timeout: time.Minute}
+	queue.breakersByType = map[string]CircuitBreakerConfig{ProviderPagerDuty: {FailureThreshold: 2}}
+
+	tests := []struct {
+		name   string
+		target *core.PublishingTarget
+		want   CircuitBreakerConfig
+	}{
+		{"queue default", &core.PublishingTarget{Name: "slack-ops", Type: ProviderSlack}, CircuitBreakerConfig{FailureThreshold: 5, SuccessThreshold: 2, Timeout: time.Minute}},
+		{"type override", &core.PublishingTarget{Name: "pd", Type: ProviderPagerDuty}, CircuitBreakerConfig{FailureThreshold: 2, SuccessThreshold: 2, Timeout: time.Minute}},
+		{"target headers", &core.PublishingTarget{Name: "pd-2", Type: ProviderPagerDuty, Headers: map[string]string{
+			targetCircuitFailureThresholdHeader: "10",
+			targetCircuitTimeoutHeader:          "5s",
+		}}, CircuitBreakerConfig{FailureThreshold: 10, SuccessThreshold: 2, Timeout: 5 * time.Second}},
+		{"invalid headers", &core.PublishingTarget{Name: "pd-3", Type: ProviderPagerDuty, Headers: map[string]string{
+			targetCircuitSuccessThresholdHeader: "0",
+		}}, CircuitBreakerConfig{FailureThreshold: 2, SuccessThreshold: 2, Timeout: time.Minute}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queue.circuitBreakerConfig(tt.target); got != tt.want {
+				t.Errorf("circuitBreakerConfig = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Existing breakers follow the target
+	target := &core.PublishingTarget{Name: "webhook", Type: "webhook"}
+	queue.getCircuitBreaker(target)
+	target.Headers = map[string]string{targetCircuitFailureThresholdHeader: "1"}
+	if got := queue.getCircuitBreaker(target).Status().FailureThreshold; got != 1 {
+		t.Errorf("failure_threshold = %d after the target changed, want 1", got)
+	}
+}
+
+func TestValidateTargetCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		header, value string
+		valid         bool
+	}{
+		{targetCircuitFailureThresholdHeader, "3", true},
+		{targetCircuitFailureThresholdHeader, "0", false},
+		{targetCircuitSuccessThresholdHeader, "abc", false},
+		{targetCircuitTimeoutHeader, "1m", true},
+		{targetCircuitTimeoutHeader, "10ms", false},
+		{targetCircuitTimeoutHeader, "2h", false},
+	}
+	for _, tt := range tests {
+		err := ValidateTargetCircuitBreaker(&core.PublishingTarget{Headers: map[string]string{tt.header: tt.value}})
+		if (err == nil) != tt.valid {
+			t.Errorf("%s=%s: err = %v, want valid %t", tt.header, tt.value, err, tt.valid)
+		}
+	}
+}
+
+func TestPublishingQueue_ForceCircuitBreaker(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	outcomes := &recordingOutcomes{}
+	queue.outcomes = outcomes
+
+	job := cooldownTestJob("pagerduty-oncall", ProviderPagerDuty)
+	if status := queue.ForceCircuitBreaker(job.Target, StateOpen); status.State != "open" || !status.Forced {
+		t.Fatalf("forced status = %+v", status)
+	}
+	queue.processJob(job)
+	if len(outcomes.outcomes) != 1 || outcomes.outcomes[0] != "panic-fingerprint/pagerduty-oncall/false" {
+		t.Fatalf("outcomes = %v, want the job dropped", outcomes.outcomes)
+	}
+
+	breakers := queue.CircuitBreakers()
+	if len(breakers) != 1 || breakers[0].Target != "pagerduty-oncall" {
+		t.Fatalf("CircuitBreakers = %+v", breakers)
+	}
+	if status := queue.ResetCircuitBreaker(job.Target); status.State != "closed" || status.Forced {
+		t.Fatalf("reset status = %+v", status)
+	}
+}
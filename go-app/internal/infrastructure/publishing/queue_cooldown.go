@@ -0,0 +1,166 @@
+package publishing
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// providerCooldowns tracks provider accounts that rate-limited the queue.
+//
+// A 429 from a provider applies to the whole account or endpoint, not to a
+// single job: when one job is rate-limited, the other jobs of the same
+// account are requeued until Retry-After elapses instead of hammering it
+// further. Other accounts of the provider are not held off.
+type providerCooldowns struct {
+	mu    sync.Mutex
+	until map[string]time.Time // cooldown key -> end of pause
+}
+
+func newProviderCooldowns() *providerCooldowns {
+	return &providerCooldowns{until: make(map[string]time.Time)}
+}
+
+// cooldownKey returns the rate limit scope of target: the endpoint host of
+// generic webhook targets, which targets posting to the same service share,
+// and the target name otherwise, each target having its own account or
+// integration.
+func cooldownKey(target *core.PublishingTarget) string {
+	switch ParseTargetType(target.Type) {
+	case TargetTypeWebhook, TargetTypeAlertmanager:
+		if u, err := url.Parse(target.URL); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	return target.Name
+}
+
+// pause pauses key until the given time. An existing longer pause is kept.
+// Reports whether the pause was extended.
+func (c *providerCooldowns) pause(key string, until time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !until.After(c.until[key]) {
+		return false
+	}
+	c.until[key] = until
+	return true
+}
+
+// pausedUntil returns the end of the pause of key, or the zero time when it
+// is not paused at now.
+func (c *providerCooldowns) pausedUntil(key string, now time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	until, ok := c.until[key]
+	if !ok {
+		return time.Time{}
+	}
+	if !until.After(now) {
+		delete(c.until, key)
+		return time.Time{}
+	}
+	return until
+}
+
+// forget drops the pause of key. Reports whether key was paused.
+func (c *providerCooldowns) forget(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, paused := c.until[key]
+	delete(c.until, key)
+	return paused
+}
+
+// providerCooldownError stops the attempts of a job whose provider account
+// is paused. The job is requeued once the pause ends.
+type providerCooldownError struct {
+	key   string
+	until time.Time
+	err   error // rate limit error of the attempt that started the pause, if any
+}
+
+func (e *providerCooldownError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("provider %s paused until %s: %v", e.key, e.until.Format(time.RFC3339), e.err)
+	}
+	return fmt.Sprintf("provider %s paused until %s", e.key, e.until.Format(time.RFC3339))
+}
+
+func (e *providerCooldownError) Unwrap() error { return e.err }
+
+// pauseProviderOnRateLimit pauses all jobs of the provider account of target
+// when err is a rate limit error, and returns the end of the pause (zero for
+// other errors). Without a Retry-After hint the pause lasts one retry
+// interval.
+func (q *PublishingQueue) pauseProviderOnRateLimit(target *core.PublishingTarget, err error) time.Time {
+	if !httperror.IsRateLimit(err) {
+		return time.Time{}
+	}
+
+	retryAfter := time.Duration(httperror.GetRetryAfter(err)) * time.Second
+	if retryAfter <= 0 {
+		retryAfter = q.retryInterval
+	}
+	key := cooldownKey(target)
+	until := time.Now().Add(retryAfter)
+
+	if q.metrics != nil {
+		q.metrics.RecordRateLimitHit(target.Type)
+	}
+	if q.cooldowns.pause(key, until) {
+		q.logger.Warn("Provider rate limited, pausing its jobs",
+			slog.String("provider", target.Type),
+			slog.String("cooldown_key", key),
+			slog.Duration("retry_after", retryAfter))
+		if q.metrics != nil {
+			q.metrics.SetProviderPausedUntil(key, until)
+		}
+	}
+	return q.cooldowns.pausedUntil(key, time.Now())
+}
+
+// requeueAfter queues job again once the cooldown of its provider ends, so
+// that it does not hold a worker meanwhile. The job stays stored (durable
+// queue) and counts as in flight until then. Called by the worker that
+// processed the job.
+func (q *PublishingQueue) requeueAfter(job *PublishingJob, until time.Time) {
+	job.State = JobStateRetrying
+	if q.jobTrackingStore != nil {
+		q.jobTrackingStore.Add(job)
+	}
+
+	target := job.Target.Name
+	q.requeuedJobs.Add(1)
+	q.trackTargetJob(target, 1)
+	time.AfterFunc(time.Until(until), func() {
+		defer q.trackTargetJob(target, -1)
+		defer q.requeuedJobs.Add(-1)
+		if q.ctx.Err() != nil {
+			// Stopped: a durable queue recovers the stored job on restart
+			return
+		}
+
+		key := cooldownKey(job.Target)
+		if q.metrics != nil && q.cooldowns.pausedUntil(key, time.Now()).IsZero() {
+			q.metrics.SetProviderPausedUntil(key, time.Time{})
+		}
+
+		job.State = JobStateQueued
+		if err := q.send(job); err != nil {
+			q.logger.Warn("Failed to requeue publishing job after provider cooldown, retrying later",
+				"job_id", job.ID,
+				"target", job.Target.Name,
+				"error", err,
+			)
+			q.requeueAfter(job, time.Now().Add(q.retryInterval))
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// scriptedPublisher returns its errors in order, then succeeds, and records
+// when each call was made.
+type scriptedPublisher struct {
+	errs  []error
+	calls []time.Time
+}
+
+func (p *scriptedPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	p.calls = append(p.calls, time.Now())
+	if len(p.errs) == 0 {
+		return nil
+	}
+	err := p.errs[0]
+	p.errs = p.errs[1:]
+	return err
+}
+
+func (p *scriptedPublisher) Name() string { return "scripted" }
+
+func newCooldownTestQueue(retryInterval time.Duration) *PublishingQueue {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	queue.retryInterval = retryInterval
+	queue.maxRetries = 0
+	return queue
+}
+
+func cooldownTestJob(name, targetType string) *PublishingJob {
+	return &PublishingJob{
+		EnrichedAlert: panicTestAlert(),
+		Target:        &core.PublishingTarget{Name: name, Type: targetType},
+	}
+}
+
+func TestProviderCooldowns_PauseOnlyExtends(t *testing.T) {
+	c := newProviderCooldowns()
+	now := time.Now()
+
+	if !c.pause("slack", now.Add(time.Minute)) {
+		t.Fatal("first pause was not recorded")
+	}
+	if c.pause("slack", now.Add(time.Second)) {
+		t.Error("shorter pause replaced a longer one")
+	}
+	if got := c.pausedUntil("slack", now); !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("pausedUntil = %v, want %v", got, now.Add(time.Minute))
+	}
+	if got := c.pausedUntil("rootly", now); !got.IsZero() {
+		t.Errorf("other provider paused until %v", got)
+	}
+	if got := c.pausedUntil("slack", now.Add(2*time.Minute)); !got.IsZero() {
+		t.Errorf("elapsed pause still reported: %v", got)
+	}
+}
+
+func TestCooldownKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		target *core.PublishingTarget
+		want   string
+	}{
+		{"provider target", &core.PublishingTarget{Name: "slack-ops", Type: ProviderSlack, URL: "https://hooks.slack.com/services/a"}, "slack-ops"},
+		{"webhook by host", &core.PublishingTarget{Name: "hook-a", Type: ProviderWebhook, URL: "https://hooks.example.com/a"}, "hooks.example.com"},
+		{"alertmanager by host", &core.PublishingTarget{Name: "am", Type: "alertmanager", URL: "http://am:9093/api/v2/alerts"}, "am:9093"},
+		{"webhook without URL", &core.PublishingTarget{Name: "hook-b", Type: ProviderWebhook}, "hook-b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cooldownKey(tt.target); got != tt.want {
+				t.Errorf("cooldownKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishingQueue_RateLimitPausesProviderAccount(t *testing.T) {
+	queue := newCooldownTestQueue(time.Second)
+	defer queue.cancel()
+
+	rateLimited := (&httperror.HTTPAPIError{
+		StatusCode: http.StatusTooManyRequests,
+		Provider:   ProviderSlack,
+	}).WithRetryAfter(30)
+	before := time.Now()
+	slackA := cooldownTestJob("slack-a", ProviderSlack).Target
+	if until := queue.pauseProviderOnRateLimit(slackA, rateLimited); until.Before(before.Add(30 * time.Second)) {
+		t.Fatalf("pause until %v, want Retry-After of 30s", until)
+	}
+
+	until := queue.cooldowns.pausedUntil("slack-a", time.Now())
+	if until.Before(before.Add(30 * time.Second)) {
+		t.Fatalf("pausedUntil = %v, want Retry-After of 30s", until)
+	}
+	if got := queue.cooldowns.pausedUntil("slack-b", time.Now()); !got.IsZero() {
+		t.Errorf("other Slack target paused until %v", got)
+	}
+
+	rootly := cooldownTestJob("rootly", ProviderRootly).Target
+	if got := queue.pauseProviderOnRateLimit(rootly, &httperror.HTTPAPIError{StatusCode: http.StatusServiceUnavailable}); !got.IsZero() {
+		t.Errorf("503 paused the provider until %v", got)
+	}
+}
+
+func TestPublishingQueue_RateLimitedJobIsRequeued(t *testing.T) {
+	const pause = 100 * time.Millisecond
+	queue := newCooldownTestQueue(pause)
+	queue.maxRetries = 2
+	defer queue.cancel()
+
+	// Without Retry-After the pause lasts one retry interval.
+	limited := &scriptedPublisher{errs: []error{&httperror.HTTPAPIError{
+		StatusCode: http.StatusTooManyRequests,
+		Provider:   ProviderSlack,
+	}}}
+	job := cooldownTestJob("slack-a", ProviderSlack)
+	start := time.Now()
+	err := queue.retryPublish(limited, job)
+	var cooldown *providerCooldownError
+	if !errors.As(err, &cooldown) {
+		t.Fatalf("retryPublish() error = %v, want a provider cooldown", err)
+	}
+	if len(limited.calls) != 1 || job.RetryCount != 1 {
+		t.Errorf("calls = %d, RetryCount = %d; want one attempt before the requeue", len(limited.calls), job.RetryCount)
+	}
+	if time.Since(start) >= pause {
+		t.Errorf("retryPublish held the worker for %v", time.Since(start))
+	}
+
+	// Another job of the account is requeued without an attempt
+	sameAccount := &scriptedPublisher{}
+	if err := queue.retryPublish(sameAccount, cooldownTestJob("slack-a", ProviderSlack)); !errors.As(err, &cooldown) {
+		t.Fatalf("retryPublish() error = %v, want a provider cooldown", err)
+	}
+	if len(sameAccount.calls) != 0 {
+		t.Error("job of the rate-limited account was published")
+	}
+
+	// Other accounts of the provider are published right away
+	otherAccount := &scriptedPublisher{}
+	if err := queue.retryPublish(otherAccount, cooldownTestJob("slack-b", ProviderSlack)); err != nil {
+		t.Fatalf("retryPublish() error = %v", err)
+	}
+
+	// The requeued job is queued again once the pause ends
+	queue.requeueAfter(job, cooldown.until)
+	if status := queue.DrainStatus(); status.InFlight != 1 {
+		t.Errorf("InFlight = %d, want the requeued job", status.InFlight)
+	}
+	select {
+	case requeued := <-queue.highPriorityJobs:
+		if requeued != job {
+			t.Fatal("another job was queued")
+		}
+		if waited := time.Since(start); waited < pause {
+			t.Errorf("job requeued after %v, want at least %v", waited, pause)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job was not requeued")
+	}
+
+	for queue.requeuedJobs.Load() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := queue.retryPublish(limited, job); err != nil {
+		t.Fatalf("retryPublish() error = %v", err)
+	}
+	if len(limited.calls) != 2 {
+		t.Errorf("calls = %d, want the retry after the pause", len(limited.calls))
+	}
+}
+
+func TestPublishingQueue_RateLimitOnLastAttemptFails(t *testing.T) {
+	queue := newCooldownTestQueue(time.Hour)
+	defer queue.cancel()
+
+	limited := &scriptedPublisher{errs: []error{&httperror.HTTPAPIError{
+		StatusCode: http.StatusTooManyRequests,
+		Provider:   ProviderSlack,
+	}}}
+	err := queue.retryPublish(limited, cooldownTestJob("slack-a", ProviderSlack))
+	var cooldown *providerCooldownError
+	if err == nil || errors.As(err, &cooldown) {
+		t.Fatalf("retryPublish() error = %v, want a failure without retries left", err)
+	}
+}
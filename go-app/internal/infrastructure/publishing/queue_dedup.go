@@ -0,0 +1,178 @@
+package publishing
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// targetDedupWindowHeader overrides the publish deduplication window of the
+// queue for a target ("0" disables it). It is never sent.
+const targetDedupWindowHeader = "dedup_window"
+
+const maxDedupWindow = 24 * time.Hour
+
+// ParseTargetDedupWindow reads the dedup_window header of target. ok is
+// false when the target has none.
+func ParseTargetDedupWindow(target *core.PublishingTarget) (window time.Duration, ok bool, err error) {
+	raw, ok := target.Headers[targetDedupWindowHeader]
+	if !ok {
+		return 0, false, nil
+	}
+	window, err = time.ParseDuration(strings.TrimSpace(raw))
+	if err != nil || window < 0 || window > maxDedupWindow {
+		return 0, false, fmt.Errorf("invalid %s %q: must be a duration up to %s", targetDedupWindowHeader, raw, maxDedupWindow)
+	}
+	return window, true, nil
+}
+
+// ValidateTargetDedupWindow checks the dedup_window header of target, if
+// any.
+func ValidateTargetDedupWindow(target *core.PublishingTarget) error {
+	_, _, err := ParseTargetDedupWindow(target)
+	return err
+}
+
+// publishDedup remembers what was last delivered to each target for each
+// alert, so that an alert submitted again unchanged (as Alertmanager does
+// every group_interval) is not published again within the window. A change
+// of status, labels or annotations always goes through.
+//
+// Only delivered jobs are remembered: an alert whose delivery failed is
+// published again when it is submitted again, and DLQ replays go through.
+type publishDedup struct {
+	window time.Duration // default window; targets override it
+
+	mu        sync.Mutex
+	targets   map[string]map[string]dedupEntry // target name -> fingerprint -> last delivery
+	lastSweep time.Time
+}
+
+type dedupEntry struct {
+	content uint64    // alertContentHash of the delivered alert
+	until   time.Time // end of the suppression window
+}
+
+func newPublishDedup(window time.Duration) *publishDedup {
+	return &publishDedup{
+		window:  window,
+		targets: make(map[string]map[string]dedupEntry),
+	}
+}
+
+// windowFor returns the dedup window of target.
+func (d *publishDedup) windowFor(target *core.PublishingTarget) time.Duration {
+	window, ok, err := ParseTargetDedupWindow(target)
+	if err != nil || !ok {
+		// Invalid headers are rejected by discovery validation
+		return d.window
+	}
+	return window
+}
+
+// duplicate reports whether alert was delivered to target with the same
+// status, labels and annotations within the dedup window.
+func (d *publishDedup) duplicate(alert *core.Alert, target *core.PublishingTarget, now time.Time) bool {
+	if d.windowFor(target) <= 0 {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry, ok := d.targets[target.Name][alert.Fingerprint]
+	return ok && now.Before(entry.until) && entry.content == alertContentHash(alert)
+}
+
+// delivered records the alerts of a delivered job.
+func (d *publishDedup) delivered(job *PublishingJob, now time.Time) {
+	window := d.windowFor(job.Target)
+	if window <= 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if now.Sub(d.lastSweep) >= time.Minute {
+		d.sweep(now)
+	}
+	alerts := d.targets[job.Target.Name]
+	if alerts == nil {
+		alerts = make(map[string]dedupEntry)
+		d.targets[job.Target.Name] = alerts
+	}
+	for _, alert := range job.alerts() {
+		alerts[alert.Alert.Fingerprint] = dedupEntry{content: alertContentHash(alert.Alert), until: now.Add(window)}
+	}
+}
+
+// alertContentHash hashes the status, labels and annotations of alert: the
+// content its payloads are formatted from.
+func alertContentHash(alert *core.Alert) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(alert.Status))
+	writeSortedPairs(h, alert.Labels)
+	writeSortedPairs(h, alert.Annotations)
+	return h.Sum64()
+}
+
+// writeSortedPairs writes the pairs of m in key order, separated by zero
+// bytes.
+func writeSortedPairs(h hash.Hash64, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h.Write([]byte{0})
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(m[k]))
+		h.Write([]byte{0})
+	}
+}
+
+// sweep drops the entries whose window elapsed. Called with d.mu held.
+func (d *publishDedup) sweep(now time.Time) {
+	d.lastSweep = now
+	for name, alerts := range d.targets {
+		for fingerprint, entry := range alerts {
+			if !now.Before(entry.until) {
+				delete(alerts, fingerprint)
+			}
+		}
+		if len(alerts) == 0 {
+			delete(d.targets, name)
+		}
+	}
+}
+
+func (d *publishDedup) forget(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.targets, name)
+}
+
+// isDuplicateJob reports whether job only repeats an alert delivered to its
+// target within the dedup window; such jobs are dropped on submission.
+func (q *PublishingQueue) isDuplicateJob(job *PublishingJob) bool {
+	if !q.dedup.duplicate(job.EnrichedAlert.Alert, job.Target, time.Now()) {
+		return false
+	}
+	q.logger.Debug("Suppressed unchanged alert already delivered to target",
+		"target", job.Target.Name,
+		"fingerprint", job.EnrichedAlert.Alert.Fingerprint,
+		"status", job.EnrichedAlert.Alert.Status,
+	)
+	if q.metrics != nil {
+		q.metrics.RecordPublishDeduplicated(job.Target.Name)
+	}
+	q.recordOutcome(job, true) // already delivered
+	return true
+}
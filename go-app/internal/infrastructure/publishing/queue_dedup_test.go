@@ -0,0 +1,118 @@
+package publishing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func newDedupTestQueue(window time.Duration) *PublishingQueue {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	queue.dedup = newPublishDedup(window)
+	return queue
+}
+
+func dedupTestJob(target *core.PublishingTarget, status core.AlertStatus) *PublishingJob {
+	alert := panicTestAlert()
+	alert.Alert.Status = status
+	return &PublishingJob{EnrichedAlert: alert, Target: target}
+}
+
+func TestPublishingQueue_DedupSuppressesUnchangedAlerts(t *testing.T) {
+	queue := newDedupTestQueue(5 * time.Minute)
+	defer queue.cancel()
+	target := &core.PublishingTarget{Name: "dedup", Type: "webhook"}
+
+	queue.dedup.delivered(dedupTestJob(target, core.StatusFiring), time.Now())
+
+	// Re-sent unchanged: suppressed
+	if err := queue.Submit(dedupTestJob(target, core.StatusFiring).EnrichedAlert, target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if size := queue.GetQueueSize(); size != 0 {
+		t.Fatalf("queued %d jobs for an unchanged alert, want 0", size)
+	}
+
+	// Other target: not delivered there yet
+	other := &core.PublishingTarget{Name: "other", Type: "webhook"}
+	if err := queue.Submit(dedupTestJob(other, core.StatusFiring).EnrichedAlert, other); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if size := queue.GetQueueSize(); size != 1 {
+		t.Fatalf("queued %d jobs, want the alert published to the other target", size)
+	}
+
+	// Status change: always published
+	if err := queue.Submit(dedupTestJob(target, core.StatusResolved).EnrichedAlert, target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if size := queue.GetQueueSize(); size != 2 {
+		t.Errorf("queued %d jobs, want the resolved alert published", size)
+	}
+}
+
+func TestPublishDedup_Window(t *testing.T) {
+	dedup := newPublishDedup(time.Minute)
+	target := &core.PublishingTarget{Name: "dedup"}
+	alert := dedupTestJob(target, core.StatusFiring)
+	now := time.Now()
+
+	dedup.delivered(alert, now)
+	if !dedup.duplicate(alert.EnrichedAlert.Alert, target, now.Add(30*time.Second)) {
+		t.Error("alert not suppressed within the window")
+	}
+	if dedup.duplicate(alert.EnrichedAlert.Alert, target, now.Add(time.Minute)) {
+		t.Error("alert suppressed after the window")
+	}
+
+	// The target header overrides the window; 0 disables it
+	disabled := &core.PublishingTarget{Name: "dedup", Headers: map[string]string{"dedup_window": "0"}}
+	if dedup.duplicate(alert.EnrichedAlert.Alert, disabled, now) {
+		t.Error("alert suppressed with dedup_window 0")
+	}
+
+	dedup.sweep(now.Add(time.Hour))
+	if len(dedup.targets) != 0 {
+		t.Errorf("sweep kept %d targets with elapsed entries", len(dedup.targets))
+	}
+
+	if err := ValidateTargetDedupWindow(&core.PublishingTarget{Headers: map[string]string{"dedup_window": "forever"}}); err == nil {
+		t.Error("invalid dedup_window accepted")
+	}
+}
+
+func TestPublishDedup_ContentChange(t *testing.T) {
+	dedup := newPublishDedup(time.Minute)
+	target := &core.PublishingTarget{Name: "dedup"}
+	now := time.Now()
+	dedup.delivered(dedupTestJob(target, core.StatusFiring), now)
+
+	changes := map[string]func(alert *core.Alert){
+		"status":     func(alert *core.Alert) { alert.Status = core.StatusResolved },
+		"label":      func(alert *core.Alert) { alert.Labels["severity"] = "critical" },
+		"annotation": func(alert *core.Alert) { alert.Annotations = map[string]string{"summary": "Disk full"} },
+	}
+	for name, change := range changes {
+		alert := dedupTestJob(target, core.StatusFiring).EnrichedAlert.Alert
+		if !dedup.duplicate(alert, target, now) {
+			t.Fatalf("%s: unchanged alert not suppressed", name)
+		}
+		change(alert)
+		if dedup.duplicate(alert, target, now) {
+			t.Errorf("alert with a changed %s suppressed", name)
+		}
+	}
+}
+
+func TestPublishingQueue_RemoveTargetForgetsDeliveredAlerts(t *testing.T) {
+	queue := newDedupTestQueue(5 * time.Minute)
+	defer queue.cancel()
+	target := &core.PublishingTarget{Name: "dedup", Type: "webhook"}
+
+	queue.dedup.delivered(dedupTestJob(target, core.StatusFiring), time.Now())
+	queue.RemoveTarget(target.Name)
+	if len(queue.dedup.targets) != 0 {
+		t.Error("delivered alerts of a removed target kept")
+	}
+}
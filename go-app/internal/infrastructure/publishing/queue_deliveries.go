@@ -0,0 +1,65 @@
+package publishing
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// DeliveryRecorder stores every publish attempt of an alert, so support can
+// prove whether and when a notification reached its provider.
+type DeliveryRecorder interface {
+	RecordDelivery(fingerprint string, attempt core.DeliveryAttempt)
+}
+
+// JobOutcomeRecorder is told the final outcome of every job, for each of its
+// alerts: delivered (or suppressed as already delivered), or not delivered
+// once its retries are exhausted or it was dropped.
+type JobOutcomeRecorder interface {
+	RecordJobOutcome(fingerprint, target string, delivered bool)
+}
+
+// recordDelivery records one attempt of job for each of its alerts. err is
+// nil for a successful attempt; receipt is nil for jobs not attempted.
+func (q *PublishingQueue) recordDelivery(job *PublishingJob, attempt int, status core.DeliveryStatus, attemptedAt time.Time, latency time.Duration, err error, receipt *deliveryReceipt) {
+	if q.deliveries == nil {
+		return
+	}
+
+	statusCode, payloadHash := receipt.get()
+	record := core.DeliveryAttempt{
+		JobID:       job.ID,
+		Target:      job.Target.Name,
+		TargetType:  job.Target.Type,
+		Attempt:     attempt,
+		Status:      status,
+		AttemptedAt: attemptedAt.UTC(),
+		LatencyMs:   latency.Milliseconds(),
+		StatusCode:  statusCode,
+		PayloadHash: payloadHash,
+	}
+	if err != nil {
+		record.Error = err.Error()
+		record.ErrorType = GetPublishingErrorType(err)
+		var providerErr httperror.ProviderError
+		if errors.As(err, &providerErr) {
+			record.StatusCode = providerErr.HTTPStatus()
+		}
+	}
+	for _, alert := range job.alerts() {
+		record.AlertStatus = alert.Alert.Status
+		q.deliveries.RecordDelivery(alert.Alert.Fingerprint, record)
+	}
+}
+
+// recordOutcome reports the final outcome of job for each of its alerts.
+func (q *PublishingQueue) recordOutcome(job *PublishingJob, delivered bool) {
+	if q.outcomes == nil {
+		return
+	}
+	for _, alert := range job.alerts() {
+		q.outcomes.RecordJobOutcome(alert.Alert.Fingerprint, job.Target.Name, delivered)
+	}
+}
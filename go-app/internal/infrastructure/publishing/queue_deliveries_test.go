@@ -0,0 +1,91 @@
+package publishing
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+type recordingDeliveries struct {
+	attempts map[string][]core.DeliveryAttempt
+}
+
+func (r *recordingDeliveries) RecordDelivery(fingerprint string, attempt core.DeliveryAttempt) {
+	if r.attempts == nil {
+		r.attempts = make(map[string][]core.DeliveryAttempt)
+	}
+	r.attempts[fingerprint] = append(r.attempts[fingerprint], attempt)
+}
+
+func TestPublishingQueue_RecordsDeliveryAttempts(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	queue.maxRetries = 2
+	deliveries := &recordingDeliveries{}
+	queue.deliveries = deliveries
+
+	job := cooldownTestJob("pagerduty-oncall", ProviderPagerDuty)
+	job.ID = "job-1"
+	publisher := &scriptedPublisher{errs: []error{&httperror.HTTPAPIError{
+		StatusCode: http.StatusServiceUnavailable,
+		Provider:   ProviderPagerDuty,
+		Message:    "unavailable",
+	}}}
+	if err := queue.retryPublish(publisher, job); err != nil {
+		t.Fatalf("retryPublish() error = %v", err)
+	}
+
+	got := deliveries.attempts[job.EnrichedAlert.Alert.Fingerprint]
+	if len(got) != 2 {
+		t.Fatalf("recorded %d attempts, want 2: %+v", len(got), got)
+	}
+	failed, succeeded := got[0], got[1]
+	if failed.Attempt != 1 || failed.Status != core.DeliveryStatusFailed || failed.StatusCode != http.StatusServiceUnavailable ||
+		failed.ErrorType == "" || failed.Error == "" {
+		t.Errorf("failed attempt = %+v", failed)
+	}
+	if succeeded.Attempt != 2 || succeeded.Status != core.DeliveryStatusSucceeded || succeeded.StatusCode != 0 || succeeded.Error != "" {
+		t.Errorf("succeeded attempt = %+v", succeeded)
+	}
+	for _, attempt := range got {
+		if attempt.JobID != "job-1" || attempt.Target != "pagerduty-oncall" || attempt.TargetType != ProviderPagerDuty || attempt.AttemptedAt.IsZero() {
+			t.Errorf("attempt = %+v", attempt)
+		}
+	}
+}
+
+type recordingOutcomes struct {
+	outcomes []string
+}
+
+func (r *recordingOutcomes) RecordJobOutcome(fingerprint, target string, delivered bool) {
+	r.outcomes = append(r.outcomes, fmt.Sprintf("%s/%s/%t", fingerprint, target, delivered))
+}
+
+func TestPublishingQueue_RecordsJobOutcomes(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	outcomes := &recordingOutcomes{}
+	queue.outcomes = outcomes
+
+	// Circuit open: not attempted
+	job := cooldownTestJob("pagerduty-oncall", ProviderPagerDuty)
+	for range 5 {
+		queue.getCircuitBreaker(job.Target).RecordFailure()
+	}
+	queue.processJob(job)
+
+	// Removed target: drained
+	queue.removedTargets["slack-ops"] = struct{}{}
+	queue.processJob(cooldownTestJob("slack-ops", ProviderSlack))
+
+	want := []string{"panic-fingerprint/pagerduty-oncall/false", "panic-fingerprint/slack-ops/false"}
+	if !slices.Equal(outcomes.outcomes, want) {
+		t.Fatalf("outcomes = %v, want %v", outcomes.outcomes, want)
+	}
+}
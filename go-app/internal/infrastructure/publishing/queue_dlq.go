@@ -3,12 +3,14 @@ package publishing
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/compression"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -36,13 +38,15 @@ type DLQEntry struct {
 
 // DLQFilters for querying DLQ entries
 type DLQFilters struct {
-	TargetName  string
-	ErrorType   string
-	Priority    string
-	Replayed    *bool
-	FailedAfter *time.Time
-	Limit       int
-	Offset      int
+	ID           uuid.UUID
+	TargetName   string
+	ErrorType    string
+	Priority     string
+	Replayed     *bool
+	FailedAfter  *time.Time
+	FailedBefore *time.Time
+	Limit        int
+	Offset       int
 }
 
 // DLQStats provides statistics about the DLQ
@@ -67,6 +71,9 @@ type DLQRepository interface {
 	// Replay attempts to replay a specific DLQ entry
 	Replay(ctx context.Context, id uuid.UUID) error
 
+	// MarkReplayed records the result of a replay of an entry
+	MarkReplayed(ctx context.Context, id uuid.UUID, result string) error
+
 	// Purge removes entries older than specified duration
 	Purge(ctx context.Context, olderThan time.Duration) (int64, error)
 
@@ -101,11 +108,12 @@ func (r *PostgreSQLDLQRepository) SetQueue(queue *PublishingQueue) {
 
 // Write adds a failed job to the DLQ
 func (r *PostgreSQLDLQRepository) Write(ctx context.Context, job *PublishingJob) error {
-	// Serialize EnrichedAlert to JSONB
+	// Serialize EnrichedAlert to JSONB (large alerts are stored zstd-compressed)
 	enrichedAlertJSON, err := json.Marshal(job.EnrichedAlert)
 	if err != nil {
 		return fmt.Errorf("failed to marshal enriched alert: %w", err)
 	}
+	enrichedAlertJSON = compression.CompressJSON(enrichedAlertJSON, compression.DefaultThreshold)
 
 	// Serialize Target to JSONB
 	targetConfigJSON, err := json.Marshal(job.Target)
@@ -119,6 +127,12 @@ func (r *PostgreSQLDLQRepository) Write(ctx context.Context, job *PublishingJob)
 		errorMessage = job.LastError.Error()
 	}
 
+	// Poisoned jobs keep the panic stack for post-mortem analysis
+	var panicErr *JobPanicError
+	if errors.As(job.LastError, &panicErr) {
+		errorMessage += "\n" + string(panicErr.Stack)
+	}
+
 	// Insert into database
 	query := `
 		INSERT INTO publishing_dlq (
@@ -184,6 +198,12 @@ func (r *PostgreSQLDLQRepository) Read(ctx context.Context, filters DLQFilters)
 	argCount := 1
 
 	// Apply filters
+	if filters.ID != uuid.Nil {
+		query += fmt.Sprintf(" AND id = $%d", argCount)
+		args = append(args, filters.ID)
+		argCount++
+	}
+
 	if filters.TargetName != "" {
 		query += fmt.Sprintf(" AND target_name = $%d", argCount)
 		args = append(args, filters.TargetName)
@@ -214,6 +234,12 @@ func (r *PostgreSQLDLQRepository) Read(ctx context.Context, filters DLQFilters)
 		argCount++
 	}
 
+	if filters.FailedBefore != nil {
+		query += fmt.Sprintf(" AND failed_at < $%d", argCount)
+		args = append(args, *filters.FailedBefore)
+		argCount++
+	}
+
 	// Order by failed_at DESC
 	query += " ORDER BY failed_at DESC"
 
@@ -273,6 +299,10 @@ func (r *PostgreSQLDLQRepository) Read(ctx context.Context, filters DLQFilters)
 		}
 
 		// Unmarshal JSON fields
+		enrichedAlertJSON, err = compression.DecompressJSON(enrichedAlertJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress enriched_alert: %w", err)
+		}
 		if err := json.Unmarshal(enrichedAlertJSON, &entry.EnrichedAlert); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal enriched_alert: %w", err)
 		}
@@ -290,7 +320,7 @@ func (r *PostgreSQLDLQRepository) Read(ctx context.Context, filters DLQFilters)
 // Replay attempts to replay a specific DLQ entry
 func (r *PostgreSQLDLQRepository) Replay(ctx context.Context, id uuid.UUID) error {
 	// Fetch entry
-	entries, err := r.Read(ctx, DLQFilters{Limit: 1})
+	entries, err := r.Read(ctx, DLQFilters{ID: id, Limit: 1})
 	if err != nil {
 		return fmt.Errorf("failed to read DLQ entry: %w", err)
 	}
@@ -316,14 +346,7 @@ func (r *PostgreSQLDLQRepository) Replay(ctx context.Context, id uuid.UUID) erro
 		replayResult = "failed"
 	}
 
-	updateQuery := `
-		UPDATE publishing_dlq
-		SET replayed = TRUE, replayed_at = NOW(), replay_result = $1
-		WHERE id = $2
-	`
-
-	_, updateErr := r.db.Exec(ctx, updateQuery, replayResult, id)
-	if updateErr != nil {
+	if updateErr := r.MarkReplayed(ctx, id, replayResult); updateErr != nil {
 		r.logger.Error("Failed to update DLQ replay status", "error", updateErr)
 	}
 
@@ -336,6 +359,19 @@ func (r *PostgreSQLDLQRepository) Replay(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+// MarkReplayed records the result of a replay of an entry
+func (r *PostgreSQLDLQRepository) MarkReplayed(ctx context.Context, id uuid.UUID, result string) error {
+	query := `
+		UPDATE publishing_dlq
+		SET replayed = TRUE, replayed_at = NOW(), replay_result = $1
+		WHERE id = $2
+	`
+	if _, err := r.db.Exec(ctx, query, result, id); err != nil {
+		return fmt.Errorf("failed to mark DLQ entry replayed: %w", err)
+	}
+	return nil
+}
+
 // Purge removes entries older than specified duration
 func (r *PostgreSQLDLQRepository) Purge(ctx context.Context, olderThan time.Duration) (int64, error) {
 	cutoffTime := time.Now().Add(-olderThan)
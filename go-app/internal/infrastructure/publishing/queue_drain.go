@@ -0,0 +1,97 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrQueueDraining is returned by Submit once the queue drains.
+var ErrQueueDraining = errors.New("publishing queue is draining")
+
+// ErrQueuePaused is returned by Drain while the queue is paused for
+// maintenance: its held jobs would never be published.
+var ErrQueuePaused = errors.New("publishing queue is paused")
+
+const drainPollInterval = 50 * time.Millisecond
+
+// DrainStatus reports the jobs not yet published by a queue.
+type DrainStatus struct {
+	// Draining is set once Drain was called.
+	Draining bool `json:"draining"`
+	// Drained is set when no job is queued or in flight.
+	Drained bool `json:"drained"`
+	// Queued jobs wait for a worker.
+	Queued int `json:"queued"`
+	// InFlight jobs are being published or retried.
+	InFlight int `json:"in_flight"`
+	// Scheduled jobs are held until the notify_after or repeat_interval of
+	// their target. They are not waited for: a durable queue keeps them
+	// stored, others lose them on Stop.
+	Scheduled int `json:"scheduled"`
+}
+
+// Drain prepares the queue for a shutdown, e.g. a rolling deployment: it
+// stops accepting new jobs (Submit returns ErrQueueDraining), flushes the
+// open batches and waits until the queued and in-flight jobs are processed,
+// timeout elapses or ctx is done. Retries and fallbacks of the jobs in
+// flight are still queued. The queue drains until it is stopped; Drain may
+// be called again to keep waiting.
+//
+// It returns the jobs left, so that callers know what Stop would drop.
+func (q *PublishingQueue) Drain(ctx context.Context, timeout time.Duration) (DrainStatus, error) {
+	if q.IsPaused() {
+		return q.DrainStatus(), ErrQueuePaused
+	}
+	if !q.draining.Swap(true) {
+		q.logger.Info("Draining publishing queue",
+			"queued_jobs", q.GetQueueSize(),
+			"pending_jobs", q.pendingJobs.Load(),
+			"timeout", timeout,
+		)
+	}
+
+	// Open batches are enqueued now; later alerts of batched targets are
+	// enqueued as is
+	q.batcher.close()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		status := q.DrainStatus()
+		if status.Drained {
+			q.logger.Info("Publishing queue drained", "scheduled_jobs", status.Scheduled)
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return q.DrainStatus(), nil
+		case <-deadline.C:
+			status = q.DrainStatus()
+			if !status.Drained {
+				q.logger.Warn("Publishing queue not drained before the timeout",
+					"timeout", timeout,
+					"queued_jobs", status.Queued,
+					"in_flight_jobs", status.InFlight,
+				)
+			}
+			return status, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// DrainStatus returns the jobs not yet published.
+func (q *PublishingQueue) DrainStatus() DrainStatus {
+	pending := int(q.pendingJobs.Load() + q.requeuedJobs.Load())
+	queued := q.GetQueueSize()
+	return DrainStatus{
+		Draining:  q.draining.Load(),
+		Drained:   pending == 0,
+		Queued:    queued,
+		InFlight:  max(pending-queued, 0),
+		Scheduled: q.ScheduledJobs(),
+	}
+}
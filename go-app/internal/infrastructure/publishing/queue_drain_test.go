@@ -0,0 +1,47 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPublishingQueue_Drain(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.Stop(time.Second)
+
+	job := cooldownTestJob("webhook", ProviderWebhook)
+	if err := queue.Submit(job.EnrichedAlert, job.Target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// Without workers the job is left at the timeout
+	status, err := queue.Drain(context.Background(), 100*time.Millisecond)
+	if err != nil || status.Drained || !status.Draining || status.Queued != 1 || status.InFlight != 0 {
+		t.Fatalf("Drain() without workers = %+v, %v", status, err)
+	}
+	if err := queue.Submit(job.EnrichedAlert, job.Target); !errors.Is(err, ErrQueueDraining) {
+		t.Fatalf("Submit() while draining error = %v, want ErrQueueDraining", err)
+	}
+
+	queue.Start()
+	status, err = queue.Drain(context.Background(), 2*time.Second)
+	if err != nil || !status.Drained || status.Queued != 0 || status.InFlight != 0 {
+		t.Fatalf("Drain() = %+v, %v", status, err)
+	}
+}
+
+func TestPublishingQueue_DrainWhilePaused(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	queue.Pause()
+	queue.Start()
+	defer queue.Stop(time.Second)
+
+	if _, err := queue.Drain(context.Background(), time.Second); !errors.Is(err, ErrQueuePaused) {
+		t.Fatalf("Drain() while paused error = %v, want ErrQueuePaused", err)
+	}
+	if queue.DrainStatus().Draining {
+		t.Error("paused queue drains")
+	}
+}
@@ -0,0 +1,282 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// DefaultVisibilityTimeout is how long a durable job stays leased to the
+// replica holding it without a renewal.
+const DefaultVisibilityTimeout = 5 * time.Minute
+
+// DurableQueueStore persists the jobs of the publishing queue so they
+// survive restarts and crashes.
+//
+// Every stored job is leased to the replica (owner) holding it in memory
+// until its lease expires; the owner renews the leases of the jobs it holds
+// and acknowledges them once processed. Jobs whose lease expired were lost by
+// a crashed replica and are claimed by another one: delivery is at least
+// once.
+type DurableQueueStore interface {
+	// Save stores job, leased to owner until leaseUntil.
+	Save(ctx context.Context, job *PublishingJob, owner string, leaseUntil time.Time) error
+
+	// Extend renews the leases of the jobs ids still leased to owner.
+	Extend(ctx context.Context, owner string, ids []string, leaseUntil time.Time) error
+
+	// Ack removes a processed job.
+	Ack(ctx context.Context, id string) error
+
+	// Claim leases to owner until leaseUntil at most limit jobs whose lease
+	// expired, and also those still leased to owner when reclaimOwned is
+	// set (startup recovery of a restarted replica), and returns them.
+	Claim(ctx context.Context, owner string, leaseUntil time.Time, limit int, reclaimOwned bool) ([]*PublishingJob, error)
+}
+
+// DurableQueueConfig makes the publishing queue durable.
+type DurableQueueConfig struct {
+	// Store persists the queued jobs (optional, in memory only when nil).
+	Store DurableQueueStore
+
+	// Owner identifies this replica in the store (default: hostname). A
+	// replica restarted with the same owner recovers its jobs right away;
+	// jobs of other replicas are recovered once their lease expires.
+	Owner string
+
+	// VisibilityTimeout is how long the jobs of a crashed replica stay
+	// leased before another replica (or its restart) claims them (default:
+	// DefaultVisibilityTimeout).
+	VisibilityTimeout time.Duration
+}
+
+// durableJobs tracks the durable jobs held by the queue.
+type durableJobs struct {
+	store      DurableQueueStore
+	owner      string
+	visibility time.Duration
+
+	mu   sync.Mutex
+	held map[string]struct{}
+
+	stop chan struct{}
+	wg   sync.WaitGroup // lease renewal loop
+}
+
+func newDurableJobs(config DurableQueueConfig) *durableJobs {
+	if config.Store == nil {
+		return nil
+	}
+	if config.Owner == "" {
+		config.Owner, _ = os.Hostname()
+	}
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	return &durableJobs{
+		store:      config.Store,
+		owner:      config.Owner,
+		visibility: config.VisibilityTimeout,
+		held:       make(map[string]struct{}),
+		stop:       make(chan struct{}),
+	}
+}
+
+// hold records id as held; reports false when it already was.
+func (d *durableJobs) hold(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, held := d.held[id]; held {
+		return false
+	}
+	d.held[id] = struct{}{}
+	return true
+}
+
+// release forgets id; reports whether it was held.
+func (d *durableJobs) release(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, held := d.held[id]
+	delete(d.held, id)
+	return held
+}
+
+func (d *durableJobs) heldIDs() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ids := make([]string, 0, len(d.held))
+	for id := range d.held {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// persistJob stores job before it is queued. A job that cannot be stored is
+// still queued, in memory only.
+func (q *PublishingQueue) persistJob(job *PublishingJob) {
+	if q.durable == nil {
+		return
+	}
+	if err := q.durable.store.Save(q.ctx, job, q.durable.owner, time.Now().Add(q.durable.visibility)); err != nil {
+		q.logger.Error("Failed to persist publishing job, queued in memory only",
+			"job_id", job.ID,
+			"target", job.Target.Name,
+			"error", err,
+		)
+		return
+	}
+	q.durable.hold(job.ID)
+}
+
+// ackJob removes a processed (or rejected) job from the store.
+func (q *PublishingQueue) ackJob(job *PublishingJob) {
+	if q.durable == nil || !q.durable.release(job.ID) {
+		return
+	}
+	// The queue context is canceled on forced stop; still acknowledge
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(q.ctx), 5*time.Second)
+	defer cancel()
+	if err := q.durable.store.Ack(ctx, job.ID); err != nil {
+		q.logger.Warn("Failed to acknowledge publishing job, it will be delivered again",
+			"job_id", job.ID,
+			"target", job.Target.Name,
+			"error", err,
+		)
+	}
+}
+
+// recoverJobs claims stored jobs (those left behind by this replica too when
+// reclaimOwned is set) up to the free queue capacity and queues them.
+// Returns the number of jobs queued.
+func (q *PublishingQueue) recoverJobs(reclaimOwned bool) int {
+	free := q.GetQueueCapacity() - q.GetQueueSize()
+	if q.durable == nil || free <= 0 {
+		return 0
+	}
+	jobs, err := q.durable.store.Claim(q.ctx, q.durable.owner, time.Now().Add(q.durable.visibility), free, reclaimOwned)
+	if err != nil {
+		q.logger.Error("Failed to claim stored publishing jobs", "error", err)
+		return 0
+	}
+
+	recovered := 0
+	for _, job := range jobs {
+		// Still queued here: its lease lapsed while renewals failed
+		if !q.durable.hold(job.ID) {
+			continue
+		}
+		job.State = JobStateQueued
+		if !job.NotBefore.IsZero() {
+			q.restoreScheduled(job)
+			recovered++
+			continue
+		}
+		if err := q.send(job); err != nil {
+			// Left leased: claimed again once the lease expires
+			q.durable.release(job.ID)
+			q.logger.Warn("Failed to queue recovered publishing job",
+				"job_id", job.ID,
+				"target", job.Target.Name,
+				"error", err,
+			)
+			continue
+		}
+		recovered++
+	}
+	if recovered > 0 {
+		q.logger.Info("Recovered stored publishing jobs", "jobs", recovered, "startup", reclaimOwned)
+		if q.metrics != nil {
+			q.metrics.RecordQueueRecovered(recovered)
+		}
+	}
+	return recovered
+}
+
+// renewLeases keeps the jobs held by the queue leased and claims the jobs
+// of crashed replicas, every third of the visibility timeout.
+func (q *PublishingQueue) renewLeases() {
+	defer q.durable.wg.Done()
+
+	ticker := time.NewTicker(q.durable.visibility / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.durable.stop:
+			return
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if ids := q.durable.heldIDs(); len(ids) > 0 {
+			if err := q.durable.store.Extend(q.ctx, q.durable.owner, ids, time.Now().Add(q.durable.visibility)); err != nil {
+				q.logger.Warn("Failed to renew publishing job leases", "jobs", len(ids), "error", err)
+			}
+		}
+		q.recoverJobs(false)
+	}
+}
+
+// durableJobRecord is the stored form of a job.
+type durableJobRecord struct {
+	ID          string                 `json:"id"`
+	Target      *core.PublishingTarget `json:"target"`
+	Alerts      []*core.EnrichedAlert  `json:"alerts"`
+	Priority    Priority               `json:"priority"`
+	SubmittedAt time.Time              `json:"submitted_at"`
+	NotBefore   time.Time              `json:"not_before"`
+	Renotify    bool                   `json:"renotify,omitempty"`
+	FallbackFor string                 `json:"fallback_for,omitempty"`
+	Fallbacks   []string               `json:"fallbacks,omitempty"`
+}
+
+func marshalDurableJob(job *PublishingJob) ([]byte, error) {
+	data, err := json.Marshal(durableJobRecord{
+		ID:          job.ID,
+		Target:      job.Target,
+		Alerts:      job.alerts(),
+		Priority:    job.Priority,
+		SubmittedAt: job.SubmittedAt,
+		NotBefore:   job.NotBefore,
+		Renotify:    job.Renotify,
+		FallbackFor: job.FallbackFor,
+		Fallbacks:   job.Fallbacks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal publishing job: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalDurableJob(data []byte) (*PublishingJob, error) {
+	var record durableJobRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal publishing job: %w", err)
+	}
+	if record.ID == "" || record.Target == nil || len(record.Alerts) == 0 {
+		return nil, fmt.Errorf("incomplete publishing job %q", record.ID)
+	}
+
+	job := &PublishingJob{
+		ID:            record.ID,
+		EnrichedAlert: record.Alerts[0],
+		Target:        record.Target,
+		Priority:      record.Priority,
+		SubmittedAt:   record.SubmittedAt,
+		NotBefore:     record.NotBefore,
+		Renotify:      record.Renotify,
+		FallbackFor:   record.FallbackFor,
+		Fallbacks:     record.Fallbacks,
+		State:         JobStateQueued,
+	}
+	if len(record.Alerts) > 1 {
+		job.Batch = record.Alerts
+	}
+	return job, nil
+}
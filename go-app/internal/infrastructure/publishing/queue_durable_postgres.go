@@ -0,0 +1,115 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgreSQLDurableQueueStore stores the publishing queue in the
+// publishing_queue_jobs table.
+type PostgreSQLDurableQueueStore struct {
+	db     *pgxpool.Pool
+	logger *slog.Logger
+}
+
+// NewPostgreSQLDurableQueueStore creates a durable queue store on db.
+func NewPostgreSQLDurableQueueStore(db *pgxpool.Pool, logger *slog.Logger) *PostgreSQLDurableQueueStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PostgreSQLDurableQueueStore{db: db, logger: logger}
+}
+
+// Save stores job, leased to owner until leaseUntil.
+func (s *PostgreSQLDurableQueueStore) Save(ctx context.Context, job *PublishingJob, owner string, leaseUntil time.Time) error {
+	data, err := marshalDurableJob(job)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO publishing_queue_jobs (id, owner, lease_until, target_name, priority, job)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET owner = EXCLUDED.owner, lease_until = EXCLUDED.lease_until, job = EXCLUDED.job
+	`
+	if _, err := s.db.Exec(ctx, query, job.ID, owner, leaseUntil, job.Target.Name, job.Priority.String(), data); err != nil {
+		return fmt.Errorf("failed to store publishing job: %w", err)
+	}
+	return nil
+}
+
+// Extend renews the leases of the jobs ids still leased to owner.
+func (s *PostgreSQLDurableQueueStore) Extend(ctx context.Context, owner string, ids []string, leaseUntil time.Time) error {
+	query := `
+		UPDATE publishing_queue_jobs
+		SET lease_until = $3
+		WHERE owner = $1 AND id = ANY($2)
+	`
+	if _, err := s.db.Exec(ctx, query, owner, ids, leaseUntil); err != nil {
+		return fmt.Errorf("failed to renew publishing job leases: %w", err)
+	}
+	return nil
+}
+
+// Ack removes a processed job.
+func (s *PostgreSQLDurableQueueStore) Ack(ctx context.Context, id string) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM publishing_queue_jobs WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to remove publishing job: %w", err)
+	}
+	return nil
+}
+
+// Claim leases to owner the jobs whose lease expired (and owner's own jobs
+// when reclaimOwned is set), oldest first. Concurrent claims of several
+// replicas never return the same job.
+func (s *PostgreSQLDurableQueueStore) Claim(ctx context.Context, owner string, leaseUntil time.Time, limit int, reclaimOwned bool) ([]*PublishingJob, error) {
+	query := `
+		UPDATE publishing_queue_jobs
+		SET owner = $1, lease_until = $2
+		WHERE id IN (
+			SELECT id FROM publishing_queue_jobs
+			WHERE lease_until < NOW() OR ($4 AND owner = $1)
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, job
+	`
+	rows, err := s.db.Query(ctx, query, owner, leaseUntil, limit, reclaimOwned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim publishing jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*PublishingJob
+	var corrupt []string
+	for rows.Next() {
+		var id string
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan publishing job: %w", err)
+		}
+		job, err := unmarshalDurableJob(data)
+		if err != nil {
+			s.logger.Error("Dropping unreadable stored publishing job", "job_id", id, "error", err)
+			corrupt = append(corrupt, id)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read publishing jobs: %w", err)
+	}
+
+	for _, id := range corrupt {
+		if err := s.Ack(ctx, id); err != nil {
+			s.logger.Warn("Failed to drop unreadable publishing job", "job_id", id, "error", err)
+		}
+	}
+	return jobs, nil
+}
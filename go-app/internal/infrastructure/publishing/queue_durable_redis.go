@@ -0,0 +1,177 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ipiton/AMP/internal/infrastructure/cache"
+)
+
+// RedisDurableQueueStore stores the publishing queue in Redis: the jobs in
+// the <prefix>:jobs hash, their lease deadlines (unix ms) in the
+// <prefix>:leases sorted set and their owners in the <prefix>:owners hash.
+type RedisDurableQueueStore struct {
+	client    *redis.Client
+	jobsKey   string
+	leasesKey string
+	ownersKey string
+	logger    *slog.Logger
+}
+
+// NewRedisDurableQueueStore creates a durable queue store on the Redis
+// client of redisCache, which must be a *cache.RedisCache.
+func NewRedisDurableQueueStore(redisCache cache.Cache, keyPrefix string, logger *slog.Logger) (*RedisDurableQueueStore, error) {
+	concreteCache, ok := redisCache.(*cache.RedisCache)
+	if !ok {
+		return nil, fmt.Errorf("durable publishing queue requires *cache.RedisCache, got %T", redisCache)
+	}
+	if keyPrefix == "" {
+		return nil, fmt.Errorf("key prefix is required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return newRedisDurableQueueStore(concreteCache.GetClient(), keyPrefix, logger), nil
+}
+
+func newRedisDurableQueueStore(client *redis.Client, keyPrefix string, logger *slog.Logger) *RedisDurableQueueStore {
+	return &RedisDurableQueueStore{
+		client:    client,
+		jobsKey:   keyPrefix + ":jobs",
+		leasesKey: keyPrefix + ":leases",
+		ownersKey: keyPrefix + ":owners",
+		logger:    logger,
+	}
+}
+
+// Save stores job, leased to owner until leaseUntil.
+func (s *RedisDurableQueueStore) Save(ctx context.Context, job *PublishingJob, owner string, leaseUntil time.Time) error {
+	data, err := marshalDurableJob(job)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.jobsKey, job.ID, data)
+		pipe.HSet(ctx, s.ownersKey, job.ID, owner)
+		pipe.ZAdd(ctx, s.leasesKey, redis.Z{Score: float64(leaseUntil.UnixMilli()), Member: job.ID})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store publishing job: %w", err)
+	}
+	return nil
+}
+
+// extendLeasesScript renews the leases of the jobs ARGV[3:] owned by
+// ARGV[1] to ARGV[2].
+var extendLeasesScript = redis.NewScript(`
+for i = 3, #ARGV do
+	if redis.call('HGET', KEYS[2], ARGV[i]) == ARGV[1] then
+		redis.call('ZADD', KEYS[1], 'XX', ARGV[2], ARGV[i])
+	end
+end
+return 0
+`)
+
+// Extend renews the leases of the jobs ids still leased to owner.
+func (s *RedisDurableQueueStore) Extend(ctx context.Context, owner string, ids []string, leaseUntil time.Time) error {
+	args := make([]any, 0, len(ids)+2)
+	args = append(args, owner, leaseUntil.UnixMilli())
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	if err := extendLeasesScript.Run(ctx, s.client, []string{s.leasesKey, s.ownersKey}, args...).Err(); err != nil {
+		return fmt.Errorf("failed to renew publishing job leases: %w", err)
+	}
+	return nil
+}
+
+// Ack removes a processed job.
+func (s *RedisDurableQueueStore) Ack(ctx context.Context, id string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, s.jobsKey, id)
+		pipe.HDel(ctx, s.ownersKey, id)
+		pipe.ZRem(ctx, s.leasesKey, id)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove publishing job: %w", err)
+	}
+	return nil
+}
+
+// claimJobsScript leases to ARGV[1] until ARGV[3] at most ARGV[4] jobs whose
+// lease expired by ARGV[2], and those of ARGV[1] when ARGV[5] is "1".
+// Returns id, job pairs.
+var claimJobsScript = redis.NewScript(`
+local owner, limit = ARGV[1], tonumber(ARGV[4])
+local candidates = {}
+if ARGV[5] == '1' then
+	local owners = redis.call('HGETALL', KEYS[3])
+	for i = 1, #owners, 2 do
+		if owners[i + 1] == owner then
+			table.insert(candidates, owners[i])
+		end
+	end
+end
+for _, id in ipairs(redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', '(' .. ARGV[2])) do
+	table.insert(candidates, id)
+end
+
+local claimed, seen = {}, {}
+for _, id in ipairs(candidates) do
+	if #claimed >= 2 * limit then
+		break
+	end
+	if not seen[id] then
+		seen[id] = true
+		local job = redis.call('HGET', KEYS[1], id)
+		if job then
+			redis.call('ZADD', KEYS[2], ARGV[3], id)
+			redis.call('HSET', KEYS[3], id, owner)
+			table.insert(claimed, id)
+			table.insert(claimed, job)
+		else
+			redis.call('ZREM', KEYS[2], id)
+			redis.call('HDEL', KEYS[3], id)
+		end
+	end
+end
+return claimed
+`)
+
+// Claim leases to owner the jobs whose lease expired (and owner's own jobs
+// when reclaimOwned is set). The claim is atomic: concurrent claims of
+// several replicas never return the same job.
+func (s *RedisDurableQueueStore) Claim(ctx context.Context, owner string, leaseUntil time.Time, limit int, reclaimOwned bool) ([]*PublishingJob, error) {
+	reclaim := "0"
+	if reclaimOwned {
+		reclaim = "1"
+	}
+	result, err := claimJobsScript.Run(ctx, s.client,
+		[]string{s.jobsKey, s.leasesKey, s.ownersKey},
+		owner, time.Now().UnixMilli(), leaseUntil.UnixMilli(), limit, reclaim,
+	).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim publishing jobs: %w", err)
+	}
+
+	jobs := make([]*PublishingJob, 0, len(result)/2)
+	for i := 0; i+1 < len(result); i += 2 {
+		id, data := result[i], result[i+1]
+		job, err := unmarshalDurableJob([]byte(data))
+		if err != nil {
+			s.logger.Error("Dropping unreadable stored publishing job", "job_id", id, "error", err)
+			if err := s.Ack(ctx, id); err != nil {
+				s.logger.Warn("Failed to drop unreadable publishing job", "job_id", id, "error", err)
+			}
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,262 @@
+package publishing
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+type storedJob struct {
+	job        *PublishingJob
+	owner      string
+	leaseUntil time.Time
+}
+
+// memoryDurableStore is an in-memory DurableQueueStore.
+type memoryDurableStore struct {
+	mu   sync.Mutex
+	jobs map[string]*storedJob
+}
+
+func newMemoryDurableStore() *memoryDurableStore {
+	return &memoryDurableStore{jobs: make(map[string]*storedJob)}
+}
+
+func (s *memoryDurableStore) Save(ctx context.Context, job *PublishingJob, owner string, leaseUntil time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = &storedJob{job: job, owner: owner, leaseUntil: leaseUntil}
+	return nil
+}
+
+func (s *memoryDurableStore) Extend(ctx context.Context, owner string, ids []string, leaseUntil time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if stored, ok := s.jobs[id]; ok && stored.owner == owner {
+			stored.leaseUntil = leaseUntil
+		}
+	}
+	return nil
+}
+
+func (s *memoryDurableStore) Ack(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *memoryDurableStore) Claim(ctx context.Context, owner string, leaseUntil time.Time, limit int, reclaimOwned bool) ([]*PublishingJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var jobs []*PublishingJob
+	for _, stored := range s.jobs {
+		if len(jobs) == limit {
+			break
+		}
+		if stored.leaseUntil.Before(time.Now()) || (reclaimOwned && stored.owner == owner) {
+			stored.owner, stored.leaseUntil = owner, leaseUntil
+			jobs = append(jobs, stored.job)
+		}
+	}
+	return jobs, nil
+}
+
+func (s *memoryDurableStore) ids() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.jobs))
+	for id := range s.jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func newDurableTestQueue(store DurableQueueStore, owner string) *PublishingQueue {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	queue.durable = newDurableJobs(DurableQueueConfig{Store: store, Owner: owner, VisibilityTimeout: time.Minute})
+	return queue
+}
+
+func durableTestJob(id string) *PublishingJob {
+	return &PublishingJob{
+		ID:            id,
+		EnrichedAlert: panicTestAlert(),
+		Target:        &core.PublishingTarget{Name: "durable", Type: "webhook", URL: "http://127.0.0.1:1"},
+		Priority:      PriorityMedium,
+		SubmittedAt:   time.Now().UTC(),
+	}
+}
+
+func TestPublishingQueue_PersistsJobsUntilProcessed(t *testing.T) {
+	store := newMemoryDurableStore()
+	queue := newDurableTestQueue(store, "replica-a")
+
+	target := &core.PublishingTarget{Name: "durable", Type: "webhook", URL: "http://127.0.0.1:1"}
+	if err := queue.Submit(panicTestAlert(), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	ids := store.ids()
+	if len(ids) != 1 || store.jobs[ids[0]].owner != "replica-a" {
+		t.Fatalf("stored jobs = %v, want the submitted job leased to replica-a", ids)
+	}
+
+	queue.Start()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(store.ids()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("processed job was not acknowledged")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := queue.Stop(time.Second); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+}
+
+func TestPublishingQueue_RecoversStoredJobs(t *testing.T) {
+	store := newMemoryDurableStore()
+	future, past := time.Now().Add(time.Hour), time.Now().Add(-time.Second)
+	_ = store.Save(context.Background(), durableTestJob("own"), "replica-a", future)
+	_ = store.Save(context.Background(), durableTestJob("other"), "replica-b", future)
+	_ = store.Save(context.Background(), durableTestJob("crashed"), "replica-c", past)
+
+	queue := newDurableTestQueue(store, "replica-a")
+	defer queue.cancel()
+
+	if got := queue.recoverJobs(true); got != 2 {
+		t.Fatalf("recoverJobs(true) = %d, want own and expired jobs", got)
+	}
+	var recovered []string
+	for range 2 {
+		recovered = append(recovered, (<-queue.mediumPriorityJobs).ID)
+	}
+	sort.Strings(recovered)
+	if recovered[0] != "crashed" || recovered[1] != "own" {
+		t.Errorf("recovered jobs = %v", recovered)
+	}
+	if owner := store.jobs["crashed"].owner; owner != "replica-a" {
+		t.Errorf("crashed job owner = %q, want replica-a", owner)
+	}
+
+	// Jobs already held are not queued twice
+	store.jobs["own"].leaseUntil = past
+	if got := queue.recoverJobs(false); got != 0 {
+		t.Errorf("recoverJobs(false) = %d, want 0 for held jobs", got)
+	}
+}
+
+func TestPublishingQueue_AcksJobsRejectedByFullQueue(t *testing.T) {
+	store := newMemoryDurableStore()
+	queue := newDurableTestQueue(store, "replica-a")
+	defer queue.cancel()
+
+	target := &core.PublishingTarget{Name: "durable", Type: "webhook", URL: "http://127.0.0.1:1"}
+	for range queue.GetQueueCapacity() {
+		_ = queue.Submit(panicTestAlert(), target)
+	}
+	stored := len(store.ids())
+	if err := queue.Submit(panicTestAlert(), target); err == nil {
+		t.Fatal("Submit() to a full queue succeeded")
+	}
+	if got := len(store.ids()); got != stored {
+		t.Errorf("stored jobs = %d after a rejected submit, want %d", got, stored)
+	}
+}
+
+func TestDurableJobRoundTrip(t *testing.T) {
+	job := durableTestJob("batch")
+	job.Batch = []*core.EnrichedAlert{panicTestAlert(), batchTestAlert("fp-2", "critical")}
+	job.EnrichedAlert = job.Batch[0]
+	job.Priority = PriorityHigh
+
+	data, err := marshalDurableJob(job)
+	if err != nil {
+		t.Fatalf("marshalDurableJob() error = %v", err)
+	}
+	got, err := unmarshalDurableJob(data)
+	if err != nil {
+		t.Fatalf("unmarshalDurableJob() error = %v", err)
+	}
+	if got.ID != job.ID || got.Priority != PriorityHigh || got.Target.Name != "durable" || got.State != JobStateQueued {
+		t.Errorf("job = %+v", got)
+	}
+	if len(got.Batch) != 2 || got.EnrichedAlert != got.Batch[0] || got.Batch[1].Alert.Fingerprint != "fp-2" {
+		t.Errorf("batch = %+v", got.Batch)
+	}
+
+	if _, err := unmarshalDurableJob([]byte(`{"id":"x"}`)); err == nil {
+		t.Error("unmarshalDurableJob() accepted a job without target and alerts")
+	}
+}
+
+func TestRedisDurableQueueStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	store := newRedisDurableQueueStore(client, "amp:publishing_queue", slog.Default())
+	ctx := context.Background()
+
+	future := time.Now().Add(time.Hour)
+	for _, id := range []string{"job-1", "job-2"} {
+		if err := store.Save(ctx, durableTestJob(id), "replica-a", future); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := store.Save(ctx, durableTestJob("job-3"), "replica-b", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Another replica only claims expired jobs
+	jobs, err := store.Claim(ctx, "replica-c", future, 10, false)
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "job-3" || jobs[0].Target.Name != "durable" {
+		t.Fatalf("claimed = %+v, want the expired job-3", jobs)
+	}
+	if jobs, _ := store.Claim(ctx, "replica-d", future, 10, false); len(jobs) != 0 {
+		t.Errorf("claimed %d jobs already leased", len(jobs))
+	}
+
+	// A restarted replica reclaims its own jobs
+	jobs, err = store.Claim(ctx, "replica-a", future, 10, true)
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("reclaimed %d jobs, want 2", len(jobs))
+	}
+
+	// Only the owner renews a lease
+	past := time.Now().Add(-time.Second)
+	if err := store.Extend(ctx, "replica-c", []string{"job-1", "job-3"}, past); err != nil {
+		t.Fatalf("Extend() error = %v", err)
+	}
+	jobs, _ = store.Claim(ctx, "replica-e", future, 10, false)
+	if len(jobs) != 1 || jobs[0].ID != "job-3" {
+		t.Fatalf("claimed = %+v, want only job-3", jobs)
+	}
+
+	for _, id := range []string{"job-1", "job-2", "job-3"} {
+		if err := store.Ack(ctx, id); err != nil {
+			t.Fatalf("Ack() error = %v", err)
+		}
+	}
+	if jobs, _ := store.Claim(ctx, "replica-a", future, 10, true); len(jobs) != 0 {
+		t.Errorf("claimed %d acknowledged jobs", len(jobs))
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Errorf("keys left = %v", keys)
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"testing"
 )
 
-// mockHTTPError implements interface{ StatusCode() int } for testing
+// mockHTTPError implements httperror.ProviderError for testing
 type mockHTTPError struct {
 	statusCode int
 	message    string
@@ -19,10 +19,14 @@ func (e *mockHTTPError) Error() string {
 	return e.message
 }
 
-func (e *mockHTTPError) StatusCode() int {
+func (e *mockHTTPError) HTTPStatus() int {
 	return e.statusCode
 }
 
+func (e *mockHTTPError) ProviderName() string {
+	return "mock"
+}
+
 // TestClassifyPublishingError_HTTPTransient tests transient HTTP errors
 func TestClassifyPublishingError_HTTPTransient(t *testing.T) {
 	tests := []struct {
@@ -84,8 +88,8 @@ func TestClassifyPublishingError_HTTPPermanent(t *testing.T) {
 	}
 }
 
-// TestClassifyPublishingError_HTTPUnknown5xx tests unknown 5xx errors
-func TestClassifyPublishingError_HTTPUnknown5xx(t *testing.T) {
+// TestClassifyPublishingError_HTTPOther5xx tests the other 5xx errors
+func TestClassifyPublishingError_HTTPOther5xx(t *testing.T) {
 	tests := []struct {
 		name       string
 		statusCode int
@@ -104,53 +108,47 @@ func TestClassifyPublishingError_HTTPUnknown5xx(t *testing.T) {
 
 			errorType := classifyPublishingError(err)
 
-			if errorType != QueueErrorTypePermanent {
-				t.Errorf("Expected QueueErrorTypePermanent for unknown 5xx %s, got %v", tt.name, errorType)
+			if errorType != QueueErrorTypeTransient {
+				t.Errorf("Expected QueueErrorTypeTransient for 5xx %s, got %v", tt.name, errorType)
 			}
 		})
 	}
 }
 
-// TestClassifyPublishingError_StringHTTPTransient tests string-based HTTP error parsing (transient)
-func TestClassifyPublishingError_StringHTTPTransient(t *testing.T) {
+// TestClassifyPublishingError_ProviderErrors tests errors returned by the publishers
+func TestClassifyPublishingError_ProviderErrors(t *testing.T) {
 	tests := []struct {
-		name    string
-		errMsg  string
-		wantErr QueueErrorType
+		name string
+		err  error
+		want QueueErrorType
 	}{
-		{"408 in message", "HTTP 408 Request Timeout", QueueErrorTypeTransient},
-		{"429 in message", "received 429 Too Many Requests", QueueErrorTypeTransient},
-		{"502 in message", "status code: 502", QueueErrorTypeTransient},
-		{"503 in message", "HTTP 503 Service Unavailable", QueueErrorTypeTransient},
-		{"504 in message", "status code: 504", QueueErrorTypeTransient},
+		{"slack 500", NewPublishingError(http.StatusInternalServerError, "internal_error", ProviderSlack), QueueErrorTypeTransient},
+		{"rootly 429", NewRootlyAPIError(http.StatusTooManyRequests, "Rate limited", "", ""), QueueErrorTypeTransient},
+		{"wrapped pagerduty 403", fmt.Errorf("publish failed: %w", NewPagerDutyAPIError(http.StatusForbidden, "forbidden", nil)), QueueErrorTypePermanent},
+		{"webhook network error", NewWebhookErrorWithType(ErrorTypeNetwork, "request failed", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), QueueErrorTypeTransient},
+		{"not retried 503", fmt.Errorf("%w (%w)", NewPublishingError(http.StatusServiceUnavailable, "unavailable", ProviderKafka), errNotRetried), QueueErrorTypePermanent},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := errors.New(tt.errMsg)
-
-			errorType := classifyPublishingError(err)
-
-			if errorType != tt.wantErr {
-				t.Errorf("Expected %v for %s, got %v", tt.wantErr, tt.name, errorType)
+			if got := classifyPublishingError(tt.err); got != tt.want {
+				t.Errorf("Expected %v for %s, got %v", tt.want, tt.name, got)
 			}
 		})
 	}
 }
 
-// TestClassifyPublishingError_StringHTTPPermanent tests string-based HTTP error parsing (permanent)
-func TestClassifyPublishingError_StringHTTPPermanent(t *testing.T) {
+// TestClassifyPublishingError_StatusInMessage tests that status codes in
+// messages are not classified: they may quote a response or command output
+func TestClassifyPublishingError_StatusInMessage(t *testing.T) {
 	tests := []struct {
-		name    string
-		errMsg  string
-		wantErr QueueErrorType
+		name   string
+		errMsg string
 	}{
-		{"400 in message", "HTTP 400 Bad Request", QueueErrorTypePermanent},
-		{"401 in message", "status code: 401", QueueErrorTypePermanent},
-		{"403 in message", "HTTP 403 Forbidden", QueueErrorTypePermanent},
-		{"404 in message", "HTTP 404 Not Found", QueueErrorTypePermanent},
-		{"405 in message", "status code: 405", QueueErrorTypePermanent},
-		{"422 in message", "HTTP 422 Unprocessable Entity", QueueErrorTypePermanent},
+		{"429 in message", "received 429 Too Many Requests"},
+		{"503 in message", "HTTP 503 Service Unavailable"},
+		{"404 in message", "HTTP 404 Not Found"},
+		{"404 in exec output", "command failed: exit status 22: curl: (22) The requested URL returned error: 404"},
 	}
 
 	for _, tt := range tests {
@@ -159,8 +157,8 @@ func TestClassifyPublishingError_StringHTTPPermanent(t *testing.T) {
 
 			errorType := classifyPublishingError(err)
 
-			if errorType != tt.wantErr {
-				t.Errorf("Expected %v for %s, got %v", tt.wantErr, tt.name, errorType)
+			if errorType != QueueErrorTypeUnknown {
+				t.Errorf("Expected QueueErrorTypeUnknown for %s, got %v", tt.name, errorType)
 			}
 		})
 	}
@@ -247,60 +245,6 @@ func TestClassifyPublishingError_GenericError(t *testing.T) {
 	}
 }
 
-// TestClassifyQueueHTTPError_DirectStatusCodes tests direct status code classification
-func TestClassifyQueueHTTPError_DirectStatusCodes(t *testing.T) {
-	tests := []struct {
-		statusCode int
-		want       QueueErrorType
-		name       string
-	}{
-		{200, QueueErrorTypeUnknown, "200 OK"},
-		{408, QueueErrorTypeTransient, "408 Timeout"},
-		{429, QueueErrorTypeTransient, "429 Rate Limit"},
-		{400, QueueErrorTypePermanent, "400 Bad Request"},
-		{401, QueueErrorTypePermanent, "401 Unauthorized"},
-		{502, QueueErrorTypeTransient, "502 Bad Gateway"},
-		{503, QueueErrorTypeTransient, "503 Service Unavailable"},
-		{504, QueueErrorTypeTransient, "504 Gateway Timeout"},
-		{500, QueueErrorTypePermanent, "500 Internal Server Error"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := classifyQueueHTTPError(tt.statusCode)
-
-			if got != tt.want {
-				t.Errorf("classifyQueueHTTPError(%d) = %v, want %v", tt.statusCode, got, tt.want)
-			}
-		})
-	}
-}
-
-// TestClassifyQueueHTTPErrorString_VariousFormats tests string parsing with various formats
-func TestClassifyQueueHTTPErrorString_VariousFormats(t *testing.T) {
-	tests := []struct {
-		errMsg string
-		want   QueueErrorType
-		name   string
-	}{
-		{"HTTP 404 Not Found", QueueErrorTypePermanent, "HTTP format"},
-		{"status code: 503", QueueErrorTypeTransient, "status code format"},
-		{"received 429 Too Many Requests", QueueErrorTypeTransient, "received format"},
-		{"error: 401 Unauthorized", QueueErrorTypePermanent, "error format"},
-		{"no status code here", QueueErrorTypeUnknown, "no status code"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := classifyQueueHTTPErrorString(tt.errMsg)
-
-			if got != tt.want {
-				t.Errorf("classifyQueueHTTPErrorString(%q) = %v, want %v", tt.errMsg, got, tt.want)
-			}
-		})
-	}
-}
-
 // TestClassifyPublishingError_TemporaryNetworkError tests temporary network errors
 func TestClassifyPublishingError_TemporaryNetworkError(t *testing.T) {
 	err := &net.OpError{
@@ -334,11 +278,3 @@ func BenchmarkClassifyPublishingError(b *testing.B) {
 		_ = classifyPublishingError(err)
 	}
 }
-
-// BenchmarkClassifyQueueHTTPError benchmarks direct HTTP status code classification
-func BenchmarkClassifyQueueHTTPError(b *testing.B) {
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = classifyQueueHTTPError(503)
-	}
-}
@@ -0,0 +1,149 @@
+package publishing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// targetFallbackHeader is the target header listing, comma-separated, the
+// targets its alerts fall back to, in order, when it cannot deliver them.
+// It is never sent.
+const targetFallbackHeader = "fallback"
+
+// Fallback reasons, the reason label of
+// alert_history_publishing_target_fallbacks_total.
+const (
+	FallbackReasonCircuitOpen = "circuit_open" // the circuit breaker dropped the job
+	FallbackReasonFailed      = "failed"       // the job failed after its retries
+)
+
+// Delivery paths, the path label of
+// alert_history_publishing_target_deliveries_total.
+const (
+	DeliveryPathPrimary  = "primary"
+	DeliveryPathFallback = "fallback"
+)
+
+// ParseTargetFallback returns the fallback chain of target, nil if it has
+// none.
+func ParseTargetFallback(target *core.PublishingTarget) ([]string, error) {
+	raw, ok := target.Headers[targetFallbackHeader]
+	if !ok {
+		return nil, nil
+	}
+	var chain []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("invalid %s %q: empty target name", targetFallbackHeader, raw)
+		case name == target.Name:
+			return nil, fmt.Errorf("invalid %s %q: a target cannot fall back to itself", targetFallbackHeader, raw)
+		case slices.Contains(chain, name):
+			return nil, fmt.Errorf("invalid %s %q: duplicate target %q", targetFallbackHeader, raw, name)
+		}
+		chain = append(chain, name)
+	}
+	return chain, nil
+}
+
+// ValidateTargetFallback checks the fallback header of target, if any.
+func ValidateTargetFallback(target *core.PublishingTarget) error {
+	_, err := ParseTargetFallback(target)
+	return err
+}
+
+// deliveryPath returns the path of job: primary, or fallback of another
+// target.
+func (j *PublishingJob) deliveryPath() string {
+	if j.FallbackFor != "" {
+		return DeliveryPathFallback
+	}
+	return DeliveryPathPrimary
+}
+
+// fallBack hands the alerts of job, which its target could not deliver, to
+// the first available target of the fallback chain: that of the primary
+// target, less the targets already tried. Targets missing from discovery,
+// disabled, removed or with an open circuit breaker are skipped. Fallback
+// jobs are deduplicated but not scheduled, and fall back in turn to the rest
+// of the chain. It reports whether a fallback target took the alerts.
+func (q *PublishingQueue) fallBack(job *PublishingJob, reason string) bool {
+	if q.targets == nil {
+		return false
+	}
+	primary, chain := job.FallbackFor, job.Fallbacks
+	if primary == "" {
+		primary = job.Target.Name
+		var err error
+		if chain, err = ParseTargetFallback(job.Target); err != nil {
+			// Rejected by discovery validation
+			q.logger.Warn("Ignoring invalid target fallback", "target", job.Target.Name, "error", err)
+			return false
+		}
+	}
+	if len(chain) == 0 {
+		return false
+	}
+
+	for i, name := range chain {
+		if name == primary {
+			continue
+		}
+		target, err := q.targets.GetTarget(name)
+		if err != nil || !target.Enabled || q.isTargetRemoved(name) || q.isCircuitOpen(name) {
+			q.logger.Warn("Fallback target not available",
+				"target", job.Target.Name,
+				"fallback", name,
+			)
+			continue
+		}
+
+		q.logger.Warn("Publishing to fallback target",
+			"job_id", job.ID,
+			"target", job.Target.Name,
+			"fallback", name,
+			"reason", reason,
+			"alerts", len(job.alerts()),
+		)
+		if q.metrics != nil {
+			q.metrics.RecordTargetFallback(job.Target.Name, name, reason)
+		}
+		for _, alert := range job.alerts() {
+			fallback := &PublishingJob{
+				EnrichedAlert: alert,
+				Target:        target,
+				SubmittedAt:   time.Now(),
+				FallbackFor:   primary,
+				Fallbacks:     chain[i+1:],
+				ID:            uuid.NewString(),
+				Priority:      job.Priority,
+				State:         JobStateQueued,
+			}
+			if q.isDuplicateJob(fallback) {
+				continue
+			}
+			if err := q.admit(fallback); err != nil {
+				q.logger.Error("Failed to submit fallback job",
+					"target", job.Target.Name,
+					"fallback", name,
+					"fingerprint", alert.Alert.Fingerprint,
+					"error", err,
+				)
+			}
+		}
+		return true
+	}
+
+	q.logger.Error("No fallback target available",
+		"job_id", job.ID,
+		"target", job.Target.Name,
+		"fallback", chain,
+	)
+	return false
+}
@@ -0,0 +1,99 @@
+package publishing
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+func TestParseTargetFallback(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+		valid  bool
+	}{
+		{"email-oncall", []string{"email-oncall"}, true},
+		{" slack-backup , email-oncall ", []string{"slack-backup", "email-oncall"}, true},
+		{"email-oncall,", nil, false},
+		{"slack-oncall", nil, false},
+		{"email-oncall,email-oncall", nil, false},
+	}
+	for _, tt := range tests {
+		chain, err := ParseTargetFallback(&core.PublishingTarget{
+			Name:    "slack-oncall",
+			Headers: map[string]string{targetFallbackHeader: tt.header},
+		})
+		if (err == nil) != tt.valid || !slices.Equal(chain, tt.want) {
+			t.Errorf("%q: chain = %v, err = %v, want %v (valid %t)", tt.header, chain, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestPublishingQueue_FallBack(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	queue.metrics = v2.NewPublishingMetrics(registry)
+	queue.targets = &mockTargetDiscoveryManager{targets: []*core.PublishingTarget{
+		{Name: "slack-disabled", Type: ProviderSlack},
+		{Name: "slack-backup", Type: ProviderSlack, Enabled: true},
+		{Name: "email-oncall", Type: "email", Enabled: true},
+	}}
+
+	job := cooldownTestJob("slack-oncall", ProviderSlack)
+	job.Target.Headers = map[string]string{targetFallbackHeader: "missing,slack-disabled,slack-backup,email-oncall"}
+	next := func() *PublishingJob {
+		t.Helper()
+		select {
+		case job := <-queue.jobsFor(job.Priority):
+			return job
+		default:
+			return nil
+		}
+	}
+
+	// Circuit open: the first available target of the chain takes the job
+	queue.ForceCircuitBreaker(job.Target, StateOpen)
+	queue.processJob(job)
+	fallback := next()
+	if fallback == nil || fallback.Target.Name != "slack-backup" || fallback.FallbackFor != "slack-oncall" ||
+		!slices.Equal(fallback.Fallbacks, []string{"email-oncall"}) || fallback.deliveryPath() != DeliveryPathFallback {
+		t.Fatalf("fallback job = %+v", fallback)
+	}
+
+	// The fallback job falls back to the rest of the chain
+	queue.ForceCircuitBreaker(fallback.Target, StateOpen)
+	queue.processJob(fallback)
+	fallback = next()
+	if fallback == nil || fallback.Target.Name != "email-oncall" || fallback.FallbackFor != "slack-oncall" || len(fallback.Fallbacks) != 0 {
+		t.Fatalf("second fallback job = %+v", fallback)
+	}
+
+	// End of the chain
+	queue.ForceCircuitBreaker(fallback.Target, StateOpen)
+	queue.processJob(fallback)
+	if fallback := next(); fallback != nil {
+		t.Fatalf("job past the end of the chain = %+v", fallback)
+	}
+
+	if got := testutil.CollectAndCount(registry, "alert_history_publishing_target_fallbacks_total"); got != 2 {
+		t.Errorf("fallback series = %d, want 2", got)
+	}
+}
+
+func TestPublishingQueue_FallBackWithoutChain(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	queue.targets = &mockTargetDiscoveryManager{}
+
+	job := cooldownTestJob("slack-oncall", ProviderSlack)
+	if queue.fallBack(job, FallbackReasonFailed) {
+		t.Fatal("job without fallback header fell back")
+	}
+}
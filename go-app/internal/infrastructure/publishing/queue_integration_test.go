@@ -229,6 +229,7 @@ func TestQueueErrorType_String(t *testing.T) {
 	}{
 		{QueueErrorTypeTransient, "transient"},
 		{QueueErrorTypePermanent, "permanent"},
+		{QueueErrorTypePoison, "poison"},
 		{QueueErrorTypeUnknown, "unknown"},
 	}
 
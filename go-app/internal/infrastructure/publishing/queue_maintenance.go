@@ -0,0 +1,72 @@
+package publishing
+
+import (
+	"sync"
+)
+
+// queueHold pauses delivery for maintenance.
+//
+// Unlike metrics-only mode, which drops jobs, a paused queue keeps accepting
+// jobs up to its capacity and holds them until it is resumed.
+type queueHold struct {
+	mu      sync.Mutex
+	resumed chan struct{} // nil when not paused; closed on resume
+}
+
+// wait returns a channel closed when the queue is resumed, or nil when the
+// queue is not paused.
+func (h *queueHold) wait() <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.resumed
+}
+
+// Pause stops workers from taking new jobs and holds retries of jobs in
+// flight. Jobs submitted meanwhile stay queued. Reports whether the queue
+// was running.
+func (q *PublishingQueue) Pause() bool {
+	q.hold.mu.Lock()
+	defer q.hold.mu.Unlock()
+
+	if q.hold.resumed != nil {
+		return false
+	}
+	q.hold.resumed = make(chan struct{})
+	q.logger.Warn("Publishing queue paused", "queued_jobs", q.GetQueueSize())
+	return true
+}
+
+// Resume lets workers drain the jobs held while paused. Reports whether the
+// queue was paused.
+func (q *PublishingQueue) Resume() bool {
+	q.hold.mu.Lock()
+	defer q.hold.mu.Unlock()
+
+	if q.hold.resumed == nil {
+		return false
+	}
+	close(q.hold.resumed)
+	q.hold.resumed = nil
+	q.logger.Info("Publishing queue resumed", "held_jobs", q.GetQueueSize())
+	return true
+}
+
+// IsPaused reports whether the queue is paused.
+func (q *PublishingQueue) IsPaused() bool {
+	return q.hold.wait() != nil
+}
+
+// waitResumed blocks while the queue is paused, until it is resumed or
+// stopped.
+func (q *PublishingQueue) waitResumed() error {
+	resumed := q.hold.wait()
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-q.ctx.Done():
+		return q.ctx.Err()
+	}
+}
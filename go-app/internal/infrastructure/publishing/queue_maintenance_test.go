@@ -0,0 +1,86 @@
+package publishing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPublishingQueue_PauseHoldsJobs(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	if !queue.Pause() {
+		t.Fatal("Pause() of a running queue = false")
+	}
+	if queue.Pause() {
+		t.Error("second Pause() = true")
+	}
+	queue.Start()
+	defer queue.Stop(time.Second)
+
+	job := cooldownTestJob("webhook", ProviderWebhook)
+	if err := queue.Submit(job.EnrichedAlert, job.Target); err != nil {
+		t.Fatalf("Submit() while paused error = %v", err)
+	}
+	time.Sleep(250 * time.Millisecond)
+	if got := queue.GetQueueSize(); got != 1 {
+		t.Fatalf("queue size while paused = %d, want 1 held job", got)
+	}
+
+	if !queue.Resume() {
+		t.Fatal("Resume() of a paused queue = false")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for queue.GetQueueSize() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("held job was not drained after Resume()")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if queue.Resume() {
+		t.Error("second Resume() = true")
+	}
+}
+
+func TestPublishingQueue_PauseHoldsRetries(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+
+	queue.Pause()
+	publisher := &scriptedPublisher{}
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.retryPublish(publisher, cooldownTestJob("slack", ProviderSlack))
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("retryPublish() returned while paused: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	queue.Resume()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("retryPublish() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("retryPublish() still blocked after Resume()")
+	}
+	if len(publisher.calls) != 1 {
+		t.Errorf("publisher called %d times, want 1", len(publisher.calls))
+	}
+}
+
+func TestPublishingQueue_StopWhilePaused(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	queue.Pause()
+	queue.Start()
+
+	job := cooldownTestJob("webhook", ProviderWebhook)
+	if err := queue.Submit(job.EnrichedAlert, job.Target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := queue.Stop(time.Second); err != nil {
+		t.Errorf("Stop() while paused error = %v", err)
+	}
+}
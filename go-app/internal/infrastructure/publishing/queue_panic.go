@@ -0,0 +1,78 @@
+package publishing
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// JobPanicError records a panic raised while processing a publishing job.
+//
+// Jobs that panic are treated as poisoned: they are not retried and are
+// quarantined in the DLQ with the panic value and stack trace.
+type JobPanicError struct {
+	// Value is the value passed to panic().
+	Value any
+
+	// Stack is the goroutine stack captured at recovery time.
+	Stack []byte
+}
+
+// Error implements error. The stack is intentionally omitted to keep logs short.
+func (e *JobPanicError) Error() string {
+	return fmt.Sprintf("publisher panicked: %v", e.Value)
+}
+
+// safeProcessJob runs processJob and recovers from publisher panics.
+//
+// Without recovery a single misbehaving publisher would kill the worker
+// goroutine permanently and silently shrink the pool.
+func (q *PublishingQueue) safeProcessJob(job *PublishingJob, workerID int) {
+	defer func() {
+		if r := recover(); r != nil {
+			q.quarantineJob(job, workerID, &JobPanicError{Value: r, Stack: debug.Stack()})
+		}
+	}()
+
+	q.processJob(job)
+}
+
+// quarantineJob marks a panicked job as poisoned and sends it to the DLQ.
+func (q *PublishingQueue) quarantineJob(job *PublishingJob, workerID int, panicErr *JobPanicError) {
+	q.totalPanics.Add(1)
+	q.totalFailed.Add(1)
+
+	now := time.Now()
+	job.State = JobStateDLQ
+	job.CompletedAt = &now
+	job.LastError = panicErr
+	job.ErrorType = QueueErrorTypePoison
+
+	q.logger.Error("Publisher panicked, job quarantined",
+		"job_id", job.ID,
+		"target", job.Target.Name,
+		"worker_id", workerID,
+		"panic", fmt.Sprint(panicErr.Value),
+		"stack", string(panicErr.Stack),
+	)
+
+	q.getCircuitBreaker(job.Target).RecordFailure()
+	if q.metrics != nil {
+		q.metrics.RecordWorkerPanic(job.Target.Name)
+		q.metrics.RecordJobFailure(job.Target.Name)
+	}
+
+	if q.dlqRepository != nil {
+		if err := q.writeDLQ(job); err != nil {
+			q.logger.Error("Failed to write poisoned job to DLQ",
+				"job_id", job.ID,
+				"target", job.Target.Name,
+				"error", err,
+			)
+		}
+	}
+
+	if q.jobTrackingStore != nil {
+		q.jobTrackingStore.Add(job)
+	}
+}
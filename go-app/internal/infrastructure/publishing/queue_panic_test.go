@@ -0,0 +1,145 @@
+package publishing
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// recordingDLQRepository captures jobs written to the DLQ.
+type recordingDLQRepository struct {
+	mu   sync.Mutex
+	jobs []*PublishingJob
+}
+
+func (r *recordingDLQRepository) Write(ctx context.Context, job *PublishingJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func (r *recordingDLQRepository) Read(ctx context.Context, filters DLQFilters) ([]*DLQEntry, error) {
+	return nil, nil
+}
+
+func (r *recordingDLQRepository) Replay(ctx context.Context, id uuid.UUID) error { return nil }
+
+func (r *recordingDLQRepository) MarkReplayed(ctx context.Context, id uuid.UUID, result string) error {
+	return nil
+}
+
+func (r *recordingDLQRepository) Purge(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (r *recordingDLQRepository) GetStats(ctx context.Context) (*DLQStats, error) {
+	return &DLQStats{}, nil
+}
+
+func (r *recordingDLQRepository) written() []*PublishingJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*PublishingJob(nil), r.jobs...)
+}
+
+// newPanickingQueue returns a queue whose publisher creation panics
+// (nil factory → nil pointer dereference inside CreatePublisher).
+func newPanickingQueue(dlq DLQRepository) *PublishingQueue {
+	return NewPublishingQueue(
+		nil,
+		dlq,
+		NewLRUJobTrackingStore(16),
+		PublishingQueueConfig{
+			WorkerCount:             1,
+			HighPriorityQueueSize:   4,
+			MediumPriorityQueueSize: 4,
+			LowPriorityQueueSize:    4,
+			RetryInterval:           time.Millisecond,
+			Metrics:                 v2.NewRegistry(v2.WithPrometheusRegisterer(prometheus.NewRegistry())).Publishing,
+		},
+		nil,
+		slog.Default(),
+	)
+}
+
+func panicTestAlert() *core.EnrichedAlert {
+	return &core.EnrichedAlert{
+		Alert: &core.Alert{
+			Fingerprint: "panic-fingerprint",
+			AlertName:   "PanicTest",
+			Status:      core.StatusFiring,
+			Labels:      map[string]string{"severity": "warning"},
+			StartsAt:    time.Now().UTC(),
+		},
+	}
+}
+
+func TestPublishingQueue_SafeProcessJobQuarantinesPanic(t *testing.T) {
+	dlq := &recordingDLQRepository{}
+	queue := newPanickingQueue(dlq)
+
+	target := &core.PublishingTarget{Name: "panicky", Type: "webhook", URL: "http://127.0.0.1:1"}
+	if err := queue.Submit(panicTestAlert(), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	job := <-queue.mediumPriorityJobs
+	queue.safeProcessJob(job, 0)
+
+	if job.State != JobStateDLQ {
+		t.Fatalf("State = %s, want dlq", job.State)
+	}
+	if job.ErrorType != QueueErrorTypePoison {
+		t.Fatalf("ErrorType = %s, want poison", job.ErrorType)
+	}
+
+	var panicErr *JobPanicError
+	if !errors.As(job.LastError, &panicErr) {
+		t.Fatalf("LastError = %v, want *JobPanicError", job.LastError)
+	}
+	if !strings.Contains(string(panicErr.Stack), "CreatePublisher") {
+		t.Errorf("stack does not point at the panicking publisher:\n%s", panicErr.Stack)
+	}
+
+	if got := len(dlq.written()); got != 1 {
+		t.Fatalf("DLQ writes = %d, want 1", got)
+	}
+
+	stats := queue.GetStats()
+	if stats.TotalPanics != 1 || stats.TotalFailed != 1 {
+		t.Fatalf("TotalPanics = %d, TotalFailed = %d, want 1 and 1", stats.TotalPanics, stats.TotalFailed)
+	}
+}
+
+func TestPublishingQueue_WorkerSurvivesPanic(t *testing.T) {
+	dlq := &recordingDLQRepository{}
+	queue := newPanickingQueue(dlq)
+	queue.Start()
+	defer queue.Stop(time.Second)
+
+	target := &core.PublishingTarget{Name: "panicky", Type: "webhook", URL: "http://127.0.0.1:1"}
+	for i := 0; i < 3; i++ {
+		if err := queue.Submit(panicTestAlert(), target); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	// A single worker must process all jobs despite each one panicking
+	deadline := time.Now().Add(2 * time.Second)
+	for len(dlq.written()) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("DLQ writes = %d, want 3 (worker died?)", len(dlq.written()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -31,10 +31,10 @@ func determinePriority(enrichedAlert *core.EnrichedAlert) Priority {
 	}
 
 	alert := enrichedAlert.Alert
+	severity := enrichedAlert.KnownLabels().Severity
 
 	// HIGH priority: Critical firing alerts
-	severity := alert.Severity()
-	if severity != nil && *severity == "critical" && alert.Status == core.StatusFiring {
+	if severity == "critical" && alert.Status == core.StatusFiring {
 		return PriorityHigh
 	}
 
@@ -51,7 +51,7 @@ func determinePriority(enrichedAlert *core.EnrichedAlert) Priority {
 	}
 
 	// LOW priority: Info severity
-	if severity != nil && *severity == "info" {
+	if severity == "info" {
 		return PriorityLow
 	}
 
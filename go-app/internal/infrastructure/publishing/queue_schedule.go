@@ -0,0 +1,318 @@
+package publishing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Target headers scheduling the firing notifications of a target. They are
+// never sent.
+//
+// With notify_after, a firing alert is published once it has been firing
+// for that long (since its StartsAt), and not at all when it resolves or
+// expires (EndsAt passed) before. With repeat_interval, a published firing
+// alert is published again at that interval until it resolves or expires.
+const (
+	targetNotifyAfterHeader    = "notify_after"
+	targetRepeatIntervalHeader = "repeat_interval"
+)
+
+const (
+	maxNotifyAfter    = 24 * time.Hour
+	minRepeatInterval = time.Minute
+)
+
+// Scheduled job release reasons (metric label)
+const (
+	ScheduleReleaseNotify   = "notify"
+	ScheduleReleaseRenotify = "renotify"
+	ScheduleReleaseResolved = "resolved"
+	ScheduleReleaseExpired  = "expired"
+	ScheduleReleaseRemoved  = "removed"
+)
+
+// TargetSchedule is the notification schedule of a target.
+type TargetSchedule struct {
+	NotifyAfter    time.Duration
+	RepeatInterval time.Duration
+}
+
+// Enabled reports whether notifications of the target are scheduled.
+func (s TargetSchedule) Enabled() bool {
+	return s.NotifyAfter > 0 || s.RepeatInterval > 0
+}
+
+// ParseTargetSchedule reads the schedule headers of target.
+func ParseTargetSchedule(target *core.PublishingTarget) (TargetSchedule, error) {
+	var schedule TargetSchedule
+	if raw, ok := target.Headers[targetNotifyAfterHeader]; ok {
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || d < 0 || d > maxNotifyAfter {
+			return TargetSchedule{}, fmt.Errorf("invalid %s %q: must be a duration up to %s", targetNotifyAfterHeader, raw, maxNotifyAfter)
+		}
+		schedule.NotifyAfter = d
+	}
+	if raw, ok := target.Headers[targetRepeatIntervalHeader]; ok {
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || (d != 0 && d < minRepeatInterval) {
+			return TargetSchedule{}, fmt.Errorf("invalid %s %q: must be 0 or a duration of at least %s", targetRepeatIntervalHeader, raw, minRepeatInterval)
+		}
+		schedule.RepeatInterval = d
+	}
+	return schedule, nil
+}
+
+// ValidateTargetSchedule checks the schedule headers of target, if any.
+func ValidateTargetSchedule(target *core.PublishingTarget) error {
+	_, err := ParseTargetSchedule(target)
+	return err
+}
+
+// jobScheduler holds the jobs of scheduled targets until they are due: one
+// per target and alert.
+//
+// A scheduled job is stored like a queued job when the queue is durable,
+// with its due time in NotBefore, so schedules survive restarts.
+type jobScheduler struct {
+	mu      sync.Mutex
+	entries map[string]*scheduledJob // by target name and fingerprint
+	closed  bool
+}
+
+type scheduledJob struct {
+	job   *PublishingJob // Renotify once the alert was published
+	timer *time.Timer
+}
+
+func newJobScheduler() *jobScheduler {
+	return &jobScheduler{entries: make(map[string]*scheduledJob)}
+}
+
+func scheduleKey(target, fingerprint string) string {
+	return target + "\x00" + fingerprint
+}
+
+// stillFiring reports whether alert has neither resolved nor expired at now.
+func stillFiring(alert *core.Alert, now time.Time) bool {
+	return alert.Status == core.StatusFiring && (alert.EndsAt == nil || alert.EndsAt.After(now))
+}
+
+// scheduleJob applies the schedule of its target to a submitted job.
+// Reports whether the job is handled: held until notify_after, or a
+// resolved alert the target was never notified of. Other jobs are queued
+// by the caller; published firing alerts are scheduled for renotification.
+func (q *PublishingQueue) scheduleJob(job *PublishingJob) bool {
+	schedule, err := ParseTargetSchedule(job.Target)
+	if err != nil {
+		// Rejected by discovery validation; publish right away
+		schedule = TargetSchedule{}
+	}
+
+	s := q.scheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+
+	alert := job.EnrichedAlert.Alert
+	key := scheduleKey(job.Target.Name, alert.Fingerprint)
+	entry := s.entries[key]
+	now := time.Now()
+
+	if alert.Status != core.StatusFiring {
+		if entry == nil {
+			return false
+		}
+		q.unschedule(key, entry, ScheduleReleaseResolved)
+		// The target was never notified of the alert
+		return !entry.job.Renotify
+	}
+
+	if entry != nil && !entry.job.Renotify {
+		// Still waiting for notify_after: publish the latest alert then
+		entry.job.EnrichedAlert = job.EnrichedAlert
+		entry.job.Target = job.Target
+		q.persistJob(entry.job)
+		return true
+	}
+	if entry == nil && schedule.NotifyAfter > 0 {
+		if due := alert.StartsAt.Add(schedule.NotifyAfter); due.After(now) {
+			q.reschedule(key, scheduledJobFor(job, false), due)
+			return true
+		}
+	}
+
+	// Published now: renotified after repeat_interval
+	switch {
+	case schedule.RepeatInterval > 0:
+		q.reschedule(key, scheduledJobFor(job, true), now.Add(schedule.RepeatInterval))
+	case entry != nil:
+		q.unschedule(key, entry, "")
+	}
+	return false
+}
+
+// scheduledJobFor returns the scheduled copy of a submitted job.
+func scheduledJobFor(job *PublishingJob, renotify bool) *PublishingJob {
+	return &PublishingJob{
+		ID:            uuid.NewString(),
+		EnrichedAlert: job.EnrichedAlert,
+		Target:        job.Target,
+		Priority:      job.Priority,
+		SubmittedAt:   job.SubmittedAt,
+		State:         JobStateQueued,
+		Renotify:      renotify,
+	}
+}
+
+// reschedule holds job until due, replacing the scheduled job of key.
+// Called with the scheduler locked.
+func (q *PublishingQueue) reschedule(key string, job *PublishingJob, due time.Time) {
+	s := q.scheduler
+	if old := s.entries[key]; old != nil {
+		old.timer.Stop()
+		if old.job.ID != job.ID {
+			q.ackJob(old.job)
+		}
+	}
+
+	job.NotBefore = due
+	q.persistJob(job)
+	entry := &scheduledJob{job: job}
+	entry.timer = time.AfterFunc(time.Until(due), func() { q.releaseScheduled(key, entry) })
+	s.entries[key] = entry
+	q.updateScheduledMetric()
+}
+
+// unschedule drops the scheduled job of key. Called with the scheduler
+// locked.
+func (q *PublishingQueue) unschedule(key string, entry *scheduledJob, reason string) {
+	entry.timer.Stop()
+	delete(q.scheduler.entries, key)
+	q.ackJob(entry.job)
+	q.updateScheduledMetric()
+	if reason != "" && q.metrics != nil {
+		q.metrics.RecordScheduledRelease(entry.job.Target.Name, reason)
+	}
+}
+
+// releaseScheduled queues a due scheduled job when its alert is still
+// firing, and schedules its renotification.
+func (q *PublishingQueue) releaseScheduled(key string, entry *scheduledJob) {
+	s := q.scheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.entries[key] != entry {
+		// Stopped, or replaced or dropped meanwhile
+		return
+	}
+
+	scheduled := entry.job
+	now := time.Now()
+	if q.isTargetRemoved(scheduled.Target.Name) {
+		q.unschedule(key, entry, ScheduleReleaseRemoved)
+		return
+	}
+	if !stillFiring(scheduled.EnrichedAlert.Alert, now) {
+		q.unschedule(key, entry, ScheduleReleaseExpired)
+		return
+	}
+
+	job := &PublishingJob{
+		ID:            uuid.NewString(),
+		EnrichedAlert: scheduled.EnrichedAlert,
+		Target:        scheduled.Target,
+		Priority:      scheduled.Priority,
+		SubmittedAt:   now,
+		State:         JobStateQueued,
+	}
+	reason := ScheduleReleaseNotify
+	if scheduled.Renotify {
+		reason = ScheduleReleaseRenotify
+	}
+	if err := q.admit(job); err != nil {
+		q.logger.Warn("Failed to queue scheduled publishing job",
+			"target", scheduled.Target.Name,
+			"fingerprint", scheduled.EnrichedAlert.Alert.Fingerprint,
+			"renotify", scheduled.Renotify,
+			"error", err,
+		)
+	} else if q.metrics != nil {
+		q.metrics.RecordScheduledRelease(scheduled.Target.Name, reason)
+	}
+
+	schedule, _ := ParseTargetSchedule(scheduled.Target)
+	if schedule.RepeatInterval <= 0 {
+		q.unschedule(key, entry, "")
+		return
+	}
+	scheduled.Renotify = true
+	q.reschedule(key, scheduled, now.Add(schedule.RepeatInterval))
+}
+
+// restoreScheduled schedules again a job stored with a due time, recovered
+// after a restart or from a crashed replica.
+func (q *PublishingQueue) restoreScheduled(job *PublishingJob) {
+	s := q.scheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := scheduleKey(job.Target.Name, job.EnrichedAlert.Alert.Fingerprint)
+	if s.closed || s.entries[key] != nil {
+		// Superseded by an alert submitted since
+		q.ackJob(job)
+		return
+	}
+	q.reschedule(key, job, job.NotBefore)
+}
+
+// unscheduleTarget drops the scheduled jobs of a removed target.
+func (q *PublishingQueue) unscheduleTarget(name string) {
+	s := q.scheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.entries {
+		if entry.job.Target.Name == name {
+			q.unschedule(key, entry, ScheduleReleaseRemoved)
+		}
+	}
+}
+
+// closeScheduler stops the schedule timers. Scheduled jobs of a durable
+// queue stay stored and are scheduled again on restart; others are lost.
+func (q *PublishingQueue) closeScheduler() {
+	s := q.scheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for _, entry := range s.entries {
+		entry.timer.Stop()
+	}
+	if len(s.entries) > 0 && q.durable == nil {
+		q.logger.Warn("Publishing queue stopped with scheduled jobs, dropping them", "scheduled_jobs", len(s.entries))
+	}
+}
+
+// ScheduledJobs returns the number of jobs held until notify_after or
+// repeat_interval elapses.
+func (q *PublishingQueue) ScheduledJobs() int {
+	q.scheduler.mu.Lock()
+	defer q.scheduler.mu.Unlock()
+	return len(q.scheduler.entries)
+}
+
+func (q *PublishingQueue) updateScheduledMetric() {
+	if q.metrics != nil {
+		q.metrics.SetScheduledJobs(len(q.scheduler.entries))
+	}
+}
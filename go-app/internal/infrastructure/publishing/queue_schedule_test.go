@@ -0,0 +1,223 @@
+package publishing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func scheduleTestAlert(status core.AlertStatus, startsAt time.Time) *core.EnrichedAlert {
+	alert := panicTestAlert()
+	alert.Alert.Status = status
+	alert.Alert.StartsAt = startsAt
+	return alert
+}
+
+func scheduleTestTarget(headers map[string]string) *core.PublishingTarget {
+	return &core.PublishingTarget{Name: "scheduled", Type: "webhook", URL: "http://127.0.0.1:1", Headers: headers}
+}
+
+// scheduledEntry returns the scheduled job of the test alert for target.
+func scheduledEntry(t *testing.T, queue *PublishingQueue, target *core.PublishingTarget) (string, *scheduledJob) {
+	t.Helper()
+	key := scheduleKey(target.Name, panicTestAlert().Alert.Fingerprint)
+	queue.scheduler.mu.Lock()
+	defer queue.scheduler.mu.Unlock()
+	entry := queue.scheduler.entries[key]
+	if entry == nil {
+		t.Fatal("alert is not scheduled")
+	}
+	return key, entry
+}
+
+func TestParseTargetSchedule(t *testing.T) {
+	schedule, err := ParseTargetSchedule(scheduleTestTarget(map[string]string{
+		"notify_after":    "10m",
+		"repeat_interval": "4h",
+	}))
+	if err != nil {
+		t.Fatalf("ParseTargetSchedule() error = %v", err)
+	}
+	if schedule != (TargetSchedule{NotifyAfter: 10 * time.Minute, RepeatInterval: 4 * time.Hour}) {
+		t.Errorf("ParseTargetSchedule() = %+v", schedule)
+	}
+
+	for _, headers := range []map[string]string{
+		{"notify_after": "-1m"},
+		{"notify_after": "48h"},
+		{"repeat_interval": "30s"},
+	} {
+		if err := ValidateTargetSchedule(scheduleTestTarget(headers)); err == nil {
+			t.Errorf("ValidateTargetSchedule(%v) succeeded", headers)
+		}
+	}
+
+	headers := targetHTTPHeaders(map[string]string{"notify_after": "1m", "repeat_interval": "1h", "X-Team": "sre"})
+	if len(headers) != 1 {
+		t.Errorf("targetHTTPHeaders() = %v, want only X-Team", headers)
+	}
+}
+
+func TestPublishingQueue_NotifyAfterDropsResolvedAlert(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.cancel()
+	target := scheduleTestTarget(map[string]string{"notify_after": "10m"})
+
+	if err := queue.Submit(scheduleTestAlert(core.StatusFiring, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if queue.GetQueueSize() != 0 || queue.ScheduledJobs() != 1 {
+		t.Fatalf("queued %d, scheduled %d; want the firing alert held", queue.GetQueueSize(), queue.ScheduledJobs())
+	}
+
+	// Resent while held: still a single scheduled job
+	if err := queue.Submit(scheduleTestAlert(core.StatusFiring, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if queue.ScheduledJobs() != 1 {
+		t.Fatalf("scheduled %d jobs, want 1", queue.ScheduledJobs())
+	}
+
+	if err := queue.Submit(scheduleTestAlert(core.StatusResolved, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if queue.GetQueueSize() != 0 || queue.ScheduledJobs() != 0 {
+		t.Errorf("queued %d, scheduled %d; want the alert never published", queue.GetQueueSize(), queue.ScheduledJobs())
+	}
+}
+
+func TestPublishingQueue_NotifyAfterReleasesFiringAlert(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.cancel()
+	target := scheduleTestTarget(map[string]string{"notify_after": "50ms"})
+
+	if err := queue.Submit(scheduleTestAlert(core.StatusFiring, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for queue.GetQueueSize() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("firing alert was not published after notify_after")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if queue.ScheduledJobs() != 0 {
+		t.Errorf("scheduled %d jobs without repeat_interval", queue.ScheduledJobs())
+	}
+
+	// Firing for longer than notify_after: published right away
+	if err := queue.Submit(scheduleTestAlert(core.StatusFiring, time.Now().Add(-time.Hour)), scheduleTestTarget(map[string]string{"notify_after": "10m"})); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if queue.GetQueueSize() != 2 {
+		t.Errorf("queued %d jobs, want 2", queue.GetQueueSize())
+	}
+}
+
+func TestPublishingQueue_RepeatIntervalRenotifiesUntilResolved(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.cancel()
+	target := scheduleTestTarget(map[string]string{"repeat_interval": "1h"})
+
+	if err := queue.Submit(scheduleTestAlert(core.StatusFiring, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if queue.GetQueueSize() != 1 || queue.ScheduledJobs() != 1 {
+		t.Fatalf("queued %d, scheduled %d; want published and scheduled", queue.GetQueueSize(), queue.ScheduledJobs())
+	}
+
+	key, entry := scheduledEntry(t, queue, target)
+	if !entry.job.Renotify || time.Until(entry.job.NotBefore) < 59*time.Minute {
+		t.Fatalf("renotification due %v, renotify %v", entry.job.NotBefore, entry.job.Renotify)
+	}
+	firstDue := entry.job.NotBefore
+
+	// repeat_interval elapsed
+	queue.releaseScheduled(key, entry)
+	if queue.GetQueueSize() != 2 {
+		t.Fatalf("queued %d jobs after repeat_interval, want 2", queue.GetQueueSize())
+	}
+	if _, entry = scheduledEntry(t, queue, target); !entry.job.NotBefore.After(firstDue) {
+		t.Errorf("next renotification due %v, want after %v", entry.job.NotBefore, firstDue)
+	}
+
+	// The resolved alert is published and ends the renotifications
+	if err := queue.Submit(scheduleTestAlert(core.StatusResolved, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if queue.GetQueueSize() != 3 || queue.ScheduledJobs() != 0 {
+		t.Errorf("queued %d, scheduled %d; want resolved published and unscheduled", queue.GetQueueSize(), queue.ScheduledJobs())
+	}
+}
+
+func TestPublishingQueue_ScheduledAlertExpires(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.cancel()
+	target := scheduleTestTarget(map[string]string{"notify_after": "10m"})
+
+	alert := scheduleTestAlert(core.StatusFiring, time.Now())
+	endsAt := time.Now().Add(time.Minute)
+	alert.Alert.EndsAt = &endsAt
+	if err := queue.Submit(alert, target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// Due after the alert expired without a resolved notification
+	endsAt = time.Now().Add(-time.Second)
+	key, entry := scheduledEntry(t, queue, target)
+	queue.releaseScheduled(key, entry)
+	if queue.GetQueueSize() != 0 || queue.ScheduledJobs() != 0 {
+		t.Errorf("queued %d, scheduled %d; want the expired alert dropped", queue.GetQueueSize(), queue.ScheduledJobs())
+	}
+}
+
+func TestPublishingQueue_RemoveTargetDropsScheduledJobs(t *testing.T) {
+	queue := newPanickingQueue(&recordingDLQRepository{})
+	defer queue.cancel()
+	target := scheduleTestTarget(map[string]string{"notify_after": "10m"})
+
+	if err := queue.Submit(scheduleTestAlert(core.StatusFiring, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	queue.RemoveTarget(target.Name)
+	if queue.ScheduledJobs() != 0 {
+		t.Errorf("scheduled %d jobs of a removed target", queue.ScheduledJobs())
+	}
+}
+
+func TestPublishingQueue_ScheduledJobsSurviveRestart(t *testing.T) {
+	store := newMemoryDurableStore()
+	queue := newDurableTestQueue(store, "replica-a")
+	target := scheduleTestTarget(map[string]string{"notify_after": "10m"})
+
+	if err := queue.Submit(scheduleTestAlert(core.StatusFiring, time.Now()), target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := queue.Stop(time.Second); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	ids := store.ids()
+	if len(ids) != 1 || store.jobs[ids[0]].job.NotBefore.IsZero() {
+		t.Fatalf("stored jobs = %v, want the scheduled job with its due time", ids)
+	}
+
+	data, err := marshalDurableJob(store.jobs[ids[0]].job)
+	if err != nil {
+		t.Fatalf("marshalDurableJob() error = %v", err)
+	}
+	job, err := unmarshalDurableJob(data)
+	if err != nil {
+		t.Fatalf("unmarshalDurableJob() error = %v", err)
+	}
+	if !job.NotBefore.Equal(store.jobs[ids[0]].job.NotBefore) {
+		t.Errorf("due time %v lost in the stored job", job.NotBefore)
+	}
+
+	restarted := newDurableTestQueue(store, "replica-a")
+	restarted.Start()
+	defer func() { _ = restarted.Stop(time.Second) }()
+	if restarted.ScheduledJobs() != 1 || restarted.GetQueueSize() != 0 {
+		t.Errorf("after restart: scheduled %d, queued %d; want the job scheduled again", restarted.ScheduledJobs(), restarted.GetQueueSize())
+	}
+}
@@ -0,0 +1,164 @@
+package publishing
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// ErrJobShed is returned by Submit when a job is shed under queue pressure.
+var ErrJobShed = errors.New("job shed under queue pressure")
+
+// ShedClassResolved is the shed class of resolved alerts. Firing alerts are
+// classified by their severity label ("info", "warning", ...).
+const ShedClassResolved = "resolved"
+
+// DefaultShedOrder sheds resolved alerts first, then info, then warning
+// alerts. Critical alerts are never shed.
+var DefaultShedOrder = []string{ShedClassResolved, "info", "warning"}
+
+// ShedPolicy decides which jobs are dropped when the queue approaches
+// capacity, so that low-value jobs are shed before the queue is full and
+// starts rejecting whatever arrives next.
+//
+// Between SoftLimit and full capacity the fill range is split into one band
+// per class of Order: at SoftLimit the first class is shed, each further
+// band adds the next class. With SoftLimit 0.8 and the default order,
+// resolved alerts are shed from 80% fill, info from ~87% and warning from
+// ~93%. Classes not listed in Order are never shed; a full queue still
+// rejects them.
+type ShedPolicy struct {
+	// SoftLimit is the fill ratio (0-1) at which shedding starts.
+	// 0 disables shedding.
+	SoftLimit float64
+	// Order lists the shed classes, shed first to last: ShedClassResolved
+	// or a severity.
+	Order []string
+}
+
+// Validate checks the policy.
+func (p ShedPolicy) Validate() error {
+	if p.SoftLimit < 0 || p.SoftLimit >= 1 {
+		return fmt.Errorf("soft limit must be in [0, 1), got %v", p.SoftLimit)
+	}
+	seen := make(map[string]bool, len(p.Order))
+	for _, class := range p.Order {
+		if class == "" {
+			return fmt.Errorf("shed order contains an empty class")
+		}
+		if seen[class] {
+			return fmt.Errorf("shed order contains %q twice", class)
+		}
+		seen[class] = true
+	}
+	return nil
+}
+
+func (p ShedPolicy) enabled() bool {
+	return p.SoftLimit > 0 && len(p.Order) > 0
+}
+
+// threshold returns the fill ratio at which class is shed, or false when
+// the class is never shed.
+func (p ShedPolicy) threshold(class string) (float64, bool) {
+	for i, c := range p.Order {
+		if strings.EqualFold(c, class) {
+			return p.SoftLimit + (1-p.SoftLimit)*float64(i)/float64(len(p.Order)), true
+		}
+	}
+	return 0, false
+}
+
+// shedClass returns the class of an alert for the shed policy, and the
+// severity it is counted under.
+func shedClass(enrichedAlert *core.EnrichedAlert) (class, severity string) {
+	severity = alertSeverity(enrichedAlert)
+	if enrichedAlert != nil && enrichedAlert.Alert != nil && enrichedAlert.Alert.Status == core.StatusResolved {
+		return ShedClassResolved, severity
+	}
+	return severity, severity
+}
+
+// shedCounters counts shed jobs by severity.
+type shedCounters struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (c *shedCounters) inc(severity string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int64)
+	}
+	c.counts[severity]++
+}
+
+// snapshot returns a copy of the counters and their total.
+func (c *shedCounters) snapshot() (map[string]int64, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int64, len(c.counts))
+	var total int64
+	for severity, n := range c.counts {
+		out[severity] = n
+		total += n
+	}
+	return out, total
+}
+
+// queuePressure returns the fill ratio relevant for a job submitted to
+// targetQueue: the higher of the overall and the tier fill.
+func (q *PublishingQueue) queuePressure(targetQueue chan *PublishingJob) float64 {
+	pressure := 0.0
+	if capacity := q.GetQueueCapacity(); capacity > 0 {
+		pressure = float64(q.GetQueueSize()) / float64(capacity)
+	}
+	if cap(targetQueue) > 0 {
+		if tier := float64(len(targetQueue)) / float64(cap(targetQueue)); tier > pressure {
+			pressure = tier
+		}
+	}
+	return pressure
+}
+
+// shouldShed reports whether job is shed under the current queue pressure,
+// and records it if so.
+func (q *PublishingQueue) shouldShed(job *PublishingJob, targetQueue chan *PublishingJob) bool {
+	if !q.shedPolicy.enabled() {
+		return false
+	}
+	class, severity := shedClass(job.EnrichedAlert)
+	threshold, ok := q.shedPolicy.threshold(class)
+	if !ok {
+		return false
+	}
+	pressure := q.queuePressure(targetQueue)
+	if pressure < threshold {
+		return false
+	}
+
+	status := string(core.StatusFiring)
+	if class == ShedClassResolved {
+		status = string(core.StatusResolved)
+	}
+	q.shed.inc(severity)
+	if q.metrics != nil {
+		q.metrics.RecordQueueShed(severity, status)
+	}
+	// Level guard: shedding happens in bursts during incidents
+	if q.logger.Enabled(q.ctx, slog.LevelDebug) {
+		q.logger.Debug("Job shed under queue pressure",
+			"priority", job.Priority,
+			"severity", severity,
+			"status", status,
+			"target", job.Target.Name,
+			"fill", pressure,
+		)
+	}
+	return true
+}
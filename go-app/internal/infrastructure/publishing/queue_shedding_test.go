@@ -0,0 +1,132 @@
+package publishing
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+func newSheddingTestQueue(policy ShedPolicy) *PublishingQueue {
+	return NewPublishingQueue(
+		NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, ""),
+		nil,
+		nil,
+		PublishingQueueConfig{
+			WorkerCount:             1,
+			HighPriorityQueueSize:   4,
+			MediumPriorityQueueSize: 4,
+			LowPriorityQueueSize:    4,
+			Metrics:                 v2.NewRegistry(v2.WithPrometheusRegisterer(prometheus.NewRegistry())).Publishing,
+			Shedding:                policy,
+		},
+		nil,
+		slog.Default(),
+	)
+}
+
+func shedTestAlert(severity string, status core.AlertStatus) *core.EnrichedAlert {
+	return &core.EnrichedAlert{
+		Alert: &core.Alert{
+			Fingerprint: "shed-" + severity + "-" + string(status),
+			AlertName:   "ShedTest",
+			Status:      status,
+			Labels:      map[string]string{"severity": severity},
+		},
+	}
+}
+
+func TestPublishingQueue_ShedsBySeverityUnderPressure(t *testing.T) {
+	queue := newSheddingTestQueue(ShedPolicy{SoftLimit: 0.5, Order: DefaultShedOrder})
+	target := &core.PublishingTarget{Name: "webhook", Type: "webhook"}
+
+	// Thresholds: resolved 0.5, info ~0.67, warning ~0.83 of the tier or the
+	// whole queue, whichever is fuller.
+	steps := []struct {
+		alert *core.EnrichedAlert
+		shed  bool
+	}{
+		{shedTestAlert("info", core.StatusFiring), false},      // low 0/4
+		{shedTestAlert("info", core.StatusFiring), false},      // low 1/4
+		{shedTestAlert("warning", core.StatusResolved), true},  // low 2/4: resolved shed
+		{shedTestAlert("info", core.StatusFiring), false},      // low 2/4
+		{shedTestAlert("info", core.StatusFiring), true},       // low 3/4: info shed
+		{shedTestAlert("warning", core.StatusFiring), false},   // medium 0/4
+		{shedTestAlert("critical", core.StatusResolved), true}, // low 3/4: resolved shed
+	}
+	for i, step := range steps {
+		err := queue.Submit(step.alert, target)
+		if got := errors.Is(err, ErrJobShed); got != step.shed {
+			t.Fatalf("step %d: Submit() error = %v, want shed = %v", i, err, step.shed)
+		}
+		if !step.shed && err != nil {
+			t.Fatalf("step %d: Submit() error = %v", i, err)
+		}
+	}
+
+	// Critical firing alerts are never shed; a full tier still rejects them.
+	for i := 0; i < 4; i++ {
+		if err := queue.Submit(shedTestAlert("critical", core.StatusFiring), target); err != nil {
+			t.Fatalf("Submit(critical %d) error = %v", i, err)
+		}
+	}
+	err := queue.Submit(shedTestAlert("critical", core.StatusFiring), target)
+	if err == nil || errors.Is(err, ErrJobShed) || !strings.Contains(err.Error(), "queue full") {
+		t.Fatalf("Submit(critical) on full tier error = %v, want queue full", err)
+	}
+
+	stats := queue.GetStats()
+	if stats.TotalShed != 3 {
+		t.Errorf("TotalShed = %d, want 3", stats.TotalShed)
+	}
+	want := map[string]int64{"info": 1, "warning": 1, "critical": 1}
+	for severity, n := range want {
+		if stats.ShedBySeverity[severity] != n {
+			t.Errorf("ShedBySeverity[%s] = %d, want %d", severity, stats.ShedBySeverity[severity], n)
+		}
+	}
+	if stats.TotalSubmitted != 8 {
+		t.Errorf("TotalSubmitted = %d, want 8", stats.TotalSubmitted)
+	}
+}
+
+func TestPublishingQueue_SheddingDisabled(t *testing.T) {
+	queue := newSheddingTestQueue(ShedPolicy{Order: DefaultShedOrder})
+	target := &core.PublishingTarget{Name: "webhook", Type: "webhook"}
+
+	for i := 0; i < 4; i++ {
+		if err := queue.Submit(shedTestAlert("info", core.StatusResolved), target); err != nil {
+			t.Fatalf("Submit(%d) error = %v", i, err)
+		}
+	}
+	if stats := queue.GetStats(); stats.TotalShed != 0 {
+		t.Errorf("TotalShed = %d, want 0", stats.TotalShed)
+	}
+}
+
+func TestShedPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ShedPolicy
+		wantErr bool
+	}{
+		{"disabled", ShedPolicy{}, false},
+		{"default order", ShedPolicy{SoftLimit: 0.8, Order: DefaultShedOrder}, false},
+		{"negative limit", ShedPolicy{SoftLimit: -0.1}, true},
+		{"limit of one", ShedPolicy{SoftLimit: 1}, true},
+		{"empty class", ShedPolicy{SoftLimit: 0.8, Order: []string{""}}, true},
+		{"duplicate class", ShedPolicy{SoftLimit: 0.8, Order: []string{"info", "info"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package publishing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// SLO results recorded for a firing notification.
+const (
+	SLOResultMet      = "met"      // acknowledged by the provider within the threshold
+	SLOResultBreached = "breached" // acknowledged, but after the threshold
+	SLOResultFailed   = "failed"   // never acknowledged (retries exhausted)
+)
+
+// DefaultDeliverySLO pages critical alerts within 30s and everything else
+// within a few minutes.
+var DefaultDeliverySLO = DeliverySLO{
+	Thresholds: map[string]time.Duration{
+		"critical": 30 * time.Second,
+		"warning":  2 * time.Minute,
+		"info":     5 * time.Minute,
+	},
+	DefaultThreshold: 5 * time.Minute,
+}
+
+// DeliverySLO defines how fast firing alerts must reach their targets,
+// measured from ingestion until the provider acknowledged the notification.
+// This covers queueing, retries and rate-limit cooldowns, so it shows when
+// AMP itself is too slow to page people.
+//
+// Resolved notifications are not tracked.
+type DeliverySLO struct {
+	// Thresholds maps a severity to its delivery deadline.
+	Thresholds map[string]time.Duration
+	// DefaultThreshold applies to severities not listed in Thresholds.
+	// 0 disables SLO tracking for them.
+	DefaultThreshold time.Duration
+}
+
+// Validate checks the SLO.
+func (s DeliverySLO) Validate() error {
+	if s.DefaultThreshold < 0 {
+		return fmt.Errorf("default threshold must be non-negative, got %s", s.DefaultThreshold)
+	}
+	for severity, threshold := range s.Thresholds {
+		if severity == "" {
+			return fmt.Errorf("thresholds contain an empty severity")
+		}
+		if threshold <= 0 {
+			return fmt.Errorf("threshold for %q must be positive, got %s", severity, threshold)
+		}
+	}
+	return nil
+}
+
+// threshold returns the delivery deadline for severity, or false when
+// notifications of that severity are not tracked.
+func (s DeliverySLO) threshold(severity string) (time.Duration, bool) {
+	for sev, threshold := range s.Thresholds {
+		if strings.EqualFold(sev, severity) {
+			return threshold, true
+		}
+	}
+	return s.DefaultThreshold, s.DefaultThreshold > 0
+}
+
+// exportThresholds publishes the thresholds as gauges, so that dashboards
+// can draw them next to the latency histogram. The default threshold is
+// exported under severity "default".
+func (s DeliverySLO) exportThresholds(metrics *v2.PublishingMetrics) {
+	for severity, threshold := range s.Thresholds {
+		metrics.SetNotificationSLOThreshold(strings.ToLower(severity), threshold)
+	}
+	if s.DefaultThreshold > 0 {
+		metrics.SetNotificationSLOThreshold("default", s.DefaultThreshold)
+	}
+}
+
+// alertSeverity returns the lower-cased severity of an alert, or "unknown".
+func alertSeverity(enrichedAlert *core.EnrichedAlert) string {
+	if enrichedAlert == nil || enrichedAlert.Alert == nil {
+		return "unknown"
+	}
+	if s := enrichedAlert.KnownLabels().Severity; s != "" {
+		return strings.ToLower(s)
+	}
+	return "unknown"
+}
+
+// ingestedAt returns when AMP received enrichedAlert: the receive timestamp
+// set by the webhook parsers, else the time it was handed to publishing,
+// else the time its job was submitted.
+func ingestedAt(enrichedAlert *core.EnrichedAlert, submittedAt time.Time) time.Time {
+	if alert := enrichedAlert.Alert; alert != nil && alert.Timestamp != nil {
+		return *alert.Timestamp
+	}
+	if ts := enrichedAlert.ProcessingTimestamp; ts != nil {
+		return *ts
+	}
+	return submittedAt
+}
+
+// recordDeliverySLO records the end-to-end delivery of the firing alerts of
+// job once it is acknowledged (delivered) or has finally failed.
+func (q *PublishingQueue) recordDeliverySLO(job *PublishingJob, delivered bool) {
+	if q.metrics == nil {
+		return
+	}
+	for _, enrichedAlert := range job.alerts() {
+		q.recordAlertDeliverySLO(job, enrichedAlert, delivered)
+	}
+}
+
+func (q *PublishingQueue) recordAlertDeliverySLO(job *PublishingJob, enrichedAlert *core.EnrichedAlert, delivered bool) {
+	if enrichedAlert == nil || enrichedAlert.Alert == nil {
+		return
+	}
+	if enrichedAlert.Alert.Status != core.StatusFiring {
+		return
+	}
+	severity := alertSeverity(enrichedAlert)
+	threshold, ok := q.deliverySLO.threshold(severity)
+	if !ok {
+		return
+	}
+
+	if !delivered {
+		q.metrics.RecordNotificationSLO(job.Target.Name, severity, SLOResultFailed)
+		return
+	}
+
+	latency := time.Since(ingestedAt(enrichedAlert, job.SubmittedAt))
+	result := SLOResultMet
+	if latency > threshold {
+		result = SLOResultBreached
+		q.logger.Warn("Notification delivered after SLO deadline",
+			"target", job.Target.Name,
+			"fingerprint", enrichedAlert.Alert.Fingerprint,
+			"severity", severity,
+			"latency", latency,
+			"threshold", threshold,
+		)
+	}
+	q.metrics.RecordNotificationLatency(job.Target.Name, severity, latency)
+	q.metrics.RecordNotificationSLO(job.Target.Name, severity, result)
+}
@@ -0,0 +1,120 @@
+package publishing
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+func TestDeliverySLO_Threshold(t *testing.T) {
+	slo := DeliverySLO{
+		Thresholds:       map[string]time.Duration{"Critical": 30 * time.Second},
+		DefaultThreshold: 5 * time.Minute,
+	}
+	if got, ok := slo.threshold("critical"); !ok || got != 30*time.Second {
+		t.Errorf("threshold(critical) = %v, %v, want 30s", got, ok)
+	}
+	if got, ok := slo.threshold("info"); !ok || got != 5*time.Minute {
+		t.Errorf("threshold(info) = %v, %v, want 5m", got, ok)
+	}
+
+	slo.DefaultThreshold = 0
+	if _, ok := slo.threshold("info"); ok {
+		t.Error("threshold(info) tracked without default threshold")
+	}
+}
+
+func TestDeliverySLO_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		slo     DeliverySLO
+		wantErr bool
+	}{
+		{"disabled", DeliverySLO{}, false},
+		{"default", DefaultDeliverySLO, false},
+		{"negative default", DeliverySLO{DefaultThreshold: -time.Second}, true},
+		{"zero threshold", DeliverySLO{Thresholds: map[string]time.Duration{"critical": 0}}, true},
+		{"empty severity", DeliverySLO{Thresholds: map[string]time.Duration{"": time.Second}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.slo.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublishingQueue_RecordDeliverySLO(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	queue := NewPublishingQueue(
+		NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, ""),
+		nil,
+		nil,
+		PublishingQueueConfig{
+			WorkerCount:             1,
+			HighPriorityQueueSize:   1,
+			MediumPriorityQueueSize: 1,
+			LowPriorityQueueSize:    1,
+			Metrics:                 v2.NewRegistry(v2.WithPrometheusRegisterer(registry)).Publishing,
+			DeliverySLO:             DeliverySLO{Thresholds: map[string]time.Duration{"critical": 30 * time.Second}},
+		},
+		nil,
+		slog.Default(),
+	)
+	target := &core.PublishingTarget{Name: "oncall", Type: "webhook"}
+
+	newJob := func(severity string, status core.AlertStatus, ingested time.Time) *PublishingJob {
+		alert := shedTestAlert(severity, status)
+		alert.Alert.Timestamp = &ingested
+		return &PublishingJob{EnrichedAlert: alert, Target: target, SubmittedAt: time.Now()}
+	}
+
+	queue.recordDeliverySLO(newJob("critical", core.StatusFiring, time.Now().Add(-time.Second)), true)
+	queue.recordDeliverySLO(newJob("critical", core.StatusFiring, time.Now().Add(-time.Minute)), true)
+	queue.recordDeliverySLO(newJob("critical", core.StatusFiring, time.Now()), false)
+	// Not tracked: resolved, and severities without a threshold
+	queue.recordDeliverySLO(newJob("critical", core.StatusResolved, time.Now().Add(-time.Hour)), true)
+	queue.recordDeliverySLO(newJob("info", core.StatusFiring, time.Now().Add(-time.Hour)), true)
+
+	metric := "alert_history_publishing_notification_slo_total"
+	if n := testutil.CollectAndCount(registry, metric); n != 3 {
+		t.Fatalf("%s series = %d, want 3", metric, n)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != metric {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["target"] != "oncall" || labels["severity"] != "critical" {
+				t.Errorf("%s labels = %v, want oncall/critical", metric, labels)
+			}
+			results[labels["result"]] = m.GetCounter().GetValue()
+		}
+	}
+	want := map[string]float64{SLOResultMet: 1, SLOResultBreached: 1, SLOResultFailed: 1}
+	for result, n := range want {
+		if results[result] != n {
+			t.Errorf("%s{result=%q} = %v, want %v", metric, result, results[result], n)
+		}
+	}
+
+	if n := testutil.CollectAndCount(registry, "alert_history_publishing_notification_latency_seconds"); n != 1 {
+		t.Errorf("latency series = %d, want 1", n)
+	}
+}
@@ -0,0 +1,196 @@
+package publishing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Target headers limiting how fast the workers publish to a target. They
+// override the limits configured for the target type and are never sent.
+const (
+	targetRateLimitHeader      = "rate_limit"      // publishes per second
+	targetRateBurstHeader      = "rate_burst"      // default 1
+	targetMaxConcurrencyHeader = "max_concurrency" // publishes in flight
+)
+
+const maxTargetConcurrency = 1000
+
+// Target limits (metric label)
+const (
+	TargetLimitRate        = "rate"
+	TargetLimitConcurrency = "concurrency"
+)
+
+// TargetLimit bounds the publishes of a target. Zero fields are unlimited.
+type TargetLimit struct {
+	// RateLimit is the number of publishes per second.
+	RateLimit float64
+
+	// Burst is the number of publishes allowed at once above RateLimit
+	// (default: 1).
+	Burst int
+
+	// MaxConcurrency is the number of publishes in flight.
+	MaxConcurrency int
+}
+
+// Enabled reports whether the limit bounds anything.
+func (l TargetLimit) Enabled() bool {
+	return l.RateLimit > 0 || l.MaxConcurrency > 0
+}
+
+// Validate checks the limit values.
+func (l TargetLimit) Validate() error {
+	if l.RateLimit < 0 {
+		return fmt.Errorf("rate limit must be non-negative, got %v", l.RateLimit)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("burst must be non-negative, got %d", l.Burst)
+	}
+	if l.MaxConcurrency < 0 || l.MaxConcurrency > maxTargetConcurrency {
+		return fmt.Errorf("max concurrency must be between 0 and %d, got %d", maxTargetConcurrency, l.MaxConcurrency)
+	}
+	return nil
+}
+
+// ParseTargetLimit reads the limit headers of target over base, the limit
+// of its type.
+func ParseTargetLimit(target *core.PublishingTarget, base TargetLimit) (TargetLimit, error) {
+	limit := base
+	if raw, ok := target.Headers[targetRateLimitHeader]; ok {
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || value < 0 {
+			return TargetLimit{}, fmt.Errorf("invalid %s %q: must be a non-negative number of publishes per second", targetRateLimitHeader, raw)
+		}
+		limit.RateLimit = value
+	}
+	if raw, ok := target.Headers[targetRateBurstHeader]; ok {
+		value, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || value < 1 {
+			return TargetLimit{}, fmt.Errorf("invalid %s %q: must be a positive integer", targetRateBurstHeader, raw)
+		}
+		limit.Burst = value
+	}
+	if raw, ok := target.Headers[targetMaxConcurrencyHeader]; ok {
+		value, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || value < 0 || value > maxTargetConcurrency {
+			return TargetLimit{}, fmt.Errorf("invalid %s %q: must be between 0 and %d", targetMaxConcurrencyHeader, raw, maxTargetConcurrency)
+		}
+		limit.MaxConcurrency = value
+	}
+	return limit, nil
+}
+
+// ValidateTargetLimits checks the limit headers of target, if any.
+func ValidateTargetLimits(target *core.PublishingTarget) error {
+	_, err := ParseTargetLimit(target, TargetLimit{})
+	return err
+}
+
+// targetLimiters holds the rate limiter and concurrency slots of every
+// limited target, shared by the workers.
+type targetLimiters struct {
+	byType map[string]TargetLimit // target type -> limit
+
+	mu      sync.Mutex
+	targets map[string]*targetLimiter // by target name
+}
+
+type targetLimiter struct {
+	limit TargetLimit
+	rate  *rate.Limiter // nil: no rate limit
+	slots chan struct{} // nil: no concurrency limit
+}
+
+func newTargetLimiters(byType map[string]TargetLimit) *targetLimiters {
+	return &targetLimiters{
+		byType:  byType,
+		targets: make(map[string]*targetLimiter),
+	}
+}
+
+// get returns the limiter of target, or nil when it is unlimited. The
+// limiter is rebuilt when the limit of the target changed.
+func (l *targetLimiters) get(target *core.PublishingTarget) *targetLimiter {
+	base := l.byType[target.Type]
+	limit, err := ParseTargetLimit(target, base)
+	if err != nil {
+		// Rejected by discovery validation; keep the type limit
+		limit = base
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !limit.Enabled() {
+		delete(l.targets, target.Name)
+		return nil
+	}
+	if limiter, ok := l.targets[target.Name]; ok && limiter.limit == limit {
+		return limiter
+	}
+
+	limiter := &targetLimiter{limit: limit}
+	if limit.RateLimit > 0 {
+		limiter.rate = rate.NewLimiter(rate.Limit(limit.RateLimit), max(limit.Burst, 1))
+	}
+	if limit.MaxConcurrency > 0 {
+		limiter.slots = make(chan struct{}, limit.MaxConcurrency)
+	}
+	l.targets[target.Name] = limiter
+	return limiter
+}
+
+func (l *targetLimiters) forget(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.targets, name)
+}
+
+// acquireTargetLimits blocks until target has a free concurrency slot and
+// its rate limit allows a publish, or the queue is stopped. The returned
+// function releases the slot.
+func (q *PublishingQueue) acquireTargetLimits(target *core.PublishingTarget) (func(), error) {
+	limiter := q.limiters.get(target)
+	if limiter == nil {
+		return func() {}, nil
+	}
+
+	release := func() {}
+	if slots := limiter.slots; slots != nil {
+		select {
+		case slots <- struct{}{}:
+		default:
+			start := time.Now()
+			select {
+			case slots <- struct{}{}:
+			case <-q.ctx.Done():
+				return nil, q.ctx.Err()
+			}
+			q.recordTargetLimitWait(target.Name, TargetLimitConcurrency, time.Since(start))
+		}
+		release = func() { <-slots }
+	}
+
+	if limiter.rate != nil && !limiter.rate.Allow() {
+		start := time.Now()
+		if err := limiter.rate.Wait(q.ctx); err != nil {
+			release()
+			return nil, err
+		}
+		q.recordTargetLimitWait(target.Name, TargetLimitRate, time.Since(start))
+	}
+	return release, nil
+}
+
+func (q *PublishingQueue) recordTargetLimitWait(target, limit string, wait time.Duration) {
+	if q.metrics != nil {
+		q.metrics.RecordTargetLimitWait(target, limit, wait)
+	}
+}
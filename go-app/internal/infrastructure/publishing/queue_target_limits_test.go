@@ -0,0 +1,200 @@
+package publishing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// blockingPublisher holds every publish until release is closed and records
+// the most publishes in flight at once.
+type blockingPublisher struct {
+	release  chan struct{}
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		seen := p.maxSeen.Load()
+		if n <= seen || p.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	<-p.release
+	return nil
+}
+
+func (p *blockingPublisher) Name() string { return "blocking" }
+
+type limitPanicPublisher struct{}
+
+func (limitPanicPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	panic("publisher bug")
+}
+
+func (limitPanicPublisher) Name() string { return "panic" }
+
+func TestParseTargetLimit(t *testing.T) {
+	base := TargetLimit{RateLimit: 1, Burst: 2}
+
+	limit, err := ParseTargetLimit(&core.PublishingTarget{}, base)
+	if err != nil || limit != base {
+		t.Fatalf("ParseTargetLimit() = %+v, %v, want type limit %+v", limit, err, base)
+	}
+
+	limit, err = ParseTargetLimit(&core.PublishingTarget{Headers: map[string]string{
+		"rate_limit":      "0.5",
+		"max_concurrency": "3",
+	}}, base)
+	if err != nil {
+		t.Fatalf("ParseTargetLimit() error = %v", err)
+	}
+	if want := (TargetLimit{RateLimit: 0.5, Burst: 2, MaxConcurrency: 3}); limit != want {
+		t.Errorf("ParseTargetLimit() = %+v, want %+v", limit, want)
+	}
+
+	for _, headers := range []map[string]string{
+		{"rate_limit": "fast"},
+		{"rate_limit": "-1"},
+		{"rate_burst": "0"},
+		{"max_concurrency": "10000"},
+	} {
+		if err := ValidateTargetLimits(&core.PublishingTarget{Headers: headers}); err == nil {
+			t.Errorf("ValidateTargetLimits(%v) succeeded", headers)
+		}
+	}
+}
+
+func TestWebhookHeaders_DropLimitHeaders(t *testing.T) {
+	headers := targetHTTPHeaders(map[string]string{
+		"rate_limit":      "1",
+		"rate_burst":      "2",
+		"max_concurrency": "1",
+		"X-Team":          "sre",
+	})
+	if len(headers) != 1 || headers["X-Team"] != "sre" {
+		t.Errorf("targetHTTPHeaders() = %v, want only X-Team", headers)
+	}
+}
+
+func TestPublishingQueue_TargetRateLimit(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+	queue.limiters = newTargetLimiters(map[string]TargetLimit{
+		ProviderSlack: {RateLimit: 10},
+	})
+
+	slack := &scriptedPublisher{}
+	for range 3 {
+		if err := queue.retryPublish(slack, cooldownTestJob("slack", ProviderSlack)); err != nil {
+			t.Fatalf("retryPublish() error = %v", err)
+		}
+	}
+	// Burst 1 at 10/s: the third publish is due 200ms after the first
+	if spread := slack.calls[2].Sub(slack.calls[0]); spread < 150*time.Millisecond {
+		t.Errorf("3 publishes at 10/s took %v", spread)
+	}
+
+	// The header overrides the limit of the type; other types are unlimited
+	fast := cooldownTestJob("slack-fast", ProviderSlack)
+	fast.Target.Headers = map[string]string{"rate_limit": "0"}
+	webhook := &scriptedPublisher{}
+	start := time.Now()
+	for range 3 {
+		if err := queue.retryPublish(webhook, fast); err != nil {
+			t.Fatalf("retryPublish() error = %v", err)
+		}
+		if err := queue.retryPublish(webhook, cooldownTestJob("webhook", ProviderWebhook)); err != nil {
+			t.Fatalf("retryPublish() error = %v", err)
+		}
+	}
+	if waited := time.Since(start); waited >= 100*time.Millisecond {
+		t.Errorf("unlimited targets waited %v", waited)
+	}
+}
+
+func TestPublishingQueue_TargetMaxConcurrency(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+
+	publisher := &blockingPublisher{release: make(chan struct{})}
+	target := &core.PublishingTarget{
+		Name:    "pagerduty",
+		Type:    ProviderPagerDuty,
+		Headers: map[string]string{"max_concurrency": "2"},
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job := &PublishingJob{EnrichedAlert: panicTestAlert(), Target: target}
+			if err := queue.retryPublish(publisher, job); err != nil {
+				t.Errorf("retryPublish() error = %v", err)
+			}
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for publisher.inFlight.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(publisher.release)
+	wg.Wait()
+
+	if got := publisher.maxSeen.Load(); got != 2 {
+		t.Errorf("max publishes in flight = %d, want 2", got)
+	}
+}
+
+func TestPublishingQueue_TargetSlotReleasedOnPanic(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+
+	job := &PublishingJob{
+		EnrichedAlert: panicTestAlert(),
+		Target: &core.PublishingTarget{
+			Name:    "webhook",
+			Type:    ProviderWebhook,
+			Headers: map[string]string{"max_concurrency": "1"},
+		},
+	}
+	func() {
+		defer func() { _ = recover() }()
+		_ = queue.retryPublish(limitPanicPublisher{}, job)
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- queue.retryPublish(&scriptedPublisher{}, job) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("retryPublish() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("slot of the panicked publish was not released")
+	}
+}
+
+func TestPublishingQueue_RemoveTargetForgetsLimits(t *testing.T) {
+	queue := newCooldownTestQueue(time.Millisecond)
+	defer queue.cancel()
+
+	target := &core.PublishingTarget{Name: "slack", Type: ProviderSlack, Headers: map[string]string{"rate_limit": "1"}}
+	if queue.limiters.get(target) == nil {
+		t.Fatal("limited target has no limiter")
+	}
+	queue.RemoveTarget("slack")
+	if _, ok := queue.limiters.targets["slack"]; ok {
+		t.Error("limiter of removed target kept")
+	}
+}
@@ -0,0 +1,112 @@
+package publishing
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// ErrTargetRemoved is the error of jobs drained because their target was
+// removed from discovery.
+var ErrTargetRemoved = errors.New("publishing target removed")
+
+// RemoveTarget forgets a target removed from discovery: its circuit breaker,
+// limits, provider cooldown, delivered alerts and scheduled jobs are dropped,
+// its open batch is flushed, and jobs still outstanding for it (queued, in
+// flight or requeued after a cooldown) are drained to the DLQ instead of
+// published. Jobs in flight finish their current attempt.
+func (q *PublishingQueue) RemoveTarget(name string) {
+	q.unscheduleTarget(name)
+
+	q.mu.Lock()
+	delete(q.circuitBreakers, name)
+	q.removedTargets[name] = struct{}{}
+	q.limiters.forget(name)
+	q.dedup.forget(name)
+	q.mu.Unlock()
+
+	// Webhook targets share the cooldown of their host with other targets
+	if q.cooldowns.forget(name) && q.metrics != nil {
+		q.metrics.SetProviderPausedUntil(name, time.Time{})
+	}
+	q.batcher.forget(name)
+}
+
+// TargetNames returns the targets the queue keeps state for.
+func (q *PublishingQueue) TargetNames() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	names := make([]string, 0, len(q.circuitBreakers))
+	for name := range q.circuitBreakers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// restoreTargets forgets the removal of targets that are active again, and
+// of those with no outstanding job left to drain. Jobs stored by a durable
+// queue but not claimed by this replica are not tracked.
+func (q *PublishingQueue) restoreTargets(active map[string]bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for name := range q.removedTargets {
+		if active[name] || q.targetJobs[name] == 0 {
+			delete(q.removedTargets, name)
+		}
+	}
+}
+
+func (q *PublishingQueue) isTargetRemoved(name string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	_, removed := q.removedTargets[name]
+	return removed
+}
+
+// trackTargetJob counts the outstanding jobs of a target, which keep its
+// removal until they are drained.
+func (q *PublishingQueue) trackTargetJob(name string, delta int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if n := q.targetJobs[name] + delta; n > 0 {
+		q.targetJobs[name] = n
+	} else {
+		delete(q.targetJobs, name)
+	}
+}
+
+// drainRemovedTargetJob moves a job of a removed target to the DLQ without
+// publishing it.
+func (q *PublishingQueue) drainRemovedTargetJob(job *PublishingJob) {
+	now := time.Now()
+	job.State = JobStateFailed
+	job.CompletedAt = &now
+	job.LastError = fmt.Errorf("%w: %s", ErrTargetRemoved, job.Target.Name)
+	job.ErrorType = QueueErrorTypePermanent
+	q.totalFailed.Add(1)
+	q.recordDelivery(job, 1, core.DeliveryStatusTargetRemoved, now, 0, job.LastError, nil)
+	q.recordOutcome(job, false)
+
+	if q.dlqRepository != nil {
+		job.State = JobStateDLQ
+		if err := q.writeDLQ(job); err != nil {
+			q.logger.Error("Failed to write job of removed target to DLQ",
+				"job_id", job.ID,
+				"target", job.Target.Name,
+				"error", err,
+			)
+		}
+	}
+	q.logger.Info("Job of removed target drained",
+		"job_id", job.ID,
+		"target", job.Target.Name,
+		"fingerprint", job.EnrichedAlert.Alert.Fingerprint,
+		"dlq", job.State == JobStateDLQ,
+	)
+
+	if q.jobTrackingStore != nil {
+		q.jobTrackingStore.Add(job)
+	}
+}
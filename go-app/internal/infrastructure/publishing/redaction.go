@@ -0,0 +1,304 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Target headers redacting the alerts published to a target, so that
+// secrets in labels and annotations never leave the cluster. They are never
+// sent.
+//
+//   - redact_labels, redact_annotations: comma-separated names (or globs,
+//     e.g. "secret_*") of labels and annotations dropped from the alert
+//   - redact_scrubbers: comma-separated built-in scrubbers (tokens, ips,
+//     emails) replacing matches in every published text with [REDACTED]
+//   - redact_pattern: a regular expression scrubbed the same way
+const (
+	targetRedactLabelsHeader      = "redact_labels"
+	targetRedactAnnotationsHeader = "redact_annotations"
+	targetRedactScrubbersHeader   = "redact_scrubbers"
+	targetRedactPatternHeader     = "redact_pattern"
+)
+
+// redactedText replaces scrubbed text.
+const redactedText = "[REDACTED]"
+
+// scrubber replaces the matches of re with replacement, which may keep
+// submatches (e.g. the name of a "password=..." pair).
+type scrubber struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// redactionScrubbers are the built-in scrubbers by name, applied in this
+// order: credentials in URLs go before e-mail addresses they look like.
+var redactionScrubbers = []struct {
+	name      string
+	scrubbers []scrubber
+}{
+	{"tokens", []scrubber{
+		// Credentials in URLs and connection strings
+		{regexp.MustCompile(`(?i)\b([a-z][a-z0-9+.-]*://)[^/\s:@]+:[^/\s@]+@`), "${1}" + redactedText + "@"},
+		// Authorization header values
+		{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[a-z0-9\-._~+/]+=*`), "${1} " + redactedText},
+		// JSON web tokens
+		{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), redactedText},
+		// Well-known token formats: Slack, GitHub, GitLab, AWS access keys
+		{regexp.MustCompile(`\b(?:xox[abposr]-[A-Za-z0-9-]+|gh[pousr]_[A-Za-z0-9]{20,}|glpat-[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16})\b`), redactedText},
+		// password=..., api_key: ... and the like
+		{regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key)(\s*[=:]\s*)[^\s,;&"']+`), "${1}${2}" + redactedText},
+	}},
+	{"emails", []scrubber{
+		{regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`), redactedText},
+	}},
+	{"ips", []scrubber{
+		{regexp.MustCompile(`\b(?:25[0-5]|2[0-4]\d|1?\d?\d)(?:\.(?:25[0-5]|2[0-4]\d|1?\d?\d)){3}\b`), redactedText},
+		{regexp.MustCompile(`(?i)\b(?:[0-9a-f]{1,4}:){7}[0-9a-f]{1,4}\b`), redactedText},
+		{regexp.MustCompile(`(?i)\b(?:[0-9a-f]{1,4}:){1,7}:(?:[0-9a-f]{1,4}(?::[0-9a-f]{1,4}){0,6})?`), redactedText},
+	}},
+}
+
+// TargetRedaction is the redaction of the alerts published to a target.
+type TargetRedaction struct {
+	labels      []string // globs of dropped labels
+	annotations []string // globs of dropped annotations
+	scrubbers   []scrubber
+}
+
+// ParseTargetRedaction reads the redaction headers of target. It returns
+// nil when the target redacts nothing.
+func ParseTargetRedaction(target *core.PublishingTarget) (*TargetRedaction, error) {
+	var r TargetRedaction
+	var err error
+	if r.labels, err = parseRedactionGlobs(target.Headers[targetRedactLabelsHeader], targetRedactLabelsHeader); err != nil {
+		return nil, err
+	}
+	if r.annotations, err = parseRedactionGlobs(target.Headers[targetRedactAnnotationsHeader], targetRedactAnnotationsHeader); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, name := range splitRedactionList(target.Headers[targetRedactScrubbersHeader]) {
+		names[name] = true
+	}
+	for _, builtin := range redactionScrubbers {
+		if names[builtin.name] {
+			r.scrubbers = append(r.scrubbers, builtin.scrubbers...)
+			delete(names, builtin.name)
+		}
+	}
+	for name := range names {
+		return nil, fmt.Errorf("invalid %s: unknown scrubber %q (want tokens, emails or ips)", targetRedactScrubbersHeader, name)
+	}
+
+	if pattern := strings.TrimSpace(target.Headers[targetRedactPatternHeader]); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", targetRedactPatternHeader, err)
+		}
+		r.scrubbers = append(r.scrubbers, scrubber{re: re, replacement: redactedText})
+	}
+
+	if len(r.labels) == 0 && len(r.annotations) == 0 && len(r.scrubbers) == 0 {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+// ValidateTargetRedaction checks the redaction headers of target, if any.
+func ValidateTargetRedaction(target *core.PublishingTarget) error {
+	_, err := ParseTargetRedaction(target)
+	return err
+}
+
+func splitRedactionList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseRedactionGlobs(raw, header string) ([]string, error) {
+	globs := splitRedactionList(raw)
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s: %q: %w", header, glob, err)
+		}
+	}
+	return globs, nil
+}
+
+// dropped reports whether name matches one of globs.
+func dropped(globs []string, name string) bool {
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// scrub applies the scrubbers to s.
+func (r *TargetRedaction) scrub(s string) string {
+	for _, sc := range r.scrubbers {
+		s = sc.re.ReplaceAllString(s, sc.replacement)
+	}
+	return s
+}
+
+// redactPairs returns pairs without the dropped names, values scrubbed.
+func (r *TargetRedaction) redactPairs(pairs map[string]string, globs []string) map[string]string {
+	if pairs == nil {
+		return nil
+	}
+	out := make(map[string]string, len(pairs))
+	for k, v := range pairs {
+		if !dropped(globs, k) {
+			out[k] = r.scrub(v)
+		}
+	}
+	return out
+}
+
+// Alert returns a redacted copy of enrichedAlert: dropped labels and
+// annotations removed, and its texts (label and annotation values,
+// generator URL, classification reasoning and recommendations, enrichment
+// metadata) scrubbed. The fingerprint is kept.
+func (r *TargetRedaction) Alert(enrichedAlert *core.EnrichedAlert) *core.EnrichedAlert {
+	if r == nil || enrichedAlert == nil || enrichedAlert.Alert == nil {
+		return enrichedAlert
+	}
+
+	alert := *enrichedAlert.Alert
+	alert.AlertName = r.scrub(alert.AlertName)
+	alert.Labels = r.redactPairs(alert.Labels, r.labels)
+	alert.Annotations = r.redactPairs(alert.Annotations, r.annotations)
+	if alert.GeneratorURL != nil {
+		generatorURL := r.scrub(*alert.GeneratorURL)
+		alert.GeneratorURL = &generatorURL
+	}
+
+	known := enrichedAlert.KnownLabels()
+	label := func(name, value string) string {
+		if dropped(r.labels, name) {
+			return ""
+		}
+		return r.scrub(value)
+	}
+	known.AlertName = label(core.LabelAlertName, known.AlertName)
+	known.Namespace = label(core.LabelNamespace, known.Namespace)
+	known.Severity = label(core.LabelSeverity, known.Severity)
+	known.Team = label(core.LabelTeam, known.Team)
+	known.Service = label(core.LabelService, known.Service)
+	known.Environment = label(core.LabelEnvironment, known.Environment)
+	known.Region = label(core.LabelRegion, known.Region)
+
+	redacted := enrichedAlert.WithAlert(&alert, known)
+	if classification := enrichedAlert.Classification; classification != nil {
+		c := *classification
+		c.Reasoning = r.scrub(c.Reasoning)
+		c.Recommendations = make([]string, len(classification.Recommendations))
+		for i, recommendation := range classification.Recommendations {
+			c.Recommendations[i] = r.scrub(recommendation)
+		}
+		redacted.Classification = &c
+	}
+	if enrichedAlert.EnrichmentMetadata != nil {
+		redacted.EnrichmentMetadata, _ = r.scrubValue(enrichedAlert.EnrichmentMetadata).(map[string]any)
+	}
+	return redacted
+}
+
+// Payload returns payload with every text scrubbed.
+func (r *TargetRedaction) Payload(payload map[string]any) map[string]any {
+	if r == nil || len(r.scrubbers) == 0 || payload == nil {
+		return payload
+	}
+	scrubbed, _ := r.scrubValue(payload).(map[string]any)
+	return scrubbed
+}
+
+// scrubValue returns a scrubbed copy of v, a formatted payload value.
+func (r *TargetRedaction) scrubValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return r.scrub(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, value := range v {
+			out[k] = r.scrubValue(value)
+		}
+		return out
+	case map[string]string:
+		return r.redactPairs(v, nil)
+	case []any:
+		out := make([]any, len(v))
+		for i, value := range v {
+			out[i] = r.scrubValue(value)
+		}
+		return out
+	case []string:
+		out := make([]string, len(v))
+		for i, value := range v {
+			out[i] = r.scrub(value)
+		}
+		return out
+	case []map[string]any:
+		out := make([]map[string]any, len(v))
+		for i, value := range v {
+			out[i], _ = r.scrubValue(value).(map[string]any)
+		}
+		return out
+	case []map[string]string:
+		out := make([]map[string]string, len(v))
+		for i, value := range v {
+			out[i] = r.redactPairs(value, nil)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+type redactionKey struct{}
+
+// withRedaction attaches redaction to ctx; the formatter redacts the alerts
+// and scrubs the payloads it formats with it. A nil redaction detaches it.
+func withRedaction(ctx context.Context, redaction *TargetRedaction) context.Context {
+	return context.WithValue(ctx, redactionKey{}, redaction)
+}
+
+// redactionFromContext returns the redaction attached by withRedaction, or
+// nil.
+func redactionFromContext(ctx context.Context) *TargetRedaction {
+	redaction, _ := ctx.Value(redactionKey{}).(*TargetRedaction)
+	return redaction
+}
+
+// redactForTarget returns ctx carrying the redaction of target and alerts
+// redacted for it, for publishers that read the alerts directly. Invalid
+// headers are rejected by target discovery; should some get here anyway,
+// publishing fails rather than sending unredacted alerts.
+func redactForTarget(ctx context.Context, target *core.PublishingTarget, alerts []*core.EnrichedAlert) (context.Context, []*core.EnrichedAlert, error) {
+	redaction, err := ParseTargetRedaction(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	if redaction == nil {
+		return ctx, alerts, nil
+	}
+	redacted := make([]*core.EnrichedAlert, len(alerts))
+	for i, alert := range alerts {
+		redacted[i] = redaction.Alert(alert)
+	}
+	return withRedaction(ctx, redaction), redacted, nil
+}
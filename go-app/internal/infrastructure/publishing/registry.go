@@ -108,7 +108,7 @@ type DefaultFormatRegistry struct {
 //
 // Returns:
 //
-//	FormatRegistry: Registry pre-loaded with 5 standard formats
+//	FormatRegistry: Registry pre-loaded with 13 standard formats
 func NewDefaultFormatRegistry() FormatRegistry {
 	r := &DefaultFormatRegistry{
 		formats:   make(map[core.PublishingFormat]formatFunc, 10),
@@ -121,7 +121,7 @@ func NewDefaultFormatRegistry() FormatRegistry {
 	return r
 }
 
-// registerBuiltins adds the 5 standard formats
+// registerBuiltins adds the 13 standard formats
 func (r *DefaultFormatRegistry) registerBuiltins() {
 	// Create formatter instance to access methods
 	baseFormatter := &DefaultAlertFormatter{}
@@ -131,6 +131,14 @@ func (r *DefaultFormatRegistry) registerBuiltins() {
 	baseFormatter.formatters[core.FormatPagerDuty] = baseFormatter.formatPagerDuty
 	baseFormatter.formatters[core.FormatSlack] = baseFormatter.formatSlack
 	baseFormatter.formatters[core.FormatWebhook] = baseFormatter.formatWebhook
+	baseFormatter.formatters[core.FormatTeams] = baseFormatter.formatTeams
+	baseFormatter.formatters[core.FormatOpsgenie] = baseFormatter.formatOpsgenie
+	baseFormatter.formatters[core.FormatKafka] = baseFormatter.formatKafka
+	baseFormatter.formatters[core.FormatJira] = baseFormatter.formatJira
+	baseFormatter.formatters[core.FormatGoogleChat] = baseFormatter.formatGoogleChat
+	baseFormatter.formatters[core.FormatMattermost] = baseFormatter.formatMattermost
+	baseFormatter.formatters[core.FormatAWS] = baseFormatter.formatKafka
+	baseFormatter.formatters[core.FormatExec] = baseFormatter.formatKafka
 
 	// Register formats without validation (built-ins are trusted)
 	r.formats[core.FormatAlertmanager] = baseFormatter.formatAlertmanager
@@ -138,6 +146,14 @@ func (r *DefaultFormatRegistry) registerBuiltins() {
 	r.formats[core.FormatPagerDuty] = baseFormatter.formatPagerDuty
 	r.formats[core.FormatSlack] = baseFormatter.formatSlack
 	r.formats[core.FormatWebhook] = baseFormatter.formatWebhook
+	r.formats[core.FormatTeams] = baseFormatter.formatTeams
+	r.formats[core.FormatOpsgenie] = baseFormatter.formatOpsgenie
+	r.formats[core.FormatKafka] = baseFormatter.formatKafka
+	r.formats[core.FormatJira] = baseFormatter.formatJira
+	r.formats[core.FormatGoogleChat] = baseFormatter.formatGoogleChat
+	r.formats[core.FormatMattermost] = baseFormatter.formatMattermost
+	r.formats[core.FormatAWS] = baseFormatter.formatKafka
+	r.formats[core.FormatExec] = baseFormatter.formatKafka
 
 	// Initialize reference counts
 	for format := range r.formats {
@@ -9,9 +9,15 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
 )
 
 // RootlyIncidentsClient defines interface for Rootly Incidents API v1
@@ -19,6 +25,9 @@ type RootlyIncidentsClient interface {
 	CreateIncident(ctx context.Context, req *CreateIncidentRequest) (*IncidentResponse, error)
 	UpdateIncident(ctx context.Context, id string, req *UpdateIncidentRequest) (*IncidentResponse, error)
 	ResolveIncident(ctx context.Context, id string, req *ResolveIncidentRequest) (*IncidentResponse, error)
+	// FindOpenIncident returns the open (not resolved) incident tagged with
+	// tag, or nil when there is none.
+	FindOpenIncident(ctx context.Context, tag string) (*IncidentResponse, error)
 }
 
 // ClientConfig holds configuration for Rootly API client
@@ -77,11 +86,11 @@ func NewRootlyIncidentsClient(config ClientConfig, logger *slog.Logger) RootlyIn
 	return &defaultRootlyIncidentsClient{
 		httpClient: &http.Client{
 			Timeout: config.Timeout,
-			Transport: &http.Transport{
+			Transport: newOutboundTransport(&http.Transport{
 				TLSClientConfig: &tls.Config{
 					MinVersion: tls.VersionTLS12, // TLS 1.2+
 				},
-			},
+			}),
 		},
 		baseURL:     config.BaseURL,
 		apiKey:      config.APIKey,
@@ -281,6 +290,61 @@ func (c *defaultRootlyIncidentsClient) ResolveIncident(
 	return &incidentResp, nil
 }
 
+// FindOpenIncident searches the open incidents for one tagged with tag
+func (c *defaultRootlyIncidentsClient) FindOpenIncident(
+	ctx context.Context,
+	tag string,
+) (*IncidentResponse, error) {
+	// Wait for rate limiter
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter: %w", err)
+	}
+
+	query := url.Values{}
+	query.Set("filter[search]", tag)
+	query.Set("filter[status]", "started")
+	query.Set("page[size]", "10")
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		c.baseURL+"/incidents?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	// Set headers
+	c.setHeaders(httpReq)
+
+	// Execute with retry
+	resp, err := c.doRequestWithRetry(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Parse response
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var listResp IncidentListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	// The search is full-text: only trust incidents carrying the exact tag
+	for _, incident := range listResp.Data {
+		if incident.Attributes.Status != "resolved" && slices.Contains(incident.Attributes.Tags, tag) {
+			return &IncidentResponse{Data: incident}, nil
+		}
+	}
+	return nil, nil
+}
+
 // doRequestWithRetry executes HTTP request with exponential backoff retry
 func (c *defaultRootlyIncidentsClient) doRequestWithRetry(req *http.Request) (*http.Response, error) {
 	var resp *http.Response
@@ -299,13 +363,8 @@ func (c *defaultRootlyIncidentsClient) doRequestWithRetry(req *http.Request) (*h
 		// Execute request
 		resp, err = c.httpClient.Do(req)
 
-		// Success (2xx or non-retryable error)
-		if err == nil && !isRetryableStatus(resp.StatusCode) {
-			return resp, nil
-		}
-
-		// Permanent error (4xx except 429)
-		if err == nil && isPermanentError(resp.StatusCode) {
+		// Success (2xx) or permanent error (4xx except 429)
+		if err == nil && !httperror.IsRetryableStatus(resp.StatusCode) {
 			return resp, nil
 		}
 
@@ -388,16 +447,6 @@ func (c *defaultRootlyIncidentsClient) parseError(resp *http.Response) error {
 
 // Helper functions
 
-// isRetryableStatus checks if HTTP status is retryable
-func isRetryableStatus(code int) bool {
-	return code == http.StatusTooManyRequests || code >= 500
-}
-
-// isPermanentError checks if HTTP status is permanent error
-func isPermanentError(code int) bool {
-	return code >= 400 && code < 500 && code != http.StatusTooManyRequests
-}
-
 // getStatusCode safely gets status code from response
 func getStatusCode(resp *http.Response) int {
 	if resp == nil {
@@ -405,3 +454,41 @@ func getStatusCode(resp *http.Response) int {
 	}
 	return resp.StatusCode
 }
+
+// rootlyAPIKey returns the Rootly API key of target, from its
+// Authorization header.
+func rootlyAPIKey(target *core.PublishingTarget) string {
+	return strings.TrimPrefix(target.Headers["Authorization"], "Bearer ")
+}
+
+// rootlyClients caches Rootly clients by API URL and key.
+type rootlyClients struct {
+	*clientCache[RootlyIncidentsClient]
+	logger *slog.Logger
+}
+
+func newRootlyClients(logger *slog.Logger) *rootlyClients {
+	return &rootlyClients{
+		clientCache: newClientCache[RootlyIncidentsClient](func(target *core.PublishingTarget) string {
+			return target.URL + "\x00" + rootlyAPIKey(target)
+		}),
+		logger: logger,
+	}
+}
+
+// get returns the client for target, nil when the target has no API key.
+// Every API key gets its own client, so that each is rate limited
+// separately.
+func (c *rootlyClients) get(target *core.PublishingTarget) RootlyIncidentsClient {
+	apiKey := rootlyAPIKey(target)
+	if apiKey == "" {
+		return nil
+	}
+	return c.getOrCreate(target, func() RootlyIncidentsClient {
+		return NewRootlyIncidentsClient(ClientConfig{
+			BaseURL: target.URL,
+			APIKey:  apiKey,
+			Timeout: 10 * time.Second,
+		}, c.logger)
+	})
+}
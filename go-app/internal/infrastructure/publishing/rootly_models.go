@@ -75,19 +75,28 @@ func (r *ResolveIncidentRequest) Validate() error {
 
 // IncidentResponse represents response from Rootly API
 type IncidentResponse struct {
-	Data struct {
-		ID         string `json:"id"`   // Incident ID (e.g., "01HKXYZ...")
-		Type       string `json:"type"` // "incidents"
-		Attributes struct {
-			Title      string     `json:"title"`
-			Severity   string     `json:"severity"`
-			StartedAt  time.Time  `json:"started_at"`
-			Status     string     `json:"status"` // "started", "resolved"
-			CreatedAt  time.Time  `json:"created_at"`
-			UpdatedAt  time.Time  `json:"updated_at,omitempty"`
-			ResolvedAt *time.Time `json:"resolved_at,omitempty"`
-		} `json:"attributes"`
-	} `json:"data"`
+	Data IncidentData `json:"data"`
+}
+
+// IncidentData represents a Rootly incident resource
+type IncidentData struct {
+	ID         string `json:"id"`   // Incident ID (e.g., "01HKXYZ...")
+	Type       string `json:"type"` // "incidents"
+	Attributes struct {
+		Title      string     `json:"title"`
+		Severity   string     `json:"severity"`
+		StartedAt  time.Time  `json:"started_at"`
+		Status     string     `json:"status"` // "started", "resolved"
+		Tags       []string   `json:"tags,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updated_at,omitempty"`
+		ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	} `json:"attributes"`
+}
+
+// IncidentListResponse represents a page of incidents from Rootly API
+type IncidentListResponse struct {
+	Data []IncidentData `json:"data"`
 }
 
 // GetID returns incident ID from response
@@ -9,11 +9,21 @@ import (
 	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
 )
 
+// rootlyFingerprintTagPrefix prefixes the tag carrying the alert
+// fingerprint on incidents created by AMP, used to find the open incident
+// of an alert that is not cached (after a restart or from another replica).
+const rootlyFingerprintTagPrefix = "amp_fingerprint:"
+
+// maxRootlyTags is the number of tags Rootly accepts on an incident.
+const maxRootlyTags = 20
+
 // EnhancedRootlyPublisher publishes alerts to Rootly with full incident lifecycle management
 type EnhancedRootlyPublisher struct {
 	*BaseEnhancedPublisher                       // Embedded base publisher for common functionality
-	client                 RootlyIncidentsClient // Rootly-specific incidents client
+	client                 RootlyIncidentsClient // Rootly-specific incidents client (nil: resolved per target)
+	clients                *rootlyClients        // Rootly clients by API URL and key
 	cache                  IncidentIDCache       // For tracking incident IDs (lifecycle)
+	fallback               AlertPublisher        // For targets without an API key
 }
 
 // NewEnhancedRootlyPublisher creates a new enhanced Rootly publisher
@@ -30,17 +40,53 @@ func NewEnhancedRootlyPublisher(
 			formatter,
 			logger.With("component", "rootly_publisher"),
 		),
-		client: client,
-		cache:  cache,
+		client:   client,
+		cache:    cache,
+		fallback: NewRootlyPublisher(formatter, logger),
+	}
+}
+
+// newEnhancedRootlyPublisher creates an enhanced Rootly publisher resolving
+// the client of each target from clients at publish time.
+func newEnhancedRootlyPublisher(clients *rootlyClients, cache IncidentIDCache, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedRootlyPublisher {
+	return &EnhancedRootlyPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "rootly_publisher"),
+		),
+		clients:  clients,
+		cache:    cache,
+		fallback: NewRootlyPublisher(formatter, logger),
 	}
 }
 
+// rootlyIncidentKey returns the incident cache key of an alert published to
+// a target: the same alert opens an incident per Rootly target.
+func rootlyIncidentKey(target, fingerprint string) string {
+	return target + "/" + fingerprint
+}
+
+// rootlyFingerprintTag returns the tag identifying the incident of an alert.
+func rootlyFingerprintTag(fingerprint string) string {
+	return rootlyFingerprintTagPrefix + fingerprint
+}
+
 // Publish implements AlertPublisher interface
 func (p *EnhancedRootlyPublisher) Publish(
 	ctx context.Context,
 	enrichedAlert *core.EnrichedAlert,
 	target *core.PublishingTarget,
 ) error {
+	client := p.client
+	if client == nil {
+		if client = p.clients.get(target); client == nil {
+			// Without an API key, post the alert to the target URL
+			// (e.g. a Rootly alert source webhook)
+			return p.fallback.Publish(ctx, enrichedAlert, target)
+		}
+	}
+
 	// Format alert for Rootly
 	payload, err := p.formatter.FormatAlert(ctx, enrichedAlert, core.FormatRootly)
 	if err != nil {
@@ -48,39 +94,77 @@ func (p *EnhancedRootlyPublisher) Publish(
 	}
 
 	// Route based on alert status
+	key := rootlyIncidentKey(target.Name, enrichedAlert.Alert.Fingerprint)
 	switch enrichedAlert.Alert.Status {
 	case core.StatusFiring:
-		return p.createOrUpdateIncident(ctx, enrichedAlert, payload)
+		return p.createOrUpdateIncident(ctx, client, key, enrichedAlert, payload)
 	case core.StatusResolved:
-		return p.resolveIncident(ctx, enrichedAlert)
+		return p.resolveIncident(ctx, client, key, enrichedAlert)
 	default:
 		return fmt.Errorf("unknown alert status: %s", enrichedAlert.Alert.Status)
 	}
 }
 
+// lookupIncident returns the ID of the open incident of an alert: cached,
+// or else found in Rootly by its fingerprint tag and cached.
+func (p *EnhancedRootlyPublisher) lookupIncident(
+	ctx context.Context,
+	client RootlyIncidentsClient,
+	key string,
+	enrichedAlert *core.EnrichedAlert,
+) (string, bool, error) {
+	if incidentID, exists := p.cache.Get(key); exists {
+		return incidentID, true, nil
+	}
+
+	resp, err := client.FindOpenIncident(ctx, rootlyFingerprintTag(enrichedAlert.Alert.Fingerprint))
+	if err != nil {
+		if p.metrics != nil {
+			p.metrics.RecordAPIError(v2.ProviderRootly, "incidents", GetPublishingErrorType(err))
+		}
+		return "", false, fmt.Errorf("find incident failed: %w", err)
+	}
+	if resp == nil {
+		return "", false, nil
+	}
+
+	incidentID := resp.GetID()
+	p.cache.Set(key, incidentID)
+	p.GetLogger().Info("Found open Rootly incident of alert",
+		"incident_id", incidentID,
+		"fingerprint", enrichedAlert.Alert.Fingerprint,
+	)
+	return incidentID, true, nil
+}
+
 // createOrUpdateIncident creates new incident or updates existing
 func (p *EnhancedRootlyPublisher) createOrUpdateIncident(
 	ctx context.Context,
+	client RootlyIncidentsClient,
+	key string,
 	enrichedAlert *core.EnrichedAlert,
 	payload map[string]interface{},
 ) error {
-	fingerprint := enrichedAlert.Alert.Fingerprint
-
-	// Check if incident exists in cache
-	incidentID, exists := p.cache.Get(fingerprint)
+	// Check if an incident is open for the alert
+	incidentID, exists, err := p.lookupIncident(ctx, client, key, enrichedAlert)
+	if err != nil {
+		return err
+	}
 
 	if exists {
 		// Update existing incident
-		return p.updateIncident(ctx, incidentID, enrichedAlert, payload)
+		return p.updateIncident(ctx, client, key, incidentID, enrichedAlert, payload)
 	}
 
 	// Create new incident
-	return p.createIncident(ctx, enrichedAlert, payload)
+	return p.createIncident(ctx, client, key, enrichedAlert, payload)
 }
 
 // createIncident creates a new Rootly incident
 func (p *EnhancedRootlyPublisher) createIncident(
 	ctx context.Context,
+	client RootlyIncidentsClient,
+	key string,
 	enrichedAlert *core.EnrichedAlert,
 	payload map[string]interface{},
 ) error {
@@ -92,9 +176,13 @@ func (p *EnhancedRootlyPublisher) createIncident(
 		StartedAt:   enrichedAlert.Alert.StartsAt,
 	}
 
-	// Add tags if present
+	// Add tags if present, after the fingerprint tag
+	req.Tags = []string{rootlyFingerprintTag(enrichedAlert.Alert.Fingerprint)}
 	if tags, ok := payload["tags"].([]string); ok {
-		req.Tags = tags
+		req.Tags = append(req.Tags, tags...)
+	}
+	if len(req.Tags) > maxRootlyTags {
+		req.Tags = req.Tags[:maxRootlyTags]
 	}
 
 	// Add custom fields if present
@@ -103,7 +191,7 @@ func (p *EnhancedRootlyPublisher) createIncident(
 	}
 
 	// Call Rootly API
-	resp, err := p.client.CreateIncident(ctx, req)
+	resp, err := client.CreateIncident(ctx, req)
 	if err != nil {
 		if p.metrics != nil {
 			p.metrics.RecordAPIError(v2.ProviderRootly, "incidents", GetPublishingErrorType(err))
@@ -113,11 +201,12 @@ func (p *EnhancedRootlyPublisher) createIncident(
 
 	// Store incident ID in cache
 	incidentID := resp.GetID()
-	p.cache.Set(enrichedAlert.Alert.Fingerprint, incidentID)
+	p.cache.Set(key, incidentID)
 
 	// Update metrics
 	if p.GetMetrics() != nil {
 		p.GetMetrics().RecordIncidentCreated(req.Severity)
+		p.GetMetrics().SetRootlyActiveIncidents(p.cache.Size())
 	}
 
 	// Log success
@@ -134,6 +223,8 @@ func (p *EnhancedRootlyPublisher) createIncident(
 // updateIncident updates an existing Rootly incident
 func (p *EnhancedRootlyPublisher) updateIncident(
 	ctx context.Context,
+	client RootlyIncidentsClient,
+	key string,
 	incidentID string,
 	enrichedAlert *core.EnrichedAlert,
 	payload map[string]interface{},
@@ -152,7 +243,7 @@ func (p *EnhancedRootlyPublisher) updateIncident(
 	}
 
 	// Call Rootly API
-	_, err := p.client.UpdateIncident(ctx, incidentID, req)
+	_, err := client.UpdateIncident(ctx, incidentID, req)
 	if err != nil {
 		// If 404 Not Found, incident was deleted in Rootly
 		if IsRootlyNotFoundError(err) {
@@ -162,8 +253,8 @@ func (p *EnhancedRootlyPublisher) updateIncident(
 			)
 
 			// Delete from cache and recreate
-			p.cache.Delete(enrichedAlert.Alert.Fingerprint)
-			return p.createIncident(ctx, enrichedAlert, payload)
+			p.cache.Delete(key)
+			return p.createIncident(ctx, client, key, enrichedAlert, payload)
 		}
 
 		if p.metrics != nil {
@@ -189,22 +280,27 @@ func (p *EnhancedRootlyPublisher) updateIncident(
 // resolveIncident resolves a Rootly incident
 func (p *EnhancedRootlyPublisher) resolveIncident(
 	ctx context.Context,
+	client RootlyIncidentsClient,
+	key string,
 	enrichedAlert *core.EnrichedAlert,
 ) error {
-	// Lookup incident ID from cache
-	incidentID, exists := p.cache.Get(enrichedAlert.Alert.Fingerprint)
+	// Lookup the open incident of the alert
+	incidentID, exists, err := p.lookupIncident(ctx, client, key, enrichedAlert)
+	if err != nil {
+		return err
+	}
 	if !exists {
-		// Not tracked, skip resolution (not an error)
-		p.GetLogger().Debug("Incident ID not found in cache, skipping resolution",
+		// No open incident, skip resolution (not an error)
+		p.GetLogger().Debug("No open incident found for alert, skipping resolution",
 			"fingerprint", enrichedAlert.Alert.Fingerprint,
 		)
 		return nil
 	}
 
 	// Build ResolveIncidentRequest
-	namespace := "unknown"
-	if ns := enrichedAlert.Alert.Namespace(); ns != nil {
-		namespace = *ns
+	namespace := enrichedAlert.KnownLabels().Namespace
+	if namespace == "" {
+		namespace = "unknown"
 	}
 
 	req := &ResolveIncidentRequest{
@@ -215,7 +311,7 @@ func (p *EnhancedRootlyPublisher) resolveIncident(
 	}
 
 	// Call Rootly API
-	_, err := p.client.ResolveIncident(ctx, incidentID, req)
+	_, err = client.ResolveIncident(ctx, incidentID, req)
 	if err != nil {
 		// If 404 Not Found or 409 Conflict, handle gracefully
 		if IsRootlyNotFoundError(err) || IsRootlyConflictError(err) {
@@ -225,7 +321,7 @@ func (p *EnhancedRootlyPublisher) resolveIncident(
 			)
 
 			// Delete from cache
-			p.cache.Delete(enrichedAlert.Alert.Fingerprint)
+			p.cache.Delete(key)
 			return nil // Not an error
 		}
 
@@ -236,11 +332,12 @@ func (p *EnhancedRootlyPublisher) resolveIncident(
 	}
 
 	// Delete from cache
-	p.cache.Delete(enrichedAlert.Alert.Fingerprint)
+	p.cache.Delete(key)
 
 	// Update metrics
 	if p.GetMetrics() != nil {
 		p.GetMetrics().RecordIncidentResolved()
+		p.GetMetrics().SetRootlyActiveIncidents(p.cache.Size())
 	}
 
 	// Log success
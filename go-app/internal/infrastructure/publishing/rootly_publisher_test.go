@@ -0,0 +1,177 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// fakeRootlyAPI serves the Rootly incidents endpoints used by the publisher
+type fakeRootlyAPI struct {
+	mu        sync.Mutex
+	incidents map[string]*IncidentData
+	requests  []string // "METHOD /path"
+}
+
+func newFakeRootlyAPI(t *testing.T) (*fakeRootlyAPI, *httptest.Server) {
+	api := &fakeRootlyAPI{incidents: make(map[string]*IncidentData)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		api.requests = append(api.requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer rootly-key", r.Header.Get("Authorization"))
+
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/incidents/"), "/resolve")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/incidents":
+			assert.Equal(t, "started", r.URL.Query().Get("filter[status]"))
+			var list IncidentListResponse
+			for _, incident := range api.incidents {
+				if strings.Contains(strings.Join(incident.Attributes.Tags, ","), r.URL.Query().Get("filter[search]")) {
+					list.Data = append(list.Data, *incident)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		case r.Method == http.MethodPost && r.URL.Path == "/incidents":
+			var req CreateIncidentRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			incident := &IncidentData{ID: fmt.Sprintf("inc-%d", len(api.incidents)+1), Type: "incidents"}
+			incident.Attributes.Status = "started"
+			incident.Attributes.Tags = req.Tags
+			api.incidents[incident.ID] = incident
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(IncidentResponse{Data: *incident})
+		case api.incidents[id] == nil:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPatch:
+			_ = json.NewEncoder(w).Encode(IncidentResponse{Data: *api.incidents[id]})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/resolve"):
+			api.incidents[id].Attributes.Status = "resolved"
+			_ = json.NewEncoder(w).Encode(IncidentResponse{Data: *api.incidents[id]})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return api, server
+}
+
+func (api *fakeRootlyAPI) takeRequests() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	requests := api.requests
+	api.requests = nil
+	return requests
+}
+
+func newTestRootlyPublisher() *EnhancedRootlyPublisher {
+	return newEnhancedRootlyPublisher(newRootlyClients(slog.Default()), NewIncidentIDCache(time.Hour), nil, NewAlertFormatter(""), slog.Default())
+}
+
+func rootlyTestAlert(status core.AlertStatus) *core.EnrichedAlert {
+	return &core.EnrichedAlert{Alert: &core.Alert{
+		Fingerprint: "fp-1",
+		AlertName:   "DiskFull",
+		Status:      status,
+		Labels:      map[string]string{"alertname": "DiskFull", "severity": "critical"},
+		StartsAt:    time.Now(),
+	}}
+}
+
+func rootlyTestTarget(url string) *core.PublishingTarget {
+	return &core.PublishingTarget{Name: "rootly", Type: "rootly", URL: url, Headers: map[string]string{"Authorization": "Bearer rootly-key"}}
+}
+
+// TestEnhancedRootlyPublisher_UpdatesAndResolvesIncident tests that re-fires
+// update the incident of an alert instead of opening duplicates
+func TestEnhancedRootlyPublisher_UpdatesAndResolvesIncident(t *testing.T) {
+	api, server := newFakeRootlyAPI(t)
+	defer server.Close()
+	publisher := newTestRootlyPublisher()
+	target := rootlyTestTarget(server.URL)
+	ctx := context.Background()
+
+	require.NoError(t, publisher.Publish(ctx, rootlyTestAlert(core.StatusFiring), target))
+	assert.Equal(t, []string{"GET /incidents", "POST /incidents"}, api.takeRequests())
+	assert.Contains(t, api.incidents["inc-1"].Attributes.Tags, "amp_fingerprint:fp-1")
+
+	require.NoError(t, publisher.Publish(ctx, rootlyTestAlert(core.StatusFiring), target))
+	assert.Equal(t, []string{"PATCH /incidents/inc-1"}, api.takeRequests())
+
+	require.NoError(t, publisher.Publish(ctx, rootlyTestAlert(core.StatusResolved), target))
+	assert.Equal(t, []string{"POST /incidents/inc-1/resolve"}, api.takeRequests())
+	assert.Equal(t, 0, publisher.cache.Size())
+}
+
+// TestEnhancedRootlyPublisher_FindsUntrackedIncident tests alerts whose
+// incident was opened before a restart or by another replica
+func TestEnhancedRootlyPublisher_FindsUntrackedIncident(t *testing.T) {
+	api, server := newFakeRootlyAPI(t)
+	defer server.Close()
+	target := rootlyTestTarget(server.URL)
+	ctx := context.Background()
+
+	require.NoError(t, newTestRootlyPublisher().Publish(ctx, rootlyTestAlert(core.StatusFiring), target))
+	api.takeRequests()
+
+	// Re-fire: the open incident is found and updated
+	restarted := newTestRootlyPublisher()
+	require.NoError(t, restarted.Publish(ctx, rootlyTestAlert(core.StatusFiring), target))
+	assert.Equal(t, []string{"GET /incidents", "PATCH /incidents/inc-1"}, api.takeRequests())
+
+	// Resolve: the open incident is found and resolved
+	restarted = newTestRootlyPublisher()
+	require.NoError(t, restarted.Publish(ctx, rootlyTestAlert(core.StatusResolved), target))
+	assert.Equal(t, []string{"GET /incidents", "POST /incidents/inc-1/resolve"}, api.takeRequests())
+
+	// Resolved incidents are not reused
+	require.NoError(t, newTestRootlyPublisher().Publish(ctx, rootlyTestAlert(core.StatusFiring), target))
+	assert.Equal(t, []string{"GET /incidents", "POST /incidents"}, api.takeRequests())
+	assert.Len(t, api.incidents, 2)
+}
+
+// TestEnhancedRootlyPublisher_IncidentPerTarget tests that the same alert
+// opens an incident per Rootly target
+func TestEnhancedRootlyPublisher_IncidentPerTarget(t *testing.T) {
+	api, server := newFakeRootlyAPI(t)
+	defer server.Close()
+	publisher := newTestRootlyPublisher()
+	ctx := context.Background()
+
+	require.NoError(t, publisher.Publish(ctx, rootlyTestAlert(core.StatusFiring), rootlyTestTarget(server.URL)))
+	api.takeRequests()
+
+	other := rootlyTestTarget(server.URL)
+	other.Name = "rootly-other"
+	// Found by its tag, as the target shares the Rootly account
+	require.NoError(t, publisher.Publish(ctx, rootlyTestAlert(core.StatusFiring), other))
+	assert.Equal(t, []string{"GET /incidents", "PATCH /incidents/inc-1"}, api.takeRequests())
+	assert.Equal(t, 2, publisher.cache.Size())
+}
+
+// TestEnhancedRootlyPublisher_WithoutAPIKey tests that targets without an
+// API key get the formatted alert posted to their URL
+func TestEnhancedRootlyPublisher_WithoutAPIKey(t *testing.T) {
+	var posted bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted = true
+		assert.Equal(t, "/", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := &core.PublishingTarget{Name: "rootly-webhook", Type: "rootly", URL: server.URL + "/"}
+	require.NoError(t, newTestRootlyPublisher().Publish(context.Background(), rootlyTestAlert(core.StatusFiring), target))
+	assert.True(t, posted)
+}
@@ -0,0 +1,106 @@
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+// Target headers overriding the severity styles of a target. The suffix is
+// a style key (critical, warning, info, noise or resolved), e.g.
+// emoji_critical: "🚨" or color_info: "#439FE0".
+const (
+	targetEmojiHeaderPrefix     = "emoji_"
+	targetColorHeaderPrefix     = "color_"
+	targetCardColorHeaderPrefix = "card_color_"
+)
+
+// TargetSeverityStyles parses the severity style overrides of a target from
+// its headers. It returns nil when there are none.
+func TargetSeverityStyles(headers map[string]string) (map[string]domain.SeverityStyle, error) {
+	var styles map[string]domain.SeverityStyle
+	for header, value := range headers {
+		key, field, ok := parseSeverityStyleHeader(header)
+		if !ok {
+			continue
+		}
+		if !domain.IsSeverityStyleKey(key) {
+			return nil, fmt.Errorf("%s: unknown severity %q (must be one of %s)",
+				header, key, strings.Join(domain.SeverityStyleKeys, ", "))
+		}
+		if styles == nil {
+			styles = make(map[string]domain.SeverityStyle)
+		}
+		style := styles[key]
+		*field(&style) = value
+		if err := domain.ValidateSeverityStyle(style); err != nil {
+			return nil, fmt.Errorf("%s: %w", header, err)
+		}
+		styles[key] = style
+	}
+	return styles, nil
+}
+
+// parseSeverityStyleHeader splits a style override header into its style
+// key and the field it sets. ok is false for other headers.
+func parseSeverityStyleHeader(header string) (key string, field func(*domain.SeverityStyle) *string, ok bool) {
+	if key, ok := strings.CutPrefix(header, targetCardColorHeaderPrefix); ok {
+		return key, func(s *domain.SeverityStyle) *string { return &s.CardColor }, true
+	}
+	if key, ok := strings.CutPrefix(header, targetColorHeaderPrefix); ok {
+		return key, func(s *domain.SeverityStyle) *string { return &s.Color }, true
+	}
+	if key, ok := strings.CutPrefix(header, targetEmojiHeaderPrefix); ok {
+		return key, func(s *domain.SeverityStyle) *string { return &s.Emoji }, true
+	}
+	return "", nil, false
+}
+
+type severityStylesKey struct{}
+
+// WithSeverityStyles attaches per-target severity style overrides to ctx.
+// The formatter merges them over its own styles.
+func WithSeverityStyles(ctx context.Context, styles map[string]domain.SeverityStyle) context.Context {
+	if len(styles) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, severityStylesKey{}, styles)
+}
+
+// SeverityStylesFromContext returns the overrides attached by
+// WithSeverityStyles, or nil.
+func SeverityStylesFromContext(ctx context.Context) map[string]domain.SeverityStyle {
+	styles, _ := ctx.Value(severityStylesKey{}).(map[string]domain.SeverityStyle)
+	return styles
+}
+
+// withTargetSeverityStyles attaches the style overrides of target to ctx.
+// Invalid overrides are rejected by target discovery; should one get here
+// anyway, the formatter's styles are used.
+func withTargetSeverityStyles(ctx context.Context, target *core.PublishingTarget) context.Context {
+	if target == nil {
+		return ctx
+	}
+	styles, err := TargetSeverityStyles(target.Headers)
+	if err != nil {
+		return ctx
+	}
+	return WithSeverityStyles(ctx, styles)
+}
+
+// severityStyleKey returns the style key of an alert: "resolved" for
+// resolved alerts, otherwise its effective severity. Alerts without any
+// severity are styled as warnings.
+func severityStyleKey(enrichedAlert *core.EnrichedAlert) string {
+	if enrichedAlert.Alert.Status == core.StatusResolved {
+		return domain.SeverityStyleResolved
+	}
+	severity, source := enrichedAlert.EffectiveSeverity()
+	if source == domain.SeveritySourceDefault {
+		return string(core.SeverityWarning)
+	}
+	return string(severity)
+}
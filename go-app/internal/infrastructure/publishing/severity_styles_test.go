@@ -0,0 +1,121 @@
+package publishing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func slackHeaderAndColor(t *testing.T, payload map[string]any) (string, string) {
+	t.Helper()
+	blocks, ok := payload["blocks"].([]map[string]any)
+	require.True(t, ok, "blocks")
+	header := blocks[0]["text"].(map[string]any)["text"].(string)
+	attachments, ok := payload["attachments"].([]map[string]any)
+	require.True(t, ok, "attachments")
+	return header, attachments[0]["color"].(string)
+}
+
+func newCriticalTestAlert() *core.EnrichedAlert {
+	alert := createTestEnrichedAlert()
+	alert.Classification.Severity = core.SeverityCritical
+	return alert
+}
+
+func TestFormatAlert_SeverityStyles(t *testing.T) {
+	formatter := NewAlertFormatterWithStyles("", map[string]domain.SeverityStyle{
+		"critical": {Emoji: "\U0001f6a8"},
+		"info":     {Color: "#439FE0"},
+	})
+
+	t.Run("configured emoji keeps default color", func(t *testing.T) {
+		payload, err := formatter.FormatAlert(context.Background(), newCriticalTestAlert(), core.FormatSlack)
+		require.NoError(t, err)
+		header, color := slackHeaderAndColor(t, payload)
+		assert.Equal(t, "\U0001f6a8 *TestAlert* - firing", header)
+		assert.Equal(t, "#FF0000", color)
+	})
+
+	t.Run("severity label without classification", func(t *testing.T) {
+		alert := createTestEnrichedAlert()
+		alert.Classification = nil
+		alert.Alert.Labels["severity"] = "info"
+		payload, err := formatter.FormatAlert(context.Background(), alert, core.FormatSlack)
+		require.NoError(t, err)
+		header, color := slackHeaderAndColor(t, payload)
+		assert.Equal(t, "ℹ️ *TestAlert* - firing", header)
+		assert.Equal(t, "#439FE0", color)
+	})
+
+	t.Run("no severity is styled as warning", func(t *testing.T) {
+		alert := createTestEnrichedAlert()
+		alert.Classification = nil
+		delete(alert.Alert.Labels, "severity")
+		payload, err := formatter.FormatAlert(context.Background(), alert, core.FormatSlack)
+		require.NoError(t, err)
+		_, color := slackHeaderAndColor(t, payload)
+		assert.Equal(t, "#FFA500", color)
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		alert := createTestEnrichedAlert()
+		alert.Alert.Status = core.StatusResolved
+		payload, err := formatter.FormatAlert(context.Background(), alert, core.FormatSlack)
+		require.NoError(t, err)
+		header, color := slackHeaderAndColor(t, payload)
+		assert.Equal(t, "✅ *TestAlert* - resolved", header)
+		assert.Equal(t, "#36A64F", color)
+	})
+
+	t.Run("target overrides", func(t *testing.T) {
+		target := &core.PublishingTarget{Headers: map[string]string{
+			"emoji_critical":      ":fire:",
+			"card_color_critical": "warning",
+		}}
+		ctx := withTargetSeverityStyles(context.Background(), target)
+
+		payload, err := formatter.FormatAlert(ctx, newCriticalTestAlert(), core.FormatTeams)
+		require.NoError(t, err)
+		body := teamsCard(t, payload)["body"].([]map[string]any)
+		assert.Equal(t, ":fire: TestAlert - firing", body[0]["text"])
+		assert.Equal(t, "warning", body[0]["color"])
+
+		// Other targets keep the configured styles
+		payload, err = formatter.FormatAlert(context.Background(), newCriticalTestAlert(), core.FormatTeams)
+		require.NoError(t, err)
+		body = teamsCard(t, payload)["body"].([]map[string]any)
+		assert.Equal(t, "\U0001f6a8 TestAlert - firing", body[0]["text"])
+		assert.Equal(t, "attention", body[0]["color"])
+	})
+}
+
+func TestTargetSeverityStyles(t *testing.T) {
+	styles, err := TargetSeverityStyles(map[string]string{
+		"Authorization":   "Bearer token",
+		"group_by":        "alertname",
+		"emoji_info":      "\U0001f4d8",
+		"color_info":      "#439FE0",
+		"card_color_info": "accent",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.SeverityStyle{
+		"info": {Emoji: "\U0001f4d8", Color: "#439FE0", CardColor: "accent"},
+	}, styles)
+
+	styles, err = TargetSeverityStyles(map[string]string{"Authorization": "Bearer token"})
+	require.NoError(t, err)
+	assert.Nil(t, styles)
+
+	_, err = TargetSeverityStyles(map[string]string{"emoji_page": ":pager:"})
+	assert.ErrorContains(t, err, "unknown severity")
+
+	_, err = TargetSeverityStyles(map[string]string{"color_critical": "red"})
+	assert.ErrorContains(t, err, "color_critical")
+
+	assert.True(t, isTargetOption("card_color_resolved"))
+	assert.False(t, isTargetOption("Content-Type"))
+}
@@ -12,11 +12,14 @@ import (
 type MessageEntry struct {
 	MessageTS string    // Message timestamp (ts) returned by Slack
 	ThreadTS  string    // Thread timestamp (thread_ts) for replies
+	Channel   string    // Channel ID returned by the Web API (empty for webhooks), for updates
+	Severity  string    // Severity style key of the last firing notification, to detect reclassification
 	CreatedAt time.Time // Cache creation time (for TTL)
 }
 
 // MessageIDCache stores alert fingerprint → MessageEntry mappings
 // Enables threading: resolved/still-firing alerts reply to original message
+// The publisher keys entries by target and fingerprint (slackMessageKey)
 type MessageIDCache interface {
 	// Store saves MessageEntry for alert fingerprint
 	Store(fingerprint string, entry *MessageEntry)
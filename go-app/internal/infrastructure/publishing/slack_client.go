@@ -14,6 +14,9 @@ import (
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
 )
 
 // slack_client.go - Slack Webhook API client with rate limiting and retry logic
@@ -31,6 +34,11 @@ type SlackWebhookClient interface {
 	// Sets message.ThreadTS automatically before posting
 	ReplyInThread(ctx context.Context, threadTS string, message *SlackMessage) (*SlackResponse, error)
 
+	// UpdateMessage replaces the content of a posted message (chat.update)
+	// channel and ts identify the message, as returned by PostMessage
+	// Returns ErrSlackUpdateUnsupported for incoming webhooks
+	UpdateMessage(ctx context.Context, channel, ts string, message *SlackMessage) (*SlackResponse, error)
+
 	// Health checks if the webhook URL is reachable
 	// Posts a minimal test message to verify connectivity
 	// Returns error if webhook is invalid or unreachable
@@ -40,9 +48,15 @@ type SlackWebhookClient interface {
 // HTTPSlackWebhookClient implements SlackWebhookClient using HTTP
 // Provides rate limiting (1 msg/sec), retry logic with exponential backoff,
 // and comprehensive error handling for Slack webhook API
+//
+// With a bot token, messages are posted with the Web API (chat.postMessage)
+// instead, which returns the message ts and channel needed for threading
+// and lets messages be updated (chat.update).
 type HTTPSlackWebhookClient struct {
 	httpClient  *http.Client
-	webhookURL  string
+	webhookURL  string        // incoming webhook URL, or Web API base URL with a token
+	token       string        // bot token (Web API mode)
+	channel     string        // channel to post to (Web API mode)
 	rateLimiter *rate.Limiter // 1 message per second
 	logger      *slog.Logger
 }
@@ -57,7 +71,7 @@ func NewHTTPSlackWebhookClient(
 	return &HTTPSlackWebhookClient{
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
-			Transport: &http.Transport{
+			Transport: newOutboundTransport(&http.Transport{
 				TLSClientConfig: &tls.Config{
 					MinVersion: tls.VersionTLS12, // TLS 1.2+ required
 				},
@@ -68,7 +82,7 @@ func NewHTTPSlackWebhookClient(
 					Timeout:   5 * time.Second,
 					KeepAlive: 30 * time.Second,
 				}).DialContext,
-			},
+			}),
 		},
 		webhookURL:  webhookURL,
 		rateLimiter: rate.NewLimiter(rate.Every(1*time.Second), 1), // 1 msg/sec, burst 1
@@ -76,6 +90,17 @@ func NewHTTPSlackWebhookClient(
 	}
 }
 
+// NewHTTPSlackAPIClient creates a Slack client posting with the Web API
+// apiURL: Web API base URL (https://slack.com/api)
+// token: bot token (xoxb-...) with the chat:write scope
+// channel: channel ID or name to post to
+func NewHTTPSlackAPIClient(apiURL, token, channel string, logger *slog.Logger) SlackWebhookClient {
+	client := NewHTTPSlackWebhookClient(strings.TrimSuffix(apiURL, "/"), logger).(*HTTPSlackWebhookClient)
+	client.token = token
+	client.channel = channel
+	return client
+}
+
 // PostMessage posts a new message to Slack
 // Blocks until rate limit token is available (max 1 msg/sec)
 // Retries transient errors (429, 503, network) with exponential backoff
@@ -83,6 +108,34 @@ func (c *HTTPSlackWebhookClient) PostMessage(ctx context.Context, message *Slack
 	c.logger.DebugContext(ctx, "Posting message to Slack",
 		slog.String("webhook_url", maskWebhookURL(c.webhookURL)))
 
+	url := c.webhookURL
+	if c.token != "" {
+		url += "/chat.postMessage"
+		if message.Channel == "" {
+			message.Channel = c.channel
+		}
+	}
+	return c.send(ctx, url, message)
+}
+
+// UpdateMessage replaces the content of a posted message
+// Only available with a bot token: incoming webhooks cannot edit messages
+func (c *HTTPSlackWebhookClient) UpdateMessage(ctx context.Context, channel, ts string, message *SlackMessage) (*SlackResponse, error) {
+	if c.token == "" {
+		return nil, ErrSlackUpdateUnsupported
+	}
+	c.logger.DebugContext(ctx, "Updating Slack message",
+		slog.String("channel", channel),
+		slog.String("ts", ts))
+
+	message.Channel = channel
+	message.TS = ts
+	message.ThreadTS = ""
+	return c.send(ctx, c.webhookURL+"/chat.update", message)
+}
+
+// send posts message to url, rate limited and with retries
+func (c *HTTPSlackWebhookClient) send(ctx context.Context, url string, message *SlackMessage) (*SlackResponse, error) {
 	// Rate limit check (blocks until token available)
 	if err := c.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
@@ -94,11 +147,14 @@ func (c *HTTPSlackWebhookClient) PostMessage(ctx context.Context, message *Slack
 		return nil, fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 
 	// Execute with retry logic
 	resp, err := c.doRequestWithRetry(ctx, req, body)
@@ -152,7 +208,7 @@ func (c *HTTPSlackWebhookClient) doRequestWithRetry(ctx context.Context, req *ht
 		httpResp, err := c.httpClient.Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("HTTP request failed: %w", err)
-			if !isRetryableNetworkError(err) {
+			if !httperror.IsRetryableNetworkError(err) {
 				return nil, lastErr // Don't retry network errors
 			}
 			c.logger.WarnContext(ctx, "Retrying after network error",
@@ -175,6 +231,11 @@ func (c *HTTPSlackWebhookClient) doRequestWithRetry(ctx context.Context, req *ht
 
 		// Check status code
 		if httpResp.StatusCode == http.StatusOK {
+			// Incoming webhooks answer a plain "ok", without message ts
+			if strings.TrimSpace(string(respBody)) == "ok" {
+				return &SlackResponse{OK: true}, nil
+			}
+
 			// Success - parse response
 			var slackResp SlackResponse
 			if err := json.Unmarshal(respBody, &slackResp); err != nil {
@@ -244,3 +305,58 @@ func maskWebhookURL(url string) string {
 	}
 	return strings.Join(parts, "/")
 }
+
+// Slack target options. Without a bot token in the Authorization header
+// ("Bearer xoxb-..."), target.URL is an incoming webhook URL. With one,
+// target.URL is the Web API base URL and the channel header names the
+// channel to post to; only then are messages threaded and updated, because
+// incoming webhooks return no message ts.
+const slackChannelHeader = "channel"
+
+// slackBotToken returns the bot token of a Slack target, if any.
+func slackBotToken(target *core.PublishingTarget) string {
+	return strings.TrimSpace(strings.TrimPrefix(target.Headers["Authorization"], "Bearer "))
+}
+
+// ValidateSlackTarget checks the Web API options of a Slack target: a bot
+// token needs a channel.
+func ValidateSlackTarget(target *core.PublishingTarget) error {
+	if target.Type != string(TargetTypeSlack) || slackBotToken(target) == "" {
+		return nil
+	}
+	if strings.TrimSpace(target.Headers[slackChannelHeader]) == "" {
+		return fmt.Errorf("slack target with a bot token needs a %s header", slackChannelHeader)
+	}
+	return nil
+}
+
+// slackClients caches Slack clients by URL, bot token and channel.
+type slackClients struct {
+	*clientCache[SlackWebhookClient]
+	logger *slog.Logger
+}
+
+func newSlackClients(logger *slog.Logger) *slackClients {
+	return &slackClients{
+		clientCache: newClientCache[SlackWebhookClient](slackClientKey),
+		logger:      logger,
+	}
+}
+
+func slackClientKey(target *core.PublishingTarget) string {
+	return target.URL + "\x00" + slackBotToken(target) + "\x00" + target.Headers[slackChannelHeader]
+}
+
+// get returns the client for target: a Web API client when the target has a
+// bot token, else an incoming webhook client.
+func (c *slackClients) get(target *core.PublishingTarget) (SlackWebhookClient, error) {
+	if target.URL == "" {
+		return nil, ErrMissingWebhookURL
+	}
+	return c.getOrCreate(target, func() SlackWebhookClient {
+		if token := slackBotToken(target); token != "" {
+			return NewHTTPSlackAPIClient(target.URL, token, target.Headers[slackChannelHeader], c.logger)
+		}
+		return NewHTTPSlackWebhookClient(target.URL, c.logger)
+	}), nil
+}
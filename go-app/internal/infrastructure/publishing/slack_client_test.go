@@ -0,0 +1,125 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// unlimitedSlackClient lifts the 1 msg/sec limit of client for tests
+func unlimitedSlackClient(client SlackWebhookClient) SlackWebhookClient {
+	client.(*HTTPSlackWebhookClient).rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	return client
+}
+
+// TestSlackWebhookClient_PlainOKResponse tests incoming webhooks answering "ok"
+func TestSlackWebhookClient_PlainOKResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := unlimitedSlackClient(NewHTTPSlackWebhookClient(server.URL, slog.Default()))
+	resp, err := client.PostMessage(context.Background(), &SlackMessage{Text: "test"})
+	require.NoError(t, err)
+	assert.True(t, resp.OK)
+	assert.Empty(t, resp.TS)
+
+	_, err = client.UpdateMessage(context.Background(), "C123", "1.1", &SlackMessage{Text: "test"})
+	assert.ErrorIs(t, err, ErrSlackUpdateUnsupported)
+}
+
+// TestSlackAPIClient_PostAndUpdate tests posting and updating with a bot token
+func TestSlackAPIClient_PostAndUpdate(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "Bearer xoxb-test", r.Header.Get("Authorization"))
+
+		var message SlackMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			assert.Equal(t, "#alerts", message.Channel)
+		case "/chat.update":
+			assert.Equal(t, "C123", message.Channel)
+			assert.Equal(t, "1.1", message.TS)
+			assert.Empty(t, message.ThreadTS)
+		}
+		_ = json.NewEncoder(w).Encode(SlackResponse{OK: true, TS: "1.1", Channel: "C123"})
+	}))
+	defer server.Close()
+
+	client := unlimitedSlackClient(NewHTTPSlackAPIClient(server.URL+"/", "xoxb-test", "#alerts", slog.Default()))
+	resp, err := client.PostMessage(context.Background(), &SlackMessage{Text: "firing"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.1", resp.TS)
+	assert.Equal(t, "C123", resp.Channel)
+
+	_, err = client.UpdateMessage(context.Background(), resp.Channel, resp.TS, &SlackMessage{Text: "resolved", ThreadTS: "1.1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/chat.postMessage", "/chat.update"}, paths)
+}
+
+// TestSlackAPIClient_ErrorResponse tests Web API errors returned with status 200
+func TestSlackAPIClient_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(SlackResponse{OK: false, Error: "channel_not_found"})
+	}))
+	defer server.Close()
+
+	client := unlimitedSlackClient(NewHTTPSlackAPIClient(server.URL, "xoxb-test", "#missing", slog.Default()))
+	_, err := client.PostMessage(context.Background(), &SlackMessage{Text: "firing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "channel_not_found")
+}
+
+// TestSlackClients tests the per-target client cache
+func TestSlackClients(t *testing.T) {
+	clients := newSlackClients(slog.Default())
+
+	webhook := &core.PublishingTarget{Name: "webhook", Type: "slack", URL: "https://hooks.slack.com/services/T/B/X"}
+	bot := &core.PublishingTarget{
+		Name:    "bot",
+		Type:    "slack",
+		URL:     "https://slack.com/api",
+		Headers: map[string]string{"Authorization": "Bearer xoxb-test", "channel": "#alerts"},
+	}
+
+	webhookClient, err := clients.get(webhook)
+	require.NoError(t, err)
+	assert.Empty(t, webhookClient.(*HTTPSlackWebhookClient).token)
+
+	botClient, err := clients.get(bot)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-test", botClient.(*HTTPSlackWebhookClient).token)
+	assert.Equal(t, "#alerts", botClient.(*HTTPSlackWebhookClient).channel)
+
+	again, err := clients.get(bot)
+	require.NoError(t, err)
+	assert.Same(t, botClient, again)
+
+	_, err = clients.get(&core.PublishingTarget{Name: "empty", Type: "slack"})
+	assert.ErrorIs(t, err, ErrMissingWebhookURL)
+
+	clients.retain([]*core.PublishingTarget{bot})
+	assert.Len(t, clients.clients, 1)
+
+	assert.NoError(t, ValidateSlackTarget(webhook))
+	assert.NoError(t, ValidateSlackTarget(bot))
+	assert.Error(t, ValidateSlackTarget(&core.PublishingTarget{
+		Type:    "slack",
+		URL:     "https://slack.com/api",
+		Headers: map[string]string{"Authorization": "Bearer xoxb-test"},
+	}))
+}
@@ -21,6 +21,13 @@ type SlackMessage struct {
 	// Attachments are legacy color-coded message attachments
 	// Used for color bars (critical=red, warning=orange, etc.)
 	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Channel is the channel to post to (Web API only; webhooks are bound
+	// to their channel)
+	Channel string `json:"channel,omitempty"`
+
+	// TS is the timestamp of the message to update (chat.update only)
+	TS string `json:"ts,omitempty"`
 }
 
 // Block represents a Slack Block Kit block
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
@@ -14,13 +15,20 @@ import (
 // Implements AlertPublisher interface with message tracking and threading support
 
 // EnhancedSlackPublisher implements AlertPublisher with full Slack webhook support
-// Provides message lifecycle management (post, thread reply) and message tracking
+// Provides message lifecycle management (post, thread reply, update on resolve)
+// and message tracking per target and alert fingerprint
 type EnhancedSlackPublisher struct {
 	*BaseEnhancedPublisher                    // Embedded base publisher for common functionality
-	client                 SlackWebhookClient // Slack-specific webhook client
+	client                 SlackWebhookClient // Slack-specific webhook client (nil: resolved per target)
+	clients                *slackClients      // Slack clients by target, when client is nil
 	cache                  MessageIDCache     // For tracking message timestamps (threading)
+	snoozes                core.SnoozeChecker // Personal snoozes; snoozed users are not mentioned (optional)
 }
 
+// slackMentionsAnnotation lists Slack member IDs (comma-separated) to mention
+// when an alert is first posted, e.g. "U012AB3CD,U045EF6GH".
+const slackMentionsAnnotation = "slack_mentions"
+
 // NewEnhancedSlackPublisher creates a new enhanced Slack publisher
 // cache: Message ID cache for tracking message timestamps (for threading)
 // metrics: Prometheus metrics recorder
@@ -43,40 +51,96 @@ func NewEnhancedSlackPublisher(
 	}
 }
 
+// newEnhancedSlackPublisher creates a Slack publisher resolving the client
+// of each target at publish time, as the publishing queue needs.
+func newEnhancedSlackPublisher(clients *slackClients, cache MessageIDCache, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedSlackPublisher {
+	return &EnhancedSlackPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "slack_publisher"),
+		),
+		clients: clients,
+		cache:   cache,
+	}
+}
+
+// slackMessageKey returns the message cache key of an alert posted to a
+// target: the same alert is posted to each Slack target separately.
+func slackMessageKey(target, fingerprint string) string {
+	return target + "/" + fingerprint
+}
+
+// clientFor returns the Slack client of target.
+func (p *EnhancedSlackPublisher) clientFor(target *core.PublishingTarget) (SlackWebhookClient, error) {
+	if p.client != nil {
+		return p.client, nil
+	}
+	return p.clients.get(target)
+}
+
 // Publish publishes enriched alert to Slack
-// Routes to postMessage() or replyInThread() based on alert status and cache
+// Routes to postMessage() or replyInThread() based on alert status and cache:
+// the first firing notification is posted, later ones (still firing,
+// reclassified, resolved) reply in its thread, and on resolve the original
+// message is updated to the resolved color and emoji
 func (p *EnhancedSlackPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	client, err := p.clientFor(target)
+	if err != nil {
+		return err
+	}
+
 	alert := enrichedAlert.Alert
 	fingerprint := alert.Fingerprint
+	key := slackMessageKey(target.Name, fingerprint)
+	ctx = withTargetSeverityStyles(ctx, target)
 
 	p.LogPublishStart(ctx, v2.ProviderSlack, enrichedAlert)
 
 	// Check cache for existing message
-	entry, found := p.cache.Get(fingerprint)
+	entry, found := p.cache.Get(key)
 
 	// Determine action based on alert status and cache
 	switch alert.Status {
 	case core.StatusFiring:
 		if found {
-			// Alert still firing - reply in thread
 			p.RecordCacheHit(v2.ProviderSlack)
-			return p.replyInThread(ctx, entry.ThreadTS, enrichedAlert, "🔴 Still firing")
+			severity := severityStyleKey(enrichedAlert)
+			if entry.Severity != "" && entry.Severity != severity {
+				// Severity changed - reply in thread and remember it
+				statusText := fmt.Sprintf("🔄 Reclassified: %s → %s", entry.Severity, severity)
+				if err := p.replyInThread(ctx, client, entry.ThreadTS, enrichedAlert, statusText); err != nil {
+					return err
+				}
+				updated := *entry
+				updated.Severity = severity
+				p.cache.Store(key, &updated)
+				return nil
+			}
+			// Alert still firing - reply in thread
+			return p.replyInThread(ctx, client, entry.ThreadTS, enrichedAlert, "🔴 Still firing")
 		}
 		// New firing alert - post new message
 		p.RecordCacheMiss(v2.ProviderSlack)
-		return p.postMessage(ctx, enrichedAlert, fingerprint)
+		return p.postMessage(ctx, client, enrichedAlert, key)
 
 	case core.StatusResolved:
 		if found {
-			// Alert resolved - reply in thread
+			// Alert resolved - reply in thread and update the original message
 			p.RecordCacheHit(v2.ProviderSlack)
-			return p.replyInThread(ctx, entry.ThreadTS, enrichedAlert, "🟢 Resolved")
+			if err := p.replyInThread(ctx, client, entry.ThreadTS, enrichedAlert, "🟢 Resolved"); err != nil {
+				return err
+			}
+			p.updateMessage(ctx, client, entry, enrichedAlert)
+			// A new firing starts a new thread
+			p.cache.Delete(key)
+			return nil
 		}
 		// Resolved alert without firing message (cache miss) - post new message with resolved status
 		p.GetLogger().WarnContext(ctx, "Resolved alert without firing message (cache miss), posting new message",
 			slog.String("fingerprint", fingerprint))
 		p.RecordCacheMiss(v2.ProviderSlack)
-		return p.postMessage(ctx, enrichedAlert, fingerprint)
+		return p.postMessage(ctx, client, enrichedAlert, key)
 
 	default:
 		return fmt.Errorf("unknown alert status: %s", alert.Status)
@@ -90,7 +154,7 @@ func (p *EnhancedSlackPublisher) Name() string {
 
 // postMessage posts a new message to Slack channel
 // Formats alert using TN-051 formatter, posts to Slack, caches message timestamp
-func (p *EnhancedSlackPublisher) postMessage(ctx context.Context, enrichedAlert *core.EnrichedAlert, fingerprint string) error {
+func (p *EnhancedSlackPublisher) postMessage(ctx context.Context, client SlackWebhookClient, enrichedAlert *core.EnrichedAlert, key string) error {
 	startTime := time.Now()
 
 	// Format alert using TN-051 formatter
@@ -104,9 +168,10 @@ func (p *EnhancedSlackPublisher) postMessage(ctx context.Context, enrichedAlert
 
 	// Build SlackMessage from formatted payload
 	message := p.buildMessage(formattedPayload)
+	p.addMentions(message, enrichedAlert.Alert)
 
 	// Post message to Slack
-	resp, err := p.client.PostMessage(ctx, message)
+	resp, err := client.PostMessage(ctx, message)
 	if err != nil {
 		if p.GetMetrics() != nil {
 			p.GetMetrics().RecordAPIError(v2.ProviderSlack, "post_message", classifySlackError(err))
@@ -115,13 +180,19 @@ func (p *EnhancedSlackPublisher) postMessage(ctx context.Context, enrichedAlert
 		return fmt.Errorf("failed to post message: %w", err)
 	}
 
-	// Cache message timestamp for threading
-	entry := &MessageEntry{
-		MessageTS: resp.TS,
-		ThreadTS:  resp.TS, // First message is thread root
-		CreatedAt: time.Now(),
+	// Cache message timestamp for threading (incoming webhooks return none)
+	if resp.TS != "" {
+		entry := &MessageEntry{
+			MessageTS: resp.TS,
+			ThreadTS:  resp.TS, // First message is thread root
+			Channel:   resp.Channel,
+			CreatedAt: time.Now(),
+		}
+		if enrichedAlert.Alert.Status == core.StatusFiring {
+			entry.Severity = severityStyleKey(enrichedAlert)
+		}
+		p.cache.Store(key, entry)
 	}
-	p.cache.Store(fingerprint, entry)
 
 	// Record metrics
 	if p.GetMetrics() != nil {
@@ -130,15 +201,59 @@ func (p *EnhancedSlackPublisher) postMessage(ctx context.Context, enrichedAlert
 	}
 
 	p.GetLogger().InfoContext(ctx, "Message posted successfully",
-		slog.String("fingerprint", fingerprint),
+		slog.String("fingerprint", enrichedAlert.Alert.Fingerprint),
 		slog.String("message_ts", resp.TS))
 
 	return nil
 }
 
+// addMentions appends a "cc" block mentioning the alert's Slack users,
+// skipping users who snoozed this fingerprint.
+func (p *EnhancedSlackPublisher) addMentions(message *SlackMessage, alert *core.Alert) {
+	mentions := p.mentionsFor(alert, time.Now())
+	if len(mentions) == 0 {
+		return
+	}
+
+	cc := "cc " + strings.Join(mentions, " ")
+	message.Blocks = append(message.Blocks, Block{
+		Type: "context",
+		Text: &Text{Type: "mrkdwn", Text: cc},
+	})
+	if message.Text != "" {
+		message.Text += "\n"
+	}
+	message.Text += cc
+}
+
+// mentionsFor returns the formatted mentions for the alert's
+// slack_mentions annotation, without users who snoozed the fingerprint.
+func (p *EnhancedSlackPublisher) mentionsFor(alert *core.Alert, now time.Time) []string {
+	raw := alert.Annotations[slackMentionsAnnotation]
+	if raw == "" {
+		return nil
+	}
+
+	var mentions []string
+	for _, userID := range strings.Split(raw, ",") {
+		userID = strings.TrimSpace(userID)
+		if userID == "" {
+			continue
+		}
+		if p.snoozes != nil && p.snoozes.IsSnoozed(userID, alert.Fingerprint, now) {
+			p.GetLogger().Debug("Skipping mention of user who snoozed the alert",
+				slog.String("fingerprint", alert.Fingerprint),
+				slog.String("user", userID))
+			continue
+		}
+		mentions = append(mentions, "<@"+userID+">")
+	}
+	return mentions
+}
+
 // replyInThread replies to an existing message thread
-// Used for "still firing" updates and "resolved" notifications
-func (p *EnhancedSlackPublisher) replyInThread(ctx context.Context, threadTS string, enrichedAlert *core.EnrichedAlert, statusText string) error {
+// Used for "still firing", "reclassified" and "resolved" notifications
+func (p *EnhancedSlackPublisher) replyInThread(ctx context.Context, client SlackWebhookClient, threadTS string, enrichedAlert *core.EnrichedAlert, statusText string) error {
 	startTime := time.Now()
 
 	// Build simple reply message
@@ -169,7 +284,7 @@ func (p *EnhancedSlackPublisher) replyInThread(ctx context.Context, threadTS str
 	}
 
 	// Reply in thread
-	_, err := p.client.ReplyInThread(ctx, threadTS, message)
+	_, err := client.ReplyInThread(ctx, threadTS, message)
 	if err != nil {
 		if p.GetMetrics() != nil {
 			p.GetMetrics().RecordAPIError(v2.ProviderSlack, "thread_reply", classifySlackError(err))
@@ -191,6 +306,48 @@ func (p *EnhancedSlackPublisher) replyInThread(ctx context.Context, threadTS str
 	return nil
 }
 
+// updateMessage updates the original message of a resolved alert, so that
+// it shows the resolved color and emoji. Best effort: the resolved reply is
+// already posted, so failures are only logged. Messages posted with an
+// incoming webhook (no channel cached) cannot be updated.
+func (p *EnhancedSlackPublisher) updateMessage(ctx context.Context, client SlackWebhookClient, entry *MessageEntry, enrichedAlert *core.EnrichedAlert) {
+	if entry.Channel == "" {
+		return
+	}
+	startTime := time.Now()
+
+	formattedPayload, err := p.GetFormatter().FormatAlert(ctx, enrichedAlert, core.FormatSlack)
+	if err != nil {
+		p.GetLogger().WarnContext(ctx, "Failed to format resolved message update",
+			slog.String("fingerprint", enrichedAlert.Alert.Fingerprint),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	_, err = client.UpdateMessage(ctx, entry.Channel, entry.MessageTS, p.buildMessage(formattedPayload))
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordAPIDuration(v2.ProviderSlack, "update_message", "POST", time.Since(startTime))
+	}
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderSlack, "update_message", classifySlackError(err))
+			p.GetMetrics().RecordSlackMessageUpdate("error")
+		}
+		p.GetLogger().WarnContext(ctx, "Failed to update original message of resolved alert",
+			slog.String("fingerprint", enrichedAlert.Alert.Fingerprint),
+			slog.String("message_ts", entry.MessageTS),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordSlackMessageUpdate("success")
+	}
+	p.GetLogger().InfoContext(ctx, "Original message updated as resolved",
+		slog.String("fingerprint", enrichedAlert.Alert.Fingerprint),
+		slog.String("message_ts", entry.MessageTS))
+}
+
 // buildMessage builds SlackMessage from formatted payload (TN-051 output)
 // Converts formatter output (map[string]any) to Slack-specific structures
 func (p *EnhancedSlackPublisher) buildMessage(payload map[string]any) *SlackMessage {
@@ -202,21 +359,13 @@ func (p *EnhancedSlackPublisher) buildMessage(payload map[string]any) *SlackMess
 	}
 
 	// Extract blocks (Block Kit)
-	if blocksRaw, ok := payload["blocks"].([]interface{}); ok {
-		for _, blockRaw := range blocksRaw {
-			if blockMap, ok := blockRaw.(map[string]interface{}); ok {
-				message.Blocks = append(message.Blocks, p.buildBlock(blockMap))
-			}
-		}
+	for _, blockMap := range mapSlice(payload["blocks"]) {
+		message.Blocks = append(message.Blocks, p.buildBlock(blockMap))
 	}
 
 	// Extract attachments (color coding)
-	if attachmentsRaw, ok := payload["attachments"].([]interface{}); ok {
-		for _, attachRaw := range attachmentsRaw {
-			if attachMap, ok := attachRaw.(map[string]interface{}); ok {
-				message.Attachments = append(message.Attachments, p.buildAttachment(attachMap))
-			}
-		}
+	for _, attachMap := range mapSlice(payload["attachments"]) {
+		message.Attachments = append(message.Attachments, p.buildAttachment(attachMap))
 	}
 
 	return message
@@ -243,19 +392,15 @@ func (p *EnhancedSlackPublisher) buildBlock(blockMap map[string]interface{}) Blo
 	}
 
 	// Extract fields (for section blocks)
-	if fieldsRaw, ok := blockMap["fields"].([]interface{}); ok {
-		for _, fieldRaw := range fieldsRaw {
-			if fieldMap, ok := fieldRaw.(map[string]interface{}); ok {
-				field := Field{}
-				if fieldType, ok := fieldMap["type"].(string); ok {
-					field.Type = fieldType
-				}
-				if fieldText, ok := fieldMap["text"].(string); ok {
-					field.Text = fieldText
-				}
-				block.Fields = append(block.Fields, field)
-			}
+	for _, fieldMap := range mapSlice(blockMap["fields"]) {
+		field := Field{}
+		if fieldType, ok := fieldMap["type"].(string); ok {
+			field.Type = fieldType
+		}
+		if fieldText, ok := fieldMap["text"].(string); ok {
+			field.Text = fieldText
 		}
+		block.Fields = append(block.Fields, field)
 	}
 
 	return block
@@ -278,29 +423,33 @@ func (p *EnhancedSlackPublisher) buildAttachment(attachMap map[string]interface{
 	return attachment
 }
 
+// mapSlice returns the objects of a formatter list value. The formatter builds
+// []map[string]any directly, while JSON-decoded payloads hold []interface{}.
+func mapSlice(v interface{}) []map[string]interface{} {
+	switch items := v.(type) {
+	case []map[string]interface{}:
+		return items
+	case []interface{}:
+		out := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				out = append(out, m)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
 // classifySlackError classifies error for metrics labeling
 func classifySlackError(err error) string {
 	if err == nil {
 		return "unknown"
 	}
 
-	// Check for Slack API error
-	if IsSlackRetryableError(err) {
-		if IsSlackRateLimitError(err) {
-			return "rate_limit"
-		}
-		if IsSlackServerError(err) {
-			return "server_error"
-		}
-		return "api_error"
-	}
-
-	// Check for specific error types
-	if IsSlackAuthError(err) {
-		return "auth_error"
-	}
-	if IsSlackBadRequestError(err) {
-		return "bad_request"
+	if apiErr := AsPublishingError(err); apiErr != nil {
+		return apiErr.Type()
 	}
 
 	// Default: network error
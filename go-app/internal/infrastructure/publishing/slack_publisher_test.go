@@ -41,6 +41,14 @@ func (m *mockSlackWebhookClient) ReplyInThread(ctx context.Context, threadTS str
 	return args.Get(0).(*SlackResponse), args.Error(1)
 }
 
+func (m *mockSlackWebhookClient) UpdateMessage(ctx context.Context, channel, ts string, message *SlackMessage) (*SlackResponse, error) {
+	args := m.Called(ctx, channel, ts, message)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*SlackResponse), args.Error(1)
+}
+
 func (m *mockSlackWebhookClient) Health(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -90,6 +98,14 @@ func (m *mockSlackAlertFormatter) FormatAlert(ctx context.Context, enrichedAlert
 	return args.Get(0).(map[string]any), args.Error(1)
 }
 
+func (m *mockSlackAlertFormatter) FormatBatch(ctx context.Context, alerts []*core.EnrichedAlert, format core.PublishingFormat) (map[string]any, error) {
+	args := m.Called(ctx, alerts, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]any), args.Error(1)
+}
+
 // Helper functions
 
 func setupSlackPublisher(t *testing.T) (*EnhancedSlackPublisher, *mockSlackWebhookClient, *mockSlackMessageIDCache, *mockSlackAlertFormatter) {
@@ -130,6 +146,9 @@ func createSlackTestTarget() *core.PublishingTarget {
 	}
 }
 
+// slackTestKey is the message cache key of alert fp123 posted to the test target
+var slackTestKey = slackMessageKey("test-slack", "fp123")
+
 // Test cases
 
 // TestPublish_NewFiringAlert tests publishing a new firing alert
@@ -141,7 +160,7 @@ func TestPublish_NewFiringAlert(t *testing.T) {
 	target := createSlackTestTarget()
 
 	// Mock cache miss (no existing message)
-	cache.On("Get", "fp123").Return(nil, false)
+	cache.On("Get", slackTestKey).Return(nil, false)
 
 	// Mock formatter success
 	formattedPayload := map[string]any{
@@ -166,7 +185,7 @@ func TestPublish_NewFiringAlert(t *testing.T) {
 	client.On("PostMessage", ctx, mock.AnythingOfType("*publishing.SlackMessage")).Return(slackResp, nil)
 
 	// Mock cache store
-	cache.On("Store", "fp123", mock.MatchedBy(func(entry *MessageEntry) bool {
+	cache.On("Store", slackTestKey, mock.MatchedBy(func(entry *MessageEntry) bool {
 		return entry.MessageTS == "1234567890.123456" && entry.ThreadTS == "1234567890.123456"
 	})).Return()
 
@@ -180,6 +199,38 @@ func TestPublish_NewFiringAlert(t *testing.T) {
 	client.AssertExpectations(t)
 }
 
+// fakeSnoozeChecker snoozes the listed "user/fingerprint" pairs
+type fakeSnoozeChecker map[string]bool
+
+func (f fakeSnoozeChecker) IsSnoozed(userID, fingerprint string, now time.Time) bool {
+	return f[userID+"/"+fingerprint]
+}
+
+// TestPublish_NewFiringAlert_SkipsSnoozedMentions tests that users who snoozed the alert are not mentioned
+func TestPublish_NewFiringAlert_SkipsSnoozedMentions(t *testing.T) {
+	publisher, client, cache, formatter := setupSlackPublisher(t)
+	publisher.snoozes = fakeSnoozeChecker{"U2/fp123": true}
+	ctx := context.Background()
+
+	alert := createSlackTestAlert("fp123", "test-alert", core.StatusFiring)
+	alert.Alert.Annotations[slackMentionsAnnotation] = "U1, U2,U3"
+
+	cache.On("Get", slackTestKey).Return(nil, false)
+	cache.On("Store", slackTestKey, mock.Anything).Return()
+	formatter.On("FormatAlert", ctx, alert, core.FormatSlack).Return(map[string]any{"text": "Test alert"}, nil)
+
+	var posted *SlackMessage
+	client.On("PostMessage", ctx, mock.AnythingOfType("*publishing.SlackMessage")).
+		Run(func(args mock.Arguments) { posted = args.Get(1).(*SlackMessage) }).
+		Return(&SlackResponse{OK: true, TS: "1234567890.123456"}, nil)
+
+	require.NoError(t, publisher.Publish(ctx, alert, createSlackTestTarget()))
+	require.NotNil(t, posted)
+	require.Len(t, posted.Blocks, 1)
+	assert.Equal(t, "cc <@U1> <@U3>", posted.Blocks[0].Text.Text)
+	assert.Equal(t, "Test alert\ncc <@U1> <@U3>", posted.Text)
+}
+
 // TestPublish_ResolvedAlert_CacheHit tests publishing resolved alert (reply in thread)
 func TestPublish_ResolvedAlert_CacheHit(t *testing.T) {
 	publisher, client, cache, formatter := setupSlackPublisher(t)
@@ -194,7 +245,7 @@ func TestPublish_ResolvedAlert_CacheHit(t *testing.T) {
 		ThreadTS:  "1234567890.123456",
 		CreatedAt: time.Now(),
 	}
-	cache.On("Get", "fp123").Return(cacheEntry, true)
+	cache.On("Get", slackTestKey).Return(cacheEntry, true)
 
 	// Mock client thread reply success
 	slackResp := &SlackResponse{
@@ -203,6 +254,9 @@ func TestPublish_ResolvedAlert_CacheHit(t *testing.T) {
 	}
 	client.On("ReplyInThread", ctx, "1234567890.123456", mock.AnythingOfType("*publishing.SlackMessage")).Return(slackResp, nil)
 
+	// A new firing starts a new thread
+	cache.On("Delete", slackTestKey).Return()
+
 	// Execute
 	err := publisher.Publish(ctx, alert, target)
 
@@ -210,7 +264,122 @@ func TestPublish_ResolvedAlert_CacheHit(t *testing.T) {
 	require.NoError(t, err)
 	cache.AssertExpectations(t)
 	client.AssertExpectations(t)
-	formatter.AssertNotCalled(t, "FormatAlert") // No formatting needed for thread reply
+	formatter.AssertNotCalled(t, "FormatAlert") // Webhook messages cannot be updated
+	client.AssertNotCalled(t, "UpdateMessage")
+}
+
+// TestPublish_ResolvedAlert_UpdatesOriginalMessage tests that the message posted with the Web API is updated on resolve
+func TestPublish_ResolvedAlert_UpdatesOriginalMessage(t *testing.T) {
+	publisher, client, cache, formatter := setupSlackPublisher(t)
+	ctx := context.Background()
+
+	alert := createSlackTestAlert("fp123", "test-alert", core.StatusResolved)
+	cache.On("Get", slackTestKey).Return(&MessageEntry{
+		MessageTS: "1234567890.123456",
+		ThreadTS:  "1234567890.123456",
+		Channel:   "C123",
+		CreatedAt: time.Now(),
+	}, true)
+	cache.On("Delete", slackTestKey).Return()
+	client.On("ReplyInThread", ctx, "1234567890.123456", mock.AnythingOfType("*publishing.SlackMessage")).
+		Return(&SlackResponse{OK: true, TS: "1234567890.123457"}, nil)
+	formatter.On("FormatAlert", ctx, alert, core.FormatSlack).Return(map[string]any{
+		"text":        "✅ test-alert - resolved",
+		"attachments": []interface{}{map[string]interface{}{"color": ColorResolved}},
+	}, nil)
+
+	var updated *SlackMessage
+	client.On("UpdateMessage", ctx, "C123", "1234567890.123456", mock.AnythingOfType("*publishing.SlackMessage")).
+		Run(func(args mock.Arguments) { updated = args.Get(3).(*SlackMessage) }).
+		Return(&SlackResponse{OK: true, TS: "1234567890.123456"}, nil)
+
+	require.NoError(t, publisher.Publish(ctx, alert, createSlackTestTarget()))
+	require.NotNil(t, updated)
+	assert.Equal(t, "✅ test-alert - resolved", updated.Text)
+	require.Len(t, updated.Attachments, 1)
+	assert.Equal(t, ColorResolved, updated.Attachments[0].Color)
+	cache.AssertExpectations(t)
+}
+
+// TestPublish_ResolvedAlert_UpdateErrorIgnored tests that a failed update does not fail the published resolve
+func TestPublish_ResolvedAlert_UpdateErrorIgnored(t *testing.T) {
+	publisher, client, cache, formatter := setupSlackPublisher(t)
+	ctx := context.Background()
+
+	alert := createSlackTestAlert("fp123", "test-alert", core.StatusResolved)
+	cache.On("Get", slackTestKey).Return(&MessageEntry{MessageTS: "1.1", ThreadTS: "1.1", Channel: "C123"}, true)
+	cache.On("Delete", slackTestKey).Return()
+	client.On("ReplyInThread", ctx, "1.1", mock.Anything).Return(&SlackResponse{OK: true, TS: "1.2"}, nil)
+	formatter.On("FormatAlert", ctx, alert, core.FormatSlack).Return(map[string]any{"text": "resolved"}, nil)
+	client.On("UpdateMessage", ctx, "C123", "1.1", mock.Anything).Return(nil, errors.New("message_not_found"))
+
+	require.NoError(t, publisher.Publish(ctx, alert, createSlackTestTarget()))
+	client.AssertExpectations(t)
+}
+
+// TestPublish_Reclassified_RepliesInThread tests that a severity change is posted in the thread
+func TestPublish_Reclassified_RepliesInThread(t *testing.T) {
+	publisher, client, cache, _ := setupSlackPublisher(t)
+	ctx := context.Background()
+
+	alert := createSlackTestAlert("fp123", "test-alert", core.StatusFiring)
+	alert.Classification.Severity = core.SeverityWarning
+	cache.On("Get", slackTestKey).Return(&MessageEntry{
+		MessageTS: "1234567890.123456",
+		ThreadTS:  "1234567890.123456",
+		Severity:  string(core.SeverityCritical),
+	}, true)
+	cache.On("Store", slackTestKey, mock.MatchedBy(func(entry *MessageEntry) bool {
+		return entry.Severity == string(core.SeverityWarning) && entry.ThreadTS == "1234567890.123456"
+	})).Return()
+
+	var reply *SlackMessage
+	client.On("ReplyInThread", ctx, "1234567890.123456", mock.AnythingOfType("*publishing.SlackMessage")).
+		Run(func(args mock.Arguments) { reply = args.Get(2).(*SlackMessage) }).
+		Return(&SlackResponse{OK: true, TS: "1234567890.123457"}, nil)
+
+	require.NoError(t, publisher.Publish(ctx, alert, createSlackTestTarget()))
+	require.NotNil(t, reply)
+	assert.Contains(t, reply.Text, "Reclassified: critical → warning")
+	cache.AssertExpectations(t)
+}
+
+// TestPublish_ThreadsPerTarget tests that each target threads its own message of an alert
+func TestPublish_ThreadsPerTarget(t *testing.T) {
+	client := new(mockSlackWebhookClient)
+	formatter := new(mockSlackAlertFormatter)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	publisher := NewEnhancedSlackPublisher(client, NewMessageCache(), nil, formatter, logger)
+	ctx := context.Background()
+
+	alert := createSlackTestAlert("fp123", "test-alert", core.StatusFiring)
+	formatter.On("FormatAlert", mock.Anything, alert, core.FormatSlack).Return(map[string]any{"text": "Test alert"}, nil)
+	client.On("PostMessage", mock.Anything, mock.Anything).Return(&SlackResponse{OK: true, TS: "1.1", Channel: "C1"}, nil).Once()
+	client.On("PostMessage", mock.Anything, mock.Anything).Return(&SlackResponse{OK: true, TS: "2.1", Channel: "C2"}, nil).Once()
+	client.On("ReplyInThread", mock.Anything, "2.1", mock.Anything).Return(&SlackResponse{OK: true, TS: "2.2"}, nil).Once()
+
+	first := createSlackTestTarget()
+	second := createSlackTestTarget()
+	second.Name = "test-slack-2"
+	require.NoError(t, publisher.Publish(ctx, alert, first))
+	require.NoError(t, publisher.Publish(ctx, alert, second))
+	require.NoError(t, publisher.Publish(ctx, alert, second))
+
+	client.AssertExpectations(t)
+}
+
+// TestPublish_WebhookResponseWithoutTS tests that messages without ts (incoming webhooks) are not cached
+func TestPublish_WebhookResponseWithoutTS(t *testing.T) {
+	publisher, client, cache, formatter := setupSlackPublisher(t)
+	ctx := context.Background()
+
+	alert := createSlackTestAlert("fp123", "test-alert", core.StatusFiring)
+	cache.On("Get", slackTestKey).Return(nil, false)
+	formatter.On("FormatAlert", ctx, alert, core.FormatSlack).Return(map[string]any{"text": "Test alert"}, nil)
+	client.On("PostMessage", ctx, mock.Anything).Return(&SlackResponse{OK: true}, nil)
+
+	require.NoError(t, publisher.Publish(ctx, alert, createSlackTestTarget()))
+	cache.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
 }
 
 // TestPublish_ResolvedAlert_CacheMiss tests resolved alert without firing message
@@ -222,7 +391,7 @@ func TestPublish_ResolvedAlert_CacheMiss(t *testing.T) {
 	target := createSlackTestTarget()
 
 	// Mock cache miss
-	cache.On("Get", "fp123").Return(nil, false)
+	cache.On("Get", slackTestKey).Return(nil, false)
 
 	// Mock formatter success
 	formattedPayload := map[string]any{
@@ -238,7 +407,7 @@ func TestPublish_ResolvedAlert_CacheMiss(t *testing.T) {
 	client.On("PostMessage", ctx, mock.AnythingOfType("*publishing.SlackMessage")).Return(slackResp, nil)
 
 	// Mock cache store
-	cache.On("Store", "fp123", mock.AnythingOfType("*publishing.MessageEntry")).Return()
+	cache.On("Store", slackTestKey, mock.AnythingOfType("*publishing.MessageEntry")).Return()
 
 	// Execute
 	err := publisher.Publish(ctx, alert, target)
@@ -264,7 +433,7 @@ func TestPublish_StillFiring_CacheHit(t *testing.T) {
 		ThreadTS:  "1234567890.123456",
 		CreatedAt: time.Now().Add(-10 * time.Minute), // Old firing alert
 	}
-	cache.On("Get", "fp123").Return(cacheEntry, true)
+	cache.On("Get", slackTestKey).Return(cacheEntry, true)
 
 	// Mock client thread reply success
 	slackResp := &SlackResponse{
@@ -291,7 +460,7 @@ func TestPublish_FormatterError(t *testing.T) {
 	target := createSlackTestTarget()
 
 	// Mock cache miss
-	cache.On("Get", "fp123").Return(nil, false)
+	cache.On("Get", slackTestKey).Return(nil, false)
 
 	// Mock formatter error
 	formatter.On("FormatAlert", ctx, alert, core.FormatSlack).Return(nil, errors.New("formatter error"))
@@ -315,7 +484,7 @@ func TestPublish_ClientError(t *testing.T) {
 	target := createSlackTestTarget()
 
 	// Mock cache miss
-	cache.On("Get", "fp123").Return(nil, false)
+	cache.On("Get", slackTestKey).Return(nil, false)
 
 	// Mock formatter success
 	formattedPayload := map[string]any{"text": "Test"}
@@ -349,7 +518,7 @@ func TestPublish_ThreadReplyError(t *testing.T) {
 		ThreadTS:  "1234567890.123456",
 		CreatedAt: time.Now(),
 	}
-	cache.On("Get", "fp123").Return(cacheEntry, true)
+	cache.On("Get", slackTestKey).Return(cacheEntry, true)
 
 	// Mock client error
 	client.On("ReplyInThread", ctx, "1234567890.123456", mock.AnythingOfType("*publishing.SlackMessage")).Return(nil, errors.New("thread reply error"))
@@ -373,7 +542,7 @@ func TestPublish_UnknownStatus(t *testing.T) {
 	target := createSlackTestTarget()
 
 	// Mock cache miss (Publish will check cache regardless of status)
-	cache.On("Get", "fp123").Return(nil, false)
+	cache.On("Get", slackTestKey).Return(nil, false)
 
 	// Execute
 	err := publisher.Publish(ctx, alert, target)
@@ -0,0 +1,159 @@
+package publishing
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// TargetForgetter is implemented by components that keep state per
+// publishing target, to release it once the target is removed.
+type TargetForgetter interface {
+	ForgetTarget(name string)
+}
+
+// TargetGCConfig configures TargetGC.
+type TargetGCConfig struct {
+	// Interval between collections (default: 1m).
+	Interval time.Duration
+
+	// Metrics whose gauge series of removed targets are deleted (optional).
+	Metrics *v2.PublishingMetrics
+
+	Logger *slog.Logger
+}
+
+// DefaultTargetGCInterval is the default interval between collections.
+const DefaultTargetGCInterval = time.Minute
+
+// TargetGC releases the state kept for targets that were removed from
+// discovery (deleted secrets, renamed targets). Without it their circuit
+// breakers, groups, provider clients and gauge series would live forever.
+//
+// A target is removed when it was discovered at the previous collection,
+// or the queue has state for it, and discovery no longer lists it. Then:
+//   - the queue drops its circuit breaker and drains its queued jobs to the DLQ
+//   - forgetters (target groupings, health status) release its state
+//   - its gauge series are deleted
+//
+// Provider clients that no discovered target uses anymore are dropped on
+// every collection.
+type TargetGC struct {
+	discovery  TargetDiscoveryManager
+	queue      *PublishingQueue
+	factory    *PublisherFactory
+	metrics    *v2.PublishingMetrics
+	interval   time.Duration
+	logger     *slog.Logger
+	forgetters []TargetForgetter
+
+	mu    sync.Mutex
+	known map[string]bool // targets discovered at the previous collection
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewTargetGC creates a target garbage collector. factory may be nil.
+func NewTargetGC(discovery TargetDiscoveryManager, queue *PublishingQueue, factory *PublisherFactory, config TargetGCConfig) *TargetGC {
+	if config.Interval <= 0 {
+		config.Interval = DefaultTargetGCInterval
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	return &TargetGC{
+		discovery: discovery,
+		queue:     queue,
+		factory:   factory,
+		metrics:   config.Metrics,
+		interval:  config.Interval,
+		logger:    config.Logger.With("component", "target_gc"),
+		known:     make(map[string]bool),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// AddForgetter registers a component to notify of removed targets. Call it
+// before Start.
+func (gc *TargetGC) AddForgetter(forgetter TargetForgetter) {
+	gc.forgetters = append(gc.forgetters, forgetter)
+}
+
+// Collect releases the state of removed targets and returns their names.
+func (gc *TargetGC) Collect() []string {
+	targets := gc.discovery.ListTargets()
+	active := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		active[target.Name] = true
+	}
+
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	candidates := gc.known
+	for _, name := range gc.queue.TargetNames() {
+		candidates[name] = true
+	}
+	var removed []string
+	for name := range candidates {
+		if !active[name] {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(removed)
+
+	for _, name := range removed {
+		gc.queue.RemoveTarget(name)
+		for _, forgetter := range gc.forgetters {
+			forgetter.ForgetTarget(name)
+		}
+		deleted := 0
+		if gc.metrics != nil {
+			deleted = gc.metrics.DeleteTargetSeries(name)
+		}
+		gc.logger.Info("Released state of removed publishing target",
+			"target", name,
+			"deleted_series", deleted,
+		)
+	}
+	gc.queue.restoreTargets(active)
+	if gc.factory != nil {
+		gc.factory.RetainTargets(targets)
+	}
+
+	gc.known = active
+	return removed
+}
+
+// Start collects every interval until ctx is done or Stop is called.
+func (gc *TargetGC) Start(ctx context.Context) {
+	gc.wg.Add(1)
+	go func() {
+		defer gc.wg.Done()
+		ticker := time.NewTicker(gc.interval)
+		defer ticker.Stop()
+		gc.Collect() // remember the targets discovered at startup
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-gc.stopCh:
+				return
+			case <-ticker.C:
+				gc.Collect()
+			}
+		}
+	}()
+}
+
+// Stop stops collecting and waits for a running collection.
+func (gc *TargetGC) Stop() {
+	gc.stopOnce.Do(func() { close(gc.stopCh) })
+	gc.wg.Wait()
+}
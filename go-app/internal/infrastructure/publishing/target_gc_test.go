@@ -0,0 +1,160 @@
+package publishing
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingForgetter records the targets it was asked to forget.
+type recordingForgetter struct {
+	names []string
+}
+
+func (f *recordingForgetter) ForgetTarget(name string) {
+	f.names = append(f.names, name)
+}
+
+func countGaugeSeries(t *testing.T, reg *prometheus.Registry, name string) int {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			return len(family.GetMetric())
+		}
+	}
+	return 0
+}
+
+func TestTargetGC_Collect(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := v2.NewRegistry(v2.WithPrometheusRegisterer(reg)).Publishing
+	dlq := &recordingDLQRepository{}
+	queue := NewPublishingQueue(nil, dlq, NewLRUJobTrackingStore(16), PublishingQueueConfig{
+		WorkerCount:             1,
+		HighPriorityQueueSize:   4,
+		MediumPriorityQueueSize: 4,
+		LowPriorityQueueSize:    4,
+		Metrics:                 metrics,
+	}, nil, slog.Default())
+
+	oldTarget := &core.PublishingTarget{Name: "old-webhook", Type: "webhook", URL: "https://old.example.com"}
+	keptTarget := &core.PublishingTarget{Name: "kept-webhook", Type: "webhook", URL: "https://kept.example.com"}
+	discovery := NewStubTargetDiscoveryManager(slog.Default())
+	discovery.SetTargets([]*core.PublishingTarget{oldTarget, keptTarget})
+
+	forgetter := &recordingForgetter{}
+	gc := NewTargetGC(discovery, queue, nil, TargetGCConfig{Metrics: metrics})
+	gc.AddForgetter(forgetter)
+
+	queue.getCircuitBreaker(oldTarget)
+	queue.getCircuitBreaker(keptTarget)
+	metrics.SetCircuitBreakerState(oldTarget.Name, v2.CircuitBreakerOpen)
+	metrics.SetCircuitBreakerState(keptTarget.Name, v2.CircuitBreakerClosed)
+	assert.Empty(t, gc.Collect())
+
+	// The target is removed with a job still queued
+	require.NoError(t, queue.Submit(panicTestAlert(), oldTarget))
+	discovery.RemoveTarget(oldTarget.Name)
+
+	assert.Equal(t, []string{"old-webhook"}, gc.Collect())
+	assert.Equal(t, []string{"old-webhook"}, forgetter.names)
+	assert.Equal(t, []string{"kept-webhook"}, queue.TargetNames())
+	assert.Equal(t, 1, countGaugeSeries(t, reg, "alert_history_publishing_circuit_breaker_state"))
+	assert.True(t, queue.isTargetRemoved(oldTarget.Name))
+
+	// The queued job is drained to the DLQ instead of published
+	queue.Start()
+	t.Cleanup(func() { _ = queue.Stop(time.Second) })
+	require.Eventually(t, func() bool { return len(dlq.written()) == 1 }, time.Second, 5*time.Millisecond)
+	job := dlq.written()[0]
+	assert.Equal(t, JobStateDLQ, job.State)
+	assert.True(t, errors.Is(job.LastError, ErrTargetRemoved))
+	require.Eventually(t, func() bool { return queue.GetQueueSize() == 0 }, time.Second, 5*time.Millisecond)
+
+	// Nothing left to drain: removals are forgotten, re-added targets publish again
+	assert.Empty(t, gc.Collect())
+	assert.False(t, queue.isTargetRemoved(oldTarget.Name))
+	assert.Equal(t, []string{"old-webhook"}, forgetter.names)
+}
+
+func TestPublishingQueue_RemovalKeptWhileJobsOutstanding(t *testing.T) {
+	dlq := &recordingDLQRepository{}
+	queue := newPanickingQueue(dlq)
+	job := cooldownTestJob("slack-ops", ProviderSlack)
+
+	// A job requeued after a provider cooldown is in no job channel
+	queue.RemoveTarget("slack-ops")
+	queue.requeueAfter(job, time.Now().Add(200*time.Millisecond))
+	require.Equal(t, 0, queue.GetQueueSize())
+
+	queue.restoreTargets(nil)
+	assert.True(t, queue.isTargetRemoved("slack-ops"), "removal forgotten with a requeued job")
+
+	// Requeued, then drained instead of published
+	require.Eventually(t, func() bool { return queue.requeuedJobs.Load() == 0 }, time.Second, 5*time.Millisecond)
+	queue.restoreTargets(nil)
+	assert.True(t, queue.isTargetRemoved("slack-ops"), "removal forgotten with a queued job")
+	queue.Start()
+	t.Cleanup(func() { _ = queue.Stop(time.Second) })
+	require.Eventually(t, func() bool { return len(dlq.written()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.True(t, errors.Is(dlq.written()[0].LastError, ErrTargetRemoved))
+	require.Eventually(t, func() bool {
+		queue.mu.RLock()
+		defer queue.mu.RUnlock()
+		return len(queue.targetJobs) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	queue.restoreTargets(nil)
+	assert.False(t, queue.isTargetRemoved("slack-ops"))
+}
+
+func TestPublishingQueue_RemoveTargetDropsCooldownAndBatch(t *testing.T) {
+	dlq := &recordingDLQRepository{}
+	queue := newPanickingQueue(dlq)
+	target := &core.PublishingTarget{
+		Name:    "batched-webhook",
+		Type:    "webhook",
+		URL:     "https://hooks.example.com",
+		Headers: map[string]string{targetBatchFlushIntervalHeader: "1m"},
+	}
+
+	queue.cooldowns.pause(target.Name, time.Now().Add(time.Hour))
+	require.NoError(t, queue.Submit(panicTestAlert(), target))
+	require.Equal(t, 0, queue.GetQueueSize())
+
+	// The open batch is flushed right away and its job drained
+	queue.RemoveTarget(target.Name)
+	assert.True(t, queue.cooldowns.pausedUntil(target.Name, time.Now()).IsZero())
+	assert.Empty(t, queue.batcher.batches)
+	require.Equal(t, 1, queue.GetQueueSize())
+
+	queue.Start()
+	t.Cleanup(func() { _ = queue.Stop(time.Second) })
+	require.Eventually(t, func() bool { return len(dlq.written()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.True(t, errors.Is(dlq.written()[0].LastError, ErrTargetRemoved))
+}
+
+func TestPublisherFactory_RetainTargets(t *testing.T) {
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	t.Cleanup(factory.Shutdown)
+
+	kept := &core.PublishingTarget{Name: "jira", URL: "https://jira.example.com", Headers: map[string]string{"Authorization": "Bearer new"}}
+	rotated := &core.PublishingTarget{Name: "jira", URL: "https://jira.example.com", Headers: map[string]string{"Authorization": "Bearer old"}}
+	_, err := factory.jiraClients.get(rotated)
+	require.NoError(t, err)
+	_, err = factory.jiraClients.get(kept)
+	require.NoError(t, err)
+
+	factory.RetainTargets([]*core.PublishingTarget{kept})
+	assert.Len(t, factory.jiraClients.clients, 1)
+	assert.Contains(t, factory.jiraClients.clients, kept.URL+"\x00Bearer new")
+}
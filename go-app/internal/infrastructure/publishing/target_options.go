@@ -0,0 +1,86 @@
+package publishing
+
+import "strings"
+
+// targetOptions are the target headers configuring how AMP publishes to a
+// target (grouping, batching, limits, circuit breaker, fallback, schedule,
+// dedup, redaction, outbound HTTP, payload template and signing) rather
+// than HTTP headers of its requests. A new option is registered here, so
+// that no publisher sends it to the receiver.
+var targetOptions = map[string]struct{}{
+	targetGroupByHeader:                 {},
+	targetGroupWaitHeader:               {},
+	targetGroupIntervalHeader:           {},
+	targetBatchMaxSizeHeader:            {},
+	targetBatchFlushIntervalHeader:      {},
+	targetRateLimitHeader:               {},
+	targetRateBurstHeader:               {},
+	targetMaxConcurrencyHeader:          {},
+	targetCircuitFailureThresholdHeader: {},
+	targetCircuitSuccessThresholdHeader: {},
+	targetCircuitTimeoutHeader:          {},
+	targetFallbackHeader:                {},
+	targetNotifyAfterHeader:             {},
+	targetRepeatIntervalHeader:          {},
+	targetDedupWindowHeader:             {},
+	targetRedactLabelsHeader:            {},
+	targetRedactAnnotationsHeader:       {},
+	targetRedactScrubbersHeader:         {},
+	targetRedactPatternHeader:           {},
+	targetProxyURLHeader:                {},
+	targetTLSCAFileHeader:               {},
+	targetTLSCertFileHeader:             {},
+	targetTLSKeyFileHeader:              {},
+	targetTLSServerNameHeader:           {},
+	targetTLSMinVersionHeader:           {},
+	targetTLSInsecureSkipVerifyHeader:   {},
+	targetOAuth2TokenURLHeader:          {},
+	targetOAuth2ClientIDHeader:          {},
+	targetOAuth2ClientSecretHeader:      {},
+	targetOAuth2ScopesHeader:            {},
+	targetPayloadTemplateHeader:         {},
+	targetSigningSecretHeader:           {},
+}
+
+// targetOptionPrefixes are the prefixes of the severity style overrides.
+var targetOptionPrefixes = []string{
+	targetEmojiHeaderPrefix,
+	targetColorHeaderPrefix,
+	targetCardColorHeaderPrefix,
+}
+
+// isTargetOption reports whether the target header key is a target option,
+// never sent as an HTTP header.
+func isTargetOption(key string) bool {
+	if _, ok := targetOptions[key]; ok {
+		return true
+	}
+	for _, prefix := range targetOptionPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetHTTPHeaders returns the target headers sent as HTTP headers: all
+// but the target options. headers is returned as is when it has none.
+func targetHTTPHeaders(headers map[string]string) map[string]string {
+	found := false
+	for k := range headers {
+		if isTargetOption(k) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return headers
+	}
+	filtered := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if !isTargetOption(k) {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,80 @@
+package publishing
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optionHeaders sets one of each kind of target option next to X-Team.
+func optionHeaders() map[string]string {
+	return map[string]string{
+		"X-Team":                    "sre",
+		"group_by":                  "service",
+		"group_wait":                "10s",
+		"group_interval":            "1m",
+		"batch_max_size":            "10",
+		"rate_limit":                "5",
+		"circuit_failure_threshold": "3",
+		"circuit_timeout":           "1m",
+		"fallback":                  "backup",
+		"notify_after":              "1m",
+		"dedup_window":              "5m",
+		"redact_labels":             "instance",
+		"proxy_url":                 "http://proxy:3128",
+		"oauth2_client_secret":      "s3cret",
+		"payload_template":          `{"text":"x"}`,
+		"signing_secret":            "s3cret",
+		"emoji_critical":            ":fire:",
+		"color_warning":             "#ffaa00",
+		"card_color_resolved":       "good",
+	}
+}
+
+func TestTargetHTTPHeaders(t *testing.T) {
+	plain := map[string]string{"X-Team": "sre", "Authorization": "Bearer x"}
+	assert.Equal(t, plain, targetHTTPHeaders(plain))
+
+	headers := optionHeaders()
+	assert.Equal(t, map[string]string{"X-Team": "sre"}, targetHTTPHeaders(headers))
+	assert.Len(t, headers, 19, "target headers are not modified")
+}
+
+func TestPublishers_DoNotSendTargetOptions(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	enhanced, err := factory.CreatePublisher("webhook")
+	require.NoError(t, err)
+	publishers := []AlertPublisher{NewWebhookPublisher(NewAlertFormatter(""), slog.Default()), enhanced}
+
+	enrichedAlert := &core.EnrichedAlert{
+		Alert: &core.Alert{Fingerprint: "fp-options", AlertName: "TestAlert", Status: core.StatusFiring},
+	}
+	for _, publisher := range publishers {
+		t.Run(publisher.Name(), func(t *testing.T) {
+			target := &core.PublishingTarget{Name: "options", Type: "webhook", URL: server.URL, Format: core.FormatWebhook, Headers: optionHeaders()}
+			target.Headers["proxy_url"] = "" // keep the request direct
+			delete(target.Headers, "payload_template")
+
+			require.NoError(t, publisher.Publish(context.Background(), enrichedAlert, target))
+			assert.Equal(t, "sre", received.Get("X-Team"))
+			for key := range target.Headers {
+				if key != "X-Team" {
+					assert.Empty(t, received.Values(key), "target option %s was sent", key)
+				}
+			}
+		})
+	}
+}
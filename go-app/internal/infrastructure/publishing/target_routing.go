@@ -0,0 +1,244 @@
+package publishing
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// TargetRoute is a route of a TargetRouter: the alerts whose labels match
+// all of Match and MatchRE are published to Targets, unless a child route
+// matches them too.
+type TargetRoute struct {
+	// Targets are the names of the targets of the route. Empty inherits
+	// the targets of the parent route; at the root, all targets.
+	Targets []string
+
+	// Match requires labels to equal these values.
+	Match map[string]string
+
+	// MatchRE requires labels to match these regular expressions, anchored
+	// at both ends.
+	MatchRE map[string]string
+
+	// Continue keeps matching the next sibling routes once this route
+	// matched.
+	Continue bool
+
+	Routes []TargetRoute
+}
+
+// TargetRouter selects the publishing targets of alerts by their labels,
+// with the semantics of the Alertmanager route tree: the deepest matching
+// routes win, a matching route stops its next siblings unless it has
+// Continue, and the root route catches the alerts no child route matches.
+type TargetRouter struct {
+	root *compiledTargetRoute
+}
+
+type compiledTargetRoute struct {
+	labelMatchers
+	path      string
+	targets   []string // nil: all targets
+	continues bool
+	routes    []*compiledTargetRoute
+}
+
+// labelMatchers match the labels of alerts: equal to every value of match
+// and fully matching every regular expression of matchRE.
+type labelMatchers struct {
+	match   map[string]string
+	matchRE map[string]*regexp.Regexp
+}
+
+// compileLabelMatchers compiles matchRE, anchored at both ends as in
+// Alertmanager. Errors are prefixed with path.
+func compileLabelMatchers(match, matchRE map[string]string, path string) (labelMatchers, error) {
+	matchers := labelMatchers{match: match, matchRE: make(map[string]*regexp.Regexp, len(matchRE))}
+	for name, pattern := range matchRE {
+		regex, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return labelMatchers{}, fmt.Errorf("%s.match_re.%s: %w", path, name, err)
+		}
+		matchers.matchRE[name] = regex
+	}
+	return matchers, nil
+}
+
+func (m labelMatchers) matches(labels map[string]string) bool {
+	for name, value := range m.match {
+		if labels[name] != value {
+			return false
+		}
+	}
+	for name, regex := range m.matchRE {
+		if !regex.MatchString(labels[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TargetRouting is the outcome of routing an alert.
+type TargetRouting struct {
+	// Routes are the paths of the matched routes that selected the
+	// targets, e.g. "route" or "route.routes[0].routes[1]".
+	Routes []string
+
+	// Targets are the target names of the matched routes, unless All.
+	Targets []string
+
+	// All is true when a matched route selects all targets.
+	All bool
+}
+
+// NewTargetRouter compiles the route tree under root. The root route
+// matches every alert: it cannot have matchers.
+func NewTargetRouter(root TargetRoute) (*TargetRouter, error) {
+	if len(root.Match) > 0 || len(root.MatchRE) > 0 {
+		return nil, fmt.Errorf("route: the root route cannot have matchers")
+	}
+	compiled, err := compileTargetRoute(root, "route", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TargetRouter{root: compiled}, nil
+}
+
+func compileTargetRoute(route TargetRoute, path string, parentTargets []string) (*compiledTargetRoute, error) {
+	matchers, err := compileLabelMatchers(route.Match, route.MatchRE, path)
+	if err != nil {
+		return nil, err
+	}
+	compiled := &compiledTargetRoute{
+		labelMatchers: matchers,
+		path:          path,
+		targets:       parentTargets,
+		continues:     route.Continue,
+	}
+	if len(route.Targets) > 0 {
+		compiled.targets = route.Targets
+	}
+	for i, child := range route.Routes {
+		compiledChild, err := compileTargetRoute(child, fmt.Sprintf("%s.routes[%d]", path, i), compiled.targets)
+		if err != nil {
+			return nil, err
+		}
+		compiled.routes = append(compiled.routes, compiledChild)
+	}
+	return compiled, nil
+}
+
+// Route returns the routes matched by an alert with labels and their
+// targets.
+func (r *TargetRouter) Route(labels map[string]string) TargetRouting {
+	var routing TargetRouting
+	for _, route := range r.root.matching(labels) {
+		routing.Routes = append(routing.Routes, route.path)
+		if route.targets == nil {
+			routing.All = true
+		}
+		for _, target := range route.targets {
+			if !slices.Contains(routing.Targets, target) {
+				routing.Targets = append(routing.Targets, target)
+			}
+		}
+	}
+	if routing.All {
+		routing.Targets = nil
+	}
+	return routing
+}
+
+// matching returns the deepest routes below route (including route itself)
+// that match labels, or nil if route does not match.
+func (route *compiledTargetRoute) matching(labels map[string]string) []*compiledTargetRoute {
+	if !route.matches(labels) {
+		return nil
+	}
+
+	var matched []*compiledTargetRoute
+	for _, child := range route.routes {
+		childMatched := child.matching(labels)
+		matched = append(matched, childMatched...)
+		if childMatched != nil && !child.continues {
+			break
+		}
+	}
+	if len(matched) == 0 {
+		matched = []*compiledTargetRoute{route}
+	}
+	return matched
+}
+
+// Selects reports whether the routing selects target.
+func (routing TargetRouting) Selects(target *core.PublishingTarget) bool {
+	return routing.All || slices.Contains(routing.Targets, target.Name)
+}
+
+// routingLabels returns the labels alert is routed by: its labels, with
+// severity set to its effective severity (see publishing.severity_policy)
+// so that classified alerts are routed by their classification.
+func routingLabels(alert *core.EnrichedAlert) map[string]string {
+	labels := make(map[string]string, len(alert.Alert.Labels)+1)
+	for name, value := range alert.Alert.Labels {
+		labels[name] = value
+	}
+	if severity, _ := alert.EffectiveSeverity(); severity != "" {
+		labels["severity"] = string(severity)
+	}
+	return labels
+}
+
+// RoutedTargets are the targets an alert is published to.
+type RoutedTargets struct {
+	// Routes are the paths of the matched routes, empty without routing.
+	Routes []string
+
+	// Targets are the enabled targets the alert is published to.
+	Targets []*core.PublishingTarget
+
+	// Missing are the targets of the matched routes that are not discovered
+	// or disabled.
+	Missing []string
+}
+
+// SetTargetRouter makes PublishToAll publish every alert to the targets
+// router selects rather than to all enabled targets. Call it before
+// publishing starts.
+func (c *PublishingCoordinator) SetTargetRouter(router *TargetRouter) {
+	c.router = router
+}
+
+// RouteTargets returns the targets PublishToAll would publish enrichedAlert
+// to, and the routes that select them.
+func (c *PublishingCoordinator) RouteTargets(enrichedAlert *core.EnrichedAlert) RoutedTargets {
+	var enabledTargets []*core.PublishingTarget
+	for _, target := range c.discoveryManager.ListTargets() {
+		if target.Enabled {
+			enabledTargets = append(enabledTargets, target)
+		}
+	}
+	if c.router == nil {
+		return RoutedTargets{Targets: enabledTargets}
+	}
+	return c.routeTargets(enrichedAlert, enabledTargets)
+}
+
+func (c *PublishingCoordinator) routeTargets(enrichedAlert *core.EnrichedAlert, enabledTargets []*core.PublishingTarget) RoutedTargets {
+	routing := c.router.Route(routingLabels(enrichedAlert))
+	routed := RoutedTargets{Routes: routing.Routes}
+	for _, target := range enabledTargets {
+		if routing.Selects(target) {
+			routed.Targets = append(routed.Targets, target)
+		}
+	}
+	for _, name := range routing.Targets {
+		if !slices.ContainsFunc(routed.Targets, func(target *core.PublishingTarget) bool { return target.Name == name }) {
+			routed.Missing = append(routed.Missing, name)
+		}
+	}
+	return routed
+}
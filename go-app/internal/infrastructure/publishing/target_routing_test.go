@@ -0,0 +1,118 @@
+package publishing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestTargetRouter_Route(t *testing.T) {
+	router, err := NewTargetRouter(TargetRoute{
+		Routes: []TargetRoute{
+			{
+				Targets:  []string{"pagerduty"},
+				Match:    map[string]string{"severity": "critical"},
+				Continue: true,
+				Routes: []TargetRoute{
+					{Targets: []string{"oncall-db"}, Match: map[string]string{"team": "db"}},
+				},
+			},
+			{
+				Targets: []string{"slack-infra"},
+				MatchRE: map[string]string{"namespace": "kube-.*|monitoring"},
+				Routes: []TargetRoute{
+					{Match: map[string]string{"severity": "critical"}},
+				},
+			},
+			{Targets: []string{"slack-apps"}, Match: map[string]string{"severity": "critical"}},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   TargetRouting
+	}{
+		{
+			name:   "no route matches",
+			labels: map[string]string{"severity": "info"},
+			want:   TargetRouting{Routes: []string{"route"}, All: true},
+		},
+		{
+			name:   "regex is anchored",
+			labels: map[string]string{"namespace": "app-monitoring"},
+			want:   TargetRouting{Routes: []string{"route"}, All: true},
+		},
+		{
+			name:   "child inherits the targets",
+			labels: map[string]string{"namespace": "monitoring", "severity": "critical"},
+			want: TargetRouting{
+				Routes:  []string{"route.routes[0]", "route.routes[1].routes[0]"},
+				Targets: []string{"pagerduty", "slack-infra"},
+			},
+		},
+		{
+			name:   "deepest route wins and continue reaches the next sibling",
+			labels: map[string]string{"team": "db", "severity": "critical"},
+			want: TargetRouting{
+				Routes:  []string{"route.routes[0].routes[0]", "route.routes[2]"},
+				Targets: []string{"oncall-db", "slack-apps"},
+			},
+		},
+		{
+			name:   "first match stops",
+			labels: map[string]string{"namespace": "kube-system", "severity": "warning"},
+			want:   TargetRouting{Routes: []string{"route.routes[1]"}, Targets: []string{"slack-infra"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, router.Route(tt.labels))
+		})
+	}
+}
+
+func TestNewTargetRouter_Invalid(t *testing.T) {
+	_, err := NewTargetRouter(TargetRoute{Match: map[string]string{"team": "db"}})
+	assert.Error(t, err, "root route with matchers")
+
+	_, err = NewTargetRouter(TargetRoute{Routes: []TargetRoute{{MatchRE: map[string]string{"team": "db("}}}})
+	assert.ErrorContains(t, err, "route.routes[0].match_re.team")
+}
+
+func TestPublishingCoordinator_RouteTargets(t *testing.T) {
+	c := &PublishingCoordinator{discoveryManager: &mockTargetDiscoveryManager{targets: []*core.PublishingTarget{
+		{Name: "pagerduty", Enabled: true},
+		{Name: "slack", Enabled: true},
+		{Name: "paused"},
+	}}}
+	alert := &core.EnrichedAlert{
+		Alert:              &core.Alert{Fingerprint: "fp-1", Labels: map[string]string{"severity": "warning"}},
+		EnrichmentMetadata: map[string]any{core.MetadataEffectiveSeverity: "critical"},
+	}
+	assert.Len(t, c.RouteTargets(alert).Targets, 2, "all enabled targets without router")
+
+	router, err := NewTargetRouter(TargetRoute{
+		Targets: []string{"slack"},
+		Routes: []TargetRoute{
+			{Targets: []string{"pagerduty", "paused", "unknown"}, Match: map[string]string{"severity": "critical"}},
+		},
+	})
+	require.NoError(t, err)
+	c.SetTargetRouter(router)
+
+	routed := c.RouteTargets(alert)
+	assert.Equal(t, []string{"route.routes[0]"}, routed.Routes, "routed by the effective severity")
+	require.Len(t, routed.Targets, 1)
+	assert.Equal(t, "pagerduty", routed.Targets[0].Name)
+	assert.Equal(t, []string{"paused", "unknown"}, routed.Missing)
+
+	alert.EnrichmentMetadata = nil
+	routed = c.RouteTargets(alert)
+	require.Len(t, routed.Targets, 1)
+	assert.Equal(t, "slack", routed.Targets[0].Name)
+}
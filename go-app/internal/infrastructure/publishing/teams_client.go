@@ -0,0 +1,152 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+// teams_client.go - Microsoft Teams webhook client
+
+// ErrMissingTeamsWebhookURL is returned when a Teams target has no webhook
+// URL.
+var ErrMissingTeamsWebhookURL = errors.New("teams: webhook URL not found in target configuration")
+
+// TeamsWebhookClient posts messages to a Microsoft Teams webhook
+// (Teams Workflows or a legacy Office 365 connector).
+type TeamsWebhookClient interface {
+	// PostMessage posts a JSON-encoded Teams message.
+	// Failed requests are returned as *httperror.HTTPAPIError with
+	// ProviderTeams, so that the publishing queue can classify them.
+	PostMessage(ctx context.Context, payload []byte) error
+}
+
+// HTTPTeamsWebhookClient implements TeamsWebhookClient for one incoming
+// webhook or Workflows URL. A 429 it returns pauses all Teams jobs of the
+// publishing queue (see pauseProviderOnRateLimit).
+type HTTPTeamsWebhookClient struct {
+	httpClient *http.Client
+	webhookURL string
+	logger     *slog.Logger
+}
+
+// teamsConnectorErrorRE matches the errors legacy Office 365 connectors
+// report in the body of an HTTP 200 response, e.g.
+// "Microsoft Teams endpoint returned HTTP error 429 with ContextId ...".
+var teamsConnectorErrorRE = regexp.MustCompile(`returned HTTP error (\d{3})`)
+
+// NewHTTPTeamsWebhookClient creates a new Teams webhook client
+// webhookURL: Teams Workflows or incoming webhook URL
+// timeout: request timeout (<= 0 uses 10s)
+func NewHTTPTeamsWebhookClient(webhookURL string, timeout time.Duration, logger *slog.Logger) TeamsWebhookClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPTeamsWebhookClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: newOutboundTransport(&http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12, // TLS 1.2+ required
+				},
+				MaxIdleConns:        10,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     30 * time.Second,
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+			}),
+		},
+		webhookURL: webhookURL,
+		logger:     logger.With("component", "teams_client"),
+	}
+}
+
+// PostMessage posts payload to the webhook.
+func (c *HTTPTeamsWebhookClient) PostMessage(ctx context.Context, payload []byte) error {
+	c.logger.DebugContext(ctx, "Posting message to Teams",
+		slog.String("webhook_url", maskWebhookURL(c.webhookURL)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	return parseTeamsResponse(resp, body)
+}
+
+// parseTeamsResponse returns nil for a successful response and an
+// *httperror.HTTPAPIError otherwise.
+func parseTeamsResponse(resp *http.Response, body []byte) error {
+	statusCode := resp.StatusCode
+	if statusCode >= 200 && statusCode < 300 {
+		// Legacy connectors answer 200 and report failures in the body.
+		m := teamsConnectorErrorRE.FindSubmatch(body)
+		if m == nil {
+			return nil
+		}
+		statusCode, _ = strconv.Atoi(string(m[1]))
+	}
+
+	apiErr := &httperror.HTTPAPIError{
+		StatusCode: statusCode,
+		Message:    truncateString(string(body), 512),
+		Provider:   ProviderTeams,
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			apiErr.RetryAfter = seconds
+		}
+	}
+	return apiErr
+}
+
+// teamsClients caches Teams clients by webhook URL, which identifies the
+// channel and carries its credentials.
+type teamsClients struct {
+	*clientCache[TeamsWebhookClient]
+	logger *slog.Logger
+}
+
+func newTeamsClients(logger *slog.Logger) *teamsClients {
+	return &teamsClients{
+		clientCache: newClientCache[TeamsWebhookClient](func(target *core.PublishingTarget) string {
+			return target.URL
+		}),
+		logger: logger,
+	}
+}
+
+// get returns the client for target.
+func (c *teamsClients) get(target *core.PublishingTarget) (TeamsWebhookClient, error) {
+	if target.URL == "" {
+		return nil, ErrMissingTeamsWebhookURL
+	}
+	return c.getOrCreate(target, func() TeamsWebhookClient {
+		return NewHTTPTeamsWebhookClient(target.URL, 10*time.Second, c.logger)
+	}), nil
+}
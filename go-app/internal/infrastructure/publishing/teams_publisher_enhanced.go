@@ -0,0 +1,96 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+)
+
+// teams_publisher_enhanced.go - Microsoft Teams publisher (Adaptive Cards)
+
+// EnhancedTeamsPublisher implements AlertPublisher for Microsoft Teams.
+// Every notification (firing and resolved) is posted as a new Adaptive Card:
+// Teams webhooks cannot update or thread messages.
+type EnhancedTeamsPublisher struct {
+	*BaseEnhancedPublisher               // Embedded base publisher for common functionality
+	clients                *teamsClients // Teams clients by webhook URL
+}
+
+// NewEnhancedTeamsPublisher creates a new Teams publisher
+// metrics: Prometheus metrics recorder
+// formatter: Alert formatter used with core.FormatTeams
+func NewEnhancedTeamsPublisher(
+	metrics *v2.PublishingMetrics,
+	formatter AlertFormatter,
+	logger *slog.Logger,
+) AlertPublisher {
+	return newEnhancedTeamsPublisher(newTeamsClients(logger), metrics, formatter, logger)
+}
+
+func newEnhancedTeamsPublisher(clients *teamsClients, metrics *v2.PublishingMetrics, formatter AlertFormatter, logger *slog.Logger) *EnhancedTeamsPublisher {
+	return &EnhancedTeamsPublisher{
+		BaseEnhancedPublisher: NewBaseEnhancedPublisher(
+			metrics,
+			formatter,
+			logger.With("component", "teams_publisher"),
+		),
+		clients: clients,
+	}
+}
+
+// Publish posts the alert to Teams as an Adaptive Card.
+func (p *EnhancedTeamsPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	client, err := p.clients.get(target)
+	if err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	fingerprint := enrichedAlert.Alert.Fingerprint
+	ctx = withTargetSeverityStyles(ctx, target)
+
+	p.LogPublishStart(ctx, v2.ProviderTeams, enrichedAlert)
+
+	payload, err := p.GetFormatter().FormatAlert(ctx, enrichedAlert, core.FormatTeams)
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderTeams, "post_message", "format_error")
+		}
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordPayloadSize(v2.ProviderTeams, len(payloadBytes))
+	}
+
+	err = client.PostMessage(ctx, payloadBytes)
+	duration := time.Since(startTime)
+	if err != nil {
+		if p.GetMetrics() != nil {
+			p.GetMetrics().RecordAPIError(v2.ProviderTeams, "post_message", GetPublishingErrorType(err))
+			p.GetMetrics().RecordAPIDuration(v2.ProviderTeams, "post_message", "POST", duration)
+		}
+		p.LogPublishError(ctx, v2.ProviderTeams, fingerprint, err)
+		return fmt.Errorf("failed to post message to %s: %w", target.Name, err)
+	}
+
+	if p.GetMetrics() != nil {
+		p.GetMetrics().RecordMessage(v2.ProviderTeams, "success")
+		p.GetMetrics().RecordAPIDuration(v2.ProviderTeams, "post_message", "POST", duration)
+	}
+	p.LogPublishSuccess(ctx, v2.ProviderTeams, fingerprint, duration)
+	return nil
+}
+
+// Name returns publisher name
+func (p *EnhancedTeamsPublisher) Name() string {
+	return "Teams"
+}
@@ -0,0 +1,169 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+)
+
+func newTeamsTestAlert(status core.AlertStatus) *core.EnrichedAlert {
+	return &core.EnrichedAlert{
+		Alert: &core.Alert{
+			Fingerprint: "teams-fp",
+			AlertName:   "HighCPU",
+			Status:      status,
+			Labels:      map[string]string{"alertname": "HighCPU", "severity": "critical", "namespace": "prod"},
+			Annotations: map[string]string{
+				"summary":     "CPU usage above 90%",
+				"runbook_url": "https://runbooks.example.com/high-cpu",
+			},
+			StartsAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+// teamsCard returns the Adaptive Card of a formatted Teams message.
+func teamsCard(t *testing.T, payload map[string]any) map[string]any {
+	t.Helper()
+	attachments, ok := payload["attachments"].([]map[string]any)
+	require.True(t, ok, "attachments")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachments[0]["contentType"])
+	card, ok := attachments[0]["content"].(map[string]any)
+	require.True(t, ok, "content")
+	return card
+}
+
+func TestFormatTeams_FiringAlert(t *testing.T) {
+	formatter := NewAlertFormatter("https://amp.example.com")
+	payload, err := formatter.FormatAlert(context.Background(), newTeamsTestAlert(core.StatusFiring), core.FormatTeams)
+	require.NoError(t, err)
+
+	assert.Equal(t, "message", payload["type"])
+	card := teamsCard(t, payload)
+	assert.Equal(t, "AdaptiveCard", card["type"])
+
+	body := card["body"].([]map[string]any)
+	assert.Equal(t, "🔴 HighCPU - firing", body[0]["text"])
+	assert.Equal(t, "attention", body[0]["color"])
+
+	titles := map[string]string{}
+	for _, action := range card["actions"].([]map[string]any) {
+		titles[action["title"].(string)] = action["url"].(string)
+	}
+	assert.Equal(t, "https://runbooks.example.com/high-cpu", titles["Runbook"])
+	assert.Contains(t, titles["Silence"], "https://amp.example.com/#/silences?filter=")
+}
+
+func TestFormatTeams_ResolvedAlert(t *testing.T) {
+	alert := newTeamsTestAlert(core.StatusResolved)
+	endsAt := alert.Alert.StartsAt.Add(time.Hour)
+	alert.Alert.EndsAt = &endsAt
+
+	payload, err := NewAlertFormatter("https://amp.example.com").FormatAlert(context.Background(), alert, core.FormatTeams)
+	require.NoError(t, err)
+
+	card := teamsCard(t, payload)
+	body := card["body"].([]map[string]any)
+	assert.Equal(t, "good", body[0]["color"])
+	for _, action := range card["actions"].([]map[string]any) {
+		assert.NotEqual(t, "Silence", action["title"], "resolved alerts need no silence link")
+	}
+
+	var facts []map[string]any
+	for _, element := range body {
+		if element["type"] == "FactSet" {
+			facts = element["facts"].([]map[string]any)
+		}
+	}
+	assert.Contains(t, facts, map[string]any{"title": "Ended", "value": "2026-03-01T13:00:00Z"})
+}
+
+func TestEnhancedTeamsPublisher_Publish(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{Name: "ops-teams", Type: "teams", URL: server.URL, Format: core.FormatTeams}
+
+	publisher, err := factory.CreatePublisherForTarget(target)
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedTeamsPublisher{}, publisher)
+
+	require.NoError(t, publisher.Publish(context.Background(), newTeamsTestAlert(core.StatusFiring), target))
+	assert.Equal(t, "message", received["type"])
+	assert.Len(t, received["attachments"], 1)
+
+	// The publishing queue creates publishers by type
+	received = nil
+	publisher, err = factory.CreatePublisher("teams")
+	require.NoError(t, err)
+	require.IsType(t, &EnhancedTeamsPublisher{}, publisher)
+	require.NoError(t, publisher.Publish(context.Background(), newTeamsTestAlert(core.StatusResolved), target))
+	assert.Equal(t, "message", received["type"])
+
+	err = publisher.Publish(context.Background(), newTeamsTestAlert(core.StatusFiring), &core.PublishingTarget{Name: "no-url", Type: "teams"})
+	assert.ErrorIs(t, err, ErrMissingTeamsWebhookURL)
+}
+
+func TestHTTPTeamsWebhookClient_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		retryAfter string
+		wantStatus int
+		wantClass  httperror.Class
+	}{
+		{"accepted", http.StatusOK, "1", "", 0, 0},
+		{"rate limited", http.StatusTooManyRequests, "", "30", http.StatusTooManyRequests, httperror.ClassTransient},
+		{"legacy connector rate limited", http.StatusOK, "Microsoft Teams endpoint returned HTTP error 429 with ContextId tcid=0", "", http.StatusTooManyRequests, httperror.ClassTransient},
+		{"unavailable", http.StatusServiceUnavailable, "", "", http.StatusServiceUnavailable, httperror.ClassTransient},
+		{"bad card", http.StatusBadRequest, "Bad payload", "", http.StatusBadRequest, httperror.ClassPermanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := NewHTTPTeamsWebhookClient(server.URL, time.Second, slog.Default()).PostMessage(context.Background(), []byte(`{}`))
+			if tt.wantStatus == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			apiErr := AsPublishingError(err)
+			require.NotNil(t, apiErr, "error = %v", err)
+			assert.Equal(t, tt.wantStatus, apiErr.StatusCode)
+			assert.Equal(t, ProviderTeams, apiErr.Provider)
+			assert.Equal(t, tt.wantClass, httperror.Classify(err))
+			if tt.retryAfter != "" {
+				assert.Equal(t, 30, httperror.GetRetryAfter(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package publishing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// maxProviderResponseBody bounds the response body kept by SendTestAlert.
+const maxProviderResponseBody = 4 * 1024
+
+// ProviderResponse is an HTTP response of a provider to a publisher.
+type ProviderResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Truncated   bool   `json:"truncated,omitempty"` // Body is the first maxProviderResponseBody bytes
+}
+
+// TestAlertResult is the outcome of SendTestAlert.
+type TestAlertResult struct {
+	Success  bool
+	Error    string
+	Duration time.Duration
+	// Response is the last response of the provider, nil for targets not
+	// published over HTTP (email, exec, plugin) or that were not reached.
+	Response *ProviderResponse
+}
+
+// NewTestAlert returns the synthetic alert sent to test a target, named
+// alertName ("TestAlert" if empty).
+func NewTestAlert(alertName string) *core.EnrichedAlert {
+	if alertName == "" {
+		alertName = "TestAlert"
+	}
+
+	now := time.Now()
+	generatorURL := "http://test/alert"
+
+	return &core.EnrichedAlert{
+		Alert: &core.Alert{
+			Fingerprint: "test-" + alertName,
+			AlertName:   alertName,
+			Status:      core.StatusFiring,
+			Labels: map[string]string{
+				"alertname": alertName,
+				"severity":  "info",
+				"test":      "true",
+			},
+			Annotations: map[string]string{
+				"summary":     "Test alert for target validation",
+				"description": "This is a test alert sent via API",
+			},
+			StartsAt:     now,
+			GeneratorURL: &generatorURL,
+		},
+		Classification: &core.ClassificationResult{
+			Severity:   core.SeverityInfo,
+			Confidence: 1.0,
+			Reasoning:  "Test alert",
+			Recommendations: []string{
+				"This is a test - no action required",
+			},
+		},
+	}
+}
+
+// SendTestAlert publishes alert to target with a publisher of factory and
+// returns the outcome with the response of the provider. Unlike queued
+// alerts, the alert is sent once: no retry, circuit breaker, deduplication
+// or DLQ, so that the response tells what the provider thinks of the target.
+func SendTestAlert(ctx context.Context, factory *PublisherFactory, target *core.PublishingTarget, alert *core.EnrichedAlert) TestAlertResult {
+	recorder := &responseRecorder{}
+	start := time.Now()
+	err := sendTestAlert(withResponseRecorder(ctx, recorder), factory, target, alert)
+
+	result := TestAlertResult{
+		Success:  err == nil,
+		Duration: time.Since(start),
+		Response: recorder.last(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func sendTestAlert(ctx context.Context, factory *PublisherFactory, target *core.PublishingTarget, alert *core.EnrichedAlert) error {
+	ctx, alerts, err := redactForTarget(ctx, target, []*core.EnrichedAlert{alert})
+	if err != nil {
+		return fmt.Errorf("failed to redact alerts: %w", err)
+	}
+	if ctx, err = withTargetHTTPConfig(ctx, target); err != nil {
+		return fmt.Errorf("invalid outbound HTTP config: %w", err)
+	}
+	publisher, err := factory.CreatePublisher(target.Type)
+	if err != nil {
+		return err
+	}
+	return publisher.Publish(ctx, alerts[0], target)
+}
+
+type responseRecorderKey struct{}
+
+// responseRecorder keeps the last response of the outbound transport to the
+// requests of a context.
+type responseRecorder struct {
+	mu       sync.Mutex
+	response *ProviderResponse
+}
+
+func withResponseRecorder(ctx context.Context, recorder *responseRecorder) context.Context {
+	return context.WithValue(ctx, responseRecorderKey{}, recorder)
+}
+
+func responseRecorderFromContext(ctx context.Context) *responseRecorder {
+	recorder, _ := ctx.Value(responseRecorderKey{}).(*responseRecorder)
+	return recorder
+}
+
+// record copies the status and the beginning of the body of resp, which
+// stays readable in full by the publisher.
+func (r *responseRecorder) record(resp *http.Response) {
+	head, err := io.ReadAll(io.LimitReader(resp.Body, maxProviderResponseBody+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	if err != nil {
+		head = nil
+	}
+
+	response := &ProviderResponse{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if len(head) > maxProviderResponseBody {
+		head, response.Truncated = head[:maxProviderResponseBody], true
+	}
+	response.Body = string(head)
+
+	r.mu.Lock()
+	r.response = response
+	r.mu.Unlock()
+}
+
+func (r *responseRecorder) last() *ProviderResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.response
+}
@@ -0,0 +1,53 @@
+package publishing
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestSendTestAlert(t *testing.T) {
+	status, body := http.StatusOK, `{"status":"accepted"}`
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	factory := NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, "")
+	defer factory.Shutdown()
+	target := &core.PublishingTarget{Name: "hooks", Type: "webhook", URL: server.URL, Format: core.FormatWebhook, Enabled: true}
+
+	result := SendTestAlert(context.Background(), factory, target, NewTestAlert("Smoke"))
+	assert.True(t, result.Success)
+	assert.Empty(t, result.Error)
+	assert.Equal(t, &ProviderResponse{StatusCode: http.StatusOK, ContentType: "application/json", Body: body}, result.Response)
+	assert.Contains(t, received, "Smoke")
+
+	status, body = http.StatusBadRequest, strings.Repeat("x", maxProviderResponseBody+10)
+	result = SendTestAlert(context.Background(), factory, target, NewTestAlert(""))
+	assert.False(t, result.Success)
+	assert.NotEmpty(t, result.Error)
+	require.NotNil(t, result.Response)
+	assert.Equal(t, http.StatusBadRequest, result.Response.StatusCode)
+	assert.Len(t, result.Response.Body, maxProviderResponseBody)
+	assert.True(t, result.Response.Truncated)
+
+	server.Close()
+	result = SendTestAlert(context.Background(), factory, target, NewTestAlert(""))
+	assert.False(t, result.Success)
+	assert.Nil(t, result.Response, "no response from an unreachable target")
+}
@@ -226,13 +226,24 @@ func (m *tracingFormatterMiddleware) FormatAlert(ctx context.Context, enrichedAl
 			)
 		}
 
-		// Add label attributes (sample)
-		if enrichedAlert.Alert.Labels != nil {
-			if severity, ok := enrichedAlert.Alert.Labels["severity"]; ok {
-				span.SetAttributes(String("alert.label.severity", severity))
-			}
-			if namespace, ok := enrichedAlert.Alert.Labels["namespace"]; ok {
-				span.SetAttributes(String("alert.label.namespace", namespace))
+		severity, source := enrichedAlert.EffectiveSeverity()
+		span.SetAttributes(
+			String("alert.effective_severity", string(severity)),
+			String("alert.severity_source", string(source)),
+		)
+
+		// Add well-known label attributes
+		labels := enrichedAlert.KnownLabels()
+		for _, attr := range [...]struct{ key, value string }{
+			{"alert.label.severity", labels.Severity},
+			{"alert.label.namespace", labels.Namespace},
+			{"alert.label.team", labels.Team},
+			{"alert.label.service", labels.Service},
+			{"alert.label.environment", labels.Environment},
+			{"alert.label.region", labels.Region},
+		} {
+			if attr.value != "" {
+				span.SetAttributes(String(attr.key, attr.value))
 			}
 		}
 	}
@@ -272,6 +283,31 @@ func (m *tracingFormatterMiddleware) FormatAlert(ctx context.Context, enrichedAl
 	return result, err
 }
 
+func (m *tracingFormatterMiddleware) FormatBatch(ctx context.Context, alerts []*core.EnrichedAlert, format core.PublishingFormat) (map[string]any, error) {
+	ctx, span := m.tracer.Start(ctx, "FormatBatch",
+		WithSpanKind(SpanKindInternal),
+		WithAttributes(
+			String("format", string(format)),
+			Int("batch.size", len(alerts)),
+		),
+	)
+	defer span.End()
+
+	result, err := m.next.FormatBatch(ctx, alerts, format)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(StatusCodeError, err.Error())
+		span.SetAttributes(String("error.type", classifyError(err)))
+	} else {
+		span.SetStatus(StatusCodeOk, "")
+		if result != nil {
+			span.SetAttributes(Int("result.size_bytes", estimateJSONSize(result)))
+		}
+	}
+
+	return result, err
+}
+
 // TracingCacheMiddleware wraps CachingMiddleware with tracing
 //
 // Adds:
@@ -12,6 +12,8 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/ipiton/AMP/pkg/webhooksec"
 )
 
 // WebhookHTTPClient handles HTTP requests to webhook endpoints with retry logic
@@ -31,7 +33,7 @@ func NewWebhookHTTPClient(retryConfig WebhookRetryConfig, logger *slog.Logger) *
 	// Create HTTP client with optimized settings
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second, // Default timeout
-		Transport: &http.Transport{
+		Transport: newOutboundTransport(&http.Transport{
 			// TLS configuration
 			TLSClientConfig: &tls.Config{
 				MinVersion: tls.VersionTLS12, // Enforce TLS 1.2+
@@ -50,7 +52,7 @@ func NewWebhookHTTPClient(retryConfig WebhookRetryConfig, logger *slog.Logger) *
 			TLSHandshakeTimeout:   5 * time.Second,
 			ResponseHeaderTimeout: 10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
-		},
+		}),
 	}
 
 	return &WebhookHTTPClient{
@@ -63,6 +65,14 @@ func NewWebhookHTTPClient(retryConfig WebhookRetryConfig, logger *slog.Logger) *
 
 // Post sends a POST request to webhook endpoint with retry logic
 func (c *WebhookHTTPClient) Post(ctx context.Context, url string, payload map[string]interface{}, headers map[string]string, authConfig *AuthConfig) (*WebhookResponse, error) {
+	return c.PostSigned(ctx, url, payload, headers, authConfig, "")
+}
+
+// PostSigned is Post with the payload signed with signingSecret in the
+// X-AMP-Signature header (see webhooksec.VerifyAMP). Every attempt is signed
+// with its own timestamp, so retries stay within the receiver's allowed
+// skew. An empty signingSecret sends the request unsigned.
+func (c *WebhookHTTPClient) PostSigned(ctx context.Context, url string, payload map[string]interface{}, headers map[string]string, authConfig *AuthConfig, signingSecret string) (*WebhookResponse, error) {
 	startTime := time.Now()
 
 	// Marshal payload to JSON
@@ -99,7 +109,7 @@ func (c *WebhookHTTPClient) Post(ctx context.Context, url string, payload map[st
 	}
 
 	// Execute request with retry logic
-	resp, err := c.doRequestWithRetry(ctx, req, payloadBytes)
+	resp, err := c.doRequestWithRetry(ctx, req, payloadBytes, signingSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +126,7 @@ func (c *WebhookHTTPClient) Post(ctx context.Context, url string, payload map[st
 }
 
 // doRequestWithRetry executes HTTP request with exponential backoff retry
-func (c *WebhookHTTPClient) doRequestWithRetry(ctx context.Context, req *http.Request, bodyBytes []byte) (*WebhookResponse, error) {
+func (c *WebhookHTTPClient) doRequestWithRetry(ctx context.Context, req *http.Request, bodyBytes []byte, signingSecret string) (*WebhookResponse, error) {
 	var lastErr error
 	backoff := c.retryConfig.BaseBackoff
 
@@ -144,6 +154,11 @@ func (c *WebhookHTTPClient) doRequestWithRetry(ctx context.Context, req *http.Re
 			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 		}
 
+		// Sign the payload (fresh timestamp per attempt)
+		if signingSecret != "" {
+			req.Header.Set(webhooksec.AMPSignatureHeader, webhooksec.SignAMP(signingSecret, bodyBytes, time.Now()))
+		}
+
 		// Execute HTTP request
 		reqStartTime := time.Now()
 		resp, err := c.httpClient.Do(req)
@@ -197,23 +212,20 @@ func (c *WebhookHTTPClient) doRequestWithRetry(ctx context.Context, req *http.Re
 		}
 
 		// HTTP error (4xx, 5xx)
-		errorType := classifyErrorType(resp.StatusCode)
-		category := classifyHTTPError(resp.StatusCode)
-
-		lastErr = NewPublishingError(resp.StatusCode,
+		httpErr := NewPublishingError(resp.StatusCode,
 			fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
 			ProviderWebhook)
-		_ = errorType // Used for logging only
+		lastErr = httpErr
 
 		c.logger.WarnContext(ctx, "HTTP error",
 			slog.Int("attempt", attempt),
 			slog.Int("status_code", resp.StatusCode),
-			slog.String("error_type", errorType.String()),
-			slog.String("category", category.String()),
+			slog.String("error_type", httpErr.Type()),
+			slog.Bool("retryable", httpErr.IsRetryable()),
 			slog.Duration("duration", duration))
 
 		// Check if retryable
-		if category == ErrorCategoryRetryable && attempt < c.retryConfig.MaxRetries {
+		if httpErr.IsRetryable() && attempt < c.retryConfig.MaxRetries {
 			// Check for Retry-After header (429 Rate Limit)
 			if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
 				if seconds, err := strconv.Atoi(retryAfter); err == nil {
@@ -234,7 +246,7 @@ func (c *WebhookHTTPClient) doRequestWithRetry(ctx context.Context, req *http.Re
 		c.logger.ErrorContext(ctx, "Permanent error or max retries exceeded",
 			slog.Int("attempt", attempt),
 			slog.Int("status_code", resp.StatusCode),
-			slog.String("error_type", errorType.String()))
+			slog.String("error_type", httpErr.Type()))
 
 		return nil, lastErr
 	}
@@ -259,7 +271,5 @@ func (c *WebhookHTTPClient) SetTimeout(timeout time.Duration) {
 
 // Close closes idle connections
 func (c *WebhookHTTPClient) Close() {
-	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
-		transport.CloseIdleConnections()
-	}
+	c.httpClient.CloseIdleConnections()
 }
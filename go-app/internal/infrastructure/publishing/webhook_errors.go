@@ -109,59 +109,3 @@ func IsWebhookPermanentError(err error) bool {
 
 	return !httperror.IsRetryable(err)
 }
-
-// classifyHTTPError classifies HTTP status code to error category
-func classifyHTTPError(statusCode int) ErrorCategory {
-	switch {
-	case statusCode == 429:
-		return ErrorCategoryRetryable // Rate limit
-	case statusCode >= 500:
-		return ErrorCategoryRetryable // Server errors
-	case statusCode >= 400 && statusCode < 500:
-		return ErrorCategoryPermanent // Client errors
-	default:
-		return ErrorCategoryUnknown
-	}
-}
-
-// classifyErrorType classifies HTTP status code to ErrorType
-func classifyErrorType(statusCode int) ErrorType {
-	switch {
-	case statusCode == 429:
-		return ErrorTypeRateLimit
-	case statusCode >= 500:
-		return ErrorTypeServer
-	case statusCode == 401 || statusCode == 403:
-		return ErrorTypeAuth
-	case statusCode == 408 || statusCode == 504:
-		return ErrorTypeTimeout
-	default:
-		return ErrorTypeValidation
-	}
-}
-
-// ErrorCategory classifies errors for retry decision
-type ErrorCategory int
-
-const (
-	// ErrorCategoryRetryable means the error can be retried
-	ErrorCategoryRetryable ErrorCategory = iota
-
-	// ErrorCategoryPermanent means the error should not be retried
-	ErrorCategoryPermanent
-
-	// ErrorCategoryUnknown means the error category is unknown (treat as permanent)
-	ErrorCategoryUnknown
-)
-
-// String returns the string representation of ErrorCategory
-func (c ErrorCategory) String() string {
-	switch c {
-	case ErrorCategoryRetryable:
-		return "retryable"
-	case ErrorCategoryPermanent:
-		return "permanent"
-	default:
-		return "unknown"
-	}
-}
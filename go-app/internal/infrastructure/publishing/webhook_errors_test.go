@@ -251,120 +251,33 @@ func TestIsWebhookPermanentError_NonWebhookError(t *testing.T) {
 
 // ==================== HTTP Error Classification Tests ====================
 
-func TestClassifyHTTPError_2xx(t *testing.T) {
-	tests := []int{200, 201, 202, 204}
-	for _, code := range tests {
-		errType := classifyErrorType(code)
-		// 2xx codes don't create errors, so classifyErrorType returns 0 (ErrorTypeValidation by default)
-		// We just check that no panic occurs
-		_ = errType
-	}
-}
-
-func TestClassifyHTTPError_400(t *testing.T) {
-	errType := classifyErrorType(400)
-	if errType != ErrorTypeValidation {
-		t.Errorf("Status 400 should map to validation, got %v", errType)
-	}
-	// Check it's permanent via HTTPAPIError
-	err := NewWebhookErrorWithType(errType, "test", nil)
-	if !IsWebhookPermanentError(err) {
-		t.Error("400 should be permanent")
-	}
-}
-
-func TestClassifyHTTPError_401(t *testing.T) {
-	errType := classifyErrorType(401)
-	if errType != ErrorTypeAuth {
-		t.Errorf("Status 401 should map to auth, got %v", errType)
-	}
-	err := NewWebhookErrorWithType(errType, "test", nil)
-	if !IsWebhookPermanentError(err) {
-		t.Error("401 should be permanent")
-	}
-}
-
-func TestClassifyHTTPError_403(t *testing.T) {
-	errType := classifyErrorType(403)
-	if errType != ErrorTypeAuth {
-		t.Errorf("Status 403 should map to auth, got %v", errType)
-	}
-	err := NewWebhookErrorWithType(errType, "test", nil)
-	if !IsWebhookPermanentError(err) {
-		t.Error("403 should be permanent")
-	}
-}
-
-func TestClassifyHTTPError_404(t *testing.T) {
-	errType := classifyErrorType(404)
-	if errType != ErrorTypeValidation {
-		t.Errorf("Status 404 should map to validation, got %v", errType)
-	}
-	err := NewWebhookErrorWithType(errType, "test", nil)
-	if !IsWebhookPermanentError(err) {
-		t.Error("404 should be permanent")
-	}
-}
-
-func TestClassifyHTTPError_429(t *testing.T) {
-	errType := classifyErrorType(429)
-	if errType != ErrorTypeRateLimit {
-		t.Errorf("Status 429 should map to rate_limit, got %v", errType)
-	}
-	err := NewWebhookErrorWithType(errType, "test", nil)
-	if !IsWebhookRetryableError(err) {
-		t.Error("429 should be retryable")
+func TestWebhookHTTPError_Classification(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		errorType  string
+		retryable  bool
+	}{
+		{400, "bad_request", false},
+		{401, "auth_error", false},
+		{403, "auth_error", false},
+		{404, "not_found", false},
+		{429, "rate_limit", true},
+		{500, "server_error", true},
+		{502, "server_error", true},
+		{503, "server_error", true},
+		{504, "timeout", true},
 	}
-}
 
-func TestClassifyHTTPError_5xx(t *testing.T) {
-	tests := []int{500, 502, 503, 504}
-	for _, code := range tests {
-		errType := classifyErrorType(code)
-		if errType != ErrorTypeServer {
-			t.Errorf("Status %d should map to server, got %v", code, errType)
+	for _, tt := range tests {
+		err := NewPublishingError(tt.statusCode, "test", ProviderWebhook)
+		if got := err.Type(); got != tt.errorType {
+			t.Errorf("Status %d should map to %s, got %s", tt.statusCode, tt.errorType, got)
 		}
-		err := NewWebhookErrorWithType(errType, "test", nil)
-		if !IsWebhookRetryableError(err) {
-			t.Errorf("%d should be retryable", code)
+		if got := IsWebhookRetryableError(err); got != tt.retryable {
+			t.Errorf("Status %d retryable = %v, want %v", tt.statusCode, got, tt.retryable)
 		}
-	}
-}
-
-func TestClassifyErrorType_400(t *testing.T) {
-	result := classifyErrorType(400)
-	if result != ErrorTypeValidation {
-		t.Errorf("Status 400 should map to validation, got %v", result)
-	}
-}
-
-func TestClassifyErrorType_401(t *testing.T) {
-	result := classifyErrorType(401)
-	if result != ErrorTypeAuth {
-		t.Errorf("Status 401 should map to auth, got %v", result)
-	}
-}
-
-func TestClassifyErrorType_403(t *testing.T) {
-	result := classifyErrorType(403)
-	if result != ErrorTypeAuth {
-		t.Errorf("Status 403 should map to auth, got %v", result)
-	}
-}
-
-func TestClassifyErrorType_429(t *testing.T) {
-	result := classifyErrorType(429)
-	if result != ErrorTypeRateLimit {
-		t.Errorf("Status 429 should map to rate_limit, got %v", result)
-	}
-}
-
-func TestClassifyErrorType_5xx(t *testing.T) {
-	tests := []int{500, 502, 503, 504}
-	for _, code := range tests {
-		result := classifyErrorType(code)
-		if result != ErrorTypeServer {
-			t.Errorf("Status %d should map to server, got %v", code, result)
+		if got := IsWebhookPermanentError(err); got == tt.retryable {
+			t.Errorf("Status %d permanent = %v, want %v", tt.statusCode, got, !tt.retryable)
 		}
 	}
 }
@@ -79,6 +79,16 @@ var DefaultWebhookRetryConfig = WebhookRetryConfig{
 	Multiplier:  2.0,
 }
 
+// queueWebhookRetryConfig is the retry configuration of the webhook client
+// of the publishing queue: failed publishes are retried by the queue, with
+// its backoff, circuit breakers and fallbacks.
+var queueWebhookRetryConfig = WebhookRetryConfig{
+	MaxRetries:  0,
+	BaseBackoff: DefaultWebhookRetryConfig.BaseBackoff,
+	MaxBackoff:  DefaultWebhookRetryConfig.MaxBackoff,
+	Multiplier:  DefaultWebhookRetryConfig.Multiplier,
+}
+
 // CalculateBackoff calculates exponential backoff duration for a given attempt
 func (c *WebhookRetryConfig) CalculateBackoff(attempt int) time.Duration {
 	if attempt < 0 {
@@ -195,3 +205,18 @@ var DefaultValidationConfig = ValidationConfig{
 	MaxTimeout: 60 * time.Second,
 	MaxRetries: 5,
 }
+
+// targetValidationConfig validates the webhook and Alertmanager targets of
+// the publishing queue. Unlike DefaultValidationConfig, it allows plain HTTP
+// and in-cluster receivers: targets are configured by the operators, like
+// the discovered target secrets.
+var targetValidationConfig = ValidationConfig{
+	MaxPayloadSize:  DefaultValidationConfig.MaxPayloadSize,
+	MaxHeaders:      DefaultValidationConfig.MaxHeaders,
+	MaxHeaderSize:   DefaultValidationConfig.MaxHeaderSize,
+	AllowedSchemes:  []string{"http", "https"},
+	AllowPrivateIPs: true,
+	MinTimeout:      DefaultValidationConfig.MinTimeout,
+	MaxTimeout:      DefaultValidationConfig.MaxTimeout,
+	MaxRetries:      DefaultValidationConfig.MaxRetries,
+}
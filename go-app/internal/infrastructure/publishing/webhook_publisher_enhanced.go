@@ -39,12 +39,29 @@ func NewEnhancedWebhookPublisher(
 
 // Publish publishes enriched alert to webhook endpoint
 func (p *EnhancedWebhookPublisher) Publish(ctx context.Context, enrichedAlert *core.EnrichedAlert, target *core.PublishingTarget) error {
+	return p.publish(ctx, target, slog.String("fingerprint", enrichedAlert.Alert.Fingerprint),
+		func(ctx context.Context) (map[string]any, error) {
+			return p.formatter.FormatAlert(ctx, enrichedAlert, webhookFormat(target))
+		})
+}
+
+// PublishBatch publishes several alerts to the webhook endpoint in one request
+func (p *EnhancedWebhookPublisher) PublishBatch(ctx context.Context, alerts []*core.EnrichedAlert, target *core.PublishingTarget) error {
+	return p.publish(ctx, target, slog.Int("batch_size", len(alerts)),
+		func(ctx context.Context) (map[string]any, error) {
+			return p.formatter.FormatBatch(ctx, alerts, webhookFormat(target))
+		})
+}
+
+// publish validates target, formats the payload with format and posts it.
+// subject identifies the published alert(s) in logs.
+func (p *EnhancedWebhookPublisher) publish(ctx context.Context, target *core.PublishingTarget, subject slog.Attr, format func(context.Context) (map[string]any, error)) error {
 	startTime := time.Now()
 
 	p.GetLogger().InfoContext(ctx, "Publishing alert to webhook",
 		slog.String("target", target.Name),
 		slog.String("url", maskURL(target.URL)),
-		slog.String("fingerprint", enrichedAlert.Alert.Fingerprint))
+		subject)
 
 	// Validate target configuration
 	if err := p.validator.ValidateTarget(target); err != nil {
@@ -57,8 +74,8 @@ func (p *EnhancedWebhookPublisher) Publish(ctx context.Context, enrichedAlert *c
 		return fmt.Errorf("target validation failed: %w", err)
 	}
 
-	// Format alert for webhook (generic JSON format)
-	payload, err := p.formatter.FormatAlert(ctx, enrichedAlert, core.FormatWebhook)
+	// Format alert in the target format
+	payload, err := format(withTargetPayloadTemplate(withTargetSeverityStyles(ctx, target), target))
 	if err != nil {
 		p.GetLogger().ErrorContext(ctx, "Failed to format alert",
 			slog.String("target", target.Name),
@@ -101,13 +118,14 @@ func (p *EnhancedWebhookPublisher) Publish(ctx context.Context, enrichedAlert *c
 	}
 
 	// Parse authentication config from target headers
-	authConfig := p.extractAuthConfig(target)
+	headers := targetHTTPHeaders(target.Headers)
+	authConfig := p.extractAuthConfig(headers)
 
 	// Note: Timeout is currently set at client level during initialization
 	// Individual per-target timeout configuration can be added in future if needed
 
 	// Execute HTTP POST with retry logic
-	resp, err := p.client.Post(ctx, target.URL, payload, target.Headers, authConfig)
+	resp, err := p.client.PostSigned(ctx, target.URL, payload, headers, authConfig, target.Headers[targetSigningSecretHeader])
 	duration := time.Since(startTime)
 
 	if err != nil {
@@ -145,20 +163,33 @@ func (p *EnhancedWebhookPublisher) Publish(ctx context.Context, enrichedAlert *c
 		slog.Int("status_code", resp.StatusCode),
 		slog.Duration("duration", duration),
 		slog.Int("payload_size", len(payloadBytes)),
-		slog.String("fingerprint", enrichedAlert.Alert.Fingerprint))
+		subject)
 
 	return nil
 }
 
+// webhookFormat returns the payload format of target: its format if set
+// (e.g. alertmanager), else the generic webhook JSON.
+func webhookFormat(target *core.PublishingTarget) core.PublishingFormat {
+	if target.Format == "" {
+		return core.FormatWebhook
+	}
+	return target.Format
+}
+
 // Name returns publisher name
 func (p *EnhancedWebhookPublisher) Name() string {
 	return "EnhancedWebhook"
 }
 
+// targetSigningSecretHeader is the target header holding the secret the
+// payload is signed with (X-AMP-Signature). It is never sent.
+const targetSigningSecretHeader = "signing_secret"
+
 // extractAuthConfig extracts authentication configuration from target headers
-func (p *EnhancedWebhookPublisher) extractAuthConfig(target *core.PublishingTarget) *AuthConfig {
+func (p *EnhancedWebhookPublisher) extractAuthConfig(headers map[string]string) *AuthConfig {
 	// Check for Authorization header (Bearer or Basic)
-	if authHeader, exists := target.Headers["Authorization"]; exists {
+	if authHeader, exists := headers["Authorization"]; exists {
 		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
 			// Bearer Token authentication
 			return &AuthConfig{
@@ -171,7 +202,7 @@ func (p *EnhancedWebhookPublisher) extractAuthConfig(target *core.PublishingTarg
 	}
 
 	// Check for API Key header
-	if apiKey, exists := target.Headers["X-API-Key"]; exists {
+	if apiKey, exists := headers["X-API-Key"]; exists {
 		return &AuthConfig{
 			Type:         AuthTypeAPIKey,
 			APIKey:       apiKey,
@@ -180,7 +211,7 @@ func (p *EnhancedWebhookPublisher) extractAuthConfig(target *core.PublishingTarg
 	}
 
 	// Check for custom API key header patterns
-	for key, value := range target.Headers {
+	for key, value := range headers {
 		if key == "X-Api-Key" || key == "X-ApiKey" || key == "Api-Key" {
 			return &AuthConfig{
 				Type:         AuthTypeAPIKey,
@@ -191,10 +222,10 @@ func (p *EnhancedWebhookPublisher) extractAuthConfig(target *core.PublishingTarg
 	}
 
 	// If no specific auth detected, use custom headers
-	if len(target.Headers) > 0 {
+	if len(headers) > 0 {
 		// Filter out standard headers
 		customHeaders := make(map[string]string)
-		for key, value := range target.Headers {
+		for key, value := range headers {
 			if !isStandardHeader(key) {
 				customHeaders[key] = value
 			}
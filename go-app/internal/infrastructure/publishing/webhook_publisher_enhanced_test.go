@@ -2,13 +2,18 @@ package publishing
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ipiton/AMP/internal/core"
 	v2 "github.com/ipiton/AMP/pkg/metrics/v2"
+	"github.com/ipiton/AMP/pkg/webhooksec"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // ==================== Test Helpers ====================
@@ -238,6 +243,124 @@ func TestEnhancedWebhookPublisher_Publish_WithCustomHeaders(t *testing.T) {
 
 // ==================== Factory Methods Tests ====================
 
+func TestEnhancedWebhookPublisher_Publish_Signed(t *testing.T) {
+	// Receiver verifying the signature, as downstream consumers do
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		if err := webhooksec.VerifyAMP([]string{"s3cret"}, r.Header, body, time.Now(), webhooksec.DefaultMaxSkew); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	publisher := NewEnhancedWebhookPublisherWithDefaults(NewAlertFormatter(""), nil, slog.Default())
+	publisher.validator = NewWebhookValidatorWithConfig(ValidationConfig{
+		AllowedSchemes:  []string{"http", "https"},
+		AllowPrivateIPs: true,
+		MaxPayloadSize:  1024 * 1024,
+		MaxHeaders:      100,
+		MaxHeaderSize:   4096,
+	}, nil)
+
+	enrichedAlert := &core.EnrichedAlert{
+		Alert: &core.Alert{Fingerprint: "test123", AlertName: "TestAlert", Status: "firing"},
+	}
+	target := &core.PublishingTarget{
+		Name:    "signed-webhook",
+		Type:    "webhook",
+		URL:     server.URL,
+		Format:  core.FormatWebhook,
+		Headers: map[string]string{"signing_secret": "s3cret", "X-Team": "sre"},
+	}
+
+	if err := publisher.Publish(context.Background(), enrichedAlert, target); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received.Get("X-Team") != "sre" {
+		t.Errorf("Expected X-Team header to be forwarded")
+	}
+	if received.Get("signing_secret") != "" {
+		t.Errorf("Signing secret must not be sent")
+	}
+
+	// Without a secret, requests stay unsigned
+	target.Headers = map[string]string{"X-Team": "sre"}
+	if err := publisher.Publish(context.Background(), enrichedAlert, target); err == nil {
+		t.Errorf("Expected unsigned request to be rejected")
+	}
+	if received.Get(webhooksec.AMPSignatureHeader) != "" {
+		t.Errorf("Expected no signature without a secret")
+	}
+}
+
+func TestPublishingQueue_SignsWebhooks(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received http.Header
+		verified error
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = r.Header.Clone()
+		verified = webhooksec.VerifyAMP([]string{"s3cret"}, r.Header, body, time.Now(), webhooksec.DefaultMaxSkew)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	queue := NewPublishingQueue(
+		NewPublisherFactory(NewAlertFormatter(""), slog.Default(), nil, ""),
+		nil,
+		NewLRUJobTrackingStore(16),
+		PublishingQueueConfig{
+			WorkerCount:             1,
+			HighPriorityQueueSize:   4,
+			MediumPriorityQueueSize: 4,
+			LowPriorityQueueSize:    4,
+			RetryInterval:           time.Millisecond,
+			Metrics:                 v2.NewRegistry(v2.WithPrometheusRegisterer(prometheus.NewRegistry())).Publishing,
+		},
+		nil,
+		slog.Default(),
+	)
+	enrichedAlert := &core.EnrichedAlert{
+		Alert: &core.Alert{Fingerprint: "signed-fingerprint", AlertName: "TestAlert", Status: core.StatusFiring, StartsAt: time.Now()},
+	}
+	target := &core.PublishingTarget{
+		Name:    "signed-webhook",
+		Type:    "webhook",
+		URL:     server.URL,
+		Enabled: true,
+		Format:  core.FormatWebhook,
+		Headers: map[string]string{"signing_secret": "s3cret", "X-Team": "sre"},
+	}
+
+	if err := queue.Submit(enrichedAlert, target); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	queue.processJob(<-queue.mediumPriorityJobs)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received == nil {
+		t.Fatal("Expected the webhook to be posted")
+	}
+	if verified != nil {
+		t.Errorf("Expected a valid signature, got %v", verified)
+	}
+	if received.Get("signing_secret") != "" {
+		t.Errorf("Signing secret must not be sent")
+	}
+	if received.Get("X-Team") != "sre" {
+		t.Errorf("Expected X-Team header to be forwarded")
+	}
+}
+
 func TestNewEnhancedWebhookPublisherWithDefaults(t *testing.T) {
 	formatter := NewAlertFormatter("")
 	var metrics *v2.PublishingMetrics
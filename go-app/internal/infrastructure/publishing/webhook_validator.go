@@ -47,15 +47,16 @@ func (v *WebhookValidator) ValidateTarget(target *core.PublishingTarget) error {
 		return err
 	}
 
-	// Validate headers
-	if err := v.ValidateHeaders(target.Headers); err != nil {
+	// Validate the headers sent (the payload template may exceed the
+	// header size limit)
+	if err := v.ValidateHeaders(targetHTTPHeaders(target.Headers)); err != nil {
 		return err
 	}
 
 	// Note: Individual per-target timeout configuration can be added in future if needed
 	// Currently using default client timeout (10s)
 
-	v.logger.Info("Target validation passed",
+	v.logger.Debug("Target validation passed",
 		slog.String("target", target.Name),
 		slog.String("url", maskURL(target.URL)))
 
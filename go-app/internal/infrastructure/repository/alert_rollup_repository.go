@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// sqliteRollupTimeLayout is a fixed-width UTC layout so that bucket starts
+// stored as TEXT sort and compare chronologically.
+const sqliteRollupTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SQLiteAlertRollupRepository implements core.AlertRollupStore on the
+// alert_rollups table created by infrastructure.SQLiteDatabase.MigrateUp.
+type SQLiteAlertRollupRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAlertRollupRepository creates a SQLite alert rollup repository.
+func NewSQLiteAlertRollupRepository(db *sql.DB) *SQLiteAlertRollupRepository {
+	return &SQLiteAlertRollupRepository{db: db}
+}
+
+// ReplaceAlertRollups implements core.AlertRollupStore.
+func (r *SQLiteAlertRollupRepository) ReplaceAlertRollups(ctx context.Context, granularity core.AlertRollupGranularity, bucketStart time.Time, rollups []core.AlertRollup) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin alert rollup transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	bucket := bucketStart.UTC().Format(sqliteRollupTimeLayout)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM alert_rollups WHERE granularity = ? AND bucket_start = ?`,
+		string(granularity), bucket); err != nil {
+		return fmt.Errorf("failed to delete alert rollups: %w", err)
+	}
+	computedAt := time.Now().UTC().Format(sqliteRollupTimeLayout)
+	for _, rollup := range rollups {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO alert_rollups (granularity, bucket_start, dimension, value, count, computed_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			string(granularity), bucket, string(rollup.Dimension), rollup.Value, rollup.Count, computedAt,
+		); err != nil {
+			return fmt.Errorf("failed to insert alert rollup: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit alert rollups: %w", err)
+	}
+	return nil
+}
+
+// ListAlertRollups implements core.AlertRollupStore.
+func (r *SQLiteAlertRollupRepository) ListAlertRollups(ctx context.Context, query core.AlertRollupQuery) ([]core.AlertRollup, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT bucket_start, dimension, value, count
+		FROM alert_rollups
+		WHERE granularity = ? AND bucket_start >= ? AND bucket_start < ? AND (? = '' OR dimension = ?)
+		ORDER BY bucket_start, dimension, count DESC, value`,
+		string(query.Granularity),
+		query.From.UTC().Format(sqliteRollupTimeLayout), query.To.UTC().Format(sqliteRollupTimeLayout),
+		string(query.Dimension), string(query.Dimension),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rollups: %w", err)
+	}
+	defer rows.Close()
+
+	rollups := make([]core.AlertRollup, 0)
+	for rows.Next() {
+		var (
+			rollup      core.AlertRollup
+			bucketStart string
+			dimension   string
+		)
+		if err := rows.Scan(&bucketStart, &dimension, &rollup.Value, &rollup.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan alert rollup: %w", err)
+		}
+		if rollup.BucketStart, err = time.Parse(sqliteRollupTimeLayout, bucketStart); err != nil {
+			return nil, fmt.Errorf("invalid alert rollup bucket %q: %w", bucketStart, err)
+		}
+		rollup.Granularity = query.Granularity
+		rollup.Dimension = core.AlertRollupDimension(dimension)
+		rollups = append(rollups, rollup)
+	}
+	return rollups, rows.Err()
+}
+
+// DeleteAlertRollupsBefore implements core.AlertRollupStore.
+func (r *SQLiteAlertRollupRepository) DeleteAlertRollupsBefore(ctx context.Context, granularity core.AlertRollupGranularity, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM alert_rollups WHERE granularity = ? AND bucket_start < ?`,
+		string(granularity), cutoff.UTC().Format(sqliteRollupTimeLayout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete alert rollups: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// PostgresAlertRollupRepository implements core.AlertRollupStore on the
+// alert_rollups table (see migrations).
+type PostgresAlertRollupRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAlertRollupRepository creates a PostgreSQL alert rollup repository.
+func NewPostgresAlertRollupRepository(pool *pgxpool.Pool) *PostgresAlertRollupRepository {
+	return &PostgresAlertRollupRepository{pool: pool}
+}
+
+// ReplaceAlertRollups implements core.AlertRollupStore.
+func (r *PostgresAlertRollupRepository) ReplaceAlertRollups(ctx context.Context, granularity core.AlertRollupGranularity, bucketStart time.Time, rollups []core.AlertRollup) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin alert rollup transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM alert_rollups WHERE granularity = $1 AND bucket_start = $2`,
+		string(granularity), bucketStart.UTC()); err != nil {
+		return fmt.Errorf("failed to delete alert rollups: %w", err)
+	}
+	batch := &pgx.Batch{}
+	for _, rollup := range rollups {
+		batch.Queue(`
+			INSERT INTO alert_rollups (granularity, bucket_start, dimension, value, count, computed_at)
+			VALUES ($1, $2, $3, $4, $5, NOW())`,
+			string(granularity), bucketStart.UTC(), string(rollup.Dimension), rollup.Value, rollup.Count)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to insert alert rollups: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit alert rollups: %w", err)
+	}
+	return nil
+}
+
+// ListAlertRollups implements core.AlertRollupStore.
+func (r *PostgresAlertRollupRepository) ListAlertRollups(ctx context.Context, query core.AlertRollupQuery) ([]core.AlertRollup, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT bucket_start, dimension, value, count
+		FROM alert_rollups
+		WHERE granularity = $1 AND bucket_start >= $2 AND bucket_start < $3 AND ($4 = '' OR dimension = $4)
+		ORDER BY bucket_start, dimension, count DESC, value`,
+		string(query.Granularity), query.From.UTC(), query.To.UTC(), string(query.Dimension),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rollups: %w", err)
+	}
+	defer rows.Close()
+
+	rollups := make([]core.AlertRollup, 0)
+	for rows.Next() {
+		var (
+			rollup    core.AlertRollup
+			dimension string
+		)
+		if err := rows.Scan(&rollup.BucketStart, &dimension, &rollup.Value, &rollup.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan alert rollup: %w", err)
+		}
+		rollup.BucketStart = rollup.BucketStart.UTC()
+		rollup.Granularity = query.Granularity
+		rollup.Dimension = core.AlertRollupDimension(dimension)
+		rollups = append(rollups, rollup)
+	}
+	return rollups, rows.Err()
+}
+
+// DeleteAlertRollupsBefore implements core.AlertRollupStore.
+func (r *PostgresAlertRollupRepository) DeleteAlertRollupsBefore(ctx context.Context, granularity core.AlertRollupGranularity, cutoff time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM alert_rollups WHERE granularity = $1 AND bucket_start < $2`,
+		string(granularity), cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete alert rollups: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure"
+)
+
+func newTestSQLiteAlertRollupRepository(t *testing.T) *SQLiteAlertRollupRepository {
+	t.Helper()
+	db, err := infrastructure.NewSQLiteDatabase(&infrastructure.Config{
+		Driver:     "sqlite",
+		SQLiteFile: filepath.Join(t.TempDir(), "rollups.db"),
+		Logger:     slog.Default(),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	t.Cleanup(func() { _ = db.Disconnect(ctx) })
+	require.NoError(t, db.MigrateUp(ctx))
+	return NewSQLiteAlertRollupRepository(db.DB())
+}
+
+func TestSQLiteAlertRollupRepository(t *testing.T) {
+	repo := newTestSQLiteAlertRollupRepository(t)
+	ctx := context.Background()
+	hour := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	rollup := func(bucket time.Time, dimension core.AlertRollupDimension, value string, count int64) core.AlertRollup {
+		return core.AlertRollup{Granularity: core.AlertRollupHourly, BucketStart: bucket, Dimension: dimension, Value: value, Count: count}
+	}
+	require.NoError(t, repo.ReplaceAlertRollups(ctx, core.AlertRollupHourly, hour, []core.AlertRollup{
+		rollup(hour, core.AlertRollupTotal, "", 3),
+		rollup(hour, core.AlertRollupSeverity, "warning", 1),
+		rollup(hour, core.AlertRollupSeverity, "critical", 2),
+	}))
+	require.NoError(t, repo.ReplaceAlertRollups(ctx, core.AlertRollupHourly, hour.Add(time.Hour), []core.AlertRollup{
+		rollup(hour.Add(time.Hour), core.AlertRollupTotal, "", 0),
+	}))
+	require.NoError(t, repo.ReplaceAlertRollups(ctx, core.AlertRollupDaily, hour.Truncate(24*time.Hour), []core.AlertRollup{
+		{Dimension: core.AlertRollupTotal, Count: 3},
+	}))
+
+	rollups, err := repo.ListAlertRollups(ctx, core.AlertRollupQuery{
+		Granularity: core.AlertRollupHourly, From: hour, To: hour.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []core.AlertRollup{
+		rollup(hour, core.AlertRollupSeverity, "critical", 2),
+		rollup(hour, core.AlertRollupSeverity, "warning", 1),
+		rollup(hour, core.AlertRollupTotal, "", 3),
+		rollup(hour.Add(time.Hour), core.AlertRollupTotal, "", 0),
+	}, rollups)
+
+	// Replacing a bucket drops its previous rollups
+	require.NoError(t, repo.ReplaceAlertRollups(ctx, core.AlertRollupHourly, hour, []core.AlertRollup{
+		rollup(hour, core.AlertRollupTotal, "", 4),
+	}))
+	rollups, err = repo.ListAlertRollups(ctx, core.AlertRollupQuery{
+		Granularity: core.AlertRollupHourly, Dimension: core.AlertRollupTotal, From: hour, To: hour.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []core.AlertRollup{rollup(hour, core.AlertRollupTotal, "", 4)}, rollups)
+
+	deleted, err := repo.DeleteAlertRollupsBefore(ctx, core.AlertRollupHourly, hour.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	rollups, err = repo.ListAlertRollups(ctx, core.AlertRollupQuery{
+		Granularity: core.AlertRollupDaily, From: hour.Add(-24 * time.Hour), To: hour,
+	})
+	require.NoError(t, err)
+	assert.Len(t, rollups, 1, "other granularities are kept")
+}
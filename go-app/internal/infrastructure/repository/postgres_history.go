@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/compression"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -217,6 +218,9 @@ func (r *PostgresHistoryRepository) GetAlertsByFingerprint(ctx context.Context,
 		if err := json.Unmarshal(annotationsJSON, &alert.Annotations); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
 		}
+		if err := compression.DecompressValues(alert.Annotations); err != nil {
+			return nil, fmt.Errorf("failed to decompress annotations: %w", err)
+		}
 
 		// Handle nullable fields
 		if endsAt != nil {
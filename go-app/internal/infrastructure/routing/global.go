@@ -2,6 +2,8 @@ package routing
 
 import (
 	"time"
+
+	"github.com/ipiton/AMP/pkg/core/domain"
 )
 
 // GlobalConfig represents global Alertmanager configuration.
@@ -44,6 +46,15 @@ func (g *GlobalConfig) Defaults() {
 	}
 }
 
+// ResolveTimeoutOrDefault returns resolve_timeout, or the Alertmanager
+// default (domain.DefaultResolveTimeout) when unset. Safe on a nil config.
+func (g *GlobalConfig) ResolveTimeoutOrDefault() time.Duration {
+	if g == nil || g.ResolveTimeout == nil || *g.ResolveTimeout <= 0 {
+		return domain.DefaultResolveTimeout
+	}
+	return time.Duration(*g.ResolveTimeout)
+}
+
 // Clone creates a deep copy.
 func (g *GlobalConfig) Clone() *GlobalConfig {
 	clone := &GlobalConfig{
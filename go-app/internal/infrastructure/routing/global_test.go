@@ -17,6 +17,15 @@ func TestGlobalConfig_Defaults(t *testing.T) {
 	assert.Equal(t, Duration(5*time.Minute), *config.ResolveTimeout)
 }
 
+func TestGlobalConfig_ResolveTimeoutOrDefault(t *testing.T) {
+	var unset *GlobalConfig
+	assert.Equal(t, 5*time.Minute, unset.ResolveTimeoutOrDefault())
+
+	timeout := Duration(10 * time.Minute)
+	config := &GlobalConfig{ResolveTimeout: &timeout}
+	assert.Equal(t, 10*time.Minute, config.ResolveTimeoutOrDefault())
+}
+
 func TestGlobalConfig_Clone(t *testing.T) {
 	original := &GlobalConfig{
 		SMTPFrom:      "alerts@example.com",
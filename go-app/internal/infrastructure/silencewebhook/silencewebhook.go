@@ -0,0 +1,454 @@
+// Package silencewebhook posts silence lifecycle events (created, updated,
+// expired) to external webhooks, so systems like change-management tools can
+// track suppression windows.
+//
+// Created, updated and expired (through the API, or replaced by an update)
+// events come from the silence audit hook; silences reaching their end time
+// are found by a periodic check. Delivery is asynchronous: events are queued
+// and posted by a background worker, in order, with retries. When the queue
+// is full, events are dropped and counted.
+//
+// Every event has a stable ID (also sent as X-AMP-Event-ID), so receivers can
+// drop duplicates, e.g. the end of a silence reported by several replicas.
+// Endpoints with a signing secret get an X-AMP-Signature header (see
+// webhooksec.VerifyAMP).
+//
+// Usage:
+//
+//	n, err := silencewebhook.New(silencewebhook.Config{Endpoints: endpoints}, silenceStore)
+//	n.Start(ctx)
+//	defer n.Stop()
+//
+//	// in the silence store's audit hook
+//	n.HandleAudit(event)
+package silencewebhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/httperror"
+	"github.com/ipiton/AMP/pkg/retry"
+	"github.com/ipiton/AMP/pkg/webhooksec"
+)
+
+// Event types.
+const (
+	EventCreated = "silence.created"
+	EventUpdated = "silence.updated"
+	EventExpired = "silence.expired"
+)
+
+// EventTypes lists the event types.
+var EventTypes = []string{EventCreated, EventUpdated, EventExpired}
+
+// Headers of event requests.
+const (
+	HeaderEventID   = "X-AMP-Event-ID"
+	HeaderEventType = "X-AMP-Event"
+)
+
+// ReasonEnded is the reason of expired events of silences that reached their
+// end time.
+const ReasonEnded = "ended"
+
+// Delivery results counted in alert_history_silence_webhooks_requests_total.
+const (
+	ResultSent    = "sent"    // accepted by the endpoint
+	ResultFailed  = "failed"  // rejected by the endpoint or retries exhausted
+	ResultDropped = "dropped" // queue full
+)
+
+const (
+	defaultInterval  = time.Minute
+	defaultTimeout   = 10 * time.Second
+	defaultQueueSize = 1000
+	metricsNamespace = "alert_history"
+	metricsSubsystem = "silence_webhooks"
+)
+
+// Endpoint is a webhook receiving silence events.
+type Endpoint struct {
+	// URL the events are posted to (required).
+	URL string
+
+	// Events the endpoint receives (default: all of EventTypes).
+	Events []string
+
+	// Headers added to every request, e.g. Authorization.
+	Headers map[string]string
+
+	// SigningSecret signs requests with X-AMP-Signature (optional).
+	SigningSecret string
+}
+
+// Config configures the Notifier.
+type Config struct {
+	// Endpoints receiving the events (at least one).
+	Endpoints []Endpoint
+
+	// Interval between checks for silences reaching their end time
+	// (default: 1m).
+	Interval time.Duration
+
+	// Timeout per request (default: 10s).
+	Timeout time.Duration
+
+	// QueueSize bounds the events waiting to be sent (default: 1000).
+	QueueSize int
+
+	// MaxRetries for network errors, 429 and 5xx responses (0 = none).
+	// Retries back off exponentially from 1s (see retry.Webhook).
+	MaxRetries int
+
+	// Registerer for metrics (default: prometheus.DefaultRegisterer).
+	Registerer prometheus.Registerer
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// HTTPClient overrides the client built from Timeout (for tests).
+	HTTPClient *http.Client
+}
+
+// SilenceLister lists silences. Implemented by memory.SilenceStore.
+type SilenceLister interface {
+	List(now time.Time) []core.APISilence
+}
+
+// Event is the body of an event request.
+type Event struct {
+	// ID is stable for an event, so receivers can drop duplicates.
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	// Actor made the change: the API token name when authenticated,
+	// otherwise the silence's createdBy. Empty for silences that ended.
+	Actor string `json:"actor,omitempty"`
+	// Reason explains changes not made directly by the actor, e.g.
+	// "ended" or "replaced by <id>".
+	Reason string `json:"reason,omitempty"`
+	// Silence is the silence after the change.
+	Silence core.APISilence `json:"silence"`
+}
+
+// Notifier posts silence events to the configured endpoints.
+//
+// Thread-safe: HandleAudit may be called concurrently from any goroutine.
+type Notifier struct {
+	config   Config
+	silences SilenceLister
+	client   *http.Client
+	logger   *slog.Logger
+	queue    chan *Event
+
+	retry retry.Strategy
+
+	mu      sync.Mutex
+	running map[string]bool // IDs of the silences not expired yet
+
+	requests  *prometheus.CounterVec
+	queueSize prometheus.Gauge
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a Notifier and registers its metrics.
+func New(config Config, silences SilenceLister) (*Notifier, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("no endpoints configured")
+	}
+	for i, endpoint := range config.Endpoints {
+		if err := validateEndpoint(endpoint); err != nil {
+			return nil, fmt.Errorf("endpoint %d: %w", i, err)
+		}
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.Registerer == nil {
+		config.Registerer = prometheus.DefaultRegisterer
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+
+	n := &Notifier{
+		config:   config,
+		silences: silences,
+		client:   client,
+		logger:   config.Logger.With("component", "silence_webhooks"),
+		queue:    make(chan *Event, config.QueueSize),
+		running:  make(map[string]bool),
+
+		retry: retry.Webhook(config.MaxRetries),
+
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "requests_total",
+			Help:      "Silence events posted to webhooks by result (sent/failed/dropped)",
+		}, []string{"result"}),
+		queueSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "queue_size",
+			Help:      "Silence events waiting to be posted to webhooks",
+		}),
+	}
+	n.retry.Logger = n.logger
+	n.retry.OperationName = "silence_webhook"
+	config.Registerer.MustRegister(n.requests, n.queueSize)
+	return n, nil
+}
+
+// validateEndpoint checks the URL and events of an endpoint.
+func validateEndpoint(endpoint Endpoint) error {
+	u, err := url.Parse(endpoint.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute HTTP or HTTPS URL, got %q", endpoint.URL)
+	}
+	for _, event := range endpoint.Events {
+		if !slices.Contains(EventTypes, event) {
+			return fmt.Errorf("unknown event %q", event)
+		}
+	}
+	return nil
+}
+
+// Start starts the background sender and the check for silences reaching
+// their end time. Silences already expired are never reported.
+func (n *Notifier) Start(ctx context.Context) {
+	n.check(time.Now())
+
+	ctx, n.cancel = context.WithCancel(ctx)
+	n.wg.Add(2)
+	go n.run(ctx)
+	go n.checkLoop(ctx)
+
+	n.logger.Info("Silence webhooks started", "endpoints", len(n.config.Endpoints), "interval", n.config.Interval)
+}
+
+// Stop stops the background sender. Events still queued are dropped.
+func (n *Notifier) Stop() {
+	if n.cancel == nil {
+		return
+	}
+	n.cancel()
+	n.wg.Wait()
+}
+
+// HandleAudit queues the event of a silence change. Deletions are not
+// reported. Never blocks. Safe to call on a nil Notifier.
+func (n *Notifier) HandleAudit(audit core.SilenceAuditEvent) {
+	if n == nil || audit.After == nil {
+		return
+	}
+
+	event := &Event{
+		Timestamp: audit.Timestamp,
+		Actor:     audit.Actor,
+		Reason:    audit.Reason,
+		Silence:   *audit.After,
+	}
+	n.mu.Lock()
+	switch audit.Action {
+	case core.SilenceAuditCreate:
+		event.Type = EventCreated
+		event.ID = audit.SilenceID + "/created"
+		n.running[audit.SilenceID] = true
+	case core.SilenceAuditUpdate:
+		event.Type = EventUpdated
+		event.ID = audit.SilenceID + "/updated/" + strconv.FormatInt(audit.Timestamp.UnixNano(), 10)
+		n.running[audit.SilenceID] = true
+	case core.SilenceAuditExpire:
+		event.Type = EventExpired
+		event.ID = audit.SilenceID + "/expired"
+		delete(n.running, audit.SilenceID)
+	default:
+		n.mu.Unlock()
+		return
+	}
+	n.mu.Unlock()
+
+	n.enqueue(event)
+}
+
+// check queues expired events for the silences that reached their end time
+// since the previous check.
+func (n *Notifier) check(now time.Time) int {
+	silences := n.silences.List(now)
+
+	var ended []*Event
+	n.mu.Lock()
+	seen := make(map[string]bool, len(silences))
+	for _, silence := range silences {
+		seen[silence.ID] = true
+		if silence.Status.State != "expired" {
+			n.running[silence.ID] = true
+			continue
+		}
+		if !n.running[silence.ID] {
+			continue
+		}
+		delete(n.running, silence.ID)
+		timestamp := now.UTC()
+		if endsAt, err := time.Parse(time.RFC3339, silence.EndsAt); err == nil {
+			timestamp = endsAt
+		}
+		ended = append(ended, &Event{
+			ID:        silence.ID + "/expired",
+			Type:      EventExpired,
+			Timestamp: timestamp,
+			Reason:    ReasonEnded,
+			Silence:   silence,
+		})
+	}
+	for id := range n.running {
+		if !seen[id] {
+			delete(n.running, id)
+		}
+	}
+	n.mu.Unlock()
+
+	for _, event := range ended {
+		n.enqueue(event)
+	}
+	return len(ended)
+}
+
+func (n *Notifier) checkLoop(ctx context.Context) {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(n.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			n.check(now)
+		}
+	}
+}
+
+func (n *Notifier) enqueue(event *Event) {
+	select {
+	case n.queue <- event:
+		n.queueSize.Set(float64(len(n.queue)))
+	default:
+		n.requests.WithLabelValues(ResultDropped).Inc()
+		n.logger.Warn("Silence webhook queue full, event dropped",
+			"event", event.Type,
+			"silence_id", event.Silence.ID,
+			"queue_size", n.config.QueueSize)
+	}
+}
+
+func (n *Notifier) run(ctx context.Context) {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-n.queue:
+			n.queueSize.Set(float64(len(n.queue)))
+			n.deliver(ctx, event)
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// deliver posts event to every endpoint subscribed to its type.
+func (n *Notifier) deliver(ctx context.Context, event *Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.logger.Error("Failed to encode silence event", "event", event.Type, "error", err)
+		return
+	}
+	for _, endpoint := range n.config.Endpoints {
+		if len(endpoint.Events) > 0 && !slices.Contains(endpoint.Events, event.Type) {
+			continue
+		}
+		if err := n.sendWithRetry(ctx, endpoint, event, body); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			n.requests.WithLabelValues(ResultFailed).Inc()
+			n.logger.Error("Failed to post silence event",
+				"event", event.Type,
+				"silence_id", event.Silence.ID,
+				"url", endpoint.URL,
+				"error", err)
+			continue
+		}
+		n.requests.WithLabelValues(ResultSent).Inc()
+	}
+}
+
+func (n *Notifier) sendWithRetry(ctx context.Context, endpoint Endpoint, event *Event, body []byte) error {
+	return retry.DoSimple(ctx, n.retry, func() error {
+		return n.send(ctx, endpoint, event, body)
+	})
+}
+
+// send posts event once. Responses other than 2xx are returned as
+// *httperror.HTTPAPIError, so the retry classifier retries 429 and 5xx only.
+func (n *Notifier) send(ctx context.Context, endpoint Endpoint, event *Event, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range endpoint.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, event.ID)
+	req.Header.Set(HeaderEventType, event.Type)
+	if endpoint.SigningSecret != "" {
+		// Signed per attempt, so retries carry a fresh timestamp
+		req.Header.Set(webhooksec.AMPSignatureHeader, webhooksec.SignAMP(endpoint.SigningSecret, body, time.Now()))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return httperror.NewHTTPError(resp.StatusCode, fmt.Sprintf("endpoint returned HTTP %d", resp.StatusCode), "silence_webhook")
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package silencewebhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/retry"
+	"github.com/ipiton/AMP/pkg/webhooksec"
+)
+
+// fakeEndpoint records posted events and answers with the queued statuses
+// (200 once they are used up).
+type fakeEndpoint struct {
+	mu       sync.Mutex
+	statuses []int
+	events   []Event
+	headers  []http.Header
+	bodies   [][]byte
+	received chan struct{}
+}
+
+func newFakeEndpoint(statuses ...int) *fakeEndpoint {
+	return &fakeEndpoint{statuses: statuses, received: make(chan struct{}, 16)}
+}
+
+func (e *fakeEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var event Event
+	_ = json.Unmarshal(body, &event)
+
+	e.mu.Lock()
+	status := http.StatusOK
+	if len(e.statuses) > 0 {
+		status, e.statuses = e.statuses[0], e.statuses[1:]
+	}
+	if status == http.StatusOK {
+		e.events = append(e.events, event)
+		e.headers = append(e.headers, r.Header.Clone())
+		e.bodies = append(e.bodies, body)
+	}
+	e.mu.Unlock()
+
+	w.WriteHeader(status)
+	e.received <- struct{}{}
+}
+
+func (e *fakeEndpoint) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-e.received:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("endpoint received %d of %d requests", i, n)
+		}
+	}
+}
+
+func (e *fakeEndpoint) snapshot() ([]Event, []http.Header, [][]byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Event(nil), e.events...), append([]http.Header(nil), e.headers...), append([][]byte(nil), e.bodies...)
+}
+
+// fakeSilences is a SilenceLister returning fixed silences.
+type fakeSilences struct {
+	mu       sync.Mutex
+	silences []core.APISilence
+}
+
+func (f *fakeSilences) List(time.Time) []core.APISilence {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]core.APISilence(nil), f.silences...)
+}
+
+func (f *fakeSilences) set(silences ...core.APISilence) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.silences = silences
+}
+
+func silence(id, state string) core.APISilence {
+	return core.APISilence{
+		ID:        id,
+		Matchers:  []core.APISilenceMatcher{{Name: "alertname", Value: "HighCPU", IsEqual: true}},
+		StartsAt:  "2026-01-01T10:00:00Z",
+		EndsAt:    "2026-01-01T12:00:00Z",
+		CreatedBy: "alice",
+		Comment:   "CHG-1234 database upgrade",
+		Status:    core.APISilenceStatus{State: state},
+	}
+}
+
+func newTestNotifier(t *testing.T, silences SilenceLister, endpoints ...Endpoint) *Notifier {
+	t.Helper()
+	n, err := New(Config{Endpoints: endpoints, Interval: time.Hour, MaxRetries: 2, Registerer: prometheus.NewRegistry()}, silences)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	n.retry = n.retry.WithAfter(func(time.Duration) <-chan time.Time { return time.After(time.Millisecond) })
+	n.Start(context.Background())
+	t.Cleanup(n.Stop)
+	return n
+}
+
+func TestNotifier_AuditEvents(t *testing.T) {
+	endpoint := newFakeEndpoint()
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	n := newTestNotifier(t, &fakeSilences{}, Endpoint{
+		URL:           srv.URL,
+		Headers:       map[string]string{"Authorization": "Bearer change-mgmt"},
+		SigningSecret: "s3cret",
+	})
+
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	active := silence("s1", "active")
+	expired := silence("s1", "expired")
+	n.HandleAudit(core.NewSilenceAuditEvent("s1", core.SilenceAuditCreate, "alice", nil, &active, now))
+	n.HandleAudit(core.NewSilenceAuditEvent("s1", core.SilenceAuditExpire, "bob", &active, &expired, now.Add(time.Hour)))
+	n.HandleAudit(core.NewSilenceAuditEvent("s1", core.SilenceAuditDelete, "", &expired, nil, now.Add(2*time.Hour)))
+	endpoint.wait(t, 2)
+
+	events, headers, bodies := endpoint.snapshot()
+	if len(events) != 2 {
+		t.Fatalf("received %d events, want 2", len(events))
+	}
+	if events[0].Type != EventCreated || events[0].ID != "s1/created" || events[0].Actor != "alice" ||
+		events[0].Silence.CreatedBy != "alice" || events[0].Silence.Comment != "CHG-1234 database upgrade" {
+		t.Errorf("created event = %+v", events[0])
+	}
+	if events[1].Type != EventExpired || events[1].ID != "s1/expired" || events[1].Actor != "bob" {
+		t.Errorf("expired event = %+v", events[1])
+	}
+
+	if got := headers[0].Get(HeaderEventType); got != EventCreated {
+		t.Errorf("%s = %q", HeaderEventType, got)
+	}
+	if got := headers[0].Get(HeaderEventID); got != "s1/created" {
+		t.Errorf("%s = %q", HeaderEventID, got)
+	}
+	if got := headers[0].Get("Authorization"); got != "Bearer change-mgmt" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if err := webhooksec.VerifyAMP([]string{"s3cret"}, headers[0], bodies[0], time.Now(), time.Minute); err != nil {
+		t.Errorf("signature: %v", err)
+	}
+}
+
+func TestNotifier_EndedSilences(t *testing.T) {
+	endpoint := newFakeEndpoint()
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	silences := &fakeSilences{}
+	// Silences already expired at start are not reported
+	silences.set(silence("old", "expired"), silence("s1", "active"), silence("s2", "pending"))
+	n := newTestNotifier(t, silences, Endpoint{URL: srv.URL})
+
+	silences.set(silence("old", "expired"), silence("s1", "expired"), silence("s2", "active"))
+	if got := n.check(time.Now()); got != 1 {
+		t.Fatalf("check reported %d silences, want 1", got)
+	}
+	// Reported once
+	if got := n.check(time.Now()); got != 0 {
+		t.Fatalf("second check reported %d silences, want 0", got)
+	}
+	endpoint.wait(t, 1)
+
+	events, _, _ := endpoint.snapshot()
+	if events[0].Type != EventExpired || events[0].Silence.ID != "s1" || events[0].Reason != ReasonEnded {
+		t.Errorf("event = %+v", events[0])
+	}
+	if want := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC); !events[0].Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want the end time %v", events[0].Timestamp, want)
+	}
+}
+
+func TestNotifier_ExpiredThroughAPINotReportedAsEnded(t *testing.T) {
+	endpoint := newFakeEndpoint()
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	silences := &fakeSilences{}
+	silences.set(silence("s1", "active"))
+	n := newTestNotifier(t, silences, Endpoint{URL: srv.URL, Events: []string{EventExpired}})
+
+	active, expired := silence("s1", "active"), silence("s1", "expired")
+	n.HandleAudit(core.NewSilenceAuditEvent("s1", core.SilenceAuditExpire, "bob", &active, &expired, time.Now()))
+	silences.set(expired)
+	if got := n.check(time.Now()); got != 0 {
+		t.Errorf("check reported %d silences, want 0", got)
+	}
+	endpoint.wait(t, 1)
+
+	events, _, _ := endpoint.snapshot()
+	if len(events) != 1 || events[0].Actor != "bob" {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestNotifier_EventFilterAndRetries(t *testing.T) {
+	createdOnly := newFakeEndpoint(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	srvCreated := httptest.NewServer(createdOnly)
+	defer srvCreated.Close()
+	rejecting := newFakeEndpoint(http.StatusBadRequest)
+	srvRejecting := httptest.NewServer(rejecting)
+	defer srvRejecting.Close()
+
+	n := newTestNotifier(t, &fakeSilences{},
+		Endpoint{URL: srvCreated.URL, Events: []string{EventCreated}},
+		Endpoint{URL: srvRejecting.URL},
+	)
+
+	active := silence("s1", "active")
+	updated := active
+	updated.Comment = "extended"
+	now := time.Now()
+	n.HandleAudit(core.NewSilenceAuditEvent("s1", core.SilenceAuditCreate, "alice", nil, &active, now))
+	n.HandleAudit(core.NewSilenceAuditEvent("s1", core.SilenceAuditUpdate, "alice", &active, &updated, now))
+	createdOnly.wait(t, 3) // two retries
+	rejecting.wait(t, 2)   // created rejected without retry, updated accepted
+
+	events, _, _ := createdOnly.snapshot()
+	if len(events) != 1 || events[0].Type != EventCreated {
+		t.Errorf("created-only endpoint received %+v", events)
+	}
+	events, _, _ = rejecting.snapshot()
+	if len(events) != 1 || events[0].Type != EventUpdated {
+		t.Errorf("second endpoint received %+v", events)
+	}
+	if got := testutil.ToFloat64(n.requests.WithLabelValues(ResultFailed)); got != 1 {
+		t.Errorf("failed = %v, want 1", got)
+	}
+}
+
+func TestNotifier_RetriesBackOffWithJitter(t *testing.T) {
+	endpoint := newFakeEndpoint(http.StatusServiceUnavailable, http.StatusBadGateway)
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	n, err := New(Config{Endpoints: []Endpoint{{URL: srv.URL}}, Interval: time.Hour, MaxRetries: 2, Registerer: prometheus.NewRegistry()}, &fakeSilences{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var mu sync.Mutex
+	var delays []time.Duration
+	n.retry = n.retry.WithJitterSource(retry.NewSeededJitterSource(1)).WithAfter(func(d time.Duration) <-chan time.Time {
+		mu.Lock()
+		delays = append(delays, d)
+		mu.Unlock()
+		return time.After(time.Millisecond)
+	})
+	n.Start(context.Background())
+	t.Cleanup(n.Stop)
+
+	active := silence("s1", "active")
+	n.HandleAudit(core.NewSilenceAuditEvent("s1", core.SilenceAuditCreate, "alice", nil, &active, time.Now()))
+	endpoint.wait(t, 3)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delays) != 2 {
+		t.Fatalf("backed off %d times, want 2", len(delays))
+	}
+	for i, d := range delays {
+		base := time.Second << i
+		if d < base*85/100 || d > base*115/100 {
+			t.Errorf("delay %d = %v, want %v ±15%%", i, d, base)
+		}
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []Endpoint
+	}{
+		{"no endpoints", nil},
+		{"relative url", []Endpoint{{URL: "/hooks"}}},
+		{"unknown event", []Endpoint{{URL: "https://example.com", Events: []string{"silence.deleted"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(Config{Endpoints: tt.endpoints, Registerer: prometheus.NewRegistry()}, &fakeSilences{}); err == nil {
+				t.Error("New accepted an invalid config")
+			}
+		})
+	}
+}
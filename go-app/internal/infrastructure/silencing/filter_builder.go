@@ -28,7 +28,7 @@ import (
 func (r *PostgresSilenceRepository) buildListQuery(filter SilenceFilter) (string, []interface{}) {
 	// Base SELECT clause
 	query := `
-		SELECT id, created_by, comment, starts_at, ends_at, matchers, status, created_at, updated_at
+		SELECT id, created_by, comment, starts_at, ends_at, matchers, status, created_at, updated_at, require_label_presence
 		FROM silences
 		WHERE 1=1
 	`
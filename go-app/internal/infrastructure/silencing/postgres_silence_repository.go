@@ -125,8 +125,8 @@ func (r *PostgresSilenceRepository) CreateSilence(ctx context.Context, silence *
 
 	// Step 6: Insert silence
 	query := `
-		INSERT INTO silences (id, created_by, comment, starts_at, ends_at, matchers, status, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		INSERT INTO silences (id, created_by, comment, starts_at, ends_at, matchers, status, require_label_presence, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
 		RETURNING created_at
 	`
 
@@ -139,6 +139,7 @@ func (r *PostgresSilenceRepository) CreateSilence(ctx context.Context, silence *
 		silence.EndsAt,
 		matchersJSON,
 		silence.Status,
+		silence.RequireLabelPresence,
 	).Scan(&createdAt)
 
 	if err != nil {
@@ -208,7 +209,7 @@ func (r *PostgresSilenceRepository) GetSilenceByID(ctx context.Context, id strin
 
 	// Step 2: Execute SELECT query
 	query := `
-		SELECT id, created_by, comment, starts_at, ends_at, matchers, status, created_at, updated_at
+		SELECT id, created_by, comment, starts_at, ends_at, matchers, status, created_at, updated_at, require_label_presence
 		FROM silences
 		WHERE id = $1
 	`
@@ -227,6 +228,7 @@ func (r *PostgresSilenceRepository) GetSilenceByID(ctx context.Context, id strin
 		&silence.Status,
 		&silence.CreatedAt,
 		&updatedAt,
+		&silence.RequireLabelPresence,
 	)
 
 	if err != nil {
@@ -324,9 +326,10 @@ func (r *PostgresSilenceRepository) UpdateSilence(ctx context.Context, silence *
 			ends_at = $4,
 			matchers = $5,
 			status = $6,
+			require_label_presence = $7,
 			updated_at = NOW()
-		WHERE id = $7
-		  AND (updated_at IS NULL OR updated_at = $8)
+		WHERE id = $8
+		  AND (updated_at IS NULL OR updated_at = $9)
 		RETURNING updated_at
 	`
 
@@ -338,6 +341,7 @@ func (r *PostgresSilenceRepository) UpdateSilence(ctx context.Context, silence *
 		silence.EndsAt,
 		matchersJSON,
 		silence.Status,
+		silence.RequireLabelPresence,
 		silence.ID,
 		silence.UpdatedAt,
 	).Scan(&updatedAt)
@@ -481,6 +485,7 @@ func (r *PostgresSilenceRepository) ListSilences(ctx context.Context, filter Sil
 			&silence.ID, &silence.CreatedBy, &silence.Comment,
 			&silence.StartsAt, &silence.EndsAt, &matchersJSON,
 			&silence.Status, &silence.CreatedAt, &updatedAt,
+			&silence.RequireLabelPresence,
 		)
 		if err != nil {
 			if r.metrics != nil {
@@ -654,7 +659,7 @@ func (r *PostgresSilenceRepository) GetExpiringSoon(ctx context.Context, window
 
 	query := `
 		SELECT id, created_by, comment, starts_at, ends_at,
-		       matchers, status, created_at, updated_at
+		       matchers, status, created_at, updated_at, require_label_presence
 		FROM silences
 		WHERE status IN ($1, $2)
 		  AND ends_at > $3
@@ -692,6 +697,7 @@ func (r *PostgresSilenceRepository) GetExpiringSoon(ctx context.Context, window
 			&silence.ID, &silence.CreatedBy, &silence.Comment,
 			&silence.StartsAt, &silence.EndsAt, &matchersJSON,
 			&silence.Status, &silence.CreatedAt, &updatedAt,
+			&silence.RequireLabelPresence,
 		)
 		if err != nil {
 			if r.metrics != nil {
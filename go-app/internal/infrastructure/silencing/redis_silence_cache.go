@@ -0,0 +1,209 @@
+package silencing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/cache"
+)
+
+// RedisSilenceCache shares the silences of AMP replicas through Redis, so a
+// silence created on one replica applies on all of them without database
+// reads.
+//
+// Redis schema:
+//
+//	Silences:
+//	  Key: "{prefix}:state"
+//	  Type: Hash (silence ID -> APISilence JSON)
+//
+//	Change notifications:
+//	  Channel: "{prefix}:changes"
+//	  Payload: ID of the replica that made the change
+type RedisSilenceCache struct {
+	client    *redis.Client
+	stateKey  string
+	channel   string
+	replicaID string
+	logger    *slog.Logger
+
+	mu sync.Mutex
+	// written is the JSON of each silence as last written or adopted by
+	// this replica.
+	written map[string]string
+}
+
+// NewRedisSilenceCache creates a silence cache on the Redis client of
+// redisCache, which must be a *cache.RedisCache.
+func NewRedisSilenceCache(redisCache cache.Cache, keyPrefix, replicaID string, logger *slog.Logger) (*RedisSilenceCache, error) {
+	concreteCache, ok := redisCache.(*cache.RedisCache)
+	if !ok {
+		return nil, fmt.Errorf("silence cache requires *cache.RedisCache, got %T", redisCache)
+	}
+	if keyPrefix == "" || replicaID == "" {
+		return nil, fmt.Errorf("key prefix and replica ID are required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RedisSilenceCache{
+		client:    concreteCache.GetClient(),
+		stateKey:  keyPrefix + ":state",
+		channel:   keyPrefix + ":changes",
+		replicaID: replicaID,
+		logger:    logger,
+		written:   make(map[string]string),
+	}, nil
+}
+
+// Seed replaces the cached silences with silences and notifies the other
+// replicas.
+func (c *RedisSilenceCache) Seed(ctx context.Context, silences []core.APISilence) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	written := make(map[string]string, len(silences))
+	for _, silence := range silences {
+		written[silence.ID] = cachedSilenceJSON(silence)
+	}
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, c.stateKey)
+		if len(written) > 0 {
+			pipe.HSet(ctx, c.stateKey, written)
+		}
+		pipe.Publish(ctx, c.channel, c.replicaID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to seed silence cache: %w", err)
+	}
+	c.written = written
+	return nil
+}
+
+// Sync writes the silences that changed since the last Sync, Seed or Adopt
+// and notifies the other replicas. Reports whether anything was written.
+func (c *RedisSilenceCache) Sync(ctx context.Context, silences []core.APISilence) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := make(map[string]string, len(silences))
+	changed := make(map[string]string)
+	for _, silence := range silences {
+		data := cachedSilenceJSON(silence)
+		current[silence.ID] = data
+		if c.written[silence.ID] != data {
+			changed[silence.ID] = data
+		}
+	}
+	var removed []string
+	for id := range c.written {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return false, nil
+	}
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(changed) > 0 {
+			pipe.HSet(ctx, c.stateKey, changed)
+		}
+		if len(removed) > 0 {
+			pipe.HDel(ctx, c.stateKey, removed...)
+		}
+		pipe.Publish(ctx, c.channel, c.replicaID)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to sync silence cache: %w", err)
+	}
+	c.written = current
+	return true, nil
+}
+
+// Load returns the cached silences. Entries that cannot be decoded are
+// skipped.
+func (c *RedisSilenceCache) Load(ctx context.Context) ([]core.APISilence, error) {
+	entries, err := c.client.HGetAll(ctx, c.stateKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load silence cache: %w", err)
+	}
+
+	silences := make([]core.APISilence, 0, len(entries))
+	for id, data := range entries {
+		var silence core.APISilence
+		if err := json.Unmarshal([]byte(data), &silence); err != nil {
+			c.logger.Warn("Skipping undecodable cached silence", "silence_id", id, "error", err)
+			continue
+		}
+		silences = append(silences, silence)
+	}
+	return silences, nil
+}
+
+// Adopt records loaded silences as the replica's state, so the next Sync
+// only writes what changed locally after they were applied.
+func (c *RedisSilenceCache) Adopt(silences []core.APISilence) {
+	written := make(map[string]string, len(silences))
+	for _, silence := range silences {
+		written[silence.ID] = cachedSilenceJSON(silence)
+	}
+
+	c.mu.Lock()
+	c.written = written
+	c.mu.Unlock()
+}
+
+// Watch subscribes to the changes of the other replicas. The returned
+// channel receives a value after each change (bursts are coalesced) and is
+// closed when ctx is done.
+func (c *RedisSilenceCache) Watch(ctx context.Context) (<-chan struct{}, error) {
+	pubsub := c.client.Subscribe(ctx, c.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to silence changes: %w", err)
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				if msg.Payload == c.replicaID {
+					continue
+				}
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// cachedSilenceJSON encodes a silence without its time-dependent status.
+func cachedSilenceJSON(silence core.APISilence) string {
+	silence.Status = core.APISilenceStatus{}
+	data, _ := json.Marshal(silence)
+	return string(data)
+}
@@ -0,0 +1,108 @@
+package silencing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/infrastructure/cache"
+)
+
+func newTestSilenceCaches(t *testing.T) (*RedisSilenceCache, *RedisSilenceCache) {
+	mr := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisCache(&cache.CacheConfig{
+		Addr:        mr.Addr(),
+		PoolSize:    5,
+		DialTimeout: time.Second,
+		ReadTimeout: time.Second,
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+
+	a, err := NewRedisSilenceCache(redisCache, "amp:silences", "replica-a", nil)
+	require.NoError(t, err)
+	b, err := NewRedisSilenceCache(redisCache, "amp:silences", "replica-b", nil)
+	require.NoError(t, err)
+	return a, b
+}
+
+func cachedSilence(id, comment string) core.APISilence {
+	return core.APISilence{
+		ID:        id,
+		Matchers:  []core.APISilenceMatcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+		StartsAt:  "2026-03-16T09:00:00Z",
+		EndsAt:    "2026-03-16T11:00:00Z",
+		CreatedBy: "alice",
+		Comment:   comment,
+		Status:    core.APISilenceStatus{State: "active"},
+	}
+}
+
+func waitForChange(t *testing.T, changes <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("no change notification within a second")
+	}
+}
+
+func TestRedisSilenceCache_ReplicasConverge(t *testing.T) {
+	a, b := newTestSilenceCaches(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changesA, err := a.Watch(ctx)
+	require.NoError(t, err)
+	changesB, err := b.Watch(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, a.Seed(ctx, []core.APISilence{cachedSilence("s1", "maintenance")}))
+	waitForChange(t, changesB)
+
+	loaded, err := b.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "maintenance", loaded[0].Comment)
+	assert.Empty(t, loaded[0].Status.State, "status is not cached")
+
+	// b updates s1 and adds s2; a sees both.
+	wrote, err := b.Sync(ctx, []core.APISilence{cachedSilence("s1", "extended"), cachedSilence("s2", "deploy")})
+	require.NoError(t, err)
+	assert.True(t, wrote)
+	waitForChange(t, changesA)
+
+	loaded, err = a.Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, loaded, 2)
+
+	// a deletes s1; unchanged state writes nothing.
+	wrote, err = a.Sync(ctx, []core.APISilence{cachedSilence("s2", "deploy")})
+	require.NoError(t, err)
+	assert.True(t, wrote)
+	wrote, err = a.Sync(ctx, []core.APISilence{cachedSilence("s2", "deploy")})
+	require.NoError(t, err)
+	assert.False(t, wrote)
+	waitForChange(t, changesB)
+
+	loaded, err = b.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "s2", loaded[0].ID)
+
+	select {
+	case <-changesA:
+		t.Fatal("a replica is not notified of its own changes")
+	default:
+	}
+}
+
+func TestNewRedisSilenceCache_RequiresRedis(t *testing.T) {
+	_, err := NewRedisSilenceCache(cache.NewMemoryCache(nil), "amp:silences", "replica-a", nil)
+	assert.Error(t, err)
+}
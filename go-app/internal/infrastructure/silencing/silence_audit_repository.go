@@ -0,0 +1,193 @@
+package silencing
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// SQLiteSilenceAuditRepository implements core.SilenceAuditStore on the
+// append-only silence_audit table created by
+// infrastructure.SQLiteDatabase.MigrateUp.
+type SQLiteSilenceAuditRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteSilenceAuditRepository creates a SQLite silence audit repository.
+func NewSQLiteSilenceAuditRepository(db *sql.DB) *SQLiteSilenceAuditRepository {
+	return &SQLiteSilenceAuditRepository{db: db}
+}
+
+// AppendSilenceAudit implements core.SilenceAuditStore.
+func (r *SQLiteSilenceAuditRepository) AppendSilenceAudit(ctx context.Context, event *core.SilenceAuditEvent) error {
+	before, after, changes, err := marshalSilenceAudit(event)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO silence_audit (silence_id, action, actor, reason, occurred_at, before_state, after_state, changes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.SilenceID, string(event.Action), event.Actor, event.Reason,
+		event.Timestamp.UTC().Format(sqliteTimeLayout), sqliteJSON(before), sqliteJSON(after), sqliteJSON(changes),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to append silence audit event: %w", err)
+	}
+	if event.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to read silence audit event id: %w", err)
+	}
+	return nil
+}
+
+// ListSilenceAudit implements core.SilenceAuditStore.
+func (r *SQLiteSilenceAuditRepository) ListSilenceAudit(ctx context.Context, silenceID string) ([]core.SilenceAuditEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, silence_id, action, actor, reason, occurred_at, before_state, after_state, changes
+		FROM silence_audit
+		WHERE silence_id = ?
+		ORDER BY id`, silenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silence audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]core.SilenceAuditEvent, 0)
+	for rows.Next() {
+		var (
+			event                  core.SilenceAuditEvent
+			action, occurredAt     string
+			before, after, changes sql.NullString
+		)
+		if err := rows.Scan(&event.ID, &event.SilenceID, &action, &event.Actor, &event.Reason,
+			&occurredAt, &before, &after, &changes); err != nil {
+			return nil, fmt.Errorf("failed to scan silence audit event: %w", err)
+		}
+		event.Action = core.SilenceAuditAction(action)
+		if event.Timestamp, err = time.Parse(sqliteTimeLayout, occurredAt); err != nil {
+			return nil, fmt.Errorf("invalid silence audit timestamp %q: %w", occurredAt, err)
+		}
+		if err := unmarshalSilenceAudit(&event, []byte(before.String), []byte(after.String), []byte(changes.String)); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// sqliteJSON stores JSON as TEXT, and absent values as NULL.
+func sqliteJSON(data []byte) any {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
+
+// PostgresSilenceAuditRepository implements core.SilenceAuditStore on the
+// append-only silence_audit table (see migrations).
+type PostgresSilenceAuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresSilenceAuditRepository creates a PostgreSQL silence audit repository.
+func NewPostgresSilenceAuditRepository(pool *pgxpool.Pool) *PostgresSilenceAuditRepository {
+	return &PostgresSilenceAuditRepository{pool: pool}
+}
+
+// AppendSilenceAudit implements core.SilenceAuditStore.
+func (r *PostgresSilenceAuditRepository) AppendSilenceAudit(ctx context.Context, event *core.SilenceAuditEvent) error {
+	before, after, changes, err := marshalSilenceAudit(event)
+	if err != nil {
+		return err
+	}
+
+	err = r.pool.QueryRow(ctx, `
+		INSERT INTO silence_audit (silence_id, action, actor, reason, occurred_at, before_state, after_state, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		event.SilenceID, string(event.Action), event.Actor, event.Reason,
+		event.Timestamp.UTC(), before, after, changes,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to append silence audit event: %w", err)
+	}
+	return nil
+}
+
+// ListSilenceAudit implements core.SilenceAuditStore.
+func (r *PostgresSilenceAuditRepository) ListSilenceAudit(ctx context.Context, silenceID string) ([]core.SilenceAuditEvent, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, silence_id, action, actor, reason, occurred_at, before_state, after_state, changes
+		FROM silence_audit
+		WHERE silence_id = $1
+		ORDER BY id`, silenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silence audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]core.SilenceAuditEvent, 0)
+	for rows.Next() {
+		var (
+			event                  core.SilenceAuditEvent
+			action                 string
+			before, after, changes []byte
+		)
+		if err := rows.Scan(&event.ID, &event.SilenceID, &action, &event.Actor, &event.Reason,
+			&event.Timestamp, &before, &after, &changes); err != nil {
+			return nil, fmt.Errorf("failed to scan silence audit event: %w", err)
+		}
+		event.Action = core.SilenceAuditAction(action)
+		event.Timestamp = event.Timestamp.UTC()
+		if err := unmarshalSilenceAudit(&event, before, after, changes); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// marshalSilenceAudit encodes the JSON columns; absent states are NULL.
+func marshalSilenceAudit(event *core.SilenceAuditEvent) (before, after, changes []byte, err error) {
+	if event.Before != nil {
+		if before, err = json.Marshal(event.Before); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to marshal silence before state: %w", err)
+		}
+	}
+	if event.After != nil {
+		if after, err = json.Marshal(event.After); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to marshal silence after state: %w", err)
+		}
+	}
+	if changes, err = json.Marshal(event.Changes); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal silence changes: %w", err)
+	}
+	return before, after, changes, nil
+}
+
+func unmarshalSilenceAudit(event *core.SilenceAuditEvent, before, after, changes []byte) error {
+	if len(before) > 0 {
+		event.Before = &core.APISilence{}
+		if err := json.Unmarshal(before, event.Before); err != nil {
+			return fmt.Errorf("invalid silence before state: %w", err)
+		}
+	}
+	if len(after) > 0 {
+		event.After = &core.APISilence{}
+		if err := json.Unmarshal(after, event.After); err != nil {
+			return fmt.Errorf("invalid silence after state: %w", err)
+		}
+	}
+	if len(changes) > 0 {
+		if err := json.Unmarshal(changes, &event.Changes); err != nil {
+			return fmt.Errorf("invalid silence changes: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package silencing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestSQLiteSilenceAuditRepository_AppendAndList(t *testing.T) {
+	db := newTestSQLiteSilenceRepository(t).db
+	repo := NewSQLiteSilenceAuditRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	after := &core.APISilence{ID: "s1", EndsAt: now.Add(time.Hour).Format(time.RFC3339), CreatedBy: "alice", Comment: "deploy"}
+	created := core.NewSilenceAuditEvent("s1", core.SilenceAuditCreate, "alice", nil, after, now)
+	require.NoError(t, repo.AppendSilenceAudit(ctx, &created))
+	assert.NotZero(t, created.ID)
+
+	expired := *after
+	expired.EndsAt = now.Add(time.Minute).Format(time.RFC3339)
+	expire := core.NewSilenceAuditEvent("s1", core.SilenceAuditExpire, "bob", after, &expired, now.Add(time.Minute))
+	require.NoError(t, repo.AppendSilenceAudit(ctx, &expire))
+	other := core.NewSilenceAuditEvent("s2", core.SilenceAuditCreate, "carol", nil, &core.APISilence{ID: "s2"}, now)
+	require.NoError(t, repo.AppendSilenceAudit(ctx, &other))
+
+	history, err := repo.ListSilenceAudit(ctx, "s1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, core.SilenceAuditCreate, history[0].Action)
+	assert.Nil(t, history[0].Before)
+	assert.Equal(t, "bob", history[1].Actor)
+	assert.Equal(t, now.Add(time.Minute), history[1].Timestamp)
+	assert.Equal(t, []core.SilenceFieldChange{{Field: "endsAt", Before: after.EndsAt, After: expired.EndsAt}}, history[1].Changes)
+
+	_, err = db.ExecContext(ctx, `DELETE FROM silence_audit`)
+	assert.Error(t, err, "audit table is append-only")
+}
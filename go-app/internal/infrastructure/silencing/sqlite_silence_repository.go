@@ -0,0 +1,645 @@
+package silencing
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ipiton/AMP/internal/core/silencing"
+)
+
+// sqliteTimeLayout is a fixed-width UTC layout so that timestamps stored as TEXT
+// compare correctly with plain string comparison (used by range filters and ORDER BY).
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SQLiteSilenceRepository implements SilenceRepository for SQLite (lite profile).
+//
+// The schema is created by infrastructure.SQLiteDatabase.MigrateUp. Matchers are
+// stored as a JSON array and filtered with the JSON1 json_each() table function.
+//
+// Thread-safety: All methods are safe for concurrent use.
+type SQLiteSilenceRepository struct {
+	db      *sql.DB
+	logger  *slog.Logger
+	metrics *SilenceMetrics
+}
+
+// NewSQLiteSilenceRepository creates a new SQLite silence repository.
+//
+// Parameters:
+//   - db: Connected SQLite database handle (required)
+//   - logger: Structured logger (optional, defaults to slog.Default())
+//   - metrics: Repository metrics (optional, nil disables metrics)
+func NewSQLiteSilenceRepository(db *sql.DB, logger *slog.Logger, metrics *SilenceMetrics) *SQLiteSilenceRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SQLiteSilenceRepository{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// CreateSilence implements SilenceRepository.CreateSilence.
+func (r *SQLiteSilenceRepository) CreateSilence(ctx context.Context, silence *silencing.Silence) (*silencing.Silence, error) {
+	const operation = "create"
+	defer r.observe(operation, time.Now())
+
+	if err := silence.Validate(); err != nil {
+		r.recordError(operation, "validation")
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err)
+	}
+
+	if silence.ID == "" {
+		silence.ID = uuid.New().String()
+	}
+
+	silence.Status = silence.CalculateStatus()
+
+	matchersJSON, err := json.Marshal(silence.Matchers)
+	if err != nil {
+		r.recordError(operation, "marshal")
+		return nil, fmt.Errorf("marshal matchers: %w", err)
+	}
+
+	createdAt := time.Now().UTC()
+	query := `
+		INSERT INTO silences (id, created_by, comment, starts_at, ends_at, matchers, status, require_label_presence, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		silence.ID,
+		silence.CreatedBy,
+		silence.Comment,
+		formatSQLiteTime(silence.StartsAt),
+		formatSQLiteTime(silence.EndsAt),
+		string(matchersJSON),
+		string(silence.Status),
+		silence.RequireLabelPresence,
+		formatSQLiteTime(createdAt),
+	)
+	if err != nil {
+		r.recordError(operation, "insert")
+		if isSQLiteUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: silence with ID %s already exists", ErrSilenceExists, silence.ID)
+		}
+		return nil, fmt.Errorf("insert silence: %w", err)
+	}
+
+	silence.CreatedAt = createdAt
+	r.recordSuccess(operation)
+
+	r.logger.Info("silence created",
+		"silence_id", silence.ID,
+		"created_by", silence.CreatedBy,
+		"status", silence.Status,
+		"matchers_count", len(silence.Matchers),
+	)
+
+	return silence, nil
+}
+
+// GetSilenceByID implements SilenceRepository.GetSilenceByID.
+func (r *SQLiteSilenceRepository) GetSilenceByID(ctx context.Context, id string) (*silencing.Silence, error) {
+	const operation = "get_by_id"
+	defer r.observe(operation, time.Now())
+
+	if _, err := uuid.Parse(id); err != nil {
+		r.recordError(operation, "invalid_uuid")
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUUID, err)
+	}
+
+	query := `SELECT ` + sqliteSilenceColumns + ` FROM silences WHERE id = ?`
+
+	silence, err := scanSQLiteSilence(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.recordError(operation, "not_found")
+			return nil, fmt.Errorf("%w: silence with ID %s", ErrSilenceNotFound, id)
+		}
+		r.recordError(operation, "query")
+		return nil, fmt.Errorf("query silence: %w", err)
+	}
+
+	r.recordSuccess(operation)
+	return silence, nil
+}
+
+// ListSilences implements SilenceRepository.ListSilences.
+func (r *SQLiteSilenceRepository) ListSilences(ctx context.Context, filter SilenceFilter) ([]*silencing.Silence, error) {
+	const operation = "list"
+	defer r.observe(operation, time.Now())
+
+	filter.ApplyDefaults()
+	if err := filter.Validate(); err != nil {
+		r.recordError(operation, "validation")
+		return nil, err
+	}
+
+	where, args := buildSQLiteWhere(filter)
+	query := `SELECT ` + sqliteSilenceColumns + ` FROM silences` + where + sqliteOrderClause(filter) + ` LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	silences, err := r.querySilences(ctx, query, args...)
+	if err != nil {
+		r.recordError(operation, "query")
+		return nil, err
+	}
+
+	r.recordSuccess(operation)
+	return silences, nil
+}
+
+// UpdateSilence implements SilenceRepository.UpdateSilence.
+//
+// Uses the same optimistic locking contract as the PostgreSQL implementation:
+// the update only succeeds if silence.UpdatedAt matches the stored value.
+func (r *SQLiteSilenceRepository) UpdateSilence(ctx context.Context, silence *silencing.Silence) error {
+	const operation = "update"
+	defer r.observe(operation, time.Now())
+
+	if err := silence.Validate(); err != nil {
+		r.recordError(operation, "validation")
+		return fmt.Errorf("%w: %s", ErrValidation, err)
+	}
+
+	silence.Status = silence.CalculateStatus()
+
+	matchersJSON, err := json.Marshal(silence.Matchers)
+	if err != nil {
+		r.recordError(operation, "marshal")
+		return fmt.Errorf("marshal matchers: %w", err)
+	}
+
+	var expectedUpdatedAt any
+	if silence.UpdatedAt != nil {
+		expectedUpdatedAt = formatSQLiteTime(*silence.UpdatedAt)
+	}
+
+	updatedAt := time.Now().UTC()
+	query := `
+		UPDATE silences
+		SET created_by = ?,
+			comment = ?,
+			starts_at = ?,
+			ends_at = ?,
+			matchers = ?,
+			status = ?,
+			require_label_presence = ?,
+			updated_at = ?
+		WHERE id = ?
+		  AND (updated_at IS NULL OR updated_at = ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		silence.CreatedBy,
+		silence.Comment,
+		formatSQLiteTime(silence.StartsAt),
+		formatSQLiteTime(silence.EndsAt),
+		string(matchersJSON),
+		string(silence.Status),
+		silence.RequireLabelPresence,
+		formatSQLiteTime(updatedAt),
+		silence.ID,
+		expectedUpdatedAt,
+	)
+	if err != nil {
+		r.recordError(operation, "update")
+		return fmt.Errorf("update silence: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.recordError(operation, "update")
+		return fmt.Errorf("update silence: %w", err)
+	}
+	if affected == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM silences WHERE id = ?)`, silence.ID).Scan(&exists); err == nil && !exists {
+			r.recordError(operation, "not_found")
+			return fmt.Errorf("%w: silence with ID %s", ErrSilenceNotFound, silence.ID)
+		}
+		r.recordError(operation, "conflict")
+		return fmt.Errorf("%w: silence was modified by another transaction", ErrSilenceConflict)
+	}
+
+	silence.UpdatedAt = &updatedAt
+	r.recordSuccess(operation)
+
+	r.logger.Info("silence updated",
+		"silence_id", silence.ID,
+		"status", silence.Status,
+	)
+
+	return nil
+}
+
+// DeleteSilence implements SilenceRepository.DeleteSilence.
+func (r *SQLiteSilenceRepository) DeleteSilence(ctx context.Context, id string) error {
+	const operation = "delete"
+	defer r.observe(operation, time.Now())
+
+	if _, err := uuid.Parse(id); err != nil {
+		r.recordError(operation, "invalid_uuid")
+		return fmt.Errorf("%w: %s", ErrInvalidUUID, err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM silences WHERE id = ?`, id)
+	if err != nil {
+		r.recordError(operation, "delete")
+		return fmt.Errorf("delete silence: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.recordError(operation, "delete")
+		return fmt.Errorf("delete silence: %w", err)
+	}
+	if affected == 0 {
+		r.recordError(operation, "not_found")
+		return fmt.Errorf("%w: silence with ID %s", ErrSilenceNotFound, id)
+	}
+
+	r.recordSuccess(operation)
+	r.logger.Info("silence deleted", "silence_id", id)
+
+	return nil
+}
+
+// CountSilences implements SilenceRepository.CountSilences.
+func (r *SQLiteSilenceRepository) CountSilences(ctx context.Context, filter SilenceFilter) (int64, error) {
+	const operation = "count"
+	defer r.observe(operation, time.Now())
+
+	where, args := buildSQLiteWhere(filter)
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM silences`+where, args...).Scan(&count); err != nil {
+		r.recordError(operation, "query")
+		return 0, fmt.Errorf("count silences: %w", err)
+	}
+
+	r.recordSuccess(operation)
+	return count, nil
+}
+
+// ExpireSilences implements SilenceRepository.ExpireSilences.
+func (r *SQLiteSilenceRepository) ExpireSilences(ctx context.Context, before time.Time, deleteExpired bool) (int64, error) {
+	operation := "expire"
+	defer func(start time.Time) { r.observe(operation, start) }(time.Now())
+
+	var query string
+	var args []any
+
+	if deleteExpired {
+		operation = "delete_expired"
+		query = `DELETE FROM silences WHERE status = ? AND ends_at < ?`
+		args = []any{string(silencing.SilenceStatusExpired), formatSQLiteTime(before)}
+	} else {
+		query = `
+			UPDATE silences
+			SET status = ?, updated_at = ?
+			WHERE status IN (?, ?)
+			  AND ends_at < ?
+		`
+		args = []any{
+			string(silencing.SilenceStatusExpired),
+			formatSQLiteTime(time.Now()),
+			string(silencing.SilenceStatusActive),
+			string(silencing.SilenceStatusPending),
+			formatSQLiteTime(before),
+		}
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.recordError(operation, "query")
+		return 0, fmt.Errorf("expire silences: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.recordError(operation, "query")
+		return 0, fmt.Errorf("expire silences: %w", err)
+	}
+
+	r.recordSuccess(operation)
+	r.logger.Info("silences expired",
+		"count", affected,
+		"before", before,
+		"deleted", deleteExpired,
+	)
+
+	return affected, nil
+}
+
+// GetExpiringSoon implements SilenceRepository.GetExpiringSoon.
+func (r *SQLiteSilenceRepository) GetExpiringSoon(ctx context.Context, window time.Duration) ([]*silencing.Silence, error) {
+	const operation = "get_expiring_soon"
+	defer r.observe(operation, time.Now())
+
+	now := time.Now()
+	query := `SELECT ` + sqliteSilenceColumns + `
+		FROM silences
+		WHERE status IN (?, ?)
+		  AND ends_at > ?
+		  AND ends_at <= ?
+		ORDER BY ends_at ASC
+		LIMIT 1000`
+
+	silences, err := r.querySilences(ctx, query,
+		string(silencing.SilenceStatusActive),
+		string(silencing.SilenceStatusPending),
+		formatSQLiteTime(now),
+		formatSQLiteTime(now.Add(window)),
+	)
+	if err != nil {
+		r.recordError(operation, "query")
+		return nil, err
+	}
+
+	r.recordSuccess(operation)
+	return silences, nil
+}
+
+// BulkUpdateStatus implements SilenceRepository.BulkUpdateStatus.
+func (r *SQLiteSilenceRepository) BulkUpdateStatus(ctx context.Context, ids []string, status silencing.SilenceStatus) error {
+	const operation = "bulk_update_status"
+	defer r.observe(operation, time.Now())
+
+	if len(ids) == 0 {
+		r.recordError(operation, "validation")
+		return fmt.Errorf("%w: ids cannot be empty", ErrInvalidFilter)
+	}
+	if status == "" {
+		r.recordError(operation, "validation")
+		return fmt.Errorf("%w: status cannot be empty", ErrInvalidFilter)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.recordError(operation, "transaction")
+		return fmt.Errorf("%w: %s", ErrTransactionFailed, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	args := make([]any, 0, len(ids)+2)
+	args = append(args, string(status), formatSQLiteTime(time.Now()))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	query := `UPDATE silences SET status = ?, updated_at = ? WHERE id IN (` + sqlitePlaceholders(len(ids)) + `)`
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		r.recordError(operation, "query")
+		return fmt.Errorf("bulk update status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.recordError(operation, "transaction")
+		return fmt.Errorf("%w: %s", ErrTransactionFailed, err)
+	}
+
+	r.recordSuccess(operation)
+	return nil
+}
+
+// GetSilenceStats implements SilenceRepository.GetSilenceStats.
+func (r *SQLiteSilenceRepository) GetSilenceStats(ctx context.Context) (*SilenceStats, error) {
+	const operation = "get_stats"
+	defer r.observe(operation, time.Now())
+
+	stats := &SilenceStats{
+		ByCreator: make(map[string]int64),
+	}
+
+	countQuery := `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0)
+		FROM silences
+	`
+	err := r.db.QueryRowContext(ctx, countQuery,
+		string(silencing.SilenceStatusActive),
+		string(silencing.SilenceStatusPending),
+		string(silencing.SilenceStatusExpired),
+	).Scan(&stats.Total, &stats.Active, &stats.Pending, &stats.Expired)
+	if err != nil {
+		r.recordError(operation, "query")
+		return nil, fmt.Errorf("query silence counts: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT created_by, COUNT(*) AS count
+		FROM silences
+		GROUP BY created_by
+		ORDER BY count DESC
+		LIMIT 10
+	`)
+	if err != nil {
+		r.recordError(operation, "query")
+		return nil, fmt.Errorf("query creators: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var creator string
+		var count int64
+		if err := rows.Scan(&creator, &count); err != nil {
+			r.recordError(operation, "scan")
+			return nil, fmt.Errorf("scan creator stats: %w", err)
+		}
+		stats.ByCreator[creator] = count
+	}
+	if err := rows.Err(); err != nil {
+		r.recordError(operation, "rows")
+		return nil, fmt.Errorf("iterate creator rows: %w", err)
+	}
+
+	r.recordSuccess(operation)
+	return stats, nil
+}
+
+const sqliteSilenceColumns = `id, created_by, comment, starts_at, ends_at, matchers, status, created_at, updated_at, require_label_presence`
+
+// querySilences runs a SELECT over sqliteSilenceColumns and scans all rows.
+func (r *SQLiteSilenceRepository) querySilences(ctx context.Context, query string, args ...any) ([]*silencing.Silence, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query silences: %w", err)
+	}
+	defer rows.Close()
+
+	silences := []*silencing.Silence{}
+	for rows.Next() {
+		silence, err := scanSQLiteSilence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan silence: %w", err)
+		}
+		silences = append(silences, silence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return silences, nil
+}
+
+type sqliteRowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSQLiteSilence(row sqliteRowScanner) (*silencing.Silence, error) {
+	var (
+		silence                               silencing.Silence
+		startsAt, endsAt, createdAt, matchers string
+		status                                string
+		updatedAt                             sql.NullString
+	)
+
+	if err := row.Scan(
+		&silence.ID, &silence.CreatedBy, &silence.Comment,
+		&startsAt, &endsAt, &matchers,
+		&status, &createdAt, &updatedAt,
+		&silence.RequireLabelPresence,
+	); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if silence.StartsAt, err = parseSQLiteTime(startsAt); err != nil {
+		return nil, err
+	}
+	if silence.EndsAt, err = parseSQLiteTime(endsAt); err != nil {
+		return nil, err
+	}
+	if silence.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	if updatedAt.Valid {
+		ts, err := parseSQLiteTime(updatedAt.String)
+		if err != nil {
+			return nil, err
+		}
+		silence.UpdatedAt = &ts
+	}
+	if err := json.Unmarshal([]byte(matchers), &silence.Matchers); err != nil {
+		return nil, fmt.Errorf("unmarshal matchers: %w", err)
+	}
+	silence.Status = silencing.SilenceStatus(status)
+
+	return &silence, nil
+}
+
+// buildSQLiteWhere is the SQLite counterpart of buildListQuery's WHERE clause.
+func buildSQLiteWhere(filter SilenceFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "status IN ("+sqlitePlaceholders(len(filter.Statuses))+")")
+		for _, status := range filter.Statuses {
+			args = append(args, string(status))
+		}
+	}
+	if filter.CreatedBy != "" {
+		conditions = append(conditions, "created_by = ?")
+		args = append(args, filter.CreatedBy)
+	}
+	if filter.MatcherName != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(silences.matchers) WHERE json_extract(json_each.value, '$.name') = ?)")
+		args = append(args, filter.MatcherName)
+	}
+	if filter.MatcherValue != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(silences.matchers) WHERE json_extract(json_each.value, '$.value') = ?)")
+		args = append(args, filter.MatcherValue)
+	}
+	if filter.StartsAfter != nil {
+		conditions = append(conditions, "starts_at >= ?")
+		args = append(args, formatSQLiteTime(*filter.StartsAfter))
+	}
+	if filter.StartsBefore != nil {
+		conditions = append(conditions, "starts_at <= ?")
+		args = append(args, formatSQLiteTime(*filter.StartsBefore))
+	}
+	if filter.EndsAfter != nil {
+		conditions = append(conditions, "ends_at >= ?")
+		args = append(args, formatSQLiteTime(*filter.EndsAfter))
+	}
+	if filter.EndsBefore != nil {
+		conditions = append(conditions, "ends_at <= ?")
+		args = append(args, formatSQLiteTime(*filter.EndsBefore))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// sqliteOrderClause mirrors the ordering rules of the PostgreSQL filter builder.
+func sqliteOrderClause(filter SilenceFilter) string {
+	orderBy := filter.OrderBy
+	if !sanitizeOrderBy(orderBy) {
+		orderBy = "created_at"
+	}
+
+	direction := "DESC"
+	if orderBy != "created_at" && !filter.OrderDesc {
+		direction = "ASC"
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s, id ASC", orderBy, direction)
+}
+
+func sqlitePlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func parseSQLiteTime(s string) (time.Time, error) {
+	t, err := time.Parse(sqliteTimeLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse timestamp %q: %w", s, err)
+	}
+	return t.UTC(), nil
+}
+
+func isSQLiteUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+func (r *SQLiteSilenceRepository) observe(operation string, start time.Time) {
+	if r.metrics != nil {
+		r.metrics.OperationDuration.WithLabelValues(operation, "success").Observe(time.Since(start).Seconds())
+	}
+}
+
+func (r *SQLiteSilenceRepository) recordSuccess(operation string) {
+	if r.metrics != nil {
+		r.metrics.Operations.WithLabelValues(operation, "success").Inc()
+	}
+}
+
+func (r *SQLiteSilenceRepository) recordError(operation, errorType string) {
+	if r.metrics != nil {
+		r.metrics.Errors.WithLabelValues(operation, errorType).Inc()
+	}
+}
+
+var _ SilenceRepository = (*SQLiteSilenceRepository)(nil)
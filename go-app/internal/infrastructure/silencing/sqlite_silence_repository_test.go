@@ -0,0 +1,212 @@
+package silencing
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core/silencing"
+	"github.com/ipiton/AMP/internal/infrastructure"
+)
+
+func newTestSQLiteSilenceRepository(t *testing.T) *SQLiteSilenceRepository {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db, err := infrastructure.NewSQLiteDatabase(&infrastructure.Config{
+		Driver:     "sqlite",
+		SQLiteFile: filepath.Join(t.TempDir(), "silences.db"),
+		Logger:     logger,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	t.Cleanup(func() { _ = db.Disconnect(ctx) })
+	require.NoError(t, db.MigrateUp(ctx))
+
+	return NewSQLiteSilenceRepository(db.DB(), logger, nil)
+}
+
+func newTestSilence(alertname string, startsAt, endsAt time.Time) *silencing.Silence {
+	return &silencing.Silence{
+		CreatedBy: "ops@example.com",
+		Comment:   "Planned maintenance",
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Matchers: []silencing.Matcher{
+			{Name: "alertname", Value: alertname, Type: silencing.MatcherTypeEqual},
+		},
+	}
+}
+
+func TestSQLiteSilenceRepository_CRUD(t *testing.T) {
+	repo := newTestSQLiteSilenceRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	created, err := repo.CreateSilence(ctx, newTestSilence("HighCPU", now.Add(-time.Minute), now.Add(time.Hour)))
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	assert.Equal(t, silencing.SilenceStatusActive, created.Status)
+
+	_, err = repo.CreateSilence(ctx, created)
+	assert.ErrorIs(t, err, ErrSilenceExists)
+
+	got, err := repo.GetSilenceByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+	assert.True(t, created.StartsAt.Equal(got.StartsAt))
+	assert.True(t, created.EndsAt.Equal(got.EndsAt))
+	assert.Equal(t, created.Matchers, got.Matchers)
+	assert.Nil(t, got.UpdatedAt)
+
+	got.Comment = "Extended maintenance"
+	require.NoError(t, repo.UpdateSilence(ctx, got))
+	require.NotNil(t, got.UpdatedAt)
+
+	stale := *got
+	stale.UpdatedAt = &created.CreatedAt
+	assert.ErrorIs(t, repo.UpdateSilence(ctx, &stale), ErrSilenceConflict)
+
+	reloaded, err := repo.GetSilenceByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Extended maintenance", reloaded.Comment)
+
+	require.NoError(t, repo.DeleteSilence(ctx, created.ID))
+	_, err = repo.GetSilenceByID(ctx, created.ID)
+	assert.ErrorIs(t, err, ErrSilenceNotFound)
+	assert.ErrorIs(t, repo.DeleteSilence(ctx, created.ID), ErrSilenceNotFound)
+	assert.ErrorIs(t, repo.DeleteSilence(ctx, "not-a-uuid"), ErrInvalidUUID)
+}
+
+func TestSQLiteSilenceRepository_CreateValidation(t *testing.T) {
+	repo := newTestSQLiteSilenceRepository(t)
+	now := time.Now().UTC()
+
+	invalid := newTestSilence("HighCPU", now, now.Add(time.Hour))
+	invalid.Comment = ""
+
+	_, err := repo.CreateSilence(context.Background(), invalid)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestSQLiteSilenceRepository_ListFilters(t *testing.T) {
+	repo := newTestSQLiteSilenceRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	active, err := repo.CreateSilence(ctx, newTestSilence("HighCPU", now.Add(-time.Minute), now.Add(time.Hour)))
+	require.NoError(t, err)
+	pending, err := repo.CreateSilence(ctx, newTestSilence("DiskFull", now.Add(time.Hour), now.Add(2*time.Hour)))
+	require.NoError(t, err)
+	other := newTestSilence("HighCPU", now.Add(-time.Minute), now.Add(3*time.Hour))
+	other.CreatedBy = "sre@example.com"
+	other.Matchers = append(other.Matchers, silencing.Matcher{Name: "env", Value: "prod.*", Type: silencing.MatcherTypeRegex})
+	other, err = repo.CreateSilence(ctx, other)
+	require.NoError(t, err)
+
+	ids := func(silences []*silencing.Silence) []string {
+		out := make([]string, 0, len(silences))
+		for _, s := range silences {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		filter SilenceFilter
+		want   []string
+	}{
+		{
+			name:   "by status",
+			filter: SilenceFilter{Statuses: []silencing.SilenceStatus{silencing.SilenceStatusPending}},
+			want:   []string{pending.ID},
+		},
+		{
+			name:   "by creator",
+			filter: SilenceFilter{CreatedBy: "sre@example.com"},
+			want:   []string{other.ID},
+		},
+		{
+			name:   "by matcher name",
+			filter: SilenceFilter{MatcherName: "env"},
+			want:   []string{other.ID},
+		},
+		{
+			name:   "by matcher value",
+			filter: SilenceFilter{MatcherValue: "HighCPU", OrderBy: "ends_at"},
+			want:   []string{active.ID, other.ID},
+		},
+		{
+			name:   "by ends_at range",
+			filter: SilenceFilter{EndsAfter: ptrTime(now.Add(90 * time.Minute)), OrderBy: "ends_at"},
+			want:   []string{pending.ID, other.ID},
+		},
+		{
+			name:   "pagination",
+			filter: SilenceFilter{OrderBy: "ends_at", Limit: 1, Offset: 1},
+			want:   []string{pending.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListSilences(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(got))
+		})
+	}
+
+	count, err := repo.CountSilences(ctx, SilenceFilter{MatcherValue: "HighCPU"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = repo.ListSilences(ctx, SilenceFilter{OrderBy: "comment"})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestSQLiteSilenceRepository_ExpireAndStats(t *testing.T) {
+	repo := newTestSQLiteSilenceRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	expiring, err := repo.CreateSilence(ctx, newTestSilence("A", now.Add(-time.Hour), now.Add(time.Minute)))
+	require.NoError(t, err)
+	_, err = repo.CreateSilence(ctx, newTestSilence("B", now.Add(-time.Hour), now.Add(48*time.Hour)))
+	require.NoError(t, err)
+
+	soon, err := repo.GetExpiringSoon(ctx, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, soon, 1)
+	assert.Equal(t, expiring.ID, soon[0].ID)
+
+	affected, err := repo.ExpireSilences(ctx, now.Add(time.Hour), false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	stats, err := repo.GetSilenceStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Total)
+	assert.Equal(t, int64(1), stats.Active)
+	assert.Equal(t, int64(1), stats.Expired)
+	assert.Equal(t, int64(2), stats.ByCreator["ops@example.com"])
+
+	deleted, err := repo.ExpireSilences(ctx, now.Add(time.Hour), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	require.NoError(t, repo.BulkUpdateStatus(ctx, []string{soon[0].ID}, silencing.SilenceStatusExpired))
+	assert.ErrorIs(t, repo.BulkUpdateStatus(ctx, nil, silencing.SilenceStatusExpired), ErrInvalidFilter)
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -14,6 +14,7 @@ import (
 	_ "modernc.org/sqlite"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/compression"
 )
 
 // SQLiteDatabase адаптер для SQLite, реализующий общий интерфейс Database
@@ -101,6 +102,11 @@ func (s *SQLiteDatabase) Connect(ctx context.Context) error {
 	return nil
 }
 
+// DB возвращает низкоуровневый *sql.DB (nil до Connect)
+func (s *SQLiteDatabase) DB() *sql.DB {
+	return s.db
+}
+
 // Disconnect закрывает соединение с SQLite
 func (s *SQLiteDatabase) Disconnect(ctx context.Context) error {
 	if s.db == nil {
@@ -176,6 +182,25 @@ func (s *SQLiteDatabase) Begin(ctx context.Context) (*sql.Tx, error) {
 	return s.db.BeginTx(ctx, nil)
 }
 
+// addColumnIfMissing добавляет колонку в существующую таблицу (SQLite не
+// поддерживает ADD COLUMN IF NOT EXISTS)
+func (s *SQLiteDatabase) addColumnIfMissing(ctx context.Context, table, column, definition string) error {
+	var count int
+	query := `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`
+	if err := s.db.QueryRowContext(ctx, query, table, column).Scan(&count); err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to add %s.%s column: %w", table, column, err)
+	}
+	return nil
+}
+
 // MigrateUp выполняет миграции схемы для SQLite
 func (s *SQLiteDatabase) MigrateUp(ctx context.Context) error {
 	if s.db == nil {
@@ -264,8 +289,90 @@ func (s *SQLiteDatabase) MigrateUp(ctx context.Context) error {
 		return fmt.Errorf("failed to create publishing table: %w", err)
 	}
 
+	// Создаем таблицу silences (персистентность silence-правил между рестартами)
+	createSilencesTableSQL := `
+	CREATE TABLE IF NOT EXISTS silences (
+		id TEXT PRIMARY KEY,
+		created_by TEXT NOT NULL,
+		comment TEXT NOT NULL CHECK (length(comment) >= 3 AND length(comment) <= 1024),
+		starts_at TEXT NOT NULL, -- UTC, fixed-width layout (sortable)
+		ends_at TEXT NOT NULL CHECK (ends_at > starts_at),
+		matchers TEXT NOT NULL, -- JSON array
+		status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'expired')),
+		created_at TEXT NOT NULL,
+		updated_at TEXT,
+		require_label_presence INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_silences_status ON silences(status, ends_at);
+	CREATE INDEX IF NOT EXISTS idx_silences_starts_at ON silences(starts_at);
+	CREATE INDEX IF NOT EXISTS idx_silences_created_by ON silences(created_by);
+	`
+
+	if _, err := s.db.ExecContext(ctx, createSilencesTableSQL); err != nil {
+		return fmt.Errorf("failed to create silences table: %w", err)
+	}
+
+	// Колонки, добавленные после создания таблицы silences
+	if err := s.addColumnIfMissing(ctx, "silences", "require_label_presence", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Создаем append-only таблицу silence_audit (история изменений silence-правил)
+	createSilenceAuditTableSQL := `
+	CREATE TABLE IF NOT EXISTS silence_audit (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		silence_id TEXT NOT NULL, -- no FK: history outlives deleted silences
+		action TEXT NOT NULL CHECK (action IN ('create', 'update', 'expire', 'delete')),
+		actor TEXT NOT NULL DEFAULT '',
+		reason TEXT NOT NULL DEFAULT '',
+		occurred_at TEXT NOT NULL, -- UTC, fixed-width layout (sortable)
+		before_state TEXT, -- JSON APISilence
+		after_state TEXT, -- JSON APISilence
+		changes TEXT -- JSON array of field diffs
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_silence_audit_silence_id ON silence_audit(silence_id, id);
+	CREATE INDEX IF NOT EXISTS idx_silence_audit_occurred_at ON silence_audit(occurred_at);
+
+	CREATE TRIGGER IF NOT EXISTS silence_audit_no_update
+		BEFORE UPDATE ON silence_audit
+		BEGIN
+			SELECT RAISE(ABORT, 'silence_audit is append-only');
+		END;
+
+	CREATE TRIGGER IF NOT EXISTS silence_audit_no_delete
+		BEFORE DELETE ON silence_audit
+		BEGIN
+			SELECT RAISE(ABORT, 'silence_audit is append-only');
+		END;
+	`
+
+	if _, err := s.db.ExecContext(ctx, createSilenceAuditTableSQL); err != nil {
+		return fmt.Errorf("failed to create silence_audit table: %w", err)
+	}
+
+	// Создаем таблицу alert_rollups (почасовые/посуточные агрегаты алертов)
+	createAlertRollupsTableSQL := `
+	CREATE TABLE IF NOT EXISTS alert_rollups (
+		granularity TEXT NOT NULL CHECK (granularity IN ('hour', 'day')),
+		bucket_start TEXT NOT NULL, -- UTC, fixed-width layout (sortable)
+		dimension TEXT NOT NULL,
+		value TEXT NOT NULL DEFAULT '',
+		count INTEGER NOT NULL DEFAULT 0,
+		computed_at TEXT NOT NULL,
+		PRIMARY KEY (granularity, bucket_start, dimension, value)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_alert_rollups_dimension ON alert_rollups(granularity, dimension, bucket_start);
+	`
+
+	if _, err := s.db.ExecContext(ctx, createAlertRollupsTableSQL); err != nil {
+		return fmt.Errorf("failed to create alert_rollups table: %w", err)
+	}
+
 	s.logger.Info("SQLite schema migration completed successfully",
-		"tables_created", []string{"alerts", "classifications", "publishing"})
+		"tables_created", []string{"alerts", "classifications", "publishing", "silences", "silence_audit", "alert_rollups"})
 	return nil
 }
 
@@ -281,7 +388,7 @@ func (s *SQLiteDatabase) SaveAlert(ctx context.Context, alert *core.Alert) error
 		return fmt.Errorf("failed to marshal labels: %w", err)
 	}
 
-	annotationsJSON, err := json.Marshal(alert.Annotations)
+	annotationsJSON, err := json.Marshal(compression.CompressValues(alert.Annotations, compression.DefaultThreshold))
 	if err != nil {
 		return fmt.Errorf("failed to marshal annotations: %w", err)
 	}
@@ -345,6 +452,9 @@ func (s *SQLiteDatabase) GetAlertByFingerprint(ctx context.Context, fingerprint
 	if err := json.Unmarshal([]byte(annotationsJSON), &alert.Annotations); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
 	}
+	if err := compression.DecompressValues(alert.Annotations); err != nil {
+		return nil, fmt.Errorf("failed to decompress annotations: %w", err)
+	}
 
 	// Обработка nullable полей
 	if endsAt != nil {
@@ -476,6 +586,9 @@ func (s *SQLiteDatabase) ListAlerts(ctx context.Context, filters *core.AlertFilt
 		if err := json.Unmarshal([]byte(annotationsJSON), &alert.Annotations); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal annotations: %w", err)
 		}
+		if err := compression.DecompressValues(alert.Annotations); err != nil {
+			return nil, fmt.Errorf("failed to decompress annotations: %w", err)
+		}
 
 		// Обработка nullable полей
 		if endsAt != nil {
@@ -519,7 +632,7 @@ func (s *SQLiteDatabase) UpdateAlert(ctx context.Context, alert *core.Alert) err
 		return fmt.Errorf("failed to marshal labels: %w", err)
 	}
 
-	annotationsJSON, err := json.Marshal(alert.Annotations)
+	annotationsJSON, err := json.Marshal(compression.CompressValues(alert.Annotations, compression.DefaultThreshold))
 	if err != nil {
 		return fmt.Errorf("failed to marshal annotations: %w", err)
 	}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -211,6 +212,45 @@ func TestSQLiteDatabase_CRUD(t *testing.T) {
 	assert.Equal(t, 1, alertList.Total)
 }
 
+func TestSQLiteDatabase_LargeAnnotationsAreCompressed(t *testing.T) {
+	config := &Config{
+		Driver:     "sqlite",
+		SQLiteFile: filepath.Join(t.TempDir(), "test_compress.db"),
+		Logger:     slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+	}
+
+	db, err := NewSQLiteDatabase(config)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Disconnect(ctx)
+	require.NoError(t, db.MigrateUp(ctx))
+
+	runbook := strings.Repeat("Check the node exporter and restart kubelet.\n", 500)
+	alert := &core.Alert{
+		Fingerprint: "large-annotations",
+		AlertName:   "NodeNotReady",
+		Status:      core.StatusFiring,
+		Labels:      map[string]string{"alertname": "NodeNotReady"},
+		Annotations: map[string]string{"summary": "Node is not ready", "runbook": runbook},
+		StartsAt:    time.Now(),
+	}
+	require.NoError(t, db.SaveAlert(ctx, alert))
+
+	// Stored compressed
+	var stored string
+	require.NoError(t, db.db.QueryRowContext(ctx,
+		"SELECT annotations FROM alerts WHERE fingerprint = ?", alert.Fingerprint).Scan(&stored))
+	assert.Less(t, len(stored), len(runbook))
+	assert.Contains(t, stored, `"summary":"Node is not ready"`)
+
+	// Read back transparently
+	retrieved, err := db.GetAlertByFingerprint(ctx, alert.Fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, alert.Annotations, retrieved.Annotations)
+}
+
 func TestSQLiteDatabase_Transaction(t *testing.T) {
 	tempDir := t.TempDir()
 	dbPath := filepath.Join(tempDir, "test_transaction.db")
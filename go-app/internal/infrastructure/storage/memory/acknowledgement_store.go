@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// acknowledgementTTL bounds how long an acknowledgement is kept when its
+// incident is never resolved or unacknowledged in the source.
+const acknowledgementTTL = 7 * 24 * time.Hour
+
+// AcknowledgementStore holds the alerts acknowledged in incident management
+// systems (e.g. PagerDuty), by alert fingerprint.
+//
+// Thread-safe. Acknowledgements older than a week are dropped lazily.
+type AcknowledgementStore struct {
+	mu   sync.RWMutex
+	acks map[string]acknowledgement
+}
+
+type acknowledgement struct {
+	ack core.AlertAcknowledgement
+	at  time.Time
+}
+
+func NewAcknowledgementStore() *AcknowledgementStore {
+	return &AcknowledgementStore{
+		acks: make(map[string]acknowledgement),
+	}
+}
+
+// Acknowledge records that the alert with fingerprint was acknowledged at
+// at, replacing any previous acknowledgement.
+func (s *AcknowledgementStore) Acknowledge(fingerprint string, ack core.AlertAcknowledgement, at time.Time) {
+	ack.At = at.UTC().Format(time.RFC3339)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(at)
+	s.acks[fingerprint] = acknowledgement{ack: ack, at: at}
+}
+
+// Unacknowledge forgets the acknowledgement of fingerprint, if any.
+func (s *AcknowledgementStore) Unacknowledge(fingerprint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.acks, fingerprint)
+}
+
+// Get returns the acknowledgement of fingerprint and when it was made.
+func (s *AcknowledgementStore) Get(fingerprint string, now time.Time) (core.AlertAcknowledgement, time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.acks[fingerprint]
+	if !ok || now.Sub(entry.at) >= acknowledgementTTL {
+		return core.AlertAcknowledgement{}, time.Time{}, false
+	}
+	return entry.ack, entry.at, true
+}
+
+// prune drops expired acknowledgements. Called with s.mu held.
+func (s *AcknowledgementStore) prune(now time.Time) {
+	for fingerprint, entry := range s.acks {
+		if now.Sub(entry.at) >= acknowledgementTTL {
+			delete(s.acks, fingerprint)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestAcknowledgementStore_AcknowledgeAndUnacknowledge(t *testing.T) {
+	store := NewAcknowledgementStore()
+	now := time.Now()
+
+	store.Acknowledge("fp", core.AlertAcknowledgement{Source: "pagerduty", By: "Jane"}, now)
+
+	ack, at, ok := store.Get("fp", now)
+	if !ok {
+		t.Fatal("expected fp to be acknowledged")
+	}
+	if ack.Source != "pagerduty" || ack.By != "Jane" || ack.At != now.UTC().Format(time.RFC3339) {
+		t.Errorf("unexpected acknowledgement: %+v", ack)
+	}
+	if !at.Equal(now) {
+		t.Errorf("acknowledged at %v, want %v", at, now)
+	}
+	if _, _, ok := store.Get("other", now); ok {
+		t.Error("acknowledgement must not apply to other fingerprints")
+	}
+
+	store.Unacknowledge("fp")
+	if _, _, ok := store.Get("fp", now); ok {
+		t.Error("expected fp to be unacknowledged")
+	}
+}
+
+func TestAcknowledgementStore_Expires(t *testing.T) {
+	store := NewAcknowledgementStore()
+	now := time.Now()
+
+	store.Acknowledge("old", core.AlertAcknowledgement{Source: "pagerduty"}, now)
+	if _, _, ok := store.Get("old", now.Add(acknowledgementTTL)); ok {
+		t.Error("acknowledgement must expire")
+	}
+
+	store.Acknowledge("new", core.AlertAcknowledgement{Source: "pagerduty"}, now.Add(acknowledgementTTL))
+	if len(store.acks) != 1 {
+		t.Errorf("expired acknowledgements kept: %d entries", len(store.acks))
+	}
+}
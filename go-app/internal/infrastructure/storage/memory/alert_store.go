@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/core/domain"
 )
 
 type AlertStore struct {
@@ -87,7 +88,9 @@ func (s *AlertStore) apply(in *core.StoredAlertState, now time.Time) {
 	}
 
 	if isSameAlertPayload(existing, in) {
-		return // exact duplicate
+		// Exact duplicate: still an update, so the alert does not expire
+		existing.UpdatedAt = now
+		return
 	}
 
 	existing.Labels = cloneStringMap(in.Labels)
@@ -150,6 +153,50 @@ func (s *AlertStore) resolveAlertLocked(in *core.StoredAlertState, now time.Time
 	}
 }
 
+// DeleteExpiredBefore deletes up to limit alerts that expired before cutoff:
+// resolved alerts by EndsAt, and firing alerts without EndsAt resolveTimeout
+// after their last update (see domain.Alert.ExpiresAt). Returns the dedup
+// keys of the deleted alerts.
+func (s *AlertStore) DeleteExpiredBefore(cutoff time.Time, resolveTimeout time.Duration, limit int, now time.Time) []string {
+	s.mu.Lock()
+	var deleted []string
+	for key, state := range s.all {
+		if len(deleted) >= limit {
+			break
+		}
+		alert := storedAlertTimes(state)
+		if !alert.IsExpiredAt(now, resolveTimeout) || !alert.ExpiresAt(resolveTimeout).Before(cutoff) {
+			continue
+		}
+		delete(s.all, key)
+		if activeSet, ok := s.activeByBase[state.BaseFingerprint]; ok {
+			delete(activeSet, key)
+			if len(activeSet) == 0 {
+				delete(s.activeByBase, state.BaseFingerprint)
+			}
+		}
+		deleted = append(deleted, key)
+	}
+	s.mu.Unlock()
+
+	if len(deleted) > 0 {
+		s.notifyChange()
+	}
+	return deleted
+}
+
+// storedAlertTimes returns the status and timestamps of state as a
+// domain.Alert, which implements the expiry rules.
+func storedAlertTimes(state *core.StoredAlertState) *domain.Alert {
+	updatedAt := state.UpdatedAt
+	return &domain.Alert{
+		Status:    domain.AlertStatus(state.Status),
+		StartsAt:  state.StartsAt,
+		EndsAt:    state.EndsAt,
+		Timestamp: &updatedAt,
+	}
+}
+
 func (s *AlertStore) markActiveLocked(baseFingerprint, dedupKey string) {
 	if _, ok := s.activeByBase[baseFingerprint]; !ok {
 		s.activeByBase[baseFingerprint] = make(map[string]struct{})
@@ -245,7 +292,7 @@ func (s *AlertStore) GroupAlerts(groupBy []string) []core.APIGettableAlertGroup
 	}
 
 	sort.Slice(out, func(i, j int) bool {
-		return labelsFingerprint(out[i].Labels) < labelsFingerprint(out[j].Labels)
+		return domain.LabelsFingerprint(out[i].Labels) < domain.LabelsFingerprint(out[j].Labels)
 	})
 
 	return out
@@ -272,8 +319,9 @@ func toGettableAlert(alert core.APIAlert, now time.Time) core.APIGettableAlert {
 		GeneratorURL: alert.GeneratorURL,
 		Fingerprint:  alert.Fingerprint,
 		Status: core.APIAlertStatus{
-			State:      state,
-			SilencedBy: []string{},
+			State:       state,
+			SilencedBy:  []string{},
+			InhibitedBy: []string{},
 		},
 	}
 }
@@ -334,7 +382,7 @@ func normalizeIngestInput(in core.AlertIngestInput, now time.Time) (*core.Stored
 
 	baseFingerprint := strings.TrimSpace(in.Fingerprint)
 	if baseFingerprint == "" {
-		baseFingerprint = labelsFingerprint(labels)
+		baseFingerprint = domain.LabelsFingerprint(labels)
 	}
 	if baseFingerprint == "" {
 		baseFingerprint = shortHash(startsAt.UTC().Format(time.RFC3339Nano))
@@ -404,26 +452,6 @@ func normalizeStatus(raw string, endsAt *time.Time, now time.Time) string {
 	return "firing"
 }
 
-func labelsFingerprint(labels map[string]string) string {
-	if len(labels) == 0 {
-		return ""
-	}
-	keys := make([]string, 0, len(labels))
-	for k := range labels {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	var b strings.Builder
-	for _, k := range keys {
-		b.WriteString(k)
-		b.WriteByte('=')
-		b.WriteString(labels[k])
-		b.WriteByte('|')
-	}
-	return shortHash(b.String())
-}
-
 func dedupKey(baseFingerprint string, startsAt time.Time) string {
 	return shortHash(baseFingerprint + "|" + startsAt.UTC().Format(time.RFC3339Nano))
 }
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestAlertStore_DeleteExpiredBefore(t *testing.T) {
+	store := NewAlertStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	endsAt := now.Add(-3 * time.Hour).Format(time.RFC3339)
+
+	require.NoError(t, store.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "Resolved"}, Status: "resolved", StartsAt: now.Add(-4 * time.Hour).Format(time.RFC3339), EndsAt: endsAt},
+		{Labels: map[string]string{"alertname": "Stale"}, StartsAt: now.Add(-4 * time.Hour).Format(time.RFC3339)},
+	}, now.Add(-3*time.Hour)))
+	require.NoError(t, store.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "Fresh"}, StartsAt: now.Add(-4 * time.Hour).Format(time.RFC3339)},
+	}, now.Add(-time.Minute)))
+
+	// Resending an unchanged alert keeps it from expiring
+	require.NoError(t, store.IngestBatch([]core.AlertIngestInput{
+		{Labels: map[string]string{"alertname": "Fresh"}, StartsAt: now.Add(-4 * time.Hour).Format(time.RFC3339)},
+	}, now))
+
+	deleted := store.DeleteExpiredBefore(now.Add(-time.Hour), 5*time.Minute, 10, now)
+	assert.Len(t, deleted, 2)
+
+	total, firing, _ := store.Stats()
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 1, firing)
+	assert.Equal(t, "Fresh", store.List("", true)[0].Labels["alertname"])
+	assert.Empty(t, store.DeleteExpiredBefore(now.Add(time.Hour), 5*time.Minute, 10, now))
+}
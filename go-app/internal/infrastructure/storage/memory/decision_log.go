@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+const (
+	// DefaultDecisionTracesPerAlert is how many recent traces are kept per fingerprint.
+	DefaultDecisionTracesPerAlert = 20
+	// DefaultDecisionLogMaxAlerts bounds the number of fingerprints tracked.
+	DefaultDecisionLogMaxAlerts = 10000
+)
+
+// DecisionLog keeps the most recent pipeline decision traces per alert fingerprint.
+//
+// Memory is bounded on both axes: each fingerprint keeps at most perAlert
+// traces, and the least recently updated fingerprint is evicted once
+// maxAlerts is exceeded.
+type DecisionLog struct {
+	mu        sync.RWMutex
+	perAlert  int
+	maxAlerts int
+	entries   map[string]*list.Element
+	lru       *list.List // front = most recently updated
+}
+
+type decisionLogEntry struct {
+	fingerprint string
+	traces      []core.DecisionTrace // oldest first
+}
+
+// NewDecisionLog creates a decision log. Non-positive limits fall back to defaults.
+func NewDecisionLog(perAlert, maxAlerts int) *DecisionLog {
+	if perAlert <= 0 {
+		perAlert = DefaultDecisionTracesPerAlert
+	}
+	if maxAlerts <= 0 {
+		maxAlerts = DefaultDecisionLogMaxAlerts
+	}
+	return &DecisionLog{
+		perAlert:  perAlert,
+		maxAlerts: maxAlerts,
+		entries:   make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// Record stores a completed trace.
+func (l *DecisionLog) Record(trace *core.DecisionTrace) {
+	if trace == nil || trace.Fingerprint == "" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[trace.Fingerprint]
+	if !ok {
+		elem = l.lru.PushFront(&decisionLogEntry{fingerprint: trace.Fingerprint})
+		l.entries[trace.Fingerprint] = elem
+	} else {
+		l.lru.MoveToFront(elem)
+	}
+
+	entry := elem.Value.(*decisionLogEntry)
+	entry.traces = append(entry.traces, *trace)
+	if over := len(entry.traces) - l.perAlert; over > 0 {
+		entry.traces = append([]core.DecisionTrace(nil), entry.traces[over:]...)
+	}
+
+	for l.lru.Len() > l.maxAlerts {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.entries, oldest.Value.(*decisionLogEntry).fingerprint)
+	}
+}
+
+// Get returns the recorded traces for fingerprint, newest first.
+func (l *DecisionLog) Get(fingerprint string) []core.DecisionTrace {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	elem, ok := l.entries[fingerprint]
+	if !ok {
+		return nil
+	}
+
+	traces := elem.Value.(*decisionLogEntry).traces
+	out := make([]core.DecisionTrace, 0, len(traces))
+	for i := len(traces) - 1; i >= 0; i-- {
+		out = append(out, traces[i])
+	}
+	return out
+}
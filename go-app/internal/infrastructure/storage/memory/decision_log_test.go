@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestDecisionLog_KeepsRecentTracesNewestFirst(t *testing.T) {
+	log := NewDecisionLog(2, 10)
+	now := time.Now()
+
+	for i, result := range []core.DecisionResult{core.DecisionResultPublished, core.DecisionResultSilenced, core.DecisionResultFiltered} {
+		log.Record(&core.DecisionTrace{Fingerprint: "fp", ProcessedAt: now.Add(time.Duration(i) * time.Second), Result: result})
+	}
+
+	traces := log.Get("fp")
+	if len(traces) != 2 {
+		t.Fatalf("expected 2 traces, got %d", len(traces))
+	}
+	if traces[0].Result != core.DecisionResultFiltered || traces[1].Result != core.DecisionResultSilenced {
+		t.Fatalf("unexpected order: %s, %s", traces[0].Result, traces[1].Result)
+	}
+}
+
+func TestDecisionLog_EvictsLeastRecentlyUpdatedAlert(t *testing.T) {
+	log := NewDecisionLog(1, 2)
+
+	log.Record(&core.DecisionTrace{Fingerprint: "a"})
+	log.Record(&core.DecisionTrace{Fingerprint: "b"})
+	log.Record(&core.DecisionTrace{Fingerprint: "a"})
+	log.Record(&core.DecisionTrace{Fingerprint: "c"})
+
+	if got := log.Get("b"); got != nil {
+		t.Fatalf("expected b to be evicted, got %v", got)
+	}
+	if len(log.Get("a")) != 1 || len(log.Get("c")) != 1 {
+		t.Fatal("expected a and c to be retained")
+	}
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+const (
+	// DefaultDeliveryAttemptsPerAlert is how many recent attempts are kept per fingerprint.
+	DefaultDeliveryAttemptsPerAlert = 50
+	// DefaultDeliveryLogMaxAlerts bounds the number of fingerprints tracked.
+	DefaultDeliveryLogMaxAlerts = 10000
+)
+
+// DeliveryLog keeps the most recent publish attempts per alert fingerprint.
+//
+// Memory is bounded like DecisionLog: each fingerprint keeps at most
+// perAlert attempts, and the least recently updated fingerprint is evicted
+// once maxAlerts is exceeded.
+type DeliveryLog struct {
+	mu        sync.RWMutex
+	perAlert  int
+	maxAlerts int
+	entries   map[string]*list.Element
+	lru       *list.List // front = most recently updated
+}
+
+type deliveryLogEntry struct {
+	fingerprint string
+	attempts    []core.DeliveryAttempt // oldest first
+}
+
+// NewDeliveryLog creates a delivery log. Non-positive limits fall back to defaults.
+func NewDeliveryLog(perAlert, maxAlerts int) *DeliveryLog {
+	if perAlert <= 0 {
+		perAlert = DefaultDeliveryAttemptsPerAlert
+	}
+	if maxAlerts <= 0 {
+		maxAlerts = DefaultDeliveryLogMaxAlerts
+	}
+	return &DeliveryLog{
+		perAlert:  perAlert,
+		maxAlerts: maxAlerts,
+		entries:   make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// RecordDelivery stores a publish attempt for fingerprint.
+func (l *DeliveryLog) RecordDelivery(fingerprint string, attempt core.DeliveryAttempt) {
+	if fingerprint == "" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[fingerprint]
+	if !ok {
+		elem = l.lru.PushFront(&deliveryLogEntry{fingerprint: fingerprint})
+		l.entries[fingerprint] = elem
+	} else {
+		l.lru.MoveToFront(elem)
+	}
+
+	entry := elem.Value.(*deliveryLogEntry)
+	entry.attempts = append(entry.attempts, attempt)
+	if over := len(entry.attempts) - l.perAlert; over > 0 {
+		entry.attempts = append([]core.DeliveryAttempt(nil), entry.attempts[over:]...)
+	}
+
+	for l.lru.Len() > l.maxAlerts {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.entries, oldest.Value.(*deliveryLogEntry).fingerprint)
+	}
+}
+
+// Get returns the recorded attempts for fingerprint, newest first.
+func (l *DeliveryLog) Get(fingerprint string) []core.DeliveryAttempt {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	elem, ok := l.entries[fingerprint]
+	if !ok {
+		return nil
+	}
+
+	attempts := elem.Value.(*deliveryLogEntry).attempts
+	out := make([]core.DeliveryAttempt, 0, len(attempts))
+	for i := len(attempts) - 1; i >= 0; i-- {
+		out = append(out, attempts[i])
+	}
+	return out
+}
+
+// ListDeliveries returns the latest limit attempts recorded for fingerprint,
+// newest first, like the persisted delivery audit log.
+func (l *DeliveryLog) ListDeliveries(_ context.Context, fingerprint string, limit int) ([]core.DeliveryAttempt, error) {
+	attempts := l.Get(fingerprint)
+	if limit > 0 && len(attempts) > limit {
+		attempts = attempts[:limit]
+	}
+	return attempts, nil
+}
@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestDeliveryLog_KeepsRecentAttemptsNewestFirst(t *testing.T) {
+	log := NewDeliveryLog(2, 1)
+
+	for i := 1; i <= 3; i++ {
+		log.RecordDelivery("fp", core.DeliveryAttempt{Target: "pagerduty", Attempt: i})
+	}
+	attempts := log.Get("fp")
+	if len(attempts) != 2 || attempts[0].Attempt != 3 || attempts[1].Attempt != 2 {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+
+	log.RecordDelivery("other", core.DeliveryAttempt{Attempt: 1})
+	if got := log.Get("fp"); got != nil {
+		t.Fatalf("expected fp to be evicted, got %v", got)
+	}
+}
@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/internal/core/silencing"
+)
+
+// maxOccurrencesPerPass bounds the windows returned per recurrence by one
+// DueOccurrences call, so a tight schedule with a long lookahead cannot
+// flood the silence store.
+const maxOccurrencesPerPass = 100
+
+// ErrRecurringSilenceNotFound is returned when a recurring silence ID does not exist.
+var ErrRecurringSilenceNotFound = errors.New("recurring silence not found")
+
+// RecurringSilenceStore holds recurring silences and tracks which of their
+// windows have been materialized as concrete silences.
+//
+// Thread-safe.
+type RecurringSilenceStore struct {
+	mu          sync.RWMutex
+	recurrences map[string]*recurringSilence
+}
+
+type recurringSilence struct {
+	id        string
+	expr      string
+	schedule  *silencing.CronSchedule
+	duration  time.Duration
+	matchers  []core.SilenceMatcherInput
+	createdBy string
+	comment   string
+	createdAt time.Time
+
+	// lastStart is the start of the latest materialized window (zero if none).
+	lastStart time.Time
+	// windows are materialized silences that have not ended yet.
+	windows []materializedWindow
+}
+
+type materializedWindow struct {
+	silenceID string
+	endsAt    time.Time
+}
+
+func NewRecurringSilenceStore() *RecurringSilenceStore {
+	return &RecurringSilenceStore{
+		recurrences: make(map[string]*recurringSilence),
+	}
+}
+
+// Create validates in and stores a new recurring silence. Returns its ID.
+func (s *RecurringSilenceStore) Create(in *core.RecurringSilenceInput, now time.Time) (string, error) {
+	if in == nil {
+		return "", fmt.Errorf("recurring silence payload is required")
+	}
+
+	loc := time.UTC
+	if tz := strings.TrimSpace(in.Timezone); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return "", fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+	}
+
+	schedule, err := silencing.ParseCronSchedule(in.Schedule, loc)
+	if err != nil {
+		return "", fmt.Errorf("invalid schedule: %w", err)
+	}
+	if schedule.Next(now).IsZero() {
+		return "", fmt.Errorf("schedule %q never fires", in.Schedule)
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(in.Duration))
+	if err != nil {
+		return "", fmt.Errorf("invalid duration: %w", err)
+	}
+	if duration < time.Minute {
+		return "", fmt.Errorf("duration must be at least 1m")
+	}
+
+	if _, err := ValidateSilenceMatchers(in.Matchers); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(in.CreatedBy) == "" {
+		return "", fmt.Errorf("creator information missing")
+	}
+	if strings.TrimSpace(in.Comment) == "" {
+		return "", fmt.Errorf("comment missing")
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate recurring silence id: %w", err)
+	}
+
+	rs := &recurringSilence{
+		id:        id.String(),
+		expr:      strings.TrimSpace(in.Schedule),
+		schedule:  schedule,
+		duration:  duration,
+		matchers:  append([]core.SilenceMatcherInput(nil), in.Matchers...),
+		createdBy: in.CreatedBy,
+		comment:   in.Comment,
+		createdAt: now.UTC(),
+	}
+
+	s.mu.Lock()
+	s.recurrences[rs.id] = rs
+	s.mu.Unlock()
+
+	return rs.id, nil
+}
+
+// Get returns the recurring silence with id.
+func (s *RecurringSilenceStore) Get(id string, now time.Time) (core.APIRecurringSilence, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rs, ok := s.recurrences[id]
+	if !ok {
+		return core.APIRecurringSilence{}, false
+	}
+	return rs.toAPI(now), true
+}
+
+// List returns all recurring silences ordered by creation time.
+func (s *RecurringSilenceStore) List(now time.Time) []core.APIRecurringSilence {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]core.APIRecurringSilence, 0, len(s.recurrences))
+	for _, rs := range s.recurrences {
+		out = append(out, rs.toAPI(now))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt != out[j].CreatedAt {
+			return out[i].CreatedAt < out[j].CreatedAt
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Delete removes a recurring silence and returns the IDs of its materialized
+// silences that have not ended yet, so the caller can decide what to expire.
+func (s *RecurringSilenceStore) Delete(id string, now time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs, ok := s.recurrences[id]
+	if !ok {
+		return nil, ErrRecurringSilenceNotFound
+	}
+	delete(s.recurrences, id)
+	return rs.openSilenceIDs(now), nil
+}
+
+// DueOccurrences returns the windows that start before now+lookahead, have
+// not been materialized yet and have not already ended.
+func (s *RecurringSilenceStore) DueOccurrences(now time.Time, lookahead time.Duration) []core.RecurringSilenceOccurrence {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	horizon := now.Add(lookahead)
+	var out []core.RecurringSilenceOccurrence
+	for _, rs := range s.recurrences {
+		// Windows that started before now-duration are already over.
+		cursor := now.Add(-rs.duration)
+		if rs.lastStart.After(cursor) {
+			cursor = rs.lastStart
+		}
+
+		for n := 0; n < maxOccurrencesPerPass; n++ {
+			start := rs.schedule.Next(cursor)
+			if start.IsZero() || start.After(horizon) {
+				break
+			}
+			out = append(out, core.RecurringSilenceOccurrence{
+				RecurrenceID: rs.id,
+				StartsAt:     start.UTC(),
+				EndsAt:       start.Add(rs.duration).UTC(),
+				Matchers:     rs.matchers,
+				CreatedBy:    rs.createdBy,
+				Comment:      rs.comment,
+			})
+			cursor = start
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartsAt.Equal(out[j].StartsAt) {
+			return out[i].StartsAt.Before(out[j].StartsAt)
+		}
+		return out[i].RecurrenceID < out[j].RecurrenceID
+	})
+	return out
+}
+
+// MarkMaterialized records that the occurrence was created as silenceID.
+// Unknown recurrences (deleted meanwhile) are ignored.
+func (s *RecurringSilenceStore) MarkMaterialized(occ core.RecurringSilenceOccurrence, silenceID string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs, ok := s.recurrences[occ.RecurrenceID]
+	if !ok {
+		return
+	}
+	if occ.StartsAt.After(rs.lastStart) {
+		rs.lastStart = occ.StartsAt
+	}
+
+	windows := rs.windows[:0]
+	for _, w := range rs.windows {
+		if w.endsAt.After(now) {
+			windows = append(windows, w)
+		}
+	}
+	rs.windows = append(windows, materializedWindow{silenceID: silenceID, endsAt: occ.EndsAt})
+}
+
+func (rs *recurringSilence) openSilenceIDs(now time.Time) []string {
+	ids := make([]string, 0, len(rs.windows))
+	for _, w := range rs.windows {
+		if w.endsAt.After(now) {
+			ids = append(ids, w.silenceID)
+		}
+	}
+	return ids
+}
+
+func (rs *recurringSilence) toAPI(now time.Time) core.APIRecurringSilence {
+	matchers := make([]core.APISilenceMatcher, 0, len(rs.matchers))
+	for _, m := range rs.matchers {
+		isEqual := true
+		if m.IsEqual != nil {
+			isEqual = *m.IsEqual
+		}
+		matchers = append(matchers, core.APISilenceMatcher{Name: m.Name, Value: m.Value, IsRegex: m.IsRegex, IsEqual: isEqual})
+	}
+
+	out := core.APIRecurringSilence{
+		ID:         rs.id,
+		Schedule:   rs.expr,
+		Timezone:   rs.schedule.Location().String(),
+		Duration:   rs.duration.String(),
+		Matchers:   matchers,
+		CreatedBy:  rs.createdBy,
+		Comment:    rs.comment,
+		CreatedAt:  rs.createdAt.Format(time.RFC3339),
+		SilenceIDs: rs.openSilenceIDs(now),
+	}
+
+	cursor := now
+	if rs.lastStart.After(cursor) {
+		cursor = rs.lastStart
+	}
+	if next := rs.schedule.Next(cursor); !next.IsZero() {
+		out.NextStartsAt = next.UTC().Format(time.RFC3339)
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// SetPolicy installs the silence policy checked by CreateOrUpdate for all
+// input except BypassPolicy. A check wrapping core.ErrSilenceApprovalRequired
+// makes the caller queue the silence with RequestApproval.
+func (s *SilenceStore) SetPolicy(check func(*core.SilenceInput, time.Time) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = check
+}
+
+func (s *SilenceStore) checkPolicy(in *core.SilenceInput, now time.Time) error {
+	s.mu.RLock()
+	check := s.policy
+	s.mu.RUnlock()
+	if check == nil || in.BypassPolicy {
+		return nil
+	}
+	return check(in, now)
+}
+
+// RequestApproval queues a silence that the policy holds for approval; reason
+// is shown to approvers. Requests expire when the silence would have ended.
+func (s *SilenceStore) RequestApproval(in *core.SilenceInput, reason string, now time.Time) (core.SilenceApprovalRequest, error) {
+	if in == nil {
+		return core.SilenceApprovalRequest{}, fmt.Errorf("silence payload is required")
+	}
+	now = now.UTC()
+	if _, err := normalizeSilenceInput(in, now, false); err != nil {
+		return core.SilenceApprovalRequest{}, err
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return core.SilenceApprovalRequest{}, fmt.Errorf("failed to generate approval id: %w", err)
+	}
+
+	requestedBy := strings.TrimSpace(in.Actor)
+	if requestedBy == "" {
+		requestedBy = strings.TrimSpace(in.CreatedBy)
+	}
+	silence := *in
+	silence.ApprovedBy = ""
+	req := &core.SilenceApprovalRequest{
+		ID:          id.String(),
+		Silence:     silence,
+		RequestedBy: requestedBy,
+		RequestedAt: now.Format(time.RFC3339),
+		Reason:      reason,
+	}
+
+	s.mu.Lock()
+	s.pruneApprovals(now)
+	s.approvals[req.ID] = req
+	s.mu.Unlock()
+	return *req, nil
+}
+
+// ListApprovals returns the pending approval requests, oldest first.
+func (s *SilenceStore) ListApprovals(now time.Time) []core.SilenceApprovalRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneApprovals(now.UTC())
+	out := make([]core.SilenceApprovalRequest, 0, len(s.approvals))
+	for _, req := range s.approvals {
+		out = append(out, *req)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].RequestedAt != out[j].RequestedAt {
+			return out[i].RequestedAt < out[j].RequestedAt
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// GetApproval returns a pending approval request.
+func (s *SilenceStore) GetApproval(id string, now time.Time) (core.SilenceApprovalRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneApprovals(now.UTC())
+	req, ok := s.approvals[id]
+	if !ok {
+		return core.SilenceApprovalRequest{}, false
+	}
+	return *req, true
+}
+
+// Approve creates the silence of an approval request on behalf of approver
+// and removes the request. The policy still applies, so the requester cannot
+// approve their own silence.
+func (s *SilenceStore) Approve(id, approver string, now time.Time) (string, error) {
+	req, ok := s.GetApproval(id, now)
+	if !ok {
+		return "", ErrSilenceApprovalNotFound
+	}
+
+	in := req.Silence
+	in.Actor = req.RequestedBy
+	in.ApprovedBy = strings.TrimSpace(approver)
+	if in.ApprovedBy == "" {
+		return "", fmt.Errorf("%w: approver is required", core.ErrSilencePolicyViolation)
+	}
+	silenceID, err := s.CreateOrUpdate(&in, now)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	delete(s.approvals, id)
+	s.mu.Unlock()
+	return silenceID, nil
+}
+
+// RejectApproval drops a pending approval request.
+func (s *SilenceStore) RejectApproval(id string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneApprovals(now.UTC())
+	if _, ok := s.approvals[id]; !ok {
+		return ErrSilenceApprovalNotFound
+	}
+	delete(s.approvals, id)
+	return nil
+}
+
+// pruneApprovals drops requests whose silence would have ended. Caller must
+// hold the write lock.
+func (s *SilenceStore) pruneApprovals(now time.Time) {
+	for id, req := range s.approvals {
+		endsAt, err := time.Parse(time.RFC3339, req.Silence.EndsAt)
+		if err != nil || !now.Before(endsAt) {
+			delete(s.approvals, id)
+		}
+	}
+}
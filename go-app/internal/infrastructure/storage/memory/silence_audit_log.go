@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// DefaultSilenceAuditLogMaxEvents bounds the in-memory silence audit trail.
+const DefaultSilenceAuditLogMaxEvents = 10000
+
+// SilenceAuditLog is an in-memory core.SilenceAuditStore, used when silences
+// are not persisted. It keeps the most recent maxEvents events across all
+// silences and does not survive a restart.
+type SilenceAuditLog struct {
+	mu        sync.RWMutex
+	maxEvents int
+	nextID    int64
+	events    []core.SilenceAuditEvent // oldest first
+}
+
+// NewSilenceAuditLog creates an audit log. A non-positive limit falls back to
+// DefaultSilenceAuditLogMaxEvents.
+func NewSilenceAuditLog(maxEvents int) *SilenceAuditLog {
+	if maxEvents <= 0 {
+		maxEvents = DefaultSilenceAuditLogMaxEvents
+	}
+	return &SilenceAuditLog{maxEvents: maxEvents}
+}
+
+// AppendSilenceAudit implements core.SilenceAuditStore.
+func (l *SilenceAuditLog) AppendSilenceAudit(_ context.Context, event *core.SilenceAuditEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	event.ID = l.nextID
+	l.events = append(l.events, *event)
+	if over := len(l.events) - l.maxEvents; over > 0 {
+		l.events = append(l.events[:0:0], l.events[over:]...)
+	}
+	return nil
+}
+
+// ListSilenceAudit implements core.SilenceAuditStore.
+func (l *SilenceAuditLog) ListSilenceAudit(_ context.Context, silenceID string) ([]core.SilenceAuditEvent, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	out := make([]core.SilenceAuditEvent, 0)
+	for _, event := range l.events {
+		if event.SilenceID == silenceID {
+			out = append(out, event)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestSilenceStore_AuditTrail(t *testing.T) {
+	store := NewSilenceStore()
+	log := NewSilenceAuditLog(0)
+	store.SetAuditHook(func(event core.SilenceAuditEvent) {
+		_ = log.AppendSilenceAudit(context.Background(), &event)
+	})
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	in := core.SilenceInput{
+		Matchers:  []core.SilenceMatcherInput{{Name: "env", Value: "prod"}},
+		StartsAt:  now.Format(time.RFC3339),
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "alice",
+		Comment:   "deploy",
+	}
+	id, err := store.CreateOrUpdate(&in, now)
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+
+	// Same matchers: updated in place.
+	in.ID = id
+	in.EndsAt = now.Add(2 * time.Hour).Format(time.RFC3339)
+	in.Actor = "ops-token"
+	if _, err := store.CreateOrUpdate(&in, now.Add(time.Minute)); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+
+	// Changed matchers: the silence is expired and replaced.
+	in.Matchers = []core.SilenceMatcherInput{{Name: "env", Value: "staging"}}
+	newID, err := store.CreateOrUpdate(&in, now.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("replace error: %v", err)
+	}
+
+	history, _ := log.ListSilenceAudit(context.Background(), id)
+	if len(history) != 3 {
+		t.Fatalf("got %d events for %s, want 3: %+v", len(history), id, history)
+	}
+	if history[0].Action != core.SilenceAuditCreate || history[0].Actor != "alice" || history[0].Before != nil {
+		t.Errorf("unexpected create event: %+v", history[0])
+	}
+	update := history[1]
+	if update.Action != core.SilenceAuditUpdate || update.Actor != "ops-token" {
+		t.Errorf("unexpected update event: %+v", update)
+	}
+	if len(update.Changes) != 1 || update.Changes[0].Field != "endsAt" {
+		t.Errorf("update changes = %+v, want endsAt only", update.Changes)
+	}
+	if history[2].Action != core.SilenceAuditExpire || history[2].Reason != "replaced by "+newID {
+		t.Errorf("unexpected expire event: %+v", history[2])
+	}
+
+	replacement, _ := log.ListSilenceAudit(context.Background(), newID)
+	if len(replacement) != 1 || replacement[0].Reason != "replaces "+id {
+		t.Fatalf("unexpected replacement history: %+v", replacement)
+	}
+
+	if err := store.ExpireBy(newID, "bob", now.Add(3*time.Minute)); err != nil {
+		t.Fatalf("expire error: %v", err)
+	}
+	replacement, _ = log.ListSilenceAudit(context.Background(), newID)
+	if last := replacement[len(replacement)-1]; last.Action != core.SilenceAuditExpire || last.Actor != "bob" {
+		t.Errorf("unexpected expire event: %+v", last)
+	}
+}
+
+func TestSilenceAuditLog_Bounded(t *testing.T) {
+	log := NewSilenceAuditLog(2)
+	for i := 0; i < 3; i++ {
+		_ = log.AppendSilenceAudit(context.Background(), &core.SilenceAuditEvent{SilenceID: "s1"})
+	}
+
+	events, _ := log.ListSilenceAudit(context.Background(), "s1")
+	if len(events) != 2 || events[0].ID != 2 || events[1].ID != 3 {
+		t.Errorf("expected the two most recent events, got %+v", events)
+	}
+}
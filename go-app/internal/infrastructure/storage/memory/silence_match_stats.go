@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// RecordMatches counts a suppressed alert for each silence in ids. Unknown
+// IDs are ignored. Match statistics are kept in memory only: they are not
+// persisted, shared with other replicas or reported to the change hook.
+func (s *SilenceStore) RecordMatches(ids []string, now time.Time) {
+	if len(ids) == 0 {
+		return
+	}
+	now = now.UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := s.silences[id]; !ok {
+			continue
+		}
+		stats, ok := s.matchStats[id]
+		if !ok {
+			stats = &core.SilenceMatchStats{}
+			s.matchStats[id] = stats
+		}
+		stats.MatchCount++
+		matchedAt := now
+		stats.LastMatchedAt = &matchedAt
+	}
+}
+
+// MatchStats returns the match statistics of every silence in the store,
+// including silences that never matched.
+func (s *SilenceStore) MatchStats() map[string]core.SilenceMatchStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]core.SilenceMatchStats, len(s.silences))
+	for id := range s.silences {
+		if stats, ok := s.matchStats[id]; ok {
+			out[id] = *stats
+		} else {
+			out[id] = core.SilenceMatchStats{}
+		}
+	}
+	return out
+}
+
+// pruneMatchStats drops the statistics of silences no longer in the store.
+// The caller must hold s.mu.
+func (s *SilenceStore) pruneMatchStats() {
+	for id := range s.matchStats {
+		if _, ok := s.silences[id]; !ok {
+			delete(s.matchStats, id)
+		}
+	}
+}
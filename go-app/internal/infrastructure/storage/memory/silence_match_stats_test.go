@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func createTestSilence(t *testing.T, store *SilenceStore, alertname string, now time.Time) string {
+	t.Helper()
+	id, err := store.CreateOrUpdate(&core.SilenceInput{
+		Matchers:  []core.SilenceMatcherInput{{Name: "alertname", Value: alertname, IsEqual: boolPtr(true)}},
+		StartsAt:  now.Format(time.RFC3339),
+		EndsAt:    now.Add(time.Hour).Format(time.RFC3339),
+		CreatedBy: "alice",
+		Comment:   "maintenance",
+	}, now)
+	require.NoError(t, err)
+	return id
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+func TestSilenceStore_MatchStats(t *testing.T) {
+	store := NewSilenceStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	busy := createTestSilence(t, store, "HighCPU", now)
+	zombie := createTestSilence(t, store, "Retired", now)
+
+	store.RecordMatches([]string{busy}, now.Add(time.Minute))
+	store.RecordMatches([]string{busy, "unknown"}, now.Add(2*time.Minute))
+
+	stats := store.MatchStats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, uint64(2), stats[busy].MatchCount)
+	require.NotNil(t, stats[busy].LastMatchedAt)
+	assert.Equal(t, now.Add(2*time.Minute), *stats[busy].LastMatchedAt)
+	assert.Equal(t, core.SilenceMatchStats{}, stats[zombie])
+
+	// Statistics leave with their silence.
+	require.True(t, store.Delete(busy))
+	assert.NotContains(t, store.MatchStats(), busy)
+
+	store.RecordMatches([]string{zombie}, now)
+	require.NoError(t, store.ReplaceFromPersistence(nil, now))
+	assert.Empty(t, store.MatchStats())
+	assert.Empty(t, store.matchStats)
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// Overlaps returns the active and pending silences whose time range overlaps
+// the silence described by in and whose matchers subsume or are subsumed by
+// its matchers. Invalid input has no overlaps; CreateOrUpdate reports it.
+//
+// Subsumption is decided per matcher and errs on the side of no overlap: a
+// matcher is implied only by an identical matcher on the same label or by an
+// equality matcher whose value it matches. A silence requiring label presence
+// only subsumes one that does not if the other pins each of its labels to a
+// non-empty value.
+func (s *SilenceStore) Overlaps(in *core.SilenceInput, now time.Time) []core.SilenceOverlap {
+	if in == nil {
+		return nil
+	}
+	now = now.UTC()
+	next, err := normalizeSilenceInput(in, now, false)
+	if err != nil {
+		return nil
+	}
+	// CreateOrUpdate clamps StartsAt to now.
+	if nowSec := now.Truncate(time.Second); next.StartsAt.Before(nowSec) {
+		next.StartsAt = nowSec
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []core.SilenceOverlap
+	for _, silence := range s.silences {
+		if silenceState(silence, now) == "expired" {
+			continue
+		}
+		if !silence.StartsAt.Before(next.EndsAt) || !next.StartsAt.Before(silence.EndsAt) {
+			continue
+		}
+
+		broader := silenceSubsumes(silence, next)
+		narrower := silenceSubsumes(next, silence)
+		var relation core.SilenceOverlapRelation
+		switch {
+		case broader && narrower:
+			relation = core.SilenceOverlapDuplicate
+		case broader:
+			relation = core.SilenceOverlapBroader
+		case narrower:
+			relation = core.SilenceOverlapNarrower
+		default:
+			continue
+		}
+		out = append(out, core.SilenceOverlap{Relation: relation, Silence: toAPISilence(silence, now)})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Silence.ID < out[j].Silence.ID })
+	return out
+}
+
+// silenceSubsumes reports whether every alert matched by narrow is also
+// matched by broad.
+func silenceSubsumes(broad, narrow *core.StoredSilenceState) bool {
+	presence := broad.RequireLabelPresence && !narrow.RequireLabelPresence
+	for _, b := range broad.Matchers {
+		if !silenceMatcherImplied(b, narrow.Matchers, presence) {
+			return false
+		}
+	}
+	return true
+}
+
+// silenceMatcherImplied reports whether any matcher of narrow on the same
+// label restricts the label to values that matcher accepts. With
+// requirePresence, only a matcher pinning the label to a non-empty value
+// implies it.
+func silenceMatcherImplied(matcher core.StoredSilenceMatcher, narrow []core.StoredSilenceMatcher, requirePresence bool) bool {
+	for _, n := range narrow {
+		if n.Name != matcher.Name {
+			continue
+		}
+		if requirePresence && (!n.IsEqual || n.IsRegex || n.Value == "") {
+			continue
+		}
+		if n == matcher {
+			return true
+		}
+		if n.IsEqual && !n.IsRegex &&
+			silenceMatchesLabels([]core.StoredSilenceMatcher{matcher}, map[string]string{n.Name: n.Value}) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func overlapInput(start, end time.Time, matchers ...core.SilenceMatcherInput) *core.SilenceInput {
+	return &core.SilenceInput{
+		Matchers:  matchers,
+		StartsAt:  start.Format(time.RFC3339),
+		EndsAt:    end.Format(time.RFC3339),
+		CreatedBy: "bob",
+		Comment:   "deploy",
+	}
+}
+
+func TestSilenceStore_Overlaps(t *testing.T) {
+	store := NewSilenceStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	existing := createTestSilence(t, store, "HighCPU", now) // alertname="HighCPU" for an hour
+
+	alertname := core.SilenceMatcherInput{Name: "alertname", Value: "HighCPU"}
+	prod := core.SilenceMatcherInput{Name: "env", Value: "prod"}
+
+	tests := []struct {
+		name     string
+		in       *core.SilenceInput
+		relation core.SilenceOverlapRelation
+	}{
+		{"same matchers", overlapInput(now, now.Add(2*time.Hour), alertname), core.SilenceOverlapDuplicate},
+		{"more specific", overlapInput(now, now.Add(time.Hour), alertname, prod), core.SilenceOverlapBroader},
+		{"regex covering existing", overlapInput(now, now.Add(time.Hour),
+			core.SilenceMatcherInput{Name: "alertname", Value: "High.*", IsRegex: true}), core.SilenceOverlapNarrower},
+		{"different label value", overlapInput(now, now.Add(time.Hour),
+			core.SilenceMatcherInput{Name: "alertname", Value: "DiskFull"}), ""},
+		{"after existing ends", overlapInput(now.Add(time.Hour), now.Add(2*time.Hour), alertname), ""},
+		{"invalid input", overlapInput(now, now.Add(time.Hour)), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlaps := store.Overlaps(tt.in, now)
+			if tt.relation == "" {
+				assert.Empty(t, overlaps)
+				return
+			}
+			require.Len(t, overlaps, 1)
+			assert.Equal(t, tt.relation, overlaps[0].Relation)
+			assert.Equal(t, existing, overlaps[0].Silence.ID)
+		})
+	}
+}
+
+func TestSilenceStore_OverlapsIgnoresExpired(t *testing.T) {
+	store := NewSilenceStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	id := createTestSilence(t, store, "HighCPU", now)
+	require.NoError(t, store.Expire(id, now.Add(time.Minute)))
+
+	in := overlapInput(now, now.Add(time.Hour), core.SilenceMatcherInput{Name: "alertname", Value: "HighCPU"})
+	assert.Empty(t, store.Overlaps(in, now.Add(2*time.Minute)))
+}
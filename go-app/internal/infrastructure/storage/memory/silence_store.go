@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"sort"
@@ -12,47 +13,152 @@ import (
 	"github.com/ipiton/AMP/internal/core"
 )
 
+var (
+	// ErrSilenceNotFound is returned when a silence ID does not exist.
+	ErrSilenceNotFound = errors.New("silence not found")
+	// ErrSilenceAlreadyExpired is returned when expiring a silence that is already expired.
+	ErrSilenceAlreadyExpired = errors.New("silence already expired")
+	// ErrSilenceApprovalNotFound is returned when an approval request does not exist.
+	ErrSilenceApprovalNotFound = errors.New("silence approval request not found")
+)
+
+var silenceLabelNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 type SilenceStore struct {
 	mu       sync.RWMutex
 	silences map[string]*core.StoredSilenceState
 	onChange func()
+	onAudit  func(core.SilenceAuditEvent)
+	policy   func(*core.SilenceInput, time.Time) error
+
+	// approvals are silences held for a second person's approval. They are
+	// not persisted: a restart drops them rather than activating them.
+	approvals map[string]*core.SilenceApprovalRequest
+
+	// matchStats counts the alerts each silence suppressed (see RecordMatches).
+	matchStats map[string]*core.SilenceMatchStats
 }
 
 func NewSilenceStore() *SilenceStore {
 	return &SilenceStore{
-		silences: make(map[string]*core.StoredSilenceState),
+		silences:   make(map[string]*core.StoredSilenceState),
+		approvals:  make(map[string]*core.SilenceApprovalRequest),
+		matchStats: make(map[string]*core.SilenceMatchStats),
 	}
 }
 
+// CreateOrUpdate creates a silence or updates the one referenced by in.ID,
+// following Alertmanager semantics:
+//   - an unknown ID returns ErrSilenceNotFound
+//   - a silence is updated in place only if its matchers are unchanged and the
+//     new time range is compatible with its state (see canUpdateSilence);
+//     otherwise the old silence is expired and a new one with a fresh ID is created
+//   - new silences never start in the past (StartsAt is clamped to now)
+//
+// Returns the ID of the resulting silence.
 func (s *SilenceStore) CreateOrUpdate(in *core.SilenceInput, now time.Time) (string, error) {
-	return s.createOrUpdateInternal(in, now, true, true, false)
-}
-
-func (s *SilenceStore) createOrUpdateInternal(in *core.SilenceInput, now time.Time, notify, enforceExistingID, allowPastEndsAt bool) (string, error) {
 	if in == nil {
 		return "", fmt.Errorf("silence payload is required")
 	}
 
-	updateRequested := strings.TrimSpace(in.ID) != ""
-	normalized, err := normalizeSilenceInput(in, now, allowPastEndsAt)
+	now = now.UTC()
+	next, err := normalizeSilenceInput(in, now, false)
 	if err != nil {
 		return "", err
 	}
+	if err := s.checkPolicy(in, now); err != nil {
+		return "", err
+	}
+
+	actor := strings.TrimSpace(in.Actor)
+	if actor == "" {
+		actor = next.CreatedBy
+	}
 
+	var events []core.SilenceAuditEvent
 	s.mu.Lock()
-	if enforceExistingID && updateRequested {
-		if _, ok := s.silences[normalized.ID]; !ok {
+	if strings.TrimSpace(in.ID) != "" {
+		prev, ok := s.silences[next.ID]
+		if !ok {
 			s.mu.Unlock()
-			return "", fmt.Errorf("silence not found")
+			return "", ErrSilenceNotFound
 		}
+		before := toAPISilence(prev, now)
+
+		if canUpdateSilence(prev, next, now) {
+			s.silences[next.ID] = next
+			after := toAPISilence(next, now)
+			s.mu.Unlock()
+			s.audit(core.NewSilenceAuditEvent(next.ID, core.SilenceAuditUpdate, actor, &before, &after, now))
+			s.notifyChange()
+			return next.ID, nil
+		}
+
+		id, err := uuid.NewRandom()
+		if err != nil {
+			s.mu.Unlock()
+			return "", fmt.Errorf("failed to generate silence id: %w", err)
+		}
+
+		if silenceState(prev, now) != "expired" {
+			expireSilence(prev, now)
+			after := toAPISilence(prev, now)
+			event := core.NewSilenceAuditEvent(prev.ID, core.SilenceAuditExpire, actor, &before, &after, now)
+			event.Reason = "replaced by " + id.String()
+			events = append(events, event)
+		}
+		next.ID = id.String()
+	}
+
+	// Clamp at second precision: the API exposes RFC3339 timestamps and clients
+	// send StartsAt back unchanged when updating an active silence.
+	if nowSec := now.Truncate(time.Second); next.StartsAt.Before(nowSec) {
+		next.StartsAt = nowSec
 	}
-	s.silences[normalized.ID] = normalized
+	s.silences[next.ID] = next
+	after := toAPISilence(next, now)
 	s.mu.Unlock()
 
-	if notify {
-		s.notifyChange()
+	created := core.NewSilenceAuditEvent(next.ID, core.SilenceAuditCreate, actor, nil, &after, now)
+	if id := strings.TrimSpace(in.ID); id != "" {
+		created.Reason = "replaces " + id
 	}
-	return normalized.ID, nil
+	if approver := strings.TrimSpace(in.ApprovedBy); approver != "" {
+		created.Reason = strings.TrimPrefix(created.Reason+"; approved by "+approver, "; ")
+	}
+	s.audit(append(events, created)...)
+	s.notifyChange()
+	return next.ID, nil
+}
+
+// Expire ends a silence now (Alertmanager DELETE semantics). The silence is kept
+// so it remains visible as expired. Pending silences get StartsAt = EndsAt = now.
+func (s *SilenceStore) Expire(id string, now time.Time) error {
+	return s.ExpireBy(id, "", now)
+}
+
+// ExpireBy is Expire recording actor in the audit trail.
+func (s *SilenceStore) ExpireBy(id, actor string, now time.Time) error {
+	now = now.UTC()
+
+	s.mu.Lock()
+	silence, ok := s.silences[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrSilenceNotFound
+	}
+	if silenceState(silence, now) == "expired" {
+		s.mu.Unlock()
+		return ErrSilenceAlreadyExpired
+	}
+	before := toAPISilence(silence, now)
+	expireSilence(silence, now)
+	after := toAPISilence(silence, now)
+	s.mu.Unlock()
+
+	s.audit(core.NewSilenceAuditEvent(id, core.SilenceAuditExpire, actor, &before, &after, now))
+	s.notifyChange()
+	return nil
 }
 
 func (s *SilenceStore) List(now time.Time) []core.APISilence {
@@ -82,24 +188,96 @@ func (s *SilenceStore) Get(id string, now time.Time) (core.APISilence, bool) {
 }
 
 func (s *SilenceStore) Delete(id string) bool {
+	now := time.Now().UTC()
+
 	s.mu.Lock()
-	if _, ok := s.silences[id]; !ok {
+	silence, ok := s.silences[id]
+	if !ok {
 		s.mu.Unlock()
 		return false
 	}
 
+	before := toAPISilence(silence, now)
 	delete(s.silences, id)
+	delete(s.matchStats, id)
 	s.mu.Unlock()
+	s.audit(core.NewSilenceAuditEvent(id, core.SilenceAuditDelete, "", &before, nil, now))
 	s.notifyChange()
 	return true
 }
 
+// DeleteExpiredBefore permanently removes up to limit silences that ended
+// before cutoff, oldest first (limit <= 0 removes all of them). Each removal
+// is audited as a delete by actor with reason; the change hook runs once.
+// Returns the IDs removed.
+func (s *SilenceStore) DeleteExpiredBefore(cutoff time.Time, limit int, actor, reason string, now time.Time) []string {
+	now = now.UTC()
+
+	s.mu.Lock()
+	candidates := make([]*core.StoredSilenceState, 0)
+	for _, silence := range s.silences {
+		if silence.EndsAt.Before(cutoff) && silenceState(silence, now) == "expired" {
+			candidates = append(candidates, silence)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if !candidates[i].EndsAt.Equal(candidates[j].EndsAt) {
+			return candidates[i].EndsAt.Before(candidates[j].EndsAt)
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	ids := make([]string, 0, len(candidates))
+	events := make([]core.SilenceAuditEvent, 0, len(candidates))
+	for _, silence := range candidates {
+		before := toAPISilence(silence, now)
+		delete(s.silences, silence.ID)
+		delete(s.matchStats, silence.ID)
+		event := core.NewSilenceAuditEvent(silence.ID, core.SilenceAuditDelete, actor, &before, nil, now)
+		event.Reason = reason
+		events = append(events, event)
+		ids = append(ids, silence.ID)
+	}
+	s.mu.Unlock()
+
+	if len(ids) == 0 {
+		return ids
+	}
+	s.audit(events...)
+	s.notifyChange()
+	return ids
+}
+
 func (s *SilenceStore) SetOnChange(fn func()) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.onChange = fn
 }
 
+// SetAuditHook registers fn to receive an audit event for every create,
+// update, expire and delete. Restoring from persistence is not audited.
+func (s *SilenceStore) SetAuditHook(fn func(core.SilenceAuditEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onAudit = fn
+}
+
+func (s *SilenceStore) audit(events ...core.SilenceAuditEvent) {
+	s.mu.RLock()
+	fn := s.onAudit
+	s.mu.RUnlock()
+
+	if fn == nil {
+		return
+	}
+	for _, event := range events {
+		fn(event)
+	}
+}
+
 func (s *SilenceStore) notifyChange() {
 	s.mu.RLock()
 	fn := s.onChange
@@ -134,32 +312,78 @@ func (s *SilenceStore) Stats(now time.Time) (total, active, pending, expired int
 
 func (s *SilenceStore) RestoreFromPersistence(items []core.APISilence, now time.Time) error {
 	for i, item := range items {
-		matchers := make([]core.SilenceMatcherInput, 0, len(item.Matchers))
-		for _, matcher := range item.Matchers {
-			isEqual := matcher.IsEqual
-			matchers = append(matchers, core.SilenceMatcherInput{
-				Name:    matcher.Name,
-				Value:   matcher.Value,
-				IsRegex: matcher.IsRegex,
-				IsEqual: &isEqual,
-			})
+		normalized, err := normalizeSilenceInput(persistedSilenceInput(item), now, true)
+		if err != nil {
+			return fmt.Errorf("persisted silence[%d]: %w", i, err)
 		}
 
-		in := &core.SilenceInput{
-			ID:        item.ID,
-			Matchers:  matchers,
-			StartsAt:  item.StartsAt,
-			EndsAt:    item.EndsAt,
-			CreatedBy: item.CreatedBy,
-			Comment:   item.Comment,
-		}
-		if _, err := s.createOrUpdateInternal(in, now, false, false, true); err != nil {
+		s.mu.Lock()
+		s.silences[normalized.ID] = normalized
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// ReplaceFromPersistence replaces all silences with items, e.g. the silences
+// changed by another replica. Like restoring, it neither notifies the change
+// hook nor audits. Nothing is replaced when an item is invalid.
+func (s *SilenceStore) ReplaceFromPersistence(items []core.APISilence, now time.Time) error {
+	silences := make(map[string]*core.StoredSilenceState, len(items))
+	for i, item := range items {
+		normalized, err := normalizeSilenceInput(persistedSilenceInput(item), now, true)
+		if err != nil {
 			return fmt.Errorf("persisted silence[%d]: %w", i, err)
 		}
+		silences[normalized.ID] = normalized
 	}
+
+	s.mu.Lock()
+	s.silences = silences
+	s.pruneMatchStats()
+	s.mu.Unlock()
 	return nil
 }
 
+func persistedSilenceInput(item core.APISilence) *core.SilenceInput {
+	matchers := make([]core.SilenceMatcherInput, 0, len(item.Matchers))
+	for _, matcher := range item.Matchers {
+		isEqual := matcher.IsEqual
+		matchers = append(matchers, core.SilenceMatcherInput{
+			Name:    matcher.Name,
+			Value:   matcher.Value,
+			IsRegex: matcher.IsRegex,
+			IsEqual: &isEqual,
+		})
+	}
+
+	return &core.SilenceInput{
+		ID:        item.ID,
+		Matchers:  matchers,
+		StartsAt:  item.StartsAt,
+		EndsAt:    item.EndsAt,
+		CreatedBy: item.CreatedBy,
+		Comment:   item.Comment,
+
+		NotifyOnExpiry: item.NotifyOnExpiry,
+
+		RequireLabelPresence: item.RequireLabelPresence,
+	}
+}
+
+// ActiveSilences returns the silences that are active at now.
+func (s *SilenceStore) ActiveSilences(now time.Time) []core.APISilence {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]core.APISilence, 0, len(s.silences))
+	for _, silence := range s.silences {
+		if silenceState(silence, now) == "active" {
+			out = append(out, toAPISilence(silence, now))
+		}
+	}
+	return out
+}
+
 func (s *SilenceStore) ActiveMatchingSilenceIDs(labels map[string]string, now time.Time) []string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -173,7 +397,7 @@ func (s *SilenceStore) ActiveMatchingSilenceIDs(labels map[string]string, now ti
 		if silenceState(silence, now) != "active" {
 			continue
 		}
-		if silenceMatchesLabels(silence.Matchers, labels) {
+		if silenceMatches(silence, labels) {
 			out = append(out, silence.ID)
 		}
 	}
@@ -194,7 +418,7 @@ func (s *SilenceStore) HasActiveMatch(labels map[string]string, now time.Time) b
 		if silenceState(silence, now) != "active" {
 			continue
 		}
-		if silenceMatchesLabels(silence.Matchers, labels) {
+		if silenceMatches(silence, labels) {
 			return true
 		}
 	}
@@ -203,6 +427,62 @@ func (s *SilenceStore) HasActiveMatch(labels map[string]string, now time.Time) b
 
 // Internal helpers
 
+// canUpdateSilence mirrors Alertmanager's rules for in-place silence updates.
+func canUpdateSilence(prev, next *core.StoredSilenceState, now time.Time) bool {
+	if prev.RequireLabelPresence != next.RequireLabelPresence {
+		return false
+	}
+	if len(prev.Matchers) != len(next.Matchers) {
+		return false
+	}
+	for i := range prev.Matchers {
+		if prev.Matchers[i] != next.Matchers[i] {
+			return false
+		}
+	}
+
+	switch silenceState(prev, now) {
+	case "active":
+		return next.StartsAt.Equal(prev.StartsAt) && !next.EndsAt.Before(now)
+	case "pending":
+		return !next.StartsAt.Before(now)
+	default:
+		return false
+	}
+}
+
+// expireSilence ends the silence at now. Caller must hold the write lock.
+func expireSilence(silence *core.StoredSilenceState, now time.Time) {
+	if now.Before(silence.StartsAt) {
+		silence.StartsAt = now
+	}
+	silence.EndsAt = now
+	silence.UpdatedAt = now
+}
+
+// matcherMatchesEmpty reports whether the matcher would match a missing label.
+func matcherMatchesEmpty(matcher core.StoredSilenceMatcher) bool {
+	match := matcher.Value == ""
+	if matcher.IsRegex {
+		re, err := regexp.Compile("^(?:" + matcher.Value + ")$")
+		match = err == nil && re.MatchString("")
+	}
+	return match == matcher.IsEqual
+}
+
+// silenceMatches reports whether the silence matches labels, honoring its
+// RequireLabelPresence flag.
+func silenceMatches(silence *core.StoredSilenceState, labels map[string]string) bool {
+	if silence.RequireLabelPresence {
+		for _, matcher := range silence.Matchers {
+			if labels[matcher.Name] == "" {
+				return false
+			}
+		}
+	}
+	return silenceMatchesLabels(silence.Matchers, labels)
+}
+
 func silenceMatchesLabels(matchers []core.StoredSilenceMatcher, labels map[string]string) bool {
 	for _, matcher := range matchers {
 		labelValue := labels[matcher.Name]
@@ -230,7 +510,10 @@ func silenceMatchesLabels(matchers []core.StoredSilenceMatcher, labels map[strin
 	return true
 }
 
-func normalizeSilenceInput(in *core.SilenceInput, now time.Time, allowPastEndsAt bool) (*core.StoredSilenceState, error) {
+// normalizeSilenceInput validates API input and converts it to the stored form.
+// Restored silences (restoring=true) skip checks that only apply to new input:
+// they may already be expired and may predate the creator/comment requirement.
+func normalizeSilenceInput(in *core.SilenceInput, now time.Time, restoring bool) (*core.StoredSilenceState, error) {
 	id := strings.TrimSpace(in.ID)
 	if id == "" {
 		uid, err := uuid.NewRandom()
@@ -240,7 +523,7 @@ func normalizeSilenceInput(in *core.SilenceInput, now time.Time, allowPastEndsAt
 		id = uid.String()
 	}
 
-	startsAt := now.UTC()
+	startsAt := now.UTC().Truncate(time.Second)
 	if startsAtRaw := strings.TrimSpace(in.StartsAt); startsAtRaw != "" {
 		parsedStartsAt, err := time.Parse(time.RFC3339, startsAtRaw)
 		if err != nil {
@@ -257,22 +540,74 @@ func normalizeSilenceInput(in *core.SilenceInput, now time.Time, allowPastEndsAt
 		}
 		endsAt = parsedEndsAt.UTC()
 	}
-	if !endsAt.After(startsAt) {
+	// Expiring a silence before it started leaves StartsAt == EndsAt,
+	// which is kept when restoring.
+	if endsAt.Before(startsAt) || (!restoring && endsAt.Equal(startsAt)) {
 		return nil, fmt.Errorf("start time must be before end time")
 	}
-	if !allowPastEndsAt && endsAt.Before(now.UTC()) {
+	if !restoring && endsAt.Before(now.UTC()) {
 		return nil, fmt.Errorf("end time can't be in the past")
 	}
+	if !restoring && strings.TrimSpace(in.CreatedBy) == "" {
+		return nil, fmt.Errorf("creator information missing")
+	}
+	if !restoring && strings.TrimSpace(in.Comment) == "" {
+		return nil, fmt.Errorf("comment missing")
+	}
+
+	matchers, err := normalizeSilenceMatchers(in.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	if in.RequireLabelPresence {
+		for i, matcher := range matchers {
+			// Prometheus treats an empty label as a missing one.
+			if matcher.IsEqual && !matcher.IsRegex && matcher.Value == "" {
+				return nil, fmt.Errorf("matcher %d: %s=\"\" only matches a missing label and can never match with requireLabelPresence", i, matcher.Name)
+			}
+		}
+	}
+
+	return &core.StoredSilenceState{
+		ID:        id,
+		Matchers:  matchers,
+		StartsAt:  startsAt.UTC(),
+		EndsAt:    endsAt.UTC(),
+		CreatedBy: in.CreatedBy,
+		Comment:   in.Comment,
+		UpdatedAt: now.UTC(),
+
+		NotifyOnExpiry: strings.TrimSpace(in.NotifyOnExpiry),
 
-	if len(in.Matchers) == 0 {
+		RequireLabelPresence: in.RequireLabelPresence,
+	}, nil
+}
+
+// ValidateSilenceMatchers applies the silence matcher rules used by
+// CreateOrUpdate and returns the matchers in API form (isEqual defaulted).
+func ValidateSilenceMatchers(in []core.SilenceMatcherInput) ([]core.APISilenceMatcher, error) {
+	matchers, err := normalizeSilenceMatchers(in)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]core.APISilenceMatcher, 0, len(matchers))
+	for _, m := range matchers {
+		out = append(out, core.APISilenceMatcher{Name: m.Name, Value: m.Value, IsRegex: m.IsRegex, IsEqual: m.IsEqual})
+	}
+	return out, nil
+}
+
+func normalizeSilenceMatchers(in []core.SilenceMatcherInput) ([]core.StoredSilenceMatcher, error) {
+	if len(in) == 0 {
 		return nil, fmt.Errorf("at least 1 matcher is required")
 	}
 
-	matchers := make([]core.StoredSilenceMatcher, 0, len(in.Matchers))
-	for i, matcher := range in.Matchers {
+	matchers := make([]core.StoredSilenceMatcher, 0, len(in))
+	for i, matcher := range in {
 		name := strings.TrimSpace(matcher.Name)
-		if name == "" {
-			return nil, fmt.Errorf("matcher %d: invalid label name", i)
+		if !silenceLabelNameRe.MatchString(name) {
+			return nil, fmt.Errorf("matcher %d: invalid label name %q", i, name)
 		}
 
 		isEqual := true
@@ -281,10 +616,6 @@ func normalizeSilenceInput(in *core.SilenceInput, now time.Time, allowPastEndsAt
 		}
 		value := matcher.Value
 
-		if strings.TrimSpace(value) == "" {
-			return nil, fmt.Errorf("at least one matcher must not match the empty string")
-		}
-
 		if matcher.IsRegex {
 			if _, err := regexp.Compile(value); err != nil {
 				return nil, fmt.Errorf("matcher %d: invalid regex: %w", i, err)
@@ -299,15 +630,18 @@ func normalizeSilenceInput(in *core.SilenceInput, now time.Time, allowPastEndsAt
 		})
 	}
 
-	return &core.StoredSilenceState{
-		ID:        id,
-		Matchers:  matchers,
-		StartsAt:  startsAt.UTC(),
-		EndsAt:    endsAt.UTC(),
-		CreatedBy: in.CreatedBy,
-		Comment:   in.Comment,
-		UpdatedAt: now.UTC(),
-	}, nil
+	allMatchEmpty := true
+	for _, matcher := range matchers {
+		if !matcherMatchesEmpty(matcher) {
+			allMatchEmpty = false
+			break
+		}
+	}
+	if allMatchEmpty {
+		return nil, fmt.Errorf("at least one matcher must not match the empty string")
+	}
+
+	return matchers, nil
 }
 
 func toAPISilence(in *core.StoredSilenceState, now time.Time) core.APISilence {
@@ -332,6 +666,9 @@ func toAPISilence(in *core.StoredSilenceState, now time.Time) core.APISilence {
 		Status: core.APISilenceStatus{
 			State: silenceState(in, now),
 		},
+		NotifyOnExpiry: in.NotifyOnExpiry,
+
+		RequireLabelPresence: in.RequireLabelPresence,
 	}
 }
 
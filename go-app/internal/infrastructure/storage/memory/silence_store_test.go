@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestSilenceStore_RequireLabelPresence(t *testing.T) {
+	store := NewSilenceStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	alertname := core.SilenceMatcherInput{Name: "alertname", Value: "HighCPU"}
+	notCanary := core.SilenceMatcherInput{Name: "env", Value: "canary", IsEqual: boolPtr(false)}
+
+	lenient, err := store.CreateOrUpdate(overlapInput(now, now.Add(time.Hour), alertname, notCanary), now)
+	require.NoError(t, err)
+	strictIn := overlapInput(now, now.Add(time.Hour), alertname, notCanary)
+	strictIn.RequireLabelPresence = true
+	strict, err := store.CreateOrUpdate(strictIn, now)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{lenient, strict}, store.ActiveMatchingSilenceIDs(map[string]string{"alertname": "HighCPU", "env": "prod"}, now))
+	assert.Equal(t, []string{lenient}, store.ActiveMatchingSilenceIDs(map[string]string{"alertname": "HighCPU"}, now))
+	assert.Equal(t, []string{lenient}, store.ActiveMatchingSilenceIDs(map[string]string{"alertname": "HighCPU", "env": ""}, now))
+
+	got, ok := store.Get(strict, now)
+	require.True(t, ok)
+	assert.True(t, got.RequireLabelPresence)
+}
+
+func TestSilenceStore_RequireLabelPresenceRejectsEmptyEquality(t *testing.T) {
+	store := NewSilenceStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+
+	in := overlapInput(now, now.Add(time.Hour),
+		core.SilenceMatcherInput{Name: "alertname", Value: "HighCPU"},
+		core.SilenceMatcherInput{Name: "team", Value: ""})
+	in.RequireLabelPresence = true
+	_, err := store.CreateOrUpdate(in, now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requireLabelPresence")
+
+	in.RequireLabelPresence = false
+	_, err = store.CreateOrUpdate(in, now)
+	assert.NoError(t, err)
+}
+
+func TestSilenceStore_RequireLabelPresenceChangeCreatesNewSilence(t *testing.T) {
+	store := NewSilenceStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	id := createTestSilence(t, store, "HighCPU", now)
+
+	in := overlapInput(now, now.Add(time.Hour), core.SilenceMatcherInput{Name: "alertname", Value: "HighCPU"})
+	in.ID = id
+	in.RequireLabelPresence = true
+	newID, err := store.CreateOrUpdate(in, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, newID)
+
+	old, ok := store.Get(id, now)
+	require.True(t, ok)
+	assert.Equal(t, "expired", old.Status.State)
+}
+
+func TestSilenceStore_OverlapsRequireLabelPresence(t *testing.T) {
+	store := NewSilenceStore()
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	alertname := core.SilenceMatcherInput{Name: "alertname", Value: "HighCPU"}
+	notCanary := core.SilenceMatcherInput{Name: "env", Value: "canary", IsEqual: boolPtr(false)}
+
+	strictIn := overlapInput(now, now.Add(time.Hour), alertname, notCanary)
+	strictIn.RequireLabelPresence = true
+	_, err := store.CreateOrUpdate(strictIn, now)
+	require.NoError(t, err)
+
+	// The lenient silence also matches alerts without env.
+	overlaps := store.Overlaps(overlapInput(now, now.Add(time.Hour), alertname, notCanary), now)
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, core.SilenceOverlapNarrower, overlaps[0].Relation)
+
+	// Pinning env to a value makes the label present.
+	overlaps = store.Overlaps(overlapInput(now, now.Add(time.Hour), alertname, core.SilenceMatcherInput{Name: "env", Value: "prod"}), now)
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, core.SilenceOverlapBroader, overlaps[0].Relation)
+}
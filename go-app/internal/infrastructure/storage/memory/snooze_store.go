@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+// maxSnoozeDuration caps a personal snooze; longer mutes should be silences.
+const maxSnoozeDuration = 7 * 24 * time.Hour
+
+// ErrSnoozeNotFound is returned when a user has no active snooze for a fingerprint.
+var ErrSnoozeNotFound = errors.New("snooze not found")
+
+// SnoozeStore holds personal snoozes: per-user mutes of an alert fingerprint
+// that suppress mentions of that user without silencing the alert for
+// everyone else.
+//
+// Thread-safe. Expired snoozes are dropped lazily.
+type SnoozeStore struct {
+	mu sync.RWMutex
+	// snoozes maps user -> fingerprint -> until
+	snoozes map[string]map[string]time.Time
+}
+
+func NewSnoozeStore() *SnoozeStore {
+	return &SnoozeStore{
+		snoozes: make(map[string]map[string]time.Time),
+	}
+}
+
+// Snooze validates in and mutes the fingerprint for the user until
+// now+duration, replacing any existing snooze. Returns the stored snooze.
+func (s *SnoozeStore) Snooze(in *core.SnoozeInput, now time.Time) (core.APISnooze, error) {
+	if in == nil {
+		return core.APISnooze{}, fmt.Errorf("snooze payload is required")
+	}
+	user := strings.TrimSpace(in.User)
+	if user == "" {
+		return core.APISnooze{}, fmt.Errorf("user is required")
+	}
+	fingerprint := strings.TrimSpace(in.Fingerprint)
+	if fingerprint == "" {
+		return core.APISnooze{}, fmt.Errorf("fingerprint is required")
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(in.Duration))
+	if err != nil {
+		return core.APISnooze{}, fmt.Errorf("invalid duration: %w", err)
+	}
+	if duration <= 0 || duration > maxSnoozeDuration {
+		return core.APISnooze{}, fmt.Errorf("duration must be between 0 and %s", maxSnoozeDuration)
+	}
+
+	until := now.Add(duration).UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byFingerprint, ok := s.snoozes[user]
+	if !ok {
+		byFingerprint = make(map[string]time.Time)
+		s.snoozes[user] = byFingerprint
+	}
+	pruneSnoozes(byFingerprint, now)
+	byFingerprint[fingerprint] = until
+
+	return core.APISnooze{User: user, Fingerprint: fingerprint, Until: until.Format(time.RFC3339)}, nil
+}
+
+// Unsnooze removes the user's snooze for fingerprint.
+func (s *SnoozeStore) Unsnooze(user, fingerprint string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.snoozes[user][fingerprint]
+	if !ok {
+		return ErrSnoozeNotFound
+	}
+	delete(s.snoozes[user], fingerprint)
+	if len(s.snoozes[user]) == 0 {
+		delete(s.snoozes, user)
+	}
+	if !until.After(now) {
+		return ErrSnoozeNotFound
+	}
+	return nil
+}
+
+// List returns the user's active snoozes ordered by expiry.
+func (s *SnoozeStore) List(user string, now time.Time) []core.APISnooze {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]core.APISnooze, 0)
+	for fingerprint, until := range s.snoozes[user] {
+		if until.After(now) {
+			out = append(out, core.APISnooze{User: user, Fingerprint: fingerprint, Until: until.Format(time.RFC3339)})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Until != out[j].Until {
+			return out[i].Until < out[j].Until
+		}
+		return out[i].Fingerprint < out[j].Fingerprint
+	})
+	return out
+}
+
+// IsSnoozed implements core.SnoozeChecker.
+func (s *SnoozeStore) IsSnoozed(user, fingerprint string, now time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	until, ok := s.snoozes[user][fingerprint]
+	return ok && until.After(now)
+}
+
+func pruneSnoozes(byFingerprint map[string]time.Time, now time.Time) {
+	for fingerprint, until := range byFingerprint {
+		if !until.After(now) {
+			delete(byFingerprint, fingerprint)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ipiton/AMP/internal/core"
+)
+
+func TestSnoozeStore_IsPerUserAndExpires(t *testing.T) {
+	store := NewSnoozeStore()
+	now := time.Now()
+
+	if _, err := store.Snooze(&core.SnoozeInput{User: "U1", Fingerprint: "fp", Duration: "1h"}, now); err != nil {
+		t.Fatalf("Snooze() error = %v", err)
+	}
+
+	if !store.IsSnoozed("U1", "fp", now.Add(30*time.Minute)) {
+		t.Error("expected U1 to be snoozed for fp")
+	}
+	if store.IsSnoozed("U2", "fp", now) {
+		t.Error("snooze must not apply to other users")
+	}
+	if store.IsSnoozed("U1", "other", now) {
+		t.Error("snooze must not apply to other fingerprints")
+	}
+	if store.IsSnoozed("U1", "fp", now.Add(time.Hour)) {
+		t.Error("snooze must expire after its duration")
+	}
+	if got := store.List("U1", now.Add(2*time.Hour)); len(got) != 0 {
+		t.Errorf("expected no active snoozes, got %+v", got)
+	}
+}
+
+func TestSnoozeStore_Unsnooze(t *testing.T) {
+	store := NewSnoozeStore()
+	now := time.Now()
+
+	if _, err := store.Snooze(&core.SnoozeInput{User: "U1", Fingerprint: "fp", Duration: "1h"}, now); err != nil {
+		t.Fatalf("Snooze() error = %v", err)
+	}
+	if got := store.List("U1", now); len(got) != 1 || got[0].Fingerprint != "fp" {
+		t.Fatalf("unexpected list: %+v", got)
+	}
+
+	if err := store.Unsnooze("U1", "fp", now); err != nil {
+		t.Fatalf("Unsnooze() error = %v", err)
+	}
+	if store.IsSnoozed("U1", "fp", now) {
+		t.Error("expected snooze to be removed")
+	}
+	if err := store.Unsnooze("U1", "fp", now); !errors.Is(err, ErrSnoozeNotFound) {
+		t.Errorf("second Unsnooze() error = %v, want ErrSnoozeNotFound", err)
+	}
+}
+
+func TestSnoozeStore_Validation(t *testing.T) {
+	store := NewSnoozeStore()
+	for _, in := range []core.SnoozeInput{
+		{Fingerprint: "fp", Duration: "1h"},
+		{User: "U1", Duration: "1h"},
+		{User: "U1", Fingerprint: "fp", Duration: "soon"},
+		{User: "U1", Fingerprint: "fp", Duration: "-1h"},
+		{User: "U1", Fingerprint: "fp", Duration: "192h"},
+	} {
+		if _, err := store.Snooze(&in, time.Now()); err == nil {
+			t.Errorf("Snooze(%+v) expected error", in)
+		}
+	}
+}
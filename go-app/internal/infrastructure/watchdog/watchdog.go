@@ -0,0 +1,377 @@
+// Package watchdog detects goroutine, file descriptor and worker leaks.
+//
+// The watchdog periodically samples runtime resources and per-subsystem worker
+// heartbeats, exports them as Prometheus metrics and raises meta-alerts
+// (alerts about AMP itself) when configured thresholds are crossed.
+//
+// Typical leaks caught early:
+//   - unclosed SSE / WebSocket streams (goroutines and FDs grow unbounded)
+//   - HTTP response bodies that are never closed (FDs grow)
+//   - background workers that died or deadlocked (heartbeats stop)
+//
+// Usage:
+//
+//	wd := watchdog.New(watchdog.Config{
+//	    GoroutineThreshold: 10000,
+//	    Sink:               alertProcessor,
+//	})
+//	wd.RegisterSubsystem("publishing_queue", time.Minute)
+//	wd.Start(ctx)
+//	defer wd.Stop()
+//
+//	// in the worker loop
+//	wd.Heartbeat("publishing_queue")
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+	"github.com/ipiton/AMP/pkg/core/domain"
+)
+
+// Meta-alert names raised by the watchdog.
+const (
+	AlertGoroutineLeak  = "AMPGoroutineLeak"
+	AlertFDLeak         = "AMPFileDescriptorLeak"
+	AlertWorkerStalled  = "AMPWorkerStalled"
+	metaAlertComponent  = "amp-watchdog"
+	metricsSubsystem    = "watchdog"
+	metricsNamespace    = "alert_history"
+	defaultInterval     = 15 * time.Second
+	defaultStaleAfter   = 2 * time.Minute
+	unavailableFDSample = -1
+)
+
+// AlertSink receives meta-alerts. *services.AlertProcessor satisfies it.
+type AlertSink interface {
+	ProcessAlert(ctx context.Context, alert *core.Alert) error
+}
+
+// Config configures the Watchdog.
+type Config struct {
+	// Interval between samples (default: 15s).
+	Interval time.Duration
+
+	// GoroutineThreshold raises AMPGoroutineLeak when exceeded (0 = disabled).
+	GoroutineThreshold int
+
+	// FDThreshold raises AMPFileDescriptorLeak when exceeded (0 = disabled).
+	FDThreshold int
+
+	// Sink receives meta-alerts (nil = metrics and logs only).
+	Sink AlertSink
+
+	// Registerer for metrics (default: prometheus.DefaultRegisterer).
+	Registerer prometheus.Registerer
+
+	// Logger (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Clock (default: clock.Real()).
+	Clock clock.Clock
+
+	// Goroutines and OpenFDs override resource sampling (for tests).
+	Goroutines func() int
+	OpenFDs    func() int
+}
+
+// subsystem tracks heartbeats of a registered worker pool.
+type subsystem struct {
+	staleAfter    time.Duration
+	lastHeartbeat time.Time
+}
+
+// Watchdog samples resources and worker liveness.
+//
+// Thread-safe: Heartbeat may be called concurrently from any goroutine.
+type Watchdog struct {
+	config Config
+	logger *slog.Logger
+	clock  clock.Clock
+
+	mu         sync.Mutex
+	subsystems map[string]*subsystem
+	firing     map[string]*core.Alert // keyed by fingerprint
+
+	goroutinesGauge prometheus.Gauge
+	openFDsGauge    prometheus.Gauge
+	heartbeatAge    *prometheus.GaugeVec
+	workerAlive     *prometheus.GaugeVec
+	metaAlerts      *prometheus.CounterVec
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a Watchdog and registers its metrics.
+func New(config Config) *Watchdog {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.Registerer == nil {
+		config.Registerer = prometheus.DefaultRegisterer
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.Goroutines == nil {
+		config.Goroutines = countGoroutines
+	}
+	if config.OpenFDs == nil {
+		config.OpenFDs = countOpenFDs
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	w := &Watchdog{
+		config:     config,
+		logger:     config.Logger.With("component", "watchdog"),
+		clock:      config.Clock,
+		subsystems: make(map[string]*subsystem),
+		firing:     make(map[string]*core.Alert),
+		goroutinesGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "goroutines",
+			Help:      "Number of goroutines at the last watchdog sample",
+		}),
+		openFDsGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "open_fds",
+			Help:      "Number of open file descriptors at the last watchdog sample (-1 if unavailable)",
+		}),
+		heartbeatAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "heartbeat_age_seconds",
+			Help:      "Seconds since the last heartbeat of a subsystem worker",
+		}, []string{"subsystem"}),
+		workerAlive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "worker_alive",
+			Help:      "1 if the subsystem heartbeat is fresh, 0 if stalled",
+		}, []string{"subsystem"}),
+		metaAlerts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "meta_alerts_total",
+			Help:      "Total meta-alerts raised by the watchdog",
+		}, []string{"alertname", "status"}),
+	}
+
+	config.Registerer.MustRegister(
+		w.goroutinesGauge,
+		w.openFDsGauge,
+		w.heartbeatAge,
+		w.workerAlive,
+		w.metaAlerts,
+	)
+
+	return w
+}
+
+// RegisterSubsystem starts tracking worker liveness for name.
+//
+// The subsystem is considered stalled when no Heartbeat arrives within
+// staleAfter (default: 2m). Registering counts as the first heartbeat.
+func (w *Watchdog) RegisterSubsystem(name string, staleAfter time.Duration) {
+	if staleAfter <= 0 {
+		staleAfter = defaultStaleAfter
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subsystems[name] = &subsystem{
+		staleAfter:    staleAfter,
+		lastHeartbeat: w.clock.Now(),
+	}
+}
+
+// Heartbeat records that a worker of the named subsystem is alive.
+// Heartbeats for unregistered subsystems are ignored.
+func (w *Watchdog) Heartbeat(name string) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s, ok := w.subsystems[name]; ok {
+		s.lastHeartbeat = w.clock.Now()
+	}
+}
+
+// Start runs the sampling loop until ctx is cancelled or Stop is called.
+func (w *Watchdog) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := w.clock.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.Check(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				w.Check(ctx)
+			}
+		}
+	}()
+
+	w.logger.Info("Watchdog started",
+		"interval", w.config.Interval,
+		"goroutine_threshold", w.config.GoroutineThreshold,
+		"fd_threshold", w.config.FDThreshold,
+	)
+}
+
+// Stop stops the sampling loop and waits for it to exit.
+func (w *Watchdog) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// Check takes one sample, updates metrics and fires/resolves meta-alerts.
+func (w *Watchdog) Check(ctx context.Context) {
+	goroutines := w.config.Goroutines()
+	w.goroutinesGauge.Set(float64(goroutines))
+	w.evaluate(ctx, AlertGoroutineLeak, "", w.config.GoroutineThreshold > 0 && goroutines > w.config.GoroutineThreshold,
+		fmt.Sprintf("%d goroutines exceed threshold %d", goroutines, w.config.GoroutineThreshold))
+
+	fds := w.config.OpenFDs()
+	w.openFDsGauge.Set(float64(fds))
+	w.evaluate(ctx, AlertFDLeak, "", w.config.FDThreshold > 0 && fds > w.config.FDThreshold,
+		fmt.Sprintf("%d open file descriptors exceed threshold %d", fds, w.config.FDThreshold))
+
+	now := w.clock.Now()
+	for _, name := range w.subsystemNames() {
+		w.mu.Lock()
+		s := w.subsystems[name]
+		age := now.Sub(s.lastHeartbeat)
+		stale := age > s.staleAfter
+		staleAfter := s.staleAfter
+		w.mu.Unlock()
+
+		w.heartbeatAge.WithLabelValues(name).Set(age.Seconds())
+		alive := 1.0
+		if stale {
+			alive = 0
+		}
+		w.workerAlive.WithLabelValues(name).Set(alive)
+
+		w.evaluate(ctx, AlertWorkerStalled, name, stale,
+			fmt.Sprintf("no heartbeat from %s for %s (stale after %s)", name, age.Truncate(time.Second), staleAfter))
+	}
+}
+
+// evaluate fires a meta-alert on a false→true transition and resolves it on true→false.
+func (w *Watchdog) evaluate(ctx context.Context, alertName, subsystemName string, breached bool, summary string) {
+	labels := map[string]string{
+		"alertname": alertName,
+		"severity":  "warning",
+		"component": metaAlertComponent,
+	}
+	if subsystemName != "" {
+		labels["subsystem"] = subsystemName
+	}
+	fingerprint := domain.LabelsFingerprint(labels)
+
+	w.mu.Lock()
+	active, isFiring := w.firing[fingerprint]
+	var alert *core.Alert
+	switch {
+	case breached && !isFiring:
+		alert = &core.Alert{
+			Fingerprint: fingerprint,
+			AlertName:   alertName,
+			Status:      core.StatusFiring,
+			Labels:      labels,
+			Annotations: map[string]string{"summary": summary},
+			StartsAt:    w.clock.Now(),
+		}
+		w.firing[fingerprint] = alert
+	case !breached && isFiring:
+		endsAt := w.clock.Now()
+		resolved := *active
+		resolved.Status = core.StatusResolved
+		resolved.EndsAt = &endsAt
+		alert = &resolved
+		delete(w.firing, fingerprint)
+	}
+	w.mu.Unlock()
+
+	if alert == nil {
+		return
+	}
+
+	w.metaAlerts.WithLabelValues(alertName, string(alert.Status)).Inc()
+	if alert.Status == core.StatusFiring {
+		w.logger.Warn("Watchdog threshold breached", "alertname", alertName, "subsystem", subsystemName, "summary", summary)
+	} else {
+		w.logger.Info("Watchdog threshold recovered", "alertname", alertName, "subsystem", subsystemName)
+	}
+
+	if w.config.Sink != nil {
+		if err := w.config.Sink.ProcessAlert(ctx, alert); err != nil {
+			w.logger.Error("Failed to send meta-alert", "alertname", alertName, "error", err)
+		}
+	}
+}
+
+// FiringAlerts returns currently firing meta-alerts.
+func (w *Watchdog) FiringAlerts() []core.Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	alerts := make([]core.Alert, 0, len(w.firing))
+	for _, a := range w.firing {
+		alerts = append(alerts, *a)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Fingerprint < alerts[j].Fingerprint })
+	return alerts
+}
+
+func (w *Watchdog) subsystemNames() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	names := make([]string, 0, len(w.subsystems))
+	for name := range w.subsystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func countGoroutines() int {
+	return runtime.NumGoroutine()
+}
+
+// countOpenFDs counts entries in /proc/self/fd.
+// Returns -1 on platforms without procfs.
+func countOpenFDs() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return unavailableFDSample
+	}
+	return len(entries)
+}
@@ -0,0 +1,157 @@
+package watchdog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	alerts []core.Alert
+}
+
+func (s *recordingSink) ProcessAlert(_ context.Context, alert *core.Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts = append(s.alerts, *alert)
+	return nil
+}
+
+func (s *recordingSink) snapshot() []core.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]core.Alert(nil), s.alerts...)
+}
+
+func newTestWatchdog(t *testing.T, config Config) (*Watchdog, *recordingSink, *clock.Fake) {
+	t.Helper()
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	sink := &recordingSink{}
+	config.Sink = sink
+	config.Clock = fake
+	config.Registerer = prometheus.NewRegistry()
+	return New(config), sink, fake
+}
+
+func TestWatchdog_GoroutineThreshold(t *testing.T) {
+	var goroutines atomic.Int64
+	goroutines.Store(100)
+
+	wd, sink, _ := newTestWatchdog(t, Config{
+		GoroutineThreshold: 500,
+		Goroutines:         func() int { return int(goroutines.Load()) },
+		OpenFDs:            func() int { return 10 },
+	})
+	ctx := context.Background()
+
+	wd.Check(ctx)
+	assert.Empty(t, sink.snapshot())
+	assert.Equal(t, 100.0, testutil.ToFloat64(wd.goroutinesGauge))
+
+	goroutines.Store(1000)
+	wd.Check(ctx)
+	wd.Check(ctx) // still breached: no duplicate meta-alert
+	alerts := sink.snapshot()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertGoroutineLeak, alerts[0].AlertName)
+	assert.Equal(t, core.StatusFiring, alerts[0].Status)
+	assert.Equal(t, metaAlertComponent, alerts[0].Labels["component"])
+	assert.Len(t, wd.FiringAlerts(), 1)
+
+	goroutines.Store(100)
+	wd.Check(ctx)
+	alerts = sink.snapshot()
+	require.Len(t, alerts, 2)
+	assert.Equal(t, core.StatusResolved, alerts[1].Status)
+	assert.Equal(t, alerts[0].Fingerprint, alerts[1].Fingerprint)
+	require.NotNil(t, alerts[1].EndsAt)
+	assert.Empty(t, wd.FiringAlerts())
+}
+
+func TestWatchdog_FDThreshold(t *testing.T) {
+	wd, sink, _ := newTestWatchdog(t, Config{
+		FDThreshold: 100,
+		Goroutines:  func() int { return 1 },
+		OpenFDs:     func() int { return 200 },
+	})
+
+	wd.Check(context.Background())
+
+	alerts := sink.snapshot()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertFDLeak, alerts[0].AlertName)
+	assert.Equal(t, 200.0, testutil.ToFloat64(wd.openFDsGauge))
+}
+
+func TestWatchdog_ThresholdsDisabled(t *testing.T) {
+	wd, sink, _ := newTestWatchdog(t, Config{
+		Goroutines: func() int { return 1 << 20 },
+		OpenFDs:    func() int { return 1 << 20 },
+	})
+
+	wd.Check(context.Background())
+	assert.Empty(t, sink.snapshot())
+}
+
+func TestWatchdog_WorkerStalled(t *testing.T) {
+	wd, sink, fake := newTestWatchdog(t, Config{
+		Goroutines: func() int { return 1 },
+		OpenFDs:    func() int { return 1 },
+	})
+	ctx := context.Background()
+
+	wd.RegisterSubsystem("publishing_queue", time.Minute)
+	wd.Heartbeat("unknown") // ignored
+
+	fake.Advance(30 * time.Second)
+	wd.Check(ctx)
+	assert.Empty(t, sink.snapshot())
+	assert.Equal(t, 1.0, testutil.ToFloat64(wd.workerAlive.WithLabelValues("publishing_queue")))
+
+	fake.Advance(time.Minute)
+	wd.Check(ctx)
+	alerts := sink.snapshot()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertWorkerStalled, alerts[0].AlertName)
+	assert.Equal(t, "publishing_queue", alerts[0].Labels["subsystem"])
+	assert.Equal(t, 0.0, testutil.ToFloat64(wd.workerAlive.WithLabelValues("publishing_queue")))
+	assert.Equal(t, 90.0, testutil.ToFloat64(wd.heartbeatAge.WithLabelValues("publishing_queue")))
+
+	wd.Heartbeat("publishing_queue")
+	wd.Check(ctx)
+	alerts = sink.snapshot()
+	require.Len(t, alerts, 2)
+	assert.Equal(t, core.StatusResolved, alerts[1].Status)
+}
+
+func TestWatchdog_StartStop(t *testing.T) {
+	var samples atomic.Int64
+	wd, _, fake := newTestWatchdog(t, Config{
+		Interval:   time.Second,
+		Goroutines: func() int { samples.Add(1); return 1 },
+		OpenFDs:    func() int { return 1 },
+	})
+
+	wd.Start(context.Background())
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+
+	assert.Eventually(t, func() bool { return samples.Load() >= 2 }, time.Second, time.Millisecond)
+	wd.Stop()
+}
+
+func TestWatchdog_NilHeartbeat(t *testing.T) {
+	var wd *Watchdog
+	assert.NotPanics(t, func() { wd.Heartbeat("any") })
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
 )
 
 // WebhookParser defines the interface for parsing webhook payloads.
@@ -23,8 +24,44 @@ type WebhookParser interface {
 	ConvertToDomain(webhook *AlertmanagerWebhook) ([]*core.Alert, error)
 }
 
+// ParserOption configures a WebhookParser.
+type ParserOption func(*parserOptions)
+
+// parserOptions holds settings shared by all parsers.
+type parserOptions struct {
+	clockSkewTolerance time.Duration
+	clock              clock.Clock
+}
+
+// WithClockSkewTolerance sets how much sender clock skew is absorbed during
+// conversion (default: core.DefaultClockSkewTolerance, 0 = UTC conversion only).
+func WithClockSkewTolerance(d time.Duration) ParserOption {
+	return func(o *parserOptions) {
+		o.clockSkewTolerance = d
+	}
+}
+
+// WithClock sets the clock used for receive timestamps (default: clock.Real()).
+func WithClock(c clock.Clock) ParserOption {
+	return func(o *parserOptions) {
+		o.clock = clock.OrReal(c)
+	}
+}
+
+func newParserOptions(opts []ParserOption) parserOptions {
+	o := parserOptions{
+		clockSkewTolerance: core.DefaultClockSkewTolerance,
+		clock:              clock.Real(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
 // alertmanagerParser implements WebhookParser for Alertmanager webhooks.
 type alertmanagerParser struct {
+	parserOptions
 	validator WebhookValidator
 }
 
@@ -32,9 +69,11 @@ type alertmanagerParser struct {
 //
 // Returns:
 //   - WebhookParser: Initialized parser with validator
-func NewAlertmanagerParser() WebhookParser {
+func NewAlertmanagerParser(opts ...ParserOption) WebhookParser {
+	o := newParserOptions(opts)
 	return &alertmanagerParser{
-		validator: NewWebhookValidator(),
+		parserOptions: o,
+		validator:     newWebhookValidator(o.clockSkewTolerance),
 	}
 }
 
@@ -76,7 +115,8 @@ func (p *alertmanagerParser) Validate(webhook *AlertmanagerWebhook) *ValidationR
 //   - Extracts "alertname" from labels → AlertName field
 //   - Maps "status" string → core.AlertStatus type
 //   - Generates fingerprint if missing (based on alertname + labels)
-//   - Converts timestamps (StartsAt required, EndsAt optional)
+//   - Converts timestamps (StartsAt required, EndsAt optional) to UTC,
+//     absorbing small sender clock skew (see core.Alert.NormalizeTimestamps)
 //   - Maps GeneratorURL as pointer
 //
 // Parameters:
@@ -145,9 +185,9 @@ func (p *alertmanagerParser) convertSingleAlert(amAlert *AlertmanagerAlert, inde
 	}
 
 	// Set timestamp to current time
-	now := time.Now()
+	now := p.clock.Now()
 
-	return &core.Alert{
+	alert := &core.Alert{
 		Fingerprint:  fingerprint,
 		AlertName:    alertName,
 		Status:       status,
@@ -157,7 +197,10 @@ func (p *alertmanagerParser) convertSingleAlert(amAlert *AlertmanagerAlert, inde
 		EndsAt:       endsAt,
 		GeneratorURL: generatorURL,
 		Timestamp:    &now,
-	}, nil
+	}
+	alert.NormalizeTimestamps(now, p.clockSkewTolerance)
+
+	return alert, nil
 }
 
 // mapAlertStatus maps Alertmanager status string to core.AlertStatus.
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"testing"
+)
+
+// webhookFuzzSeeds are realistic and hostile payloads for the ingestion fuzzers.
+var webhookFuzzSeeds = []string{
+	`{"version":"4","groupKey":"g","status":"firing","receiver":"r","alerts":[{"status":"firing","labels":{"alertname":"HighCPU"},"startsAt":"2025-01-01T00:00:00Z"}]}`,
+	`{"alerts":[{"status":"resolved","labels":{"alertname":"X"},"startsAt":"2025-01-01T00:00:00Z","endsAt":"2024-01-01T00:00:00Z"}]}`,
+	`[{"labels":{"alertname":"Prom"},"annotations":{"summary":"s"},"state":"firing","activeAt":"2025-01-01T00:00:00Z","value":"1"}]`,
+	`{"data":{"groups":[{"name":"g","file":"f","rules":[{"alerts":[{"labels":{"alertname":"P"},"state":"pending","activeAt":"2025-01-01T00:00:00Z"}]}]}]}}`,
+	`{"alerts":[{"status":"firing","labels":{"alertname":"‮\u0000🔥"},"annotations":{"summary":"\xff\xfe"},"startsAt":"0001-01-01T00:00:00Z"}]}`,
+	`{"alerts":[{"labels":null}]}`,
+	`[]`,
+	`null`,
+	``,
+}
+
+// FuzzAlertmanagerParser ensures arbitrary payloads never panic the Alertmanager parser.
+func FuzzAlertmanagerParser(f *testing.F) {
+	for _, seed := range webhookFuzzSeeds {
+		f.Add([]byte(seed))
+	}
+
+	parser := NewAlertmanagerParser()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		webhook, err := parser.Parse(data)
+		if err != nil {
+			return
+		}
+		if !parser.Validate(webhook).Valid {
+			return
+		}
+		alerts, err := parser.ConvertToDomain(webhook)
+		if err != nil {
+			return
+		}
+		for _, alert := range alerts {
+			if alert.Fingerprint == "" || alert.AlertName == "" {
+				t.Fatalf("converted alert missing identity: %+v", alert)
+			}
+		}
+	})
+}
+
+// FuzzPrometheusParser ensures arbitrary payloads never panic the Prometheus parser.
+func FuzzPrometheusParser(f *testing.F) {
+	for _, seed := range webhookFuzzSeeds {
+		f.Add([]byte(seed))
+	}
+
+	parser := NewPrometheusParser("http://amp.example.com")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		webhook, err := parser.Parse(data)
+		if err != nil {
+			return
+		}
+		if !parser.Validate(webhook).Valid {
+			return
+		}
+		_, _ = parser.ConvertToDomain(webhook)
+	})
+}
+
+// FuzzWebhookDetector ensures format detection never panics.
+func FuzzWebhookDetector(f *testing.F) {
+	for _, seed := range webhookFuzzSeeds {
+		f.Add([]byte(seed))
+	}
+
+	detector := NewWebhookDetector()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = detector.Detect(data)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ipiton/AMP/internal/core"
+	"github.com/ipiton/AMP/pkg/clock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -607,3 +608,60 @@ func BenchmarkGenerateFingerprint(b *testing.B) {
 		_ = generateFingerprint("TestAlert", labels)
 	}
 }
+
+func TestConvertToDomain_NormalizesTimestampsAndClockSkew(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	parser := NewAlertmanagerParser(WithClock(clock.NewFake(now)))
+	plus2 := time.FixedZone("UTC+2", 2*60*60)
+
+	webhook := &AlertmanagerWebhook{
+		Alerts: []AlertmanagerAlert{
+			{
+				// Sender clock is 10s ahead and reports a local time zone
+				Status:   "firing",
+				Labels:   map[string]string{"alertname": "SkewedClock"},
+				StartsAt: now.Add(10 * time.Second).In(plus2),
+			},
+			{
+				// Far future StartsAt is not skew and is kept as-is
+				Status:   "firing",
+				Labels:   map[string]string{"alertname": "FutureAlert"},
+				StartsAt: now.Add(time.Hour),
+			},
+		},
+	}
+
+	alerts, err := parser.ConvertToDomain(webhook)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+
+	assert.Equal(t, now, alerts[0].StartsAt)
+	assert.Equal(t, time.UTC, alerts[0].StartsAt.Location())
+	assert.Equal(t, now, *alerts[0].Timestamp)
+	assert.Equal(t, now.Add(time.Hour), alerts[1].StartsAt)
+}
+
+func TestValidate_EndsAtBeforeStartsAtWithinSkew(t *testing.T) {
+	startsAt := time.Now().Add(-time.Minute)
+	newWebhook := func(endsAt time.Time) *AlertmanagerWebhook {
+		return &AlertmanagerWebhook{
+			Version:  "4",
+			GroupKey: "test",
+			Status:   "resolved",
+			Receiver: "default",
+			Alerts: []AlertmanagerAlert{{
+				Status:   "resolved",
+				Labels:   map[string]string{"alertname": "Skew"},
+				StartsAt: startsAt,
+				EndsAt:   endsAt,
+			}},
+		}
+	}
+
+	parser := NewAlertmanagerParser()
+	assert.True(t, parser.Validate(newWebhook(startsAt.Add(-2*time.Second))).Valid, "small skew is tolerated")
+	assert.False(t, parser.Validate(newWebhook(startsAt.Add(-time.Hour))).Valid, "large inversion is rejected")
+
+	strict := NewAlertmanagerParser(WithClockSkewTolerance(0))
+	assert.False(t, strict.Validate(newWebhook(startsAt.Add(-2*time.Second))).Valid)
+}
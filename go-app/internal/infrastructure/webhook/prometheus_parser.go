@@ -29,6 +29,7 @@ import (
 //   - Parse 100 alerts: < 1ms (target)
 //   - Zero allocations in hot path
 type prometheusParser struct {
+	parserOptions
 	validator      WebhookValidator
 	formatDetector PrometheusFormatDetector
 	externalURL    string
@@ -45,9 +46,11 @@ type prometheusParser struct {
 //
 // Returns:
 //   - WebhookParser: Initialized parser ready for use
-func NewPrometheusParser(externalURL string) WebhookParser {
+func NewPrometheusParser(externalURL string, opts ...ParserOption) WebhookParser {
+	o := newParserOptions(opts)
 	return &prometheusParser{
-		validator:      NewWebhookValidator(),
+		parserOptions:  o,
+		validator:      newWebhookValidator(o.clockSkewTolerance),
 		formatDetector: NewPrometheusFormatDetector(),
 		externalURL:    externalURL,
 	}
@@ -296,9 +299,9 @@ func (p *prometheusParser) convertSingleAlert(amAlert *AlertmanagerAlert, index
 	}
 
 	// Set timestamp to current time
-	now := time.Now()
+	now := p.clock.Now()
 
-	return &core.Alert{
+	alert := &core.Alert{
 		Fingerprint:  fingerprint,
 		AlertName:    alertName,
 		Status:       status,
@@ -308,7 +311,10 @@ func (p *prometheusParser) convertSingleAlert(amAlert *AlertmanagerAlert, index
 		EndsAt:       endsAt,
 		GeneratorURL: generatorURL,
 		Timestamp:    &now,
-	}, nil
+	}
+	alert.NormalizeTimestamps(now, p.clockSkewTolerance)
+
+	return alert, nil
 }
 
 // mapPrometheusState maps Prometheus state to Alertmanager status.
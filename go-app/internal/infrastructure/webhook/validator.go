@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/ipiton/AMP/internal/core"
 )
 
 // ValidationError represents a single validation error for a webhook field.
@@ -39,10 +41,18 @@ type WebhookValidator interface {
 // webhookValidator implements WebhookValidator using go-playground/validator.
 type webhookValidator struct {
 	validate *validator.Validate
+
+	// clockSkewTolerance allows endsAt slightly before startsAt (sender clock skew).
+	clockSkewTolerance time.Duration
 }
 
 // NewWebhookValidator creates a new webhook validator with custom validation rules.
 func NewWebhookValidator() WebhookValidator {
+	return newWebhookValidator(core.DefaultClockSkewTolerance)
+}
+
+// newWebhookValidator creates a validator with the given clock skew tolerance.
+func newWebhookValidator(clockSkewTolerance time.Duration) *webhookValidator {
 	v := validator.New()
 
 	// Register custom validation functions
@@ -52,7 +62,8 @@ func NewWebhookValidator() WebhookValidator {
 	_ = v.RegisterValidation("webhook_status", validateWebhookStatus)
 
 	return &webhookValidator{
-		validate: v,
+		validate:           v,
+		clockSkewTolerance: clockSkewTolerance,
 	}
 }
 
@@ -200,7 +211,7 @@ func (v *webhookValidator) validateAlert(alert *AlertmanagerAlert, index int) []
 
 	// Validate timestamps
 	if !alert.StartsAt.IsZero() && !alert.EndsAt.IsZero() {
-		if alert.EndsAt.Before(alert.StartsAt) {
+		if alert.StartsAt.Sub(alert.EndsAt) > v.clockSkewTolerance {
 			errors = append(errors, &ValidationError{
 				Field:   fmt.Sprintf("%s.endsAt", prefix),
 				Message: "endsAt cannot be before startsAt",
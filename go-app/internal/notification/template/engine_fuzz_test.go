@@ -0,0 +1,50 @@
+package template
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// FuzzExecute ensures that hostile templates and alert data never panic the engine.
+//
+// Label and annotation values come from external alert sources and often contain
+// exotic Unicode (RTL overrides, ZWJ emoji, invalid UTF-8), so they are fed
+// through the built-in string functions as well.
+func FuzzExecute(f *testing.F) {
+	seeds := []struct{ tmpl, value string }{
+		{"{{ .Labels.alertname }}", "HighCPU"},
+		{"{{ .Annotations.summary | toUpper }}", "İstanbul ß"},
+		{"{{ .Annotations.summary | title }}", "‮evil‬"},
+		{"{{ truncate 5 .Annotations.summary }}", "\U0001F468‍\U0001F469‍\U0001F467"},
+		{"{{ .Annotations.summary | reReplaceAll \"(.)\" \"$1$1\" }}", "\xff\xfe\xfd"},
+		{"{{ .Annotations.summary | safeHtml }}", "<script>\u0000</script>"},
+		{"{{ range .Labels }}{{ . }}{{ end }}", "\t\n"},
+		{"{{ template \"missing\" }}", ""},
+		{"{{ .Nope.Nested.Field }}", ""},
+		{"{{", ""},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.tmpl, seed.value)
+	}
+
+	opts := DefaultTemplateEngineOptions()
+	opts.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	opts.ExecutionTimeout = time.Second
+	engine, err := NewNotificationTemplateEngine(opts)
+	if err != nil {
+		f.Fatalf("failed to create engine: %v", err)
+	}
+
+	startsAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, tmpl, value string) {
+		data := NewTemplateData("firing",
+			map[string]string{"alertname": value, "severity": "critical"},
+			map[string]string{"summary": value, "description": value},
+			startsAt)
+
+		_, _ = engine.Execute(context.Background(), tmpl, data)
+	})
+}
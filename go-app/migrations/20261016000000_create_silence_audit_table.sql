@@ -0,0 +1,41 @@
+-- +goose Up
+-- Append-only change history of silences (who created, updated or expired
+-- a silence, when, and the before/after state).
+CREATE TABLE IF NOT EXISTS silence_audit (
+    id           BIGSERIAL PRIMARY KEY,
+    -- No foreign key: the history outlives deleted silences.
+    silence_id   UUID NOT NULL,
+    action       VARCHAR(16) NOT NULL,
+    actor        VARCHAR(255) NOT NULL DEFAULT '',
+    reason       TEXT NOT NULL DEFAULT '',
+    occurred_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    before_state JSONB,
+    after_state  JSONB,
+    changes      JSONB,
+
+    CONSTRAINT silence_audit_valid_action CHECK (
+        action IN ('create', 'update', 'expire', 'delete')
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_silence_audit_silence_id ON silence_audit (silence_id, id);
+CREATE INDEX IF NOT EXISTS idx_silence_audit_occurred_at ON silence_audit (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_silence_audit_actor ON silence_audit (actor);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION silence_audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'silence_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS silence_audit_append_only ON silence_audit;
+CREATE TRIGGER silence_audit_append_only
+    BEFORE UPDATE OR DELETE ON silence_audit
+    FOR EACH ROW EXECUTE FUNCTION silence_audit_append_only();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS silence_audit_append_only ON silence_audit;
+DROP FUNCTION IF EXISTS silence_audit_append_only();
+DROP TABLE IF EXISTS silence_audit;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- When set, a silence matcher never matches a missing or empty label
+-- (Alertmanager treats a missing label as an empty value).
+ALTER TABLE silences
+    ADD COLUMN IF NOT EXISTS require_label_presence BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE silences
+    DROP COLUMN IF EXISTS require_label_presence;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Hourly and daily alert counts by dimension (total, severity, alertname,
+-- team), maintained by the alert rollup job for the dashboard and stats API.
+CREATE TABLE IF NOT EXISTS alert_rollups (
+    granularity  VARCHAR(8) NOT NULL,
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    dimension    VARCHAR(32) NOT NULL,
+    -- Empty for alerts without the label (and for the total).
+    value        TEXT NOT NULL DEFAULT '',
+    count        BIGINT NOT NULL DEFAULT 0,
+    computed_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (granularity, bucket_start, dimension, value),
+    CONSTRAINT alert_rollups_valid_granularity CHECK (
+        granularity IN ('hour', 'day')
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rollups_dimension ON alert_rollups (granularity, dimension, bucket_start);
+
+-- +goose Down
+DROP TABLE IF EXISTS alert_rollups;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Jobs of the durable publishing queue (publishing.queue.durable.backend:
+-- postgres). A job is leased to the replica holding it until lease_until and
+-- deleted once processed; jobs with an expired lease are claimed again.
+CREATE TABLE IF NOT EXISTS publishing_queue_jobs (
+    id          VARCHAR(64) PRIMARY KEY,
+    owner       VARCHAR(255) NOT NULL,
+    lease_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    target_name VARCHAR(255) NOT NULL,
+    priority    VARCHAR(16) NOT NULL,
+    job         JSONB NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_publishing_queue_jobs_lease ON publishing_queue_jobs (lease_until);
+CREATE INDEX IF NOT EXISTS idx_publishing_queue_jobs_owner ON publishing_queue_jobs (owner);
+
+-- +goose Down
+DROP TABLE IF EXISTS publishing_queue_jobs;
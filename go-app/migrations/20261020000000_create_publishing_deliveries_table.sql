@@ -0,0 +1,27 @@
+-- +goose Up
+-- Audit log of publish attempts (publishing.deliveries.persist): one row per
+-- attempt to publish an alert to a target, with the provider status code and
+-- the hash of the payload sent. Rows older than
+-- publishing.deliveries.retention are purged.
+CREATE TABLE IF NOT EXISTS publishing_deliveries (
+    id           BIGSERIAL PRIMARY KEY,
+    fingerprint  VARCHAR(64) NOT NULL,
+    job_id       VARCHAR(64) NOT NULL,
+    target_name  VARCHAR(255) NOT NULL,
+    target_type  VARCHAR(64) NOT NULL,
+    alert_status VARCHAR(16) NOT NULL,
+    attempt      INTEGER NOT NULL,
+    status       VARCHAR(32) NOT NULL,
+    status_code  INTEGER NOT NULL DEFAULT 0,
+    error_type   VARCHAR(64) NOT NULL DEFAULT '',
+    error        TEXT NOT NULL DEFAULT '',
+    payload_hash VARCHAR(80) NOT NULL DEFAULT '',
+    latency_ms   BIGINT NOT NULL DEFAULT 0,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_publishing_deliveries_fingerprint ON publishing_deliveries (fingerprint, attempted_at DESC);
+CREATE INDEX IF NOT EXISTS idx_publishing_deliveries_attempted_at ON publishing_deliveries (attempted_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS publishing_deliveries;
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Alert is an alert as returned by GET /api/v2/alerts.
+type Alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	Receivers    []Receiver        `json:"receivers"`
+	StartsAt     time.Time         `json:"startsAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+	Fingerprint  string            `json:"fingerprint"`
+	Status       AlertStatus       `json:"status"`
+}
+
+// Receiver is a receiver of an alert.
+type Receiver struct {
+	Name string `json:"name"`
+}
+
+// AlertStatus is the state of an alert: "active", "suppressed" (silenced
+// or inhibited) or "unprocessed".
+type AlertStatus struct {
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+	MutedBy     []string `json:"mutedBy"`
+}
+
+// AlertGroup is a group of alerts as returned by GET /api/v2/alerts/groups.
+type AlertGroup struct {
+	Labels   map[string]string `json:"labels"`
+	Receiver Receiver          `json:"receiver"`
+	Alerts   []Alert           `json:"alerts"`
+}
+
+// PostableAlert is an alert sent to AMP, as Prometheus sends them.
+// Zero StartsAt and EndsAt are left to the server.
+type PostableAlert struct {
+	Labels       map[string]string
+	Annotations  map[string]string
+	StartsAt     time.Time
+	EndsAt       time.Time
+	GeneratorURL string
+}
+
+// postableAlert is the wire form of PostableAlert.
+type postableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     string            `json:"startsAt,omitempty"`
+	EndsAt       string            `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// AlertsQuery filters the alerts of ListAlerts.
+type AlertsQuery struct {
+	// Filter holds label matchers, e.g. `severity="critical"` or `team=~"db.*"`.
+	Filter []string
+	// Status is "firing" or "resolved" ("" for firing).
+	Status string
+	// Resolved includes resolved alerts.
+	Resolved bool
+}
+
+// ListAlerts returns the alerts matching query.
+func (c *Client) ListAlerts(ctx context.Context, query AlertsQuery) ([]Alert, error) {
+	params := url.Values{}
+	for _, filter := range query.Filter {
+		params.Add("filter", filter)
+	}
+	if query.Status != "" {
+		params.Set("status", query.Status)
+	}
+	if query.Resolved {
+		params.Set("resolved", "true")
+	}
+
+	var alerts []Alert
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v2/alerts", query: params, idempotent: true}, &alerts)
+	return alerts, err
+}
+
+// ListAlertGroups returns the alerts grouped by the groupBy labels.
+func (c *Client) ListAlertGroups(ctx context.Context, groupBy ...string) ([]AlertGroup, error) {
+	params := url.Values{}
+	for _, label := range groupBy {
+		params.Add("group_by", label)
+	}
+
+	var groups []AlertGroup
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v2/alerts/groups", query: params, idempotent: true}, &groups)
+	return groups, err
+}
+
+// PostAlerts sends alerts to AMP. Sending an alert again updates it, so the
+// request is retried like reads. Alerts the server fails to process are
+// reported as an error; the others are accepted.
+func (c *Client) PostAlerts(ctx context.Context, alerts []PostableAlert) error {
+	body := make([]postableAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		wire := postableAlert{
+			Labels:       alert.Labels,
+			Annotations:  alert.Annotations,
+			GeneratorURL: alert.GeneratorURL,
+		}
+		if !alert.StartsAt.IsZero() {
+			wire.StartsAt = alert.StartsAt.UTC().Format(time.RFC3339Nano)
+		}
+		if !alert.EndsAt.IsZero() {
+			wire.EndsAt = alert.EndsAt.UTC().Format(time.RFC3339Nano)
+		}
+		body = append(body, wire)
+	}
+	// 207 Multi-Status reports alerts that failed to process
+	var result struct {
+		Received int `json:"received"`
+		Failed   int `json:"failed"`
+	}
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v2/alerts", body: body, idempotent: true}, &result); err != nil {
+		return err
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d of %d alerts failed to process", result.Failed, result.Received)
+	}
+	return nil
+}
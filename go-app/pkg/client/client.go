@@ -0,0 +1,212 @@
+// Package client is a typed Go client for the AMP HTTP API: alerts,
+// silences, publishing targets and the publishing dead letter queue.
+//
+// Usage:
+//
+//	c, err := client.NewClient(client.Config{URL: "http://amp:9093", Token: token})
+//	if err != nil {
+//	    return err
+//	}
+//	alerts, err := c.ListAlerts(ctx, client.AlertsQuery{Filter: []string{`severity="critical"`}})
+//
+// Failed requests are returned as *httperror.HTTPAPIError (provider "amp"),
+// so callers can use httperror.IsNotFound, httperror.IsAuthError and the
+// other classifiers. Requests are retried with the Config.Retry strategy
+// (pkg/retry); requests that are not safe to repeat, such as creating a
+// silence, are only retried when the server did not process them (429, 503).
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipiton/AMP/pkg/httperror"
+	"github.com/ipiton/AMP/pkg/retry"
+)
+
+// Provider is the provider of the errors returned by the client.
+const Provider = "amp"
+
+// maxResponseSize bounds the responses the client reads.
+const maxResponseSize = 32 * 1024 * 1024
+
+// Config configures the Client.
+type Config struct {
+	// URL is the AMP base URL, e.g. http://amp:9093.
+	URL string
+	// Token is sent as a bearer token (API tokens, scoped tokens).
+	Token string
+	// Timeout bounds a single request (default: 30s).
+	Timeout time.Duration
+	// HTTPClient (default: a client with Timeout).
+	HTTPClient *http.Client
+	// Retry is the retry strategy of requests (default: retry.Default()).
+	// Use retry.NoRetry() to disable retries.
+	Retry *retry.Strategy
+	// UserAgent is sent with every request (default: "amp-client").
+	UserAgent string
+}
+
+// Client is an AMP API client. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	token      string
+	userAgent  string
+	httpClient *http.Client
+	retry      retry.Strategy
+}
+
+// NewClient creates a client for the AMP at config.URL.
+func NewClient(config Config) (*Client, error) {
+	base, err := url.Parse(strings.TrimSpace(config.URL))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid AMP url %q", config.URL)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: config.Timeout}
+	}
+	strategy := retry.Default()
+	if config.Retry != nil {
+		strategy = *config.Retry
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = "amp-client"
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(base.String(), "/"),
+		token:      config.Token,
+		userAgent:  config.UserAgent,
+		httpClient: config.HTTPClient,
+		retry:      strategy,
+	}, nil
+}
+
+// request is an API request.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	// idempotent requests are retried on every retryable error, others only
+	// when the server rejected them unprocessed (429, 503).
+	idempotent bool
+}
+
+// do sends req with retries and decodes the JSON response into out (nil
+// discards it).
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	strategy := c.retry
+	if !req.idempotent {
+		strategy = strategy.WithErrorClassifier(&retry.CustomErrorClassifier{Fn: isUnprocessed})
+	}
+	var lastErr error
+	err := retry.DoSimple(ctx, strategy, func() error {
+		lastErr = c.send(ctx, req, body, out)
+		return lastErr
+	})
+	if err != nil && lastErr != nil && ctx.Err() == nil {
+		// The API error itself, without the retry wrapping
+		return lastErr
+	}
+	return err
+}
+
+// isUnprocessed reports whether err is a response of a server that did not
+// process the request.
+func isUnprocessed(err error) bool {
+	var apiErr *httperror.HTTPAPIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte, out any) error {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", req.method, req.path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseError(req, resp, data)
+	}
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response to %s %s: %w", req.method, req.path, err)
+	}
+	return nil
+}
+
+// responseError converts an error response into an *httperror.HTTPAPIError,
+// with the message of the {"error": ..., "message": ...} body the API
+// returns.
+func responseError(req request, resp *http.Response, data []byte) error {
+	var errBody struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &errBody) == nil && errBody.Error != "" {
+		message = errBody.Error
+		if errBody.Message != "" {
+			message += ": " + errBody.Message
+		}
+	}
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	apiErr := httperror.NewHTTPError(resp.StatusCode, req.method+" "+req.path+": "+message, Provider)
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && retryAfter > 0 {
+		apiErr = apiErr.WithRetryAfter(retryAfter)
+	}
+	return apiErr
+}
@@ -0,0 +1,332 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipiton/AMP/pkg/httperror"
+	"github.com/ipiton/AMP/pkg/retry"
+)
+
+// recordedRequest is a request received by the fake AMP.
+type recordedRequest struct {
+	method string
+	path   string
+	query  string
+	auth   string
+	body   string
+}
+
+// newTestClient returns a client of a fake AMP answering with handler, and
+// the requests it received. Retries do not wait.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, func() []recordedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, recordedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			query:  r.URL.RawQuery,
+			auth:   r.Header.Get("Authorization"),
+			body:   string(body),
+		})
+		mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	strategy := retry.Default().WithAfter(func(time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	})
+	c, err := NewClient(Config{URL: server.URL + "/", Token: "secret", Retry: &strategy})
+	require.NoError(t, err)
+	return c, func() []recordedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedRequest(nil), requests...)
+	}
+}
+
+func TestNewClient_InvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "amp:9093", "ftp://amp"} {
+		_, err := NewClient(Config{URL: raw})
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestClient_ListAlerts(t *testing.T) {
+	c, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"labels":{"alertname":"HighCPU"},"annotations":{},"receivers":[{"name":"ops"}],
+			"startsAt":"2026-03-01T12:00:00Z","updatedAt":"2026-03-01T12:05:00Z","endsAt":"2026-03-01T12:10:00Z",
+			"fingerprint":"fp1","status":{"state":"suppressed","silencedBy":["s1"],"inhibitedBy":[],"mutedBy":[]}}]`))
+	})
+
+	alerts, err := c.ListAlerts(context.Background(), AlertsQuery{Filter: []string{`severity="critical"`}, Resolved: true})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "fp1", alerts[0].Fingerprint)
+	assert.Equal(t, "suppressed", alerts[0].Status.State)
+	assert.Equal(t, []string{"s1"}, alerts[0].Status.SilencedBy)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), alerts[0].StartsAt)
+
+	got := requests()
+	require.Len(t, got, 1)
+	assert.Equal(t, "/api/v2/alerts", got[0].path)
+	assert.Equal(t, "filter=severity%3D%22critical%22&resolved=true", got[0].query)
+	assert.Equal(t, "Bearer secret", got[0].auth)
+}
+
+func TestClient_PostAlerts(t *testing.T) {
+	status := http.StatusOK
+	c, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		if status == http.StatusMultiStatus {
+			_, _ = w.Write([]byte(`{"received":2,"processed":1,"failed":1}`))
+		}
+	})
+
+	alerts := []PostableAlert{
+		{Labels: map[string]string{"alertname": "HighCPU"}, StartsAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Labels: map[string]string{"alertname": "DiskFull"}},
+	}
+	require.NoError(t, c.PostAlerts(context.Background(), alerts))
+
+	got := requests()
+	require.Len(t, got, 1)
+	var body []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(got[0].body), &body))
+	assert.Equal(t, "2026-03-01T12:00:00Z", body[0]["startsAt"])
+	assert.NotContains(t, body[1], "startsAt", "zero times are left to the server")
+	assert.NotContains(t, body[1], "endsAt")
+
+	status = http.StatusMultiStatus
+	assert.EqualError(t, c.PostAlerts(context.Background(), alerts), "1 of 2 alerts failed to process")
+}
+
+func TestClient_Silences(t *testing.T) {
+	c, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Query().Get("allowOverlap") == "true":
+			_, _ = w.Write([]byte(`{"silenceID":"s2","warnings":[{"relation":"broader","silence":{"id":"s1"}}]}`))
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"approvalID":"a1","status":"pending_approval","reason":"longer than 24h"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silence/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":"s1","matchers":[{"name":"team","value":"db","isRegex":false,"isEqual":true}],
+				"startsAt":"2026-03-01T12:00:00Z","endsAt":"2026-03-01T14:00:00Z","updatedAt":"2026-03-01T12:00:00Z",
+				"createdBy":"alice","comment":"maintenance","status":{"state":"active"}}`))
+		}
+	})
+	ctx := context.Background()
+
+	silence, err := c.GetSilence(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "active", silence.Status.State)
+	assert.Equal(t, []Matcher{{Name: "team", Value: "db", IsEqual: true}}, silence.Matchers)
+
+	_, err = c.GetSilence(ctx, "missing")
+	assert.True(t, httperror.IsNotFound(err))
+
+	in := PostableSilence{
+		Matchers:  []Matcher{{Name: "team", Value: "db", IsEqual: true}},
+		StartsAt:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		EndsAt:    time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC),
+		CreatedBy: "alice",
+		Comment:   "migration",
+	}
+	result, err := c.PostSilence(ctx, in)
+	require.NoError(t, err)
+	assert.True(t, result.PendingApproval())
+	assert.Equal(t, "a1", result.ApprovalID)
+
+	in.AllowOverlap = true
+	result, err = c.PostSilence(ctx, in)
+	require.NoError(t, err)
+	assert.False(t, result.PendingApproval())
+	assert.Equal(t, "s2", result.SilenceID)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "s1", result.Warnings[0].Silence.ID)
+
+	got := requests()
+	require.Len(t, got, 4)
+	assert.Equal(t, "/api/v2/silence/s1", got[0].path)
+	assert.JSONEq(t, `{"matchers":[{"name":"team","value":"db","isRegex":false,"isEqual":true}],
+		"startsAt":"2026-03-01T12:00:00Z","endsAt":"2026-03-03T12:00:00Z","createdBy":"alice","comment":"migration"}`, got[2].body)
+}
+
+func TestClient_Retry(t *testing.T) {
+	var mu sync.Mutex
+	failures := map[string]int{}
+	c, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures[r.Method] < 1 {
+			failures[r.Method]++
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"error":"upstream unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	ctx := context.Background()
+
+	// Reads are retried on server errors
+	_, err := c.ListSilences(ctx)
+	require.NoError(t, err)
+	assert.Len(t, requests(), 2)
+
+	// Creating a silence is not retried after a server error: it may have
+	// been created
+	_, err = c.PostSilence(ctx, PostableSilence{})
+	var apiErr *httperror.HTTPAPIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, "POST /api/v2/silences: upstream unavailable", apiErr.Message)
+	assert.Len(t, requests(), 3)
+}
+
+func TestClient_RetryUnprocessed(t *testing.T) {
+	attempts := 0
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"silenceID":"s1"}`))
+	})
+
+	result, err := c.PostSilence(context.Background(), PostableSilence{})
+	require.NoError(t, err)
+	assert.Equal(t, "s1", result.SilenceID)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestClient_Targets(t *testing.T) {
+	c, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/publishing/targets":
+			_, _ = w.Write([]byte(`[{"name":"slack-ops","type":"slack","url":"https://hooks.slack.com/x","enabled":true,"format":"slack"}]`))
+		case "/api/v1/publishing/targets/slack-ops/test":
+			_, _ = w.Write([]byte(`{"success":false,"message":"Test alert sent","error":"HTTP 410"}`))
+		case "/api/v1/publishing/targets/refresh":
+			_, _ = w.Write([]byte(`{"success":true,"message":"Targets refreshed successfully","total_targets":3,"enabled_targets":2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"Target not found","message":"target 'nope' not found"}`))
+		}
+	})
+	ctx := context.Background()
+
+	targets, err := c.ListTargets(ctx)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, "slack-ops", targets[0].Name)
+	assert.True(t, targets[0].Enabled)
+
+	result, err := c.TestTarget(ctx, "slack-ops", "")
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "HTTP 410", result.Error)
+
+	refresh, err := c.RefreshTargets(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, refresh.EnabledTargets)
+
+	_, err = c.GetTarget(ctx, "nope")
+	assert.True(t, httperror.IsNotFound(err))
+	assert.ErrorContains(t, err, "Target not found: target 'nope' not found")
+	assert.Len(t, requests(), 4, "not found is not retried")
+}
+
+func TestClient_TargetWrites(t *testing.T) {
+	c, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"name":"hooks","type":"webhook","url":"https://hooks.example.com","enabled":true,"format":"webhook"}`))
+		case http.MethodPut:
+			_, _ = w.Write([]byte(`{"name":"hooks","type":"webhook","url":"https://hooks.example.com/v2","enabled":true,"format":"webhook"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":"target is not managed through the API"}`))
+		}
+	})
+	ctx := context.Background()
+
+	target := Target{Name: "hooks", Type: "webhook", URL: "https://hooks.example.com", Enabled: true, Format: "webhook"}
+	created, err := c.CreateTarget(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, target, *created)
+
+	target.URL = "https://hooks.example.com/v2"
+	updated, err := c.UpdateTarget(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, target.URL, updated.URL)
+
+	err = c.DeleteTarget(ctx, "configured")
+	assert.ErrorContains(t, err, "not managed")
+
+	sent := requests()
+	require.Len(t, sent, 3, "conflicts are not retried")
+	assert.Equal(t, "/api/v1/publishing/targets", sent[0].path)
+	assert.JSONEq(t, `{"name":"hooks","type":"webhook","url":"https://hooks.example.com","enabled":true,"format":"webhook"}`, sent[0].body)
+	assert.Equal(t, http.MethodPut, sent[1].method)
+	assert.Equal(t, "/api/v1/publishing/targets/hooks", sent[1].path)
+	assert.Equal(t, "/api/v1/publishing/targets/configured", sent[2].path)
+}
+
+func TestClient_DLQ(t *testing.T) {
+	c, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"entries":[{"id":"e1","job_id":"j1","fingerprint":"fp1","target_name":"slack-ops",
+				"error_type":"permanent","failed_at":"2026-03-01T12:00:00Z","replayed":false}],"total_count":1,
+				"stats":{"total_entries":1,"replayed_count":0}}`))
+		case r.URL.Path == "/api/v1/publishing/dlq/e1/replay":
+			_, _ = w.Write([]byte(`{"success":true,"message":"DLQ entry replayed successfully"}`))
+		case r.URL.Path == "/api/v1/publishing/dlq/e2/replay":
+			_, _ = w.Write([]byte(`{"success":false,"message":"Replay failed","error":"entry already replayed"}`))
+		case r.Method == http.MethodDelete:
+			_, _ = w.Write([]byte(`{"success":true,"deleted_count":4}`))
+		}
+	})
+	ctx := context.Background()
+
+	replayed := false
+	list, err := c.ListDLQ(ctx, DLQQuery{Target: "slack-ops", Replayed: &replayed, Limit: 50, IncludeStats: true})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	assert.Equal(t, "slack-ops", list.Entries[0].TargetName)
+	require.NotNil(t, list.Stats)
+	assert.Equal(t, 1, list.Stats.TotalEntries)
+
+	require.NoError(t, c.ReplayDLQEntry(ctx, "e1"))
+	assert.EqualError(t, c.ReplayDLQEntry(ctx, "e2"), "replay of DLQ entry e2 failed: entry already replayed")
+
+	deleted, err := c.PurgeDLQ(ctx, 48*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+
+	got := requests()
+	require.Len(t, got, 4)
+	assert.Equal(t, "include_stats=true&limit=50&replayed=false&target=slack-ops", got[0].query)
+	assert.JSONEq(t, `{"older_than_hours":48}`, got[3].body)
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Target is a publishing target.
+type Target struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	URL     string            `json:"url"`
+	Enabled bool              `json:"enabled"`
+	Format  string            `json:"format"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// FilterConfig restricts the alerts published to the target.
+	FilterConfig map[string]any `json:"filter_config,omitempty"`
+}
+
+// RefreshResult is the outcome of RefreshTargets.
+type RefreshResult struct {
+	Success        bool   `json:"success"`
+	Message        string `json:"message"`
+	TotalTargets   int    `json:"total_targets"`
+	EnabledTargets int    `json:"enabled_targets"`
+}
+
+// TargetTestResult is the outcome of TestTarget.
+type TargetTestResult struct {
+	Success    bool              `json:"success"`
+	Message    string            `json:"message"`
+	Error      string            `json:"error,omitempty"`
+	DurationMs int64             `json:"duration_ms,omitempty"`
+	Response   *ProviderResponse `json:"response,omitempty"`
+}
+
+// ProviderResponse is the HTTP response of a provider to a test alert.
+type ProviderResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	// Truncated is set when Body is the beginning of the response only.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// DLQEntry is a publishing job that failed all its retries.
+type DLQEntry struct {
+	ID           string     `json:"id"`
+	JobID        string     `json:"job_id"`
+	Fingerprint  string     `json:"fingerprint"`
+	TargetName   string     `json:"target_name"`
+	TargetType   string     `json:"target_type"`
+	ErrorMessage string     `json:"error_message"`
+	ErrorType    string     `json:"error_type"`
+	RetryCount   int        `json:"retry_count"`
+	Priority     string     `json:"priority"`
+	FailedAt     time.Time  `json:"failed_at"`
+	Replayed     bool       `json:"replayed"`
+	ReplayedAt   *time.Time `json:"replayed_at,omitempty"`
+}
+
+// DLQStats summarizes the dead letter queue.
+type DLQStats struct {
+	TotalEntries       int            `json:"total_entries"`
+	EntriesByErrorType map[string]int `json:"entries_by_error_type"`
+	EntriesByTarget    map[string]int `json:"entries_by_target"`
+	EntriesByPriority  map[string]int `json:"entries_by_priority"`
+	ReplayedCount      int            `json:"replayed_count"`
+}
+
+// DLQList is a page of dead letter queue entries.
+type DLQList struct {
+	Entries    []DLQEntry `json:"entries"`
+	TotalCount int        `json:"total_count"`
+	Stats      *DLQStats  `json:"stats,omitempty"`
+}
+
+// DLQQuery filters the entries of ListDLQ.
+type DLQQuery struct {
+	Target    string
+	ErrorType string
+	Priority  string
+	// Replayed filters on whether entries were replayed (nil: all).
+	Replayed *bool
+	// Limit is the page size (server default 100, at most 1000).
+	Limit  int
+	Offset int
+	// IncludeStats adds the DLQ statistics.
+	IncludeStats bool
+}
+
+// ListTargets returns the publishing targets.
+func (c *Client) ListTargets(ctx context.Context) ([]Target, error) {
+	var targets []Target
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/publishing/targets", idempotent: true}, &targets)
+	return targets, err
+}
+
+// GetTarget returns the publishing target name.
+func (c *Client) GetTarget(ctx context.Context, name string) (*Target, error) {
+	var target Target
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/publishing/targets/" + url.PathEscape(name), idempotent: true}, &target); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// CreateTarget creates a publishing target, persisted by the server. The
+// target is disabled unless Enabled is set.
+func (c *Client) CreateTarget(ctx context.Context, target Target) (*Target, error) {
+	var created Target
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/publishing/targets", body: target}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateTarget replaces the publishing target target.Name. Only targets
+// created through the API can be updated.
+func (c *Client) UpdateTarget(ctx context.Context, target Target) (*Target, error) {
+	var updated Target
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/api/v1/publishing/targets/" + url.PathEscape(target.Name), body: target, idempotent: true}, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteTarget deletes the publishing target name. Only targets created
+// through the API can be deleted.
+func (c *Client) DeleteTarget(ctx context.Context, name string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/v1/publishing/targets/" + url.PathEscape(name), idempotent: true}, nil)
+}
+
+// RefreshTargets rediscovers the publishing targets now.
+func (c *Client) RefreshTargets(ctx context.Context) (*RefreshResult, error) {
+	var result RefreshResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/publishing/targets/refresh", idempotent: true}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TestTarget publishes a test alert named alertName ("" for the server
+// default) to the target name. A delivery failure is reported in the
+// result, not as an error.
+func (c *Client) TestTarget(ctx context.Context, name, alertName string) (*TargetTestResult, error) {
+	body := map[string]string{"alert_name": alertName}
+	var result TargetTestResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/publishing/targets/" + url.PathEscape(name) + "/test", body: body}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListDLQ returns the dead letter queue entries matching query.
+func (c *Client) ListDLQ(ctx context.Context, query DLQQuery) (*DLQList, error) {
+	params := url.Values{}
+	for name, value := range map[string]string{"target": query.Target, "error_type": query.ErrorType, "priority": query.Priority} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	if query.Replayed != nil {
+		params.Set("replayed", strconv.FormatBool(*query.Replayed))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Offset > 0 {
+		params.Set("offset", strconv.Itoa(query.Offset))
+	}
+	if query.IncludeStats {
+		params.Set("include_stats", "true")
+	}
+
+	var list DLQList
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/publishing/dlq", query: params, idempotent: true}, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ReplayDLQEntry resubmits the dead letter queue entry id to the publishing
+// queue.
+func (c *Client) ReplayDLQEntry(ctx context.Context, id string) error {
+	var result struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/publishing/dlq/" + url.PathEscape(id) + "/replay"}, &result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return errors.New("replay of DLQ entry " + id + " failed: " + result.Error)
+	}
+	return nil
+}
+
+// PurgeDLQ deletes the dead letter queue entries older than olderThan
+// (rounded down to hours; less than an hour uses the server default of 7
+// days) and returns how many were deleted.
+func (c *Client) PurgeDLQ(ctx context.Context, olderThan time.Duration) (int64, error) {
+	body := map[string]int{"older_than_hours": int(olderThan / time.Hour)}
+	var result struct {
+		DeletedCount int64 `json:"deleted_count"`
+	}
+	if err := c.do(ctx, request{method: http.MethodDelete, path: "/api/v1/publishing/dlq/purge", body: body, idempotent: true}, &result); err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Matcher is a label matcher of a silence. IsEqual false negates it
+// (!= or !~), as in the Alertmanager API.
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Silence is a silence as returned by the API.
+type Silence struct {
+	ID        string        `json:"id"`
+	Matchers  []Matcher     `json:"matchers"`
+	StartsAt  time.Time     `json:"startsAt"`
+	EndsAt    time.Time     `json:"endsAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	CreatedBy string        `json:"createdBy"`
+	Comment   string        `json:"comment"`
+	Status    SilenceStatus `json:"status"`
+
+	NotifyOnExpiry       string `json:"notifyOnExpiry,omitempty"`
+	RequireLabelPresence bool   `json:"requireLabelPresence,omitempty"`
+}
+
+// SilenceStatus is the state of a silence: "active", "pending" or "expired".
+type SilenceStatus struct {
+	State string `json:"state"`
+}
+
+// PostableSilence creates a silence, or updates the silence with ID.
+type PostableSilence struct {
+	ID        string    `json:"id,omitempty"`
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+
+	// NotifyOnExpiry names a publishing target notified before the silence
+	// ends.
+	NotifyOnExpiry string `json:"notifyOnExpiry,omitempty"`
+	// RequireLabelPresence makes the silence match only alerts carrying
+	// every label its matchers name.
+	RequireLabelPresence bool `json:"requireLabelPresence,omitempty"`
+
+	// AllowOverlap creates the silence even when it duplicates, subsumes or
+	// is subsumed by an existing silence (otherwise a 409 error).
+	AllowOverlap bool `json:"-"`
+}
+
+// SilenceOverlap is an existing silence overlapping a new one; Relation is
+// "duplicate", "broader" or "narrower".
+type SilenceOverlap struct {
+	Relation string  `json:"relation"`
+	Silence  Silence `json:"silence"`
+}
+
+// SilenceResult is the outcome of PostSilence. Silences the silence policy
+// holds for approval have an ApprovalID instead of a SilenceID.
+type SilenceResult struct {
+	SilenceID string           `json:"silenceID"`
+	Warnings  []SilenceOverlap `json:"warnings,omitempty"`
+
+	ApprovalID string `json:"approvalID,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// PendingApproval reports whether the silence waits for a second person's
+// approval.
+func (r *SilenceResult) PendingApproval() bool {
+	return r.ApprovalID != ""
+}
+
+// ListSilences returns the silences matching the filter label matchers,
+// including expired ones.
+func (c *Client) ListSilences(ctx context.Context, filter ...string) ([]Silence, error) {
+	params := url.Values{}
+	for _, f := range filter {
+		params.Add("filter", f)
+	}
+
+	var silences []Silence
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v2/silences", query: params, idempotent: true}, &silences)
+	return silences, err
+}
+
+// GetSilence returns the silence with id.
+func (c *Client) GetSilence(ctx context.Context, id string) (*Silence, error) {
+	var silence Silence
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v2/silence/" + url.PathEscape(id), idempotent: true}, &silence); err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+// PostSilence creates or updates a silence. Updates are retried like reads;
+// creations only when the server did not process the request.
+func (c *Client) PostSilence(ctx context.Context, silence PostableSilence) (*SilenceResult, error) {
+	params := url.Values{}
+	if silence.AllowOverlap {
+		params.Set("allowOverlap", "true")
+	}
+
+	var result SilenceResult
+	req := request{method: http.MethodPost, path: "/api/v2/silences", query: params, body: silence, idempotent: silence.ID != ""}
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExpireSilence expires the silence with id.
+func (c *Client) ExpireSilence(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/v2/silence/" + url.PathEscape(id), idempotent: true}, nil)
+}
@@ -0,0 +1,109 @@
+// Package clock provides a time abstraction for timer-driven subsystems.
+//
+// Production code uses Real(), which delegates to the standard time package.
+// Tests use NewFake() to control time explicitly instead of sleeping:
+//
+//	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+//	manager, _ := grouping.NewDefaultTimerManager(grouping.TimerManagerConfig{
+//	    Clock: fake,
+//	    // ...
+//	})
+//	manager.StartTimer(ctx, key, grouping.GroupWaitTimer, 30*time.Second)
+//	fake.Advance(30 * time.Second) // timer fires immediately
+package clock
+
+import "time"
+
+// Clock abstracts time.Now and timer creation.
+//
+// Thread-safe: All implementations must be safe for concurrent use.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// Until returns the duration until t.
+	Until(t time.Time) time.Duration
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTimer creates a Timer that fires once after d.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker creates a Ticker that fires every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the Clock equivalent of *time.Timer.
+type Timer interface {
+	// C returns the channel on which the expiration time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing.
+	// Returns false if the timer already expired or was stopped.
+	Stop() bool
+
+	// Reset changes the timer to expire after d.
+	// Returns true if the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the Clock equivalent of *time.Ticker.
+type Ticker interface {
+	// C returns the channel on which ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker.
+	Stop()
+
+	// Reset stops the ticker and resets its period to d.
+	Reset(d time.Duration)
+}
+
+// Real returns a Clock backed by the standard time package.
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal returns c, or Real() if c is nil.
+//
+// Convenience for applying defaults in constructors:
+//
+//	config.Clock = clock.OrReal(config.Clock)
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Until(t time.Time) time.Duration        { return time.Until(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{t: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{t: time.NewTicker(d)}
+}
+
+type realTimer struct{ t *time.Timer }
+
+func (r *realTimer) C() <-chan time.Time        { return r.t.C }
+func (r *realTimer) Stop() bool                 { return r.t.Stop() }
+func (r *realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r *realTicker) C() <-chan time.Time   { return r.t.C }
+func (r *realTicker) Stop()                 { r.t.Stop() }
+func (r *realTicker) Reset(d time.Duration) { r.t.Reset(d) }
@@ -0,0 +1,208 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a manually driven Clock for tests.
+//
+// Time only moves when Advance or Set is called. Timers and tickers created
+// from a Fake fire synchronously inside Advance/Set once their deadline is
+// reached, so tests never need to sleep.
+//
+// Thread-safe: All methods are safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter backs both fake timers and fake tickers.
+type fakeWaiter struct {
+	clock    *Fake
+	ch       chan time.Time
+	deadline time.Time
+	period   time.Duration // 0 for one-shot timers
+	active   bool
+}
+
+// NewFake creates a Fake clock starting at now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now implements Clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since implements Clock.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Until implements Clock.
+func (f *Fake) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+// After implements Clock.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer implements Clock.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.addWaiter(d, 0)
+}
+
+// NewTicker implements Clock.
+//
+// Panics if d <= 0, matching time.NewTicker.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return &fakeTicker{w: f.addWaiter(d, d)}
+}
+
+// Advance moves the clock forward by d and fires due timers and tickers.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.fireLocked()
+}
+
+// Set moves the clock to t and fires due timers and tickers.
+//
+// Moving the clock backwards is allowed but never fires anything.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+	f.fireLocked()
+}
+
+// Waiters returns the number of active timers and tickers.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil blocks until at least n timers or tickers are active.
+//
+// Use it to synchronize with goroutines that create timers asynchronously
+// (e.g. a worker's run loop) before calling Advance.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) addWaiter(d, period time.Duration) *fakeWaiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{
+		clock:    f,
+		ch:       make(chan time.Time, 1),
+		deadline: f.now.Add(d),
+		period:   period,
+	}
+	f.activateLocked(w)
+	f.fireLocked()
+	return w
+}
+
+// activateLocked registers w. Must be called with f.mu held.
+func (f *Fake) activateLocked(w *fakeWaiter) {
+	if !w.active {
+		w.active = true
+		f.waiters = append(f.waiters, w)
+		f.cond.Broadcast()
+	}
+}
+
+// deactivateLocked unregisters w. Must be called with f.mu held.
+func (f *Fake) deactivateLocked(w *fakeWaiter) bool {
+	if !w.active {
+		return false
+	}
+	w.active = false
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// fireLocked delivers due ticks. Must be called with f.mu held.
+//
+// Like the real time package, a slow receiver drops ticks rather than
+// blocking the clock.
+func (f *Fake) fireLocked() {
+	for _, w := range append([]*fakeWaiter(nil), f.waiters...) {
+		if w.deadline.After(f.now) {
+			continue
+		}
+		select {
+		case w.ch <- f.now:
+		default:
+		}
+		if w.period == 0 {
+			f.deactivateLocked(w)
+			continue
+		}
+		for !w.deadline.After(f.now) {
+			w.deadline = w.deadline.Add(w.period)
+		}
+	}
+}
+
+// C implements Timer.
+func (w *fakeWaiter) C() <-chan time.Time { return w.ch }
+
+// Stop implements Timer.
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.deactivateLocked(w)
+}
+
+// Reset implements Timer.
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	wasActive := w.active
+	w.deadline = w.clock.now.Add(d)
+	if w.period != 0 {
+		w.period = d
+	}
+	w.clock.activateLocked(w)
+	w.clock.fireLocked()
+	return wasActive
+}
+
+type fakeTicker struct{ w *fakeWaiter }
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.C() }
+func (t *fakeTicker) Stop()               { t.w.Stop() }
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.w.Reset(d)
+}
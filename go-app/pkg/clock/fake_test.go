@@ -0,0 +1,124 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// TestFake_Now tests that time only moves on Advance/Set
+func TestFake_Now(t *testing.T) {
+	fake := NewFake(epoch)
+	assert.Equal(t, epoch, fake.Now())
+
+	fake.Advance(5 * time.Second)
+	assert.Equal(t, epoch.Add(5*time.Second), fake.Now())
+	assert.Equal(t, 5*time.Second, fake.Since(epoch))
+	assert.Equal(t, 5*time.Second, fake.Until(epoch.Add(10*time.Second)))
+
+	fake.Set(epoch)
+	assert.Equal(t, epoch, fake.Now())
+}
+
+// TestFake_Timer tests one-shot timer firing and stopping
+func TestFake_Timer(t *testing.T) {
+	fake := NewFake(epoch)
+	timer := fake.NewTimer(10 * time.Second)
+
+	fake.Advance(9 * time.Second)
+	assertNotFired(t, timer.C())
+
+	fake.Advance(1 * time.Second)
+	assertFiredAt(t, timer.C(), epoch.Add(10*time.Second))
+	assert.False(t, timer.Stop(), "fired timer should not be active")
+	assert.Equal(t, 0, fake.Waiters())
+
+	stopped := fake.NewTimer(time.Second)
+	assert.True(t, stopped.Stop())
+	fake.Advance(time.Hour)
+	assertNotFired(t, stopped.C())
+}
+
+// TestFake_TimerReset tests rescheduling a timer
+func TestFake_TimerReset(t *testing.T) {
+	fake := NewFake(epoch)
+	timer := fake.NewTimer(10 * time.Second)
+
+	fake.Advance(5 * time.Second)
+	assert.True(t, timer.Reset(10*time.Second))
+
+	fake.Advance(5 * time.Second)
+	assertNotFired(t, timer.C())
+
+	fake.Advance(5 * time.Second)
+	assertFiredAt(t, timer.C(), epoch.Add(15*time.Second))
+}
+
+// TestFake_Ticker tests periodic delivery and tick dropping
+func TestFake_Ticker(t *testing.T) {
+	fake := NewFake(epoch)
+	ticker := fake.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	fake.Advance(time.Minute)
+	assertFiredAt(t, ticker.C(), epoch.Add(time.Minute))
+
+	// Slow receiver: only one tick is buffered
+	fake.Advance(time.Minute)
+	fake.Advance(time.Minute)
+	assertFiredAt(t, ticker.C(), epoch.Add(2*time.Minute))
+	assertNotFired(t, ticker.C())
+
+	ticker.Stop()
+	fake.Advance(time.Hour)
+	assertNotFired(t, ticker.C())
+}
+
+// TestFake_BlockUntil tests synchronizing with asynchronously created timers
+func TestFake_BlockUntil(t *testing.T) {
+	fake := NewFake(epoch)
+	fired := make(chan struct{})
+
+	go func() {
+		<-fake.After(time.Second)
+		close(fired)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+}
+
+// TestOrReal tests default clock selection
+func TestOrReal(t *testing.T) {
+	fake := NewFake(epoch)
+	assert.Same(t, fake, OrReal(fake))
+	assert.IsType(t, realClock{}, OrReal(nil))
+}
+
+func assertFiredAt(t *testing.T, ch <-chan time.Time, expected time.Time) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		assert.Equal(t, expected, got)
+	default:
+		t.Fatal("expected channel to fire")
+	}
+}
+
+func assertNotFired(t *testing.T, ch <-chan time.Time) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		t.Fatalf("unexpected fire at %v", got)
+	default:
+	}
+}
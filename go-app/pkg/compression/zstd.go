@@ -0,0 +1,214 @@
+// Package compression provides transparent zstd compression for stored blobs.
+//
+// Payloads below a size threshold are stored as-is. Larger payloads are
+// stored compressed only when that makes them smaller. Compressed values are
+// self-describing, so readers handle old uncompressed rows and new
+// compressed ones alike:
+//   - binary blobs are plain zstd frames, recognized by the zstd frame magic
+//   - text values (e.g. annotation values, JSONB documents) carry the
+//     "zstd:" prefix followed by the base64 encoded frame
+//
+// Usage:
+//
+//	stored := compression.CompressJSON(doc, compression.DefaultThreshold)
+//	// ... write stored to a JSONB column, read it back ...
+//	doc, err := compression.DecompressJSON(stored)
+package compression
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultThreshold is the size in bytes from which payloads are compressed.
+// Smaller payloads rarely shrink enough to pay for the decoding cost.
+const DefaultThreshold = 4 << 10
+
+// maxDecodedSize bounds decompression to protect against corrupt or
+// malicious frames.
+const maxDecodedSize = 64 << 20
+
+// StringPrefix marks a text value holding a base64 encoded zstd frame.
+const StringPrefix = "zstd:"
+
+// frameMagic starts every zstd frame (RFC 8878).
+var frameMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	codecOnce sync.Once
+	encoder   *zstd.Encoder
+	decoder   *zstd.Decoder
+	codecErr  error
+)
+
+// codec returns the shared encoder and decoder. Both are safe for
+// concurrent EncodeAll/DecodeAll calls.
+func codec() (*zstd.Encoder, *zstd.Decoder, error) {
+	codecOnce.Do(func() {
+		encoder, codecErr = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if codecErr != nil {
+			return
+		}
+		decoder, codecErr = zstd.NewReader(nil,
+			zstd.WithDecoderConcurrency(0),
+			zstd.WithDecoderMaxMemory(maxDecodedSize))
+	})
+	return encoder, decoder, codecErr
+}
+
+// Compress returns data as a zstd frame when it is at least threshold bytes
+// long and compression makes it smaller, and data unchanged otherwise.
+func Compress(data []byte, threshold int) []byte {
+	if len(data) < threshold || len(data) == 0 {
+		return data
+	}
+	enc, _, err := codec()
+	if err != nil {
+		return data
+	}
+	compressed := enc.EncodeAll(data, make([]byte, 0, len(data)/2))
+	if len(compressed) >= len(data) {
+		return data
+	}
+	return compressed
+}
+
+// IsCompressed reports whether data is a zstd frame.
+func IsCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, frameMagic)
+}
+
+// Decompress decodes data written by Compress. Data that is not a zstd
+// frame is returned unchanged.
+func Decompress(data []byte) ([]byte, error) {
+	if !IsCompressed(data) {
+		return data, nil
+	}
+	_, dec, err := codec()
+	if err != nil {
+		return nil, err
+	}
+	out, err := dec.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("zstd decode: %w", err)
+	}
+	return out, nil
+}
+
+// CompressString is Compress for text storage: a compressed value is
+// StringPrefix followed by the base64 encoded frame. The value is returned
+// unchanged when encoding would not make it smaller.
+func CompressString(s string, threshold int) string {
+	if len(s) < threshold {
+		return s
+	}
+	compressed := Compress([]byte(s), threshold)
+	if !IsCompressed(compressed) {
+		return s
+	}
+	encoded := StringPrefix + base64.StdEncoding.EncodeToString(compressed)
+	if len(encoded) >= len(s) {
+		return s
+	}
+	return encoded
+}
+
+// DecompressString decodes a value written by CompressString. Values
+// without StringPrefix, or whose payload is not a base64 encoded zstd frame,
+// are plain text and returned unchanged; an error means a corrupt frame.
+func DecompressString(s string) (string, error) {
+	if !strings.HasPrefix(s, StringPrefix) {
+		return s, nil
+	}
+	frame, err := base64.StdEncoding.DecodeString(s[len(StringPrefix):])
+	if err != nil || !IsCompressed(frame) {
+		return s, nil
+	}
+	out, err := Decompress(frame)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// CompressValues applies CompressString to every value of values. The input
+// map is never modified; it is returned as-is when no value was compressed.
+func CompressValues(values map[string]string, threshold int) map[string]string {
+	var out map[string]string
+	for key, value := range values {
+		compressed := CompressString(value, threshold)
+		if compressed == value {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(values))
+			for k, v := range values {
+				out[k] = v
+			}
+		}
+		out[key] = compressed
+	}
+	if out == nil {
+		return values
+	}
+	return out
+}
+
+// DecompressValues decodes the values of values written by CompressValues
+// in place.
+func DecompressValues(values map[string]string) error {
+	for key, value := range values {
+		if !strings.HasPrefix(value, StringPrefix) {
+			continue
+		}
+		decoded, err := DecompressString(value)
+		if err != nil {
+			return fmt.Errorf("value %q: %w", key, err)
+		}
+		values[key] = decoded
+	}
+	return nil
+}
+
+// CompressJSON stores a JSON document for a JSON/JSONB column: documents of
+// at least threshold bytes become a JSON string holding the CompressString
+// encoding. Smaller or incompressible documents are returned unchanged.
+func CompressJSON(doc []byte, threshold int) []byte {
+	encoded := CompressString(string(doc), threshold)
+	if encoded == string(doc) {
+		return doc
+	}
+	out, err := json.Marshal(encoded)
+	if err != nil {
+		return doc
+	}
+	return out
+}
+
+// DecompressJSON reverses CompressJSON. Documents that are not a compressed
+// JSON string (including all documents written before compression was
+// enabled) are returned unchanged.
+func DecompressJSON(doc []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(doc)
+	if !bytes.HasPrefix(trimmed, []byte(`"`+StringPrefix)) {
+		return doc, nil
+	}
+	var encoded string
+	if err := json.Unmarshal(trimmed, &encoded); err != nil {
+		return doc, nil
+	}
+	decoded, err := DecompressString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if decoded == encoded {
+		return doc, nil
+	}
+	return []byte(decoded), nil
+}
@@ -0,0 +1,126 @@
+package compression
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCompress_RoundTrip(t *testing.T) {
+	large := bytes.Repeat([]byte(`{"alertname":"HighCPU","instance":"db-01"}`), 200)
+
+	compressed := Compress(large, DefaultThreshold)
+	if !IsCompressed(compressed) {
+		t.Fatal("large compressible payload was not compressed")
+	}
+	if len(compressed) >= len(large) {
+		t.Errorf("compressed size %d >= raw size %d", len(compressed), len(large))
+	}
+
+	decoded, err := Decompress(compressed)
+	if err != nil {
+		t.Fatalf("Decompress() error = %v", err)
+	}
+	if !bytes.Equal(decoded, large) {
+		t.Error("round trip changed the payload")
+	}
+}
+
+func TestCompress_BelowThresholdIsUnchanged(t *testing.T) {
+	small := []byte(`{"alertname":"HighCPU"}`)
+	if got := Compress(small, DefaultThreshold); !bytes.Equal(got, small) {
+		t.Errorf("Compress() = %q, want unchanged", got)
+	}
+
+	// Legacy uncompressed payloads decode as-is.
+	got, err := Decompress(small)
+	if err != nil || !bytes.Equal(got, small) {
+		t.Errorf("Decompress() = %q, %v; want unchanged", got, err)
+	}
+}
+
+func TestDecompress_CorruptFrame(t *testing.T) {
+	corrupt := append(append([]byte{}, frameMagic...), 0x00, 0x01, 0x02)
+	if _, err := Decompress(corrupt); err == nil {
+		t.Error("Decompress() of a corrupt frame succeeded")
+	}
+}
+
+func TestCompressString_RoundTrip(t *testing.T) {
+	runbook := strings.Repeat("Restart the pod and check the logs.\n", 200)
+
+	encoded := CompressString(runbook, DefaultThreshold)
+	if !strings.HasPrefix(encoded, StringPrefix) || len(encoded) >= len(runbook) {
+		t.Fatalf("CompressString() did not compress: %d bytes", len(encoded))
+	}
+	decoded, err := DecompressString(encoded)
+	if err != nil || decoded != runbook {
+		t.Fatalf("DecompressString() = %d bytes, %v; want the original", len(decoded), err)
+	}
+
+	for _, plain := range []string{"short", "zstd: not base64!", "zstd:aGVsbG8="} {
+		if got, err := DecompressString(plain); err != nil || got != plain {
+			t.Errorf("DecompressString(%q) = %q, %v; want unchanged", plain, got, err)
+		}
+	}
+}
+
+func TestCompressValues(t *testing.T) {
+	values := map[string]string{
+		"summary":     "CPU is high",
+		"description": strings.Repeat("load average above threshold; ", 300),
+	}
+
+	stored := CompressValues(values, DefaultThreshold)
+	if stored["summary"] != "CPU is high" {
+		t.Errorf("small value changed: %q", stored["summary"])
+	}
+	if !strings.HasPrefix(stored["description"], StringPrefix) {
+		t.Error("large value was not compressed")
+	}
+	if strings.HasPrefix(values["description"], StringPrefix) {
+		t.Error("input map was modified")
+	}
+
+	if err := DecompressValues(stored); err != nil {
+		t.Fatalf("DecompressValues() error = %v", err)
+	}
+	if stored["description"] != values["description"] {
+		t.Error("round trip changed the value")
+	}
+
+	small := map[string]string{"summary": "CPU is high"}
+	if got := CompressValues(small, DefaultThreshold); len(got) != 1 || got["summary"] != "CPU is high" {
+		t.Errorf("CompressValues() = %v, want unchanged", got)
+	}
+}
+
+func TestCompressJSON_RoundTrip(t *testing.T) {
+	doc, err := json.Marshal(map[string]string{"payload": strings.Repeat("x", 10000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := CompressJSON(doc, DefaultThreshold)
+	if !json.Valid(stored) {
+		t.Fatalf("stored document is not valid JSON: %q", stored)
+	}
+	if len(stored) >= len(doc) {
+		t.Errorf("stored size %d >= raw size %d", len(stored), len(doc))
+	}
+
+	got, err := DecompressJSON(stored)
+	if err != nil {
+		t.Fatalf("DecompressJSON() error = %v", err)
+	}
+	if !bytes.Equal(got, doc) {
+		t.Error("round trip changed the document")
+	}
+
+	// Documents stored before compression are read unchanged.
+	legacy := []byte(`{"alertname":"HighCPU"}`)
+	if got, err := DecompressJSON(legacy); err != nil || !bytes.Equal(got, legacy) {
+		t.Errorf("DecompressJSON(legacy) = %q, %v; want unchanged", got, err)
+	}
+}
@@ -161,7 +161,11 @@ const (
 //   - Status must be "firing" or "resolved"
 //   - StartsAt must not be zero
 //   - Labels must contain "alertname" key
-//   - If status is "resolved", EndsAt must be set and after StartsAt
+//   - If status is "resolved", EndsAt must be set and not before StartsAt
+//     by more than DefaultClockSkewTolerance
+//
+// Validate does not modify the alert; parsers normalize timestamps with
+// NormalizeTimestamps and their configured clock skew tolerance first.
 //
 // Returns:
 //   - nil if valid
@@ -189,7 +193,7 @@ func (a *Alert) Validate() error {
 		if a.EndsAt == nil {
 			return fmt.Errorf("ends_at is required for resolved alerts")
 		}
-		if a.EndsAt.Before(a.StartsAt) {
+		if a.StartsAt.Sub(*a.EndsAt) > DefaultClockSkewTolerance {
 			return fmt.Errorf("ends_at must be after starts_at")
 		}
 	}
@@ -252,6 +256,87 @@ func (a *Alert) Duration() time.Duration {
 	return time.Since(a.StartsAt)
 }
 
+// DefaultResolveTimeout is the Alertmanager default for global.resolve_timeout.
+// Used when an alert without EndsAt stops receiving updates.
+const DefaultResolveTimeout = 5 * time.Minute
+
+// LastUpdated returns when the alert was last updated.
+// Returns Timestamp if set, otherwise StartsAt.
+func (a *Alert) LastUpdated() time.Time {
+	if a.Timestamp != nil && !a.Timestamp.IsZero() {
+		return *a.Timestamp
+	}
+	return a.StartsAt
+}
+
+// ExpiresAt returns when the alert should be treated as resolved.
+//
+// Follows Alertmanager semantics:
+//   - If EndsAt is set, the alert expires at EndsAt
+//   - Otherwise it expires resolveTimeout after the last update
+//
+// A non-positive resolveTimeout falls back to DefaultResolveTimeout.
+func (a *Alert) ExpiresAt(resolveTimeout time.Duration) time.Time {
+	if a.EndsAt != nil && !a.EndsAt.IsZero() {
+		return *a.EndsAt
+	}
+	if resolveTimeout <= 0 {
+		resolveTimeout = DefaultResolveTimeout
+	}
+	return a.LastUpdated().Add(resolveTimeout)
+}
+
+// IsExpired returns true if the alert should be treated as resolved now.
+// See ExpiresAt for the expiry rules.
+func (a *Alert) IsExpired(resolveTimeout time.Duration) bool {
+	return a.IsExpiredAt(time.Now(), resolveTimeout)
+}
+
+// DefaultClockSkewTolerance is how far in the future StartsAt may be before it
+// is treated as a real timestamp instead of sender clock skew.
+const DefaultClockSkewTolerance = 30 * time.Second
+
+// NormalizeTimestamps converts all timestamps to UTC and absorbs small clock
+// skew of the sender (e.g. a Prometheus server with a drifting clock):
+//   - StartsAt up to skewTolerance in the future is clamped to now
+//   - EndsAt up to skewTolerance before StartsAt is clamped to StartsAt
+//
+// A non-positive skewTolerance only converts to UTC.
+func (a *Alert) NormalizeTimestamps(now time.Time, skewTolerance time.Duration) {
+	a.StartsAt = a.StartsAt.UTC()
+	if a.EndsAt != nil {
+		endsAt := a.EndsAt.UTC()
+		a.EndsAt = &endsAt
+	}
+	if a.Timestamp != nil {
+		timestamp := a.Timestamp.UTC()
+		a.Timestamp = &timestamp
+	}
+
+	if skewTolerance <= 0 {
+		return
+	}
+
+	now = now.UTC()
+	if a.StartsAt.After(now) && a.StartsAt.Sub(now) <= skewTolerance {
+		a.StartsAt = now
+	}
+	if a.EndsAt != nil && a.EndsAt.Before(a.StartsAt) && a.StartsAt.Sub(*a.EndsAt) <= skewTolerance {
+		endsAt := a.StartsAt
+		a.EndsAt = &endsAt
+	}
+}
+
+// IsExpiredAt returns true if the alert should be treated as resolved at now.
+//
+// Resolved alerts are always expired. Use this variant with an injected clock.
+func (a *Alert) IsExpiredAt(now time.Time, resolveTimeout time.Duration) bool {
+	if a.Status == StatusResolved {
+		return true
+	}
+	return !now.Before(a.ExpiresAt(resolveTimeout))
+}
+
 // Copy creates a deep copy of the alert.
 // Useful for creating modified versions without mutating the original.
 func (a *Alert) Copy() *Alert {
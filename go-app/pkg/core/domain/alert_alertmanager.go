@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ================================================================================
+// Alertmanager API v2 Wire Format
+// ================================================================================
+// Alert has two JSON representations:
+//   - Native: json.Marshal(alert) using the struct tags above (snake_case)
+//   - Alertmanager v2: MarshalAlertmanagerV2 / UnmarshalAlertmanagerV2 producing
+//     the exact GettableAlert / PostableAlert schema (camelCase, status.state,
+//     receivers, updatedAt), so Alertmanager clients such as Grafana and amtool
+//     can consume /api/v2/alerts without changes.
+
+// Alertmanager v2 alert states (GettableAlert.status.state).
+const (
+	AlertmanagerStateActive      = "active"
+	AlertmanagerStateSuppressed  = "suppressed"
+	AlertmanagerStateUnprocessed = "unprocessed"
+)
+
+// AlertmanagerV2Receiver is a receiver reference in a GettableAlert.
+type AlertmanagerV2Receiver struct {
+	Name string `json:"name"`
+}
+
+// AlertmanagerV2Status is the status block of a GettableAlert.
+type AlertmanagerV2Status struct {
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+	MutedBy     []string `json:"mutedBy"`
+}
+
+// AlertmanagerV2GettableAlert is the GET /api/v2/alerts item schema.
+type AlertmanagerV2GettableAlert struct {
+	Labels       map[string]string        `json:"labels"`
+	Annotations  map[string]string        `json:"annotations"`
+	Receivers    []AlertmanagerV2Receiver `json:"receivers"`
+	StartsAt     time.Time                `json:"startsAt"`
+	UpdatedAt    time.Time                `json:"updatedAt"`
+	EndsAt       time.Time                `json:"endsAt"`
+	GeneratorURL string                   `json:"generatorURL,omitempty"`
+	Fingerprint  string                   `json:"fingerprint"`
+	Status       AlertmanagerV2Status     `json:"status"`
+}
+
+// AlertmanagerV2PostableAlert is the POST /api/v2/alerts item schema.
+type AlertmanagerV2PostableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     *time.Time        `json:"startsAt,omitempty"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// AlertmanagerV2Meta carries the state Alert does not own itself:
+// routing results, silence/inhibition state and resolve_timeout.
+type AlertmanagerV2Meta struct {
+	// Receivers the alert was routed to.
+	Receivers []string
+
+	// SilencedBy contains IDs of silences muting the alert.
+	SilencedBy []string
+
+	// InhibitedBy contains fingerprints of alerts inhibiting the alert.
+	InhibitedBy []string
+
+	// MutedBy contains names of time intervals muting the alert.
+	MutedBy []string
+
+	// ResolveTimeout is used to derive endsAt for firing alerts
+	// without EndsAt (default: DefaultResolveTimeout).
+	ResolveTimeout time.Duration
+}
+
+// ToAlertmanagerV2 converts the alert to an Alertmanager v2 GettableAlert.
+//
+// State is "suppressed" if the alert is silenced, inhibited or muted,
+// otherwise "active". Nil slices are rendered as [] like Alertmanager does.
+func (a *Alert) ToAlertmanagerV2(meta AlertmanagerV2Meta) AlertmanagerV2GettableAlert {
+	receivers := make([]AlertmanagerV2Receiver, 0, len(meta.Receivers))
+	for _, name := range meta.Receivers {
+		receivers = append(receivers, AlertmanagerV2Receiver{Name: name})
+	}
+
+	status := AlertmanagerV2Status{
+		State:       AlertmanagerStateActive,
+		SilencedBy:  nonNilStrings(meta.SilencedBy),
+		InhibitedBy: nonNilStrings(meta.InhibitedBy),
+		MutedBy:     nonNilStrings(meta.MutedBy),
+	}
+	if len(status.SilencedBy)+len(status.InhibitedBy)+len(status.MutedBy) > 0 {
+		status.State = AlertmanagerStateSuppressed
+	}
+
+	fingerprint := a.Fingerprint
+	if fingerprint == "" {
+		fingerprint = LabelsFingerprint(a.Labels)
+	}
+
+	out := AlertmanagerV2GettableAlert{
+		Labels:      nonNilMap(a.Labels),
+		Annotations: nonNilMap(a.Annotations),
+		Receivers:   receivers,
+		StartsAt:    a.StartsAt.UTC(),
+		UpdatedAt:   a.LastUpdated().UTC(),
+		EndsAt:      a.ExpiresAt(meta.ResolveTimeout).UTC(),
+		Fingerprint: fingerprint,
+		Status:      status,
+	}
+	if a.GeneratorURL != nil {
+		out.GeneratorURL = *a.GeneratorURL
+	}
+
+	return out
+}
+
+// ToAlertmanagerV2Postable converts the alert to an Alertmanager v2 PostableAlert.
+func (a *Alert) ToAlertmanagerV2Postable() AlertmanagerV2PostableAlert {
+	out := AlertmanagerV2PostableAlert{
+		Labels:      nonNilMap(a.Labels),
+		Annotations: a.Annotations,
+	}
+	if !a.StartsAt.IsZero() {
+		startsAt := a.StartsAt.UTC()
+		out.StartsAt = &startsAt
+	}
+	if a.EndsAt != nil && !a.EndsAt.IsZero() {
+		endsAt := a.EndsAt.UTC()
+		out.EndsAt = &endsAt
+	}
+	if a.GeneratorURL != nil {
+		out.GeneratorURL = *a.GeneratorURL
+	}
+	return out
+}
+
+// MarshalAlertmanagerV2 encodes the alert as an Alertmanager v2 GettableAlert
+// with no receivers and no silence/inhibition state.
+//
+// Use ToAlertmanagerV2 with AlertmanagerV2Meta to include them.
+func (a *Alert) MarshalAlertmanagerV2() ([]byte, error) {
+	return json.Marshal(a.ToAlertmanagerV2(AlertmanagerV2Meta{}))
+}
+
+// UnmarshalAlertmanagerV2 decodes an Alertmanager v2 GettableAlert or
+// PostableAlert into the alert, replacing its contents.
+//
+// Derived fields:
+//   - AlertName from the "alertname" label
+//   - Fingerprint from the payload, or computed from labels (LabelsFingerprint)
+//   - Status is resolved if endsAt is set and not in the future, else firing
+//   - Timestamp from updatedAt (GettableAlert only)
+//
+// Returns an error if the payload is not valid JSON or has no labels.
+func (a *Alert) UnmarshalAlertmanagerV2(data []byte) error {
+	var in struct {
+		Labels       map[string]string `json:"labels"`
+		Annotations  map[string]string `json:"annotations"`
+		StartsAt     *time.Time        `json:"startsAt"`
+		UpdatedAt    *time.Time        `json:"updatedAt"`
+		EndsAt       *time.Time        `json:"endsAt"`
+		GeneratorURL string            `json:"generatorURL"`
+		Fingerprint  string            `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("invalid alertmanager v2 alert: %w", err)
+	}
+	if len(in.Labels) == 0 {
+		return fmt.Errorf("invalid alertmanager v2 alert: labels are required")
+	}
+
+	*a = Alert{
+		Fingerprint: in.Fingerprint,
+		AlertName:   in.Labels["alertname"],
+		Status:      StatusFiring,
+		Labels:      in.Labels,
+		Annotations: nonNilMap(in.Annotations),
+	}
+	if a.Fingerprint == "" {
+		a.Fingerprint = LabelsFingerprint(in.Labels)
+	}
+	if in.StartsAt != nil {
+		a.StartsAt = *in.StartsAt
+	}
+	if in.UpdatedAt != nil && !in.UpdatedAt.IsZero() {
+		updatedAt := *in.UpdatedAt
+		a.Timestamp = &updatedAt
+	}
+	if in.EndsAt != nil && !in.EndsAt.IsZero() {
+		endsAt := *in.EndsAt
+		a.EndsAt = &endsAt
+		if !endsAt.After(time.Now()) {
+			a.Status = StatusResolved
+		}
+	}
+	if in.GeneratorURL != "" {
+		generatorURL := in.GeneratorURL
+		a.GeneratorURL = &generatorURL
+	}
+
+	return nil
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
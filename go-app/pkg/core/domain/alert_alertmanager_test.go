@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAlert_ToAlertmanagerV2 tests GettableAlert conversion
+func TestAlert_ToAlertmanagerV2(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	updated := start.Add(10 * time.Minute)
+	url := "http://prometheus/graph"
+
+	alert := Alert{
+		Fingerprint:  "abc123",
+		AlertName:    "HighCPU",
+		Status:       StatusFiring,
+		Labels:       map[string]string{"alertname": "HighCPU"},
+		StartsAt:     start,
+		Timestamp:    &updated,
+		GeneratorURL: &url,
+	}
+
+	t.Run("active", func(t *testing.T) {
+		out := alert.ToAlertmanagerV2(AlertmanagerV2Meta{
+			Receivers:      []string{"team-a"},
+			ResolveTimeout: 5 * time.Minute,
+		})
+
+		assert.Equal(t, "abc123", out.Fingerprint)
+		assert.Equal(t, []AlertmanagerV2Receiver{{Name: "team-a"}}, out.Receivers)
+		assert.Equal(t, updated, out.UpdatedAt)
+		assert.Equal(t, updated.Add(5*time.Minute), out.EndsAt)
+		assert.Equal(t, url, out.GeneratorURL)
+		assert.Equal(t, AlertmanagerStateActive, out.Status.State)
+		assert.NotNil(t, out.Annotations)
+		assert.NotNil(t, out.Status.SilencedBy)
+	})
+
+	t.Run("suppressed", func(t *testing.T) {
+		out := alert.ToAlertmanagerV2(AlertmanagerV2Meta{SilencedBy: []string{"silence-1"}})
+		assert.Equal(t, AlertmanagerStateSuppressed, out.Status.State)
+		assert.Equal(t, []string{"silence-1"}, out.Status.SilencedBy)
+	})
+}
+
+// TestAlert_MarshalAlertmanagerV2 tests the exact JSON schema
+func TestAlert_MarshalAlertmanagerV2(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	alert := Alert{
+		Fingerprint: "abc123",
+		Labels:      map[string]string{"alertname": "HighCPU"},
+		StartsAt:    start,
+	}
+
+	data, err := alert.MarshalAlertmanagerV2()
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+
+	for _, key := range []string{"labels", "annotations", "receivers", "startsAt", "updatedAt", "endsAt", "fingerprint", "status"} {
+		assert.Contains(t, raw, key)
+	}
+	assert.NotContains(t, raw, "generatorURL")
+	assert.Equal(t, []any{}, raw["receivers"])
+
+	status := raw["status"].(map[string]any)
+	assert.Equal(t, "active", status["state"])
+	assert.Equal(t, []any{}, status["silencedBy"])
+	assert.Equal(t, []any{}, status["inhibitedBy"])
+	assert.Equal(t, []any{}, status["mutedBy"])
+}
+
+// TestAlert_UnmarshalAlertmanagerV2 tests decoding of Gettable and Postable alerts
+func TestAlert_UnmarshalAlertmanagerV2(t *testing.T) {
+	t.Run("postable firing", func(t *testing.T) {
+		var a Alert
+		err := a.UnmarshalAlertmanagerV2([]byte(`{
+			"labels": {"alertname": "HighCPU", "severity": "critical"},
+			"annotations": {"summary": "CPU high"},
+			"startsAt": "2025-01-01T12:00:00Z",
+			"generatorURL": "http://prometheus/graph"
+		}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, "HighCPU", a.AlertName)
+		assert.Equal(t, StatusFiring, a.Status)
+		assert.Equal(t, LabelsFingerprint(a.Labels), a.Fingerprint)
+		assert.Len(t, a.Fingerprint, 32)
+		assert.Equal(t, "CPU high", a.Annotations["summary"])
+		require.NotNil(t, a.GeneratorURL)
+		assert.Nil(t, a.EndsAt)
+		assert.NoError(t, a.Validate())
+	})
+
+	t.Run("postable resolved", func(t *testing.T) {
+		var a Alert
+		err := a.UnmarshalAlertmanagerV2([]byte(`{
+			"labels": {"alertname": "HighCPU"},
+			"startsAt": "2025-01-01T12:00:00Z",
+			"endsAt": "2025-01-01T13:00:00Z"
+		}`))
+		require.NoError(t, err)
+		assert.Equal(t, StatusResolved, a.Status)
+		require.NotNil(t, a.EndsAt)
+	})
+
+	t.Run("gettable round trip", func(t *testing.T) {
+		start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		updated := start.Add(time.Minute)
+		original := Alert{
+			Fingerprint: "abc123",
+			AlertName:   "HighCPU",
+			Status:      StatusFiring,
+			Labels:      map[string]string{"alertname": "HighCPU"},
+			Annotations: map[string]string{"summary": "CPU high"},
+			StartsAt:    start,
+			Timestamp:   &updated,
+		}
+
+		data, err := original.MarshalAlertmanagerV2()
+		require.NoError(t, err)
+
+		var decoded Alert
+		require.NoError(t, decoded.UnmarshalAlertmanagerV2(data))
+		assert.Equal(t, original.Fingerprint, decoded.Fingerprint)
+		assert.Equal(t, original.Labels, decoded.Labels)
+		assert.Equal(t, original.Annotations, decoded.Annotations)
+		assert.True(t, original.StartsAt.Equal(decoded.StartsAt))
+		require.NotNil(t, decoded.Timestamp)
+		assert.True(t, updated.Equal(*decoded.Timestamp))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var a Alert
+		assert.Error(t, a.UnmarshalAlertmanagerV2([]byte(`not json`)))
+		assert.Error(t, a.UnmarshalAlertmanagerV2([]byte(`{"labels": {}}`)))
+	})
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAlert_ExpiresAt tests expiry calculation per Alertmanager semantics
+func TestAlert_ExpiresAt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	updated := start.Add(10 * time.Minute)
+	ends := start.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		alert          Alert
+		resolveTimeout time.Duration
+		expected       time.Time
+	}{
+		{
+			name:           "no updates: StartsAt + resolve_timeout",
+			alert:          Alert{StartsAt: start},
+			resolveTimeout: 5 * time.Minute,
+			expected:       start.Add(5 * time.Minute),
+		},
+		{
+			name:           "last update: Timestamp + resolve_timeout",
+			alert:          Alert{StartsAt: start, Timestamp: &updated},
+			resolveTimeout: 5 * time.Minute,
+			expected:       updated.Add(5 * time.Minute),
+		},
+		{
+			name:           "explicit EndsAt wins",
+			alert:          Alert{StartsAt: start, Timestamp: &updated, EndsAt: &ends},
+			resolveTimeout: 5 * time.Minute,
+			expected:       ends,
+		},
+		{
+			name:           "zero timeout uses default",
+			alert:          Alert{StartsAt: start},
+			resolveTimeout: 0,
+			expected:       start.Add(DefaultResolveTimeout),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.alert.ExpiresAt(tt.resolveTimeout))
+		})
+	}
+}
+
+// TestAlert_IsExpiredAt tests expiry checks against a fixed instant
+func TestAlert_IsExpiredAt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	firing := &Alert{Status: StatusFiring, StartsAt: start}
+
+	assert.False(t, firing.IsExpiredAt(start.Add(4*time.Minute), 5*time.Minute))
+	assert.True(t, firing.IsExpiredAt(start.Add(5*time.Minute), 5*time.Minute))
+	assert.True(t, firing.IsExpiredAt(start.Add(time.Hour), 5*time.Minute))
+
+	resolved := &Alert{Status: StatusResolved, StartsAt: start}
+	assert.True(t, resolved.IsExpiredAt(start, 5*time.Minute), "resolved alerts are always expired")
+
+	fresh := &Alert{Status: StatusFiring, StartsAt: time.Now()}
+	assert.False(t, fresh.IsExpired(time.Hour))
+}
+
+// TestAlert_NormalizeTimestamps tests UTC conversion and clock skew absorption
+func TestAlert_NormalizeTimestamps(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	local := time.FixedZone("UTC-5", -5*60*60)
+
+	a := Alert{StartsAt: now.Add(10 * time.Second).In(local)}
+	a.NormalizeTimestamps(now, DefaultClockSkewTolerance)
+	assert.Equal(t, now, a.StartsAt)
+	assert.Equal(t, time.UTC, a.StartsAt.Location())
+
+	future := Alert{StartsAt: now.Add(time.Hour)}
+	future.NormalizeTimestamps(now, DefaultClockSkewTolerance)
+	assert.Equal(t, now.Add(time.Hour), future.StartsAt)
+
+	endsAt := now.Add(-2 * time.Second)
+	inverted := Alert{StartsAt: now, EndsAt: &endsAt}
+	inverted.NormalizeTimestamps(now, DefaultClockSkewTolerance)
+	assert.Equal(t, now, *inverted.EndsAt)
+}
+
+// TestAlert_Validate_ClockSkew tests that Validate tolerates small EndsAt skew
+func TestAlert_Validate_ClockSkew(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	newResolved := func(endsAt time.Time) *Alert {
+		return &Alert{
+			Fingerprint: "abc",
+			AlertName:   "Test",
+			Status:      StatusResolved,
+			Labels:      map[string]string{"alertname": "Test"},
+			StartsAt:    start,
+			EndsAt:      &endsAt,
+		}
+	}
+
+	skewed := newResolved(start.Add(-time.Second))
+	assert.NoError(t, skewed.Validate())
+	assert.Equal(t, start, skewed.StartsAt, "Validate must not modify the alert")
+
+	assert.Error(t, newResolved(start.Add(-time.Hour)).Validate())
+}
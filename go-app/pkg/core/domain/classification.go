@@ -256,18 +256,26 @@ func (e *EnrichedAlert) HasClassification() bool {
 	return e.Classification != nil
 }
 
-// EffectiveSeverity returns the severity to use for this alert.
-// Uses classification severity if available, otherwise falls back to label.
+// EffectiveSeverity returns the severity to use for this alert under
+// DefaultSeverityPolicy: classification severity if available, otherwise the
+// severity label, otherwise info.
 func (e *EnrichedAlert) EffectiveSeverity() AlertSeverity {
+	severity, _ := e.ResolveSeverity(DefaultSeverityPolicy)
+	return severity
+}
+
+// ResolveSeverity returns the severity to use for this alert under policy
+// and where it came from. See SeverityPolicy.
+func (e *EnrichedAlert) ResolveSeverity(policy SeverityPolicy) (AlertSeverity, SeveritySource) {
+	var classification AlertSeverity
 	if e.Classification != nil {
-		return e.Classification.Severity
+		classification = e.Classification.Severity
 	}
-	// Fallback to severity label
+	var label string
 	if e.Alert != nil {
 		if sev := e.Alert.Severity(); sev != nil {
-			return AlertSeverity(*sev)
+			label = *sev
 		}
 	}
-	// Default
-	return SeverityInfo
+	return ResolveSeverity(policy, classification, label)
 }
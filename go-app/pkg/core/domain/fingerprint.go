@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// LabelsFingerprint is the fingerprint AMP gives alerts pushed without one:
+// the first 16 bytes of the SHA-256 of the sorted "key=value|" label pairs,
+// hex encoded. Alerts with the same labels get the same fingerprint whether
+// they are pushed, replayed or generated. Empty labels have none ("").
+func LabelsFingerprint(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		builder.WriteString(key)
+		builder.WriteByte('=')
+		builder.WriteString(labels[key])
+		builder.WriteByte('|')
+	}
+
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelsFingerprint(t *testing.T) {
+	labels := map[string]string{"instance": "node-1", "alertname": "HighCPU"}
+
+	// Pinned: stored alerts and links keep their fingerprints across releases
+	assert.Equal(t, "38635c36991162e93d91f72b7d9e71dd", LabelsFingerprint(labels))
+	assert.NotEqual(t, LabelsFingerprint(labels), LabelsFingerprint(map[string]string{"alertname": "HighCPU"}))
+	assert.Empty(t, LabelsFingerprint(nil))
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// ================================================================================
+// Compiled Matchers
+// ================================================================================
+// Matching is hot-path for silences and inhibition: 10k alerts against 500
+// silences means millions of Matches() calls. Regex matchers are compiled once
+// and shared through a thread-safe cache instead of being recompiled per call.
+
+// DefaultMatcherCacheSize is the capacity of the package-level matcher cache.
+const DefaultMatcherCacheSize = 4096
+
+// CompiledMatcher is a Matcher with its regex pre-compiled.
+//
+// Immutable after creation and safe for concurrent use.
+//
+// Example:
+//
+//	cm, err := matcher.Compile()
+//	if err != nil {
+//	    return err
+//	}
+//	for _, alert := range alerts {
+//	    if cm.Matches(alert.Labels) { ... }
+//	}
+type CompiledMatcher struct {
+	Matcher
+
+	// re is the compiled pattern for =~ and !~ matchers (nil otherwise).
+	re *regexp.Regexp
+}
+
+// Compile validates the matcher type and compiles its regex (if any).
+//
+// Does not use the cache; see MatcherCache.Compile for the cached variant.
+func (m Matcher) Compile() (*CompiledMatcher, error) {
+	cm := &CompiledMatcher{Matcher: m}
+	cm.IsRegex = m.Type == MatcherTypeRegex || m.Type == MatcherTypeNotRegex
+
+	switch m.Type {
+	case MatcherTypeEqual, MatcherTypeNotEqual:
+	case MatcherTypeRegex, MatcherTypeNotRegex:
+		re, err := regexp.Compile(m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		cm.re = re
+	default:
+		return nil, fmt.Errorf("unknown matcher type: %s", m.Type)
+	}
+
+	return cm, nil
+}
+
+// Matches checks if the compiled matcher matches the given labels.
+//
+// Semantics are identical to Matcher.Matches, but compilation errors are
+// impossible at this point so no error is returned.
+func (c *CompiledMatcher) Matches(labels map[string]string) bool {
+	labelValue, exists := labels[c.Name]
+
+	switch c.Type {
+	case MatcherTypeEqual:
+		return exists && labelValue == c.Value
+	case MatcherTypeNotEqual:
+		return !exists || labelValue != c.Value
+	case MatcherTypeRegex:
+		return exists && c.re.MatchString(labelValue)
+	case MatcherTypeNotRegex:
+		return !exists || !c.re.MatchString(labelValue)
+	default:
+		return false
+	}
+}
+
+// matcherKey identifies a matcher independent of the IsRegex hint.
+type matcherKey struct {
+	name  string
+	value string
+	typ   MatcherType
+}
+
+// MatcherCache caches CompiledMatchers keyed by (Name, Type, Value).
+//
+// Thread-safety: Safe for concurrent use (RWMutex, read-mostly).
+//
+// Eviction Strategy: the whole cache is cleared when maxSize is reached.
+// This is sufficient for the typical stable set of silences and inhibition rules.
+type MatcherCache struct {
+	mu      sync.RWMutex
+	entries map[matcherKey]*CompiledMatcher
+	maxSize int
+}
+
+// NewMatcherCache creates a cache holding at most maxSize compiled matchers.
+// A non-positive maxSize falls back to DefaultMatcherCacheSize.
+func NewMatcherCache(maxSize int) *MatcherCache {
+	if maxSize <= 0 {
+		maxSize = DefaultMatcherCacheSize
+	}
+	return &MatcherCache{
+		entries: make(map[matcherKey]*CompiledMatcher),
+		maxSize: maxSize,
+	}
+}
+
+// Compile returns the cached CompiledMatcher for m, compiling it on a miss.
+//
+// Invalid matchers are not cached.
+func (c *MatcherCache) Compile(m Matcher) (*CompiledMatcher, error) {
+	key := matcherKey{name: m.Name, value: m.Value, typ: m.Type}
+
+	c.mu.RLock()
+	cm, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok {
+		return cm, nil
+	}
+
+	cm, err := m.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.entries[key]; ok {
+		return existing, nil
+	}
+	if len(c.entries) >= c.maxSize {
+		c.entries = make(map[matcherKey]*CompiledMatcher)
+	}
+	c.entries[key] = cm
+
+	return cm, nil
+}
+
+// Len returns the number of cached matchers.
+func (c *MatcherCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Clear removes all cached matchers.
+func (c *MatcherCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[matcherKey]*CompiledMatcher)
+}
+
+// defaultMatcherCache backs Matcher.Matches and Silence.CompileMatchers.
+var defaultMatcherCache = NewMatcherCache(DefaultMatcherCacheSize)
+
+// CompileMatchers compiles all silence matchers through the shared cache.
+//
+// Use the result to match many alerts against the same silence:
+//
+//	compiled, err := silence.CompileMatchers()
+//	for _, alert := range alerts {
+//	    if MatchAll(compiled, alert.Labels) { ... }
+//	}
+func (s *Silence) CompileMatchers() ([]*CompiledMatcher, error) {
+	compiled := make([]*CompiledMatcher, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		cm, err := defaultMatcherCache.Compile(m)
+		if err != nil {
+			return nil, fmt.Errorf("matcher %s failed: %w", m.Name, err)
+		}
+		compiled = append(compiled, cm)
+	}
+	return compiled, nil
+}
+
+// MatchAll returns true if every compiled matcher matches labels (AND logic).
+func MatchAll(matchers []*CompiledMatcher, labels map[string]string) bool {
+	for _, m := range matchers {
+		if !m.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
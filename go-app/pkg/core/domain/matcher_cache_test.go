@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompiledMatcher_Matches tests parity with Matcher.Matches
+func TestCompiledMatcher_Matches(t *testing.T) {
+	labels := map[string]string{"alertname": "HighCPU", "instance": "prod-01"}
+
+	tests := []struct {
+		name     string
+		matcher  Matcher
+		expected bool
+	}{
+		{"equal match", Matcher{Name: "alertname", Value: "HighCPU", Type: MatcherTypeEqual}, true},
+		{"equal missing label", Matcher{Name: "job", Value: "api", Type: MatcherTypeEqual}, false},
+		{"not equal match", Matcher{Name: "alertname", Value: "DiskFull", Type: MatcherTypeNotEqual}, true},
+		{"not equal missing label", Matcher{Name: "job", Value: "api", Type: MatcherTypeNotEqual}, true},
+		{"regex match", Matcher{Name: "instance", Value: "prod-.*", Type: MatcherTypeRegex}, true},
+		{"regex missing label", Matcher{Name: "job", Value: ".*", Type: MatcherTypeRegex}, false},
+		{"not regex match", Matcher{Name: "instance", Value: "staging-.*", Type: MatcherTypeNotRegex}, true},
+		{"not regex missing label", Matcher{Name: "job", Value: ".*", Type: MatcherTypeNotRegex}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, err := tt.matcher.Compile()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cm.Matches(labels))
+
+			legacy, err := tt.matcher.Matches(labels)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, legacy)
+		})
+	}
+}
+
+// TestMatcher_CompileErrors tests invalid regex and type handling
+func TestMatcher_CompileErrors(t *testing.T) {
+	_, err := Matcher{Name: "a", Value: "[", Type: MatcherTypeRegex}.Compile()
+	assert.Error(t, err)
+
+	_, err = Matcher{Name: "a", Value: "b", Type: "=="}.Compile()
+	assert.Error(t, err)
+
+	m := Matcher{Name: "a", Value: "(", Type: MatcherTypeNotRegex}
+	_, err = m.Matches(map[string]string{"a": "b"})
+	assert.Error(t, err)
+}
+
+// TestMatcherCache_Reuse tests that identical matchers share one compilation
+func TestMatcherCache_Reuse(t *testing.T) {
+	cache := NewMatcherCache(10)
+	m := Matcher{Name: "instance", Value: "prod-.*", Type: MatcherTypeRegex}
+
+	first, err := cache.Compile(m)
+	require.NoError(t, err)
+	second, err := cache.Compile(m)
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, cache.Len())
+
+	_, err = cache.Compile(Matcher{Name: "a", Value: "[", Type: MatcherTypeRegex})
+	assert.Error(t, err)
+	assert.Equal(t, 1, cache.Len(), "invalid matchers must not be cached")
+}
+
+// TestMatcherCache_Eviction tests clearing when maxSize is reached
+func TestMatcherCache_Eviction(t *testing.T) {
+	cache := NewMatcherCache(3)
+	for i := 0; i < 3; i++ {
+		_, err := cache.Compile(Matcher{Name: "n", Value: fmt.Sprintf("v%d", i), Type: MatcherTypeEqual})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, cache.Len())
+
+	_, err := cache.Compile(Matcher{Name: "n", Value: "v3", Type: MatcherTypeEqual})
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
+
+// TestMatcherCache_Concurrent tests concurrent compilation (run with -race)
+func TestMatcherCache_Concurrent(t *testing.T) {
+	cache := NewMatcherCache(0)
+	labels := map[string]string{"instance": "prod-42"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cm, err := cache.Compile(Matcher{Name: "instance", Value: fmt.Sprintf("prod-%d|prod-.*", j%10), Type: MatcherTypeRegex})
+				assert.NoError(t, err)
+				assert.True(t, cm.Matches(labels))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 10, cache.Len())
+}
+
+// TestSilence_CompileMatchers tests compiled silence matching
+func TestSilence_CompileMatchers(t *testing.T) {
+	silence := &Silence{Matchers: []Matcher{
+		{Name: "alertname", Value: "HighCPU", Type: MatcherTypeEqual},
+		{Name: "instance", Value: "prod-.*", Type: MatcherTypeRegex},
+	}}
+
+	compiled, err := silence.CompileMatchers()
+	require.NoError(t, err)
+	assert.Len(t, compiled, 2)
+
+	assert.True(t, MatchAll(compiled, map[string]string{"alertname": "HighCPU", "instance": "prod-01"}))
+	assert.False(t, MatchAll(compiled, map[string]string{"alertname": "HighCPU", "instance": "dev-01"}))
+}
+
+// BenchmarkMatcher_Matches_Regex measures cached regex matching on the hot path
+func BenchmarkMatcher_Matches_Regex(b *testing.B) {
+	m := Matcher{Name: "instance", Value: "prod-[0-9]+", Type: MatcherTypeRegex}
+	labels := map[string]string{"instance": "prod-42"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.Matches(labels)
+	}
+}
+
+// BenchmarkCompiledMatcher_Matches measures matching with a pre-compiled matcher
+func BenchmarkCompiledMatcher_Matches(b *testing.B) {
+	cm, _ := Matcher{Name: "instance", Value: "prod-[0-9]+", Type: MatcherTypeRegex}.Compile()
+	labels := map[string]string{"instance": "prod-42"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = cm.Matches(labels)
+	}
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ================================================================================
+// Severity Precedence Policy
+// ================================================================================
+// An enriched alert can carry two severities: the classifier's and the
+// "severity" label set by the alert source. The policy decides which one is
+// effective:
+//
+//	classification-first (default): classification, then label, then info
+//	label-first:                    label, then classification, then info
+//	max-of:                         the higher of both (critical > warning > info > noise)
+//
+// Label values other than critical, warning, info and noise (e.g. "page")
+// are not severities and are ignored.
+
+// SeverityPolicy selects how the effective severity of an enriched alert is
+// resolved.
+type SeverityPolicy string
+
+const (
+	// SeverityPolicyClassificationFirst prefers the classification severity.
+	SeverityPolicyClassificationFirst SeverityPolicy = "classification-first"
+
+	// SeverityPolicyLabelFirst prefers the severity label of the alert source.
+	SeverityPolicyLabelFirst SeverityPolicy = "label-first"
+
+	// SeverityPolicyMax uses the higher of the classification and label severity.
+	SeverityPolicyMax SeverityPolicy = "max-of"
+)
+
+// DefaultSeverityPolicy is the policy used when none is configured.
+const DefaultSeverityPolicy = SeverityPolicyClassificationFirst
+
+// SeveritySource identifies where an effective severity came from.
+type SeveritySource string
+
+const (
+	// SeveritySourceClassification means the classification severity was used.
+	SeveritySourceClassification SeveritySource = "classification"
+
+	// SeveritySourceLabel means the alert's severity label was used.
+	SeveritySourceLabel SeveritySource = "label"
+
+	// SeveritySourceDefault means neither was available and info was used.
+	SeveritySourceDefault SeveritySource = "default"
+)
+
+// ParseSeverityPolicy parses a policy name. An empty name is the default policy.
+func ParseSeverityPolicy(name string) (SeverityPolicy, error) {
+	switch policy := SeverityPolicy(strings.ToLower(strings.TrimSpace(name))); policy {
+	case "":
+		return DefaultSeverityPolicy, nil
+	case SeverityPolicyClassificationFirst, SeverityPolicyLabelFirst, SeverityPolicyMax:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown severity policy %q (want %s, %s or %s)",
+			name, SeverityPolicyClassificationFirst, SeverityPolicyLabelFirst, SeverityPolicyMax)
+	}
+}
+
+// ParseAlertSeverity normalizes a severity value. Reports false for values
+// that are not a known severity.
+func ParseAlertSeverity(value string) (AlertSeverity, bool) {
+	switch severity := AlertSeverity(strings.ToLower(strings.TrimSpace(value))); severity {
+	case SeverityCritical, SeverityWarning, SeverityInfo, SeverityNoise:
+		return severity, true
+	default:
+		return "", false
+	}
+}
+
+// severityRank orders severities for SeverityPolicyMax.
+func severityRank(severity AlertSeverity) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ResolveSeverity applies policy to a classification severity and a label
+// value, either of which may be empty, and returns the effective severity and
+// its source.
+func ResolveSeverity(policy SeverityPolicy, classification AlertSeverity, label string) (AlertSeverity, SeveritySource) {
+	fromClassification, hasClassification := ParseAlertSeverity(string(classification))
+	fromLabel, hasLabel := ParseAlertSeverity(label)
+
+	switch {
+	case hasClassification && hasLabel:
+		switch policy {
+		case SeverityPolicyLabelFirst:
+			return fromLabel, SeveritySourceLabel
+		case SeverityPolicyMax:
+			// Ties go to the classification, matching the default policy.
+			if severityRank(fromLabel) > severityRank(fromClassification) {
+				return fromLabel, SeveritySourceLabel
+			}
+			return fromClassification, SeveritySourceClassification
+		default:
+			return fromClassification, SeveritySourceClassification
+		}
+	case hasClassification:
+		return fromClassification, SeveritySourceClassification
+	case hasLabel:
+		return fromLabel, SeveritySourceLabel
+	default:
+		return SeverityInfo, SeveritySourceDefault
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSeverity(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         SeverityPolicy
+		classification AlertSeverity
+		label          string
+		want           AlertSeverity
+		wantSource     SeveritySource
+	}{
+		{"classification first", SeverityPolicyClassificationFirst, SeverityWarning, "critical", SeverityWarning, SeveritySourceClassification},
+		{"label first", SeverityPolicyLabelFirst, SeverityWarning, "critical", SeverityCritical, SeveritySourceLabel},
+		{"max of prefers label", SeverityPolicyMax, SeverityWarning, "critical", SeverityCritical, SeveritySourceLabel},
+		{"max of prefers classification", SeverityPolicyMax, SeverityCritical, "info", SeverityCritical, SeveritySourceClassification},
+		{"max of tie goes to classification", SeverityPolicyMax, SeverityWarning, "warning", SeverityWarning, SeveritySourceClassification},
+		{"label only", SeverityPolicyClassificationFirst, "", "Critical", SeverityCritical, SeveritySourceLabel},
+		{"classification only", SeverityPolicyLabelFirst, SeverityNoise, "", SeverityNoise, SeveritySourceClassification},
+		{"unknown label ignored", SeverityPolicyLabelFirst, SeverityWarning, "page", SeverityWarning, SeveritySourceClassification},
+		{"nothing set", SeverityPolicyMax, "", "page", SeverityInfo, SeveritySourceDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, source := ResolveSeverity(tt.policy, tt.classification, tt.label)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestParseSeverityPolicy(t *testing.T) {
+	policy, err := ParseSeverityPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSeverityPolicy, policy)
+
+	policy, err = ParseSeverityPolicy(" Max-Of ")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityPolicyMax, policy)
+
+	_, err = ParseSeverityPolicy("highest")
+	assert.Error(t, err)
+}
+
+func TestEnrichedAlert_ResolveSeverity(t *testing.T) {
+	enriched := &EnrichedAlert{
+		Alert:          &Alert{Labels: map[string]string{"severity": "critical"}},
+		Classification: &ClassificationResult{Severity: SeverityInfo},
+	}
+
+	assert.Equal(t, SeverityInfo, enriched.EffectiveSeverity())
+
+	got, source := enriched.ResolveSeverity(SeverityPolicyLabelFirst)
+	assert.Equal(t, SeverityCritical, got)
+	assert.Equal(t, SeveritySourceLabel, source)
+}
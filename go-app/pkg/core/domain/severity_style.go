@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// ================================================================================
+// Severity Styles
+// ================================================================================
+// Chat notifications mark an alert with an emoji and a color picked by its
+// severity (or "resolved"). The defaults below can be overridden globally
+// (publishing.severity_styles) and per publishing target (emoji_<key>,
+// color_<key> and card_color_<key> headers).
+//
+// Emoji are written as escapes so that the source cannot be re-encoded by an
+// editor; configured emoji are rejected when they are not valid UTF-8 or look
+// double-encoded (e.g. "â„¹ï¸" for "ℹ️", a UTF-8 file read as Windows-1252).
+
+// SeverityStyleResolved is the style key of resolved alerts.
+const SeverityStyleResolved = "resolved"
+
+// SeverityStyleKeys lists the keys of severity styles.
+var SeverityStyleKeys = []string{
+	string(SeverityCritical), string(SeverityWarning), string(SeverityInfo), string(SeverityNoise), SeverityStyleResolved,
+}
+
+// SeverityStyle is how a severity is shown in chat notifications.
+type SeverityStyle struct {
+	// Emoji prefixes the notification title.
+	Emoji string `mapstructure:"emoji" json:"emoji,omitempty"`
+
+	// Color is the "#RRGGBB" color of Slack attachments.
+	Color string `mapstructure:"color" json:"color,omitempty"`
+
+	// CardColor is the Adaptive Card text color of Teams messages: default,
+	// dark, light, accent, good, warning or attention.
+	CardColor string `mapstructure:"card_color" json:"card_color,omitempty"`
+}
+
+// DefaultSeverityStyles returns the built-in severity styles.
+func DefaultSeverityStyles() map[string]SeverityStyle {
+	return map[string]SeverityStyle{
+		string(SeverityCritical): {Emoji: "\U0001f534", Color: "#FF0000", CardColor: "attention"}, // red circle
+		string(SeverityWarning):  {Emoji: "\u26a0\ufe0f", Color: "#FFA500", CardColor: "warning"}, // warning sign
+		string(SeverityInfo):     {Emoji: "\u2139\ufe0f", Color: "#36A64F", CardColor: "accent"},  // information source
+		string(SeverityNoise):    {Emoji: "\U0001f507", Color: "#808080", CardColor: "default"},   // muted speaker
+		SeverityStyleResolved:    {Emoji: "\u2705", Color: "#36A64F", CardColor: "good"},          // check mark
+	}
+}
+
+// MergeSeverityStyles returns base with the non-empty fields of overrides
+// applied. Neither map is modified.
+func MergeSeverityStyles(base, overrides map[string]SeverityStyle) map[string]SeverityStyle {
+	merged := make(map[string]SeverityStyle, len(base)+len(overrides))
+	for key, style := range base {
+		merged[key] = style
+	}
+	for key, override := range overrides {
+		style := merged[key]
+		if override.Emoji != "" {
+			style.Emoji = override.Emoji
+		}
+		if override.Color != "" {
+			style.Color = override.Color
+		}
+		if override.CardColor != "" {
+			style.CardColor = override.CardColor
+		}
+		merged[key] = style
+	}
+	return merged
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// cardColors are the Adaptive Card text colors.
+var cardColors = map[string]bool{
+	"default": true, "dark": true, "light": true, "accent": true, "good": true, "warning": true, "attention": true,
+}
+
+// IsSeverityStyleKey reports whether key is one of SeverityStyleKeys.
+func IsSeverityStyleKey(key string) bool {
+	for _, k := range SeverityStyleKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSeverityStyle checks the non-empty fields of style.
+func ValidateSeverityStyle(style SeverityStyle) error {
+	if err := ValidateSeverityEmoji(style.Emoji); err != nil {
+		return err
+	}
+	if style.Color != "" && !hexColorPattern.MatchString(style.Color) {
+		return fmt.Errorf("color %q is not #RRGGBB", style.Color)
+	}
+	if style.CardColor != "" && !cardColors[style.CardColor] {
+		return fmt.Errorf("card_color %q must be one of default, dark, light, accent, good, warning, attention", style.CardColor)
+	}
+	return nil
+}
+
+// ValidateSeverityEmoji checks that emoji is short, valid UTF-8 and not
+// double-encoded.
+func ValidateSeverityEmoji(emoji string) error {
+	switch {
+	case !utf8.ValidString(emoji):
+		return fmt.Errorf("emoji %q is not valid UTF-8", emoji)
+	case utf8.RuneCountInString(emoji) > 16:
+		return fmt.Errorf("emoji %q is longer than 16 characters", emoji)
+	case IsMojibake(emoji):
+		return fmt.Errorf("emoji %q looks double-encoded (UTF-8 read as Windows-1252); save the configuration as UTF-8", emoji)
+	}
+	return nil
+}
+
+// cp1252Bytes maps the characters Windows-1252 puts at 0x80-0x9F to their
+// byte values.
+var cp1252Bytes = map[rune]byte{
+	'\u20ac': 0x80, '\u201a': 0x82, '\u0192': 0x83, '\u201e': 0x84, '\u2026': 0x85, '\u2020': 0x86, '\u2021': 0x87,
+	'\u02c6': 0x88, '\u2030': 0x89, '\u0160': 0x8A, '\u2039': 0x8B, '\u0152': 0x8C, '\u017d': 0x8E,
+	'\u2018': 0x91, '\u2019': 0x92, '\u201c': 0x93, '\u201d': 0x94, '\u2022': 0x95, '\u2013': 0x96, '\u2014': 0x97,
+	'\u02dc': 0x98, '\u2122': 0x99, '\u0161': 0x9A, '\u203a': 0x9B, '\u0153': 0x9C, '\u017e': 0x9E, '\u0178': 0x9F,
+}
+
+// IsMojibake reports whether s looks like UTF-8 text that was decoded as
+// Windows-1252 (or Latin-1) and encoded again: every character maps back to
+// a single byte, and those bytes are UTF-8 with multi-byte characters.
+// Windows-1252 has no characters for 0x81, 0x8D, 0x8F, 0x90 and 0x9D, so
+// such text often lost its last byte ("â„¹ï¸"); a truncated final
+// character is therefore accepted.
+func IsMojibake(s string) bool {
+	raw := make([]byte, 0, len(s))
+	for _, r := range s {
+		if b, ok := cp1252Bytes[r]; ok {
+			raw = append(raw, b)
+		} else if r < 0x100 {
+			raw = append(raw, byte(r))
+		} else {
+			return false
+		}
+	}
+
+	multiByte := false
+	for len(raw) > 0 {
+		r, size := utf8.DecodeRune(raw)
+		if r == utf8.RuneError && size <= 1 {
+			if utf8.FullRune(raw) {
+				return false
+			}
+			break // truncated final character
+		}
+		multiByte = multiByte || size > 1
+		raw = raw[size:]
+	}
+	return multiByte
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultSeverityStyles(t *testing.T) {
+	styles := DefaultSeverityStyles()
+	for _, key := range SeverityStyleKeys {
+		style, ok := styles[key]
+		require.True(t, ok, key)
+		assert.NoError(t, ValidateSeverityStyle(style), key)
+	}
+	assert.Equal(t, "ℹ️", styles[string(SeverityInfo)].Emoji)
+}
+
+func TestMergeSeverityStyles(t *testing.T) {
+	base := DefaultSeverityStyles()
+	merged := MergeSeverityStyles(base, map[string]SeverityStyle{
+		string(SeverityCritical): {Emoji: "\U0001f6a8"},
+	})
+
+	assert.Equal(t, SeverityStyle{Emoji: "\U0001f6a8", Color: "#FF0000", CardColor: "attention"}, merged[string(SeverityCritical)])
+	assert.Equal(t, base[string(SeverityInfo)], merged[string(SeverityInfo)])
+	assert.Equal(t, "\U0001f534", base[string(SeverityCritical)].Emoji, "base must not be modified")
+}
+
+func TestValidateSeverityStyle(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   SeverityStyle
+		wantErr string
+	}{
+		{"empty", SeverityStyle{}, ""},
+		{"emoji with variation selector", SeverityStyle{Emoji: "⚠️", Color: "#ffa500", CardColor: "warning"}, ""},
+		{"slack shortcode", SeverityStyle{Emoji: ":rotating_light:"}, ""},
+		{"accented text", SeverityStyle{Emoji: "Réseau"}, ""},
+		{"invalid UTF-8", SeverityStyle{Emoji: "\xe2\x84"}, "not valid UTF-8"},
+		{"double-encoded", SeverityStyle{Emoji: "â„¹ï¸\u008f"}, "double-encoded"},
+		{"double-encoded check mark", SeverityStyle{Emoji: "âœ…"}, "double-encoded"},
+		{"color name", SeverityStyle{Color: "red"}, "#RRGGBB"},
+		{"short color", SeverityStyle{Color: "#F00"}, "#RRGGBB"},
+		{"card color", SeverityStyle{CardColor: "red"}, "card_color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSeverityStyle(tt.style)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
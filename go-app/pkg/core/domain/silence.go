@@ -335,6 +335,9 @@ func (s *Silence) MatchesAlert(alert *Alert) (bool, error) {
 // Matcher Methods
 // ================================================================================
 
+// labelNameRegex validates Prometheus label names.
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // Validate checks if the matcher is valid.
 //
 // Validation rules:
@@ -351,8 +354,7 @@ func (m *Matcher) Validate() error {
 	if m.Name == "" {
 		return fmt.Errorf("matcher name is required")
 	}
-	validName := regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
-	if !validName.MatchString(m.Name) {
+	if !labelNameRegex.MatchString(m.Name) {
 		return fmt.Errorf("invalid matcher name: %s (must match [a-zA-Z_][a-zA-Z0-9_]*)", m.Name)
 	}
 
@@ -398,37 +400,12 @@ func (m *Matcher) Validate() error {
 //   - (true, nil) if matches
 //   - (false, nil) if doesn't match
 //   - (false, error) if validation/regex error
+//
+// Regex matchers are compiled once and served from a shared cache.
 func (m *Matcher) Matches(labels map[string]string) (bool, error) {
-	labelValue, exists := labels[m.Name]
-
-	switch m.Type {
-	case MatcherTypeEqual:
-		return exists && labelValue == m.Value, nil
-
-	case MatcherTypeNotEqual:
-		return !exists || labelValue != m.Value, nil
-
-	case MatcherTypeRegex:
-		if !exists {
-			return false, nil
-		}
-		re, err := regexp.Compile(m.Value)
-		if err != nil {
-			return false, fmt.Errorf("invalid regex: %w", err)
-		}
-		return re.MatchString(labelValue), nil
-
-	case MatcherTypeNotRegex:
-		if !exists {
-			return true, nil // Missing label "doesn't match" any regex
-		}
-		re, err := regexp.Compile(m.Value)
-		if err != nil {
-			return false, fmt.Errorf("invalid regex: %w", err)
-		}
-		return !re.MatchString(labelValue), nil
-
-	default:
-		return false, fmt.Errorf("unknown matcher type: %s", m.Type)
+	cm, err := defaultMatcherCache.Compile(*m)
+	if err != nil {
+		return false, err
 	}
+	return cm.Matches(labels), nil
 }
@@ -179,8 +179,7 @@ func (e *HTTPAPIError) IsRetryable() bool {
 	if e == nil {
 		return false
 	}
-	if e.StatusCode == http.StatusTooManyRequests ||
-		e.StatusCode >= http.StatusInternalServerError {
+	if IsRetryableStatus(e.StatusCode) {
 		return true
 	}
 	// Check if cause is retryable (e.g. network error)
@@ -0,0 +1,141 @@
+package httperror
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ProviderError is implemented by errors returned by external service APIs.
+//
+// HTTPAPIError implements it. Provider-specific error types either alias
+// HTTPAPIError or implement ProviderError themselves, so retry and queue
+// classification never depend on the concrete error type.
+type ProviderError interface {
+	error
+
+	// HTTPStatus returns the HTTP status code of the failed request,
+	// or 0 when the request failed before a response was received.
+	HTTPStatus() int
+
+	// ProviderName returns the external service the error came from.
+	ProviderName() string
+}
+
+// HTTPStatus implements ProviderError.
+func (e *HTTPAPIError) HTTPStatus() int {
+	if e == nil {
+		return 0
+	}
+	return e.StatusCode
+}
+
+// ProviderName implements ProviderError.
+func (e *HTTPAPIError) ProviderName() string {
+	if e == nil || e.Provider == "" {
+		return "unknown"
+	}
+	return e.Provider
+}
+
+// IsRetryableStatus reports whether a request that failed with statusCode
+// may succeed when sent again right away: 429 and 5xx.
+func IsRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
+// Class is the outcome of classifying a failed delivery for a retry queue.
+type Class int
+
+const (
+	// ClassUnknown means the error could not be classified; retry with caution.
+	ClassUnknown Class = iota
+
+	// ClassTransient means the delivery should be retried after a backoff.
+	ClassTransient
+
+	// ClassPermanent means retrying cannot succeed.
+	ClassPermanent
+)
+
+// String returns the string representation of Class.
+func (c Class) String() string {
+	switch c {
+	case ClassTransient:
+		return "transient"
+	case ClassPermanent:
+		return "permanent"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassifyStatus classifies an HTTP status code for a retry queue. Like
+// IsRetryableStatus, every 5xx is transient: a failing server may recover,
+// so the delivery is retried until the queue gives up.
+func ClassifyStatus(statusCode int) Class {
+	switch statusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return ClassTransient
+	case http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusForbidden,
+		http.StatusNotFound,
+		http.StatusMethodNotAllowed,
+		http.StatusConflict,
+		http.StatusGone,
+		http.StatusUnprocessableEntity:
+		return ClassPermanent
+	}
+	if statusCode >= 500 && statusCode < 600 {
+		return ClassTransient
+	}
+	return ClassUnknown
+}
+
+// Classify classifies a failed delivery for a retry queue.
+//
+// A ProviderError is classified by its HTTP status. Errors without a
+// status are classified as network errors: timeouts, temporary failures,
+// DNS and dial errors and refused, reset or timed out connections are
+// transient. Status codes in error messages are ignored, as the messages
+// may quote responses or command output.
+func Classify(err error) Class {
+	if err == nil {
+		return ClassUnknown
+	}
+
+	var providerErr ProviderError
+	if errors.As(err, &providerErr) && providerErr.HTTPStatus() != 0 {
+		return ClassifyStatus(providerErr.HTTPStatus())
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && (netErr.Timeout() || netErr.Temporary()) {
+		return ClassTransient
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ClassTransient
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ClassTransient
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ETIMEDOUT) {
+		return ClassTransient
+	}
+
+	return ClassUnknown
+}
@@ -0,0 +1,80 @@
+package httperror
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestHTTPAPIError_ImplementsProviderError(t *testing.T) {
+	var err ProviderError = NewHTTPError(503, "unavailable", "slack")
+
+	if err.HTTPStatus() != 503 {
+		t.Errorf("HTTPStatus() = %d, want 503", err.HTTPStatus())
+	}
+	if err.ProviderName() != "slack" {
+		t.Errorf("ProviderName() = %q, want slack", err.ProviderName())
+	}
+	if name := (&HTTPAPIError{}).ProviderName(); name != "unknown" {
+		t.Errorf("ProviderName() without provider = %q, want unknown", name)
+	}
+}
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       Class
+	}{
+		{200, ClassUnknown},
+		{408, ClassTransient},
+		{429, ClassTransient},
+		{502, ClassTransient},
+		{503, ClassTransient},
+		{504, ClassTransient},
+		{400, ClassPermanent},
+		{401, ClassPermanent},
+		{404, ClassPermanent},
+		{410, ClassPermanent},
+		{422, ClassPermanent},
+		{500, ClassTransient},
+		{501, ClassTransient},
+		{418, ClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.statusCode), func(t *testing.T) {
+			if got := ClassifyStatus(tt.statusCode); got != tt.want {
+				t.Errorf("ClassifyStatus(%d) = %v, want %v", tt.statusCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Class
+	}{
+		{"nil", nil, ClassUnknown},
+		{"provider error", NewHTTPError(500, "internal error", "rootly"), ClassTransient},
+		{"permanent provider error", NewHTTPError(404, "not found", "rootly"), ClassPermanent},
+		{"wrapped provider error", fmt.Errorf("publish: %w", NewRateLimitError("slack", 30)), ClassTransient},
+		{"network error without status", WrapNetworkError("webhook", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), ClassTransient},
+		{"status in message", errors.New("received 429 Too Many Requests"), ClassUnknown},
+		{"command output with a status", errors.New("exec: exit status 1: GET /api/v1 404"), ClassUnknown},
+		{"dns error", &net.DNSError{Err: "no such host", Name: "invalid.example.com"}, ClassTransient},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), ClassTransient},
+		{"generic error", errors.New("something went wrong"), ClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1028,6 +1028,25 @@ type BusinessMetrics struct {
 	SilenceCacheMissesTotal *prometheus.CounterVec
 	SilenceRequestDuration  *prometheus.HistogramVec
 	SilenceRateLimitHits    prometheus.Counter
+	SilenceGCRunsTotal      *prometheus.CounterVec
+	SilenceGCDeletedTotal   prometheus.Counter
+
+	// Storm detection metrics
+	StormActive           prometheus.Gauge
+	StormTransitionsTotal *prometheus.CounterVec
+	StormWindowAlerts     prometheus.Gauge
+	StormBaselineAlerts   prometheus.Gauge
+	StormSuppressedTotal  *prometheus.CounterVec
+	StormDigestsTotal     prometheus.Counter
+	StormDigestedAlerts   prometheus.Counter
+
+	// Alert sampling metrics
+	SamplingSampledOutTotal *prometheus.CounterVec
+
+	// Alert rollup metrics
+	AlertRollupRunsTotal    *prometheus.CounterVec
+	AlertRollupBucketsTotal *prometheus.CounterVec
+	AlertRollupLastSuccess  prometheus.Gauge
 
 	// Inhibition state metrics
 	InhibitionStateActive      prometheus.Gauge
@@ -1035,6 +1054,7 @@ type BusinessMetrics struct {
 	InhibitionStateRecords     *prometheus.CounterVec
 	InhibitionStateRemovals    *prometheus.CounterVec
 	InhibitionStateRedisErrors *prometheus.CounterVec
+	InhibitionStateDegraded    prometheus.Gauge
 	InhibitionCheckTotal       *prometheus.CounterVec
 	InhibitionMatchTotal       *prometheus.CounterVec
 	InhibitionDuration         *prometheus.HistogramVec
@@ -1106,6 +1126,103 @@ func NewBusinessMetrics() *BusinessMetrics {
 				Help:      "Total number of rate limit hits.",
 			},
 		),
+		SilenceGCRunsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "silence_gc_runs_total",
+				Help:      "Total number of silence garbage collection runs.",
+			},
+			[]string{"result"},
+		),
+		SilenceGCDeletedTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "silence_gc_deleted_total",
+				Help:      "Total number of expired silences deleted by garbage collection.",
+			},
+		),
+		StormActive: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      "storm_active",
+				Help:      "Whether alert storm mode is on (1) or off (0).",
+			},
+		),
+		StormTransitionsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "storm_transitions_total",
+				Help:      "Total number of storm mode transitions.",
+			},
+			[]string{"state"},
+		),
+		StormWindowAlerts: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      "storm_window_alerts",
+				Help:      "Firing alerts ingested in the last completed storm detection window.",
+			},
+		),
+		StormBaselineAlerts: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      "storm_baseline_alerts",
+				Help:      "Baseline firing alerts per storm detection window.",
+			},
+		),
+		StormSuppressedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "storm_suppressed_alerts_total",
+				Help:      "Total number of alerts not published individually during storms.",
+			},
+			[]string{"reason"},
+		),
+		StormDigestsTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "storm_digests_total",
+				Help:      "Total number of storm digests sent.",
+			},
+		),
+		StormDigestedAlerts: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "storm_digested_alerts_total",
+				Help:      "Total number of alerts summarized in storm digests.",
+			},
+		),
+		SamplingSampledOutTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "sampling_sampled_out_total",
+				Help:      "Total number of alerts skipped by a processing stage due to sampling.",
+			},
+			[]string{"stage", "policy"},
+		),
+		AlertRollupRunsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "alert_rollup_runs_total",
+				Help:      "Total number of alert rollup runs.",
+			},
+			[]string{"result"},
+		),
+		AlertRollupBucketsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      "alert_rollup_buckets_total",
+				Help:      "Total number of alert rollup buckets computed.",
+			},
+			[]string{"granularity"},
+		),
+		AlertRollupLastSuccess: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      "alert_rollup_last_success_timestamp_seconds",
+				Help:      "Unix time of the last successful alert rollup run.",
+			},
+		),
 		InhibitionStateActive: promauto.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: Namespace,
@@ -1145,6 +1262,13 @@ func NewBusinessMetrics() *BusinessMetrics {
 			},
 			[]string{"operation"},
 		),
+		InhibitionStateDegraded: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      "inhibition_state_degraded",
+				Help:      "1 while Redis is unavailable and inhibition state is kept in local memory only.",
+			},
+		),
 		InhibitionCheckTotal: promauto.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: Namespace,
@@ -1249,6 +1373,16 @@ func (m *BusinessMetrics) RecordInhibitionStateRedisError(operation string) {
 	m.InhibitionStateRedisErrors.WithLabelValues(operation).Inc()
 }
 
+// SetInhibitionStateDegraded records whether inhibition state runs on the
+// local fallback because Redis is unavailable
+func (m *BusinessMetrics) SetInhibitionStateDegraded(degraded bool) {
+	if degraded {
+		m.InhibitionStateDegraded.Set(1)
+		return
+	}
+	m.InhibitionStateDegraded.Set(0)
+}
+
 // SetInhibitionStateActive sets the number of active inhibition states
 func (m *BusinessMetrics) SetInhibitionStateActive(count float64) {
 	m.InhibitionStateActive.Set(count)
@@ -1428,6 +1562,60 @@ func (m *BusinessMetrics) RecordSilenceRequest(method, endpoint, status string,
 	m.SilenceRequestDuration.WithLabelValues(method, endpoint, status).Observe(duration)
 }
 
+// RecordSilenceCheck records a pipeline silence check (result: silenced|allowed)
+func (m *BusinessMetrics) RecordSilenceCheck(result string) {
+	m.SilenceOperationsTotal.WithLabelValues("check", result).Inc()
+}
+
+// RecordSilenceGC records a silence GC run (result: clean|deleted|truncated)
+func (m *BusinessMetrics) RecordSilenceGC(result string, deleted int) {
+	m.SilenceGCRunsTotal.WithLabelValues(result).Inc()
+	m.SilenceGCDeletedTotal.Add(float64(deleted))
+}
+
+// RecordStormTransition records storm mode being entered or exited (state: entered|exited)
+func (m *BusinessMetrics) RecordStormTransition(state string) {
+	m.StormTransitionsTotal.WithLabelValues(state).Inc()
+	if state == "entered" {
+		m.StormActive.Set(1)
+	} else {
+		m.StormActive.Set(0)
+	}
+}
+
+// SetStormRates records the alerts of the last storm detection window and the baseline
+func (m *BusinessMetrics) SetStormRates(window, baseline float64) {
+	m.StormWindowAlerts.Set(window)
+	m.StormBaselineAlerts.Set(baseline)
+}
+
+// RecordStormSuppressed records an alert held back during a storm (reason: digested|grouped)
+func (m *BusinessMetrics) RecordStormSuppressed(reason string) {
+	m.StormSuppressedTotal.WithLabelValues(reason).Inc()
+}
+
+// RecordStormDigest records a storm digest summarizing alerts
+func (m *BusinessMetrics) RecordStormDigest(alerts int) {
+	m.StormDigestsTotal.Inc()
+	m.StormDigestedAlerts.Add(float64(alerts))
+}
+
+// RecordAlertSampledOut records an alert skipping a stage (stage: classification|enrichment)
+func (m *BusinessMetrics) RecordAlertSampledOut(stage, policy string) {
+	m.SamplingSampledOutTotal.WithLabelValues(stage, policy).Inc()
+}
+
+// RecordAlertRollup records an alert rollup run (result: ok|error) and the
+// hourly and daily buckets it computed.
+func (m *BusinessMetrics) RecordAlertRollup(result string, hourly, daily int) {
+	m.AlertRollupRunsTotal.WithLabelValues(result).Inc()
+	m.AlertRollupBucketsTotal.WithLabelValues("hour").Add(float64(hourly))
+	m.AlertRollupBucketsTotal.WithLabelValues("day").Add(float64(daily))
+	if result == "ok" {
+		m.AlertRollupLastSuccess.SetToCurrentTime()
+	}
+}
+
 // SilenceRateLimitExceeded records rate limit exceeded
 func (m *BusinessMetrics) SilenceRateLimitExceeded() {
 	m.SilenceRateLimitHits.Inc()
@@ -12,11 +12,20 @@ const publishingSubsystem = "publishing"
 
 // Provider constants for consistent labeling.
 const (
-	ProviderSlack     = "slack"
-	ProviderPagerDuty = "pagerduty"
-	ProviderRootly    = "rootly"
-	ProviderWebhook   = "webhook"
-	ProviderEmail     = "email"
+	ProviderSlack      = "slack"
+	ProviderPagerDuty  = "pagerduty"
+	ProviderRootly     = "rootly"
+	ProviderWebhook    = "webhook"
+	ProviderEmail      = "email"
+	ProviderTeams      = "teams"
+	ProviderOpsgenie   = "opsgenie"
+	ProviderKafka      = "kafka"
+	ProviderJira       = "jira"
+	ProviderGoogleChat = "googlechat"
+	ProviderMattermost = "mattermost"
+	ProviderAWS        = "aws"
+	ProviderPlugin     = "plugin"
+	ProviderExec       = "exec"
 )
 
 // PublishingMetrics provides consolidated metrics for all publishing operations.
@@ -63,6 +72,16 @@ type PublishingMetrics struct {
 	// Labels: provider
 	rateLimitHitsTotal *prometheus.CounterVec
 
+	// providerPausedUntil is the unix time until which publishing to a
+	// rate-limited provider is paused (0 when not paused).
+	// Labels: provider
+	providerPausedUntil *prometheus.GaugeVec
+
+	// targetLimitWaitSeconds measures how long jobs waited for the rate
+	// limit or a concurrency slot of their target.
+	// Labels: target, limit (rate/concurrency)
+	targetLimitWaitSeconds *prometheus.HistogramVec
+
 	// payloadSizeBytes measures payload size by provider.
 	// Labels: provider
 	payloadSizeBytes *prometheus.HistogramVec
@@ -72,7 +91,8 @@ type PublishingMetrics struct {
 	// ========================================================================
 
 	// Slack-specific
-	threadRepliesTotal *prometheus.CounterVec // Labels: status
+	threadRepliesTotal  *prometheus.CounterVec // Labels: status
+	messageUpdatesTotal *prometheus.CounterVec // Labels: status
 
 	// Rootly-specific
 	incidentsCreatedTotal  *prometheus.CounterVec // Labels: severity
@@ -97,6 +117,10 @@ type PublishingMetrics struct {
 	// Labels: priority
 	queueCapacityUtil *prometheus.GaugeVec
 
+	// queueShedTotal counts jobs shed under queue pressure.
+	// Labels: severity, status (firing/resolved)
+	queueShedTotal *prometheus.CounterVec
+
 	// jobsProcessedTotal counts jobs by target and status.
 	// Labels: target, status (succeeded/failed/dlq)
 	jobsProcessedTotal *prometheus.CounterVec
@@ -115,10 +139,91 @@ type PublishingMetrics struct {
 	// workersIdle tracks idle workers.
 	workersIdle prometheus.Gauge
 
+	// workers tracks the size of the worker pool.
+	workers prometheus.Gauge
+
+	// workerScalingTotal counts worker pool scaling events.
+	// Labels: direction (up/down)
+	workerScalingTotal *prometheus.CounterVec
+
 	// dlqSize tracks DLQ size by target.
 	// Labels: target
 	dlqSize *prometheus.GaugeVec
 
+	// workerPanicsTotal counts publisher panics recovered by queue workers.
+	// Labels: target
+	workerPanicsTotal *prometheus.CounterVec
+
+	// queueRecoveredTotal counts stored jobs recovered by the durable queue.
+	queueRecoveredTotal prometheus.Counter
+
+	// publishDeduplicatedTotal counts unchanged alerts not published again
+	// within the dedup window.
+	// Labels: target
+	publishDeduplicatedTotal *prometheus.CounterVec
+
+	// scheduledJobs tracks the jobs held for notify_after or repeat_interval.
+	scheduledJobs prometheus.Gauge
+
+	// scheduledReleasedTotal counts scheduled jobs leaving the schedule.
+	// Labels: target, reason (notify/renotify/resolved/expired/removed)
+	scheduledReleasedTotal *prometheus.CounterVec
+
+	// dlqReplayedTotal counts DLQ entries replayed automatically once their
+	// target recovered.
+	// Labels: target, result (success/failed)
+	dlqReplayedTotal *prometheus.CounterVec
+
+	// payloadTruncatedTotal counts payloads truncated to the size limits of
+	// their provider.
+	// Labels: format, stage (blocks/labels/annotations/reasoning/exceeded)
+	payloadTruncatedTotal *prometheus.CounterVec
+
+	// quorumTotal counts the quorum evaluations of firing alerts.
+	// Labels: policy, result (met/not_met/timeout)
+	quorumTotal *prometheus.CounterVec
+
+	// quorumFallbacksTotal counts the alerts escalated to a fallback target
+	// because their quorum was not met.
+	// Labels: policy, target
+	quorumFallbacksTotal *prometheus.CounterVec
+
+	// targetDeliveriesTotal counts the jobs delivered by target and path
+	// (primary, or fallback of an unavailable target).
+	// Labels: target, path (primary/fallback)
+	targetDeliveriesTotal *prometheus.CounterVec
+
+	// targetFallbacksTotal counts the jobs of an unavailable target handed
+	// to one of its fallback targets.
+	// Labels: target, fallback, reason (circuit_open/failed)
+	targetFallbacksTotal *prometheus.CounterVec
+
+	// deliveryAuditDroppedTotal counts the publish attempts missing from
+	// the delivery audit log.
+	// Labels: reason (buffer_full/store_error)
+	deliveryAuditDroppedTotal *prometheus.CounterVec
+
+	// batchSize measures the alerts per batched notification.
+	// Labels: target, trigger (size/interval/shutdown/removed)
+	batchSize *prometheus.HistogramVec
+
+	// ========================================================================
+	// Delivery SLO Metrics
+	// ========================================================================
+
+	// notificationLatencySeconds measures firing notifications from ingestion
+	// to provider acknowledgement.
+	// Labels: target, severity
+	notificationLatencySeconds *prometheus.HistogramVec
+
+	// notificationSLOTotal counts firing notifications by SLO result.
+	// Labels: target, severity, result (met/breached/failed)
+	notificationSLOTotal *prometheus.CounterVec
+
+	// notificationSLOThreshold exposes the configured delivery deadlines.
+	// Labels: severity
+	notificationSLOThreshold *prometheus.GaugeVec
+
 	// ========================================================================
 	// Circuit Breaker Metrics
 	// ========================================================================
@@ -238,6 +343,17 @@ func NewPublishingMetrics(registerer prometheus.Registerer) *PublishingMetrics {
 		"Rate limit hits by provider",
 		[]string{"provider"})
 
+	m.providerPausedUntil = newGaugeVec(registerer, publishingSubsystem,
+		"provider_paused_until_seconds",
+		"Unix time until which publishing to a rate-limited provider account is paused by target name, or endpoint host of webhook targets (0 when not paused)",
+		[]string{"provider"})
+
+	m.targetLimitWaitSeconds = newHistogramVec(registerer, publishingSubsystem,
+		"target_limit_wait_seconds",
+		"Time jobs waited for the rate limit or a concurrency slot of their target by target and limit (rate/concurrency)",
+		APILatencyBuckets,
+		[]string{"target", "limit"})
+
 	m.payloadSizeBytes = newHistogramVec(registerer, publishingSubsystem,
 		"payload_size_bytes",
 		"Payload size in bytes by provider",
@@ -250,6 +366,11 @@ func NewPublishingMetrics(registerer prometheus.Registerer) *PublishingMetrics {
 		"Total Slack thread replies by status",
 		[]string{"status"})
 
+	m.messageUpdatesTotal = newCounterVec(registerer, publishingSubsystem,
+		"slack_message_updates_total",
+		"Total Slack message updates (original message of resolved alerts) by status",
+		[]string{"status"})
+
 	// Rootly-specific
 	m.incidentsCreatedTotal = newCounterVec(registerer, publishingSubsystem,
 		"rootly_incidents_created_total",
@@ -294,6 +415,11 @@ func NewPublishingMetrics(registerer prometheus.Registerer) *PublishingMetrics {
 		"Queue capacity utilization (0-1) by priority",
 		[]string{"priority"})
 
+	m.queueShedTotal = newCounterVec(registerer, publishingSubsystem,
+		"queue_shed_total",
+		"Total jobs shed under queue pressure by severity and status",
+		[]string{"severity", "status"})
+
 	m.jobsProcessedTotal = newCounterVec(registerer, publishingSubsystem,
 		"jobs_processed_total",
 		"Total jobs processed by target and status",
@@ -318,11 +444,101 @@ func NewPublishingMetrics(registerer prometheus.Registerer) *PublishingMetrics {
 		"workers_idle",
 		"Number of idle workers")
 
+	m.workers = newGauge(registerer, publishingSubsystem,
+		"workers",
+		"Current number of queue workers")
+
+	m.workerScalingTotal = newCounterVec(registerer, publishingSubsystem,
+		"worker_scaling_total",
+		"Total worker pool scaling events by direction",
+		[]string{"direction"})
+
 	m.dlqSize = newGaugeVec(registerer, publishingSubsystem,
 		"dlq_size",
 		"Dead letter queue size by target",
 		[]string{"target"})
 
+	m.workerPanicsTotal = newCounterVec(registerer, publishingSubsystem,
+		"worker_panics_total",
+		"Publisher panics recovered by queue workers by target",
+		[]string{"target"})
+
+	m.queueRecoveredTotal = newCounter(registerer, publishingSubsystem,
+		"queue_recovered_jobs_total",
+		"Total stored jobs recovered by the durable publishing queue after a restart or from crashed replicas")
+
+	m.publishDeduplicatedTotal = newCounterVec(registerer, publishingSubsystem,
+		"deduplicated_total",
+		"Unchanged alerts not published again to a target within the dedup window by target",
+		[]string{"target"})
+
+	m.scheduledJobs = newGauge(registerer, publishingSubsystem,
+		"scheduled_jobs",
+		"Jobs held by the publishing queue until notify_after or repeat_interval elapses")
+
+	m.scheduledReleasedTotal = newCounterVec(registerer, publishingSubsystem,
+		"scheduled_released_total",
+		"Scheduled jobs released by target and reason (notify/renotify published, resolved/expired/removed dropped)",
+		[]string{"target", "reason"})
+
+	m.dlqReplayedTotal = newCounterVec(registerer, publishingSubsystem,
+		"dlq_replayed_total",
+		"Total DLQ entries replayed automatically after their target recovered by target and result",
+		[]string{"target", "result"})
+
+	m.payloadTruncatedTotal = newCounterVec(registerer, publishingSubsystem,
+		"payload_truncated_total",
+		"Payloads truncated to the size limits of their provider by format and last truncation stage",
+		[]string{"format", "stage"})
+
+	m.quorumTotal = newCounterVec(registerer, publishingSubsystem,
+		"quorum_total",
+		"Quorum evaluations of firing alerts by policy and result (met/not_met/timeout)",
+		[]string{"policy", "result"})
+
+	m.quorumFallbacksTotal = newCounterVec(registerer, publishingSubsystem,
+		"quorum_fallbacks_total",
+		"Alerts escalated to a fallback target after missing their quorum by policy and target",
+		[]string{"policy", "target"})
+
+	m.targetDeliveriesTotal = newCounterVec(registerer, publishingSubsystem,
+		"target_deliveries_total",
+		"Jobs delivered by target and path (primary/fallback)",
+		[]string{"target", "path"})
+
+	m.targetFallbacksTotal = newCounterVec(registerer, publishingSubsystem,
+		"target_fallbacks_total",
+		"Jobs of an unavailable target handed to a fallback target by target, fallback target and reason (circuit_open/failed)",
+		[]string{"target", "fallback", "reason"})
+
+	m.deliveryAuditDroppedTotal = newCounterVec(registerer, publishingSubsystem,
+		"delivery_audit_dropped_total",
+		"Publish attempts missing from the delivery audit log by reason (buffer_full/store_error)",
+		[]string{"reason"})
+
+	m.batchSize = newHistogramVec(registerer, publishingSubsystem,
+		"batch_size",
+		"Alerts per batched notification by target and flush trigger (size/interval/shutdown/removed)",
+		BatchSizeBuckets,
+		[]string{"target", "trigger"})
+
+	// Delivery SLO
+	m.notificationLatencySeconds = newHistogramVec(registerer, publishingSubsystem,
+		"notification_latency_seconds",
+		"Time from alert ingestion to provider acknowledgement of firing notifications by target and severity",
+		NotificationLatencyBuckets,
+		[]string{"target", "severity"})
+
+	m.notificationSLOTotal = newCounterVec(registerer, publishingSubsystem,
+		"notification_slo_total",
+		"Firing notifications by target, severity and SLO result (met/breached/failed)",
+		[]string{"target", "severity", "result"})
+
+	m.notificationSLOThreshold = newGaugeVec(registerer, publishingSubsystem,
+		"notification_slo_threshold_seconds",
+		"Configured notification delivery deadline by severity",
+		[]string{"severity"})
+
 	// Circuit Breaker
 	m.circuitBreakerState = newGaugeVec(registerer, publishingSubsystem,
 		"circuit_breaker_state",
@@ -443,6 +659,22 @@ func (m *PublishingMetrics) RecordRateLimitHit(provider string) {
 	m.rateLimitHitsTotal.WithLabelValues(provider).Inc()
 }
 
+// SetProviderPausedUntil sets the time until which publishing to a provider
+// is paused. A zero time clears the pause.
+func (m *PublishingMetrics) SetProviderPausedUntil(provider string, until time.Time) {
+	if until.IsZero() {
+		m.providerPausedUntil.WithLabelValues(provider).Set(0)
+		return
+	}
+	m.providerPausedUntil.WithLabelValues(provider).Set(float64(until.Unix()))
+}
+
+// RecordTargetLimitWait records how long a job waited for a limit (rate or
+// concurrency) of its target.
+func (m *PublishingMetrics) RecordTargetLimitWait(target, limit string, wait time.Duration) {
+	m.targetLimitWaitSeconds.WithLabelValues(target, limit).Observe(wait.Seconds())
+}
+
 // RecordPayloadSize records the payload size.
 func (m *PublishingMetrics) RecordPayloadSize(provider string, bytes int) {
 	m.payloadSizeBytes.WithLabelValues(provider).Observe(float64(bytes))
@@ -457,6 +689,11 @@ func (m *PublishingMetrics) RecordSlackThreadReply(status string) {
 	m.threadRepliesTotal.WithLabelValues(status).Inc()
 }
 
+// RecordSlackMessageUpdate records an update of a posted Slack message.
+func (m *PublishingMetrics) RecordSlackMessageUpdate(status string) {
+	m.messageUpdatesTotal.WithLabelValues(status).Inc()
+}
+
 // ============================================================================
 // Rootly-specific Methods
 // ============================================================================
@@ -517,6 +754,11 @@ func (m *PublishingMetrics) UpdateQueueSize(priority string, currentSize, capaci
 	m.queueCapacityUtil.WithLabelValues(priority).Set(utilization)
 }
 
+// RecordQueueShed records a job shed under queue pressure.
+func (m *PublishingMetrics) RecordQueueShed(severity, status string) {
+	m.queueShedTotal.WithLabelValues(severity, status).Inc()
+}
+
 // RecordJobSuccess records a successful job.
 func (m *PublishingMetrics) RecordJobSuccess(target, priority string, duration time.Duration) {
 	m.jobsProcessedTotal.WithLabelValues(target, "succeeded").Inc()
@@ -544,6 +786,94 @@ func (m *PublishingMetrics) SetWorkerCounts(active, idle int) {
 	m.workersIdle.Set(float64(idle))
 }
 
+// RecordWorkerPanic records a publisher panic recovered by a queue worker.
+func (m *PublishingMetrics) RecordWorkerPanic(target string) {
+	m.workerPanicsTotal.WithLabelValues(target).Inc()
+}
+
+// RecordQueueRecovered records stored jobs recovered by the durable queue.
+func (m *PublishingMetrics) RecordQueueRecovered(count int) {
+	m.queueRecoveredTotal.Add(float64(count))
+}
+
+// RecordPublishDeduplicated records an unchanged alert of target suppressed
+// by the dedup window.
+func (m *PublishingMetrics) RecordPublishDeduplicated(target string) {
+	m.publishDeduplicatedTotal.WithLabelValues(target).Inc()
+}
+
+// SetScheduledJobs sets the number of jobs held until their schedule.
+func (m *PublishingMetrics) SetScheduledJobs(count int) {
+	m.scheduledJobs.Set(float64(count))
+}
+
+// RecordScheduledRelease records a scheduled job of target leaving the
+// schedule for reason.
+func (m *PublishingMetrics) RecordScheduledRelease(target, reason string) {
+	m.scheduledReleasedTotal.WithLabelValues(target, reason).Inc()
+}
+
+// RecordDLQReplay records a DLQ entry replayed automatically for target.
+func (m *PublishingMetrics) RecordDLQReplay(target, result string) {
+	m.dlqReplayedTotal.WithLabelValues(target, result).Inc()
+}
+
+// RecordPayloadTruncated records a payload of format truncated down to
+// stage to fit the size limits of its provider.
+func (m *PublishingMetrics) RecordPayloadTruncated(format, stage string) {
+	m.payloadTruncatedTotal.WithLabelValues(format, stage).Inc()
+}
+
+// RecordQuorum records the quorum evaluation of an alert under policy.
+func (m *PublishingMetrics) RecordQuorum(policy, result string) {
+	m.quorumTotal.WithLabelValues(policy, result).Inc()
+}
+
+// RecordQuorumFallback records an alert escalated to target after missing
+// the quorum of policy.
+func (m *PublishingMetrics) RecordQuorumFallback(policy, target string) {
+	m.quorumFallbacksTotal.WithLabelValues(policy, target).Inc()
+}
+
+// RecordTargetDelivery records a job delivered by target, on path primary
+// or fallback.
+func (m *PublishingMetrics) RecordTargetDelivery(target, path string) {
+	m.targetDeliveriesTotal.WithLabelValues(target, path).Inc()
+}
+
+// RecordTargetFallback records a job of target handed to fallback for
+// reason.
+func (m *PublishingMetrics) RecordTargetFallback(target, fallback, reason string) {
+	m.targetFallbacksTotal.WithLabelValues(target, fallback, reason).Inc()
+}
+
+// RecordDeliveryAuditDropped records count publish attempts not written
+// to the delivery audit log for reason.
+func (m *PublishingMetrics) RecordDeliveryAuditDropped(reason string, count int) {
+	m.deliveryAuditDroppedTotal.WithLabelValues(reason).Add(float64(count))
+}
+
+// RecordBatchFlush records the size of a batch flushed for target.
+func (m *PublishingMetrics) RecordBatchFlush(target, trigger string, size int) {
+	m.batchSize.WithLabelValues(target, trigger).Observe(float64(size))
+}
+
+// RecordNotificationLatency records the ingest-to-acknowledgement latency of
+// a firing notification.
+func (m *PublishingMetrics) RecordNotificationLatency(target, severity string, latency time.Duration) {
+	m.notificationLatencySeconds.WithLabelValues(target, severity).Observe(latency.Seconds())
+}
+
+// RecordNotificationSLO records the SLO result of a firing notification.
+func (m *PublishingMetrics) RecordNotificationSLO(target, severity, result string) {
+	m.notificationSLOTotal.WithLabelValues(target, severity, result).Inc()
+}
+
+// SetNotificationSLOThreshold sets the delivery deadline of a severity.
+func (m *PublishingMetrics) SetNotificationSLOThreshold(severity string, threshold time.Duration) {
+	m.notificationSLOThreshold.WithLabelValues(severity).Set(threshold.Seconds())
+}
+
 // UpdateDLQSize updates DLQ size.
 func (m *PublishingMetrics) UpdateDLQSize(target string, size int) {
 	m.dlqSize.WithLabelValues(target).Set(float64(size))
@@ -611,6 +941,26 @@ func (m *PublishingMetrics) SetSuccessRate(target string, rate float64) {
 	m.targetSuccessRate.WithLabelValues(target).Set(rate)
 }
 
+// DeleteTargetSeries deletes the gauge series of a target removed from
+// discovery (DLQ size, circuit breaker state and health), so that they stop
+// being exported with their last value. Counters and histograms are kept:
+// their totals remain valid for rate() and increase() over past windows.
+// Returns the number of series deleted.
+func (m *PublishingMetrics) DeleteTargetSeries(target string) int {
+	labels := prometheus.Labels{"target": target}
+	deleted := 0
+	for _, gauge := range []*prometheus.GaugeVec{
+		m.dlqSize,
+		m.circuitBreakerState,
+		m.targetHealthStatus,
+		m.targetConsecutiveFailures,
+		m.targetSuccessRate,
+	} {
+		deleted += gauge.DeletePartialMatch(labels)
+	}
+	return deleted
+}
+
 // ============================================================================
 // Refresh/Discovery Methods
 // ============================================================================
@@ -762,4 +1112,17 @@ func (m *PublishingMetrics) RecordWorkerIdle() {
 func (m *PublishingMetrics) InitializeWorkerMetrics(workerCount int) {
 	m.workersActive.Set(0)
 	m.workersIdle.Set(float64(workerCount))
+	m.workers.Set(float64(workerCount))
+}
+
+// RecordWorkerScaling records workers added (delta > 0) to or retired
+// (delta < 0) from the worker pool, now of size workers.
+func (m *PublishingMetrics) RecordWorkerScaling(delta, workers int) {
+	direction := "up"
+	if delta < 0 {
+		direction = "down"
+	}
+	m.workerScalingTotal.WithLabelValues(direction).Inc()
+	m.workersIdle.Add(float64(delta))
+	m.workers.Set(float64(workers))
 }
@@ -217,6 +217,13 @@ var (
 	// DatabaseBuckets are suitable for database query latencies (1ms to 5s).
 	DatabaseBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0}
 
+	// NotificationLatencyBuckets cover end-to-end notification delivery,
+	// including queueing and retries (100ms to 30m).
+	NotificationLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800}
+
 	// PayloadSizeBuckets are suitable for request/response payload sizes (1KB to 16MB).
 	PayloadSizeBuckets = prometheus.ExponentialBuckets(1024, 2, 15) // 1KB to 16MB
+
+	// BatchSizeBuckets are suitable for alerts per batched notification (1 to 1000).
+	BatchSizeBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
 )
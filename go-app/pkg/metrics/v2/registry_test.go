@@ -239,6 +239,32 @@ func TestDatabaseMetrics_Transactions(t *testing.T) {
 	}
 }
 
+func TestPublishingMetrics_DeleteTargetSeries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewPublishingMetrics(reg)
+
+	for _, target := range []string{"slack-old", "slack-prod"} {
+		metrics.SetCircuitBreakerState(target, CircuitBreakerOpen)
+		metrics.SetTargetHealthStatus(target, "slack", HealthStatusUnhealthy)
+		metrics.SetConsecutiveFailures(target, 3)
+		metrics.RecordJobFailure(target)
+	}
+
+	if deleted := metrics.DeleteTargetSeries("slack-old"); deleted != 3 {
+		t.Errorf("expected 3 deleted series, got %d", deleted)
+	}
+	if count := testutil.CollectAndCount(metrics.targetHealthStatus); count != 1 {
+		t.Errorf("expected 1 targetHealthStatus series, got %d", count)
+	}
+	if count := testutil.CollectAndCount(metrics.circuitBreakerState); count != 1 {
+		t.Errorf("expected 1 circuitBreakerState series, got %d", count)
+	}
+	// Counters keep their series
+	if count := testutil.CollectAndCount(metrics.jobsProcessedTotal); count != 2 {
+		t.Errorf("expected 2 jobsProcessed series, got %d", count)
+	}
+}
+
 func TestCacheMetrics_HitsMisses(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	metrics := NewCacheMetrics(reg)
@@ -0,0 +1,175 @@
+// Package publisherplugin is the protocol between AMP and external publisher
+// plugins, and the SDK to write them.
+//
+// A plugin is a binary that AMP launches and supervises (publishing.plugins
+// in the AMP configuration). It serves the gRPC service
+// amp.publisher.v1.Publisher on a unix socket AMP passes in its environment;
+// the Publish, Health and Shutdown methods mirror AMP's own publishers.
+// Messages are JSON encoded (gRPC content subtype "json"), so plugins need no
+// generated code:
+//
+//	type pager struct{}
+//
+//	func (pager) Name() string { return "pager" }
+//
+//	func (pager) Publish(ctx context.Context, alert *publisherplugin.EnrichedAlert, target *publisherplugin.Target) error {
+//	    // deliver alert to target.URL
+//	    return nil
+//	}
+//
+//	func main() {
+//	    if err := publisherplugin.Serve(pager{}); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+//
+// Publishing targets of type "plugin" name their plugin in the "plugin"
+// header. Publish errors decide whether AMP retries the alert: return
+// Retryable or RateLimited errors for temporary failures and Permanent errors
+// for alerts that can never be delivered (they go to the dead letter queue).
+// Other errors are retried with caution.
+package publisherplugin
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProtocolVersion is the version of the plugin protocol.
+const ProtocolVersion = "1"
+
+// Environment AMP launches plugins with.
+const (
+	// MagicCookieEnv holds MagicCookieValue, telling a plugin binary it was
+	// launched by AMP rather than by a user.
+	MagicCookieEnv   = "AMP_PUBLISHER_PLUGIN"
+	MagicCookieValue = "7c4a1e52-amp-publisher-plugin"
+	// ProtocolVersionEnv holds the ProtocolVersion of AMP.
+	ProtocolVersionEnv = "AMP_PLUGIN_PROTOCOL_VERSION"
+	// SocketEnv holds the path of the unix socket to serve on.
+	SocketEnv = "AMP_PLUGIN_SOCKET"
+	// NameEnv holds the name of the plugin in the AMP configuration.
+	NameEnv = "AMP_PLUGIN_NAME"
+)
+
+// ServiceName is the gRPC service of plugins.
+const ServiceName = "amp.publisher.v1.Publisher"
+
+// Full gRPC method names of the service.
+const (
+	PublishMethod  = "/" + ServiceName + "/Publish"
+	HealthMethod   = "/" + ServiceName + "/Health"
+	ShutdownMethod = "/" + ServiceName + "/Shutdown"
+)
+
+// Publisher delivers alerts to the targets of a plugin. Implementations may
+// also implement HealthChecker and Shutdowner.
+type Publisher interface {
+	// Publish delivers alert to target.
+	Publish(ctx context.Context, alert *EnrichedAlert, target *Target) error
+	// Name identifies the publisher in health reports.
+	Name() string
+}
+
+// HealthChecker is implemented by publishers that can report their health,
+// e.g. the reachability of their backend. A plugin is healthy as long as it
+// serves requests otherwise.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// Shutdowner is implemented by publishers with resources to release when AMP
+// stops the plugin.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// Alert is an alert, as in AMP.
+type Alert struct {
+	Fingerprint  string            `json:"fingerprint"`
+	AlertName    string            `json:"alert_name"`
+	Status       string            `json:"status"` // "firing" or "resolved"
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"starts_at"`
+	EndsAt       *time.Time        `json:"ends_at,omitempty"`
+	GeneratorURL *string           `json:"generator_url,omitempty"`
+	Timestamp    *time.Time        `json:"timestamp,omitempty"`
+	SilencedBy   []string          `json:"silenced_by,omitempty"`
+}
+
+// Classification is the classification of an alert.
+type Classification struct {
+	Severity        string         `json:"severity"` // critical, warning, info or noise
+	Confidence      float64        `json:"confidence"`
+	Reasoning       string         `json:"reasoning"`
+	Recommendations []string       `json:"recommendations"`
+	ProcessingTime  float64        `json:"processing_time"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+}
+
+// EnrichedAlert is an alert with its classification, if any.
+type EnrichedAlert struct {
+	Alert               *Alert          `json:"alert"`
+	Classification      *Classification `json:"classification,omitempty"`
+	EnrichmentMetadata  map[string]any  `json:"enrichment_metadata,omitempty"`
+	ProcessingTimestamp *time.Time      `json:"processing_timestamp,omitempty"`
+}
+
+// Target is a publishing target served by the plugin. Headers carry its
+// plugin-specific configuration.
+type Target struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	URL          string            `json:"url"`
+	Enabled      bool              `json:"enabled"`
+	FilterConfig map[string]any    `json:"filter_config"`
+	Headers      map[string]string `json:"headers"`
+	Format       string            `json:"format"`
+}
+
+// PublishRequest is the request of the Publish method.
+type PublishRequest struct {
+	Alert  *EnrichedAlert `json:"alert"`
+	Target *Target        `json:"target"`
+}
+
+// PublishResponse is the response of the Publish method.
+type PublishResponse struct{}
+
+// HealthRequest is the request of the Health method.
+type HealthRequest struct{}
+
+// HealthResponse is the response of the Health method.
+type HealthResponse struct {
+	Name            string `json:"name"`
+	ProtocolVersion string `json:"protocol_version"`
+	Healthy         bool   `json:"healthy"`
+	Message         string `json:"message,omitempty"`
+}
+
+// ShutdownRequest is the request of the Shutdown method.
+type ShutdownRequest struct{}
+
+// ShutdownResponse is the response of the Shutdown method.
+type ShutdownResponse struct{}
+
+// Retryable marks err as temporary: AMP retries the alert.
+func Retryable(err error) error {
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// RateLimited marks err as a rate limit of the backend: AMP retries the
+// alert after a backoff.
+func RateLimited(err error) error {
+	return status.Error(codes.ResourceExhausted, err.Error())
+}
+
+// Permanent marks err as permanent: AMP does not retry the alert and moves it
+// to the dead letter queue.
+func Permanent(err error) error {
+	return status.Error(codes.FailedPrecondition, err.Error())
+}
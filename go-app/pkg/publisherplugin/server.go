@@ -0,0 +1,141 @@
+package publisherplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotLaunchedByAMP is returned by Serve when the binary was not launched
+// by AMP.
+var ErrNotLaunchedByAMP = errors.New("this binary is an AMP publisher plugin: declare it in publishing.plugins of the AMP configuration instead of running it")
+
+// pluginService is the handler type of the service.
+type pluginService interface {
+	publish(ctx context.Context, req *PublishRequest) (*PublishResponse, error)
+	health(ctx context.Context, req *HealthRequest) (*HealthResponse, error)
+	shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*pluginService)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Publish", Handler: unaryHandler(PublishMethod, pluginService.publish)},
+		{MethodName: "Health", Handler: unaryHandler(HealthMethod, pluginService.health)},
+		{MethodName: "Shutdown", Handler: unaryHandler(ShutdownMethod, pluginService.shutdown)},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "amp/publisher/v1/publisher",
+}
+
+// unaryHandler adapts a method of pluginService to a gRPC method handler.
+func unaryHandler[Req, Resp any](fullMethod string, method func(pluginService, context.Context, *Req) (*Resp, error)) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return method(srv.(pluginService), ctx, req)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+		return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return method(srv.(pluginService), ctx, req.(*Req))
+		})
+	}
+}
+
+// server serves a Publisher.
+type server struct {
+	impl Publisher
+	grpc *grpc.Server
+}
+
+func (s *server) publish(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
+	if req.Alert == nil || req.Alert.Alert == nil || req.Target == nil {
+		return nil, status.Error(codes.InvalidArgument, "publish request without alert or target")
+	}
+	if err := s.impl.Publish(ctx, req.Alert, req.Target); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	return &PublishResponse{}, nil
+}
+
+func (s *server) health(ctx context.Context, _ *HealthRequest) (*HealthResponse, error) {
+	resp := &HealthResponse{Name: s.impl.Name(), ProtocolVersion: ProtocolVersion, Healthy: true}
+	if checker, ok := s.impl.(HealthChecker); ok {
+		if err := checker.Health(ctx); err != nil {
+			resp.Healthy = false
+			resp.Message = err.Error()
+		}
+	}
+	return resp, nil
+}
+
+// shutdown releases the resources of the publisher and stops the server once
+// the response is sent.
+func (s *server) shutdown(ctx context.Context, _ *ShutdownRequest) (*ShutdownResponse, error) {
+	var err error
+	if shutdowner, ok := s.impl.(Shutdowner); ok {
+		err = shutdowner.Shutdown(ctx)
+	}
+	go s.grpc.GracefulStop()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &ShutdownResponse{}, nil
+}
+
+// NewServer returns a gRPC server serving p, for plugins that manage their
+// listener themselves (and tests). The Shutdown method stops the server
+// gracefully.
+func NewServer(p Publisher, opts ...grpc.ServerOption) *grpc.Server {
+	s := &server{impl: p, grpc: grpc.NewServer(opts...)}
+	s.grpc.RegisterService(&serviceDesc, s)
+	return s.grpc
+}
+
+// Serve serves p on the socket AMP launched the plugin with, until AMP calls
+// Shutdown or the process receives SIGINT or SIGTERM. It returns
+// ErrNotLaunchedByAMP when the binary is run by hand.
+func Serve(p Publisher) error {
+	if os.Getenv(MagicCookieEnv) != MagicCookieValue {
+		return ErrNotLaunchedByAMP
+	}
+	if version := os.Getenv(ProtocolVersionEnv); version != ProtocolVersion {
+		return fmt.Errorf("AMP speaks plugin protocol version %q, plugin speaks %q", version, ProtocolVersion)
+	}
+	socket := os.Getenv(SocketEnv)
+	if socket == "" {
+		return fmt.Errorf("%s not set", SocketEnv)
+	}
+
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	srv := NewServer(p)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		if _, ok := <-signals; ok {
+			srv.GracefulStop()
+		}
+	}()
+
+	return srv.Serve(lis)
+}
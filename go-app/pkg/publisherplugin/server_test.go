@@ -0,0 +1,167 @@
+package publisherplugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakePublisher struct {
+	published []*PublishRequest
+	err       error
+	healthErr error
+	shutdown  bool
+}
+
+func (p *fakePublisher) Name() string { return "fake" }
+
+func (p *fakePublisher) Publish(_ context.Context, alert *EnrichedAlert, target *Target) error {
+	p.published = append(p.published, &PublishRequest{Alert: alert, Target: target})
+	return p.err
+}
+
+func (p *fakePublisher) Health(context.Context) error { return p.healthErr }
+
+func (p *fakePublisher) Shutdown(context.Context) error {
+	p.shutdown = true
+	return nil
+}
+
+// startServer serves p on an in-memory listener and returns a client
+// connection to it.
+func startServer(t *testing.T, p Publisher) (*grpc.ClientConn, chan error) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(p)
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///plugin",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, served
+}
+
+func TestServer_Publish(t *testing.T) {
+	p := &fakePublisher{}
+	conn, _ := startServer(t, p)
+
+	req := &PublishRequest{
+		Alert: &EnrichedAlert{
+			Alert:          &Alert{Fingerprint: "abc", AlertName: "HighCPU", Status: "firing", Labels: map[string]string{"severity": "critical"}},
+			Classification: &Classification{Severity: "critical", Confidence: 0.9},
+		},
+		Target: &Target{Name: "pager", Type: "plugin", Headers: map[string]string{"plugin": "pager"}},
+	}
+	if err := conn.Invoke(context.Background(), PublishMethod, req, &PublishResponse{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(p.published) != 1 {
+		t.Fatalf("published %d alerts, want 1", len(p.published))
+	}
+	got := p.published[0]
+	if got.Alert.Alert.Fingerprint != "abc" || got.Alert.Alert.Labels["severity"] != "critical" || got.Alert.Classification.Confidence != 0.9 {
+		t.Errorf("alert = %+v", got.Alert.Alert)
+	}
+	if got.Target.Headers["plugin"] != "pager" {
+		t.Errorf("target = %+v", got.Target)
+	}
+}
+
+func TestServer_PublishErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"plain", errors.New("boom"), codes.Unknown},
+		{"retryable", Retryable(errors.New("backend down")), codes.Unavailable},
+		{"rate limited", RateLimited(errors.New("slow down")), codes.ResourceExhausted},
+		{"permanent", Permanent(errors.New("no such channel")), codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _ := startServer(t, &fakePublisher{err: tt.err})
+			req := &PublishRequest{Alert: &EnrichedAlert{Alert: &Alert{Fingerprint: "abc"}}, Target: &Target{Name: "t"}}
+			err := conn.Invoke(context.Background(), PublishMethod, req, &PublishResponse{})
+			if code := status.Code(err); code != tt.want {
+				t.Errorf("code = %v, want %v (%v)", code, tt.want, err)
+			}
+		})
+	}
+
+	conn, _ := startServer(t, &fakePublisher{})
+	err := conn.Invoke(context.Background(), PublishMethod, &PublishRequest{}, &PublishResponse{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty request: %v, want InvalidArgument", err)
+	}
+}
+
+func TestServer_Health(t *testing.T) {
+	p := &fakePublisher{}
+	conn, _ := startServer(t, p)
+
+	var resp HealthResponse
+	if err := conn.Invoke(context.Background(), HealthMethod, &HealthRequest{}, &resp); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if !resp.Healthy || resp.Name != "fake" || resp.ProtocolVersion != ProtocolVersion {
+		t.Errorf("health = %+v", resp)
+	}
+
+	p.healthErr = errors.New("backend unreachable")
+	if err := conn.Invoke(context.Background(), HealthMethod, &HealthRequest{}, &resp); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if resp.Healthy || resp.Message != "backend unreachable" {
+		t.Errorf("health = %+v, want unhealthy", resp)
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	p := &fakePublisher{}
+	conn, served := startServer(t, p)
+
+	if err := conn.Invoke(context.Background(), ShutdownMethod, &ShutdownRequest{}, &ShutdownResponse{}); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if !p.shutdown {
+		t.Error("publisher not shut down")
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Serve = %v, want nil after Shutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server still serving after Shutdown")
+	}
+}
+
+func TestServe_NotLaunchedByAMP(t *testing.T) {
+	t.Setenv(MagicCookieEnv, "")
+	if err := Serve(&fakePublisher{}); !errors.Is(err, ErrNotLaunchedByAMP) {
+		t.Errorf("Serve = %v, want ErrNotLaunchedByAMP", err)
+	}
+
+	t.Setenv(MagicCookieEnv, MagicCookieValue)
+	t.Setenv(ProtocolVersionEnv, "0")
+	if err := Serve(&fakePublisher{}); err == nil {
+		t.Error("Serve accepted a protocol version mismatch")
+	}
+}
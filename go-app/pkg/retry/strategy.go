@@ -29,6 +29,7 @@ import (
 	"log/slog"
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/ipiton/AMP/pkg/httperror"
@@ -78,6 +79,46 @@ type Strategy struct {
 	// OperationName is used for logging and metrics labels.
 	// Optional, defaults to "unknown"
 	OperationName string
+
+	// JitterSource supplies random values in [0.0, 1.0) for jitter calculation.
+	// If nil, the global math/rand source is used.
+	// Use NewSeededJitterSource for reproducible schedules in tests.
+	JitterSource JitterSource
+
+	// After waits for the backoff delay to elapse.
+	// If nil, time.After is used. Tests can inject a function that records
+	// the requested delays and returns immediately.
+	After func(d time.Duration) <-chan time.Time
+}
+
+// JitterSource provides random values for retry jitter.
+//
+// Implementations must be thread-safe: a single Strategy may be shared
+// between goroutines.
+type JitterSource interface {
+	// Float64 returns a pseudo-random number in [0.0, 1.0).
+	Float64() float64
+}
+
+// lockedSource wraps *rand.Rand (which is not thread-safe) with a mutex.
+type lockedSource struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// Float64 implements JitterSource.
+func (s *lockedSource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Float64()
+}
+
+// NewSeededJitterSource returns a thread-safe JitterSource seeded with seed.
+//
+// Two strategies using sources with the same seed produce identical
+// retry schedules, which makes jitter reproducible in unit tests.
+func NewSeededJitterSource(seed int64) JitterSource {
+	return &lockedSource{rnd: rand.New(rand.NewSource(seed))}
 }
 
 // ErrorClassifier determines if an error should trigger a retry.
@@ -170,6 +211,28 @@ func Conservative() Strategy {
 	}
 }
 
+// Webhook returns the strategy of outgoing webhook deliveries, such as
+// mirrored alerts and silence events.
+//
+// Configuration:
+//   - MaxAttempts: maxRetries + 1 (negative maxRetries: no retry)
+//   - BaseDelay: 1s
+//   - MaxDelay: 30s
+//   - Multiplier: 2.0 (exponential)
+//   - JitterRatio: 0.15 (±15%)
+//   - ErrorClassifier: HTTPErrorClassifier (network errors, 429 and 5xx
+//     responses as *httperror.HTTPAPIError)
+func Webhook(maxRetries int) Strategy {
+	return Strategy{
+		MaxAttempts:     max(maxRetries, 0) + 1,
+		BaseDelay:       time.Second,
+		MaxDelay:        30 * time.Second,
+		Multiplier:      2.0,
+		JitterRatio:     0.15,
+		ErrorClassifier: &HTTPErrorClassifier{},
+	}
+}
+
 // NoRetry returns a strategy that never retries (useful for testing).
 func NoRetry() Strategy {
 	return Strategy{
@@ -203,6 +266,18 @@ func (s Strategy) WithErrorClassifier(classifier ErrorClassifier) Strategy {
 	return s
 }
 
+// WithJitterSource returns a copy of the strategy with the specified jitter source.
+func (s Strategy) WithJitterSource(source JitterSource) Strategy {
+	s.JitterSource = source
+	return s
+}
+
+// WithAfter returns a copy of the strategy with the specified backoff wait function.
+func (s Strategy) WithAfter(after func(d time.Duration) <-chan time.Time) Strategy {
+	s.After = after
+	return s
+}
+
 // Do executes operation with retry logic, returning the result or error.
 //
 // Parameters:
@@ -247,6 +322,9 @@ func Do[T any](ctx context.Context, strategy Strategy, operation func() (T, erro
 	if strategy.ErrorClassifier == nil {
 		strategy.ErrorClassifier = &HTTPErrorClassifier{}
 	}
+	if strategy.After == nil {
+		strategy.After = time.After
+	}
 
 	operationName := strategy.OperationName
 	if operationName == "" {
@@ -330,7 +408,7 @@ func Do[T any](ctx context.Context, strategy Strategy, operation func() (T, erro
 
 		// Wait with backoff
 		select {
-		case <-time.After(delay):
+		case <-strategy.After(delay):
 			// Continue to next attempt
 		case <-ctx.Done():
 			return result, fmt.Errorf("cancelled during backoff: %w", ctx.Err())
@@ -389,13 +467,21 @@ func (s Strategy) calculateDelay(attempt int) time.Duration {
 	// Add jitter (±JitterRatio)
 	if s.JitterRatio > 0 {
 		// Random value from -1.0 to 1.0
-		jitterFactor := (rand.Float64()*2.0 - 1.0) * s.JitterRatio
+		jitterFactor := (s.randFloat64()*2.0 - 1.0) * s.JitterRatio
 		delay = delay * (1.0 + jitterFactor)
 	}
 
 	return time.Duration(delay)
 }
 
+// randFloat64 returns a jitter value from JitterSource or the global source.
+func (s Strategy) randFloat64() float64 {
+	if s.JitterSource != nil {
+		return s.JitterSource.Float64()
+	}
+	return rand.Float64()
+}
+
 // AllErrorsClassifier treats all errors as retryable (use with caution).
 type AllErrorsClassifier struct{}
 
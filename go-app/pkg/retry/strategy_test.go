@@ -215,6 +215,83 @@ func TestCalculateDelay(t *testing.T) {
 	}
 }
 
+// fixedJitter is a JitterSource that always returns the same value.
+type fixedJitter float64
+
+func (f fixedJitter) Float64() float64 { return float64(f) }
+
+// TestCalculateDelay_JitterSource tests exact delays with injected jitter
+func TestCalculateDelay_JitterSource(t *testing.T) {
+	strategy := Strategy{
+		BaseDelay:   1000 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Multiplier:  2.0,
+		JitterRatio: 0.2,
+	}
+
+	tests := []struct {
+		name     string
+		jitter   float64
+		expected time.Duration
+	}{
+		{name: "minimum jitter", jitter: 0.0, expected: 800 * time.Millisecond},
+		{name: "no jitter", jitter: 0.5, expected: 1000 * time.Millisecond},
+		{name: "high jitter", jitter: 0.75, expected: 1100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := strategy.WithJitterSource(fixedJitter(tt.jitter))
+			assert.Equal(t, tt.expected, s.calculateDelay(0))
+		})
+	}
+}
+
+// TestSeededJitterSource_Reproducible tests that equal seeds yield equal schedules
+func TestSeededJitterSource_Reproducible(t *testing.T) {
+	schedule := func(seed int64) []time.Duration {
+		s := Default().WithJitterSource(NewSeededJitterSource(seed))
+		delays := make([]time.Duration, 0, 5)
+		for attempt := 0; attempt < 5; attempt++ {
+			delays = append(delays, s.calculateDelay(attempt))
+		}
+		return delays
+	}
+
+	assert.Equal(t, schedule(42), schedule(42))
+	assert.NotEqual(t, schedule(42), schedule(7))
+}
+
+// TestDo_InjectedAfter tests that the backoff schedule is observable without sleeping
+func TestDo_InjectedAfter(t *testing.T) {
+	var waited []time.Duration
+	after := func(d time.Duration) <-chan time.Time {
+		waited = append(waited, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+
+	strategy := Default().
+		WithMaxAttempts(4).
+		WithErrorClassifier(&AllErrorsClassifier{}).
+		WithJitterSource(fixedJitter(0.5)).
+		WithAfter(after)
+
+	start := time.Now()
+	err := DoSimple(context.Background(), strategy, func() error {
+		return errors.New("transient")
+	})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "should not actually sleep")
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+	}, waited)
+}
+
 // TestHTTPErrorClassifier tests HTTP error classification
 func TestHTTPErrorClassifier(t *testing.T) {
 	classifier := &HTTPErrorClassifier{}
@@ -288,6 +365,30 @@ func TestStrategyDefaults(t *testing.T) {
 				ErrorClassifier: &HTTPErrorClassifier{},
 			},
 		},
+		{
+			name:     "Webhook(3)",
+			strategy: Webhook(3),
+			expected: Strategy{
+				MaxAttempts:     4,
+				BaseDelay:       time.Second,
+				MaxDelay:        30 * time.Second,
+				Multiplier:      2.0,
+				JitterRatio:     0.15,
+				ErrorClassifier: &HTTPErrorClassifier{},
+			},
+		},
+		{
+			name:     "Webhook(-1)",
+			strategy: Webhook(-1),
+			expected: Strategy{
+				MaxAttempts:     1,
+				BaseDelay:       time.Second,
+				MaxDelay:        30 * time.Second,
+				Multiplier:      2.0,
+				JitterRatio:     0.15,
+				ErrorClassifier: &HTTPErrorClassifier{},
+			},
+		},
 		{
 			name:     "NoRetry()",
 			strategy: NoRetry(),
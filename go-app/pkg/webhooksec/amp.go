@@ -0,0 +1,68 @@
+package webhooksec
+
+import (
+	"crypto/hmac"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AMPSignatureHeader carries the signature of webhooks posted by AMP:
+// "t=<unix timestamp>,v1=<hex(HMAC-SHA256(secret, "<timestamp>.<body>"))>".
+// Several v1 values may be present while a secret is rotated.
+const AMPSignatureHeader = "X-AMP-Signature"
+
+// SignAMP returns the X-AMP-Signature value of body sent at ts.
+func SignAMP(secret string, body []byte, ts time.Time) string {
+	rawTS := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + rawTS + ",v1=" + SignHMACSHA256(secret, []byte(rawTS+"."), body)
+}
+
+// VerifyAMP checks the X-AMP-Signature of a webhook posted by AMP. The
+// request is valid when its timestamp is within maxSkew of now and any v1
+// signature matches any of secrets, so secrets can be rotated without
+// downtime.
+func VerifyAMP(secrets []string, header http.Header, body []byte, now time.Time, maxSkew time.Duration) error {
+	raw := header.Get(AMPSignatureHeader)
+	if raw == "" {
+		return ErrMissingSignature
+	}
+
+	var rawTS string
+	var signatures []string
+	for _, part := range strings.Split(raw, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			rawTS = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if len(signatures) == 0 {
+		return ErrMissingSignature
+	}
+	ts, err := parseUnixTimestamp(rawTS)
+	if err != nil {
+		return err
+	}
+	if err := CheckTimestamp(ts, now, maxSkew); err != nil {
+		return err
+	}
+
+	matched := false
+	for _, secret := range secrets {
+		expected := []byte(SignHMACSHA256(secret, []byte(rawTS+"."), body))
+		for _, signature := range signatures {
+			// No early return: keep the time independent of which one matched
+			if hmac.Equal([]byte(signature), expected) {
+				matched = true
+			}
+		}
+	}
+	if !matched {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
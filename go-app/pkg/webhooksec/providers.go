@@ -0,0 +1,69 @@
+package webhooksec
+
+import (
+	"crypto/hmac"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// SlackSignatureHeader and SlackTimestampHeader carry Slack's v0 signature.
+	SlackSignatureHeader = "X-Slack-Signature"
+	SlackTimestampHeader = "X-Slack-Request-Timestamp"
+
+	// PagerDutySignatureHeader carries PagerDuty v3 webhook signatures.
+	PagerDutySignatureHeader = "X-PagerDuty-Signature"
+)
+
+// VerifySlack checks Slack's v0 request signature:
+// X-Slack-Signature = "v0=" + hex(HMAC-SHA256(secret, "v0:<timestamp>:<body>")),
+// with the timestamp from X-Slack-Request-Timestamp.
+func VerifySlack(secret string, header http.Header, body []byte, now time.Time, maxSkew time.Duration) error {
+	signature := header.Get(SlackSignatureHeader)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	rawTS := header.Get(SlackTimestampHeader)
+	ts, err := parseUnixTimestamp(rawTS)
+	if err != nil {
+		return err
+	}
+	if err := CheckTimestamp(ts, now, maxSkew); err != nil {
+		return err
+	}
+
+	expected := "v0=" + SignHMACSHA256(secret, []byte("v0:"+rawTS+":"), body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// VerifyPagerDuty checks PagerDuty v3 webhook signatures:
+// X-PagerDuty-Signature holds comma-separated "v1=<hex(HMAC-SHA256(secret, body))>"
+// values, one per active secret. The request is valid when any signature
+// matches any of secrets, so secrets can be rotated without downtime.
+//
+// PagerDuty does not sign a timestamp; use a secret per subscription.
+func VerifyPagerDuty(secrets []string, header http.Header, body []byte) error {
+	raw := header.Get(PagerDutySignatureHeader)
+	if raw == "" {
+		return ErrMissingSignature
+	}
+
+	matched := false
+	for _, secret := range secrets {
+		expected := []byte("v1=" + SignHMACSHA256(secret, body))
+		for _, signature := range strings.Split(raw, ",") {
+			// No early return: keep the time independent of which one matched
+			if hmac.Equal([]byte(strings.TrimSpace(signature)), expected) {
+				matched = true
+			}
+		}
+	}
+	if !matched {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
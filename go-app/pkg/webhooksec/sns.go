@@ -0,0 +1,213 @@
+package webhooksec
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snsCertHost matches the hosts Amazon SNS serves signing certificates from.
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// maxSNSCertSize bounds the downloaded signing certificate.
+const maxSNSCertSize = 64 * 1024
+
+// SNSMessage is an Amazon SNS HTTP(S) message, as posted to subscribed
+// endpoints.
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token,omitempty"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject,omitempty"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL,omitempty"`
+	UnsubscribeURL   string `json:"UnsubscribeURL,omitempty"`
+}
+
+// SNSVerifier verifies Amazon SNS message signatures. Signing certificates
+// are only fetched over HTTPS from SNS hosts and are cached by URL.
+//
+// Thread-safe: Verify may be called concurrently.
+type SNSVerifier struct {
+	// MaxSkew is the allowed age of the message timestamp. SNS retries
+	// failed deliveries with the original timestamp, so raise it for
+	// delivery policies that retry for longer.
+	MaxSkew time.Duration
+
+	client         *http.Client
+	certURLAllowed func(u *url.URL) bool
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSNSVerifier creates a verifier fetching certificates with client
+// (http.DefaultClient when nil).
+func NewSNSVerifier(client *http.Client) *SNSVerifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &SNSVerifier{
+		MaxSkew:        DefaultMaxSkew,
+		client:         client,
+		certURLAllowed: isSNSCertURL,
+		certs:          make(map[string]*x509.Certificate),
+	}
+}
+
+// Verify checks the signature (SignatureVersion 1: SHA1withRSA, 2:
+// SHA256withRSA) and the timestamp of msg.
+func (v *SNSVerifier) Verify(ctx context.Context, msg *SNSMessage, now time.Time) error {
+	if msg.Signature == "" {
+		return ErrMissingSignature
+	}
+	ts, err := time.Parse(time.RFC3339Nano, msg.Timestamp)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", msg.Timestamp)
+	}
+	if err := CheckTimestamp(ts, now, v.MaxSkew); err != nil {
+		return err
+	}
+
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported signature version %q", msg.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	stringToSign, err := snsStringToSign(msg)
+	if err != nil {
+		return err
+	}
+
+	cert, err := v.certificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("signing certificate has no RSA public key")
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(stringToSign))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(pub, hash, digest, signature); err != nil {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// snsStringToSign builds the canonical "Key\nValue\n" string SNS signs.
+func snsStringToSign(msg *SNSMessage) (string, error) {
+	type field struct{ key, value string }
+	var fields []field
+	switch msg.Type {
+	case "Notification":
+		fields = []field{{"Message", msg.Message}, {"MessageId", msg.MessageID}}
+		if msg.Subject != "" {
+			fields = append(fields, field{"Subject", msg.Subject})
+		}
+		fields = append(fields, field{"Timestamp", msg.Timestamp}, field{"TopicArn", msg.TopicArn}, field{"Type", msg.Type})
+	case "SubscriptionConfirmation", "UnsubscribeConfirmation":
+		fields = []field{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageID},
+			{"SubscribeURL", msg.SubscribeURL},
+			{"Timestamp", msg.Timestamp},
+			{"Token", msg.Token},
+			{"TopicArn", msg.TopicArn},
+			{"Type", msg.Type},
+		}
+	default:
+		return "", fmt.Errorf("unsupported message type %q", msg.Type)
+	}
+
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteString(f.key + "\n" + f.value + "\n")
+	}
+	return b.String(), nil
+}
+
+// certificate returns the cached or downloaded certificate of certURL.
+func (v *SNSVerifier) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	u, err := url.Parse(certURL)
+	if err != nil || !v.certURLAllowed(u) {
+		return nil, fmt.Errorf("untrusted signing certificate URL %q", certURL)
+	}
+
+	v.mu.Lock()
+	cert, ok := v.certs[certURL]
+	v.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch signing certificate: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSNSCertSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing certificate is not PEM encoded")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing certificate: %w", err)
+	}
+
+	v.mu.Lock()
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+	return cert, nil
+}
+
+// isSNSCertURL reports whether u is an HTTPS .pem URL on an SNS host.
+func isSNSCertURL(u *url.URL) bool {
+	return u.Scheme == "https" && snsCertHost.MatchString(u.Hostname()) && u.Port() == "" &&
+		strings.HasSuffix(u.Path, ".pem")
+}
@@ -0,0 +1,149 @@
+package webhooksec
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSNSTestSigner serves a self-signed certificate and returns a verifier
+// trusting it and a function signing messages with its key.
+func newSNSTestSigner(t *testing.T) (*SNSVerifier, string, func(msg *SNSMessage), *atomic.Int32) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	var fetches atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_, _ = w.Write(certPEM)
+	}))
+	t.Cleanup(server.Close)
+
+	verifier := NewSNSVerifier(server.Client())
+	verifier.certURLAllowed = func(u *url.URL) bool { return u.Host == strings.TrimPrefix(server.URL, "https://") }
+
+	sign := func(msg *SNSMessage) {
+		stringToSign, err := snsStringToSign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hash crypto.Hash
+		var digest []byte
+		if msg.SignatureVersion == "1" {
+			sum := sha1.Sum([]byte(stringToSign))
+			hash, digest = crypto.SHA1, sum[:]
+		} else {
+			sum := sha256.Sum256([]byte(stringToSign))
+			hash, digest = crypto.SHA256, sum[:]
+		}
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, hash, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.Signature = base64.StdEncoding.EncodeToString(sig)
+	}
+	return verifier, server.URL + "/SimpleNotificationService-test.pem", sign, &fetches
+}
+
+func TestSNSVerifier_Verify(t *testing.T) {
+	verifier, certURL, sign, fetches := newSNSTestSigner(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	for _, version := range []string{"1", "2"} {
+		msg := &SNSMessage{
+			Type:             "Notification",
+			MessageID:        "m-" + version,
+			TopicArn:         "arn:aws:sns:eu-west-1:123456789012:alerts",
+			Subject:          "ALARM",
+			Message:          `{"AlarmName":"HighCPU"}`,
+			Timestamp:        now.Add(-time.Minute).Format("2006-01-02T15:04:05.000Z"),
+			SignatureVersion: version,
+			SigningCertURL:   certURL,
+		}
+		sign(msg)
+		if err := verifier.Verify(ctx, msg, now); err != nil {
+			t.Errorf("SignatureVersion %s: valid message rejected: %v", version, err)
+		}
+
+		msg.Message = `{"AlarmName":"Tampered"}`
+		if err := verifier.Verify(ctx, msg, now); !errors.Is(err, ErrSignatureMismatch) {
+			t.Errorf("SignatureVersion %s: tampered message: err = %v", version, err)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("certificate fetched %d times, want 1 (cached)", got)
+	}
+
+	confirmation := &SNSMessage{
+		Type:             "SubscriptionConfirmation",
+		MessageID:        "c-1",
+		Token:            "token",
+		TopicArn:         "arn:aws:sns:eu-west-1:123456789012:alerts",
+		Message:          "You have chosen to subscribe",
+		SubscribeURL:     "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription",
+		Timestamp:        now.Format(time.RFC3339),
+		SignatureVersion: "2",
+		SigningCertURL:   certURL,
+	}
+	sign(confirmation)
+	if err := verifier.Verify(ctx, confirmation, now); err != nil {
+		t.Errorf("subscription confirmation rejected: %v", err)
+	}
+	if err := verifier.Verify(ctx, confirmation, now.Add(time.Hour)); !errors.Is(err, ErrTimestampSkew) {
+		t.Errorf("stale message: err = %v", err)
+	}
+}
+
+func TestSNSVerifier_RejectsUntrustedCertURL(t *testing.T) {
+	verifier := NewSNSVerifier(nil)
+	now := time.Now()
+	for _, certURL := range []string{
+		"http://sns.eu-west-1.amazonaws.com/cert.pem",
+		"https://sns.eu-west-1.amazonaws.com.evil.example/cert.pem",
+		"https://evil.example/sns.eu-west-1.amazonaws.com/cert.pem",
+		"https://sns.eu-west-1.amazonaws.com/cert.txt",
+	} {
+		msg := &SNSMessage{
+			Type:             "Notification",
+			Timestamp:        now.Format(time.RFC3339),
+			SignatureVersion: "1",
+			Signature:        "c2ln",
+			SigningCertURL:   certURL,
+		}
+		err := verifier.Verify(context.Background(), msg, now)
+		if err == nil || !strings.Contains(err.Error(), "untrusted signing certificate URL") {
+			t.Errorf("%s: err = %v", certURL, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package webhooksec
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyHMACSHA256(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	valid := "sha256=" + SignHMACSHA256("secret", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		wantErr   error
+	}{
+		{"valid", "secret", valid, nil},
+		{"missing", "secret", "", ErrMissingSignature},
+		{"wrong secret", "other", valid, ErrSignatureMismatch},
+		{"missing prefix", "secret", SignHMACSHA256("secret", body), ErrSignatureMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyHMACSHA256(tt.secret, tt.signature, "sha256=", body); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyHMACSHA256() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifySlack(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("command=%2Famp&text=silence")
+	signed := func(ts time.Time, secret string) http.Header {
+		rawTS := strconv.FormatInt(ts.Unix(), 10)
+		h := http.Header{}
+		h.Set(SlackTimestampHeader, rawTS)
+		h.Set(SlackSignatureHeader, "v0="+SignHMACSHA256(secret, []byte("v0:"+rawTS+":"), body))
+		return h
+	}
+
+	if err := VerifySlack("secret", signed(now.Add(-time.Minute), "secret"), body, now, DefaultMaxSkew); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := VerifySlack("secret", signed(now, "other"), body, now, DefaultMaxSkew); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("wrong secret: err = %v", err)
+	}
+	if err := VerifySlack("secret", signed(now.Add(-10*time.Minute), "secret"), body, now, DefaultMaxSkew); !errors.Is(err, ErrTimestampSkew) {
+		t.Errorf("replayed request: err = %v", err)
+	}
+	if err := VerifySlack("secret", signed(now.Add(10*time.Minute), "secret"), body, now, DefaultMaxSkew); !errors.Is(err, ErrTimestampSkew) {
+		t.Errorf("future timestamp: err = %v", err)
+	}
+	if err := VerifySlack("secret", http.Header{}, body, now, DefaultMaxSkew); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("unsigned request: err = %v", err)
+	}
+
+	h := signed(now, "secret")
+	h.Set(SlackTimestampHeader, "yesterday")
+	if err := VerifySlack("secret", h, body, now, DefaultMaxSkew); err == nil {
+		t.Error("invalid timestamp accepted")
+	}
+}
+
+func TestVerifyPagerDuty(t *testing.T) {
+	body := []byte(`{"event":{"event_type":"incident.acknowledged"}}`)
+	header := func(signatures string) http.Header {
+		h := http.Header{}
+		h.Set(PagerDutySignatureHeader, signatures)
+		return h
+	}
+	oldSig := "v1=" + SignHMACSHA256("old", body)
+	newSig := "v1=" + SignHMACSHA256("new", body)
+
+	if err := VerifyPagerDuty([]string{"new"}, header(oldSig+", "+newSig), body); err != nil {
+		t.Errorf("rotated secret rejected: %v", err)
+	}
+	if err := VerifyPagerDuty([]string{"old", "new"}, header(oldSig), body); err != nil {
+		t.Errorf("previous secret rejected: %v", err)
+	}
+	if err := VerifyPagerDuty([]string{"other"}, header(oldSig+","+newSig), body); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("unknown secret: err = %v", err)
+	}
+	if err := VerifyPagerDuty([]string{"new"}, http.Header{}, body); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("unsigned request: err = %v", err)
+	}
+}
+
+func TestVerifyAMP(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"alert":{"fingerprint":"abc"}}`)
+	header := func(value string) http.Header {
+		h := http.Header{}
+		h.Set(AMPSignatureHeader, value)
+		return h
+	}
+	signed := SignAMP("secret", body, now.Add(-time.Minute))
+
+	if err := VerifyAMP([]string{"secret"}, header(signed), body, now, DefaultMaxSkew); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := VerifyAMP([]string{"old", "secret"}, header(signed), body, now, DefaultMaxSkew); err != nil {
+		t.Errorf("rotated secret rejected: %v", err)
+	}
+	rotated := signed + ",v1=" + SignHMACSHA256("new", []byte(strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)+"."), body)
+	if err := VerifyAMP([]string{"new"}, header(rotated), body, now, DefaultMaxSkew); err != nil {
+		t.Errorf("second signature rejected: %v", err)
+	}
+	if err := VerifyAMP([]string{"other"}, header(signed), body, now, DefaultMaxSkew); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("wrong secret: err = %v", err)
+	}
+	if err := VerifyAMP([]string{"secret"}, header(signed), []byte(`{}`), now, DefaultMaxSkew); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("tampered body: err = %v", err)
+	}
+	if err := VerifyAMP([]string{"secret"}, header(SignAMP("secret", body, now.Add(-10*time.Minute))), body, now, DefaultMaxSkew); !errors.Is(err, ErrTimestampSkew) {
+		t.Errorf("replayed request: err = %v", err)
+	}
+	if err := VerifyAMP([]string{"secret"}, header("t=1700000000"), body, now, DefaultMaxSkew); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("no v1 signature: err = %v", err)
+	}
+	if err := VerifyAMP([]string{"secret"}, http.Header{}, body, now, DefaultMaxSkew); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("unsigned request: err = %v", err)
+	}
+}
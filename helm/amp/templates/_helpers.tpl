@@ -145,6 +145,8 @@ Expected input: dict "target" <map>
 {{- if .apiKey }}
 {{- if eq $target.type "webhook" }}
 {{- $_ := set $headers "X-API-Key" .apiKey -}}
+{{- else if eq $target.type "opsgenie" }}
+{{- $_ := set $headers "Authorization" (printf "GenieKey %s" .apiKey) -}}
 {{- else }}
 {{- $_ := set $headers "Authorization" (printf "Bearer %s" .apiKey) -}}
 {{- end }}
@@ -155,6 +157,9 @@ Expected input: dict "target" <map>
 {{- if .authHeader }}
 {{- $_ := set $headers "Authorization" .authHeader -}}
 {{- end }}
+{{- if .signingSecret }}
+{{- $_ := set $headers "signing_secret" .signingSecret -}}
+{{- end }}
 {{- if .routingKey }}
 {{- $_ := set $headers "routing_key" .routingKey -}}
 {{- end }}